@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/validation"
+)
+
+// Operations generated.
+const (
+	opIngest = "ingest"
+	opSearch = "search"
+	opSync   = "sync"
+)
+
+// options configure a load generation run.
+type options struct {
+	URL         string
+	APIKey      string
+	Stores      int
+	StorePrefix string
+	Duration    time.Duration
+	IngestRate  float64
+	IngestBatch int
+	SearchRate  float64
+	SearchMode  string
+	SyncRate    float64
+	Concurrency int
+	Timeout     time.Duration
+	Seed        uint64
+	JSON        bool
+	FailOnBusy  bool
+}
+
+func defaultOptions() options {
+	return options{
+		URL:         "http://localhost:8080",
+		Stores:      4,
+		StorePrefix: "loadgen",
+		Duration:    time.Minute,
+		IngestRate:  10,
+		IngestBatch: 5,
+		SearchRate:  20,
+		SearchMode:  string(types.SearchModeKeyword),
+		SyncRate:    5,
+		Concurrency: 64,
+		Timeout:     10 * time.Second,
+		Seed:        1,
+	}
+}
+
+func (o options) validate() error {
+	switch {
+	case o.URL == "":
+		return errors.New("--url is required")
+	case o.APIKey == "":
+		return errors.New("--api-key or ENGRAM_API_KEY is required")
+	case o.Stores < 1:
+		return errors.New("--stores must be at least 1")
+	case o.Duration <= 0:
+		return errors.New("--duration must be positive")
+	case o.IngestRate < 0 || o.SearchRate < 0 || o.SyncRate < 0:
+		return errors.New("rates must not be negative")
+	case o.IngestRate+o.SearchRate+o.SyncRate == 0:
+		return errors.New("at least one rate must be positive")
+	case o.IngestBatch < 1 || o.IngestBatch > validation.MaxBatchSize:
+		return fmt.Errorf("--ingest-batch must be between 1 and %d", validation.MaxBatchSize)
+	case o.Concurrency < 1:
+		return errors.New("--concurrency must be at least 1")
+	}
+	switch types.SearchMode(o.SearchMode) {
+	case types.SearchModeKeyword, types.SearchModeSemantic, types.SearchModeHybrid:
+	default:
+		return fmt.Errorf("--search-mode %q: must be keyword, semantic or hybrid", o.SearchMode)
+	}
+	return nil
+}
+
+// generator issues requests and records their outcomes.
+type generator struct {
+	opts   options
+	base   string
+	client *http.Client
+	stores []string
+	stats  map[string]*opStats
+	sem    chan struct{}
+
+	mu      sync.Mutex // guards rng and cursors
+	rng     *rand.Rand
+	cursors []int64 // last synced sequence per store
+}
+
+func newGenerator(o options) *generator {
+	g := &generator{
+		opts:    o,
+		base:    strings.TrimRight(o.URL, "/") + "/api/v1",
+		client:  &http.Client{Timeout: o.Timeout},
+		stats:   map[string]*opStats{opIngest: {}, opSearch: {}, opSync: {}},
+		sem:     make(chan struct{}, o.Concurrency),
+		rng:     rand.New(rand.NewPCG(o.Seed, o.Seed)),
+		cursors: make([]int64, o.Stores),
+	}
+	for i := range o.Stores {
+		g.stores = append(g.stores, fmt.Sprintf("%s-%d", o.StorePrefix, i))
+	}
+	return g
+}
+
+// setup creates the stores traffic is spread over, leaving any that
+// already exist as they are.
+func (g *generator) setup(ctx context.Context) error {
+	for _, id := range g.stores {
+		body, _ := json.Marshal(map[string]string{
+			"store_id":    id,
+			"description": "Synthetic load generation",
+		})
+		resp, err := g.do(ctx, http.MethodPost, g.base+"/stores", body)
+		if err != nil {
+			return fmt.Errorf("create store %s: %w", id, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusConflict {
+			return fmt.Errorf("create store %s: %s", id, resp.Status)
+		}
+	}
+	return nil
+}
+
+// run generates traffic until the configured duration passes or ctx is
+// done, waits for requests in flight, and reports.
+func (g *generator) run(ctx context.Context) *report {
+	runCtx, cancel := context.WithTimeout(ctx, g.opts.Duration)
+	defer cancel()
+
+	start := time.Now()
+	var dispatchers, requests sync.WaitGroup
+	for op, rate := range map[string]float64{
+		opIngest: g.opts.IngestRate,
+		opSearch: g.opts.SearchRate,
+		opSync:   g.opts.SyncRate,
+	} {
+		if rate <= 0 {
+			continue
+		}
+		dispatchers.Add(1)
+		go func() {
+			defer dispatchers.Done()
+			g.dispatch(runCtx, ctx, op, rate, &requests)
+		}()
+	}
+	dispatchers.Wait()
+	requests.Wait()
+
+	return g.report(time.Since(start))
+}
+
+// dispatch starts op rate times a second until runCtx is done. Requests
+// run under reqCtx so those in flight when the run ends can finish. A tick
+// that finds the concurrency limit reached is skipped rather than queued,
+// so a slow server does not quietly lower the offered load.
+func (g *generator) dispatch(runCtx, reqCtx context.Context, op string, rate float64, requests *sync.WaitGroup) {
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-runCtx.Done():
+			return
+		case <-ticker.C:
+		}
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			g.stats[op].skip()
+			continue
+		}
+		requests.Add(1)
+		go func() {
+			defer func() {
+				<-g.sem
+				requests.Done()
+			}()
+			g.execute(reqCtx, op)
+		}()
+	}
+}
+
+// execute issues one request for op against a random store and records
+// the outcome.
+func (g *generator) execute(ctx context.Context, op string) {
+	g.mu.Lock()
+	storeIdx := g.rng.IntN(len(g.stores))
+	var method, target string
+	var body []byte
+	switch op {
+	case opIngest:
+		method = http.MethodPost
+		target = g.storeURL(storeIdx, "/lore")
+		body = g.ingestBody()
+	case opSearch:
+		method = http.MethodGet
+		target = g.storeURL(storeIdx, "/lore/search") + "?" + url.Values{
+			"q":     {g.searchText()},
+			"mode":  {g.opts.SearchMode},
+			"limit": {"10"},
+		}.Encode()
+	case opSync:
+		method = http.MethodGet
+		target = g.storeURL(storeIdx, "/sync/delta") + "?after=" + strconv.FormatInt(g.cursors[storeIdx], 10) + "&limit=500"
+	}
+	g.mu.Unlock()
+
+	start := time.Now()
+	resp, err := g.do(ctx, method, target, body)
+	if err != nil {
+		g.stats[op].fail()
+		return
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	elapsed := time.Since(start)
+	if err != nil {
+		g.stats[op].fail()
+		return
+	}
+	g.stats[op].record(elapsed, resp.StatusCode, data)
+
+	if op == opSync && resp.StatusCode == http.StatusOK {
+		var delta struct {
+			LastSequence int64 `json:"last_sequence"`
+		}
+		if json.Unmarshal(data, &delta) == nil {
+			g.mu.Lock()
+			g.cursors[storeIdx] = max(g.cursors[storeIdx], delta.LastSequence)
+			g.mu.Unlock()
+		}
+	}
+}
+
+func (g *generator) storeURL(idx int, path string) string {
+	return g.base + "/stores/" + url.PathEscape(g.stores[idx]) + path
+}
+
+func (g *generator) do(ctx context.Context, method, target string, body []byte) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, r)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+g.opts.APIKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return g.client.Do(req)
+}
+
+// Vocabulary for generated lore. Combined at random they give entries
+// that read like real lore and share enough words for searches to match.
+var (
+	loreComponents = []string{
+		"payment webhooks", "the order service", "the search index", "session tokens",
+		"the billing export", "image uploads", "the notification queue", "feature flags",
+		"the inventory cache", "database migrations", "the auth gateway", "report generation",
+	}
+	loreEvents = []string{
+		"time out under load", "retry without backoff", "race on startup", "drift between replicas",
+		"exceed the rate limit", "fail after a deploy", "leak connections", "return stale reads",
+	}
+	loreActions = []string{
+		"add exponential backoff with jitter", "batch writes per transaction", "cache results for a minute",
+		"move the work to a background job", "pin the client library version", "add an idempotency key",
+		"warm the cache before shifting traffic", "split the table by tenant",
+	}
+	loreReasons = []string{
+		"the upstream throttles bursts", "SQLite serializes writers", "clients retry aggressively",
+		"the load balancer drops idle connections", "the schema changed between versions",
+		"cold starts dominate latency",
+	}
+	loreCategories = []types.LoreCategory{
+		types.CategoryArchitecturalDecision, types.CategoryPatternOutcome, types.CategoryInterfaceLesson,
+		types.CategoryEdgeCaseDiscovery, types.CategoryImplementationFriction, types.CategoryTestingStrategy,
+		types.CategoryDependencyBehavior, types.CategoryPerformanceInsight,
+	}
+)
+
+// loadgenSources are the source IDs generated lore is attributed to, as if
+// from a team of clients.
+const loadgenSources = 16
+
+// ingestBody returns a generated ingest request. The caller holds g.mu.
+func (g *generator) ingestBody() []byte {
+	type entry struct {
+		Content    string             `json:"content"`
+		Category   types.LoreCategory `json:"category"`
+		Confidence float64            `json:"confidence"`
+	}
+	var req struct {
+		SourceID string  `json:"source_id"`
+		Lore     []entry `json:"lore"`
+	}
+	req.SourceID = fmt.Sprintf("loadgen-client-%d", g.rng.IntN(loadgenSources))
+	for range g.opts.IngestBatch {
+		req.Lore = append(req.Lore, entry{
+			Content: fmt.Sprintf("When %s %s, %s because %s.",
+				pick(g.rng, loreComponents), pick(g.rng, loreEvents), pick(g.rng, loreActions), pick(g.rng, loreReasons)),
+			Category:   pick(g.rng, loreCategories),
+			Confidence: 0.5 + g.rng.Float64()/2,
+		})
+	}
+	body, _ := json.Marshal(req)
+	return body
+}
+
+// searchText returns a generated search query. The caller holds g.mu.
+func (g *generator) searchText() string {
+	if g.rng.IntN(2) == 0 {
+		return pick(g.rng, loreComponents) + " " + pick(g.rng, loreEvents)
+	}
+	return pick(g.rng, loreActions)
+}
+
+func pick[T any](rng *rand.Rand, from []T) T {
+	return from[rng.IntN(len(from))]
+}
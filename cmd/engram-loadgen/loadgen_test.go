@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	for p, want := range map[float64]time.Duration{
+		50:  50 * time.Millisecond,
+		90:  90 * time.Millisecond,
+		99:  99 * time.Millisecond,
+		100: 100 * time.Millisecond,
+		0:   time.Millisecond,
+	} {
+		if got := percentile(sorted, p); got != want {
+			t.Errorf("percentile(%v) = %v, want %v", p, got, want)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("percentile of nothing = %v, want 0", got)
+	}
+	if got := percentile([]time.Duration{time.Second}, 99); got != time.Second {
+		t.Errorf("percentile of one value = %v, want 1s", got)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	valid := defaultOptions()
+	valid.APIKey = "key"
+	if err := valid.validate(); err != nil {
+		t.Fatalf("validate() error = %v", err)
+	}
+
+	for name, mutate := range map[string]func(*options){
+		"no api key":     func(o *options) { o.APIKey = "" },
+		"no stores":      func(o *options) { o.Stores = 0 },
+		"no rates":       func(o *options) { o.IngestRate, o.SearchRate, o.SyncRate = 0, 0, 0 },
+		"negative rate":  func(o *options) { o.SyncRate = -1 },
+		"batch too big":  func(o *options) { o.IngestBatch = 51 },
+		"bad mode":       func(o *options) { o.SearchMode = "fuzzy" },
+		"no concurrency": func(o *options) { o.Concurrency = 0 },
+	} {
+		o := valid
+		mutate(&o)
+		if err := o.validate(); err == nil {
+			t.Errorf("%s: validate() error = nil", name)
+		}
+	}
+}
+
+// fakeServer answers the endpoints the generator calls. Searches on the
+// store named busyStore fail as a busy database would.
+type fakeServer struct {
+	busyStore string
+
+	mu       sync.Mutex
+	stores   map[string]bool
+	ingested int
+	afters   []string
+	auth     atomic.Bool
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer test-key" {
+		f.auth.Store(true)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1")
+	switch {
+	case path == "/stores" && r.Method == http.MethodPost:
+		var req struct {
+			StoreID string `json:"store_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if f.stores[req.StoreID] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		f.stores[req.StoreID] = true
+		w.WriteHeader(http.StatusCreated)
+	case strings.HasSuffix(path, "/lore") && r.Method == http.MethodPost:
+		var req struct {
+			SourceID string            `json:"source_id"`
+			Lore     []json.RawMessage `json:"lore"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		f.ingested += len(req.Lore)
+		w.Write([]byte(`{"accepted":1}`))
+	case strings.HasSuffix(path, "/lore/search"):
+		if strings.Contains(path, "/"+f.busyStore+"/") {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"status":503,"detail":"Database is busy. Please retry after the indicated interval."}`))
+			return
+		}
+		w.Write([]byte(`{"results":[]}`))
+	case strings.HasSuffix(path, "/sync/delta"):
+		f.afters = append(f.afters, r.URL.Query().Get("after"))
+		w.Write([]byte(`{"entries":[],"last_sequence":42,"latest_sequence":42,"has_more":false}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestGeneratorRun(t *testing.T) {
+	fake := &fakeServer{busyStore: "lg-1", stores: map[string]bool{"lg-0": true}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	o := defaultOptions()
+	o.URL = srv.URL
+	o.APIKey = "test-key"
+	o.Stores = 2
+	o.StorePrefix = "lg"
+	o.Duration = 300 * time.Millisecond
+	o.IngestRate, o.SearchRate, o.SyncRate = 50, 50, 50
+	o.IngestBatch = 3
+
+	g := newGenerator(o)
+	ctx := context.Background()
+	if err := g.setup(ctx); err != nil {
+		t.Fatalf("setup() error = %v", err)
+	}
+	if !fake.stores["lg-1"] {
+		t.Errorf("setup did not create lg-1")
+	}
+	r := g.run(ctx)
+
+	if fake.auth.Load() {
+		t.Error("a request was sent without the API key")
+	}
+	if len(r.Ops) != 3 {
+		t.Fatalf("report has %d operations, want 3: %+v", len(r.Ops), r.Ops)
+	}
+	byOp := make(map[string]opReport)
+	for _, op := range r.Ops {
+		byOp[op.Op] = op
+		if op.Requests == 0 || op.P50 <= 0 || op.Max < op.P99 || op.P99 < op.P50 {
+			t.Errorf("%s: implausible report %+v", op.Op, op)
+		}
+	}
+	if ingest := byOp[opIngest]; ingest.Failed != 0 || fake.ingested != 3*ingest.Requests {
+		t.Errorf("ingest: %+v, server saw %d entries", ingest, fake.ingested)
+	}
+	if search := byOp[opSearch]; search.Busy == 0 || search.Busy != search.Failed || search.Shed != 0 {
+		t.Errorf("search: busy %d, failed %d, shed %d; want busy failures only", search.Busy, search.Failed, search.Shed)
+	}
+	if r.busy() != byOp[opSearch].Busy {
+		t.Errorf("busy() = %d, want %d", r.busy(), byOp[opSearch].Busy)
+	}
+
+	// Sync follows the sequence the server reports
+	followed := false
+	for _, after := range fake.afters {
+		followed = followed || after == "42"
+	}
+	if !followed && len(fake.afters) > 2 {
+		t.Errorf("sync cursors never advanced: %v", fake.afters)
+	}
+
+	var buf bytes.Buffer
+	if err := r.writeTable(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"OP", "ingest", "search", "sync", "across 2 stores"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("table missing %q:\n%s", want, buf.String())
+		}
+	}
+	buf.Reset()
+	if err := r.writeJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded.Ops) != 3 {
+		t.Errorf("JSON report = %s, err %v", buf.String(), err)
+	}
+}
+
+func TestGeneratorRun_SkipsAtConcurrencyLimit(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		<-release
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	o := defaultOptions()
+	o.URL = srv.URL
+	o.APIKey = "test-key"
+	o.Stores = 1
+	o.Duration = 200 * time.Millisecond
+	o.IngestRate, o.SearchRate, o.SyncRate = 0, 100, 0
+	o.Concurrency = 1
+	o.Timeout = 300 * time.Millisecond
+
+	g := newGenerator(o)
+	r := g.run(context.Background())
+	if len(r.Ops) != 1 || r.Ops[0].Skipped == 0 {
+		t.Errorf("report = %+v, want skipped searches", r.Ops)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// busyDetail starts the problem detail of a 503 the server sends when
+// SQLite refused a statement as busy, which tells it apart from a request
+// shed for overload.
+const busyDetail = "Database is busy"
+
+// opStats accumulates the outcomes of one operation's requests.
+type opStats struct {
+	mu         sync.Mutex
+	latencies  []time.Duration // of requests that got a response
+	unanswered int             // requests that got no response
+	failed     int             // non-2xx responses and unanswered requests
+	busy       int             // 503s for a busy database
+	shed       int             // other 503s
+	skipped    int             // ticks dropped at the concurrency limit
+	statuses   map[int]int
+}
+
+func (s *opStats) record(elapsed time.Duration, status int, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latencies = append(s.latencies, elapsed)
+	if s.statuses == nil {
+		s.statuses = make(map[int]int)
+	}
+	s.statuses[status]++
+	if status >= 200 && status < 300 {
+		return
+	}
+	s.failed++
+	if status == http.StatusServiceUnavailable {
+		var problem struct {
+			Detail string `json:"detail"`
+		}
+		if json.Unmarshal(body, &problem) == nil && strings.HasPrefix(problem.Detail, busyDetail) {
+			s.busy++
+		} else {
+			s.shed++
+		}
+	}
+}
+
+// fail records a request that got no response.
+func (s *opStats) fail() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failed++
+	s.unanswered++
+}
+
+func (s *opStats) skip() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.skipped++
+}
+
+// opReport summarizes one operation. Latencies are in milliseconds and
+// cover every request that got a response, failed or not.
+type opReport struct {
+	Op       string         `json:"op"`
+	Requests int            `json:"requests"`
+	Rate     float64        `json:"rate"` // requests completed per second
+	Failed   int            `json:"failed"`
+	Busy     int            `json:"busy"`
+	Shed     int            `json:"shed"`
+	Skipped  int            `json:"skipped"`
+	Statuses map[string]int `json:"statuses,omitempty"`
+	P50      float64        `json:"p50_ms"`
+	P90      float64        `json:"p90_ms"`
+	P99      float64        `json:"p99_ms"`
+	Max      float64        `json:"max_ms"`
+}
+
+// report is the outcome of a run.
+type report struct {
+	DurationMS int64      `json:"duration_ms"`
+	Stores     int        `json:"stores"`
+	Ops        []opReport `json:"ops"`
+}
+
+func (g *generator) report(elapsed time.Duration) *report {
+	r := &report{DurationMS: elapsed.Milliseconds(), Stores: len(g.stores)}
+	for _, op := range []string{opIngest, opSearch, opSync} {
+		s := g.stats[op]
+		s.mu.Lock()
+		requests := len(s.latencies) + s.unanswered
+		if requests == 0 && s.skipped == 0 {
+			s.mu.Unlock()
+			continue
+		}
+		latencies := slices.Clone(s.latencies)
+		slices.Sort(latencies)
+		or := opReport{
+			Op:       op,
+			Requests: requests,
+			Rate:     float64(requests) / elapsed.Seconds(),
+			Failed:   s.failed,
+			Busy:     s.busy,
+			Shed:     s.shed,
+			Skipped:  s.skipped,
+			P50:      millis(percentile(latencies, 50)),
+			P90:      millis(percentile(latencies, 90)),
+			P99:      millis(percentile(latencies, 99)),
+			Max:      millis(percentile(latencies, 100)),
+		}
+		for status, n := range s.statuses {
+			if or.Statuses == nil {
+				or.Statuses = make(map[string]int)
+			}
+			or.Statuses[fmt.Sprint(status)] = n
+		}
+		s.mu.Unlock()
+		r.Ops = append(r.Ops, or)
+	}
+	return r
+}
+
+// busy returns the number of requests refused as database busy.
+func (r *report) busy() int {
+	n := 0
+	for _, op := range r.Ops {
+		n += op.Busy
+	}
+	return n
+}
+
+func (r *report) writeJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func (r *report) writeTable(w io.Writer) error {
+	fmt.Fprintf(w, "%s across %d stores\n\n", time.Duration(r.DurationMS)*time.Millisecond, r.Stores)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "OP\tREQUESTS\tRATE/S\tFAILED\tBUSY\tSHED\tSKIPPED\tP50\tP90\tP99\tMAX")
+	for _, op := range r.Ops {
+		fmt.Fprintf(tw, "%s\t%d\t%.1f\t%d\t%d\t%d\t%d\t%.1fms\t%.1fms\t%.1fms\t%.1fms\n",
+			op.Op, op.Requests, op.Rate, op.Failed, op.Busy, op.Shed, op.Skipped, op.P50, op.P90, op.P99, op.Max)
+	}
+	return tw.Flush()
+}
+
+// percentile returns the p-th percentile of sorted by the nearest-rank
+// method, or 0 for no values.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
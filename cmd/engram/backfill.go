@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperengineering/engram/internal/config"
+	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/worker"
+)
+
+var (
+	backfillEmbeddings  bool
+	backfillBatchSize   int
+	backfillConcurrency int
+)
+
+func init() {
+	rootCmd.Flags().BoolVar(&backfillEmbeddings, "backfill-embeddings", false,
+		"Embed the pending and failed backlog of every store, then exit without serving")
+	rootCmd.Flags().IntVar(&backfillBatchSize, "backfill-batch-size", 256,
+		"Entries per embedding request during --backfill-embeddings")
+	rootCmd.Flags().IntVar(&backfillConcurrency, "backfill-concurrency", 4,
+		"Embedding requests in flight during --backfill-embeddings")
+}
+
+// runEmbeddingBackfill drains the embedding backlog of every managed store.
+// Interrupting it is safe: a rerun resumes from each store's checkpoint.
+func runEmbeddingBackfill(ctx context.Context, manager *multistore.StoreManager, embedder worker.Embedder, chunking worker.Chunking) error {
+	backfill := worker.NewEmbeddingBackfill(
+		worker.NewEmbeddingStoreManagerAdapter(manager),
+		embedder,
+		worker.BackfillConfig{
+			BatchSize:   backfillBatchSize,
+			Concurrency: backfillConcurrency,
+			Chunking:    chunking,
+		},
+	)
+	result, err := backfill.Run(ctx)
+	if err != nil {
+		return fmt.Errorf("embedding backfill: %w", err)
+	}
+	fmt.Printf("Embedding backfill complete: %d stores, %d entries embedded, %d failed\n",
+		result.Stores, result.Embedded, result.Failed)
+	return nil
+}
+
+// embeddingChunking returns the chunking the embedding workers apply to
+// long entries.
+func embeddingChunking(cfg *config.Config) worker.Chunking {
+	size, overlap := cfg.GetEmbeddingChunking()
+	return worker.Chunking{Size: size, Overlap: overlap}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/signal"
+	"syscall"
+
+	"github.com/hyperengineering/engram/internal/config"
+	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var migrateDryRun bool
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply pending schema migrations, then exit",
+	Long: "Migrate the main database and every active store to the current schema, then exit without serving. " +
+		"Run it from an init container or a deploy job, before any server starts, so servers open databases " +
+		"that are already migrated. Archived stores are migrated when thawed. API keys are not required.",
+	Args: cobra.NoArgs,
+	RunE: runMigrateCmd,
+}
+
+func init() {
+	rootCmd.Flags().BoolVar(&migrateDryRun, "migrate-dry-run", false,
+		"Print the migrations pending for the database and every store, then exit without applying them")
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false,
+		"Print the pending migrations without applying them")
+	rootCmd.AddCommand(migrateCmd)
+}
+
+func runMigrateCmd(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(),
+		syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+
+	initPlugins()
+	cfg, err := config.LoadUnvalidated()
+	if err != nil {
+		return err
+	}
+	if err := loadExternalPlugins(cfg.Plugins.Dir); err != nil {
+		return err
+	}
+
+	if migrateDryRun {
+		return runMigrateDryRun(ctx, cmd.OutOrStdout(), cfg)
+	}
+	return runMigrate(ctx, cmd.OutOrStdout(), cfg)
+}
+
+// runMigrate applies the pending migrations of the main database and every
+// active store, reporting each. A store that fails to migrate is restored
+// from its pre-migration copy and does not stop the others, but fails the
+// command.
+func runMigrate(ctx context.Context, w io.Writer, cfg *config.Config) error {
+	status, err := store.InspectMigrations(cfg.Database.Path, "", nil)
+	if err != nil {
+		return fmt.Errorf("inspect database %s: %w", cfg.Database.Path, err)
+	}
+	if len(status.Pending) > 0 {
+		db, err := store.NewSQLiteStore(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("migrate database %s: %w", cfg.Database.Path, err)
+		}
+		db.Close()
+	}
+	printMigrated(w, cfg.Database.Path, *status)
+
+	manager, err := multistore.NewStoreManager(cfg.Stores.RootPath, storeLayout(cfg.Stores)...)
+	if err != nil {
+		return fmt.Errorf("initialize store manager: %w", err)
+	}
+	defer manager.Close()
+
+	stores, err := manager.PendingMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, s := range stores {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if len(s.Pending) > 0 {
+			// Opening a store migrates it
+			if _, err := manager.GetStore(ctx, s.StoreID); err != nil {
+				fmt.Fprintf(w, "store %s: migration failed: %v\n", s.StoreID, err)
+				failed++
+				continue
+			}
+		}
+		printMigrated(w, "store "+s.StoreID, s.MigrationStatus)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d stores failed to migrate", failed, len(stores))
+	}
+	return nil
+}
+
+// printMigrated reports the migrations applied to a database that had the
+// given status before migrating.
+func printMigrated(w io.Writer, name string, status store.MigrationStatus) {
+	switch {
+	case len(status.Pending) == 0:
+		fmt.Fprintf(w, "%s: up to date at schema version %d\n", name, status.Version)
+	case !status.Exists:
+		fmt.Fprintf(w, "%s: created, %d migrations applied\n", name, len(status.Pending))
+	default:
+		fmt.Fprintf(w, "%s: %d migrations applied from schema version %d\n", name, len(status.Pending), status.Version)
+	}
+}
+
+// runMigrateDryRun prints the migrations each database would apply on the
+// next start. Nothing is opened for writing.
+func runMigrateDryRun(ctx context.Context, w io.Writer, cfg *config.Config) error {
+	status, err := store.InspectMigrations(cfg.Database.Path, "", nil)
+	if err != nil {
+		return fmt.Errorf("inspect database %s: %w", cfg.Database.Path, err)
+	}
+	printMigrationStatus(w, cfg.Database.Path, *status)
+
+	manager, err := multistore.NewStoreManager(cfg.Stores.RootPath, storeLayout(cfg.Stores)...)
+	if err != nil {
+		return fmt.Errorf("initialize store manager: %w", err)
+	}
+	defer manager.Close()
+
+	stores, err := manager.PendingMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	pending := 0
+	for _, s := range stores {
+		printMigrationStatus(w, "store "+s.StoreID, s.MigrationStatus)
+		if len(s.Pending) > 0 {
+			pending++
+		}
+	}
+	if len(status.Pending) > 0 {
+		pending++
+	}
+	fmt.Fprintf(w, "%d of %d databases have pending migrations\n", pending, len(stores)+1)
+	return nil
+}
+
+func printMigrationStatus(w io.Writer, name string, status store.MigrationStatus) {
+	switch {
+	case !status.Exists:
+		fmt.Fprintf(w, "%s: new database, %d migrations to apply\n", name, len(status.Pending))
+		return
+	case len(status.Pending) == 0:
+		fmt.Fprintf(w, "%s: up to date at schema version %d\n", name, status.Version)
+		return
+	}
+	fmt.Fprintf(w, "%s: schema version %d, %d pending\n", name, status.Version, len(status.Pending))
+	for _, m := range status.Pending {
+		if m.Plugin != "" {
+			fmt.Fprintf(w, "  %s plugin %d %s\n", m.Plugin, m.Version, m.Name)
+			continue
+		}
+		fmt.Fprintf(w, "  %d %s\n", m.Version, m.Name)
+	}
+}
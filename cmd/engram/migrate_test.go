@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/plugin"
+)
+
+// executeMigrateCmd runs "engram migrate" against a database and store root
+// under dir, without API keys.
+func executeMigrateCmd(t *testing.T, dir string, args ...string) (string, error) {
+	t.Helper()
+	plugin.Reset()
+	migrateDryRun = false
+
+	t.Setenv("ENGRAM_CONFIG_PATH", filepath.Join(dir, "missing.yaml"))
+	t.Setenv("ENGRAM_DB_PATH", filepath.Join(dir, "engram.db"))
+	t.Setenv("ENGRAM_STORES_ROOT", filepath.Join(dir, "stores"))
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("ENGRAM_API_KEY", "")
+	t.Setenv("ENGRAM_DEV_MODE", "")
+
+	out := new(bytes.Buffer)
+	rootCmd.SetOut(out)
+	rootCmd.SetArgs(append([]string{"migrate"}, args...))
+	err := rootCmd.Execute()
+	rootCmd.SetOut(nil)
+	rootCmd.SetArgs(nil)
+	return out.String(), err
+}
+
+func TestMigrateCmd(t *testing.T) {
+	dir := t.TempDir()
+	if _, _, err := executeStoreCmd(t, filepath.Join(dir, "stores"), "create", "team-a"); err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+
+	out, err := executeMigrateCmd(t, dir, "--dry-run")
+	if err != nil {
+		t.Fatalf("migrate --dry-run: %v", err)
+	}
+	if !strings.Contains(out, "new database") {
+		t.Errorf("dry run output = %q, want the main database listed as new", out)
+	}
+
+	out, err = executeMigrateCmd(t, dir)
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if !strings.Contains(out, "engram.db: created") || !strings.Contains(out, "store team-a: up to date") {
+		t.Errorf("migrate output = %q, want the main database created and team-a up to date", out)
+	}
+
+	out, err = executeMigrateCmd(t, dir)
+	if err != nil {
+		t.Fatalf("second migrate: %v", err)
+	}
+	if strings.Contains(out, "applied") || strings.Contains(out, "created") {
+		t.Errorf("second migrate output = %q, want everything up to date", out)
+	}
+}
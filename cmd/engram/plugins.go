@@ -1,8 +1,12 @@
 package main
 
 import (
+	"fmt"
+	"log/slog"
+
 	"github.com/hyperengineering/engram/internal/plugin"
 	"github.com/hyperengineering/engram/internal/plugin/generic"
+	"github.com/hyperengineering/engram/internal/plugin/manifest"
 	"github.com/hyperengineering/engram/internal/plugin/recall"
 	"github.com/hyperengineering/engram/internal/plugin/tract"
 )
@@ -17,3 +21,31 @@ func initPlugins() {
 	plugin.Register(recall.New())
 	plugin.Register(tract.New())
 }
+
+// loadExternalPlugins registers manifest-declared plugins found under dir.
+// Must run after initPlugins so built-in types take precedence and
+// collisions are reported rather than silently shadowing them.
+func loadExternalPlugins(dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	plugins, err := manifest.LoadDir(dir)
+	if err != nil {
+		return fmt.Errorf("load external plugins: %w", err)
+	}
+
+	for _, p := range plugins {
+		if err := manifest.Register(p); err != nil {
+			return fmt.Errorf("register external plugin %q: %w", p.Type(), err)
+		}
+		slog.Info("external plugin registered",
+			"component", "plugin",
+			"action", "plugin_registered",
+			"store_type", p.Type(),
+			"tables", len(p.TableSchemas()),
+			"migrations", len(p.Migrations()),
+		)
+	}
+	return nil
+}
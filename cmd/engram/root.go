@@ -7,16 +7,25 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/hyperengineering/engram/internal/api"
 	"github.com/hyperengineering/engram/internal/config"
+	"github.com/hyperengineering/engram/internal/digest"
 	"github.com/hyperengineering/engram/internal/embedding"
+	"github.com/hyperengineering/engram/internal/eventbus"
+	"github.com/hyperengineering/engram/internal/leader"
+	"github.com/hyperengineering/engram/internal/llm"
 	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/plugin"
+	"github.com/hyperengineering/engram/internal/secrets"
 	"github.com/hyperengineering/engram/internal/snapshot"
 	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/webhook"
 	"github.com/hyperengineering/engram/internal/worker"
 	"github.com/spf13/cobra"
 )
@@ -74,15 +83,98 @@ func run(cmd *cobra.Command, args []string) error {
 	slog.SetDefault(logger)
 	slog.Info("logger initialized", "level", cfg.Log.Level)
 
+	// Register external plugins declared on disk (needs configuration)
+	if err := loadExternalPlugins(cfg.Plugins.Dir); err != nil {
+		return err
+	}
+
+	// Dry-run mode lists pending migrations and exits before any database
+	// is opened for writing.
+	if migrateDryRun {
+		return runMigrateDryRun(ctx, os.Stdout, cfg)
+	}
+
 	// 4. Initialize store (migrations, WAL mode)
-	db, err := store.NewSQLiteStore(cfg.Database.Path)
+	// The default store is always recall-typed.
+	quantization, err := store.ParseQuantization(cfg.Embedding.Quantization)
 	if err != nil {
 		return err
 	}
-	slog.Info("store initialized", "path", cfg.Database.Path)
+	// Fault injection is for soak tests only
+	if err := cfg.Faults.Validate(); err != nil {
+		return fmt.Errorf("invalid faults config: %w", err)
+	}
+	storeFaults := store.Faults{
+		BusyRate: cfg.Faults.DatabaseBusyRate,
+		Latency:  time.Duration(cfg.Faults.DatabaseLatency),
+	}
+	if cfg.Faults.Enabled() {
+		slog.Warn("fault injection enabled; never run this configuration in production",
+			"embedding_error_rate", cfg.Faults.EmbeddingErrorRate,
+			"embedding_latency", time.Duration(cfg.Faults.EmbeddingLatency).String(),
+			"database_busy_rate", cfg.Faults.DatabaseBusyRate,
+			"database_latency", time.Duration(cfg.Faults.DatabaseLatency).String(),
+		)
+	}
+	tuning := store.Tuning{
+		PageSize:  cfg.Database.SQLite.PageSize,
+		CacheSize: cfg.Database.SQLite.CacheSize,
+		MmapSize:  cfg.Database.SQLite.MmapSize,
+		TempStore: cfg.Database.SQLite.TempStore,
+	}
+	if err := tuning.Validate(); err != nil {
+		return fmt.Errorf("invalid database.sqlite config: %w", err)
+	}
+	feedbackLimit := store.FeedbackLimit{
+		Window: time.Duration(cfg.Feedback.RateLimitWindow),
+		Max:    cfg.Feedback.RateLimitMax,
+	}
+	selfFeedback := store.SelfFeedback{
+		Exclude: cfg.Feedback.ExcludeSelf,
+		Weight:  cfg.Feedback.SelfWeight,
+	}
+	if err := selfFeedback.Validate(); err != nil {
+		return fmt.Errorf("invalid feedback config: %w", err)
+	}
+	recallPlugin, _ := plugin.Get("recall")
+	db, err := store.NewSQLiteStore(cfg.Database.Path,
+		store.WithPluginHooks(recallPlugin),
+		store.WithEmbeddingQuantization(quantization),
+		store.WithFaults(storeFaults),
+		store.WithTuning(tuning),
+		store.WithSlowQueryThreshold(time.Duration(cfg.Database.SlowQueryThreshold)),
+		store.WithFeedbackLimit(feedbackLimit),
+		store.WithSelfFeedback(selfFeedback),
+	)
+	if err != nil {
+		return err
+	}
+	slog.Info("store initialized", "path", cfg.Database.Path, "embedding_quantization", quantization)
+
+	// Secrets read from files are read again as they age, so they can be
+	// rotated without a restart
+	refresh := time.Duration(cfg.Secrets.RefreshInterval)
+	embeddingKey, err := secrets.Source(cfg.Embedding.APIKey, cfg.Embedding.APIKeyFile, refresh)
+	if err != nil {
+		return fmt.Errorf("embedding api key: %w", err)
+	}
+	apiKey, err := secrets.Source(cfg.Auth.APIKey, cfg.Auth.APIKeyFile, refresh)
+	if err != nil {
+		return fmt.Errorf("api key: %w", err)
+	}
+	adminKey, err := secrets.Source(cfg.Auth.AdminKey, cfg.Auth.AdminKeyFile, refresh)
+	if err != nil {
+		return fmt.Errorf("admin api key: %w", err)
+	}
 
 	// 5. Initialize embedding service
-	embedder := embedding.NewOpenAI(cfg.Embedding.APIKey, cfg.Embedding.Model)
+	var embedder embedding.Embedder = embedding.NewOpenAI(cfg.Embedding.APIKey, cfg.Embedding.Model)
+	if cfg.Embedding.APIKeyFile != "" {
+		embedder = embedding.NewOpenAIWithKeyFunc(embeddingKey, cfg.Embedding.Model)
+	}
+	if cfg.Faults.EmbeddingErrorRate > 0 || cfg.Faults.EmbeddingLatency > 0 {
+		embedder = embedding.NewFaulty(embedder, cfg.Faults.EmbeddingErrorRate, time.Duration(cfg.Faults.EmbeddingLatency))
+	}
 	slog.Info("embedder initialized", "model", cfg.Embedding.Model)
 
 	// 6. Configure store dependencies for deduplication
@@ -92,15 +184,48 @@ func run(cmd *cobra.Command, args []string) error {
 		"threshold", cfg.Deduplication.SimilarityThreshold)
 
 	// 7. Initialize multi-store support (Story 7.3)
-	storeManager, err := multistore.NewStoreManager(cfg.Stores.RootPath)
+	storeManager, err := multistore.NewStoreManager(cfg.Stores.RootPath, append(storeLayout(cfg.Stores),
+		multistore.WithMaxOpenStores(cfg.Stores.MaxOpen),
+		multistore.WithIdleTimeout(time.Duration(cfg.Stores.IdleTimeout)),
+		multistore.WithEmbeddingQuantization(quantization),
+		multistore.WithStoreFaults(storeFaults),
+		multistore.WithStoreTuning(tuning),
+		multistore.WithSlowQueryThreshold(time.Duration(cfg.Database.SlowQueryThreshold)),
+		multistore.WithFeedbackLimit(feedbackLimit),
+		multistore.WithSelfFeedback(selfFeedback),
+	)...)
 	if err != nil {
 		return fmt.Errorf("initialize store manager: %w", err)
 	}
 	defer storeManager.Close()
-	slog.Info("store manager initialized", "root_path", cfg.Stores.RootPath)
+	slog.Info("store manager initialized",
+		"root_path", cfg.Stores.RootPath,
+		"store_paths", len(cfg.Stores.Paths),
+		"archive_path", cfg.Stores.ArchivePath,
+		"max_open", cfg.Stores.MaxOpen,
+		"idle_timeout", time.Duration(cfg.Stores.IdleTimeout).String(),
+	)
+
+	// Backfill mode embeds the backlog of every store and exits without
+	// starting the server.
+	if backfillEmbeddings {
+		return runEmbeddingBackfill(ctx, storeManager, embedder, embeddingChunking(cfg))
+	}
 
 	// 8. Initialize snapshot uploader (S3-compatible storage)
-	uploader, err := snapshot.NewUploader(cfg.SnapshotStorage)
+	var uploaderOpts []snapshot.UploaderOption
+	if cfg.SnapshotStorage.AccessKeyFile != "" || cfg.SnapshotStorage.SecretKeyFile != "" {
+		accessKey, err := secrets.Source(cfg.SnapshotStorage.AccessKey, cfg.SnapshotStorage.AccessKeyFile, refresh)
+		if err != nil {
+			return fmt.Errorf("s3 access key: %w", err)
+		}
+		secretKey, err := secrets.Source(cfg.SnapshotStorage.SecretKey, cfg.SnapshotStorage.SecretKeyFile, refresh)
+		if err != nil {
+			return fmt.Errorf("s3 secret key: %w", err)
+		}
+		uploaderOpts = append(uploaderOpts, snapshot.WithCredentials(accessKey, secretKey))
+	}
+	uploader, err := snapshot.NewUploader(cfg.SnapshotStorage, uploaderOpts...)
 	if err != nil {
 		return fmt.Errorf("initialize snapshot uploader: %w", err)
 	}
@@ -111,9 +236,143 @@ func run(cmd *cobra.Command, args []string) error {
 			"endpoint", cfg.SnapshotStorage.Endpoint,
 		)
 	}
+	// Archived stores are backed up to the same bucket as snapshots
+	if archives, ok := uploader.(multistore.ArchiveStorage); ok {
+		storeManager.SetArchiveStorage(archives)
+	}
+	// Stateless deployments rebuild an empty stores root from S3
+	if cfg.SnapshotStorage.RestoreOnStartup {
+		snapshots, ok := uploader.(multistore.SnapshotSource)
+		if !ok {
+			return fmt.Errorf("restore on startup requires snapshot storage (ENGRAM_SNAPSHOT_BUCKET)")
+		}
+		if _, err := storeManager.RestoreFromSnapshots(ctx, snapshots); err != nil {
+			return fmt.Errorf("restore stores from snapshots: %w", err)
+		}
+	}
+
+	// Initialize webhook dispatcher (no-op when no endpoints are configured)
+	webhooks, err := webhook.NewDispatcher(cfg.Webhooks)
+	if err != nil {
+		return fmt.Errorf("initialize webhooks: %w", err)
+	}
+	if n := len(cfg.Webhooks.Endpoints); n > 0 {
+		slog.Info("webhook notifications enabled", "endpoints", n)
+	}
+
+	// Validate digest reports before starting anything
+	digestReports, err := digest.ParseReports(cfg.Digests)
+	if err != nil {
+		return fmt.Errorf("initialize digests: %w", err)
+	}
+
+	searchRanking := types.SearchRanking{
+		Fusion:         types.SearchFusion(cfg.Search.Fusion),
+		KeywordWeight:  cfg.Search.KeywordWeight,
+		SemanticWeight: cfg.Search.SemanticWeight,
+		RRFK:           cfg.Search.RRFK,
+	}
+	if err := searchRanking.Validate(); err != nil {
+		return fmt.Errorf("invalid search config: %w", err)
+	}
+	searchBoost := types.SearchBoost{
+		ConfidenceWeight: cfg.Search.ConfidenceBoost,
+		ValidationWeight: cfg.Search.ValidationBoost,
+		FeedbackWeight:   cfg.Search.FeedbackBoost,
+		RecencyWeight:    cfg.Search.RecencyBoost,
+		ValidationPivot:  cfg.Search.ValidationPivot,
+		RecencyHalfLife:  time.Duration(cfg.Search.RecencyHalfLife),
+	}
+	if err := searchBoost.Validate(); err != nil {
+		return fmt.Errorf("invalid search config: %w", err)
+	}
+
+	// Repeated search queries reuse their embedding
+	var queryEmbedder embedding.Embedder = embedder
+	if ttl := time.Duration(cfg.Search.QueryCacheTTL); ttl > 0 {
+		queryEmbedder = embedding.NewQueryCache(embedder, ttl, cfg.Search.QueryCacheSize)
+	}
+
+	latencySLOs := make(map[string]time.Duration, len(cfg.Server.LatencySLOs))
+	for route, target := range cfg.Server.LatencySLOs {
+		latencySLOs[route] = time.Duration(target)
+	}
+
+	var pprofKey string
+	if cfg.Server.Pprof {
+		if cfg.Auth.AdminKey == "" {
+			slog.Warn("pprof requested but ENGRAM_ADMIN_API_KEY is not set; profiling endpoints disabled")
+		} else {
+			pprofKey = cfg.Auth.AdminKey
+			slog.Info("pprof endpoints enabled", "path", "/debug/pprof/")
+		}
+	}
+
+	// Optional language model for lore summarization
+	summarizer, err := llm.New(llm.Config{
+		Provider: cfg.LLM.Provider,
+		Model:    cfg.LLM.Model,
+		BaseURL:  cfg.LLM.BaseURL,
+		APIKey:   cfg.LLM.APIKey,
+		Timeout:  time.Duration(cfg.LLM.Timeout),
+	})
+	if err != nil {
+		return fmt.Errorf("initialize llm: %w", err)
+	}
+	if summarizer != nil {
+		slog.Info("lore summarization enabled", "provider", cfg.LLM.Provider, "model", summarizer.ModelName())
+	}
 
 	// 9. Initialize HTTP router
-	handler := api.NewHandler(db, storeManager, embedder, uploader, cfg.Auth.APIKey, Version)
+	shedder := api.NewLoadShedder(api.ShedConfig{
+		MaxInFlight:       cfg.Shedding.MaxInFlight,
+		IngestMaxInFlight: cfg.Shedding.IngestMaxInFlight,
+		MaxBusyErrors:     cfg.Shedding.MaxBusyErrors,
+		BusyWindow:        time.Duration(cfg.Shedding.BusyWindow),
+		MaxEmbeddingQueue: cfg.Shedding.MaxEmbeddingQueue,
+		RetryAfter:        time.Duration(cfg.Shedding.RetryAfter),
+	}, pendingEmbeddings(storeManager))
+	if err := cfg.Sync.Signing.Validate(); err != nil {
+		return fmt.Errorf("invalid sync signing config: %w", err)
+	}
+	var pushSigning *api.PushSigning
+	if len(cfg.Sync.Signing.Sources) > 0 {
+		sourceSecrets := make(map[string]string, len(cfg.Sync.Signing.Sources))
+		for _, src := range cfg.Sync.Signing.Sources {
+			sourceSecrets[src.SourceID] = src.Secret
+		}
+		pushSigning = api.NewPushSigning(sourceSecrets, cfg.Sync.Signing.Required, time.Duration(cfg.Sync.Signing.MaxAge))
+		slog.Info("push signing enabled", "sources", len(sourceSecrets), "required", cfg.Sync.Signing.Required)
+	}
+	trustedProxies, err := api.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("invalid server.trusted_proxies: %w", err)
+	}
+	handler := api.NewHandler(db, storeManager, embedder, uploader, cfg.Auth.APIKey, Version,
+		api.WithMaxPushBytes(cfg.Sync.MaxPushBytes),
+		api.WithMaxIngestBytes(cfg.Server.MaxIngestBytes),
+		api.WithPushSessionTTL(time.Duration(cfg.Sync.PushSessionTTL)),
+		api.WithMaxClockSkew(time.Duration(cfg.Sync.MaxClockSkew)),
+		api.WithPushSigning(pushSigning),
+		api.WithNotifier(webhooks),
+		api.WithSearchRanking(searchRanking),
+		api.WithSearchBoost(searchBoost),
+		api.WithQueryEmbedder(queryEmbedder),
+		api.WithAccessLogSampling(cfg.Log.AccessSampleRates),
+		api.WithLatencySLOs(latencySLOs),
+		api.WithLoadShedder(shedder),
+		api.WithAuthLockout(api.NewAuthLockout(api.LockoutConfig{
+			MaxFailures: cfg.Auth.Lockout.MaxFailures,
+			Window:      time.Duration(cfg.Auth.Lockout.Window),
+			Duration:    time.Duration(cfg.Auth.Lockout.Duration),
+		})),
+		api.WithTrustedProxies(trustedProxies),
+		api.WithPprof(pprofKey),
+		api.WithAdminKey(cfg.Auth.AdminKey),
+		api.WithAPIKeySource(apiKey),
+		api.WithAdminKeySource(adminKey),
+		api.WithSummarizer(summarizer),
+	)
 	router := api.NewRouter(handler, storeManager)
 	slog.Info("router initialized")
 
@@ -129,6 +388,22 @@ func run(cmd *cobra.Command, args []string) error {
 	// 10. Worker lifecycle infrastructure
 	var wg sync.WaitGroup
 
+	startWorker(ctx, &wg, "webhook-dispatcher", webhooks.Run)
+
+	// Close stores left idle (no-op when stores.idle_timeout is 0)
+	startWorker(ctx, &wg, "store-idle-eviction", storeManager.RunIdleEviction)
+
+	// Replicas sharing the stores elect one to run the background jobs
+	// below (nil, running them here, when election is disabled)
+	elector, err := newElector(cfg.Leader, storeManager.RootPath())
+	if err != nil {
+		return err
+	}
+	if elector != nil {
+		defer elector.Close()
+		startWorker(ctx, &wg, "leader-election", elector.Run)
+	}
+
 	// Initialize store manager adapters for multi-store workers
 	storeAdapter := worker.NewStoreManagerAdapter(storeManager)
 	decayAdapter := worker.NewDecayStoreManagerAdapter(storeManager)
@@ -142,7 +417,8 @@ func run(cmd *cobra.Command, args []string) error {
 		cfg.Worker.EmbeddingRetryMaxAttempts,
 		cfg.Worker.EmbeddingRetryBatchSize,
 	)
-	startWorker(ctx, &wg, "embedding-coordinator", embeddingCoordinator.Run)
+	embeddingCoordinator.SetChunking(embeddingChunking(cfg))
+	startWorker(ctx, &wg, "embedding-coordinator", elector.Lead(embeddingCoordinator.Run))
 
 	// Initialize and start snapshot coordinator (multi-store aware)
 	snapshotCoordinator := worker.NewSnapshotCoordinator(
@@ -150,7 +426,11 @@ func run(cmd *cobra.Command, args []string) error {
 		time.Duration(cfg.Worker.SnapshotInterval),
 		uploader,
 	)
-	startWorker(ctx, &wg, "snapshot-coordinator", snapshotCoordinator.Run)
+	snapshotCoordinator.SetNotifier(webhooks)
+	// Uploads run alongside generation and wait out API load shedding
+	snapshotCoordinator.SetUploadConcurrency(cfg.SnapshotStorage.UploadConcurrency)
+	snapshotCoordinator.SetUploadGate(shedder.Healthy)
+	startWorker(ctx, &wg, "snapshot-coordinator", elector.Lead(snapshotCoordinator.Run))
 
 	// Initialize and start confidence decay coordinator (multi-store aware)
 	decayCoordinator := worker.NewDecayCoordinator(
@@ -158,7 +438,8 @@ func run(cmd *cobra.Command, args []string) error {
 		time.Duration(cfg.Worker.DecayInterval),
 		store.DefaultDecayAmount,
 	)
-	startWorker(ctx, &wg, "decay-coordinator", decayCoordinator.Run)
+	decayCoordinator.SetNotifier(webhooks)
+	startWorker(ctx, &wg, "decay-coordinator", elector.Lead(decayCoordinator.Run))
 
 	// Initialize and start compaction coordinator (multi-store aware)
 	compactionAdapter := worker.NewCompactionStoreManagerAdapter(storeManager)
@@ -167,7 +448,85 @@ func run(cmd *cobra.Command, args []string) error {
 		time.Duration(cfg.Worker.CompactionInterval),
 		time.Duration(cfg.Worker.CompactionRetention),
 	)
-	startWorker(ctx, &wg, "compaction-coordinator", compactionCoordinator.Run)
+	startWorker(ctx, &wg, "compaction-coordinator", elector.Lead(compactionCoordinator.Run))
+
+	// Initialize and start idempotency cleanup coordinator (multi-store aware)
+	idempotencyAdapter := worker.NewIdempotencyStoreManagerAdapter(storeManager)
+	idempotencyCoordinator := worker.NewIdempotencyCoordinator(
+		idempotencyAdapter,
+		time.Duration(cfg.Worker.IdempotencyInterval),
+		cfg.Worker.IdempotencyMaxEntries,
+	)
+	startWorker(ctx, &wg, "idempotency-coordinator", elector.Lead(idempotencyCoordinator.Run))
+
+	// Initialize and start a disk monitor for every directory holding
+	// stores or archives (disabled by a zero interval)
+	if cfg.Worker.DiskCheckInterval > 0 {
+		diskPaths := storeManager.Roots()
+		if root := storeManager.ArchiveRoot(); root != "" {
+			diskPaths = append(diskPaths, root)
+		}
+		for i, path := range diskPaths {
+			diskMonitor := worker.NewDiskMonitor(
+				path,
+				time.Duration(cfg.Worker.DiskCheckInterval),
+				cfg.Worker.DiskThresholds,
+			)
+			diskMonitor.SetNotifier(webhooks)
+			name := "disk-monitor"
+			if i > 0 {
+				name = fmt.Sprintf("disk-monitor-%d", i)
+			}
+			startWorker(ctx, &wg, name, diskMonitor.Run)
+		}
+	}
+
+	// Initialize and start digest coordinator (no-op when no reports are configured)
+	digestCoordinator := worker.NewDigestCoordinator(
+		worker.NewDigestStoreManagerAdapter(storeManager),
+		digestReports,
+		digest.NewHTTPSender(time.Duration(cfg.Digests.Timeout)),
+		time.Duration(cfg.Digests.CheckInterval),
+		cfg.Digests.TopN,
+	)
+	startWorker(ctx, &wg, "digest-coordinator", elector.Lead(digestCoordinator.Run))
+
+	// Initialize and start consolidation coordinator (disabled by a zero interval)
+	if cfg.Consolidation.Interval > 0 {
+		consolidationCoordinator := worker.NewConsolidationCoordinator(
+			worker.NewConsolidationStoreManagerAdapter(storeManager),
+			time.Duration(cfg.Consolidation.Interval),
+			cfg.Consolidation.Threshold,
+			cfg.Consolidation.MaxProposals,
+			cfg.Consolidation.Apply,
+		)
+		startWorker(ctx, &wg, "consolidation-coordinator", elector.Lead(consolidationCoordinator.Run))
+	}
+
+	// Initialize and start event bus relay (only when a driver is configured)
+	if cfg.EventBus.Driver != "" {
+		publisher, err := eventbus.New(cfg.EventBus)
+		if err != nil {
+			return fmt.Errorf("initialize event bus: %w", err)
+		}
+		slog.Info("event bus publishing enabled",
+			"driver", cfg.EventBus.Driver,
+			"topic_prefix", cfg.EventBus.TopicPrefix,
+		)
+		eventBusCoordinator := worker.NewEventBusCoordinator(
+			worker.NewEventOutboxStoreManagerAdapter(storeManager),
+			publisher,
+			cfg.EventBus.TopicPrefix,
+			time.Duration(cfg.EventBus.PollInterval),
+			cfg.EventBus.BatchSize,
+			cfg.EventBus.Stores,
+			time.Duration(cfg.EventBus.OutboxRetention),
+		)
+		startWorker(ctx, &wg, "eventbus-coordinator", elector.Lead(eventBusCoordinator.Run))
+	} else {
+		// Stop capture left on by an earlier event bus configuration
+		worker.DisableEventOutboxes(ctx, worker.NewEventOutboxStoreManagerAdapter(storeManager))
+	}
 
 	// 11. Start HTTP server in goroutine
 	go func() {
@@ -207,6 +566,32 @@ func run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// newElector creates the leader elector for background jobs, or returns nil
+// when leader election is disabled.
+func newElector(cfg config.LeaderConfig, storesRoot string) (*leader.Elector, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	path := cfg.LeasePath
+	if path == "" {
+		path = filepath.Join(storesRoot, ".leader.db")
+	}
+	id := cfg.ID
+	if id == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("resolve leader ID: %w", err)
+		}
+		id = hostname
+	}
+	elector, err := leader.NewElector(path, id, time.Duration(cfg.LeaseDuration))
+	if err != nil {
+		return nil, fmt.Errorf("initialize leader election: %w", err)
+	}
+	slog.Info("leader election enabled", "id", id, "lease_path", path)
+	return elector, nil
+}
+
 func parseLogLevel(level string) slog.Level {
 	switch level {
 	case "debug":
@@ -220,6 +605,36 @@ func parseLogLevel(level string) slog.Level {
 	}
 }
 
+// pendingEmbeddings returns a probe summing the embedding backlog across
+// all managed stores, used by the load shedder.
+func pendingEmbeddings(manager *multistore.StoreManager) func(ctx context.Context) (int64, error) {
+	return func(ctx context.Context) (int64, error) {
+		stores, err := manager.ListActiveStores(ctx)
+		if err != nil {
+			return 0, err
+		}
+		var total int64
+		for _, info := range stores {
+			managed, err := manager.GetStore(ctx, info.ID)
+			if err != nil {
+				return 0, err
+			}
+			counter, ok := managed.Store.(interface {
+				CountPendingEmbeddings(ctx context.Context) (int64, error)
+			})
+			if !ok {
+				continue
+			}
+			n, err := counter.CountPendingEmbeddings(ctx)
+			if err != nil {
+				return 0, err
+			}
+			total += n
+		}
+		return total, nil
+	}
+}
+
 // startWorker launches a background worker goroutine that respects context cancellation.
 // Workers are tracked via WaitGroup for graceful shutdown.
 // Note: Workers log their own start/stop messages with detailed context.
@@ -32,23 +32,46 @@ func init() {
 	storeCmd.AddCommand(storeListCmd)
 	storeCmd.AddCommand(storeInfoCmd)
 	storeCmd.AddCommand(storeDeleteCmd)
+	storeCmd.AddCommand(storeQuantizationCmd)
 }
 
 // resolveStoreManager creates a StoreManager from config with optional --root override.
-// Calls initPlugins() to ensure schema migrations are available.
+// Calls initPlugins() and loads external plugins to ensure schema migrations
+// are available.
 func resolveStoreManager() (*multistore.StoreManager, error) {
 	initPlugins()
 
+	pluginsCfg, err := config.LoadPluginsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	if err := loadExternalPlugins(pluginsCfg.Dir); err != nil {
+		return nil, err
+	}
+
+	storesCfg, err := config.LoadStoresConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
 	rootPath := storeRootOverride
 	if rootPath == "" {
-		storesCfg, err := config.LoadStoresConfig()
-		if err != nil {
-			return nil, fmt.Errorf("load config: %w", err)
-		}
 		rootPath = storesCfg.RootPath
 	}
 
-	return multistore.NewStoreManager(rootPath)
+	return multistore.NewStoreManager(rootPath, storeLayout(*storesCfg)...)
+}
+
+// storeLayout returns the manager options placing stores and archives
+// outside the stores root, as configured.
+func storeLayout(cfg config.StoresConfig) []multistore.ManagerOption {
+	paths := make([]multistore.StorePath, len(cfg.Paths))
+	for i, p := range cfg.Paths {
+		paths[i] = multistore.StorePath{Match: p.Match, Root: p.Path}
+	}
+	return []multistore.ManagerOption{
+		multistore.WithStorePaths(paths),
+		multistore.WithArchiveRoot(cfg.ArchivePath),
+	}
 }
 
 // printJSON marshals v to JSON and writes to the given writer.
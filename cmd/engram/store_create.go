@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/hyperengineering/engram/internal/multistore"
 	"github.com/spf13/cobra"
@@ -12,6 +13,7 @@ import (
 var (
 	createType        string
 	createDescription string
+	createTemplate    string
 	createIfNotExists bool
 )
 
@@ -25,9 +27,11 @@ var storeCreateCmd = &cobra.Command{
 
 func init() {
 	storeCreateCmd.Flags().StringVar(&createType, "type", "",
-		"Store type: recall, tract (default: recall)")
+		"Store type: recall, tract, or an external plugin type (default: recall)")
 	storeCreateCmd.Flags().StringVar(&createDescription, "description", "",
 		"Human-readable description")
+	storeCreateCmd.Flags().StringVar(&createTemplate, "template", "",
+		"Seed type and settings from a template: "+strings.Join(multistore.TemplateNames(), ", "))
 	storeCreateCmd.Flags().BoolVar(&createIfNotExists, "if-not-exists", false,
 		"Exit 0 if store already exists")
 }
@@ -42,7 +46,19 @@ func runStoreCreate(cmd *cobra.Command, args []string) error {
 	}
 	defer mgr.Close()
 
-	managed, err := mgr.CreateStore(ctx, storeID, createType, createDescription)
+	var managed *multistore.ManagedStore
+	if createTemplate != "" {
+		t, terr := multistore.Template(createTemplate)
+		if terr != nil {
+			return terr
+		}
+		if createType != "" && createType != t.Type {
+			return fmt.Errorf("--type %s conflicts with template %s, which creates %s stores", createType, t.Name, t.Type)
+		}
+		managed, err = mgr.CreateStoreFromTemplate(ctx, storeID, createTemplate, createDescription)
+	} else {
+		managed, err = mgr.CreateStore(ctx, storeID, createType, createDescription)
+	}
 	if err != nil {
 		if errors.Is(err, multistore.ErrStoreAlreadyExists) && createIfNotExists {
 			// Idempotent mode: load existing store and report it
@@ -71,9 +87,14 @@ func runStoreCreate(cmd *cobra.Command, args []string) error {
 			"type":        managed.Type(),
 			"created":     managed.Meta.Created,
 			"description": managed.Meta.Description,
+			"template":    managed.Meta.Template,
 		})
 	}
 
+	if managed.Meta.Template != "" {
+		fmt.Fprintf(cmd.OutOrStdout(), "Created store %q (type: %s, template: %s)\n", managed.ID, managed.Type(), managed.Meta.Template)
+		return nil
+	}
 	fmt.Fprintf(cmd.OutOrStdout(), "Created store %q (type: %s)\n", managed.ID, managed.Type())
 	return nil
 }
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	quantizationQueries int
+	quantizationK       int
+)
+
+var storeQuantizationCmd = &cobra.Command{
+	Use:   "quantization <store-id>",
+	Short: "Report how embedding quantization would affect a store",
+	Long: "Compare similarity search over a store's embeddings as stored and as quantized with float16 and int8, " +
+		"reporting recall of the nearest entries, similarity error and size. The store is only read.",
+	Args: cobra.ExactArgs(1),
+	RunE: runStoreQuantization,
+}
+
+func init() {
+	storeQuantizationCmd.Flags().IntVar(&quantizationQueries, "queries", 100,
+		"Entries used as search queries")
+	storeQuantizationCmd.Flags().IntVar(&quantizationK, "k", 10,
+		"Nearest entries compared per query")
+}
+
+// quantizationEvaluator is implemented by stores that can evaluate
+// embedding quantization.
+type quantizationEvaluator interface {
+	EvaluateQuantization(ctx context.Context, q store.Quantization, queries, k int) (*store.QuantizationReport, error)
+}
+
+func runStoreQuantization(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	mgr, err := resolveStoreManager()
+	if err != nil {
+		return err
+	}
+	defer mgr.Close()
+
+	managed, err := mgr.GetStore(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	evaluator, ok := managed.Store.(quantizationEvaluator)
+	if !ok {
+		return fmt.Errorf("store %q does not support quantization reports", args[0])
+	}
+
+	var reports []*store.QuantizationReport
+	for _, q := range []store.Quantization{store.QuantizationFloat16, store.QuantizationInt8} {
+		report, err := evaluator.EvaluateQuantization(ctx, q, quantizationQueries, quantizationK)
+		if err != nil {
+			return fmt.Errorf("evaluate %s: %w", q, err)
+		}
+		reports = append(reports, report)
+	}
+
+	out := cmd.OutOrStdout()
+	if storeJSONOutput {
+		return printJSON(out, map[string]any{"reports": reports})
+	}
+
+	if reports[0].Entries < 2 {
+		fmt.Fprintf(out, "Store %s has %d embedded entries; nothing to compare.\n", managed.ID, reports[0].Entries)
+		return nil
+	}
+	fmt.Fprintf(out, "Store %s: %d embedded entries, %d queries\n\n", managed.ID, reports[0].Entries, reports[0].Queries)
+	w := newTabWriter(out)
+	fmt.Fprintln(w, "QUANTIZATION\tRECALL@K\tMEAN ERROR\tMAX ERROR\tSIZE")
+	for _, r := range reports {
+		fmt.Fprintf(w, "%s\t%.3f (k=%d)\t%.5f\t%.5f\t%s (from %s)\n",
+			r.Quantization,
+			r.RecallAtK,
+			r.K,
+			r.MeanSimilarityError,
+			r.MaxSimilarityError,
+			formatSize(r.QuantizedBytes),
+			formatSize(r.StoredBytes),
+		)
+	}
+	return w.Flush()
+}
@@ -27,8 +27,11 @@ func executeStoreCmd(t *testing.T, rootPath string, args ...string) (stdout, std
 	storeJSONOutput = false
 	createType = ""
 	createDescription = ""
+	createTemplate = ""
 	createIfNotExists = false
 	deleteForce = false
+	quantizationQueries = 100
+	quantizationK = 10
 
 	// Build full args: "store" + subcommand args + "--root" + rootPath
 	fullArgs := append([]string{"store"}, args...)
@@ -62,8 +65,11 @@ func executeStoreCmdWithStdin(t *testing.T, rootPath string, stdin string, args
 	storeJSONOutput = false
 	createType = ""
 	createDescription = ""
+	createTemplate = ""
 	createIfNotExists = false
 	deleteForce = false
+	quantizationQueries = 100
+	quantizationK = 10
 
 	fullArgs := append([]string{"store"}, args...)
 	fullArgs = append(fullArgs, "--root", rootPath)
@@ -120,6 +126,33 @@ func TestStoreCreate_WithTypeAndDescription(t *testing.T) {
 	}
 }
 
+func TestStoreCreate_WithTemplate(t *testing.T) {
+	root := t.TempDir()
+	stdout, _, err := executeStoreCmd(t, root, "create", "strict", "--template", "recall-strict-dedup")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(stdout, "template: recall-strict-dedup") {
+		t.Errorf("stdout = %q, want it to name the template", stdout)
+	}
+	meta, err := os.ReadFile(filepath.Join(root, "strict", "meta.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(meta), "similarity_threshold: 0.85") {
+		t.Errorf("meta.yaml = %s, want the template's dedup threshold", meta)
+	}
+}
+
+func TestStoreCreate_TemplateAndTypeConflict(t *testing.T) {
+	root := t.TempDir()
+	_, _, err := executeStoreCmd(t, root, "create", "strict", "--template", "tract", "--type", "recall")
+	if err == nil {
+		t.Fatal("expected error combining --type and --template")
+	}
+}
+
 func TestStoreCreate_NestedID(t *testing.T) {
 	root := t.TempDir()
 	stdout, _, err := executeStoreCmd(t, root, "create", "org/team/project")
@@ -425,6 +458,59 @@ func TestStoreInfo_JSONOutput(t *testing.T) {
 	}
 }
 
+// --- Quantization Tests ---
+
+func TestStoreQuantization_EmptyStore(t *testing.T) {
+	root := t.TempDir()
+
+	if _, _, err := executeStoreCmd(t, root, "create", "my-project"); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	stdout, _, err := executeStoreCmd(t, root, "quantization", "my-project")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "nothing to compare") {
+		t.Errorf("stdout = %q, want it to say there is nothing to compare", stdout)
+	}
+}
+
+func TestStoreQuantization_JSONOutput(t *testing.T) {
+	root := t.TempDir()
+
+	if _, _, err := executeStoreCmd(t, root, "create", "my-project"); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	stdout, _, err := executeStoreCmd(t, root, "quantization", "my-project", "--json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		Reports []map[string]any `json:"reports"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\nraw: %s", err, stdout)
+	}
+	if len(result.Reports) != 2 {
+		t.Fatalf("got %d reports, want 2", len(result.Reports))
+	}
+	if result.Reports[0]["quantization"] != "float16" || result.Reports[1]["quantization"] != "int8" {
+		t.Errorf("reports = %v, want float16 then int8", result.Reports)
+	}
+}
+
+func TestStoreQuantization_Nonexistent(t *testing.T) {
+	root := t.TempDir()
+
+	_, _, err := executeStoreCmd(t, root, "quantization", "nonexistent")
+	if err == nil {
+		t.Fatal("expected error for nonexistent store, got nil")
+	}
+}
+
 // --- Delete Tests ---
 
 func TestStoreDelete_WithForce(t *testing.T) {
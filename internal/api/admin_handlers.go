@@ -0,0 +1,395 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/validation"
+)
+
+// RescanStores handles POST /api/v1/admin/stores/rescan
+//
+// Reconciles open stores with the store directories on disk after an
+// operator restores, adds or removes stores while the server runs.
+func (h *Handler) RescanStores(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if h.storeManager == nil {
+		WriteProblem(w, r, http.StatusServiceUnavailable, "Multi-store support not configured")
+		return
+	}
+
+	report, err := h.storeManager.RescanStores(ctx)
+	if err != nil {
+		slog.Error("store rescan failed", "error", err)
+		WriteProblem(w, r, http.StatusInternalServerError, "Internal error rescanning stores")
+		return
+	}
+
+	slog.Info("stores rescanned via API",
+		"component", "api",
+		"action", "rescan_stores",
+		"stores", report.Stores,
+		"request_id", GetRequestID(ctx),
+		"remote_addr", r.RemoteAddr,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// SimilarityReindexer is implemented by stores whose similarity index can
+// be rebuilt from their stored embeddings. Implemented by SQLiteStore.
+type SimilarityReindexer interface {
+	ReindexSimilarity(ctx context.Context) (*store.ReindexReport, error)
+}
+
+// ReindexStore handles POST /api/v1/admin/stores/{store_id}/reindex
+//
+// Rebuilds the store's similarity index from scratch and verifies its
+// counts, for recovering from index drift. The rebuild is all or nothing:
+// a failed verification leaves the previous index in place.
+func (h *Handler) ReindexStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	storeID := StoreIDFromContext(ctx)
+
+	reindexer, ok := h.getStoreForRequest(r).(SimilarityReindexer)
+	if !ok {
+		WriteProblem(w, r, http.StatusNotImplemented, "Store does not support reindexing")
+		return
+	}
+
+	report, err := reindexer.ReindexSimilarity(ctx)
+	if err != nil {
+		slog.Error("similarity reindex failed",
+			"component", "api",
+			"action", "reindex_store_failed",
+			"store_id", storeID,
+			"error", err,
+		)
+		WriteProblem(w, r, http.StatusInternalServerError, "Internal error rebuilding similarity index")
+		return
+	}
+
+	slog.Info("store reindexed via API",
+		"component", "api",
+		"action", "reindex_store",
+		"store_id", storeID,
+		"repaired", report.Repaired,
+		"orphan_chunks", report.OrphanChunks,
+		"orphan_embeddings", report.OrphanEmbeddings,
+		"request_id", GetRequestID(ctx),
+		"remote_addr", r.RemoteAddr,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// LoreRecategorizer is implemented by stores that can move lore between
+// categories in bulk. Implemented by SQLiteStore.
+type LoreRecategorizer interface {
+	RecategorizeLore(ctx context.Context, rc types.Recategorization) (*types.RecategorizeResult, error)
+}
+
+// RecategorizeLore handles POST /api/v1/admin/lore/recategorize and
+// POST /api/v1/admin/stores/{store_id}/lore/recategorize
+//
+// Moves every live entry in one category, optionally narrowed to a repo and
+// path, to another, for when a team restructures its taxonomy. Each move is
+// versioned and recorded in the change log like an edit, so clients pick it
+// up on their next sync. With dry_run the matching entries are listed but
+// not moved.
+func (h *Handler) RecategorizeLore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	storeID := StoreIDFromContext(ctx)
+
+	var req types.Recategorization
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err.Error()))
+		return
+	}
+	if errs := validation.ValidateRecategorization(req); len(errs) > 0 {
+		WriteProblemWithErrors(w, r, "Request contains invalid fields", errs)
+		return
+	}
+
+	recategorizer, ok := h.getStoreForRequest(r).(LoreRecategorizer)
+	if !ok {
+		WriteProblem(w, r, http.StatusNotImplemented, "Store does not support recategorization")
+		return
+	}
+
+	result, err := recategorizer.RecategorizeLore(ctx, req)
+	if err != nil {
+		slog.Error("lore recategorization failed",
+			"component", "api",
+			"action", "recategorize_lore_failed",
+			"store_id", storeID,
+			"from", req.From,
+			"to", req.To,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	slog.Info("lore recategorized via API",
+		"component", "api",
+		"action", "recategorize_lore",
+		"store_id", storeID,
+		"from", req.From,
+		"to", req.To,
+		"moved", result.Moved,
+		"dry_run", result.DryRun,
+		"source_id", req.SourceID,
+		"request_id", GetRequestID(ctx),
+		"remote_addr", r.RemoteAddr,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// ConfidenceRecalibrator is implemented by stores that can rescale lore
+// confidence in bulk. Implemented by SQLiteStore.
+type ConfidenceRecalibrator interface {
+	RecalibrateConfidence(ctx context.Context, rc types.ConfidenceRecalibration) (*types.RecalibrationResult, error)
+}
+
+// RecalibrateConfidence handles POST /api/v1/admin/lore/recalibrate and
+// POST /api/v1/admin/stores/{store_id}/lore/recalibrate
+//
+// Rescales confidence to a target mean and spread, or through a mapping
+// table, after months of boosts and decay have drifted the scores. The
+// response compares the distribution before and after; with dry_run
+// nothing is changed.
+func (h *Handler) RecalibrateConfidence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	storeID := StoreIDFromContext(ctx)
+
+	var req types.ConfidenceRecalibration
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err.Error()))
+		return
+	}
+	if errs := validation.ValidateConfidenceRecalibration(req); len(errs) > 0 {
+		WriteProblemWithErrors(w, r, "Request contains invalid fields", errs)
+		return
+	}
+
+	recalibrator, ok := h.getStoreForRequest(r).(ConfidenceRecalibrator)
+	if !ok {
+		WriteProblem(w, r, http.StatusNotImplemented, "Store does not support confidence recalibration")
+		return
+	}
+
+	result, err := recalibrator.RecalibrateConfidence(ctx, req)
+	if err != nil {
+		slog.Error("confidence recalibration failed",
+			"component", "api",
+			"action", "recalibrate_confidence_failed",
+			"store_id", storeID,
+			"error", err,
+		)
+		WriteProblem(w, r, http.StatusInternalServerError, "Internal error recalibrating confidence")
+		return
+	}
+
+	slog.Info("confidence recalibrated via API",
+		"component", "api",
+		"action", "recalibrate_confidence",
+		"store_id", storeID,
+		"category", req.Category,
+		"changed", result.Changed,
+		"dry_run", result.DryRun,
+		"request_id", GetRequestID(ctx),
+		"remote_addr", r.RemoteAddr,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// SnapshotStatsProvider is implemented by stores that report their
+// snapshot state without computing full extended stats. Implemented by
+// SQLiteStore.
+type SnapshotStatsProvider interface {
+	GetSnapshotStats(ctx context.Context) (types.SnapshotStats, error)
+}
+
+// StoreSnapshotStats is one store's entry in ListSnapshotsResponse.
+type StoreSnapshotStats struct {
+	StoreID string `json:"store_id"`
+	types.SnapshotStats
+	// Stale is set when the store has no snapshot, or one older than the
+	// request's stale_after.
+	Stale bool   `json:"stale"`
+	Error string `json:"error,omitempty"` // set when the store could not be read
+}
+
+// ListSnapshotsResponse is the response body for GET /api/v1/admin/snapshots.
+type ListSnapshotsResponse struct {
+	Stores    []StoreSnapshotStats `json:"stores"`
+	Stale     int                  `json:"stale"`
+	StatsAsOf time.Time            `json:"stats_as_of"`
+}
+
+// ListSnapshots handles GET /api/v1/admin/snapshots
+//
+// Reports snapshot availability, age, size and pending-entry drift for every
+// active store in one call, for alerting on stale snapshots. With
+// ?stale_after=<duration>, snapshots older than that are flagged stale as
+// well as missing ones.
+func (h *Handler) ListSnapshots(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if h.storeManager == nil {
+		WriteProblem(w, r, http.StatusServiceUnavailable, "Multi-store support not configured")
+		return
+	}
+
+	var staleAfter time.Duration
+	if v := r.URL.Query().Get("stale_after"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			WriteProblem(w, r, http.StatusBadRequest, "stale_after must be a positive duration (e.g. 6h)")
+			return
+		}
+		staleAfter = d
+	}
+
+	storeInfos, err := h.storeManager.ListActiveStores(ctx)
+	if err != nil {
+		slog.Error("list stores failed", "error", err)
+		WriteProblem(w, r, http.StatusInternalServerError, "Internal error listing stores")
+		return
+	}
+
+	resp := ListSnapshotsResponse{
+		Stores:    make([]StoreSnapshotStats, 0, len(storeInfos)),
+		StatsAsOf: time.Now().UTC(),
+	}
+	for _, info := range storeInfos {
+		item := StoreSnapshotStats{StoreID: info.ID}
+		if managed, err := h.storeManager.GetStore(ctx, info.ID); err != nil {
+			item.Error = err.Error()
+		} else if provider, ok := managed.Store.(SnapshotStatsProvider); !ok {
+			item.Error = "store does not report snapshot stats"
+		} else if item.SnapshotStats, err = provider.GetSnapshotStats(ctx); err != nil {
+			slog.Error("get snapshot stats failed", "store_id", info.ID, "error", err)
+			item.Error = "internal error getting snapshot stats"
+		}
+		item.Stale = !item.Available ||
+			(staleAfter > 0 && time.Duration(item.AgeSeconds)*time.Second > staleAfter)
+		if item.Stale {
+			resp.Stale++
+		}
+		resp.Stores = append(resp.Stores, item)
+	}
+
+	sort.Slice(resp.Stores, func(i, j int) bool {
+		return resp.Stores[i].StoreID < resp.Stores[j].StoreID
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// MaintenanceRequest is the body of PUT /api/v1/admin/maintenance and
+// PUT /api/v1/admin/stores/{store_id}/maintenance.
+type MaintenanceRequest struct {
+	// Reason is appended to the detail of refused writes.
+	Reason string `json:"reason,omitempty"`
+	// RetryAfter is a duration (e.g. 10m) sent as Retry-After with refused
+	// writes. Empty uses DefaultMaintenanceRetryAfter.
+	RetryAfter string `json:"retry_after,omitempty"`
+}
+
+// GetMaintenance handles GET /api/v1/admin/maintenance
+//
+// Lists the server-wide and per-store maintenance flags currently set.
+func (h *Handler) GetMaintenance(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.maintenance.Status())
+}
+
+// SetMaintenance handles PUT /api/v1/admin/maintenance and
+// PUT /api/v1/admin/stores/{store_id}/maintenance
+//
+// Makes the server, or one store, read-only: writes are refused with 503
+// and Retry-After until the flag is cleared, while reads, snapshots and
+// deltas keep working. Admin routes are not affected.
+func (h *Handler) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+	storeID := maintenanceStoreID(r)
+
+	var req MaintenanceRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err.Error()))
+			return
+		}
+	}
+	var retryAfter time.Duration
+	if req.RetryAfter != "" {
+		d, err := time.ParseDuration(req.RetryAfter)
+		if err != nil || d < time.Second {
+			WriteProblem(w, r, http.StatusBadRequest, "retry_after must be a duration of at least 1s (e.g. 10m)")
+			return
+		}
+		retryAfter = d
+	}
+
+	win := h.maintenance.Enable(storeID, req.Reason, retryAfter)
+
+	slog.Warn("maintenance mode enabled",
+		"component", "api",
+		"action", "maintenance_enabled",
+		"store_id", storeID,
+		"reason", req.Reason,
+		"retry_after_seconds", win.RetryAfterSeconds,
+		"request_id", GetRequestID(r.Context()),
+		"remote_addr", r.RemoteAddr,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(win)
+}
+
+// ClearMaintenance handles DELETE /api/v1/admin/maintenance and
+// DELETE /api/v1/admin/stores/{store_id}/maintenance
+//
+// Clears a maintenance flag. Returns 404 if none was set.
+func (h *Handler) ClearMaintenance(w http.ResponseWriter, r *http.Request) {
+	storeID := maintenanceStoreID(r)
+
+	if !h.maintenance.Disable(storeID) {
+		WriteProblem(w, r, http.StatusNotFound, "Maintenance mode is not enabled")
+		return
+	}
+
+	slog.Info("maintenance mode disabled",
+		"component", "api",
+		"action", "maintenance_disabled",
+		"store_id", storeID,
+		"request_id", GetRequestID(r.Context()),
+		"remote_addr", r.RemoteAddr,
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// maintenanceStoreID returns the store a maintenance request targets, or ""
+// for the server-wide flag.
+func maintenanceStoreID(r *http.Request) string {
+	if !IsStoreScoped(r.Context()) {
+		return ""
+	}
+	return StoreIDFromContext(r.Context())
+}
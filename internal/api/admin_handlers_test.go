@@ -0,0 +1,266 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestRescanStores_API(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+
+	if _, err := manager.CreateStore(context.Background(), "restored", "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &mockStore{stats: &types.StoreStats{}}
+	embedder := &mockEmbedder{model: "test-model"}
+
+	rescan := func(router http.Handler, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/stores/rescan", nil)
+		req.Header.Set("Authorization", "Bearer "+key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	handler := NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0", WithAdminKey("admin-key"))
+	router := NewRouter(handler, manager)
+
+	w := rescan(router, "admin-key")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var report multistore.RescanReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if report.Stores != 1 || report.Reloaded == nil || report.Invalid == nil {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	if w := rescan(router, "test-api-key"); w.Code != http.StatusUnauthorized {
+		t.Errorf("client key: expected status 401, got %d", w.Code)
+	}
+
+	noAdmin := NewRouter(NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0"), manager)
+	if w := rescan(noAdmin, "test-api-key"); w.Code != http.StatusNotFound {
+		t.Errorf("without admin key: expected status 404, got %d", w.Code)
+	}
+}
+
+func TestReindexStore_API(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+
+	s := &mockStore{stats: &types.StoreStats{}}
+	embedder := &mockEmbedder{model: "test-model"}
+	handler := NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0", WithAdminKey("admin-key"))
+	router := NewRouter(handler, manager)
+
+	reindex := func(storeID, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/stores/"+storeID+"/reindex", nil)
+		req.Header.Set("Authorization", "Bearer "+key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := reindex("default", "admin-key")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var report store.ReindexReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if report.Repaired != 0 || report.OrphanChunks != 0 {
+		t.Errorf("unexpected report for an empty store: %+v", report)
+	}
+
+	if w := reindex("default", "test-api-key"); w.Code != http.StatusUnauthorized {
+		t.Errorf("client key: expected status 401, got %d", w.Code)
+	}
+	if w := reindex("missing", "admin-key"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown store: expected status 404, got %d", w.Code)
+	}
+}
+
+func TestRecategorizeLore_API(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+
+	ctx := context.Background()
+	managed, err := manager.GetStore(ctx, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := managed.Store.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Retry payments", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src"},
+		{Content: "Cache warmup", Category: "PERFORMANCE_INSIGHT", Confidence: 0.5, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &mockStore{stats: &types.StoreStats{}}
+	embedder := &mockEmbedder{model: "test-model"}
+	handler := NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0", WithAdminKey("admin-key"))
+	router := NewRouter(handler, manager)
+
+	recategorize := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer admin-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := recategorize("/api/v1/admin/lore/recategorize",
+		`{"from":"PATTERN_OUTCOME","to":"TESTING_STRATEGY","source_id":"taxonomy"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result types.RecategorizeResult
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Moved != 1 || len(result.IDs) != 1 || result.DryRun {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	entry, err := managed.Store.GetLore(ctx, result.IDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Category != "TESTING_STRATEGY" {
+		t.Errorf("category = %s, want TESTING_STRATEGY", entry.Category)
+	}
+
+	w = recategorize("/api/v1/admin/stores/default/lore/recategorize",
+		`{"from":"PERFORMANCE_INSIGHT","to":"PERFORMANCE_INSIGHT","source_id":"taxonomy"}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("same category: expected status 422, got %d", w.Code)
+	}
+	w = recategorize("/api/v1/admin/stores/missing/lore/recategorize",
+		`{"from":"PERFORMANCE_INSIGHT","to":"TESTING_STRATEGY","source_id":"taxonomy"}`)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown store: expected status 404, got %d", w.Code)
+	}
+}
+
+func TestRecalibrateConfidence_API(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+
+	ctx := context.Background()
+	managed, err := manager.GetStore(ctx, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := managed.Store.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Retry payments", Category: "PATTERN_OUTCOME", Confidence: 0.9, SourceID: "src"},
+		{Content: "Cache warmup", Category: "PERFORMANCE_INSIGHT", Confidence: 0.7, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &mockStore{stats: &types.StoreStats{}}
+	embedder := &mockEmbedder{model: "test-model"}
+	handler := NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0", WithAdminKey("admin-key"))
+	router := NewRouter(handler, manager)
+
+	recalibrate := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer admin-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := recalibrate("/api/v1/admin/stores/default/lore/recalibrate", `{"target_mean":0.5,"dry_run":true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result types.RecalibrationResult
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Changed != 2 || !result.DryRun || result.Before.Count != 2 || result.After.Max != 0.6 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	if w := recalibrate("/api/v1/admin/lore/recalibrate", `{}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("no targets: expected status 422, got %d", w.Code)
+	}
+}
+
+func TestListSnapshots_API(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+
+	ctx := context.Background()
+	if _, err := manager.CreateStore(ctx, "team-a", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	managed, err := manager.GetStore(ctx, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	managed.Store.(*store.SQLiteStore).SetSnapshotMeta(0, 4096, time.Now().UTC().Add(-2*time.Hour))
+
+	s := &mockStore{stats: &types.StoreStats{}}
+	embedder := &mockEmbedder{model: "test-model"}
+	handler := NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0", WithAdminKey("admin-key"))
+	router := NewRouter(handler, manager)
+
+	list := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/snapshots"+query, nil)
+		req.Header.Set("Authorization", "Bearer admin-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, tt := range []struct {
+		query        string
+		defaultStale bool
+		stale        int
+	}{
+		{"", false, 1},
+		{"?stale_after=1h", true, 2},
+	} {
+		w := list(tt.query)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected status 200, got %d: %s", tt.query, w.Code, w.Body.String())
+		}
+		var resp ListSnapshotsResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(resp.Stores) != 2 || resp.Stores[0].StoreID != "default" || resp.Stores[1].StoreID != "team-a" {
+			t.Fatalf("%q: stores = %+v", tt.query, resp.Stores)
+		}
+		def, team := resp.Stores[0], resp.Stores[1]
+		if !def.Available || def.SizeBytes != 4096 || def.AgeSeconds < 7200 || def.Stale != tt.defaultStale {
+			t.Errorf("%q: default = %+v", tt.query, def)
+		}
+		if team.Available || !team.Stale {
+			t.Errorf("%q: team-a without a snapshot = %+v", tt.query, team)
+		}
+		if resp.Stale != tt.stale {
+			t.Errorf("%q: stale = %d, want %d", tt.query, resp.Stale, tt.stale)
+		}
+	}
+
+	if w := list("?stale_after=soon"); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid stale_after: expected status 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,259 @@
+package api
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported response encodings, in server preference order.
+const (
+	encodingZstd = "zstd"
+	encodingGzip = "gzip"
+)
+
+var gzipWriterPool = sync.Pool{
+	New: func() any {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	},
+}
+
+var zstdEncoderPool = sync.Pool{
+	New: func() any {
+		// Options are constant, so NewWriter cannot fail here.
+		enc, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault), zstd.WithEncoderConcurrency(1))
+		return enc
+	},
+}
+
+// CompressionMiddleware compresses responses with zstd or gzip when the
+// client advertises support via Accept-Encoding. zstd is preferred when
+// both are acceptable with equal weight.
+//
+// Responses that already carry a Content-Encoding, and bodiless statuses
+// (204, 304), are passed through unchanged.
+func CompressionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks the best supported encoding from an
+// Accept-Encoding header, honoring q-values. Returns "" for identity.
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if v, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		weights[name] = q
+	}
+
+	weight := func(enc string) float64 {
+		if q, ok := weights[enc]; ok {
+			return q
+		}
+		if q, ok := weights["*"]; ok {
+			return q
+		}
+		return 0
+	}
+
+	best, bestQ := "", 0.0
+	for _, enc := range []string{encodingZstd, encodingGzip} {
+		if q := weight(enc); q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+// maxDecoderWindow bounds the memory a zstd request body may make the decoder
+// allocate. It matches the window of the default compression levels.
+const maxDecoderWindow = 8 << 20
+
+var zstdDecoderPool = sync.Pool{
+	New: func() any {
+		// Options are constant, so NewReader cannot fail here.
+		dec, _ := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxWindow(maxDecoderWindow))
+		return dec
+	},
+}
+
+// DecompressionMiddleware decodes request bodies sent with
+// Content-Encoding gzip or zstd, so clients can compress large uploads.
+// Handlers see the decompressed body, so their body size limits cap the
+// decompressed size rather than the bytes on the wire. Other encodings are
+// rejected with 415.
+func DecompressionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+		var body io.ReadCloser
+		switch encoding {
+		case "", "identity":
+			next.ServeHTTP(w, r)
+			return
+		case encodingGzip:
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				WriteProblem(w, r, http.StatusBadRequest, "Request body is not valid gzip")
+				return
+			}
+			body = gz
+		case encodingZstd:
+			dec := zstdDecoderPool.Get().(*zstd.Decoder)
+			if err := dec.Reset(r.Body); err != nil {
+				zstdDecoderPool.Put(dec)
+				WriteProblem(w, r, http.StatusBadRequest, "Request body is not valid zstd")
+				return
+			}
+			body = &zstdBody{dec: dec}
+		default:
+			WriteProblem(w, r, http.StatusUnsupportedMediaType,
+				fmt.Sprintf("Unsupported Content-Encoding %q; use gzip or zstd", encoding))
+			return
+		}
+		defer body.Close()
+
+		r.Header.Del("Content-Encoding")
+		r.Header.Del("Content-Length")
+		r.ContentLength = -1
+		r.Body = body
+		next.ServeHTTP(w, r)
+	})
+}
+
+// zstdBody reads a request body through a pooled decoder, returning it to
+// the pool on Close.
+type zstdBody struct {
+	dec *zstd.Decoder
+}
+
+func (b *zstdBody) Read(p []byte) (int, error) {
+	if b.dec == nil {
+		return 0, io.ErrClosedPipe
+	}
+	return b.dec.Read(p)
+}
+
+func (b *zstdBody) Close() error {
+	if b.dec != nil {
+		b.dec.Reset(nil)
+		zstdDecoderPool.Put(b.dec)
+		b.dec = nil
+	}
+	return nil
+}
+
+// compressWriter lazily wraps the response body in an encoder once the
+// handler commits to a status code.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	encoder     io.WriteCloser
+	wroteHeader bool
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+
+	h := cw.Header()
+	if code != http.StatusNoContent && code != http.StatusNotModified && h.Get("Content-Encoding") == "" {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", cw.encoding)
+		cw.encoder = cw.newEncoder()
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.encoder == nil {
+		return cw.ResponseWriter.Write(b)
+	}
+	return cw.encoder.Write(b)
+}
+
+// Flush flushes buffered compressed data to the client.
+func (cw *compressWriter) Flush() {
+	switch enc := cw.encoder.(type) {
+	case *gzip.Writer:
+		enc.Flush()
+	case *zstd.Encoder:
+		enc.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close finishes the compressed stream and returns the encoder to its pool.
+func (cw *compressWriter) Close() error {
+	if cw.encoder == nil {
+		return nil
+	}
+	err := cw.encoder.Close()
+	switch enc := cw.encoder.(type) {
+	case *gzip.Writer:
+		gzipWriterPool.Put(enc)
+	case *zstd.Encoder:
+		zstdEncoderPool.Put(enc)
+	}
+	cw.encoder = nil
+	return err
+}
+
+func (cw *compressWriter) newEncoder() io.WriteCloser {
+	switch cw.encoding {
+	case encodingZstd:
+		enc := zstdEncoderPool.Get().(*zstd.Encoder)
+		enc.Reset(cw.ResponseWriter)
+		return enc
+	default:
+		gz := gzipWriterPool.Get().(*gzip.Writer)
+		gz.Reset(cw.ResponseWriter)
+		return gz
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
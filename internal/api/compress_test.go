@@ -0,0 +1,275 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"gzip, deflate, br", "gzip"},
+		{"zstd", "zstd"},
+		{"gzip, zstd", "zstd"},
+		{"zstd;q=0.5, gzip", "gzip"},
+		{"gzip;q=0, zstd;q=0", ""},
+		{"*", "zstd"},
+		{"*, zstd;q=0", "gzip"},
+		{"GZIP", "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := negotiateEncoding(tt.header); got != tt.want {
+				t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+var compressBody = strings.Repeat(`{"id":"entry","embedding":"AAAAAAAAAAAAAAAA"}`, 200)
+
+func serveCompressed(acceptEncoding string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	CompressionMiddleware(handler).ServeHTTP(w, req)
+	return w
+}
+
+func jsonHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", "999")
+	io.WriteString(w, compressBody)
+}
+
+func TestCompressionMiddleware_Gzip(t *testing.T) {
+	w := serveCompressed("gzip", jsonHandler)
+
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if w.Header().Get("Content-Length") != "" {
+		t.Error("Content-Length should be removed when compressing")
+	}
+	if w.Header().Get("Vary") != "Accept-Encoding" {
+		t.Errorf("Vary = %q, want Accept-Encoding", w.Header().Get("Vary"))
+	}
+	if w.Body.Len() >= len(compressBody) {
+		t.Errorf("compressed size %d not smaller than %d", w.Body.Len(), len(compressBody))
+	}
+
+	gr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	got, err := io.ReadAll(gr)
+	if err != nil {
+		t.Fatalf("read gzip body: %v", err)
+	}
+	if string(got) != compressBody {
+		t.Error("decompressed body does not match original")
+	}
+}
+
+func TestCompressionMiddleware_Zstd(t *testing.T) {
+	w := serveCompressed("gzip, zstd", jsonHandler)
+
+	if got := w.Header().Get("Content-Encoding"); got != "zstd" {
+		t.Fatalf("Content-Encoding = %q, want zstd", got)
+	}
+
+	dec, err := zstd.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("zstd.NewReader: %v", err)
+	}
+	defer dec.Close()
+	got, err := io.ReadAll(dec)
+	if err != nil {
+		t.Fatalf("read zstd body: %v", err)
+	}
+	if string(got) != compressBody {
+		t.Error("decompressed body does not match original")
+	}
+}
+
+func TestCompressionMiddleware_NoAcceptEncoding(t *testing.T) {
+	w := serveCompressed("", jsonHandler)
+
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want none", got)
+	}
+	if w.Body.String() != compressBody {
+		t.Error("body should be passed through unchanged")
+	}
+}
+
+func TestCompressionMiddleware_NoContent(t *testing.T) {
+	w := serveCompressed("gzip", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", w.Code)
+	}
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want none for 204", got)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("body length = %d, want 0", w.Body.Len())
+	}
+}
+
+func TestCompressionMiddleware_PreservesExistingEncoding(t *testing.T) {
+	w := serveCompressed("gzip", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		io.WriteString(w, "already-encoded")
+	})
+
+	if got := w.Header().Get("Content-Encoding"); got != "br" {
+		t.Errorf("Content-Encoding = %q, want br", got)
+	}
+	if w.Body.String() != "already-encoded" {
+		t.Error("pre-encoded body should be passed through unchanged")
+	}
+}
+
+func TestSyncDelta_GzipResponse(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+	pushEntries(t, router, 3)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stores/test-store/sync/delta?after=0", nil)
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+
+	gr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	plain := httptest.NewRecorder()
+	io.Copy(plain, gr)
+	resp := decodeDeltaResponse(t, plain)
+	if len(resp.Entries) != 3 {
+		t.Errorf("expected 3 entries, got %d", len(resp.Entries))
+	}
+}
+
+func echoBodyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Encoding") != "" {
+		http.Error(w, "Content-Encoding not cleared", http.StatusInternalServerError)
+		return
+	}
+	io.Copy(w, r.Body)
+}
+
+func serveDecompressed(encoding string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+	w := httptest.NewRecorder()
+	DecompressionMiddleware(http.HandlerFunc(echoBodyHandler)).ServeHTTP(w, req)
+	return w
+}
+
+func TestDecompressionMiddleware(t *testing.T) {
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	io.WriteString(gw, compressBody)
+	gw.Close()
+
+	enc, _ := zstd.NewWriter(nil)
+	zstded := enc.EncodeAll([]byte(compressBody), nil)
+	enc.Close()
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+	}{
+		{"identity", "", []byte(compressBody)},
+		{"gzip", "gzip", gzipped.Bytes()},
+		{"zstd", "ZSTD", zstded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveDecompressed(tt.encoding, tt.body)
+			if w.Code != http.StatusOK || w.Body.String() != compressBody {
+				t.Errorf("got %d with %d bytes, want the %d byte body", w.Code, w.Body.Len(), len(compressBody))
+			}
+		})
+	}
+
+	if w := serveDecompressed("br", []byte("x")); w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("br: status = %d, want 415", w.Code)
+	}
+	if w := serveDecompressed("gzip", []byte("not gzip")); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid gzip: status = %d, want 400", w.Code)
+	}
+}
+
+func TestSyncPush_ZstdBodyLimitedAfterDecompression(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	handler.maxPushBytes = 4096
+	router := NewRouter(handler, manager)
+
+	push := func(n int) *httptest.ResponseRecorder {
+		body, err := json.Marshal(engramsync.PushRequest{
+			PushID:        fmt.Sprintf("push-zstd-%d", n),
+			SourceID:      "test-client",
+			SchemaVersion: 2,
+			Entries:       loreEntries(t, 1, n),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		enc, _ := zstd.NewWriter(nil)
+		compressed := enc.EncodeAll(body, nil)
+		enc.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/stores/test-store/sync/push", bytes.NewReader(compressed))
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Encoding", "zstd")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := push(1); w.Code != http.StatusOK {
+		t.Fatalf("small push: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	// Repetitive entries compress far below the limit, but not once decoded
+	if w := push(50); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("large push: expected 413, got %d: %s", w.Code, w.Body.String())
+	}
+}
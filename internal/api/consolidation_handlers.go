@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// Consolidations handles GET /api/v1/lore/consolidations and GET /api/v1/stores/{store_id}/lore/consolidations
+//
+// Returns the report of the store's latest consolidation run: clusters of
+// near-duplicate entries and whether each was merged. 404 until the
+// consolidation job has run once.
+func (h *Handler) Consolidations(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	storeID := StoreIDFromContext(r.Context())
+	s := h.getStoreForRequest(r)
+
+	raw, err := s.GetSyncMeta(r.Context(), store.ConsolidationReportKey)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		slog.Error("consolidation report lookup failed",
+			"component", "api",
+			"action", "consolidation_report_failed",
+			"store_id", storeID,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+	if raw == "" {
+		WriteProblem(w, r, http.StatusNotFound, "No consolidation report yet")
+		return
+	}
+
+	var report types.ConsolidationReport
+	if err := json.Unmarshal([]byte(raw), &report); err != nil {
+		slog.Error("consolidation report corrupt",
+			"component", "api",
+			"action", "consolidation_report_failed",
+			"store_id", storeID,
+			"error", err,
+		)
+		WriteProblem(w, r, http.StatusInternalServerError, "Internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
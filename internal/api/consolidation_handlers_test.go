@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func serveConsolidations(t *testing.T, s *mockStore) *httptest.ResponseRecorder {
+	t.Helper()
+	router := NewRouter(NewHandler(s, nil, &mockEmbedder{}, nil, "test-key", "1.0.0"), nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lore/consolidations", nil)
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestConsolidations_ReturnsLatestReport(t *testing.T) {
+	report := `{"generated_at":"2026-10-01T00:00:00Z","threshold":0.95,"applied":false,"proposals":[` +
+		`{"target_id":"01ARZ3NDEKTSV4RRFFQ69G5FAV","category":"PATTERN_OUTCOME","content":"Retry with backoff",` +
+		`"members":[{"id":"01ARZ3NDEKTSV4RRFFQ69G5FAW","content":"Retry using backoff","similarity":0.98}],"status":"proposed"}]}`
+	s := &mockStore{syncMeta: map[string]string{store.ConsolidationReportKey: report}}
+
+	w := serveConsolidations(t, s)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp types.ConsolidationReport
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Threshold != 0.95 || len(resp.Proposals) != 1 {
+		t.Fatalf("unexpected report: %+v", resp)
+	}
+	p := resp.Proposals[0]
+	if p.Status != types.ConsolidationProposed || len(p.Members) != 1 || p.Members[0].Similarity != 0.98 {
+		t.Errorf("unexpected proposal: %+v", p)
+	}
+}
+
+func TestConsolidations_NoReportYet(t *testing.T) {
+	w := serveConsolidations(t, &mockStore{})
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/llm"
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/validation"
+)
+
+// Contradictions handles GET /api/v1/lore/contradictions and GET /api/v1/stores/{store_id}/lore/contradictions
+//
+// Lists pairs of entries in the same category that are at least
+// min_similarity (default 0.85) similar but differ in polarity, optionally
+// only in one category. limit defaults to 20 (max 100). With judge=true,
+// the configured language model checks each pair and only those it
+// confirms are returned, with its reason; that responds 503 when no model
+// is configured and 502 when the model fails.
+func (h *Handler) Contradictions(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	start := time.Now()
+	storeID := StoreIDFromContext(r.Context())
+	s := h.getStoreForRequest(r)
+	params := r.URL.Query()
+
+	q := types.ContradictionQuery{
+		MinSimilarity: store.DefaultContradictionMinSimilarity,
+		Limit:         store.DefaultContradictionLimit,
+	}
+	if raw := params.Get("min_similarity"); raw != "" {
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil || f < 0 || f > 1 {
+			WriteProblem(w, r, http.StatusBadRequest, "Invalid min_similarity: must be a number between 0 and 1")
+			return
+		}
+		q.MinSimilarity = f
+	}
+	if raw := params.Get("category"); raw != "" {
+		if verr := validation.ValidateEnum("category", raw, validation.ValidLoreCategories); verr != nil {
+			WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid category: %s", verr.Message))
+			return
+		}
+		q.Category = raw
+	}
+	if raw := params.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > store.MaxContradictionLimit {
+			WriteProblem(w, r, http.StatusBadRequest,
+				fmt.Sprintf("Invalid limit: must be an integer between 1 and %d", store.MaxContradictionLimit))
+			return
+		}
+		q.Limit = n
+	}
+	var judge bool
+	if raw := params.Get("judge"); raw != "" {
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			WriteProblem(w, r, http.StatusBadRequest, "Invalid judge: must be true or false")
+			return
+		}
+		judge = b
+	}
+	if judge && h.summarizer == nil {
+		WriteProblem(w, r, http.StatusServiceUnavailable, "Contradiction judging not configured")
+		return
+	}
+
+	candidates, err := s.FindContradictions(r.Context(), q)
+	if err != nil {
+		slog.Error("contradiction search failed",
+			"component", "api",
+			"action", "contradictions_failed",
+			"store_id", storeID,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	resp := types.ContradictionsResponse{Contradictions: candidates}
+	if judge {
+		resp.JudgedBy = h.summarizer.ModelName()
+		resp.Contradictions = make([]types.Contradiction, 0, len(candidates))
+		for _, c := range candidates {
+			yes, reason, err := llm.JudgeContradiction(r.Context(), h.summarizer, c.A.Content, c.B.Content)
+			if err != nil {
+				slog.Error("contradiction judging failed",
+					"component", "api",
+					"action", "contradictions_failed",
+					"store_id", storeID,
+					"model", resp.JudgedBy,
+					"lore_id", c.A.ID,
+					"other_lore_id", c.B.ID,
+					"error", err,
+				)
+				WriteProblem(w, r, http.StatusBadGateway, "Language model failed to judge contradictions")
+				return
+			}
+			if yes {
+				c.Reason = reason
+				resp.Contradictions = append(resp.Contradictions, c)
+			}
+		}
+	}
+
+	slog.Info("contradictions listed",
+		"component", "api",
+		"action", "contradictions",
+		"store_id", storeID,
+		"candidates", len(candidates),
+		"contradictions", len(resp.Contradictions),
+		"judged", judge,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/llm"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func serveContradictions(t *testing.T, s *mockStore, p llm.Provider, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	var opts []HandlerOption
+	if p != nil {
+		opts = append(opts, WithSummarizer(p))
+	}
+	router := NewRouter(NewHandler(s, nil, &mockEmbedder{}, nil, "test-key", "1.0.0", opts...), nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lore/contradictions"+query, nil)
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func contradictionCandidates() []types.Contradiction {
+	return []types.Contradiction{{
+		Category:   "PATTERN_OUTCOME",
+		Similarity: 0.93,
+		A:          types.ContradictionEntry{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Content: "Always retry webhooks"},
+		B:          types.ContradictionEntry{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAW", Content: "Never retry webhooks"},
+	}}
+}
+
+func TestContradictions_ListsCandidates(t *testing.T) {
+	s := &mockStore{contradictions: contradictionCandidates()}
+
+	w := serveContradictions(t, s, nil, "?min_similarity=0.9&category=PATTERN_OUTCOME&limit=5")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	want := types.ContradictionQuery{MinSimilarity: 0.9, Category: "PATTERN_OUTCOME", Limit: 5}
+	if s.contradictionQ == nil || *s.contradictionQ != want {
+		t.Errorf("query = %+v, want %+v", s.contradictionQ, want)
+	}
+	var resp types.ContradictionsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Contradictions) != 1 || resp.JudgedBy != "" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestContradictions_Judged(t *testing.T) {
+	s := &mockStore{contradictions: contradictionCandidates()}
+
+	w := serveContradictions(t, s, &mockSummarizer{text: "YES\nOne always retries, the other never does."}, "?judge=true")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp types.ContradictionsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.JudgedBy != "test-llm" || len(resp.Contradictions) != 1 ||
+		resp.Contradictions[0].Reason != "One always retries, the other never does." {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	w = serveContradictions(t, s, &mockSummarizer{text: "NO. Different services."}, "?judge=true")
+	resp = types.ContradictionsResponse{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Contradictions) != 0 {
+		t.Errorf("rejected pair was returned: %+v", resp)
+	}
+}
+
+func TestContradictions_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		p     llm.Provider
+		query string
+		want  int
+	}{
+		{"invalid min_similarity", nil, "?min_similarity=2", http.StatusBadRequest},
+		{"invalid category", nil, "?category=NOPE", http.StatusBadRequest},
+		{"invalid limit", nil, "?limit=101", http.StatusBadRequest},
+		{"invalid judge", nil, "?judge=maybe", http.StatusBadRequest},
+		{"judge without model", nil, "?judge=true", http.StatusServiceUnavailable},
+		{"model failure", &mockSummarizer{err: errors.New("boom")}, "?judge=true", http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveContradictions(t, &mockStore{contradictions: contradictionCandidates()}, tt.p, tt.query)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d; body = %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hyperengineering/engram/internal/store"
+)
+
+// staleETagDetail is the detail of a 412 response to a mutation whose
+// If-Match no longer matches the entry.
+const staleETagDetail = "Lore entry has changed; fetch it again for a current ETag"
+
+// loreETag returns the entity tag of a lore entry at a version.
+func loreETag(version int) string {
+	return fmt.Sprintf(`"v%d"`, version)
+}
+
+// parseIfMatch parses an If-Match header into the lore versions it lists.
+// wildcard is true for "*". Weak and malformed tags are dropped, since If-Match
+// uses strong comparison and they can never match.
+func parseIfMatch(header string) (versions []int, wildcard bool) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil, true
+		}
+		if !strings.HasPrefix(tag, `"v`) || !strings.HasSuffix(tag, `"`) {
+			continue
+		}
+		n, err := strconv.Atoi(tag[2 : len(tag)-1])
+		if err != nil || n < 1 {
+			continue
+		}
+		versions = append(versions, n)
+	}
+	return versions, false
+}
+
+// ifMatchVersion resolves the request's If-Match header to the version a
+// mutation of entry id must find: 0 when the header is absent or "*". When
+// no listed tag matches, it writes a 412 and returns ok false.
+func ifMatchVersion(w http.ResponseWriter, r *http.Request, s store.Store, id string) (version int, ok bool) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return 0, true
+	}
+	versions, wildcard := parseIfMatch(header)
+	switch {
+	case wildcard:
+		return 0, true
+	case len(versions) == 1:
+		// The store compares the version in the same transaction as the write.
+		return versions[0], true
+	case len(versions) == 0:
+		WriteProblem(w, r, http.StatusPreconditionFailed, staleETagDetail)
+		return 0, false
+	}
+
+	current, err := s.GetLoreVersion(r.Context(), id)
+	if err != nil {
+		MapStoreError(w, r, err)
+		return 0, false
+	}
+	if !slices.Contains(versions, current) {
+		WriteProblem(w, r, http.StatusPreconditionFailed, staleETagDetail)
+		return 0, false
+	}
+	return current, true
+}
+
+// setLoreETag sets the ETag header to an entry's current version. Failing to
+// read the version only omits the header.
+func setLoreETag(w http.ResponseWriter, r *http.Request, s store.Store, id string) {
+	if version, err := s.GetLoreVersion(r.Context(), id); err == nil {
+		w.Header().Set("ETag", loreETag(version))
+	}
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header   string
+		versions []int
+		wildcard bool
+	}{
+		{`"v3"`, []int{3}, false},
+		{`"v1", "v12"`, []int{1, 12}, false},
+		{`*`, nil, true},
+		{`W/"v3"`, nil, false},
+		{`"3", "v0", "vx", v4`, nil, false},
+	}
+	for _, tt := range tests {
+		versions, wildcard := parseIfMatch(tt.header)
+		if !slices.Equal(versions, tt.versions) || wildcard != tt.wildcard {
+			t.Errorf("parseIfMatch(%q) = %v, %v; want %v, %v", tt.header, versions, wildcard, tt.versions, tt.wildcard)
+		}
+	}
+}
+
+func TestLoreETag_RoundTrip(t *testing.T) {
+	versions, _ := parseIfMatch(loreETag(7))
+	if !slices.Equal(versions, []int{7}) {
+		t.Errorf("parseIfMatch(loreETag(7)) = %v, want [7]", versions)
+	}
+}
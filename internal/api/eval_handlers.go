@@ -0,0 +1,296 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/hyperengineering/engram/internal/evaluation"
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/validation"
+)
+
+// EvalSetStore is implemented by stores that keep labeled query sets for
+// evaluating search. Implemented by SQLiteStore.
+type EvalSetStore interface {
+	PutEvalSet(ctx context.Context, set types.EvalSet) (*types.EvalSet, error)
+	GetEvalSet(ctx context.Context, name string) (*types.EvalSet, error)
+	ListEvalSets(ctx context.Context) ([]types.EvalSetSummary, error)
+	DeleteEvalSet(ctx context.Context, name string) error
+}
+
+// evalSetStore returns the request's store as an EvalSetStore, writing a
+// problem and returning false when it is not a recall store or cannot keep
+// evaluation sets.
+func (h *Handler) evalSetStore(w http.ResponseWriter, r *http.Request) (EvalSetStore, bool) {
+	if !h.requireRecallStore(w, r) {
+		return nil, false
+	}
+	sets, ok := h.getStoreForRequest(r).(EvalSetStore)
+	if !ok {
+		WriteProblem(w, r, http.StatusNotImplemented, "Store does not support search evaluation")
+		return nil, false
+	}
+	return sets, true
+}
+
+// PutEvalSet handles PUT /api/v1/admin/eval-sets/{name} and
+// PUT /api/v1/admin/stores/{store_id}/eval-sets/{name}
+//
+// Uploads a set of labeled queries, each with the IDs of the lore entries
+// a search for it should return, replacing any set of the same name.
+func (h *Handler) PutEvalSet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	storeID := StoreIDFromContext(ctx)
+	sets, ok := h.evalSetStore(w, r)
+	if !ok {
+		return
+	}
+
+	var set types.EvalSet
+	if err := json.NewDecoder(r.Body).Decode(&set); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err.Error()))
+		return
+	}
+	set.Name = chi.URLParam(r, "name")
+	for i := range set.Cases {
+		set.Cases[i].Path = scopePath(set.Cases[i].Path)
+	}
+	if errs := validation.ValidateEvalSet(set); len(errs) > 0 {
+		WriteProblemWithErrors(w, r, "Request contains invalid fields", errs)
+		return
+	}
+
+	stored, err := sets.PutEvalSet(ctx, set)
+	if err != nil {
+		slog.Error("eval set upload failed",
+			"component", "api",
+			"action", "put_eval_set_failed",
+			"store_id", storeID,
+			"eval_set", set.Name,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	slog.Info("eval set uploaded via API",
+		"component", "api",
+		"action", "put_eval_set",
+		"store_id", storeID,
+		"eval_set", stored.Name,
+		"cases", len(stored.Cases),
+		"request_id", GetRequestID(ctx),
+		"remote_addr", r.RemoteAddr,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stored)
+}
+
+// ListEvalSets handles GET /api/v1/admin/eval-sets and
+// GET /api/v1/admin/stores/{store_id}/eval-sets
+func (h *Handler) ListEvalSets(w http.ResponseWriter, r *http.Request) {
+	sets, ok := h.evalSetStore(w, r)
+	if !ok {
+		return
+	}
+
+	list, err := sets.ListEvalSets(r.Context())
+	if err != nil {
+		slog.Error("eval set listing failed",
+			"component", "api",
+			"action", "list_eval_sets_failed",
+			"store_id", StoreIDFromContext(r.Context()),
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"eval_sets": list})
+}
+
+// GetEvalSet handles GET /api/v1/admin/eval-sets/{name} and
+// GET /api/v1/admin/stores/{store_id}/eval-sets/{name}
+func (h *Handler) GetEvalSet(w http.ResponseWriter, r *http.Request) {
+	sets, ok := h.evalSetStore(w, r)
+	if !ok {
+		return
+	}
+
+	set, err := sets.GetEvalSet(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		MapStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(set)
+}
+
+// DeleteEvalSet handles DELETE /api/v1/admin/eval-sets/{name} and
+// DELETE /api/v1/admin/stores/{store_id}/eval-sets/{name}
+func (h *Handler) DeleteEvalSet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sets, ok := h.evalSetStore(w, r)
+	if !ok {
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	if err := sets.DeleteEvalSet(ctx, name); err != nil {
+		MapStoreError(w, r, err)
+		return
+	}
+
+	slog.Info("eval set deleted via API",
+		"component", "api",
+		"action", "delete_eval_set",
+		"store_id", StoreIDFromContext(ctx),
+		"eval_set", name,
+		"request_id", GetRequestID(ctx),
+		"remote_addr", r.RemoteAddr,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RunEvalSet handles POST /api/v1/admin/eval-sets/{name}/run and
+// POST /api/v1/admin/stores/{store_id}/eval-sets/{name}/run
+//
+// Searches the store for every query of the set and reports recall@k and
+// MRR against its labels, with per-query results. k (1-100; default 10)
+// is the result cutoff. The search takes the server's configured ranking
+// and boost unless overridden by the mode, fusion, keyword_weight,
+// semantic_weight, rrf_k and boost parameters of the search endpoint, so a
+// candidate ranking can be compared with the current one before it is
+// rolled out. Unlike search, a query that cannot be embedded fails the run
+// with 503 rather than falling back to keyword ranking, which would score
+// a different configuration than the one asked for.
+func (h *Handler) RunEvalSet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	storeID := StoreIDFromContext(ctx)
+	sets, ok := h.evalSetStore(w, r)
+	if !ok {
+		return
+	}
+	params := r.URL.Query()
+
+	k := evaluation.DefaultK
+	if raw := params.Get("k"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > store.MaxSearchLimit {
+			WriteProblem(w, r, http.StatusBadRequest,
+				fmt.Sprintf("Invalid k: must be an integer between 1 and %d", store.MaxSearchLimit))
+			return
+		}
+		k = n
+	}
+
+	mode := types.SearchMode(params.Get("mode"))
+	switch mode {
+	case "":
+		mode = types.SearchModeHybrid
+	case types.SearchModeKeyword, types.SearchModeSemantic, types.SearchModeHybrid:
+	default:
+		WriteProblem(w, r, http.StatusBadRequest,
+			fmt.Sprintf("Invalid mode %q: must be keyword, semantic, or hybrid", mode))
+		return
+	}
+
+	ranking, err := h.parseSearchRanking(params)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid ranking: %s", err))
+		return
+	}
+
+	boost := h.searchBoost
+	if v := params.Get("boost"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			WriteProblem(w, r, http.StatusBadRequest, "Invalid boost: must be true or false")
+			return
+		}
+		if !b {
+			boost = types.SearchBoost{}
+		}
+	}
+
+	set, err := sets.GetEvalSet(ctx, chi.URLParam(r, "name"))
+	if err != nil {
+		MapStoreError(w, r, err)
+		return
+	}
+
+	embedder := h.queryEmbedder
+	if embedder == nil {
+		embedder = h.embedder
+	}
+	s := h.getStoreForRequest(r)
+	report, err := evaluation.Run(ctx, set, k, func(ctx context.Context, c types.EvalCase, k int) ([]types.SearchResult, error) {
+		query := types.SearchQuery{
+			Text:     c.Query,
+			Mode:     mode,
+			Ranking:  ranking,
+			Boost:    boost,
+			Category: c.Category,
+			Repo:     c.Repo,
+			Path:     c.Path,
+			Limit:    k,
+		}
+		if mode != types.SearchModeKeyword {
+			vec, err := embedder.Embed(ctx, c.Query)
+			if err == nil && len(vec) == 0 {
+				err = store.ErrEmbeddingUnavailable
+			}
+			if err != nil {
+				return nil, fmt.Errorf("embed query: %v: %w", err, store.ErrEmbeddingUnavailable)
+			}
+			query.Embedding = vec
+		}
+		return s.SearchLore(ctx, query)
+	})
+	if err != nil {
+		slog.Error("eval set run failed",
+			"component", "api",
+			"action", "run_eval_set_failed",
+			"store_id", storeID,
+			"eval_set", set.Name,
+			"mode", string(mode),
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+	report.Mode = mode
+	if mode == types.SearchModeHybrid {
+		report.Ranking = &ranking
+	}
+	report.Boosted = boost.TotalWeight() > 0
+
+	slog.Info("eval set run via API",
+		"component", "api",
+		"action", "run_eval_set",
+		"store_id", storeID,
+		"eval_set", set.Name,
+		"mode", string(mode),
+		"k", k,
+		"cases", report.Cases,
+		"recall_at_k", report.RecallAtK,
+		"mrr", report.MRR,
+		"duration_ms", report.DurationMS,
+		"request_id", GetRequestID(ctx),
+		"remote_addr", r.RemoteAddr,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
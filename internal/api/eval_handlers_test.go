@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestEvalSets_API(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+
+	ctx := context.Background()
+	managed, err := manager.GetStore(ctx, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const retries, cache = "01HGW2N5E56F2ZXQWRR78YQRZ8", "01HGW2N5E56F2ZXQWRR78YQRZ9"
+	if _, err := managed.Store.IngestLore(ctx, []types.NewLoreEntry{
+		{ID: retries, Content: "Retry payment webhooks with exponential backoff", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src"},
+		{ID: cache, Content: "Warm the product cache before traffic shifts", Category: "PERFORMANCE_INSIGHT", Confidence: 0.5, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &mockStore{stats: &types.StoreStats{}}
+	embedder := &mockEmbedder{model: "test-model"}
+	handler := NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0", WithAdminKey("admin-key"))
+	router := NewRouter(handler, manager)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer admin-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	body := `{"description": "golden queries", "cases": [
+		{"query": "payment webhooks", "relevant": ["` + retries + `"]},
+		{"query": "product cache", "relevant": ["` + cache + `", "01ARZ3NDEKTSV4RRFFQ69G5FAV"]}
+	]}`
+	w := do(http.MethodPut, "/api/v1/admin/stores/default/eval-sets/golden", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("upload: expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = do(http.MethodGet, "/api/v1/admin/eval-sets", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"cases":2`) {
+		t.Errorf("list: got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = do(http.MethodPost, "/api/v1/admin/stores/default/eval-sets/golden/run?mode=keyword&k=5", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("run: expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var report types.EvalReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if report.Mode != types.SearchModeKeyword || report.K != 5 || report.Cases != 2 || report.Ranking != nil {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if report.RecallAtK != 0.75 || report.MRR != 1 {
+		t.Errorf("recall@k = %v, mrr = %v; want 0.75, 1", report.RecallAtK, report.MRR)
+	}
+	if missed := report.Results[1].Missed; len(missed) != 1 || missed[0] != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Errorf("missed = %v, want the unlabeled-in-store entry", missed)
+	}
+
+	// Without an embedding the hybrid run fails rather than scoring keyword ranking
+	if w := do(http.MethodPost, "/api/v1/admin/eval-sets/golden/run", ""); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("hybrid run without embeddings: expected status 503, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := do(http.MethodPost, "/api/v1/admin/eval-sets/golden/run?k=0", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("k=0: expected status 400, got %d", w.Code)
+	}
+	if w := do(http.MethodPut, "/api/v1/admin/eval-sets/Golden", body); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("invalid name: expected status 422, got %d", w.Code)
+	}
+
+	if w := do(http.MethodDelete, "/api/v1/admin/eval-sets/golden", ""); w.Code != http.StatusNoContent {
+		t.Errorf("delete: expected status 204, got %d", w.Code)
+	}
+	if w := do(http.MethodPost, "/api/v1/admin/eval-sets/golden/run", ""); w.Code != http.StatusNotFound {
+		t.Errorf("run deleted set: expected status 404, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/eval-sets", nil)
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("client key: expected status 401, got %d", w.Code)
+	}
+}
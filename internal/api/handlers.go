@@ -1,31 +1,46 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/hyperengineering/engram/internal/embedding"
+	"github.com/hyperengineering/engram/internal/llm"
 	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/plugin"
 	"github.com/hyperengineering/engram/internal/snapshot"
 	"github.com/hyperengineering/engram/internal/store"
 	"github.com/hyperengineering/engram/internal/types"
 	"github.com/hyperengineering/engram/internal/validation"
+	"github.com/hyperengineering/engram/internal/webhook"
 )
 
 // HeaderRecallSourceID is the header name for client identification.
 // Recall clients should send this header to enable per-client tracking in logs.
 const HeaderRecallSourceID = "X-Recall-Source-ID"
 
+// storeDrainTimeout bounds how long DeleteStore waits for in-flight requests
+// to a store before giving up and leaving the store in place.
+const storeDrainTimeout = 30 * time.Second
+
+// DefaultMaxIngestBytes is the default request body limit for an ingest.
+// A full batch of maximum-length entries fits well within it.
+const DefaultMaxIngestBytes int64 = 4 << 20
+
 // extractSourceID returns the client source ID from the request header.
 // Returns "unknown" if the header is not present or empty.
 func extractSourceID(r *http.Request) string {
@@ -44,28 +59,232 @@ type Handler struct {
 	uploader     snapshot.Uploader
 	apiKey       string
 	version      string
+
+	maxPushBytes   int64
+	maxIngestBytes int64
+	pushSessionTTL time.Duration
+	maxClockSkew   time.Duration
+	notifier       webhook.Notifier
+	searchRanking  types.SearchRanking
+	searchBoost    types.SearchBoost
+	queryEmbedder  embedding.Embedder
+	accessSampling map[string]float64
+	latency        *LatencyTracker
+	shedder        *LoadShedder
+	maintenance    *Maintenance
+	storeLocks     *StoreLocks
+	signing        *PushSigning
+	pprofKey       string
+	adminKey       string
+	apiKeySource   func() string
+	adminKeySource func() string
+	lockout        *AuthLockout
+	trustedProxies []netip.Prefix
+	summarizer     llm.Provider
+}
+
+// HandlerOption configures optional Handler behavior.
+type HandlerOption func(*Handler)
+
+// WithMaxPushBytes sets the request body limit for sync pushes and push
+// session appends. Non-positive values keep DefaultMaxPushBytes.
+func WithMaxPushBytes(n int64) HandlerOption {
+	return func(h *Handler) {
+		if n > 0 {
+			h.maxPushBytes = n
+		}
+	}
+}
+
+// WithMaxIngestBytes sets the request body limit for lore ingest.
+// Non-positive values keep DefaultMaxIngestBytes.
+func WithMaxIngestBytes(n int64) HandlerOption {
+	return func(h *Handler) {
+		if n > 0 {
+			h.maxIngestBytes = n
+		}
+	}
+}
+
+// WithPushSessionTTL sets how long a push session stays open.
+// Non-positive values keep DefaultPushSessionTTL.
+func WithPushSessionTTL(d time.Duration) HandlerOption {
+	return func(h *Handler) {
+		if d > 0 {
+			h.pushSessionTTL = d
+		}
+	}
+}
+
+// WithMaxClockSkew sets how far ahead of the server clock pushed
+// timestamps may be before they are clamped. Non-positive values keep
+// DefaultMaxClockSkew.
+func WithMaxClockSkew(d time.Duration) HandlerOption {
+	return func(h *Handler) {
+		if d > 0 {
+			h.maxClockSkew = d
+		}
+	}
+}
+
+// WithNotifier sets the webhook notifier for lore events.
+// Without one, no events are emitted.
+func WithNotifier(n webhook.Notifier) HandlerOption {
+	return func(h *Handler) {
+		h.notifier = n
+	}
+}
+
+// WithSearchRanking sets the default hybrid search ranking, which clients
+// may override per request. The ranking must already be valid.
+func WithSearchRanking(r types.SearchRanking) HandlerOption {
+	return func(h *Handler) {
+		h.searchRanking = r
+	}
+}
+
+// WithSearchBoost sets the quality boost applied to searches. A zero boost
+// ranks by relevance alone. The boost must already be valid.
+func WithSearchBoost(b types.SearchBoost) HandlerOption {
+	return func(h *Handler) {
+		h.searchBoost = b
+	}
+}
+
+// WithQueryEmbedder sets the embedder for search queries, typically a
+// caching wrapper around the handler's embedder. Without one, queries use
+// the handler's embedder.
+func WithQueryEmbedder(e embedding.Embedder) HandlerOption {
+	return func(h *Handler) {
+		h.queryEmbedder = e
+	}
+}
+
+// WithAccessLogSampling sets the fraction of successful requests the access
+// log records per route template. Routes not listed are always logged.
+func WithAccessLogSampling(rates map[string]float64) HandlerOption {
+	return func(h *Handler) {
+		h.accessSampling = rates
+	}
+}
+
+// WithLatencySLOs sets the latency target per route template. Requests
+// slower than their route's target are logged as warnings. Without this
+// option, DefaultLatencySLOs apply; a nil map sets no targets.
+func WithLatencySLOs(slos map[string]time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.latency = NewLatencyTracker(slos)
+	}
+}
+
+// WithLoadShedder sets the load shedder guarding authenticated routes.
+// Without one, requests are never shed.
+func WithLoadShedder(s *LoadShedder) HandlerOption {
+	return func(h *Handler) {
+		h.shedder = s
+	}
+}
+
+// WithPprof serves net/http/pprof under /debug/pprof/, authenticated by
+// adminKey rather than the client API key. An empty key leaves it off.
+func WithPprof(adminKey string) HandlerOption {
+	return func(h *Handler) {
+		h.pprofKey = adminKey
+	}
+}
+
+// WithAdminKey enables the operator endpoints under /api/v1/admin,
+// authenticated by adminKey rather than the client API key. An empty key
+// leaves them off.
+func WithAdminKey(adminKey string) HandlerOption {
+	return func(h *Handler) {
+		h.adminKey = adminKey
+	}
+}
+
+// WithAPIKeySource looks up the client API key on every request, so the
+// key can be rotated while serving. Without it, the key passed to
+// NewHandler is used.
+func WithAPIKeySource(key func() string) HandlerOption {
+	return func(h *Handler) {
+		h.apiKeySource = key
+	}
+}
+
+// WithAdminKeySource looks up the admin key on every request, for the
+// operator endpoints and pprof. It does not enable them: that still takes
+// WithAdminKey or WithPprof.
+func WithAdminKeySource(key func() string) HandlerOption {
+	return func(h *Handler) {
+		h.adminKeySource = key
+	}
+}
+
+// WithSummarizer sets the language model behind lore summarization and
+// contradiction judging. Without one, those requests fail with 503.
+func WithSummarizer(p llm.Provider) HandlerOption {
+	return func(h *Handler) {
+		h.summarizer = p
+	}
 }
 
 // NewHandler creates a new Handler with store.Store interface
 // The storeManager parameter can be nil for backward compatibility.
 // The uploader parameter can be nil; when nil, snapshot serving falls back to local streaming.
-func NewHandler(s store.Store, mgr *multistore.StoreManager, e embedding.Embedder, uploader snapshot.Uploader, apiKey, version string) *Handler {
-	return &Handler{
-		store:        s,
-		storeManager: mgr,
-		embedder:     e,
-		uploader:     uploader,
-		apiKey:       apiKey,
-		version:      version,
+func NewHandler(s store.Store, mgr *multistore.StoreManager, e embedding.Embedder, uploader snapshot.Uploader, apiKey, version string, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		store:          s,
+		storeManager:   mgr,
+		embedder:       e,
+		uploader:       uploader,
+		apiKey:         apiKey,
+		version:        version,
+		maxPushBytes:   DefaultMaxPushBytes,
+		maxIngestBytes: DefaultMaxIngestBytes,
+		pushSessionTTL: DefaultPushSessionTTL,
+		maxClockSkew:   DefaultMaxClockSkew,
+		searchRanking:  store.DefaultSearchRanking,
+		searchBoost:    store.DefaultSearchBoost,
+		latency:        NewLatencyTracker(DefaultLatencySLOs),
+		maintenance:    NewMaintenance(),
+		storeLocks:     NewStoreLocks(),
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	return h
+}
+
+// keySource returns source if set, or else a function returning key.
+func keySource(key string, source func() string) func() string {
+	if source != nil {
+		return source
+	}
+	return func() string { return key }
+}
+
+// notify emits a webhook event if a notifier is configured.
+func (h *Handler) notify(eventType webhook.EventType, storeID string, data any) {
+	if h.notifier != nil {
+		h.notifier.Notify(webhook.NewEvent(eventType, storeID, data))
+	}
+}
+
+// EmbeddingDimensionReporter is implemented by stores that check the
+// dimension of the embeddings they are given. Implemented by SQLiteStore.
+type EmbeddingDimensionReporter interface {
+	EmbeddingDimensions() types.EmbeddingDimensions
 }
 
 // Health returns the health status.
 // Accepts optional ?store={store_id} query parameter for store-specific stats.
+// Status is "degraded" when the store has refused embeddings of another
+// dimension than its own, as after an embedding model switch.
 func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 	storeID := r.URL.Query().Get("store")
 	ctx := r.Context()
 
+	var checked store.Store
 	var stats *types.StoreStats
 	var storeType string
 	var schemaVersion int
@@ -89,10 +308,19 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 				WriteProblem(w, r, http.StatusNotFound, "Store not found")
 				return
 			}
+			if errors.Is(err, multistore.ErrStoreArchived) {
+				WriteProblemStoreArchived(w, r, storeID)
+				return
+			}
+			if errors.Is(err, multistore.ErrStoreDeleting) {
+				WriteProblem(w, r, http.StatusGone, "Store is being deleted")
+				return
+			}
 			slog.Error("health store lookup failed", "store_id", storeID, "error", err)
 			WriteProblem(w, r, http.StatusInternalServerError, "Internal error")
 			return
 		}
+		checked = managed.Store
 		stats, err = managed.Store.GetStats(ctx)
 		if err != nil {
 			slog.Error("health stats failed", "store_id", storeID, "error", err)
@@ -103,6 +331,7 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 		schemaVersion = managed.SchemaVersion(ctx)
 	} else {
 		// Default/global health (backward compatible)
+		checked = h.store
 		stats, err = h.store.GetStats(ctx)
 		if err != nil {
 			slog.Error("health stats failed", "error", err)
@@ -129,6 +358,14 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 		SchemaVersion:  schemaVersion,
 	}
 
+	if reporter, ok := checked.(EmbeddingDimensionReporter); ok {
+		dims := reporter.EmbeddingDimensions()
+		resp.EmbeddingDimensions = &dims
+		if dims.Mismatches > 0 {
+			resp.Status = "degraded"
+		}
+	}
+
 	// Include store_id in response if specified
 	if storeID != "" {
 		resp.StoreID = storeID
@@ -234,8 +471,14 @@ func (h *Handler) IngestLore(w http.ResponseWriter, r *http.Request) {
 	// Get store (from context if available, otherwise fallback to h.store)
 	s := h.getStoreForRequest(r)
 
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxIngestBytes)
 	var req types.IngestRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			WriteProblemTooLarge(w, r, tooLarge.Limit, "Split the lore across several ingest requests.")
+			return
+		}
 		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err.Error()))
 		return
 	}
@@ -249,26 +492,37 @@ func (h *Handler) IngestLore(w http.ResponseWriter, r *http.Request) {
 
 	// Validate each entry, separate valid from invalid (partial acceptance)
 	var validEntries []types.NewLoreEntry
+	var validIndices []int
 	var allErrors []string
+	results := make([]types.IngestEntryResult, len(req.Lore))
 
 	for i, lore := range req.Lore {
+		results[i].Index = i
+		lore.Path = scopePath(lore.Path)
 		errs := validation.ValidateLoreEntry(i, lore)
 		if len(errs) > 0 {
+			results[i].Status = types.IngestRejected
 			for _, err := range errs {
 				allErrors = append(allErrors, fmt.Sprintf("%s: %s", err.Field, err.Message))
+				results[i].Errors = append(results[i].Errors, fmt.Sprintf("%s: %s", err.Field, err.Message))
 			}
 			continue
 		}
+		validIndices = append(validIndices, i)
 		validEntries = append(validEntries, types.NewLoreEntry{
+			ID:         lore.ID,
 			Content:    lore.Content,
 			Context:    lore.Context,
 			Category:   string(lore.Category),
 			Confidence: lore.Confidence,
 			SourceID:   req.SourceID,
+			Language:   lore.Language,
+			Repo:       lore.Repo,
+			Path:       lore.Path,
 		})
 	}
 
-	var accepted, merged int
+	var accepted, merged, updated, hookRejected int
 	if len(validEntries) > 0 {
 		result, err := s.IngestLore(r.Context(), validEntries)
 		if err != nil {
@@ -285,9 +539,20 @@ func (h *Handler) IngestLore(w http.ResponseWriter, r *http.Request) {
 		}
 		accepted = result.Accepted
 		merged = result.Merged
+		updated = result.Updated
+		hookRejected = result.Rejected
+		allErrors = append(allErrors, result.Errors...)
+		// The store indexes its results by position among the valid entries
+		for _, outcome := range result.Results {
+			if outcome.Index < 0 || outcome.Index >= len(validIndices) {
+				continue
+			}
+			outcome.Index = validIndices[outcome.Index]
+			results[outcome.Index] = outcome
+		}
 	}
 
-	rejected := len(req.Lore) - len(validEntries)
+	rejected := len(req.Lore) - len(validEntries) + hookRejected
 
 	slog.Info("lore ingested",
 		"component", "api",
@@ -297,17 +562,44 @@ func (h *Handler) IngestLore(w http.ResponseWriter, r *http.Request) {
 		"remote_addr", r.RemoteAddr,
 		"accepted", accepted,
 		"merged", merged,
+		"updated", updated,
 		"rejected", rejected,
 		"duration_ms", time.Since(start).Milliseconds(),
 	)
 
+	if accepted > 0 || merged > 0 {
+		h.notify(webhook.EventLoreIngested, storeID, webhook.IngestData{
+			SourceID: req.SourceID,
+			Accepted: accepted,
+			Merged:   merged,
+			Rejected: rejected,
+		})
+	}
+	if merged > 0 {
+		h.notify(webhook.EventLoreMerged, storeID, webhook.MergeData{
+			SourceID: req.SourceID,
+			Merged:   merged,
+		})
+	}
+
 	resp := types.IngestResult{
 		Accepted: accepted,
 		Merged:   merged,
+		Updated:  updated,
 		Rejected: rejected,
 		Errors:   allErrors,
+		Results:  results,
+	}
+	for _, outcome := range results {
+		if outcome.Status == types.IngestAccepted && outcome.LoreID != "" {
+			resp.CreatedIDs = append(resp.CreatedIDs, outcome.LoreID)
+		}
 	}
 
+	// A single created entry can be fetched at once from its Location
+	if len(req.Lore) == 1 && len(resp.CreatedIDs) == 1 {
+		w.Header().Set("Location", strings.TrimSuffix(r.URL.EscapedPath(), "/")+"/"+resp.CreatedIDs[0])
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
@@ -343,12 +635,24 @@ func (h *Handler) trySnapshotRedirect(w http.ResponseWriter, r *http.Request, st
 	return false
 }
 
+// setSnapshotBaseHeader sets X-Snapshot-Base-Sequence to the change_log
+// sequence of the store's current snapshot, if known. Call it before opening
+// the snapshot: a snapshot replaced in between then carries a later sequence
+// than the header, so the client re-reads a few changes instead of skipping
+// them.
+func setSnapshotBaseHeader(w http.ResponseWriter, s store.Store) {
+	if sb, ok := s.(SnapshotBaseSequencer); ok {
+		if seq, ok := sb.SnapshotBaseSequence(); ok {
+			w.Header().Set("X-Snapshot-Base-Sequence", strconv.FormatInt(seq, 10))
+		}
+	}
+}
+
 // Snapshot handles GET /api/v1/lore/snapshot and GET /api/v1/stores/{store_id}/lore/snapshot
 // Streams the cached database snapshot as application/octet-stream.
 // When S3 is configured, returns a 302 redirect to a pre-signed URL.
 // Returns 503 with Retry-After if no snapshot is available.
 func (h *Handler) Snapshot(w http.ResponseWriter, r *http.Request) {
-	start := time.Now()
 	sourceID := extractSourceID(r)
 	storeID := StoreIDFromContext(r.Context())
 
@@ -359,6 +663,7 @@ func (h *Handler) Snapshot(w http.ResponseWriter, r *http.Request) {
 
 	s := h.getStoreForRequest(r)
 
+	setSnapshotBaseHeader(w, s)
 	reader, err := s.GetSnapshot(r.Context())
 	if errors.Is(err, store.ErrSnapshotNotAvailable) {
 		slog.Warn("snapshot not available",
@@ -390,26 +695,14 @@ func (h *Handler) Snapshot(w http.ResponseWriter, r *http.Request) {
 	defer reader.Close()
 
 	w.Header().Set("Content-Type", "application/octet-stream")
-	bytesWritten, err := io.Copy(w, reader)
-	if err != nil {
+	if _, err := io.Copy(w, reader); err != nil {
 		slog.Debug("snapshot stream interrupted",
 			"component", "api",
 			"store_id", storeID,
 			"source_id", sourceID,
 			"error", err,
 		)
-		return
 	}
-
-	slog.Info("recall client bootstrap",
-		"component", "api",
-		"action", "client_bootstrap",
-		"store_id", storeID,
-		"source_id", sourceID,
-		"remote_addr", r.RemoteAddr,
-		"bytes_served", bytesWritten,
-		"duration_ms", time.Since(start).Milliseconds(),
-	)
 }
 
 // Delta handles GET /api/v1/lore/delta and GET /api/v1/stores/{store_id}/lore/delta
@@ -417,7 +710,6 @@ func (h *Handler) Snapshot(w http.ResponseWriter, r *http.Request) {
 // Returns 400 if since is missing or invalid.
 // Returns JSON with lore[], deleted_ids[], and as_of.
 func (h *Handler) Delta(w http.ResponseWriter, r *http.Request) {
-	start := time.Now()
 	sourceID := extractSourceID(r)
 	storeID := StoreIDFromContext(r.Context())
 
@@ -471,16 +763,14 @@ func (h *Handler) Delta(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	slog.Info("recall client sync",
+	slog.Debug("recall client sync",
 		"component", "api",
 		"action", "client_sync",
 		"store_id", storeID,
 		"source_id", sourceID,
-		"remote_addr", r.RemoteAddr,
 		"since", since,
 		"lore_count", len(result.Lore),
 		"deleted_count", len(result.DeletedIDs),
-		"duration_ms", time.Since(start).Milliseconds(),
 	)
 
 	w.Header().Set("Content-Type", "application/json")
@@ -499,6 +789,15 @@ type feedbackRequest struct {
 type feedbackReqEntry struct {
 	LoreID string `json:"lore_id"`
 	Type   string `json:"type"`
+	Note   string `json:"note,omitempty"`
+}
+
+// bulkFeedbackRequest applies one feedback type and note to many lore entries.
+type bulkFeedbackRequest struct {
+	SourceID string   `json:"source_id"`
+	LoreIDs  []string `json:"lore_ids"`
+	Type     string   `json:"type"`
+	Note     string   `json:"note,omitempty"`
 }
 
 // --- Store Management API Types ---
@@ -506,9 +805,19 @@ type feedbackReqEntry struct {
 // ListStoresResponse is the response for GET /api/v1/stores.
 type ListStoresResponse struct {
 	Stores []StoreListItem `json:"stores"`
-	Total  int             `json:"total"`
+	// Total counts every store, not just this page; listing the store
+	// directories gives it without opening any store.
+	Total      int    `json:"total"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
+// Page size bounds for GET /api/v1/stores.
+const (
+	DefaultStoreListLimit = 100
+	MaxStoreListLimit     = 1000
+)
+
 // StoreListItem represents a store in the list response.
 type StoreListItem struct {
 	ID            string    `json:"id"`
@@ -518,18 +827,23 @@ type StoreListItem struct {
 	LastAccessed  time.Time `json:"last_accessed"`
 	SizeBytes     int64     `json:"size_bytes"`
 	Description   string    `json:"description,omitempty"`
+	State         string    `json:"state"`
 }
 
 // StoreInfoResponse is the response for GET /api/v1/stores/{store_id}.
 type StoreInfoResponse struct {
-	ID            string               `json:"id"`
-	Type          string               `json:"type"`
-	SchemaVersion int                  `json:"schema_version"`
-	Created       time.Time            `json:"created"`
-	LastAccessed  time.Time            `json:"last_accessed"`
-	Description   string               `json:"description,omitempty"`
-	SizeBytes     int64                `json:"size_bytes"`
-	Stats         *types.ExtendedStats `json:"stats"`
+	ID            string                  `json:"id"`
+	Type          string                  `json:"type"`
+	SchemaVersion int                     `json:"schema_version"`
+	Created       time.Time               `json:"created"`
+	LastAccessed  time.Time               `json:"last_accessed"`
+	Description   string                  `json:"description,omitempty"`
+	Template      string                  `json:"template,omitempty"`
+	ClonedFrom    string                  `json:"cloned_from,omitempty"`
+	State         string                  `json:"state"`
+	Archive       *multistore.ArchiveInfo `json:"archive,omitempty"`
+	SizeBytes     int64                   `json:"size_bytes"`
+	Stats         *types.ExtendedStats    `json:"stats"`
 }
 
 // CreateStoreRequest is the request body for POST /api/v1/stores.
@@ -537,6 +851,9 @@ type CreateStoreRequest struct {
 	StoreID     string `json:"store_id"`
 	Type        string `json:"type,omitempty"`
 	Description string `json:"description,omitempty"`
+	// Template seeds the store's type and settings from a built-in
+	// template, e.g. "recall-strict-dedup".
+	Template string `json:"template,omitempty"`
 }
 
 // CreateStoreResponse is the response for POST /api/v1/stores.
@@ -546,6 +863,25 @@ type CreateStoreResponse struct {
 	SchemaVersion int       `json:"schema_version"`
 	Created       time.Time `json:"created"`
 	Description   string    `json:"description,omitempty"`
+	Template      string    `json:"template,omitempty"`
+}
+
+// CloneStoreRequest is the request body for POST /api/v1/stores/{store_id}/clone.
+type CloneStoreRequest struct {
+	TargetID    string `json:"target_id"`
+	Description string `json:"description,omitempty"`
+	types.CloneFilter
+}
+
+// CloneStoreResponse is the response for POST /api/v1/stores/{store_id}/clone.
+type CloneStoreResponse struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	SchemaVersion int       `json:"schema_version"`
+	Created       time.Time `json:"created"`
+	Description   string    `json:"description,omitempty"`
+	ClonedFrom    string    `json:"cloned_from"`
+	LoreCount     int64     `json:"lore_count"`
 }
 
 // Feedback handles POST /api/v1/lore/feedback and POST /api/v1/stores/{store_id}/lore/feedback
@@ -574,6 +910,7 @@ func (h *Handler) Feedback(w http.ResponseWriter, r *http.Request) {
 	for i, entry := range req.Feedback {
 		errs := validation.ValidateFeedbackEntry(i, entry.LoreID, entry.Type)
 		allErrors = append(allErrors, errs...)
+		allErrors = append(allErrors, validation.ValidateFeedbackNote(fmt.Sprintf("feedback[%d].note", i), entry.Note)...)
 	}
 	if len(allErrors) > 0 {
 		WriteProblemWithErrors(w, r, "Request contains invalid fields", allErrors)
@@ -587,6 +924,7 @@ func (h *Handler) Feedback(w http.ResponseWriter, r *http.Request) {
 			LoreID:   entry.LoreID,
 			Type:     entry.Type,
 			SourceID: req.SourceID,
+			Note:     entry.Note,
 		}
 	}
 
@@ -605,36 +943,259 @@ func (h *Handler) Feedback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Performance logging
-	duration := time.Since(start)
-	if duration > 500*time.Millisecond {
-		slog.Warn("feedback processing exceeded performance target",
+	slog.Info("feedback processed",
+		"component", "api",
+		"action", "feedback",
+		"store_id", storeID,
+		"source_id", req.SourceID,
+		"remote_addr", r.RemoteAddr,
+		"updated_count", len(result.Updates),
+		"skipped_count", len(result.Skipped),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+
+	h.notifyLowConfidence(storeID, result.Updates)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// BulkFeedback handles POST /api/v1/lore/feedback/bulk and POST /api/v1/stores/{store_id}/lore/feedback/bulk
+//
+// Applies one feedback type, and an optional note, to every listed lore
+// entry in a single transaction. If any entry does not exist, nothing is
+// applied and the missing IDs are reported with 404. Entries over the
+// feedback rate limit are skipped without failing the rest.
+func (h *Handler) BulkFeedback(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	storeID := StoreIDFromContext(r.Context())
+
+	s := h.getStoreForRequest(r)
+
+	var req bulkFeedbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err.Error()))
+		return
+	}
+
+	if errs := validation.ValidateBulkFeedbackRequest(req.SourceID, req.LoreIDs, req.Type, req.Note); len(errs) > 0 {
+		WriteProblemWithErrors(w, r, "Request contains invalid fields", errs)
+		return
+	}
+
+	feedbackEntries := make([]types.FeedbackEntry, len(req.LoreIDs))
+	for i, id := range req.LoreIDs {
+		feedbackEntries[i] = types.FeedbackEntry{
+			LoreID:   id,
+			Type:     req.Type,
+			SourceID: req.SourceID,
+			Note:     req.Note,
+		}
+	}
+
+	result, err := s.RecordFeedbackAtomic(r.Context(), feedbackEntries)
+	if err != nil {
+		slog.Error("bulk feedback processing failed",
 			"component", "api",
-			"action", "feedback",
+			"action", "bulk_feedback_failed",
 			"store_id", storeID,
 			"source_id", req.SourceID,
 			"remote_addr", r.RemoteAddr,
-			"duration_ms", duration.Milliseconds(),
-			"count", len(req.Feedback),
+			"error", err,
 		)
+		MapStoreError(w, r, err)
+		return
 	}
 
-	slog.Info("feedback processed",
+	var missing []string
+	for _, sk := range result.Skipped {
+		if sk.Reason == "not_found" {
+			missing = append(missing, sk.LoreID)
+		}
+	}
+	if len(missing) > 0 {
+		WriteProblem(w, r, http.StatusNotFound,
+			fmt.Sprintf("Lore entries not found: %s", strings.Join(missing, ", ")))
+		return
+	}
+
+	slog.Info("bulk feedback processed",
 		"component", "api",
-		"action", "feedback",
+		"action", "bulk_feedback",
 		"store_id", storeID,
 		"source_id", req.SourceID,
 		"remote_addr", r.RemoteAddr,
+		"type", req.Type,
 		"updated_count", len(result.Updates),
-		"skipped_count", len(result.Skipped),
-		"duration_ms", duration.Milliseconds(),
+		"duration_ms", time.Since(start).Milliseconds(),
 	)
 
+	h.notifyLowConfidence(storeID, result.Updates)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(result)
 }
 
+// FeedbackHistory handles GET /api/v1/lore/{id}/feedback and GET /api/v1/stores/{store_id}/lore/{id}/feedback
+//
+// Lists the feedback recorded for a lore entry, newest first, including any
+// notes. The optional limit query parameter defaults to 50 (max 500);
+// cursor resumes from a previous page's next_cursor.
+func (h *Handler) FeedbackHistory(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	storeID := StoreIDFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+
+	s := h.getStoreForRequest(r)
+
+	if err := validation.ValidateULID("id", id); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest,
+			"Invalid lore ID format: must be valid ULID")
+		return
+	}
+
+	limit := store.DefaultFeedbackHistoryLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > store.MaxFeedbackHistoryLimit {
+			WriteProblem(w, r, http.StatusBadRequest,
+				fmt.Sprintf("Invalid limit: must be an integer between 1 and %d", store.MaxFeedbackHistoryLimit))
+			return
+		}
+		limit = n
+	}
+	var after struct{ ID int64 }
+	if raw := r.URL.Query().Get("cursor"); raw != "" {
+		if err := decodeCursor(raw, &after); err != nil {
+			WriteProblem(w, r, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+	}
+
+	events, err := s.GetFeedbackHistory(r.Context(), id, after.ID, limit+1)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			WriteProblem(w, r, http.StatusNotFound,
+				"Lore entry not found")
+			return
+		}
+		slog.Error("feedback history lookup failed",
+			"component", "api",
+			"action", "feedback_history_failed",
+			"store_id", storeID,
+			"lore_id", id,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+	if events == nil {
+		events = []types.FeedbackEvent{}
+	}
+
+	resp := types.FeedbackHistoryResponse{LoreID: id}
+	resp.Events, resp.HasMore = page(events, limit)
+	if resp.HasMore {
+		resp.NextCursor = encodeCursor(struct{ ID int64 }{resp.Events[len(resp.Events)-1].ID})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// notifyLowConfidence emits a webhook for each feedback update that pushed
+// an entry below the low-confidence threshold.
+func (h *Handler) notifyLowConfidence(storeID string, updates []types.FeedbackResultUpdate) {
+	for _, u := range updates {
+		if webhook.CrossedLowConfidence(u.PreviousConfidence, u.CurrentConfidence) {
+			h.notify(webhook.EventLoreLowConfidence, storeID, webhook.LowConfidenceData{
+				LoreID:             u.LoreID,
+				PreviousConfidence: u.PreviousConfidence,
+				CurrentConfidence:  u.CurrentConfidence,
+				Threshold:          webhook.LowConfidenceThreshold,
+				Reason:             "feedback",
+			})
+		}
+	}
+}
+
+// CountLore handles GET /api/v1/lore/count and GET /api/v1/stores/{store_id}/lore/count
+//
+// Returns the number of live lore entries. Listings are paged without a
+// total, so clients needing one ask for it here.
+func (h *Handler) CountLore(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	storeID := StoreIDFromContext(r.Context())
+	stats, err := h.getStoreForRequest(r).GetStats(r.Context())
+	if err != nil {
+		slog.Error("lore count failed",
+			"component", "api",
+			"action", "count_lore_failed",
+			"store_id", storeID,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	resp := types.LoreCountResponse{Count: stats.LoreCount}
+	if IsStoreScoped(r.Context()) {
+		resp.StoreID = storeID
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetLore handles GET /api/v1/lore/{id} and GET /api/v1/stores/{store_id}/lore/{id}
+//
+// The ETag header carries the entry's version; send it as If-Match when
+// editing or deleting the entry.
+func (h *Handler) GetLore(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	storeID := StoreIDFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+	s := h.getStoreForRequest(r)
+
+	if err := validation.ValidateULID("id", id); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest,
+			"Invalid lore ID format: must be valid ULID")
+		return
+	}
+
+	entry, err := s.GetLore(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			WriteProblem(w, r, http.StatusNotFound, "Lore entry not found")
+			return
+		}
+		slog.Error("get lore failed",
+			"component", "api",
+			"action", "get_lore_failed",
+			"store_id", storeID,
+			"lore_id", id,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	setLoreETag(w, r, s, id)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entry)
+}
+
 // DeleteLore handles DELETE /api/v1/lore/{id} and DELETE /api/v1/stores/{store_id}/lore/{id}
+//
+// An If-Match header carrying the entry's current ETag (or "*") is required.
 func (h *Handler) DeleteLore(w http.ResponseWriter, r *http.Request) {
 	// Store type guard: /lore/* only valid for recall stores
 	if !h.requireRecallStore(w, r) {
@@ -653,9 +1214,25 @@ func (h *Handler) DeleteLore(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.Header.Get("If-Match") == "" {
+		WriteProblem(w, r, http.StatusPreconditionRequired,
+			"If-Match header is required: send the entry's ETag")
+		return
+	}
+
 	sourceID := extractSourceID(r)
 
-	err := s.DeleteLore(r.Context(), id, sourceID)
+	ifVersion, ok := ifMatchVersion(w, r, s, id)
+	if !ok {
+		return
+	}
+
+	var err error
+	if ifVersion != 0 {
+		err = s.DeleteLoreIfVersion(r.Context(), id, sourceID, ifVersion)
+	} else {
+		err = s.DeleteLore(r.Context(), id, sourceID)
+	}
 	if err != nil {
 		if errors.Is(err, store.ErrNotFound) {
 			// Generic message - don't echo user-supplied ID (Issue #3)
@@ -663,6 +1240,10 @@ func (h *Handler) DeleteLore(w http.ResponseWriter, r *http.Request) {
 				"Lore entry not found")
 			return
 		}
+		if errors.Is(err, plugin.ErrVetoed) || errors.Is(err, store.ErrVersionMismatch) {
+			MapStoreError(w, r, err)
+			return
+		}
 		slog.Error("delete lore failed",
 			"store_id", storeID,
 			"error", err,
@@ -685,12 +1266,20 @@ func (h *Handler) DeleteLore(w http.ResponseWriter, r *http.Request) {
 		"remote_addr", r.RemoteAddr,
 	)
 
+	h.notify(webhook.EventLoreDeleted, storeID, webhook.DeleteData{
+		LoreID:   id,
+		SourceID: sourceID,
+	})
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
 // --- Store Management Handlers ---
 
 // ListStores handles GET /api/v1/stores
+//
+// Lists stores ordered by ID. The optional limit query parameter defaults
+// to 100 (max 1000); cursor resumes from a previous page's next_cursor.
 func (h *Handler) ListStores(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -706,6 +1295,34 @@ func (h *Handler) ListStores(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	limit := DefaultStoreListLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > MaxStoreListLimit {
+			WriteProblem(w, r, http.StatusBadRequest,
+				fmt.Sprintf("Invalid limit: must be an integer between 1 and %d", MaxStoreListLimit))
+			return
+		}
+		limit = n
+	}
+	var after struct{ ID string }
+	if raw := r.URL.Query().Get("cursor"); raw != "" {
+		if err := decodeCursor(raw, &after); err != nil {
+			WriteProblem(w, r, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+	}
+
+	// Page before enriching, so only the listed stores are opened
+	total := len(storeInfos)
+	sort.Slice(storeInfos, func(i, j int) bool {
+		return storeInfos[i].ID < storeInfos[j].ID
+	})
+	start := sort.Search(len(storeInfos), func(i int) bool {
+		return storeInfos[i].ID > after.ID
+	})
+	storeInfos, hasMore := page(storeInfos[start:], limit)
+
 	// Enrich with record counts
 	items := make([]StoreListItem, 0, len(storeInfos))
 	for _, info := range storeInfos {
@@ -716,6 +1333,7 @@ func (h *Handler) ListStores(w http.ResponseWriter, r *http.Request) {
 			LastAccessed:  info.LastAccessed,
 			SizeBytes:     info.SizeBytes,
 			Description:   info.Description,
+			State:         info.State,
 		}
 
 		// Get record count from store (if loaded or loadable)
@@ -730,24 +1348,17 @@ func (h *Handler) ListStores(w http.ResponseWriter, r *http.Request) {
 		items = append(items, item)
 	}
 
-	// Sort by ID
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].ID < items[j].ID
-	})
-
 	resp := ListStoresResponse{
-		Stores: items,
-		Total:  len(items),
+		Stores:  items,
+		Total:   total,
+		HasMore: hasMore,
+	}
+	if hasMore {
+		resp.NextCursor = encodeCursor(struct{ ID string }{items[len(items)-1].ID})
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
-
-	slog.Info("stores listed",
-		"component", "api",
-		"action", "list_stores",
-		"count", len(items),
-	)
 }
 
 // GetStoreInfo handles GET /api/v1/stores/{store_id}
@@ -780,6 +1391,14 @@ func (h *Handler) GetStoreInfo(w http.ResponseWriter, r *http.Request) {
 			WriteProblem(w, r, http.StatusNotFound, "Store not found")
 			return
 		}
+		if errors.Is(err, multistore.ErrStoreArchived) {
+			h.writeArchivedStoreInfo(w, r, decodedID)
+			return
+		}
+		if errors.Is(err, multistore.ErrStoreDeleting) {
+			WriteProblem(w, r, http.StatusGone, "Store is being deleted")
+			return
+		}
 		slog.Error("get store failed", "store_id", decodedID, "error", err)
 		WriteProblem(w, r, http.StatusInternalServerError, "Internal error")
 		return
@@ -807,18 +1426,15 @@ func (h *Handler) GetStoreInfo(w http.ResponseWriter, r *http.Request) {
 		Created:       managed.Meta.Created,
 		LastAccessed:  managed.Meta.LastAccessed,
 		Description:   managed.Meta.Description,
+		Template:      managed.Meta.Template,
+		ClonedFrom:    managed.Meta.ClonedFrom,
+		State:         multistore.StoreStateActive,
 		SizeBytes:     sizeBytes,
 		Stats:         stats,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
-
-	slog.Info("store info retrieved",
-		"component", "api",
-		"action", "get_store_info",
-		"store_id", decodedID,
-	)
 }
 
 // CreateStore handles POST /api/v1/stores
@@ -842,8 +1458,24 @@ func (h *Handler) CreateStore(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create store with type
-	managed, err := h.storeManager.CreateStore(ctx, req.StoreID, req.Type, req.Description)
+	// Create store from a template, or with a type
+	var managed *multistore.ManagedStore
+	var err error
+	if req.Template != "" {
+		t, terr := multistore.Template(req.Template)
+		if terr != nil {
+			WriteProblem(w, r, http.StatusBadRequest, terr.Error())
+			return
+		}
+		if req.Type != "" && req.Type != t.Type {
+			WriteProblem(w, r, http.StatusBadRequest,
+				fmt.Sprintf("Type %q conflicts with template %q, which creates %q stores", req.Type, t.Name, t.Type))
+			return
+		}
+		managed, err = h.storeManager.CreateStoreFromTemplate(ctx, req.StoreID, req.Template, req.Description)
+	} else {
+		managed, err = h.storeManager.CreateStore(ctx, req.StoreID, req.Type, req.Description)
+	}
 	if err != nil {
 		if errors.Is(err, multistore.ErrStoreAlreadyExists) {
 			WriteProblemConflict(w, r, fmt.Sprintf("Store already exists: %s", req.StoreID))
@@ -864,6 +1496,7 @@ func (h *Handler) CreateStore(w http.ResponseWriter, r *http.Request) {
 		SchemaVersion: managed.SchemaVersion(ctx),
 		Created:       managed.Meta.Created,
 		Description:   managed.Meta.Description,
+		Template:      managed.Meta.Template,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -875,6 +1508,7 @@ func (h *Handler) CreateStore(w http.ResponseWriter, r *http.Request) {
 		"action", "create_store",
 		"store_id", req.StoreID,
 		"store_type", managed.Type(),
+		"template", managed.Meta.Template,
 		"request_id", GetRequestID(ctx),
 		"remote_addr", r.RemoteAddr,
 	)
@@ -915,14 +1549,23 @@ func (h *Handler) DeleteStore(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Delete store
-	if err := h.storeManager.DeleteStore(ctx, decodedID); err != nil {
-		if errors.Is(err, multistore.ErrStoreNotFound) {
+	// Delete store, waiting a bounded time for in-flight requests to drain
+	drainCtx, cancel := context.WithTimeout(ctx, storeDrainTimeout)
+	defer cancel()
+	if err := h.storeManager.DeleteStore(drainCtx, decodedID); err != nil {
+		switch {
+		case errors.Is(err, multistore.ErrStoreNotFound):
 			WriteProblem(w, r, http.StatusNotFound, "Store not found")
-			return
+		case errors.Is(err, multistore.ErrStoreDeleting):
+			WriteProblemConflict(w, r, fmt.Sprintf("Store is already being deleted: %s", decodedID))
+		case errors.Is(err, context.DeadlineExceeded):
+			w.Header().Set("Retry-After", strconv.Itoa(int(storeDrainTimeout.Seconds())))
+			WriteProblem(w, r, http.StatusServiceUnavailable,
+				"In-flight requests to the store did not finish; the store was not deleted. Retry later.")
+		default:
+			slog.Error("delete store failed", "store_id", decodedID, "error", err)
+			WriteProblem(w, r, http.StatusInternalServerError, "Internal error deleting store")
 		}
-		slog.Error("delete store failed", "store_id", decodedID, "error", err)
-		WriteProblem(w, r, http.StatusInternalServerError, "Internal error deleting store")
 		return
 	}
 
@@ -936,3 +1579,254 @@ func (h *Handler) DeleteStore(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// CloneStore handles POST /api/v1/stores/{store_id}/clone
+//
+// Creates target_id from a point-in-time copy of the store, keeping only
+// lore in categories and at or above min_confidence when they are given.
+func (h *Handler) CloneStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	start := time.Now()
+
+	if h.storeManager == nil {
+		WriteProblem(w, r, http.StatusServiceUnavailable, "Multi-store support not configured")
+		return
+	}
+
+	sourceID, ok := decodeStoreIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req CloneStoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err.Error()))
+		return
+	}
+	if err := multistore.ValidateStoreID(req.TargetID); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errs := validation.ValidateCloneFilter(req.CloneFilter); len(errs) > 0 {
+		WriteProblemWithErrors(w, r, "Request contains invalid fields", errs)
+		return
+	}
+
+	source, err := h.storeManager.GetStore(ctx, sourceID)
+	if err != nil {
+		if errors.Is(err, multistore.ErrStoreNotFound) {
+			WriteProblem(w, r, http.StatusNotFound, "Store not found")
+			return
+		}
+		if errors.Is(err, multistore.ErrStoreArchived) {
+			WriteProblemStoreArchived(w, r, sourceID)
+			return
+		}
+		if errors.Is(err, multistore.ErrStoreDeleting) {
+			WriteProblem(w, r, http.StatusGone, "Store is being deleted")
+			return
+		}
+		slog.Error("get store failed", "store_id", sourceID, "error", err)
+		WriteProblem(w, r, http.StatusInternalServerError, "Internal error")
+		return
+	}
+	if !req.CloneFilter.IsZero() && source.Type() != multistore.DefaultStoreType {
+		WriteProblem(w, r, http.StatusBadRequest,
+			fmt.Sprintf("Lore filters only apply to recall stores. Store %q has type %q", sourceID, source.Type()))
+		return
+	}
+
+	managed, copied, err := h.storeManager.CloneStore(ctx, sourceID, req.TargetID, req.Description, req.CloneFilter)
+	if err != nil {
+		if errors.Is(err, multistore.ErrStoreAlreadyExists) {
+			WriteProblemConflict(w, r, fmt.Sprintf("Store already exists: %s", req.TargetID))
+			return
+		}
+		if errors.Is(err, multistore.ErrStoreNotFound) {
+			WriteProblem(w, r, http.StatusNotFound, "Store not found")
+			return
+		}
+		slog.Error("clone store failed", "store_id", sourceID, "target_id", req.TargetID, "error", err)
+		WriteProblem(w, r, http.StatusInternalServerError, "Internal error cloning store")
+		return
+	}
+
+	resp := CloneStoreResponse{
+		ID:            managed.ID,
+		Type:          managed.Type(),
+		SchemaVersion: managed.SchemaVersion(ctx),
+		Created:       managed.Meta.Created,
+		Description:   managed.Meta.Description,
+		ClonedFrom:    sourceID,
+		LoreCount:     copied,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+
+	slog.Info("store cloned via API",
+		"component", "api",
+		"action", "clone_store",
+		"store_id", sourceID,
+		"target_id", req.TargetID,
+		"lore_count", copied,
+		"duration_ms", time.Since(start).Milliseconds(),
+		"request_id", GetRequestID(ctx),
+		"remote_addr", r.RemoteAddr,
+	)
+}
+
+// writeArchivedStoreInfo writes the info response for an archived store,
+// which has no database to report stats from.
+func (h *Handler) writeArchivedStoreInfo(w http.ResponseWriter, r *http.Request, storeID string) {
+	info, err := h.storeManager.GetStoreInfo(r.Context(), storeID)
+	if err != nil {
+		slog.Error("get archived store info failed", "store_id", storeID, "error", err)
+		WriteProblem(w, r, http.StatusInternalServerError, "Internal error")
+		return
+	}
+
+	resp := StoreInfoResponse{
+		ID:            info.ID,
+		Type:          info.Type,
+		SchemaVersion: info.SchemaVersion,
+		Created:       info.Created,
+		LastAccessed:  info.LastAccessed,
+		Description:   info.Description,
+		Template:      info.Template,
+		State:         info.State,
+		Archive:       info.Archive,
+		SizeBytes:     info.SizeBytes,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ArchiveStore handles POST /api/v1/stores/{store_id}/archive
+//
+// Closes the store and replaces its database with a compressed archive,
+// uploaded to snapshot storage when S3 is configured. Requests to the store
+// return 423 until it is thawed.
+func (h *Handler) ArchiveStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if h.storeManager == nil {
+		WriteProblem(w, r, http.StatusServiceUnavailable, "Multi-store support not configured")
+		return
+	}
+
+	storeID, ok := decodeStoreIDParam(w, r)
+	if !ok {
+		return
+	}
+	if multistore.IsDefaultStore(storeID) {
+		WriteProblemForbidden(w, r, "Cannot archive the default store")
+		return
+	}
+
+	if _, err := h.storeManager.ArchiveStore(ctx, storeID); err != nil {
+		switch {
+		case errors.Is(err, multistore.ErrStoreNotFound):
+			WriteProblem(w, r, http.StatusNotFound, "Store not found")
+		case errors.Is(err, multistore.ErrStoreArchived):
+			WriteProblemConflict(w, r, fmt.Sprintf("Store already archived: %s", storeID))
+		case errors.Is(err, multistore.ErrStoreDeleting):
+			WriteProblem(w, r, http.StatusGone, "Store is being deleted")
+		default:
+			slog.Error("archive store failed", "store_id", storeID, "error", err)
+			WriteProblem(w, r, http.StatusInternalServerError, "Internal error archiving store")
+		}
+		return
+	}
+
+	slog.Info("store archived via API",
+		"component", "api",
+		"action", "archive_store",
+		"store_id", storeID,
+		"request_id", GetRequestID(ctx),
+		"remote_addr", r.RemoteAddr,
+	)
+
+	h.writeArchivedStoreInfo(w, r, storeID)
+}
+
+// ThawStore handles POST /api/v1/stores/{store_id}/thaw
+//
+// Restores an archived store from its archive and reopens it.
+func (h *Handler) ThawStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	start := time.Now()
+
+	if h.storeManager == nil {
+		WriteProblem(w, r, http.StatusServiceUnavailable, "Multi-store support not configured")
+		return
+	}
+
+	storeID, ok := decodeStoreIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	managed, err := h.storeManager.ThawStore(ctx, storeID)
+	if err != nil {
+		switch {
+		case errors.Is(err, multistore.ErrStoreNotFound):
+			WriteProblem(w, r, http.StatusNotFound, "Store not found")
+		case errors.Is(err, multistore.ErrStoreNotArchived):
+			WriteProblemConflict(w, r, fmt.Sprintf("Store is not archived: %s", storeID))
+		case errors.Is(err, multistore.ErrStoreDeleting):
+			WriteProblem(w, r, http.StatusGone, "Store is being deleted")
+		default:
+			slog.Error("thaw store failed", "store_id", storeID, "error", err)
+			WriteProblem(w, r, http.StatusInternalServerError, "Internal error thawing store")
+		}
+		return
+	}
+
+	resp := StoreInfoResponse{
+		ID:            managed.ID,
+		Type:          managed.Type(),
+		SchemaVersion: managed.SchemaVersion(ctx),
+		Created:       managed.Meta.Created,
+		LastAccessed:  managed.Meta.LastAccessed,
+		Description:   managed.Meta.Description,
+		Template:      managed.Meta.Template,
+		ClonedFrom:    managed.Meta.ClonedFrom,
+		State:         multistore.StoreStateActive,
+	}
+	if stats, err := managed.Store.GetExtendedStats(ctx); err == nil {
+		resp.Stats = stats
+	}
+	if info, err := os.Stat(filepath.Join(managed.BasePath, "engram.db")); err == nil {
+		resp.SizeBytes = info.Size()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+
+	slog.Info("store thawed via API",
+		"component", "api",
+		"action", "thaw_store",
+		"store_id", storeID,
+		"duration_ms", time.Since(start).Milliseconds(),
+		"request_id", GetRequestID(ctx),
+		"remote_addr", r.RemoteAddr,
+	)
+}
+
+// decodeStoreIDParam returns the decoded, validated store_id URL
+// parameter, writing a 400 response and returning false if it is invalid.
+func decodeStoreIDParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	storeID, err := url.PathUnescape(chi.URLParam(r, "store_id"))
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "Invalid store ID encoding")
+		return "", false
+	}
+	if err := multistore.ValidateStoreID(storeID); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return "", false
+	}
+	return storeID, true
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/hyperengineering/engram/internal/store"
 	engramsync "github.com/hyperengineering/engram/internal/sync"
 	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/validation"
 )
 
 // --- Mock Implementations for Testing ---
@@ -40,6 +41,34 @@ type mockStore struct {
 	feedbackResult   *types.FeedbackResult
 	feedbackErr      error
 	deleteErr        error
+	lastFeedback     []types.FeedbackEntry
+	history          []types.FeedbackEvent
+	historyErr       error
+	lastHistoryLimit int
+	historyBefore    int64
+	reviewQueue      []types.ReviewCandidate
+	lastReviewQuery  *types.ReviewQueueQuery
+	review           *types.LoreReview
+	reviewErr        error
+	lastReviewStatus types.ReviewStatus
+	lock             *types.ConfidenceLock
+	lockErr          error
+	lastLock         *types.ConfidenceLock
+	lockCleared      bool
+	entry            *types.LoreEntry
+	syncMeta         map[string]string
+	contradictions   []types.Contradiction
+	contradictionQ   *types.ContradictionQuery
+	loreVersion      int
+	lastIfVersion    int
+	updated          *types.LoreEntry
+	lastUpdate       *types.LoreUpdate
+	versions         []types.LoreVersion
+	versionErr       error
+	lastRevert       int
+	searchResults    []types.SearchResult
+	searchErr        error
+	lastSearch       *types.SearchQuery
 }
 
 func (m *mockStore) IngestLore(ctx context.Context, entries []types.NewLoreEntry) (*types.IngestResult, error) {
@@ -58,14 +87,29 @@ func (m *mockStore) FindSimilar(ctx context.Context, embedding []float32, catego
 	return nil, nil
 }
 
+func (m *mockStore) SearchLore(ctx context.Context, query types.SearchQuery) ([]types.SearchResult, error) {
+	m.lastSearch = &query
+	return m.searchResults, m.searchErr
+}
+
 func (m *mockStore) MergeLore(ctx context.Context, targetID string, source types.NewLoreEntry) error {
 	return nil
 }
 
 func (m *mockStore) GetLore(ctx context.Context, id string) (*types.LoreEntry, error) {
+	if m.entry != nil {
+		return m.entry, nil
+	}
 	return nil, store.ErrNotFound
 }
 
+func (m *mockStore) GetLoreVersion(ctx context.Context, id string) (int, error) {
+	if m.loreVersion != 0 {
+		return m.loreVersion, nil
+	}
+	return 1, nil
+}
+
 func (m *mockStore) DeleteLore(ctx context.Context, id, sourceID string) error {
 	if m.deleteErr != nil {
 		return m.deleteErr
@@ -73,6 +117,38 @@ func (m *mockStore) DeleteLore(ctx context.Context, id, sourceID string) error {
 	return nil
 }
 
+func (m *mockStore) DeleteLoreIfVersion(ctx context.Context, id, sourceID string, ifVersion int) error {
+	m.lastIfVersion = ifVersion
+	return m.DeleteLore(ctx, id, sourceID)
+}
+
+func (m *mockStore) UpdateLore(ctx context.Context, id string, update types.LoreUpdate, ifVersion int) (*types.LoreEntry, error) {
+	m.lastUpdate = &update
+	m.lastIfVersion = ifVersion
+	if m.versionErr != nil {
+		return nil, m.versionErr
+	}
+	if m.updated != nil {
+		return m.updated, nil
+	}
+	return &types.LoreEntry{ID: id}, nil
+}
+
+func (m *mockStore) ListLoreVersions(ctx context.Context, loreID string) ([]types.LoreVersion, error) {
+	return m.versions, m.versionErr
+}
+
+func (m *mockStore) RevertLore(ctx context.Context, id string, version int, sourceID string) (*types.LoreEntry, error) {
+	m.lastRevert = version
+	if m.versionErr != nil {
+		return nil, m.versionErr
+	}
+	if m.updated != nil {
+		return m.updated, nil
+	}
+	return &types.LoreEntry{ID: id}, nil
+}
+
 func (m *mockStore) GetMetadata(ctx context.Context) (*types.StoreMetadata, error) {
 	return nil, nil
 }
@@ -94,6 +170,7 @@ func (m *mockStore) GetSnapshotPath(ctx context.Context) (string, error) {
 }
 
 func (m *mockStore) RecordFeedback(ctx context.Context, feedback []types.FeedbackEntry) (*types.FeedbackResult, error) {
+	m.lastFeedback = feedback
 	if m.feedbackErr != nil {
 		return nil, m.feedbackErr
 	}
@@ -103,6 +180,73 @@ func (m *mockStore) RecordFeedback(ctx context.Context, feedback []types.Feedbac
 	return &types.FeedbackResult{Updates: []types.FeedbackResultUpdate{}}, nil
 }
 
+func (m *mockStore) RecordFeedbackAtomic(ctx context.Context, feedback []types.FeedbackEntry) (*types.FeedbackResult, error) {
+	return m.RecordFeedback(ctx, feedback)
+}
+
+func (m *mockStore) ListReviewQueue(ctx context.Context, q types.ReviewQueueQuery) ([]types.ReviewCandidate, error) {
+	m.lastReviewQuery = &q
+	return m.reviewQueue, m.reviewErr
+}
+
+func (m *mockStore) FindContradictions(ctx context.Context, q types.ContradictionQuery) ([]types.Contradiction, error) {
+	m.contradictionQ = &q
+	return m.contradictions, nil
+}
+
+func (m *mockStore) GetReview(ctx context.Context, loreID string) (*types.LoreReview, error) {
+	if m.reviewErr != nil {
+		return nil, m.reviewErr
+	}
+	if m.review != nil {
+		return m.review, nil
+	}
+	return &types.LoreReview{LoreID: loreID, Status: types.ReviewUnreviewed}, nil
+}
+
+func (m *mockStore) ReviewLore(ctx context.Context, loreID string, status types.ReviewStatus, reviewer, note string) (*types.LoreReview, error) {
+	m.lastReviewStatus = status
+	if m.reviewErr != nil {
+		return nil, m.reviewErr
+	}
+	return &types.LoreReview{LoreID: loreID, Status: status, Reviewer: reviewer, Note: note}, nil
+}
+
+func (m *mockStore) GetConfidenceLock(ctx context.Context, loreID string) (*types.ConfidenceLock, error) {
+	if m.lockErr != nil {
+		return nil, m.lockErr
+	}
+	if m.lock != nil {
+		return m.lock, nil
+	}
+	return &types.ConfidenceLock{LoreID: loreID}, nil
+}
+
+func (m *mockStore) SetConfidenceLock(ctx context.Context, loreID string, floor, ceiling *float64, setBy string) (*types.ConfidenceLock, error) {
+	m.lastLock = &types.ConfidenceLock{LoreID: loreID, Floor: floor, Ceiling: ceiling, SetBy: setBy}
+	if m.lockErr != nil {
+		return nil, m.lockErr
+	}
+	return m.lastLock, nil
+}
+
+func (m *mockStore) ClearConfidenceLock(ctx context.Context, loreID string) error {
+	if m.lockErr != nil {
+		return m.lockErr
+	}
+	m.lockCleared = true
+	return nil
+}
+
+func (m *mockStore) GetFeedbackHistory(ctx context.Context, loreID string, beforeID int64, limit int) ([]types.FeedbackEvent, error) {
+	m.historyBefore = beforeID
+	m.lastHistoryLimit = limit
+	if m.historyErr != nil {
+		return nil, m.historyErr
+	}
+	return m.history, nil
+}
+
 func (m *mockStore) DecayConfidence(ctx context.Context, threshold time.Time, amount float64) (int64, error) {
 	return 0, nil
 }
@@ -144,9 +288,15 @@ func (m *mockStore) AppendChangeLogBatch(ctx context.Context, entries []engramsy
 func (m *mockStore) GetChangeLogAfter(ctx context.Context, afterSeq int64, limit int) ([]engramsync.ChangeLogEntry, error) {
 	return nil, nil
 }
+func (m *mockStore) QueryChangeLog(ctx context.Context, afterSeq int64, limit int, filter engramsync.ChangeLogFilter) ([]engramsync.ChangeLogEntry, error) {
+	return nil, nil
+}
 func (m *mockStore) GetLatestSequence(ctx context.Context) (int64, error) {
 	return 0, nil
 }
+func (m *mockStore) GetLatestEntries(ctx context.Context, tableNames []string) ([]engramsync.ChangeLogEntry, error) {
+	return nil, nil
+}
 func (m *mockStore) CheckPushIdempotency(ctx context.Context, pushID string) ([]byte, bool, error) {
 	return nil, false, nil
 }
@@ -156,8 +306,35 @@ func (m *mockStore) RecordPushIdempotency(ctx context.Context, pushID, storeID s
 func (m *mockStore) CleanExpiredIdempotency(ctx context.Context) (int64, error) {
 	return 0, nil
 }
+func (m *mockStore) CreatePushSession(ctx context.Context, session engramsync.PushSession) error {
+	return nil
+}
+func (m *mockStore) GetPushSession(ctx context.Context, pushID string) (*engramsync.PushSession, error) {
+	return nil, nil
+}
+func (m *mockStore) AppendPushSessionEntries(ctx context.Context, pushID string, entries []engramsync.ChangeLogEntry) (int, error) {
+	return 0, nil
+}
+func (m *mockStore) GetPushSessionEntries(ctx context.Context, pushID string) ([]engramsync.ChangeLogEntry, error) {
+	return nil, nil
+}
+func (m *mockStore) DeletePushSession(ctx context.Context, pushID string) error {
+	return nil
+}
+func (m *mockStore) CleanExpiredPushSessions(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+func (m *mockStore) ListSyncConflicts(ctx context.Context, filter engramsync.SyncConflictFilter) ([]engramsync.SyncConflict, error) {
+	return nil, nil
+}
+func (m *mockStore) GetSyncConflict(ctx context.Context, id string) (*engramsync.SyncConflict, error) {
+	return nil, nil
+}
+func (m *mockStore) ResolveSyncConflict(ctx context.Context, id, resolution, note string) (*engramsync.SyncConflict, error) {
+	return nil, nil
+}
 func (m *mockStore) GetSyncMeta(ctx context.Context, key string) (string, error) {
-	return "", nil
+	return m.syncMeta[key], nil
 }
 func (m *mockStore) SetSyncMeta(ctx context.Context, key, value string) error {
 	return nil
@@ -183,11 +360,13 @@ func (m *mockStore) Close() error {
 
 // mockEmbedder implements the embedding.Embedder interface for testing
 type mockEmbedder struct {
-	model string
+	model  string
+	vector []float32
+	err    error
 }
 
 func (m *mockEmbedder) Embed(ctx context.Context, content string) ([]float32, error) {
-	return nil, nil
+	return m.vector, m.err
 }
 
 func (m *mockEmbedder) EmbedBatch(ctx context.Context, contents []string) ([][]float32, error) {
@@ -201,10 +380,11 @@ func (m *mockEmbedder) ModelName() string {
 // newTestHandler creates a Handler with minimal dependencies for health endpoint testing
 func newTestHandler(s store.Store, embedder *mockEmbedder, apiKey, version string) *Handler {
 	return &Handler{
-		store:    s,
-		embedder: embedder,
-		apiKey:   apiKey,
-		version:  version,
+		store:          s,
+		embedder:       embedder,
+		apiKey:         apiKey,
+		version:        version,
+		maxIngestBytes: DefaultMaxIngestBytes,
 	}
 }
 
@@ -285,6 +465,28 @@ func TestHealth_LoreCountReflectsStoreValue(t *testing.T) {
 	}
 }
 
+func TestCountLore(t *testing.T) {
+	s := &mockStore{stats: &types.StoreStats{LoreCount: 42}}
+	handler := newTestHandler(s, &mockEmbedder{model: "test-model"}, "api-key", "1.0.0")
+	router := NewRouter(handler, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lore/count", nil)
+	req.Header.Set("Authorization", "Bearer api-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp types.LoreCountResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Count != 42 {
+		t.Errorf("count = %d, want 42", resp.Count)
+	}
+}
+
 func TestHealth_LastSnapshotNullWhenNone(t *testing.T) {
 	store := &mockStore{
 		stats: &types.StoreStats{
@@ -1219,6 +1421,125 @@ func TestIngestLore_PartialBatch(t *testing.T) {
 	}
 }
 
+func TestIngestLore_PerEntryResults(t *testing.T) {
+	s := &mockStore{
+		stats: &types.StoreStats{},
+		ingestResult: &types.IngestResult{
+			Accepted: 1,
+			Merged:   1,
+			Results: []types.IngestEntryResult{
+				{Index: 0, Status: types.IngestMerged, LoreID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", MergedIntoID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Similarity: 0.97},
+				{Index: 1, Status: types.IngestAccepted, LoreID: "01BX5ZZKBKACTAV9WEVGEMMVRZ"},
+			},
+		},
+	}
+	handler := newTestHandler(s, &mockEmbedder{}, "api-key", "1.0.0")
+
+	body := `{
+		"source_id": "devcontainer-abc123",
+		"lore": [
+			{"content": "", "category": "DEPENDENCY_BEHAVIOR", "confidence": 0.5},
+			{"content": "duplicate", "category": "PATTERN_OUTCOME", "confidence": 0.8},
+			{"content": "new", "category": "PATTERN_OUTCOME", "confidence": 0.8}
+		]
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lore", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.IngestLore(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp types.IngestResult
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Results) != 3 {
+		t.Fatalf("results = %+v, want one per submitted entry", resp.Results)
+	}
+	if r := resp.Results[0]; r.Index != 0 || r.Status != types.IngestRejected || len(r.Errors) == 0 {
+		t.Errorf("results[0] = %+v, want rejected with errors", r)
+	}
+	if r := resp.Results[1]; r.Index != 1 || r.Status != types.IngestMerged || r.MergedIntoID != "01ARZ3NDEKTSV4RRFFQ69G5FAV" || r.Similarity != 0.97 {
+		t.Errorf("results[1] = %+v, want merged", r)
+	}
+	if r := resp.Results[2]; r.Index != 2 || r.Status != types.IngestAccepted || r.LoreID != "01BX5ZZKBKACTAV9WEVGEMMVRZ" {
+		t.Errorf("results[2] = %+v, want accepted", r)
+	}
+	if len(resp.CreatedIDs) != 1 || resp.CreatedIDs[0] != "01BX5ZZKBKACTAV9WEVGEMMVRZ" {
+		t.Errorf("created_ids = %v, want only the accepted entry", resp.CreatedIDs)
+	}
+	if loc := w.Header().Get("Location"); loc != "" {
+		t.Errorf("Location = %q for a multi-entry batch, want none", loc)
+	}
+}
+
+func TestIngestLore_SingleEntryLocation(t *testing.T) {
+	const id = "01BX5ZZKBKACTAV9WEVGEMMVRZ"
+	s := &mockStore{
+		stats: &types.StoreStats{},
+		ingestResult: &types.IngestResult{
+			Accepted: 1,
+			Results:  []types.IngestEntryResult{{Index: 0, Status: types.IngestAccepted, LoreID: id}},
+		},
+	}
+	handler := newTestHandler(s, &mockEmbedder{}, "api-key", "1.0.0")
+
+	body := `{"source_id": "devcontainer-abc123", "lore": [{"content": "new", "category": "PATTERN_OUTCOME", "confidence": 0.8}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/stores/acme%2Fpayments/lore/", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.IngestLore(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if loc, want := w.Header().Get("Location"), "/api/v1/stores/acme%2Fpayments/lore/"+id; loc != want {
+		t.Errorf("Location = %q, want %q", loc, want)
+	}
+	var resp types.IngestResult
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.CreatedIDs) != 1 || resp.CreatedIDs[0] != id {
+		t.Errorf("created_ids = %v, want [%s]", resp.CreatedIDs, id)
+	}
+}
+
+func TestIngestLore_LanguageAndScope(t *testing.T) {
+	s := &mockStore{stats: &types.StoreStats{}}
+	handler := newTestHandler(s, &mockEmbedder{}, "api-key", "1.0.0")
+
+	body := `{
+		"source_id": "devcontainer-abc123",
+		"lore": [
+			{"content": "scoped", "category": "PATTERN_OUTCOME", "confidence": 0.7, "language": "de", "repo": "github.com/acme/payments", "path": "/internal/queue/"},
+			{"content": "bad language", "category": "PATTERN_OUTCOME", "confidence": 0.7, "language": "German"},
+			{"content": "bad repo", "category": "PATTERN_OUTCOME", "confidence": 0.7, "repo": "acme payments"},
+			{"content": "bad path", "category": "PATTERN_OUTCOME", "confidence": 0.7, "path": "internal/../secrets"}
+		]
+	}`
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lore", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.IngestLore(w, req)
+
+	var resp types.IngestResult
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Accepted != 1 || resp.Rejected != 3 {
+		t.Errorf("accepted = %d, rejected = %d; want 1, 3", resp.Accepted, resp.Rejected)
+	}
+	want := types.NewLoreEntry{
+		Content: "scoped", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "devcontainer-abc123",
+		Language: "de", Repo: "github.com/acme/payments", Path: "internal/queue",
+	}
+	if len(s.lastEntries) != 1 || s.lastEntries[0] != want {
+		t.Errorf("entries = %+v, want %+v", s.lastEntries, want)
+	}
+}
+
 func TestIngestLore_EmptyLoreArray(t *testing.T) {
 	s := &mockStore{stats: &types.StoreStats{}}
 	embedder := &mockEmbedder{model: "text-embedding-3-small"}
@@ -1242,6 +1563,34 @@ func TestIngestLore_EmptyLoreArray(t *testing.T) {
 	}
 }
 
+func TestIngestLore_BodyTooLarge(t *testing.T) {
+	s := &mockStore{stats: &types.StoreStats{}}
+	embedder := &mockEmbedder{model: "text-embedding-3-small"}
+	handler := newTestHandler(s, embedder, "api-key", "1.0.0")
+	WithMaxIngestBytes(256)(handler)
+
+	body := `{"source_id": "devcontainer-abc123", "lore": [{"content": "` + strings.Repeat("x", 512) + `", "category": "DEPENDENCY_BEHAVIOR", "confidence": 0.5}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lore", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.IngestLore(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusRequestEntityTooLarge, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", ct)
+	}
+	var problem ProblemTooLarge
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("failed to unmarshal problem: %v", err)
+	}
+	if problem.LimitBytes != 256 || !strings.Contains(problem.Detail, "256 bytes") {
+		t.Errorf("problem = %+v, want a 256 byte limit", problem)
+	}
+}
+
 func TestIngestLore_BatchTooLarge(t *testing.T) {
 	s := &mockStore{stats: &types.StoreStats{}}
 	embedder := &mockEmbedder{model: "text-embedding-3-small"}
@@ -2309,6 +2658,259 @@ func TestFeedback_ContentType(t *testing.T) {
 	}
 }
 
+func TestBulkFeedback_AppliesTypeAndNoteToAll(t *testing.T) {
+	s := &mockStore{
+		stats: &types.StoreStats{},
+		feedbackResult: &types.FeedbackResult{
+			Updates: []types.FeedbackResultUpdate{
+				{LoreID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", PreviousConfidence: 0.5, CurrentConfidence: 0.42},
+				{LoreID: "01ARZ3NDEKTSV4RRFFQ69G5FAW", PreviousConfidence: 0.6, CurrentConfidence: 0.52},
+			},
+		},
+	}
+	handler := newTestHandler(s, &mockEmbedder{}, "api-key", "1.0.0")
+
+	body := `{
+		"source_id": "devcontainer-abc123",
+		"lore_ids": ["01ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAW"],
+		"type": "not_relevant",
+		"note": "recalled for a Go question, entries are about Python"
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lore/feedback/bulk", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.BulkFeedback(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if len(s.lastFeedback) != 2 {
+		t.Fatalf("store received %d entries, want 2", len(s.lastFeedback))
+	}
+	for _, e := range s.lastFeedback {
+		if e.Type != "not_relevant" || e.SourceID != "devcontainer-abc123" || e.Note == "" {
+			t.Errorf("entry = %+v, want shared type, source and note", e)
+		}
+	}
+
+	var result types.FeedbackResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(result.Updates) != 2 {
+		t.Errorf("expected 2 updates, got %d", len(result.Updates))
+	}
+}
+
+func TestBulkFeedback_MissingEntriesReturn404(t *testing.T) {
+	s := &mockStore{
+		stats: &types.StoreStats{},
+		feedbackResult: &types.FeedbackResult{
+			Updates: []types.FeedbackResultUpdate{},
+			Skipped: []types.FeedbackSkipped{{LoreID: "01ARZ3NDEKTSV4RRFFQ69G5FAW", Reason: "not_found"}},
+		},
+	}
+	handler := newTestHandler(s, &mockEmbedder{}, "api-key", "1.0.0")
+
+	body := `{"source_id": "src", "lore_ids": ["01ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAW"], "type": "helpful"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lore/feedback/bulk", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.BulkFeedback(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "01ARZ3NDEKTSV4RRFFQ69G5FAW") {
+		t.Errorf("body should name the missing entry: %s", w.Body.String())
+	}
+}
+
+func TestBulkFeedback_InvalidRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"invalid JSON", `{`, http.StatusBadRequest},
+		{"missing source", `{"lore_ids": ["01ARZ3NDEKTSV4RRFFQ69G5FAV"], "type": "helpful"}`, http.StatusUnprocessableEntity},
+		{"empty ids", `{"source_id": "src", "lore_ids": [], "type": "helpful"}`, http.StatusUnprocessableEntity},
+		{"bad id", `{"source_id": "src", "lore_ids": ["nope"], "type": "helpful"}`, http.StatusUnprocessableEntity},
+		{"bad type", `{"source_id": "src", "lore_ids": ["01ARZ3NDEKTSV4RRFFQ69G5FAV"], "type": "great"}`, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &mockStore{stats: &types.StoreStats{}}
+			handler := newTestHandler(s, &mockEmbedder{}, "api-key", "1.0.0")
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/lore/feedback/bulk", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.BulkFeedback(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+			if s.lastFeedback != nil {
+				t.Error("store should not be called for an invalid request")
+			}
+		})
+	}
+}
+
+func TestBulkFeedback_Routed(t *testing.T) {
+	s := &mockStore{stats: &types.StoreStats{}}
+	router := NewRouter(NewHandler(s, nil, &mockEmbedder{}, nil, "test-key", "1.0.0"), nil)
+
+	body := `{"source_id": "src", "lore_ids": ["01ARZ3NDEKTSV4RRFFQ69G5FAV"], "type": "helpful"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lore/feedback/bulk", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestFeedback_PassesNote(t *testing.T) {
+	s := &mockStore{stats: &types.StoreStats{}}
+	handler := newTestHandler(s, &mockEmbedder{}, "api-key", "1.0.0")
+
+	body := `{
+		"source_id": "devcontainer-abc123",
+		"feedback": [
+			{"lore_id": "01ARZ3NDEKTSV4RRFFQ69G5FAV", "type": "incorrect", "note": "flag was renamed to --max-retries"}
+		]
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lore/feedback", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.Feedback(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if len(s.lastFeedback) != 1 || s.lastFeedback[0].Note != "flag was renamed to --max-retries" {
+		t.Errorf("store received %+v, want the note", s.lastFeedback)
+	}
+}
+
+func TestFeedback_InvalidNote(t *testing.T) {
+	s := &mockStore{stats: &types.StoreStats{}}
+	handler := newTestHandler(s, &mockEmbedder{}, "api-key", "1.0.0")
+
+	body := `{"source_id": "src", "feedback": [{"lore_id": "01ARZ3NDEKTSV4RRFFQ69G5FAV", "type": "incorrect", "note": "` +
+		strings.Repeat("x", validation.MaxFeedbackNoteLength+1) + `"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lore/feedback", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.Feedback(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "feedback[0].note") {
+		t.Errorf("body should name the note field: %s", w.Body.String())
+	}
+}
+
+func serveFeedbackHistory(t *testing.T, s *mockStore, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := NewRouter(NewHandler(s, nil, &mockEmbedder{}, nil, "test-key", "1.0.0"), nil)
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestFeedbackHistory_ListsEvents(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := &mockStore{history: []types.FeedbackEvent{
+		{ID: 2, Type: "incorrect", Note: "port is 5433 now", CreatedAt: at},
+		{ID: 1, Type: "helpful", CreatedAt: at.Add(-time.Hour)},
+	}}
+
+	w := serveFeedbackHistory(t, s, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/feedback?limit=20")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp types.FeedbackHistoryResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.LoreID != "01ARZ3NDEKTSV4RRFFQ69G5FAV" || len(resp.Events) != 2 || resp.Events[0].Note != "port is 5433 now" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if s.lastHistoryLimit != 21 {
+		t.Errorf("limit = %d, want 21 (one more, to detect another page)", s.lastHistoryLimit)
+	}
+	if resp.HasMore || resp.NextCursor != "" {
+		t.Errorf("has_more = %v, next_cursor = %q on the only page", resp.HasMore, resp.NextCursor)
+	}
+}
+
+func TestFeedbackHistory_Pagination(t *testing.T) {
+	s := &mockStore{history: []types.FeedbackEvent{
+		{ID: 9, Type: "incorrect"},
+		{ID: 7, Type: "helpful"},
+	}}
+
+	w := serveFeedbackHistory(t, s, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/feedback?limit=1")
+	var resp types.FeedbackHistoryResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Events) != 1 || !resp.HasMore || resp.NextCursor == "" {
+		t.Fatalf("first page = %+v, want one event and a cursor", resp)
+	}
+
+	serveFeedbackHistory(t, s, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/feedback?limit=1&cursor="+resp.NextCursor)
+	if s.historyBefore != 9 {
+		t.Errorf("before = %d, want 9, the last event of the first page", s.historyBefore)
+	}
+
+	w = serveFeedbackHistory(t, s, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/feedback?cursor=not-a-cursor")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid cursor: status = %d, want 400", w.Code)
+	}
+}
+
+func TestFeedbackHistory_EmptyArray(t *testing.T) {
+	w := serveFeedbackHistory(t, &mockStore{}, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/feedback")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"events":[]`) {
+		t.Errorf("expected empty events array, got %s", w.Body.String())
+	}
+}
+
+func TestFeedbackHistory_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		err  error
+		want int
+	}{
+		{"invalid id", "/api/v1/lore/not-a-ulid/feedback", nil, http.StatusBadRequest},
+		{"zero limit", "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/feedback?limit=0", nil, http.StatusBadRequest},
+		{"large limit", "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/feedback?limit=501", nil, http.StatusBadRequest},
+		{"not found", "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/feedback", store.ErrNotFound, http.StatusNotFound},
+		{"store error", "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/feedback", errors.New("disk I/O error"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveFeedbackHistory(t, &mockStore{historyErr: tt.err}, tt.path)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
 // --- Graceful Shutdown Tests (Epic 5 Retro Action Item) ---
 
 // slowFeedbackStore wraps mockStore with a delay for testing graceful shutdown
@@ -2461,6 +3063,7 @@ func TestDeleteLore_Success(t *testing.T) {
 	handler := newTestHandler(s, embedder, "api-key", "1.0.0")
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV", nil)
+	req.Header.Set("If-Match", "*")
 	req = withChiURLParam(req, "id", "01ARZ3NDEKTSV4RRFFQ69G5FAV")
 	w := httptest.NewRecorder()
 
@@ -2485,6 +3088,7 @@ func TestDeleteLore_NotFound(t *testing.T) {
 	handler := newTestHandler(s, embedder, "api-key", "1.0.0")
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV", nil)
+	req.Header.Set("If-Match", "*")
 	req = withChiURLParam(req, "id", "01ARZ3NDEKTSV4RRFFQ69G5FAV")
 	w := httptest.NewRecorder()
 
@@ -2548,6 +3152,7 @@ func TestDeleteLore_StoreError(t *testing.T) {
 	handler := newTestHandler(s, embedder, "api-key", "1.0.0")
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV", nil)
+	req.Header.Set("If-Match", "*")
 	req = withChiURLParam(req, "id", "01ARZ3NDEKTSV4RRFFQ69G5FAV")
 	w := httptest.NewRecorder()
 
@@ -2592,7 +3197,8 @@ type logEntry struct {
 	Action    string `json:"action"`
 	SourceID  string `json:"source_id"`
 	RemoteAddr string `json:"remote_addr"`
-	BytesServed int64 `json:"bytes_served"`
+	Route      string `json:"route"`
+	Bytes      int64  `json:"bytes"`
 	DurationMs int64 `json:"duration_ms"`
 	Since      string `json:"since"`
 	LoreCount  int    `json:"lore_count"`
@@ -2671,7 +3277,7 @@ func TestExtractSourceID_EmptyHeader(t *testing.T) {
 	}
 }
 
-func TestSnapshot_LogsClientBootstrapWithSourceID(t *testing.T) {
+func TestSnapshot_AccessLogRecordsSourceIDAndBytes(t *testing.T) {
 	testData := []byte("SQLite format 3\x00test snapshot data")
 	reader := io.NopCloser(strings.NewReader(string(testData)))
 
@@ -2681,70 +3287,38 @@ func TestSnapshot_LogsClientBootstrapWithSourceID(t *testing.T) {
 		snapshotErr:    nil,
 	}
 	embedder := &mockEmbedder{model: "text-embedding-3-small"}
-	handler := newTestHandler(s, embedder, "api-key", "1.0.0")
+	router := NewRouter(newTestHandler(s, embedder, "api-key", "1.0.0"), nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/lore/snapshot", nil)
+	req.Header.Set("Authorization", "Bearer api-key")
 	req.Header.Set(HeaderRecallSourceID, "devcontainer-test123")
 	w := httptest.NewRecorder()
 
 	entries := captureLog(t, func() {
-		handler.Snapshot(w, req)
+		router.ServeHTTP(w, req)
 	})
 
 	if w.Code != http.StatusOK {
 		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
 	}
 
-	entry := findLogEntry(entries, "client_bootstrap")
+	var entry *logEntry
+	for i := range entries {
+		if entries[i].Msg == "request completed" {
+			entry = &entries[i]
+		}
+	}
 	if entry == nil {
-		t.Fatalf("expected log with action=client_bootstrap, got entries: %+v", entries)
+		t.Fatalf("expected access log entry, got entries: %+v", entries)
 	}
-
 	if entry.SourceID != "devcontainer-test123" {
 		t.Errorf("source_id = %q, want %q", entry.SourceID, "devcontainer-test123")
 	}
-	if entry.Msg != "recall client bootstrap" {
-		t.Errorf("msg = %q, want %q", entry.Msg, "recall client bootstrap")
-	}
-	if entry.Component != "api" {
-		t.Errorf("component = %q, want %q", entry.Component, "api")
-	}
-	if entry.BytesServed == 0 {
-		t.Error("expected bytes_served > 0")
-	}
-}
-
-func TestSnapshot_LogsUnknownSourceIDWithoutHeader(t *testing.T) {
-	testData := []byte("SQLite format 3\x00test snapshot data")
-	reader := io.NopCloser(strings.NewReader(string(testData)))
-
-	s := &mockStore{
-		stats:          &types.StoreStats{},
-		snapshotReader: reader,
-		snapshotErr:    nil,
-	}
-	embedder := &mockEmbedder{model: "text-embedding-3-small"}
-	handler := newTestHandler(s, embedder, "api-key", "1.0.0")
-
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/lore/snapshot", nil)
-	// No X-Recall-Source-ID header
-	w := httptest.NewRecorder()
-
-	entries := captureLog(t, func() {
-		handler.Snapshot(w, req)
-	})
-
-	if w.Code != http.StatusOK {
-		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	if entry.Route != "/api/v1/lore/snapshot" {
+		t.Errorf("route = %q, want %q", entry.Route, "/api/v1/lore/snapshot")
 	}
-
-	entry := findLogEntry(entries, "client_bootstrap")
-	if entry == nil {
-		t.Fatalf("expected log with action=client_bootstrap, got entries: %+v", entries)
-	}
-
-	if entry.SourceID != "unknown" {
-		t.Errorf("source_id = %q, want %q when header absent", entry.SourceID, "unknown")
+	if entry.Bytes != int64(len(testData)) {
+		t.Errorf("bytes = %d, want %d", entry.Bytes, len(testData))
 	}
 }
 
@@ -2921,17 +3495,19 @@ func TestIngestLore_LogsFailureWithSourceID(t *testing.T) {
 	}
 }
 
-func TestFeedback_PerformanceWarningIncludesSourceID(t *testing.T) {
-	// Create a slow feedback store that takes > 500ms
+func TestFeedback_LatencySLOWarningIncludesSourceID(t *testing.T) {
+	// Create a feedback store slower than the route's SLO
 	baseStore := &mockStore{
 		stats: &types.StoreStats{},
 		feedbackResult: &types.FeedbackResult{
 			Updates: []types.FeedbackResultUpdate{},
 		},
 	}
-	slowStore := &slowFeedbackStore{mockStore: baseStore, delay: 600 * time.Millisecond}
+	slowStore := &slowFeedbackStore{mockStore: baseStore, delay: 20 * time.Millisecond}
 	embedder := &mockEmbedder{model: "text-embedding-3-small"}
-	handler := NewHandler(slowStore, nil, embedder, nil, "api-key", "1.0.0")
+	handler := NewHandler(slowStore, nil, embedder, nil, "api-key", "1.0.0",
+		WithLatencySLOs(map[string]time.Duration{"/api/v1/lore/feedback": 10 * time.Millisecond}))
+	router := NewRouter(handler, nil)
 
 	body := `{
 		"source_id": "devcontainer-slow123",
@@ -2942,31 +3518,35 @@ func TestFeedback_PerformanceWarningIncludesSourceID(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/lore/feedback", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer api-key")
+	req.Header.Set(HeaderRecallSourceID, "devcontainer-slow123")
 	w := httptest.NewRecorder()
 
 	entries := captureLog(t, func() {
-		handler.Feedback(w, req)
+		router.ServeHTTP(w, req)
 	})
 
 	if w.Code != http.StatusOK {
 		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
 	}
 
-	// Find the warning log (it should have action=feedback and be a WARN level)
 	var warningEntry *logEntry
 	for _, e := range entries {
-		if e.Action == "feedback" && e.Level == "WARN" {
+		if e.Action == "latency_slo" && e.Level == "WARN" {
 			warningEntry = &e
 			break
 		}
 	}
 
 	if warningEntry == nil {
-		t.Fatalf("expected WARN log with action=feedback, got entries: %+v", entries)
+		t.Fatalf("expected WARN log with action=latency_slo, got entries: %+v", entries)
 	}
 
 	if warningEntry.SourceID != "devcontainer-slow123" {
-		t.Errorf("performance warning source_id = %q, want %q", warningEntry.SourceID, "devcontainer-slow123")
+		t.Errorf("latency warning source_id = %q, want %q", warningEntry.SourceID, "devcontainer-slow123")
+	}
+	if warningEntry.Route != "/api/v1/lore/feedback" {
+		t.Errorf("latency warning route = %q, want %q", warningEntry.Route, "/api/v1/lore/feedback")
 	}
 }
 
@@ -3048,6 +3628,7 @@ func TestDeleteEndpoint_RoundTrip(t *testing.T) {
 
 	// Make DELETE request
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/lore/"+entryID, nil)
+	req.Header.Set("If-Match", "*")
 	req.Header.Set("Authorization", "Bearer test-api-key")
 	w := httptest.NewRecorder()
 
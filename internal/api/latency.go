@@ -0,0 +1,195 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// DefaultLatencySLOs are the latency targets applied when none are configured.
+var DefaultLatencySLOs = map[string]time.Duration{
+	"/api/v1/lore/feedback":                   500 * time.Millisecond,
+	"/api/v1/stores/{store_id}/lore/feedback": 500 * time.Millisecond,
+}
+
+// latencyBuckets are the histogram bucket upper bounds, each 25% wider than
+// the last, from 0.5ms to about a minute. Percentiles are reported as the
+// bound of the bucket they fall in, so they overstate by at most 25%.
+var latencyBuckets = func() []time.Duration {
+	var bounds []time.Duration
+	for b := 500 * time.Microsecond; b < time.Minute; b = b * 5 / 4 {
+		bounds = append(bounds, b)
+	}
+	return bounds
+}()
+
+type latencyKey struct {
+	method, route string
+}
+
+type latencyHistogram struct {
+	counts   []int64 // one per bucket, plus overflow
+	count    int64
+	max      time.Duration
+	breaches int64
+}
+
+// quantile returns the bucket bound below which a fraction q of samples fall,
+// capped at the largest sample.
+func (h *latencyHistogram) quantile(q float64) time.Duration {
+	target := int64(q*float64(h.count) + 0.5)
+	if target < 1 {
+		target = 1
+	}
+	var seen int64
+	for i, n := range h.counts {
+		seen += n
+		if seen >= target {
+			if i < len(latencyBuckets) {
+				return min(latencyBuckets[i], h.max)
+			}
+			break
+		}
+	}
+	return h.max
+}
+
+// LatencyTracker records request latency per endpoint in an in-process
+// histogram and flags requests slower than the endpoint's SLO. Counters
+// cover the lifetime of the current server process.
+type LatencyTracker struct {
+	slos  map[string]time.Duration
+	since time.Time
+
+	mu     sync.Mutex
+	routes map[latencyKey]*latencyHistogram
+}
+
+// NewLatencyTracker creates a tracker with SLOs keyed by route template
+// (e.g. "/api/v1/lore/feedback"), applying to every method on the route.
+// Routes without an SLO are tracked but never flagged.
+func NewLatencyTracker(slos map[string]time.Duration) *LatencyTracker {
+	return &LatencyTracker{
+		slos:   slos,
+		since:  time.Now().UTC(),
+		routes: make(map[latencyKey]*latencyHistogram),
+	}
+}
+
+// Observe records one request and reports whether it exceeded the route's SLO.
+func (t *LatencyTracker) Observe(method, route string, d time.Duration) (slo time.Duration, exceeded bool) {
+	slo, hasSLO := t.slos[route]
+	exceeded = hasSLO && slo > 0 && d > slo
+
+	bucket := sort.Search(len(latencyBuckets), func(i int) bool { return latencyBuckets[i] >= d })
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := latencyKey{method: method, route: route}
+	h := t.routes[key]
+	if h == nil {
+		h = &latencyHistogram{counts: make([]int64, len(latencyBuckets)+1)}
+		t.routes[key] = h
+	}
+	h.counts[bucket]++
+	h.count++
+	h.max = max(h.max, d)
+	if exceeded {
+		h.breaches++
+	}
+	return slo, exceeded
+}
+
+// Summary returns p50/p95/p99 latency per endpoint, ordered by route.
+func (t *LatencyTracker) Summary() types.LatencyStats {
+	if t == nil {
+		return types.LatencyStats{Routes: []types.RouteLatency{}}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	routes := make([]types.RouteLatency, 0, len(t.routes))
+	for key, h := range t.routes {
+		routes = append(routes, types.RouteLatency{
+			Method:      key.method,
+			Route:       key.route,
+			Count:       h.count,
+			P50Ms:       durationMs(h.quantile(0.50)),
+			P95Ms:       durationMs(h.quantile(0.95)),
+			P99Ms:       durationMs(h.quantile(0.99)),
+			MaxMs:       durationMs(h.max),
+			SLOMs:       durationMs(t.slos[key.route]),
+			SLOBreaches: h.breaches,
+		})
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Route != routes[j].Route {
+			return routes[i].Route < routes[j].Route
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return types.LatencyStats{Since: t.since, Routes: routes}
+}
+
+// Middleware records each request's latency under its route template and
+// logs a warning when it exceeds the route's SLO. Requests that matched no
+// route, such as 404s and auth failures, are not recorded. A nil tracker
+// records nothing.
+func (t *LatencyTracker) Middleware(next http.Handler) http.Handler {
+	if t == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		next.ServeHTTP(wrapped, r)
+
+		rctx := chi.RouteContext(r.Context())
+		if rctx == nil {
+			return
+		}
+		route := rctx.RoutePattern()
+		if route == "" || strings.HasSuffix(route, "*") {
+			return
+		}
+
+		elapsed := time.Since(start)
+		slo, exceeded := t.Observe(r.Method, route, elapsed)
+		if !exceeded {
+			return
+		}
+		attrs := []any{
+			"component", "api",
+			"action", "latency_slo",
+			"request_id", GetRequestID(r.Context()),
+			"method", r.Method,
+			"route", route,
+			"status", wrapped.statusCode,
+			"duration_ms", elapsed.Milliseconds(),
+			"slo_ms", slo.Milliseconds(),
+		}
+		if sourceID := r.Header.Get(HeaderRecallSourceID); sourceID != "" {
+			attrs = append(attrs, "source_id", sourceID)
+		}
+		slog.Warn("request exceeded latency SLO", attrs...)
+	})
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// LatencyStats handles GET /api/v1/stats/latency
+// Returns per-endpoint latency percentiles and SLO breaches since startup.
+func (h *Handler) LatencyStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.latency.Summary())
+}
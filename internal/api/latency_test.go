@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestLatencyTracker_Percentiles(t *testing.T) {
+	tracker := NewLatencyTracker(nil)
+	for i := 1; i <= 100; i++ {
+		tracker.Observe(http.MethodGet, "/items", time.Duration(i)*time.Millisecond)
+	}
+
+	summary := tracker.Summary()
+	if len(summary.Routes) != 1 {
+		t.Fatalf("routes = %+v, want 1", summary.Routes)
+	}
+	got := summary.Routes[0]
+	if got.Count != 100 || got.MaxMs != 100 {
+		t.Errorf("count = %d, max = %v; want 100, 100", got.Count, got.MaxMs)
+	}
+	// Buckets are 25% wide, so percentiles may overstate by up to that much.
+	for name, tt := range map[string]struct{ got, want float64 }{
+		"p50": {got.P50Ms, 50},
+		"p95": {got.P95Ms, 95},
+		"p99": {got.P99Ms, 99},
+	} {
+		if tt.got < tt.want || tt.got > tt.want*1.25 {
+			t.Errorf("%s = %v, want within 25%% above %v", name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestLatencyTracker_SLOBreaches(t *testing.T) {
+	tracker := NewLatencyTracker(map[string]time.Duration{"/slow": 100 * time.Millisecond})
+
+	if _, exceeded := tracker.Observe(http.MethodPost, "/slow", 50*time.Millisecond); exceeded {
+		t.Error("50ms should be within the 100ms SLO")
+	}
+	slo, exceeded := tracker.Observe(http.MethodPost, "/slow", 150*time.Millisecond)
+	if !exceeded || slo != 100*time.Millisecond {
+		t.Errorf("Observe(150ms) = %v, %v; want 100ms, true", slo, exceeded)
+	}
+	if _, exceeded := tracker.Observe(http.MethodPost, "/other", time.Minute); exceeded {
+		t.Error("routes without an SLO are never flagged")
+	}
+
+	for _, route := range tracker.Summary().Routes {
+		if route.Route == "/slow" && (route.SLOBreaches != 1 || route.SLOMs != 100) {
+			t.Errorf("slow route = %+v, want 1 breach of a 100ms SLO", route)
+		}
+	}
+}
+
+func TestLatencyTracker_OverflowReportsMax(t *testing.T) {
+	tracker := NewLatencyTracker(nil)
+	tracker.Observe(http.MethodGet, "/items", 2*time.Minute)
+
+	if got := tracker.Summary().Routes[0].P99Ms; got != 120000 {
+		t.Errorf("p99 = %v, want the 2m maximum", got)
+	}
+}
+
+func TestLatencyStats_Endpoint(t *testing.T) {
+	router := NewRouter(NewHandler(&mockStore{stats: &types.StoreStats{}}, nil, &mockEmbedder{}, nil, "test-key", "1.0.0"), nil)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/nope", nil))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/stats/latency", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var stats types.LatencyStats
+	if err := json.NewDecoder(w.Body).Decode(&stats); err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Routes) != 1 {
+		t.Fatalf("routes = %+v, want only the health check", stats.Routes)
+	}
+	if r := stats.Routes[0]; r.Method != http.MethodGet || r.Route != "/api/v1/health" || r.Count != 1 {
+		t.Errorf("route = %+v", r)
+	}
+}
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// LockoutConfig sets when a client address that keeps failing
+// authentication is banned. A zero MaxFailures bans nothing.
+type LockoutConfig struct {
+	// MaxFailures bans an address once it has failed authentication this
+	// many times within Window.
+	MaxFailures int
+	Window      time.Duration
+	// Duration is how long a ban lasts.
+	Duration time.Duration
+}
+
+// AuthLockout bans client addresses that keep failing authentication,
+// refusing their requests with 429 and Retry-After before the key is even
+// checked, so a misconfigured client fleet or a scanner backs off rather
+// than retrying forever. It also aggregates auth failure logging to one
+// line per address per Window, carrying the count since the last one.
+//
+// Failures are counted per address only. The source IDs a failing client
+// claims are unauthenticated, so they are logged but never banned, or
+// anyone could lock a source out by sending bad keys in its name. The
+// address is the connection's peer unless RealIPMiddleware took it from a
+// trusted proxy.
+type AuthLockout struct {
+	cfg LockoutConfig
+
+	mu       sync.Mutex
+	clients  map[string]*authClient
+	prunedAt time.Time
+}
+
+// authClient tracks the failures of one client address.
+type authClient struct {
+	failures    []time.Time // Within Window, oldest first
+	bannedUntil time.Time
+
+	loggedAt  time.Time
+	unlogged  int // Failures since the last log line
+	refused   int // Requests refused by the ban since the last log line
+	sourceIDs map[string]struct{}
+}
+
+// NewAuthLockout creates an AuthLockout. It returns nil, which locks
+// nothing out, if cfg.MaxFailures or cfg.Duration is not positive.
+func NewAuthLockout(cfg LockoutConfig) *AuthLockout {
+	if cfg.MaxFailures <= 0 || cfg.Duration <= 0 {
+		return nil
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = time.Minute
+	}
+	return &AuthLockout{cfg: cfg, clients: make(map[string]*authClient)}
+}
+
+// WithAuthLockout bans addresses that keep failing authentication. Nil
+// bans nothing.
+func WithAuthLockout(l *AuthLockout) HandlerOption {
+	return func(h *Handler) {
+		h.lockout = l
+	}
+}
+
+type lockoutKey struct{}
+
+// Middleware refuses requests from banned addresses and lets the auth
+// middleware after it report failures through recordAuthFailure. A nil
+// AuthLockout refuses nothing.
+func (l *AuthLockout) Middleware(next http.Handler) http.Handler {
+	if l == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addr := clientAddr(r)
+		if remaining := l.banned(addr, r); remaining > 0 {
+			secs := int((remaining + time.Second - 1) / time.Second)
+			w.Header().Set("Retry-After", strconv.Itoa(secs))
+			WriteProblem(w, r, http.StatusTooManyRequests,
+				"Too many failed authentication attempts. Please retry after the indicated interval.")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), lockoutKey{}, l)))
+	})
+}
+
+// banned returns how long addr remains banned, counting r as refused if
+// it is.
+func (l *AuthLockout) banned(addr string, r *http.Request) time.Duration {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c := l.clients[addr]
+	if c == nil || !now.Before(c.bannedUntil) {
+		return 0
+	}
+	c.refused++
+	c.noteSource(r)
+	l.logThrottled(addr, c, now)
+	return c.bannedUntil.Sub(now)
+}
+
+// fail records a failed authentication from r's address, banning it once
+// it reaches MaxFailures within Window.
+func (l *AuthLockout) fail(r *http.Request) {
+	addr := clientAddr(r)
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prune(now)
+
+	c := l.clients[addr]
+	if c == nil {
+		c = &authClient{}
+		l.clients[addr] = c
+	}
+	cutoff := now.Add(-l.cfg.Window)
+	i := 0
+	for i < len(c.failures) && c.failures[i].Before(cutoff) {
+		i++
+	}
+	c.failures = append(c.failures[i:], now)
+	c.unlogged++
+	c.noteSource(r)
+
+	if len(c.failures) < l.cfg.MaxFailures {
+		l.logThrottled(addr, c, now)
+		return
+	}
+	c.bannedUntil = now.Add(l.cfg.Duration)
+	c.failures = nil
+	slog.Warn("auth lockout",
+		"component", "api",
+		"action", "auth_lockout",
+		"remote_ip", addr,
+		"failures", l.cfg.MaxFailures,
+		"window", l.cfg.Window.String(),
+		"duration", l.cfg.Duration.String(),
+		"source_ids", c.sources(),
+	)
+	c.loggedAt, c.unlogged, c.refused, c.sourceIDs = now, 0, 0, nil
+}
+
+// logThrottled logs the failures and refusals counted for c, unless a line
+// for it was logged within Window. Callers hold mu.
+func (l *AuthLockout) logThrottled(addr string, c *authClient, now time.Time) {
+	if now.Sub(c.loggedAt) < l.cfg.Window {
+		return
+	}
+	slog.Warn("auth failures",
+		"component", "api",
+		"action", "auth_failure",
+		"remote_ip", addr,
+		"failures", c.unlogged,
+		"refused", c.refused,
+		"banned", now.Before(c.bannedUntil),
+		"source_ids", c.sources(),
+	)
+	c.loggedAt, c.unlogged, c.refused, c.sourceIDs = now, 0, 0, nil
+}
+
+// prune forgets addresses with no recent failures and no ban, at most once
+// per Window. Counts not yet logged are dropped with them. Callers hold mu.
+func (l *AuthLockout) prune(now time.Time) {
+	if now.Sub(l.prunedAt) < l.cfg.Window {
+		return
+	}
+	l.prunedAt = now
+	cutoff := now.Add(-l.cfg.Window)
+	for addr, c := range l.clients {
+		if now.Before(c.bannedUntil) {
+			continue
+		}
+		if n := len(c.failures); n == 0 || c.failures[n-1].Before(cutoff) {
+			delete(l.clients, addr)
+		}
+	}
+}
+
+// maxLoggedSources caps the source IDs an auth failure line lists, as a
+// scanner may send a different one every time.
+const maxLoggedSources = 5
+
+// noteSource records the source ID r claims, if any, for the next log line.
+func (c *authClient) noteSource(r *http.Request) {
+	id := r.Header.Get(HeaderRecallSourceID)
+	if id == "" || len(c.sourceIDs) >= maxLoggedSources {
+		return
+	}
+	if c.sourceIDs == nil {
+		c.sourceIDs = make(map[string]struct{})
+	}
+	c.sourceIDs[id] = struct{}{}
+}
+
+func (c *authClient) sources() []string {
+	ids := make([]string, 0, len(c.sourceIDs))
+	for id := range c.sourceIDs {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// recordAuthFailure reports a failed authentication to the request's
+// AuthLockout, returning false if there is none to log it.
+func recordAuthFailure(r *http.Request) bool {
+	l, ok := r.Context().Value(lockoutKey{}).(*AuthLockout)
+	if !ok {
+		return false
+	}
+	l.fail(r)
+	return true
+}
+
+// clientAddr returns the IP address of r's client, without the port. It is
+// the connection's peer, or the address a trusted proxy forwarded.
+func clientAddr(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAuthLockout_BansRepeatedFailures(t *testing.T) {
+	lockout := NewAuthLockout(LockoutConfig{MaxFailures: 3, Window: time.Minute, Duration: time.Minute})
+	handler, _ := mockHandler()
+	mw := lockout.Middleware(AuthMiddleware(testAPIKey)(handler))
+
+	do := func(remoteAddr, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/lore", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		mw.ServeHTTP(w, req)
+		return w
+	}
+
+	// Failures count per address, whatever the port
+	for i, addr := range []string{"10.0.0.1:1000", "10.0.0.1:1001", "10.0.0.1:1002"} {
+		if w := do(addr, "wrong"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("failure %d: status = %d, want %d", i+1, w.Code, http.StatusUnauthorized)
+		}
+	}
+
+	w := do("10.0.0.1:1003", testAPIKey)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("banned address with valid key: status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if secs, _ := strconv.Atoi(w.Header().Get("Retry-After")); secs < 1 || secs > 60 {
+		t.Errorf("Retry-After = %q, want the rest of the 60s ban", w.Header().Get("Retry-After"))
+	}
+
+	if w := do("10.0.0.2:1000", testAPIKey); w.Code != http.StatusOK {
+		t.Errorf("other address: status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestAuthLockout_BanExpires(t *testing.T) {
+	lockout := NewAuthLockout(LockoutConfig{MaxFailures: 1, Window: time.Minute, Duration: 50 * time.Millisecond})
+	handler, _ := mockHandler()
+	mw := lockout.Middleware(AuthMiddleware(testAPIKey)(handler))
+
+	do := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/lore", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		mw.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if got := do("wrong"); got != http.StatusUnauthorized {
+		t.Fatalf("failure: status = %d, want %d", got, http.StatusUnauthorized)
+	}
+	if got := do(testAPIKey); got != http.StatusTooManyRequests {
+		t.Fatalf("while banned: status = %d, want %d", got, http.StatusTooManyRequests)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if got := do(testAPIKey); got != http.StatusOK {
+		t.Errorf("after ban: status = %d, want %d", got, http.StatusOK)
+	}
+}
+
+func TestAuthLockout_FailuresOutsideWindow(t *testing.T) {
+	lockout := NewAuthLockout(LockoutConfig{MaxFailures: 2, Window: 30 * time.Millisecond, Duration: time.Minute})
+	handler, _ := mockHandler()
+	mw := lockout.Middleware(AuthMiddleware(testAPIKey)(handler))
+
+	do := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/lore", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		mw.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	do("wrong")
+	time.Sleep(40 * time.Millisecond)
+	do("wrong")
+	if got := do(testAPIKey); got != http.StatusOK {
+		t.Errorf("failures spread beyond the window: status = %d, want %d", got, http.StatusOK)
+	}
+}
+
+func TestNewAuthLockout_Disabled(t *testing.T) {
+	if l := NewAuthLockout(LockoutConfig{Window: time.Minute, Duration: time.Minute}); l != nil {
+		t.Error("NewAuthLockout() with zero MaxFailures != nil")
+	}
+	handler, called := mockHandler()
+	var l *AuthLockout
+	l.Middleware(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if !*called {
+		t.Error("nil lockout did not pass the request on")
+	}
+}
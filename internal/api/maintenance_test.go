@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestMaintenance_API(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+	ctx := context.Background()
+	for _, id := range []string{"team-a", "team-b"} {
+		if _, err := manager.CreateStore(ctx, id, "", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &mockStore{stats: &types.StoreStats{}}
+	embedder := &mockEmbedder{model: "test-model"}
+	handler := NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0", WithAdminKey("admin-key"))
+	router := NewRouter(handler, manager)
+
+	do := func(method, path, key, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+key)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	// A write that fails validation: anything but 503 means it got past
+	// the maintenance guard
+	write := func(storeID string) *httptest.ResponseRecorder {
+		t.Helper()
+		return do(http.MethodPost, "/api/v1/stores/"+storeID+"/lore/feedback", "test-api-key", `{}`)
+	}
+
+	w := do(http.MethodPut, "/api/v1/admin/stores/team-a/maintenance", "admin-key", `{"reason":"Backup in progress.","retry_after":"2m"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("enable store maintenance: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = write("team-a")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("write during store maintenance: expected 503, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Retry-After"); got != "120" {
+		t.Errorf("Retry-After = %q, want 120", got)
+	}
+	if !strings.Contains(w.Body.String(), "Backup in progress.") {
+		t.Errorf("detail doesn't include the reason: %s", w.Body.String())
+	}
+	if w := write("team-b"); w.Code == http.StatusServiceUnavailable {
+		t.Errorf("write to another store refused: %s", w.Body.String())
+	}
+	if w := do(http.MethodGet, "/api/v1/stores/team-a/sync/delta?after=0", "test-api-key", ""); w.Code != http.StatusOK {
+		t.Errorf("read during maintenance: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Server-wide maintenance covers every store
+	if w := do(http.MethodPut, "/api/v1/admin/maintenance", "admin-key", ""); w.Code != http.StatusOK {
+		t.Fatalf("enable server maintenance: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	w = write("team-b")
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "300" {
+		t.Errorf("write during server maintenance = %d with Retry-After %q, want 503 with 300", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	w = do(http.MethodGet, "/api/v1/admin/maintenance", "admin-key", "")
+	var status MaintenanceStatus
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if status.Server == nil || len(status.Stores) != 1 || status.Stores[0].StoreID != "team-a" {
+		t.Errorf("status = %+v", status)
+	}
+
+	for _, path := range []string{"/api/v1/admin/maintenance", "/api/v1/admin/stores/team-a/maintenance"} {
+		if w := do(http.MethodDelete, path, "admin-key", ""); w.Code != http.StatusNoContent {
+			t.Errorf("DELETE %s: expected 204, got %d", path, w.Code)
+		}
+		if w := do(http.MethodDelete, path, "admin-key", ""); w.Code != http.StatusNotFound {
+			t.Errorf("DELETE %s again: expected 404, got %d", path, w.Code)
+		}
+	}
+	if w := write("team-a"); w.Code == http.StatusServiceUnavailable {
+		t.Errorf("write after maintenance cleared refused: %s", w.Body.String())
+	}
+
+	if w := do(http.MethodPut, "/api/v1/admin/maintenance", "admin-key", `{"retry_after":"soon"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid retry_after: expected 400, got %d", w.Code)
+	}
+	if w := do(http.MethodPut, "/api/v1/admin/maintenance", "test-api-key", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("client key: expected 401, got %d", w.Code)
+	}
+}
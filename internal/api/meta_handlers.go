@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/validation"
+)
+
+// ClientMetaStore is implemented by stores that keep small values for
+// clients alongside their sync metadata. Implemented by SQLiteStore.
+type ClientMetaStore interface {
+	GetClientMeta(ctx context.Context, key string) (string, error)
+	PutClientMeta(ctx context.Context, key, value string) error
+	DeleteClientMeta(ctx context.Context, key string) error
+}
+
+// putMetaRequest is the body of PUT /stores/{store_id}/meta/{key}.
+type putMetaRequest struct {
+	Value *string `json:"value"`
+}
+
+// clientMetaStore returns the request's store as a ClientMetaStore with
+// the key from the path, writing a problem and returning false when the
+// key is invalid or the store cannot keep client metadata.
+func (h *Handler) clientMetaStore(w http.ResponseWriter, r *http.Request) (ClientMetaStore, string, bool) {
+	key := chi.URLParam(r, "key")
+	if verr := validation.ValidateMetaKey("key", key); verr != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid key: %s", verr.Message))
+		return nil, "", false
+	}
+	meta, ok := h.getStoreForRequest(r).(ClientMetaStore)
+	if !ok {
+		WriteProblem(w, r, http.StatusNotImplemented, "Store does not support metadata")
+		return nil, "", false
+	}
+	return meta, key, true
+}
+
+// GetStoreMeta handles GET /api/v1/stores/{store_id}/meta/{key}
+//
+// Returns a value a client stored under key with PutStoreMeta.
+func (h *Handler) GetStoreMeta(w http.ResponseWriter, r *http.Request) {
+	meta, key, ok := h.clientMetaStore(w, r)
+	if !ok {
+		return
+	}
+
+	value, err := meta.GetClientMeta(r.Context(), key)
+	if err != nil {
+		MapStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(types.StoreMetaEntry{Key: key, Value: value})
+}
+
+// PutStoreMeta handles PUT /api/v1/stores/{store_id}/meta/{key}
+//
+// Stores a small value under key, replacing any value there, so clients
+// can share coordination values such as fleet configuration through the
+// server. Keys live apart from the server's own sync metadata, which
+// clients can neither read nor overwrite here. A store holds at most 100
+// keys of up to 16 KiB each.
+func (h *Handler) PutStoreMeta(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	meta, key, ok := h.clientMetaStore(w, r)
+	if !ok {
+		return
+	}
+
+	var req putMetaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err.Error()))
+		return
+	}
+	if req.Value == nil {
+		WriteProblemWithErrors(w, r, "Request contains invalid fields",
+			[]validation.ValidationError{{Field: "value", Message: "is required"}})
+		return
+	}
+	if errs := validation.ValidateMetaValue("value", *req.Value); len(errs) > 0 {
+		WriteProblemWithErrors(w, r, "Request contains invalid fields", errs)
+		return
+	}
+
+	if err := meta.PutClientMeta(ctx, key, *req.Value); err != nil {
+		slog.Error("store meta update failed",
+			"component", "api",
+			"action", "put_store_meta_failed",
+			"store_id", StoreIDFromContext(ctx),
+			"key", key,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	slog.Info("store meta updated via API",
+		"component", "api",
+		"action", "put_store_meta",
+		"store_id", StoreIDFromContext(ctx),
+		"key", key,
+		"bytes", len(*req.Value),
+		"request_id", GetRequestID(ctx),
+		"remote_addr", r.RemoteAddr,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(types.StoreMetaEntry{Key: key, Value: *req.Value})
+}
+
+// DeleteStoreMeta handles DELETE /api/v1/stores/{store_id}/meta/{key}
+func (h *Handler) DeleteStoreMeta(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	meta, key, ok := h.clientMetaStore(w, r)
+	if !ok {
+		return
+	}
+
+	if err := meta.DeleteClientMeta(ctx, key); err != nil {
+		MapStoreError(w, r, err)
+		return
+	}
+
+	slog.Info("store meta deleted via API",
+		"component", "api",
+		"action", "delete_store_meta",
+		"store_id", StoreIDFromContext(ctx),
+		"key", key,
+		"request_id", GetRequestID(ctx),
+		"remote_addr", r.RemoteAddr,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
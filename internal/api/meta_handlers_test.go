@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestStoreMeta_API(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+
+	s := &mockStore{stats: &types.StoreStats{}}
+	embedder := &mockEmbedder{model: "test-model"}
+	handler := NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0")
+	router := NewRouter(handler, manager)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do(http.MethodGet, "/api/v1/stores/default/meta/fleet.config", ""); w.Code != http.StatusNotFound {
+		t.Errorf("get before put: expected status 404, got %d", w.Code)
+	}
+
+	w := do(http.MethodPut, "/api/v1/stores/default/meta/fleet.config", `{"value": "{\"sync_interval\":\"5m\"}"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("put: expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = do(http.MethodGet, "/api/v1/stores/default/meta/fleet.config", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("get: expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var entry types.StoreMetaEntry
+	if err := json.NewDecoder(w.Body).Decode(&entry); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if entry.Key != "fleet.config" || entry.Value != `{"sync_interval":"5m"}` {
+		t.Errorf("entry = %+v", entry)
+	}
+
+	// Server sync metadata is out of reach
+	if w := do(http.MethodGet, "/api/v1/stores/default/meta/schema_version", ""); w.Code != http.StatusNotFound {
+		t.Errorf("get schema_version: expected status 404, got %d", w.Code)
+	}
+
+	if w := do(http.MethodDelete, "/api/v1/stores/default/meta/fleet.config", ""); w.Code != http.StatusNoContent {
+		t.Errorf("delete: expected status 204, got %d", w.Code)
+	}
+	if w := do(http.MethodDelete, "/api/v1/stores/default/meta/fleet.config", ""); w.Code != http.StatusNotFound {
+		t.Errorf("delete twice: expected status 404, got %d", w.Code)
+	}
+}
+
+func TestStoreMeta_Invalid(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+
+	s := &mockStore{stats: &types.StoreStats{}}
+	embedder := &mockEmbedder{model: "test-model"}
+	handler := NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0")
+	router := NewRouter(handler, manager)
+
+	tests := []struct {
+		name, method, key, body string
+		want                    int
+	}{
+		{"uppercase key", http.MethodGet, "Fleet", "", http.StatusBadRequest},
+		{"leading dot", http.MethodPut, ".hidden", `{"value": "x"}`, http.StatusBadRequest},
+		{"missing value", http.MethodPut, "fleet", `{}`, http.StatusUnprocessableEntity},
+		{"value too large", http.MethodPut, "fleet", `{"value": "` + strings.Repeat("x", 16<<10+1) + `"}`, http.StatusUnprocessableEntity},
+		{"bad JSON", http.MethodPut, "fleet", `{`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/stores/default/meta/"+tt.key, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer test-api-key")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -44,6 +44,7 @@ import (
 	"crypto/subtle"
 	"errors"
 	"log/slog"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
 	"runtime/debug"
@@ -105,15 +106,23 @@ func constantTimeEqual(a, b string) bool {
 // Returns 401 RFC 7807 Problem Details on auth failure.
 // MUST NOT include expected API key in logs or responses.
 func AuthMiddleware(apiKey string) func(http.Handler) http.Handler {
+	return AuthMiddlewareFunc(func() string { return apiKey })
+}
+
+// AuthMiddlewareFunc is AuthMiddleware with the key looked up on every
+// request, so a rotated key takes effect without restarting.
+func AuthMiddlewareFunc(apiKey func() string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			token := extractBearerToken(r)
-			if !constantTimeEqual(token, apiKey) {
-				slog.Warn("auth failure",
-					"path", r.URL.Path,
-					"method", r.Method,
-					"remote_ip", r.RemoteAddr,
-				)
+			if !constantTimeEqual(token, apiKey()) {
+				if !recordAuthFailure(r) {
+					slog.Warn("auth failure",
+						"path", r.URL.Path,
+						"method", r.Method,
+						"remote_ip", r.RemoteAddr,
+					)
+				}
 				WriteProblem(w, r, http.StatusUnauthorized, "Missing or invalid API key")
 				return
 			}
@@ -122,32 +131,67 @@ func AuthMiddleware(apiKey string) func(http.Handler) http.Handler {
 	}
 }
 
-// LoggingMiddleware logs HTTP requests with structured fields.
+// LoggingMiddleware logs every HTTP request with structured fields.
 // Emits log at INFO for 2xx/3xx, WARN for 4xx, ERROR for 5xx.
 func LoggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-
-		// Wrap response writer to capture status code
-		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
-
-		next.ServeHTTP(wrapped, r)
-
-		level := logLevelForStatus(wrapped.statusCode)
-		slog.Log(r.Context(), level, "request completed",
-			"request_id", GetRequestID(r.Context()),
-			"method", r.Method,
-			"path", r.URL.Path,
-			"status", wrapped.statusCode,
-			"duration_ms", time.Since(start).Milliseconds(),
-			"remote_addr", r.RemoteAddr,
-		)
-	})
+	return AccessLogMiddleware(nil)(next)
+}
+
+// AccessLogMiddleware emits one "request completed" line per HTTP request
+// with the method, route template, status, latency, response bytes and, when
+// the client sends X-Recall-Source-ID, its source ID.
+//
+// sampleRates maps route templates (e.g. "/api/v1/stores/{store_id}/sync/delta")
+// to the fraction of successful requests logged, so high-volume polling
+// endpoints don't drown the log. Routes not listed are always logged, as are
+// 4xx and 5xx responses whatever the rate.
+func AccessLogMiddleware(sampleRates map[string]float64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			// Wrap response writer to capture status code and size
+			wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+			next.ServeHTTP(wrapped, r)
+
+			// The route template is only known once chi has matched it.
+			route := ""
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				route = rctx.RoutePattern()
+			}
+
+			attrs := []any{
+				"request_id", GetRequestID(r.Context()),
+				"method", r.Method,
+				"path", r.URL.Path,
+				"route", route,
+				"status", wrapped.statusCode,
+				"duration_ms", time.Since(start).Milliseconds(),
+				"bytes", wrapped.bytes,
+				"remote_addr", r.RemoteAddr,
+			}
+			if sourceID := r.Header.Get(HeaderRecallSourceID); sourceID != "" {
+				attrs = append(attrs, "source_id", sourceID)
+			}
+			if rate, ok := sampleRates[route]; ok && wrapped.statusCode < 400 {
+				if rate <= 0 || (rate < 1 && rand.Float64() >= rate) {
+					return
+				}
+				if rate < 1 {
+					attrs = append(attrs, "sample_rate", rate)
+				}
+			}
+
+			slog.Log(r.Context(), logLevelForStatus(wrapped.statusCode), "request completed", attrs...)
+		})
+	}
 }
 
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
+	bytes      int64
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
@@ -155,6 +199,12 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytes += int64(n)
+	return n, err
+}
+
 // RecoveryMiddleware catches panics and returns 500 Problem Details.
 // Panic details are logged but never exposed to the client.
 func RecoveryMiddleware(next http.Handler) http.Handler {
@@ -176,14 +226,16 @@ func RecoveryMiddleware(next http.Handler) http.Handler {
 
 // StoreGetter provides an interface for retrieving stores.
 // This allows the middleware to work with both the real StoreManager
-// and test mocks.
+// and test mocks. AcquireStore keeps the store open until Release, so the
+// manager cannot evict it while a request is using it.
 type StoreGetter interface {
-	GetStore(ctx context.Context, id string) (*multistore.ManagedStore, error)
+	AcquireStore(ctx context.Context, id string) (*multistore.ManagedStore, error)
 }
 
 // StoreContextMiddleware creates middleware that resolves store from URL path.
 // Injects the resolved store into the request context.
-// Returns 404 if store doesn't exist, 400 if store ID is invalid.
+// Returns 404 if store doesn't exist, 400 if store ID is invalid, 423 if the
+// store is archived and 410 if it is being deleted.
 func StoreContextMiddleware(mgr StoreGetter) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -202,19 +254,29 @@ func StoreContextMiddleware(mgr StoreGetter) func(http.Handler) http.Handler {
 				return
 			}
 
-			// Get store from manager
-			managed, err := mgr.GetStore(r.Context(), decodedID)
+			// Get store from manager, held open for the request
+			managed, err := mgr.AcquireStore(r.Context(), decodedID)
 			if err != nil {
 				if errors.Is(err, multistore.ErrStoreNotFound) {
 					WriteProblem(w, r, http.StatusNotFound, "Store not found")
 					return
 				}
+				if errors.Is(err, multistore.ErrStoreArchived) {
+					WriteProblemStoreArchived(w, r, decodedID)
+					return
+				}
+				if errors.Is(err, multistore.ErrStoreDeleting) {
+					WriteProblem(w, r, http.StatusGone, "Store is being deleted")
+					return
+				}
 				slog.Error("store context middleware error",
 					"store_id", decodedID, "error", err)
 				WriteProblem(w, r, http.StatusInternalServerError, "Internal error")
 				return
 			}
 
+			defer managed.Release()
+
 			// Inject store, store ID, and scoped flag into context
 			ctx := WithStore(r.Context(), managed.Store)
 			ctx = WithStoreID(ctx, decodedID)
@@ -230,7 +292,7 @@ func StoreContextMiddleware(mgr StoreGetter) func(http.Handler) http.Handler {
 func DefaultStoreMiddleware(mgr StoreGetter) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			managed, err := mgr.GetStore(r.Context(), multistore.DefaultStoreID)
+			managed, err := mgr.AcquireStore(r.Context(), multistore.DefaultStoreID)
 			if err != nil {
 				slog.Error("default store middleware error",
 					"component", "api",
@@ -240,6 +302,7 @@ func DefaultStoreMiddleware(mgr StoreGetter) func(http.Handler) http.Handler {
 					"Internal error: default store unavailable")
 				return
 			}
+			defer managed.Release()
 
 			ctx := WithStore(r.Context(), managed.Store)
 			ctx = WithStoreID(ctx, multistore.DefaultStoreID)
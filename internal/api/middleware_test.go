@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -192,6 +193,31 @@ func TestAuthMiddleware_NoKeyLeak(t *testing.T) {
 	}
 }
 
+func TestAuthMiddlewareFunc_RotatedKey(t *testing.T) {
+	key := "old-key"
+	handler, _ := mockHandler()
+	middleware := AuthMiddlewareFunc(func() string { return key })(handler)
+
+	status := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/lore", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		middleware.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if got := status("old-key"); got != http.StatusOK {
+		t.Errorf("old key before rotation: status = %d, want %d", got, http.StatusOK)
+	}
+	key = "new-key"
+	if got := status("old-key"); got != http.StatusUnauthorized {
+		t.Errorf("old key after rotation: status = %d, want %d", got, http.StatusUnauthorized)
+	}
+	if got := status("new-key"); got != http.StatusOK {
+		t.Errorf("new key after rotation: status = %d, want %d", got, http.StatusOK)
+	}
+}
+
 func TestExtractBearerToken(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -353,6 +379,40 @@ func TestAuthMiddleware_HealthBypass_ViaRoutes(t *testing.T) {
 	}
 }
 
+func TestRouter_PprofRequiresAdminKey(t *testing.T) {
+	router := NewRouter(NewHandler(&mockStore{}, nil, &mockEmbedder{}, nil, testAPIKey, "1.0.0",
+		WithPprof("admin-key")), nil)
+
+	for key, want := range map[string]int{
+		"":          http.StatusUnauthorized,
+		testAPIKey:  http.StatusUnauthorized,
+		"admin-key": http.StatusOK,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/debug/pprof/goroutine?debug=1", nil)
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("key %q: status = %d, want %d", key, w.Code, want)
+		}
+	}
+}
+
+func TestRouter_PprofOffWithoutAdminKey(t *testing.T) {
+	router := NewRouter(NewHandler(&mockStore{}, nil, &mockEmbedder{}, nil, testAPIKey, "1.0.0"), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+	req.Header.Set("Authorization", "Bearer "+testAPIKey)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
 // --- RecoveryMiddleware Tests ---
 
 func TestRecoveryMiddleware_NoPanic(t *testing.T) {
@@ -716,6 +776,111 @@ func TestLoggingMiddleware_MessageChanged(t *testing.T) {
 	}
 }
 
+// serveAccessLog sends one request per status through AccessLogMiddleware on
+// the /items/{id} route and returns the decoded log lines.
+func serveAccessLog(t *testing.T, rates map[string]float64, sourceID string, statuses ...int) []map[string]any {
+	t.Helper()
+	var logBuf bytes.Buffer
+	oldLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, nil)))
+	defer slog.SetDefault(oldLogger)
+
+	router := chi.NewRouter()
+	router.Use(AccessLogMiddleware(rates))
+	router.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		status, _ := strconv.Atoi(r.URL.Query().Get("status"))
+		w.WriteHeader(status)
+		w.Write([]byte("hello"))
+	})
+
+	for _, status := range statuses {
+		req := httptest.NewRequest(http.MethodGet, "/items/42?status="+strconv.Itoa(status), nil)
+		if sourceID != "" {
+			req.Header.Set(HeaderRecallSourceID, sourceID)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	var lines []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(logBuf.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("parse log line %s: %v", line, err)
+		}
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestAccessLogMiddleware_Fields(t *testing.T) {
+	lines := serveAccessLog(t, nil, "devcontainer-abc", http.StatusOK)
+
+	if len(lines) != 1 {
+		t.Fatalf("got %d log lines, want 1", len(lines))
+	}
+	entry := lines[0]
+	if entry["route"] != "/items/{id}" {
+		t.Errorf("route = %v, want the route template", entry["route"])
+	}
+	if entry["path"] != "/items/42" {
+		t.Errorf("path = %v, want /items/42", entry["path"])
+	}
+	if entry["bytes"] != float64(5) {
+		t.Errorf("bytes = %v, want 5", entry["bytes"])
+	}
+	if entry["source_id"] != "devcontainer-abc" {
+		t.Errorf("source_id = %v, want devcontainer-abc", entry["source_id"])
+	}
+	if _, ok := entry["sample_rate"]; ok {
+		t.Error("sample_rate should be omitted for unsampled routes")
+	}
+}
+
+func TestAccessLogMiddleware_OmitsMissingSourceID(t *testing.T) {
+	lines := serveAccessLog(t, nil, "", http.StatusOK)
+
+	if len(lines) != 1 {
+		t.Fatalf("got %d log lines, want 1", len(lines))
+	}
+	if _, ok := lines[0]["source_id"]; ok {
+		t.Errorf("source_id = %v, want it omitted without the header", lines[0]["source_id"])
+	}
+}
+
+func TestAccessLogMiddleware_SamplingSkipsSuccessesOnly(t *testing.T) {
+	lines := serveAccessLog(t, map[string]float64{"/items/{id}": 0}, "",
+		http.StatusOK, http.StatusNotModified, http.StatusNotFound, http.StatusInternalServerError)
+
+	if len(lines) != 2 {
+		t.Fatalf("got %d log lines, want only the 404 and 500", len(lines))
+	}
+	if lines[0]["status"] != float64(404) || lines[1]["status"] != float64(500) {
+		t.Errorf("statuses = %v, %v; want 404, 500", lines[0]["status"], lines[1]["status"])
+	}
+}
+
+func TestAccessLogMiddleware_SampledLineRecordsRate(t *testing.T) {
+	lines := serveAccessLog(t, map[string]float64{"/items/{id}": 0.999999}, "", http.StatusOK, http.StatusOK, http.StatusOK)
+
+	if len(lines) == 0 {
+		t.Fatal("expected sampled lines")
+	}
+	if lines[0]["sample_rate"] != 0.999999 {
+		t.Errorf("sample_rate = %v, want 0.999999", lines[0]["sample_rate"])
+	}
+}
+
+func TestAccessLogMiddleware_OtherRoutesUnsampled(t *testing.T) {
+	lines := serveAccessLog(t, map[string]float64{"/other": 0}, "", http.StatusOK)
+
+	if len(lines) != 1 {
+		t.Fatalf("got %d log lines, want 1", len(lines))
+	}
+}
+
 func TestRecoveryMiddleware_PanicNoLeak(t *testing.T) {
 	// Suppress log output during test
 	var logBuf bytes.Buffer
@@ -794,6 +959,15 @@ func (m *mockStoreManager) GetStore(ctx context.Context, id string) (*multistore
 	return managed, nil
 }
 
+func (m *mockStoreManager) AcquireStore(ctx context.Context, id string) (*multistore.ManagedStore, error) {
+	managed, err := m.GetStore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	managed.Acquire()
+	return managed, nil
+}
+
 // TestStoreContextMiddleware_ValidStore tests that a valid store ID injects the store into context.
 func TestStoreContextMiddleware_ValidStore(t *testing.T) {
 	mgr := newMockStoreManager()
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// List endpoints page by keyset rather than offset: each page ends with an
+// opaque next_cursor naming its last item's position, and the next request
+// passes it back as cursor to list the items after it. A listing fetches
+// one item more than its limit to learn whether another page exists, so no
+// request counts the whole listing. Totals are served separately, as by
+// GET /lore/count.
+
+// errInvalidCursor is returned for a cursor not issued by encodeCursor.
+var errInvalidCursor = errors.New("invalid cursor")
+
+// encodeCursor returns an opaque cursor for the position key.
+func encodeCursor(key any) string {
+	b, err := json.Marshal(key)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor reads a cursor from encodeCursor into key.
+func decodeCursor(cursor string, key any) error {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return errInvalidCursor
+	}
+	if err := json.Unmarshal(b, key); err != nil {
+		return errInvalidCursor
+	}
+	return nil
+}
+
+// page trims items fetched with one more than limit to limit, reporting
+// whether the extra item showed that more follow.
+func page[T any](items []T, limit int) ([]T, bool) {
+	if len(items) > limit {
+		return items[:limit], true
+	}
+	return items, false
+}
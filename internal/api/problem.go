@@ -3,9 +3,12 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 
+	"github.com/hyperengineering/engram/internal/plugin"
 	"github.com/hyperengineering/engram/internal/store"
 	"github.com/hyperengineering/engram/internal/validation"
 )
@@ -44,6 +47,10 @@ var problemTypes = map[int]struct {
 		typeURI: "https://engram.dev/errors/validation-error",
 		title:   "Validation Error",
 	},
+	http.StatusBadGateway: {
+		typeURI: "https://engram.dev/errors/bad-gateway",
+		title:   "Bad Gateway",
+	},
 	http.StatusServiceUnavailable: {
 		typeURI: "https://engram.dev/errors/service-unavailable",
 		title:   "Service Unavailable",
@@ -60,6 +67,30 @@ var problemTypes = map[int]struct {
 		typeURI: "https://engram.dev/errors/rate-limit",
 		title:   "Too Many Requests",
 	},
+	http.StatusRequestEntityTooLarge: {
+		typeURI: "https://engram.dev/errors/payload-too-large",
+		title:   "Payload Too Large",
+	},
+	http.StatusUnsupportedMediaType: {
+		typeURI: "https://engram.dev/errors/unsupported-media-type",
+		title:   "Unsupported Media Type",
+	},
+	http.StatusPreconditionFailed: {
+		typeURI: "https://engram.dev/errors/precondition-failed",
+		title:   "Precondition Failed",
+	},
+	http.StatusPreconditionRequired: {
+		typeURI: "https://engram.dev/errors/precondition-required",
+		title:   "Precondition Required",
+	},
+	http.StatusLocked: {
+		typeURI: "https://engram.dev/errors/locked",
+		title:   "Locked",
+	},
+	http.StatusGone: {
+		typeURI: "https://engram.dev/errors/gone",
+		title:   "Gone",
+	},
 }
 
 // WriteProblem writes an RFC 7807 Problem Details response.
@@ -118,6 +149,36 @@ func WriteProblemWithErrors(w http.ResponseWriter, r *http.Request, detail strin
 	}
 }
 
+// ProblemTooLarge extends Problem with the request body limit that was
+// exceeded, so clients can size their retries.
+type ProblemTooLarge struct {
+	Problem
+	LimitBytes int64 `json:"limit_bytes"`
+}
+
+// WriteProblemTooLarge writes a 413 Problem Details response for a request
+// body over limit bytes. The hint tells the client how to split the request.
+func WriteProblemTooLarge(w http.ResponseWriter, r *http.Request, limit int64, hint string) {
+	pt := problemTypes[http.StatusRequestEntityTooLarge]
+
+	p := ProblemTooLarge{
+		Problem: Problem{
+			Type:     pt.typeURI,
+			Title:    pt.title,
+			Status:   http.StatusRequestEntityTooLarge,
+			Detail:   fmt.Sprintf("Request body exceeds %d bytes. %s", limit, hint),
+			Instance: r.URL.Path,
+		},
+		LimitBytes: limit,
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		slog.Error("failed to encode problem response", "error", err)
+	}
+}
+
 // WriteProblemConflict writes a 409 Conflict problem response.
 func WriteProblemConflict(w http.ResponseWriter, r *http.Request, detail string) {
 	WriteProblem(w, r, http.StatusConflict, detail)
@@ -128,6 +189,54 @@ func WriteProblemForbidden(w http.ResponseWriter, r *http.Request, detail string
 	WriteProblem(w, r, http.StatusForbidden, detail)
 }
 
+// WriteProblemStoreArchived writes a 423 Locked problem response for a
+// request to an archived store, saying how to thaw it.
+func WriteProblemStoreArchived(w http.ResponseWriter, r *http.Request, storeID string) {
+	WriteProblem(w, r, http.StatusLocked, fmt.Sprintf(
+		"Store %q is archived. Restore it with POST /api/v1/stores/%s/thaw, then retry the request.",
+		storeID, url.PathEscape(storeID)))
+}
+
+// ProblemHistoryCompacted extends Problem with where a client whose delta
+// cursor predates change_log compaction should re-bootstrap from.
+// SnapshotBaseSequence is the cursor to resume deltas after once the
+// snapshot is applied; it is omitted when no snapshot has been generated
+// since the server started.
+type ProblemHistoryCompacted struct {
+	Problem
+	CompactedThrough     int64  `json:"compacted_through"`
+	Snapshot             string `json:"snapshot"`
+	SnapshotBaseSequence *int64 `json:"snapshot_base_sequence,omitempty"`
+}
+
+// WriteProblemHistoryCompacted writes a 410 Problem Details response for a
+// delta requested after a sequence whose later changes compaction may have
+// removed.
+func WriteProblemHistoryCompacted(w http.ResponseWriter, r *http.Request, storeID string, compactedThrough int64, snapshotBase *int64) {
+	pt := problemTypes[http.StatusGone]
+
+	p := ProblemHistoryCompacted{
+		Problem: Problem{
+			Type:   pt.typeURI,
+			Title:  pt.title,
+			Status: http.StatusGone,
+			Detail: fmt.Sprintf(
+				"Change log history through sequence %d has been compacted. Re-bootstrap from the snapshot, then resume deltas after its base sequence.",
+				compactedThrough),
+			Instance: r.URL.Path,
+		},
+		CompactedThrough:     compactedThrough,
+		Snapshot:             fmt.Sprintf("/api/v1/stores/%s/sync/snapshot", url.PathEscape(storeID)),
+		SnapshotBaseSequence: snapshotBase,
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(http.StatusGone)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		slog.Error("failed to encode problem response", "error", err)
+	}
+}
+
 // MapStoreError converts domain errors to Problem Details responses.
 func MapStoreError(w http.ResponseWriter, r *http.Request, err error) {
 	switch {
@@ -135,8 +244,40 @@ func MapStoreError(w http.ResponseWriter, r *http.Request, err error) {
 		WriteProblem(w, r, http.StatusNotFound, "Resource not found")
 	case errors.Is(err, store.ErrDuplicateLore):
 		WriteProblem(w, r, http.StatusConflict, "Duplicate entry")
+	case errors.Is(err, store.ErrPushSessionNotFound):
+		WriteProblem(w, r, http.StatusNotFound, "Push session not found")
+	case errors.Is(err, store.ErrPushSessionExists):
+		WriteProblem(w, r, http.StatusConflict, "Push session already exists")
+	case errors.Is(err, store.ErrPushSessionFull):
+		WriteProblem(w, r, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("Push session exceeds maximum of %d entries", store.MaxPushSessionEntries))
+	case errors.Is(err, store.ErrConflictNotFound):
+		WriteProblem(w, r, http.StatusNotFound, "Sync conflict not found")
+	case errors.Is(err, store.ErrConflictResolved):
+		WriteProblem(w, r, http.StatusConflict, "Sync conflict already resolved")
+	case errors.Is(err, store.ErrAlreadyReviewed):
+		WriteProblem(w, r, http.StatusConflict, "Lore entry already reviewed")
+	case errors.Is(err, store.ErrVersionNotFound):
+		WriteProblem(w, r, http.StatusNotFound, "Lore version not found")
+	case errors.Is(err, store.ErrEvalSetNotFound):
+		WriteProblem(w, r, http.StatusNotFound, "Evaluation set not found")
+	case errors.Is(err, store.ErrMetaNotFound):
+		WriteProblem(w, r, http.StatusNotFound, "Metadata key not found")
+	case errors.Is(err, store.ErrMetaFull):
+		WriteProblem(w, r, http.StatusConflict,
+			fmt.Sprintf("Store already holds %d metadata keys; delete one first", store.MaxClientMetaKeys))
+	case errors.Is(err, store.ErrVersionMismatch):
+		WriteProblem(w, r, http.StatusPreconditionFailed, staleETagDetail)
 	case errors.Is(err, store.ErrEmbeddingUnavailable):
 		WriteProblem(w, r, http.StatusServiceUnavailable, "Embedding service unavailable")
+	case errors.Is(err, store.ErrDimensionMismatch):
+		WriteProblem(w, r, http.StatusServiceUnavailable,
+			"Embedding model does not match the store's embeddings")
+	case store.IsBusy(err):
+		writeBusy(w, r)
+	case errors.Is(err, plugin.ErrVetoed):
+		// Veto messages are authored by the plugin for the client
+		WriteProblem(w, r, http.StatusUnprocessableEntity, err.Error())
 	default:
 		// Never expose internal error details to client
 		WriteProblem(w, r, http.StatusInternalServerError, "Internal Server Error")
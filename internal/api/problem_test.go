@@ -3,10 +3,13 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/hyperengineering/engram/internal/plugin"
 	"github.com/hyperengineering/engram/internal/store"
 	"github.com/hyperengineering/engram/internal/validation"
 )
@@ -222,6 +225,50 @@ func TestMapStoreError_Duplicate(t *testing.T) {
 	}
 }
 
+func TestMapStoreError_AlreadyReviewed(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/lore/123/approve", nil)
+
+	MapStoreError(w, r, fmt.Errorf("review lore: %w", store.ErrAlreadyReviewed))
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusConflict)
+	}
+}
+
+func TestMapStoreError_PushSessionFull(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/stores/default/sync/push-sessions/p/entries", nil)
+
+	MapStoreError(w, r, store.ErrPushSessionFull)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestMapStoreError_VersionNotFound(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/lore/123/versions/9/revert", nil)
+
+	MapStoreError(w, r, fmt.Errorf("revert lore: %w", store.ErrVersionNotFound))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestMapStoreError_VersionMismatch(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPatch, "/api/v1/lore/123", nil)
+
+	MapStoreError(w, r, fmt.Errorf("update lore: %w", store.ErrVersionMismatch))
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusPreconditionFailed)
+	}
+}
+
 func TestMapStoreError_EmbeddingUnavailable(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest(http.MethodPost, "/api/v1/recall", nil)
@@ -241,6 +288,25 @@ func TestMapStoreError_EmbeddingUnavailable(t *testing.T) {
 	}
 }
 
+func TestMapStoreError_Vetoed(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodDelete, "/api/v1/lore/123", nil)
+
+	MapStoreError(w, r, fmt.Errorf("before delete hook: %w: entry is pinned", plugin.ErrVetoed))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+
+	var p Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if !strings.Contains(p.Detail, "entry is pinned") {
+		t.Errorf("detail = %q, want plugin message", p.Detail)
+	}
+}
+
 func TestMapStoreError_Unknown(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest(http.MethodGet, "/api/v1/lore", nil)
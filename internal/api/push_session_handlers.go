@@ -0,0 +1,275 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/plugin"
+	"github.com/hyperengineering/engram/internal/store"
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+// Multi-part push sessions let a client upload a backlog larger than one
+// push request allows. The client opens a session with a push_id, appends
+// entries in chunks, then commits; the commit is applied exactly like a
+// single push with all staged entries, in one transaction.
+
+// requireSyncStore resolves the managed store for a sync request.
+// Writes a 404 and returns nil when the store is unavailable.
+func (h *Handler) requireSyncStore(w http.ResponseWriter, r *http.Request) *multistore.ManagedStore {
+	if h.storeManager == nil {
+		WriteProblem(w, r, http.StatusNotFound, "Store not found")
+		return nil
+	}
+	managed, err := h.storeManager.GetStore(r.Context(), StoreIDFromContext(r.Context()))
+	if err != nil {
+		WriteProblem(w, r, http.StatusNotFound, "Store not found")
+		return nil
+	}
+	return managed
+}
+
+// BeginPushSession handles POST /api/v1/stores/{store_id}/sync/push-sessions
+//
+// The body is a push request without entries. Push options
+// (allow_deferred_parents, cascade_deletes, quarantine_invalid, mode) are
+// fixed at this point and apply to the commit.
+func (h *Handler) BeginPushSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	storeID := StoreIDFromContext(ctx)
+
+	managed := h.requireSyncStore(w, r)
+	if managed == nil {
+		return
+	}
+
+	var req engramsync.PushRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err))
+		return
+	}
+	if err := validatePushSessionRequest(req); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.signing.Authorize(ctx, req.SourceID); err != nil {
+		writePushAuthorizeError(w, r, req.SourceID, err)
+		return
+	}
+
+	// Reject early rather than after the whole backlog is uploaded
+	p, _ := plugin.Get(managed.Type())
+	if !checkClientSchema(w, r, p, req.SchemaVersion, managed.SchemaVersion(ctx)) {
+		return
+	}
+
+	now := time.Now().UTC()
+	session := engramsync.PushSession{
+		PushID:               req.PushID,
+		SourceID:             req.SourceID,
+		SchemaVersion:        req.SchemaVersion,
+		AllowDeferredParents: req.AllowDeferredParents,
+		CascadeDeletes:       req.CascadeDeletes,
+		QuarantineInvalid:    req.QuarantineInvalid,
+		Mode:                 req.Mode,
+		CreatedAt:            now,
+		ExpiresAt:            now.Add(h.pushSessionTTL),
+	}
+	if err := managed.Store.CreatePushSession(ctx, session); err != nil {
+		MapStoreError(w, r, err)
+		return
+	}
+
+	slog.Info("push session started",
+		"component", "api",
+		"action", "push_session_begin",
+		"store_id", storeID,
+		"push_id", req.PushID,
+		"source_id", req.SourceID,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(session)
+}
+
+// AppendPushSession handles POST /api/v1/stores/{store_id}/sync/push-sessions/{push_id}/entries
+//
+// Each chunk is subject to the same entry and byte limits as a single push.
+func (h *Handler) AppendPushSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	storeID := StoreIDFromContext(ctx)
+	pushID := chi.URLParam(r, "push_id")
+
+	managed := h.requireSyncStore(w, r)
+	if managed == nil {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxPushBytes)
+	var req engramsync.PushSessionAppendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writePushTooLarge(w, r, tooLarge.Limit)
+			return
+		}
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err))
+		return
+	}
+	if len(req.Entries) == 0 {
+		WriteProblem(w, r, http.StatusBadRequest, "entries array is required")
+		return
+	}
+	if len(req.Entries) > MaxPushEntries {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("entries exceeds maximum of %d", MaxPushEntries))
+		return
+	}
+
+	session, err := managed.Store.GetPushSession(ctx, pushID)
+	if err != nil {
+		MapStoreError(w, r, err)
+		return
+	}
+	if err := h.signing.Authorize(ctx, session.SourceID); err != nil {
+		writePushAuthorizeError(w, r, session.SourceID, err)
+		return
+	}
+
+	count, err := managed.Store.AppendPushSessionEntries(ctx, pushID, req.Entries)
+	if err != nil {
+		if !errors.Is(err, store.ErrPushSessionNotFound) {
+			slog.Error("push session append failed", "store_id", storeID, "push_id", pushID, "error", err)
+		}
+		MapStoreError(w, r, err)
+		return
+	}
+
+	session.EntryCount = count
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// CommitPushSession handles POST /api/v1/stores/{store_id}/sync/push-sessions/{push_id}/commit
+//
+// Applies all staged entries as one push. Committing an already applied
+// push_id replays the cached response, as with a retried push.
+func (h *Handler) CommitPushSession(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ctx := r.Context()
+	storeID := StoreIDFromContext(ctx)
+	pushID := chi.URLParam(r, "push_id")
+
+	managed := h.requireSyncStore(w, r)
+	if managed == nil {
+		return
+	}
+
+	if replayed := writeIdempotentReplay(w, r, managed.Store, storeID, pushID); replayed {
+		return
+	}
+
+	session, err := managed.Store.GetPushSession(ctx, pushID)
+	if err != nil {
+		MapStoreError(w, r, err)
+		return
+	}
+	// Checked again, as the source may have been revoked since it began
+	if err := h.signing.Authorize(ctx, session.SourceID); err != nil {
+		writePushAuthorizeError(w, r, session.SourceID, err)
+		return
+	}
+	entries, err := managed.Store.GetPushSessionEntries(ctx, pushID)
+	if err != nil {
+		slog.Error("push session load failed", "store_id", storeID, "push_id", pushID, "error", err)
+		MapStoreError(w, r, err)
+		return
+	}
+	if len(entries) == 0 {
+		WriteProblem(w, r, http.StatusBadRequest, "Push session has no entries")
+		return
+	}
+
+	req := engramsync.PushRequest{
+		PushID:               session.PushID,
+		SourceID:             session.SourceID,
+		SchemaVersion:        session.SchemaVersion,
+		Entries:              entries,
+		AllowDeferredParents: session.AllowDeferredParents,
+		CascadeDeletes:       session.CascadeDeletes,
+		QuarantineInvalid:    session.QuarantineInvalid,
+		Mode:                 session.Mode,
+	}
+	respBytes, ok := h.applyPush(w, r, managed, req, start)
+	if !ok {
+		// Keep the session so the client can inspect the error and abort
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(respBytes)
+
+	if err := managed.Store.DeletePushSession(ctx, pushID); err != nil {
+		slog.Warn("failed to delete committed push session", "store_id", storeID, "push_id", pushID, "error", err)
+	}
+}
+
+// AbortPushSession handles DELETE /api/v1/stores/{store_id}/sync/push-sessions/{push_id}
+//
+// Discards the session and its staged entries. Aborting an unknown session
+// succeeds so clients can retry safely.
+func (h *Handler) AbortPushSession(w http.ResponseWriter, r *http.Request) {
+	storeID := StoreIDFromContext(r.Context())
+	pushID := chi.URLParam(r, "push_id")
+
+	managed := h.requireSyncStore(w, r)
+	if managed == nil {
+		return
+	}
+
+	session, err := managed.Store.GetPushSession(r.Context(), pushID)
+	if errors.Is(err, store.ErrPushSessionNotFound) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		MapStoreError(w, r, err)
+		return
+	}
+	// Only the session's own source may discard it
+	if err := h.signing.Authorize(r.Context(), session.SourceID); err != nil {
+		writePushAuthorizeError(w, r, session.SourceID, err)
+		return
+	}
+
+	if err := managed.Store.DeletePushSession(r.Context(), pushID); err != nil {
+		slog.Error("push session abort failed", "store_id", storeID, "push_id", pushID, "error", err)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validatePushSessionRequest validates the session-opening request. Entries
+// are sent separately via append.
+func validatePushSessionRequest(req engramsync.PushRequest) error {
+	if req.PushID == "" {
+		return fmt.Errorf("push_id is required")
+	}
+	if req.SourceID == "" {
+		return fmt.Errorf("source_id is required")
+	}
+	if req.SchemaVersion < 1 {
+		return fmt.Errorf("schema_version must be >= 1")
+	}
+	if len(req.Entries) > 0 {
+		return fmt.Errorf("entries must be appended to the session, not sent on begin")
+	}
+	return validatePushMode(req.Mode)
+}
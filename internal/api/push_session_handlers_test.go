@@ -0,0 +1,324 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+func loreEntries(t *testing.T, from, n int) []engramsync.ChangeLogEntry {
+	t.Helper()
+	entries := make([]engramsync.ChangeLogEntry, n)
+	for i := range entries {
+		id := fmt.Sprintf("lore-%03d", from+i)
+		entries[i] = engramsync.ChangeLogEntry{
+			TableName: "lore_entries",
+			EntityID:  id,
+			Operation: "upsert",
+			Payload:   validLorePayload(t, id),
+		}
+	}
+	return entries
+}
+
+func doSyncRequest(t *testing.T, router http.Handler, method, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, "/api/v1/stores/test-store/sync"+path, &buf)
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func beginSession(t *testing.T, router http.Handler, pushID string) {
+	t.Helper()
+	w := doSyncRequest(t, router, http.MethodPost, "/push-sessions", engramsync.PushRequest{
+		PushID:        pushID,
+		SourceID:      "test-client",
+		SchemaVersion: 2,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("begin: expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSyncPush_BodyTooLarge(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	handler.maxPushBytes = 1024
+	router := NewRouter(handler, manager)
+
+	w := doSyncRequest(t, router, http.MethodPost, "/push", engramsync.PushRequest{
+		PushID:        "push-big",
+		SourceID:      "test-client",
+		SchemaVersion: 2,
+		Entries:       loreEntries(t, 1, 10),
+	})
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d: %s", w.Code, w.Body.String())
+	}
+	var problem ProblemTooLarge
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Type != "https://engram.dev/errors/payload-too-large" {
+		t.Errorf("type = %q, want payload-too-large", problem.Type)
+	}
+	if problem.LimitBytes != 1024 {
+		t.Errorf("limit_bytes = %d, want 1024", problem.LimitBytes)
+	}
+}
+
+func TestPushSession_BeginAppendCommit(t *testing.T) {
+	manager, handler, managed := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	beginSession(t, router, "push-session-1")
+
+	for _, chunk := range [][]engramsync.ChangeLogEntry{loreEntries(t, 1, 3), loreEntries(t, 4, 2)} {
+		w := doSyncRequest(t, router, http.MethodPost, "/push-sessions/push-session-1/entries",
+			engramsync.PushSessionAppendRequest{Entries: chunk})
+		if w.Code != http.StatusOK {
+			t.Fatalf("append: expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	w := doSyncRequest(t, router, http.MethodPost, "/push-sessions/push-session-1/commit", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("commit: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp engramsync.PushResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Accepted != 5 {
+		t.Errorf("Accepted = %d, want 5", resp.Accepted)
+	}
+
+	entries, err := managed.Store.GetChangeLogAfter(context.Background(), 0, 100)
+	if err != nil {
+		t.Fatalf("GetChangeLogAfter() error = %v", err)
+	}
+	if len(entries) != 5 {
+		t.Errorf("change_log entries = %d, want 5", len(entries))
+	}
+
+	// Session is removed on success; retrying the commit replays the response
+	if _, err := managed.Store.GetPushSession(context.Background(), "push-session-1"); err == nil {
+		t.Error("expected session to be deleted after commit")
+	}
+	w = doSyncRequest(t, router, http.MethodPost, "/push-sessions/push-session-1/commit", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("commit retry: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("X-Idempotent-Replay") != "true" {
+		t.Error("expected X-Idempotent-Replay on commit retry")
+	}
+}
+
+func TestPushSession_CommitAuthorized(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	beginSession(t, router, "push-session-signed")
+	w := doSyncRequest(t, router, http.MethodPost, "/push-sessions/push-session-signed/entries",
+		engramsync.PushSessionAppendRequest{Entries: loreEntries(t, 1, 1)})
+	if w.Code != http.StatusOK {
+		t.Fatalf("append: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The source must sign from now on, so its unsigned commit is refused
+	WithPushSigning(NewPushSigning(map[string]string{"test-client": "s3cret"}, false, 0))(handler)
+	w = doSyncRequest(t, router, http.MethodPost, "/push-sessions/push-session-signed/commit", nil)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned commit: expected 401, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestPushSession_AbortAuthorized(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	WithPushSigning(NewPushSigning(map[string]string{"test-client": "s3cret"}, false, 0))(handler)
+	router := NewRouter(handler, manager)
+
+	signed := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		t.Helper()
+		fullPath := "/api/v1/stores/test-store/sync" + path
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req := httptest.NewRequest(method, fullPath, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(HeaderSyncSource, "test-client")
+		req.Header.Set(HeaderSyncTimestamp, ts)
+		req.Header.Set(HeaderSyncSignature, SignPush("s3cret", ts, method, fullPath, "test-store", body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	body, _ := json.Marshal(engramsync.PushRequest{PushID: "push-session-abort", SourceID: "test-client", SchemaVersion: 2})
+	if w := signed(http.MethodPost, "/push-sessions", body); w.Code != http.StatusCreated {
+		t.Fatalf("begin: expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Another API key holder cannot discard the session unsigned
+	w := doSyncRequest(t, router, http.MethodDelete, "/push-sessions/push-session-abort", nil)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned abort: expected 401, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := signed(http.MethodDelete, "/push-sessions/push-session-abort", nil); w.Code != http.StatusNoContent {
+		t.Errorf("signed abort: expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestPushSession_DuplicateBegin(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	beginSession(t, router, "push-dup")
+
+	w := doSyncRequest(t, router, http.MethodPost, "/push-sessions", engramsync.PushRequest{
+		PushID:        "push-dup",
+		SourceID:      "test-client",
+		SchemaVersion: 2,
+	})
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected 409, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestPushSession_BeginRejectsEntries(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	w := doSyncRequest(t, router, http.MethodPost, "/push-sessions", engramsync.PushRequest{
+		PushID:        "push-with-entries",
+		SourceID:      "test-client",
+		SchemaVersion: 2,
+		Entries:       loreEntries(t, 1, 1),
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestPushSession_BeginSchemaAhead(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	w := doSyncRequest(t, router, http.MethodPost, "/push-sessions", engramsync.PushRequest{
+		PushID:        "push-ahead",
+		SourceID:      "test-client",
+		SchemaVersion: 5,
+	})
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected 409, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestPushSession_UnknownSession(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	w := doSyncRequest(t, router, http.MethodPost, "/push-sessions/nope/entries",
+		engramsync.PushSessionAppendRequest{Entries: loreEntries(t, 1, 1)})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("append: expected 404, got %d", w.Code)
+	}
+
+	w = doSyncRequest(t, router, http.MethodPost, "/push-sessions/nope/commit", nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("commit: expected 404, got %d", w.Code)
+	}
+}
+
+func TestPushSession_Expired(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	handler.pushSessionTTL = time.Millisecond
+	router := NewRouter(handler, manager)
+
+	beginSession(t, router, "push-expiring")
+	time.Sleep(5 * time.Millisecond)
+
+	w := doSyncRequest(t, router, http.MethodPost, "/push-sessions/push-expiring/entries",
+		engramsync.PushSessionAppendRequest{Entries: loreEntries(t, 1, 1)})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for expired session, got %d", w.Code)
+	}
+}
+
+func TestPushSession_AppendTooLarge(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	beginSession(t, router, "push-chunk")
+	handler.maxPushBytes = 512
+
+	w := doSyncRequest(t, router, http.MethodPost, "/push-sessions/push-chunk/entries",
+		engramsync.PushSessionAppendRequest{Entries: loreEntries(t, 1, 10)})
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestPushSession_CommitEmpty(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	beginSession(t, router, "push-empty")
+
+	w := doSyncRequest(t, router, http.MethodPost, "/push-sessions/push-empty/commit", nil)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestPushSession_Abort(t *testing.T) {
+	manager, handler, managed := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	beginSession(t, router, "push-abort")
+	doSyncRequest(t, router, http.MethodPost, "/push-sessions/push-abort/entries",
+		engramsync.PushSessionAppendRequest{Entries: loreEntries(t, 1, 2)})
+
+	w := doSyncRequest(t, router, http.MethodDelete, "/push-sessions/push-abort", nil)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+
+	w = doSyncRequest(t, router, http.MethodPost, "/push-sessions/push-abort/commit", nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("commit after abort: expected 404, got %d", w.Code)
+	}
+	entries, _ := managed.Store.GetChangeLogAfter(context.Background(), 0, 100)
+	if len(entries) != 0 {
+		t.Errorf("change_log entries = %d, want 0", len(entries))
+	}
+}
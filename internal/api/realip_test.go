@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRealIPMiddleware(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.10"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		peer      string
+		forwarded string
+		realIP    string
+		want      string
+	}{
+		{"untrusted peer", "203.0.113.5:1000", "198.51.100.1", "198.51.100.2", "203.0.113.5:1000"},
+		{"trusted peer", "10.1.2.3:1000", "198.51.100.1", "", "198.51.100.1"},
+		{"spoofed prefix", "10.1.2.3:1000", "1.2.3.4, 198.51.100.1", "", "198.51.100.1"},
+		{"proxy chain", "10.1.2.3:1000", "198.51.100.1, 192.168.1.10", "", "198.51.100.1"},
+		{"real ip", "192.168.1.10:1000", "", "198.51.100.2", "198.51.100.2"},
+		{"malformed", "10.1.2.3:1000", "not-an-ip", "", "10.1.2.3:1000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			mw := RealIPMiddleware(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.peer
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			mw.ServeHTTP(httptest.NewRecorder(), req)
+			if got != tt.want {
+				t.Errorf("RemoteAddr = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxies_Invalid(t *testing.T) {
+	if _, err := ParseTrustedProxies([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("ParseTrustedProxies() with a bad CIDR = nil, want error")
+	}
+	if _, err := ParseTrustedProxies([]string{"proxy.local"}); err == nil {
+		t.Error("ParseTrustedProxies() with a hostname = nil, want error")
+	}
+}
+
+func TestAuthLockout_IgnoresForgedForwardedFor(t *testing.T) {
+	lockout := NewAuthLockout(LockoutConfig{MaxFailures: 2, Window: time.Minute, Duration: time.Minute})
+	handler, _ := mockHandler()
+	mw := RealIPMiddleware(nil)(lockout.Middleware(AuthMiddleware(testAPIKey)(handler)))
+
+	do := func(peer, forwarded, token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/lore", nil)
+		req.RemoteAddr = peer
+		req.Header.Set("X-Forwarded-For", forwarded)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		mw.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// An attacker naming a victim's address bans only themselves
+	do("203.0.113.5:1000", "198.51.100.7", "wrong")
+	do("203.0.113.5:1001", "198.51.100.8", "wrong")
+	if got := do("198.51.100.7:2000", "", testAPIKey); got != http.StatusOK {
+		t.Errorf("victim: status = %d, want %d", got, http.StatusOK)
+	}
+	if got := do("203.0.113.5:1002", "198.51.100.9", testAPIKey); got != http.StatusTooManyRequests {
+		t.Errorf("attacker with a new forwarded address: status = %d, want %d", got, http.StatusTooManyRequests)
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/recall"
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/validation"
+)
+
+// Recall handles POST /api/v1/lore/recall and POST /api/v1/stores/{store_id}/lore/recall
+//
+// Runs a hybrid search for the task and returns the results as a bundle
+// for prompt injection: ranked, with near-duplicates dropped, and cut to
+// fit token_budget. format selects markdown (default) or json,
+// category_quotas bounds the entries taken from each category, and repo and
+// path keep out lore recorded for other repositories or paths.
+func (h *Handler) Recall(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	start := time.Now()
+	storeID := StoreIDFromContext(r.Context())
+	s := h.getStoreForRequest(r)
+
+	var req types.RecallRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err.Error()))
+		return
+	}
+	req.Task = strings.TrimSpace(req.Task)
+	req.Path = scopePath(req.Path)
+	if errs := validation.ValidateRecallRequest(req); len(errs) > 0 {
+		WriteProblemWithErrors(w, r, "Request contains invalid fields", errs)
+		return
+	}
+	if req.Format == "" {
+		req.Format = types.RecallFormatMarkdown
+	}
+
+	results, err := s.SearchLore(r.Context(), h.hybridSearchQuery(r.Context(), storeID, "recall", types.SearchQuery{
+		Text:     req.Task,
+		Category: req.Category,
+		Language: req.Language,
+		Repo:     req.Repo,
+		Path:     req.Path,
+		Limit:    validation.RecallCandidates,
+		Quotas:   req.CategoryQuotas,
+	}))
+	if err != nil {
+		slog.Error("recall search failed",
+			"component", "api",
+			"action", "recall_failed",
+			"store_id", storeID,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	resp := recall.Bundle(results, req.TokenBudget, req.Format)
+
+	slog.Info("recall bundle built",
+		"component", "api",
+		"action", "recall",
+		"store_id", storeID,
+		"format", resp.Format,
+		"candidates", len(results),
+		"entries", len(resp.Entries),
+		"duplicates_dropped", resp.DuplicatesDropped,
+		"estimated_tokens", resp.EstimatedTokens,
+		"token_budget", resp.TokenBudget,
+		"truncated", resp.Truncated,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/validation"
+)
+
+func serveRecall(t *testing.T, s *mockStore, e *mockEmbedder, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := NewRouter(NewHandler(s, nil, e, nil, "test-key", "1.0.0"), nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lore/recall", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRecall_ReturnsMarkdownBundle(t *testing.T) {
+	s := &mockStore{searchResults: []types.SearchResult{
+		{Lore: types.LoreEntry{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Category: "PATTERN_OUTCOME", Content: "Retry with backoff", Confidence: 0.9}},
+		{Lore: types.LoreEntry{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAW", Category: "PATTERN_OUTCOME", Content: "Retry with backoff.", Confidence: 0.7}},
+	}}
+	e := &mockEmbedder{vector: []float32{0.1, 0.2}}
+
+	w := serveRecall(t, s, e, `{"task":"  add retries to payments  ","token_budget":500,"category":"PATTERN_OUTCOME","repo":"acme/payments","path":"internal/queue/"}`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if q := s.lastSearch; q == nil || q.Text != "add retries to payments" || q.Mode != types.SearchModeHybrid ||
+		q.Category != "PATTERN_OUTCOME" || q.Limit != validation.RecallCandidates ||
+		q.Repo != "acme/payments" || q.Path != "internal/queue" {
+		t.Errorf("search = %+v", s.lastSearch)
+	}
+	var resp types.RecallResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Format != types.RecallFormatMarkdown || len(resp.Entries) != 1 || resp.DuplicatesDropped != 1 ||
+		!strings.Contains(resp.Content, "Retry with backoff") || resp.TokenBudget != 500 {
+		t.Errorf("unexpected bundle: %+v", resp)
+	}
+}
+
+func TestRecall_FallsBackToKeywordSearch(t *testing.T) {
+	s := &mockStore{}
+	w := serveRecall(t, s, &mockEmbedder{err: errors.New("down")}, `{"task":"retries","token_budget":500,"format":"json"}`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if s.lastSearch == nil || s.lastSearch.Mode != types.SearchModeKeyword {
+		t.Errorf("search = %+v, want keyword mode", s.lastSearch)
+	}
+	var resp types.RecallResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Format != types.RecallFormatJSON || resp.Entries == nil || resp.Content != "" {
+		t.Errorf("unexpected bundle: %+v", resp)
+	}
+}
+
+func TestRecall_InvalidRequest(t *testing.T) {
+	for _, body := range []string{`{"task":"retries"}`, `{"task":"","token_budget":100}`, `{"task":"retries","token_budget":100,"format":"xml"}`} {
+		w := serveRecall(t, &mockStore{}, &mockEmbedder{}, body)
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status = %d, want 422", body, w.Code)
+		}
+	}
+	if w := serveRecall(t, &mockStore{}, &mockEmbedder{}, `{`); w.Code != http.StatusBadRequest {
+		t.Errorf("malformed JSON: status = %d, want 400", w.Code)
+	}
+}
@@ -0,0 +1,361 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/validation"
+	"github.com/hyperengineering/engram/internal/webhook"
+)
+
+// reviewRequest is the body of an approve or reject call.
+type reviewRequest struct {
+	Reviewer string `json:"reviewer"`
+	Note     string `json:"note,omitempty"`
+}
+
+// ReviewQueue handles GET /api/v1/lore/review and GET /api/v1/stores/{store_id}/lore/review
+//
+// Lists unreviewed entries for human curation: those with confidence below
+// max_confidence (default 0.3) or at least min_incorrect (default 2)
+// "incorrect" feedback events, optionally only those in one language.
+// limit defaults to 50 (max 500); cursor resumes from a previous page's
+// next_cursor.
+func (h *Handler) ReviewQueue(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	storeID := StoreIDFromContext(r.Context())
+	s := h.getStoreForRequest(r)
+	params := r.URL.Query()
+
+	q := types.ReviewQueueQuery{
+		MaxConfidence: store.DefaultReviewMaxConfidence,
+		MinIncorrect:  store.DefaultReviewMinIncorrect,
+		Limit:         store.DefaultReviewQueueLimit,
+	}
+	if raw := params.Get("max_confidence"); raw != "" {
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil || f < 0 || f > 1 {
+			WriteProblem(w, r, http.StatusBadRequest, "Invalid max_confidence: must be a number between 0 and 1")
+			return
+		}
+		q.MaxConfidence = f
+	}
+	if raw := params.Get("min_incorrect"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			WriteProblem(w, r, http.StatusBadRequest, "Invalid min_incorrect: must be a positive integer")
+			return
+		}
+		q.MinIncorrect = n
+	}
+	if raw := params.Get("language"); raw != "" {
+		if verr := validation.ValidateLanguage("language", raw); verr != nil {
+			WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid language: %s", verr.Message))
+			return
+		}
+		q.Language = raw
+	}
+	if raw := params.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > store.MaxReviewQueueLimit {
+			WriteProblem(w, r, http.StatusBadRequest,
+				fmt.Sprintf("Invalid limit: must be an integer between 1 and %d", store.MaxReviewQueueLimit))
+			return
+		}
+		q.Limit = n
+	}
+	if raw := params.Get("cursor"); raw != "" {
+		q.After = &types.ReviewQueueKey{}
+		if err := decodeCursor(raw, q.After); err != nil {
+			WriteProblem(w, r, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+	}
+
+	limit := q.Limit
+	q.Limit++
+	entries, err := s.ListReviewQueue(r.Context(), q)
+	if err != nil {
+		slog.Error("review queue lookup failed",
+			"component", "api",
+			"action", "review_queue_failed",
+			"store_id", storeID,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+	if entries == nil {
+		entries = []types.ReviewCandidate{}
+	}
+
+	var resp types.ReviewQueueResponse
+	resp.Entries, resp.HasMore = page(entries, limit)
+	if resp.HasMore {
+		last := resp.Entries[len(resp.Entries)-1]
+		resp.NextCursor = encodeCursor(types.ReviewQueueKey{
+			IncorrectCount: last.IncorrectCount,
+			Confidence:     last.Confidence,
+			ID:             last.ID,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetReview handles GET /api/v1/lore/{id}/review and GET /api/v1/stores/{store_id}/lore/{id}/review
+func (h *Handler) GetReview(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	storeID := StoreIDFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+	s := h.getStoreForRequest(r)
+
+	if err := validation.ValidateULID("id", id); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest,
+			"Invalid lore ID format: must be valid ULID")
+		return
+	}
+
+	review, err := s.GetReview(r.Context(), id)
+	if err != nil {
+		slog.Error("review lookup failed",
+			"component", "api",
+			"action", "get_review_failed",
+			"store_id", storeID,
+			"lore_id", id,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+// ApproveLore handles POST /api/v1/lore/{id}/approve and POST /api/v1/stores/{store_id}/lore/{id}/approve
+//
+// Marks an unreviewed entry approved and locks its confidence floor at the
+// current confidence.
+func (h *Handler) ApproveLore(w http.ResponseWriter, r *http.Request) {
+	h.reviewLore(w, r, types.ReviewApproved)
+}
+
+// RejectLore handles POST /api/v1/lore/{id}/reject and POST /api/v1/stores/{store_id}/lore/{id}/reject
+//
+// Marks an unreviewed entry rejected and soft-deletes it.
+func (h *Handler) RejectLore(w http.ResponseWriter, r *http.Request) {
+	h.reviewLore(w, r, types.ReviewRejected)
+}
+
+func (h *Handler) reviewLore(w http.ResponseWriter, r *http.Request, status types.ReviewStatus) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	storeID := StoreIDFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+	s := h.getStoreForRequest(r)
+
+	if err := validation.ValidateULID("id", id); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest,
+			"Invalid lore ID format: must be valid ULID")
+		return
+	}
+
+	var req reviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err.Error()))
+		return
+	}
+	if errs := validation.ValidateReviewRequest(req.Reviewer, req.Note); len(errs) > 0 {
+		WriteProblemWithErrors(w, r, "Request contains invalid fields", errs)
+		return
+	}
+
+	review, err := s.ReviewLore(r.Context(), id, status, req.Reviewer, req.Note)
+	if err != nil {
+		slog.Error("lore review failed",
+			"component", "api",
+			"action", "review_failed",
+			"store_id", storeID,
+			"lore_id", id,
+			"status", string(status),
+			"reviewer", req.Reviewer,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	slog.Info("lore reviewed",
+		"component", "api",
+		"action", "review",
+		"store_id", storeID,
+		"lore_id", id,
+		"status", string(status),
+		"reviewer", req.Reviewer,
+		"request_id", GetRequestID(r.Context()),
+		"remote_addr", r.RemoteAddr,
+	)
+
+	if status == types.ReviewRejected {
+		h.notify(webhook.EventLoreDeleted, storeID, webhook.DeleteData{
+			LoreID:   id,
+			SourceID: req.Reviewer,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+// confidenceLockRequest is the body of a confidence lock update. A nil
+// bound is removed.
+type confidenceLockRequest struct {
+	Floor   *float64 `json:"floor"`
+	Ceiling *float64 `json:"ceiling"`
+	SetBy   string   `json:"set_by"`
+}
+
+// GetConfidenceLock handles GET /api/v1/lore/{id}/lock and GET /api/v1/stores/{store_id}/lore/{id}/lock
+func (h *Handler) GetConfidenceLock(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	storeID := StoreIDFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+	s := h.getStoreForRequest(r)
+
+	if err := validation.ValidateULID("id", id); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest,
+			"Invalid lore ID format: must be valid ULID")
+		return
+	}
+
+	lock, err := s.GetConfidenceLock(r.Context(), id)
+	if err != nil {
+		slog.Error("confidence lock lookup failed",
+			"component", "api",
+			"action", "get_lock_failed",
+			"store_id", storeID,
+			"lore_id", id,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lock)
+}
+
+// SetConfidenceLock handles PUT /api/v1/lore/{id}/lock and PUT /api/v1/stores/{store_id}/lore/{id}/lock
+//
+// Pins an entry with a floor and/or deprecates it with a ceiling, replacing
+// any previous bounds. The entry's confidence moves into the new range.
+func (h *Handler) SetConfidenceLock(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	storeID := StoreIDFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+	s := h.getStoreForRequest(r)
+
+	if err := validation.ValidateULID("id", id); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest,
+			"Invalid lore ID format: must be valid ULID")
+		return
+	}
+
+	var req confidenceLockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err.Error()))
+		return
+	}
+	if errs := validation.ValidateConfidenceLock(req.Floor, req.Ceiling, req.SetBy); len(errs) > 0 {
+		WriteProblemWithErrors(w, r, "Request contains invalid fields", errs)
+		return
+	}
+
+	lock, err := s.SetConfidenceLock(r.Context(), id, req.Floor, req.Ceiling, req.SetBy)
+	if err != nil {
+		slog.Error("confidence lock update failed",
+			"component", "api",
+			"action", "set_lock_failed",
+			"store_id", storeID,
+			"lore_id", id,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	slog.Info("confidence lock set",
+		"component", "api",
+		"action", "set_lock",
+		"store_id", storeID,
+		"lore_id", id,
+		"pinned", req.Floor != nil,
+		"deprecated", req.Ceiling != nil,
+		"set_by", req.SetBy,
+		"request_id", GetRequestID(r.Context()),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lock)
+}
+
+// ClearConfidenceLock handles DELETE /api/v1/lore/{id}/lock and DELETE /api/v1/stores/{store_id}/lore/{id}/lock
+func (h *Handler) ClearConfidenceLock(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	storeID := StoreIDFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+	s := h.getStoreForRequest(r)
+
+	if err := validation.ValidateULID("id", id); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest,
+			"Invalid lore ID format: must be valid ULID")
+		return
+	}
+
+	if err := s.ClearConfidenceLock(r.Context(), id); err != nil {
+		slog.Error("confidence lock removal failed",
+			"component", "api",
+			"action", "clear_lock_failed",
+			"store_id", storeID,
+			"lore_id", id,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	slog.Info("confidence lock cleared",
+		"component", "api",
+		"action", "clear_lock",
+		"store_id", storeID,
+		"lore_id", id,
+		"request_id", GetRequestID(r.Context()),
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
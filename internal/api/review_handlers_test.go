@@ -0,0 +1,255 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func serveReview(t *testing.T, s *mockStore, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := NewRouter(NewHandler(s, nil, &mockEmbedder{}, nil, "test-key", "1.0.0"), nil)
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestReviewQueue_Defaults(t *testing.T) {
+	s := &mockStore{reviewQueue: []types.ReviewCandidate{
+		{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Confidence: 0.2, IncorrectCount: 3, Reasons: []string{"low_confidence", "flagged_incorrect"}},
+	}}
+
+	w := serveReview(t, s, http.MethodGet, "/api/v1/lore/review", "")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp types.ReviewQueueResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Entries) != 1 || resp.Entries[0].IncorrectCount != 3 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	// One more than the limit is fetched to learn whether more follow
+	want := types.ReviewQueueQuery{
+		MaxConfidence: store.DefaultReviewMaxConfidence,
+		MinIncorrect:  store.DefaultReviewMinIncorrect,
+		Limit:         store.DefaultReviewQueueLimit + 1,
+	}
+	if *s.lastReviewQuery != want {
+		t.Errorf("query = %+v, want %+v", *s.lastReviewQuery, want)
+	}
+}
+
+func TestReviewQueue_Parameters(t *testing.T) {
+	s := &mockStore{}
+	w := serveReview(t, s, http.MethodGet, "/api/v1/lore/review?max_confidence=0.5&min_incorrect=4&language=fr&limit=10", "")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	want := types.ReviewQueueQuery{MaxConfidence: 0.5, MinIncorrect: 4, Language: "fr", Limit: 11}
+	if *s.lastReviewQuery != want {
+		t.Errorf("query = %+v, want %+v", *s.lastReviewQuery, want)
+	}
+	if !strings.Contains(w.Body.String(), `"entries":[]`) {
+		t.Errorf("expected empty entries array, got %s", w.Body.String())
+	}
+}
+
+func TestReviewQueue_Pagination(t *testing.T) {
+	s := &mockStore{reviewQueue: []types.ReviewCandidate{
+		{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Confidence: 0.2, IncorrectCount: 3},
+		{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAW", Confidence: 0.1, IncorrectCount: 0},
+	}}
+	w := serveReview(t, s, http.MethodGet, "/api/v1/lore/review?limit=1", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp types.ReviewQueueResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Entries) != 1 || !resp.HasMore || resp.NextCursor == "" {
+		t.Fatalf("first page = %+v, want one entry and a cursor", resp)
+	}
+
+	w = serveReview(t, s, http.MethodGet, "/api/v1/lore/review?limit=1&cursor="+resp.NextCursor, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	want := types.ReviewQueueKey{IncorrectCount: 3, Confidence: 0.2, ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV"}
+	if after := s.lastReviewQuery.After; after == nil || *after != want {
+		t.Errorf("After = %+v, want %+v", after, want)
+	}
+}
+
+func TestReviewQueue_InvalidParameters(t *testing.T) {
+	for _, query := range []string{
+		"?max_confidence=1.5", "?max_confidence=low", "?min_incorrect=0", "?limit=0", "?limit=501", "?language=FR",
+		"?cursor=not-a-cursor",
+	} {
+		s := &mockStore{}
+		w := serveReview(t, s, http.MethodGet, "/api/v1/lore/review"+query, "")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, w.Code)
+		}
+		if s.lastReviewQuery != nil {
+			t.Errorf("%s: store should not be queried", query)
+		}
+	}
+}
+
+func TestApproveLore(t *testing.T) {
+	s := &mockStore{}
+	w := serveReview(t, s, http.MethodPost, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/approve",
+		`{"reviewer": "alice", "note": "matches the runbook"}`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if s.lastReviewStatus != types.ReviewApproved {
+		t.Errorf("status = %q, want approved", s.lastReviewStatus)
+	}
+	var review types.LoreReview
+	if err := json.NewDecoder(w.Body).Decode(&review); err != nil {
+		t.Fatal(err)
+	}
+	if review.Reviewer != "alice" || review.Note != "matches the runbook" {
+		t.Errorf("review = %+v", review)
+	}
+}
+
+func TestRejectLore(t *testing.T) {
+	s := &mockStore{}
+	w := serveReview(t, s, http.MethodPost, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/reject", `{"reviewer": "bob"}`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if s.lastReviewStatus != types.ReviewRejected {
+		t.Errorf("status = %q, want rejected", s.lastReviewStatus)
+	}
+}
+
+func TestReviewLore_Errors(t *testing.T) {
+	tests := []struct {
+		name, path, body string
+		err              error
+		want             int
+	}{
+		{"invalid id", "/api/v1/lore/bad/approve", `{"reviewer": "alice"}`, nil, http.StatusBadRequest},
+		{"invalid JSON", "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/approve", `{`, nil, http.StatusBadRequest},
+		{"missing reviewer", "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/reject", `{}`, nil, http.StatusUnprocessableEntity},
+		{"not found", "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/approve", `{"reviewer": "alice"}`, store.ErrNotFound, http.StatusNotFound},
+		{"already reviewed", "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/reject", `{"reviewer": "alice"}`, store.ErrAlreadyReviewed, http.StatusConflict},
+		{"store error", "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/approve", `{"reviewer": "alice"}`, errors.New("disk I/O error"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveReview(t, &mockStore{reviewErr: tt.err}, http.MethodPost, tt.path, tt.body)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetReview(t *testing.T) {
+	w := serveReview(t, &mockStore{}, http.MethodGet, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/review", "")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var review types.LoreReview
+	if err := json.NewDecoder(w.Body).Decode(&review); err != nil {
+		t.Fatal(err)
+	}
+	if review.Status != types.ReviewUnreviewed {
+		t.Errorf("status = %q, want unreviewed", review.Status)
+	}
+
+	w = serveReview(t, &mockStore{reviewErr: store.ErrNotFound}, http.MethodGet, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/review", "")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing entry: status = %d, want 404", w.Code)
+	}
+}
+
+func TestSetConfidenceLock(t *testing.T) {
+	s := &mockStore{}
+	w := serveReview(t, s, http.MethodPut, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/lock",
+		`{"ceiling": 0.2, "set_by": "alice"}`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if s.lastLock == nil || s.lastLock.Floor != nil || s.lastLock.Ceiling == nil || *s.lastLock.Ceiling != 0.2 {
+		t.Errorf("store received %+v, want ceiling 0.2 and no floor", s.lastLock)
+	}
+}
+
+func TestSetConfidenceLock_Errors(t *testing.T) {
+	tests := []struct {
+		name, path, body string
+		err              error
+		want             int
+	}{
+		{"invalid id", "/api/v1/lore/bad/lock", `{"floor": 0.5, "set_by": "alice"}`, nil, http.StatusBadRequest},
+		{"invalid JSON", "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/lock", `{`, nil, http.StatusBadRequest},
+		{"no bounds", "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/lock", `{"set_by": "alice"}`, nil, http.StatusUnprocessableEntity},
+		{"inverted bounds", "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/lock", `{"floor": 0.8, "ceiling": 0.2, "set_by": "alice"}`, nil, http.StatusUnprocessableEntity},
+		{"not found", "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/lock", `{"floor": 0.5, "set_by": "alice"}`, store.ErrNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveReview(t, &mockStore{lockErr: tt.err}, http.MethodPut, tt.path, tt.body)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetConfidenceLock(t *testing.T) {
+	floor := 0.6
+	s := &mockStore{lock: &types.ConfidenceLock{LoreID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Floor: &floor, Pinned: true, Confidence: 0.7}}
+
+	w := serveReview(t, s, http.MethodGet, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/lock", "")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var lock types.ConfidenceLock
+	if err := json.NewDecoder(w.Body).Decode(&lock); err != nil {
+		t.Fatal(err)
+	}
+	if !lock.Pinned || lock.Floor == nil || *lock.Floor != 0.6 {
+		t.Errorf("lock = %+v", lock)
+	}
+}
+
+func TestClearConfidenceLock(t *testing.T) {
+	s := &mockStore{}
+	w := serveReview(t, s, http.MethodDelete, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/lock", "")
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", w.Code)
+	}
+	if !s.lockCleared {
+		t.Error("expected the lock to be cleared")
+	}
+
+	w = serveReview(t, &mockStore{lockErr: store.ErrNotFound}, http.MethodDelete, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV/lock", "")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing entry: status = %d, want 404", w.Code)
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -15,18 +16,75 @@ func NewRouter(h *Handler, mgr StoreGetter) *chi.Mux {
 
 	// Global middleware (all routes)
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
-	r.Use(LoggingMiddleware)
+	r.Use(RealIPMiddleware(h.trustedProxies))
+	r.Use(AccessLogMiddleware(h.accessSampling))
+	r.Use(h.latency.Middleware)
 	r.Use(middleware.Recoverer)
 
 	// Rate limiter for DELETE operations: 100 deletes max, refill 1 per 100ms
 	// This allows burst of 100 deletes, then sustained rate of 10/second
 	deleteRateLimiter := NewDeleteRateLimiter(100, 100*time.Millisecond)
 
+	// Profiling is for operators, so it takes the admin key, not the client key
+	if h.pprofKey != "" {
+		r.Route("/debug", func(r chi.Router) {
+			r.Use(h.lockout.Middleware)
+			r.Use(AuthMiddlewareFunc(keySource(h.pprofKey, h.adminKeySource)))
+			r.Mount("/", middleware.Profiler())
+		})
+	}
+
 	r.Route("/api/v1", func(r chi.Router) {
 		// Public routes (no auth required per NFR8)
 		r.Get("/health", h.Health)
 		r.Get("/stats", h.Stats)
+		r.Get("/stats/latency", h.LatencyStats)
+
+		// Operator routes take the admin key, not the client key
+		if h.adminKey != "" {
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(h.lockout.Middleware)
+				r.Use(AuthMiddlewareFunc(keySource(h.adminKey, h.adminKeySource)))
+
+				r.Post("/stores/rescan", h.RescanStores)
+				r.Get("/snapshots", h.ListSnapshots)
+				r.Get("/maintenance", h.GetMaintenance)
+				r.Put("/maintenance", h.SetMaintenance)
+				r.Delete("/maintenance", h.ClearMaintenance)
+				if mgr != nil {
+					r.With(StoreContextMiddleware(mgr)).Put("/stores/{store_id}/maintenance", h.SetMaintenance)
+					r.With(StoreContextMiddleware(mgr)).Delete("/stores/{store_id}/maintenance", h.ClearMaintenance)
+					r.With(StoreContextMiddleware(mgr)).Post("/stores/{store_id}/reindex", h.ReindexStore)
+					r.With(StoreContextMiddleware(mgr)).Post("/stores/{store_id}/lore/recategorize", h.RecategorizeLore)
+					r.With(StoreContextMiddleware(mgr)).Post("/stores/{store_id}/lore/recalibrate", h.RecalibrateConfidence)
+					r.Route("/stores/{store_id}/eval-sets", func(r chi.Router) {
+						r.Use(StoreContextMiddleware(mgr))
+						r.Get("/", h.ListEvalSets)
+						r.Get("/{name}", h.GetEvalSet)
+						r.Put("/{name}", h.PutEvalSet)
+						r.Delete("/{name}", h.DeleteEvalSet)
+						r.Post("/{name}/run", h.RunEvalSet)
+					})
+				}
+				r.Route("/lore", func(r chi.Router) {
+					if mgr != nil {
+						r.Use(DefaultStoreMiddleware(mgr))
+					}
+					r.Post("/recategorize", h.RecategorizeLore)
+					r.Post("/recalibrate", h.RecalibrateConfidence)
+				})
+				r.Route("/eval-sets", func(r chi.Router) {
+					if mgr != nil {
+						r.Use(DefaultStoreMiddleware(mgr))
+					}
+					r.Get("/", h.ListEvalSets)
+					r.Get("/{name}", h.GetEvalSet)
+					r.Put("/{name}", h.PutEvalSet)
+					r.Delete("/{name}", h.DeleteEvalSet)
+					r.Post("/{name}/run", h.RunEvalSet)
+				})
+			})
+		}
 
 		// Store-scoped public stats (no auth required)
 		if mgr != nil {
@@ -35,33 +93,99 @@ func NewRouter(h *Handler, mgr StoreGetter) *chi.Mux {
 
 		// Protected routes (auth required)
 		r.Group(func(r chi.Router) {
-			r.Use(AuthMiddleware(h.apiKey))
+			r.Use(h.lockout.Middleware)
+			r.Use(AuthMiddlewareFunc(keySource(h.apiKey, h.apiKeySource)))
+			r.Use(h.shedder.Middleware)
+
+			// Routes that write take guard, so maintenance mode and store
+			// locks refuse them while reads continue
+			guard := func(next http.Handler) http.Handler {
+				return h.maintenance.Guard(h.storeLocks.Guard(next))
+			}
 
 			// Store management routes
-			r.Get("/stores", h.ListStores)
-			r.Post("/stores", h.CreateStore)
+			r.With(CompressionMiddleware).Get("/stores", h.ListStores)
+			r.With(guard).Post("/stores", h.CreateStore)
 			r.Get("/stores/{store_id}", h.GetStoreInfo)
-			r.Delete("/stores/{store_id}", h.DeleteStore)
+			r.With(guard).Delete("/stores/{store_id}", h.DeleteStore)
+			r.With(guard).Post("/stores/{store_id}/clone", h.CloneStore)
+			r.With(guard).Post("/stores/{store_id}/archive", h.ArchiveStore)
+			r.With(guard).Post("/stores/{store_id}/thaw", h.ThawStore)
+			r.Post("/stores/{store_id}/lock", h.LockStore)
+			r.Post("/stores/{store_id}/unlock", h.UnlockStore)
+
+			// Schema versions supported per store type
+			r.Get("/plugins/schemas", h.PluginSchemas)
+			r.Get("/plugins/{type}/payload-schemas", h.PluginPayloadSchemas)
 
 			// Store-scoped lore routes (NEW for Story 7.3)
 			if mgr != nil {
 				r.Route("/stores/{store_id}/lore", func(r chi.Router) {
 					r.Use(StoreContextMiddleware(mgr))
 
-					r.Post("/", h.IngestLore)
+					r.With(guard, h.shedder.Ingest, DecompressionMiddleware).Post("/", h.IngestLore)
 					r.Get("/snapshot", h.Snapshot)
-					r.Get("/delta", h.Delta)
-					r.Post("/feedback", h.Feedback)
-					r.With(deleteRateLimiter.Middleware).Delete("/{id}", h.DeleteLore)
+					r.Get("/count", h.CountLore)
+					r.With(CompressionMiddleware).Get("/delta", h.Delta)
+					r.With(CompressionMiddleware).Get("/search", h.SearchLore)
+					r.Post("/summarize", h.SummarizeLore)
+					r.Post("/recall", h.Recall)
+					r.Get("/consolidations", h.Consolidations)
+					r.Get("/contradictions", h.Contradictions)
+					r.With(guard).Post("/feedback", h.Feedback)
+					r.With(guard).Post("/feedback/bulk", h.BulkFeedback)
+					r.Get("/{id}/feedback", h.FeedbackHistory)
+					r.Get("/review", h.ReviewQueue)
+					r.Get("/{id}/review", h.GetReview)
+					r.With(guard).Post("/{id}/approve", h.ApproveLore)
+					r.With(guard).Post("/{id}/reject", h.RejectLore)
+					r.Get("/{id}/lock", h.GetConfidenceLock)
+					r.With(guard).Put("/{id}/lock", h.SetConfidenceLock)
+					r.With(guard).Delete("/{id}/lock", h.ClearConfidenceLock)
+					r.Get("/{id}/versions", h.LoreVersions)
+					r.With(guard).Post("/{id}/versions/{version}/revert", h.RevertLore)
+					r.Get("/{id}", h.GetLore)
+					r.With(guard).Patch("/{id}", h.UpdateLore)
+					r.With(guard, deleteRateLimiter.Middleware).Delete("/{id}", h.DeleteLore)
 				})
 
 				// Store-scoped sync routes (Story 8.5+)
 				r.Route("/stores/{store_id}/sync", func(r chi.Router) {
 					r.Use(StoreContextMiddleware(mgr))
 
-					r.Post("/push", h.SyncPush)
-					r.Get("/delta", h.SyncDelta)
+					// Signatures cover the body as sent, so they are verified
+					// before decompression
+					signed := h.signing.Verify(h.maxPushBytes)
+
+					r.With(guard, h.shedder.Ingest, signed, DecompressionMiddleware).Post("/push", h.SyncPush)
+					r.With(guard, signed).Post("/push-sessions", h.BeginPushSession)
+					r.With(guard, h.shedder.Ingest, signed, DecompressionMiddleware).Post("/push-sessions/{push_id}/entries", h.AppendPushSession)
+					r.With(guard, signed).Post("/push-sessions/{push_id}/commit", h.CommitPushSession)
+					r.With(guard, signed).Delete("/push-sessions/{push_id}", h.AbortPushSession)
+					r.With(CompressionMiddleware).Get("/delta", h.SyncDelta)
+					r.With(guard, h.shedder.Ingest, signed, DecompressionMiddleware, CompressionMiddleware).Post("/exchange", h.SyncExchange)
 					r.Get("/snapshot", h.SyncSnapshot)
+					r.Get("/schema", h.SyncSchema)
+					r.Get("/verify", h.SyncVerify)
+					r.Get("/conflicts", h.ListSyncConflicts)
+					r.Get("/conflicts/{conflict_id}", h.GetSyncConflict)
+					r.With(guard).Post("/conflicts/{conflict_id}/resolve", h.ResolveSyncConflict)
+				})
+
+				// Client metadata kept per store
+				r.Route("/stores/{store_id}/meta", func(r chi.Router) {
+					r.Use(StoreContextMiddleware(mgr))
+
+					r.Get("/{key}", h.GetStoreMeta)
+					r.With(guard).Put("/{key}", h.PutStoreMeta)
+					r.With(guard).Delete("/{key}", h.DeleteStoreMeta)
+				})
+
+				// Store-scoped tract read models
+				r.Route("/stores/{store_id}/tract", func(r chi.Router) {
+					r.Use(StoreContextMiddleware(mgr))
+
+					r.Get("/goals/{goal_id}/summary", h.TractGoalSummary)
 				})
 			}
 
@@ -72,12 +196,31 @@ func NewRouter(h *Handler, mgr StoreGetter) *chi.Mux {
 					r.Use(DefaultStoreMiddleware(mgr))
 				}
 
-				r.Post("/", h.IngestLore)
+				r.With(guard, h.shedder.Ingest, DecompressionMiddleware).Post("/", h.IngestLore)
 				r.Get("/snapshot", h.Snapshot)
-				r.Get("/delta", h.Delta)
-				r.Post("/feedback", h.Feedback)
+				r.Get("/count", h.CountLore)
+				r.With(CompressionMiddleware).Get("/delta", h.Delta)
+				r.With(CompressionMiddleware).Get("/search", h.SearchLore)
+				r.Post("/summarize", h.SummarizeLore)
+				r.Post("/recall", h.Recall)
+				r.Get("/consolidations", h.Consolidations)
+				r.Get("/contradictions", h.Contradictions)
+				r.With(guard).Post("/feedback", h.Feedback)
+				r.With(guard).Post("/feedback/bulk", h.BulkFeedback)
+				r.Get("/{id}/feedback", h.FeedbackHistory)
+				r.Get("/review", h.ReviewQueue)
+				r.Get("/{id}/review", h.GetReview)
+				r.With(guard).Post("/{id}/approve", h.ApproveLore)
+				r.With(guard).Post("/{id}/reject", h.RejectLore)
+				r.Get("/{id}/lock", h.GetConfidenceLock)
+				r.With(guard).Put("/{id}/lock", h.SetConfidenceLock)
+				r.With(guard).Delete("/{id}/lock", h.ClearConfidenceLock)
+				r.Get("/{id}/versions", h.LoreVersions)
+				r.With(guard).Post("/{id}/versions/{version}/revert", h.RevertLore)
+				r.Get("/{id}", h.GetLore)
+				r.With(guard).Patch("/{id}", h.UpdateLore)
 				// DELETE has additional rate limiting to prevent abuse
-				r.With(deleteRateLimiter.Middleware).Delete("/{id}", h.DeleteLore)
+				r.With(guard, deleteRateLimiter.Middleware).Delete("/{id}", h.DeleteLore)
 			})
 		})
 	})
@@ -0,0 +1,295 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/validation"
+)
+
+// SearchLore handles GET /api/v1/lore/search and GET /api/v1/stores/{store_id}/lore/search
+//
+// Query parameters: q (required), mode (keyword, semantic or hybrid; default
+// hybrid), category, language, and limit (1-100; default 10). repo and path
+// restrict results to lore recorded for that repository and for that path or
+// a directory above it; lore recorded without them always matches.
+// min_per_category and max_per_category bound the results per category, as
+// comma-separated CATEGORY:N pairs. Hybrid searches also take fusion,
+// keyword_weight, semantic_weight and rrf_k, each defaulting to the server's
+// configured ranking. Relevance is scaled by the configured quality
+// boost unless boost=false. When the query cannot be embedded, hybrid
+// searches fall back to keyword ranking and report mode "keyword"; semantic
+// searches fail with 503.
+func (h *Handler) SearchLore(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	storeID := StoreIDFromContext(r.Context())
+	s := h.getStoreForRequest(r)
+	params := r.URL.Query()
+
+	text := strings.TrimSpace(params.Get("q"))
+	if text == "" {
+		WriteProblem(w, r, http.StatusBadRequest, "Missing required query parameter: q")
+		return
+	}
+	if utf8.RuneCountInString(text) > validation.MaxContentLength {
+		WriteProblem(w, r, http.StatusBadRequest,
+			fmt.Sprintf("Query exceeds maximum length of %d characters", validation.MaxContentLength))
+		return
+	}
+
+	mode := types.SearchMode(params.Get("mode"))
+	switch mode {
+	case "":
+		mode = types.SearchModeHybrid
+	case types.SearchModeKeyword, types.SearchModeSemantic, types.SearchModeHybrid:
+	default:
+		WriteProblem(w, r, http.StatusBadRequest,
+			fmt.Sprintf("Invalid mode %q: must be keyword, semantic, or hybrid", mode))
+		return
+	}
+
+	category := params.Get("category")
+	if category != "" {
+		if verr := validation.ValidateEnum("category", category, validation.ValidLoreCategories); verr != nil {
+			WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid category: %s", verr.Message))
+			return
+		}
+	}
+
+	lang := params.Get("language")
+	if lang != "" {
+		if verr := validation.ValidateLanguage("language", lang); verr != nil {
+			WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid language: %s", verr.Message))
+			return
+		}
+	}
+
+	repo := params.Get("repo")
+	if repo != "" {
+		if verr := validation.ValidateRepo("repo", repo); verr != nil {
+			WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid repo: %s", verr.Message))
+			return
+		}
+	}
+
+	scope := scopePath(params.Get("path"))
+	if scope != "" {
+		if verr := validation.ValidateScopePath("path", scope); verr != nil {
+			WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid path: %s", verr.Message))
+			return
+		}
+	}
+
+	limit := store.DefaultSearchLimit
+	if raw := params.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > store.MaxSearchLimit {
+			WriteProblem(w, r, http.StatusBadRequest,
+				fmt.Sprintf("Invalid limit: must be an integer between 1 and %d", store.MaxSearchLimit))
+			return
+		}
+		limit = n
+	}
+
+	ranking, err := h.parseSearchRanking(params)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid ranking: %s", err))
+		return
+	}
+
+	quotas, err := parseCategoryQuotas(params, limit)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid category quotas: %s", err))
+		return
+	}
+
+	query := types.SearchQuery{
+		Text:     text,
+		Mode:     mode,
+		Ranking:  ranking,
+		Category: category,
+		Language: lang,
+		Repo:     repo,
+		Path:     scope,
+		Limit:    limit,
+		Quotas:   quotas,
+	}
+	if v := params.Get("boost"); v == "" {
+		query.Boost = h.searchBoost
+	} else if boost, err := strconv.ParseBool(v); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "Invalid boost: must be true or false")
+		return
+	} else if boost {
+		query.Boost = h.searchBoost
+	}
+	if mode != types.SearchModeKeyword {
+		embedder := h.queryEmbedder
+		if embedder == nil {
+			embedder = h.embedder
+		}
+		vec, err := embedder.Embed(r.Context(), text)
+		if err == nil && len(vec) == 0 {
+			err = store.ErrEmbeddingUnavailable
+		}
+		switch {
+		case err == nil:
+			query.Embedding = vec
+		case mode == types.SearchModeHybrid:
+			slog.Warn("search query embedding failed, using keyword ranking",
+				"component", "api",
+				"action", "search_degraded",
+				"store_id", storeID,
+				"error", err,
+			)
+			query.Mode = types.SearchModeKeyword
+		default:
+			slog.Error("search query embedding failed",
+				"component", "api",
+				"action", "search_failed",
+				"store_id", storeID,
+				"error", err,
+			)
+			MapStoreError(w, r, fmt.Errorf("embed query: %v: %w", err, store.ErrEmbeddingUnavailable))
+			return
+		}
+	}
+
+	results, err := s.SearchLore(r.Context(), query)
+	if errors.Is(err, store.ErrDimensionMismatch) && query.Mode == types.SearchModeHybrid {
+		slog.Warn("search query embedding does not match the store, using keyword ranking",
+			"component", "api",
+			"action", "search_degraded",
+			"store_id", storeID,
+			"error", err,
+		)
+		query.Mode = types.SearchModeKeyword
+		query.Embedding = nil
+		results, err = s.SearchLore(r.Context(), query)
+	}
+	if err != nil {
+		slog.Error("search failed",
+			"component", "api",
+			"action", "search_failed",
+			"store_id", storeID,
+			"mode", string(query.Mode),
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+	if results == nil {
+		results = []types.SearchResult{}
+	}
+
+	slog.Debug("lore searched",
+		"component", "api",
+		"action", "search",
+		"store_id", storeID,
+		"mode", string(query.Mode),
+		"result_count", len(results),
+	)
+
+	resp := types.SearchResponse{
+		Query:   text,
+		Mode:    query.Mode,
+		Results: results,
+	}
+	if query.Mode == types.SearchModeHybrid {
+		resp.Ranking = &query.Ranking
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// parseSearchRanking applies per-request ranking overrides to the handler's
+// default ranking.
+func (h *Handler) parseSearchRanking(params url.Values) (types.SearchRanking, error) {
+	ranking := h.searchRanking
+	if ranking == (types.SearchRanking{}) {
+		ranking = store.DefaultSearchRanking
+	}
+
+	if v := params.Get("fusion"); v != "" {
+		ranking.Fusion = types.SearchFusion(v)
+	}
+	for _, p := range []struct {
+		name string
+		dst  *float64
+	}{
+		{"keyword_weight", &ranking.KeywordWeight},
+		{"semantic_weight", &ranking.SemanticWeight},
+	} {
+		if v := params.Get(p.name); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+				return ranking, fmt.Errorf("%s must be a number", p.name)
+			}
+			*p.dst = f
+		}
+	}
+	if v := params.Get("rrf_k"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return ranking, errors.New("rrf_k must be an integer")
+		}
+		ranking.RRFK = n
+	}
+
+	return ranking, ranking.Validate()
+}
+
+// scopePath trims surrounding whitespace and slashes from a path within a
+// repository, so "/internal/api/" and "internal/api" name the same scope.
+func scopePath(p string) string {
+	return strings.Trim(strings.TrimSpace(p), "/")
+}
+
+// parseCategoryQuotas reads the min_per_category and max_per_category
+// parameters, each a comma-separated list of CATEGORY:N pairs. Returns nil
+// when neither is set.
+func parseCategoryQuotas(params url.Values, limit int) (types.CategoryQuotas, error) {
+	var quotas types.CategoryQuotas
+	for _, p := range []struct {
+		name string
+		set  func(q *types.CategoryQuota, n int)
+	}{
+		{"min_per_category", func(q *types.CategoryQuota, n int) { q.Min = n }},
+		{"max_per_category", func(q *types.CategoryQuota, n int) { q.Max = n }},
+	} {
+		v := params.Get(p.name)
+		if v == "" {
+			continue
+		}
+		for _, pair := range strings.Split(v, ",") {
+			category, count, ok := strings.Cut(strings.TrimSpace(pair), ":")
+			n, err := strconv.Atoi(count)
+			if !ok || err != nil || n < 1 {
+				return nil, fmt.Errorf("%s: %q must be CATEGORY:N with N at least 1", p.name, pair)
+			}
+			if verr := validation.ValidateEnum(p.name, category, validation.ValidLoreCategories); verr != nil {
+				return nil, fmt.Errorf("%s: %s", p.name, verr.Message)
+			}
+			if quotas == nil {
+				quotas = make(types.CategoryQuotas)
+			}
+			q := quotas[category]
+			p.set(&q, n)
+			quotas[category] = q
+		}
+	}
+	return quotas, quotas.Validate(limit)
+}
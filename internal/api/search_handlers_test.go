@@ -0,0 +1,337 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func serveSearch(t *testing.T, s *mockStore, e *mockEmbedder, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := NewRouter(NewHandler(s, nil, e, nil, "test-key", "1.0.0"), nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lore/search"+query, nil)
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSearchLore_HybridByDefault(t *testing.T) {
+	s := &mockStore{searchResults: []types.SearchResult{
+		{Lore: types.LoreEntry{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Content: "Retry with backoff"}, Score: 0.9},
+	}}
+	e := &mockEmbedder{vector: []float32{1, 0}}
+
+	w := serveSearch(t, s, e, "?q=retry+backoff")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp types.SearchResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Mode != types.SearchModeHybrid || resp.Query != "retry backoff" || len(resp.Results) != 1 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if resp.Results[0].Score != 0.9 {
+		t.Errorf("score = %v, want 0.9", resp.Results[0].Score)
+	}
+	q := s.lastSearch
+	if q.Mode != types.SearchModeHybrid || len(q.Embedding) != 2 || q.Limit != 10 {
+		t.Errorf("store query = %+v", q)
+	}
+	if resp.Ranking == nil || *resp.Ranking != store.DefaultSearchRanking {
+		t.Errorf("ranking = %+v, want server default", resp.Ranking)
+	}
+}
+
+func TestSearchLore_RankingOverrides(t *testing.T) {
+	s := &mockStore{}
+	e := &mockEmbedder{vector: []float32{1, 0}}
+	h := NewHandler(s, nil, e, nil, "test-key", "1.0.0", WithSearchRanking(types.SearchRanking{
+		Fusion: types.SearchFusionRRF, KeywordWeight: 1, SemanticWeight: 1, RRFK: 30,
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lore/search?q=retry&fusion=weighted&keyword_weight=0.2&semantic_weight=0.8", nil)
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+	NewRouter(h, nil).ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	want := types.SearchRanking{Fusion: types.SearchFusionWeighted, KeywordWeight: 0.2, SemanticWeight: 0.8, RRFK: 30}
+	if s.lastSearch.Ranking != want {
+		t.Errorf("ranking = %+v, want %+v", s.lastSearch.Ranking, want)
+	}
+}
+
+func TestSearchLore_CategoryQuotas(t *testing.T) {
+	s := &mockStore{}
+	w := serveSearch(t, s, &mockEmbedder{vector: []float32{1, 0}},
+		"?q=retry&min_per_category=ARCHITECTURAL_DECISION:1&max_per_category=PATTERN_OUTCOME:3,ARCHITECTURAL_DECISION:2")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	want := types.CategoryQuotas{
+		"ARCHITECTURAL_DECISION": {Min: 1, Max: 2},
+		"PATTERN_OUTCOME":        {Max: 3},
+	}
+	if !reflect.DeepEqual(s.lastSearch.Quotas, want) {
+		t.Errorf("quotas = %+v, want %+v", s.lastSearch.Quotas, want)
+	}
+}
+
+func TestSearchLore_RepoAndPath(t *testing.T) {
+	s := &mockStore{}
+	w := serveSearch(t, s, &mockEmbedder{vector: []float32{1, 0}}, "?q=retry&repo=github.com/acme/payments&path=/internal/queue/")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if s.lastSearch.Repo != "github.com/acme/payments" || s.lastSearch.Path != "internal/queue" {
+		t.Errorf("repo, path = %q, %q", s.lastSearch.Repo, s.lastSearch.Path)
+	}
+}
+
+func TestSearchLore_BoostParameter(t *testing.T) {
+	for query, want := range map[string]types.SearchBoost{
+		"?q=x":             store.DefaultSearchBoost,
+		"?q=x&boost=true":  store.DefaultSearchBoost,
+		"?q=x&boost=false": {},
+	} {
+		s := &mockStore{}
+		w := serveSearch(t, s, &mockEmbedder{vector: []float32{1}}, query)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d", query, w.Code)
+		}
+		if s.lastSearch.Boost != want {
+			t.Errorf("%s: boost = %+v, want %+v", query, s.lastSearch.Boost, want)
+		}
+	}
+}
+
+func TestSearchLore_UsesQueryEmbedder(t *testing.T) {
+	s := &mockStore{}
+	h := NewHandler(s, nil, &mockEmbedder{err: errors.New("not this one")}, nil, "test-key", "1.0.0",
+		WithQueryEmbedder(&mockEmbedder{vector: []float32{0.5, 0.5}}))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lore/search?q=retry&mode=semantic", nil)
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+	NewRouter(h, nil).ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if len(s.lastSearch.Embedding) != 2 {
+		t.Errorf("embedding = %v, want the query embedder's vector", s.lastSearch.Embedding)
+	}
+}
+
+func TestSearchLore_KeywordOmitsRanking(t *testing.T) {
+	w := serveSearch(t, &mockStore{}, &mockEmbedder{}, "?q=retry&mode=keyword")
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(w.Body).Decode(&raw); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := raw["ranking"]; ok {
+		t.Error("keyword search response should not include ranking")
+	}
+}
+
+func TestSearchLore_SignalScoresInResponse(t *testing.T) {
+	kw, sem := 0.0, 0.8
+	s := &mockStore{searchResults: []types.SearchResult{{
+		Lore:          types.LoreEntry{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV"},
+		Score:         0.4,
+		KeywordScore:  &kw,
+		SemanticScore: &sem,
+		SemanticRank:  1,
+	}}}
+
+	w := serveSearch(t, s, &mockEmbedder{vector: []float32{1}}, "?q=retry")
+
+	var resp struct {
+		Results []map[string]any `json:"results"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	got := resp.Results[0]
+	if got["keyword_score"] != 0.0 || got["semantic_score"] != 0.8 || got["semantic_rank"] != 1.0 {
+		t.Errorf("signal fields = %v", got)
+	}
+	if _, ok := got["keyword_rank"]; ok {
+		t.Error("keyword_rank should be omitted for an entry keyword search did not match")
+	}
+}
+
+func TestSearchLore_KeywordSkipsEmbedding(t *testing.T) {
+	s := &mockStore{}
+	e := &mockEmbedder{err: errors.New("embedder down")}
+
+	w := serveSearch(t, s, e, "?q=ERR_CONN_RESET&mode=keyword&category=DEPENDENCY_BEHAVIOR&language=de&limit=5")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	q := s.lastSearch
+	if q.Mode != types.SearchModeKeyword || q.Embedding != nil || q.Category != "DEPENDENCY_BEHAVIOR" || q.Language != "de" || q.Limit != 5 {
+		t.Errorf("store query = %+v", q)
+	}
+	var resp types.SearchResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Results == nil {
+		t.Error("expected empty results array, got null")
+	}
+}
+
+func TestSearchLore_HybridFallsBackToKeyword(t *testing.T) {
+	s := &mockStore{}
+	e := &mockEmbedder{err: errors.New("embedder down")}
+
+	w := serveSearch(t, s, e, "?q=retry")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp types.SearchResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Mode != types.SearchModeKeyword || s.lastSearch.Mode != types.SearchModeKeyword {
+		t.Errorf("mode = %q (store %q), want keyword", resp.Mode, s.lastSearch.Mode)
+	}
+}
+
+func TestSearchLore_SemanticRequiresEmbedder(t *testing.T) {
+	s := &mockStore{}
+	e := &mockEmbedder{err: errors.New("embedder down")}
+
+	w := serveSearch(t, s, e, "?q=retry&mode=semantic")
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", w.Code)
+	}
+	if s.lastSearch != nil {
+		t.Error("store should not be searched without a query embedding")
+	}
+}
+
+func TestSearchLore_InvalidParameters(t *testing.T) {
+	tests := []struct {
+		name, query string
+	}{
+		{"missing q", ""},
+		{"blank q", "?q=++"},
+		{"bad mode", "?q=x&mode=fuzzy"},
+		{"bad category", "?q=x&category=NOPE"},
+		{"bad language", "?q=x&language=English"},
+		{"bad repo", "?q=x&repo=acme+payments"},
+		{"bad path", "?q=x&path=internal/../secrets"},
+		{"zero limit", "?q=x&limit=0"},
+		{"large limit", "?q=x&limit=101"},
+		{"non-numeric limit", "?q=x&limit=ten"},
+		{"bad fusion", "?q=x&fusion=max"},
+		{"negative weight", "?q=x&keyword_weight=-1"},
+		{"zero weights", "?q=x&keyword_weight=0&semantic_weight=0"},
+		{"non-numeric weight", "?q=x&semantic_weight=high"},
+		{"zero rrf_k", "?q=x&fusion=rrf&rrf_k=0"},
+		{"bad boost", "?q=x&boost=maybe"},
+		{"malformed quota", "?q=x&max_per_category=PATTERN_OUTCOME"},
+		{"zero quota", "?q=x&max_per_category=PATTERN_OUTCOME:0"},
+		{"quota for unknown category", "?q=x&min_per_category=NOPE:1"},
+		{"min above max", "?q=x&min_per_category=PATTERN_OUTCOME:3&max_per_category=PATTERN_OUTCOME:2"},
+		{"minimums above limit", "?q=x&limit=1&min_per_category=PATTERN_OUTCOME:1,TESTING_STRATEGY:1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &mockStore{}
+			w := serveSearch(t, s, &mockEmbedder{vector: []float32{1}}, tt.query)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", w.Code)
+			}
+			if s.lastSearch != nil {
+				t.Error("store should not be searched for an invalid request")
+			}
+		})
+	}
+}
+
+func TestSearchLore_StoreError(t *testing.T) {
+	s := &mockStore{searchErr: errors.New("disk I/O error")}
+
+	w := serveSearch(t, s, &mockEmbedder{}, "?q=x&mode=keyword")
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", w.Code)
+	}
+}
+
+func TestSearchLore_DimensionMismatch(t *testing.T) {
+	s, err := store.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+	if _, err := s.IngestLore(ctx, []types.NewLoreEntry{{
+		Content: "Retry with backoff", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "src",
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := s.GetPendingEmbeddings(ctx, 1)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("GetPendingEmbeddings() = %v, %v", pending, err)
+	}
+	if err := s.UpdateEmbedding(ctx, pending[0].ID, []float32{1, 0, 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Queries are embedded by a model with smaller vectors than the store's
+	router := NewRouter(NewHandler(s, nil, &mockEmbedder{model: "switched", vector: []float32{1, 0}}, nil, "test-key", "1.0.0"), nil)
+	serve := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer test-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("/api/v1/lore/search?q=retry")
+	if w.Code != http.StatusOK {
+		t.Fatalf("hybrid status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp types.SearchResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Mode != types.SearchModeKeyword || len(resp.Results) != 1 {
+		t.Errorf("hybrid search = mode %q with %d results, want keyword fallback with 1", resp.Mode, len(resp.Results))
+	}
+
+	if w := serve("/api/v1/lore/search?q=retry&mode=semantic"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("semantic status = %d, want 503", w.Code)
+	}
+
+	w = serve("/api/v1/health")
+	var health types.HealthResponse
+	if err := json.NewDecoder(w.Body).Decode(&health); err != nil {
+		t.Fatal(err)
+	}
+	want := types.EmbeddingDimensions{Expected: 3, Mismatches: 2, LastSeen: 2}
+	if health.Status != "degraded" || health.EmbeddingDimensions == nil || *health.EmbeddingDimensions != want {
+		t.Errorf("health = %q with dimensions %+v, want degraded with %+v", health.Status, health.EmbeddingDimensions, want)
+	}
+}
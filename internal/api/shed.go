@@ -0,0 +1,198 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// queueDepthRefresh is how long a sampled embedding queue depth is reused
+// before ingest requests sample it again.
+const queueDepthRefresh = 5 * time.Second
+
+// ShedConfig sets the pressure thresholds at which requests are refused with
+// 503. A zero threshold disables that check.
+type ShedConfig struct {
+	// MaxInFlight sheds any authenticated request once this many are in flight.
+	MaxInFlight int
+	// IngestMaxInFlight sheds ingest at a lower in-flight count than
+	// MaxInFlight, keeping the remaining capacity for reads.
+	IngestMaxInFlight int
+	// MaxBusyErrors sheds ingest once SQLite has refused this many
+	// statements as busy within BusyWindow.
+	MaxBusyErrors int
+	BusyWindow    time.Duration
+	// MaxEmbeddingQueue sheds ingest while more entries than this await
+	// embedding.
+	MaxEmbeddingQueue int64
+	// RetryAfter is sent in the Retry-After header of shed responses.
+	RetryAfter time.Duration
+}
+
+// LoadShedder refuses requests with 503 and Retry-After when the server is
+// under pressure, so overload produces fast retryable failures instead of
+// every request timing out. Ingest is shed first: it is refused on lower
+// in-flight counts, on SQLite lock contention and on embedding backlog,
+// none of which slow reads down.
+type LoadShedder struct {
+	cfg        ShedConfig
+	queueDepth func(ctx context.Context) (int64, error)
+	inFlight   atomic.Int64
+
+	busyMu sync.Mutex
+	busy   []time.Time
+
+	depthMu sync.Mutex
+	depthAt time.Time
+	depth   atomic.Int64
+}
+
+// NewLoadShedder creates a load shedder. queueDepth reports how many entries
+// await embedding across all stores; it may be nil to skip that check.
+func NewLoadShedder(cfg ShedConfig, queueDepth func(ctx context.Context) (int64, error)) *LoadShedder {
+	return &LoadShedder{cfg: cfg, queueDepth: queueDepth}
+}
+
+type shedderKey struct{}
+
+// Middleware counts in-flight requests and sheds any request beyond
+// MaxInFlight. A nil shedder sheds nothing.
+func (s *LoadShedder) Middleware(next http.Handler) http.Handler {
+	if s == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+
+		if s.cfg.MaxInFlight > 0 && n > int64(s.cfg.MaxInFlight) {
+			s.shed(w, r, "in_flight")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), shedderKey{}, s)))
+	})
+}
+
+// Ingest sheds ingest requests under any pressure signal. It must run inside
+// Middleware so the in-flight count includes the request itself.
+func (s *LoadShedder) Ingest(next http.Handler) http.Handler {
+	if s == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if reason := s.ingestPressure(r.Context()); reason != "" {
+			s.shed(w, r, reason)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ingestPressure returns why ingest should be shed, or "" if it shouldn't.
+func (s *LoadShedder) ingestPressure(ctx context.Context) string {
+	switch {
+	case s.cfg.IngestMaxInFlight > 0 && s.inFlight.Load() > int64(s.cfg.IngestMaxInFlight):
+		return "in_flight"
+	case s.cfg.MaxBusyErrors > 0 && s.recentBusy() >= s.cfg.MaxBusyErrors:
+		return "sqlite_busy"
+	case s.cfg.MaxEmbeddingQueue > 0 && s.embeddingQueueDepth(ctx) > s.cfg.MaxEmbeddingQueue:
+		return "embedding_queue"
+	}
+	return ""
+}
+
+// Healthy reports whether the server is free of the pressure that sheds
+// ingest, so background work such as snapshot uploads can wait it out. A
+// nil shedder is always healthy.
+func (s *LoadShedder) Healthy(ctx context.Context) bool {
+	return s == nil || s.ingestPressure(ctx) == ""
+}
+
+// RecordBusy notes that SQLite refused a statement as busy.
+func (s *LoadShedder) RecordBusy() {
+	now := time.Now()
+	s.busyMu.Lock()
+	defer s.busyMu.Unlock()
+	s.busy = append(s.pruneBusy(now), now)
+}
+
+// recentBusy returns how many busy errors fell within BusyWindow.
+func (s *LoadShedder) recentBusy() int {
+	s.busyMu.Lock()
+	defer s.busyMu.Unlock()
+	s.busy = s.pruneBusy(time.Now())
+	return len(s.busy)
+}
+
+// pruneBusy drops busy errors older than BusyWindow. Callers hold busyMu.
+func (s *LoadShedder) pruneBusy(now time.Time) []time.Time {
+	cutoff := now.Add(-s.cfg.BusyWindow)
+	i := 0
+	for i < len(s.busy) && s.busy[i].Before(cutoff) {
+		i++
+	}
+	return s.busy[i:]
+}
+
+// embeddingQueueDepth returns the last sampled embedding queue depth,
+// resampling it when stale. Only one request resamples at a time; the others
+// use the previous value rather than wait.
+func (s *LoadShedder) embeddingQueueDepth(ctx context.Context) int64 {
+	if s.queueDepth == nil || !s.depthMu.TryLock() {
+		return s.depth.Load()
+	}
+	defer s.depthMu.Unlock()
+
+	if time.Since(s.depthAt) >= queueDepthRefresh {
+		depth, err := s.queueDepth(ctx)
+		if err != nil {
+			slog.Warn("embedding queue depth unavailable",
+				"component", "api",
+				"action", "load_shed",
+				"error", err,
+			)
+		} else {
+			s.depth.Store(depth)
+		}
+		s.depthAt = time.Now()
+	}
+	return s.depth.Load()
+}
+
+// retryAfter returns the Retry-After header value in whole seconds.
+func (s *LoadShedder) retryAfter() string {
+	secs := int(s.cfg.RetryAfter.Round(time.Second) / time.Second)
+	return strconv.Itoa(max(secs, 1))
+}
+
+func (s *LoadShedder) shed(w http.ResponseWriter, r *http.Request, reason string) {
+	slog.Warn("request shed",
+		"component", "api",
+		"action", "load_shed",
+		"reason", reason,
+		"method", r.Method,
+		"path", r.URL.Path,
+		"in_flight", s.inFlight.Load(),
+		"request_id", GetRequestID(r.Context()),
+	)
+	w.Header().Set("Retry-After", s.retryAfter())
+	WriteProblem(w, r, http.StatusServiceUnavailable,
+		"Server is overloaded. Please retry after the indicated interval.")
+}
+
+// writeBusy responds 503 to a statement SQLite refused as busy and counts it
+// toward the request's load shedder, if any.
+func writeBusy(w http.ResponseWriter, r *http.Request) {
+	retryAfter := "1"
+	if s, ok := r.Context().Value(shedderKey{}).(*LoadShedder); ok {
+		s.RecordBusy()
+		retryAfter = s.retryAfter()
+	}
+	w.Header().Set("Retry-After", retryAfter)
+	WriteProblem(w, r, http.StatusServiceUnavailable,
+		"Database is busy. Please retry after the indicated interval.")
+}
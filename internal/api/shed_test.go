@@ -0,0 +1,207 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// busyError returns a real SQLite busy error by writing to a database while
+// another connection holds its write lock.
+func busyError(t *testing.T) error {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "busy.db")
+	holder, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { holder.Close() })
+	waiter, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { waiter.Close() })
+
+	tx, err := holder.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	if _, err := tx.Exec("CREATE TABLE held (x INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = waiter.Exec("CREATE TABLE blocked (x INTEGER)")
+	if err == nil {
+		t.Fatal("expected a busy error")
+	}
+	return err
+}
+
+// serveShed sends a request through s.Middleware, and through s.Ingest too
+// when ingest is set.
+func serveShed(s *LoadShedder, ingest bool, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	var h http.Handler = handler
+	if ingest {
+		h = s.Ingest(h)
+	}
+	w := httptest.NewRecorder()
+	s.Middleware(h).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/lore", nil))
+	return w
+}
+
+func okHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestLoadShedder_MaxInFlight(t *testing.T) {
+	s := NewLoadShedder(ShedConfig{MaxInFlight: 1, RetryAfter: 3 * time.Second}, nil)
+
+	var w *httptest.ResponseRecorder
+	serveShed(s, false, func(http.ResponseWriter, *http.Request) {
+		// A second request while this one is in flight exceeds the limit.
+		w = serveShed(s, false, okHandler)
+	})
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "3" {
+		t.Errorf("Retry-After = %q, want 3", got)
+	}
+	if w := serveShed(s, false, okHandler); w.Code != http.StatusOK {
+		t.Errorf("status after the first request finished = %d, want 200", w.Code)
+	}
+}
+
+func TestLoadShedder_IngestShedBeforeReads(t *testing.T) {
+	s := NewLoadShedder(ShedConfig{MaxInFlight: 3, IngestMaxInFlight: 1}, nil)
+
+	var read, ingest *httptest.ResponseRecorder
+	serveShed(s, false, func(http.ResponseWriter, *http.Request) {
+		read = serveShed(s, false, okHandler)
+		ingest = serveShed(s, true, okHandler)
+	})
+
+	if read.Code != http.StatusOK {
+		t.Errorf("read status = %d, want 200", read.Code)
+	}
+	if ingest.Code != http.StatusServiceUnavailable {
+		t.Errorf("ingest status = %d, want 503", ingest.Code)
+	}
+}
+
+func TestLoadShedder_BusyErrors(t *testing.T) {
+	s := NewLoadShedder(ShedConfig{MaxBusyErrors: 2, BusyWindow: time.Minute}, nil)
+
+	s.RecordBusy()
+	if w := serveShed(s, true, okHandler); w.Code != http.StatusOK {
+		t.Fatalf("status after one busy error = %d, want 200", w.Code)
+	}
+	s.RecordBusy()
+	if w := serveShed(s, true, okHandler); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("ingest status = %d, want 503", w.Code)
+	}
+	if w := serveShed(s, false, okHandler); w.Code != http.StatusOK {
+		t.Errorf("read status = %d, want 200 despite busy errors", w.Code)
+	}
+}
+
+func TestLoadShedder_Healthy(t *testing.T) {
+	s := NewLoadShedder(ShedConfig{MaxBusyErrors: 1, BusyWindow: time.Minute}, nil)
+	if !s.Healthy(context.Background()) {
+		t.Error("Healthy() = false before any pressure")
+	}
+	s.RecordBusy()
+	if s.Healthy(context.Background()) {
+		t.Error("Healthy() = true while ingest is shed")
+	}
+
+	var nilShedder *LoadShedder
+	if !nilShedder.Healthy(context.Background()) {
+		t.Error("a nil shedder should be healthy")
+	}
+}
+
+func TestLoadShedder_BusyErrorsExpire(t *testing.T) {
+	s := NewLoadShedder(ShedConfig{MaxBusyErrors: 1, BusyWindow: time.Millisecond}, nil)
+
+	s.RecordBusy()
+	time.Sleep(5 * time.Millisecond)
+
+	if w := serveShed(s, true, okHandler); w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 once the busy error left the window", w.Code)
+	}
+}
+
+func TestLoadShedder_EmbeddingQueue(t *testing.T) {
+	var probes atomic.Int32
+	s := NewLoadShedder(ShedConfig{MaxEmbeddingQueue: 5}, func(context.Context) (int64, error) {
+		probes.Add(1)
+		return 10, nil
+	})
+
+	for range 2 {
+		if w := serveShed(s, true, okHandler); w.Code != http.StatusServiceUnavailable {
+			t.Errorf("status = %d, want 503", w.Code)
+		}
+	}
+	if n := probes.Load(); n != 1 {
+		t.Errorf("queue depth sampled %d times, want 1 within the refresh interval", n)
+	}
+}
+
+func TestLoadShedder_Nil(t *testing.T) {
+	var s *LoadShedder
+	if w := serveShed(s, true, okHandler); w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestMapStoreError_BusyRecordedByShedder(t *testing.T) {
+	s := NewLoadShedder(ShedConfig{MaxBusyErrors: 1, BusyWindow: time.Minute, RetryAfter: 2 * time.Second}, nil)
+	err := busyError(t)
+
+	w := serveShed(s, false, func(w http.ResponseWriter, r *http.Request) {
+		MapStoreError(w, r, err)
+	})
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+	if n := s.recentBusy(); n != 1 {
+		t.Errorf("busy errors = %d, want 1", n)
+	}
+}
+
+func TestRouter_ShedsIngestOnly(t *testing.T) {
+	s := NewLoadShedder(ShedConfig{MaxBusyErrors: 1, BusyWindow: time.Minute}, nil)
+	s.RecordBusy()
+	router := NewRouter(NewHandler(&mockStore{stats: &types.StoreStats{}}, nil, &mockEmbedder{}, nil, "test-key", "1.0.0",
+		WithLoadShedder(s)), nil)
+
+	send := func(method, path, body string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer test-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := send(http.MethodPost, "/api/v1/lore", `{"source_id":"src","lore":[]}`); code != http.StatusServiceUnavailable {
+		t.Errorf("ingest status = %d, want 503", code)
+	}
+	if code := send(http.MethodGet, "/api/v1/lore/"+versionTestLoreID+"/versions", ""); code != http.StatusOK {
+		t.Errorf("read status = %d, want 200", code)
+	}
+}
@@ -0,0 +1,248 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/webhook"
+)
+
+// Headers of a signed push. The signature is "sha256=" followed by the hex
+// HMAC-SHA256 of the timestamp in Unix seconds, the method, the path and
+// the store ID, each followed by a newline, then the body; see SignPush.
+const (
+	HeaderSyncSource    = "X-Engram-Source"
+	HeaderSyncTimestamp = webhook.HeaderTimestamp
+	HeaderSyncSignature = webhook.HeaderSignature
+)
+
+// SignPush returns the signature of a push request to the given path of a
+// store, for the HeaderSyncSignature header. Covering the method, path and
+// store keeps a captured request from being replayed against another store
+// or endpoint.
+func SignPush(secret, timestamp, method, path, storeID string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	for _, field := range []string{timestamp, method, path, storeID} {
+		mac.Write([]byte(field))
+		mac.Write([]byte("\n"))
+	}
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// DefaultSignatureMaxAge is how far a push signature's timestamp may be
+// from the server clock when PushSigning is given no other.
+const DefaultSignatureMaxAge = 5 * time.Minute
+
+var (
+	errPushUnsigned       = errors.New("push is not signed")
+	errPushSourceMismatch = errors.New("push source does not match its signature")
+)
+
+// signedSourceContextKey is the context key for the source a request was
+// signed as.
+type signedSourceContextKey struct{}
+
+// PushSignatureStore is implemented by stores that record the push
+// signatures they have accepted, so that a signature is used once across
+// restarts and replicas. Implemented by SQLiteStore.
+type PushSignatureStore interface {
+	ClaimPushSignature(ctx context.Context, signature string, expires time.Time) (bool, error)
+}
+
+// PushSigning verifies HMAC signatures on sync pushes, so that a push
+// captured on the network cannot be replayed by a third party even with
+// the bearer token. Each source signs with its own secret. A signature is
+// accepted only while its timestamp is within the max age of the server
+// clock, and only once; a client retrying a push signs it again.
+type PushSigning struct {
+	secrets  map[string]string
+	required bool
+	maxAge   time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time // Accepted signatures of stores that keep none, until they expire
+}
+
+// NewPushSigning creates a PushSigning for the given source secrets. With
+// required set, unsigned pushes are refused from every source; otherwise
+// only from sources with a secret. A non-positive maxAge uses
+// DefaultSignatureMaxAge.
+func NewPushSigning(secrets map[string]string, required bool, maxAge time.Duration) *PushSigning {
+	if maxAge <= 0 {
+		maxAge = DefaultSignatureMaxAge
+	}
+	return &PushSigning{
+		secrets:  secrets,
+		required: required,
+		maxAge:   maxAge,
+		seen:     make(map[string]time.Time),
+	}
+}
+
+// WithPushSigning verifies signatures on sync pushes. Nil leaves pushes
+// unsigned.
+func WithPushSigning(p *PushSigning) HandlerOption {
+	return func(h *Handler) {
+		h.signing = p
+	}
+}
+
+// Verify checks the signature of a signed request and records its source
+// for Authorize, refusing the request with 401 if the signature is
+// invalid, stale or already used. Unsigned requests pass, unless every
+// push must be signed. Apply it to push routes before decompression, as
+// the signature covers the body as sent. A nil PushSigning verifies
+// nothing.
+func (p *PushSigning) Verify(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if p == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			signature := r.Header.Get(HeaderSyncSignature)
+			if signature == "" {
+				if p.required {
+					writeSignatureProblem(w, r, "", "Push must be signed")
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			source := r.Header.Get(HeaderSyncSource)
+			secret, ok := p.secrets[source]
+			if !ok {
+				writeSignatureProblem(w, r, source, fmt.Sprintf("No signing secret for source %q", source))
+				return
+			}
+			timestamp := r.Header.Get(HeaderSyncTimestamp)
+			signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				writeSignatureProblem(w, r, source, HeaderSyncTimestamp+" must be Unix seconds")
+				return
+			}
+			if age := time.Since(time.Unix(signedAt, 0)); age > p.maxAge || age < -p.maxAge {
+				writeSignatureProblem(w, r, source, "Signature timestamp is outside the allowed window")
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					writePushTooLarge(w, r, tooLarge.Limit)
+					return
+				}
+				WriteProblem(w, r, http.StatusBadRequest, "Failed to read request body")
+				return
+			}
+			expected := SignPush(secret, timestamp, r.Method, r.URL.Path, StoreIDFromContext(r.Context()), body)
+			if !hmac.Equal([]byte(signature), []byte(expected)) {
+				writeSignatureProblem(w, r, source, "Invalid signature")
+				return
+			}
+			accepted, err := p.accept(r.Context(), signature, time.Unix(signedAt, 0).Add(p.maxAge))
+			if err != nil {
+				slog.Error("push signature check failed",
+					"component", "api",
+					"store_id", StoreIDFromContext(r.Context()),
+					"source_id", source,
+					"error", err,
+				)
+				WriteProblem(w, r, http.StatusInternalServerError, "Failed to check push signature")
+				return
+			}
+			if !accepted {
+				writeSignatureProblem(w, r, source, "Signature has already been used")
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			ctx := context.WithValue(r.Context(), signedSourceContextKey{}, source)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// accept records signature as used until expires, reporting false if it
+// was used already. Signatures are recorded in the request's store when it
+// keeps them, and otherwise in memory, where they hold only for this
+// process. Expired signatures are forgotten, as their timestamps would now
+// be refused anyway.
+func (p *PushSigning) accept(ctx context.Context, signature string, expires time.Time) (bool, error) {
+	if s, err := StoreFromContext(ctx); err == nil {
+		if ledger, ok := s.(PushSignatureStore); ok {
+			return ledger.ClaimPushSignature(ctx, signature, expires)
+		}
+	}
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for sig, exp := range p.seen {
+		if now.After(exp) {
+			delete(p.seen, sig)
+		}
+	}
+	if _, used := p.seen[signature]; used {
+		return false, nil
+	}
+	p.seen[signature] = expires
+	return true, nil
+}
+
+// Authorize checks that a push from sourceID may be applied under the
+// request's signature: a signed request must come from the source it was
+// signed as, and a source with a secret must sign. A nil PushSigning
+// authorizes everything.
+func (p *PushSigning) Authorize(ctx context.Context, sourceID string) error {
+	if p == nil {
+		return nil
+	}
+	signed, _ := ctx.Value(signedSourceContextKey{}).(string)
+	if signed == "" {
+		if _, ok := p.secrets[sourceID]; ok {
+			return errPushUnsigned
+		}
+		return nil
+	}
+	if signed != sourceID {
+		return errPushSourceMismatch
+	}
+	return nil
+}
+
+// writePushAuthorizeError writes the response for a push refused by
+// PushSigning.Authorize.
+func writePushAuthorizeError(w http.ResponseWriter, r *http.Request, sourceID string, err error) {
+	if errors.Is(err, errPushSourceMismatch) {
+		WriteProblemForbidden(w, r, fmt.Sprintf("Push for source %q is signed by another source", sourceID))
+		return
+	}
+	writeSignatureProblem(w, r, sourceID, fmt.Sprintf("Pushes from source %q must be signed", sourceID))
+}
+
+// writeSignatureProblem writes a 401 for a push whose signature was
+// refused, logging it, as refusals may be replay attempts.
+func writeSignatureProblem(w http.ResponseWriter, r *http.Request, source, detail string) {
+	slog.Warn("push signature refused",
+		"component", "api",
+		"action", "push_signature_refused",
+		"store_id", StoreIDFromContext(r.Context()),
+		"source_id", source,
+		"reason", detail,
+		"remote_addr", r.RemoteAddr,
+	)
+	WriteProblem(w, r, http.StatusUnauthorized, detail)
+}
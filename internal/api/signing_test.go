@@ -0,0 +1,155 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+	"github.com/hyperengineering/engram/internal/webhook"
+)
+
+func TestSyncPush_Signing(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	WithPushSigning(NewPushSigning(map[string]string{"laptop": "s3cret"}, false, time.Minute))(handler)
+	router := NewRouter(handler, manager)
+
+	pushBody := func(pushID, sourceID string) []byte {
+		return makePushBody(t, engramsync.PushRequest{
+			PushID:        pushID,
+			SourceID:      sourceID,
+			SchemaVersion: 2,
+			Entries: []engramsync.ChangeLogEntry{
+				{TableName: "lore_entries", EntityID: pushID, Operation: "upsert", Payload: validLorePayload(t, pushID)},
+			},
+		}).Bytes()
+	}
+	push := func(body []byte, headers map[string]string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/stores/test-store/sync/push", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	sign := func(source string, at time.Time, body []byte) map[string]string {
+		ts := strconv.FormatInt(at.Unix(), 10)
+		return map[string]string{
+			HeaderSyncSource:    source,
+			HeaderSyncTimestamp: ts,
+			HeaderSyncSignature: SignPush("s3cret", ts, http.MethodPost, "/api/v1/stores/test-store/sync/push", "test-store", body),
+		}
+	}
+
+	body := pushBody("01JHQ7Z4C3S8W1M2N5P6R7T8V1", "laptop")
+	headers := sign("laptop", time.Now(), body)
+	if w := push(body, headers); w.Code != http.StatusOK {
+		t.Fatalf("signed push: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := push(body, headers); w.Code != http.StatusUnauthorized {
+		t.Errorf("replayed push: expected 401, got %d: %s", w.Code, w.Body.String())
+	}
+	// The store remembers the signature, as after a restart or on another replica
+	WithPushSigning(NewPushSigning(map[string]string{"laptop": "s3cret"}, false, time.Minute))(handler)
+	router = NewRouter(handler, manager)
+	if w := push(body, headers); w.Code != http.StatusUnauthorized {
+		t.Errorf("push replayed to a new server: expected 401, got %d: %s", w.Code, w.Body.String())
+	}
+
+	body = pushBody("01JHQ7Z4C3S8W1M2N5P6R7T8V2", "laptop")
+	tests := []struct {
+		name    string
+		body    []byte
+		headers map[string]string
+		want    int
+	}{
+		{"unsigned from a source with a secret", body, nil, http.StatusUnauthorized},
+		{"stale timestamp", body, sign("laptop", time.Now().Add(-2*time.Minute), body), http.StatusUnauthorized},
+		{"tampered body", body, sign("laptop", time.Now(), pushBody("01JHQ7Z4C3S8W1M2N5P6R7T8V3", "laptop")), http.StatusUnauthorized},
+		{"unknown signing source", body, sign("desktop", time.Now(), body), http.StatusUnauthorized},
+		{"signed as a webhook", body, map[string]string{
+			HeaderSyncSource:    "laptop",
+			HeaderSyncTimestamp: strconv.FormatInt(time.Now().Unix(), 10),
+			HeaderSyncSignature: webhook.Sign("s3cret", strconv.FormatInt(time.Now().Unix(), 10), body),
+		}, http.StatusUnauthorized},
+		{"unsigned from a source without a secret", pushBody("01JHQ7Z4C3S8W1M2N5P6R7T8V4", "desktop"), nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := push(tt.body, tt.headers); w.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// Signed as one source, pushing as another
+	other := pushBody("01JHQ7Z4C3S8W1M2N5P6R7T8V5", "desktop")
+	if w := push(other, sign("laptop", time.Now(), other)); w.Code != http.StatusForbidden {
+		t.Errorf("push signed by another source: expected 403, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSyncPush_SignatureBoundToRequest(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	WithPushSigning(NewPushSigning(map[string]string{"laptop": "s3cret"}, false, time.Minute))(handler)
+	router := NewRouter(handler, manager)
+
+	body := makePushBody(t, engramsync.PushRequest{
+		PushID:        "01JHQ7Z4C3S8W1M2N5P6R7T8W1",
+		SourceID:      "laptop",
+		SchemaVersion: 2,
+		Entries: []engramsync.ChangeLogEntry{
+			{TableName: "lore_entries", EntityID: "e1", Operation: "upsert", Payload: validLorePayload(t, "e1")},
+		},
+	}).Bytes()
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	// Signed for another store, or for the exchange endpoint, then sent as a push
+	for _, sig := range []string{
+		SignPush("s3cret", ts, http.MethodPost, "/api/v1/stores/other-store/sync/push", "other-store", body),
+		SignPush("s3cret", ts, http.MethodPost, "/api/v1/stores/test-store/sync/exchange", "test-store", body),
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/stores/test-store/sync/push", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		req.Header.Set(HeaderSyncSource, "laptop")
+		req.Header.Set(HeaderSyncTimestamp, ts)
+		req.Header.Set(HeaderSyncSignature, sig)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("push signed for another request: expected 401, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestSyncPush_SigningRequired(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	WithPushSigning(NewPushSigning(map[string]string{"laptop": "s3cret"}, true, 0))(handler)
+	router := NewRouter(handler, manager)
+
+	body := makePushBody(t, engramsync.PushRequest{
+		PushID:        "01JHQ7Z4C3S8W1M2N5P6R7T8V6",
+		SourceID:      "desktop",
+		SchemaVersion: 2,
+		Entries: []engramsync.ChangeLogEntry{
+			{TableName: "lore_entries", EntityID: "e1", Operation: "upsert", Payload: validLorePayload(t, "e1")},
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/stores/test-store/sync/push", body)
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned push with signing required: expected 401, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/store"
+)
+
+// StoreWriteLocker is implemented by stores whose writes can be paused
+// while their files are copied. Implemented by SQLiteStore.
+type StoreWriteLocker interface {
+	LockWrites(ctx context.Context) (*store.WriteLock, error)
+}
+
+// StoreLockRequest is the body of POST /api/v1/stores/{store_id}/lock.
+type StoreLockRequest struct {
+	// Token renews the lock it was issued for instead of taking a new one.
+	Token string `json:"token,omitempty"`
+	// Lease is a duration (e.g. 10m) after which the lock is released
+	// unless renewed. Empty uses DefaultStoreLockLease.
+	Lease string `json:"lease,omitempty"`
+	// Holder names the tool or person taking the lock, for others to see.
+	Holder string `json:"holder,omitempty"`
+	// Reason is appended to the detail of refused writes.
+	Reason string `json:"reason,omitempty"`
+}
+
+// StoreUnlockRequest is the body of POST /api/v1/stores/{store_id}/unlock.
+type StoreUnlockRequest struct {
+	Token string `json:"token"`
+}
+
+// LockStore handles POST /api/v1/stores/{store_id}/lock
+//
+// Pauses writes to a store so external tooling can copy its files or
+// analyze them offline while the rest of the server keeps running. Writes
+// through the API are refused with 503 and Retry-After, and the database
+// write lock is held so background workers cannot write either; reads
+// continue. The lock is released by unlock or when its lease runs out;
+// posting the returned token renews the lease.
+func (h *Handler) LockStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if h.storeManager == nil {
+		WriteProblem(w, r, http.StatusServiceUnavailable, "Multi-store support not configured")
+		return
+	}
+
+	storeID, ok := decodeStoreIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req StoreLockRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err.Error()))
+			return
+		}
+	}
+	lease := DefaultStoreLockLease
+	if req.Lease != "" {
+		d, err := time.ParseDuration(req.Lease)
+		if err != nil || d < time.Second || d > MaxStoreLockLease {
+			WriteProblem(w, r, http.StatusBadRequest,
+				fmt.Sprintf("lease must be a duration from 1s to %s (e.g. 10m)", MaxStoreLockLease))
+			return
+		}
+		lease = d
+	}
+
+	if req.Token != "" {
+		lock, err := h.storeLocks.Renew(storeID, req.Token, lease)
+		if err != nil {
+			writeStoreLockError(w, r, storeID, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(lock)
+		return
+	}
+
+	if held := h.storeLocks.Active(storeID); held != nil {
+		writeStoreLocked(w, r, held)
+		return
+	}
+
+	// Held for the lease, so the store is not closed while locked
+	managed, err := h.storeManager.AcquireStore(ctx, storeID)
+	if err != nil {
+		switch {
+		case errors.Is(err, multistore.ErrStoreNotFound):
+			WriteProblem(w, r, http.StatusNotFound, "Store not found")
+		case errors.Is(err, multistore.ErrStoreArchived):
+			WriteProblemStoreArchived(w, r, storeID)
+		case errors.Is(err, multistore.ErrStoreDeleting):
+			WriteProblem(w, r, http.StatusGone, "Store is being deleted")
+		default:
+			slog.Error("lock store failed", "store_id", storeID, "error", err)
+			WriteProblem(w, r, http.StatusInternalServerError, "Internal error locking store")
+		}
+		return
+	}
+
+	locker, ok := managed.Store.(StoreWriteLocker)
+	if !ok {
+		managed.Release()
+		WriteProblem(w, r, http.StatusNotImplemented, "Store does not support locking")
+		return
+	}
+	writeLock, err := locker.LockWrites(ctx)
+	if err != nil {
+		managed.Release()
+		switch {
+		case errors.Is(err, store.ErrInMemoryStore):
+			WriteProblem(w, r, http.StatusNotImplemented, "Store does not support locking")
+		case store.IsBusy(err):
+			MapStoreError(w, r, err)
+		default:
+			slog.Error("lock store failed", "store_id", storeID, "error", err)
+			WriteProblem(w, r, http.StatusInternalServerError, "Internal error locking store")
+		}
+		return
+	}
+	release := func() {
+		if err := writeLock.Release(); err != nil {
+			slog.Error("release store write lock failed",
+				"component", "api",
+				"store_id", storeID,
+				"error", err,
+			)
+		}
+		managed.Release()
+	}
+
+	lock, err := h.storeLocks.Lock(storeID, req.Holder, req.Reason, lease, release)
+	if err != nil {
+		// Another request locked the store first
+		release()
+		writeStoreLocked(w, r, h.storeLocks.Active(storeID))
+		return
+	}
+
+	slog.Warn("store locked",
+		"component", "api",
+		"action", "store_locked",
+		"store_id", storeID,
+		"holder", req.Holder,
+		"reason", req.Reason,
+		"lease_seconds", lock.LeaseSeconds,
+		"request_id", GetRequestID(ctx),
+		"remote_addr", r.RemoteAddr,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lock)
+}
+
+// UnlockStore handles POST /api/v1/stores/{store_id}/unlock
+//
+// Releases a store lock, resuming writes. Only the lock's token unlocks it.
+func (h *Handler) UnlockStore(w http.ResponseWriter, r *http.Request) {
+	storeID, ok := decodeStoreIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req StoreUnlockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err.Error()))
+		return
+	}
+	if req.Token == "" {
+		WriteProblem(w, r, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	if err := h.storeLocks.Unlock(storeID, req.Token); err != nil {
+		writeStoreLockError(w, r, storeID, err)
+		return
+	}
+
+	slog.Info("store unlocked",
+		"component", "api",
+		"action", "store_unlocked",
+		"store_id", storeID,
+		"request_id", GetRequestID(r.Context()),
+		"remote_addr", r.RemoteAddr,
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeStoreLockError writes the response for a renew or unlock refused by
+// StoreLocks.
+func writeStoreLockError(w http.ResponseWriter, r *http.Request, storeID string, err error) {
+	switch {
+	case errors.Is(err, errStoreNotLocked):
+		WriteProblem(w, r, http.StatusNotFound, fmt.Sprintf("Store %q is not locked", storeID))
+	case errors.Is(err, errLockToken):
+		WriteProblemConflict(w, r, fmt.Sprintf("Store %q is locked with another token", storeID))
+	default:
+		WriteProblem(w, r, http.StatusInternalServerError, "Internal error")
+	}
+}
+
+// writeStoreLocked writes the 409 for a lock request on a store locked by
+// someone else.
+func writeStoreLocked(w http.ResponseWriter, r *http.Request, held *StoreLock) {
+	if held == nil {
+		WriteProblemConflict(w, r, "Store is locked")
+		return
+	}
+	detail := fmt.Sprintf("Store %q is locked until %s", held.StoreID, held.ExpiresAt.Format(time.RFC3339))
+	if held.Holder != "" {
+		detail += " by " + held.Holder
+	}
+	WriteProblemConflict(w, r, detail)
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// Lease bounds for store locks.
+const (
+	// DefaultStoreLockLease is the lease of a lock request that doesn't
+	// set one.
+	DefaultStoreLockLease = 5 * time.Minute
+
+	// MaxStoreLockLease is the longest lease a lock may be taken or
+	// renewed for, so a crashed client cannot hold a store for long.
+	MaxStoreLockLease = time.Hour
+)
+
+var (
+	errStoreLocked    = errors.New("store is locked")
+	errStoreNotLocked = errors.New("store is not locked")
+	errLockToken      = errors.New("lock token does not match")
+)
+
+// StoreLock describes a lock on a store's writes. Token is only returned to
+// the client holding the lock, which needs it to renew or unlock.
+type StoreLock struct {
+	StoreID      string    `json:"store_id"`
+	Token        string    `json:"token,omitempty"`
+	Holder       string    `json:"holder,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	LeaseSeconds int64     `json:"lease_seconds"`
+	LockedAt     time.Time `json:"locked_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// heldLock is a StoreLock with what ends it.
+type heldLock struct {
+	StoreLock
+	release  func()
+	timer    *time.Timer
+	renewals int // So a timer superseded by renewal expires nothing
+}
+
+// StoreLocks holds the leased locks that pause writes to stores while
+// external tooling copies or inspects their files. While a lock is held,
+// Guard refuses writes to its store with 503 and Retry-After. A lock whose
+// lease runs out without being renewed is released. Locks live in memory,
+// so a restart releases them.
+type StoreLocks struct {
+	mu    sync.Mutex
+	locks map[string]*heldLock
+}
+
+// NewStoreLocks creates a StoreLocks with no locks held.
+func NewStoreLocks() *StoreLocks {
+	return &StoreLocks{locks: make(map[string]*heldLock)}
+}
+
+// Lock records a lock on storeID for lease and returns it with a new token.
+// release is called once when the lock is unlocked or expires. It returns
+// errStoreLocked, without calling release, if storeID is already locked.
+func (l *StoreLocks) Lock(storeID, holder, reason string, lease time.Duration, release func()) (StoreLock, error) {
+	now := time.Now().UTC()
+	held := &heldLock{
+		StoreLock: StoreLock{
+			StoreID:      storeID,
+			Token:        ulid.Make().String(),
+			Holder:       holder,
+			Reason:       reason,
+			LeaseSeconds: int64(lease / time.Second),
+			LockedAt:     now,
+			ExpiresAt:    now.Add(lease),
+		},
+		release: release,
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.locks[storeID]; ok {
+		return StoreLock{}, errStoreLocked
+	}
+	held.timer = time.AfterFunc(lease, func() { l.expire(held, 0) })
+	l.locks[storeID] = held
+	return held.StoreLock, nil
+}
+
+// Renew extends the lock on storeID to lease from now.
+func (l *StoreLocks) Renew(storeID, token string, lease time.Duration) (StoreLock, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	held, err := l.heldLocked(storeID, token)
+	if err != nil {
+		return StoreLock{}, err
+	}
+	held.timer.Stop()
+	held.renewals++
+	n := held.renewals
+	held.LeaseSeconds = int64(lease / time.Second)
+	held.ExpiresAt = time.Now().UTC().Add(lease)
+	held.timer = time.AfterFunc(lease, func() { l.expire(held, n) })
+	return held.StoreLock, nil
+}
+
+// Unlock releases the lock on storeID.
+func (l *StoreLocks) Unlock(storeID, token string) error {
+	l.mu.Lock()
+	held, err := l.heldLocked(storeID, token)
+	if err == nil {
+		held.timer.Stop()
+		delete(l.locks, storeID)
+	}
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	held.release()
+	return nil
+}
+
+// heldLocked returns the lock on storeID if token is its token. l.mu must
+// be held.
+func (l *StoreLocks) heldLocked(storeID, token string) (*heldLock, error) {
+	held, ok := l.locks[storeID]
+	if !ok {
+		return nil, errStoreNotLocked
+	}
+	if token != held.Token {
+		return nil, errLockToken
+	}
+	return held, nil
+}
+
+// expire releases held when the lease of renewal n runs out, unless it was unlocked
+// or renewed in the meantime.
+func (l *StoreLocks) expire(held *heldLock, n int) {
+	l.mu.Lock()
+	if l.locks[held.StoreID] != held || held.renewals != n {
+		l.mu.Unlock()
+		return
+	}
+	delete(l.locks, held.StoreID)
+	l.mu.Unlock()
+
+	slog.Warn("store lock lease expired",
+		"component", "api",
+		"action", "store_lock_expired",
+		"store_id", held.StoreID,
+		"holder", held.Holder,
+		"locked_at", held.LockedAt,
+	)
+	held.release()
+}
+
+// Active returns the lock on storeID, without its token, or nil if writes
+// to it are allowed.
+func (l *StoreLocks) Active(storeID string) *StoreLock {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	held, ok := l.locks[storeID]
+	if !ok {
+		return nil
+	}
+	lock := held.StoreLock
+	lock.Token = ""
+	return &lock
+}
+
+// Guard refuses the request with 503 while its store is locked, with
+// Retry-After set to the rest of the lease. Apply it to routes that write;
+// the store is resolved as by Maintenance.Guard. A nil StoreLocks refuses
+// nothing.
+func (l *StoreLocks) Guard(next http.Handler) http.Handler {
+	if l == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		storeID := guardedStoreID(r)
+		lock := l.Active(storeID)
+		if lock == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		detail := fmt.Sprintf("Store %q is locked and read-only.", storeID)
+		if lock.Reason != "" {
+			detail += " " + lock.Reason
+		}
+		slog.Debug("write refused while store locked",
+			"component", "api",
+			"action", "store_lock_refused",
+			"store_id", storeID,
+			"method", r.Method,
+			"path", r.URL.Path,
+		)
+		retryAfter := max(int64(time.Until(lock.ExpiresAt).Round(time.Second)/time.Second), 1)
+		w.Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+		WriteProblem(w, r, http.StatusServiceUnavailable, detail+" Please retry after the indicated interval.")
+	})
+}
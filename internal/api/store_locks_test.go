@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestStoreLock_API(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+	ctx := context.Background()
+	for _, id := range []string{"team-a", "team-b"} {
+		if _, err := manager.CreateStore(ctx, id, "", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &mockStore{stats: &types.StoreStats{}}
+	handler := NewHandler(s, manager, &mockEmbedder{model: "test-model"}, nil, "test-api-key", "1.0.0")
+	router := NewRouter(handler, manager)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	// A write that fails validation: anything but 503 means it got past
+	// the lock guard
+	write := func(storeID string) *httptest.ResponseRecorder {
+		t.Helper()
+		return do(http.MethodPost, "/api/v1/stores/"+storeID+"/lore/feedback", `{}`)
+	}
+
+	w := do(http.MethodPost, "/api/v1/stores/team-a/lock", `{"lease":"2m","holder":"backup-job","reason":"Copying files."}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("lock: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var lock StoreLock
+	if err := json.NewDecoder(w.Body).Decode(&lock); err != nil {
+		t.Fatalf("decode lock: %v", err)
+	}
+	if lock.Token == "" || lock.LeaseSeconds != 120 || lock.Holder != "backup-job" {
+		t.Errorf("lock = %+v, want a token, 120s lease and holder backup-job", lock)
+	}
+
+	w = write("team-a")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("write while locked: expected 503, got %d: %s", w.Code, w.Body.String())
+	}
+	if retry, _ := strconv.Atoi(w.Header().Get("Retry-After")); retry < 1 || retry > 120 {
+		t.Errorf("Retry-After = %q, want the rest of the 120s lease", w.Header().Get("Retry-After"))
+	}
+	if !strings.Contains(w.Body.String(), "Copying files.") {
+		t.Errorf("detail doesn't include the reason: %s", w.Body.String())
+	}
+	if w := write("team-b"); w.Code == http.StatusServiceUnavailable {
+		t.Errorf("write to another store refused: %s", w.Body.String())
+	}
+	if w := do(http.MethodGet, "/api/v1/stores/team-a/sync/delta?after=0", ""); w.Code != http.StatusOK {
+		t.Errorf("read while locked: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Someone else cannot take the lock, but the holder can renew it
+	if w := do(http.MethodPost, "/api/v1/stores/team-a/lock", `{}`); w.Code != http.StatusConflict {
+		t.Errorf("second lock: expected 409, got %d: %s", w.Code, w.Body.String())
+	}
+	w = do(http.MethodPost, "/api/v1/stores/team-a/lock", `{"token":"`+lock.Token+`","lease":"10m"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("renew: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var renewed StoreLock
+	if err := json.NewDecoder(w.Body).Decode(&renewed); err != nil {
+		t.Fatalf("decode renewed lock: %v", err)
+	}
+	if renewed.Token != lock.Token || !renewed.ExpiresAt.After(lock.ExpiresAt) {
+		t.Errorf("renewed = %+v, want the same token and a later expiry than %v", renewed, lock.ExpiresAt)
+	}
+
+	if w := do(http.MethodPost, "/api/v1/stores/team-a/unlock", `{"token":"wrong"}`); w.Code != http.StatusConflict {
+		t.Errorf("unlock with wrong token: expected 409, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodPost, "/api/v1/stores/team-a/unlock", `{"token":"`+lock.Token+`"}`); w.Code != http.StatusNoContent {
+		t.Fatalf("unlock: expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := write("team-a"); w.Code == http.StatusServiceUnavailable {
+		t.Errorf("write after unlock refused: %s", w.Body.String())
+	}
+	if w := do(http.MethodPost, "/api/v1/stores/team-a/unlock", `{"token":"`+lock.Token+`"}`); w.Code != http.StatusNotFound {
+		t.Errorf("unlock when not locked: expected 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestStoreLock_InvalidRequests(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+	if _, err := manager.CreateStore(context.Background(), "team-a", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	handler := NewHandler(&mockStore{}, manager, &mockEmbedder{}, nil, "test-api-key", "1.0.0")
+	router := NewRouter(handler, manager)
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		want int
+	}{
+		{"lease too long", "/api/v1/stores/team-a/lock", `{"lease":"2h"}`, http.StatusBadRequest},
+		{"lease not a duration", "/api/v1/stores/team-a/lock", `{"lease":"soon"}`, http.StatusBadRequest},
+		{"unknown store", "/api/v1/stores/nope/lock", `{}`, http.StatusNotFound},
+		{"renew when not locked", "/api/v1/stores/team-a/lock", `{"token":"x"}`, http.StatusNotFound},
+		{"unlock without token", "/api/v1/stores/team-a/unlock", `{}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer test-api-key")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestStoreLocks_LeaseExpires(t *testing.T) {
+	locks := NewStoreLocks()
+	released := make(chan struct{})
+	lock, err := locks.Lock("team-a", "", "", 50*time.Millisecond, func() { close(released) })
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	// A renewal supersedes the first lease
+	if _, err := locks.Renew("team-a", lock.Token, 150*time.Millisecond); err != nil {
+		t.Fatalf("Renew() error = %v", err)
+	}
+	time.Sleep(80 * time.Millisecond)
+	if locks.Active("team-a") == nil {
+		t.Fatal("lock released at its first lease despite renewal")
+	}
+
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Fatal("lock not released when its lease ran out")
+	}
+	if locks.Active("team-a") != nil {
+		t.Error("expired lock still active")
+	}
+	if err := locks.Unlock("team-a", lock.Token); err != errStoreNotLocked {
+		t.Errorf("Unlock() after expiry error = %v, want errStoreNotLocked", err)
+	}
+}
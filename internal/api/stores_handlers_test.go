@@ -8,7 +8,10 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hyperengineering/engram/internal/multistore"
 	"github.com/hyperengineering/engram/internal/types"
@@ -110,6 +113,62 @@ func TestListStores_Multiple(t *testing.T) {
 	}
 }
 
+func TestListStores_Pagination(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+
+	ctx := context.Background()
+	manager.GetStore(ctx, "default")
+	manager.CreateStore(ctx, "project-a", "", "Project A")
+	manager.CreateStore(ctx, "project-b", "", "Project B")
+
+	s := &mockStore{stats: &types.StoreStats{}}
+	embedder := &mockEmbedder{model: "test-model"}
+	handler := NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0")
+	router := NewRouter(handler, manager)
+
+	list := func(query string) (int, ListStoresResponse) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/stores"+query, nil)
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp ListStoresResponse
+		json.NewDecoder(w.Body).Decode(&resp)
+		return w.Code, resp
+	}
+
+	var ids []string
+	query := "?limit=2"
+	for page := 0; ; page++ {
+		code, resp := list(query)
+		if code != http.StatusOK {
+			t.Fatalf("page %d: expected status 200, got %d", page, code)
+		}
+		if resp.Total != 3 {
+			t.Errorf("page %d: expected total 3, got %d", page, resp.Total)
+		}
+		for _, st := range resp.Stores {
+			ids = append(ids, st.ID)
+		}
+		if !resp.HasMore {
+			break
+		}
+		if page > 2 {
+			t.Fatal("pagination did not end")
+		}
+		query = "?limit=2&cursor=" + resp.NextCursor
+	}
+	if want := []string{"default", "project-a", "project-b"}; !slices.Equal(ids, want) {
+		t.Errorf("expected stores %v across pages, got %v", want, ids)
+	}
+
+	for _, query := range []string{"?limit=0", "?limit=1001", "?cursor=not-a-cursor"} {
+		if code, _ := list(query); code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, code)
+		}
+	}
+}
+
 func TestListStores_Unauthorized(t *testing.T) {
 	manager, _ := setupStoreManager(t)
 	defer manager.Close()
@@ -1110,3 +1169,283 @@ func TestCreateStore_API_SchemaVersionInResponse(t *testing.T) {
 		t.Errorf("SchemaVersion should be >= 0, got %d", resp.SchemaVersion)
 	}
 }
+
+func TestCreateStore_API_WithTemplate(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+
+	s := &mockStore{stats: &types.StoreStats{}}
+	embedder := &mockEmbedder{model: "test-model"}
+	handler := NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0")
+	router := NewRouter(handler, manager)
+
+	body := `{"store_id": "decisions", "template": "recall-decisions"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/stores", bytes.NewBufferString(body))
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp CreateStoreResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Type != "recall" || resp.Template != "recall-decisions" {
+		t.Errorf("type = %q, template = %q; want recall, recall-decisions", resp.Type, resp.Template)
+	}
+
+	managed, err := manager.GetStore(context.Background(), "decisions")
+	if err != nil {
+		t.Fatalf("GetStore() error = %v", err)
+	}
+	if len(managed.Meta.Categories) != 2 {
+		t.Errorf("Categories = %v, want the template's two", managed.Meta.Categories)
+	}
+}
+
+func TestCreateStore_API_TemplateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown template", `{"store_id": "s1", "template": "nope"}`},
+		{"type conflicts", `{"store_id": "s2", "type": "tract", "template": "recall-default"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, rootPath := setupStoreManager(t)
+			defer manager.Close()
+
+			s := &mockStore{stats: &types.StoreStats{}}
+			embedder := &mockEmbedder{model: "test-model"}
+			handler := NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0")
+			router := NewRouter(handler, manager)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/stores", bytes.NewBufferString(tt.body))
+			req.Header.Set("Authorization", "Bearer test-api-key")
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+			entries, _ := os.ReadDir(rootPath)
+			for _, e := range entries {
+				if e.Name() != "default" {
+					t.Errorf("unexpected store directory %q", e.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestCloneStore_API(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+
+	ctx := context.Background()
+	source, err := manager.CreateStore(ctx, "main", "", "Main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.Store.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Orders use event sourcing", Category: "ARCHITECTURAL_DECISION", Confidence: 0.9, SourceID: "s1"},
+		{Content: "Retries hid the outage", Category: "PATTERN_OUTCOME", Confidence: 0.9, SourceID: "s1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.CreateStore(ctx, "goals", "tract", "Goals"); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &mockStore{stats: &types.StoreStats{}}
+	embedder := &mockEmbedder{model: "test-model"}
+	handler := NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0")
+	router := NewRouter(handler, manager)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/api/v1/stores/main/clone", `{"target_id": "exp/decisions", "categories": ["ARCHITECTURAL_DECISION"]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp CloneStoreResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.ID != "exp/decisions" || resp.ClonedFrom != "main" || resp.LoreCount != 1 || resp.Type != "recall" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		want int
+	}{
+		{"target exists", "/api/v1/stores/main/clone", `{"target_id": "exp/decisions"}`, http.StatusConflict},
+		{"source missing", "/api/v1/stores/missing/clone", `{"target_id": "exp/other"}`, http.StatusNotFound},
+		{"invalid target", "/api/v1/stores/main/clone", `{"target_id": "Bad ID"}`, http.StatusBadRequest},
+		{"invalid filter", "/api/v1/stores/main/clone", `{"target_id": "exp/other", "min_confidence": 2}`, http.StatusUnprocessableEntity},
+		{"filter on tract store", "/api/v1/stores/goals/clone", `{"target_id": "exp/goals", "categories": ["PATTERN_OUTCOME"]}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := post(tt.path, tt.body); w.Code != tt.want {
+				t.Errorf("expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestArchiveAndThawStore_API(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+
+	ctx := context.Background()
+	managed, err := manager.CreateStore(ctx, "dormant", "", "Dormant project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := managed.Store.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Orders use event sourcing", Category: "ARCHITECTURAL_DECISION", Confidence: 0.9, SourceID: "s1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &mockStore{stats: &types.StoreStats{}}
+	embedder := &mockEmbedder{model: "test-model"}
+	handler := NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0")
+	router := NewRouter(handler, manager)
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/api/v1/stores/dormant/archive")
+	if w.Code != http.StatusOK {
+		t.Fatalf("archive: expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var archived StoreInfoResponse
+	if err := json.NewDecoder(w.Body).Decode(&archived); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if archived.State != multistore.StoreStateArchived || archived.Archive == nil {
+		t.Errorf("archive response = %+v, want archived state", archived)
+	}
+
+	w = do(http.MethodGet, "/api/v1/stores/dormant/lore/search?q=orders")
+	if w.Code != http.StatusLocked {
+		t.Fatalf("search on archived store: expected status 423, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "/api/v1/stores/dormant/thaw") {
+		t.Errorf("423 detail should explain how to thaw: %s", w.Body.String())
+	}
+
+	w = do(http.MethodGet, "/api/v1/stores/dormant")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"state":"archived"`) {
+		t.Errorf("store info: got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = do(http.MethodPost, "/api/v1/stores/dormant/thaw")
+	if w.Code != http.StatusOK {
+		t.Fatalf("thaw: expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var thawed StoreInfoResponse
+	if err := json.NewDecoder(w.Body).Decode(&thawed); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if thawed.State != multistore.StoreStateActive || thawed.Stats == nil || thawed.Stats.ActiveLore != 1 {
+		t.Errorf("thaw response = %+v, want active store with its lore", thawed)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"thaw active store", "/api/v1/stores/dormant/thaw", http.StatusConflict},
+		{"archive default store", "/api/v1/stores/default/archive", http.StatusForbidden},
+		{"archive missing store", "/api/v1/stores/missing/archive", http.StatusNotFound},
+		{"thaw missing store", "/api/v1/stores/missing/thaw", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := do(http.MethodPost, tt.path); w.Code != tt.want {
+				t.Errorf("expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDeleteStore_API_DrainsInFlightRequests(t *testing.T) {
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+
+	ctx := context.Background()
+	if _, err := manager.CreateStore(ctx, "busy", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	inFlight, err := manager.AcquireStore(ctx, "busy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &mockStore{stats: &types.StoreStats{}}
+	embedder := &mockEmbedder{model: "test-model"}
+	handler := NewHandler(s, manager, embedder, nil, "test-api-key", "1.0.0")
+	router := NewRouter(handler, manager)
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	deleted := make(chan *httptest.ResponseRecorder, 1)
+	go func() { deleted <- do(http.MethodDelete, "/api/v1/stores/busy?confirm=true") }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		w := do(http.MethodGet, "/api/v1/stores/busy/lore/search?q=orders")
+		if w.Code == http.StatusGone {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 410 while the store drains, last status %d: %s", w.Code, w.Body.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if w := do(http.MethodDelete, "/api/v1/stores/busy?confirm=true"); w.Code != http.StatusConflict {
+		t.Errorf("second delete: expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+
+	inFlight.Release()
+	if w := <-deleted; w.Code != http.StatusNoContent {
+		t.Fatalf("delete: expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodGet, "/api/v1/stores/busy/lore/search?q=orders"); w.Code != http.StatusNotFound {
+		t.Errorf("after delete: expected status 404, got %d", w.Code)
+	}
+}
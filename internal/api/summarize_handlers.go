@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/llm"
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/validation"
+)
+
+// DefaultSummaryLimit is how many search results a summarize request by
+// query condenses when it sets no limit.
+const DefaultSummaryLimit = 20
+
+// SummarizeLore handles POST /api/v1/lore/summarize and POST /api/v1/stores/{store_id}/lore/summarize
+//
+// The body selects entries either by ids or by a hybrid search for query,
+// optionally filtered by category and language and capped by limit. The
+// selected entries are condensed by the configured language model into a
+// Markdown digest. Responds 503 when no model is configured and 502 when
+// the model fails.
+func (h *Handler) SummarizeLore(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+	if h.summarizer == nil {
+		WriteProblem(w, r, http.StatusServiceUnavailable, "Lore summarization not configured")
+		return
+	}
+
+	start := time.Now()
+	storeID := StoreIDFromContext(r.Context())
+	s := h.getStoreForRequest(r)
+
+	var req types.SummarizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err.Error()))
+		return
+	}
+	req.Query = strings.TrimSpace(req.Query)
+	if errs := validation.ValidateSummarizeRequest(req); len(errs) > 0 {
+		WriteProblemWithErrors(w, r, "Request contains invalid fields", errs)
+		return
+	}
+
+	var entries []types.LoreEntry
+	if len(req.IDs) > 0 {
+		var missing []string
+		for _, id := range req.IDs {
+			entry, err := s.GetLore(r.Context(), id)
+			if errors.Is(err, store.ErrNotFound) {
+				missing = append(missing, id)
+				continue
+			}
+			if err != nil {
+				MapStoreError(w, r, err)
+				return
+			}
+			entries = append(entries, *entry)
+		}
+		if len(missing) > 0 {
+			WriteProblem(w, r, http.StatusNotFound,
+				fmt.Sprintf("Lore entries not found: %s", strings.Join(missing, ", ")))
+			return
+		}
+	} else {
+		results, err := s.SearchLore(r.Context(), h.summarySearchQuery(r.Context(), storeID, req))
+		if err != nil {
+			MapStoreError(w, r, err)
+			return
+		}
+		for _, res := range results {
+			entries = append(entries, res.Lore)
+		}
+	}
+
+	resp := types.SummarizeResponse{
+		Model:    h.summarizer.ModelName(),
+		EntryIDs: make([]string, len(entries)),
+	}
+	for i, e := range entries {
+		resp.EntryIDs[i] = e.ID
+	}
+
+	// Nothing matched, so there is nothing to ask the model about
+	if len(entries) > 0 {
+		summary, err := llm.SummarizeLore(r.Context(), h.summarizer, entries, req.Focus)
+		if err != nil {
+			slog.Error("lore summarization failed",
+				"component", "api",
+				"action", "summarize_failed",
+				"store_id", storeID,
+				"model", resp.Model,
+				"entry_count", len(entries),
+				"error", err,
+			)
+			WriteProblem(w, r, http.StatusBadGateway, "Language model failed to summarize lore")
+			return
+		}
+		resp.Summary = summary
+	}
+
+	slog.Info("lore summarized",
+		"component", "api",
+		"action", "summarize",
+		"store_id", storeID,
+		"model", resp.Model,
+		"entry_count", len(entries),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// summarySearchQuery builds the hybrid search selecting entries for a
+// summarize request by query.
+func (h *Handler) summarySearchQuery(ctx context.Context, storeID string, req types.SummarizeRequest) types.SearchQuery {
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultSummaryLimit
+	}
+	return h.hybridSearchQuery(ctx, storeID, "summarize", types.SearchQuery{
+		Text:     req.Query,
+		Category: req.Category,
+		Language: req.Language,
+		Limit:    limit,
+	})
+}
+
+// hybridSearchQuery completes query as a hybrid search with the server's
+// ranking and boost. When the text cannot be embedded it falls back to
+// keyword ranking, as searches do, logging the degradation under action.
+func (h *Handler) hybridSearchQuery(ctx context.Context, storeID, action string, query types.SearchQuery) types.SearchQuery {
+	query.Mode = types.SearchModeHybrid
+	query.Ranking = h.searchRanking
+	query.Boost = h.searchBoost
+
+	embedder := h.queryEmbedder
+	if embedder == nil {
+		embedder = h.embedder
+	}
+	vec, err := embedder.Embed(ctx, query.Text)
+	if err == nil && len(vec) == 0 {
+		err = store.ErrEmbeddingUnavailable
+	}
+	if err != nil {
+		slog.Warn(action+" query embedding failed, using keyword ranking",
+			"component", "api",
+			"action", action+"_degraded",
+			"store_id", storeID,
+			"error", err,
+		)
+		query.Mode = types.SearchModeKeyword
+		return query
+	}
+	query.Embedding = vec
+	return query
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/llm"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// mockSummarizer implements llm.Provider for testing
+type mockSummarizer struct {
+	text       string
+	err        error
+	calls      int
+	lastPrompt string
+}
+
+func (m *mockSummarizer) Complete(ctx context.Context, system, prompt string) (string, error) {
+	m.calls++
+	m.lastPrompt = prompt
+	return m.text, m.err
+}
+
+func (m *mockSummarizer) ModelName() string {
+	return "test-llm"
+}
+
+func serveSummarize(t *testing.T, s *mockStore, e *mockEmbedder, p llm.Provider, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	var opts []HandlerOption
+	if p != nil {
+		opts = append(opts, WithSummarizer(p))
+	}
+	router := NewRouter(NewHandler(s, nil, e, nil, "test-key", "1.0.0", opts...), nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lore/summarize", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSummarizeLore_ByIDs(t *testing.T) {
+	s := &mockStore{entry: &types.LoreEntry{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Content: "Retry with backoff", Category: "PATTERN_OUTCOME"}}
+	p := &mockSummarizer{text: "## Retries\nUse backoff."}
+
+	w := serveSummarize(t, s, &mockEmbedder{}, p, `{"ids":["01ARZ3NDEKTSV4RRFFQ69G5FAV"],"focus":"payments onboarding"}`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp types.SummarizeResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Summary != "## Retries\nUse backoff." || resp.Model != "test-llm" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if len(resp.EntryIDs) != 1 || resp.EntryIDs[0] != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Errorf("entry_ids = %v", resp.EntryIDs)
+	}
+	if !strings.Contains(p.lastPrompt, "Retry with backoff") || !strings.Contains(p.lastPrompt, "payments onboarding") {
+		t.Errorf("prompt missing entry or focus:\n%s", p.lastPrompt)
+	}
+	if s.lastSearch != nil {
+		t.Error("summarize by ids should not search")
+	}
+}
+
+func TestSummarizeLore_ByQuery(t *testing.T) {
+	s := &mockStore{searchResults: []types.SearchResult{
+		{Lore: types.LoreEntry{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Content: "Retry with backoff"}},
+		{Lore: types.LoreEntry{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAW", Content: "Cap retries at five"}},
+	}}
+	p := &mockSummarizer{text: "Use capped backoff."}
+
+	w := serveSummarize(t, s, &mockEmbedder{vector: []float32{1, 0}}, p,
+		`{"query":" retries ","category":"PATTERN_OUTCOME","language":"en","limit":5}`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	q := s.lastSearch
+	if q == nil {
+		t.Fatal("expected a search")
+	}
+	if q.Text != "retries" || q.Mode != types.SearchModeHybrid || q.Category != "PATTERN_OUTCOME" || q.Language != "en" || q.Limit != 5 {
+		t.Errorf("unexpected search query: %+v", q)
+	}
+	var resp types.SummarizeResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.EntryIDs) != 2 {
+		t.Errorf("entry_ids = %v, want 2", resp.EntryIDs)
+	}
+}
+
+func TestSummarizeLore_QueryFallsBackToKeyword(t *testing.T) {
+	s := &mockStore{}
+	w := serveSummarize(t, s, &mockEmbedder{err: errors.New("embedding down")}, &mockSummarizer{text: "x"}, `{"query":"retries"}`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if s.lastSearch.Mode != types.SearchModeKeyword || s.lastSearch.Limit != DefaultSummaryLimit {
+		t.Errorf("unexpected search query: %+v", s.lastSearch)
+	}
+}
+
+func TestSummarizeLore_NoMatchesSkipsModel(t *testing.T) {
+	p := &mockSummarizer{text: "x"}
+	w := serveSummarize(t, &mockStore{}, &mockEmbedder{vector: []float32{1}}, p, `{"query":"nothing"}`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if p.calls != 0 {
+		t.Errorf("model called %d times, want 0", p.calls)
+	}
+	if !strings.Contains(w.Body.String(), `"entry_ids":[]`) {
+		t.Errorf("body = %s, want empty entry_ids", w.Body.String())
+	}
+}
+
+func TestSummarizeLore_Errors(t *testing.T) {
+	entry := &types.LoreEntry{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Content: "x"}
+	tests := []struct {
+		name     string
+		store    *mockStore
+		provider llm.Provider
+		body     string
+		want     int
+	}{
+		{"not configured", &mockStore{}, nil, `{"query":"x"}`, http.StatusServiceUnavailable},
+		{"invalid json", &mockStore{}, &mockSummarizer{}, `{`, http.StatusBadRequest},
+		{"no selection", &mockStore{}, &mockSummarizer{}, `{}`, http.StatusUnprocessableEntity},
+		{"ids and query", &mockStore{}, &mockSummarizer{}, `{"ids":["01ARZ3NDEKTSV4RRFFQ69G5FAV"],"query":"x"}`, http.StatusUnprocessableEntity},
+		{"missing entry", &mockStore{}, &mockSummarizer{}, `{"ids":["01ARZ3NDEKTSV4RRFFQ69G5FAV"]}`, http.StatusNotFound},
+		{"model failure", &mockStore{entry: entry}, &mockSummarizer{err: llm.ErrEmptyCompletion}, `{"ids":["01ARZ3NDEKTSV4RRFFQ69G5FAV"]}`, http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveSummarize(t, tt.store, &mockEmbedder{}, tt.provider, tt.body)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d; body = %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+	"github.com/hyperengineering/engram/internal/validation"
+)
+
+// The conflict inbox holds entries that a push made with
+// quarantine_invalid rejected, so a client can review its problematic
+// local changes later instead of losing them with the failed batch.
+
+const (
+	// DefaultConflictListLimit is the page size of a conflict listing.
+	DefaultConflictListLimit = 100
+	// MaxConflictListLimit is the largest accepted limit.
+	MaxConflictListLimit = 1000
+)
+
+// ConflictListResponse is the response for GET /sync/conflicts.
+type ConflictListResponse struct {
+	Conflicts []engramsync.SyncConflict `json:"conflicts"`
+	// NextCursor is passed as after to fetch the next page. Empty on the
+	// last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// resolveConflictRequest is the body of a resolve call.
+type resolveConflictRequest struct {
+	Resolution string `json:"resolution"`
+	Note       string `json:"note,omitempty"`
+}
+
+// ListSyncConflicts handles GET /api/v1/stores/{store_id}/sync/conflicts
+//
+// Lists the store's conflict inbox, oldest first. status (open or
+// resolved) and source_id filter the listing; limit defaults to 100
+// (max 1000) and after resumes from a previous page's next_cursor.
+func (h *Handler) ListSyncConflicts(w http.ResponseWriter, r *http.Request) {
+	managed := h.requireSyncStore(w, r)
+	if managed == nil {
+		return
+	}
+	params := r.URL.Query()
+
+	filter := engramsync.SyncConflictFilter{
+		Status:   params.Get("status"),
+		SourceID: params.Get("source_id"),
+		AfterID:  params.Get("after"),
+		Limit:    DefaultConflictListLimit,
+	}
+	if filter.Status != "" && filter.Status != engramsync.ConflictStatusOpen && filter.Status != engramsync.ConflictStatusResolved {
+		WriteProblem(w, r, http.StatusBadRequest, "Invalid status: must be open or resolved")
+		return
+	}
+	if raw := params.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > MaxConflictListLimit {
+			WriteProblem(w, r, http.StatusBadRequest,
+				fmt.Sprintf("Invalid limit: must be an integer between 1 and %d", MaxConflictListLimit))
+			return
+		}
+		filter.Limit = n
+	}
+
+	conflicts, err := managed.Store.ListSyncConflicts(r.Context(), filter)
+	if err != nil {
+		slog.Error("conflict listing failed",
+			"component", "api",
+			"action", "list_sync_conflicts_failed",
+			"store_id", StoreIDFromContext(r.Context()),
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	resp := ConflictListResponse{Conflicts: conflicts}
+	if resp.Conflicts == nil {
+		resp.Conflicts = []engramsync.SyncConflict{}
+	}
+	if len(conflicts) == filter.Limit {
+		resp.NextCursor = conflicts[len(conflicts)-1].ID
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetSyncConflict handles GET /api/v1/stores/{store_id}/sync/conflicts/{conflict_id}
+func (h *Handler) GetSyncConflict(w http.ResponseWriter, r *http.Request) {
+	managed := h.requireSyncStore(w, r)
+	if managed == nil {
+		return
+	}
+	id := chi.URLParam(r, "conflict_id")
+	if err := validation.ValidateULID("conflict_id", id); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "Invalid conflict ID format: must be valid ULID")
+		return
+	}
+
+	conflict, err := managed.Store.GetSyncConflict(r.Context(), id)
+	if err != nil {
+		MapStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(conflict)
+}
+
+// ResolveSyncConflict handles POST /api/v1/stores/{store_id}/sync/conflicts/{conflict_id}/resolve
+//
+// Closes an open conflict. resolution is "discarded" when the client
+// dropped the change or "fixed" once it pushed a corrected one; the
+// server does not re-apply the quarantined entry.
+func (h *Handler) ResolveSyncConflict(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	storeID := StoreIDFromContext(ctx)
+
+	managed := h.requireSyncStore(w, r)
+	if managed == nil {
+		return
+	}
+	id := chi.URLParam(r, "conflict_id")
+	if err := validation.ValidateULID("conflict_id", id); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "Invalid conflict ID format: must be valid ULID")
+		return
+	}
+
+	var req resolveConflictRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err))
+		return
+	}
+	if req.Resolution != engramsync.ConflictResolutionDiscarded && req.Resolution != engramsync.ConflictResolutionFixed {
+		WriteProblem(w, r, http.StatusUnprocessableEntity, "resolution must be discarded or fixed")
+		return
+	}
+	if len(req.Note) > 1000 {
+		WriteProblem(w, r, http.StatusUnprocessableEntity, "note must be at most 1000 characters")
+		return
+	}
+
+	conflict, err := managed.Store.ResolveSyncConflict(ctx, id, req.Resolution, req.Note)
+	if err != nil {
+		MapStoreError(w, r, err)
+		return
+	}
+
+	slog.Info("sync conflict resolved",
+		"component", "api",
+		"action", "resolve_sync_conflict",
+		"store_id", storeID,
+		"conflict_id", id,
+		"resolution", req.Resolution,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(conflict)
+}
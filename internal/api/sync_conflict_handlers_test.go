@@ -0,0 +1,119 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+func TestSyncPush_QuarantineInvalid(t *testing.T) {
+	manager, handler, managed := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	do := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	validID := "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	push := engramsync.PushRequest{
+		PushID:            "quarantine-push",
+		SourceID:          "client-1",
+		SchemaVersion:     2,
+		QuarantineInvalid: true,
+		Entries: []engramsync.ChangeLogEntry{
+			{Sequence: 1, TableName: "lore_entries", EntityID: validID, Operation: "upsert", Payload: validLorePayload(t, validID)},
+			{Sequence: 2, TableName: "unknown_table", EntityID: "e2", Operation: "upsert", Payload: json.RawMessage(`{}`)},
+		},
+	}
+	w := do(http.MethodPost, "/api/v1/stores/test-store/sync/push", makePushBody(t, push).Bytes())
+	if w.Code != http.StatusOK {
+		t.Fatalf("push: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp engramsync.PushResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode push response: %v", err)
+	}
+	if resp.Accepted != 1 || len(resp.Quarantined) != 1 {
+		t.Fatalf("push response = %+v, want 1 accepted and 1 quarantined", resp)
+	}
+	q := resp.Quarantined[0]
+	if q.Sequence != 2 || q.ConflictID == "" || len(q.Errors) == 0 {
+		t.Errorf("quarantined = %+v, want sequence 2 with a conflict ID and errors", q)
+	}
+	if entry, err := managed.Store.GetLore(context.Background(), validID); err != nil || entry == nil {
+		t.Errorf("valid entry not applied: %v", err)
+	}
+
+	w = do(http.MethodGet, "/api/v1/stores/test-store/sync/conflicts?status=open", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var list ConflictListResponse
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(list.Conflicts) != 1 || list.Conflicts[0].ID != q.ConflictID || list.Conflicts[0].Entry.TableName != "unknown_table" {
+		t.Fatalf("conflicts = %+v, want the quarantined entry", list.Conflicts)
+	}
+
+	path := "/api/v1/stores/test-store/sync/conflicts/" + q.ConflictID
+	if w := do(http.MethodGet, path, nil); w.Code != http.StatusOK {
+		t.Errorf("get: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodPost, path+"/resolve", []byte(`{"resolution":"ignored"}`)); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("resolve with unknown resolution: expected 422, got %d", w.Code)
+	}
+	if w := do(http.MethodPost, path+"/resolve", []byte(`{"resolution":"discarded","note":"stale draft"}`)); w.Code != http.StatusOK {
+		t.Fatalf("resolve: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodPost, path+"/resolve", []byte(`{"resolution":"discarded"}`)); w.Code != http.StatusConflict {
+		t.Errorf("second resolve: expected 409, got %d", w.Code)
+	}
+	if w := do(http.MethodGet, "/api/v1/stores/test-store/sync/conflicts/01ARZ3NDEKTSV4RRFFQ69G5FAW", nil); w.Code != http.StatusNotFound {
+		t.Errorf("unknown conflict: expected 404, got %d", w.Code)
+	}
+}
+
+func TestSyncPush_QuarantineAllEntries(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	push := engramsync.PushRequest{
+		PushID:            "quarantine-all-push",
+		SourceID:          "client-1",
+		SchemaVersion:     2,
+		QuarantineInvalid: true,
+		Entries: []engramsync.ChangeLogEntry{
+			{Sequence: 1, TableName: "unknown_table", EntityID: "e1", Operation: "upsert", Payload: json.RawMessage(`{}`)},
+		},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/stores/test-store/sync/push", makePushBody(t, push))
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp engramsync.PushResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Accepted != 0 || len(resp.Quarantined) != 1 {
+		t.Errorf("response = %+v, want nothing accepted and 1 quarantined", resp)
+	}
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+// SyncExchange handles POST /api/v1/stores/{store_id}/sync/exchange
+//
+// Applies an optional push, then returns the delta after the given cursor,
+// saving a round trip over separate push and delta calls. The push half
+// behaves exactly like POST /sync/push: if it fails, its error response is
+// returned and no delta is read. The delta is read after the push commits,
+// so it includes the pushed entries.
+func (h *Handler) SyncExchange(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ctx := r.Context()
+	storeID := StoreIDFromContext(ctx)
+
+	// 1. Get managed store
+	managed := h.requireSyncStore(w, r)
+	if managed == nil {
+		return
+	}
+
+	// 2. Parse request
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxPushBytes)
+	var req engramsync.ExchangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writePushTooLarge(w, r, tooLarge.Limit)
+			return
+		}
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err))
+		return
+	}
+
+	// 3. Validate request structure
+	deltaReq, err := validateExchangeRequest(req)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Reject a stale cursor before applying the push, so the client can
+	// re-bootstrap and retry the whole exchange
+	if !checkDeltaCursor(w, r, managed.Store, deltaReq.After) {
+		return
+	}
+
+	// 4. Apply push, or replay the cached result for a known push_id
+	var resp engramsync.ExchangeResponse
+	if req.Push != nil {
+		respBytes, found, err := managed.Store.CheckPushIdempotency(ctx, req.Push.PushID)
+		if err != nil {
+			slog.Error("idempotency check failed", "store_id", storeID, "error", err)
+			WriteProblem(w, r, http.StatusInternalServerError, "Internal error")
+			return
+		}
+		if found {
+			w.Header().Set("X-Idempotent-Replay", "true")
+		} else {
+			var ok bool
+			respBytes, ok = h.applyPush(w, r, managed, *req.Push, start)
+			if !ok {
+				return
+			}
+		}
+
+		resp.Push = &engramsync.PushResponse{}
+		if err := json.Unmarshal(respBytes, resp.Push); err != nil {
+			slog.Error("decode push response failed", "store_id", storeID, "push_id", req.Push.PushID, "error", err)
+			WriteProblem(w, r, http.StatusInternalServerError, "Internal error")
+			return
+		}
+	}
+
+	// 5. Read delta
+	resp.Delta, err = buildDelta(ctx, managed.Store, deltaReq, h.serverOnlyTables(r))
+	if err != nil {
+		slog.Error("exchange delta failed",
+			"component", "api",
+			"action", "sync_exchange_failed",
+			"store_id", storeID,
+			"after", deltaReq.After,
+			"error", err,
+		)
+		WriteProblem(w, r, http.StatusInternalServerError, "Failed to retrieve delta")
+		return
+	}
+
+	// 6. Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+
+	slog.Debug("sync exchange served",
+		"component", "api",
+		"action", "sync_exchange",
+		"store_id", storeID,
+		"pushed", resp.Push != nil,
+		"after", deltaReq.After,
+		"entries_returned", len(resp.Delta.Entries),
+		"last_sequence", resp.Delta.LastSequence,
+		"has_more", resp.Delta.HasMore,
+	)
+}
+
+// validateExchangeRequest checks the push half and derives the delta
+// request, applying the same limit defaults as GET /sync/delta.
+func validateExchangeRequest(req engramsync.ExchangeRequest) (engramsync.DeltaRequest, error) {
+	if req.Push != nil {
+		if err := validatePushRequest(*req.Push); err != nil {
+			return engramsync.DeltaRequest{}, fmt.Errorf("push: %w", err)
+		}
+	}
+	if req.After < 0 {
+		return engramsync.DeltaRequest{}, fmt.Errorf("invalid after: must be >= 0")
+	}
+
+	limit := req.Limit
+	switch {
+	case limit == 0:
+		limit = engramsync.DefaultDeltaLimit
+	case limit < 0:
+		return engramsync.DeltaRequest{}, fmt.Errorf("invalid limit: must be >= 1")
+	case limit > engramsync.MaxDeltaLimit:
+		limit = engramsync.MaxDeltaLimit
+	}
+
+	return engramsync.DeltaRequest{
+		After:         req.After,
+		Limit:         limit,
+		Tables:        req.Tables,
+		Exclude:       req.Exclude,
+		ExcludeSource: req.ExcludeSource,
+	}, nil
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+func decodeExchangeResponse(t *testing.T, body []byte) engramsync.ExchangeResponse {
+	t.Helper()
+	var resp engramsync.ExchangeResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("decode exchange response: %v", err)
+	}
+	return resp
+}
+
+func TestSyncExchange_PushAndDelta(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+	pushEntries(t, router, 3)
+
+	w := doSyncRequest(t, router, http.MethodPost, "/exchange", engramsync.ExchangeRequest{
+		Push: &engramsync.PushRequest{
+			PushID:        "exchange-1",
+			SourceID:      "test-client",
+			SchemaVersion: 2,
+			Entries:       loreEntries(t, 10, 2),
+		},
+		After: 2,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeExchangeResponse(t, w.Body.Bytes())
+	if resp.Push == nil || resp.Push.Accepted != 2 || resp.Push.RemoteSequence != 5 {
+		t.Errorf("push = %+v, want accepted 2 at sequence 5", resp.Push)
+	}
+	if len(resp.Delta.Entries) != 3 {
+		t.Fatalf("delta entries = %d, want 3", len(resp.Delta.Entries))
+	}
+	if resp.Delta.Entries[0].Sequence != 3 || resp.Delta.LastSequence != 5 {
+		t.Errorf("delta range = %d..%d, want 3..5", resp.Delta.Entries[0].Sequence, resp.Delta.LastSequence)
+	}
+}
+
+func TestSyncExchange_DeltaOnly(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+	pushEntries(t, router, 4)
+
+	w := doSyncRequest(t, router, http.MethodPost, "/exchange", engramsync.ExchangeRequest{After: 0, Limit: 3})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeExchangeResponse(t, w.Body.Bytes())
+	if resp.Push != nil {
+		t.Errorf("push = %+v, want nil", resp.Push)
+	}
+	if len(resp.Delta.Entries) != 3 || !resp.Delta.HasMore {
+		t.Errorf("delta = %d entries has_more %v, want 3 / true", len(resp.Delta.Entries), resp.Delta.HasMore)
+	}
+}
+
+func TestSyncExchange_IdempotentReplay(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	req := engramsync.ExchangeRequest{
+		Push: &engramsync.PushRequest{
+			PushID:        "exchange-retry",
+			SourceID:      "test-client",
+			SchemaVersion: 2,
+			Entries:       loreEntries(t, 1, 2),
+		},
+	}
+	first := doSyncRequest(t, router, http.MethodPost, "/exchange", req)
+	if first.Code != http.StatusOK {
+		t.Fatalf("first: expected 200, got %d: %s", first.Code, first.Body.String())
+	}
+
+	second := doSyncRequest(t, router, http.MethodPost, "/exchange", req)
+	if second.Code != http.StatusOK {
+		t.Fatalf("retry: expected 200, got %d: %s", second.Code, second.Body.String())
+	}
+	if second.Header().Get("X-Idempotent-Replay") != "true" {
+		t.Error("expected X-Idempotent-Replay on retry")
+	}
+	resp := decodeExchangeResponse(t, second.Body.Bytes())
+	if resp.Push == nil || resp.Push.Accepted != 2 {
+		t.Errorf("push = %+v, want cached accepted 2", resp.Push)
+	}
+	if len(resp.Delta.Entries) != 2 {
+		t.Errorf("delta entries = %d, want 2 (no duplicate apply)", len(resp.Delta.Entries))
+	}
+}
+
+func TestSyncExchange_PushValidationFailure(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	w := doSyncRequest(t, router, http.MethodPost, "/exchange", engramsync.ExchangeRequest{
+		Push: &engramsync.PushRequest{
+			PushID:        "exchange-bad",
+			SourceID:      "test-client",
+			SchemaVersion: 2,
+			Entries: []engramsync.ChangeLogEntry{{
+				TableName: "unknown_table",
+				EntityID:  "x",
+				Operation: "upsert",
+				Payload:   json.RawMessage(`{}`),
+			}},
+		},
+	})
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", w.Code, w.Body.String())
+	}
+	var errResp engramsync.PushErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if len(errResp.Errors) == 0 {
+		t.Error("expected push errors in response")
+	}
+}
+
+func TestSyncExchange_InvalidRequest(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	tests := []struct {
+		name string
+		req  engramsync.ExchangeRequest
+	}{
+		{"negative after", engramsync.ExchangeRequest{After: -1}},
+		{"negative limit", engramsync.ExchangeRequest{Limit: -5}},
+		{"push missing push_id", engramsync.ExchangeRequest{Push: &engramsync.PushRequest{SourceID: "c", SchemaVersion: 2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doSyncRequest(t, router, http.MethodPost, "/exchange", tt.req)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestValidateExchangeRequest_LimitDefaults(t *testing.T) {
+	got, err := validateExchangeRequest(engramsync.ExchangeRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Limit != engramsync.DefaultDeltaLimit {
+		t.Errorf("Limit = %d, want %d", got.Limit, engramsync.DefaultDeltaLimit)
+	}
+
+	got, _ = validateExchangeRequest(engramsync.ExchangeRequest{Limit: engramsync.MaxDeltaLimit + 1})
+	if got.Limit != engramsync.MaxDeltaLimit {
+		t.Errorf("Limit = %d, want capped %d", got.Limit, engramsync.MaxDeltaLimit)
+	}
+}
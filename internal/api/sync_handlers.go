@@ -10,8 +10,10 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/hyperengineering/engram/internal/multistore"
 	"github.com/hyperengineering/engram/internal/plugin"
 	"github.com/hyperengineering/engram/internal/store"
 	engramsync "github.com/hyperengineering/engram/internal/sync"
@@ -23,6 +25,19 @@ const (
 
 	// MaxPushEntries is the maximum entries per push request.
 	MaxPushEntries = 1000
+
+	// DefaultMaxPushBytes is the default request body limit for a push.
+	DefaultMaxPushBytes int64 = 16 << 20
+
+	// DefaultPushSessionTTL is the default lifetime of a push session.
+	DefaultPushSessionTTL = time.Hour
+
+	// DefaultMaxClockSkew is how far ahead of the server clock a pushed
+	// timestamp may be before it is clamped.
+	DefaultMaxClockSkew = 5 * time.Minute
+
+	// MaxPushSessionEntries is the maximum entries staged in one push session.
+	MaxPushSessionEntries = store.MaxPushSessionEntries
 )
 
 // SyncPush handles POST /api/v1/stores/{store_id}/sync/push
@@ -43,8 +58,14 @@ func (h *Handler) SyncPush(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 2. Parse request
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxPushBytes)
 	var req engramsync.PushRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writePushTooLarge(w, r, tooLarge.Limit)
+			return
+		}
 		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err))
 		return
 	}
@@ -56,50 +77,124 @@ func (h *Handler) SyncPush(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 4. Check idempotency
-	cachedResp, found, err := managed.Store.CheckPushIdempotency(ctx, req.PushID)
+	if replayed := writeIdempotentReplay(w, r, managed.Store, storeID, req.PushID); replayed {
+		return
+	}
+
+	// 5-10. Validate, replay, and record
+	respBytes, ok := h.applyPush(w, r, managed, req, start)
+	if !ok {
+		return
+	}
+
+	// 11. Return response
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(respBytes)
+}
+
+// writeIdempotentReplay writes the cached response for a push_id that was
+// already applied. Returns true if a response (replay or error) was written.
+func writeIdempotentReplay(w http.ResponseWriter, r *http.Request, s store.Store, storeID, pushID string) bool {
+	cachedResp, found, err := s.CheckPushIdempotency(r.Context(), pushID)
 	if err != nil {
 		slog.Error("idempotency check failed", "store_id", storeID, "error", err)
 		WriteProblem(w, r, http.StatusInternalServerError, "Internal error")
-		return
+		return true
 	}
-	if found {
-		w.Header().Set("Content-Type", "application/json")
-		w.Header().Set("X-Idempotent-Replay", "true")
-		w.Write(cachedResp)
-		slog.Info("push idempotent replay",
-			"component", "api",
-			"action", "sync_push_replay",
-			"store_id", storeID,
-			"push_id", req.PushID,
-		)
-		return
+	if !found {
+		return false
 	}
 
-	// 5. Check schema version
-	serverVersion := managed.SchemaVersion(ctx)
-	if req.SchemaVersion > serverVersion {
-		writeSchemaMismatch(w, r, req.SchemaVersion, serverVersion)
-		return
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Idempotent-Replay", "true")
+	w.Write(cachedResp)
+	slog.Info("push idempotent replay",
+		"component", "api",
+		"action", "sync_push_replay",
+		"store_id", storeID,
+		"push_id", pushID,
+	)
+	return true
+}
+
+// applyPush validates and replays a structurally valid push whose push_id
+// has not been seen, then records the response for idempotency. Shared by
+// single-request pushes, push session commits, and exchanges. On failure it
+// writes the error response and returns false; on success it returns the
+// encoded PushResponse for the caller to send.
+func (h *Handler) applyPush(w http.ResponseWriter, r *http.Request, managed *multistore.ManagedStore, req engramsync.PushRequest, start time.Time) ([]byte, bool) {
+	ctx := r.Context()
+	storeID := StoreIDFromContext(ctx)
+
+	if err := h.signing.Authorize(ctx, req.SourceID); err != nil {
+		writePushAuthorizeError(w, r, req.SourceID, err)
+		return nil, false
 	}
 
-	// 6. Get domain plugin
+	// 5. Get domain plugin
 	p, _ := plugin.Get(managed.Type())
 
-	// 7. Validate entries via plugin
-	orderedEntries, err := p.ValidatePush(ctx, req.Entries)
+	// 6. Check schema version against the plugin's registry
+	serverVersion := managed.SchemaVersion(ctx)
+	if !checkClientSchema(w, r, p, req.SchemaVersion, serverVersion) {
+		return nil, false
+	}
+
+	// 7. Validate entries. Partial and quarantine_invalid pushes set
+	// rejected entries aside and validate the rest again, as dropping an
+	// entry can invalidate others that reference it.
+	entries := req.Entries
+	var rejectedEntries []engramsync.SyncConflict
+	orderedEntries, submitted, err := validatePushEntries(ctx, managed, p, req, entries)
+	for err != nil && (req.QuarantineInvalid || req.Mode == engramsync.PushModePartial) {
+		remaining, rejected := quarantineEntries(req, entries, err)
+		if len(rejected) == 0 {
+			break
+		}
+		rejectedEntries = append(rejectedEntries, rejected...)
+		entries = remaining
+		if len(entries) == 0 {
+			orderedEntries, submitted, err = nil, 0, nil
+			break
+		}
+		orderedEntries, submitted, err = validatePushEntries(ctx, managed, p, req, entries)
+	}
 	if err != nil {
+		var missingErr *plugin.MissingParentsError
 		var validationErrs plugin.ValidationErrors
-		if errors.As(err, &validationErrs) {
+		switch {
+		case errors.As(err, &missingErr):
+			writeMissingParents(w, missingErr)
+		case errors.As(err, &validationErrs):
 			writePushValidationErrors(w, validationErrs)
-			return
+		default:
+			slog.Error("push validation failed", "store_id", storeID, "error", err)
+			WriteProblem(w, r, http.StatusInternalServerError, "Validation error")
 		}
-		slog.Error("plugin validation failed", "store_id", storeID, "error", err)
-		WriteProblem(w, r, http.StatusInternalServerError, "Validation error")
-		return
+		return nil, false
+	}
+
+	// Only quarantine_invalid keeps rejected entries in the conflict inbox
+	var conflicts []engramsync.SyncConflict
+	if req.QuarantineInvalid {
+		conflicts = rejectedEntries
+	}
+
+	// Clamp timestamps from clients whose clocks run ahead
+	warnings := engramsync.ClampClockSkew(orderedEntries, time.Now(), h.maxClockSkew)
+	if len(warnings) > 0 {
+		slog.Warn("push timestamps clamped for clock skew",
+			"component", "api",
+			"action", "sync_push_clock_skew",
+			"store_id", storeID,
+			"push_id", req.PushID,
+			"source_id", req.SourceID,
+			"clamped", len(warnings),
+		)
 	}
 
 	// 8. Execute replay in transaction
-	remoteSeq, err := executePushTransaction(ctx, managed.Store, p, req.SourceID, orderedEntries)
+	remoteSeq, err := executePushTransaction(ctx, managed.Store, p, req.SourceID, orderedEntries, conflicts)
 	if err != nil {
 		slog.Error("push transaction failed",
 			"component", "api",
@@ -108,14 +203,40 @@ func (h *Handler) SyncPush(w http.ResponseWriter, r *http.Request) {
 			"push_id", req.PushID,
 			"error", err,
 		)
+		if store.IsBusy(err) {
+			writeBusy(w, r)
+			return nil, false
+		}
 		WriteProblem(w, r, http.StatusInternalServerError, "Push failed")
-		return
+		return nil, false
 	}
 
 	// 9. Build success response
 	resp := engramsync.PushResponse{
 		Accepted:       len(orderedEntries),
 		RemoteSequence: remoteSeq,
+		Cascaded:       len(orderedEntries) - submitted,
+		Warnings:       warnings,
+	}
+	for _, c := range conflicts {
+		resp.Quarantined = append(resp.Quarantined, engramsync.QuarantinedEntry{
+			ConflictID: c.ID,
+			Sequence:   c.Entry.Sequence,
+			TableName:  c.Entry.TableName,
+			EntityID:   c.Entry.EntityID,
+			Errors:     c.Errors,
+		})
+	}
+	if !req.QuarantineInvalid {
+		for _, c := range rejectedEntries {
+			resp.Rejected = append(resp.Rejected, c.Errors...)
+		}
+	}
+	if migrations := plugin.ClientMigrationsBetween(p, req.SchemaVersion, serverVersion); len(migrations) > 0 {
+		resp.SchemaUpgrade = &engramsync.SchemaUpgrade{
+			ServerVersion: serverVersion,
+			Migrations:    migrations,
+		}
 	}
 
 	respBytes, _ := json.Marshal(resp)
@@ -125,10 +246,6 @@ func (h *Handler) SyncPush(w http.ResponseWriter, r *http.Request) {
 		slog.Warn("failed to cache idempotency", "store_id", storeID, "push_id", req.PushID, "error", err)
 	}
 
-	// 11. Return response
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(respBytes)
-
 	slog.Info("push completed",
 		"component", "api",
 		"action", "sync_push",
@@ -136,9 +253,129 @@ func (h *Handler) SyncPush(w http.ResponseWriter, r *http.Request) {
 		"push_id", req.PushID,
 		"source_id", req.SourceID,
 		"entries", len(orderedEntries),
+		"quarantined", len(conflicts),
+		"rejected", len(rejectedEntries)-len(conflicts),
 		"remote_sequence", remoteSeq,
 		"duration_ms", time.Since(start).Milliseconds(),
 	)
+	return respBytes, true
+}
+
+// validatePushEntries runs a push's entries through the plugin's
+// validation, payload schemas, cascade expansion, hooks, sync policies and
+// reference checks. It returns the entries to replay and how many of them
+// were submitted rather than generated by cascades. Rejections are
+// returned as plugin.ValidationErrors or *plugin.MissingParentsError.
+func validatePushEntries(ctx context.Context, managed *multistore.ManagedStore, p plugin.DomainPlugin, req engramsync.PushRequest, entries []engramsync.ChangeLogEntry) ([]engramsync.ChangeLogEntry, int, error) {
+	ordered, err := p.ValidatePush(ctx, entries)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := plugin.ValidatePayloads(p, req.SchemaVersion, ordered); err != nil {
+		return nil, 0, err
+	}
+
+	// Expand cascade deletes when requested
+	submitted := len(ordered)
+	if req.CascadeDeletes {
+		if ce, ok := p.(plugin.CascadeExpander); ok {
+			ordered, err = ce.ExpandCascade(ctx, managed.Store, ordered)
+			if err != nil {
+				return nil, 0, fmt.Errorf("expand cascade: %w", err)
+			}
+		}
+	}
+
+	// Run the plugin's ingest and delete hooks, as the API endpoints do
+	ordered, err = plugin.RunPushHooks(ctx, p, ordered)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Enforce the plugin's per-table sync policies
+	if err := plugin.EnforceSyncPolicies(ctx, p, managed.Store, ordered); err != nil {
+		return nil, 0, err
+	}
+
+	// Check references against previously pushed entities
+	if !req.AllowDeferredParents {
+		if err := validatePushReferences(ctx, managed.Store, p, ordered); err != nil {
+			return nil, 0, err
+		}
+	}
+	return ordered, submitted, nil
+}
+
+// quarantineEntries splits entries into those not named by the validation
+// error err and conflicts for those that are. It returns no conflicts if
+// err is not a validation error or names none of the entries, such as
+// when only cascade-generated entries were rejected.
+func quarantineEntries(req engramsync.PushRequest, entries []engramsync.ChangeLogEntry, err error) ([]engramsync.ChangeLogEntry, []engramsync.SyncConflict) {
+	pushErrors := pushErrorsFor(err)
+	if len(pushErrors) == 0 {
+		return entries, nil
+	}
+	bySequence := make(map[int64][]engramsync.PushError)
+	for _, e := range pushErrors {
+		bySequence[e.Sequence] = append(bySequence[e.Sequence], e)
+	}
+
+	var remaining []engramsync.ChangeLogEntry
+	var conflicts []engramsync.SyncConflict
+	for _, entry := range entries {
+		errs, rejected := bySequence[entry.Sequence]
+		if !rejected {
+			remaining = append(remaining, entry)
+			continue
+		}
+		conflicts = append(conflicts, engramsync.SyncConflict{
+			PushID:   req.PushID,
+			SourceID: req.SourceID,
+			Entry:    entry,
+			Errors:   errs,
+		})
+	}
+	return remaining, conflicts
+}
+
+// pushErrorsFor converts a push validation error into per-entry push
+// errors. Returns nil for other errors.
+func pushErrorsFor(err error) []engramsync.PushError {
+	var missingErr *plugin.MissingParentsError
+	if errors.As(err, &missingErr) {
+		return toPushErrors(missingErr.ValidationErrors, engramsync.PushErrorMissingParent)
+	}
+	var validationErrs plugin.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return toPushErrors(validationErrs, engramsync.PushErrorValidation)
+	}
+	return nil
+}
+
+// toPushErrors converts plugin validation errors into push errors with code.
+func toPushErrors(errs plugin.ValidationErrors, code string) []engramsync.PushError {
+	pushErrors := make([]engramsync.PushError, len(errs.Errors))
+	for i, e := range errs.Errors {
+		msg := e.Message
+		if e.Field != "" {
+			msg = e.Field + ": " + msg
+		}
+		pushErrors[i] = engramsync.PushError{
+			Sequence:   e.Sequence,
+			TableName:  e.TableName,
+			EntityID:   e.EntityID,
+			Code:       code,
+			Message:    msg,
+			Field:      e.Field,
+			Constraint: e.Constraint,
+		}
+	}
+	return pushErrors
+}
+
+// writePushTooLarge writes a 413 response for a push body over the byte limit.
+func writePushTooLarge(w http.ResponseWriter, r *http.Request, limit int64) {
+	WriteProblemTooLarge(w, r, limit, "Split the upload across a push session (POST /sync/push-sessions).")
 }
 
 // txCapableStore is the interface for stores that support transactions.
@@ -146,15 +383,18 @@ func (h *Handler) SyncPush(w http.ResponseWriter, r *http.Request) {
 type txCapableStore interface {
 	BeginTx(ctx context.Context) (*sql.Tx, error)
 	AppendChangeLogBatchTx(ctx context.Context, tx *sql.Tx, entries []engramsync.ChangeLogEntry) (int64, error)
+	UpsertRowTx(ctx context.Context, tx *sql.Tx, tableName, entityID string, payload []byte) error
 }
 
-// executePushTransaction replays entries and records to change log atomically.
+// executePushTransaction replays entries and records them to the change
+// log atomically, together with any quarantined conflicts.
 func executePushTransaction(
 	ctx context.Context,
 	s store.Store,
 	p plugin.DomainPlugin,
 	sourceID string,
 	entries []engramsync.ChangeLogEntry,
+	conflicts []engramsync.SyncConflict,
 ) (int64, error) {
 	sqlStore, ok := s.(txCapableStore)
 	if !ok {
@@ -168,17 +408,19 @@ func executePushTransaction(
 	defer tx.Rollback()
 
 	// Create transaction-scoped replay store
-	replayStore := &txReplayStore{tx: tx}
+	replayStore := &txReplayStore{tx: tx, store: sqlStore}
 
 	// Replay entries via plugin
 	if err := p.OnReplay(ctx, replayStore, entries); err != nil {
 		return 0, fmt.Errorf("replay entries: %w", err)
 	}
 
-	// Stamp entries with source_id and current time
+	// Stamp entries with source_id and current time. The client's own
+	// sequence is kept as source_sequence so it can recognize echoes.
 	now := time.Now().UTC()
 	for i := range entries {
 		entries[i].SourceID = sourceID
+		entries[i].SourceSequence = entries[i].Sequence
 		entries[i].ReceivedAt = now
 	}
 
@@ -187,6 +429,16 @@ func executePushTransaction(
 	if err != nil {
 		return 0, fmt.Errorf("append change log: %w", err)
 	}
+	if len(entries) == 0 {
+		// Everything was quarantined; report the unchanged head
+		if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(sequence), 0) FROM change_log`).Scan(&maxSeq); err != nil {
+			return 0, fmt.Errorf("read latest sequence: %w", err)
+		}
+	}
+
+	if err := store.InsertSyncConflictsTx(ctx, tx, conflicts); err != nil {
+		return 0, fmt.Errorf("quarantine entries: %w", err)
+	}
 
 	if err := tx.Commit(); err != nil {
 		return 0, fmt.Errorf("commit transaction: %w", err)
@@ -197,11 +449,12 @@ func executePushTransaction(
 
 // txReplayStore wraps a transaction for plugin replay.
 type txReplayStore struct {
-	tx *sql.Tx
+	tx    *sql.Tx
+	store txCapableStore
 }
 
 func (s *txReplayStore) UpsertRow(ctx context.Context, tableName, entityID string, payload []byte) error {
-	return store.UpsertRowTx(ctx, s.tx, tableName, entityID, payload)
+	return s.store.UpsertRowTx(ctx, s.tx, tableName, entityID, payload)
 }
 
 func (s *txReplayStore) DeleteRow(ctx context.Context, tableName, entityID string) error {
@@ -229,7 +482,33 @@ func validatePushRequest(req engramsync.PushRequest) error {
 	if len(req.Entries) > MaxPushEntries {
 		return fmt.Errorf("entries exceeds maximum of %d", MaxPushEntries)
 	}
-	return nil
+	return validatePushMode(req.Mode)
+}
+
+// validatePushMode checks a push request's mode. Empty means atomic.
+func validatePushMode(mode string) error {
+	switch mode {
+	case "", engramsync.PushModeAtomic, engramsync.PushModePartial:
+		return nil
+	}
+	return fmt.Errorf("mode must be %s or %s", engramsync.PushModeAtomic, engramsync.PushModePartial)
+}
+
+// serverOnlyTables returns the tables of the request's store that its
+// plugin keeps out of sync deltas.
+func (h *Handler) serverOnlyTables(r *http.Request) []string {
+	if h.storeManager == nil {
+		return nil
+	}
+	managed, err := h.storeManager.GetStore(r.Context(), StoreIDFromContext(r.Context()))
+	if err != nil {
+		return nil
+	}
+	p, _ := plugin.Get(managed.Type())
+	if p == nil {
+		return nil
+	}
+	return plugin.ServerOnlyTables(p)
 }
 
 // writeSchemaMismatch writes a 409 response for schema version mismatch.
@@ -257,22 +536,38 @@ func writeSchemaMismatch(w http.ResponseWriter, r *http.Request, clientVersion,
 	json.NewEncoder(w).Encode(resp)
 }
 
-// writePushValidationErrors writes the push error response.
-func writePushValidationErrors(w http.ResponseWriter, errs plugin.ValidationErrors) {
-	pushErrors := make([]engramsync.PushError, len(errs.Errors))
-	for i, e := range errs.Errors {
-		pushErrors[i] = engramsync.PushError{
-			Sequence:  e.Sequence,
-			TableName: e.TableName,
-			EntityID:  e.EntityID,
-			Code:      engramsync.PushErrorValidation,
-			Message:   e.Message,
-		}
+// validatePushReferences runs the plugin's cross-batch reference check when
+// both the plugin and the store support it.
+func validatePushReferences(ctx context.Context, s store.Store, p plugin.DomainPlugin, entries []engramsync.ChangeLogEntry) error {
+	rv, ok := p.(plugin.ReferenceValidator)
+	if !ok {
+		return nil
 	}
+	lookup, ok := s.(plugin.EntityLookup)
+	if !ok {
+		return nil
+	}
+	return rv.ValidateReferences(ctx, lookup, entries)
+}
 
+// writeMissingParents writes the push error response for unresolved parents.
+func writeMissingParents(w http.ResponseWriter, err *plugin.MissingParentsError) {
+	resp := engramsync.PushErrorResponse{
+		Accepted:       0,
+		Errors:         toPushErrors(err.ValidationErrors, engramsync.PushErrorMissingParent),
+		MissingParents: err.Missing,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// writePushValidationErrors writes the push error response.
+func writePushValidationErrors(w http.ResponseWriter, errs plugin.ValidationErrors) {
 	resp := engramsync.PushErrorResponse{
 		Accepted: 0,
-		Errors:   pushErrors,
+		Errors:   toPushErrors(errs, engramsync.PushErrorValidation),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -282,7 +577,6 @@ func writePushValidationErrors(w http.ResponseWriter, errs plugin.ValidationErro
 
 // SyncDelta handles GET /api/v1/stores/{store_id}/sync/delta
 func (h *Handler) SyncDelta(w http.ResponseWriter, r *http.Request) {
-	start := time.Now()
 	ctx := r.Context()
 	storeID := StoreIDFromContext(ctx)
 
@@ -300,8 +594,11 @@ func (h *Handler) SyncDelta(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 3. Query change log
-	entries, err := s.GetChangeLogAfter(ctx, req.After, req.Limit)
+	// 3. Query change log and build response
+	if !checkDeltaCursor(w, r, s, req.After) {
+		return
+	}
+	resp, err := buildDelta(ctx, s, req, h.serverOnlyTables(r))
 	if err != nil {
 		slog.Error("delta query failed",
 			"component", "api",
@@ -314,58 +611,120 @@ func (h *Handler) SyncDelta(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 4. Get latest sequence for pagination info
+	// 4. Write response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+
+	slog.Debug("sync delta served",
+		"component", "api",
+		"action", "sync_delta",
+		"store_id", storeID,
+		"after", req.After,
+		"limit", req.Limit,
+		"tables", req.Tables,
+		"exclude_source", req.ExcludeSource,
+		"since_time", req.SinceTime,
+		"until_time", req.UntilTime,
+		"entries_returned", len(resp.Entries),
+		"last_sequence", resp.LastSequence,
+		"latest_sequence", resp.LatestSequence,
+		"has_more", resp.HasMore,
+	)
+}
+
+// SnapshotBaseSequencer is implemented by stores that know the change_log
+// sequence their current snapshot was taken at. Implemented by SQLiteStore.
+type SnapshotBaseSequencer interface {
+	SnapshotBaseSequence() (seq int64, ok bool)
+}
+
+// checkDeltaCursor writes a 410 and returns false if compaction has removed
+// change_log entries after the delta cursor after, which a client resuming
+// from it would never see. The client must re-bootstrap from the snapshot.
+func checkDeltaCursor(w http.ResponseWriter, r *http.Request, s store.Store, after int64) bool {
+	raw, err := s.GetSyncMeta(r.Context(), engramsync.SyncMetaLastCompactionSeq)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		MapStoreError(w, r, err)
+		return false
+	}
+	compacted, _ := strconv.ParseInt(raw, 10, 64)
+	if after >= compacted {
+		return true
+	}
+
+	storeID := StoreIDFromContext(r.Context())
+	var snapshotBase *int64
+	if sb, ok := s.(SnapshotBaseSequencer); ok {
+		if seq, ok := sb.SnapshotBaseSequence(); ok {
+			snapshotBase = &seq
+		}
+	}
+	slog.Info("delta cursor predates compaction",
+		"component", "api",
+		"action", "sync_delta_compacted",
+		"store_id", storeID,
+		"after", after,
+		"compacted_through", compacted,
+	)
+	WriteProblemHistoryCompacted(w, r, storeID, compacted, snapshotBase)
+	return false
+}
+
+// buildDelta reads one page of the change log after req.After, leaving out
+// entries of the hidden tables.
+func buildDelta(ctx context.Context, s store.Store, req engramsync.DeltaRequest, hidden []string) (engramsync.DeltaResponse, error) {
+	// Read the latest sequence first: a short page then proves every entry
+	// up to it was either returned or filtered out.
 	latestSeq, err := s.GetLatestSequence(ctx)
 	if err != nil {
-		slog.Error("get latest sequence failed",
-			"component", "api",
-			"action", "sync_delta_failed",
-			"store_id", storeID,
-			"error", err,
-		)
-		WriteProblem(w, r, http.StatusInternalServerError, "Failed to retrieve delta")
-		return
+		return engramsync.DeltaResponse{}, fmt.Errorf("get latest sequence: %w", err)
+	}
+
+	filter := engramsync.ChangeLogFilter{
+		Tables:          req.Tables,
+		ExcludeTables:   hidden,
+		ExcludeSourceID: req.ExcludeSource,
+		ReceivedSince:   req.SinceTime,
+		ReceivedUntil:   req.UntilTime,
+	}
+	entries, err := s.QueryChangeLog(ctx, req.After, req.Limit, filter)
+	if err != nil {
+		return engramsync.DeltaResponse{}, fmt.Errorf("query change log: %w", err)
+	}
+
+	// Drop excluded payload fields
+	if len(req.Exclude) > 0 {
+		for i := range entries {
+			entries[i].Payload = excludePayloadFields(entries[i].Payload, req.Exclude)
+		}
 	}
 
-	// 5. Calculate pagination info
 	var lastSeq int64
 	if len(entries) > 0 {
 		lastSeq = entries[len(entries)-1].Sequence
 	} else {
 		lastSeq = req.After
 	}
-
-	hasMore := len(entries) == req.Limit && lastSeq < latestSeq
-
-	// 6. Build response
-	resp := engramsync.DeltaResponse{
-		Entries:        entries,
-		LastSequence:   lastSeq,
-		LatestSequence: latestSeq,
-		HasMore:        hasMore,
+	// Advance the cursor past filtered-out entries so clients don't rescan them
+	if len(entries) < req.Limit && lastSeq < latestSeq {
+		lastSeq = latestSeq
+	}
+	if lastSeq > latestSeq && len(entries) > 0 {
+		// Entries appended after the latest sequence was read
+		latestSeq = lastSeq
 	}
 
 	// Ensure entries is [] not null in JSON
-	if resp.Entries == nil {
-		resp.Entries = []engramsync.ChangeLogEntry{}
+	if entries == nil {
+		entries = []engramsync.ChangeLogEntry{}
 	}
 
-	// 7. Write response
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
-
-	slog.Info("sync delta served",
-		"component", "api",
-		"action", "sync_delta",
-		"store_id", storeID,
-		"after", req.After,
-		"limit", req.Limit,
-		"entries_returned", len(entries),
-		"last_sequence", lastSeq,
-		"latest_sequence", latestSeq,
-		"has_more", hasMore,
-		"duration_ms", time.Since(start).Milliseconds(),
-	)
+	return engramsync.DeltaResponse{
+		Entries:        entries,
+		LastSequence:   lastSeq,
+		LatestSequence: latestSeq,
+		HasMore:        len(entries) == req.Limit && lastSeq < latestSeq,
+	}, nil
 }
 
 // SyncSnapshot handles GET /api/v1/stores/{store_id}/sync/snapshot
@@ -373,7 +732,6 @@ func (h *Handler) SyncDelta(w http.ResponseWriter, r *http.Request) {
 // When S3 is configured, returns a 302 redirect to a pre-signed URL.
 // Returns 503 with Retry-After if no snapshot is available.
 func (h *Handler) SyncSnapshot(w http.ResponseWriter, r *http.Request) {
-	start := time.Now()
 	ctx := r.Context()
 	storeID := StoreIDFromContext(ctx)
 
@@ -390,6 +748,7 @@ func (h *Handler) SyncSnapshot(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get snapshot reader
+	setSnapshotBaseHeader(w, s)
 	reader, err := s.GetSnapshot(ctx)
 	if errors.Is(err, store.ErrSnapshotNotAvailable) {
 		slog.Warn("sync snapshot not available",
@@ -419,24 +778,13 @@ func (h *Handler) SyncSnapshot(w http.ResponseWriter, r *http.Request) {
 
 	// Stream snapshot
 	w.Header().Set("Content-Type", "application/octet-stream")
-	bytesWritten, err := io.Copy(w, reader)
-	if err != nil {
+	if _, err := io.Copy(w, reader); err != nil {
 		slog.Debug("sync snapshot stream interrupted",
 			"component", "api",
 			"store_id", storeID,
 			"error", err,
 		)
-		return
 	}
-
-	slog.Info("sync snapshot served",
-		"component", "api",
-		"action", "sync_snapshot",
-		"store_id", storeID,
-		"remote_addr", r.RemoteAddr,
-		"bytes_served", bytesWritten,
-		"duration_ms", time.Since(start).Milliseconds(),
-	)
 }
 
 // parseDeltaRequest extracts and validates query parameters for GET /sync/delta.
@@ -476,5 +824,63 @@ func parseDeltaRequest(r *http.Request) (engramsync.DeltaRequest, error) {
 		req.Limit = limit
 	}
 
+	// Parse tables and exclude (optional, comma-separated)
+	req.Tables = splitQueryList(r.URL.Query().Get("tables"))
+	req.Exclude = splitQueryList(r.URL.Query().Get("exclude"))
+	req.ExcludeSource = r.URL.Query().Get("exclude_source")
+
+	// Parse since_time and until_time (optional, RFC 3339)
+	if v := r.URL.Query().Get("since_time"); v != "" {
+		if req.SinceTime, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return req, fmt.Errorf("invalid since_time parameter: must be an RFC 3339 timestamp")
+		}
+	}
+	if v := r.URL.Query().Get("until_time"); v != "" {
+		if req.UntilTime, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return req, fmt.Errorf("invalid until_time parameter: must be an RFC 3339 timestamp")
+		}
+	}
+	if !req.SinceTime.IsZero() && !req.UntilTime.IsZero() && !req.UntilTime.After(req.SinceTime) {
+		return req, fmt.Errorf("invalid until_time parameter: must be after since_time")
+	}
+
 	return req, nil
 }
+
+// splitQueryList splits a comma-separated query value, dropping blanks.
+func splitQueryList(v string) []string {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// excludePayloadFields removes top-level fields from a JSON object payload.
+// Payloads that are empty or not JSON objects are returned unchanged.
+func excludePayloadFields(payload json.RawMessage, fields []string) json.RawMessage {
+	if len(payload) == 0 {
+		return payload
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &obj); err != nil {
+		return payload
+	}
+	removed := false
+	for _, f := range fields {
+		if _, ok := obj[f]; ok {
+			delete(obj, f)
+			removed = true
+		}
+	}
+	if !removed {
+		return payload
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return payload
+	}
+	return b
+}
@@ -126,11 +126,6 @@ func setupSyncTestEnv(t *testing.T) (*multistore.StoreManager, *Handler, *multis
 		t.Fatalf("CreateStore() error = %v", err)
 	}
 
-	// Set schema version to 2
-	if err := managed.Store.SetSyncMeta(ctx, "schema_version", "2"); err != nil {
-		t.Fatalf("SetSyncMeta() error = %v", err)
-	}
-
 	defaultStore := &mockStore{stats: &types.StoreStats{}}
 	embedder := &mockEmbedder{model: "test-model"}
 	handler := NewHandler(defaultStore, manager, embedder, nil, "test-api-key", "1.0.0")
@@ -475,6 +470,55 @@ func TestSyncPush_ValidationFailure(t *testing.T) {
 	}
 }
 
+func TestSyncPush_PayloadSchemaErrors(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(validLorePayload(t, "e1"), &payload); err != nil {
+		t.Fatal(err)
+	}
+	payload["category"] = "GOSSIP"
+	delete(payload, "content")
+	body, _ := json.Marshal(payload)
+
+	req := engramsync.PushRequest{
+		PushID:        "schema-errors-push",
+		SourceID:      "client-1",
+		SchemaVersion: 2,
+		Entries: []engramsync.ChangeLogEntry{
+			{Sequence: 7, TableName: "lore_entries", EntityID: "e1", Operation: "upsert", Payload: body},
+		},
+	}
+
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/stores/test-store/sync/push", makePushBody(t, req))
+	httpReq.Header.Set("Authorization", "Bearer test-api-key")
+	httpReq.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, httpReq)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp engramsync.PushErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	got := make(map[string]string)
+	for _, e := range resp.Errors {
+		if e.Sequence != 7 || e.Code != engramsync.PushErrorValidation {
+			t.Errorf("error = %+v, want sequence 7 with code %s", e, engramsync.PushErrorValidation)
+		}
+		got[e.Field] = e.Constraint
+	}
+	if got["content"] != "required" || got["category"] != "enum" {
+		t.Errorf("errors = %+v, want content/required and category/enum", resp.Errors)
+	}
+}
+
 func TestSyncPush_AllOrNothing(t *testing.T) {
 	manager, handler, _ := setupSyncTestEnv(t)
 	defer manager.Close()
@@ -550,6 +594,62 @@ func TestSyncPush_ReplaySuccess(t *testing.T) {
 	}
 }
 
+func TestSyncPush_RunsPluginHooks(t *testing.T) {
+	manager, handler, managed := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	lorePayload := func(id, content string) json.RawMessage {
+		var payload map[string]interface{}
+		if err := json.Unmarshal(validLorePayload(t, id), &payload); err != nil {
+			t.Fatal(err)
+		}
+		payload["content"] = content
+		b, _ := json.Marshal(payload)
+		return b
+	}
+	push := func(pushID string, entries ...engramsync.ChangeLogEntry) *httptest.ResponseRecorder {
+		req := engramsync.PushRequest{PushID: pushID, SourceID: "client-1", SchemaVersion: 2, Entries: entries}
+		httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/stores/test-store/sync/push", makePushBody(t, req))
+		httpReq.Header.Set("Authorization", "Bearer test-api-key")
+		httpReq.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httpReq)
+		return w
+	}
+
+	// The recall plugin's BeforeIngest trims content
+	entryID := "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	w := push("hooks-trim-push", engramsync.ChangeLogEntry{
+		Sequence: 1, TableName: "lore_entries", EntityID: entryID, Operation: "upsert", Payload: lorePayload(entryID, "  Padded content\n"),
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	entry, err := managed.Store.GetLore(context.Background(), entryID)
+	if err != nil {
+		t.Fatalf("GetLore() error = %v", err)
+	}
+	if entry.Content != "Padded content" {
+		t.Errorf("Content = %q, want %q", entry.Content, "Padded content")
+	}
+
+	// ...and vetoes blank content
+	w = push("hooks-veto-push", engramsync.ChangeLogEntry{
+		Sequence: 2, TableName: "lore_entries", EntityID: "e2", Operation: "upsert", Payload: lorePayload("e2", " \t "),
+	})
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp engramsync.PushErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Errors) != 1 || resp.Errors[0].Sequence != 2 || !strings.Contains(resp.Errors[0].Message, "vetoed") {
+		t.Errorf("errors = %+v, want a veto of sequence 2", resp.Errors)
+	}
+}
+
 func TestSyncPush_ChangeLogRecorded(t *testing.T) {
 	manager, handler, managed := setupSyncTestEnv(t)
 	defer manager.Close()
@@ -862,6 +962,63 @@ func TestParseDeltaRequest_OverMaxLimit(t *testing.T) {
 	}
 }
 
+func TestParseDeltaRequest_TablesAndExclude(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/sync/delta?after=0&tables=lore_entries,%20goals,&exclude=embedding", nil)
+	req, err := parseDeltaRequest(r)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(req.Tables) != 2 || req.Tables[0] != "lore_entries" || req.Tables[1] != "goals" {
+		t.Errorf("Tables = %v, want [lore_entries goals]", req.Tables)
+	}
+	if len(req.Exclude) != 1 || req.Exclude[0] != "embedding" {
+		t.Errorf("Exclude = %v, want [embedding]", req.Exclude)
+	}
+}
+
+func TestParseDeltaRequest_TimeWindow(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/sync/delta?after=0&since_time=2026-03-01T00:00:00Z&until_time=2026-03-02T00:00:00Z", nil)
+	req, err := parseDeltaRequest(r)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !req.SinceTime.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) || !req.UntilTime.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("window = [%v, %v), want [2026-03-01, 2026-03-02)", req.SinceTime, req.UntilTime)
+	}
+
+	for _, query := range []string{
+		"after=0&since_time=yesterday",
+		"after=0&until_time=2026-03-02",
+		"after=0&since_time=2026-03-02T00:00:00Z&until_time=2026-03-01T00:00:00Z",
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/sync/delta?"+query, nil)
+		if _, err := parseDeltaRequest(r); err == nil {
+			t.Errorf("parseDeltaRequest(%s) returned no error", query)
+		}
+	}
+}
+
+func TestExcludePayloadFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{"removes field", `{"id":"a","embedding":"AAAA","content":"x"}`, `{"content":"x","id":"a"}`},
+		{"field absent", `{"id":"a"}`, `{"id":"a"}`},
+		{"not an object", `[1,2]`, `[1,2]`},
+		{"empty", ``, ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := excludePayloadFields(json.RawMessage(tt.payload), []string{"embedding"})
+			if string(got) != tt.want {
+				t.Errorf("excludePayloadFields() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
 // --- SyncDelta Handler Tests ---
 
 // pushEntries is a helper that pushes N entries into the store via SyncPush.
@@ -1265,6 +1422,55 @@ func TestSyncDelta_FullPagination(t *testing.T) {
 	}
 }
 
+func TestSyncDelta_CursorBeforeCompaction(t *testing.T) {
+	manager, handler, managed := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+	ctx := context.Background()
+
+	pushEntries(t, router, 5)
+	if err := managed.Store.SetLastCompaction(ctx, 3, time.Now()); err != nil {
+		t.Fatalf("SetLastCompaction() error = %v", err)
+	}
+
+	// Without a snapshot the base sequence is omitted
+	w := deltaRequest(t, router, 2, 0)
+	if w.Code != http.StatusGone {
+		t.Fatalf("expected 410, got %d: %s", w.Code, w.Body.String())
+	}
+	var raw map[string]json.RawMessage
+	json.NewDecoder(w.Body).Decode(&raw)
+	if _, ok := raw["snapshot_base_sequence"]; ok {
+		t.Errorf("snapshot_base_sequence reported without a snapshot: %s", raw["snapshot_base_sequence"])
+	}
+
+	if err := managed.Store.GenerateSnapshot(ctx); err != nil {
+		t.Fatalf("GenerateSnapshot() error = %v", err)
+	}
+	w = deltaRequest(t, router, 2, 0)
+	if w.Code != http.StatusGone {
+		t.Fatalf("expected 410, got %d: %s", w.Code, w.Body.String())
+	}
+	var problem ProblemHistoryCompacted
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.CompactedThrough != 3 {
+		t.Errorf("CompactedThrough = %d, want 3", problem.CompactedThrough)
+	}
+	if problem.Snapshot != "/api/v1/stores/test-store/sync/snapshot" {
+		t.Errorf("Snapshot = %q", problem.Snapshot)
+	}
+	if problem.SnapshotBaseSequence == nil || *problem.SnapshotBaseSequence != 5 {
+		t.Errorf("SnapshotBaseSequence = %v, want 5", problem.SnapshotBaseSequence)
+	}
+
+	// Nothing after the compacted sequence was removed
+	if w := deltaRequest(t, router, 3, 0); w.Code != http.StatusOK {
+		t.Errorf("delta after compacted sequence: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
 // --- SyncSnapshot Handler Tests ---
 
 func TestSyncSnapshot_Success(t *testing.T) {
@@ -1292,6 +1498,31 @@ func TestSyncSnapshot_Success(t *testing.T) {
 	}
 }
 
+func TestSyncSnapshot_BaseSequenceHeader(t *testing.T) {
+	manager, handler, managed := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+	ctx := context.Background()
+
+	pushEntries(t, router, 3)
+	if err := managed.Store.GenerateSnapshot(ctx); err != nil {
+		t.Fatalf("GenerateSnapshot() error = %v", err)
+	}
+	pushEntries(t, router, 1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stores/test-store/sync/snapshot", nil)
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("X-Snapshot-Base-Sequence"); got != "3" {
+		t.Errorf("X-Snapshot-Base-Sequence = %q, want 3", got)
+	}
+}
+
 func TestSyncSnapshot_ContentType(t *testing.T) {
 	testData := []byte("SQLite format 3\x00test snapshot data")
 	reader := io.NopCloser(bytes.NewReader(testData))
@@ -1612,11 +1843,6 @@ func setupTractTestEnv(t *testing.T) (*multistore.StoreManager, *Handler, *multi
 		t.Fatalf("CreateStore() error = %v", err)
 	}
 
-	// Set schema version to 1
-	if err := managed.Store.SetSyncMeta(ctx, "schema_version", "1"); err != nil {
-		t.Fatalf("SetSyncMeta() error = %v", err)
-	}
-
 	defaultStore := &mockStore{stats: &types.StoreStats{}}
 	embedder := &mockEmbedder{model: "test-model"}
 	handler := NewHandler(defaultStore, manager, embedder, nil, "test-api-key", "1.0.0")
@@ -1946,3 +2172,588 @@ func TestTractIntegration_MixedDeletesAndUpserts(t *testing.T) {
 		t.Error("expected upsert entry for new CSF in change_log")
 	}
 }
+
+func TestTractIntegration_CrossBatchParent(t *testing.T) {
+	manager, handler, _ := setupTractTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	// First push creates the goal; the second references it
+	pushes := []engramsync.PushRequest{
+		{
+			PushID: "tract-parent-push", SourceID: "client-1", SchemaVersion: 1,
+			Entries: []engramsync.ChangeLogEntry{
+				{TableName: "goals", EntityID: "goal-300", Operation: "upsert", Payload: validGoalPayload(t, "goal-300", nil)},
+			},
+		},
+		{
+			PushID: "tract-child-push", SourceID: "client-1", SchemaVersion: 1,
+			Entries: []engramsync.ChangeLogEntry{
+				{TableName: "csfs", EntityID: "csf-300", Operation: "upsert", Payload: validCSFPayload(t, "csf-300", "goal-300")},
+			},
+		},
+	}
+
+	for _, req := range pushes {
+		httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/stores/tract-store/sync/push", makePushBody(t, req))
+		httpReq.Header.Set("Authorization", "Bearer test-api-key")
+		httpReq.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httpReq)
+		if w.Code != http.StatusOK {
+			t.Fatalf("push %s: expected status 200, got %d: %s", req.PushID, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestTractIntegration_MissingParentRejected(t *testing.T) {
+	manager, handler, managed := setupTractTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	req := engramsync.PushRequest{
+		PushID:        "tract-orphan-push",
+		SourceID:      "client-1",
+		SchemaVersion: 1,
+		Entries: []engramsync.ChangeLogEntry{
+			{TableName: "csfs", EntityID: "csf-400", Operation: "upsert", Payload: validCSFPayload(t, "csf-400", "goal-missing")},
+			{TableName: "fwus", EntityID: "fwu-400", Operation: "upsert", Payload: validFWUPayload(t, "fwu-400", "csf-400")},
+		},
+	}
+
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/stores/tract-store/sync/push", makePushBody(t, req))
+	httpReq.Header.Set("Authorization", "Bearer test-api-key")
+	httpReq.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httpReq)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp engramsync.PushErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	// fwu-400's parent is in the batch, so only the CSF is reported
+	if len(resp.Errors) != 1 || resp.Errors[0].EntityID != "csf-400" {
+		t.Fatalf("expected one error for csf-400, got %+v", resp.Errors)
+	}
+	if resp.Errors[0].Code != engramsync.PushErrorMissingParent {
+		t.Errorf("expected code %s, got %s", engramsync.PushErrorMissingParent, resp.Errors[0].Code)
+	}
+	if got := resp.MissingParents["goals"]; len(got) != 1 || got[0] != "goal-missing" {
+		t.Errorf("expected missing_parents.goals = [goal-missing], got %v", resp.MissingParents)
+	}
+
+	seq, err := managed.Store.GetLatestSequence(context.Background())
+	if err != nil {
+		t.Fatalf("GetLatestSequence() error = %v", err)
+	}
+	if seq != 0 {
+		t.Errorf("expected nothing written to change_log, got sequence %d", seq)
+	}
+}
+
+func TestTractIntegration_AllowDeferredParents(t *testing.T) {
+	manager, handler, _ := setupTractTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	req := engramsync.PushRequest{
+		PushID:               "tract-deferred-push",
+		SourceID:             "client-1",
+		SchemaVersion:        1,
+		AllowDeferredParents: true,
+		Entries: []engramsync.ChangeLogEntry{
+			{TableName: "csfs", EntityID: "csf-500", Operation: "upsert", Payload: validCSFPayload(t, "csf-500", "goal-later")},
+		},
+	}
+
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/stores/tract-store/sync/push", makePushBody(t, req))
+	httpReq.Header.Set("Authorization", "Bearer test-api-key")
+	httpReq.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httpReq)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTractIntegration_CascadeDeletes(t *testing.T) {
+	manager, handler, managed := setupTractTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	push := func(req engramsync.PushRequest) *httptest.ResponseRecorder {
+		httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/stores/tract-store/sync/push", makePushBody(t, req))
+		httpReq.Header.Set("Authorization", "Bearer test-api-key")
+		httpReq.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httpReq)
+		return w
+	}
+
+	w := push(engramsync.PushRequest{
+		PushID: "tract-cascade-setup", SourceID: "client-1", SchemaVersion: 1,
+		Entries: []engramsync.ChangeLogEntry{
+			{TableName: "goals", EntityID: "goal-600", Operation: "upsert", Payload: validGoalPayload(t, "goal-600", nil)},
+			{TableName: "csfs", EntityID: "csf-600", Operation: "upsert", Payload: validCSFPayload(t, "csf-600", "goal-600")},
+			{TableName: "fwus", EntityID: "fwu-600", Operation: "upsert", Payload: validFWUPayload(t, "fwu-600", "csf-600")},
+			{TableName: "implementation_contexts", EntityID: "ic-600", Operation: "upsert", Payload: validICPayload(t, "ic-600", "fwu-600")},
+		},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("setup push failed: %d: %s", w.Code, w.Body.String())
+	}
+
+	w = push(engramsync.PushRequest{
+		PushID: "tract-cascade-delete", SourceID: "client-1", SchemaVersion: 1,
+		CascadeDeletes: true,
+		Entries: []engramsync.ChangeLogEntry{
+			{TableName: "goals", EntityID: "goal-600", Operation: "delete"},
+		},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("cascade push failed: %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp engramsync.PushResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Accepted != 4 || resp.Cascaded != 3 {
+		t.Errorf("expected accepted=4 cascaded=3, got %+v", resp)
+	}
+
+	changeLog, err := managed.Store.GetChangeLogAfter(context.Background(), 4, 100)
+	if err != nil {
+		t.Fatalf("GetChangeLogAfter() error = %v", err)
+	}
+	want := []string{"ic-600", "fwu-600", "csf-600", "goal-600"}
+	if len(changeLog) != len(want) {
+		t.Fatalf("expected %d delete entries, got %d", len(want), len(changeLog))
+	}
+	for i, id := range want {
+		if changeLog[i].EntityID != id || changeLog[i].Operation != "delete" {
+			t.Errorf("entry %d: expected delete of %s, got %s %s", i, id, changeLog[i].Operation, changeLog[i].EntityID)
+		}
+		if changeLog[i].SourceID != "client-1" {
+			t.Errorf("entry %d: expected source_id client-1, got %s", i, changeLog[i].SourceID)
+		}
+	}
+}
+
+func TestTractIntegration_DeleteWithoutCascade(t *testing.T) {
+	manager, handler, managed := setupTractTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	for _, req := range []engramsync.PushRequest{
+		{
+			PushID: "tract-nocascade-setup", SourceID: "client-1", SchemaVersion: 1,
+			Entries: []engramsync.ChangeLogEntry{
+				{TableName: "goals", EntityID: "goal-700", Operation: "upsert", Payload: validGoalPayload(t, "goal-700", nil)},
+				{TableName: "csfs", EntityID: "csf-700", Operation: "upsert", Payload: validCSFPayload(t, "csf-700", "goal-700")},
+			},
+		},
+		{
+			PushID: "tract-nocascade-delete", SourceID: "client-1", SchemaVersion: 1,
+			Entries: []engramsync.ChangeLogEntry{
+				{TableName: "goals", EntityID: "goal-700", Operation: "delete"},
+			},
+		},
+	} {
+		httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/stores/tract-store/sync/push", makePushBody(t, req))
+		httpReq.Header.Set("Authorization", "Bearer test-api-key")
+		httpReq.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httpReq)
+		if w.Code != http.StatusOK {
+			t.Fatalf("push %s failed: %d: %s", req.PushID, w.Code, w.Body.String())
+		}
+	}
+
+	seq, err := managed.Store.GetLatestSequence(context.Background())
+	if err != nil {
+		t.Fatalf("GetLatestSequence() error = %v", err)
+	}
+	if seq != 3 {
+		t.Errorf("expected only the explicit delete to be recorded (seq 3), got %d", seq)
+	}
+}
+
+func TestSyncDelta_TablesFilter(t *testing.T) {
+	manager, handler, managed := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	ctx := context.Background()
+	for i, table := range []string{"lore_entries", "other_table", "lore_entries", "other_table", "lore_entries"} {
+		_, err := managed.Store.AppendChangeLog(ctx, &engramsync.ChangeLogEntry{
+			TableName: table,
+			EntityID:  fmt.Sprintf("e-%d", i),
+			Operation: "upsert",
+			Payload:   json.RawMessage(`{}`),
+			SourceID:  "test-client",
+			CreatedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			t.Fatalf("AppendChangeLog() error = %v", err)
+		}
+	}
+
+	httpReq := httptest.NewRequest(http.MethodGet, "/api/v1/stores/test-store/sync/delta?after=0&limit=2&tables=lore_entries", nil)
+	httpReq.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httpReq)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The limit applies to matching entries, so paging still works
+	resp := decodeDeltaResponse(t, w)
+	if len(resp.Entries) != 2 || resp.Entries[0].Sequence != 1 || resp.Entries[1].Sequence != 3 {
+		t.Fatalf("expected sequences [1 3], got %+v", resp.Entries)
+	}
+	if resp.LastSequence != 3 || !resp.HasMore {
+		t.Errorf("expected last_sequence=3 has_more=true, got %d %v", resp.LastSequence, resp.HasMore)
+	}
+}
+
+func TestSyncDelta_ExcludeFields(t *testing.T) {
+	manager, handler, managed := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	_, err := managed.Store.AppendChangeLog(context.Background(), &engramsync.ChangeLogEntry{
+		TableName: "lore_entries",
+		EntityID:  "e-1",
+		Operation: "upsert",
+		Payload:   json.RawMessage(`{"id":"e-1","content":"hello","embedding":"AAAAAA=="}`),
+		SourceID:  "test-client",
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		t.Fatalf("AppendChangeLog() error = %v", err)
+	}
+
+	httpReq := httptest.NewRequest(http.MethodGet, "/api/v1/stores/test-store/sync/delta?after=0&exclude=embedding", nil)
+	httpReq.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httpReq)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeDeltaResponse(t, w)
+	if len(resp.Entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(resp.Entries))
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(resp.Entries[0].Payload, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if _, ok := payload["embedding"]; ok {
+		t.Error("expected embedding to be excluded from payload")
+	}
+	if payload["content"] != "hello" {
+		t.Errorf("expected content to be preserved, got %v", payload["content"])
+	}
+}
+
+func TestSyncDelta_SourceSequence(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	entries := loreEntries(t, 1, 2)
+	entries[0].Sequence = 17
+	entries[1].Sequence = 18
+	w := doSyncRequest(t, router, http.MethodPost, "/push", engramsync.PushRequest{
+		PushID:        "push-source-seq",
+		SourceID:      "test-client",
+		SchemaVersion: 2,
+		Entries:       entries,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("push: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeDeltaResponse(t, deltaRequest(t, router, 0, 0))
+	if len(resp.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(resp.Entries))
+	}
+	for i, want := range []int64{17, 18} {
+		e := resp.Entries[i]
+		if e.SourceSequence != want {
+			t.Errorf("entry %d: source_sequence = %d, want %d", i, e.SourceSequence, want)
+		}
+		if e.Sequence != int64(i+1) {
+			t.Errorf("entry %d: sequence = %d, want server-assigned %d", i, e.Sequence, i+1)
+		}
+		if e.SourceID != "test-client" {
+			t.Errorf("entry %d: source_id = %q, want test-client", i, e.SourceID)
+		}
+	}
+}
+
+func TestSyncDelta_ExcludeSource(t *testing.T) {
+	manager, handler, managed := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	ctx := context.Background()
+	for i, source := range []string{"other-client", "test-client", "test-client"} {
+		_, err := managed.Store.AppendChangeLog(ctx, &engramsync.ChangeLogEntry{
+			TableName: "lore_entries",
+			EntityID:  fmt.Sprintf("e-%d", i),
+			Operation: "upsert",
+			Payload:   json.RawMessage(`{}`),
+			SourceID:  source,
+			CreatedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			t.Fatalf("AppendChangeLog() error = %v", err)
+		}
+	}
+
+	httpReq := httptest.NewRequest(http.MethodGet, "/api/v1/stores/test-store/sync/delta?after=0&exclude_source=test-client", nil)
+	httpReq.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httpReq)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeDeltaResponse(t, w)
+	if len(resp.Entries) != 1 || resp.Entries[0].SourceID != "other-client" {
+		t.Fatalf("expected only other-client entry, got %+v", resp.Entries)
+	}
+	// The cursor moves past the suppressed echoes
+	if resp.LastSequence != 3 || resp.HasMore {
+		t.Errorf("expected last_sequence=3 has_more=false, got %d %v", resp.LastSequence, resp.HasMore)
+	}
+}
+
+func TestSyncDelta_ExcludeSourceAllOwn(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+	pushEntries(t, router, 3)
+
+	httpReq := httptest.NewRequest(http.MethodGet, "/api/v1/stores/test-store/sync/delta?after=0&exclude_source=test-client", nil)
+	httpReq.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httpReq)
+
+	resp := decodeDeltaResponse(t, w)
+	if len(resp.Entries) != 0 {
+		t.Fatalf("expected no entries, got %d", len(resp.Entries))
+	}
+	if resp.LastSequence != 3 {
+		t.Errorf("last_sequence = %d, want 3", resp.LastSequence)
+	}
+}
+
+// --- Table Sync Policy Tests ---
+
+// guardedTractPlugin is the tract plugin with implementation contexts
+// append-only and CSFs server-only for clients.
+type guardedTractPlugin struct {
+	*tract.Plugin
+}
+
+func (p *guardedTractPlugin) Type() string { return "guarded-tract" }
+
+func (p *guardedTractPlugin) TableSchemas() []plugin.TableSchema {
+	schemas := p.Plugin.TableSchemas()
+	for i := range schemas {
+		switch schemas[i].Name {
+		case "implementation_contexts":
+			schemas[i].SyncPolicy = plugin.SyncAppendOnly
+		case "csfs":
+			schemas[i].SyncPolicy = plugin.SyncServerOnly
+		}
+	}
+	return schemas
+}
+
+func TestSyncPush_EnforcesTableSyncPolicies(t *testing.T) {
+	func() {
+		defer func() { recover() }()
+		plugin.Register(&guardedTractPlugin{tract.New()})
+	}()
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+	ctx := context.Background()
+	managed, err := manager.CreateStore(ctx, "guarded", "guarded-tract", "")
+	if err != nil {
+		t.Fatalf("CreateStore() error = %v", err)
+	}
+	handler := NewHandler(&mockStore{stats: &types.StoreStats{}}, manager, &mockEmbedder{model: "test-model"}, nil, "test-api-key", "1.0.0")
+	router := NewRouter(handler, manager)
+
+	push := func(pushID string, entries ...engramsync.ChangeLogEntry) *httptest.ResponseRecorder {
+		req := engramsync.PushRequest{PushID: pushID, SourceID: "client-1", SchemaVersion: 2, Entries: entries}
+		httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/stores/guarded/sync/push", makePushBody(t, req))
+		httpReq.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httpReq)
+		return w
+	}
+	upsert := func(seq int64, table, id string, payload json.RawMessage) engramsync.ChangeLogEntry {
+		return engramsync.ChangeLogEntry{Sequence: seq, TableName: table, EntityID: id, Operation: engramsync.OperationUpsert, Payload: payload}
+	}
+
+	// Parents are seeded server-side, as CSFs are server-only
+	if _, err := managed.Store.AppendChangeLogBatch(ctx, []engramsync.ChangeLogEntry{
+		{TableName: "csfs", EntityID: "C-1", Operation: engramsync.OperationUpsert, Payload: validCSFPayload(t, "C-1", "G-1"), SourceID: "server", CreatedAt: time.Now().UTC()},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if w := push("seed", upsert(1, "goals", "G-1", validGoalPayload(t, "G-1", nil)), upsert(2, "fwus", "F-1", validFWUPayload(t, "F-1", "C-1"))); w.Code != http.StatusOK {
+		t.Fatalf("seed push: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := push("append", upsert(1, "implementation_contexts", "IC-1", validICPayload(t, "IC-1", "F-1"))); w.Code != http.StatusOK {
+		t.Fatalf("append-only insert: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	for name, entry := range map[string]engramsync.ChangeLogEntry{
+		"append-only update": upsert(1, "implementation_contexts", "IC-1", validICPayload(t, "IC-1", "F-1")),
+		"append-only delete": {Sequence: 1, TableName: "implementation_contexts", EntityID: "IC-1", Operation: engramsync.OperationDelete},
+		"server-only upsert": upsert(1, "csfs", "C-2", validCSFPayload(t, "C-2", "G-1")),
+	} {
+		if w := push(name, entry); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: expected 422, got %d: %s", name, w.Code, w.Body.String())
+		}
+	}
+
+	httpReq := httptest.NewRequest(http.MethodGet, "/api/v1/stores/guarded/sync/delta?after=0", nil)
+	httpReq.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httpReq)
+	if w.Code != http.StatusOK {
+		t.Fatalf("delta: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var delta engramsync.DeltaResponse
+	if err := json.NewDecoder(w.Body).Decode(&delta); err != nil {
+		t.Fatal(err)
+	}
+	if len(delta.Entries) != 3 {
+		t.Errorf("delta returned %d entries, want 3", len(delta.Entries))
+	}
+	for _, e := range delta.Entries {
+		if e.TableName == "csfs" {
+			t.Errorf("delta included server-only entry %+v", e)
+		}
+	}
+}
+
+func TestSyncPush_PartialMode(t *testing.T) {
+	manager, handler, managed := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	send := func(push engramsync.PushRequest) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/stores/test-store/sync/push", makePushBody(t, push))
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	validID := "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	push := engramsync.PushRequest{
+		PushID:        "partial-push",
+		SourceID:      "client-1",
+		SchemaVersion: 2,
+		Mode:          engramsync.PushModePartial,
+		Entries: []engramsync.ChangeLogEntry{
+			{Sequence: 1, TableName: "lore_entries", EntityID: validID, Operation: "upsert", Payload: validLorePayload(t, validID)},
+			{Sequence: 2, TableName: "unknown_table", EntityID: "e2", Operation: "upsert", Payload: json.RawMessage(`{}`)},
+		},
+	}
+	w := send(push)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp engramsync.PushResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Accepted != 1 || len(resp.Rejected) != 1 || len(resp.Quarantined) != 0 {
+		t.Fatalf("response = %+v, want 1 accepted and 1 rejected", resp)
+	}
+	if resp.Rejected[0].Sequence != 2 || resp.Rejected[0].Code != engramsync.PushErrorValidation {
+		t.Errorf("rejected = %+v, want sequence 2 with VALIDATION_ERROR", resp.Rejected[0])
+	}
+	if entry, err := managed.Store.GetLore(context.Background(), validID); err != nil || entry == nil {
+		t.Errorf("valid entry not applied: %v", err)
+	}
+	if conflicts, _ := managed.Store.ListSyncConflicts(context.Background(), engramsync.SyncConflictFilter{}); len(conflicts) != 0 {
+		t.Errorf("partial push stored %d conflicts, want none", len(conflicts))
+	}
+
+	push.PushID = "bad-mode-push"
+	push.Mode = "lenient"
+	if w := send(push); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown mode: expected 400, got %d", w.Code)
+	}
+
+	push.PushID = "atomic-push"
+	push.Mode = engramsync.PushModeAtomic
+	if w := send(push); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("atomic push with an invalid entry: expected 422, got %d", w.Code)
+	}
+}
+
+func TestSyncPush_ClampsFutureTimestamps(t *testing.T) {
+	manager, handler, managed := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	id := "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	var payload map[string]interface{}
+	if err := json.Unmarshal(validLorePayload(t, id), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	payload["updated_at"] = time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+	raw, _ := json.Marshal(payload)
+
+	push := engramsync.PushRequest{
+		PushID:        "skewed-push",
+		SourceID:      "client-1",
+		SchemaVersion: 2,
+		Entries: []engramsync.ChangeLogEntry{
+			{Sequence: 1, TableName: "lore_entries", EntityID: id, Operation: "upsert", Payload: raw},
+		},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/stores/test-store/sync/push", makePushBody(t, push))
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp engramsync.PushResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Warnings) != 1 || resp.Warnings[0].Code != engramsync.PushWarningClockSkew || resp.Warnings[0].Field != "updated_at" {
+		t.Fatalf("warnings = %+v, want one CLOCK_SKEW warning for updated_at", resp.Warnings)
+	}
+
+	entry, err := managed.Store.GetLore(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetLore() error = %v", err)
+	}
+	if entry.UpdatedAt.After(time.Now()) {
+		t.Errorf("stored updated_at = %v, want clamped to server time", entry.UpdatedAt)
+	}
+}
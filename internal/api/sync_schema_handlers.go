@@ -0,0 +1,230 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hyperengineering/engram/internal/plugin"
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+// PluginSchemas handles GET /api/v1/plugins/schemas
+//
+// Lists, for every registered store type, the sync schema versions the
+// server supports and the server migrations each version requires.
+func (h *Handler) PluginSchemas(w http.ResponseWriter, r *http.Request) {
+	types := plugin.RegisteredTypes()
+	sort.Strings(types)
+
+	resp := PluginSchemasResponse{Plugins: make([]PluginSchema, 0, len(types))}
+	for _, t := range types {
+		p, _ := plugin.Get(t)
+		names := make(map[int]string)
+		for _, m := range p.Migrations() {
+			names[m.Version] = m.Name
+		}
+
+		versions := plugin.SchemaVersions(p)
+		schema := PluginSchema{
+			Type:           t,
+			CurrentVersion: plugin.CurrentSchemaVersion(p),
+			Versions:       make([]PluginSchemaVersion, 0, len(versions)),
+		}
+		for _, v := range versions {
+			sv := PluginSchemaVersion{Version: v.Version, Migrations: make([]PluginMigration, 0, len(v.Migrations))}
+			for _, m := range v.Migrations {
+				sv.Migrations = append(sv.Migrations, PluginMigration{Version: m, Name: names[m]})
+			}
+			schema.Versions = append(schema.Versions, sv)
+		}
+		resp.Plugins = append(resp.Plugins, schema)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// PluginSchemasResponse is the response for GET /api/v1/plugins/schemas.
+type PluginSchemasResponse struct {
+	Plugins []PluginSchema `json:"plugins"`
+}
+
+// PluginSchema describes the sync schema versions of one store type.
+type PluginSchema struct {
+	Type           string                `json:"type"`
+	CurrentVersion int                   `json:"current_version"`
+	Versions       []PluginSchemaVersion `json:"versions"`
+}
+
+// PluginSchemaVersion is one supported sync schema version and the server
+// migrations a store needs for it.
+type PluginSchemaVersion struct {
+	Version    int               `json:"version"`
+	Migrations []PluginMigration `json:"migrations"`
+}
+
+// PluginMigration identifies a plugin's server migration.
+type PluginMigration struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+}
+
+// PluginPayloadSchemas handles GET /api/v1/plugins/{type}/payload-schemas?schema_version=N
+//
+// Serves the JSON Schemas a store type validates pushed payloads against
+// at a sync schema version, defaulting to the current one, so clients can
+// validate entries before pushing them.
+func (h *Handler) PluginPayloadSchemas(w http.ResponseWriter, r *http.Request) {
+	storeType := chi.URLParam(r, "type")
+	p, found := plugin.Get(storeType)
+	if !found {
+		WriteProblem(w, r, http.StatusNotFound, fmt.Sprintf("Unknown store type: %s", storeType))
+		return
+	}
+
+	version := plugin.CurrentSchemaVersion(p)
+	if raw := r.URL.Query().Get("schema_version"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 {
+			WriteProblem(w, r, http.StatusBadRequest, "schema_version must be an integer >= 1")
+			return
+		}
+		if !plugin.SupportsSchemaVersion(p, v) {
+			WriteProblem(w, r, http.StatusNotFound, fmt.Sprintf("Store type %s does not support schema version %d", storeType, v))
+			return
+		}
+		version = v
+	}
+
+	resp := PayloadSchemasResponse{
+		Type:          storeType,
+		SchemaVersion: version,
+		Schemas:       plugin.PayloadSchemasAt(p, version),
+	}
+	if resp.Schemas == nil {
+		resp.Schemas = []plugin.PayloadSchema{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// PayloadSchemasResponse is the response for
+// GET /api/v1/plugins/{type}/payload-schemas.
+type PayloadSchemasResponse struct {
+	Type          string                 `json:"type"`
+	SchemaVersion int                    `json:"schema_version"`
+	Schemas       []plugin.PayloadSchema `json:"schemas"`
+}
+
+// SyncSchema handles GET /api/v1/stores/{store_id}/sync/schema?client_version=N
+//
+// Lets a client check its local schema against the store before syncing.
+// A client behind the server receives the plugin's forward migrations for
+// its replica; a client ahead of the server gets the same 409 as a push.
+func (h *Handler) SyncSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	storeID := StoreIDFromContext(ctx)
+
+	managed := h.requireSyncStore(w, r)
+	if managed == nil {
+		return
+	}
+
+	clientVersion, err := strconv.Atoi(r.URL.Query().Get("client_version"))
+	if err != nil || clientVersion < 1 {
+		WriteProblem(w, r, http.StatusBadRequest, "client_version must be an integer >= 1")
+		return
+	}
+
+	p, _ := plugin.Get(managed.Type())
+	serverVersion := managed.SchemaVersion(ctx)
+	if !checkClientSchema(w, r, p, clientVersion, serverVersion) {
+		return
+	}
+
+	resp := engramsync.SchemaNegotiationResponse{
+		ClientVersion:     clientVersion,
+		ServerVersion:     serverVersion,
+		SupportedVersions: supportedSchemaVersions(p, serverVersion),
+		Migrations:        plugin.ClientMigrationsBetween(p, clientVersion, serverVersion),
+	}
+	if resp.Migrations == nil {
+		resp.Migrations = []engramsync.ClientMigration{}
+	}
+
+	slog.Info("schema negotiated",
+		"component", "api",
+		"action", "sync_schema",
+		"store_id", storeID,
+		"client_version", clientVersion,
+		"server_version", serverVersion,
+		"migrations", len(resp.Migrations),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// checkClientSchema writes a 409 and returns false unless clientVersion is
+// a sync schema version the store can serve: no later than serverVersion
+// and one the store type's plugin supports.
+func checkClientSchema(w http.ResponseWriter, r *http.Request, p plugin.DomainPlugin, clientVersion, serverVersion int) bool {
+	if clientVersion > serverVersion {
+		writeSchemaMismatch(w, r, clientVersion, serverVersion)
+		return false
+	}
+	if p != nil && !plugin.SupportsSchemaVersion(p, clientVersion) {
+		writeUnsupportedSchema(w, r, clientVersion, serverVersion, supportedSchemaVersions(p, serverVersion))
+		return false
+	}
+	return true
+}
+
+// supportedSchemaVersions lists the versions of p's schema registry up to
+// serverVersion, the versions a client of the store may sync with.
+func supportedSchemaVersions(p plugin.DomainPlugin, serverVersion int) []int {
+	versions := []int{}
+	if p == nil {
+		return versions
+	}
+	for _, v := range plugin.SchemaVersions(p) {
+		if v.Version <= serverVersion {
+			versions = append(versions, v.Version)
+		}
+	}
+	return versions
+}
+
+// writeUnsupportedSchema writes a 409 response for a client schema version
+// missing from the store type's schema registry.
+func writeUnsupportedSchema(w http.ResponseWriter, r *http.Request, clientVersion, serverVersion int, supported []int) {
+	resp := struct {
+		Type              string `json:"type"`
+		Title             string `json:"title"`
+		Status            int    `json:"status"`
+		Detail            string `json:"detail"`
+		Instance          string `json:"instance"`
+		ClientVersion     int    `json:"client_version"`
+		ServerVersion     int    `json:"server_version"`
+		SupportedVersions []int  `json:"supported_versions"`
+	}{
+		Type:              "https://engram.dev/errors/schema-mismatch",
+		Title:             "Schema Version Mismatch",
+		Status:            http.StatusConflict,
+		Detail:            fmt.Sprintf("Client schema version %d is not supported by this store.", clientVersion),
+		Instance:          r.URL.Path,
+		ClientVersion:     clientVersion,
+		ServerVersion:     serverVersion,
+		SupportedVersions: supported,
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/plugin"
+	"github.com/hyperengineering/engram/internal/plugin/generic"
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func schemaRequest(t *testing.T, router http.Handler, storeID, clientVersion string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stores/"+storeID+"/sync/schema?client_version="+clientVersion, nil)
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSyncSchema_ClientBehindGetsMigrations(t *testing.T) {
+	manager, handler, _ := setupTractTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	w := schemaRequest(t, router, "tract-store", "1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp engramsync.SchemaNegotiationResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.ClientVersion != 1 || resp.ServerVersion != 2 {
+		t.Errorf("versions = %d/%d, want 1/2", resp.ClientVersion, resp.ServerVersion)
+	}
+	if len(resp.Migrations) != 1 || resp.Migrations[0].Version != 2 || resp.Migrations[0].SQL == "" {
+		t.Errorf("migrations = %+v, want one version 2 migration with SQL", resp.Migrations)
+	}
+}
+
+func TestSyncSchema_ClientCurrent(t *testing.T) {
+	manager, handler, _ := setupTractTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	w := schemaRequest(t, router, "tract-store", "2")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var raw map[string]json.RawMessage
+	json.NewDecoder(w.Body).Decode(&raw)
+	if string(raw["migrations"]) != "[]" {
+		t.Errorf("migrations = %s, want []", raw["migrations"])
+	}
+}
+
+func TestSyncSchema_ClientAhead(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	w := schemaRequest(t, router, "test-store", "3")
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected 409, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSyncSchema_PluginWithoutMigrations(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	w := schemaRequest(t, router, "test-store", "1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp engramsync.SchemaNegotiationResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Migrations) != 0 {
+		t.Errorf("migrations = %+v, want none for recall", resp.Migrations)
+	}
+}
+
+func TestSyncSchema_InvalidClientVersion(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	for _, v := range []string{"", "abc", "0"} {
+		if w := schemaRequest(t, router, "test-store", v); w.Code != http.StatusBadRequest {
+			t.Errorf("client_version=%q: expected 400, got %d", v, w.Code)
+		}
+	}
+}
+
+func TestSyncPush_BehindClientGetsSchemaUpgrade(t *testing.T) {
+	manager, handler, _ := setupTractTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	req := engramsync.PushRequest{
+		PushID:        "push-behind",
+		SourceID:      "tract-client",
+		SchemaVersion: 1,
+		Entries: []engramsync.ChangeLogEntry{{
+			TableName: "goals",
+			EntityID:  "G-1",
+			Operation: "upsert",
+			Payload:   validGoalPayload(t, "G-1", nil),
+		}},
+	}
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/stores/tract-store/sync/push", makePushBody(t, req))
+	httpReq.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httpReq)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp engramsync.PushResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.SchemaUpgrade == nil {
+		t.Fatal("expected schema_upgrade for behind client")
+	}
+	if resp.SchemaUpgrade.ServerVersion != 2 || len(resp.SchemaUpgrade.Migrations) != 1 {
+		t.Errorf("schema_upgrade = %+v, want server 2 with one migration", resp.SchemaUpgrade)
+	}
+}
+
+// gappedPlugin supports schema versions 1 and 3, but not 2.
+type gappedPlugin struct {
+	*generic.Plugin
+}
+
+func (p *gappedPlugin) Type() string { return "gapped" }
+
+func (p *gappedPlugin) SchemaVersions() []plugin.SchemaVersion {
+	return []plugin.SchemaVersion{{Version: 1}, {Version: 3}}
+}
+
+func TestSyncSchema_UnsupportedClientVersion(t *testing.T) {
+	func() {
+		defer func() { recover() }()
+		plugin.Register(&gappedPlugin{generic.New()})
+	}()
+	manager, _ := setupStoreManager(t)
+	defer manager.Close()
+	managed, err := manager.CreateStore(context.Background(), "gapped-store", "gapped", "")
+	if err != nil {
+		t.Fatalf("CreateStore() error = %v", err)
+	}
+	if v := managed.SchemaVersion(context.Background()); v != 3 {
+		t.Fatalf("SchemaVersion() = %d, want 3 from the registry", v)
+	}
+	handler := NewHandler(&mockStore{stats: &types.StoreStats{}}, manager, &mockEmbedder{model: "test-model"}, nil, "test-api-key", "1.0.0")
+	router := NewRouter(handler, manager)
+
+	w := schemaRequest(t, router, "gapped-store", "2")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", w.Code, w.Body.String())
+	}
+	var problem struct {
+		SupportedVersions []int `json:"supported_versions"`
+	}
+	json.NewDecoder(w.Body).Decode(&problem)
+	if len(problem.SupportedVersions) != 2 || problem.SupportedVersions[0] != 1 || problem.SupportedVersions[1] != 3 {
+		t.Errorf("supported_versions = %v, want [1 3]", problem.SupportedVersions)
+	}
+
+	w = schemaRequest(t, router, "gapped-store", "1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("client_version=1: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestPluginSchemas(t *testing.T) {
+	manager, handler, _ := setupTractTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/plugins/schemas", nil)
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp PluginSchemasResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	var tractSchema *PluginSchema
+	for i := range resp.Plugins {
+		if resp.Plugins[i].Type == "tract" {
+			tractSchema = &resp.Plugins[i]
+		}
+	}
+	if tractSchema == nil {
+		t.Fatalf("plugins = %+v, want tract listed", resp.Plugins)
+	}
+	if tractSchema.CurrentVersion != 2 || len(tractSchema.Versions) != 2 {
+		t.Fatalf("tract schema = %+v, want versions 1 and 2", tractSchema)
+	}
+	latest := tractSchema.Versions[1]
+	if len(latest.Migrations) != 1 || latest.Migrations[0].Version != 100 || latest.Migrations[0].Name != "create_tract_tables" {
+		t.Errorf("version 2 migrations = %+v, want create_tract_tables", latest.Migrations)
+	}
+}
+
+func TestPluginPayloadSchemas(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/v1/plugins/recall/payload-schemas")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp PayloadSchemasResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Type != "recall" || resp.SchemaVersion != 2 {
+		t.Errorf("response = %+v, want recall at version 2", resp)
+	}
+	if len(resp.Schemas) != 1 || resp.Schemas[0].Table != "lore_entries" {
+		t.Fatalf("schemas = %+v, want lore_entries", resp.Schemas)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(resp.Schemas[0].Schema, &schema); err != nil || schema["type"] != "object" {
+		t.Errorf("schema = %s, want an object schema", resp.Schemas[0].Schema)
+	}
+
+	for path, want := range map[string]int{
+		"/api/v1/plugins/recall/payload-schemas?schema_version=1": http.StatusOK,
+		"/api/v1/plugins/recall/payload-schemas?schema_version=9": http.StatusNotFound,
+		"/api/v1/plugins/recall/payload-schemas?schema_version=x": http.StatusBadRequest,
+		"/api/v1/plugins/unknown/payload-schemas":                 http.StatusNotFound,
+	} {
+		if w := get(path); w.Code != want {
+			t.Errorf("GET %s = %d, want %d: %s", path, w.Code, want, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/hyperengineering/engram/internal/store"
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+// ReplicaVerifier is implemented by stores that can checksum their replica
+// state at a change log sequence. Implemented by SQLiteStore.
+type ReplicaVerifier interface {
+	VerifyChangeLog(ctx context.Context, upto int64, hidden []string) (*engramsync.VerifyResponse, error)
+}
+
+// SyncVerify handles GET /api/v1/stores/{store_id}/sync/verify?upto=N
+//
+// Returns a checksum of the store's entities as of sequence upto,
+// defaulting to the latest sequence. A client compares it with the same
+// checksum over its replica and re-bootstraps from the snapshot if they
+// differ. Returns 410 if compaction has removed the state at upto.
+func (h *Handler) SyncVerify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	storeID := StoreIDFromContext(ctx)
+
+	s := h.getStoreForRequest(r)
+	if s == nil {
+		WriteProblem(w, r, http.StatusNotFound, "Store not found")
+		return
+	}
+	verifier, ok := s.(ReplicaVerifier)
+	if !ok {
+		WriteProblem(w, r, http.StatusNotImplemented, "Store does not support replica verification")
+		return
+	}
+
+	latest, err := s.GetLatestSequence(ctx)
+	if err != nil {
+		MapStoreError(w, r, err)
+		return
+	}
+	upto := latest
+	if raw := r.URL.Query().Get("upto"); raw != "" {
+		upto, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || upto < 0 {
+			WriteProblem(w, r, http.StatusBadRequest, "upto must be an integer >= 0")
+			return
+		}
+		if upto > latest {
+			WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("upto must not exceed the latest sequence %d", latest))
+			return
+		}
+	}
+
+	resp, err := verifier.VerifyChangeLog(ctx, upto, h.serverOnlyTables(r))
+	if errors.Is(err, store.ErrHistoryCompacted) {
+		WriteProblem(w, r, http.StatusGone,
+			"Change log history before this sequence has been compacted. Re-bootstrap from the snapshot.")
+		return
+	}
+	if err != nil {
+		slog.Error("replica verification failed",
+			"component", "api",
+			"action", "sync_verify_failed",
+			"store_id", storeID,
+			"upto", upto,
+			"error", err,
+		)
+		WriteProblem(w, r, http.StatusInternalServerError, "Failed to verify replica state")
+		return
+	}
+
+	slog.Debug("sync verify served",
+		"component", "api",
+		"action", "sync_verify",
+		"store_id", storeID,
+		"upto", upto,
+		"entities", resp.Entities,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
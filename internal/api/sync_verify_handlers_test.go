@@ -0,0 +1,79 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+func verifyRequest(t *testing.T, router http.Handler, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stores/test-store/sync/verify"+query, nil)
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSyncVerify(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	entryID := "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	push := engramsync.PushRequest{
+		PushID:        "verify-push",
+		SourceID:      "client-1",
+		SchemaVersion: 2,
+		Entries: []engramsync.ChangeLogEntry{
+			{TableName: "lore_entries", EntityID: entryID, Operation: "upsert", Payload: validLorePayload(t, entryID)},
+		},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/stores/test-store/sync/push", makePushBody(t, push))
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("push: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = verifyRequest(t, router, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var latest engramsync.VerifyResponse
+	if err := json.NewDecoder(w.Body).Decode(&latest); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if latest.Upto == 0 || latest.Entities != 1 || len(latest.Checksum) != len("sha256:")+64 {
+		t.Errorf("verify at latest = %+v, want one entity", latest)
+	}
+
+	w = verifyRequest(t, router, "?upto=0")
+	var empty engramsync.VerifyResponse
+	json.NewDecoder(w.Body).Decode(&empty)
+	if w.Code != http.StatusOK || empty.Entities != 0 || empty.Checksum == latest.Checksum {
+		t.Errorf("verify at 0 = %d %+v, want an empty state", w.Code, empty)
+	}
+}
+
+func TestSyncVerify_InvalidUpto(t *testing.T) {
+	manager, handler, _ := setupSyncTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	for _, query := range []string{"?upto=abc", "?upto=-1", "?upto=100"} {
+		w := verifyRequest(t, router, query)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d: %s", query, w.Code, w.Body.String())
+		}
+		if !bytes.Contains(w.Body.Bytes(), []byte("upto")) {
+			t.Errorf("%s: detail doesn't mention upto: %s", query, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/plugin/tract"
+)
+
+// requireTractStore resolves the managed store from the request and checks
+// that it is tract-typed. Writes an error response and returns nil otherwise.
+func (h *Handler) requireTractStore(w http.ResponseWriter, r *http.Request) *multistore.ManagedStore {
+	storeID := StoreIDFromContext(r.Context())
+
+	if h.storeManager == nil {
+		WriteProblem(w, r, http.StatusNotFound, "Store not found")
+		return nil
+	}
+	managed, err := h.storeManager.GetStore(r.Context(), storeID)
+	if err != nil {
+		WriteProblem(w, r, http.StatusNotFound, "Store not found")
+		return nil
+	}
+
+	if managed.Type() != "tract" {
+		WriteProblem(w, r, http.StatusBadRequest,
+			fmt.Sprintf("Endpoint /tract/* only valid for tract stores. Store %q has type %q",
+				storeID, managed.Type()))
+		return nil
+	}
+
+	return managed
+}
+
+// TractGoalSummary handles GET /api/v1/stores/{store_id}/tract/goals/{goal_id}/summary
+//
+// Returns completion, blocked FWUs, and stale implementation contexts for a
+// goal and its sub-goals. Accepts an optional stale_after duration query
+// parameter (default 336h).
+func (h *Handler) TractGoalSummary(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ctx := r.Context()
+	storeID := StoreIDFromContext(ctx)
+	goalID := chi.URLParam(r, "goal_id")
+
+	managed := h.requireTractStore(w, r)
+	if managed == nil {
+		return
+	}
+
+	staleAfter := tract.DefaultStaleAfter
+	if v := r.URL.Query().Get("stale_after"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			WriteProblem(w, r, http.StatusBadRequest, "stale_after must be a positive duration (e.g. 72h)")
+			return
+		}
+		staleAfter = d
+	}
+
+	latest, err := managed.Store.GetLatestEntries(ctx, tract.RollupTables)
+	if err != nil {
+		slog.Error("tract roll-up query failed",
+			"component", "api",
+			"action", "tract_summary_failed",
+			"store_id", storeID,
+			"error", err,
+		)
+		WriteProblem(w, r, http.StatusInternalServerError, "Internal error")
+		return
+	}
+
+	summary, err := tract.BuildGoalSummary(goalID, latest, time.Now().UTC(), staleAfter)
+	if err != nil {
+		if errors.Is(err, tract.ErrGoalNotFound) {
+			WriteProblem(w, r, http.StatusNotFound, "Goal not found")
+			return
+		}
+		slog.Error("tract roll-up failed",
+			"component", "api",
+			"action", "tract_summary_failed",
+			"store_id", storeID,
+			"error", err,
+		)
+		WriteProblem(w, r, http.StatusInternalServerError, "Internal error")
+		return
+	}
+
+	slog.Debug("tract goal summary",
+		"component", "api",
+		"action", "tract_summary",
+		"store_id", storeID,
+		"goal_id", goalID,
+		"fwu_count", summary.FWUCount,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/plugin/tract"
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+// pushTractGraph pushes a goal with one CSF and two FWUs, one of them blocked.
+func pushTractGraph(t *testing.T, router http.Handler) {
+	t.Helper()
+
+	var blocked map[string]interface{}
+	if err := json.Unmarshal(validFWUPayload(t, "fwu-2", "csf-1"), &blocked); err != nil {
+		t.Fatal(err)
+	}
+	blocked["status"] = "blocked"
+	blockedPayload, _ := json.Marshal(blocked)
+
+	req := engramsync.PushRequest{
+		PushID:        "tract-summary-push",
+		SourceID:      "client-1",
+		SchemaVersion: 1,
+		Entries: []engramsync.ChangeLogEntry{
+			{TableName: "goals", EntityID: "goal-1", Operation: "upsert", Payload: validGoalPayload(t, "goal-1", nil)},
+			{TableName: "csfs", EntityID: "csf-1", Operation: "upsert", Payload: validCSFPayload(t, "csf-1", "goal-1")},
+			{TableName: "fwus", EntityID: "fwu-1", Operation: "upsert", Payload: validFWUPayload(t, "fwu-1", "csf-1")},
+			{TableName: "fwus", EntityID: "fwu-2", Operation: "upsert", Payload: blockedPayload},
+		},
+	}
+
+	httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/stores/tract-store/sync/push", makePushBody(t, req))
+	httpReq.Header.Set("Authorization", "Bearer test-api-key")
+	httpReq.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httpReq)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("push: expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func getTractSummary(router http.Handler, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTractGoalSummary_Success(t *testing.T) {
+	manager, handler, _ := setupTractTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+	pushTractGraph(t, router)
+
+	w := getTractSummary(router, "/api/v1/stores/tract-store/tract/goals/goal-1/summary")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var summary tract.GoalSummary
+	if err := json.NewDecoder(w.Body).Decode(&summary); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if summary.GoalID != "goal-1" || summary.CSFCount != 1 || summary.FWUCount != 2 {
+		t.Errorf("summary = %+v, want goal-1 with 1 CSF and 2 FWUs", summary)
+	}
+	if len(summary.BlockedFWUs) != 1 || summary.BlockedFWUs[0].ID != "fwu-2" {
+		t.Errorf("BlockedFWUs = %+v, want [fwu-2]", summary.BlockedFWUs)
+	}
+	if summary.AsOfSequence != 4 {
+		t.Errorf("AsOfSequence = %d, want 4", summary.AsOfSequence)
+	}
+}
+
+func TestTractGoalSummary_GoalNotFound(t *testing.T) {
+	manager, handler, _ := setupTractTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	w := getTractSummary(router, "/api/v1/stores/tract-store/tract/goals/missing/summary")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTractGoalSummary_InvalidStaleAfter(t *testing.T) {
+	manager, handler, _ := setupTractTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+	pushTractGraph(t, router)
+
+	w := getTractSummary(router, "/api/v1/stores/tract-store/tract/goals/goal-1/summary?stale_after=soon")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTractGoalSummary_RecallStoreRejected(t *testing.T) {
+	manager, handler, _ := setupTractTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	if _, err := manager.CreateStore(context.Background(), "recall-store", "recall", "Recall store"); err != nil {
+		t.Fatalf("CreateStore() error = %v", err)
+	}
+
+	w := getTractSummary(router, "/api/v1/stores/recall-store/tract/goals/goal-1/summary")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTractGoalSummary_StoreNotFound(t *testing.T) {
+	manager, handler, _ := setupTractTestEnv(t)
+	defer manager.Close()
+	router := NewRouter(handler, manager)
+
+	w := getTractSummary(router, "/api/v1/stores/nope/tract/goals/goal-1/summary")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
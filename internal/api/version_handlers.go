@@ -0,0 +1,179 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/validation"
+)
+
+// UpdateLore handles PATCH /api/v1/lore/{id} and PATCH /api/v1/stores/{store_id}/lore/{id}
+//
+// Edits an entry's content, context and/or category; omitted fields are left
+// unchanged. The previous values are kept as a version that can be reverted
+// to. Changing the content queues the entry for re-embedding.
+//
+// If-Match is required, carrying the ETag from GetLore, so an edit never
+// silently overwrites one made since the caller read the entry.
+func (h *Handler) UpdateLore(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	storeID := StoreIDFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+	s := h.getStoreForRequest(r)
+
+	if err := validation.ValidateULID("id", id); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest,
+			"Invalid lore ID format: must be valid ULID")
+		return
+	}
+	if r.Header.Get("If-Match") == "" {
+		WriteProblem(w, r, http.StatusPreconditionRequired,
+			"If-Match header is required: send the entry's ETag")
+		return
+	}
+
+	var req types.LoreUpdate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid JSON: %s", err.Error()))
+		return
+	}
+	if errs := validation.ValidateLoreUpdate(req); len(errs) > 0 {
+		WriteProblemWithErrors(w, r, "Request contains invalid fields", errs)
+		return
+	}
+
+	ifVersion, ok := ifMatchVersion(w, r, s, id)
+	if !ok {
+		return
+	}
+
+	entry, err := s.UpdateLore(r.Context(), id, req, ifVersion)
+	if err != nil {
+		slog.Error("lore update failed",
+			"component", "api",
+			"action", "update_lore_failed",
+			"store_id", storeID,
+			"lore_id", id,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	slog.Info("lore updated",
+		"component", "api",
+		"action", "update_lore",
+		"store_id", storeID,
+		"lore_id", id,
+		"source_id", req.SourceID,
+		"request_id", GetRequestID(r.Context()),
+		"remote_addr", r.RemoteAddr,
+	)
+
+	setLoreETag(w, r, s, id)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entry)
+}
+
+// LoreVersions handles GET /api/v1/lore/{id}/versions and GET /api/v1/stores/{store_id}/lore/{id}/versions
+//
+// Lists the entry's prior content, context and category, newest first.
+func (h *Handler) LoreVersions(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	storeID := StoreIDFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+	s := h.getStoreForRequest(r)
+
+	if err := validation.ValidateULID("id", id); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest,
+			"Invalid lore ID format: must be valid ULID")
+		return
+	}
+
+	versions, err := s.ListLoreVersions(r.Context(), id)
+	if err != nil {
+		slog.Error("lore versions lookup failed",
+			"component", "api",
+			"action", "lore_versions_failed",
+			"store_id", storeID,
+			"lore_id", id,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+	if versions == nil {
+		versions = []types.LoreVersion{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(types.LoreVersionsResponse{LoreID: id, Versions: versions})
+}
+
+// RevertLore handles POST /api/v1/lore/{id}/versions/{version}/revert and
+// POST /api/v1/stores/{store_id}/lore/{id}/versions/{version}/revert
+//
+// Restores the entry to a prior version. The state being replaced is kept as
+// a new version. The X-Recall-Source-ID header identifies the caller.
+func (h *Handler) RevertLore(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRecallStore(w, r) {
+		return
+	}
+
+	storeID := StoreIDFromContext(r.Context())
+	id := chi.URLParam(r, "id")
+	s := h.getStoreForRequest(r)
+
+	if err := validation.ValidateULID("id", id); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest,
+			"Invalid lore ID format: must be valid ULID")
+		return
+	}
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version < 1 {
+		WriteProblem(w, r, http.StatusBadRequest, "Invalid version: must be a positive integer")
+		return
+	}
+
+	sourceID := extractSourceID(r)
+
+	entry, err := s.RevertLore(r.Context(), id, version, sourceID)
+	if err != nil {
+		slog.Error("lore revert failed",
+			"component", "api",
+			"action", "revert_lore_failed",
+			"store_id", storeID,
+			"lore_id", id,
+			"version", version,
+			"error", err,
+		)
+		MapStoreError(w, r, err)
+		return
+	}
+
+	slog.Info("lore reverted",
+		"component", "api",
+		"action", "revert_lore",
+		"store_id", storeID,
+		"lore_id", id,
+		"version", version,
+		"source_id", sourceID,
+		"request_id", GetRequestID(r.Context()),
+		"remote_addr", r.RemoteAddr,
+	)
+
+	setLoreETag(w, r, s, id)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entry)
+}
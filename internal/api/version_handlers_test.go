@@ -0,0 +1,274 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+const versionTestLoreID = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+
+func serveWithIfMatch(t *testing.T, s *mockStore, method, path, body, ifMatch string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := NewRouter(NewHandler(s, nil, &mockEmbedder{}, nil, "test-key", "1.0.0"), nil)
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-key")
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUpdateLore_Success(t *testing.T) {
+	s := &mockStore{updated: &types.LoreEntry{ID: versionTestLoreID, Content: "Edited"}}
+
+	w := serveWithIfMatch(t, s, http.MethodPatch, "/api/v1/lore/"+versionTestLoreID,
+		`{"content":"Edited","source_id":"alice"}`, `"v3"`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if s.lastUpdate == nil || *s.lastUpdate.Content != "Edited" || s.lastUpdate.Context != nil || s.lastUpdate.SourceID != "alice" {
+		t.Errorf("store update = %+v", s.lastUpdate)
+	}
+	if s.lastIfVersion != 3 {
+		t.Errorf("if version = %d, want 3", s.lastIfVersion)
+	}
+	if got := w.Header().Get("ETag"); got != `"v1"` {
+		t.Errorf("ETag = %q, want the version after the edit", got)
+	}
+	var entry types.LoreEntry
+	if err := json.NewDecoder(w.Body).Decode(&entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Content != "Edited" {
+		t.Errorf("content = %q, want Edited", entry.Content)
+	}
+}
+
+func TestUpdateLore_InvalidRequest(t *testing.T) {
+	for name, tt := range map[string]struct {
+		path, body string
+		want       int
+	}{
+		"bad id":       {"/api/v1/lore/not-a-ulid", `{"content":"x","source_id":"alice"}`, http.StatusBadRequest},
+		"bad json":     {"/api/v1/lore/" + versionTestLoreID, `{`, http.StatusBadRequest},
+		"no fields":    {"/api/v1/lore/" + versionTestLoreID, `{"source_id":"alice"}`, http.StatusUnprocessableEntity},
+		"bad category": {"/api/v1/lore/" + versionTestLoreID, `{"category":"NOPE","source_id":"alice"}`, http.StatusUnprocessableEntity},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := &mockStore{}
+			w := serveWithIfMatch(t, s, http.MethodPatch, tt.path, tt.body, "*")
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+			if s.lastUpdate != nil {
+				t.Error("store should not be updated for an invalid request")
+			}
+		})
+	}
+}
+
+func TestUpdateLore_NotFound(t *testing.T) {
+	s := &mockStore{versionErr: store.ErrNotFound}
+
+	w := serveWithIfMatch(t, s, http.MethodPatch, "/api/v1/lore/"+versionTestLoreID, `{"context":"x","source_id":"alice"}`, "*")
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
+func TestUpdateLore_RequiresIfMatch(t *testing.T) {
+	s := &mockStore{}
+
+	w := serveWithIfMatch(t, s, http.MethodPatch, "/api/v1/lore/"+versionTestLoreID, `{"context":"x","source_id":"alice"}`, "")
+
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("status = %d, want 428", w.Code)
+	}
+	if s.lastUpdate != nil {
+		t.Error("store should not be updated without If-Match")
+	}
+}
+
+func TestUpdateLore_StaleIfMatch(t *testing.T) {
+	for name, tt := range map[string]struct {
+		ifMatch string
+		store   *mockStore
+	}{
+		"store mismatch":    {`"v2"`, &mockStore{versionErr: store.ErrVersionMismatch}},
+		"no listed version": {`"v1", "v2"`, &mockStore{loreVersion: 3}},
+		"weak tag":          {`W/"v3"`, &mockStore{loreVersion: 3}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := serveWithIfMatch(t, tt.store, http.MethodPatch, "/api/v1/lore/"+versionTestLoreID,
+				`{"context":"x","source_id":"alice"}`, tt.ifMatch)
+			if w.Code != http.StatusPreconditionFailed {
+				t.Errorf("status = %d, want 412", w.Code)
+			}
+		})
+	}
+}
+
+func TestUpdateLore_IfMatchList(t *testing.T) {
+	s := &mockStore{loreVersion: 3}
+
+	w := serveWithIfMatch(t, s, http.MethodPatch, "/api/v1/lore/"+versionTestLoreID,
+		`{"context":"x","source_id":"alice"}`, `"v2", "v3"`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if s.lastIfVersion != 3 {
+		t.Errorf("if version = %d, want the matching listed version", s.lastIfVersion)
+	}
+}
+
+func TestGetLore_SetsETag(t *testing.T) {
+	s := &mockStore{entry: &types.LoreEntry{ID: versionTestLoreID, Content: "Stored"}, loreVersion: 4}
+
+	w := serveReview(t, s, http.MethodGet, "/api/v1/lore/"+versionTestLoreID, "")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("ETag"); got != `"v4"` {
+		t.Errorf("ETag = %q, want \"v4\"", got)
+	}
+	var entry types.LoreEntry
+	if err := json.NewDecoder(w.Body).Decode(&entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Content != "Stored" {
+		t.Errorf("content = %q, want Stored", entry.Content)
+	}
+}
+
+func TestGetLore_NotFound(t *testing.T) {
+	w := serveReview(t, &mockStore{}, http.MethodGet, "/api/v1/lore/"+versionTestLoreID, "")
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
+func TestDeleteLore_IfMatch(t *testing.T) {
+	s := &mockStore{}
+
+	w := serveWithIfMatch(t, s, http.MethodDelete, "/api/v1/lore/"+versionTestLoreID, "", `"v2"`)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if s.lastIfVersion != 2 {
+		t.Errorf("if version = %d, want 2", s.lastIfVersion)
+	}
+}
+
+func TestDeleteLore_RequiresIfMatch(t *testing.T) {
+	// A delete that reached the store would surface this error as a 500
+	s := &mockStore{deleteErr: errors.New("store should not be called")}
+
+	w := serveWithIfMatch(t, s, http.MethodDelete, "/api/v1/lore/"+versionTestLoreID, "", "")
+
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("status = %d, want 428", w.Code)
+	}
+}
+
+func TestDeleteLore_IfMatchWildcard(t *testing.T) {
+	s := &mockStore{}
+
+	w := serveWithIfMatch(t, s, http.MethodDelete, "/api/v1/lore/"+versionTestLoreID, "", "*")
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if s.lastIfVersion != 0 {
+		t.Errorf("if version = %d, want 0 for a wildcard", s.lastIfVersion)
+	}
+}
+
+func TestDeleteLore_StaleIfMatch(t *testing.T) {
+	s := &mockStore{deleteErr: store.ErrVersionMismatch}
+
+	w := serveWithIfMatch(t, s, http.MethodDelete, "/api/v1/lore/"+versionTestLoreID, "", `"v1"`)
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("status = %d, want 412", w.Code)
+	}
+}
+
+func TestLoreVersions_Success(t *testing.T) {
+	s := &mockStore{versions: []types.LoreVersion{{Version: 1, Content: "Original", Reason: "edit"}}}
+
+	w := serveReview(t, s, http.MethodGet, "/api/v1/lore/"+versionTestLoreID+"/versions", "")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp types.LoreVersionsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.LoreID != versionTestLoreID || len(resp.Versions) != 1 || resp.Versions[0].Content != "Original" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestLoreVersions_EmptyArray(t *testing.T) {
+	w := serveReview(t, &mockStore{}, http.MethodGet, "/api/v1/lore/"+versionTestLoreID+"/versions", "")
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(w.Body).Decode(&raw); err != nil {
+		t.Fatal(err)
+	}
+	if string(raw["versions"]) != "[]" {
+		t.Errorf("versions = %s, want []", raw["versions"])
+	}
+}
+
+func TestRevertLore_Success(t *testing.T) {
+	s := &mockStore{updated: &types.LoreEntry{ID: versionTestLoreID, Content: "Original"}}
+
+	w := serveReview(t, s, http.MethodPost, "/api/v1/lore/"+versionTestLoreID+"/versions/2/revert", "")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if s.lastRevert != 2 {
+		t.Errorf("reverted to version %d, want 2", s.lastRevert)
+	}
+}
+
+func TestRevertLore_InvalidVersion(t *testing.T) {
+	for _, version := range []string{"0", "-1", "latest"} {
+		s := &mockStore{}
+		w := serveReview(t, s, http.MethodPost, "/api/v1/lore/"+versionTestLoreID+"/versions/"+version+"/revert", "")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("version %s: status = %d, want 400", version, w.Code)
+		}
+		if s.lastRevert != 0 {
+			t.Errorf("version %s: store should not be called", version)
+		}
+	}
+}
+
+func TestRevertLore_UnknownVersion(t *testing.T) {
+	s := &mockStore{versionErr: store.ErrVersionNotFound}
+
+	w := serveReview(t, s, http.MethodPost, "/api/v1/lore/"+versionTestLoreID+"/versions/7/revert", "")
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/webhook"
+)
+
+// recordingNotifier captures webhook events emitted by handlers.
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []webhook.Event
+}
+
+func (n *recordingNotifier) Notify(event webhook.Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events = append(n.events, event)
+}
+
+func (n *recordingNotifier) types() []webhook.EventType {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	out := make([]webhook.EventType, len(n.events))
+	for i, e := range n.events {
+		out[i] = e.Type
+	}
+	return out
+}
+
+func newNotifyingHandler(s store.Store) (*Handler, *recordingNotifier) {
+	n := &recordingNotifier{}
+	h := newTestHandler(s, &mockEmbedder{model: "text-embedding-3-small"}, "api-key", "1.0.0")
+	WithNotifier(n)(h)
+	return h, n
+}
+
+func TestIngestLore_NotifiesIngestedAndMerged(t *testing.T) {
+	s := &mockStore{ingestResult: &types.IngestResult{Accepted: 1, Merged: 1, Errors: []string{}}}
+	handler, notifier := newNotifyingHandler(s)
+
+	body := `{
+		"source_id": "devcontainer-abc123",
+		"lore": [
+			{"content": "First insight about Go patterns", "category": "PATTERN_OUTCOME", "confidence": 0.8},
+			{"content": "Similar insight about Go patterns", "category": "PATTERN_OUTCOME", "confidence": 0.7}
+		]
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lore", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.IngestLore(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	got := notifier.types()
+	if len(got) != 2 || got[0] != webhook.EventLoreIngested || got[1] != webhook.EventLoreMerged {
+		t.Fatalf("events = %v, want [lore.ingested lore.merged]", got)
+	}
+
+	ingest := notifier.events[0].Data.(webhook.IngestData)
+	if ingest.Accepted != 1 || ingest.Merged != 1 || ingest.SourceID != "devcontainer-abc123" {
+		t.Errorf("unexpected ingest data: %+v", ingest)
+	}
+	if notifier.events[0].StoreID != "default" {
+		t.Errorf("store_id = %q, want default", notifier.events[0].StoreID)
+	}
+}
+
+func TestIngestLore_NoNotificationWhenNothingStored(t *testing.T) {
+	s := &mockStore{ingestResult: &types.IngestResult{Rejected: 1, Errors: []string{"vetoed"}}}
+	handler, notifier := newNotifyingHandler(s)
+
+	body := `{"source_id": "src", "lore": [{"content": "Vetoed", "category": "PATTERN_OUTCOME", "confidence": 0.5}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lore", strings.NewReader(body))
+	handler.IngestLore(httptest.NewRecorder(), req)
+
+	if got := notifier.types(); len(got) != 0 {
+		t.Errorf("events = %v, want none", got)
+	}
+}
+
+func TestFeedback_NotifiesLowConfidenceTransition(t *testing.T) {
+	s := &mockStore{
+		feedbackResult: &types.FeedbackResult{
+			Updates: []types.FeedbackResultUpdate{
+				{LoreID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", PreviousConfidence: 0.4, CurrentConfidence: 0.2},
+				{LoreID: "01ARZ3NDEKTSV4RRFFQ69G5FAW", PreviousConfidence: 0.9, CurrentConfidence: 0.7},
+			},
+		},
+	}
+	handler, notifier := newNotifyingHandler(s)
+
+	body := `{
+		"source_id": "devcontainer-abc123",
+		"feedback": [
+			{"lore_id": "01ARZ3NDEKTSV4RRFFQ69G5FAV", "type": "incorrect"},
+			{"lore_id": "01ARZ3NDEKTSV4RRFFQ69G5FAW", "type": "incorrect"}
+		]
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lore/feedback", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.Feedback(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if got := notifier.types(); len(got) != 1 || got[0] != webhook.EventLoreLowConfidence {
+		t.Fatalf("events = %v, want [lore.low_confidence]", got)
+	}
+	data := notifier.events[0].Data.(webhook.LowConfidenceData)
+	if data.LoreID != "01ARZ3NDEKTSV4RRFFQ69G5FAV" || data.Reason != "feedback" {
+		t.Errorf("unexpected data: %+v", data)
+	}
+}
+
+func TestDeleteLore_NotifiesDeleted(t *testing.T) {
+	handler, notifier := newNotifyingHandler(&mockStore{})
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV", nil)
+	req.Header.Set("If-Match", "*")
+	req.Header.Set(HeaderRecallSourceID, "client-1")
+	req = withChiURLParam(req, "id", "01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	w := httptest.NewRecorder()
+	handler.DeleteLore(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", w.Code)
+	}
+	if got := notifier.types(); len(got) != 1 || got[0] != webhook.EventLoreDeleted {
+		t.Fatalf("events = %v, want [lore.deleted]", got)
+	}
+	data := notifier.events[0].Data.(webhook.DeleteData)
+	if data.LoreID != "01ARZ3NDEKTSV4RRFFQ69G5FAV" || data.SourceID != "client-1" {
+		t.Errorf("unexpected data: %+v", data)
+	}
+}
+
+func TestDeleteLore_NoNotificationOnFailure(t *testing.T) {
+	handler, notifier := newNotifyingHandler(&mockStore{deleteErr: store.ErrNotFound})
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/lore/01ARZ3NDEKTSV4RRFFQ69G5FAV", nil)
+	req = withChiURLParam(req, "id", "01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	handler.DeleteLore(httptest.NewRecorder(), req)
+
+	if got := notifier.types(); len(got) != 0 {
+		t.Errorf("events = %v, want none", got)
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/hyperengineering/engram/internal/secrets"
 )
 
 // Config is the root configuration structure.
@@ -22,6 +25,19 @@ type Config struct {
 	Deduplication DeduplicationConfig `yaml:"deduplication"`
 	Stores          StoresConfig          `yaml:"stores"`
 	SnapshotStorage SnapshotStorageConfig `yaml:"snapshot_storage"`
+	Plugins         PluginsConfig         `yaml:"plugins"`
+	Sync            SyncConfig            `yaml:"sync"`
+	Webhooks        WebhooksConfig        `yaml:"webhooks"`
+	EventBus        EventBusConfig        `yaml:"event_bus"`
+	Digests         DigestsConfig         `yaml:"digests"`
+	Search          SearchConfig          `yaml:"search"`
+	Shedding        SheddingConfig        `yaml:"shedding"`
+	LLM             LLMConfig             `yaml:"llm"`
+	Consolidation   ConsolidationConfig   `yaml:"consolidation"`
+	Leader          LeaderConfig          `yaml:"leader"`
+	Faults          FaultsConfig          `yaml:"faults"`
+	Secrets         SecretsConfig         `yaml:"secrets"`
+	Feedback        FeedbackConfig        `yaml:"feedback"`
 }
 
 // ServerConfig contains HTTP server settings.
@@ -31,23 +47,89 @@ type ServerConfig struct {
 	ReadTimeout     Duration `yaml:"read_timeout"`
 	WriteTimeout    Duration `yaml:"write_timeout"`
 	ShutdownTimeout Duration `yaml:"shutdown_timeout"`
+	// LatencySLOs maps route templates to latency targets. Slower requests
+	// are logged as warnings and counted in /api/v1/stats/latency.
+	LatencySLOs map[string]Duration `yaml:"latency_slos"`
+	// Pprof serves net/http/pprof under /debug/pprof/ to callers presenting
+	// the admin key. It stays off when no admin key is set.
+	Pprof bool `yaml:"pprof"`
+	// MaxIngestBytes caps the request body size of a lore ingest. Larger
+	// requests are rejected with 413.
+	MaxIngestBytes int64 `yaml:"max_ingest_bytes"`
+	// TrustedProxies lists the proxy addresses and CIDR ranges whose
+	// X-Forwarded-For and X-Real-IP headers give the client address.
+	// Requests from any other peer are attributed to the peer itself.
+	TrustedProxies []string `yaml:"trusted_proxies"`
 }
 
 // DatabaseConfig contains database settings.
 type DatabaseConfig struct {
 	Path string `yaml:"path"`
+	// SQLite tunes the main database and every store.
+	SQLite SQLiteConfig `yaml:"sqlite"`
+	// SlowQueryThreshold logs statements of the main database and every
+	// store that take at least this long, with their query plan. Zero, the
+	// default, logs none.
+	SlowQueryThreshold Duration `yaml:"slow_query_threshold"`
+}
+
+// SQLiteConfig sets the SQLite pragmas that trade memory for speed. Sizes
+// are in bytes; a zero cache or mmap size is sized from each database as
+// it opens.
+type SQLiteConfig struct {
+	// PageSize applies only to databases created after it is set; zero
+	// keeps SQLite's default of 4096.
+	PageSize int64 `yaml:"page_size"`
+	// CacheSize is the page cache of each connection.
+	CacheSize int64 `yaml:"cache_size"`
+	// MmapSize is how much of each database is memory-mapped; negative
+	// disables memory mapping.
+	MmapSize int64 `yaml:"mmap_size"`
+	// TempStore is where large sorts spill: "default", "file" or "memory".
+	TempStore string `yaml:"temp_store"`
 }
 
 // EmbeddingConfig contains embedding service settings.
 type EmbeddingConfig struct {
 	APIKey     string `yaml:"-"` // env-only, never in YAML
+	APIKeyFile string `yaml:"api_key_file"`
 	Model      string `yaml:"model"`
 	Dimensions int    `yaml:"dimensions"`
+	// ChunkSize, when positive, additionally embeds entries longer than this
+	// many bytes as overlapping chunks of about that size, so similarity
+	// scans can match any part of a long entry. Zero disables chunking.
+	ChunkSize    int `yaml:"chunk_size"`
+	ChunkOverlap int `yaml:"chunk_overlap"`
+	// Quantization is how embeddings are stored: "none" keeps float32
+	// values, "float16" halves their size and "int8" quarters it at some
+	// cost to similarity precision. Existing embeddings keep their encoding.
+	Quantization string `yaml:"quantization"`
 }
 
 // AuthConfig contains authentication settings.
 type AuthConfig struct {
-	APIKey string `yaml:"-"` // env-only, never in YAML
+	APIKey   string `yaml:"-"` // env-only, never in YAML
+	AdminKey string `yaml:"-"` // env-only; guards operator endpoints such as pprof
+
+	// APIKeyFile and AdminKeyFile name files holding the keys, read in
+	// place of the environment and refreshed as set in SecretsConfig.
+	APIKeyFile   string `yaml:"api_key_file"`
+	AdminKeyFile string `yaml:"admin_key_file"`
+
+	Lockout AuthLockoutConfig `yaml:"lockout"`
+}
+
+// AuthLockoutConfig sets when a client address that keeps failing
+// authentication is refused outright for a while. Zero MaxFailures
+// disables lockout.
+type AuthLockoutConfig struct {
+	// MaxFailures bans an address after this many failed authentications
+	// within Window.
+	MaxFailures int      `yaml:"max_failures"`
+	Window      Duration `yaml:"window"`
+
+	// Duration is how long a ban lasts.
+	Duration Duration `yaml:"duration"`
 }
 
 // WorkerConfig contains background worker settings.
@@ -59,23 +141,62 @@ type WorkerConfig struct {
 	EmbeddingRetryBatchSize   int      `yaml:"embedding_retry_batch_size"`
 	CompactionInterval        Duration `yaml:"compaction_interval"`
 	CompactionRetention       Duration `yaml:"compaction_retention"`
+	IdempotencyInterval       Duration `yaml:"idempotency_interval"`
+	IdempotencyMaxEntries     int      `yaml:"idempotency_max_entries"`
+	// DiskCheckInterval is how often the filesystem holding the stores root
+	// is checked against DiskThresholds. Zero disables the check.
+	DiskCheckInterval Duration `yaml:"disk_check_interval"`
+	// DiskThresholds are utilization fractions that log a warning, and
+	// send a disk.utilization webhook, when crossed.
+	DiskThresholds []float64 `yaml:"disk_thresholds"`
 }
 
 // LogConfig contains logging settings.
 type LogConfig struct {
 	Level  string `yaml:"level"`
 	Format string `yaml:"format"`
+	// AccessSampleRates maps route templates to the fraction of successful
+	// requests written to the access log. Errors are always logged.
+	AccessSampleRates map[string]float64 `yaml:"access_sample_rates"`
 }
 
 // DeduplicationConfig contains semantic deduplication settings.
 type DeduplicationConfig struct {
 	Enabled             bool    `yaml:"enabled"`
 	SimilarityThreshold float64 `yaml:"similarity_threshold"`
+	// Approximate limits duplicate candidates to entries whose random
+	// projection bucket differs from the new entry's in at most ProbeRadius
+	// bits, trading a little recall for scans that don't grow with the store.
+	Approximate bool `yaml:"approximate"`
+	ProbeRadius int  `yaml:"probe_radius"`
+	// MaxAge limits duplicate candidates to entries created within it, and
+	// MinConfidence to entries at or above it, so stale low-confidence lore
+	// neither slows ingest nor absorbs new entries. Zero values disable them.
+	MaxAge        Duration `yaml:"max_age"`
+	MinConfidence float64  `yaml:"min_confidence"`
 }
 
 // StoresConfig contains multi-store settings.
 type StoresConfig struct {
 	RootPath string `yaml:"root_path"`
+	// MaxOpen caps how many stores are held open at once; opening another
+	// closes the least recently used idle store. Zero means no cap.
+	MaxOpen int `yaml:"max_open"`
+	// IdleTimeout closes stores unused for this long. Zero keeps them open.
+	IdleTimeout Duration `yaml:"idle_timeout"`
+	// Paths places the stores whose IDs match a pattern under another
+	// root, such as faster disk for busy stores. The first match wins.
+	Paths []StorePathConfig `yaml:"paths"`
+	// ArchivePath keeps the archives of archived stores under another
+	// root, such as cheaper disk. Empty keeps them with the stores.
+	ArchivePath string `yaml:"archive_path"`
+}
+
+// StorePathConfig maps the store IDs matching a glob pattern, such as
+// "hot/*", to the root directory holding them.
+type StorePathConfig struct {
+	Match string `yaml:"match"`
+	Path  string `yaml:"path"`
 }
 
 // SnapshotStorageConfig contains S3-compatible snapshot storage settings.
@@ -88,6 +209,402 @@ type SnapshotStorageConfig struct {
 	AccessKey string   `yaml:"-"` // env-only, never in YAML
 	SecretKey string   `yaml:"-"` // env-only, never in YAML
 	URLExpiry Duration `yaml:"url_expiry"`
+	// AccessKeyFile and SecretKeyFile name files holding the credentials,
+	// read in place of the environment and refreshed as set in
+	// SecretsConfig.
+	AccessKeyFile string `yaml:"access_key_file"`
+	SecretKeyFile string `yaml:"secret_key_file"`
+	// RestoreOnStartup rebuilds the stores from their uploaded snapshots
+	// when the stores root holds none, for deployments whose disk doesn't
+	// outlive the container.
+	RestoreOnStartup bool `yaml:"restore_on_startup"`
+	// UploadBandwidth caps the bytes per second sent by all uploads
+	// together, so large uploads don't crowd out API traffic. Zero means
+	// unlimited.
+	UploadBandwidth int64 `yaml:"upload_bandwidth"`
+	// UploadConcurrency is how many snapshot uploads run at once while
+	// the next snapshots are generated.
+	UploadConcurrency int `yaml:"upload_concurrency"`
+}
+
+// PluginsConfig contains settings for externally declared domain plugins.
+// When Dir is empty, only the compiled-in plugins are available.
+type PluginsConfig struct {
+	Dir string `yaml:"dir"`
+}
+
+// SyncConfig contains sync protocol limits.
+type SyncConfig struct {
+	// MaxPushBytes caps the request body size of a single push or push
+	// session append. Larger requests are rejected with 413.
+	MaxPushBytes int64 `yaml:"max_push_bytes"`
+
+	// PushSessionTTL is how long a multi-part push session may stay open
+	// before its staged entries are discarded.
+	PushSessionTTL Duration `yaml:"push_session_ttl"`
+
+	// MaxClockSkew is how far ahead of the server clock a pushed
+	// created_at or updated_at may be. Later timestamps are clamped to the
+	// server time and reported as push warnings.
+	MaxClockSkew Duration `yaml:"max_clock_skew"`
+
+	// Signing verifies HMAC signatures on pushes, so a captured push cannot
+	// be replayed by someone without the source's secret.
+	Signing SyncSigningConfig `yaml:"signing"`
+}
+
+// SyncSigningConfig contains the per-source secrets pushes are signed with.
+// Pushes from a source with a secret must be signed with it; pushes from
+// other sources may be unsigned unless Required is set.
+type SyncSigningConfig struct {
+	Sources []SyncSigningSource `yaml:"sources"`
+
+	// Required rejects unsigned pushes from every source.
+	Required bool `yaml:"required"`
+
+	// MaxAge is how far a signature's timestamp may be from the server
+	// clock. A signature is accepted once within it.
+	MaxAge Duration `yaml:"max_age"`
+}
+
+// SyncSigningSource is the signing secret of one sync source.
+type SyncSigningSource struct {
+	SourceID  string `yaml:"source_id"`
+	SecretEnv string `yaml:"secret_env"` // name of the env var holding the signing secret
+	Secret    string `yaml:"-"`          // resolved from SecretEnv, never in YAML
+}
+
+// Validate checks that every source has a secret, no source is listed
+// twice, and Required has sources to sign with.
+func (c SyncSigningConfig) Validate() error {
+	if c.Required && len(c.Sources) == 0 {
+		return errors.New("sync.signing.required needs at least one source")
+	}
+	if c.MaxAge < 0 {
+		return errors.New("sync.signing.max_age must not be negative")
+	}
+	seen := make(map[string]bool, len(c.Sources))
+	for _, src := range c.Sources {
+		if src.SourceID == "" {
+			return errors.New("sync.signing.sources: source_id is required")
+		}
+		if seen[src.SourceID] {
+			return fmt.Errorf("sync.signing.sources: %q is listed twice", src.SourceID)
+		}
+		seen[src.SourceID] = true
+		if src.Secret == "" {
+			return fmt.Errorf("sync.signing.sources: no secret for %q; set the env var named by secret_env", src.SourceID)
+		}
+	}
+	return nil
+}
+
+// WebhooksConfig contains outbound webhook notification settings.
+// When Endpoints is empty, no webhooks are sent.
+type WebhooksConfig struct {
+	Endpoints []WebhookEndpoint `yaml:"endpoints"`
+
+	// MaxAttempts is the number of delivery attempts per event, including
+	// the first. Retries back off exponentially from InitialBackoff up to
+	// MaxBackoff.
+	MaxAttempts    int      `yaml:"max_attempts"`
+	InitialBackoff Duration `yaml:"initial_backoff"`
+	MaxBackoff     Duration `yaml:"max_backoff"`
+
+	// Timeout bounds a single delivery request.
+	Timeout Duration `yaml:"timeout"`
+
+	// QueueSize is the number of undelivered events buffered per endpoint.
+	// Events are dropped when the queue is full.
+	QueueSize int `yaml:"queue_size"`
+}
+
+// WebhookEndpoint is a single webhook receiver.
+// Empty Events or Stores match everything.
+type WebhookEndpoint struct {
+	URL       string   `yaml:"url"`
+	SecretEnv string   `yaml:"secret_env"` // name of the env var holding the signing secret
+	Secret    string   `yaml:"-"`          // resolved from SecretEnv, never in YAML
+	Events    []string `yaml:"events"`
+	Stores    []string `yaml:"stores"`
+}
+
+// EventBusConfig contains settings for publishing change_log events to a
+// message broker. When Driver is empty, nothing is published.
+type EventBusConfig struct {
+	// Driver selects the broker: "nats", "kafka" or "kafka-rest" (via a
+	// Kafka REST Proxy).
+	Driver string `yaml:"driver"`
+
+	// URL is the broker address: comma-separated nats:// or tls:// server
+	// URLs, optionally with user:pass@ or a token@, for NATS; comma-separated
+	// host:port seed brokers for Kafka; or the REST Proxy base URL. For
+	// backward compatibility, kafka with an http(s) URL uses the REST Proxy.
+	URL string `yaml:"url"`
+
+	// TLS secures the broker connection.
+	TLS EventBusTLSConfig `yaml:"tls"`
+
+	// CredsFile and NKeyFile authenticate to NATS with a credentials file
+	// (user JWT and nkey seed) or an nkey seed file. NATS only.
+	CredsFile string `yaml:"creds_file"`
+	NKeyFile  string `yaml:"nkey_file"`
+
+	// SASL authenticates to the Kafka brokers. Kafka only.
+	SASL EventBusSASLConfig `yaml:"sasl"`
+
+	// TopicPrefix is prepended to the store ID to form the per-store topic
+	// (NATS subject or Kafka topic).
+	TopicPrefix string `yaml:"topic_prefix"`
+
+	// JetStream waits for a JetStream publish acknowledgement instead of a
+	// plain server round trip. NATS only.
+	JetStream bool `yaml:"jetstream"`
+
+	// Stores limits publishing to the listed store IDs. Empty means all.
+	Stores []string `yaml:"stores"`
+
+	PollInterval Duration `yaml:"poll_interval"`
+	BatchSize    int      `yaml:"batch_size"`
+	Timeout      Duration `yaml:"timeout"`
+
+	// OutboxRetention is how long an entry the broker has not acknowledged
+	// stays in a store's outbox before it is dropped. Zero keeps entries
+	// until they are delivered.
+	OutboxRetention Duration `yaml:"outbox_retention"`
+}
+
+// EventBusTLSConfig contains TLS settings for the broker connection. Files
+// are PEM encoded; without CAFile the system roots are trusted.
+type EventBusTLSConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	CAFile  string `yaml:"ca_file"`
+	// CertFile and KeyFile are a client certificate, for brokers that
+	// authenticate clients by certificate.
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// EventBusSASLConfig contains Kafka SASL credentials. When Mechanism is
+// empty, no SASL authentication is done.
+type EventBusSASLConfig struct {
+	// Mechanism is "PLAIN", "SCRAM-SHA-256" or "SCRAM-SHA-512".
+	Mechanism string `yaml:"mechanism"`
+	Username  string `yaml:"username"`
+	Password  string `yaml:"-"` // env-only, never in YAML
+	// PasswordFile names a file holding the password, read in place of
+	// the environment.
+	PasswordFile string `yaml:"password_file"`
+}
+
+// SearchConfig contains the default hybrid search ranking, which clients may
+// override per request, and the quality boost applied to every search.
+type SearchConfig struct {
+	// Fusion combines keyword and semantic relevance: "weighted" averages
+	// the two scores, "rrf" uses reciprocal rank fusion.
+	Fusion string `yaml:"fusion"`
+
+	KeywordWeight  float64 `yaml:"keyword_weight"`
+	SemanticWeight float64 `yaml:"semantic_weight"`
+
+	// RRFK is the reciprocal rank fusion constant. Larger values flatten
+	// the advantage of top-ranked entries.
+	RRFK int `yaml:"rrf_k"`
+
+	// Boost weights scale relevance by entry quality. Their sum is the
+	// largest share of relevance an entry of the lowest quality can lose;
+	// all zero ranks by relevance alone.
+	ConfidenceBoost float64 `yaml:"confidence_boost"`
+	ValidationBoost float64 `yaml:"validation_boost"`
+	FeedbackBoost   float64 `yaml:"feedback_boost"`
+	RecencyBoost    float64 `yaml:"recency_boost"`
+
+	// ValidationPivot is the validation count at which the validation
+	// feature reaches half its weight.
+	ValidationPivot float64 `yaml:"validation_pivot"`
+
+	// RecencyHalfLife is how long after its last validation an entry's
+	// recency feature halves.
+	RecencyHalfLife Duration `yaml:"recency_half_life"`
+
+	// QueryCacheTTL is how long a search query's embedding is reused for
+	// repeats of the same query. Zero disables the cache.
+	QueryCacheTTL Duration `yaml:"query_cache_ttl"`
+
+	// QueryCacheSize caps the number of cached query embeddings.
+	QueryCacheSize int `yaml:"query_cache_size"`
+}
+
+// SheddingConfig contains the thresholds at which requests are refused with
+// 503 rather than queued. Zero disables a threshold.
+type SheddingConfig struct {
+	// MaxInFlight sheds any authenticated request beyond this many in flight.
+	MaxInFlight int `yaml:"max_in_flight"`
+
+	// IngestMaxInFlight sheds ingest and sync pushes at a lower in-flight
+	// count, leaving the rest of MaxInFlight to reads.
+	IngestMaxInFlight int `yaml:"ingest_max_in_flight"`
+
+	// MaxBusyErrors sheds ingest once SQLite has reported the database busy
+	// this many times within BusyWindow.
+	MaxBusyErrors int      `yaml:"max_busy_errors"`
+	BusyWindow    Duration `yaml:"busy_window"`
+
+	// MaxEmbeddingQueue sheds ingest while more entries than this await
+	// embedding across all stores.
+	MaxEmbeddingQueue int64 `yaml:"max_embedding_queue"`
+
+	// RetryAfter is the Retry-After sent with shed responses.
+	RetryAfter Duration `yaml:"retry_after"`
+}
+
+// LLMConfig contains the language model settings behind lore
+// summarization. When Provider is empty, summarization is disabled.
+type LLMConfig struct {
+	Provider string `yaml:"provider"` // "openai" or "ollama"
+	Model    string `yaml:"model"`    // defaults per provider
+	BaseURL  string `yaml:"base_url"` // optional; e.g. an OpenAI-compatible gateway
+	APIKey   string `yaml:"-"`        // env-only; openai falls back to OPENAI_API_KEY
+
+	// Timeout bounds a single completion request.
+	Timeout Duration `yaml:"timeout"`
+}
+
+// ConsolidationConfig contains settings for the job that clusters
+// near-duplicate lore that deduplication never merged. When Interval is
+// zero, the job does not run.
+type ConsolidationConfig struct {
+	Interval Duration `yaml:"interval"`
+
+	// Threshold is the cosine similarity at or above which entries in the
+	// same category are consolidated.
+	Threshold float64 `yaml:"threshold"`
+
+	// Apply merges the proposed clusters. When false, the job only reports
+	// them at /api/v1/lore/consolidations.
+	Apply bool `yaml:"apply"`
+
+	// MaxProposals caps the clusters considered per store and run.
+	MaxProposals int `yaml:"max_proposals"`
+}
+
+// LeaderConfig contains settings for electing one replica to run the
+// background jobs when several share the stores on common storage. When
+// Enabled is false, every replica runs them.
+type LeaderConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// LeasePath is the SQLite database holding the lease, on storage every
+	// replica mounts. Empty means .leader.db in the stores root.
+	LeasePath string `yaml:"lease_path"`
+
+	// LeaseDuration is how long the lease lasts unless renewed. The leader
+	// renews it every third of this; after a leader dies another replica
+	// takes over within about this long.
+	LeaseDuration Duration `yaml:"lease_duration"`
+
+	// ID names this replica in the lease. Empty means the hostname, which
+	// under Kubernetes is the pod name.
+	ID string `yaml:"id"`
+}
+
+// FaultsConfig contains failures injected into the embedding and storage
+// layers for soak tests, so that the pending-embedding fallback, the
+// embedding retry worker and busy-database shedding can be exercised
+// without a real outage. All zero, the default, injects nothing. Never
+// enable in production.
+type FaultsConfig struct {
+	// EmbeddingErrorRate is the fraction of embedding calls, from 0 to 1,
+	// that fail as if the provider were unreachable.
+	EmbeddingErrorRate float64 `yaml:"embedding_error_rate"`
+
+	// EmbeddingLatency is added to every embedding call.
+	EmbeddingLatency Duration `yaml:"embedding_latency"`
+
+	// DatabaseBusyRate is the fraction of SQL statements, from 0 to 1, that
+	// fail as if SQLite had found the database locked.
+	DatabaseBusyRate float64 `yaml:"database_busy_rate"`
+
+	// DatabaseLatency is added to every SQL statement, as from a slow disk.
+	DatabaseLatency Duration `yaml:"database_latency"`
+}
+
+// Enabled reports whether any fault is injected.
+func (f FaultsConfig) Enabled() bool {
+	return f.EmbeddingErrorRate > 0 || f.EmbeddingLatency > 0 || f.DatabaseBusyRate > 0 || f.DatabaseLatency > 0
+}
+
+// Validate checks that the rates are fractions and the latencies are not
+// negative.
+func (f FaultsConfig) Validate() error {
+	for name, rate := range map[string]float64{
+		"embedding_error_rate": f.EmbeddingErrorRate,
+		"database_busy_rate":   f.DatabaseBusyRate,
+	} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("faults.%s must be between 0 and 1, got %v", name, rate)
+		}
+	}
+	if f.EmbeddingLatency < 0 || f.DatabaseLatency < 0 {
+		return errors.New("faults latencies must not be negative")
+	}
+	return nil
+}
+
+// FeedbackConfig contains settings guarding feedback against abuse.
+type FeedbackConfig struct {
+	// RateLimitWindow and RateLimitMax cap the feedback of one type a
+	// source may give one lore entry: at most RateLimitMax within
+	// RateLimitWindow of its first. Feedback over the cap is skipped as
+	// rate_limited. Zero in either disables the cap.
+	RateLimitWindow Duration `yaml:"rate_limit_window"`
+	RateLimitMax    int      `yaml:"rate_limit_max"`
+
+	// ExcludeSelf stops helpful feedback from a source already among a
+	// lore entry's sources from boosting it. Such feedback is applied at
+	// SelfWeight, from 0 to 1, without counting as a validation; at zero
+	// it is skipped as self_feedback.
+	ExcludeSelf bool    `yaml:"exclude_self"`
+	SelfWeight  float64 `yaml:"self_weight"`
+}
+
+// SecretsConfig contains settings for secrets read from files.
+type SecretsConfig struct {
+	// RefreshInterval is how often secret files are read again, so a
+	// rotated secret takes effect without a restart. Zero reads them once.
+	RefreshInterval Duration `yaml:"refresh_interval"`
+}
+
+// DigestsConfig contains scheduled activity digest settings.
+// When Reports is empty, no digests are sent.
+type DigestsConfig struct {
+	Reports []DigestReport `yaml:"reports"`
+
+	// CheckInterval is how often report schedules are checked. A digest is
+	// posted on the first check after its scheduled time.
+	CheckInterval Duration `yaml:"check_interval"`
+
+	// Timeout bounds a single post to a chat webhook.
+	Timeout Duration `yaml:"timeout"`
+
+	// TopN is the maximum number of items in each digest list.
+	TopN int `yaml:"top_n"`
+}
+
+// DigestReport posts a periodic digest for one store to a Slack or Teams
+// incoming webhook.
+type DigestReport struct {
+	// Name distinguishes reports on the same store. Defaults to
+	// "<schedule>-<format>".
+	Name     string `yaml:"name"`
+	Store    string `yaml:"store"`
+	Schedule string `yaml:"schedule"` // "daily" or "weekly"
+	Format   string `yaml:"format"`   // "slack" or "teams"
+	Hour     int    `yaml:"hour"`     // UTC hour the digest is sent, 0-23
+	Weekday  string `yaml:"weekday"`  // weekly only; defaults to "monday"
+	URLEnv   string `yaml:"url_env"`  // name of the env var holding the webhook URL
+	URL      string `yaml:"-"`        // resolved from URLEnv, never in YAML
 }
 
 // GetDeduplicationEnabled returns whether deduplication is enabled.
@@ -95,6 +612,24 @@ func (c *Config) GetDeduplicationEnabled() bool {
 	return c.Deduplication.Enabled
 }
 
+// GetDeduplicationProbeRadius returns the bucket probe radius and whether
+// deduplication is approximate.
+func (c *Config) GetDeduplicationProbeRadius() (int, bool) {
+	return c.Deduplication.ProbeRadius, c.Deduplication.Approximate
+}
+
+// GetDeduplicationScope returns the maximum age and minimum confidence of
+// entries deduplication compares against. Zero values mean no limit.
+func (c *Config) GetDeduplicationScope() (maxAge time.Duration, minConfidence float64) {
+	return time.Duration(c.Deduplication.MaxAge), c.Deduplication.MinConfidence
+}
+
+// GetEmbeddingChunking returns the chunk size and overlap for multi-vector
+// embedding of long entries. A size of zero disables chunking.
+func (c *Config) GetEmbeddingChunking() (size, overlap int) {
+	return c.Embedding.ChunkSize, c.Embedding.ChunkOverlap
+}
+
 // GetSimilarityThreshold returns the similarity threshold for deduplication.
 func (c *Config) GetSimilarityThreshold() float64 {
 	return c.Deduplication.SimilarityThreshold
@@ -137,6 +672,9 @@ func Load() (*Config, error) {
 
 	// Apply environment variable overrides
 	applyEnvOverrides(cfg)
+	if err := readSecretFiles(cfg); err != nil {
+		return nil, err
+	}
 
 	// Validate configuration
 	if err := cfg.validate(); err != nil {
@@ -146,6 +684,21 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// LoadUnvalidated loads configuration as Load does, but neither reads
+// secret files nor requires API keys, for CLI commands that only touch the
+// databases, such as migrate.
+func LoadUnvalidated() (*Config, error) {
+	cfg := newDefaults()
+
+	configPath := getEnv("ENGRAM_CONFIG_PATH", "config/engram.yaml")
+	if err := loadYAMLFile(cfg, configPath); err != nil {
+		return nil, err
+	}
+	applyEnvOverrides(cfg)
+
+	return cfg, nil
+}
+
 // LoadFromFile loads configuration from a specific path.
 // Used for testing and explicit path specification.
 func LoadFromFile(path string) (*Config, error) {
@@ -163,6 +716,9 @@ func LoadFromFile(path string) (*Config, error) {
 
 	// Apply environment variable overrides
 	applyEnvOverrides(cfg)
+	if err := readSecretFiles(cfg); err != nil {
+		return nil, err
+	}
 
 	// Validate configuration
 	if err := cfg.validate(); err != nil {
@@ -180,13 +736,20 @@ func newDefaults() *Config {
 			ReadTimeout:     Duration(30 * time.Second),
 			WriteTimeout:    Duration(30 * time.Second),
 			ShutdownTimeout: Duration(15 * time.Second),
+			LatencySLOs: map[string]Duration{
+				"/api/v1/lore/feedback":                   Duration(500 * time.Millisecond),
+				"/api/v1/stores/{store_id}/lore/feedback": Duration(500 * time.Millisecond),
+			},
+			MaxIngestBytes: 4 << 20,
 		},
 		Database: DatabaseConfig{
 			Path: "data/engram.db",
 		},
 		Embedding: EmbeddingConfig{
-			Model:      "text-embedding-3-small",
-			Dimensions: 1536,
+			Model:        "text-embedding-3-small",
+			Dimensions:   1536,
+			ChunkOverlap: 200,
+			Quantization: "none",
 		},
 		Worker: WorkerConfig{
 			SnapshotInterval:          Duration(1 * time.Hour),
@@ -196,22 +759,112 @@ func newDefaults() *Config {
 			EmbeddingRetryBatchSize:   50,
 			CompactionInterval:        Duration(24 * time.Hour),
 			CompactionRetention:       Duration(7 * 24 * time.Hour),
+			IdempotencyInterval:       Duration(1 * time.Hour),
+			IdempotencyMaxEntries:     10000,
+			DiskCheckInterval:         Duration(1 * time.Minute),
+			DiskThresholds:            []float64{0.8, 0.9, 0.95},
 		},
 		Log: LogConfig{
 			Level:  "info",
 			Format: "json",
+			AccessSampleRates: map[string]float64{
+				"/api/v1/stores/{store_id}/sync/delta": 0.1,
+				"/api/v1/lore/delta":                   0.1,
+			},
 		},
 		Deduplication: DeduplicationConfig{
 			Enabled:             true,
 			SimilarityThreshold: 0.92,
+			ProbeRadius:         3,
 		},
 		Stores: StoresConfig{
-			RootPath: "~/.engram/stores",
+			RootPath:    "~/.engram/stores",
+			MaxOpen:     256,
+			IdleTimeout: Duration(30 * time.Minute),
 		},
 		SnapshotStorage: SnapshotStorageConfig{
-			Region:    "us-east-1",
-			UseSSL:    boolPtr(true),
-			URLExpiry: Duration(15 * time.Minute),
+			Region:            "us-east-1",
+			UseSSL:            boolPtr(true),
+			URLExpiry:         Duration(15 * time.Minute),
+			UploadConcurrency: 1,
+		},
+		Sync: SyncConfig{
+			MaxPushBytes:   16 << 20,
+			PushSessionTTL: Duration(1 * time.Hour),
+			MaxClockSkew:   Duration(5 * time.Minute),
+			Signing: SyncSigningConfig{
+				MaxAge: Duration(5 * time.Minute),
+			},
+		},
+		Webhooks: WebhooksConfig{
+			MaxAttempts:    5,
+			InitialBackoff: Duration(1 * time.Second),
+			MaxBackoff:     Duration(1 * time.Minute),
+			Timeout:        Duration(10 * time.Second),
+			QueueSize:      1000,
+		},
+		EventBus: EventBusConfig{
+			TopicPrefix:  "engram",
+			PollInterval: Duration(1 * time.Second),
+			BatchSize:    100,
+			Timeout:      Duration(10 * time.Second),
+
+			OutboxRetention: Duration(7 * 24 * time.Hour),
+		},
+		Digests: DigestsConfig{
+			CheckInterval: Duration(5 * time.Minute),
+			Timeout:       Duration(10 * time.Second),
+			TopN:          5,
+		},
+		Search: SearchConfig{
+			Fusion:         "weighted",
+			KeywordWeight:  0.5,
+			SemanticWeight: 0.5,
+			RRFK:           60,
+
+			ConfidenceBoost: 0.1,
+			ValidationBoost: 0.1,
+			FeedbackBoost:   0.1,
+			RecencyBoost:    0.1,
+			ValidationPivot: 5,
+			RecencyHalfLife: Duration(90 * 24 * time.Hour),
+
+			QueryCacheTTL:  Duration(10 * time.Minute),
+			QueryCacheSize: 1000,
+		},
+		Auth: AuthConfig{
+			Lockout: AuthLockoutConfig{
+				MaxFailures: 20,
+				Window:      Duration(time.Minute),
+				Duration:    Duration(5 * time.Minute),
+			},
+		},
+		Shedding: SheddingConfig{
+			MaxInFlight:       512,
+			IngestMaxInFlight: 256,
+			MaxBusyErrors:     5,
+			BusyWindow:        Duration(30 * time.Second),
+			MaxEmbeddingQueue: 10000,
+			RetryAfter:        Duration(5 * time.Second),
+		},
+		LLM: LLMConfig{
+			Timeout: Duration(2 * time.Minute),
+		},
+		Consolidation: ConsolidationConfig{
+			Interval:     Duration(24 * time.Hour),
+			Threshold:    0.95,
+			MaxProposals: 100,
+		},
+		Leader: LeaderConfig{
+			LeaseDuration: Duration(15 * time.Second),
+		},
+		Secrets: SecretsConfig{
+			RefreshInterval: Duration(time.Minute),
+		},
+		Feedback: FeedbackConfig{
+			RateLimitWindow: Duration(24 * time.Hour),
+			RateLimitMax:    1,
+			ExcludeSelf:     true,
 		},
 	}
 }
@@ -262,24 +915,116 @@ func applyEnvOverrides(cfg *Config) {
 			cfg.Server.ShutdownTimeout = Duration(d)
 		}
 	}
+	if v := os.Getenv("ENGRAM_LATENCY_SLOS"); v != "" {
+		cfg.Server.LatencySLOs = make(map[string]Duration)
+		for route, target := range parseRoutePairs(v) {
+			if d, err := time.ParseDuration(target); err == nil {
+				cfg.Server.LatencySLOs[route] = Duration(d)
+			}
+		}
+	}
+	if v := os.Getenv("ENGRAM_PPROF"); v != "" {
+		cfg.Server.Pprof = v == "true" || v == "1"
+	}
+	if v := os.Getenv("ENGRAM_MAX_INGEST_BYTES"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			cfg.Server.MaxIngestBytes = n
+		}
+	}
+	if v := os.Getenv("ENGRAM_TRUSTED_PROXIES"); v != "" {
+		cfg.Server.TrustedProxies = nil
+		for _, p := range strings.Split(v, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				cfg.Server.TrustedProxies = append(cfg.Server.TrustedProxies, p)
+			}
+		}
+	}
 
 	// Database
 	if v := os.Getenv("ENGRAM_DB_PATH"); v != "" {
 		cfg.Database.Path = v
 	}
+	if v := os.Getenv("ENGRAM_DB_PAGE_SIZE"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			cfg.Database.SQLite.PageSize = n
+		}
+	}
+	if v := os.Getenv("ENGRAM_DB_CACHE_SIZE"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			cfg.Database.SQLite.CacheSize = n
+		}
+	}
+	if v := os.Getenv("ENGRAM_DB_MMAP_SIZE"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			cfg.Database.SQLite.MmapSize = n
+		}
+	}
+	if v := os.Getenv("ENGRAM_DB_TEMP_STORE"); v != "" {
+		cfg.Database.SQLite.TempStore = v
+	}
+	if v := os.Getenv("ENGRAM_DB_SLOW_QUERY_THRESHOLD"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Database.SlowQueryThreshold = Duration(d)
+		}
+	}
 
 	// Embedding (OPENAI_API_KEY is industry convention)
 	if v := os.Getenv("OPENAI_API_KEY"); v != "" {
 		cfg.Embedding.APIKey = v
 	}
+	if v := os.Getenv("OPENAI_API_KEY_FILE"); v != "" {
+		cfg.Embedding.APIKeyFile = v
+	}
 	if v := os.Getenv("ENGRAM_EMBEDDING_MODEL"); v != "" {
 		cfg.Embedding.Model = v
 	}
+	if v := os.Getenv("ENGRAM_EMBEDDING_CHUNK_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.Embedding.ChunkSize = n
+		}
+	}
+	if v := os.Getenv("ENGRAM_EMBEDDING_CHUNK_OVERLAP"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.Embedding.ChunkOverlap = n
+		}
+	}
+	if v := os.Getenv("ENGRAM_EMBEDDING_QUANTIZATION"); v != "" {
+		cfg.Embedding.Quantization = v
+	}
 
 	// Auth
 	if v := os.Getenv("ENGRAM_API_KEY"); v != "" {
 		cfg.Auth.APIKey = v
 	}
+	if v := os.Getenv("ENGRAM_ADMIN_API_KEY"); v != "" {
+		cfg.Auth.AdminKey = v
+	}
+	if v := os.Getenv("ENGRAM_API_KEY_FILE"); v != "" {
+		cfg.Auth.APIKeyFile = v
+	}
+	if v := os.Getenv("ENGRAM_ADMIN_API_KEY_FILE"); v != "" {
+		cfg.Auth.AdminKeyFile = v
+	}
+	if v := os.Getenv("ENGRAM_AUTH_LOCKOUT_MAX_FAILURES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.Auth.Lockout.MaxFailures = n
+		}
+	}
+	if v := os.Getenv("ENGRAM_AUTH_LOCKOUT_WINDOW"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Auth.Lockout.Window = Duration(d)
+		}
+	}
+	if v := os.Getenv("ENGRAM_AUTH_LOCKOUT_DURATION"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Auth.Lockout.Duration = Duration(d)
+		}
+	}
+	if v := os.Getenv("ENGRAM_SECRETS_REFRESH_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Secrets.RefreshInterval = Duration(d)
+		}
+	}
 
 	// Worker
 	if v := os.Getenv("ENGRAM_SNAPSHOT_INTERVAL"); v != "" {
@@ -317,6 +1062,29 @@ func applyEnvOverrides(cfg *Config) {
 			cfg.Worker.CompactionRetention = Duration(d)
 		}
 	}
+	if v := os.Getenv("ENGRAM_IDEMPOTENCY_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Worker.IdempotencyInterval = Duration(d)
+		}
+	}
+	if v := os.Getenv("ENGRAM_IDEMPOTENCY_MAX_ENTRIES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.Worker.IdempotencyMaxEntries = n
+		}
+	}
+	if v := os.Getenv("ENGRAM_DISK_CHECK_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Worker.DiskCheckInterval = Duration(d)
+		}
+	}
+	if v := os.Getenv("ENGRAM_DISK_THRESHOLDS"); v != "" {
+		cfg.Worker.DiskThresholds = nil
+		for _, t := range strings.Split(v, ",") {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(t), 64); err == nil {
+				cfg.Worker.DiskThresholds = append(cfg.Worker.DiskThresholds, f)
+			}
+		}
+	}
 
 	// Log
 	if v := os.Getenv("ENGRAM_LOG_LEVEL"); v != "" {
@@ -325,6 +1093,14 @@ func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("ENGRAM_LOG_FORMAT"); v != "" {
 		cfg.Log.Format = v
 	}
+	if v := os.Getenv("ENGRAM_LOG_ACCESS_SAMPLE_RATES"); v != "" {
+		cfg.Log.AccessSampleRates = make(map[string]float64)
+		for route, rate := range parseRoutePairs(v) {
+			if f, err := strconv.ParseFloat(rate, 64); err == nil {
+				cfg.Log.AccessSampleRates[route] = f
+			}
+		}
+	}
 
 	// Deduplication
 	if v := os.Getenv("ENGRAM_DEDUPLICATION_ENABLED"); v != "" {
@@ -335,11 +1111,40 @@ func applyEnvOverrides(cfg *Config) {
 			cfg.Deduplication.SimilarityThreshold = f
 		}
 	}
+	if v := os.Getenv("ENGRAM_DEDUPLICATION_APPROXIMATE"); v != "" {
+		cfg.Deduplication.Approximate = v == "true" || v == "1"
+	}
+	if v := os.Getenv("ENGRAM_DEDUPLICATION_PROBE_RADIUS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.Deduplication.ProbeRadius = n
+		}
+	}
+	if v := os.Getenv("ENGRAM_DEDUPLICATION_MAX_AGE"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Deduplication.MaxAge = Duration(d)
+		}
+	}
+	if v := os.Getenv("ENGRAM_DEDUPLICATION_MIN_CONFIDENCE"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			cfg.Deduplication.MinConfidence = f
+		}
+	}
 
 	// Stores
 	if v := os.Getenv("ENGRAM_STORES_ROOT"); v != "" {
 		cfg.Stores.RootPath = v
 	}
+	applyStoreLayoutEnv(&cfg.Stores)
+	if v := os.Getenv("ENGRAM_STORES_MAX_OPEN"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.Stores.MaxOpen = n
+		}
+	}
+	if v := os.Getenv("ENGRAM_STORES_IDLE_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Stores.IdleTimeout = Duration(d)
+		}
+	}
 
 	// Snapshot storage (S3-compatible)
 	if v := os.Getenv("ENGRAM_SNAPSHOT_BUCKET"); v != "" {
@@ -357,6 +1162,12 @@ func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("ENGRAM_S3_SECRET_KEY"); v != "" {
 		cfg.SnapshotStorage.SecretKey = v
 	}
+	if v := os.Getenv("ENGRAM_S3_ACCESS_KEY_FILE"); v != "" {
+		cfg.SnapshotStorage.AccessKeyFile = v
+	}
+	if v := os.Getenv("ENGRAM_S3_SECRET_KEY_FILE"); v != "" {
+		cfg.SnapshotStorage.SecretKeyFile = v
+	}
 	if v := os.Getenv("ENGRAM_S3_USE_SSL"); v != "" {
 		b := v == "true" || v == "1"
 		cfg.SnapshotStorage.UseSSL = &b
@@ -366,6 +1177,333 @@ func applyEnvOverrides(cfg *Config) {
 			cfg.SnapshotStorage.URLExpiry = Duration(d)
 		}
 	}
+	if v := os.Getenv("ENGRAM_S3_UPLOAD_BANDWIDTH"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			cfg.SnapshotStorage.UploadBandwidth = n
+		}
+	}
+	if v := os.Getenv("ENGRAM_S3_UPLOAD_CONCURRENCY"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.SnapshotStorage.UploadConcurrency = n
+		}
+	}
+	if v := os.Getenv("ENGRAM_S3_RESTORE_ON_STARTUP"); v != "" {
+		cfg.SnapshotStorage.RestoreOnStartup = v == "true" || v == "1"
+	}
+
+	// Plugins
+	if v := os.Getenv("ENGRAM_PLUGINS_DIR"); v != "" {
+		cfg.Plugins.Dir = v
+	}
+
+	// Sync
+	if v := os.Getenv("ENGRAM_SYNC_MAX_PUSH_BYTES"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			cfg.Sync.MaxPushBytes = n
+		}
+	}
+	if v := os.Getenv("ENGRAM_SYNC_PUSH_SESSION_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Sync.PushSessionTTL = Duration(d)
+		}
+	}
+	if v := os.Getenv("ENGRAM_SYNC_MAX_CLOCK_SKEW"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Sync.MaxClockSkew = Duration(d)
+		}
+	}
+	if v := os.Getenv("ENGRAM_SYNC_SIGNING_REQUIRED"); v != "" {
+		cfg.Sync.Signing.Required = v == "true" || v == "1"
+	}
+	if v := os.Getenv("ENGRAM_SYNC_SIGNING_MAX_AGE"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Sync.Signing.MaxAge = Duration(d)
+		}
+	}
+	for i := range cfg.Sync.Signing.Sources {
+		if name := cfg.Sync.Signing.Sources[i].SecretEnv; name != "" {
+			cfg.Sync.Signing.Sources[i].Secret = os.Getenv(name)
+		}
+	}
+
+	// Webhooks: ENGRAM_WEBHOOK_URL adds a single endpoint on top of any
+	// configured in YAML.
+	if v := os.Getenv("ENGRAM_WEBHOOK_URL"); v != "" {
+		endpoint := WebhookEndpoint{URL: v, SecretEnv: "ENGRAM_WEBHOOK_SECRET"}
+		if events := os.Getenv("ENGRAM_WEBHOOK_EVENTS"); events != "" {
+			for _, e := range strings.Split(events, ",") {
+				if e = strings.TrimSpace(e); e != "" {
+					endpoint.Events = append(endpoint.Events, e)
+				}
+			}
+		}
+		cfg.Webhooks.Endpoints = append(cfg.Webhooks.Endpoints, endpoint)
+	}
+	for i := range cfg.Webhooks.Endpoints {
+		if name := cfg.Webhooks.Endpoints[i].SecretEnv; name != "" {
+			cfg.Webhooks.Endpoints[i].Secret = os.Getenv(name)
+		}
+	}
+
+	// Event bus
+	if v := os.Getenv("ENGRAM_EVENT_BUS_DRIVER"); v != "" {
+		cfg.EventBus.Driver = v
+	}
+	if v := os.Getenv("ENGRAM_EVENT_BUS_URL"); v != "" {
+		cfg.EventBus.URL = v
+	}
+	if v := os.Getenv("ENGRAM_EVENT_BUS_TOPIC_PREFIX"); v != "" {
+		cfg.EventBus.TopicPrefix = v
+	}
+	if v := os.Getenv("ENGRAM_EVENT_BUS_JETSTREAM"); v != "" {
+		cfg.EventBus.JetStream = v == "true" || v == "1"
+	}
+	if v := os.Getenv("ENGRAM_EVENT_BUS_TLS"); v != "" {
+		cfg.EventBus.TLS.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("ENGRAM_EVENT_BUS_TLS_CA_FILE"); v != "" {
+		cfg.EventBus.TLS.CAFile = v
+	}
+	if v := os.Getenv("ENGRAM_EVENT_BUS_TLS_CERT_FILE"); v != "" {
+		cfg.EventBus.TLS.CertFile = v
+	}
+	if v := os.Getenv("ENGRAM_EVENT_BUS_TLS_KEY_FILE"); v != "" {
+		cfg.EventBus.TLS.KeyFile = v
+	}
+	if v := os.Getenv("ENGRAM_EVENT_BUS_CREDS_FILE"); v != "" {
+		cfg.EventBus.CredsFile = v
+	}
+	if v := os.Getenv("ENGRAM_EVENT_BUS_NKEY_FILE"); v != "" {
+		cfg.EventBus.NKeyFile = v
+	}
+	if v := os.Getenv("ENGRAM_EVENT_BUS_SASL_MECHANISM"); v != "" {
+		cfg.EventBus.SASL.Mechanism = v
+	}
+	if v := os.Getenv("ENGRAM_EVENT_BUS_SASL_USERNAME"); v != "" {
+		cfg.EventBus.SASL.Username = v
+	}
+	if v := os.Getenv("ENGRAM_EVENT_BUS_SASL_PASSWORD"); v != "" {
+		cfg.EventBus.SASL.Password = v
+	}
+	if v := os.Getenv("ENGRAM_EVENT_BUS_SASL_PASSWORD_FILE"); v != "" {
+		cfg.EventBus.SASL.PasswordFile = v
+	}
+
+	// Digest webhook URLs carry credentials, so they are only read from
+	// the environment.
+	for i := range cfg.Digests.Reports {
+		if name := cfg.Digests.Reports[i].URLEnv; name != "" {
+			cfg.Digests.Reports[i].URL = os.Getenv(name)
+		}
+	}
+
+	// Search
+	if v := os.Getenv("ENGRAM_SEARCH_FUSION"); v != "" {
+		cfg.Search.Fusion = v
+	}
+	if v := os.Getenv("ENGRAM_SEARCH_KEYWORD_WEIGHT"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			cfg.Search.KeywordWeight = f
+		}
+	}
+	if v := os.Getenv("ENGRAM_SEARCH_SEMANTIC_WEIGHT"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			cfg.Search.SemanticWeight = f
+		}
+	}
+	if v := os.Getenv("ENGRAM_SEARCH_RRF_K"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.Search.RRFK = n
+		}
+	}
+	for env, dst := range map[string]*float64{
+		"ENGRAM_SEARCH_CONFIDENCE_BOOST": &cfg.Search.ConfidenceBoost,
+		"ENGRAM_SEARCH_VALIDATION_BOOST": &cfg.Search.ValidationBoost,
+		"ENGRAM_SEARCH_FEEDBACK_BOOST":   &cfg.Search.FeedbackBoost,
+		"ENGRAM_SEARCH_RECENCY_BOOST":    &cfg.Search.RecencyBoost,
+		"ENGRAM_SEARCH_VALIDATION_PIVOT": &cfg.Search.ValidationPivot,
+	} {
+		if v := os.Getenv(env); v != "" {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				*dst = f
+			}
+		}
+	}
+	if v := os.Getenv("ENGRAM_SEARCH_RECENCY_HALF_LIFE"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Search.RecencyHalfLife = Duration(d)
+		}
+	}
+	if v := os.Getenv("ENGRAM_SEARCH_QUERY_CACHE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Search.QueryCacheTTL = Duration(d)
+		}
+	}
+	if v := os.Getenv("ENGRAM_SEARCH_QUERY_CACHE_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.Search.QueryCacheSize = n
+		}
+	}
+
+	// Shedding
+	for env, dst := range map[string]*int{
+		"ENGRAM_SHED_MAX_IN_FLIGHT":        &cfg.Shedding.MaxInFlight,
+		"ENGRAM_SHED_INGEST_MAX_IN_FLIGHT": &cfg.Shedding.IngestMaxInFlight,
+		"ENGRAM_SHED_MAX_BUSY_ERRORS":      &cfg.Shedding.MaxBusyErrors,
+	} {
+		if v := os.Getenv(env); v != "" {
+			if n, err := strconv.Atoi(v); err == nil {
+				*dst = n
+			}
+		}
+	}
+	if v := os.Getenv("ENGRAM_SHED_BUSY_WINDOW"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Shedding.BusyWindow = Duration(d)
+		}
+	}
+	if v := os.Getenv("ENGRAM_SHED_MAX_EMBEDDING_QUEUE"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			cfg.Shedding.MaxEmbeddingQueue = n
+		}
+	}
+	if v := os.Getenv("ENGRAM_SHED_RETRY_AFTER"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Shedding.RetryAfter = Duration(d)
+		}
+	}
+
+	// LLM
+	if v := os.Getenv("ENGRAM_LLM_PROVIDER"); v != "" {
+		cfg.LLM.Provider = v
+	}
+	if v := os.Getenv("ENGRAM_LLM_MODEL"); v != "" {
+		cfg.LLM.Model = v
+	}
+	if v := os.Getenv("ENGRAM_LLM_BASE_URL"); v != "" {
+		cfg.LLM.BaseURL = v
+	}
+	if v := os.Getenv("ENGRAM_LLM_API_KEY"); v != "" {
+		cfg.LLM.APIKey = v
+	} else if cfg.LLM.Provider == "openai" {
+		cfg.LLM.APIKey = cfg.Embedding.APIKey
+	}
+	if v := os.Getenv("ENGRAM_LLM_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.LLM.Timeout = Duration(d)
+		}
+	}
+
+	// Consolidation
+	if v := os.Getenv("ENGRAM_CONSOLIDATION_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Consolidation.Interval = Duration(d)
+		}
+	}
+	if v := os.Getenv("ENGRAM_CONSOLIDATION_THRESHOLD"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			cfg.Consolidation.Threshold = f
+		}
+	}
+	if v := os.Getenv("ENGRAM_CONSOLIDATION_APPLY"); v != "" {
+		cfg.Consolidation.Apply = v == "true" || v == "1"
+	}
+	if v := os.Getenv("ENGRAM_CONSOLIDATION_MAX_PROPOSALS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.Consolidation.MaxProposals = n
+		}
+	}
+
+	// Leader election
+	if v := os.Getenv("ENGRAM_LEADER_ELECTION"); v != "" {
+		cfg.Leader.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("ENGRAM_LEADER_LEASE_PATH"); v != "" {
+		cfg.Leader.LeasePath = v
+	}
+	if v := os.Getenv("ENGRAM_LEADER_LEASE_DURATION"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Leader.LeaseDuration = Duration(d)
+		}
+	}
+	if v := os.Getenv("ENGRAM_LEADER_ID"); v != "" {
+		cfg.Leader.ID = v
+	}
+
+	// Feedback
+	if v := os.Getenv("ENGRAM_FEEDBACK_RATE_LIMIT_WINDOW"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Feedback.RateLimitWindow = Duration(d)
+		}
+	}
+	if v := os.Getenv("ENGRAM_FEEDBACK_RATE_LIMIT_MAX"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.Feedback.RateLimitMax = n
+		}
+	}
+	if v := os.Getenv("ENGRAM_FEEDBACK_EXCLUDE_SELF"); v != "" {
+		cfg.Feedback.ExcludeSelf = v == "true" || v == "1"
+	}
+	if v := os.Getenv("ENGRAM_FEEDBACK_SELF_WEIGHT"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			cfg.Feedback.SelfWeight = f
+		}
+	}
+
+	// Fault injection
+	for env, dst := range map[string]*float64{
+		"ENGRAM_FAULT_EMBEDDING_ERROR_RATE": &cfg.Faults.EmbeddingErrorRate,
+		"ENGRAM_FAULT_DATABASE_BUSY_RATE":   &cfg.Faults.DatabaseBusyRate,
+	} {
+		if v := os.Getenv(env); v != "" {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				*dst = f
+			}
+		}
+	}
+	for env, dst := range map[string]*Duration{
+		"ENGRAM_FAULT_EMBEDDING_LATENCY": &cfg.Faults.EmbeddingLatency,
+		"ENGRAM_FAULT_DATABASE_LATENCY":  &cfg.Faults.DatabaseLatency,
+	} {
+		if v := os.Getenv(env); v != "" {
+			if d, err := time.ParseDuration(v); err == nil {
+				*dst = Duration(d)
+			}
+		}
+	}
+}
+
+// readSecretFiles replaces each secret whose file is configured with the
+// file's contents. A file that cannot be read is an error rather than a
+// fallback to the environment, which would hide a broken mount.
+func readSecretFiles(cfg *Config) error {
+	for _, sf := range []struct {
+		name  string
+		file  string
+		value *string
+	}{
+		{"OPENAI_API_KEY_FILE", cfg.Embedding.APIKeyFile, &cfg.Embedding.APIKey},
+		{"ENGRAM_API_KEY_FILE", cfg.Auth.APIKeyFile, &cfg.Auth.APIKey},
+		{"ENGRAM_ADMIN_API_KEY_FILE", cfg.Auth.AdminKeyFile, &cfg.Auth.AdminKey},
+		{"ENGRAM_S3_ACCESS_KEY_FILE", cfg.SnapshotStorage.AccessKeyFile, &cfg.SnapshotStorage.AccessKey},
+		{"ENGRAM_S3_SECRET_KEY_FILE", cfg.SnapshotStorage.SecretKeyFile, &cfg.SnapshotStorage.SecretKey},
+		{"ENGRAM_EVENT_BUS_SASL_PASSWORD_FILE", cfg.EventBus.SASL.PasswordFile, &cfg.EventBus.SASL.Password},
+	} {
+		if sf.file == "" {
+			continue
+		}
+		v, err := secrets.ReadFile(sf.file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", sf.name, err)
+		}
+		*sf.value = v
+	}
+
+	// The openai summarizer shares the embedding key unless given its own
+	if cfg.Embedding.APIKeyFile != "" && cfg.LLM.Provider == "openai" && os.Getenv("ENGRAM_LLM_API_KEY") == "" {
+		cfg.LLM.APIKey = cfg.Embedding.APIKey
+	}
+	return nil
 }
 
 // validate checks that required configuration values are set.
@@ -385,6 +1523,19 @@ func (c *Config) validate() error {
 	return nil
 }
 
+// parseRoutePairs parses "route=value" pairs separated by commas, skipping
+// pairs without a route.
+func parseRoutePairs(v string) map[string]string {
+	pairs := make(map[string]string)
+	for _, pair := range strings.Split(v, ",") {
+		route, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && route != "" {
+			pairs[route] = value
+		}
+	}
+	return pairs
+}
+
 // boolPtr returns a pointer to a bool value.
 func boolPtr(b bool) *bool {
 	return &b
@@ -408,10 +1559,46 @@ func LoadStoresConfig() (*StoresConfig, error) {
 	if v := os.Getenv("ENGRAM_STORES_ROOT"); v != "" {
 		cfg.Stores.RootPath = v
 	}
+	applyStoreLayoutEnv(&cfg.Stores)
 
 	return &cfg.Stores, nil
 }
 
+// LoadPluginsConfig loads only the external plugin settings.
+// Like LoadStoresConfig, it skips API key validation for CLI use.
+func LoadPluginsConfig() (*PluginsConfig, error) {
+	cfg := newDefaults()
+
+	configPath := getEnv("ENGRAM_CONFIG_PATH", "config/engram.yaml")
+	if err := loadYAMLFile(cfg, configPath); err != nil {
+		return nil, err
+	}
+
+	if v := os.Getenv("ENGRAM_PLUGINS_DIR"); v != "" {
+		cfg.Plugins.Dir = v
+	}
+
+	return &cfg.Plugins, nil
+}
+
+// applyStoreLayoutEnv applies the env overrides of where stores are kept
+// beyond the root. ENGRAM_STORES_PATHS lists "pattern=path" pairs,
+// comma-separated, in match order.
+func applyStoreLayoutEnv(cfg *StoresConfig) {
+	if v := os.Getenv("ENGRAM_STORES_PATHS"); v != "" {
+		cfg.Paths = nil
+		for _, pair := range strings.Split(v, ",") {
+			match, path, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok && match != "" && path != "" {
+				cfg.Paths = append(cfg.Paths, StorePathConfig{Match: match, Path: path})
+			}
+		}
+	}
+	if v := os.Getenv("ENGRAM_STORES_ARCHIVE_PATH"); v != "" {
+		cfg.ArchivePath = v
+	}
+}
+
 // getEnv returns the value of an environment variable or a default.
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -18,29 +19,129 @@ func clearEnv(t *testing.T) {
 		"ENGRAM_READ_TIMEOUT",
 		"ENGRAM_WRITE_TIMEOUT",
 		"ENGRAM_SHUTDOWN_TIMEOUT",
+		"ENGRAM_LATENCY_SLOS",
+		"ENGRAM_PPROF",
 		"ENGRAM_DB_PATH",
+		"ENGRAM_DB_PAGE_SIZE",
+		"ENGRAM_DB_CACHE_SIZE",
+		"ENGRAM_DB_MMAP_SIZE",
+		"ENGRAM_DB_TEMP_STORE",
+		"ENGRAM_DB_SLOW_QUERY_THRESHOLD",
+		"ENGRAM_FEEDBACK_RATE_LIMIT_WINDOW",
+		"ENGRAM_FEEDBACK_RATE_LIMIT_MAX",
+		"ENGRAM_FEEDBACK_EXCLUDE_SELF",
+		"ENGRAM_FEEDBACK_SELF_WEIGHT",
 		"OPENAI_API_KEY",
+		"OPENAI_API_KEY_FILE",
 		"ENGRAM_EMBEDDING_MODEL",
+		"ENGRAM_EMBEDDING_CHUNK_SIZE",
+		"ENGRAM_EMBEDDING_CHUNK_OVERLAP",
+		"ENGRAM_EMBEDDING_QUANTIZATION",
 		"ENGRAM_API_KEY",
+		"ENGRAM_ADMIN_API_KEY",
+		"ENGRAM_API_KEY_FILE",
+		"ENGRAM_ADMIN_API_KEY_FILE",
+		"ENGRAM_SECRETS_REFRESH_INTERVAL",
+		"ENGRAM_AUTH_LOCKOUT_MAX_FAILURES",
+		"ENGRAM_AUTH_LOCKOUT_WINDOW",
+		"ENGRAM_AUTH_LOCKOUT_DURATION",
 		"ENGRAM_SNAPSHOT_INTERVAL",
 		"ENGRAM_DECAY_INTERVAL",
 		"ENGRAM_EMBEDDING_RETRY_INTERVAL",
 		"ENGRAM_EMBEDDING_RETRY_MAX_ATTEMPTS",
 		"ENGRAM_LOG_LEVEL",
 		"ENGRAM_LOG_FORMAT",
+		"ENGRAM_LOG_ACCESS_SAMPLE_RATES",
 		"ENGRAM_CONFIG_PATH",
 		"ENGRAM_DEV_MODE",
 		"ENGRAM_DEDUPLICATION_ENABLED",
 		"ENGRAM_SIMILARITY_THRESHOLD",
+		"ENGRAM_DEDUPLICATION_APPROXIMATE",
+		"ENGRAM_DEDUPLICATION_PROBE_RADIUS",
+		"ENGRAM_DEDUPLICATION_MAX_AGE",
+		"ENGRAM_DEDUPLICATION_MIN_CONFIDENCE",
 		"ENGRAM_STORES_ROOT",
+		"ENGRAM_STORES_PATHS",
+		"ENGRAM_STORES_ARCHIVE_PATH",
+		"ENGRAM_STORES_MAX_OPEN",
+		"ENGRAM_STORES_IDLE_TIMEOUT",
 		"ENGRAM_ADDRESS", // legacy
 		"ENGRAM_SNAPSHOT_BUCKET",
 		"ENGRAM_S3_ENDPOINT",
 		"ENGRAM_S3_REGION",
 		"ENGRAM_S3_ACCESS_KEY",
 		"ENGRAM_S3_SECRET_KEY",
+		"ENGRAM_S3_ACCESS_KEY_FILE",
+		"ENGRAM_S3_SECRET_KEY_FILE",
 		"ENGRAM_S3_USE_SSL",
 		"ENGRAM_S3_URL_EXPIRY",
+		"ENGRAM_S3_RESTORE_ON_STARTUP",
+		"ENGRAM_S3_UPLOAD_BANDWIDTH",
+		"ENGRAM_S3_UPLOAD_CONCURRENCY",
+		"ENGRAM_PLUGINS_DIR",
+		"ENGRAM_SYNC_MAX_PUSH_BYTES",
+		"ENGRAM_SYNC_PUSH_SESSION_TTL",
+		"ENGRAM_SYNC_MAX_CLOCK_SKEW",
+		"ENGRAM_SYNC_SIGNING_REQUIRED",
+		"ENGRAM_SYNC_SIGNING_MAX_AGE",
+		"ENGRAM_IDEMPOTENCY_INTERVAL",
+		"ENGRAM_IDEMPOTENCY_MAX_ENTRIES",
+		"ENGRAM_DISK_CHECK_INTERVAL",
+		"ENGRAM_MAX_INGEST_BYTES",
+		"ENGRAM_TRUSTED_PROXIES",
+		"ENGRAM_DISK_THRESHOLDS",
+		"ENGRAM_WEBHOOK_URL",
+		"ENGRAM_WEBHOOK_SECRET",
+		"ENGRAM_WEBHOOK_EVENTS",
+		"ENGRAM_EVENT_BUS_DRIVER",
+		"ENGRAM_EVENT_BUS_URL",
+		"ENGRAM_EVENT_BUS_TOPIC_PREFIX",
+		"ENGRAM_EVENT_BUS_JETSTREAM",
+		"ENGRAM_EVENT_BUS_TLS",
+		"ENGRAM_EVENT_BUS_TLS_CA_FILE",
+		"ENGRAM_EVENT_BUS_TLS_CERT_FILE",
+		"ENGRAM_EVENT_BUS_TLS_KEY_FILE",
+		"ENGRAM_EVENT_BUS_CREDS_FILE",
+		"ENGRAM_EVENT_BUS_NKEY_FILE",
+		"ENGRAM_EVENT_BUS_SASL_MECHANISM",
+		"ENGRAM_EVENT_BUS_SASL_USERNAME",
+		"ENGRAM_EVENT_BUS_SASL_PASSWORD",
+		"ENGRAM_EVENT_BUS_SASL_PASSWORD_FILE",
+		"ENGRAM_SEARCH_FUSION",
+		"ENGRAM_SEARCH_KEYWORD_WEIGHT",
+		"ENGRAM_SEARCH_SEMANTIC_WEIGHT",
+		"ENGRAM_SEARCH_RRF_K",
+		"ENGRAM_SEARCH_CONFIDENCE_BOOST",
+		"ENGRAM_SEARCH_VALIDATION_BOOST",
+		"ENGRAM_SEARCH_FEEDBACK_BOOST",
+		"ENGRAM_SEARCH_RECENCY_BOOST",
+		"ENGRAM_SEARCH_VALIDATION_PIVOT",
+		"ENGRAM_SEARCH_RECENCY_HALF_LIFE",
+		"ENGRAM_SEARCH_QUERY_CACHE_TTL",
+		"ENGRAM_SEARCH_QUERY_CACHE_SIZE",
+		"ENGRAM_SHED_MAX_IN_FLIGHT",
+		"ENGRAM_SHED_INGEST_MAX_IN_FLIGHT",
+		"ENGRAM_SHED_MAX_BUSY_ERRORS",
+		"ENGRAM_SHED_BUSY_WINDOW",
+		"ENGRAM_SHED_MAX_EMBEDDING_QUEUE",
+		"ENGRAM_SHED_RETRY_AFTER",
+		"ENGRAM_LLM_PROVIDER",
+		"ENGRAM_LLM_MODEL",
+		"ENGRAM_LLM_BASE_URL",
+		"ENGRAM_LLM_API_KEY",
+		"ENGRAM_LLM_TIMEOUT",
+		"ENGRAM_CONSOLIDATION_INTERVAL",
+		"ENGRAM_CONSOLIDATION_THRESHOLD",
+		"ENGRAM_CONSOLIDATION_APPLY",
+		"ENGRAM_CONSOLIDATION_MAX_PROPOSALS",
+		"ENGRAM_LEADER_ELECTION",
+		"ENGRAM_LEADER_LEASE_PATH",
+		"ENGRAM_LEADER_LEASE_DURATION",
+		"ENGRAM_LEADER_ID",
+		"ENGRAM_FAULT_EMBEDDING_ERROR_RATE",
+		"ENGRAM_FAULT_EMBEDDING_LATENCY",
+		"ENGRAM_FAULT_DATABASE_BUSY_RATE",
+		"ENGRAM_FAULT_DATABASE_LATENCY",
 	}
 	for _, v := range envVars {
 		os.Unsetenv(v)
@@ -718,6 +819,30 @@ func TestConfig_StoresRootPath_EnvOverride(t *testing.T) {
 	}
 }
 
+// Test: store handle limits default and env overrides
+func TestConfig_StoresHandleLimits(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Stores.MaxOpen != 256 || time.Duration(cfg.Stores.IdleTimeout) != 30*time.Minute {
+		t.Errorf("defaults: MaxOpen = %d, IdleTimeout = %v", cfg.Stores.MaxOpen, time.Duration(cfg.Stores.IdleTimeout))
+	}
+
+	os.Setenv("ENGRAM_STORES_MAX_OPEN", "32")
+	os.Setenv("ENGRAM_STORES_IDLE_TIMEOUT", "5m")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Stores.MaxOpen != 32 || time.Duration(cfg.Stores.IdleTimeout) != 5*time.Minute {
+		t.Errorf("env: MaxOpen = %d, IdleTimeout = %v", cfg.Stores.MaxOpen, time.Duration(cfg.Stores.IdleTimeout))
+	}
+}
+
 // Test: Stores.RootPath from YAML file
 func TestConfig_StoresRootPath_FromYAML(t *testing.T) {
 	clearEnv(t)
@@ -798,6 +923,10 @@ func TestConfig_SnapshotStorage_Defaults(t *testing.T) {
 	if !*cfg.SnapshotStorage.UseSSL {
 		t.Error("SnapshotStorage.UseSSL should default to true")
 	}
+	if cfg.SnapshotStorage.UploadConcurrency != 1 || cfg.SnapshotStorage.UploadBandwidth != 0 {
+		t.Errorf("uploads = %d at once, %d B/s, want 1 at once, unlimited",
+			cfg.SnapshotStorage.UploadConcurrency, cfg.SnapshotStorage.UploadBandwidth)
+	}
 	// URLExpiry defaults to 15 minutes
 	if dur(cfg.SnapshotStorage.URLExpiry) != 15*time.Minute {
 		t.Errorf("SnapshotStorage.URLExpiry = %v, want 15m", dur(cfg.SnapshotStorage.URLExpiry))
@@ -823,6 +952,9 @@ func TestConfig_SnapshotStorage_EnvOverrides(t *testing.T) {
 	os.Setenv("ENGRAM_S3_SECRET_KEY", "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY")
 	os.Setenv("ENGRAM_S3_USE_SSL", "false")
 	os.Setenv("ENGRAM_S3_URL_EXPIRY", "30m")
+	os.Setenv("ENGRAM_S3_RESTORE_ON_STARTUP", "true")
+	os.Setenv("ENGRAM_S3_UPLOAD_BANDWIDTH", "10485760")
+	os.Setenv("ENGRAM_S3_UPLOAD_CONCURRENCY", "4")
 
 	cfg, err := Load()
 	if err != nil {
@@ -850,6 +982,15 @@ func TestConfig_SnapshotStorage_EnvOverrides(t *testing.T) {
 	if dur(cfg.SnapshotStorage.URLExpiry) != 30*time.Minute {
 		t.Errorf("URLExpiry = %v, want 30m", dur(cfg.SnapshotStorage.URLExpiry))
 	}
+	if !cfg.SnapshotStorage.RestoreOnStartup {
+		t.Error("RestoreOnStartup should be true when env var is 'true'")
+	}
+	if cfg.SnapshotStorage.UploadBandwidth != 10<<20 {
+		t.Errorf("UploadBandwidth = %d, want %d", cfg.SnapshotStorage.UploadBandwidth, 10<<20)
+	}
+	if cfg.SnapshotStorage.UploadConcurrency != 4 {
+		t.Errorf("UploadConcurrency = %d, want 4", cfg.SnapshotStorage.UploadConcurrency)
+	}
 }
 
 // Test: S3 secrets are NOT serializable via YAML
@@ -983,3 +1124,1100 @@ func TestConfig_SnapshotStorage_UseSSL_EnvValues(t *testing.T) {
 		})
 	}
 }
+
+// Test: Plugins.Dir defaults to empty (external plugins disabled)
+func TestConfig_PluginsDir_Default(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Plugins.Dir != "" {
+		t.Errorf("Plugins.Dir = %q, want empty", cfg.Plugins.Dir)
+	}
+}
+
+// Test: ENGRAM_PLUGINS_DIR env var overrides YAML
+func TestConfig_PluginsDir_EnvOverridesYAML(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	yamlContent := `
+plugins:
+  dir: /yaml/plugins
+`
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if cfg.Plugins.Dir != "/yaml/plugins" {
+		t.Errorf("Plugins.Dir = %q, want %q", cfg.Plugins.Dir, "/yaml/plugins")
+	}
+
+	os.Setenv("ENGRAM_PLUGINS_DIR", "/env/plugins")
+	os.Setenv("ENGRAM_CONFIG_PATH", configPath)
+	pluginsCfg, err := LoadPluginsConfig()
+	if err != nil {
+		t.Fatalf("LoadPluginsConfig() error = %v", err)
+	}
+	if pluginsCfg.Dir != "/env/plugins" {
+		t.Errorf("Plugins.Dir = %q, want %q", pluginsCfg.Dir, "/env/plugins")
+	}
+}
+
+func TestConfig_Sync_Defaults(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Sync.MaxPushBytes != 16<<20 {
+		t.Errorf("Sync.MaxPushBytes = %d, want %d", cfg.Sync.MaxPushBytes, 16<<20)
+	}
+	if time.Duration(cfg.Sync.PushSessionTTL) != time.Hour {
+		t.Errorf("Sync.PushSessionTTL = %v, want 1h", time.Duration(cfg.Sync.PushSessionTTL))
+	}
+	if time.Duration(cfg.Sync.MaxClockSkew) != 5*time.Minute {
+		t.Errorf("Sync.MaxClockSkew = %v, want 5m", time.Duration(cfg.Sync.MaxClockSkew))
+	}
+}
+
+func TestConfig_Sync_EnvOverrides(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	os.Setenv("ENGRAM_SYNC_MAX_PUSH_BYTES", "1048576")
+	os.Setenv("ENGRAM_SYNC_PUSH_SESSION_TTL", "10m")
+	os.Setenv("ENGRAM_SYNC_MAX_CLOCK_SKEW", "30s")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Sync.MaxPushBytes != 1048576 {
+		t.Errorf("Sync.MaxPushBytes = %d, want 1048576", cfg.Sync.MaxPushBytes)
+	}
+	if time.Duration(cfg.Sync.PushSessionTTL) != 10*time.Minute {
+		t.Errorf("Sync.PushSessionTTL = %v, want 10m", time.Duration(cfg.Sync.PushSessionTTL))
+	}
+	if time.Duration(cfg.Sync.MaxClockSkew) != 30*time.Second {
+		t.Errorf("Sync.MaxClockSkew = %v, want 30s", time.Duration(cfg.Sync.MaxClockSkew))
+	}
+}
+
+func TestConfig_MaxIngestBytes(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Server.MaxIngestBytes != 4<<20 {
+		t.Errorf("Server.MaxIngestBytes = %d, want %d", cfg.Server.MaxIngestBytes, 4<<20)
+	}
+
+	os.Setenv("ENGRAM_MAX_INGEST_BYTES", "65536")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Server.MaxIngestBytes != 65536 {
+		t.Errorf("Server.MaxIngestBytes = %d, want 65536", cfg.Server.MaxIngestBytes)
+	}
+}
+
+func TestLoad_TrustedProxies(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Server.TrustedProxies) != 0 {
+		t.Errorf("default Server.TrustedProxies = %v, want none", cfg.Server.TrustedProxies)
+	}
+
+	os.Setenv("ENGRAM_TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.10")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := []string{"10.0.0.0/8", "192.168.1.10"}; !reflect.DeepEqual(cfg.Server.TrustedProxies, want) {
+		t.Errorf("Server.TrustedProxies = %v, want %v", cfg.Server.TrustedProxies, want)
+	}
+}
+
+func TestConfig_Idempotency_Defaults(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if time.Duration(cfg.Worker.IdempotencyInterval) != time.Hour {
+		t.Errorf("Worker.IdempotencyInterval = %v, want 1h", time.Duration(cfg.Worker.IdempotencyInterval))
+	}
+	if cfg.Worker.IdempotencyMaxEntries != 10000 {
+		t.Errorf("Worker.IdempotencyMaxEntries = %d, want 10000", cfg.Worker.IdempotencyMaxEntries)
+	}
+}
+
+func TestConfig_Idempotency_EnvOverrides(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	os.Setenv("ENGRAM_IDEMPOTENCY_INTERVAL", "15m")
+	os.Setenv("ENGRAM_IDEMPOTENCY_MAX_ENTRIES", "500")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if time.Duration(cfg.Worker.IdempotencyInterval) != 15*time.Minute {
+		t.Errorf("Worker.IdempotencyInterval = %v, want 15m", time.Duration(cfg.Worker.IdempotencyInterval))
+	}
+	if cfg.Worker.IdempotencyMaxEntries != 500 {
+		t.Errorf("Worker.IdempotencyMaxEntries = %d, want 500", cfg.Worker.IdempotencyMaxEntries)
+	}
+}
+
+func TestConfig_DiskMonitor_EnvOverrides(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if time.Duration(cfg.Worker.DiskCheckInterval) != time.Minute || len(cfg.Worker.DiskThresholds) != 3 {
+		t.Errorf("defaults = %v, %v", time.Duration(cfg.Worker.DiskCheckInterval), cfg.Worker.DiskThresholds)
+	}
+
+	os.Setenv("ENGRAM_DISK_CHECK_INTERVAL", "30s")
+	os.Setenv("ENGRAM_DISK_THRESHOLDS", "0.7, 0.85")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if time.Duration(cfg.Worker.DiskCheckInterval) != 30*time.Second {
+		t.Errorf("Worker.DiskCheckInterval = %v, want 30s", time.Duration(cfg.Worker.DiskCheckInterval))
+	}
+	if got := cfg.Worker.DiskThresholds; len(got) != 2 || got[0] != 0.7 || got[1] != 0.85 {
+		t.Errorf("Worker.DiskThresholds = %v, want [0.7 0.85]", got)
+	}
+}
+
+func TestConfig_Webhooks_Defaults(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(cfg.Webhooks.Endpoints) != 0 {
+		t.Errorf("Webhooks.Endpoints = %v, want none", cfg.Webhooks.Endpoints)
+	}
+	if cfg.Webhooks.MaxAttempts != 5 {
+		t.Errorf("Webhooks.MaxAttempts = %d, want 5", cfg.Webhooks.MaxAttempts)
+	}
+	if time.Duration(cfg.Webhooks.Timeout) != 10*time.Second {
+		t.Errorf("Webhooks.Timeout = %v, want 10s", time.Duration(cfg.Webhooks.Timeout))
+	}
+	if cfg.Webhooks.QueueSize != 1000 {
+		t.Errorf("Webhooks.QueueSize = %d, want 1000", cfg.Webhooks.QueueSize)
+	}
+}
+
+func TestConfig_Webhooks_EnvEndpoint(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	os.Setenv("ENGRAM_WEBHOOK_URL", "https://hooks.example.com/engram")
+	os.Setenv("ENGRAM_WEBHOOK_SECRET", "shh")
+	os.Setenv("ENGRAM_WEBHOOK_EVENTS", "lore.deleted, snapshot.completed")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(cfg.Webhooks.Endpoints) != 1 {
+		t.Fatalf("len(Webhooks.Endpoints) = %d, want 1", len(cfg.Webhooks.Endpoints))
+	}
+	ep := cfg.Webhooks.Endpoints[0]
+	if ep.URL != "https://hooks.example.com/engram" || ep.Secret != "shh" {
+		t.Errorf("endpoint = %+v, want URL and secret from env", ep)
+	}
+	if len(ep.Events) != 2 || ep.Events[0] != "lore.deleted" || ep.Events[1] != "snapshot.completed" {
+		t.Errorf("Events = %v, want [lore.deleted snapshot.completed]", ep.Events)
+	}
+}
+
+func TestLoadFromFile_WebhookSecretFromEnv(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	t.Setenv("TEST_HOOK_SECRET", "from-env")
+
+	configPath := filepath.Join(t.TempDir(), "engram.yaml")
+	yamlContent := `
+webhooks:
+  max_attempts: 3
+  endpoints:
+    - url: https://hooks.example.com/a
+      secret_env: TEST_HOOK_SECRET
+      events: [lore.merged]
+      stores: [project-a]
+`
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+
+	if cfg.Webhooks.MaxAttempts != 3 {
+		t.Errorf("Webhooks.MaxAttempts = %d, want 3", cfg.Webhooks.MaxAttempts)
+	}
+	if len(cfg.Webhooks.Endpoints) != 1 {
+		t.Fatalf("len(Webhooks.Endpoints) = %d, want 1", len(cfg.Webhooks.Endpoints))
+	}
+	ep := cfg.Webhooks.Endpoints[0]
+	if ep.Secret != "from-env" {
+		t.Errorf("Secret = %q, want resolved from TEST_HOOK_SECRET", ep.Secret)
+	}
+	if len(ep.Stores) != 1 || ep.Stores[0] != "project-a" {
+		t.Errorf("Stores = %v, want [project-a]", ep.Stores)
+	}
+}
+
+func TestConfig_EventBus_Defaults(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.EventBus.Driver != "" {
+		t.Errorf("EventBus.Driver = %q, want empty", cfg.EventBus.Driver)
+	}
+	if cfg.EventBus.TopicPrefix != "engram" {
+		t.Errorf("EventBus.TopicPrefix = %q, want engram", cfg.EventBus.TopicPrefix)
+	}
+	if time.Duration(cfg.EventBus.PollInterval) != time.Second {
+		t.Errorf("EventBus.PollInterval = %v, want 1s", time.Duration(cfg.EventBus.PollInterval))
+	}
+	if cfg.EventBus.BatchSize != 100 {
+		t.Errorf("EventBus.BatchSize = %d, want 100", cfg.EventBus.BatchSize)
+	}
+}
+
+func TestConfig_EventBus_EnvOverrides(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	os.Setenv("ENGRAM_EVENT_BUS_DRIVER", "nats")
+	os.Setenv("ENGRAM_EVENT_BUS_URL", "nats://localhost:4222")
+	os.Setenv("ENGRAM_EVENT_BUS_TOPIC_PREFIX", "lore")
+	os.Setenv("ENGRAM_EVENT_BUS_JETSTREAM", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.EventBus.Driver != "nats" || cfg.EventBus.URL != "nats://localhost:4222" {
+		t.Errorf("EventBus = %+v, want nats driver and URL from env", cfg.EventBus)
+	}
+	if cfg.EventBus.TopicPrefix != "lore" {
+		t.Errorf("EventBus.TopicPrefix = %q, want lore", cfg.EventBus.TopicPrefix)
+	}
+	if !cfg.EventBus.JetStream {
+		t.Error("EventBus.JetStream = false, want true")
+	}
+}
+
+func TestConfig_EventBus_TLSAndSASL(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("ENGRAM_EVENT_BUS_DRIVER", "kafka")
+	os.Setenv("ENGRAM_EVENT_BUS_TLS", "true")
+	os.Setenv("ENGRAM_EVENT_BUS_TLS_CA_FILE", "/etc/engram/ca.pem")
+	os.Setenv("ENGRAM_EVENT_BUS_SASL_MECHANISM", "SCRAM-SHA-512")
+	os.Setenv("ENGRAM_EVENT_BUS_SASL_USERNAME", "engram")
+	os.Setenv("ENGRAM_EVENT_BUS_SASL_PASSWORD_FILE", passwordFile)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.EventBus.TLS.Enabled || cfg.EventBus.TLS.CAFile != "/etc/engram/ca.pem" {
+		t.Errorf("EventBus.TLS = %+v, want enabled with CA file from env", cfg.EventBus.TLS)
+	}
+	sasl := cfg.EventBus.SASL
+	if sasl.Mechanism != "SCRAM-SHA-512" || sasl.Username != "engram" || sasl.Password != "s3cret" {
+		t.Errorf("EventBus.SASL = %+v, want SCRAM-SHA-512 engram with password from file", sasl)
+	}
+}
+
+func TestLoadFromFile_DigestReports(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	t.Setenv("TEST_SLACK_URL", "https://hooks.slack.com/services/T/B/x")
+
+	configPath := filepath.Join(t.TempDir(), "engram.yaml")
+	yamlContent := `
+digests:
+  top_n: 3
+  reports:
+    - store: default
+      schedule: weekly
+      format: slack
+      hour: 9
+      weekday: friday
+      url_env: TEST_SLACK_URL
+`
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+
+	if cfg.Digests.TopN != 3 {
+		t.Errorf("Digests.TopN = %d, want 3", cfg.Digests.TopN)
+	}
+	if time.Duration(cfg.Digests.CheckInterval) != 5*time.Minute {
+		t.Errorf("Digests.CheckInterval = %v, want default 5m", time.Duration(cfg.Digests.CheckInterval))
+	}
+	if len(cfg.Digests.Reports) != 1 {
+		t.Fatalf("len(Digests.Reports) = %d, want 1", len(cfg.Digests.Reports))
+	}
+	r := cfg.Digests.Reports[0]
+	if r.Store != "default" || r.Schedule != "weekly" || r.Hour != 9 || r.Weekday != "friday" {
+		t.Errorf("report = %+v", r)
+	}
+	if r.URL != "https://hooks.slack.com/services/T/B/x" {
+		t.Errorf("URL = %q, want resolved from TEST_SLACK_URL", r.URL)
+	}
+}
+
+func TestConfig_Search_Defaults(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := SearchConfig{
+		Fusion: "weighted", KeywordWeight: 0.5, SemanticWeight: 0.5, RRFK: 60,
+		ConfidenceBoost: 0.1, ValidationBoost: 0.1, FeedbackBoost: 0.1, RecencyBoost: 0.1,
+		ValidationPivot: 5, RecencyHalfLife: Duration(90 * 24 * time.Hour),
+		QueryCacheTTL: Duration(10 * time.Minute), QueryCacheSize: 1000,
+	}
+	if cfg.Search != want {
+		t.Errorf("Search = %+v, want %+v", cfg.Search, want)
+	}
+}
+
+func TestConfig_Search_EnvOverrides(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	os.Setenv("ENGRAM_SEARCH_FUSION", "rrf")
+	os.Setenv("ENGRAM_SEARCH_KEYWORD_WEIGHT", "0.3")
+	os.Setenv("ENGRAM_SEARCH_SEMANTIC_WEIGHT", "0.7")
+	os.Setenv("ENGRAM_SEARCH_RRF_K", "20")
+	os.Setenv("ENGRAM_SEARCH_CONFIDENCE_BOOST", "0")
+	os.Setenv("ENGRAM_SEARCH_VALIDATION_BOOST", "0.2")
+	os.Setenv("ENGRAM_SEARCH_FEEDBACK_BOOST", "0.3")
+	os.Setenv("ENGRAM_SEARCH_RECENCY_BOOST", "0.05")
+	os.Setenv("ENGRAM_SEARCH_VALIDATION_PIVOT", "10")
+	os.Setenv("ENGRAM_SEARCH_RECENCY_HALF_LIFE", "720h")
+	os.Setenv("ENGRAM_SEARCH_QUERY_CACHE_TTL", "0s")
+	os.Setenv("ENGRAM_SEARCH_QUERY_CACHE_SIZE", "50")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := SearchConfig{
+		Fusion: "rrf", KeywordWeight: 0.3, SemanticWeight: 0.7, RRFK: 20,
+		ConfidenceBoost: 0, ValidationBoost: 0.2, FeedbackBoost: 0.3, RecencyBoost: 0.05,
+		ValidationPivot: 10, RecencyHalfLife: Duration(720 * time.Hour),
+		QueryCacheTTL: 0, QueryCacheSize: 50,
+	}
+	if cfg.Search != want {
+		t.Errorf("Search = %+v, want %+v", cfg.Search, want)
+	}
+}
+
+func TestConfig_AccessSampleRates_Defaults(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if got := cfg.Log.AccessSampleRates["/api/v1/stores/{store_id}/sync/delta"]; got != 0.1 {
+		t.Errorf("sync delta sample rate = %v, want 0.1", got)
+	}
+}
+
+func TestConfig_AccessSampleRates_EnvOverride(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	os.Setenv("ENGRAM_LOG_ACCESS_SAMPLE_RATES", "/api/v1/health=0.01, /api/v1/lore/search=0.5,bogus,/x=nan?")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := map[string]float64{"/api/v1/health": 0.01, "/api/v1/lore/search": 0.5}
+	if !reflect.DeepEqual(cfg.Log.AccessSampleRates, want) {
+		t.Errorf("AccessSampleRates = %v, want %v", cfg.Log.AccessSampleRates, want)
+	}
+}
+
+func TestConfig_LatencySLOs_Defaults(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if got := time.Duration(cfg.Server.LatencySLOs["/api/v1/lore/feedback"]); got != 500*time.Millisecond {
+		t.Errorf("feedback SLO = %v, want 500ms", got)
+	}
+}
+
+func TestConfig_LatencySLOs_EnvOverride(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	os.Setenv("ENGRAM_LATENCY_SLOS", "/api/v1/lore/search=250ms,/api/v1/lore/delta=soon")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := map[string]Duration{"/api/v1/lore/search": Duration(250 * time.Millisecond)}
+	if !reflect.DeepEqual(cfg.Server.LatencySLOs, want) {
+		t.Errorf("LatencySLOs = %v, want %v", cfg.Server.LatencySLOs, want)
+	}
+}
+
+func TestConfig_Shedding_Defaults(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := SheddingConfig{
+		MaxInFlight: 512, IngestMaxInFlight: 256, MaxBusyErrors: 5, BusyWindow: Duration(30 * time.Second),
+		MaxEmbeddingQueue: 10000, RetryAfter: Duration(5 * time.Second),
+	}
+	if cfg.Shedding != want {
+		t.Errorf("Shedding = %+v, want %+v", cfg.Shedding, want)
+	}
+}
+
+func TestConfig_Shedding_EnvOverrides(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	os.Setenv("ENGRAM_SHED_MAX_IN_FLIGHT", "100")
+	os.Setenv("ENGRAM_SHED_INGEST_MAX_IN_FLIGHT", "0")
+	os.Setenv("ENGRAM_SHED_MAX_BUSY_ERRORS", "2")
+	os.Setenv("ENGRAM_SHED_BUSY_WINDOW", "1m")
+	os.Setenv("ENGRAM_SHED_MAX_EMBEDDING_QUEUE", "500")
+	os.Setenv("ENGRAM_SHED_RETRY_AFTER", "10s")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := SheddingConfig{
+		MaxInFlight: 100, IngestMaxInFlight: 0, MaxBusyErrors: 2, BusyWindow: Duration(time.Minute),
+		MaxEmbeddingQueue: 500, RetryAfter: Duration(10 * time.Second),
+	}
+	if cfg.Shedding != want {
+		t.Errorf("Shedding = %+v, want %+v", cfg.Shedding, want)
+	}
+}
+
+func TestConfig_LLM_Defaults(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	os.Setenv("OPENAI_API_KEY", "sk-embed")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := LLMConfig{Timeout: Duration(2 * time.Minute)}
+	if cfg.LLM != want {
+		t.Errorf("LLM = %+v, want %+v", cfg.LLM, want)
+	}
+}
+
+func TestConfig_LLM_EnvOverrides(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	os.Setenv("ENGRAM_LLM_PROVIDER", "ollama")
+	os.Setenv("ENGRAM_LLM_MODEL", "mistral")
+	os.Setenv("ENGRAM_LLM_BASE_URL", "http://ollama:11434")
+	os.Setenv("ENGRAM_LLM_TIMEOUT", "30s")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := LLMConfig{Provider: "ollama", Model: "mistral", BaseURL: "http://ollama:11434", Timeout: Duration(30 * time.Second)}
+	if cfg.LLM != want {
+		t.Errorf("LLM = %+v, want %+v", cfg.LLM, want)
+	}
+}
+
+func TestConfig_LLM_OpenAIKeyFallback(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	os.Setenv("OPENAI_API_KEY", "sk-embed")
+	os.Setenv("ENGRAM_LLM_PROVIDER", "openai")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.LLM.APIKey != "sk-embed" {
+		t.Errorf("LLM.APIKey = %q, want the embedding key", cfg.LLM.APIKey)
+	}
+
+	os.Setenv("ENGRAM_LLM_API_KEY", "sk-llm")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.LLM.APIKey != "sk-llm" {
+		t.Errorf("LLM.APIKey = %q, want sk-llm", cfg.LLM.APIKey)
+	}
+}
+
+func TestConfig_Pprof_EnvOverride(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	os.Setenv("ENGRAM_PPROF", "true")
+	os.Setenv("ENGRAM_ADMIN_API_KEY", "admin-secret")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !cfg.Server.Pprof {
+		t.Error("Server.Pprof = false, want true")
+	}
+	if cfg.Auth.AdminKey != "admin-secret" {
+		t.Errorf("Auth.AdminKey = %q, want admin-secret", cfg.Auth.AdminKey)
+	}
+}
+
+func TestConfig_DeduplicationApproximate(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if radius, approximate := cfg.GetDeduplicationProbeRadius(); approximate || radius != 3 {
+		t.Errorf("default probe radius = %d, %v; want 3, false", radius, approximate)
+	}
+
+	os.Setenv("ENGRAM_DEDUPLICATION_APPROXIMATE", "true")
+	os.Setenv("ENGRAM_DEDUPLICATION_PROBE_RADIUS", "2")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if radius, approximate := cfg.GetDeduplicationProbeRadius(); !approximate || radius != 2 {
+		t.Errorf("probe radius = %d, %v; want 2, true", radius, approximate)
+	}
+}
+
+func TestConfig_DeduplicationScope(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if maxAge, minConfidence := cfg.GetDeduplicationScope(); maxAge != 0 || minConfidence != 0 {
+		t.Errorf("default scope = %v, %v; want no limits", maxAge, minConfidence)
+	}
+
+	os.Setenv("ENGRAM_DEDUPLICATION_MAX_AGE", "2160h")
+	os.Setenv("ENGRAM_DEDUPLICATION_MIN_CONFIDENCE", "0.3")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if maxAge, minConfidence := cfg.GetDeduplicationScope(); maxAge != 90*24*time.Hour || minConfidence != 0.3 {
+		t.Errorf("scope = %v, %v; want 2160h, 0.3", maxAge, minConfidence)
+	}
+}
+
+func TestConfig_EmbeddingChunking_EnvOverride(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if size, overlap := cfg.GetEmbeddingChunking(); size != 0 || overlap != 200 {
+		t.Errorf("default chunking = %d, %d; want 0, 200", size, overlap)
+	}
+
+	os.Setenv("ENGRAM_EMBEDDING_CHUNK_SIZE", "1000")
+	os.Setenv("ENGRAM_EMBEDDING_CHUNK_OVERLAP", "100")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if size, overlap := cfg.GetEmbeddingChunking(); size != 1000 || overlap != 100 {
+		t.Errorf("chunking = %d, %d; want 1000, 100", size, overlap)
+	}
+}
+
+func TestConfig_EmbeddingQuantization_EnvOverride(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Embedding.Quantization != "none" {
+		t.Errorf("default quantization = %q, want none", cfg.Embedding.Quantization)
+	}
+
+	os.Setenv("ENGRAM_EMBEDDING_QUANTIZATION", "int8")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Embedding.Quantization != "int8" {
+		t.Errorf("quantization = %q, want int8", cfg.Embedding.Quantization)
+	}
+}
+
+func TestConfig_Consolidation_Defaults(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := ConsolidationConfig{Interval: Duration(24 * time.Hour), Threshold: 0.95, MaxProposals: 100}
+	if cfg.Consolidation != want {
+		t.Errorf("Consolidation = %+v, want %+v", cfg.Consolidation, want)
+	}
+}
+
+func TestConfig_Consolidation_EnvOverrides(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	os.Setenv("ENGRAM_CONSOLIDATION_INTERVAL", "6h")
+	os.Setenv("ENGRAM_CONSOLIDATION_THRESHOLD", "0.97")
+	os.Setenv("ENGRAM_CONSOLIDATION_APPLY", "true")
+	os.Setenv("ENGRAM_CONSOLIDATION_MAX_PROPOSALS", "10")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := ConsolidationConfig{Interval: Duration(6 * time.Hour), Threshold: 0.97, Apply: true, MaxProposals: 10}
+	if cfg.Consolidation != want {
+		t.Errorf("Consolidation = %+v, want %+v", cfg.Consolidation, want)
+	}
+}
+
+func TestConfig_Leader_EnvOverrides(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := LeaderConfig{LeaseDuration: Duration(15 * time.Second)}
+	if cfg.Leader != want {
+		t.Errorf("Leader defaults = %+v, want %+v", cfg.Leader, want)
+	}
+
+	os.Setenv("ENGRAM_LEADER_ELECTION", "true")
+	os.Setenv("ENGRAM_LEADER_LEASE_PATH", "/shared/leader.db")
+	os.Setenv("ENGRAM_LEADER_LEASE_DURATION", "30s")
+	os.Setenv("ENGRAM_LEADER_ID", "engram-0")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want = LeaderConfig{Enabled: true, LeasePath: "/shared/leader.db", LeaseDuration: Duration(30 * time.Second), ID: "engram-0"}
+	if cfg.Leader != want {
+		t.Errorf("Leader = %+v, want %+v", cfg.Leader, want)
+	}
+}
+
+func TestConfig_Faults_EnvOverrides(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Faults.Enabled() {
+		t.Errorf("Faults enabled by default: %+v", cfg.Faults)
+	}
+
+	os.Setenv("ENGRAM_FAULT_EMBEDDING_ERROR_RATE", "0.2")
+	os.Setenv("ENGRAM_FAULT_EMBEDDING_LATENCY", "500ms")
+	os.Setenv("ENGRAM_FAULT_DATABASE_BUSY_RATE", "0.05")
+	os.Setenv("ENGRAM_FAULT_DATABASE_LATENCY", "10ms")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := FaultsConfig{
+		EmbeddingErrorRate: 0.2,
+		EmbeddingLatency:   Duration(500 * time.Millisecond),
+		DatabaseBusyRate:   0.05,
+		DatabaseLatency:    Duration(10 * time.Millisecond),
+	}
+	if cfg.Faults != want {
+		t.Errorf("Faults = %+v, want %+v", cfg.Faults, want)
+	}
+	if !cfg.Faults.Enabled() {
+		t.Error("Enabled() = false, want true")
+	}
+	if err := cfg.Faults.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestFaultsConfig_Validate(t *testing.T) {
+	for _, f := range []FaultsConfig{
+		{EmbeddingErrorRate: 1.5},
+		{DatabaseBusyRate: -0.1},
+		{DatabaseLatency: Duration(-time.Second)},
+	} {
+		if err := f.Validate(); err == nil {
+			t.Errorf("Validate(%+v) error = nil", f)
+		}
+	}
+}
+
+func TestLoadFromFile_SyncSigning(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	t.Setenv("TEST_LAPTOP_SECRET", "from-env")
+	os.Setenv("ENGRAM_SYNC_SIGNING_REQUIRED", "true")
+	os.Setenv("ENGRAM_SYNC_SIGNING_MAX_AGE", "2m")
+
+	configPath := filepath.Join(t.TempDir(), "engram.yaml")
+	yamlContent := `
+sync:
+  signing:
+    sources:
+      - source_id: laptop
+        secret_env: TEST_LAPTOP_SECRET
+`
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	signing := cfg.Sync.Signing
+	if len(signing.Sources) != 1 || signing.Sources[0].Secret != "from-env" {
+		t.Errorf("Sources = %+v, want laptop with its secret from env", signing.Sources)
+	}
+	if !signing.Required || time.Duration(signing.MaxAge) != 2*time.Minute {
+		t.Errorf("Required = %v, MaxAge = %v, want true and 2m", signing.Required, time.Duration(signing.MaxAge))
+	}
+	if err := signing.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestSyncSigningConfig_Validate(t *testing.T) {
+	for _, c := range []SyncSigningConfig{
+		{Required: true},
+		{Sources: []SyncSigningSource{{SourceID: "laptop", SecretEnv: "UNSET"}}},
+		{Sources: []SyncSigningSource{{Secret: "s"}}},
+		{Sources: []SyncSigningSource{{SourceID: "laptop", Secret: "a"}, {SourceID: "laptop", Secret: "b"}}},
+		{MaxAge: Duration(-time.Second)},
+	} {
+		if err := c.Validate(); err == nil {
+			t.Errorf("Validate(%+v) error = nil", c)
+		}
+	}
+}
+
+func TestLoadFromFile_SecretFiles(t *testing.T) {
+	clearEnv(t)
+	dir := t.TempDir()
+	writeSecret := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	os.Setenv("OPENAI_API_KEY", "sk-from-env")
+	os.Setenv("OPENAI_API_KEY_FILE", writeSecret("openai", "sk-from-file\n"))
+	os.Setenv("ENGRAM_SECRETS_REFRESH_INTERVAL", "30s")
+
+	configPath := filepath.Join(dir, "engram.yaml")
+	yamlContent := `
+auth:
+  api_key_file: ` + writeSecret("api_key", "api-from-file") + `
+snapshot_storage:
+  access_key_file: ` + writeSecret("s3_access", "AKIA") + `
+  secret_key_file: ` + writeSecret("s3_secret", "shh") + `
+`
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if cfg.Embedding.APIKey != "sk-from-file" {
+		t.Errorf("Embedding.APIKey = %q, want the file's key over the env's", cfg.Embedding.APIKey)
+	}
+	if cfg.Auth.APIKey != "api-from-file" {
+		t.Errorf("Auth.APIKey = %q, want api-from-file", cfg.Auth.APIKey)
+	}
+	if cfg.SnapshotStorage.AccessKey != "AKIA" || cfg.SnapshotStorage.SecretKey != "shh" {
+		t.Errorf("SnapshotStorage keys = %q, %q, want AKIA, shh", cfg.SnapshotStorage.AccessKey, cfg.SnapshotStorage.SecretKey)
+	}
+	if dur(cfg.Secrets.RefreshInterval) != 30*time.Second {
+		t.Errorf("Secrets.RefreshInterval = %v, want 30s", dur(cfg.Secrets.RefreshInterval))
+	}
+}
+
+func TestLoad_SecretFileMissing(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+	os.Setenv("ENGRAM_API_KEY_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	if _, err := Load(); err == nil {
+		t.Error("Load() with a missing secret file error = nil")
+	}
+}
+
+func TestLoad_AuthLockout(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.Auth.Lockout; got.MaxFailures != 20 || dur(got.Window) != time.Minute || dur(got.Duration) != 5*time.Minute {
+		t.Errorf("default Auth.Lockout = %+v, want 20 failures per 1m, 5m ban", got)
+	}
+
+	os.Setenv("ENGRAM_AUTH_LOCKOUT_MAX_FAILURES", "0")
+	os.Setenv("ENGRAM_AUTH_LOCKOUT_WINDOW", "10s")
+	os.Setenv("ENGRAM_AUTH_LOCKOUT_DURATION", "1h")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.Auth.Lockout; got.MaxFailures != 0 || dur(got.Window) != 10*time.Second || dur(got.Duration) != time.Hour {
+		t.Errorf("Auth.Lockout from env = %+v, want 0 failures per 10s, 1h ban", got)
+	}
+}
+
+func TestLoadFromFile_StorePaths(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	configPath := filepath.Join(t.TempDir(), "engram.yaml")
+	yamlContent := `
+stores:
+  paths:
+    - match: "hot/*"
+      path: /mnt/ssd/engram
+  archive_path: /mnt/cold/engram
+`
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if len(cfg.Stores.Paths) != 1 || cfg.Stores.Paths[0] != (StorePathConfig{Match: "hot/*", Path: "/mnt/ssd/engram"}) {
+		t.Errorf("Stores.Paths = %+v, want hot/* at /mnt/ssd/engram", cfg.Stores.Paths)
+	}
+	if cfg.Stores.ArchivePath != "/mnt/cold/engram" {
+		t.Errorf("Stores.ArchivePath = %q, want /mnt/cold/engram", cfg.Stores.ArchivePath)
+	}
+
+	// The env list replaces the file's, keeping its order
+	os.Setenv("ENGRAM_STORES_PATHS", "hot/api=/mnt/nvme, hot/*=/mnt/ssd")
+	stores, err := LoadStoresConfig()
+	if err != nil {
+		t.Fatalf("LoadStoresConfig() error = %v", err)
+	}
+	want := []StorePathConfig{{Match: "hot/api", Path: "/mnt/nvme"}, {Match: "hot/*", Path: "/mnt/ssd"}}
+	if len(stores.Paths) != 2 || stores.Paths[0] != want[0] || stores.Paths[1] != want[1] {
+		t.Errorf("Stores.Paths from env = %+v, want %+v", stores.Paths, want)
+	}
+}
+
+func TestLoad_SQLiteTuning(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.Database.SQLite; got != (SQLiteConfig{}) {
+		t.Errorf("default Database.SQLite = %+v, want zero (sized per database)", got)
+	}
+
+	os.Setenv("ENGRAM_DB_PAGE_SIZE", "16384")
+	os.Setenv("ENGRAM_DB_CACHE_SIZE", "67108864")
+	os.Setenv("ENGRAM_DB_MMAP_SIZE", "-1")
+	os.Setenv("ENGRAM_DB_TEMP_STORE", "memory")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := SQLiteConfig{PageSize: 16384, CacheSize: 64 << 20, MmapSize: -1, TempStore: "memory"}
+	if got := cfg.Database.SQLite; got != want {
+		t.Errorf("Database.SQLite from env = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoad_SlowQueryThreshold(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := dur(cfg.Database.SlowQueryThreshold); got != 0 {
+		t.Errorf("default Database.SlowQueryThreshold = %v, want 0 (off)", got)
+	}
+
+	os.Setenv("ENGRAM_DB_SLOW_QUERY_THRESHOLD", "250ms")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := dur(cfg.Database.SlowQueryThreshold); got != 250*time.Millisecond {
+		t.Errorf("Database.SlowQueryThreshold from env = %v, want 250ms", got)
+	}
+}
+
+func TestLoad_FeedbackRateLimit(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := dur(cfg.Feedback.RateLimitWindow); got != 24*time.Hour || cfg.Feedback.RateLimitMax != 1 {
+		t.Errorf("default Feedback = %v, %d; want 24h, 1", got, cfg.Feedback.RateLimitMax)
+	}
+
+	os.Setenv("ENGRAM_FEEDBACK_RATE_LIMIT_WINDOW", "1h")
+	os.Setenv("ENGRAM_FEEDBACK_RATE_LIMIT_MAX", "3")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := dur(cfg.Feedback.RateLimitWindow); got != time.Hour || cfg.Feedback.RateLimitMax != 3 {
+		t.Errorf("Feedback from env = %v, %d; want 1h, 3", got, cfg.Feedback.RateLimitMax)
+	}
+}
+
+func TestLoad_SelfFeedback(t *testing.T) {
+	clearEnv(t)
+	setDevModeEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Feedback.ExcludeSelf || cfg.Feedback.SelfWeight != 0 {
+		t.Errorf("default Feedback = %v, %v; want true, 0", cfg.Feedback.ExcludeSelf, cfg.Feedback.SelfWeight)
+	}
+
+	os.Setenv("ENGRAM_FEEDBACK_EXCLUDE_SELF", "false")
+	os.Setenv("ENGRAM_FEEDBACK_SELF_WEIGHT", "0.25")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Feedback.ExcludeSelf || cfg.Feedback.SelfWeight != 0.25 {
+		t.Errorf("Feedback from env = %v, %v; want false, 0.25", cfg.Feedback.ExcludeSelf, cfg.Feedback.SelfWeight)
+	}
+}
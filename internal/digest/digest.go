@@ -0,0 +1,179 @@
+// Package digest builds periodic lore activity reports and posts them to
+// Slack or Microsoft Teams incoming webhooks.
+//
+// A digest covers one schedule period ending at the report's scheduled time
+// and lists new lore, the busiest categories, the most validated entries and
+// the entries flagged incorrect.
+package digest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/config"
+)
+
+// Supported schedules.
+const (
+	ScheduleDaily  = "daily"
+	ScheduleWeekly = "weekly"
+)
+
+// Supported chat formats.
+const (
+	FormatSlack = "slack"
+	FormatTeams = "teams"
+)
+
+// Report is a validated digest report.
+type Report struct {
+	Name     string
+	StoreID  string
+	Schedule string
+	Format   string
+	Hour     int
+	Weekday  time.Weekday
+	URL      string
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// ParseReports validates the configured reports.
+func ParseReports(cfg config.DigestsConfig) ([]Report, error) {
+	reports := make([]Report, 0, len(cfg.Reports))
+	seen := make(map[string]bool)
+
+	for i, rc := range cfg.Reports {
+		r := Report{
+			Name:     rc.Name,
+			StoreID:  rc.Store,
+			Schedule: rc.Schedule,
+			Format:   rc.Format,
+			Hour:     rc.Hour,
+			Weekday:  time.Monday,
+			URL:      rc.URL,
+		}
+		if r.StoreID == "" {
+			return nil, fmt.Errorf("digest report %d: store is required", i)
+		}
+		if r.Schedule != ScheduleDaily && r.Schedule != ScheduleWeekly {
+			return nil, fmt.Errorf("digest report %d: schedule %q must be %q or %q", i, r.Schedule, ScheduleDaily, ScheduleWeekly)
+		}
+		if r.Format != FormatSlack && r.Format != FormatTeams {
+			return nil, fmt.Errorf("digest report %d: format %q must be %q or %q", i, r.Format, FormatSlack, FormatTeams)
+		}
+		if r.Hour < 0 || r.Hour > 23 {
+			return nil, fmt.Errorf("digest report %d: hour %d must be between 0 and 23", i, r.Hour)
+		}
+		if rc.Weekday != "" {
+			wd, ok := weekdays[strings.ToLower(rc.Weekday)]
+			if !ok {
+				return nil, fmt.Errorf("digest report %d: unknown weekday %q", i, rc.Weekday)
+			}
+			r.Weekday = wd
+		}
+		if u, err := url.Parse(r.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("digest report %d: url_env %q must hold an https webhook url", i, rc.URLEnv)
+		}
+		if r.Name == "" {
+			r.Name = r.Schedule + "-" + r.Format
+		}
+		key := r.StoreID + "\x00" + r.Name
+		if seen[key] {
+			return nil, fmt.Errorf("digest report %d: duplicate report %q for store %q; set a distinct name", i, r.Name, r.StoreID)
+		}
+		seen[key] = true
+
+		reports = append(reports, r)
+	}
+	return reports, nil
+}
+
+// Period returns the length of the window a digest covers.
+func (r Report) Period() time.Duration {
+	if r.Schedule == ScheduleWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// WindowEnd returns the most recent scheduled time at or before now, in UTC.
+// The digest sent at that time covers [WindowEnd-Period, WindowEnd).
+func (r Report) WindowEnd(now time.Time) time.Time {
+	now = now.UTC()
+	end := time.Date(now.Year(), now.Month(), now.Day(), r.Hour, 0, 0, 0, time.UTC)
+	if end.After(now) {
+		end = end.AddDate(0, 0, -1)
+	}
+	if r.Schedule == ScheduleWeekly {
+		back := (int(end.Weekday()) - int(r.Weekday) + 7) % 7
+		end = end.AddDate(0, 0, -back)
+	}
+	return end
+}
+
+// MetaKey is the store metadata key recording the end of the last window
+// posted for this report.
+func (r Report) MetaKey() string {
+	return "digest_last_sent:" + r.Name
+}
+
+// Sender posts a rendered digest to a chat webhook.
+type Sender interface {
+	Send(ctx context.Context, webhookURL string, body []byte) error
+}
+
+// HTTPSender posts digests over HTTP.
+type HTTPSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender creates a sender whose requests time out after timeout.
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &HTTPSender{client: &http.Client{Timeout: timeout}}
+}
+
+// Send posts body as JSON and fails on any non-2xx response.
+func (s *HTTPSender) Send(ctx context.Context, webhookURL string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "engram-digest")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// The URL embeds the webhook credential; keep it out of logs.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("post digest: %w", urlErr.Err)
+		}
+		return fmt.Errorf("post digest: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("post digest: status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
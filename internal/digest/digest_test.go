@@ -0,0 +1,160 @@
+package digest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/config"
+)
+
+func validReport() config.DigestReport {
+	return config.DigestReport{
+		Store:    "default",
+		Schedule: ScheduleDaily,
+		Format:   FormatSlack,
+		Hour:     9,
+		URLEnv:   "SLACK_URL",
+		URL:      "https://hooks.slack.com/services/T/B/x",
+	}
+}
+
+func TestParseReports_Defaults(t *testing.T) {
+	weekly := validReport()
+	weekly.Schedule = ScheduleWeekly
+	weekly.Format = FormatTeams
+
+	reports, err := ParseReports(config.DigestsConfig{Reports: []config.DigestReport{validReport(), weekly}})
+	if err != nil {
+		t.Fatalf("ParseReports() error = %v", err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 reports, got %d", len(reports))
+	}
+	if reports[0].Name != "daily-slack" || reports[1].Name != "weekly-teams" {
+		t.Errorf("names = %q, %q; want daily-slack, weekly-teams", reports[0].Name, reports[1].Name)
+	}
+	if reports[1].Weekday != time.Monday {
+		t.Errorf("Weekday = %v, want Monday", reports[1].Weekday)
+	}
+	if reports[0].MetaKey() != "digest_last_sent:daily-slack" {
+		t.Errorf("MetaKey() = %q", reports[0].MetaKey())
+	}
+}
+
+func TestParseReports_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*config.DigestReport)
+	}{
+		{"missing store", func(r *config.DigestReport) { r.Store = "" }},
+		{"unknown schedule", func(r *config.DigestReport) { r.Schedule = "hourly" }},
+		{"unknown format", func(r *config.DigestReport) { r.Format = "discord" }},
+		{"hour out of range", func(r *config.DigestReport) { r.Hour = 24 }},
+		{"unknown weekday", func(r *config.DigestReport) { r.Weekday = "funday" }},
+		{"missing url", func(r *config.DigestReport) { r.URL = "" }},
+		{"plain http url", func(r *config.DigestReport) { r.URL = "http://hooks.example.com/x" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := validReport()
+			tt.mutate(&r)
+			if _, err := ParseReports(config.DigestsConfig{Reports: []config.DigestReport{r}}); err == nil {
+				t.Error("ParseReports() should fail")
+			}
+		})
+	}
+}
+
+func TestParseReports_DuplicateName(t *testing.T) {
+	_, err := ParseReports(config.DigestsConfig{Reports: []config.DigestReport{validReport(), validReport()}})
+	if err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("ParseReports() error = %v, want duplicate error", err)
+	}
+
+	other := validReport()
+	other.Store = "project-a"
+	if _, err := ParseReports(config.DigestsConfig{Reports: []config.DigestReport{validReport(), other}}); err != nil {
+		t.Errorf("same name on different stores should be allowed: %v", err)
+	}
+}
+
+func TestReport_WindowEnd(t *testing.T) {
+	// 2026-03-04 is a Wednesday.
+	daily := Report{Schedule: ScheduleDaily, Hour: 9}
+	weekly := Report{Schedule: ScheduleWeekly, Hour: 9, Weekday: time.Monday}
+
+	tests := []struct {
+		name   string
+		report Report
+		now    time.Time
+		want   time.Time
+	}{
+		{"daily after hour", daily, time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC), time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)},
+		{"daily at hour", daily, time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC), time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)},
+		{"daily before hour", daily, time.Date(2026, 3, 4, 8, 59, 0, 0, time.UTC), time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)},
+		{"weekly midweek", weekly, time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC), time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)},
+		{"weekly before hour on weekday", weekly, time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC), time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)},
+		{"weekly after hour on weekday", weekly, time.Date(2026, 3, 9, 9, 30, 0, 0, time.UTC), time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)},
+		{"non-UTC input", daily, time.Date(2026, 3, 4, 5, 0, 0, 0, time.FixedZone("EST", -5*3600)), time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.WindowEnd(tt.now); !got.Equal(tt.want) {
+				t.Errorf("WindowEnd(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPSender_Send(t *testing.T) {
+	var gotType, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotType = r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	if err := NewHTTPSender(time.Second).Send(context.Background(), srv.URL, []byte(`{"text":"hi"}`)); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if gotType != "application/json" || gotBody != `{"text":"hi"}` {
+		t.Errorf("request = %q %q", gotType, gotBody)
+	}
+}
+
+func TestHTTPSender_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, "no_service")
+	}))
+	defer srv.Close()
+
+	err := NewHTTPSender(time.Second).Send(context.Background(), srv.URL+"/secret-token", []byte(`{}`))
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "no_service") {
+		t.Errorf("Send() error = %v, want status and body", err)
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("error leaks webhook url: %v", err)
+	}
+}
+
+func TestHTTPSender_TransportErrorOmitsURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	target := srv.URL + "/secret-token"
+	srv.Close()
+
+	err := NewHTTPSender(time.Second).Send(context.Background(), target, []byte(`{}`))
+	if err == nil {
+		t.Fatal("Send() should fail against a closed server")
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("error leaks webhook url: %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package digest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// maxContentRunes bounds how much of an entry's content is quoted.
+const maxContentRunes = 120
+
+// section is a titled list of lines, rendered as a block in either format.
+type section struct {
+	title string
+	lines []string
+	empty string
+}
+
+// Render builds the chat payload for d in the report's format.
+func Render(r Report, d *types.Digest) ([]byte, error) {
+	title := Title(r, d)
+	sections := buildSections(d)
+
+	switch r.Format {
+	case FormatSlack:
+		return renderSlack(title, sections)
+	case FormatTeams:
+		return renderTeams(title, sections)
+	default:
+		return nil, fmt.Errorf("unknown digest format %q", r.Format)
+	}
+}
+
+// Title returns the digest heading, e.g.
+// "Weekly lore digest: default (2026-02-23 09:00 to 2026-03-02 09:00 UTC)".
+func Title(r Report, d *types.Digest) string {
+	const layout = "2006-01-02 15:04"
+	schedule := "Weekly"
+	if r.Schedule == ScheduleDaily {
+		schedule = "Daily"
+	}
+	return fmt.Sprintf("%s lore digest: %s (%s to %s UTC)",
+		schedule, r.StoreID, d.Start.UTC().Format(layout), d.End.UTC().Format(layout))
+}
+
+func buildSections(d *types.Digest) []section {
+	categories := make([]string, len(d.TopCategories))
+	for i, c := range d.TopCategories {
+		categories[i] = fmt.Sprintf("%s: %d", c.Category, c.Count)
+	}
+
+	return []section{
+		{title: "New lore", lines: []string{fmt.Sprintf("%d entries added", d.NewLore)}},
+		{title: "Top categories", lines: categories, empty: "No new lore"},
+		{title: "Most validated", lines: entryLines(d.MostValidated, "helpful"), empty: "No helpful feedback"},
+		{title: "Flagged incorrect", lines: entryLines(d.FlaggedIncorrect, "incorrect"), empty: "No incorrect feedback"},
+	}
+}
+
+func entryLines(entries []types.DigestEntry, feedback string) []string {
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = fmt.Sprintf("%s (%s, %d %s, confidence %.2f) %s",
+			truncate(e.Content), e.Category, e.Count, feedback, e.Confidence, e.ID)
+	}
+	return lines
+}
+
+func truncate(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= maxContentRunes {
+		return s
+	}
+	return string(runes[:maxContentRunes-1]) + "…"
+}
+
+// renderSlack builds a Block Kit message with a plain-text fallback.
+func renderSlack(title string, sections []section) ([]byte, error) {
+	escape := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
+
+	blocks := []map[string]any{
+		{"type": "header", "text": map[string]any{"type": "plain_text", "text": title}},
+	}
+	for _, s := range sections {
+		var b strings.Builder
+		fmt.Fprintf(&b, "*%s*", s.title)
+		if len(s.lines) == 0 {
+			fmt.Fprintf(&b, "\n_%s_", s.empty)
+		}
+		for _, line := range s.lines {
+			b.WriteString("\n• " + escape(line))
+		}
+		blocks = append(blocks, map[string]any{
+			"type": "section",
+			"text": map[string]any{"type": "mrkdwn", "text": b.String()},
+		})
+	}
+
+	return json.Marshal(map[string]any{"text": title, "blocks": blocks})
+}
+
+// renderTeams builds an Adaptive Card message, accepted by Teams workflow
+// and connector webhooks.
+func renderTeams(title string, sections []section) ([]byte, error) {
+	body := []map[string]any{
+		{"type": "TextBlock", "text": title, "size": "Large", "weight": "Bolder", "wrap": true},
+	}
+	for _, s := range sections {
+		body = append(body, map[string]any{
+			"type": "TextBlock", "text": s.title, "weight": "Bolder", "spacing": "Medium", "wrap": true,
+		})
+		lines := s.lines
+		if len(lines) == 0 {
+			lines = []string{s.empty}
+		}
+		for _, line := range lines {
+			body = append(body, map[string]any{"type": "TextBlock", "text": "- " + line, "wrap": true, "spacing": "None"})
+		}
+	}
+
+	return json.Marshal(map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]any{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body":    body,
+			},
+		}},
+	})
+}
@@ -0,0 +1,124 @@
+package digest
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func testDigest() *types.Digest {
+	end := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	return &types.Digest{
+		Start:         end.Add(-7 * 24 * time.Hour),
+		End:           end,
+		NewLore:       12,
+		TopCategories: []types.CategoryCount{{Category: "PATTERN_OUTCOME", Count: 8}},
+		MostValidated: []types.DigestEntry{
+			{ID: "01A", Content: "Use <retry> & backoff", Category: "PATTERN_OUTCOME", Confidence: 0.9, Count: 4},
+		},
+		FlaggedIncorrect: []types.DigestEntry{},
+	}
+}
+
+func TestTitle(t *testing.T) {
+	d := testDigest()
+	if got := Title(Report{Schedule: ScheduleWeekly, StoreID: "default"}, d); got != "Weekly lore digest: default (2026-02-23 09:00 to 2026-03-02 09:00 UTC)" {
+		t.Errorf("weekly Title() = %q", got)
+	}
+	d.Start = d.End.Add(-24 * time.Hour)
+	if got := Title(Report{Schedule: ScheduleDaily, StoreID: "default"}, d); got != "Daily lore digest: default (2026-03-01 09:00 to 2026-03-02 09:00 UTC)" {
+		t.Errorf("daily Title() = %q", got)
+	}
+}
+
+func TestRender_Slack(t *testing.T) {
+	body, err := Render(Report{Schedule: ScheduleWeekly, Format: FormatSlack, StoreID: "default"}, testDigest())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	var msg struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Type string `json:"type"`
+			Text struct {
+				Text string `json:"text"`
+			} `json:"text"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !strings.HasPrefix(msg.Text, "Weekly lore digest") {
+		t.Errorf("fallback text = %q", msg.Text)
+	}
+	if len(msg.Blocks) != 5 || msg.Blocks[0].Type != "header" {
+		t.Fatalf("expected header and 4 sections, got %+v", msg.Blocks)
+	}
+	var all string
+	for _, b := range msg.Blocks {
+		all += b.Text.Text + "\n"
+	}
+	for _, want := range []string{"12 entries added", "PATTERN_OUTCOME: 8", "&lt;retry&gt; &amp; backoff", "No incorrect feedback"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("Slack payload missing %q: %s", want, all)
+		}
+	}
+}
+
+func TestRender_Teams(t *testing.T) {
+	body, err := Render(Report{Schedule: ScheduleWeekly, Format: FormatTeams, StoreID: "default"}, testDigest())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	var msg struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string `json:"type"`
+				Body []struct {
+					Text string `json:"text"`
+				} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if msg.Type != "message" || len(msg.Attachments) != 1 {
+		t.Fatalf("unexpected envelope: %s", body)
+	}
+	card := msg.Attachments[0]
+	if card.ContentType != "application/vnd.microsoft.card.adaptive" || card.Content.Type != "AdaptiveCard" {
+		t.Errorf("unexpected card: %+v", card)
+	}
+	var texts []string
+	for _, b := range card.Content.Body {
+		texts = append(texts, b.Text)
+	}
+	joined := strings.Join(texts, "\n")
+	for _, want := range []string{"Weekly lore digest", "- 12 entries added", "Use <retry> & backoff", "- No incorrect feedback"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Teams card missing %q:\n%s", want, joined)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	long := strings.Repeat("é", maxContentRunes+10)
+	got := truncate(long)
+	if n := len([]rune(got)); n != maxContentRunes {
+		t.Errorf("truncated length = %d runes, want %d", n, maxContentRunes)
+	}
+	if !strings.HasSuffix(got, "…") {
+		t.Errorf("truncated content should end with ellipsis: %q", got)
+	}
+	if got := truncate("line one\n  line two"); got != "line one line two" {
+		t.Errorf("truncate() = %q, want whitespace collapsed", got)
+	}
+}
@@ -0,0 +1,117 @@
+package embedding
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Compile-time interface check
+var _ Embedder = (*QueryCache)(nil)
+
+// QueryCache is an Embedder that remembers the embeddings of recent texts.
+//
+// It is meant for search queries, which agents often repeat verbatim. Texts
+// are normalized before lookup and embedding: case is folded and runs of
+// whitespace collapse to one space, so "Retry  Policy" and "retry policy"
+// share an entry. Entries expire after the TTL; when the cache is full, the
+// least recently used entry is evicted. Errors are not cached. EmbedBatch is
+// passed through uncached.
+type QueryCache struct {
+	Embedder
+
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+type queryCacheEntry struct {
+	key       string
+	vector    []float32
+	expiresAt time.Time
+}
+
+// NewQueryCache wraps inner with a cache holding at most maxEntries
+// embeddings for ttl each.
+func NewQueryCache(inner Embedder, ttl time.Duration, maxEntries int) *QueryCache {
+	return &QueryCache{
+		Embedder:   inner,
+		ttl:        ttl,
+		maxEntries: max(maxEntries, 1),
+		now:        time.Now,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Embed returns the cached embedding of the normalized text, embedding it
+// on a miss.
+func (c *QueryCache) Embed(ctx context.Context, text string) ([]float32, error) {
+	key := normalizeQuery(text)
+	if v, ok := c.get(key); ok {
+		return v, nil
+	}
+
+	v, err := c.Embedder.Embed(ctx, key)
+	if err != nil || len(v) == 0 {
+		return v, err
+	}
+	c.put(key, v)
+	return append([]float32(nil), v...), nil
+}
+
+// Len returns the number of cached embeddings, including expired ones not
+// yet evicted.
+func (c *QueryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *QueryCache) get(key string) ([]float32, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*queryCacheEntry)
+	if !c.now().Before(e.expiresAt) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	// Callers own the returned slice.
+	return append([]float32(nil), e.vector...), true
+}
+
+func (c *QueryCache) put(key string, v []float32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := &queryCacheEntry{key: key, vector: v, expiresAt: c.now().Add(c.ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(e)
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*queryCacheEntry).key)
+	}
+}
+
+// normalizeQuery folds case and collapses whitespace.
+func normalizeQuery(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
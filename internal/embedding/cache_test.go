@@ -0,0 +1,134 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingEmbedder returns a fixed vector and records the texts it embeds.
+type countingEmbedder struct {
+	texts []string
+	err   error
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	e.texts = append(e.texts, text)
+	if e.err != nil {
+		return nil, e.err
+	}
+	return []float32{float32(len(e.texts)), 0}, nil
+}
+
+func (e *countingEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	return nil, nil
+}
+
+func (e *countingEmbedder) ModelName() string { return "counting" }
+
+func newTestQueryCache(inner Embedder, ttl time.Duration, size int, now *time.Time) *QueryCache {
+	c := NewQueryCache(inner, ttl, size)
+	c.now = func() time.Time { return *now }
+	return c
+}
+
+func TestQueryCache_HitsOnNormalizedText(t *testing.T) {
+	inner := &countingEmbedder{}
+	now := time.Now()
+	c := newTestQueryCache(inner, time.Minute, 10, &now)
+	ctx := context.Background()
+
+	first, err := c.Embed(ctx, "  Retry   Policy\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.Embed(ctx, "retry policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(inner.texts) != 1 || inner.texts[0] != "retry policy" {
+		t.Errorf("embedded %q, want one call with the normalized text", inner.texts)
+	}
+	if first[0] != second[0] {
+		t.Errorf("vectors differ: %v vs %v", first, second)
+	}
+
+	// Callers may modify their copy without affecting the cache
+	second[0] = 99
+	third, _ := c.Embed(ctx, "retry policy")
+	if third[0] == 99 {
+		t.Error("cached vector was modified through a returned slice")
+	}
+}
+
+func TestQueryCache_ExpiresAfterTTL(t *testing.T) {
+	inner := &countingEmbedder{}
+	now := time.Now()
+	c := newTestQueryCache(inner, time.Minute, 10, &now)
+	ctx := context.Background()
+
+	c.Embed(ctx, "q")
+	now = now.Add(59 * time.Second)
+	c.Embed(ctx, "q")
+	if len(inner.texts) != 1 {
+		t.Fatalf("expected a hit within the TTL, got %d calls", len(inner.texts))
+	}
+
+	now = now.Add(time.Second)
+	c.Embed(ctx, "q")
+	if len(inner.texts) != 2 {
+		t.Errorf("expected a miss at the TTL, got %d calls", len(inner.texts))
+	}
+}
+
+func TestQueryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	inner := &countingEmbedder{}
+	now := time.Now()
+	c := newTestQueryCache(inner, time.Hour, 2, &now)
+	ctx := context.Background()
+
+	c.Embed(ctx, "a")
+	c.Embed(ctx, "b")
+	c.Embed(ctx, "a") // a is now most recent
+	c.Embed(ctx, "c") // evicts b
+
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+	calls := len(inner.texts)
+	c.Embed(ctx, "a")
+	if len(inner.texts) != calls {
+		t.Error("expected a to stay cached")
+	}
+	c.Embed(ctx, "b")
+	if len(inner.texts) != calls+1 {
+		t.Error("expected b to have been evicted")
+	}
+}
+
+func TestQueryCache_DoesNotCacheErrors(t *testing.T) {
+	inner := &countingEmbedder{err: errors.New("rate limited")}
+	now := time.Now()
+	c := newTestQueryCache(inner, time.Hour, 10, &now)
+	ctx := context.Background()
+
+	if _, err := c.Embed(ctx, "q"); err == nil {
+		t.Fatal("expected error")
+	}
+	inner.err = nil
+	if _, err := c.Embed(ctx, "q"); err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if len(inner.texts) != 2 {
+		t.Errorf("expected the failed embedding to be retried, got %d calls", len(inner.texts))
+	}
+}
+
+func TestQueryCache_PassesThroughModelName(t *testing.T) {
+	c := NewQueryCache(&countingEmbedder{}, time.Minute, 10)
+	if c.ModelName() != "counting" {
+		t.Errorf("ModelName() = %q", c.ModelName())
+	}
+}
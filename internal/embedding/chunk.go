@@ -0,0 +1,54 @@
+package embedding
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Chunk splits text into overlapping chunks of at most size bytes for
+// multi-vector embedding. Chunks end at whitespace where possible and
+// consecutive chunks share about overlap bytes, so a sentence cut at one
+// boundary appears whole in a neighbour. Text no longer than size, or a
+// non-positive size, yields no chunks: the entry's single embedding already
+// covers it.
+func Chunk(text string, size, overlap int) []string {
+	if size <= 0 || len(text) <= size {
+		return nil
+	}
+	overlap = min(max(overlap, 0), size/2)
+
+	var chunks []string
+	start := 0
+	for {
+		end := start + size
+		if end >= len(text) {
+			chunks = append(chunks, strings.TrimSpace(text[start:]))
+			return chunks
+		}
+		// Prefer to break after whitespace in the second half of the chunk.
+		if i := strings.LastIndexAny(text[start+size/2:end], " \t\n"); i >= 0 {
+			end = start + size/2 + i + 1
+		}
+		for end > start && !utf8.RuneStart(text[end]) {
+			end--
+		}
+		if end == start {
+			// size is smaller than one rune; take the whole rune.
+			end = start + 1
+			for end < len(text) && !utf8.RuneStart(text[end]) {
+				end++
+			}
+		}
+		chunks = append(chunks, strings.TrimSpace(text[start:end]))
+
+		next := end - overlap
+		// Start the next chunk on a word boundary inside the overlap.
+		if i := strings.IndexAny(text[next:end], " \t\n"); i >= 0 && overlap > 0 {
+			next += i + 1
+		}
+		for next < len(text) && !utf8.RuneStart(text[next]) {
+			next++
+		}
+		start = max(next, start+1)
+	}
+}
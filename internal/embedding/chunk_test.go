@@ -0,0 +1,59 @@
+package embedding
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestChunk_ShortTextHasNoChunks(t *testing.T) {
+	if got := Chunk("short text", 100, 10); got != nil {
+		t.Errorf("Chunk() = %q, want nil", got)
+	}
+	if got := Chunk(strings.Repeat("word ", 100), 0, 10); got != nil {
+		t.Errorf("Chunk() with size 0 = %d chunks, want nil", len(got))
+	}
+}
+
+func TestChunk_CoversTextWithinSize(t *testing.T) {
+	words := make([]string, 400)
+	for i := range words {
+		words[i] = "word" + strings.Repeat("x", i%7)
+	}
+	text := strings.Join(words, " ")
+
+	chunks := Chunk(text, 500, 100)
+	if len(chunks) < 2 {
+		t.Fatalf("Chunk() = %d chunks, want several", len(chunks))
+	}
+	for i, c := range chunks {
+		if len(c) > 500 {
+			t.Errorf("chunk %d is %d bytes, want <= 500", i, len(c))
+		}
+		if !strings.Contains(text, c) {
+			t.Errorf("chunk %d is not a substring of the text", i)
+		}
+		if i > 0 && strings.HasPrefix(c, "x") {
+			t.Errorf("chunk %d starts mid-word: %q", i, c[:10])
+		}
+	}
+	if !strings.HasPrefix(text, chunks[0]) || !strings.HasSuffix(text, chunks[len(chunks)-1]) {
+		t.Error("chunks do not span the whole text")
+	}
+	// Consecutive chunks overlap.
+	for i := 1; i < len(chunks); i++ {
+		tail := chunks[i-1][len(chunks[i-1])-20:]
+		if !strings.Contains(chunks[i], tail) {
+			t.Errorf("chunk %d does not overlap chunk %d", i, i-1)
+		}
+	}
+}
+
+func TestChunk_KeepsRunesWhole(t *testing.T) {
+	text := strings.Repeat("日本語", 200) // no whitespace to break at
+	for _, c := range Chunk(text, 100, 20) {
+		if !utf8.ValidString(c) {
+			t.Fatalf("chunk %q is not valid UTF-8", c)
+		}
+	}
+}
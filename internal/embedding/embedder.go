@@ -5,6 +5,9 @@ import "context"
 // Embedder defines the interface contract for embedding generation services.
 type Embedder interface {
 	Embed(ctx context.Context, content string) ([]float32, error)
+	// EmbedBatch embeds contents in order. On error the returned slice is
+	// nil, except with a *PartialError, where it holds the embeddings of the
+	// contents that succeeded and nil for the rest.
 	EmbedBatch(ctx context.Context, contents []string) ([][]float32, error)
 	ModelName() string
 }
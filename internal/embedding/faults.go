@@ -0,0 +1,63 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// Compile-time interface check
+var _ Embedder = (*Faulty)(nil)
+
+// ErrInjected is the error of calls failed by a Faulty embedder.
+var ErrInjected = errors.New("embedding provider unavailable (injected fault)")
+
+// Faulty is an Embedder that delays every call and fails a share of them,
+// for soak tests that exercise the pending-embedding fallback and the
+// retry worker without an unreliable provider. A failed batch fails whole,
+// as when the provider is unreachable. Never use it in production.
+type Faulty struct {
+	Embedder
+
+	errorRate float64
+	latency   time.Duration
+}
+
+// NewFaulty wraps inner so that each call waits latency and then fails
+// with ErrInjected at errorRate, a fraction from 0 to 1.
+func NewFaulty(inner Embedder, errorRate float64, latency time.Duration) *Faulty {
+	return &Faulty{Embedder: inner, errorRate: errorRate, latency: latency}
+}
+
+// Embed embeds content unless the call is chosen to fail.
+func (f *Faulty) Embed(ctx context.Context, content string) ([]float32, error) {
+	if err := f.inject(ctx); err != nil {
+		return nil, err
+	}
+	return f.Embedder.Embed(ctx, content)
+}
+
+// EmbedBatch embeds contents unless the call is chosen to fail.
+func (f *Faulty) EmbedBatch(ctx context.Context, contents []string) ([][]float32, error) {
+	if err := f.inject(ctx); err != nil {
+		return nil, err
+	}
+	return f.Embedder.EmbedBatch(ctx, contents)
+}
+
+func (f *Faulty) inject(ctx context.Context) error {
+	if f.latency > 0 {
+		t := time.NewTimer(f.latency)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	if f.errorRate > 0 && rand.Float64() < f.errorRate {
+		return ErrInjected
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFaulty_ErrorRate(t *testing.T) {
+	ctx := context.Background()
+
+	always := NewFaulty(&countingEmbedder{}, 1, 0)
+	if _, err := always.Embed(ctx, "text"); !errors.Is(err, ErrInjected) {
+		t.Errorf("Embed() error = %v, want ErrInjected", err)
+	}
+	if _, err := always.EmbedBatch(ctx, []string{"a", "b"}); !errors.Is(err, ErrInjected) {
+		t.Errorf("EmbedBatch() error = %v, want ErrInjected", err)
+	}
+
+	inner := &countingEmbedder{}
+	never := NewFaulty(inner, 0, 0)
+	if v, err := never.Embed(ctx, "text"); err != nil || len(v) == 0 {
+		t.Errorf("Embed() = %v, %v; want the inner embedding", v, err)
+	}
+	if never.ModelName() != "counting" {
+		t.Errorf("ModelName() = %q, want the inner model", never.ModelName())
+	}
+
+	half := NewFaulty(&countingEmbedder{}, 0.5, 0)
+	failed := 0
+	for range 200 {
+		if _, err := half.Embed(ctx, "text"); err != nil {
+			failed++
+		}
+	}
+	if failed < 50 || failed > 150 {
+		t.Errorf("%d of 200 calls failed, want about half", failed)
+	}
+}
+
+func TestFaulty_Latency(t *testing.T) {
+	f := NewFaulty(&countingEmbedder{}, 0, 20*time.Millisecond)
+
+	start := time.Now()
+	if _, err := f.Embed(context.Background(), "text"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Embed() took %v, want at least the injected 20ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := f.Embed(ctx, "text"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Embed() with cancelled context error = %v, want context.Canceled", err)
+	}
+}
@@ -2,8 +2,13 @@ package embedding
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
@@ -22,6 +27,10 @@ type EmbeddingsService interface {
 type OpenAI struct {
 	embeddings EmbeddingsService
 	model      openai.EmbeddingModel
+
+	// Per-request limits; zero means the provider default.
+	maxBatchInputs int
+	maxBatchTokens int
 }
 
 // NewOpenAI creates a new OpenAI embedding service
@@ -33,6 +42,22 @@ func NewOpenAI(apiKey, model string) *OpenAI {
 	}
 }
 
+// NewOpenAIWithKeyFunc creates an OpenAI embedding service that asks
+// apiKey for its key on every request, so a rotated key takes effect
+// without a restart.
+func NewOpenAIWithKeyFunc(apiKey func() string, model string) *OpenAI {
+	client := openai.NewClient(option.WithMiddleware(
+		func(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+			req.Header.Set("Authorization", "Bearer "+apiKey())
+			return next(req)
+		},
+	))
+	return &OpenAI{
+		embeddings: client.Embeddings,
+		model:      openai.EmbeddingModel(model),
+	}
+}
+
 // Embed generates an embedding for the given text
 func (o *OpenAI) Embed(ctx context.Context, text string) ([]float32, error) {
 	resp, err := o.embeddings.New(ctx, openai.EmbeddingNewParams{
@@ -58,12 +83,105 @@ func (o *OpenAI) Embed(ctx context.Context, text string) ([]float32, error) {
 	return embedding, nil
 }
 
-// EmbedBatch generates embeddings for multiple texts
+// Provider request limits for the embeddings endpoint.
+const (
+	defaultMaxBatchInputs = 2048
+	defaultMaxBatchTokens = 300_000
+)
+
+// estimateTokens returns a conservative token count for text. Tokenizers
+// average about four bytes per token on English prose; assuming three
+// leaves headroom for code and non-Latin scripts without a tokenizer
+// dependency. Underestimates are caught by splitting rejected batches.
+func estimateTokens(text string) int {
+	return len(text)/3 + 1
+}
+
+// PartialError reports inputs of a batch that the provider rejected on
+// their own, typically for exceeding the model's context length.
+// EmbedBatch returns it together with the embeddings of every other input;
+// rejected inputs get a nil embedding.
+type PartialError struct {
+	// Failed maps the index of each rejected input to the provider's error.
+	Failed map[int]error
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("batch embedding generation failed for %d inputs", len(e.Failed))
+}
+
+// EmbedBatch generates embeddings for multiple texts.
+//
+// Texts are sent in as many requests as the provider's per-request input
+// and token limits require. A request the provider rejects as too large is
+// split in half and retried, so one oversized text fails alone with a
+// *PartialError rather than failing the whole batch.
 func (o *OpenAI) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
 	if len(texts) == 0 {
 		return [][]float32{}, nil
 	}
 
+	embeddings := make([][]float32, len(texts))
+	failed := make(map[int]error)
+	for _, span := range o.splitBatch(texts) {
+		if err := o.embedSpan(ctx, texts, span[0], span[1], embeddings, failed); err != nil {
+			return nil, err
+		}
+	}
+	if len(failed) > 0 {
+		return embeddings, &PartialError{Failed: failed}
+	}
+	return embeddings, nil
+}
+
+// splitBatch partitions texts into [start, end) spans that fit the
+// per-request limits by estimated token count.
+func (o *OpenAI) splitBatch(texts []string) [][2]int {
+	maxInputs, maxTokens := o.maxBatchInputs, o.maxBatchTokens
+	if maxInputs <= 0 {
+		maxInputs = defaultMaxBatchInputs
+	}
+	if maxTokens <= 0 {
+		maxTokens = defaultMaxBatchTokens
+	}
+
+	var spans [][2]int
+	start, tokens := 0, 0
+	for i, text := range texts {
+		n := estimateTokens(text)
+		if i > start && (i-start >= maxInputs || tokens+n > maxTokens) {
+			spans = append(spans, [2]int{start, i})
+			start, tokens = i, 0
+		}
+		tokens += n
+	}
+	return append(spans, [2]int{start, len(texts)})
+}
+
+// embedSpan embeds texts[lo:hi] into out, halving the span whenever the
+// provider rejects it as too large and recording inputs rejected alone.
+func (o *OpenAI) embedSpan(ctx context.Context, texts []string, lo, hi int, out [][]float32, failed map[int]error) error {
+	embeddings, err := o.embedRequest(ctx, texts[lo:hi])
+	if err == nil {
+		copy(out[lo:hi], embeddings)
+		return nil
+	}
+	if !tooLarge(err) {
+		return err
+	}
+	if hi-lo == 1 {
+		failed[lo] = err
+		return nil
+	}
+	mid := lo + (hi-lo)/2
+	if err := o.embedSpan(ctx, texts, lo, mid, out, failed); err != nil {
+		return err
+	}
+	return o.embedSpan(ctx, texts, mid, hi, out, failed)
+}
+
+// embedRequest embeds texts in a single provider request.
+func (o *OpenAI) embedRequest(ctx context.Context, texts []string) ([][]float32, error) {
 	resp, err := o.embeddings.New(ctx, openai.EmbeddingNewParams{
 		Input: openai.F[openai.EmbeddingNewParamsInputUnion](
 			openai.EmbeddingNewParamsInputArrayOfStrings(texts),
@@ -95,7 +213,45 @@ func (o *OpenAI) EmbedBatch(ctx context.Context, texts []string) ([][]float32, e
 	return embeddings, nil
 }
 
+// tooLarge reports whether err is the provider rejecting a request for
+// exceeding a token limit, either of one input or of the whole request.
+func tooLarge(err error) bool {
+	var apiErr *openai.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	switch apiErr.Code {
+	case "context_length_exceeded", "max_tokens_per_request":
+		return true
+	}
+	msg := strings.ToLower(apiErr.Message)
+	return strings.Contains(msg, "maximum context length") || strings.Contains(msg, "tokens per request")
+}
+
 // ModelName returns the embedding model name
 func (o *OpenAI) ModelName() string {
 	return string(o.model)
 }
+
+// RateLimited reports whether err is an OpenAI rate limit response that is
+// worth retrying, and the delay the response asked for, or zero if it gave
+// none. Exhausted quota is also sent as 429 but is not retryable.
+func RateLimited(err error) (time.Duration, bool) {
+	var apiErr *openai.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if apiErr.Code == "insufficient_quota" {
+		return 0, false
+	}
+	if apiErr.Response == nil {
+		return 0, true
+	}
+	if ms, err := strconv.ParseFloat(apiErr.Response.Header.Get("retry-after-ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond)), true
+	}
+	if secs, err := strconv.ParseFloat(apiErr.Response.Header.Get("Retry-After"), 64); err == nil && secs > 0 {
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	return 0, true
+}
@@ -3,8 +3,12 @@ package embedding
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
@@ -387,3 +391,131 @@ func TestEmbedBatch_RespectsContextCancellation(t *testing.T) {
 	}
 }
 
+func TestRateLimited(t *testing.T) {
+	header := func(k, v string) *http.Response {
+		return &http.Response{Header: http.Header{k: []string{v}}}
+	}
+	for name, tt := range map[string]struct {
+		err       error
+		wantDelay time.Duration
+		wantOK    bool
+	}{
+		"plain error":    {errors.New("boom"), 0, false},
+		"server error":   {&openai.Error{StatusCode: 500}, 0, false},
+		"no header":      {&openai.Error{StatusCode: 429}, 0, true},
+		"retry-after":    {&openai.Error{StatusCode: 429, Response: header("Retry-After", "2")}, 2 * time.Second, true},
+		"retry-after-ms": {&openai.Error{StatusCode: 429, Response: header("Retry-After-Ms", "250")}, 250 * time.Millisecond, true},
+		"wrapped":        {fmt.Errorf("batch embedding generation failed: %w", &openai.Error{StatusCode: 429}), 0, true},
+		"quota":          {&openai.Error{StatusCode: 429, Code: "insufficient_quota"}, 0, false},
+	} {
+		delay, ok := RateLimited(tt.err)
+		if delay != tt.wantDelay || ok != tt.wantOK {
+			t.Errorf("%s: RateLimited() = (%v, %v), want (%v, %v)", name, delay, ok, tt.wantDelay, tt.wantOK)
+		}
+	}
+}
+
+// funcEmbeddingsService answers each request with respond.
+type funcEmbeddingsService struct {
+	respond func(inputs []string) (*openai.CreateEmbeddingResponse, error)
+	batches [][]string
+}
+
+func (f *funcEmbeddingsService) New(ctx context.Context, params openai.EmbeddingNewParams, opts ...option.RequestOption) (*openai.CreateEmbeddingResponse, error) {
+	inputs := []string(params.Input.Value.(openai.EmbeddingNewParamsInputArrayOfStrings))
+	f.batches = append(f.batches, inputs)
+	return f.respond(inputs)
+}
+
+// echoEmbeddings returns one embedding per input holding the input's length.
+func echoEmbeddings(inputs []string) (*openai.CreateEmbeddingResponse, error) {
+	embeddings := make([][]float64, len(inputs))
+	for i, in := range inputs {
+		embeddings[i] = []float64{float64(len(in))}
+	}
+	return createMockResponse(embeddings), nil
+}
+
+func TestEmbedBatch_SplitsByTokenBudget(t *testing.T) {
+	service := &funcEmbeddingsService{respond: echoEmbeddings}
+	client := &OpenAI{embeddings: service, maxBatchTokens: 100}
+
+	// Each 150-byte text estimates at 51 tokens, so two don't fit in one
+	// request, but the short texts fit alongside the second.
+	long := strings.Repeat("x", 150)
+	inputs := []string{long, long, "short", "short"}
+	result, err := client.EmbedBatch(context.Background(), inputs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(service.batches) != 2 || len(service.batches[1]) != 3 {
+		t.Errorf("expected requests of 1 and 3 inputs, got %v", service.batches)
+	}
+	for i, in := range inputs {
+		if result[i][0] != float32(len(in)) {
+			t.Errorf("result[%d] = %v, want embedding of input %d", i, result[i], i)
+		}
+	}
+}
+
+func TestEmbedBatch_SplitsByInputCount(t *testing.T) {
+	service := &funcEmbeddingsService{respond: echoEmbeddings}
+	client := &OpenAI{embeddings: service, maxBatchInputs: 2}
+
+	if _, err := client.EmbedBatch(context.Background(), []string{"a", "b", "c", "d", "e"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(service.batches) != 3 {
+		t.Errorf("expected 3 requests, got %d", len(service.batches))
+	}
+}
+
+func TestEmbedBatch_IsolatesOversizedInput(t *testing.T) {
+	service := &funcEmbeddingsService{respond: func(inputs []string) (*openai.CreateEmbeddingResponse, error) {
+		for _, in := range inputs {
+			if in == "huge" {
+				return nil, &openai.Error{
+					StatusCode: 400,
+					Code:       "context_length_exceeded",
+					Message:    "This model's maximum context length is 8192 tokens",
+					Request:    httptest.NewRequest("POST", "/v1/embeddings", nil),
+					Response:   &http.Response{StatusCode: 400},
+				}
+			}
+		}
+		return echoEmbeddings(inputs)
+	}}
+	client := &OpenAI{embeddings: service}
+
+	result, err := client.EmbedBatch(context.Background(), []string{"a", "bb", "huge", "dddd"})
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("expected *PartialError, got %v", err)
+	}
+	if len(partial.Failed) != 1 || partial.Failed[2] == nil {
+		t.Errorf("Failed = %v, want only input 2", partial.Failed)
+	}
+	if result[2] != nil {
+		t.Errorf("result[2] = %v, want nil for the rejected input", result[2])
+	}
+	for _, i := range []int{0, 1, 3} {
+		if len(result[i]) == 0 {
+			t.Errorf("result[%d] is empty, want an embedding", i)
+		}
+	}
+}
+
+func TestEmbedBatch_DoesNotSplitOtherErrors(t *testing.T) {
+	service := &funcEmbeddingsService{respond: func(inputs []string) (*openai.CreateEmbeddingResponse, error) {
+		return nil, &openai.Error{StatusCode: 400, Code: "invalid_request_error"}
+	}}
+	client := &OpenAI{embeddings: service}
+
+	result, err := client.EmbedBatch(context.Background(), []string{"a", "b", "c"})
+	if err == nil || result != nil {
+		t.Fatalf("EmbedBatch() = (%v, %v), want nil result and an error", result, err)
+	}
+	if len(service.batches) != 1 {
+		t.Errorf("expected 1 request, got %d", len(service.batches))
+	}
+}
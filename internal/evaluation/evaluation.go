@@ -0,0 +1,87 @@
+// Package evaluation measures how well lore search retrieves what it
+// should, by running labeled queries and scoring their results against the
+// entries marked relevant.
+//
+// Each query scores its recall, the share of its relevant entries in the
+// top K results, and its reciprocal rank, 1/r for the first relevant entry
+// at rank r within the top K or 0 if there is none. A run reports the mean
+// of each over its queries, so two search configurations can be compared on
+// the same labels before one replaces the other.
+package evaluation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// DefaultK is the result cutoff used when a run does not set one.
+const DefaultK = 10
+
+// Searcher runs the search for a labeled query and returns up to k
+// results, most relevant first.
+type Searcher func(ctx context.Context, c types.EvalCase, k int) ([]types.SearchResult, error)
+
+// Run searches every case of set with search and scores the results at
+// cutoff k. The report's search configuration fields are left for the
+// caller, which knows what search does. It stops at the first failed
+// search.
+func Run(ctx context.Context, set *types.EvalSet, k int, search Searcher) (*types.EvalReport, error) {
+	start := time.Now()
+	report := &types.EvalReport{
+		Set:     set.Name,
+		K:       k,
+		Cases:   len(set.Cases),
+		Results: make([]types.EvalCaseResult, 0, len(set.Cases)),
+	}
+	for i, c := range set.Cases {
+		results, err := search(ctx, c, k)
+		if err != nil {
+			return nil, fmt.Errorf("case %d: %w", i, err)
+		}
+		result := Score(c, results, k)
+		report.RecallAtK += result.Recall
+		report.MRR += result.ReciprocalRank
+		report.Results = append(report.Results, result)
+	}
+	if n := len(set.Cases); n > 0 {
+		report.RecallAtK /= float64(n)
+		report.MRR /= float64(n)
+	}
+	report.DurationMS = time.Since(start).Milliseconds()
+	return report, nil
+}
+
+// Score scores the results of the search for c, considering only the
+// first k.
+func Score(c types.EvalCase, results []types.SearchResult, k int) types.EvalCaseResult {
+	relevant := make(map[string]bool, len(c.Relevant))
+	for _, id := range c.Relevant {
+		relevant[id] = true
+	}
+
+	result := types.EvalCaseResult{Query: c.Query}
+	found := make(map[string]bool, len(relevant))
+	for i, r := range results[:min(k, len(results))] {
+		if !relevant[r.Lore.ID] || found[r.Lore.ID] {
+			continue
+		}
+		found[r.Lore.ID] = true
+		if result.Rank == 0 {
+			result.Rank = i + 1
+			result.ReciprocalRank = 1 / float64(i+1)
+		}
+	}
+	if len(relevant) > 0 {
+		result.Recall = float64(len(found)) / float64(len(relevant))
+	}
+	for _, id := range c.Relevant {
+		if !found[id] {
+			result.Missed = append(result.Missed, id)
+			found[id] = true // list duplicate labels once
+		}
+	}
+	return result
+}
@@ -0,0 +1,136 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func results(ids ...string) []types.SearchResult {
+	out := make([]types.SearchResult, len(ids))
+	for i, id := range ids {
+		out[i].Lore.ID = id
+	}
+	return out
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name       string
+		relevant   []string
+		results    []types.SearchResult
+		k          int
+		wantRecall float64
+		wantRR     float64
+		wantRank   int
+		wantMissed []string
+	}{
+		{
+			name:       "first result relevant",
+			relevant:   []string{"a"},
+			results:    results("a", "b", "c"),
+			k:          3,
+			wantRecall: 1,
+			wantRR:     1,
+			wantRank:   1,
+		},
+		{
+			name:       "partial recall",
+			relevant:   []string{"a", "d"},
+			results:    results("b", "a", "c"),
+			k:          3,
+			wantRecall: 0.5,
+			wantRR:     0.5,
+			wantRank:   2,
+			wantMissed: []string{"d"},
+		},
+		{
+			name:       "relevant beyond cutoff",
+			relevant:   []string{"c"},
+			results:    results("a", "b", "c"),
+			k:          2,
+			wantMissed: []string{"c"},
+		},
+		{
+			name:       "fewer results than k",
+			relevant:   []string{"b", "x"},
+			results:    results("a", "b"),
+			k:          10,
+			wantRecall: 0.5,
+			wantRR:     0.5,
+			wantRank:   2,
+			wantMissed: []string{"x"},
+		},
+		{
+			name:       "duplicate labels count once",
+			relevant:   []string{"a", "a", "z", "z"},
+			results:    results("a"),
+			k:          1,
+			wantRecall: 0.5,
+			wantRR:     1,
+			wantRank:   1,
+			wantMissed: []string{"z"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Score(types.EvalCase{Query: "q", Relevant: tt.relevant}, tt.results, tt.k)
+			if got.Recall != tt.wantRecall || got.ReciprocalRank != tt.wantRR || got.Rank != tt.wantRank {
+				t.Errorf("Score() = recall %v, rr %v, rank %d; want %v, %v, %d",
+					got.Recall, got.ReciprocalRank, got.Rank, tt.wantRecall, tt.wantRR, tt.wantRank)
+			}
+			if !slices.Equal(got.Missed, tt.wantMissed) {
+				t.Errorf("Missed = %v, want %v", got.Missed, tt.wantMissed)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	set := &types.EvalSet{
+		Name: "golden",
+		Cases: []types.EvalCase{
+			{Query: "retries", Relevant: []string{"a"}},
+			{Query: "caching", Relevant: []string{"c"}},
+		},
+	}
+	ranked := map[string][]types.SearchResult{
+		"retries": results("a", "b"),
+		"caching": results("a", "b", "c", "d"),
+	}
+	var gotK int
+	report, err := Run(context.Background(), set, 3, func(_ context.Context, c types.EvalCase, k int) ([]types.SearchResult, error) {
+		gotK = k
+		return ranked[c.Query], nil
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if gotK != 3 {
+		t.Errorf("searcher got k = %d, want 3", gotK)
+	}
+	if report.Set != "golden" || report.K != 3 || report.Cases != 2 || len(report.Results) != 2 {
+		t.Errorf("report = %+v", report)
+	}
+	if report.RecallAtK != 1 {
+		t.Errorf("RecallAtK = %v, want 1", report.RecallAtK)
+	}
+	if want := (1 + 1.0/3) / 2; math.Abs(report.MRR-want) > 1e-12 {
+		t.Errorf("MRR = %v, want %v", report.MRR, want)
+	}
+}
+
+func TestRun_SearchError(t *testing.T) {
+	set := &types.EvalSet{Cases: []types.EvalCase{{Query: "q", Relevant: []string{"a"}}}}
+	boom := errors.New("boom")
+	_, err := Run(context.Background(), set, DefaultK, func(context.Context, types.EvalCase, int) ([]types.SearchResult, error) {
+		return nil, boom
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("Run() error = %v, want %v", err, boom)
+	}
+}
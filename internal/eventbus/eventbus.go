@@ -0,0 +1,126 @@
+// Package eventbus publishes change_log entries to a message broker so
+// analytics and stream-processing pipelines can consume lore changes.
+//
+// Entries reach the publisher through a per-store outbox table filled in the
+// same transaction as the change itself. Entries are removed from the outbox
+// only after the broker acknowledges them, giving at-least-once delivery;
+// consumers should deduplicate on (store_id, sequence).
+package eventbus
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/config"
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+// Supported drivers.
+const (
+	DriverNATS      = "nats"
+	DriverKafka     = "kafka"
+	DriverKafkaREST = "kafka-rest"
+)
+
+// Publisher delivers messages to a broker topic.
+// Publish returns only after the broker has acknowledged the message.
+type Publisher interface {
+	Publish(ctx context.Context, topic, key string, value []byte) error
+	Close() error
+}
+
+// Message is the JSON value published for each change_log entry.
+type Message struct {
+	StoreID string `json:"store_id"`
+	engramsync.ChangeLogEntry
+}
+
+// Topic returns the per-store topic name. Slashes in hierarchical store IDs
+// become dots, which is valid for both NATS subjects and Kafka topics.
+func Topic(prefix, storeID string) string {
+	name := strings.ReplaceAll(storeID, "/", ".")
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// New creates the Publisher selected by cfg.Driver.
+func New(cfg config.EventBusConfig) (Publisher, error) {
+	timeout := time.Duration(cfg.Timeout)
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	tlsConfig, err := newTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Driver {
+	case DriverNATS:
+		return NewNATSPublisher(cfg.URL, NATSOptions{
+			JetStream: cfg.JetStream,
+			Timeout:   timeout,
+			TLS:       tlsConfig,
+			CredsFile: cfg.CredsFile,
+			NKeyFile:  cfg.NKeyFile,
+		})
+	case DriverKafka:
+		// Kafka used to be reached only through the REST Proxy, so an
+		// http(s) URL still means one
+		if strings.HasPrefix(cfg.URL, "http://") || strings.HasPrefix(cfg.URL, "https://") {
+			return NewKafkaRESTPublisher(cfg.URL, timeout)
+		}
+		return NewKafkaPublisher(cfg.URL, KafkaOptions{
+			Timeout:       timeout,
+			TLS:           tlsConfig,
+			SASLMechanism: cfg.SASL.Mechanism,
+			Username:      cfg.SASL.Username,
+			Password:      cfg.SASL.Password,
+		})
+	case DriverKafkaREST:
+		return NewKafkaRESTPublisher(cfg.URL, timeout)
+	default:
+		return nil, fmt.Errorf("unknown event bus driver %q (want %q, %q or %q)", cfg.Driver, DriverNATS, DriverKafka, DriverKafkaREST)
+	}
+}
+
+// newTLSConfig builds the broker TLS configuration, or nil when TLS is
+// not enabled.
+func newTLSConfig(cfg config.EventBusTLSConfig) (*tls.Config, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	tc := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read event bus ca file: %w", err)
+		}
+		tc.RootCAs = x509.NewCertPool()
+		if !tc.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("event bus ca file %s holds no PEM certificates", cfg.CAFile)
+		}
+	}
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return nil, errors.New("event bus tls cert_file and key_file must be set together")
+	}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load event bus client certificate: %w", err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+	return tc, nil
+}
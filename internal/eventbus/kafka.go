@@ -0,0 +1,109 @@
+package eventbus
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+// SASL mechanisms supported by KafkaPublisher.
+const (
+	SASLPlain       = "PLAIN"
+	SASLScramSHA256 = "SCRAM-SHA-256"
+	SASLScramSHA512 = "SCRAM-SHA-512"
+)
+
+// KafkaPublisher produces to Kafka brokers directly. Records are produced
+// idempotently and count as delivered once every in-sync replica has them.
+// Topics are created on first use if the brokers allow it.
+type KafkaPublisher struct {
+	client *kgo.Client
+}
+
+// KafkaOptions contains the connection settings of a KafkaPublisher beyond
+// its seed brokers.
+type KafkaOptions struct {
+	Timeout time.Duration
+	TLS     *tls.Config
+	// SASLMechanism is SASLPlain, SASLScramSHA256 or SASLScramSHA512.
+	// Empty disables SASL.
+	SASLMechanism string
+	Username      string
+	Password      string
+}
+
+// NewKafkaPublisher creates a publisher for comma-separated host:port seed
+// brokers. It does not connect until the first publish.
+func NewKafkaPublisher(brokers string, opts KafkaOptions) (*KafkaPublisher, error) {
+	var seeds []string
+	for _, b := range strings.Split(brokers, ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			seeds = append(seeds, b)
+		}
+	}
+	if len(seeds) == 0 {
+		return nil, fmt.Errorf("invalid kafka brokers %q", brokers)
+	}
+
+	kgoOpts := []kgo.Opt{
+		kgo.SeedBrokers(seeds...),
+		kgo.ClientID("engram"),
+		kgo.AllowAutoTopicCreation(),
+		kgo.RecordDeliveryTimeout(opts.Timeout),
+	}
+	if opts.TLS != nil {
+		kgoOpts = append(kgoOpts, kgo.DialTLSConfig(opts.TLS))
+	}
+	if opts.SASLMechanism != "" {
+		mech, err := saslMechanism(opts.SASLMechanism, opts.Username, opts.Password)
+		if err != nil {
+			return nil, err
+		}
+		kgoOpts = append(kgoOpts, kgo.SASL(mech))
+	}
+
+	client, err := kgo.NewClient(kgoOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("kafka client: %w", err)
+	}
+	return &KafkaPublisher{client: client}, nil
+}
+
+func saslMechanism(name, user, pass string) (sasl.Mechanism, error) {
+	switch strings.ToUpper(name) {
+	case SASLPlain:
+		return plain.Auth{User: user, Pass: pass}.AsMechanism(), nil
+	case SASLScramSHA256:
+		return scram.Auth{User: user, Pass: pass}.AsSha256Mechanism(), nil
+	case SASLScramSHA512:
+		return scram.Auth{User: user, Pass: pass}.AsSha512Mechanism(), nil
+	default:
+		return nil, fmt.Errorf("unknown kafka sasl mechanism %q (want %s, %s or %s)", name, SASLPlain, SASLScramSHA256, SASLScramSHA512)
+	}
+}
+
+// Publish produces a single record keyed by key, so records of one entity
+// stay in one partition and in order.
+func (p *KafkaPublisher) Publish(ctx context.Context, topic, key string, value []byte) error {
+	record := &kgo.Record{Topic: topic, Value: value}
+	if key != "" {
+		record.Key = []byte(key)
+	}
+	if err := p.client.ProduceSync(ctx, record).FirstErr(); err != nil {
+		return fmt.Errorf("kafka produce: %w", err)
+	}
+	return nil
+}
+
+// Close closes the broker connections.
+func (p *KafkaPublisher) Close() error {
+	p.client.Close()
+	return nil
+}
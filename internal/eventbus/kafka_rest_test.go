@@ -0,0 +1,132 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/config"
+)
+
+func TestKafkaRESTPublisher_ProducesRecord(t *testing.T) {
+	var gotPath, gotType string
+	var gotBody struct {
+		Records []struct {
+			Key   string          `json:"key"`
+			Value json.RawMessage `json:"value"`
+		} `json:"records"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotType = r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		json.Unmarshal(b, &gotBody)
+		io.WriteString(w, `{"offsets":[{"partition":0,"offset":42,"error_code":null,"error":null}]}`)
+	}))
+	defer srv.Close()
+
+	p, err := NewKafkaRESTPublisher(srv.URL+"/", time.Second)
+	if err != nil {
+		t.Fatalf("NewKafkaRESTPublisher() error = %v", err)
+	}
+	defer p.Close()
+
+	if err := p.Publish(context.Background(), "engram.org.project", "entity-1", []byte(`{"sequence":1}`)); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if gotPath != "/topics/engram.org.project" {
+		t.Errorf("path = %q, want /topics/engram.org.project", gotPath)
+	}
+	if gotType != "application/vnd.kafka.json.v2+json" {
+		t.Errorf("Content-Type = %q", gotType)
+	}
+	if len(gotBody.Records) != 1 || gotBody.Records[0].Key != "entity-1" || string(gotBody.Records[0].Value) != `{"sequence":1}` {
+		t.Errorf("unexpected records: %+v", gotBody.Records)
+	}
+}
+
+func TestKafkaRESTPublisher_RecordError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"offsets":[{"partition":null,"offset":null,"error_code":50003,"error":"Kafka error: leader not available"}]}`)
+	}))
+	defer srv.Close()
+
+	p, _ := NewKafkaRESTPublisher(srv.URL, time.Second)
+	err := p.Publish(context.Background(), "engram.default", "", []byte(`{}`))
+	if err == nil || !strings.Contains(err.Error(), "leader not available") {
+		t.Errorf("Publish() error = %v, want record error", err)
+	}
+}
+
+func TestKafkaRESTPublisher_HTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{"error_code":40401,"message":"Topic not found."}`)
+	}))
+	defer srv.Close()
+
+	p, _ := NewKafkaRESTPublisher(srv.URL, time.Second)
+	err := p.Publish(context.Background(), "engram.default", "", []byte(`{}`))
+	if err == nil || !strings.Contains(err.Error(), "Topic not found") {
+		t.Errorf("Publish() error = %v, want status error", err)
+	}
+}
+
+func TestTopic(t *testing.T) {
+	tests := []struct {
+		prefix, storeID, want string
+	}{
+		{"engram", "default", "engram.default"},
+		{"engram", "org/team/project", "engram.org.team.project"},
+		{"", "default", "default"},
+	}
+	for _, tt := range tests {
+		if got := Topic(tt.prefix, tt.storeID); got != tt.want {
+			t.Errorf("Topic(%q, %q) = %q, want %q", tt.prefix, tt.storeID, got, tt.want)
+		}
+	}
+}
+
+func TestNew_Drivers(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.EventBusConfig
+		want any
+	}{
+		{"kafka brokers", config.EventBusConfig{Driver: DriverKafka, URL: "broker-1:9092,broker-2:9092"}, &KafkaPublisher{}},
+		{"kafka rest proxy url", config.EventBusConfig{Driver: DriverKafka, URL: "http://rest-proxy:8082"}, &KafkaRESTPublisher{}},
+		{"kafka-rest", config.EventBusConfig{Driver: DriverKafkaREST, URL: "https://rest-proxy:8082"}, &KafkaRESTPublisher{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(tt.cfg)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			defer p.Close()
+			if reflect.TypeOf(p) != reflect.TypeOf(tt.want) {
+				t.Errorf("New() = %T, want %T", p, tt.want)
+			}
+		})
+	}
+
+	bad := []config.EventBusConfig{
+		{Driver: "rabbitmq", URL: "amqp://localhost"},
+		{Driver: DriverKafka, URL: "broker:9092", SASL: config.EventBusSASLConfig{Mechanism: "GSSAPI"}},
+		{Driver: DriverKafka, URL: "broker:9092", TLS: config.EventBusTLSConfig{Enabled: true, CAFile: "/nonexistent/ca.pem"}},
+		{Driver: DriverKafka, URL: "broker:9092", TLS: config.EventBusTLSConfig{Enabled: true, CertFile: "client.pem"}},
+	}
+	for _, cfg := range bad {
+		if p, err := New(cfg); err == nil {
+			p.Close()
+			t.Errorf("New(%+v) should fail", cfg)
+		}
+	}
+}
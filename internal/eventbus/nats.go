@@ -0,0 +1,113 @@
+package eventbus
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher publishes to NATS with the official client.
+//
+// Without JetStream, each publish is followed by a flush, a server round
+// trip, so it is considered delivered once the server has processed it.
+// With JetStream, each publish waits for the stream's acknowledgement, so
+// the message is persisted before Publish returns. Subjects must be bound
+// to a stream when JetStream is enabled.
+//
+// The client reconnects on its own after the connection drops, and the
+// first connection is retried in the background, so the broker need not be
+// up when Engram starts.
+type NATSPublisher struct {
+	conn    *nats.Conn
+	js      nats.JetStreamContext
+	timeout time.Duration
+}
+
+// NATSOptions contains the connection settings of a NATSPublisher beyond
+// its URL. User and password, or a token, are taken from the URL.
+type NATSOptions struct {
+	JetStream bool
+	Timeout   time.Duration
+	// TLS secures the connection. tls:// URLs use TLS even when it is nil.
+	TLS *tls.Config
+	// CredsFile is a credentials file holding a user JWT and nkey seed.
+	CredsFile string
+	// NKeyFile is an nkey seed file.
+	NKeyFile string
+}
+
+// NewNATSPublisher creates a publisher for comma-separated nats:// or
+// tls:// server URLs.
+func NewNATSPublisher(rawURL string, opts NATSOptions) (*NATSPublisher, error) {
+	for _, server := range strings.Split(rawURL, ",") {
+		u, err := url.Parse(strings.TrimSpace(server))
+		if err != nil || (u.Scheme != "nats" && u.Scheme != "tls") || u.Host == "" {
+			return nil, fmt.Errorf("invalid nats url %q", server)
+		}
+	}
+
+	natsOpts := []nats.Option{
+		nats.Name("engram"),
+		nats.Timeout(opts.Timeout),
+		nats.MaxReconnects(-1),
+		nats.RetryOnFailedConnect(true),
+	}
+	if opts.TLS != nil {
+		natsOpts = append(natsOpts, nats.Secure(opts.TLS))
+	}
+	if opts.CredsFile != "" {
+		natsOpts = append(natsOpts, nats.UserCredentials(opts.CredsFile))
+	}
+	if opts.NKeyFile != "" {
+		opt, err := nats.NkeyOptionFromSeed(opts.NKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("nats nkey: %w", err)
+		}
+		natsOpts = append(natsOpts, opt)
+	}
+
+	conn, err := nats.Connect(rawURL, natsOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("nats connect: %w", err)
+	}
+	p := &NATSPublisher{conn: conn, timeout: opts.Timeout}
+	if opts.JetStream {
+		if p.js, err = conn.JetStream(); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("nats jetstream: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// Publish sends value on the subject topic. key is unused: NATS has no
+// partition key, and per-store subjects already preserve order.
+func (p *NATSPublisher) Publish(ctx context.Context, topic, key string, value []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	if p.js != nil {
+		if _, err := p.js.Publish(topic, value, nats.Context(ctx)); err != nil {
+			return fmt.Errorf("jetstream publish: %w", err)
+		}
+		return nil
+	}
+	if err := p.conn.Publish(topic, value); err != nil {
+		return fmt.Errorf("nats publish: %w", err)
+	}
+	if err := p.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("nats flush: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connection.
+func (p *NATSPublisher) Close() error {
+	p.conn.Close()
+	return nil
+}
@@ -0,0 +1,222 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNATS is a minimal NATS server speaking enough of the protocol for
+// publisher tests.
+type fakeNATS struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	connects []string
+	pubs     []fakePub
+	conns    int
+	inboxSID string // Subscription ID of the client's reply inbox
+
+	// ack, when set, is the JetStream acknowledgement payload sent in
+	// reply to publishes with a reply subject.
+	ack string
+}
+
+type fakePub struct {
+	subject string
+	reply   string
+	payload string
+}
+
+func newFakeNATS(t *testing.T) *fakeNATS {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	f := &fakeNATS{ln: ln}
+	go f.serve()
+	t.Cleanup(func() { ln.Close() })
+	return f
+}
+
+func (f *fakeNATS) url() string {
+	return "nats://" + f.ln.Addr().String()
+}
+
+func (f *fakeNATS) serve() {
+	for {
+		conn, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.conns++
+		f.mu.Unlock()
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeNATS) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	io.WriteString(conn, `INFO {"server_id":"fake","max_payload":1048576,"proto":1,"headers":true}`+"\r\n")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		op, args, _ := strings.Cut(line, " ")
+
+		switch op {
+		case "CONNECT":
+			f.mu.Lock()
+			f.connects = append(f.connects, args)
+			f.mu.Unlock()
+		case "PING":
+			io.WriteString(conn, "PONG\r\n")
+		case "SUB":
+			// SUB <subject> [queue] <sid>
+			fields := strings.Fields(args)
+			if strings.HasPrefix(fields[0], "_INBOX.") {
+				f.mu.Lock()
+				f.inboxSID = fields[len(fields)-1]
+				f.mu.Unlock()
+			}
+		case "PUB":
+			fields := strings.Fields(args)
+			n, _ := strconv.Atoi(fields[len(fields)-1])
+			payload := make([]byte, n+2)
+			io.ReadFull(r, payload)
+			pub := fakePub{subject: fields[0], payload: string(payload[:n])}
+			if len(fields) == 3 {
+				pub.reply = fields[1]
+			}
+
+			f.mu.Lock()
+			ack := f.ack
+			sid := f.inboxSID
+			f.pubs = append(f.pubs, pub)
+			f.mu.Unlock()
+
+			if pub.reply != "" && ack != "" {
+				fmt.Fprintf(conn, "MSG %s %s %d\r\n%s\r\n", pub.reply, sid, len(ack), ack)
+			}
+		}
+	}
+}
+
+func (f *fakeNATS) published() []fakePub {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]fakePub(nil), f.pubs...)
+}
+
+func TestNATSPublisher_PublishesAndFlushes(t *testing.T) {
+	srv := newFakeNATS(t)
+	p, err := NewNATSPublisher(srv.url(), NATSOptions{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewNATSPublisher() error = %v", err)
+	}
+	defer p.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := p.Publish(ctx, "engram.default", "entity-1", []byte(fmt.Sprintf(`{"n":%d}`, i))); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	pubs := srv.published()
+	if len(pubs) != 2 {
+		t.Fatalf("expected 2 publishes, got %d", len(pubs))
+	}
+	if pubs[0].subject != "engram.default" || pubs[1].payload != `{"n":1}` {
+		t.Errorf("unexpected publishes: %+v", pubs)
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.conns != 1 {
+		t.Errorf("expected a single reused connection, got %d", srv.conns)
+	}
+}
+
+func TestNATSPublisher_SendsCredentials(t *testing.T) {
+	srv := newFakeNATS(t)
+	p, err := NewNATSPublisher("nats://alice:secret@"+srv.ln.Addr().String(), NATSOptions{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewNATSPublisher() error = %v", err)
+	}
+	defer p.Close()
+
+	if err := p.Publish(context.Background(), "engram.default", "", []byte(`{}`)); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if len(srv.connects) != 1 || !strings.Contains(srv.connects[0], `"user":"alice"`) || !strings.Contains(srv.connects[0], `"pass":"secret"`) {
+		t.Errorf("CONNECT = %v, want user and pass", srv.connects)
+	}
+}
+
+func TestNATSPublisher_JetStreamAck(t *testing.T) {
+	srv := newFakeNATS(t)
+	srv.ack = `{"stream":"ENGRAM","seq":7}`
+	p, err := NewNATSPublisher(srv.url(), NATSOptions{JetStream: true, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewNATSPublisher() error = %v", err)
+	}
+	defer p.Close()
+
+	if err := p.Publish(context.Background(), "engram.default", "", []byte(`{}`)); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if pubs := srv.published(); len(pubs) != 1 || !strings.HasPrefix(pubs[0].reply, "_INBOX.") {
+		t.Errorf("expected publish with inbox reply subject, got %+v", pubs)
+	}
+}
+
+func TestNATSPublisher_JetStreamErrorAck(t *testing.T) {
+	srv := newFakeNATS(t)
+	srv.ack = `{"error":{"code":503,"description":"no stream matches subject"}}`
+	p, err := NewNATSPublisher(srv.url(), NATSOptions{JetStream: true, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewNATSPublisher() error = %v", err)
+	}
+	defer p.Close()
+
+	err = p.Publish(context.Background(), "engram.default", "", []byte(`{}`))
+	if err == nil || !strings.Contains(err.Error(), "no stream matches subject") {
+		t.Errorf("Publish() error = %v, want jetstream error", err)
+	}
+}
+
+func TestNATSPublisher_JetStreamTimeoutWithoutAck(t *testing.T) {
+	srv := newFakeNATS(t)
+	p, err := NewNATSPublisher(srv.url(), NATSOptions{JetStream: true, Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewNATSPublisher() error = %v", err)
+	}
+	defer p.Close()
+
+	if err := p.Publish(context.Background(), "engram.default", "", []byte(`{}`)); err == nil {
+		t.Error("Publish() should fail when no ack arrives")
+	}
+}
+
+func TestNewNATSPublisher_InvalidURL(t *testing.T) {
+	for _, raw := range []string{"localhost:4222", "http://localhost:4222", "nats://", "nats://a:4222,http://b:4222"} {
+		if _, err := NewNATSPublisher(raw, NATSOptions{Timeout: time.Second}); err == nil {
+			t.Errorf("NewNATSPublisher(%q) should fail", raw)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Package hlc implements hybrid logical clocks.
+//
+// A hybrid logical clock reading combines wall-clock time with a logical
+// counter. Readings from one clock are strictly increasing even when the
+// wall clock stalls or steps backwards, and a clock that observes a
+// reading from another writer moves past it, so readings order changes
+// consistently across writers and restarts.
+package hlc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const logicalBits = 16
+
+// maxLogical masks the logical counter.
+const maxLogical = 1<<logicalBits - 1
+
+// DefaultMaxOffset is how far ahead of the local wall clock an observed
+// reading may be before it is ignored.
+const DefaultMaxOffset = 5 * time.Minute
+
+// ErrInvalidTimestamp is returned when parsing a malformed timestamp.
+var ErrInvalidTimestamp = errors.New("invalid hlc timestamp")
+
+// Timestamp is a hybrid logical clock reading: Unix milliseconds in the
+// high bits and a logical counter in the low 16 bits, so timestamps
+// compare as integers. The zero Timestamp is earlier than every reading.
+type Timestamp int64
+
+// New returns the timestamp for wall-clock time wall and logical counter
+// logical.
+func New(wall time.Time, logical uint16) Timestamp {
+	return Timestamp(wall.UnixMilli()<<logicalBits | int64(logical))
+}
+
+// Wall returns the wall-clock component of t.
+func (t Timestamp) Wall() time.Time {
+	return time.UnixMilli(int64(t) >> logicalBits).UTC()
+}
+
+// Logical returns the logical counter of t.
+func (t Timestamp) Logical() uint16 {
+	return uint16(t & maxLogical)
+}
+
+// IsZero reports whether t is the zero Timestamp.
+func (t Timestamp) IsZero() bool {
+	return t == 0
+}
+
+// String formats t as zero-padded Unix milliseconds and a hex logical
+// counter, e.g. "001772366400000-0003". The strings sort like the
+// timestamps they encode.
+func (t Timestamp) String() string {
+	return fmt.Sprintf("%015d-%04x", int64(t)>>logicalBits, t.Logical())
+}
+
+// Parse parses a timestamp in the format produced by String.
+func Parse(s string) (Timestamp, error) {
+	wallPart, logicalPart, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidTimestamp, s)
+	}
+	wall, err := strconv.ParseInt(wallPart, 10, 64)
+	if err != nil || wall < 0 || wall >= 1<<(63-logicalBits) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidTimestamp, s)
+	}
+	logical, err := strconv.ParseUint(logicalPart, 16, logicalBits)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidTimestamp, s)
+	}
+	return Timestamp(wall<<logicalBits | int64(logical)), nil
+}
+
+// MarshalText encodes t as its String form. JSON numbers would lose
+// precision in clients that decode them as doubles.
+func (t Timestamp) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes a timestamp in its String form.
+func (t *Timestamp) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// Clock issues hybrid logical clock readings. It is safe for concurrent use.
+type Clock struct {
+	mu        sync.Mutex
+	last      Timestamp
+	now       func() time.Time
+	maxOffset time.Duration
+}
+
+// NewClock returns a clock reading the system wall clock. Observed
+// readings more than maxOffset ahead of it are ignored; non-positive
+// values use DefaultMaxOffset.
+func NewClock(maxOffset time.Duration) *Clock {
+	if maxOffset <= 0 {
+		maxOffset = DefaultMaxOffset
+	}
+	return &Clock{now: time.Now, maxOffset: maxOffset}
+}
+
+// Now returns a reading later than every reading issued or observed so far.
+func (c *Clock) Now() Timestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tick(0)
+}
+
+// Update observes remote, a reading from another writer, and returns a
+// reading later than both it and every local reading. A remote reading
+// beyond the clock's max offset is not observed, so one writer with a
+// fast wall clock cannot drag every other clock into the future.
+func (c *Clock) Update(remote Timestamp) Timestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if remote.Wall().After(c.now().Add(c.maxOffset)) {
+		remote = 0
+	}
+	return c.tick(remote)
+}
+
+// Observe advances the clock past ts without issuing a reading. Stores
+// call it at startup with the latest persisted reading, so readings keep
+// increasing across restarts even if the wall clock moved backwards.
+func (c *Clock) Observe(ts Timestamp) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ts > c.last {
+		c.last = ts
+	}
+}
+
+// tick issues the next reading after max(last, remote). Callers hold mu.
+func (c *Clock) tick(remote Timestamp) Timestamp {
+	floor := c.last
+	if remote > floor {
+		floor = remote
+	}
+	next := New(c.now(), 0)
+	if next <= floor {
+		// A full logical counter carries into the wall-clock bits,
+		// borrowing the next millisecond.
+		next = floor + 1
+	}
+	c.last = next
+	return next
+}
@@ -0,0 +1,102 @@
+package hlc
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func fixedClock(wall *time.Time) *Clock {
+	c := NewClock(time.Minute)
+	c.now = func() time.Time { return *wall }
+	return c
+}
+
+func TestClock_Now_Monotonic(t *testing.T) {
+	wall := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := fixedClock(&wall)
+
+	first := c.Now()
+	second := c.Now()
+	if second <= first || second.Logical() != 1 || !second.Wall().Equal(wall) {
+		t.Errorf("second reading = %s after %s, want the same wall time with logical 1", second, first)
+	}
+
+	// A wall clock stepping backwards does not move readings backwards
+	wall = wall.Add(-time.Second)
+	if third := c.Now(); third <= second {
+		t.Errorf("reading after clock step back = %s, want after %s", third, second)
+	}
+
+	wall = wall.Add(time.Hour)
+	if fourth := c.Now(); fourth.Logical() != 0 || !fourth.Wall().Equal(wall) {
+		t.Errorf("reading after clock moved on = %s, want wall time with logical 0", fourth)
+	}
+}
+
+func TestClock_Now_LogicalOverflow(t *testing.T) {
+	wall := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := fixedClock(&wall)
+	c.Observe(New(wall, maxLogical))
+
+	next := c.Now()
+	if !next.Wall().Equal(wall.Add(time.Millisecond)) || next.Logical() != 0 {
+		t.Errorf("reading after full counter = %s, want the next millisecond", next)
+	}
+}
+
+func TestClock_Update(t *testing.T) {
+	wall := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := fixedClock(&wall)
+
+	remote := New(wall.Add(10*time.Second), 7)
+	if got := c.Update(remote); got != remote+1 {
+		t.Errorf("Update(%s) = %s, want %s", remote, got, remote+1)
+	}
+	if got := c.Now(); got <= remote {
+		t.Errorf("Now() after Update = %s, want after %s", got, remote)
+	}
+
+	// Readings beyond the max offset are not observed
+	far := New(wall.Add(time.Hour), 0)
+	if got := c.Update(far); got >= far {
+		t.Errorf("Update(%s) = %s, want the remote reading ignored", far, got)
+	}
+}
+
+func TestClock_Observe(t *testing.T) {
+	wall := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := fixedClock(&wall)
+	persisted := New(wall.Add(time.Minute), 3)
+	c.Observe(persisted)
+	if got := c.Now(); got != persisted+1 {
+		t.Errorf("Now() after Observe = %s, want %s", got, persisted+1)
+	}
+}
+
+func TestTimestamp_TextRoundTrip(t *testing.T) {
+	ts := New(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), 3)
+	if got := ts.String(); got != "001772366400000-0003" {
+		t.Errorf("String() = %q", got)
+	}
+
+	b, err := json.Marshal(struct {
+		HLC Timestamp `json:"hlc"`
+	}{ts})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded struct {
+		HLC Timestamp `json:"hlc"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil || decoded.HLC != ts {
+		t.Errorf("round trip of %s = %s, %v", b, decoded.HLC, err)
+	}
+
+	for _, s := range []string{"", "12", "abc-0001", "1-zz", "-1-0001"} {
+		if _, err := Parse(s); !errors.Is(err, ErrInvalidTimestamp) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidTimestamp", s, err)
+		}
+	}
+}
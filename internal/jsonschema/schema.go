@@ -0,0 +1,187 @@
+// Package jsonschema validates JSON documents against the subset of JSON
+// Schema used by domain plugins to describe sync payloads.
+//
+// Supported keywords are type, properties, required,
+// additionalProperties, enum, minLength, maxLength, pattern, minimum,
+// maximum, items, minItems, maxItems and format (date-time). Annotations
+// such as title and description are ignored; composition keywords and
+// references are rejected by Compile so a schema never silently
+// validates less than it declares.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// ErrInvalidSchema indicates a schema document could not be compiled.
+var ErrInvalidSchema = errors.New("invalid JSON schema")
+
+// unsupported lists keywords whose semantics Compile cannot honour.
+var unsupported = []string{
+	"$ref", "allOf", "anyOf", "oneOf", "not", "if", "then", "else",
+	"patternProperties", "dependencies", "dependentRequired",
+	"dependentSchemas", "propertyNames", "contains", "uniqueItems",
+	"exclusiveMinimum", "exclusiveMaximum", "multipleOf", "const",
+}
+
+// Types lists the accepted values of the type keyword.
+var Types = []string{"string", "number", "integer", "boolean", "object", "array", "null"}
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	types                []string
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	noAdditional         bool
+	enum                 []interface{}
+	minLength, maxLength *int
+	pattern              *regexp.Regexp
+	minimum, maximum     *float64
+	items                *Schema
+	minItems, maxItems   *int
+	format               string
+}
+
+// rawSchema is the decoded form of a schema document.
+type rawSchema struct {
+	Type                 json.RawMessage            `json:"type"`
+	Properties           map[string]json.RawMessage `json:"properties"`
+	Required             []string                   `json:"required"`
+	AdditionalProperties json.RawMessage            `json:"additionalProperties"`
+	Enum                 []interface{}              `json:"enum"`
+	MinLength            *int                       `json:"minLength"`
+	MaxLength            *int                       `json:"maxLength"`
+	Pattern              *string                    `json:"pattern"`
+	Minimum              *float64                   `json:"minimum"`
+	Maximum              *float64                   `json:"maximum"`
+	Items                json.RawMessage            `json:"items"`
+	MinItems             *int                       `json:"minItems"`
+	MaxItems             *int                       `json:"maxItems"`
+	Format               string                     `json:"format"`
+}
+
+// Compile parses a schema document.
+func Compile(doc []byte) (*Schema, error) {
+	s, err := compile(doc, "")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	return s, nil
+}
+
+// MustCompile is like Compile but panics if the schema is invalid.
+func MustCompile(doc []byte) *Schema {
+	s, err := Compile(doc)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func compile(doc []byte, path string) (*Schema, error) {
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &keywords); err != nil {
+		return nil, fmt.Errorf("%s: schema must be a JSON object", pathOrRoot(path))
+	}
+	for _, k := range unsupported {
+		if _, ok := keywords[k]; ok {
+			return nil, fmt.Errorf("%s: unsupported keyword %q", pathOrRoot(path), k)
+		}
+	}
+
+	var raw rawSchema
+	if err := json.Unmarshal(doc, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %v", pathOrRoot(path), err)
+	}
+
+	s := &Schema{
+		required:  raw.Required,
+		enum:      raw.Enum,
+		minLength: raw.MinLength,
+		maxLength: raw.MaxLength,
+		minimum:   raw.Minimum,
+		maximum:   raw.Maximum,
+		minItems:  raw.MinItems,
+		maxItems:  raw.MaxItems,
+		format:    raw.Format,
+	}
+
+	if len(raw.Type) > 0 {
+		types, err := decodeTypes(raw.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", pathOrRoot(path), err)
+		}
+		s.types = types
+	}
+
+	if raw.Pattern != nil {
+		re, err := regexp.Compile(*raw.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern: %v", pathOrRoot(path), err)
+		}
+		s.pattern = re
+	}
+
+	if len(raw.Properties) > 0 {
+		s.properties = make(map[string]*Schema, len(raw.Properties))
+		for name, doc := range raw.Properties {
+			prop, err := compile(doc, joinPath(path, name))
+			if err != nil {
+				return nil, err
+			}
+			s.properties[name] = prop
+		}
+	}
+
+	switch trimmed := bytes.TrimSpace(raw.AdditionalProperties); {
+	case len(trimmed) == 0, bytes.Equal(trimmed, []byte("true")):
+	case bytes.Equal(trimmed, []byte("false")):
+		s.noAdditional = true
+	default:
+		additional, err := compile(trimmed, joinPath(path, "*"))
+		if err != nil {
+			return nil, err
+		}
+		s.additionalProperties = additional
+	}
+
+	if len(raw.Items) > 0 {
+		items, err := compile(raw.Items, path+"[]")
+		if err != nil {
+			return nil, err
+		}
+		s.items = items
+	}
+
+	return s, nil
+}
+
+// decodeTypes accepts a type name or a list of type names.
+func decodeTypes(raw json.RawMessage) ([]string, error) {
+	var types []string
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		types = []string{single}
+	} else if err := json.Unmarshal(raw, &types); err != nil {
+		return nil, fmt.Errorf("type must be a string or an array of strings")
+	}
+	for _, t := range types {
+		if !slices.Contains(Types, t) {
+			return nil, fmt.Errorf("unknown type %q", t)
+		}
+	}
+	return types, nil
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
@@ -0,0 +1,84 @@
+package jsonschema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const noteSchema = `{
+	"type": "object",
+	"required": ["id", "title"],
+	"additionalProperties": false,
+	"properties": {
+		"id": {"type": "string", "minLength": 1},
+		"title": {"type": "string", "maxLength": 10},
+		"status": {"enum": ["open", "done"]},
+		"priority": {"type": "integer", "minimum": 1, "maximum": 5},
+		"due": {"type": ["string", "null"], "format": "date-time"},
+		"code": {"type": "string", "pattern": "^[A-Z]{3}$"},
+		"tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+		"meta": {"type": "object", "additionalProperties": {"type": "number"}}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	s, err := Compile([]byte(noteSchema))
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		doc  string
+		want []Error
+	}{
+		{"valid", `{"id":"n1","title":"hi","status":"open","priority":3,"due":null,"code":"ABC","tags":["a"],"meta":{"x":1}}`, nil},
+		{"root type", `[]`, []Error{{"", "type", "must be of type object"}}},
+		{"missing required", `{"id":"n1"}`, []Error{{"title", "required", "missing required field"}}},
+		{"empty string", `{"id":"","title":"hi"}`, []Error{{"id", "minLength", "must not be empty"}}},
+		{"too long", `{"id":"n1","title":"a very long title"}`, []Error{{"title", "maxLength", "must be at most 10 characters long"}}},
+		{"enum", `{"id":"n1","title":"hi","status":"closed"}`, []Error{{"status", "enum", `invalid value "closed"`}}},
+		{"range", `{"id":"n1","title":"hi","priority":9}`, []Error{{"priority", "maximum", "must be between 1 and 5"}}},
+		{"integer", `{"id":"n1","title":"hi","priority":2.5}`, []Error{{"priority", "type", "must be of type integer"}}},
+		{"date-time", `{"id":"n1","title":"hi","due":"tomorrow"}`, []Error{{"due", "format", "must be an RFC 3339 date-time"}}},
+		{"pattern", `{"id":"n1","title":"hi","code":"abc"}`, []Error{{"code", "pattern", "must match pattern ^[A-Z]{3}$"}}},
+		{"array items", `{"id":"n1","title":"hi","tags":["a",2]}`, []Error{{"tags[1]", "type", "must be of type string"}}},
+		{"max items", `{"id":"n1","title":"hi","tags":["a","b","c"]}`, []Error{{"tags", "maxItems", "must have at most 2 items"}}},
+		{"additional schema", `{"id":"n1","title":"hi","meta":{"x":"one"}}`, []Error{{"meta.x", "type", "must be of type number"}}},
+		{"unknown field", `{"id":"n1","title":"hi","colour":"red"}`, []Error{{"colour", "additionalProperties", "unknown field"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Validate([]byte(tt.doc))
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_MalformedDocument(t *testing.T) {
+	s := MustCompile([]byte(`{"type":"object"}`))
+	if _, err := s.Validate([]byte(`{`)); err == nil {
+		t.Error("Validate() on malformed JSON returned no error")
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	for _, doc := range []string{
+		`[]`,
+		`{"type":"text"}`,
+		`{"pattern":"("}`,
+		`{"properties":{"a":{"oneOf":[]}}}`,
+		`{"$ref":"#/definitions/x"}`,
+	} {
+		if _, err := Compile([]byte(doc)); !errors.Is(err, ErrInvalidSchema) {
+			t.Errorf("Compile(%s) error = %v, want ErrInvalidSchema", doc, err)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Error is a single validation failure.
+type Error struct {
+	// Path locates the failing value: dotted property names with array
+	// indexes in brackets, e.g. "sources[2]" or "meta.owner". It is empty
+	// for the document itself.
+	Path string `json:"path"`
+	// Keyword is the schema keyword that failed, e.g. "required".
+	Keyword string `json:"keyword"`
+	// Message describes the failure without repeating the path.
+	Message string `json:"message"`
+}
+
+// Error implements the error interface.
+func (e Error) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Validate checks a JSON document against the schema. It returns an error
+// only if doc is not valid JSON; validation failures are returned as
+// Errors, ordered by path.
+func (s *Schema) Validate(doc []byte) ([]Error, error) {
+	var v interface{}
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return nil, err
+	}
+	return s.ValidateValue(v), nil
+}
+
+// ValidateValue checks a value decoded by encoding/json against the schema.
+func (s *Schema) ValidateValue(v interface{}) []Error {
+	var errs []Error
+	s.validate(v, "", &errs)
+	return errs
+}
+
+func (s *Schema) validate(v interface{}, path string, errs *[]Error) {
+	fail := func(keyword, msg string) {
+		*errs = append(*errs, Error{Path: path, Keyword: keyword, Message: msg})
+	}
+
+	if len(s.types) > 0 && !s.matchesType(v) {
+		fail("type", "must be of type "+strings.Join(s.types, " or "))
+		return
+	}
+
+	if len(s.enum) > 0 && !s.inEnum(v) {
+		fail("enum", "invalid value "+encode(v))
+	}
+
+	switch val := v.(type) {
+	case string:
+		s.validateString(val, fail)
+	case float64:
+		s.validateNumber(val, fail)
+	case []interface{}:
+		if s.minItems != nil && len(val) < *s.minItems {
+			fail("minItems", fmt.Sprintf("must have at least %d items", *s.minItems))
+		}
+		if s.maxItems != nil && len(val) > *s.maxItems {
+			fail("maxItems", fmt.Sprintf("must have at most %d items", *s.maxItems))
+		}
+		if s.items != nil {
+			for i, item := range val {
+				s.items.validate(item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case map[string]interface{}:
+		s.validateObject(val, path, errs)
+	}
+}
+
+func (s *Schema) validateString(val string, fail func(keyword, msg string)) {
+	n := utf8.RuneCountInString(val)
+	if s.minLength != nil && n < *s.minLength {
+		if *s.minLength == 1 {
+			fail("minLength", "must not be empty")
+		} else {
+			fail("minLength", fmt.Sprintf("must be at least %d characters long", *s.minLength))
+		}
+	}
+	if s.maxLength != nil && n > *s.maxLength {
+		fail("maxLength", fmt.Sprintf("must be at most %d characters long", *s.maxLength))
+	}
+	if s.pattern != nil && !s.pattern.MatchString(val) {
+		fail("pattern", "must match pattern "+s.pattern.String())
+	}
+	if s.format == "date-time" {
+		if _, err := time.Parse(time.RFC3339Nano, val); err != nil {
+			fail("format", "must be an RFC 3339 date-time")
+		}
+	}
+}
+
+func (s *Schema) validateNumber(val float64, fail func(keyword, msg string)) {
+	below := s.minimum != nil && val < *s.minimum
+	above := s.maximum != nil && val > *s.maximum
+	switch {
+	case (below || above) && s.minimum != nil && s.maximum != nil:
+		keyword := "minimum"
+		if above {
+			keyword = "maximum"
+		}
+		fail(keyword, fmt.Sprintf("must be between %s and %s", formatNumber(*s.minimum), formatNumber(*s.maximum)))
+	case below:
+		fail("minimum", "must be at least "+formatNumber(*s.minimum))
+	case above:
+		fail("maximum", "must be at most "+formatNumber(*s.maximum))
+	}
+}
+
+func (s *Schema) validateObject(val map[string]interface{}, path string, errs *[]Error) {
+	for _, name := range s.required {
+		if _, ok := val[name]; !ok {
+			*errs = append(*errs, Error{Path: joinPath(path, name), Keyword: "required", Message: "missing required field"})
+		}
+	}
+
+	names := make([]string, 0, len(val))
+	for name := range val {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		child := joinPath(path, name)
+		if prop, ok := s.properties[name]; ok {
+			prop.validate(val[name], child, errs)
+			continue
+		}
+		switch {
+		case s.noAdditional:
+			*errs = append(*errs, Error{Path: child, Keyword: "additionalProperties", Message: "unknown field"})
+		case s.additionalProperties != nil:
+			s.additionalProperties.validate(val[name], child, errs)
+		}
+	}
+}
+
+func (s *Schema) matchesType(v interface{}) bool {
+	for _, t := range s.types {
+		switch t {
+		case "null":
+			if v == nil {
+				return true
+			}
+		case "string":
+			if _, ok := v.(string); ok {
+				return true
+			}
+		case "number":
+			if _, ok := v.(float64); ok {
+				return true
+			}
+		case "integer":
+			if f, ok := v.(float64); ok && f == math.Trunc(f) {
+				return true
+			}
+		case "boolean":
+			if _, ok := v.(bool); ok {
+				return true
+			}
+		case "object":
+			if _, ok := v.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := v.([]interface{}); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Schema) inEnum(v interface{}) bool {
+	for _, e := range s.enum {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinPath appends a property name to a path.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func encode(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
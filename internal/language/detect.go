@@ -0,0 +1,117 @@
+// Package language guesses the natural language of lore content so it can
+// be filtered and counted per language.
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Undetermined is the ISO 639-2 code reported when no language stands out,
+// such as for text that is mostly code, identifiers or numbers.
+const Undetermined = "und"
+
+// scripts maps writing systems to the language their text is attributed
+// to. Han without kana is taken as Chinese.
+var scripts = []struct {
+	table *unicode.RangeTable
+	code  string
+}{
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Hangul, "ko"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Greek, "el"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Thai, "th"},
+}
+
+// stopwords are frequent function words of the Latin-script languages
+// Detect can tell apart. Words shared by several languages count for each.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "in", "that", "it", "for", "with", "on", "be", "are", "this", "not", "by", "or", "when", "from", "should", "we", "if", "an", "was", "can", "must", "before", "after", "always", "never", "use", "don't", "doesn't", "instead"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "mit", "den", "dem", "ein", "eine", "zu", "auf", "für", "von", "sich", "auch", "wenn", "wird", "werden", "sind", "bei", "oder", "nach", "immer", "kein", "keine", "muss", "vor", "statt"},
+	"fr": {"le", "la", "les", "et", "est", "des", "une", "un", "du", "de", "pour", "dans", "pas", "que", "qui", "sur", "avec", "ne", "au", "aux", "il", "ce", "sont", "toujours", "jamais", "doit", "lors", "avant", "après", "plutôt"},
+	"es": {"el", "la", "los", "las", "y", "es", "de", "que", "en", "un", "una", "por", "con", "no", "para", "se", "del", "al", "lo", "siempre", "nunca", "debe", "cuando", "antes", "después", "está", "son", "usar"},
+	"pt": {"o", "os", "as", "e", "é", "de", "que", "em", "um", "uma", "para", "com", "não", "do", "da", "dos", "das", "no", "na", "se", "sempre", "nunca", "deve", "quando", "antes", "depois", "são", "está", "usar"},
+	"it": {"il", "lo", "gli", "le", "e", "è", "di", "che", "un", "una", "per", "con", "non", "del", "della", "nel", "nella", "sono", "sempre", "mai", "deve", "quando", "prima", "dopo", "anche", "questo", "usare"},
+	"nl": {"de", "het", "een", "en", "is", "van", "niet", "met", "op", "voor", "dat", "die", "zijn", "te", "wordt", "altijd", "nooit", "moet", "als", "bij", "ook", "naar", "gebruik"},
+}
+
+// stopwordLanguages inverts stopwords.
+var stopwordLanguages = func() map[string][]string {
+	m := make(map[string][]string)
+	for code, words := range stopwords {
+		for _, w := range words {
+			m[w] = append(m[w], code)
+		}
+	}
+	return m
+}()
+
+// Detect returns the ISO 639-1 code of the language text is most likely
+// written in, or Undetermined. Non-Latin scripts are recognized by the
+// script most of the letters are in; Latin text by counting stopwords,
+// which needs at least one more hit for the winner than for the runner-up.
+func Detect(text string) string {
+	var latin int
+	scriptCounts := make(map[string]int)
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if unicode.Is(unicode.Latin, r) {
+			latin++
+			continue
+		}
+		for _, s := range scripts {
+			if unicode.Is(s.table, r) {
+				scriptCounts[s.code]++
+				break
+			}
+		}
+	}
+	// Any kana marks Japanese even when most characters are Han.
+	if scriptCounts["ja"] > 0 {
+		scriptCounts["ja"] += scriptCounts["zh"]
+		delete(scriptCounts, "zh")
+	}
+	best, bestCount := "", 0
+	for code, n := range scriptCounts {
+		if n > bestCount || n == bestCount && code < best {
+			best, bestCount = code, n
+		}
+	}
+	if bestCount > latin {
+		return best
+	}
+	return detectLatin(text)
+}
+
+// detectLatin picks among the stopword languages.
+func detectLatin(text string) string {
+	scores := make(map[string]int)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	for _, w := range words {
+		for _, code := range stopwordLanguages[strings.Trim(w, "'")] {
+			scores[code]++
+		}
+	}
+	best, bestN, secondN := "", 0, 0
+	for code, n := range scores {
+		if n > bestN {
+			best, bestN, secondN = code, n, bestN
+		} else if n > secondN {
+			secondN = n
+		}
+	}
+	if bestN == secondN {
+		return Undetermined
+	}
+	return best
+}
@@ -0,0 +1,35 @@
+package language
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Always close the rows before returning the connection to the pool.", "en"},
+		{"Die Verbindung muss immer geschlossen werden, bevor sie in den Pool zurückgeht.", "de"},
+		{"Il faut toujours fermer la connexion avant de la rendre au pool.", "fr"},
+		{"Siempre cierra las filas antes de devolver la conexión al pool.", "es"},
+		{"Sempre feche as linhas antes de devolver a conexão ao pool.", "pt"},
+		{"Chiudere sempre le righe prima di restituire la connessione al pool.", "it"},
+		{"Sluit altijd de rijen voordat je de verbinding naar de pool teruggeeft.", "nl"},
+		{"接続をプールに戻す前に必ず行を閉じてください。", "ja"},
+		{"在将连接返回连接池之前始终关闭行。", "zh"},
+		{"연결을 풀에 반환하기 전에 항상 행을 닫으세요.", "ko"},
+		{"Всегда закрывайте строки перед возвратом соединения в пул.", "ru"},
+		{"pgxpool.Config.MaxConns = 32", Undetermined},
+		{"", Undetermined},
+	}
+	for _, tt := range tests {
+		if got := Detect(tt.text); got != tt.want {
+			t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDetect_MostlyLatinWithForeignTerm(t *testing.T) {
+	if got := Detect("Use the 设置 panel to change the retry budget for the worker."); got != "en" {
+		t.Errorf("Detect() = %q, want en", got)
+	}
+}
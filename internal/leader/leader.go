@@ -0,0 +1,240 @@
+// Package leader elects one of several replicas sharing storage to run the
+// background jobs, so decay, snapshots, compaction and the like don't run
+// twice. Replicas compete for a lease kept in a SQLite database on the
+// shared storage; the holder renews it while it runs and any replica may
+// take it over once it expires.
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// leaseName names the lease for background jobs in the lease table.
+const leaseName = "background-jobs"
+
+// Elector holds or waits for the background jobs lease. Run keeps it
+// contested; Lead runs a job only while this replica holds it.
+type Elector struct {
+	db       *sql.DB
+	id       string
+	duration time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	term    context.Context // cancelled when leadership is lost; nil while following
+	end     context.CancelFunc
+	changed chan struct{} // closed and replaced when leadership changes
+	renewed time.Time     // last successful acquire or renewal
+}
+
+// NewElector opens, creating if needed, the lease database at path. The
+// database uses SQLite's rollback journal rather than WAL, which shared
+// network filesystems don't support. id names this replica and must differ
+// between replicas.
+func NewElector(path, id string, duration time.Duration) (*Elector, error) {
+	if id == "" {
+		return nil, fmt.Errorf("leader election requires a replica ID")
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("leader lease duration must be positive")
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open lease database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		`PRAGMA busy_timeout=5000`,
+		`CREATE TABLE IF NOT EXISTS leases (
+			name TEXT PRIMARY KEY,
+			holder TEXT NOT NULL,
+			expires_at INTEGER NOT NULL
+		)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("prepare lease database: %w", err)
+		}
+	}
+	return &Elector{
+		db:       db,
+		id:       id,
+		duration: duration,
+		now:      time.Now,
+		changed:  make(chan struct{}),
+	}, nil
+}
+
+// Close closes the lease database. Call it after Run has returned.
+func (e *Elector) Close() error {
+	return e.db.Close()
+}
+
+// IsLeader reports whether this replica holds the lease.
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.term != nil
+}
+
+// Run contests the lease until ctx is cancelled, renewing it every third
+// of the lease duration while held, then releases it so another replica
+// takes over without waiting for it to expire.
+func (e *Elector) Run(ctx context.Context) {
+	slog.Info("leader election started",
+		"component", "leader",
+		"id", e.id,
+		"lease_duration", e.duration.String(),
+	)
+
+	ticker := time.NewTicker(e.duration / 3)
+	defer ticker.Stop()
+
+	e.contest(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			e.release()
+			return
+		case <-ticker.C:
+			e.contest(ctx)
+		}
+	}
+}
+
+// Lead returns a worker function that runs fn while this replica holds the
+// lease. fn's context is cancelled when leadership is lost, and fn is run
+// again if it is regained. A nil Elector runs fn unconditionally, as a
+// single replica does.
+func (e *Elector) Lead(fn func(ctx context.Context)) func(ctx context.Context) {
+	if e == nil {
+		return fn
+	}
+	return func(ctx context.Context) {
+		for {
+			e.mu.Lock()
+			term, changed := e.term, e.changed
+			e.mu.Unlock()
+
+			if term == nil {
+				select {
+				case <-ctx.Done():
+					return
+				case <-changed:
+					continue
+				}
+			}
+
+			jobCtx, cancel := context.WithCancel(ctx)
+			stop := context.AfterFunc(term, cancel)
+			fn(jobCtx)
+			stop()
+			cancel()
+
+			// Don't restart a job that returned on its own until the next term
+			select {
+			case <-ctx.Done():
+				return
+			case <-term.Done():
+			}
+		}
+	}
+}
+
+// contest acquires or renews the lease and updates leadership to match.
+// A leader that can't reach the lease database steps down once the lease
+// it last renewed may have expired, since another replica may hold it.
+func (e *Elector) contest(ctx context.Context) {
+	now := e.now()
+	held, err := e.acquire(ctx, now)
+	if err != nil {
+		slog.Warn("failed to renew leader lease",
+			"component", "leader",
+			"id", e.id,
+			"error", err,
+		)
+		e.mu.Lock()
+		expired := e.term != nil && now.Sub(e.renewed) >= e.duration
+		e.mu.Unlock()
+		if expired {
+			e.setLeading(false, now)
+		}
+		return
+	}
+	e.setLeading(held, now)
+}
+
+// acquire takes the lease if it is free, expired or already ours, and
+// extends it. It reports whether this replica holds the lease.
+func (e *Elector) acquire(ctx context.Context, now time.Time) (bool, error) {
+	res, err := e.db.ExecContext(ctx, `
+		INSERT INTO leases (name, holder, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET holder = excluded.holder, expires_at = excluded.expires_at
+		WHERE leases.holder = excluded.holder OR leases.expires_at <= ?
+	`, leaseName, e.id, now.Add(e.duration).UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// release gives up the lease if this replica holds it.
+func (e *Elector) release() {
+	if !e.IsLeader() {
+		return
+	}
+	e.setLeading(false, e.now())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := e.db.ExecContext(ctx, `
+		DELETE FROM leases WHERE name = ? AND holder = ?
+	`, leaseName, e.id); err != nil {
+		slog.Warn("failed to release leader lease",
+			"component", "leader",
+			"id", e.id,
+			"error", err,
+		)
+	}
+}
+
+// setLeading records whether this replica holds the lease, starting or
+// ending its term and waking jobs waiting in Lead on a change.
+func (e *Elector) setLeading(held bool, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if held {
+		e.renewed = now
+	}
+	if held == (e.term != nil) {
+		return
+	}
+
+	if held {
+		e.term, e.end = context.WithCancel(context.Background())
+		slog.Info("became leader",
+			"component", "leader",
+			"id", e.id,
+		)
+	} else {
+		e.end()
+		e.term, e.end = nil, nil
+		slog.Info("lost leadership",
+			"component", "leader",
+			"id", e.id,
+		)
+	}
+	close(e.changed)
+	e.changed = make(chan struct{})
+}
@@ -0,0 +1,125 @@
+package leader
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestElector(t *testing.T, path, id string) *Elector {
+	t.Helper()
+	e, err := NewElector(path, id, 30*time.Second)
+	if err != nil {
+		t.Fatalf("NewElector(%q) error = %v", id, err)
+	}
+	t.Cleanup(func() { e.Close() })
+	return e
+}
+
+func TestElector_OneLeaderAtATime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leader.db")
+	a := newTestElector(t, path, "replica-a")
+	b := newTestElector(t, path, "replica-b")
+	ctx := context.Background()
+
+	a.contest(ctx)
+	b.contest(ctx)
+	if !a.IsLeader() || b.IsLeader() {
+		t.Fatalf("leaders = a:%v b:%v, want only a", a.IsLeader(), b.IsLeader())
+	}
+
+	// Renewal keeps the lease with its holder
+	a.contest(ctx)
+	b.contest(ctx)
+	if !a.IsLeader() || b.IsLeader() {
+		t.Fatalf("after renewal leaders = a:%v b:%v, want only a", a.IsLeader(), b.IsLeader())
+	}
+
+	// Releasing hands over without waiting for expiry
+	a.release()
+	b.contest(ctx)
+	if a.IsLeader() || !b.IsLeader() {
+		t.Errorf("after release leaders = a:%v b:%v, want only b", a.IsLeader(), b.IsLeader())
+	}
+}
+
+func TestElector_ExpiredLeaseIsTakenOver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leader.db")
+	a := newTestElector(t, path, "replica-a")
+	b := newTestElector(t, path, "replica-b")
+	ctx := context.Background()
+
+	a.contest(ctx)
+	if !a.IsLeader() {
+		t.Fatal("a should lead")
+	}
+
+	// a stops renewing; once the lease expires b takes it and a steps down
+	later := time.Now().Add(time.Minute)
+	b.now = func() time.Time { return later }
+	b.contest(ctx)
+	if !b.IsLeader() {
+		t.Fatal("b should take over the expired lease")
+	}
+	a.now = func() time.Time { return later }
+	a.contest(ctx)
+	if a.IsLeader() {
+		t.Error("a should step down once b holds the lease")
+	}
+}
+
+func TestElector_LeadRunsJobOnlyWhileLeading(t *testing.T) {
+	e := newTestElector(t, filepath.Join(t.TempDir(), "leader.db"), "replica-a")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{}, 2)
+	stopped := make(chan struct{}, 2)
+	job := e.Lead(func(ctx context.Context) {
+		started <- struct{}{}
+		<-ctx.Done()
+		stopped <- struct{}{}
+	})
+	done := make(chan struct{})
+	go func() {
+		job(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-started:
+		t.Fatal("job started before leadership")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	e.contest(ctx)
+	waitFor(t, started, "job to start on becoming leader")
+
+	e.setLeading(false, time.Now())
+	waitFor(t, stopped, "job to stop on losing leadership")
+
+	e.contest(ctx)
+	waitFor(t, started, "job to restart on regaining leadership")
+
+	cancel()
+	waitFor(t, done, "job to return on shutdown")
+}
+
+func TestElector_NilLeadRunsJob(t *testing.T) {
+	var e *Elector
+	ran := false
+	e.Lead(func(context.Context) { ran = true })(context.Background())
+	if !ran {
+		t.Error("a nil Elector should run the job")
+	}
+}
+
+func waitFor[T any](t *testing.T, ch <-chan T, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const contradictionSystemPrompt = `You check whether two pieces of engineering lore contradict each other.
+They contradict when following one means going against the other in the same situation.
+Entries about different situations, or where one only narrows or refines the other, do not contradict.
+Answer YES or NO on the first line, then give a one-sentence reason.`
+
+// JudgeContradiction asks p whether lore entries a and b contradict each
+// other, returning its verdict and the reason it gave.
+func JudgeContradiction(ctx context.Context, p Provider, a, b string) (bool, string, error) {
+	out, err := p.Complete(ctx, contradictionSystemPrompt,
+		fmt.Sprintf("Entry A: %s\n\nEntry B: %s\n", strings.TrimSpace(a), strings.TrimSpace(b)))
+	if err != nil {
+		return false, "", err
+	}
+	return parseVerdict(out)
+}
+
+// parseVerdict splits a YES/NO answer from the reason that follows it,
+// tolerating Markdown emphasis and punctuation around the verdict.
+func parseVerdict(out string) (bool, string, error) {
+	text := strings.TrimLeft(strings.TrimSpace(out), "*_# ")
+	var yes bool
+	switch upper := strings.ToUpper(text); {
+	case strings.HasPrefix(upper, "YES"):
+		yes, text = true, text[len("YES"):]
+	case strings.HasPrefix(upper, "NO"):
+		text = text[len("NO"):]
+	default:
+		line, _, _ := strings.Cut(text, "\n")
+		return false, "", fmt.Errorf("unrecognized verdict %q", line)
+	}
+	return yes, strings.TrimSpace(strings.TrimLeft(text, "*_.,:;-— \n")), nil
+}
@@ -0,0 +1,71 @@
+// Package llm generates text with a large language model. Engram uses it
+// to condense accumulated lore into readable digests.
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Provider names accepted by New.
+const (
+	ProviderOpenAI = "openai"
+	ProviderOllama = "ollama"
+)
+
+// Models used when Config.Model is empty.
+const (
+	DefaultOpenAIModel = "gpt-4o-mini"
+	DefaultOllamaModel = "llama3.1"
+)
+
+// DefaultOllamaURL is where a local Ollama server listens.
+const DefaultOllamaURL = "http://localhost:11434"
+
+// ErrEmptyCompletion is returned when the model answers with no text.
+var ErrEmptyCompletion = errors.New("llm returned an empty completion")
+
+// Provider completes a prompt with a language model.
+type Provider interface {
+	// Complete returns the model's answer to prompt, steered by the
+	// system instructions.
+	Complete(ctx context.Context, system, prompt string) (string, error)
+	ModelName() string
+}
+
+// Config selects and configures a Provider.
+type Config struct {
+	Provider string // ProviderOpenAI or ProviderOllama
+	Model    string
+	BaseURL  string // optional; overrides the provider's API endpoint
+	APIKey   string // OpenAI only
+	Timeout  time.Duration
+}
+
+// New creates the provider named by cfg.Provider. It returns nil and no
+// error when cfg.Provider is empty, leaving LLM features disabled.
+func New(cfg Config) (Provider, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case ProviderOpenAI:
+		if cfg.APIKey == "" {
+			return nil, errors.New("openai provider requires an API key")
+		}
+		model := cfg.Model
+		if model == "" {
+			model = DefaultOpenAIModel
+		}
+		return NewOpenAI(cfg.APIKey, model, cfg.BaseURL, cfg.Timeout), nil
+	case ProviderOllama:
+		model := cfg.Model
+		if model == "" {
+			model = DefaultOllamaModel
+		}
+		return NewOllama(cfg.BaseURL, model, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown llm provider %q: must be %s or %s", cfg.Provider, ProviderOpenAI, ProviderOllama)
+	}
+}
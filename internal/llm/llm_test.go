@@ -0,0 +1,172 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+// mockCompletionsService implements ChatCompletionsService for testing
+type mockCompletionsService struct {
+	response   *openai.ChatCompletion
+	err        error
+	lastParams openai.ChatCompletionNewParams
+}
+
+func (m *mockCompletionsService) New(ctx context.Context, params openai.ChatCompletionNewParams, opts ...option.RequestOption) (*openai.ChatCompletion, error) {
+	m.lastParams = params
+	return m.response, m.err
+}
+
+func completion(text string) *openai.ChatCompletion {
+	return &openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{
+		{Message: openai.ChatCompletionMessage{Content: text}},
+	}}
+}
+
+func TestOpenAI_Complete(t *testing.T) {
+	svc := &mockCompletionsService{response: completion("  ## Retries\nUse backoff.\n")}
+	o := &OpenAI{completions: svc, model: "gpt-test"}
+
+	got, err := o.Complete(context.Background(), "be brief", "summarize")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "## Retries\nUse backoff." {
+		t.Errorf("Complete() = %q", got)
+	}
+	if svc.lastParams.Model.Value != "gpt-test" || len(svc.lastParams.Messages.Value) != 2 {
+		t.Errorf("unexpected params: model %q, %d messages", svc.lastParams.Model.Value, len(svc.lastParams.Messages.Value))
+	}
+}
+
+func TestOpenAI_CompleteErrors(t *testing.T) {
+	apiErr := errors.New("rate limited")
+	tests := []struct {
+		name string
+		svc  *mockCompletionsService
+		want error
+	}{
+		{"api error", &mockCompletionsService{err: apiErr}, apiErr},
+		{"no choices", &mockCompletionsService{response: &openai.ChatCompletion{}}, ErrEmptyCompletion},
+		{"blank content", &mockCompletionsService{response: completion(" \n")}, ErrEmptyCompletion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &OpenAI{completions: tt.svc, model: "gpt-test"}
+			if _, err := o.Complete(context.Background(), "s", "p"); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOllama_Complete(t *testing.T) {
+	var got ollamaChatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("path = %s, want /api/chat", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(`{"message":{"role":"assistant","content":"Use backoff."},"done":true}`))
+	}))
+	defer srv.Close()
+
+	o := NewOllama(srv.URL+"/", "llama-test", 0)
+	text, err := o.Complete(context.Background(), "be brief", "summarize")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Use backoff." {
+		t.Errorf("Complete() = %q", text)
+	}
+	if got.Model != "llama-test" || got.Stream || len(got.Messages) != 2 || got.Messages[0].Role != "system" {
+		t.Errorf("unexpected request: %+v", got)
+	}
+}
+
+func TestOllama_CompleteReportsServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"model \"missing\" not found"}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewOllama(srv.URL, "missing", 0).Complete(context.Background(), "s", "p")
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("error = %v, want model not found", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if p, err := New(Config{}); p != nil || err != nil {
+		t.Errorf("New(empty) = %v, %v; want nil, nil", p, err)
+	}
+	if _, err := New(Config{Provider: ProviderOpenAI}); err == nil {
+		t.Error("openai without an API key should fail")
+	}
+	if _, err := New(Config{Provider: "claude"}); err == nil {
+		t.Error("unknown provider should fail")
+	}
+	p, err := New(Config{Provider: ProviderOllama})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ModelName() != DefaultOllamaModel {
+		t.Errorf("model = %q, want %q", p.ModelName(), DefaultOllamaModel)
+	}
+}
+
+func TestSummaryPrompt(t *testing.T) {
+	prompt := summaryPrompt([]types.LoreEntry{
+		{Category: "PATTERN_OUTCOME", Confidence: 0.9, Content: "Retry with backoff.", Context: "payments client"},
+		{Category: "DEPENDENCY_BEHAVIOR", Confidence: 0.5, Content: "The SDK caches tokens."},
+	}, "onboarding to payments")
+
+	for _, want := range []string{
+		"Focus the digest on: onboarding to payments",
+		"these 2 lore entries",
+		"1. [PATTERN_OUTCOME, confidence 0.90] Retry with backoff.",
+		"   Context: payments client",
+		"2. [DEPENDENCY_BEHAVIOR, confidence 0.50] The SDK caches tokens.",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestParseVerdict(t *testing.T) {
+	tests := []struct {
+		out        string
+		wantYes    bool
+		wantReason string
+	}{
+		{"YES\nOne says always retry, the other never.", true, "One says always retry, the other never."},
+		{"**No.** They cover different services.", false, "They cover different services."},
+		{"yes - both describe the same timeout", true, "both describe the same timeout"},
+	}
+	for _, tt := range tests {
+		yes, reason, err := parseVerdict(tt.out)
+		if err != nil {
+			t.Fatalf("parseVerdict(%q) error = %v", tt.out, err)
+		}
+		if yes != tt.wantYes || reason != tt.wantReason {
+			t.Errorf("parseVerdict(%q) = %v, %q; want %v, %q", tt.out, yes, reason, tt.wantYes, tt.wantReason)
+		}
+	}
+
+	if _, _, err := parseVerdict("Maybe, it depends."); err == nil {
+		t.Error("expected error for an answer without a verdict")
+	}
+}
@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Compile-time interface check
+var _ Provider = (*Ollama)(nil)
+
+// Ollama completes prompts with a model served by Ollama's chat API.
+type Ollama struct {
+	client  *http.Client
+	baseURL string
+	model   string
+}
+
+// NewOllama creates an Ollama provider. An empty baseURL uses
+// DefaultOllamaURL; a non-positive timeout leaves requests bounded only by
+// their context.
+func NewOllama(baseURL, model string, timeout time.Duration) *Ollama {
+	if baseURL == "" {
+		baseURL = DefaultOllamaURL
+	}
+	return &Ollama{
+		client:  &http.Client{Timeout: timeout},
+		baseURL: strings.TrimRight(baseURL, "/"),
+		model:   model,
+	}
+}
+
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+}
+
+type ollamaChatResponse struct {
+	Message ollamaMessage `json:"message"`
+	Error   string        `json:"error"`
+}
+
+// Complete posts a non-streaming chat request to /api/chat.
+func (o *Ollama) Complete(ctx context.Context, system, prompt string) (string, error) {
+	body, err := json.Marshal(ollamaChatRequest{
+		Model: o.model,
+		Messages: []ollamaMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: prompt},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("ollama chat failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out ollamaChatResponse
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		if json.Unmarshal(respBody, &out) == nil && out.Error != "" {
+			return "", fmt.Errorf("ollama chat failed: status %d: %s", resp.StatusCode, out.Error)
+		}
+		return "", fmt.Errorf("ollama chat failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("ollama chat failed: decode response: %w", err)
+	}
+
+	text := strings.TrimSpace(out.Message.Content)
+	if text == "" {
+		return "", ErrEmptyCompletion
+	}
+	return text, nil
+}
+
+// ModelName returns the Ollama model in use.
+func (o *Ollama) ModelName() string {
+	return o.model
+}
@@ -0,0 +1,72 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+// Compile-time interface check
+var _ Provider = (*OpenAI)(nil)
+
+// ChatCompletionsService defines the interface for making chat completion
+// API calls, so tests need not call the real OpenAI API.
+type ChatCompletionsService interface {
+	New(ctx context.Context, params openai.ChatCompletionNewParams, opts ...option.RequestOption) (*openai.ChatCompletion, error)
+}
+
+// OpenAI completes prompts with OpenAI's chat completions API, or any
+// API compatible with it.
+type OpenAI struct {
+	completions ChatCompletionsService
+	model       string
+}
+
+// NewOpenAI creates an OpenAI provider. An empty baseURL uses OpenAI's
+// API; a non-positive timeout leaves requests bounded only by their context.
+func NewOpenAI(apiKey, model, baseURL string, timeout time.Duration) *OpenAI {
+	opts := []option.RequestOption{option.WithAPIKey(apiKey)}
+	if baseURL != "" {
+		opts = append(opts, option.WithBaseURL(baseURL))
+	}
+	if timeout > 0 {
+		opts = append(opts, option.WithRequestTimeout(timeout))
+	}
+	client := openai.NewClient(opts...)
+	return &OpenAI{
+		completions: client.Chat.Completions,
+		model:       model,
+	}
+}
+
+// Complete sends system and prompt as a two-message conversation and
+// returns the first choice.
+func (o *OpenAI) Complete(ctx context.Context, system, prompt string) (string, error) {
+	resp, err := o.completions.New(ctx, openai.ChatCompletionNewParams{
+		Model: openai.F(openai.ChatModel(o.model)),
+		Messages: openai.F([]openai.ChatCompletionMessageParamUnion{
+			openai.SystemMessage(system),
+			openai.UserMessage(prompt),
+		}),
+	})
+	if err != nil {
+		return "", fmt.Errorf("chat completion failed: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", ErrEmptyCompletion
+	}
+	text := strings.TrimSpace(resp.Choices[0].Message.Content)
+	if text == "" {
+		return "", ErrEmptyCompletion
+	}
+	return text, nil
+}
+
+// ModelName returns the chat model in use.
+func (o *OpenAI) ModelName() string {
+	return o.model
+}
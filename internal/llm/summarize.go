@@ -0,0 +1,37 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+const summarySystemPrompt = `You condense a team's accumulated engineering lore into a digest for newcomers.
+Group related entries under short Markdown headings, merge duplicates, and keep concrete details such as names, limits and commands.
+Where entries disagree, say so rather than picking one. Prefer higher-confidence entries.
+Use only the entries given; do not add advice of your own.`
+
+// SummarizeLore asks p for a Markdown digest of entries. A non-empty focus
+// tells the model what the digest is for, such as "onboarding to the
+// billing service".
+func SummarizeLore(ctx context.Context, p Provider, entries []types.LoreEntry, focus string) (string, error) {
+	return p.Complete(ctx, summarySystemPrompt, summaryPrompt(entries, focus))
+}
+
+// summaryPrompt lists the entries, one numbered block each.
+func summaryPrompt(entries []types.LoreEntry, focus string) string {
+	var b strings.Builder
+	if focus != "" {
+		fmt.Fprintf(&b, "Focus the digest on: %s\n\n", focus)
+	}
+	fmt.Fprintf(&b, "Summarize these %d lore entries:\n", len(entries))
+	for i, e := range entries {
+		fmt.Fprintf(&b, "\n%d. [%s, confidence %.2f] %s\n", i+1, e.Category, e.Confidence, strings.TrimSpace(e.Content))
+		if c := strings.TrimSpace(e.Context); c != "" {
+			fmt.Fprintf(&b, "   Context: %s\n", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,315 @@
+package multistore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Store lifecycle states, recorded in meta.yaml.
+const (
+	// StoreStateActive stores are opened on demand and serve requests.
+	// An empty state means active.
+	StoreStateActive = "active"
+	// StoreStateArchived stores hold only a compressed archive of their
+	// database and refuse requests until thawed.
+	StoreStateArchived = "archived"
+)
+
+// archiveFile is the compressed database of an archived store, kept in its
+// directory or, with WithArchiveRoot, in the store ID's directory under the
+// archive root.
+const archiveFile = "engram.db.zst"
+
+var (
+	// ErrStoreArchived indicates the store is archived and must be thawed
+	// before use.
+	ErrStoreArchived = errors.New("store is archived")
+	// ErrStoreNotArchived indicates a thaw of a store that is not archived.
+	ErrStoreNotArchived = errors.New("store is not archived")
+)
+
+// ArchiveInfo describes a store's archive.
+type ArchiveInfo struct {
+	// ArchivedAt is when the store was archived.
+	ArchivedAt time.Time `yaml:"archived_at" json:"archived_at"`
+	// SizeBytes is the size of the compressed archive.
+	SizeBytes int64 `yaml:"size_bytes" json:"size_bytes"`
+	// Uploaded reports whether a copy was uploaded to remote storage.
+	Uploaded bool `yaml:"uploaded,omitempty" json:"uploaded"`
+}
+
+// ArchiveStorage keeps remote copies of store archives. The S3 snapshot
+// uploader implements it.
+type ArchiveStorage interface {
+	UploadArchive(ctx context.Context, storeID, filePath string) error
+	DownloadArchive(ctx context.Context, storeID, filePath string) error
+}
+
+// SetArchiveStorage sets where archives are uploaded when stores are
+// archived, and downloaded from on thaw when the local copy is missing.
+// Without it archives are kept locally only.
+func (m *StoreManager) SetArchiveStorage(s ArchiveStorage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.archives = s
+}
+
+// IsArchived reports whether the metadata marks the store archived.
+func (meta *StoreMeta) IsArchived() bool {
+	return meta.State == StoreStateArchived
+}
+
+// ArchiveStore closes the store, compresses its database into an archive,
+// uploads a copy if archive storage is set, and removes the database and
+// its snapshots. Until ThawStore is called, GetStore returns
+// ErrStoreArchived for it. The default store cannot be archived.
+func (m *StoreManager) ArchiveStore(ctx context.Context, storeID string) (*ArchiveInfo, error) {
+	if err := ValidateStoreID(storeID); err != nil {
+		return nil, err
+	}
+	if IsDefaultStore(storeID) {
+		return nil, fmt.Errorf("cannot archive default store")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.deleting[storeID]; ok {
+		return nil, ErrStoreDeleting
+	}
+	storePath := m.storePath(storeID)
+	metaPath := filepath.Join(storePath, "meta.yaml")
+	meta, err := LoadStoreMeta(metaPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrStoreNotFound
+		}
+		return nil, err
+	}
+	if meta.IsArchived() {
+		return nil, ErrStoreArchived
+	}
+
+	// Close the store so its database file is complete on disk.
+	if managed, ok := m.stores[storeID]; ok {
+		if err := managed.Close(); err != nil {
+			slog.Warn("error closing store before archiving",
+				"store_id", storeID, "error", err)
+		}
+		delete(m.stores, storeID)
+		meta = managed.Meta
+	}
+
+	dbPath := filepath.Join(storePath, "engram.db")
+	archivePath := m.archivePath(storeID)
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		return nil, fmt.Errorf("create archive directory: %w", err)
+	}
+	size, err := compressFile(dbPath, archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("compress store %q: %w", storeID, err)
+	}
+
+	info := &ArchiveInfo{ArchivedAt: time.Now().UTC(), SizeBytes: size}
+	if m.archives != nil {
+		if err := m.archives.UploadArchive(ctx, storeID, archivePath); err != nil {
+			m.removeArchive(storeID)
+			return nil, err
+		}
+		info.Uploaded = true
+	}
+
+	meta.State = StoreStateArchived
+	meta.Archive = info
+	if err := SaveStoreMeta(metaPath, meta); err != nil {
+		m.removeArchive(storeID)
+		return nil, fmt.Errorf("save store metadata: %w", err)
+	}
+
+	for _, name := range []string{"engram.db", "engram.db-wal", "engram.db-shm", "snapshots"} {
+		if err := os.RemoveAll(filepath.Join(storePath, name)); err != nil {
+			slog.Warn("failed to remove archived store file",
+				"store_id", storeID, "file", name, "error", err)
+		}
+	}
+
+	slog.Info("store archived",
+		"component", "multistore",
+		"action", "store_archived",
+		"store_id", storeID,
+		"size_bytes", size,
+		"uploaded", info.Uploaded,
+	)
+	return info, nil
+}
+
+// ThawStore restores an archived store's database from its archive,
+// downloading the archive from archive storage if the local copy is gone,
+// and loads the store. Returns ErrStoreNotArchived if the store is active.
+func (m *StoreManager) ThawStore(ctx context.Context, storeID string) (*ManagedStore, error) {
+	if err := ValidateStoreID(storeID); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.deleting[storeID]; ok {
+		return nil, ErrStoreDeleting
+	}
+	storePath := m.storePath(storeID)
+	metaPath := filepath.Join(storePath, "meta.yaml")
+	meta, err := LoadStoreMeta(metaPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrStoreNotFound
+		}
+		return nil, err
+	}
+	if !meta.IsArchived() {
+		return nil, ErrStoreNotArchived
+	}
+
+	archivePath := m.archivePath(storeID)
+	if _, err := os.Stat(archivePath); os.IsNotExist(err) {
+		if m.archives == nil {
+			return nil, fmt.Errorf("archive of store %q is missing", storeID)
+		}
+		if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+			return nil, fmt.Errorf("create archive directory: %w", err)
+		}
+		if err := m.archives.DownloadArchive(ctx, storeID, archivePath); err != nil {
+			return nil, err
+		}
+	}
+
+	dbPath := filepath.Join(storePath, "engram.db")
+	if err := decompressFile(archivePath, dbPath); err != nil {
+		return nil, fmt.Errorf("decompress store %q: %w", storeID, err)
+	}
+
+	meta.State = StoreStateActive
+	meta.Archive = nil
+	if err := SaveStoreMeta(metaPath, meta); err != nil {
+		return nil, fmt.Errorf("save store metadata: %w", err)
+	}
+	m.removeArchive(storeID)
+
+	managed, err := NewManagedStore(storeID, storePath, m.storeOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("load thawed store %q: %w", storeID, err)
+	}
+	m.stores[storeID] = managed
+	m.evictOverflowLocked(storeID)
+
+	slog.Info("store thawed",
+		"component", "multistore",
+		"action", "store_thawed",
+		"store_id", storeID,
+	)
+	return managed, nil
+}
+
+// removeArchive removes a store's archive. Under an archive root, the
+// directories left empty up to the root are removed too.
+func (m *StoreManager) removeArchive(storeID string) {
+	archivePath := m.archivePath(storeID)
+	os.Remove(archivePath)
+	if m.archiveRoot == "" {
+		return
+	}
+	for dir := filepath.Dir(archivePath); dir != filepath.Clean(m.archiveRoot); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			return
+		}
+	}
+}
+
+// compressFile writes a zstd-compressed copy of src to dst through a
+// temporary file and returns the compressed size.
+func compressFile(src, dst string) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp)
+
+	enc, err := zstd.NewWriter(out, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+	if err != nil {
+		out.Close()
+		return 0, err
+	}
+	if _, err := io.Copy(enc, in); err != nil {
+		enc.Close()
+		out.Close()
+		return 0, err
+	}
+	if err := enc.Close(); err != nil {
+		out.Close()
+		return 0, err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return 0, err
+	}
+	if err := out.Close(); err != nil {
+		return 0, err
+	}
+
+	st, err := os.Stat(tmp)
+	if err != nil {
+		return 0, err
+	}
+	return st.Size(), os.Rename(tmp, dst)
+}
+
+// decompressFile restores the zstd-compressed src to dst through a
+// temporary file.
+func decompressFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	dec, err := zstd.NewReader(in)
+	if err != nil {
+		return err
+	}
+	defer dec.Close()
+
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	if _, err := io.Copy(out, dec); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
@@ -0,0 +1,160 @@
+package multistore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// fakeArchiveStorage keeps uploaded archives in a directory.
+type fakeArchiveStorage struct {
+	dir       string
+	uploads   int
+	downloads int
+}
+
+func (f *fakeArchiveStorage) UploadArchive(ctx context.Context, storeID, filePath string) error {
+	f.uploads++
+	return copyFile(filePath, filepath.Join(f.dir, filepath.Base(storeID)))
+}
+
+func (f *fakeArchiveStorage) DownloadArchive(ctx context.Context, storeID, filePath string) error {
+	f.downloads++
+	return copyFile(filepath.Join(f.dir, filepath.Base(storeID)), filePath)
+}
+
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0o644)
+}
+
+func newTestStoreManager(t *testing.T) (*StoreManager, string) {
+	t.Helper()
+	rootPath := filepath.Join(t.TempDir(), "stores")
+	manager, err := NewStoreManager(rootPath)
+	if err != nil {
+		t.Fatalf("NewStoreManager() error = %v", err)
+	}
+	t.Cleanup(func() { manager.Close() })
+	return manager, rootPath
+}
+
+func TestStoreManager_ArchiveAndThaw(t *testing.T) {
+	manager, rootPath := newTestStoreManager(t)
+	ctx := context.Background()
+
+	managed, err := manager.CreateStore(ctx, "org/dormant", "", "Dormant project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := managed.Store.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Orders use event sourcing", Category: "ARCHITECTURAL_DECISION", Confidence: 0.9, SourceID: "s1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := manager.ArchiveStore(ctx, "org/dormant")
+	if err != nil {
+		t.Fatalf("ArchiveStore() error = %v", err)
+	}
+	if info.SizeBytes == 0 || info.Uploaded {
+		t.Errorf("archive info = %+v, want a local, non-empty archive", info)
+	}
+
+	storePath := filepath.Join(rootPath, "org", "dormant")
+	if _, err := os.Stat(filepath.Join(storePath, "engram.db")); !os.IsNotExist(err) {
+		t.Error("database should be removed after archiving")
+	}
+	if _, err := manager.GetStore(ctx, "org/dormant"); !errors.Is(err, ErrStoreArchived) {
+		t.Errorf("GetStore() error = %v, want ErrStoreArchived", err)
+	}
+	if _, err := manager.ArchiveStore(ctx, "org/dormant"); !errors.Is(err, ErrStoreArchived) {
+		t.Errorf("second ArchiveStore() error = %v, want ErrStoreArchived", err)
+	}
+
+	storeInfo, err := manager.GetStoreInfo(ctx, "org/dormant")
+	if err != nil {
+		t.Fatalf("GetStoreInfo() error = %v", err)
+	}
+	if storeInfo.State != StoreStateArchived || storeInfo.Archive == nil || storeInfo.SizeBytes != info.SizeBytes {
+		t.Errorf("GetStoreInfo() = %+v, want archived with the archive's size", storeInfo)
+	}
+	active, err := manager.ListActiveStores(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 0 {
+		t.Errorf("ListActiveStores() = %+v, want none", active)
+	}
+
+	thawed, err := manager.ThawStore(ctx, "org/dormant")
+	if err != nil {
+		t.Fatalf("ThawStore() error = %v", err)
+	}
+	stats, err := thawed.Store.GetStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.LoreCount != 1 {
+		t.Errorf("LoreCount after thaw = %d, want 1", stats.LoreCount)
+	}
+	if thawed.Meta.IsArchived() || thawed.Meta.Archive != nil {
+		t.Errorf("meta after thaw = %+v, want active", thawed.Meta)
+	}
+	if _, err := os.Stat(filepath.Join(storePath, archiveFile)); !os.IsNotExist(err) {
+		t.Error("archive should be removed after thawing")
+	}
+	if _, err := manager.ThawStore(ctx, "org/dormant"); !errors.Is(err, ErrStoreNotArchived) {
+		t.Errorf("second ThawStore() error = %v, want ErrStoreNotArchived", err)
+	}
+}
+
+func TestStoreManager_ThawDownloadsMissingArchive(t *testing.T) {
+	manager, rootPath := newTestStoreManager(t)
+	storage := &fakeArchiveStorage{dir: t.TempDir()}
+	manager.SetArchiveStorage(storage)
+	ctx := context.Background()
+
+	if _, err := manager.CreateStore(ctx, "remote", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	info, err := manager.ArchiveStore(ctx, "remote")
+	if err != nil {
+		t.Fatalf("ArchiveStore() error = %v", err)
+	}
+	if !info.Uploaded || storage.uploads != 1 {
+		t.Errorf("uploaded = %v after %d uploads, want one upload", info.Uploaded, storage.uploads)
+	}
+
+	if err := os.Remove(filepath.Join(rootPath, "remote", archiveFile)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.ThawStore(ctx, "remote"); err != nil {
+		t.Fatalf("ThawStore() error = %v", err)
+	}
+	if storage.downloads != 1 {
+		t.Errorf("downloads = %d, want 1", storage.downloads)
+	}
+}
+
+func TestStoreManager_ArchiveStore_Errors(t *testing.T) {
+	manager, _ := newTestStoreManager(t)
+	ctx := context.Background()
+
+	if _, err := manager.ArchiveStore(ctx, DefaultStoreID); err == nil {
+		t.Error("ArchiveStore(default) error = nil, want an error")
+	}
+	if _, err := manager.ArchiveStore(ctx, "missing"); !errors.Is(err, ErrStoreNotFound) {
+		t.Errorf("ArchiveStore(missing) error = %v, want ErrStoreNotFound", err)
+	}
+	if _, err := manager.ThawStore(ctx, "missing"); !errors.Is(err, ErrStoreNotFound) {
+		t.Errorf("ThawStore(missing) error = %v, want ErrStoreNotFound", err)
+	}
+}
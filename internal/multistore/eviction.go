@@ -0,0 +1,144 @@
+package multistore
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// Reasons a store is closed by eviction, reported in logs.
+const (
+	evictReasonIdle     = "idle"
+	evictReasonCapacity = "capacity"
+	evictReasonReplaced = "replaced_on_disk"
+	evictReasonRemoved  = "removed_from_disk"
+)
+
+// checkpointer is implemented by stores that can fold their write-ahead
+// log into the database file before being closed.
+type checkpointer interface {
+	Checkpoint(ctx context.Context) error
+}
+
+// OpenStores returns the number of stores currently held open.
+func (m *StoreManager) OpenStores() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.stores)
+}
+
+// RunIdleEviction closes stores left unused for the idle timeout until ctx
+// is cancelled. It returns immediately when no idle timeout is set.
+func (m *StoreManager) RunIdleEviction(ctx context.Context) {
+	if m.idleTimeout <= 0 {
+		return
+	}
+
+	// Check several times per timeout so stores close soon after expiring.
+	interval := max(m.idleTimeout/4, time.Second)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.CloseIdleStores(ctx)
+		}
+	}
+}
+
+// CloseIdleStores closes every store not in use that has gone unused for
+// the idle timeout and returns how many were closed. Closed stores reopen
+// on their next GetStore.
+func (m *StoreManager) CloseIdleStores(ctx context.Context) int {
+	if m.idleTimeout <= 0 {
+		return 0
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cutoff := time.Now().Add(-m.idleTimeout)
+	var closed int
+	for id, managed := range m.stores {
+		if managed.inUse() || managed.lastAccessed().After(cutoff) {
+			continue
+		}
+		m.evictLocked(ctx, id, managed, evictReasonIdle)
+		closed++
+	}
+	return closed
+}
+
+// evictOverflowLocked closes the least recently used stores not in use
+// until no more than maxOpen are open. keep, the store just opened, is never
+// closed. Stores in use may leave the manager over the cap until they are
+// released and the next store is opened. Callers must hold m.mu.
+func (m *StoreManager) evictOverflowLocked(keep string) {
+	if m.maxOpen <= 0 || len(m.stores) <= m.maxOpen {
+		return
+	}
+
+	type candidate struct {
+		id       string
+		managed  *ManagedStore
+		accessed time.Time
+	}
+	var candidates []candidate
+	for id, managed := range m.stores {
+		if id == keep || managed.inUse() {
+			continue
+		}
+		candidates = append(candidates, candidate{id, managed, managed.lastAccessed()})
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return a.accessed.Compare(b.accessed)
+	})
+
+	for _, c := range candidates {
+		if len(m.stores) <= m.maxOpen {
+			return
+		}
+		m.evictLocked(context.Background(), c.id, c.managed, evictReasonCapacity)
+	}
+	if len(m.stores) > m.maxOpen {
+		slog.Warn("open stores above limit, remaining stores are in use",
+			"component", "multistore",
+			"open_stores", len(m.stores),
+			"max_open", m.maxOpen,
+		)
+	}
+}
+
+// evictLocked checkpoints and closes a loaded store and forgets it.
+// Callers must hold m.mu.
+func (m *StoreManager) evictLocked(ctx context.Context, storeID string, managed *ManagedStore, reason string) {
+	if c, ok := managed.Store.(checkpointer); ok {
+		if err := c.Checkpoint(ctx); err != nil {
+			slog.Warn("WAL checkpoint before closing store failed",
+				"component", "multistore",
+				"store_id", storeID,
+				"error", err,
+			)
+		}
+	}
+	if err := managed.Close(); err != nil {
+		slog.Warn("error closing evicted store",
+			"component", "multistore",
+			"store_id", storeID,
+			"error", err,
+		)
+	}
+	delete(m.stores, storeID)
+
+	slog.Info("store closed",
+		"component", "multistore",
+		"action", "store_evicted",
+		"store_id", storeID,
+		"reason", reason,
+		"open_stores", len(m.stores),
+	)
+}
@@ -0,0 +1,121 @@
+package multistore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestStoreManager_MaxOpenEvictsLeastRecentlyUsed(t *testing.T) {
+	rootPath := filepath.Join(t.TempDir(), "stores")
+	manager, err := NewStoreManager(rootPath, WithMaxOpenStores(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Close()
+	ctx := context.Background()
+
+	first, err := manager.CreateStore(ctx, "first", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := first.Store.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Orders use event sourcing", Category: "ARCHITECTURAL_DECISION", Confidence: 0.9, SourceID: "s1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	first.Meta.LastAccessed = time.Now().Add(-time.Hour)
+	for _, id := range []string{"second", "third"} {
+		if _, err := manager.CreateStore(ctx, id, "", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := manager.OpenStores(); n != 2 {
+		t.Errorf("OpenStores() = %d, want 2", n)
+	}
+	manager.mu.RLock()
+	_, stillOpen := manager.stores["first"]
+	manager.mu.RUnlock()
+	if stillOpen {
+		t.Error("least recently used store should have been closed")
+	}
+	if st, err := os.Stat(filepath.Join(rootPath, "first", "engram.db-wal")); err == nil && st.Size() > 0 {
+		t.Errorf("WAL left with %d bytes after eviction, want it checkpointed", st.Size())
+	}
+
+	reopened, err := manager.GetStore(ctx, "first")
+	if err != nil {
+		t.Fatalf("GetStore() after eviction error = %v", err)
+	}
+	stats, err := reopened.Store.GetStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.LoreCount != 1 {
+		t.Errorf("LoreCount after reopen = %d, want 1", stats.LoreCount)
+	}
+	if n := manager.OpenStores(); n != 2 {
+		t.Errorf("OpenStores() after reopen = %d, want 2", n)
+	}
+}
+
+func TestStoreManager_AcquiredStoresAreNotEvicted(t *testing.T) {
+	rootPath := filepath.Join(t.TempDir(), "stores")
+	manager, err := NewStoreManager(rootPath, WithMaxOpenStores(1), WithIdleTimeout(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Close()
+	ctx := context.Background()
+
+	if _, err := manager.CreateStore(ctx, "busy", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	busy, err := manager.AcquireStore(ctx, "busy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	busy.Meta.LastAccessed = time.Now().Add(-time.Hour)
+
+	if _, err := manager.CreateStore(ctx, "other", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if closed := manager.CloseIdleStores(ctx); closed != 0 {
+		t.Errorf("CloseIdleStores() = %d, want 0 while the idle store is acquired", closed)
+	}
+	if _, err := busy.Store.GetStats(ctx); err != nil {
+		t.Fatalf("acquired store was closed: %v", err)
+	}
+
+	busy.Release()
+	if closed := manager.CloseIdleStores(ctx); closed != 1 {
+		t.Errorf("CloseIdleStores() = %d, want 1 after release", closed)
+	}
+	if n := manager.OpenStores(); n != 1 {
+		t.Errorf("OpenStores() = %d, want 1", n)
+	}
+}
+
+func TestStoreManager_NoLimitsKeepStoresOpen(t *testing.T) {
+	manager, _ := newTestStoreManager(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"a", "b", "c"} {
+		managed, err := manager.CreateStore(ctx, id, "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		managed.Meta.LastAccessed = time.Now().Add(-24 * time.Hour)
+	}
+	if closed := manager.CloseIdleStores(ctx); closed != 0 {
+		t.Errorf("CloseIdleStores() = %d, want 0 without an idle timeout", closed)
+	}
+	if n := manager.OpenStores(); n != 3 {
+		t.Errorf("OpenStores() = %d, want 3", n)
+	}
+}
@@ -4,13 +4,16 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
 	"path/filepath"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hyperengineering/engram/internal/plugin"
 	"github.com/hyperengineering/engram/internal/store"
+	engramsync "github.com/hyperengineering/engram/internal/sync"
 )
 
 // ManagedStore wraps a SQLiteStore with metadata and access tracking.
@@ -22,10 +25,15 @@ type ManagedStore struct {
 
 	mu        sync.Mutex
 	metaDirty bool // Track if metadata needs saving
+
+	refs          atomic.Int32 // Requests holding the store open; see Acquire
+	dbFile        os.FileInfo  // Database file as opened, to detect replacement
+	schemaVersion int          // Sync schema version resolved at load; 0 if unknown
 }
 
-// NewManagedStore creates a managed store from an existing directory.
-func NewManagedStore(id, basePath string) (*ManagedStore, error) {
+// NewManagedStore creates a managed store from an existing directory. opts
+// apply after the settings in the store's metadata.
+func NewManagedStore(id, basePath string, opts ...store.StoreOption) (*ManagedStore, error) {
 	dbPath := filepath.Join(basePath, "engram.db")
 	metaPath := filepath.Join(basePath, "meta.yaml")
 
@@ -35,14 +43,21 @@ func NewManagedStore(id, basePath string) (*ManagedStore, error) {
 		return nil, fmt.Errorf("load store metadata: %w", err)
 	}
 
-	// Open SQLite store with store ID for logging context
-	sqliteStore, err := store.NewSQLiteStore(dbPath, store.WithStoreID(id))
+	// Open SQLite store with store ID for logging context, the store
+	// type's plugin for ingest/delete hooks, and its ingest settings
+	p, _ := plugin.Get(meta.Type)
+	sqliteStore, err := store.NewSQLiteStore(dbPath, append([]store.StoreOption{
+		store.WithStoreID(id),
+		store.WithPluginHooks(p),
+		store.WithNormalization(meta.Normalization),
+		store.WithCategories(meta.Categories),
+		store.WithSimilarityThreshold(meta.Deduplication.SimilarityThreshold),
+	}, opts...)...)
 	if err != nil {
 		return nil, fmt.Errorf("open store database: %w", err)
 	}
 
 	// Apply plugin-specific migrations if available
-	p, _ := plugin.Get(meta.Type)
 	if p != nil {
 		if migs := p.Migrations(); len(migs) > 0 {
 			if err := store.RunPluginMigrations(sqliteStore.DB(), migs); err != nil {
@@ -52,14 +67,68 @@ func NewManagedStore(id, basePath string) (*ManagedStore, error) {
 		}
 	}
 
+	schemaVersion := 0
+	if p != nil {
+		schemaVersion, err = recordSchemaVersion(sqliteStore, p)
+		if err != nil {
+			sqliteStore.Close()
+			return nil, fmt.Errorf("record schema version for %q: %w", meta.Type, err)
+		}
+	}
+
+	dbFile, err := os.Stat(dbPath)
+	if err != nil {
+		sqliteStore.Close()
+		return nil, fmt.Errorf("stat store database: %w", err)
+	}
+
 	return &ManagedStore{
-		ID:       id,
-		Store:    sqliteStore,
-		Meta:     meta,
-		BasePath: basePath,
+		ID:            id,
+		Store:         sqliteStore,
+		Meta:          meta,
+		BasePath:      basePath,
+		dbFile:        dbFile,
+		schemaVersion: schemaVersion,
 	}, nil
 }
 
+// recordSchemaVersion resolves the store's sync schema version from the
+// plugin's schema versions and the plugin migrations applied to it, and
+// records it in sync_meta, where snapshots carry it to clients.
+func recordSchemaVersion(s *store.SQLiteStore, p plugin.DomainPlugin) (int, error) {
+	applied, err := store.AppliedPluginMigrations(s.DB())
+	if err != nil {
+		return 0, err
+	}
+	version := plugin.ResolveSchemaVersion(p, applied)
+	if version == 0 {
+		return 0, fmt.Errorf("no supported schema version: plugin migrations missing")
+	}
+
+	ctx := context.Background()
+	value := strconv.Itoa(version)
+	if current, err := s.GetSyncMeta(ctx, engramsync.SyncMetaSchemaVersion); err == nil && current == value {
+		return version, nil
+	}
+	if err := s.SetSyncMeta(ctx, engramsync.SyncMetaSchemaVersion, value); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// replacedOnDisk reports whether the database file at the store's path is
+// no longer the one the store opened, as after a restore from backup.
+func (m *ManagedStore) replacedOnDisk() bool {
+	if m.dbFile == nil {
+		return false
+	}
+	current, err := os.Stat(filepath.Join(m.BasePath, "engram.db"))
+	if err != nil {
+		return true
+	}
+	return !os.SameFile(m.dbFile, current)
+}
+
 // TouchAccessed updates the last_accessed timestamp.
 // Saves metadata to disk periodically (not on every access).
 func (m *ManagedStore) TouchAccessed() {
@@ -70,6 +139,48 @@ func (m *ManagedStore) TouchAccessed() {
 	m.metaDirty = true
 }
 
+// lastAccessed returns when the store was last accessed.
+func (m *ManagedStore) lastAccessed() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.Meta.LastAccessed
+}
+
+// Acquire marks the store in use so the StoreManager does not close it
+// while idle or to make room for other stores. Every Acquire must be paired
+// with a Release; StoreManager.AcquireStore acquires on the caller's behalf.
+func (m *ManagedStore) Acquire() {
+	m.refs.Add(1)
+}
+
+// Release ends a use started with Acquire.
+func (m *ManagedStore) Release() {
+	m.refs.Add(-1)
+}
+
+// inUse reports whether any caller holds the store acquired.
+func (m *ManagedStore) inUse() bool {
+	return m.refs.Load() > 0
+}
+
+// drainPollInterval is how often waitReleased checks for in-flight users.
+const drainPollInterval = 10 * time.Millisecond
+
+// waitReleased blocks until no caller holds the store acquired or ctx is
+// done. The caller must stop new acquisitions first.
+func (m *ManagedStore) waitReleased(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for m.inUse() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
 // FlushMeta saves metadata to disk if dirty.
 func (m *ManagedStore) FlushMeta() error {
 	m.mu.Lock()
@@ -102,10 +213,15 @@ func (m *ManagedStore) Type() string {
 	return m.Meta.Type
 }
 
-// SchemaVersion returns the schema version from the database.
-// Returns 0 if unable to read (e.g., old database without sync_meta).
+// SchemaVersion returns the store's sync schema version: the highest
+// version of its plugin's registry (see plugin.SchemaVersions) that the
+// store's migrations support. Stores without a registered plugin fall back
+// to the version recorded in the database, and 0 if it cannot be read.
 func (m *ManagedStore) SchemaVersion(ctx context.Context) int {
-	version, err := m.Store.GetSyncMeta(ctx, "schema_version")
+	if m.schemaVersion > 0 {
+		return m.schemaVersion
+	}
+	version, err := m.Store.GetSyncMeta(ctx, engramsync.SyncMetaSchemaVersion)
 	if err != nil {
 		return 0
 	}
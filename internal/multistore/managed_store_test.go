@@ -7,7 +7,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hyperengineering/engram/internal/normalize"
 	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
 )
 
 func TestNewManagedStore_Success(t *testing.T) {
@@ -321,3 +323,37 @@ func TestManagedStore_SchemaVersion_FromMigrations(t *testing.T) {
 		t.Errorf("SchemaVersion() = %d, expected > 0 from migrations", version)
 	}
 }
+
+func TestNewManagedStore_AppliesNormalization(t *testing.T) {
+	tmpDir := t.TempDir()
+	storeDir := filepath.Join(tmpDir, "test-store")
+
+	if err := os.MkdirAll(storeDir, 0755); err != nil {
+		t.Fatalf("failed to create store dir: %v", err)
+	}
+	meta := NewStoreMeta("", "Normalized store")
+	meta.Normalization = normalize.Options{CollapseWhitespace: true}
+	if err := SaveStoreMeta(filepath.Join(storeDir, "meta.yaml"), meta); err != nil {
+		t.Fatalf("failed to save meta: %v", err)
+	}
+
+	managed, err := NewManagedStore("test-store", storeDir)
+	if err != nil {
+		t.Fatalf("NewManagedStore() error = %v", err)
+	}
+	defer managed.Close()
+
+	ctx := context.Background()
+	if _, err := managed.Store.IngestLore(ctx, []types.NewLoreEntry{{
+		Content: "  Spaced\n\nout  ", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src",
+	}}); err != nil {
+		t.Fatalf("IngestLore() error = %v", err)
+	}
+	pending, err := managed.Store.GetPendingEmbeddings(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetPendingEmbeddings() error = %v", err)
+	}
+	if len(pending) != 1 || pending[0].Content != "Spaced out" {
+		t.Errorf("stored entries = %+v, want content %q", pending, "Spaced out")
+	}
+}
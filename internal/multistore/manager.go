@@ -6,45 +6,190 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
 )
 
 // StoreManager manages multiple isolated stores with lazy loading.
+// Stores are opened on first use and, when limits are set, closed again
+// once idle or when more than the maximum number are open.
 type StoreManager struct {
-	rootPath string
+	rootPath    string
+	storePaths  []StorePath   // Roots for stores other than rootPath
+	archiveRoot string        // "" keeps archives in the store directories
+	maxOpen     int           // 0 keeps every loaded store open
+	idleTimeout time.Duration // 0 never closes idle stores
+
+	mu       sync.RWMutex
+	stores   map[string]*ManagedStore
+	deleting map[string]struct{} // Stores draining for DeleteStore
+	archives ArchiveStorage      // nil keeps archives local only
+
+	quantization  store.Quantization  // Encoding of embeddings stores write
+	faults        store.Faults        // Injected into every store's statements
+	tuning        store.Tuning        // SQLite pragmas of every store
+	slowQuery     time.Duration       // Zero logs no slow queries
+	feedbackLimit store.FeedbackLimit // Cap on repeated feedback in every store
+	selfFeedback  store.SelfFeedback  // Handling of feedback from an entry's own sources
+}
+
+// ManagerOption configures optional StoreManager behavior.
+type ManagerOption func(*StoreManager)
 
-	mu     sync.RWMutex
-	stores map[string]*ManagedStore
+// WithMaxOpenStores caps the number of stores held open. Opening a store
+// beyond the cap closes the least recently used stores not in use. Zero
+// means no cap.
+func WithMaxOpenStores(n int) ManagerOption {
+	return func(m *StoreManager) {
+		m.maxOpen = n
+	}
+}
+
+// WithIdleTimeout sets how long a store may go unused before
+// RunIdleEviction closes it. Zero keeps idle stores open.
+func WithIdleTimeout(d time.Duration) ManagerOption {
+	return func(m *StoreManager) {
+		m.idleTimeout = d
+	}
+}
+
+// WithEmbeddingQuantization sets the quantization the stores apply to the
+// embeddings they write.
+func WithEmbeddingQuantization(q store.Quantization) ManagerOption {
+	return func(m *StoreManager) {
+		m.quantization = q
+	}
+}
+
+// WithStoreFaults injects faults into the SQL statements of every store
+// the manager opens, for soak testing.
+func WithStoreFaults(f store.Faults) ManagerOption {
+	return func(m *StoreManager) {
+		m.faults = f
+	}
+}
+
+// WithStoreTuning sets the SQLite pragmas of every store the manager
+// opens.
+func WithStoreTuning(t store.Tuning) ManagerOption {
+	return func(m *StoreManager) {
+		m.tuning = t
+	}
+}
+
+// WithSlowQueryThreshold logs the statements of every store the manager
+// opens that take at least d. Zero logs none.
+func WithSlowQueryThreshold(d time.Duration) ManagerOption {
+	return func(m *StoreManager) {
+		m.slowQuery = d
+	}
+}
+
+// WithFeedbackLimit caps repeated feedback from one source on one entry in
+// every store the manager opens.
+func WithFeedbackLimit(l store.FeedbackLimit) ManagerOption {
+	return func(m *StoreManager) {
+		m.feedbackLimit = l
+	}
+}
+
+// WithSelfFeedback sets how helpful feedback from an entry's own sources is
+// applied in every store the manager opens.
+func WithSelfFeedback(f store.SelfFeedback) ManagerOption {
+	return func(m *StoreManager) {
+		m.selfFeedback = f
+	}
 }
 
 // NewStoreManager creates a manager with the given root path.
 // Creates the root directory if it doesn't exist.
-func NewStoreManager(rootPath string) (*StoreManager, error) {
+func NewStoreManager(rootPath string, opts ...ManagerOption) (*StoreManager, error) {
+	m := &StoreManager{
+		rootPath:     rootPath,
+		quantization: store.QuantizationNone,
+		stores:       make(map[string]*ManagedStore),
+		deleting:     make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if err := ValidateStorePaths(m.storePaths); err != nil {
+		return nil, err
+	}
+
 	// Expand ~ to home directory
-	if strings.HasPrefix(rootPath, "~/") {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return nil, fmt.Errorf("resolve home directory: %w", err)
+	var err error
+	if m.rootPath, err = expandHome(m.rootPath); err != nil {
+		return nil, err
+	}
+	m.storePaths = slices.Clone(m.storePaths)
+	for i := range m.storePaths {
+		if m.storePaths[i].Root, err = expandHome(m.storePaths[i].Root); err != nil {
+			return nil, err
 		}
-		rootPath = filepath.Join(home, rootPath[2:])
 	}
+	if m.archiveRoot, err = expandHome(m.archiveRoot); err != nil {
+		return nil, err
+	}
+
+	for _, root := range m.Roots() {
+		// Create root directory
+		if err := os.MkdirAll(root, 0755); err != nil {
+			return nil, fmt.Errorf("create stores root directory: %w", err)
+		}
 
-	// Create root directory
-	if err := os.MkdirAll(rootPath, 0755); err != nil {
-		return nil, fmt.Errorf("create stores root directory: %w", err)
+		// Finish removing stores whose deletion was interrupted
+		if err := os.RemoveAll(filepath.Join(root, trashDir)); err != nil {
+			slog.Warn("failed to clear deleted stores", "path", root, "error", err)
+		}
 	}
+	return m, nil
+}
 
-	return &StoreManager{
-		rootPath: rootPath,
-		stores:   make(map[string]*ManagedStore),
-	}, nil
+// storeOptions returns the options applied to every store the manager
+// opens.
+func (m *StoreManager) storeOptions() []store.StoreOption {
+	return []store.StoreOption{
+		store.WithEmbeddingQuantization(m.quantization),
+		store.WithFaults(m.faults),
+		store.WithTuning(m.tuning),
+		store.WithSlowQueryThreshold(m.slowQuery),
+		store.WithFeedbackLimit(m.feedbackLimit),
+		store.WithSelfFeedback(m.selfFeedback),
+	}
+}
+
+// RootPath returns the directory holding the stores, with ~ expanded.
+func (m *StoreManager) RootPath() string {
+	return m.rootPath
 }
 
 // GetStore returns the store for the given ID, loading it if necessary.
 // For non-default stores, returns ErrStoreNotFound if store doesn't exist.
 // For the default store, creates it if it doesn't exist.
+//
+// The store may be closed by idle or LRU eviction once the caller is done
+// with it; callers that hold it across a request should use AcquireStore.
 func (m *StoreManager) GetStore(ctx context.Context, storeID string) (*ManagedStore, error) {
+	return m.getStore(ctx, storeID, false)
+}
+
+// AcquireStore is GetStore for callers that hold the store for a while, such
+// as an HTTP request. The store is acquired before it is returned and is not
+// evicted until the caller calls Release on it.
+func (m *StoreManager) AcquireStore(ctx context.Context, storeID string) (*ManagedStore, error) {
+	return m.getStore(ctx, storeID, true)
+}
+
+// getStore implements GetStore and AcquireStore. Acquiring happens under the
+// manager lock so eviction cannot close the store in between.
+func (m *StoreManager) getStore(ctx context.Context, storeID string, acquire bool) (*ManagedStore, error) {
 	// Validate store ID
 	if err := ValidateStoreID(storeID); err != nil {
 		return nil, err
@@ -52,7 +197,14 @@ func (m *StoreManager) GetStore(ctx context.Context, storeID string) (*ManagedSt
 
 	// Fast path: check if already loaded
 	m.mu.RLock()
+	if _, ok := m.deleting[storeID]; ok {
+		m.mu.RUnlock()
+		return nil, ErrStoreDeleting
+	}
 	if managed, ok := m.stores[storeID]; ok {
+		if acquire {
+			managed.Acquire()
+		}
 		m.mu.RUnlock()
 		managed.TouchAccessed()
 		return managed, nil
@@ -64,7 +216,13 @@ func (m *StoreManager) GetStore(ctx context.Context, storeID string) (*ManagedSt
 	defer m.mu.Unlock()
 
 	// Double-check after acquiring write lock
+	if _, ok := m.deleting[storeID]; ok {
+		return nil, ErrStoreDeleting
+	}
 	if managed, ok := m.stores[storeID]; ok {
+		if acquire {
+			managed.Acquire()
+		}
 		managed.TouchAccessed()
 		return managed, nil
 	}
@@ -79,26 +237,36 @@ func (m *StoreManager) GetStore(ctx context.Context, storeID string) (*ManagedSt
 		}
 
 		// Create default store
-		if err := m.createStoreDir(storeID, DefaultStoreType, "Default store (auto-created)"); err != nil {
+		if err := m.createStoreDir(storeID, NewStoreMeta(DefaultStoreType, "Default store (auto-created)")); err != nil {
 			return nil, err
 		}
 	}
 
+	// Archived stores have no database to open until thawed
+	if meta, err := LoadStoreMeta(filepath.Join(storePath, "meta.yaml")); err == nil && meta.IsArchived() {
+		return nil, ErrStoreArchived
+	}
+
 	// Load the store
-	managed, err := NewManagedStore(storeID, storePath)
+	managed, err := NewManagedStore(storeID, storePath, m.storeOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("load store %q: %w", storeID, err)
 	}
 
+	if acquire {
+		managed.Acquire()
+	}
+	managed.TouchAccessed()
 	m.stores[storeID] = managed
+	m.evictOverflowLocked(storeID)
 
 	slog.Info("store loaded",
 		"component", "multistore",
 		"action", "store_loaded",
 		"store_id", storeID,
+		"open_stores", len(m.stores),
 	)
 
-	managed.TouchAccessed()
 	return managed, nil
 }
 
@@ -109,12 +277,80 @@ func (m *StoreManager) CreateStore(ctx context.Context, storeID, storeType, desc
 		return nil, err
 	}
 
-	// Default type early so storeType is correct for logging below.
-	// NewStoreMeta also defaults, but we normalize here for the CreateStore caller.
-	if storeType == "" {
-		storeType = DefaultStoreType
+	return m.createStore(storeID, NewStoreMeta(storeType, description), nil)
+}
+
+// CreateStoreFromTemplate creates a new store whose type and settings are
+// seeded from the named template (see TemplateNames). Returns
+// ErrUnknownTemplate for an unregistered name and ErrStoreAlreadyExists if
+// the store already exists.
+func (m *StoreManager) CreateStoreFromTemplate(ctx context.Context, storeID, templateName, description string) (*ManagedStore, error) {
+	if err := ValidateStoreID(storeID); err != nil {
+		return nil, err
 	}
+	t, err := Template(templateName)
+	if err != nil {
+		return nil, err
+	}
+	meta := NewStoreMeta(t.Type, description)
+	t.apply(meta)
+	return m.createStore(storeID, meta, nil)
+}
 
+// storeCloner is implemented by stores that can copy their database for
+// CloneStore.
+type storeCloner interface {
+	CloneInto(ctx context.Context, path string, filter types.CloneFilter) (int64, error)
+}
+
+// CloneStore creates targetID from a point-in-time copy of sourceID,
+// keeping the lore that matches filter. The clone inherits the source's
+// type and settings. It returns the new store and the number of lore
+// entries copied, ErrStoreNotFound if the source does not exist and
+// ErrStoreAlreadyExists if the target does.
+func (m *StoreManager) CloneStore(ctx context.Context, sourceID, targetID, description string, filter types.CloneFilter) (*ManagedStore, int64, error) {
+	if err := ValidateStoreID(targetID); err != nil {
+		return nil, 0, err
+	}
+	source, err := m.GetStore(ctx, sourceID)
+	if err != nil {
+		return nil, 0, err
+	}
+	cloner, ok := source.Store.(storeCloner)
+	if !ok {
+		return nil, 0, fmt.Errorf("store %q does not support cloning", sourceID)
+	}
+	if _, err := os.Stat(m.storePath(targetID)); err == nil {
+		return nil, 0, ErrStoreAlreadyExists
+	}
+
+	// Copy outside the lock, which other requests need while a large
+	// store is copied, then move the copy into the new store's directory.
+	tmpPath := filepath.Join(m.storeRoot(targetID), fmt.Sprintf(".clone-%d.db", time.Now().UnixNano()))
+	defer os.Remove(tmpPath)
+	copied, err := cloner.CloneInto(ctx, tmpPath, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("clone store %q: %w", sourceID, err)
+	}
+
+	meta := *source.Meta
+	now := time.Now().UTC()
+	meta.Created, meta.LastAccessed = now, now
+	meta.Description = description
+	meta.ClonedFrom = sourceID
+	meta.Categories = slices.Clone(meta.Categories)
+	managed, err := m.createStore(targetID, &meta, func(dbPath string) error {
+		return os.Rename(tmpPath, dbPath)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return managed, copied, nil
+}
+
+// createStore writes meta for a new store and loads it. If seed is set it
+// is called with the database path before the store is opened.
+func (m *StoreManager) createStore(storeID string, meta *StoreMeta, seed func(dbPath string) error) (*ManagedStore, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -126,23 +362,32 @@ func (m *StoreManager) CreateStore(ctx context.Context, storeID, storeType, desc
 	}
 
 	// Create store directory and metadata
-	if err := m.createStoreDir(storeID, storeType, description); err != nil {
+	if err := m.createStoreDir(storeID, meta); err != nil {
 		return nil, err
 	}
+	if seed != nil {
+		if err := seed(filepath.Join(storePath, "engram.db")); err != nil {
+			os.RemoveAll(storePath)
+			return nil, fmt.Errorf("seed store %q: %w", storeID, err)
+		}
+	}
 
 	// Load the new store
-	managed, err := NewManagedStore(storeID, storePath)
+	managed, err := NewManagedStore(storeID, storePath, m.storeOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("load new store %q: %w", storeID, err)
 	}
 
 	m.stores[storeID] = managed
+	m.evictOverflowLocked(storeID)
 
 	slog.Info("store created",
 		"component", "multistore",
 		"action", "store_created",
 		"store_id", storeID,
-		"store_type", storeType,
+		"store_type", meta.Type,
+		"template", meta.Template,
+		"cloned_from", meta.ClonedFrom,
 	)
 
 	return managed, nil
@@ -150,6 +395,13 @@ func (m *StoreManager) CreateStore(ctx context.Context, storeID, storeType, desc
 
 // DeleteStore removes a store and its data.
 // Returns ErrStoreNotFound if store doesn't exist.
+//
+// The store is first marked deleting: GetStore and AcquireStore return
+// ErrStoreDeleting for it while DeleteStore waits for requests that hold it
+// acquired to finish. If ctx ends first the store is left in place and
+// usable, and the context error is returned. Otherwise the store is closed
+// and its directory is moved out of the store tree in a single rename before
+// being removed, so a crash never leaves a partly deleted store behind.
 func (m *StoreManager) DeleteStore(ctx context.Context, storeID string) error {
 	if err := ValidateStoreID(storeID); err != nil {
 		return err
@@ -161,28 +413,54 @@ func (m *StoreManager) DeleteStore(ctx context.Context, storeID string) error {
 	}
 
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	storePath := m.storePath(storeID)
-
-	// Check if exists
 	if _, err := os.Stat(storePath); os.IsNotExist(err) {
+		m.mu.Unlock()
 		return ErrStoreNotFound
 	}
+	if _, ok := m.deleting[storeID]; ok {
+		m.mu.Unlock()
+		return ErrStoreDeleting
+	}
+	m.deleting[storeID] = struct{}{}
+	managed := m.stores[storeID]
+	m.mu.Unlock()
+
+	// Drain in-flight requests without the lock so they can finish.
+	if managed != nil {
+		if err := managed.waitReleased(ctx); err != nil {
+			m.mu.Lock()
+			delete(m.deleting, storeID)
+			m.mu.Unlock()
+			return fmt.Errorf("drain store %q: %w", storeID, err)
+		}
+	}
 
-	// Close if loaded
-	if managed, ok := m.stores[storeID]; ok {
-		if err := managed.Close(); err != nil {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	defer delete(m.deleting, storeID)
+
+	// Close if still loaded; eviction may have closed it while draining
+	if loaded, ok := m.stores[storeID]; ok {
+		if err := loaded.Close(); err != nil {
 			slog.Warn("error closing store before deletion",
 				"store_id", storeID, "error", err)
 		}
 		delete(m.stores, storeID)
 	}
 
-	// Remove directory
-	if err := os.RemoveAll(storePath); err != nil {
+	// Move the directory aside atomically, then remove it
+	trashPath, err := m.moveToTrash(m.storeRoot(storeID), storePath)
+	if err != nil {
 		return fmt.Errorf("remove store directory: %w", err)
 	}
+	if m.archiveRoot != "" {
+		m.removeArchive(storeID)
+	}
+	if err := os.RemoveAll(trashPath); err != nil {
+		slog.Warn("failed to remove deleted store files",
+			"store_id", storeID, "path", trashPath, "error", err)
+	}
 
 	slog.Info("store deleted",
 		"component", "multistore",
@@ -193,37 +471,97 @@ func (m *StoreManager) DeleteStore(ctx context.Context, storeID string) error {
 	return nil
 }
 
-// ListStores returns metadata for all existing stores.
-func (m *StoreManager) ListStores(ctx context.Context) ([]StoreInfo, error) {
-	entries, err := os.ReadDir(m.rootPath)
-	if err != nil {
-		return nil, fmt.Errorf("read stores directory: %w", err)
+// trashDir holds store directories being removed. Store ID segments cannot
+// start with a dot, so it never collides with a store.
+const trashDir = ".trash"
+
+// moveToTrash renames a store directory into the trash directory of the
+// root holding it and returns its new path.
+func (m *StoreManager) moveToTrash(root, storePath string) (string, error) {
+	trash := filepath.Join(root, trashDir)
+	if err := os.MkdirAll(trash, 0755); err != nil {
+		return "", err
+	}
+	dst := filepath.Join(trash, strconv.FormatInt(time.Now().UnixNano(), 10))
+	if err := os.Rename(storePath, dst); err != nil {
+		return "", err
 	}
+	return dst, nil
+}
 
+// ListStores returns metadata for all existing stores, across every root.
+// A store directory under a root other than the one its ID is configured
+// for is not listed, as it cannot be opened.
+func (m *StoreManager) ListStores(ctx context.Context) ([]StoreInfo, error) {
 	var result []StoreInfo
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
+	for _, root := range m.Roots() {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			return nil, fmt.Errorf("read stores directory: %w", err)
 		}
 
-		// Recursively find stores (handles nested paths like org/project)
-		stores, err := m.findStoresRecursive(ctx, entry.Name(), "")
-		if err != nil {
-			slog.Warn("error scanning store directory",
-				"path", entry.Name(), "error", err)
-			continue
+		for _, entry := range entries {
+			// Dot directories hold internal state such as the trash
+			if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+
+			// Recursively find stores (handles nested paths like org/project)
+			stores, err := m.findStoresRecursive(ctx, root, entry.Name(), "")
+			if err != nil {
+				slog.Warn("error scanning store directory",
+					"path", filepath.Join(root, entry.Name()), "error", err)
+				continue
+			}
+			for _, info := range stores {
+				if containsPath([]string{m.storeRoot(info.ID)}, root) {
+					result = append(result, info)
+				}
+			}
 		}
-		result = append(result, stores...)
 	}
 
 	return result, nil
 }
 
-// findStoresRecursive discovers stores in nested directories.
-func (m *StoreManager) findStoresRecursive(ctx context.Context, currentPath, prefix string) ([]StoreInfo, error) {
-	fullPath := filepath.Join(m.rootPath, currentPath)
+// ListActiveStores returns the stores that are not archived, for background
+// work that needs to open each store.
+func (m *StoreManager) ListActiveStores(ctx context.Context) ([]StoreInfo, error) {
+	stores, err := m.ListStores(ctx)
+	if err != nil {
+		return nil, err
+	}
+	active := stores[:0]
+	for _, info := range stores {
+		if info.State != StoreStateArchived {
+			active = append(active, info)
+		}
+	}
+	return active, nil
+}
+
+// GetStoreInfo returns summary information about a store without loading
+// it, so it also describes archived stores.
+func (m *StoreManager) GetStoreInfo(ctx context.Context, storeID string) (StoreInfo, error) {
+	if err := ValidateStoreID(storeID); err != nil {
+		return StoreInfo{}, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	info, err := m.getStoreInfo(ctx, storeID, m.storePath(storeID))
+	if os.IsNotExist(err) {
+		return StoreInfo{}, ErrStoreNotFound
+	}
+	return info, err
+}
+
+// findStoresRecursive discovers stores in nested directories under root.
+func (m *StoreManager) findStoresRecursive(ctx context.Context, root, currentPath, prefix string) ([]StoreInfo, error) {
+	fullPath := filepath.Join(root, currentPath)
 	if prefix != "" {
-		fullPath = filepath.Join(m.rootPath, prefix, currentPath)
+		fullPath = filepath.Join(root, prefix, currentPath)
 	}
 
 	// Check if this is a store (has meta.yaml)
@@ -258,7 +596,7 @@ func (m *StoreManager) findStoresRecursive(ctx context.Context, currentPath, pre
 			newPrefix = prefix + "/" + currentPath
 		}
 
-		stores, err := m.findStoresRecursive(ctx, entry.Name(), newPrefix)
+		stores, err := m.findStoresRecursive(ctx, root, entry.Name(), newPrefix)
 		if err != nil {
 			continue // Skip problematic directories
 		}
@@ -276,13 +614,21 @@ func (m *StoreManager) getStoreInfo(ctx context.Context, storeID, basePath strin
 		return StoreInfo{}, err
 	}
 
-	// Get database size
+	// Get database size, or the archive's for archived stores
 	dbPath := filepath.Join(basePath, "engram.db")
+	if meta.IsArchived() {
+		dbPath = m.archivePath(storeID)
+	}
 	var sizeBytes int64
 	if info, err := os.Stat(dbPath); err == nil {
 		sizeBytes = info.Size()
 	}
 
+	state := meta.State
+	if state == "" {
+		state = StoreStateActive
+	}
+
 	// Get schema version from the in-memory store if loaded.
 	// Returns 0 for stores not currently loaded -- this is intentional to avoid
 	// lazy-loading every store during ListStores, which would be expensive.
@@ -298,25 +644,22 @@ func (m *StoreManager) getStoreInfo(ctx context.Context, storeID, basePath strin
 		Created:       meta.Created,
 		LastAccessed:  meta.LastAccessed,
 		Description:   meta.Description,
+		Template:      meta.Template,
+		State:         state,
+		Archive:       meta.Archive,
 		SizeBytes:     sizeBytes,
+		Retention:     meta.Retention,
 	}, nil
 }
 
-// storePath returns the filesystem path for a store ID.
-func (m *StoreManager) storePath(storeID string) string {
-	// Store ID segments map to directory structure
-	return filepath.Join(m.rootPath, storeID)
-}
-
 // createStoreDir creates a new store directory with metadata.
-func (m *StoreManager) createStoreDir(storeID, storeType, description string) error {
+func (m *StoreManager) createStoreDir(storeID string, meta *StoreMeta) error {
 	storePath := m.storePath(storeID)
 
 	if err := os.MkdirAll(storePath, 0755); err != nil {
 		return fmt.Errorf("create store directory: %w", err)
 	}
 
-	meta := NewStoreMeta(storeType, description)
 	metaPath := filepath.Join(storePath, "meta.yaml")
 
 	if err := SaveStoreMeta(metaPath, meta); err != nil {
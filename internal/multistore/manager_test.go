@@ -7,6 +7,9 @@ import (
 	"path/filepath"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
 )
 
 func TestNewStoreManager_CreatesRootDirectory(t *testing.T) {
@@ -372,6 +375,113 @@ func TestStoreManager_DeleteStore_Default_Forbidden(t *testing.T) {
 	}
 }
 
+func TestStoreManager_DeleteStore_DrainsInFlightRequests(t *testing.T) {
+	manager, rootPath := newTestStoreManager(t)
+	ctx := context.Background()
+
+	if _, err := manager.CreateStore(ctx, "busy", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	inFlight, err := manager.AcquireStore(ctx, "busy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- manager.DeleteStore(ctx, "busy") }()
+
+	// New requests are refused while the in-flight one drains
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := manager.GetStore(ctx, "busy"); errors.Is(err, ErrStoreDeleting) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("store was never marked deleting")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := manager.DeleteStore(ctx, "busy"); !errors.Is(err, ErrStoreDeleting) {
+		t.Errorf("concurrent DeleteStore() error = %v, want ErrStoreDeleting", err)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("DeleteStore() returned %v before the in-flight request finished", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The in-flight request can still use the store
+	if _, err := inFlight.Store.GetStats(ctx); err != nil {
+		t.Errorf("in-flight GetStats() error = %v", err)
+	}
+	inFlight.Release()
+
+	if err := <-done; err != nil {
+		t.Fatalf("DeleteStore() error = %v", err)
+	}
+	if _, err := manager.GetStore(ctx, "busy"); !errors.Is(err, ErrStoreNotFound) {
+		t.Errorf("GetStore() after delete error = %v, want ErrStoreNotFound", err)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(rootPath, trashDir)); len(entries) != 0 {
+		t.Errorf("trash holds %d entries after delete, want 0", len(entries))
+	}
+}
+
+func TestStoreManager_DeleteStore_DrainTimeoutKeepsStore(t *testing.T) {
+	manager, _ := newTestStoreManager(t)
+	ctx := context.Background()
+
+	if _, err := manager.CreateStore(ctx, "busy", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	inFlight, err := manager.AcquireStore(ctx, "busy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inFlight.Release()
+
+	drainCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := manager.DeleteStore(drainCtx, "busy"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("DeleteStore() error = %v, want DeadlineExceeded", err)
+	}
+	managed, err := manager.GetStore(ctx, "busy")
+	if err != nil {
+		t.Fatalf("GetStore() after failed drain error = %v", err)
+	}
+	if _, err := managed.Store.GetStats(ctx); err != nil {
+		t.Errorf("store unusable after failed drain: %v", err)
+	}
+}
+
+func TestNewStoreManager_ClearsInterruptedDeletes(t *testing.T) {
+	rootPath := filepath.Join(t.TempDir(), "stores")
+	leftover := filepath.Join(rootPath, trashDir, "123", "org", "gone")
+	if err := os.MkdirAll(leftover, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveStoreMeta(filepath.Join(leftover, "meta.yaml"), NewStoreMeta("", "")); err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := NewStoreManager(rootPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Close()
+
+	if _, err := os.Stat(filepath.Join(rootPath, trashDir)); !os.IsNotExist(err) {
+		t.Error("trash directory should be removed on startup")
+	}
+	stores, err := manager.ListStores(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stores) != 0 {
+		t.Errorf("ListStores() = %+v, want none", stores)
+	}
+}
+
 func TestStoreManager_DeleteStore_InvalidID(t *testing.T) {
 	tmpDir := t.TempDir()
 	rootPath := filepath.Join(tmpDir, "stores")
@@ -671,3 +781,51 @@ func TestStoreManager_GetStore_TouchesAccessed(t *testing.T) {
 		t.Error("LastAccessed should be set")
 	}
 }
+
+func TestStoreManager_CloneStore(t *testing.T) {
+	manager, err := NewStoreManager(filepath.Join(t.TempDir(), "stores"))
+	if err != nil {
+		t.Fatalf("NewStoreManager() error = %v", err)
+	}
+	defer manager.Close()
+
+	ctx := context.Background()
+	if _, err := manager.CreateStoreFromTemplate(ctx, "main", "recall-strict-dedup", "Main"); err != nil {
+		t.Fatalf("CreateStoreFromTemplate() error = %v", err)
+	}
+
+	clone, copied, err := manager.CloneStore(ctx, "main", "exp/branch-a", "Experiment", types.CloneFilter{})
+	if err != nil {
+		t.Fatalf("CloneStore() error = %v", err)
+	}
+	if copied != 0 {
+		t.Errorf("copied = %d, want 0 from an empty store", copied)
+	}
+	if clone.Meta.ClonedFrom != "main" || clone.Meta.Description != "Experiment" {
+		t.Errorf("meta = %+v, want cloned_from main and the new description", clone.Meta)
+	}
+	if clone.Meta.Deduplication.SimilarityThreshold != 0.85 {
+		t.Errorf("clone did not inherit the source's settings: %+v", clone.Meta)
+	}
+
+	if _, _, err := manager.CloneStore(ctx, "main", "exp/branch-a", "", types.CloneFilter{}); !errors.Is(err, ErrStoreAlreadyExists) {
+		t.Errorf("CloneStore() onto existing store error = %v, want ErrStoreAlreadyExists", err)
+	}
+	if _, _, err := manager.CloneStore(ctx, "missing", "exp/branch-b", "", types.CloneFilter{}); !errors.Is(err, ErrStoreNotFound) {
+		t.Errorf("CloneStore() from missing store error = %v, want ErrStoreNotFound", err)
+	}
+
+	stores, err := manager.ListStores(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stores) != 2 {
+		t.Errorf("ListStores() = %d stores, want 2 (no leftover temporary files)", len(stores))
+	}
+	entries, _ := os.ReadDir(manager.rootPath)
+	for _, e := range entries {
+		if !e.IsDir() {
+			t.Errorf("unexpected file %q left in the root", e.Name())
+		}
+	}
+}
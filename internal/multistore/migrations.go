@@ -0,0 +1,42 @@
+package multistore
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/hyperengineering/engram/internal/plugin"
+	"github.com/hyperengineering/engram/internal/store"
+)
+
+// StoreMigrations lists the migrations a store would apply when next
+// opened.
+type StoreMigrations struct {
+	StoreID string
+	store.MigrationStatus
+}
+
+// PendingMigrations reports, for every active store, the core and plugin
+// migrations opening it would apply. Stores are inspected read-only and are
+// not loaded; archived stores are skipped, as they are migrated on thaw.
+func (m *StoreManager) PendingMigrations(ctx context.Context) ([]StoreMigrations, error) {
+	stores, err := m.ListActiveStores(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]StoreMigrations, 0, len(stores))
+	for _, info := range stores {
+		var migs []plugin.Migration
+		if p, ok := plugin.Get(info.Type); ok {
+			migs = p.Migrations()
+		}
+		dbPath := filepath.Join(m.storePath(info.ID), "engram.db")
+		status, err := store.InspectMigrations(dbPath, info.Type, migs)
+		if err != nil {
+			return nil, fmt.Errorf("inspect store %q: %w", info.ID, err)
+		}
+		result = append(result, StoreMigrations{StoreID: info.ID, MigrationStatus: *status})
+	}
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package multistore
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// StorePath places the stores whose IDs match a pattern under a root
+// other than the manager's, such as a faster volume for busy stores. A
+// store's directory is the store ID joined to the root, as under the
+// manager's root.
+type StorePath struct {
+	// Match is a path.Match pattern over store IDs, such as "hot/*".
+	Match string
+	// Root is the directory holding the matching stores.
+	Root string
+}
+
+// ValidateStorePaths checks that every pattern is well formed and every
+// root is set.
+func ValidateStorePaths(paths []StorePath) error {
+	for _, p := range paths {
+		if p.Match == "" || p.Root == "" {
+			return fmt.Errorf("store path needs both match and root: %+v", p)
+		}
+		if _, err := path.Match(p.Match, ""); err != nil {
+			return fmt.Errorf("store path pattern %q: %w", p.Match, err)
+		}
+	}
+	return nil
+}
+
+// WithStorePaths places the stores matching each pattern under its root
+// instead of the manager's. The first matching pattern wins; stores
+// matching none stay under the manager's root. Moving a store between
+// roots is an operator task: stop the server, move its directory, then
+// change the configuration.
+func WithStorePaths(paths []StorePath) ManagerOption {
+	return func(m *StoreManager) {
+		m.storePaths = paths
+	}
+}
+
+// WithArchiveRoot keeps the archives of archived stores under dir, such as
+// a cheaper volume, rather than in the stores' own directories. Their
+// metadata stays with the store.
+func WithArchiveRoot(dir string) ManagerOption {
+	return func(m *StoreManager) {
+		m.archiveRoot = dir
+	}
+}
+
+// expandHome replaces a leading ~/ in p with the home directory.
+func expandHome(p string) (string, error) {
+	if !strings.HasPrefix(p, "~/") {
+		return p, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home directory: %w", err)
+	}
+	return filepath.Join(home, p[2:]), nil
+}
+
+// Roots returns every directory holding stores: the manager's root first,
+// then those of the store paths, without duplicates.
+func (m *StoreManager) Roots() []string {
+	roots := []string{m.rootPath}
+	for _, p := range m.storePaths {
+		if !containsPath(roots, p.Root) {
+			roots = append(roots, p.Root)
+		}
+	}
+	return roots
+}
+
+// ArchiveRoot returns the directory holding archives, with ~ expanded, or
+// "" if archives are kept with their stores.
+func (m *StoreManager) ArchiveRoot() string {
+	return m.archiveRoot
+}
+
+// storeRoot returns the directory under which a store ID lives.
+func (m *StoreManager) storeRoot(storeID string) string {
+	for _, p := range m.storePaths {
+		if ok, _ := path.Match(p.Match, storeID); ok {
+			return p.Root
+		}
+	}
+	return m.rootPath
+}
+
+// storePath returns the filesystem path for a store ID.
+func (m *StoreManager) storePath(storeID string) string {
+	// Store ID segments map to directory structure
+	return filepath.Join(m.storeRoot(storeID), storeID)
+}
+
+// archivePath returns where a store's archive is kept.
+func (m *StoreManager) archivePath(storeID string) string {
+	if m.archiveRoot != "" {
+		return filepath.Join(m.archiveRoot, storeID, archiveFile)
+	}
+	return filepath.Join(m.storePath(storeID), archiveFile)
+}
+
+func containsPath(paths []string, p string) bool {
+	for _, q := range paths {
+		if filepath.Clean(q) == filepath.Clean(p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package multistore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoreManager_StorePaths(t *testing.T) {
+	dir := t.TempDir()
+	rootPath := filepath.Join(dir, "stores")
+	hotRoot := filepath.Join(dir, "ssd")
+	archiveRoot := filepath.Join(dir, "cold")
+	manager, err := NewStoreManager(rootPath,
+		WithStorePaths([]StorePath{{Match: "hot/*", Root: hotRoot}}),
+		WithArchiveRoot(archiveRoot),
+	)
+	if err != nil {
+		t.Fatalf("NewStoreManager() error = %v", err)
+	}
+	defer manager.Close()
+	ctx := context.Background()
+
+	for _, id := range []string{"hot/api", "team-a"} {
+		if _, err := manager.CreateStore(ctx, id, "", ""); err != nil {
+			t.Fatalf("CreateStore(%q) error = %v", id, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(hotRoot, "hot", "api", "engram.db")); err != nil {
+		t.Errorf("hot/api not under its configured root: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(rootPath, "hot")); !os.IsNotExist(err) {
+		t.Errorf("hot/api also created under the stores root: %v", err)
+	}
+
+	stores, err := manager.ListStores(ctx)
+	if err != nil {
+		t.Fatalf("ListStores() error = %v", err)
+	}
+	if len(stores) != 2 {
+		t.Errorf("ListStores() = %+v, want hot/api and team-a", stores)
+	}
+
+	// Archives go to the archive root and come back on thaw
+	if _, err := manager.ArchiveStore(ctx, "hot/api"); err != nil {
+		t.Fatalf("ArchiveStore() error = %v", err)
+	}
+	archivePath := filepath.Join(archiveRoot, "hot", "api", archiveFile)
+	if _, err := os.Stat(archivePath); err != nil {
+		t.Errorf("archive not under the archive root: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(hotRoot, "hot", "api", "engram.db")); !os.IsNotExist(err) {
+		t.Errorf("archived database still in place: %v", err)
+	}
+	info, err := manager.GetStoreInfo(ctx, "hot/api")
+	if err != nil || info.SizeBytes == 0 {
+		t.Errorf("GetStoreInfo() = %+v, %v, want the archive's size", info, err)
+	}
+	if _, err := manager.ThawStore(ctx, "hot/api"); err != nil {
+		t.Fatalf("ThawStore() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(archiveRoot, "hot")); !os.IsNotExist(err) {
+		t.Errorf("archive directories left behind after thaw: %v", err)
+	}
+
+	if err := manager.DeleteStore(ctx, "hot/api"); err != nil {
+		t.Fatalf("DeleteStore() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(hotRoot, "hot", "api")); !os.IsNotExist(err) {
+		t.Errorf("deleted store still on disk: %v", err)
+	}
+}
+
+func TestStoreManager_RescanMisplacedStore(t *testing.T) {
+	dir := t.TempDir()
+	rootPath := filepath.Join(dir, "stores")
+
+	// A store created before its pattern was configured stays put
+	plain, err := NewStoreManager(rootPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := plain.CreateStore(context.Background(), "hot/api", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	plain.Close()
+
+	manager, err := NewStoreManager(rootPath, WithStorePaths([]StorePath{{Match: "hot/*", Root: filepath.Join(dir, "ssd")}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Close()
+
+	report, err := manager.RescanStores(context.Background())
+	if err != nil {
+		t.Fatalf("RescanStores() error = %v", err)
+	}
+	if len(report.Invalid) != 1 || report.Invalid[0].StoreID != "hot/api" {
+		t.Errorf("Invalid = %+v, want hot/api reported as outside its root", report.Invalid)
+	}
+	if stores, _ := manager.ListStores(context.Background()); len(stores) != 0 {
+		t.Errorf("ListStores() = %+v, want the misplaced store hidden", stores)
+	}
+}
+
+func TestValidateStorePaths(t *testing.T) {
+	for _, paths := range [][]StorePath{
+		{{Match: "hot/[", Root: "/ssd"}},
+		{{Match: "hot/*"}},
+		{{Root: "/ssd"}},
+	} {
+		if err := ValidateStorePaths(paths); err == nil {
+			t.Errorf("ValidateStorePaths(%+v) error = nil", paths)
+		}
+	}
+	if err := ValidateStorePaths([]StorePath{{Match: "hot/*", Root: "/ssd"}}); err != nil {
+		t.Errorf("ValidateStorePaths() error = %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package multistore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RescanReport summarizes a RescanStores pass.
+type RescanReport struct {
+	// Stores is the number of store directories found on disk.
+	Stores int `json:"stores"`
+	// Reloaded lists open stores whose database was replaced on disk. Their
+	// handles were closed so the next request opens the restored files.
+	Reloaded []string `json:"reloaded"`
+	// Removed lists open stores whose directory is gone; they were closed.
+	Removed []string `json:"removed"`
+	// InUse lists stores that changed on disk but were serving requests and
+	// were left open. Rescan again once they are idle.
+	InUse []string `json:"in_use"`
+	// Invalid lists store directories that cannot be served.
+	Invalid []RescanIssue `json:"invalid"`
+}
+
+// RescanIssue describes a store directory that failed validation.
+type RescanIssue struct {
+	StoreID string `json:"store_id"`
+	Error   string `json:"error"`
+}
+
+// RescanStores reconciles the open stores with the store directories on
+// disk, for stores restored or removed while the server runs. Directories
+// added under the root are served on their first request without a rescan;
+// RescanStores checks they can be, and closes open handles to databases that
+// were replaced or deleted so requests see the files now on disk.
+func (m *StoreManager) RescanStores(ctx context.Context) (*RescanReport, error) {
+	ids, err := m.storeDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	report := &RescanReport{
+		Stores:   len(ids),
+		Reloaded: []string{},
+		Removed:  []string{},
+		InUse:    []string{},
+		Invalid:  []RescanIssue{},
+	}
+	onDisk := make(map[string]bool, len(ids))
+	for _, dir := range ids {
+		id := dir.id
+		err := ValidateStoreID(id)
+		if err == nil && !containsPath([]string{m.storeRoot(id)}, dir.root) {
+			err = fmt.Errorf("store is under %s but its configured root is %s", dir.root, m.storeRoot(id))
+		} else if err == nil {
+			onDisk[id] = true
+			err = checkStoreDir(m.storePath(id), m.archivePath(id))
+		}
+		if err != nil {
+			report.Invalid = append(report.Invalid, RescanIssue{StoreID: id, Error: err.Error()})
+		}
+	}
+
+	for id, managed := range m.stores {
+		if _, ok := m.deleting[id]; ok {
+			continue
+		}
+		var list *[]string
+		var reason string
+		switch {
+		case !onDisk[id]:
+			list, reason = &report.Removed, evictReasonRemoved
+		case managed.replacedOnDisk():
+			list, reason = &report.Reloaded, evictReasonReplaced
+		default:
+			continue
+		}
+		if managed.inUse() {
+			report.InUse = append(report.InUse, id)
+			continue
+		}
+		// Checkpointing empties the old handle's WAL, which SQLite would
+		// otherwise replay onto the restored database when it is opened.
+		m.evictLocked(ctx, id, managed, reason)
+		*list = append(*list, id)
+	}
+
+	slog.Info("store rescan completed",
+		"component", "multistore",
+		"action", "store_rescan",
+		"stores", report.Stores,
+		"reloaded", len(report.Reloaded),
+		"removed", len(report.Removed),
+		"in_use", len(report.InUse),
+		"invalid", len(report.Invalid),
+	)
+	return report, nil
+}
+
+// storeDir is a store directory found on disk.
+type storeDir struct {
+	id, root string
+}
+
+// storeDirs returns all store directories under every root.
+func (m *StoreManager) storeDirs() ([]storeDir, error) {
+	var dirs []storeDir
+	for _, root := range m.Roots() {
+		ids, err := storeDirsUnder(root)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			dirs = append(dirs, storeDir{id: id, root: root})
+		}
+	}
+	return dirs, nil
+}
+
+// storeDirsUnder returns the IDs of all store directories under root.
+func storeDirsUnder(root string) ([]string, error) {
+	var ids []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || path == root {
+			return nil
+		}
+		// Dot directories hold internal state such as the trash
+		if strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if _, err := os.Stat(filepath.Join(path, "meta.yaml")); err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, filepath.ToSlash(rel))
+		// Stores do not nest
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan stores directory: %w", err)
+	}
+	return ids, nil
+}
+
+// checkStoreDir reports why the store in dir, with its archive at
+// archivePath, cannot be served, if it cannot: unreadable metadata or a
+// missing database or archive.
+func checkStoreDir(dir, archivePath string) error {
+	meta, err := LoadStoreMeta(filepath.Join(dir, "meta.yaml"))
+	if err != nil {
+		return err
+	}
+	if meta.IsArchived() {
+		if _, err := os.Stat(archivePath); err != nil && (meta.Archive == nil || !meta.Archive.Uploaded) {
+			return errors.New("archived store has no local or uploaded archive")
+		}
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, "engram.db")); err != nil {
+		return errors.New("engram.db is missing")
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package multistore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// writeBackupDB creates a standalone database holding the given lore, as a
+// backup restored into the store tree would be.
+func writeBackupDB(t *testing.T, contents ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "engram.db")
+	s, err := store.NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range contents {
+		if _, err := s.IngestLore(context.Background(), []types.NewLoreEntry{
+			{Content: c, Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "backup"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestStoreManager_RescanStores(t *testing.T) {
+	manager, rootPath := newTestStoreManager(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"restored", "removed", "busy"} {
+		if _, err := manager.CreateStore(ctx, id, "", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	busy, err := manager.AcquireStore(ctx, "busy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Release()
+
+	// Restore backups over two open stores and remove a third
+	for _, id := range []string{"restored", "busy"} {
+		if err := os.Rename(writeBackupDB(t, "Retries hid the outage", "Caches need TTLs"), filepath.Join(rootPath, id, "engram.db")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.RemoveAll(filepath.Join(rootPath, "removed")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Drop in a new store, one without a database and one with a bad ID
+	for _, id := range []string{"team/dropped", "broken", "Bad_Name"} {
+		dir := filepath.Join(rootPath, id)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := SaveStoreMeta(filepath.Join(dir, "meta.yaml"), NewStoreMeta("", "restored")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Rename(writeBackupDB(t, "Orders use event sourcing"), filepath.Join(rootPath, "team", "dropped", "engram.db")); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := manager.RescanStores(ctx)
+	if err != nil {
+		t.Fatalf("RescanStores() error = %v", err)
+	}
+	if report.Stores != 5 {
+		t.Errorf("Stores = %d, want 5", report.Stores)
+	}
+	if !slices.Equal(report.Reloaded, []string{"restored"}) {
+		t.Errorf("Reloaded = %v, want [restored]", report.Reloaded)
+	}
+	if !slices.Equal(report.Removed, []string{"removed"}) {
+		t.Errorf("Removed = %v, want [removed]", report.Removed)
+	}
+	if !slices.Equal(report.InUse, []string{"busy"}) {
+		t.Errorf("InUse = %v, want [busy]", report.InUse)
+	}
+	var invalid []string
+	for _, issue := range report.Invalid {
+		invalid = append(invalid, issue.StoreID)
+	}
+	slices.Sort(invalid)
+	if !slices.Equal(invalid, []string{"Bad_Name", "broken"}) {
+		t.Errorf("Invalid = %+v, want Bad_Name and broken", report.Invalid)
+	}
+
+	for id, want := range map[string]int64{"restored": 2, "team/dropped": 1} {
+		managed, err := manager.GetStore(ctx, id)
+		if err != nil {
+			t.Fatalf("GetStore(%q) error = %v", id, err)
+		}
+		stats, err := managed.Store.GetStats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.LoreCount != want {
+			t.Errorf("%s LoreCount = %d, want %d", id, stats.LoreCount, want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package multistore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/store"
+)
+
+// SnapshotSource lists and downloads the store snapshots kept in remote
+// storage. The S3 snapshot uploader implements it.
+type SnapshotSource interface {
+	ListSnapshots(ctx context.Context) ([]string, error)
+	DownloadSnapshot(ctx context.Context, storeID, filePath string) error
+}
+
+// RestoreFromSnapshots rebuilds the stores from their latest uploaded
+// snapshots when the root directory holds no stores, as when a container
+// starts on an empty disk. It returns the IDs of the stores restored, none
+// if the root already holds stores.
+//
+// Every snapshot is downloaded and prepared before any store is created,
+// so a failed download leaves the root empty and the restore is retried on
+// the next start. Snapshots carry the database only: restored stores get
+// the type recorded in the snapshot and default settings otherwise.
+func (m *StoreManager) RestoreFromSnapshots(ctx context.Context, src SnapshotSource) ([]string, error) {
+	existing, err := m.ListStores(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list local stores: %w", err)
+	}
+	if len(existing) > 0 {
+		return nil, nil
+	}
+
+	storeIDs, err := src.ListSnapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Snapshots are staged in the root of the store they restore, so each
+	// is moved into place by a rename even when roots are on other volumes
+	staging := make(map[string]string)
+	defer func() {
+		for _, dir := range staging {
+			os.RemoveAll(dir)
+		}
+	}()
+
+	type restored struct {
+		storeID, storeType, path string
+	}
+	var pending []restored
+	for i, storeID := range storeIDs {
+		if err := ValidateStoreID(storeID); err != nil {
+			slog.Warn("skipping snapshot with invalid store ID",
+				"component", "multistore",
+				"store_id", storeID,
+				"error", err,
+			)
+			continue
+		}
+		root := m.storeRoot(storeID)
+		if staging[root] == "" {
+			dir := filepath.Join(root, fmt.Sprintf(".restore-%d", time.Now().UnixNano()))
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return nil, fmt.Errorf("create restore directory: %w", err)
+			}
+			staging[root] = dir
+		}
+		path := filepath.Join(staging[root], fmt.Sprintf("%d.db", i))
+		if err := src.DownloadSnapshot(ctx, storeID, path); err != nil {
+			return nil, fmt.Errorf("restore store %q: %w", storeID, err)
+		}
+		storeType, err := store.PrepareRestoredSnapshot(ctx, path)
+		if err != nil {
+			return nil, fmt.Errorf("restore store %q: %w", storeID, err)
+		}
+		pending = append(pending, restored{storeID: storeID, storeType: storeType, path: path})
+	}
+
+	ids := make([]string, 0, len(pending))
+	for _, r := range pending {
+		meta := NewStoreMeta(r.storeType, "Restored from snapshot")
+		_, err := m.createStore(r.storeID, meta, func(dbPath string) error {
+			return os.Rename(r.path, dbPath)
+		})
+		if err != nil {
+			return ids, fmt.Errorf("restore store %q: %w", r.storeID, err)
+		}
+		ids = append(ids, r.storeID)
+	}
+
+	slog.Info("stores restored from snapshots",
+		"component", "multistore",
+		"action", "stores_restored",
+		"stores", len(ids),
+	)
+	return ids, nil
+}
@@ -0,0 +1,110 @@
+package multistore
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// fakeSnapshotSource serves snapshot files by store ID.
+type fakeSnapshotSource struct {
+	snapshots   map[string]string
+	downloadErr error
+	downloads   int
+}
+
+func (f *fakeSnapshotSource) ListSnapshots(ctx context.Context) ([]string, error) {
+	ids := make([]string, 0, len(f.snapshots))
+	for id := range f.snapshots {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (f *fakeSnapshotSource) DownloadSnapshot(ctx context.Context, storeID, filePath string) error {
+	f.downloads++
+	if f.downloadErr != nil {
+		return f.downloadErr
+	}
+	return copyFile(f.snapshots[storeID], filePath)
+}
+
+func TestStoreManager_RestoreFromSnapshots(t *testing.T) {
+	source, _ := newTestStoreManager(t)
+	ctx := context.Background()
+
+	managed, err := source.CreateStore(ctx, "org/project", "", "Project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := managed.Store.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Orders use event sourcing", Category: "ARCHITECTURAL_DECISION", Confidence: 0.9, SourceID: "s1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := managed.Store.GenerateSnapshot(ctx); err != nil {
+		t.Fatal(err)
+	}
+	path, err := managed.Store.GetSnapshotPath(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := &fakeSnapshotSource{snapshots: map[string]string{"org/project": path}}
+
+	manager, rootPath := newTestStoreManager(t)
+	restored, err := manager.RestoreFromSnapshots(ctx, src)
+	if err != nil {
+		t.Fatalf("RestoreFromSnapshots() error = %v", err)
+	}
+	if len(restored) != 1 || restored[0] != "org/project" {
+		t.Fatalf("restored = %v, want [org/project]", restored)
+	}
+
+	got, err := manager.GetStore(ctx, "org/project")
+	if err != nil {
+		t.Fatalf("GetStore() error = %v", err)
+	}
+	if got.Meta.Type != DefaultStoreType {
+		t.Errorf("restored type = %q, want %q", got.Meta.Type, DefaultStoreType)
+	}
+	results, err := got.Store.(*store.SQLiteStore).SearchLore(ctx, types.SearchQuery{
+		Text: "event sourcing", Mode: types.SearchModeKeyword, Limit: 5,
+	})
+	if err != nil || len(results) != 1 {
+		t.Errorf("keyword search on restored store = %d results, %v, want 1", len(results), err)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(rootPath, ".restore-*")); len(matches) != 0 {
+		t.Errorf("restore left staging files behind: %v", matches)
+	}
+
+	// A root that already holds stores is left alone
+	src.downloads = 0
+	restored, err = manager.RestoreFromSnapshots(ctx, src)
+	if err != nil || len(restored) != 0 || src.downloads != 0 {
+		t.Errorf("second restore = %v, %v after %d downloads, want nothing restored", restored, err, src.downloads)
+	}
+}
+
+func TestStoreManager_RestoreFromSnapshots_DownloadFails(t *testing.T) {
+	manager, _ := newTestStoreManager(t)
+	ctx := context.Background()
+	src := &fakeSnapshotSource{
+		snapshots:   map[string]string{"org/project": "unused"},
+		downloadErr: errors.New("connection reset"),
+	}
+
+	if _, err := manager.RestoreFromSnapshots(ctx, src); err == nil {
+		t.Fatal("RestoreFromSnapshots() error = nil, want the download error")
+	}
+	stores, err := manager.ListStores(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stores) != 0 {
+		t.Errorf("failed restore left %d stores, want none so the next start retries", len(stores))
+	}
+}
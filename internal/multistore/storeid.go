@@ -23,6 +23,9 @@ var (
 	ErrStoreNotFound = errors.New("store not found")
 	// ErrStoreAlreadyExists indicates a store already exists during creation.
 	ErrStoreAlreadyExists = errors.New("store already exists")
+	// ErrStoreDeleting indicates the store is being deleted and accepts no
+	// new requests.
+	ErrStoreDeleting = errors.New("store is being deleted")
 )
 
 // storeIDSegmentPattern matches a single valid segment.
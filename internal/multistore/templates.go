@@ -0,0 +1,92 @@
+package multistore
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/normalize"
+)
+
+// ErrUnknownTemplate indicates a store template name is not registered.
+var ErrUnknownTemplate = errors.New("unknown store template")
+
+// StoreTemplate seeds the metadata of a new store, so that stores created
+// for the same purpose start from the same settings.
+type StoreTemplate struct {
+	// Name identifies the template, e.g. "recall-default".
+	Name string
+	// Summary describes what the template is for.
+	Summary string
+	// Type is the store type the template creates.
+	Type          string
+	Normalization normalize.Options
+	Categories    []string
+	Deduplication DeduplicationPolicy
+	Retention     RetentionPolicy
+}
+
+// templates holds the built-in store templates by name.
+var templates = map[string]StoreTemplate{
+	"recall-default": {
+		Name:    "recall-default",
+		Summary: "Recall store with the server's default settings",
+		Type:    "recall",
+	},
+	"recall-strict-dedup": {
+		Name:    "recall-strict-dedup",
+		Summary: "Recall store that normalizes content and merges near-duplicates more eagerly",
+		Type:    "recall",
+		Normalization: normalize.Options{
+			UnicodeNFC:         true,
+			SmartQuotes:        true,
+			StripMarkdown:      true,
+			CollapseWhitespace: true,
+		},
+		Deduplication: DeduplicationPolicy{SimilarityThreshold: 0.85},
+		Retention:     RetentionPolicy{ChangeLog: 30 * 24 * time.Hour},
+	},
+	"recall-decisions": {
+		Name:       "recall-decisions",
+		Summary:    "Recall store for design records: architectural decisions and interface lessons only",
+		Type:       "recall",
+		Categories: []string{"ARCHITECTURAL_DECISION", "INTERFACE_LESSON"},
+		Retention:  RetentionPolicy{ChangeLog: 90 * 24 * time.Hour},
+	},
+	"tract": {
+		Name:    "tract",
+		Summary: "Tract store for goals, requirements and their implementation context",
+		Type:    "tract",
+	},
+}
+
+// Template returns the built-in template with the given name.
+func Template(name string) (StoreTemplate, error) {
+	t, ok := templates[name]
+	if !ok {
+		return StoreTemplate{}, fmt.Errorf("%w %q (valid: %s)", ErrUnknownTemplate, name, strings.Join(TemplateNames(), ", "))
+	}
+	return t, nil
+}
+
+// TemplateNames returns the names of the built-in templates, sorted.
+func TemplateNames() []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// apply copies the template's settings into meta.
+func (t StoreTemplate) apply(meta *StoreMeta) {
+	meta.Template = t.Name
+	meta.Type = t.Type
+	meta.Normalization = t.Normalization
+	meta.Categories = append([]string(nil), t.Categories...)
+	meta.Deduplication = t.Deduplication
+	meta.Retention = t.Retention
+}
@@ -0,0 +1,90 @@
+package multistore
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTemplate_Unknown(t *testing.T) {
+	_, err := Template("recall-nope")
+	if !errors.Is(err, ErrUnknownTemplate) {
+		t.Fatalf("Template() error = %v, want ErrUnknownTemplate", err)
+	}
+}
+
+func TestTemplateNames_Sorted(t *testing.T) {
+	names := TemplateNames()
+	if !slices.IsSorted(names) {
+		t.Errorf("TemplateNames() = %v, want sorted", names)
+	}
+	for _, want := range []string{"recall-default", "recall-strict-dedup", "tract"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("TemplateNames() = %v, missing %q", names, want)
+		}
+	}
+}
+
+func TestStoreManager_CreateStoreFromTemplate(t *testing.T) {
+	rootPath := filepath.Join(t.TempDir(), "stores")
+	manager, err := NewStoreManager(rootPath)
+	if err != nil {
+		t.Fatalf("NewStoreManager() error = %v", err)
+	}
+	defer manager.Close()
+
+	ctx := context.Background()
+	managed, err := manager.CreateStoreFromTemplate(ctx, "strict", "recall-strict-dedup", "Strict store")
+	if err != nil {
+		t.Fatalf("CreateStoreFromTemplate() error = %v", err)
+	}
+	if managed.Type() != "recall" || managed.Meta.Description != "Strict store" {
+		t.Errorf("type = %q, description = %q", managed.Type(), managed.Meta.Description)
+	}
+
+	// The seeded settings must survive a round trip through meta.yaml.
+	meta, err := LoadStoreMeta(filepath.Join(rootPath, "strict", "meta.yaml"))
+	if err != nil {
+		t.Fatalf("LoadStoreMeta() error = %v", err)
+	}
+	if meta.Template != "recall-strict-dedup" {
+		t.Errorf("Template = %q, want recall-strict-dedup", meta.Template)
+	}
+	if !meta.Normalization.StripMarkdown {
+		t.Error("Normalization.StripMarkdown = false, want true")
+	}
+	if meta.Deduplication.SimilarityThreshold != 0.85 {
+		t.Errorf("SimilarityThreshold = %v, want 0.85", meta.Deduplication.SimilarityThreshold)
+	}
+	if meta.Retention.ChangeLog != 30*24*time.Hour {
+		t.Errorf("Retention.ChangeLog = %v, want 720h", meta.Retention.ChangeLog)
+	}
+
+	stores, err := manager.ListStores(ctx)
+	if err != nil {
+		t.Fatalf("ListStores() error = %v", err)
+	}
+	if len(stores) != 1 || stores[0].Template != "recall-strict-dedup" || stores[0].Retention.ChangeLog != 30*24*time.Hour {
+		t.Errorf("ListStores() = %+v, want the template and retention", stores)
+	}
+}
+
+func TestStoreManager_CreateStoreFromTemplate_Unknown(t *testing.T) {
+	rootPath := filepath.Join(t.TempDir(), "stores")
+	manager, err := NewStoreManager(rootPath)
+	if err != nil {
+		t.Fatalf("NewStoreManager() error = %v", err)
+	}
+	defer manager.Close()
+
+	_, err = manager.CreateStoreFromTemplate(context.Background(), "s", "nope", "")
+	if !errors.Is(err, ErrUnknownTemplate) {
+		t.Fatalf("CreateStoreFromTemplate() error = %v, want ErrUnknownTemplate", err)
+	}
+	if _, err := manager.GetStore(context.Background(), "s"); !errors.Is(err, ErrStoreNotFound) {
+		t.Errorf("GetStore() error = %v, want ErrStoreNotFound", err)
+	}
+}
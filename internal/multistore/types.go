@@ -5,6 +5,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/hyperengineering/engram/internal/normalize"
 	"gopkg.in/yaml.v3"
 )
 
@@ -22,17 +23,56 @@ type StoreMeta struct {
 	LastAccessed time.Time `yaml:"last_accessed"`
 	// Description is an optional human-readable description.
 	Description string `yaml:"description,omitempty"`
+	// Normalization selects how ingested content is rewritten before it is
+	// embedded and deduplicated. Omitted means content is stored as sent.
+	Normalization normalize.Options `yaml:"normalization,omitempty"`
+	// Template names the template the store was created from, if any.
+	Template string `yaml:"template,omitempty"`
+	// ClonedFrom names the store this one was cloned from, if any.
+	ClonedFrom string `yaml:"cloned_from,omitempty"`
+	// State is the store's lifecycle state (StoreStateActive or
+	// StoreStateArchived). Omitted means active.
+	State string `yaml:"state,omitempty"`
+	// Archive describes the store's archive while it is archived.
+	Archive *ArchiveInfo `yaml:"archive,omitempty"`
+	// Categories lists the lore categories the store accepts at ingest.
+	// Omitted means every category is accepted.
+	Categories []string `yaml:"categories,omitempty"`
+	// Deduplication overrides the server's deduplication settings.
+	Deduplication DeduplicationPolicy `yaml:"deduplication,omitempty"`
+	// Retention overrides how long the store keeps history.
+	Retention RetentionPolicy `yaml:"retention,omitempty"`
+}
+
+// DeduplicationPolicy holds per-store deduplication settings.
+type DeduplicationPolicy struct {
+	// SimilarityThreshold is the cosine similarity at which an ingested
+	// entry merges into existing lore. Zero uses the server's setting.
+	SimilarityThreshold float64 `yaml:"similarity_threshold,omitempty" json:"similarity_threshold,omitempty"`
+}
+
+// RetentionPolicy holds per-store retention settings.
+type RetentionPolicy struct {
+	// ChangeLog is how long change_log entries are kept before compaction.
+	// Zero uses the server's compaction retention.
+	ChangeLog time.Duration `yaml:"change_log,omitempty" json:"change_log,omitempty"`
 }
 
 // StoreInfo contains summary information about a store.
 type StoreInfo struct {
-	ID            string    `json:"id"`
-	Type          string    `json:"type"`
-	SchemaVersion int       `json:"schema_version"`
-	Created       time.Time `json:"created"`
-	LastAccessed  time.Time `json:"last_accessed"`
-	Description   string    `json:"description,omitempty"`
-	SizeBytes     int64     `json:"size_bytes"`
+	ID            string       `json:"id"`
+	Type          string       `json:"type"`
+	SchemaVersion int          `json:"schema_version"`
+	Created       time.Time    `json:"created"`
+	LastAccessed  time.Time    `json:"last_accessed"`
+	Description   string       `json:"description,omitempty"`
+	Template      string       `json:"template,omitempty"`
+	State         string       `json:"state"`
+	Archive       *ArchiveInfo `json:"archive,omitempty"`
+	SizeBytes     int64        `json:"size_bytes"`
+	// Retention is the store's retention policy, used by the compaction
+	// worker.
+	Retention RetentionPolicy `json:"-"`
 }
 
 // NewStoreMeta creates metadata for a new store.
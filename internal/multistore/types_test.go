@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/hyperengineering/engram/internal/normalize"
 )
 
 func TestNewStoreMeta(t *testing.T) {
@@ -182,6 +184,26 @@ func TestLoadStoreMeta_WithType(t *testing.T) {
 	}
 }
 
+func TestLoadStoreMeta_WithNormalization(t *testing.T) {
+	tmpDir := t.TempDir()
+	metaPath := filepath.Join(tmpDir, "meta.yaml")
+
+	content := []byte("type: recall\ncreated: 2026-02-17T10:00:00Z\nnormalization:\n  unicode_nfc: true\n  collapse_whitespace: true\n")
+	if err := os.WriteFile(metaPath, content, 0644); err != nil {
+		t.Fatalf("failed to write meta.yaml: %v", err)
+	}
+
+	meta, err := LoadStoreMeta(metaPath)
+	if err != nil {
+		t.Fatalf("LoadStoreMeta failed: %v", err)
+	}
+
+	want := normalize.Options{UnicodeNFC: true, CollapseWhitespace: true}
+	if meta.Normalization != want {
+		t.Errorf("Normalization = %+v, want %+v", meta.Normalization, want)
+	}
+}
+
 func TestLoadStoreMeta_NoType_DefaultsToRecall(t *testing.T) {
 	tmpDir := t.TempDir()
 	metaPath := filepath.Join(tmpDir, "meta.yaml")
@@ -0,0 +1,85 @@
+// Package normalize rewrites lore content into a canonical form before it
+// is embedded, so formatting differences alone don't defeat deduplication.
+package normalize
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Options selects the normalization steps applied to ingested content.
+// The zero value leaves content untouched.
+type Options struct {
+	// UnicodeNFC composes characters to Unicode normalization form C, so
+	// precomposed and decomposed accents compare equal.
+	UnicodeNFC bool `yaml:"unicode_nfc,omitempty" json:"unicode_nfc,omitempty"`
+	// SmartQuotes replaces typographic quotes with their ASCII forms.
+	SmartQuotes bool `yaml:"smart_quotes,omitempty" json:"smart_quotes,omitempty"`
+	// StripMarkdown removes markdown markup (headings, emphasis, inline
+	// code, links, list and quote markers, code fences), keeping the text.
+	StripMarkdown bool `yaml:"strip_markdown,omitempty" json:"strip_markdown,omitempty"`
+	// CollapseWhitespace trims the content and replaces each run of
+	// whitespace, including newlines, with a single space.
+	CollapseWhitespace bool `yaml:"collapse_whitespace,omitempty" json:"collapse_whitespace,omitempty"`
+}
+
+// Enabled reports whether any step is selected.
+func (o Options) Enabled() bool {
+	return o.UnicodeNFC || o.SmartQuotes || o.StripMarkdown || o.CollapseWhitespace
+}
+
+// Apply returns text with the selected steps applied. Markdown is stripped
+// before whitespace is collapsed because its markers are line-oriented.
+func (o Options) Apply(text string) string {
+	if o.UnicodeNFC {
+		text = norm.NFC.String(text)
+	}
+	if o.SmartQuotes {
+		text = quoteReplacer.Replace(text)
+	}
+	if o.StripMarkdown {
+		text = stripMarkdown(text)
+	}
+	if o.CollapseWhitespace {
+		text = strings.Join(strings.Fields(text), " ")
+	}
+	return text
+}
+
+var quoteReplacer = strings.NewReplacer(
+	"‘", "'", "’", "'", "‚", "'", "‛", "'", "′", "'",
+	"“", `"`, "”", `"`, "„", `"`, "‟", `"`, "″", `"`,
+	"«", `"`, "»", `"`,
+)
+
+var (
+	mdFence      = regexp.MustCompile("(?m)^[ \t]*(```|~~~).*$")
+	mdHeading    = regexp.MustCompile(`(?m)^[ \t]*#{1,6}[ \t]+`)
+	mdQuote      = regexp.MustCompile(`(?m)^[ \t]*(>[ \t]?)+`)
+	mdListItem   = regexp.MustCompile(`(?m)^([ \t]*)([-*+]|\d+[.)])[ \t]+`)
+	mdRule       = regexp.MustCompile(`(?m)^[ \t]*([-*_][ \t]*){3,}$`)
+	mdImage      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink       = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	mdInlineCode = regexp.MustCompile("`([^`]+)`")
+	mdStrong     = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*`)
+	mdEmphasis   = regexp.MustCompile(`(^|[^\w*])\*(\S(?:[^*]*?\S)?)\*`)
+)
+
+// stripMarkdown removes common markdown markup. Underscore emphasis is left
+// alone because lore routinely names identifiers such as __init__ or
+// snake_case.
+func stripMarkdown(text string) string {
+	text = mdFence.ReplaceAllString(text, "")
+	text = mdRule.ReplaceAllString(text, "")
+	text = mdHeading.ReplaceAllString(text, "")
+	text = mdQuote.ReplaceAllString(text, "")
+	text = mdListItem.ReplaceAllString(text, "$1")
+	text = mdImage.ReplaceAllString(text, "$1")
+	text = mdLink.ReplaceAllString(text, "$1")
+	text = mdInlineCode.ReplaceAllString(text, "$1")
+	text = mdStrong.ReplaceAllString(text, "$1")
+	text = mdEmphasis.ReplaceAllString(text, "$1$2")
+	return text
+}
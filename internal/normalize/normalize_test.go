@@ -0,0 +1,74 @@
+package normalize
+
+import "testing"
+
+func TestOptions_Apply(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		in   string
+		want string
+	}{
+		{
+			name: "zero value is a no-op",
+			in:   "  **Keep**  “as is”\n",
+			want: "  **Keep**  “as is”\n",
+		},
+		{
+			name: "collapse whitespace",
+			opts: Options{CollapseWhitespace: true},
+			in:   "  Retry\twith\n\n backoff  ",
+			want: "Retry with backoff",
+		},
+		{
+			name: "unicode NFC",
+			opts: Options{UnicodeNFC: true},
+			in:   "cafe\u0301",
+			want: "caf\u00e9",
+		},
+		{
+			name: "smart quotes",
+			opts: Options{SmartQuotes: true},
+			in:   "“Don’t” ‘retry’",
+			want: `"Don't" 'retry'`,
+		},
+		{
+			name: "strip markdown",
+			opts: Options{StripMarkdown: true, CollapseWhitespace: true},
+			in: "## Decision\n\n" +
+				"> Use **exponential** backoff, *not* linear.\n\n" +
+				"- See [the RFC](https://example.com/rfc)\n" +
+				"1. Call `retry()` from `__init__`\n" +
+				"```go\n" +
+				"snake_case_name := 2 * 3 * 4\n" +
+				"```\n" +
+				"---\n",
+			want: "Decision Use exponential backoff, not linear. See the RFC Call retry() from __init__ snake_case_name := 2 * 3 * 4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.Apply(tt.in); got != tt.want {
+				t.Errorf("Apply(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptions_ApplyMakesFormattingsEqual(t *testing.T) {
+	opts := Options{UnicodeNFC: true, SmartQuotes: true, StripMarkdown: true, CollapseWhitespace: true}
+	a := opts.Apply("**Don’t** cache   the session token.")
+	b := opts.Apply("Don't cache the\nsession token.")
+	if a != b {
+		t.Errorf("normalized forms differ: %q vs %q", a, b)
+	}
+}
+
+func TestOptions_Enabled(t *testing.T) {
+	if (Options{}).Enabled() {
+		t.Error("zero Options should not be enabled")
+	}
+	if !(Options{SmartQuotes: true}).Enabled() {
+		t.Error("Options with a step should be enabled")
+	}
+}
@@ -14,6 +14,9 @@ var (
 
 	// ErrInvalidPayload indicates the payload JSON is malformed.
 	ErrInvalidPayload = errors.New("invalid payload")
+
+	// ErrVetoed indicates a plugin hook rejected the operation.
+	ErrVetoed = errors.New("vetoed by plugin")
 )
 
 // ValidationError provides details about a validation failure.
@@ -22,7 +25,10 @@ type ValidationError struct {
 	TableName string `json:"table_name"`
 	EntityID  string `json:"entity_id"`
 	Field     string `json:"field,omitempty"`
-	Message   string `json:"message"`
+	// Constraint names the payload schema keyword that failed, such as
+	// "required" or "enum", for errors raised by ValidatePayloads.
+	Constraint string `json:"constraint,omitempty"`
+	Message    string `json:"message"`
 }
 
 // Error implements the error interface.
@@ -50,3 +56,17 @@ func (e ValidationErrors) Error() string {
 func (e ValidationErrors) Unwrap() error {
 	return ErrValidationFailed
 }
+
+// MissingParentsError reports entries that reference parent entities
+// present neither in the pushed batch nor in the store.
+type MissingParentsError struct {
+	ValidationErrors
+
+	// Missing maps each parent table to the sorted IDs that were not found.
+	Missing map[string][]string
+}
+
+// Unwrap returns ErrValidationFailed for errors.Is() compatibility.
+func (e *MissingParentsError) Unwrap() error {
+	return ErrValidationFailed
+}
@@ -0,0 +1,179 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperengineering/engram/internal/sync"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// The hook interfaces below are optional extensions to DomainPlugin.
+// A plugin opts in by implementing any subset of them; the store checks
+// for each hook with a type assertion, so plugins that only declare
+// schemas and validation rules are unaffected. BeforeIngest and
+// BeforeDelete also run on lore_entries changes pushed through sync; see
+// RunPushHooks.
+
+// BeforeIngestHook lets a plugin enrich, normalize, or veto an entry
+// before it is embedded and written.
+//
+// The entry may be modified in place. Returning an error rejects the
+// entry; the error message is reported back to the client and the
+// remaining entries in the batch are still ingested. Implementations
+// should wrap ErrVetoed so callers can distinguish a policy rejection
+// from an unexpected failure.
+type BeforeIngestHook interface {
+	BeforeIngest(ctx context.Context, entry *types.NewLoreEntry) error
+}
+
+// AfterMergeHook is notified after an incoming entry has been merged into
+// an existing entry by deduplication. It runs after the ingest transaction
+// commits, so it cannot affect the stored result.
+type AfterMergeHook interface {
+	AfterMerge(ctx context.Context, merged *types.LoreEntry, incoming types.NewLoreEntry)
+}
+
+// BeforeDeleteHook lets a plugin veto the deletion of an entry.
+// Returning an error aborts the delete; wrap ErrVetoed for policy rejections.
+type BeforeDeleteHook interface {
+	BeforeDelete(ctx context.Context, id string) error
+}
+
+// EntityLookup reports which entities currently exist in a store. It is
+// passed to ReferenceValidator so plugins can see data from earlier pushes.
+type EntityLookup interface {
+	// ExistingEntityIDs returns the subset of ids in tableName whose latest
+	// change is a live (not deleted) upsert.
+	ExistingEntityIDs(ctx context.Context, tableName string, ids []string) (map[string]bool, error)
+}
+
+// ReferenceValidator checks that pushed entries reference parents that
+// exist either in the same batch or in the store. It runs after
+// ValidatePush and receives the entries in replay order.
+//
+// Returning a *MissingParentsError rejects the push with the missing IDs
+// listed; clients that intend to send parents in a later push can opt out
+// with allow_deferred_parents.
+type ReferenceValidator interface {
+	ValidateReferences(ctx context.Context, lookup EntityLookup, entries []sync.ChangeLogEntry) error
+}
+
+// LatestEntryReader exposes the most recent change_log entry per entity,
+// which is the current state for plugins that do not replay into tables.
+type LatestEntryReader interface {
+	GetLatestEntries(ctx context.Context, tableNames []string) ([]sync.ChangeLogEntry, error)
+}
+
+// CascadeExpander adds delete entries for the dependents of entities
+// deleted in a push. It is only invoked when the push opts in with
+// cascade_deletes, and runs after ValidatePush. The returned entries
+// replace the batch and must already be in replay order.
+type CascadeExpander interface {
+	ExpandCascade(ctx context.Context, reader LatestEntryReader, entries []sync.ChangeLogEntry) ([]sync.ChangeLogEntry, error)
+}
+
+// ClientMigrationProvider supplies forward migrations for clients' local
+// replicas, keyed by sync schema version. When a client pushes or
+// negotiates with an older schema_version, the server returns the
+// migrations above the client's version so it can upgrade itself.
+type ClientMigrationProvider interface {
+	ClientMigrations() []sync.ClientMigration
+}
+
+// RunPushHooks passes the lore_entries entries of a push through p's
+// BeforeIngest and BeforeDelete hooks, so a push cannot go around a veto
+// that the ingest and delete endpoints enforce. Upsert payloads are
+// rewritten with any changes BeforeIngest makes; the entries passed in are
+// never modified. Entries rejected by a hook are returned as
+// ValidationErrors.
+func RunPushHooks(ctx context.Context, p DomainPlugin, entries []sync.ChangeLogEntry) ([]sync.ChangeLogEntry, error) {
+	ingest, hasIngest := p.(BeforeIngestHook)
+	del, hasDelete := p.(BeforeDeleteHook)
+	if !hasIngest && !hasDelete {
+		return entries, nil
+	}
+
+	var validationErrors []ValidationError
+	reject := func(entry sync.ChangeLogEntry, err error) {
+		validationErrors = append(validationErrors, ValidationError{
+			Sequence:  entry.Sequence,
+			TableName: entry.TableName,
+			EntityID:  entry.EntityID,
+			Message:   err.Error(),
+		})
+	}
+
+	out := make([]sync.ChangeLogEntry, len(entries))
+	copy(out, entries)
+	for i, entry := range out {
+		if entry.TableName != "lore_entries" {
+			continue
+		}
+		switch {
+		case entry.Operation == sync.OperationUpsert && hasIngest:
+			payload, err := beforeIngestPayload(ctx, ingest, entry.Payload)
+			if err != nil {
+				reject(entry, err)
+				continue
+			}
+			out[i].Payload = payload
+		case entry.Operation == sync.OperationDelete && hasDelete:
+			if err := del.BeforeDelete(ctx, entry.EntityID); err != nil {
+				reject(entry, err)
+			}
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, ValidationErrors{Errors: validationErrors}
+	}
+	return out, nil
+}
+
+// beforeIngestPayload runs h on the entry held in a lore_entries payload
+// and returns the payload with the hook's changes applied. Only the fields
+// the hook changed are rewritten; everything else, including fields the
+// hook does not see such as timestamps, is kept exactly as sent.
+func beforeIngestPayload(ctx context.Context, h BeforeIngestHook, payload json.RawMessage) (json.RawMessage, error) {
+	var entry types.NewLoreEntry
+	if err := json.Unmarshal(payload, &entry); err != nil {
+		return nil, fmt.Errorf("invalid payload JSON: %w", err)
+	}
+	original := entry
+	if err := h.BeforeIngest(ctx, &entry); err != nil {
+		return nil, err
+	}
+	if entry == original {
+		return payload, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, fmt.Errorf("invalid payload JSON: %w", err)
+	}
+	was := ingestFields(original)
+	for name, value := range ingestFields(entry) {
+		if value != was[name] {
+			fields[name], _ = json.Marshal(value)
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// ingestFields maps the payload keys a BeforeIngest hook can change to
+// their values in e.
+func ingestFields(e types.NewLoreEntry) map[string]any {
+	return map[string]any{
+		"id":         e.ID,
+		"content":    e.Content,
+		"context":    e.Context,
+		"category":   e.Category,
+		"confidence": e.Confidence,
+		"source_id":  e.SourceID,
+		"language":   e.Language,
+		"repo":       e.Repo,
+		"path":       e.Path,
+	}
+}
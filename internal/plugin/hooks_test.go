@@ -0,0 +1,125 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// hookPlugin uppercases content on ingest and vetoes content and deletes
+// of entities named "keep".
+type hookPlugin struct {
+	stubPlugin
+}
+
+func (h *hookPlugin) BeforeIngest(_ context.Context, entry *types.NewLoreEntry) error {
+	if entry.Content == "keep" {
+		return fmt.Errorf("%w: reserved content", ErrVetoed)
+	}
+	entry.Content = strings.ToUpper(entry.Content)
+	return nil
+}
+
+func (h *hookPlugin) BeforeDelete(_ context.Context, id string) error {
+	if id == "keep" {
+		return fmt.Errorf("%w: protected entry", ErrVetoed)
+	}
+	return nil
+}
+
+func TestRunPushHooks(t *testing.T) {
+	p := &hookPlugin{stubPlugin{typeName: "hooks"}}
+	entries := []engramsync.ChangeLogEntry{
+		{Sequence: 1, TableName: "lore_entries", EntityID: "L-1", Operation: engramsync.OperationUpsert,
+			Payload: json.RawMessage(`{"id":"L-1","content":"hello","created_at":"2026-01-01T00:00:00Z","embedding":[0.1]}`)},
+		{Sequence: 2, TableName: "lore_entries", EntityID: "L-2", Operation: engramsync.OperationDelete},
+		{Sequence: 3, TableName: "notes", EntityID: "keep", Operation: engramsync.OperationDelete},
+	}
+
+	got, err := RunPushHooks(context.Background(), p, entries)
+	if err != nil {
+		t.Fatalf("RunPushHooks() error = %v", err)
+	}
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(got[0].Payload, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if string(payload["content"]) != `"HELLO"` {
+		t.Errorf("content = %s, want \"HELLO\"", payload["content"])
+	}
+	if string(payload["created_at"]) != `"2026-01-01T00:00:00Z"` || string(payload["embedding"]) != `[0.1]` {
+		t.Errorf("untouched fields changed: %s", got[0].Payload)
+	}
+	if !strings.Contains(string(entries[0].Payload), `"hello"`) {
+		t.Errorf("caller's entry was modified: %s", entries[0].Payload)
+	}
+	if len(got) != len(entries) {
+		t.Errorf("len = %d, want %d", len(got), len(entries))
+	}
+}
+
+func TestRunPushHooks_WritesOnlyChangedFields(t *testing.T) {
+	p := &hookPlugin{stubPlugin{typeName: "hooks"}}
+	entries := []engramsync.ChangeLogEntry{
+		{Sequence: 1, TableName: "lore_entries", EntityID: "L-1", Operation: engramsync.OperationUpsert,
+			Payload: json.RawMessage(`{"id":"L-1","content":"hello","confidence":0.70}`)},
+	}
+
+	got, err := RunPushHooks(context.Background(), p, entries)
+	if err != nil {
+		t.Fatalf("RunPushHooks() error = %v", err)
+	}
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(got[0].Payload, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if len(payload) != 3 {
+		t.Errorf("payload keys = %d, want 3 (unsent keys added): %s", len(payload), got[0].Payload)
+	}
+	if string(payload["confidence"]) != `0.70` {
+		t.Errorf("confidence = %s, want 0.70 as sent", payload["confidence"])
+	}
+	if string(payload["content"]) != `"HELLO"` {
+		t.Errorf("content = %s, want \"HELLO\"", payload["content"])
+	}
+}
+
+func TestRunPushHooks_Vetoes(t *testing.T) {
+	p := &hookPlugin{stubPlugin{typeName: "hooks"}}
+	entries := []engramsync.ChangeLogEntry{
+		{Sequence: 1, TableName: "lore_entries", EntityID: "L-1", Operation: engramsync.OperationUpsert,
+			Payload: json.RawMessage(`{"id":"L-1","content":"keep"}`)},
+		{Sequence: 2, TableName: "lore_entries", EntityID: "keep", Operation: engramsync.OperationDelete},
+		{Sequence: 3, TableName: "lore_entries", EntityID: "L-3", Operation: engramsync.OperationDelete},
+	}
+
+	_, err := RunPushHooks(context.Background(), p, entries)
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("RunPushHooks() error = %v, want ValidationErrors", err)
+	}
+	if len(verrs.Errors) != 2 {
+		t.Fatalf("got %d errors, want 2: %v", len(verrs.Errors), verrs.Errors)
+	}
+	for i, want := range []int64{1, 2} {
+		if verrs.Errors[i].Sequence != want || !strings.Contains(verrs.Errors[i].Message, ErrVetoed.Error()) {
+			t.Errorf("Errors[%d] = %+v, want a veto of sequence %d", i, verrs.Errors[i], want)
+		}
+	}
+}
+
+func TestRunPushHooks_NoHooks(t *testing.T) {
+	entries := []engramsync.ChangeLogEntry{
+		{Sequence: 1, TableName: "lore_entries", EntityID: "keep", Operation: engramsync.OperationDelete},
+	}
+	got, err := RunPushHooks(context.Background(), &stubPlugin{typeName: "plain"}, entries)
+	if err != nil || len(got) != 1 {
+		t.Errorf("RunPushHooks() = %v, %v; want entries unchanged", got, err)
+	}
+}
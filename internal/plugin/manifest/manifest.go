@@ -0,0 +1,391 @@
+// Package manifest loads domain plugins declared on disk rather than compiled
+// into the binary. Each plugin lives in its own directory under the plugins
+// root:
+//
+//	plugins/
+//	  notes/
+//	    plugin.yaml          # type, tables, validation rules
+//	    schemas/notes.json   # optional payload JSON Schema
+//	    migrations/
+//	      100_create_notes.sql
+//
+// Migration files use goose-style "-- +goose Up" / "-- +goose Down" markers.
+// The file name prefix is the migration version.
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/hyperengineering/engram/internal/jsonschema"
+	"github.com/hyperengineering/engram/internal/plugin"
+	"github.com/hyperengineering/engram/migrations"
+)
+
+// ManifestFile is the file name expected in each plugin directory.
+const ManifestFile = "plugin.yaml"
+
+// ErrInvalidManifest indicates a manifest failed structural validation.
+var ErrInvalidManifest = errors.New("invalid plugin manifest")
+
+var (
+	typeNameRegex  = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+	tableNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	columnRegex    = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+	migrationRegex = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.sql$`)
+)
+
+// FieldTypes lists the accepted values for TableManifest.Fields.
+var FieldTypes = []string{"string", "number", "integer", "boolean", "object", "array"}
+
+// Manifest is the on-disk description of an external domain plugin.
+type Manifest struct {
+	// Type is the store type this plugin handles.
+	Type string `yaml:"type"`
+
+	// Description is a human-readable summary of the store type.
+	Description string `yaml:"description"`
+
+	// Tables is the whitelist of tables clients may sync.
+	Tables []TableManifest `yaml:"tables"`
+}
+
+// TableManifest declares one syncable table and its validation rules.
+type TableManifest struct {
+	// Name is the SQL table name (must match a migration CREATE TABLE).
+	Name string `yaml:"name"`
+
+	// Columns lists the table's columns; must include "id".
+	Columns []string `yaml:"columns"`
+
+	// SoftDelete selects soft (deleted_at) or hard delete on replay.
+	SoftDelete bool `yaml:"soft_delete"`
+
+	// Required lists payload fields that must be present and non-null on upsert.
+	Required []string `yaml:"required"`
+
+	// Fields maps payload field names to their expected JSON type.
+	// Fields not listed are accepted without type checks.
+	Fields map[string]string `yaml:"fields"`
+
+	// PayloadSchema is the path, relative to the plugin directory, of a
+	// JSON Schema file for the table's upsert payloads. It replaces the
+	// schema otherwise generated from Required and Fields.
+	PayloadSchema string `yaml:"payload_schema"`
+
+	// SyncPolicy limits what clients may push to the table: one of
+	// client_writable (the default), append_only, read_only or server_only.
+	SyncPolicy plugin.SyncPolicy `yaml:"sync_policy"`
+}
+
+// LoadDir loads every plugin found in immediate subdirectories of dir.
+// Subdirectories without a plugin.yaml are ignored. A missing dir is not
+// an error and yields no plugins.
+func LoadDir(dir string) ([]*Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read plugins directory: %w", err)
+	}
+
+	var plugins []*Plugin
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		pluginDir := filepath.Join(dir, e.Name())
+		if _, err := os.Stat(filepath.Join(pluginDir, ManifestFile)); os.IsNotExist(err) {
+			continue
+		}
+		p, err := Load(pluginDir)
+		if err != nil {
+			return nil, fmt.Errorf("load plugin %q: %w", e.Name(), err)
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins, nil
+}
+
+// Load reads and validates a single plugin directory.
+func Load(dir string) (*Plugin, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	migs, err := loadMigrations(filepath.Join(dir, "migrations"))
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := loadPayloadSchemas(dir, m.Tables)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPlugin(m, migs, schemas), nil
+}
+
+// validate checks the manifest for structural errors.
+func (m *Manifest) validate() error {
+	if !typeNameRegex.MatchString(m.Type) {
+		return fmt.Errorf("%w: type %q must match %s", ErrInvalidManifest, m.Type, typeNameRegex)
+	}
+	if len(m.Tables) == 0 {
+		return fmt.Errorf("%w: at least one table is required", ErrInvalidManifest)
+	}
+
+	seen := make(map[string]bool)
+	for _, t := range m.Tables {
+		if !tableNameRegex.MatchString(t.Name) {
+			return fmt.Errorf("%w: table name %q must match %s", ErrInvalidManifest, t.Name, tableNameRegex)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("%w: duplicate table %q", ErrInvalidManifest, t.Name)
+		}
+		seen[t.Name] = true
+
+		hasID := false
+		for _, c := range t.Columns {
+			if !columnRegex.MatchString(c) {
+				return fmt.Errorf("%w: table %q column %q must match %s", ErrInvalidManifest, t.Name, c, columnRegex)
+			}
+			if c == "id" {
+				hasID = true
+			}
+		}
+		if !hasID {
+			return fmt.Errorf("%w: table %q must declare an \"id\" column", ErrInvalidManifest, t.Name)
+		}
+		if !t.SyncPolicy.Valid() {
+			return fmt.Errorf("%w: table %q has unknown sync_policy %q", ErrInvalidManifest, t.Name, t.SyncPolicy)
+		}
+		for field, typ := range t.Fields {
+			if !isFieldType(typ) {
+				return fmt.Errorf("%w: table %q field %q has unknown type %q (want one of %s)",
+					ErrInvalidManifest, t.Name, field, typ, strings.Join(FieldTypes, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// loadPayloadSchemas returns each table's payload schema: the file named
+// by payload_schema, or one generated from required and fields. Tables
+// with neither have no schema.
+func loadPayloadSchemas(dir string, tables []TableManifest) ([]plugin.PayloadSchema, error) {
+	var schemas []plugin.PayloadSchema
+	for _, t := range tables {
+		var doc []byte
+		if t.PayloadSchema != "" {
+			path := filepath.Join(dir, filepath.Clean(t.PayloadSchema))
+			if rel, err := filepath.Rel(dir, path); err != nil || strings.HasPrefix(rel, "..") {
+				return nil, fmt.Errorf("%w: table %q payload_schema must be inside the plugin directory", ErrInvalidManifest, t.Name)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("read payload schema for table %q: %w", t.Name, err)
+			}
+			doc = data
+		} else if doc = generatePayloadSchema(t); doc == nil {
+			continue
+		}
+
+		if _, err := jsonschema.Compile(doc); err != nil {
+			return nil, fmt.Errorf("%w: table %q payload schema: %v", ErrInvalidManifest, t.Name, err)
+		}
+		schemas = append(schemas, plugin.PayloadSchema{Table: t.Name, Schema: doc})
+	}
+	return schemas, nil
+}
+
+// generatePayloadSchema expresses a table's required and fields rules as
+// a JSON Schema. Required fields must be non-null; other typed fields
+// may be null. Returns nil if the table declares no rules.
+func generatePayloadSchema(t TableManifest) json.RawMessage {
+	if len(t.Required) == 0 && len(t.Fields) == 0 {
+		return nil
+	}
+
+	properties := make(map[string]interface{})
+	for field, typ := range t.Fields {
+		properties[field] = map[string]interface{}{"type": []string{typ, "null"}}
+	}
+	for _, field := range t.Required {
+		if typ, ok := t.Fields[field]; ok {
+			properties[field] = map[string]interface{}{"type": typ}
+		} else {
+			properties[field] = map[string]interface{}{"type": FieldTypes}
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(t.Required) > 0 {
+		schema["required"] = t.Required
+	}
+	doc, _ := json.Marshal(schema)
+	return doc
+}
+
+func isFieldType(t string) bool {
+	for _, ft := range FieldTypes {
+		if t == ft {
+			return true
+		}
+	}
+	return false
+}
+
+// loadMigrations reads NNN_name.sql files from dir in version order.
+// A missing directory yields no migrations.
+func loadMigrations(dir string) ([]plugin.Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read migrations directory: %w", err)
+	}
+
+	var migs []plugin.Migration
+	versions := make(map[int]string)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+		match := migrationRegex.FindStringSubmatch(e.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: migration file %q must be named NNN_name.sql", ErrInvalidManifest, e.Name())
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%w: migration file %q has invalid version", ErrInvalidManifest, e.Name())
+		}
+		if prev, dup := versions[version]; dup {
+			return nil, fmt.Errorf("%w: migration version %d used by %q and %q", ErrInvalidManifest, version, prev, e.Name())
+		}
+		versions[version] = e.Name()
+
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %q: %w", e.Name(), err)
+		}
+		up, down := splitMigration(string(data))
+		if strings.TrimSpace(up) == "" {
+			return nil, fmt.Errorf("%w: migration %q has no Up section", ErrInvalidManifest, e.Name())
+		}
+		migs = append(migs, plugin.Migration{
+			Version: version,
+			Name:    match[2],
+			UpSQL:   up,
+			DownSQL: down,
+		})
+	}
+
+	sort.Slice(migs, func(i, j int) bool { return migs[i].Version < migs[j].Version })
+	return migs, nil
+}
+
+// splitMigration separates the Up and Down sections of a goose-style file.
+// A file without markers is treated as Up-only.
+func splitMigration(sql string) (up, down string) {
+	const upMarker, downMarker = "-- +goose Up", "-- +goose Down"
+
+	upIdx := strings.Index(sql, upMarker)
+	downIdx := strings.Index(sql, downMarker)
+	switch {
+	case upIdx < 0 && downIdx < 0:
+		return sql, ""
+	case downIdx < 0:
+		return sql[upIdx+len(upMarker):], ""
+	case upIdx < 0:
+		return sql[:downIdx], sql[downIdx+len(downMarker):]
+	case downIdx < upIdx:
+		return sql[upIdx+len(upMarker):], sql[downIdx+len(downMarker) : upIdx]
+	default:
+		return sql[upIdx+len(upMarker) : downIdx], sql[downIdx+len(downMarker):]
+	}
+}
+
+// reservedTables are base-schema tables a manifest may not claim: every
+// table the core migrations create, and the migration bookkeeping tables.
+var reservedTables = baseTables()
+
+// createTablePattern matches the table created by a CREATE TABLE or
+// CREATE VIRTUAL TABLE statement.
+var createTablePattern = regexp.MustCompile(`(?i)CREATE\s+(VIRTUAL\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?["'\x60\[]?(\w+)`)
+
+// ftsShadowSuffixes name the tables SQLite creates behind an FTS5 table.
+var ftsShadowSuffixes = []string{"_data", "_idx", "_content", "_docsize", "_config"}
+
+// baseTables collects the tables created by the core migrations.
+func baseTables() map[string]bool {
+	tables := map[string]bool{
+		"plugin_migrations": true,
+		"goose_db_version":  true,
+	}
+	files, err := fs.Glob(migrations.FS, "*.sql")
+	if err != nil {
+		panic(fmt.Sprintf("manifest: list core migrations: %v", err))
+	}
+	for _, file := range files {
+		script, err := migrations.FS.ReadFile(file)
+		if err != nil {
+			panic(fmt.Sprintf("manifest: read core migration %s: %v", file, err))
+		}
+		up, _ := splitMigration(string(script))
+		for _, match := range createTablePattern.FindAllStringSubmatch(up, -1) {
+			name := strings.ToLower(match[2])
+			tables[name] = true
+			if match[1] != "" {
+				for _, suffix := range ftsShadowSuffixes {
+					tables[name+suffix] = true
+				}
+			}
+		}
+	}
+	return tables
+}
+
+// Register adds p to the plugin registry after checking that neither its
+// type nor any of its tables collide with an already-registered plugin or
+// the base schema. Unlike plugin.Register, conflicts are returned as errors
+// because they come from operator-supplied files rather than code.
+func Register(p *Plugin) error {
+	if _, exists := plugin.Get(p.Type()); exists {
+		return fmt.Errorf("%w: store type %q is already registered", ErrInvalidManifest, p.Type())
+	}
+	for _, t := range p.manifest.Tables {
+		if reservedTables[strings.ToLower(t.Name)] {
+			return fmt.Errorf("%w: table %q is reserved", ErrInvalidManifest, t.Name)
+		}
+		if _, exists := plugin.GetTableSchema(t.Name); exists {
+			return fmt.Errorf("%w: table %q is already declared by another plugin", ErrInvalidManifest, t.Name)
+		}
+	}
+	plugin.Register(p)
+	return nil
+}
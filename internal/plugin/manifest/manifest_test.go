@@ -0,0 +1,423 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/plugin"
+	"github.com/hyperengineering/engram/internal/store"
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+const notesManifest = `
+type: notes
+description: Free-form notes
+tables:
+  - name: notes
+    columns: [id, title, body, priority, created_at, updated_at, deleted_at]
+    soft_delete: true
+    required: [title]
+    fields:
+      title: string
+      priority: integer
+`
+
+const notesMigration = `-- +goose Up
+CREATE TABLE notes (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL,
+    body TEXT,
+    priority INTEGER,
+    created_at TEXT,
+    updated_at TEXT,
+    deleted_at TEXT
+);
+
+-- +goose Down
+DROP TABLE notes;
+`
+
+// writePluginDir creates a plugin directory with the given manifest and migrations.
+func writePluginDir(t *testing.T, root, name, manifest string, migrations map[string]string) string {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Join(dir, "migrations"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for file, sql := range migrations {
+		if err := os.WriteFile(filepath.Join(dir, "migrations", file), []byte(sql), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func loadNotes(t *testing.T) *Plugin {
+	t.Helper()
+	dir := writePluginDir(t, t.TempDir(), "notes", notesManifest,
+		map[string]string{"100_create_notes.sql": notesMigration})
+	p, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return p
+}
+
+func TestLoad_ValidManifest(t *testing.T) {
+	p := loadNotes(t)
+
+	if p.Type() != "notes" {
+		t.Errorf("Type() = %q, want notes", p.Type())
+	}
+
+	migs := p.Migrations()
+	if len(migs) != 1 {
+		t.Fatalf("Migrations() len = %d, want 1", len(migs))
+	}
+	if migs[0].Version != 100 || migs[0].Name != "create_notes" {
+		t.Errorf("migration = %d/%s, want 100/create_notes", migs[0].Version, migs[0].Name)
+	}
+	if !strings.Contains(migs[0].UpSQL, "CREATE TABLE notes") || strings.Contains(migs[0].UpSQL, "DROP TABLE") {
+		t.Errorf("UpSQL not split correctly: %q", migs[0].UpSQL)
+	}
+	if !strings.Contains(migs[0].DownSQL, "DROP TABLE notes") {
+		t.Errorf("DownSQL = %q, want DROP TABLE", migs[0].DownSQL)
+	}
+
+	schemas := p.TableSchemas()
+	if len(schemas) != 1 || schemas[0].Name != "notes" || !schemas[0].SoftDelete {
+		t.Errorf("TableSchemas() = %+v", schemas)
+	}
+}
+
+func TestLoad_InvalidManifests(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     string
+	}{
+		{"bad type", "type: Notes!\ntables: [{name: notes, columns: [id]}]", "type"},
+		{"no tables", "type: notes\n", "at least one table"},
+		{"bad table name", "type: notes\ntables: [{name: \"drop;\", columns: [id]}]", "table name"},
+		{"missing id", "type: notes\ntables: [{name: notes, columns: [title]}]", "\"id\" column"},
+		{"bad column", "type: notes\ntables: [{name: notes, columns: [id, \"a b\"]}]", "column"},
+		{"duplicate table", "type: notes\ntables: [{name: notes, columns: [id]}, {name: notes, columns: [id]}]", "duplicate"},
+		{"unknown field type", "type: notes\ntables: [{name: notes, columns: [id], fields: {title: text}}]", "unknown type"},
+		{"unknown sync policy", "type: notes\ntables: [{name: notes, columns: [id], sync_policy: write_once}]", "sync_policy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writePluginDir(t, t.TempDir(), "p", tt.manifest, nil)
+			_, err := Load(dir)
+			if !errors.Is(err, ErrInvalidManifest) {
+				t.Fatalf("Load() error = %v, want ErrInvalidManifest", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q should mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_PayloadSchemaFile(t *testing.T) {
+	manifest := "type: notes\ntables: [{name: notes, columns: [id, title], required: [id], payload_schema: schemas/notes.json}]"
+	dir := writePluginDir(t, t.TempDir(), "notes", manifest, nil)
+	if err := os.MkdirAll(filepath.Join(dir, "schemas"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	schema := `{"type":"object","required":["title"],"properties":{"title":{"type":"string","maxLength":5}}}`
+	if err := os.WriteFile(filepath.Join(dir, "schemas", "notes.json"), []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	schemas := p.PayloadSchemas()
+	if len(schemas) != 1 || string(schemas[0].Schema) != schema {
+		t.Fatalf("PayloadSchemas() = %+v, want the file's schema", schemas)
+	}
+
+	entry := engramsync.ChangeLogEntry{Sequence: 1, TableName: "notes", EntityID: "n1", Operation: "upsert",
+		Payload: json.RawMessage(`{"title":"too long"}`)}
+	err = plugin.ValidatePayloads(p, plugin.BaseSchemaVersion, []engramsync.ChangeLogEntry{entry})
+	var verrs plugin.ValidationErrors
+	if !errors.As(err, &verrs) || verrs.Errors[0].Field != "title" || verrs.Errors[0].Constraint != "maxLength" {
+		t.Errorf("ValidatePayloads() error = %v, want title maxLength", err)
+	}
+}
+
+func TestLoad_InvalidPayloadSchemaFile(t *testing.T) {
+	tests := []struct {
+		name, path, schema string
+	}{
+		{"outside plugin dir", "../notes.json", `{}`},
+		{"unsupported keyword", "notes.json", `{"oneOf":[]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := "type: notes\ntables: [{name: notes, columns: [id], payload_schema: " + tt.path + "}]"
+			dir := writePluginDir(t, t.TempDir(), "notes", manifest, nil)
+			if err := os.WriteFile(filepath.Join(dir, tt.path), []byte(tt.schema), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(dir); !errors.Is(err, ErrInvalidManifest) {
+				t.Errorf("Load() error = %v, want ErrInvalidManifest", err)
+			}
+		})
+	}
+}
+
+func TestLoad_InvalidMigrationName(t *testing.T) {
+	dir := writePluginDir(t, t.TempDir(), "notes", notesManifest,
+		map[string]string{"create_notes.sql": notesMigration})
+	if _, err := Load(dir); !errors.Is(err, ErrInvalidManifest) {
+		t.Fatalf("Load() error = %v, want ErrInvalidManifest", err)
+	}
+}
+
+func TestLoadDir_SkipsNonPluginDirs(t *testing.T) {
+	root := t.TempDir()
+	writePluginDir(t, root, "notes", notesManifest, nil)
+	if err := os.MkdirAll(filepath.Join(root, "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "README"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plugins, err := LoadDir(root)
+	if err != nil {
+		t.Fatalf("LoadDir() error = %v", err)
+	}
+	if len(plugins) != 1 || plugins[0].Type() != "notes" {
+		t.Errorf("LoadDir() = %v, want one notes plugin", plugins)
+	}
+}
+
+func TestLoadDir_MissingDir(t *testing.T) {
+	plugins, err := LoadDir(filepath.Join(t.TempDir(), "nope"))
+	if err != nil || plugins != nil {
+		t.Errorf("LoadDir() = %v, %v; want nil, nil", plugins, err)
+	}
+}
+
+func TestSplitMigration_NoMarkers(t *testing.T) {
+	up, down := splitMigration("CREATE TABLE x (id TEXT);")
+	if up != "CREATE TABLE x (id TEXT);" || down != "" {
+		t.Errorf("splitMigration() = %q, %q", up, down)
+	}
+}
+
+func TestValidatePush(t *testing.T) {
+	p := loadNotes(t)
+
+	entry := func(table, op, payload string) engramsync.ChangeLogEntry {
+		e := engramsync.ChangeLogEntry{Sequence: 1, TableName: table, EntityID: "n1", Operation: op}
+		if payload != "" {
+			e.Payload = json.RawMessage(payload)
+		}
+		return e
+	}
+
+	tests := []struct {
+		name      string
+		entry     engramsync.ChangeLogEntry
+		wantErr   bool
+		wantField string
+	}{
+		{"valid upsert", entry("notes", "upsert", `{"id":"n1","title":"hi","priority":2}`), false, ""},
+		{"valid delete", entry("notes", "delete", ""), false, ""},
+		{"unknown table", entry("goals", "upsert", `{"id":"n1"}`), true, ""},
+		{"missing payload", entry("notes", "upsert", ""), true, ""},
+		{"invalid JSON", entry("notes", "upsert", `{`), true, ""},
+		{"missing required", entry("notes", "upsert", `{"id":"n1"}`), true, "title"},
+		{"null required", entry("notes", "upsert", `{"id":"n1","title":null}`), true, "title"},
+		{"wrong type", entry("notes", "upsert", `{"id":"n1","title":5}`), true, "title"},
+		{"non-integer", entry("notes", "upsert", `{"id":"n1","title":"x","priority":1.5}`), true, "priority"},
+	}
+
+	// Field rules are enforced by the generated payload schema, which the
+	// push path checks after ValidatePush.
+	validate := func(e engramsync.ChangeLogEntry) error {
+		entries, err := p.ValidatePush(context.Background(), []engramsync.ChangeLogEntry{e})
+		if err != nil {
+			return err
+		}
+		return plugin.ValidatePayloads(p, plugin.BaseSchemaVersion, entries)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate(tt.entry)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			var verrs plugin.ValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("error type = %T, want ValidationErrors", err)
+			}
+			if tt.wantField != "" && verrs.Errors[0].Field != tt.wantField {
+				t.Errorf("Field = %q, want %q", verrs.Errors[0].Field, tt.wantField)
+			}
+		})
+	}
+}
+
+// recordingReplayStore captures replay calls.
+type recordingReplayStore struct {
+	upserts []string
+	deletes []string
+}
+
+func (s *recordingReplayStore) UpsertRow(_ context.Context, table, id string, _ []byte) error {
+	s.upserts = append(s.upserts, table+"/"+id)
+	return nil
+}
+
+func (s *recordingReplayStore) DeleteRow(_ context.Context, table, id string) error {
+	s.deletes = append(s.deletes, table+"/"+id)
+	return nil
+}
+
+func (s *recordingReplayStore) QueueEmbedding(context.Context, string) error { return nil }
+
+func TestOnReplay_AppliesDeclaredTables(t *testing.T) {
+	p := loadNotes(t)
+	rs := &recordingReplayStore{}
+
+	err := p.OnReplay(context.Background(), rs, []engramsync.ChangeLogEntry{
+		{TableName: "notes", EntityID: "a", Operation: "upsert", Payload: json.RawMessage(`{"id":"a","title":"x"}`)},
+		{TableName: "notes", EntityID: "b", Operation: "delete"},
+		{TableName: "other", EntityID: "c", Operation: "upsert", Payload: json.RawMessage(`{}`)},
+	})
+	if err != nil {
+		t.Fatalf("OnReplay() error = %v", err)
+	}
+	if len(rs.upserts) != 1 || rs.upserts[0] != "notes/a" {
+		t.Errorf("upserts = %v", rs.upserts)
+	}
+	if len(rs.deletes) != 1 || rs.deletes[0] != "notes/b" {
+		t.Errorf("deletes = %v", rs.deletes)
+	}
+}
+
+func TestRegister_Conflicts(t *testing.T) {
+	plugin.Reset()
+	t.Cleanup(plugin.Reset)
+
+	p := loadNotes(t)
+	if err := Register(p); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if got, ok := plugin.Get("notes"); !ok || got != plugin.DomainPlugin(p) {
+		t.Error("notes plugin not registered")
+	}
+
+	// Same type again
+	if err := Register(loadNotes(t)); !errors.Is(err, ErrInvalidManifest) {
+		t.Errorf("duplicate type: error = %v, want ErrInvalidManifest", err)
+	}
+
+	// Different type, same table
+	dir := writePluginDir(t, t.TempDir(), "memo", strings.Replace(notesManifest, "type: notes", "type: memo", 1), nil)
+	memo, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Register(memo); !errors.Is(err, ErrInvalidManifest) {
+		t.Errorf("duplicate table: error = %v, want ErrInvalidManifest", err)
+	}
+
+	// Reserved base table
+	dir = writePluginDir(t, t.TempDir(), "bad", "type: bad\ntables: [{name: change_log, columns: [id]}]", nil)
+	bad, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Register(bad); !errors.Is(err, ErrInvalidManifest) {
+		t.Errorf("reserved table: error = %v, want ErrInvalidManifest", err)
+	}
+}
+
+func TestRegister_RejectsEveryBaseTable(t *testing.T) {
+	plugin.Reset()
+	t.Cleanup(plugin.Reset)
+
+	s, err := store.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	rows, err := s.DB().Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+	if len(tables) < 10 {
+		t.Fatalf("base schema has %d tables, want the full core schema", len(tables))
+	}
+
+	for _, table := range tables {
+		dir := writePluginDir(t, t.TempDir(), "claims", "type: claims\ntables: [{name: "+table+", columns: [id]}]", nil)
+		p, err := Load(dir)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", table, err)
+		}
+		if err := Register(p); !errors.Is(err, ErrInvalidManifest) {
+			t.Errorf("Register() claiming %s: error = %v, want ErrInvalidManifest", table, err)
+		}
+	}
+}
+
+func TestMigrations_ApplyToSQLite(t *testing.T) {
+	p := loadNotes(t)
+
+	s, err := store.NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if err := store.RunPluginMigrations(s.DB(), p.Migrations()); err != nil {
+		t.Fatalf("RunPluginMigrations() error = %v", err)
+	}
+
+	plugin.ResetTableSchemas()
+	t.Cleanup(plugin.ResetTableSchemas)
+	plugin.RegisterTableSchemas(p.TableSchemas()...)
+
+	payload := []byte(`{"id":"n1","title":"hello","priority":1}`)
+	if err := s.UpsertRow(context.Background(), "notes", "n1", payload); err != nil {
+		t.Fatalf("UpsertRow() error = %v", err)
+	}
+
+	var title string
+	if err := s.DB().QueryRow("SELECT title FROM notes WHERE id = 'n1'").Scan(&title); err != nil {
+		t.Fatal(err)
+	}
+	if title != "hello" {
+		t.Errorf("title = %q, want hello", title)
+	}
+}
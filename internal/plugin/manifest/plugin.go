@@ -0,0 +1,138 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperengineering/engram/internal/plugin"
+	"github.com/hyperengineering/engram/internal/sync"
+)
+
+// Plugin implements the DomainPlugin interface from a loaded Manifest.
+type Plugin struct {
+	manifest   Manifest
+	migrations []plugin.Migration
+	tables     map[string]TableManifest
+	schemas    []plugin.PayloadSchema
+}
+
+func newPlugin(m Manifest, migs []plugin.Migration, schemas []plugin.PayloadSchema) *Plugin {
+	tables := make(map[string]TableManifest, len(m.Tables))
+	for _, t := range m.Tables {
+		tables[t.Name] = t
+	}
+	return &Plugin{manifest: m, migrations: migs, tables: tables, schemas: schemas}
+}
+
+// Type returns the store type declared in the manifest.
+func (p *Plugin) Type() string {
+	return p.manifest.Type
+}
+
+// Manifest returns the manifest this plugin was loaded from.
+func (p *Plugin) Manifest() Manifest {
+	return p.manifest
+}
+
+// Migrations returns the SQL migrations read from the plugin's migrations directory.
+func (p *Plugin) Migrations() []plugin.Migration {
+	return p.migrations
+}
+
+// ValidatePush checks entries against the manifest's table whitelist and
+// that upserts carry a JSON payload. Field rules are enforced through
+// PayloadSchemas. Entries are returned in their original order.
+func (p *Plugin) ValidatePush(_ context.Context, entries []sync.ChangeLogEntry) ([]sync.ChangeLogEntry, error) {
+	var validationErrors []plugin.ValidationError
+
+	for _, entry := range entries {
+		if _, ok := p.tables[entry.TableName]; !ok {
+			validationErrors = append(validationErrors, plugin.ValidationError{
+				Sequence:  entry.Sequence,
+				TableName: entry.TableName,
+				EntityID:  entry.EntityID,
+				Message:   fmt.Sprintf("unknown table %q for %s store", entry.TableName, p.manifest.Type),
+			})
+			continue
+		}
+
+		if entry.Operation == sync.OperationUpsert {
+			validationErrors = append(validationErrors, validatePayload(entry)...)
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, plugin.ValidationErrors{Errors: validationErrors}
+	}
+
+	return entries, nil
+}
+
+// validatePayload checks that an upsert carries a JSON payload.
+func validatePayload(entry sync.ChangeLogEntry) []plugin.ValidationError {
+	newErr := func(msg string) plugin.ValidationError {
+		return plugin.ValidationError{
+			Sequence:  entry.Sequence,
+			TableName: entry.TableName,
+			EntityID:  entry.EntityID,
+			Message:   msg,
+		}
+	}
+
+	if len(entry.Payload) == 0 {
+		return []plugin.ValidationError{newErr("payload required for upsert")}
+	}
+	if !json.Valid(entry.Payload) {
+		return []plugin.ValidationError{newErr("invalid payload JSON")}
+	}
+	return nil
+}
+
+// PayloadSchemas returns the payload schemas loaded or generated from the
+// manifest's tables.
+func (p *Plugin) PayloadSchemas() []plugin.PayloadSchema {
+	return p.schemas
+}
+
+// OnReplay applies entries to the plugin's domain tables using the
+// registered table schemas.
+func (p *Plugin) OnReplay(ctx context.Context, store plugin.ReplayStore, entries []sync.ChangeLogEntry) error {
+	for _, entry := range entries {
+		if _, ok := p.tables[entry.TableName]; !ok {
+			continue
+		}
+
+		switch entry.Operation {
+		case sync.OperationUpsert:
+			if err := store.UpsertRow(ctx, entry.TableName, entry.EntityID, entry.Payload); err != nil {
+				return fmt.Errorf("upsert %s: %w", entry.EntityID, err)
+			}
+		case sync.OperationDelete:
+			if err := store.DeleteRow(ctx, entry.TableName, entry.EntityID); err != nil {
+				return fmt.Errorf("delete %s: %w", entry.EntityID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// TableSchemas returns the replay schemas for the manifest's tables.
+func (p *Plugin) TableSchemas() []plugin.TableSchema {
+	schemas := make([]plugin.TableSchema, 0, len(p.manifest.Tables))
+	for _, t := range p.manifest.Tables {
+		schemas = append(schemas, plugin.TableSchema{
+			Name:       t.Name,
+			Columns:    t.Columns,
+			SoftDelete: t.SoftDelete,
+			SyncPolicy: t.SyncPolicy,
+		})
+	}
+	return schemas
+}
+
+// Ensure Plugin implements DomainPlugin at compile time.
+var (
+	_ plugin.DomainPlugin          = (*Plugin)(nil)
+	_ plugin.PayloadSchemaProvider = (*Plugin)(nil)
+)
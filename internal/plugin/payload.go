@@ -0,0 +1,121 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperengineering/engram/internal/jsonschema"
+	"github.com/hyperengineering/engram/internal/sync"
+)
+
+// PayloadSchema is a JSON Schema for the upsert payloads of one table.
+type PayloadSchema struct {
+	// Table is the table whose payloads the schema describes.
+	Table string `json:"table"`
+	// SchemaVersion is the first sync schema version the schema applies
+	// to. It stays in force until a schema for the same table with a
+	// higher version supersedes it. Zero applies from the first version.
+	SchemaVersion int `json:"schema_version"`
+	// Schema is the JSON Schema document.
+	Schema json.RawMessage `json:"schema"`
+}
+
+// PayloadSchemaProvider is implemented by plugins that describe their
+// upsert payloads with JSON Schemas. Pushed payloads are validated
+// against the schema in force at the push's schema_version; tables
+// without one are only checked by ValidatePush.
+type PayloadSchemaProvider interface {
+	PayloadSchemas() []PayloadSchema
+}
+
+// PayloadSchemasAt returns, for each table of p that has one, the payload
+// schema in force at sync schema version, sorted by table.
+func PayloadSchemasAt(p DomainPlugin, version int) []PayloadSchema {
+	provider, ok := p.(PayloadSchemaProvider)
+	if !ok {
+		return nil
+	}
+
+	current := make(map[string]PayloadSchema)
+	for _, s := range provider.PayloadSchemas() {
+		if s.SchemaVersion > version {
+			continue
+		}
+		if prev, ok := current[s.Table]; !ok || s.SchemaVersion > prev.SchemaVersion {
+			current[s.Table] = s
+		}
+	}
+
+	out := make([]PayloadSchema, 0, len(current))
+	for _, s := range current {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Table < out[j].Table })
+	return out
+}
+
+// ValidatePayloads checks the upsert payloads in entries against p's
+// payload schemas at sync schema version, returning ValidationErrors that
+// name the failing field and schema constraint. Entries without a
+// payload are left to the plugin's ValidatePush.
+func ValidatePayloads(p DomainPlugin, version int, entries []sync.ChangeLogEntry) error {
+	schemas := make(map[string]*jsonschema.Schema)
+	for _, s := range PayloadSchemasAt(p, version) {
+		compiled, err := jsonschema.Compile(s.Schema)
+		if err != nil {
+			return fmt.Errorf("payload schema for %s: %w", s.Table, err)
+		}
+		schemas[s.Table] = compiled
+	}
+	if len(schemas) == 0 {
+		return nil
+	}
+
+	var validationErrors []ValidationError
+	for _, entry := range entries {
+		schema, ok := schemas[entry.TableName]
+		if !ok || entry.Operation != sync.OperationUpsert || len(entry.Payload) == 0 {
+			continue
+		}
+
+		newErr := func(field, constraint, msg string) ValidationError {
+			return ValidationError{
+				Sequence:   entry.Sequence,
+				TableName:  entry.TableName,
+				EntityID:   entry.EntityID,
+				Field:      field,
+				Constraint: constraint,
+				Message:    msg,
+			}
+		}
+
+		errs, err := schema.Validate(entry.Payload)
+		if err != nil {
+			validationErrors = append(validationErrors, newErr("", "", fmt.Sprintf("invalid payload JSON: %v", err)))
+			continue
+		}
+		for _, e := range errs {
+			validationErrors = append(validationErrors, newErr(e.Path, e.Keyword, e.Message))
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return ValidationErrors{Errors: validationErrors}
+	}
+	return nil
+}
+
+// registerPayloadSchemas panics if a payload schema of p does not compile,
+// so a broken plugin fails at startup rather than on its first push.
+func registerPayloadSchemas(p DomainPlugin) {
+	provider, ok := p.(PayloadSchemaProvider)
+	if !ok {
+		return
+	}
+	for _, s := range provider.PayloadSchemas() {
+		if _, err := jsonschema.Compile(s.Schema); err != nil {
+			panic(fmt.Sprintf("invalid payload schema for table %q: %v", s.Table, err))
+		}
+	}
+}
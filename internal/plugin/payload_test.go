@@ -0,0 +1,76 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+// schemaPlugin tightens its notes payload schema at sync schema version 3.
+type schemaPlugin struct {
+	stubPlugin
+	schemas []PayloadSchema
+}
+
+func (p *schemaPlugin) PayloadSchemas() []PayloadSchema { return p.schemas }
+
+func newSchemaPlugin() *schemaPlugin {
+	return &schemaPlugin{stubPlugin: stubPlugin{typeName: "schemas"}, schemas: []PayloadSchema{
+		{Table: "notes", Schema: json.RawMessage(`{"type":"object","required":["id"]}`)},
+		{Table: "notes", SchemaVersion: 3, Schema: json.RawMessage(
+			`{"type":"object","required":["id","title"],"properties":{"title":{"type":"string","minLength":1}}}`)},
+	}}
+}
+
+func TestPayloadSchemasAt(t *testing.T) {
+	p := newSchemaPlugin()
+	if got := PayloadSchemasAt(p, 2); len(got) != 1 || got[0].SchemaVersion != 0 {
+		t.Errorf("PayloadSchemasAt(2) = %+v, want the unversioned schema", got)
+	}
+	if got := PayloadSchemasAt(p, 4); len(got) != 1 || got[0].SchemaVersion != 3 {
+		t.Errorf("PayloadSchemasAt(4) = %+v, want the version 3 schema", got)
+	}
+	if got := PayloadSchemasAt(&stubPlugin{typeName: "plain"}, 4); got != nil {
+		t.Errorf("PayloadSchemasAt() without provider = %+v, want nil", got)
+	}
+}
+
+func TestValidatePayloads(t *testing.T) {
+	p := newSchemaPlugin()
+	entries := []engramsync.ChangeLogEntry{
+		{Sequence: 1, TableName: "notes", EntityID: "n1", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{"id":"n1"}`)},
+		{Sequence: 2, TableName: "notes", EntityID: "n2", Operation: engramsync.OperationDelete},
+		{Sequence: 3, TableName: "goals", EntityID: "g1", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{}`)},
+	}
+
+	if err := ValidatePayloads(p, 2, entries); err != nil {
+		t.Fatalf("ValidatePayloads(v2) error = %v", err)
+	}
+
+	err := ValidatePayloads(p, 3, entries)
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("ValidatePayloads(v3) error = %v, want ValidationErrors", err)
+	}
+	if len(verrs.Errors) != 1 {
+		t.Fatalf("errors = %+v, want 1", verrs.Errors)
+	}
+	got := verrs.Errors[0]
+	if got.Sequence != 1 || got.Field != "title" || got.Constraint != "required" {
+		t.Errorf("error = %+v, want sequence 1, field title, constraint required", got)
+	}
+}
+
+func TestRegister_InvalidPayloadSchemaPanics(t *testing.T) {
+	Reset()
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() with an invalid payload schema did not panic")
+		}
+	}()
+	Register(&schemaPlugin{stubPlugin: stubPlugin{typeName: "bad"}, schemas: []PayloadSchema{
+		{Table: "notes", Schema: json.RawMessage(`{"type":"text"}`)},
+	}})
+}
@@ -20,6 +20,10 @@ type TableSchema struct {
 	// SoftDelete indicates whether DeleteRow should SET deleted_at
 	// (soft delete) or issue a real DELETE FROM (hard delete).
 	SoftDelete bool
+
+	// SyncPolicy controls what clients may push to the table and whether
+	// they receive it in deltas. Empty means SyncClientWritable.
+	SyncPolicy SyncPolicy
 }
 
 // DomainPlugin provides type-specific behavior for a store.
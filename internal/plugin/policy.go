@@ -0,0 +1,123 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hyperengineering/engram/internal/sync"
+)
+
+// SyncPolicy controls what clients may do with a table through sync.
+type SyncPolicy string
+
+const (
+	// SyncClientWritable tables accept client upserts and deletes. It is
+	// the policy of tables that declare none.
+	SyncClientWritable SyncPolicy = "client_writable"
+	// SyncAppendOnly tables accept client upserts of entities that do not
+	// exist yet; updates and deletes are server-side only.
+	SyncAppendOnly SyncPolicy = "append_only"
+	// SyncReadOnly tables are pulled by clients but reject every client
+	// push.
+	SyncReadOnly SyncPolicy = "read_only"
+	// SyncServerOnly tables reject client pushes and are left out of sync
+	// deltas. Snapshots still contain them, as they copy the database.
+	SyncServerOnly SyncPolicy = "server_only"
+)
+
+// SyncPolicies lists the valid sync policies.
+var SyncPolicies = []SyncPolicy{SyncClientWritable, SyncAppendOnly, SyncReadOnly, SyncServerOnly}
+
+// Valid reports whether p is a known policy. The empty policy is valid
+// and means SyncClientWritable.
+func (p SyncPolicy) Valid() bool {
+	return p == "" || slices.Contains(SyncPolicies, p)
+}
+
+// TableSyncPolicy returns the sync policy p declares for table, or
+// SyncClientWritable if it declares none.
+func TableSyncPolicy(p DomainPlugin, table string) SyncPolicy {
+	for _, s := range p.TableSchemas() {
+		if s.Name == table && s.SyncPolicy != "" {
+			return s.SyncPolicy
+		}
+	}
+	return SyncClientWritable
+}
+
+// ServerOnlyTables returns the tables of p whose entries are left out of
+// sync deltas.
+func ServerOnlyTables(p DomainPlugin) []string {
+	var tables []string
+	for _, s := range p.TableSchemas() {
+		if s.SyncPolicy == SyncServerOnly {
+			tables = append(tables, s.Name)
+		}
+	}
+	return tables
+}
+
+// EnforceSyncPolicies checks pushed entries against the sync policies of
+// p's tables, returning ValidationErrors for entries a client may not
+// push. reader resolves whether the entities of append-only upserts
+// already exist.
+func EnforceSyncPolicies(ctx context.Context, p DomainPlugin, reader LatestEntryReader, entries []sync.ChangeLogEntry) error {
+	var validationErrors []ValidationError
+	reject := func(entry sync.ChangeLogEntry, msg string) {
+		validationErrors = append(validationErrors, ValidationError{
+			Sequence:  entry.Sequence,
+			TableName: entry.TableName,
+			EntityID:  entry.EntityID,
+			Message:   msg,
+		})
+	}
+
+	var appendOnly []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		policy := TableSyncPolicy(p, entry.TableName)
+		switch policy {
+		case SyncReadOnly, SyncServerOnly:
+			reject(entry, fmt.Sprintf("table is %s for clients", policy))
+		case SyncAppendOnly:
+			if entry.Operation != sync.OperationUpsert {
+				reject(entry, "table is append_only for clients: deletes are not allowed")
+				continue
+			}
+			key := entry.TableName + "\x00" + entry.EntityID
+			if seen[key] {
+				reject(entry, "table is append_only for clients: entity is written twice in the push")
+				continue
+			}
+			if !slices.Contains(appendOnly, entry.TableName) {
+				appendOnly = append(appendOnly, entry.TableName)
+			}
+			seen[key] = true
+		}
+	}
+
+	if len(appendOnly) > 0 && reader != nil {
+		existing, err := reader.GetLatestEntries(ctx, appendOnly)
+		if err != nil {
+			return fmt.Errorf("read existing entities: %w", err)
+		}
+		exists := make(map[string]bool, len(existing))
+		for _, e := range existing {
+			exists[e.TableName+"\x00"+e.EntityID] = true
+		}
+		for _, entry := range entries {
+			if TableSyncPolicy(p, entry.TableName) != SyncAppendOnly || entry.Operation != sync.OperationUpsert {
+				continue
+			}
+			if exists[entry.TableName+"\x00"+entry.EntityID] {
+				reject(entry, "table is append_only for clients: entity already exists")
+			}
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return ValidationErrors{Errors: validationErrors}
+	}
+	return nil
+}
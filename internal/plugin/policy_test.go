@@ -0,0 +1,108 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+// fakeLatestReader returns fixed latest entries.
+type fakeLatestReader struct {
+	entries []engramsync.ChangeLogEntry
+}
+
+func (f *fakeLatestReader) GetLatestEntries(_ context.Context, tables []string) ([]engramsync.ChangeLogEntry, error) {
+	var out []engramsync.ChangeLogEntry
+	for _, e := range f.entries {
+		for _, t := range tables {
+			if e.TableName == t {
+				out = append(out, e)
+			}
+		}
+	}
+	return out, nil
+}
+
+func policyPlugin() *stubPlugin {
+	return &stubPlugin{typeName: "policies", schemas: []TableSchema{
+		{Name: "notes", Columns: []string{"id"}},
+		{Name: "contexts", Columns: []string{"id"}, SyncPolicy: SyncAppendOnly},
+		{Name: "rollups", Columns: []string{"id"}, SyncPolicy: SyncServerOnly},
+		{Name: "statuses", Columns: []string{"id"}, SyncPolicy: SyncReadOnly},
+	}}
+}
+
+func TestEnforceSyncPolicies(t *testing.T) {
+	p := policyPlugin()
+	reader := &fakeLatestReader{entries: []engramsync.ChangeLogEntry{
+		{TableName: "contexts", EntityID: "C-old", Operation: engramsync.OperationUpsert},
+	}}
+	upsert := func(seq int64, table, id string) engramsync.ChangeLogEntry {
+		return engramsync.ChangeLogEntry{Sequence: seq, TableName: table, EntityID: id, Operation: engramsync.OperationUpsert}
+	}
+
+	allowed := []engramsync.ChangeLogEntry{
+		upsert(1, "notes", "N-1"),
+		{Sequence: 2, TableName: "notes", EntityID: "N-2", Operation: engramsync.OperationDelete},
+		upsert(3, "contexts", "C-new"),
+	}
+	if err := EnforceSyncPolicies(context.Background(), p, reader, allowed); err != nil {
+		t.Fatalf("EnforceSyncPolicies(allowed) error = %v", err)
+	}
+
+	rejected := []engramsync.ChangeLogEntry{
+		upsert(1, "rollups", "R-1"),
+		upsert(2, "statuses", "S-1"),
+		upsert(3, "contexts", "C-old"),
+		{Sequence: 4, TableName: "contexts", EntityID: "C-new", Operation: engramsync.OperationDelete},
+		upsert(5, "contexts", "C-twice"),
+		upsert(6, "contexts", "C-twice"),
+	}
+	err := EnforceSyncPolicies(context.Background(), p, reader, rejected)
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("EnforceSyncPolicies(rejected) error = %v, want ValidationErrors", err)
+	}
+	got := make(map[int64]bool)
+	for _, e := range verrs.Errors {
+		got[e.Sequence] = true
+	}
+	for _, seq := range []int64{1, 2, 3, 4, 6} {
+		if !got[seq] {
+			t.Errorf("entry %d not rejected; errors = %+v", seq, verrs.Errors)
+		}
+	}
+	if got[5] {
+		t.Error("first write of a new append-only entity should be allowed")
+	}
+}
+
+func TestSyncPolicy_Lookup(t *testing.T) {
+	p := policyPlugin()
+	if got := TableSyncPolicy(p, "notes"); got != SyncClientWritable {
+		t.Errorf("TableSyncPolicy(notes) = %q, want client_writable", got)
+	}
+	if got := TableSyncPolicy(p, "unknown"); got != SyncClientWritable {
+		t.Errorf("TableSyncPolicy(unknown) = %q, want client_writable", got)
+	}
+	if got := ServerOnlyTables(p); len(got) != 1 || got[0] != "rollups" {
+		t.Errorf("ServerOnlyTables() = %v, want [rollups]", got)
+	}
+	if SyncPolicy("write_once").Valid() || !SyncPolicy("").Valid() {
+		t.Error("Valid() should accept known and empty policies only")
+	}
+}
+
+func TestRegister_InvalidSyncPolicyPanics(t *testing.T) {
+	Reset()
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() with an unknown sync policy did not panic")
+		}
+	}()
+	Register(&stubPlugin{typeName: "bad", schemas: []TableSchema{
+		{Name: "things", Columns: []string{"id"}, SyncPolicy: "write_once"},
+	}})
+}
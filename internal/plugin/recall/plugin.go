@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/hyperengineering/engram/internal/plugin"
 	"github.com/hyperengineering/engram/internal/sync"
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/validation"
 )
 
 // Plugin implements the DomainPlugin interface for Recall stores.
@@ -62,7 +65,8 @@ func (p *Plugin) ValidatePush(_ context.Context, entries []sync.ChangeLogEntry)
 	return entries, nil
 }
 
-// validateLorePayload checks that a lore_entries payload has required fields.
+// validateLorePayload checks what the lore_entries payload schema cannot
+// express: double encoding and the language, repo and path scope formats.
 func (p *Plugin) validateLorePayload(entry sync.ChangeLogEntry) error {
 	if entry.Payload == nil {
 		return fmt.Errorf("payload required for upsert")
@@ -81,30 +85,27 @@ func (p *Plugin) validateLorePayload(entry sync.ChangeLogEntry) error {
 		}
 	}
 
+	// Field presence, types, category and confidence are checked against
+	// the payload schema; only the scope fields need code.
 	var payload LorePayload
 	if err := json.Unmarshal(entry.Payload, &payload); err != nil {
 		return fmt.Errorf("invalid payload JSON: %w", err)
 	}
 
-	if payload.ID == "" {
-		return fmt.Errorf("missing required field: id")
-	}
-	if payload.Content == "" {
-		return fmt.Errorf("missing required field: content")
-	}
-	if payload.Category == "" {
-		return fmt.Errorf("missing required field: category")
-	}
-	if payload.SourceID == "" {
-		return fmt.Errorf("missing required field: source_id")
-	}
-
-	if !isValidCategory(payload.Category) {
-		return fmt.Errorf("invalid category: %s", payload.Category)
-	}
-
-	if payload.Confidence < 0 || payload.Confidence > 1 {
-		return fmt.Errorf("confidence must be between 0 and 1")
+	for _, f := range []struct {
+		name, value string
+		validate    func(field, value string) *validation.ValidationError
+	}{
+		{"language", payload.Language, validation.ValidateLanguage},
+		{"repo", payload.Repo, validation.ValidateRepo},
+		{"path", payload.Path, validation.ValidateScopePath},
+	} {
+		if f.value == "" {
+			continue
+		}
+		if verr := f.validate(f.name, f.value); verr != nil {
+			return fmt.Errorf("invalid %s: %s", f.name, verr.Message)
+		}
 	}
 
 	return nil
@@ -141,10 +142,31 @@ func (p *Plugin) OnReplay(ctx context.Context, store plugin.ReplayStore, entries
 	return nil
 }
 
+// BeforeIngest trims surrounding whitespace from an entry's content and
+// context, so that entries differing only in padding are stored alike,
+// and vetoes entries whose content is blank.
+func (p *Plugin) BeforeIngest(_ context.Context, entry *types.NewLoreEntry) error {
+	entry.Content = strings.TrimSpace(entry.Content)
+	entry.Context = strings.TrimSpace(entry.Context)
+	if entry.Content == "" {
+		return fmt.Errorf("%w: content is blank", plugin.ErrVetoed)
+	}
+	return nil
+}
+
+// PayloadSchemas returns the JSON Schema for lore_entries payloads.
+func (p *Plugin) PayloadSchemas() []plugin.PayloadSchema {
+	return []plugin.PayloadSchema{{Table: "lore_entries", Schema: lorePayloadSchema}}
+}
+
 // TableSchemas returns nil — Recall uses the hardcoded lore_entries path.
 func (p *Plugin) TableSchemas() []plugin.TableSchema {
 	return nil
 }
 
 // Ensure Plugin implements DomainPlugin at compile time.
-var _ plugin.DomainPlugin = (*Plugin)(nil)
+var (
+	_ plugin.DomainPlugin          = (*Plugin)(nil)
+	_ plugin.PayloadSchemaProvider = (*Plugin)(nil)
+	_ plugin.BeforeIngestHook      = (*Plugin)(nil)
+)
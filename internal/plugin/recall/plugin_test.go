@@ -9,6 +9,7 @@ import (
 
 	"github.com/hyperengineering/engram/internal/plugin"
 	engramsync "github.com/hyperengineering/engram/internal/sync"
+	"github.com/hyperengineering/engram/internal/types"
 )
 
 // Compile-time check: Plugin must implement DomainPlugin.
@@ -181,123 +182,51 @@ func TestValidatePush_UnknownTable(t *testing.T) {
 	}
 }
 
-func TestValidatePush_MissingID(t *testing.T) {
-	p := New()
-	entries := []engramsync.ChangeLogEntry{
-		{
-			Sequence:  1,
-			TableName: "lore_entries",
-			EntityID:  "entry-1",
-			Operation: engramsync.OperationUpsert,
-			Payload:   payloadWithOverrides(map[string]interface{}{"id": ""}),
-		},
-	}
-
-	_, err := p.ValidatePush(context.Background(), entries)
-	if err == nil {
-		t.Fatal("expected error for missing id")
-	}
-	var ve plugin.ValidationErrors
-	if !errors.As(err, &ve) {
-		t.Fatalf("expected ValidationErrors, got %T", err)
-	}
-	assertContainsMessage(t, ve.Errors, "missing required field: id")
-}
-
-func TestValidatePush_MissingContent(t *testing.T) {
-	p := New()
-	entries := []engramsync.ChangeLogEntry{
-		{
-			Sequence:  1,
-			TableName: "lore_entries",
-			EntityID:  "entry-1",
-			Operation: engramsync.OperationUpsert,
-			Payload:   payloadWithOverrides(map[string]interface{}{"content": ""}),
-		},
-	}
-
-	_, err := p.ValidatePush(context.Background(), entries)
-	if err == nil {
-		t.Fatal("expected error for missing content")
-	}
-	var ve plugin.ValidationErrors
-	if !errors.As(err, &ve) {
-		t.Fatalf("expected ValidationErrors, got %T", err)
-	}
-	assertContainsMessage(t, ve.Errors, "missing required field: content")
-}
-
-func TestValidatePush_MissingCategory(t *testing.T) {
-	p := New()
-	entries := []engramsync.ChangeLogEntry{
-		{
-			Sequence:  1,
-			TableName: "lore_entries",
-			EntityID:  "entry-1",
-			Operation: engramsync.OperationUpsert,
-			Payload:   payloadWithOverrides(map[string]interface{}{"category": ""}),
-		},
-	}
-
-	_, err := p.ValidatePush(context.Background(), entries)
-	if err == nil {
-		t.Fatal("expected error for missing category")
-	}
-	var ve plugin.ValidationErrors
-	if !errors.As(err, &ve) {
-		t.Fatalf("expected ValidationErrors, got %T", err)
-	}
-	assertContainsMessage(t, ve.Errors, "missing required field: category")
-}
-
-func TestValidatePush_MissingSourceID(t *testing.T) {
-	p := New()
-	entries := []engramsync.ChangeLogEntry{
-		{
-			Sequence:  1,
-			TableName: "lore_entries",
-			EntityID:  "entry-1",
-			Operation: engramsync.OperationUpsert,
-			Payload:   payloadWithOverrides(map[string]interface{}{"source_id": ""}),
-		},
-	}
-
-	_, err := p.ValidatePush(context.Background(), entries)
-	if err == nil {
-		t.Fatal("expected error for missing source_id")
-	}
-	var ve plugin.ValidationErrors
-	if !errors.As(err, &ve) {
-		t.Fatalf("expected ValidationErrors, got %T", err)
-	}
-	assertContainsMessage(t, ve.Errors, "missing required field: source_id")
-}
-
-func TestValidatePush_InvalidCategory(t *testing.T) {
-	p := New()
-	entries := []engramsync.ChangeLogEntry{
-		{
-			Sequence:  1,
-			TableName: "lore_entries",
-			EntityID:  "entry-1",
-			Operation: engramsync.OperationUpsert,
-			Payload:   payloadWithOverrides(map[string]interface{}{"category": "UNKNOWN"}),
-		},
-	}
-
-	_, err := p.ValidatePush(context.Background(), entries)
-	if err == nil {
-		t.Fatal("expected error for invalid category")
-	}
-	var ve plugin.ValidationErrors
-	if !errors.As(err, &ve) {
-		t.Fatalf("expected ValidationErrors, got %T", err)
-	}
-	assertContainsMessage(t, ve.Errors, "invalid category: UNKNOWN")
-}
-
-func TestValidatePush_InvalidConfidence_TooHigh(t *testing.T) {
-	p := New()
+func TestPayloadSchema_RejectsInvalidFields(t *testing.T) {
+	tests := []struct {
+		name           string
+		overrides      map[string]interface{}
+		wantField      string
+		wantConstraint string
+	}{
+		{"empty id", map[string]interface{}{"id": ""}, "id", "minLength"},
+		{"empty content", map[string]interface{}{"content": ""}, "content", "minLength"},
+		{"empty category", map[string]interface{}{"category": ""}, "category", "enum"},
+		{"empty source_id", map[string]interface{}{"source_id": ""}, "source_id", "minLength"},
+		{"missing content", map[string]interface{}{"content": nil}, "content", "required"},
+		{"unknown category", map[string]interface{}{"category": "UNKNOWN"}, "category", "enum"},
+		{"confidence too high", map[string]interface{}{"confidence": 1.5}, "confidence", "maximum"},
+		{"confidence negative", map[string]interface{}{"confidence": -0.1}, "confidence", "minimum"},
+		{"confidence not a number", map[string]interface{}{"confidence": "high"}, "confidence", "type"},
+		{"sources not strings", map[string]interface{}{"sources": []interface{}{1}}, "sources[0]", "type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := []engramsync.ChangeLogEntry{
+				{
+					Sequence:  1,
+					TableName: "lore_entries",
+					EntityID:  "entry-1",
+					Operation: engramsync.OperationUpsert,
+					Payload:   payloadWithOverrides(tt.overrides),
+				},
+			}
+
+			err := plugin.ValidatePayloads(New(), plugin.BaseSchemaVersion, entries)
+			var ve plugin.ValidationErrors
+			if !errors.As(err, &ve) {
+				t.Fatalf("expected ValidationErrors, got %v", err)
+			}
+			got := ve.Errors[0]
+			if got.Field != tt.wantField || got.Constraint != tt.wantConstraint {
+				t.Errorf("error = %+v, want field %q constraint %q", got, tt.wantField, tt.wantConstraint)
+			}
+		})
+	}
+}
+
+func TestPayloadSchema_ConfidenceMessage(t *testing.T) {
 	entries := []engramsync.ChangeLogEntry{
 		{
 			Sequence:  1,
@@ -308,32 +237,40 @@ func TestValidatePush_InvalidConfidence_TooHigh(t *testing.T) {
 		},
 	}
 
-	_, err := p.ValidatePush(context.Background(), entries)
-	if err == nil {
-		t.Fatal("expected error for confidence > 1")
-	}
+	err := plugin.ValidatePayloads(New(), plugin.BaseSchemaVersion, entries)
 	var ve plugin.ValidationErrors
 	if !errors.As(err, &ve) {
-		t.Fatalf("expected ValidationErrors, got %T", err)
-	}
-	assertContainsMessage(t, ve.Errors, "confidence must be between 0 and 1")
-}
-
-func TestValidatePush_InvalidConfidence_Negative(t *testing.T) {
-	p := New()
-	entries := []engramsync.ChangeLogEntry{
-		{
-			Sequence:  1,
-			TableName: "lore_entries",
-			EntityID:  "entry-1",
-			Operation: engramsync.OperationUpsert,
-			Payload:   payloadWithOverrides(map[string]interface{}{"confidence": -0.1}),
-		},
-	}
-
-	_, err := p.ValidatePush(context.Background(), entries)
-	if err == nil {
-		t.Fatal("expected error for negative confidence")
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	assertContainsMessage(t, ve.Errors, "must be between 0 and 1")
+}
+
+func TestValidatePush_InvalidScope(t *testing.T) {
+	for field, value := range map[string]string{
+		"language": "German",
+		"repo":     "acme payments",
+		"path":     "../secrets",
+	} {
+		t.Run(field, func(t *testing.T) {
+			entries := []engramsync.ChangeLogEntry{
+				{
+					Sequence:  1,
+					TableName: "lore_entries",
+					EntityID:  "entry-1",
+					Operation: engramsync.OperationUpsert,
+					Payload:   payloadWithOverrides(map[string]interface{}{field: value}),
+				},
+			}
+
+			_, err := New().ValidatePush(context.Background(), entries)
+			var ve plugin.ValidationErrors
+			if !errors.As(err, &ve) {
+				t.Fatalf("expected ValidationErrors, got %v", err)
+			}
+			if len(ve.Errors) != 1 || !strings.Contains(ve.Errors[0].Message, "invalid "+field) {
+				t.Errorf("errors = %+v, want invalid %s", ve.Errors, field)
+			}
+		})
 	}
 }
 
@@ -425,7 +362,7 @@ func TestValidatePush_MultipleErrors(t *testing.T) {
 			TableName: "lore_entries",
 			EntityID:  "e3",
 			Operation: engramsync.OperationUpsert,
-			Payload:   payloadWithOverrides(map[string]interface{}{"category": "INVALID"}),
+			Payload:   payloadWithOverrides(map[string]interface{}{"language": "German"}),
 		},
 	}
 
@@ -442,41 +379,24 @@ func TestValidatePush_MultipleErrors(t *testing.T) {
 	}
 }
 
-func TestValidatePush_ConfidenceBoundaryValues(t *testing.T) {
-	p := New()
-
-	// confidence = 0 should be valid
-	entries := []engramsync.ChangeLogEntry{
-		{
-			Sequence:  1,
-			TableName: "lore_entries",
-			EntityID:  "e1",
-			Operation: engramsync.OperationUpsert,
-			Payload:   payloadWithOverrides(map[string]interface{}{"confidence": 0.0}),
-		},
-	}
-	got, err := p.ValidatePush(context.Background(), entries)
-	if err != nil {
-		t.Fatalf("confidence=0 should be valid, got error: %v", err)
-	}
-	if len(got) != 1 {
-		t.Fatalf("expected 1 entry, got %d", len(got))
-	}
-
-	// confidence = 1 should be valid
-	entries[0].Payload = payloadWithOverrides(map[string]interface{}{"confidence": 1.0})
-	got, err = p.ValidatePush(context.Background(), entries)
-	if err != nil {
-		t.Fatalf("confidence=1 should be valid, got error: %v", err)
-	}
-	if len(got) != 1 {
-		t.Fatalf("expected 1 entry, got %d", len(got))
+func TestPayloadSchema_ConfidenceBoundaryValues(t *testing.T) {
+	for _, confidence := range []float64{0, 1} {
+		entries := []engramsync.ChangeLogEntry{
+			{
+				Sequence:  1,
+				TableName: "lore_entries",
+				EntityID:  "e1",
+				Operation: engramsync.OperationUpsert,
+				Payload:   payloadWithOverrides(map[string]interface{}{"confidence": confidence}),
+			},
+		}
+		if err := plugin.ValidatePayloads(New(), plugin.BaseSchemaVersion, entries); err != nil {
+			t.Errorf("confidence=%v should be valid, got error: %v", confidence, err)
+		}
 	}
 }
 
-func TestValidatePush_AllValidCategories(t *testing.T) {
-	p := New()
-
+func TestPayloadSchema_AllValidCategories(t *testing.T) {
 	for category := range ValidCategories {
 		entries := []engramsync.ChangeLogEntry{
 			{
@@ -487,9 +407,7 @@ func TestValidatePush_AllValidCategories(t *testing.T) {
 				Payload:   payloadWithOverrides(map[string]interface{}{"category": category}),
 			},
 		}
-
-		_, err := p.ValidatePush(context.Background(), entries)
-		if err != nil {
+		if err := plugin.ValidatePayloads(New(), plugin.BaseSchemaVersion, entries); err != nil {
 			t.Errorf("category %q should be valid, got error: %v", category, err)
 		}
 	}
@@ -820,3 +738,23 @@ func assertContainsMessage(t *testing.T, errs []plugin.ValidationError, substr s
 	}
 	t.Errorf("expected validation error containing %q, got: %v", substr, errs)
 }
+
+// --- BeforeIngest tests ---
+
+func TestBeforeIngest_TrimsWhitespace(t *testing.T) {
+	entry := types.NewLoreEntry{Content: "  Use retries \n", Context: "\tpayments "}
+	if err := New().BeforeIngest(context.Background(), &entry); err != nil {
+		t.Fatalf("BeforeIngest() error = %v", err)
+	}
+	if entry.Content != "Use retries" || entry.Context != "payments" {
+		t.Errorf("entry = %q, %q; want trimmed", entry.Content, entry.Context)
+	}
+}
+
+func TestBeforeIngest_VetoesBlankContent(t *testing.T) {
+	entry := types.NewLoreEntry{Content: " \n\t"}
+	err := New().BeforeIngest(context.Background(), &entry)
+	if !errors.Is(err, plugin.ErrVetoed) {
+		t.Errorf("BeforeIngest() error = %v, want ErrVetoed", err)
+	}
+}
@@ -1,6 +1,9 @@
 package recall
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"sort"
+)
 
 // LorePayload represents the JSON structure of a lore_entries row.
 // Used for validation during sync push.
@@ -19,6 +22,9 @@ type LorePayload struct {
 	UpdatedAt       string          `json:"updated_at"`
 	DeletedAt       *string         `json:"deleted_at,omitempty"`
 	LastValidatedAt *string         `json:"last_validated_at,omitempty"`
+	Language        string          `json:"language,omitempty"`
+	Repo            string          `json:"repo,omitempty"`
+	Path            string          `json:"path,omitempty"`
 }
 
 // ValidCategories defines the allowed lore categories.
@@ -33,7 +39,46 @@ var ValidCategories = map[string]bool{
 	"PERFORMANCE_INSIGHT":     true,
 }
 
-// isValidCategory checks if a category is in the allowed set.
-func isValidCategory(category string) bool {
-	return ValidCategories[category]
-}
+// lorePayloadSchema is the JSON Schema for lore_entries payloads. Empty
+// strings are rejected where the old checks treated them as missing, and
+// unknown fields are allowed so newer clients can add columns.
+var lorePayloadSchema = func() json.RawMessage {
+	categories := make([]string, 0, len(ValidCategories))
+	for c := range ValidCategories {
+		categories = append(categories, c)
+	}
+	sort.Strings(categories)
+
+	nonEmpty := map[string]interface{}{"type": "string", "minLength": 1}
+	str := map[string]interface{}{"type": "string"}
+	nullableStr := map[string]interface{}{"type": []string{"string", "null"}}
+	schema := map[string]interface{}{
+		"$schema":  "https://json-schema.org/draft/2020-12/schema",
+		"title":    "lore_entries payload",
+		"type":     "object",
+		"required": []string{"id", "content", "category", "source_id"},
+		"properties": map[string]interface{}{
+			"id":                nonEmpty,
+			"content":           nonEmpty,
+			"context":           str,
+			"category":          map[string]interface{}{"type": "string", "enum": categories},
+			"confidence":        map[string]interface{}{"type": "number", "minimum": 0, "maximum": 1},
+			"embedding_status":  str,
+			"source_id":         nonEmpty,
+			"sources":           map[string]interface{}{"type": "array", "items": str},
+			"validation_count":  map[string]interface{}{"type": "integer", "minimum": 0},
+			"created_at":        str,
+			"updated_at":        str,
+			"deleted_at":        nullableStr,
+			"last_validated_at": nullableStr,
+			"language":          str,
+			"repo":              str,
+			"path":              str,
+		},
+	}
+	b, err := json.Marshal(schema)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}()
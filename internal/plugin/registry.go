@@ -33,6 +33,7 @@ func Register(p DomainPlugin) {
 	if _, exists := plugins[t]; exists {
 		panic("plugin already registered: " + t)
 	}
+	registerPayloadSchemas(p)
 	plugins[t] = p
 	registerTableSchemas(p)
 }
@@ -106,6 +107,9 @@ func registerTableSchemas(p DomainPlugin) {
 				panic(fmt.Sprintf("invalid column name %q in table %q", col, s.Name))
 			}
 		}
+		if !s.SyncPolicy.Valid() {
+			panic(fmt.Sprintf("invalid sync policy %q for table %q", s.SyncPolicy, s.Name))
+		}
 		tableSchemas[s.Name] = s
 	}
 }
@@ -0,0 +1,117 @@
+package plugin
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/hyperengineering/engram/internal/sync"
+)
+
+// BaseSchemaVersion is the sync schema version that introduced change_log
+// sync. Every store type supports it.
+const BaseSchemaVersion = 2
+
+// SchemaVersion is a sync schema version a store type supports.
+type SchemaVersion struct {
+	// Version is the sync schema version, as sent by clients in
+	// schema_version.
+	Version int `json:"version"`
+	// Migrations lists the versions of the plugin's server migrations a
+	// store must have applied to serve this schema version. The list is
+	// cumulative: it includes those of every earlier version.
+	Migrations []int `json:"migrations"`
+}
+
+// SchemaVersionProvider declares the sync schema versions a plugin
+// supports, in ascending order. Plugins that do not implement it support
+// versions 1 through BaseSchemaVersion, or through their highest client
+// migration if later, with every server migration required from
+// BaseSchemaVersion on.
+type SchemaVersionProvider interface {
+	SchemaVersions() []SchemaVersion
+}
+
+// SchemaVersions returns the sync schema versions p supports, in ascending
+// order.
+func SchemaVersions(p DomainPlugin) []SchemaVersion {
+	if provider, ok := p.(SchemaVersionProvider); ok {
+		versions := slices.Clone(provider.SchemaVersions())
+		sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+		return versions
+	}
+
+	latest := BaseSchemaVersion
+	if provider, ok := p.(ClientMigrationProvider); ok {
+		for _, m := range provider.ClientMigrations() {
+			latest = max(latest, m.Version)
+		}
+	}
+	all := []int{}
+	for _, m := range p.Migrations() {
+		all = append(all, m.Version)
+	}
+	slices.Sort(all)
+
+	versions := []SchemaVersion{{Version: 1, Migrations: []int{}}}
+	for v := BaseSchemaVersion; v <= latest; v++ {
+		versions = append(versions, SchemaVersion{Version: v, Migrations: all})
+	}
+	return versions
+}
+
+// CurrentSchemaVersion returns the highest sync schema version p supports.
+func CurrentSchemaVersion(p DomainPlugin) int {
+	versions := SchemaVersions(p)
+	if len(versions) == 0 {
+		return 0
+	}
+	return versions[len(versions)-1].Version
+}
+
+// ResolveSchemaVersion returns the highest sync schema version p supports
+// whose server migrations are all in applied, or 0 if there is none.
+func ResolveSchemaVersion(p DomainPlugin, applied map[int]bool) int {
+	resolved := 0
+	for _, v := range SchemaVersions(p) {
+		ok := true
+		for _, m := range v.Migrations {
+			if !applied[m] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			resolved = v.Version
+		}
+	}
+	return resolved
+}
+
+// SupportsSchemaVersion reports whether version is one of p's sync schema
+// versions.
+func SupportsSchemaVersion(p DomainPlugin, version int) bool {
+	for _, v := range SchemaVersions(p) {
+		if v.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientMigrationsBetween returns p's client replica migrations with
+// from < version <= to, in version order. Returns nil when p provides none.
+func ClientMigrationsBetween(p DomainPlugin, from, to int) []sync.ClientMigration {
+	provider, ok := p.(ClientMigrationProvider)
+	if !ok || from >= to {
+		return nil
+	}
+
+	var out []sync.ClientMigration
+	for _, m := range provider.ClientMigrations() {
+		if m.Version > from && m.Version <= to {
+			out = append(out, m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out
+}
@@ -0,0 +1,86 @@
+package plugin
+
+import (
+	"reflect"
+	"testing"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+// migratingPlugin has server migrations and client migrations up to
+// version 3.
+type migratingPlugin struct {
+	stubPlugin
+}
+
+func (p *migratingPlugin) Migrations() []Migration {
+	return []Migration{{Version: 101, Name: "b"}, {Version: 100, Name: "a"}}
+}
+
+func (p *migratingPlugin) ClientMigrations() []engramsync.ClientMigration {
+	return []engramsync.ClientMigration{{Version: 3, Name: "c"}, {Version: 2, Name: "b"}}
+}
+
+// versionedPlugin declares its schema versions explicitly.
+type versionedPlugin struct {
+	stubPlugin
+}
+
+func (p *versionedPlugin) SchemaVersions() []SchemaVersion {
+	return []SchemaVersion{
+		{Version: 4, Migrations: []int{100, 101}},
+		{Version: 2, Migrations: []int{100}},
+	}
+}
+
+func TestSchemaVersions_Default(t *testing.T) {
+	got := SchemaVersions(&stubPlugin{typeName: "plain"})
+	want := []SchemaVersion{{Version: 1, Migrations: []int{}}, {Version: 2, Migrations: []int{}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SchemaVersions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSchemaVersions_DerivedFromMigrations(t *testing.T) {
+	p := &migratingPlugin{stubPlugin{typeName: "migrating"}}
+	got := SchemaVersions(p)
+	want := []SchemaVersion{
+		{Version: 1, Migrations: []int{}},
+		{Version: 2, Migrations: []int{100, 101}},
+		{Version: 3, Migrations: []int{100, 101}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SchemaVersions() = %+v, want %+v", got, want)
+	}
+	if v := CurrentSchemaVersion(p); v != 3 {
+		t.Errorf("CurrentSchemaVersion() = %d, want 3", v)
+	}
+	if migs := ClientMigrationsBetween(p, 1, 3); len(migs) != 2 || migs[0].Version != 2 || migs[1].Version != 3 {
+		t.Errorf("ClientMigrationsBetween(1, 3) = %+v, want versions 2 and 3 in order", migs)
+	}
+	if migs := ClientMigrationsBetween(p, 3, 3); migs != nil {
+		t.Errorf("ClientMigrationsBetween(3, 3) = %+v, want nil", migs)
+	}
+}
+
+func TestResolveSchemaVersion(t *testing.T) {
+	p := &versionedPlugin{stubPlugin{typeName: "versioned"}}
+
+	tests := []struct {
+		applied map[int]bool
+		want    int
+	}{
+		{map[int]bool{}, 0},
+		{map[int]bool{100: true}, 2},
+		{map[int]bool{100: true, 101: true}, 4},
+	}
+	for _, tt := range tests {
+		if got := ResolveSchemaVersion(p, tt.applied); got != tt.want {
+			t.Errorf("ResolveSchemaVersion(%v) = %d, want %d", tt.applied, got, tt.want)
+		}
+	}
+
+	if !SupportsSchemaVersion(p, 4) || SupportsSchemaVersion(p, 3) {
+		t.Error("SupportsSchemaVersion() should accept only declared versions")
+	}
+}
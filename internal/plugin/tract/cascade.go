@@ -0,0 +1,123 @@
+package tract
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/plugin"
+	"github.com/hyperengineering/engram/internal/sync"
+)
+
+// entityKey identifies an entity across tract tables.
+type entityKey struct {
+	table string
+	id    string
+}
+
+// ExpandCascade appends delete entries for every live descendant of a goal,
+// CSF, or FWU deleted in the batch (hard delete or soft-delete upsert).
+// Sub-goals are included. Descendants that the batch itself touches are
+// left alone so an explicit upsert is never overridden.
+//
+// The expanded batch is reordered so children are deleted before parents.
+// Tract tables are soft-delete, so the generated entries mark rows as
+// deleted rather than removing them.
+func (p *Plugin) ExpandCascade(ctx context.Context, reader plugin.LatestEntryReader, entries []sync.ChangeLogEntry) ([]sync.ChangeLogEntry, error) {
+	var roots []entityKey
+	inBatch := make(map[entityKey]bool, len(entries))
+	for _, e := range entries {
+		key := entityKey{e.TableName, e.EntityID}
+		inBatch[key] = true
+		if _, hierarchy := parentRefs[e.TableName]; !hierarchy {
+			continue
+		}
+		if e.Operation == sync.OperationDelete || isSoftDeleted(e.Payload) {
+			roots = append(roots, key)
+		}
+	}
+	if len(roots) == 0 {
+		return entries, nil
+	}
+
+	latest, err := reader.GetLatestEntries(ctx, RollupTables)
+	if err != nil {
+		return nil, fmt.Errorf("load tract state: %w", err)
+	}
+
+	// Current parent of every live entity, with the batch applied on top
+	parents := make(map[entityKey]entityKey)
+	apply := func(e sync.ChangeLogEntry) {
+		key := entityKey{e.TableName, e.EntityID}
+		ref, ok := parentRefs[e.TableName]
+		if !ok {
+			return
+		}
+		delete(parents, key)
+		if e.Operation != sync.OperationUpsert || isSoftDeleted(e.Payload) {
+			return
+		}
+		if parentID := extractString(e.Payload, ref.column); parentID != "" {
+			parents[key] = entityKey{ref.parentTable, parentID}
+		}
+	}
+	for _, e := range latest {
+		apply(e)
+	}
+	for _, e := range entries {
+		apply(e)
+	}
+
+	children := make(map[entityKey][]entityKey)
+	for child, parent := range parents {
+		children[parent] = append(children[parent], child)
+	}
+
+	visited := make(map[entityKey]bool)
+	var cascaded []entityKey
+	queue := roots
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		if visited[key] {
+			continue
+		}
+		visited[key] = true
+		for _, child := range children[key] {
+			if inBatch[child] || visited[child] {
+				continue
+			}
+			cascaded = append(cascaded, child)
+			queue = append(queue, child)
+		}
+	}
+	if len(cascaded) == 0 {
+		return entries, nil
+	}
+
+	// Deterministic output regardless of map iteration order
+	sort.Slice(cascaded, func(i, j int) bool {
+		if cascaded[i].table != cascaded[j].table {
+			return cascaded[i].table < cascaded[j].table
+		}
+		return cascaded[i].id < cascaded[j].id
+	})
+
+	now := time.Now().UTC()
+	expanded := make([]sync.ChangeLogEntry, 0, len(entries)+len(cascaded))
+	expanded = append(expanded, entries...)
+	for _, key := range cascaded {
+		expanded = append(expanded, sync.ChangeLogEntry{
+			TableName: key.table,
+			EntityID:  key.id,
+			Operation: sync.OperationDelete,
+			CreatedAt: now,
+		})
+	}
+
+	return reorderForFK(expanded), nil
+}
+
+// Ensure Plugin implements CascadeExpander at compile time.
+var _ plugin.CascadeExpander = (*Plugin)(nil)
@@ -0,0 +1,165 @@
+package tract
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/sync"
+)
+
+// fakeReader serves GetLatestEntries from a fixed slice.
+type fakeReader struct {
+	latest []sync.ChangeLogEntry
+	calls  int
+	err    error
+}
+
+func (f *fakeReader) GetLatestEntries(_ context.Context, _ []string) ([]sync.ChangeLogEntry, error) {
+	f.calls++
+	return f.latest, f.err
+}
+
+// cascadeFixture is g1 ─ g2 (sub-goal) ─ c2 ─ f2
+//
+//	└ c1 ─ f1 ─ ic1
+//
+// plus an unrelated g3 ─ c3.
+func cascadeFixture() []sync.ChangeLogEntry {
+	return []sync.ChangeLogEntry{
+		makeEntry("goals", "g1", sync.OperationUpsert, map[string]interface{}{"id": "g1", "parent_goal_id": nil}),
+		makeEntry("goals", "g2", sync.OperationUpsert, map[string]interface{}{"id": "g2", "parent_goal_id": "g1"}),
+		makeEntry("goals", "g3", sync.OperationUpsert, map[string]interface{}{"id": "g3", "parent_goal_id": nil}),
+		makeEntry("csfs", "c1", sync.OperationUpsert, map[string]interface{}{"id": "c1", "goal_id": "g1"}),
+		makeEntry("csfs", "c2", sync.OperationUpsert, map[string]interface{}{"id": "c2", "goal_id": "g2"}),
+		makeEntry("csfs", "c3", sync.OperationUpsert, map[string]interface{}{"id": "c3", "goal_id": "g3"}),
+		makeEntry("fwus", "f1", sync.OperationUpsert, map[string]interface{}{"id": "f1", "csf_id": "c1"}),
+		makeEntry("fwus", "f2", sync.OperationUpsert, map[string]interface{}{"id": "f2", "csf_id": "c2"}),
+		makeEntry("implementation_contexts", "ic1", sync.OperationUpsert, map[string]interface{}{"id": "ic1", "fwu_id": "f1"}),
+	}
+}
+
+// deletedIDs returns the entity IDs of delete entries in order.
+func deletedIDs(entries []sync.ChangeLogEntry) []string {
+	var ids []string
+	for _, e := range entries {
+		if e.Operation == sync.OperationDelete {
+			ids = append(ids, e.EntityID)
+		}
+	}
+	return ids
+}
+
+func TestExpandCascade_GoalDelete(t *testing.T) {
+	p := New()
+	reader := &fakeReader{latest: cascadeFixture()}
+	batch := []sync.ChangeLogEntry{{TableName: "goals", EntityID: "g1", Operation: sync.OperationDelete}}
+
+	got, err := p.ExpandCascade(context.Background(), reader, batch)
+	if err != nil {
+		t.Fatalf("ExpandCascade() error = %v", err)
+	}
+
+	// Children before parents; g3/c3 untouched
+	want := []string{"ic1", "f1", "f2", "c1", "c2", "g1", "g2"}
+	ids := deletedIDs(got)
+	if len(ids) != len(want) {
+		t.Fatalf("deletes = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("deletes[%d] = %s, want %s (all: %v)", i, ids[i], want[i], ids)
+		}
+	}
+	for _, e := range got {
+		if e.EntityID != "g1" && e.CreatedAt.IsZero() {
+			t.Errorf("cascaded entry %s missing CreatedAt", e.EntityID)
+		}
+	}
+}
+
+func TestExpandCascade_SoftDeleteUpsertCascades(t *testing.T) {
+	p := New()
+	reader := &fakeReader{latest: cascadeFixture()}
+	batch := []sync.ChangeLogEntry{
+		makeEntry("fwus", "f1", sync.OperationUpsert, map[string]interface{}{"id": "f1", "csf_id": "c1", "deleted_at": "2026-01-01T00:00:00Z"}),
+	}
+
+	got, err := p.ExpandCascade(context.Background(), reader, batch)
+	if err != nil {
+		t.Fatalf("ExpandCascade() error = %v", err)
+	}
+	if ids := deletedIDs(got); len(ids) != 1 || ids[0] != "ic1" {
+		t.Errorf("deletes = %v, want [ic1]", ids)
+	}
+	if len(got) != 2 {
+		t.Errorf("len = %d, want original upsert plus one cascade", len(got))
+	}
+}
+
+func TestExpandCascade_SkipsEntitiesInBatch(t *testing.T) {
+	p := New()
+	reader := &fakeReader{latest: cascadeFixture()}
+	batch := []sync.ChangeLogEntry{
+		{TableName: "csfs", EntityID: "c1", Operation: sync.OperationDelete},
+		// Client moves f1 to another CSF in the same push
+		makeEntry("fwus", "f1", sync.OperationUpsert, map[string]interface{}{"id": "f1", "csf_id": "c3"}),
+	}
+
+	got, err := p.ExpandCascade(context.Background(), reader, batch)
+	if err != nil {
+		t.Fatalf("ExpandCascade() error = %v", err)
+	}
+	if ids := deletedIDs(got); len(ids) != 1 || ids[0] != "c1" {
+		t.Errorf("deletes = %v, want only [c1]", ids)
+	}
+}
+
+func TestExpandCascade_NoDeletes(t *testing.T) {
+	p := New()
+	reader := &fakeReader{latest: cascadeFixture()}
+	batch := []sync.ChangeLogEntry{
+		makeEntry("goals", "g9", sync.OperationUpsert, map[string]interface{}{"id": "g9"}),
+		{TableName: "sos", EntityID: "s1", Operation: sync.OperationDelete},
+	}
+
+	got, err := p.ExpandCascade(context.Background(), reader, batch)
+	if err != nil {
+		t.Fatalf("ExpandCascade() error = %v", err)
+	}
+	if len(got) != len(batch) {
+		t.Errorf("len = %d, want batch unchanged", len(got))
+	}
+	if reader.calls != 0 {
+		t.Errorf("GetLatestEntries called %d times, want 0", reader.calls)
+	}
+}
+
+func TestExpandCascade_AlreadyDeletedChildren(t *testing.T) {
+	p := New()
+	latest := append(cascadeFixture(),
+		sync.ChangeLogEntry{TableName: "implementation_contexts", EntityID: "ic1", Operation: sync.OperationDelete})
+	// Latest-per-entity view: drop the superseded ic1 upsert
+	latest = append(latest[:8], latest[9:]...)
+	reader := &fakeReader{latest: latest}
+	batch := []sync.ChangeLogEntry{{TableName: "fwus", EntityID: "f1", Operation: sync.OperationDelete}}
+
+	got, err := p.ExpandCascade(context.Background(), reader, batch)
+	if err != nil {
+		t.Fatalf("ExpandCascade() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("len = %d, want no cascade for already-deleted IC", len(got))
+	}
+}
+
+func TestExpandCascade_ReaderError(t *testing.T) {
+	p := New()
+	readErr := errors.New("db down")
+	reader := &fakeReader{err: readErr}
+	batch := []sync.ChangeLogEntry{{TableName: "goals", EntityID: "g1", Operation: sync.OperationDelete}}
+
+	if _, err := p.ExpandCascade(context.Background(), reader, batch); !errors.Is(err, readErr) {
+		t.Errorf("error = %v, want wrapped reader error", err)
+	}
+}
@@ -21,13 +21,9 @@ func (p *Plugin) Type() string {
 	return "tract"
 }
 
-// Migrations returns domain-specific migrations for the Tract tables.
-func (p *Plugin) Migrations() []plugin.Migration {
-	return []plugin.Migration{
-		{
-			Version: 100,
-			Name:    "create_tract_tables",
-			UpSQL: `
+// tractTablesDDL creates the Tract tables. It is shared by the server
+// migration and the client replica migration.
+const tractTablesDDL = `
 CREATE TABLE IF NOT EXISTS goals (
     id              TEXT PRIMARY KEY,
     title           TEXT NOT NULL,
@@ -97,7 +93,15 @@ CREATE TABLE IF NOT EXISTS implementation_contexts (
 
 CREATE INDEX IF NOT EXISTS idx_ic_fwu ON implementation_contexts(fwu_id);
 CREATE INDEX IF NOT EXISTS idx_ic_deleted_at ON implementation_contexts(deleted_at);
-`,
+`
+
+// Migrations returns domain-specific migrations for the Tract tables.
+func (p *Plugin) Migrations() []plugin.Migration {
+	return []plugin.Migration{
+		{
+			Version: 100,
+			Name:    "create_tract_tables",
+			UpSQL:   tractTablesDDL,
 			DownSQL: `
 DROP TABLE IF EXISTS implementation_contexts;
 DROP TABLE IF EXISTS fwus;
@@ -108,6 +112,19 @@ DROP TABLE IF EXISTS goals;
 	}
 }
 
+// ClientMigrations returns forward migrations for Tract client replicas.
+// Schema version 2 introduced change_log sync; a version 1 client gets the
+// replicated tables created in its local database.
+func (p *Plugin) ClientMigrations() []sync.ClientMigration {
+	return []sync.ClientMigration{
+		{
+			Version: 2,
+			Name:    "create_tract_tables",
+			SQL:     tractTablesDDL,
+		},
+	}
+}
+
 // TableSchemas returns the schemas for all four Tract tables.
 func (p *Plugin) TableSchemas() []plugin.TableSchema {
 	return []plugin.TableSchema{
@@ -195,3 +212,4 @@ func (p *Plugin) OnReplay(ctx context.Context, store plugin.ReplayStore, entries
 
 // Ensure Plugin implements DomainPlugin at compile time.
 var _ plugin.DomainPlugin = (*Plugin)(nil)
+var _ plugin.ClientMigrationProvider = (*Plugin)(nil)
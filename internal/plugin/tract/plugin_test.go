@@ -45,6 +45,25 @@ func TestTractPlugin_Migrations_HasVersion100(t *testing.T) {
 	}
 }
 
+func TestTractPlugin_ClientMigrations_ApplyToEmptyReplica(t *testing.T) {
+	p := New()
+	migs := p.ClientMigrations()
+	if len(migs) != 1 || migs[0].Version != 2 {
+		t.Fatalf("ClientMigrations() = %+v, want one migration to version 2", migs)
+	}
+
+	db := newTestDB(t)
+	// Applying twice must be safe for clients that already have the tables
+	for i := 0; i < 2; i++ {
+		if _, err := db.Exec(migs[0].SQL); err != nil {
+			t.Fatalf("apply client migration (pass %d): %v", i+1, err)
+		}
+	}
+	if _, err := db.Exec(`SELECT id, parent_goal_id, deleted_at FROM goals LIMIT 0`); err != nil {
+		t.Errorf("goals table missing after migration: %v", err)
+	}
+}
+
 func newTestDB(t *testing.T) *sql.DB {
 	t.Helper()
 	db, err := sql.Open("sqlite", ":memory:")
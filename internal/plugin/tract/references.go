@@ -0,0 +1,134 @@
+package tract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperengineering/engram/internal/plugin"
+	"github.com/hyperengineering/engram/internal/sync"
+)
+
+// parentRef describes the foreign key from a table to its parent.
+type parentRef struct {
+	column      string
+	parentTable string
+}
+
+// parentRefs lists the hierarchy links checked by ValidateReferences.
+// Tables outside the goal→CSF→FWU→IC spine are not checked because their
+// schema is owned by the Tract CLI.
+var parentRefs = map[string]parentRef{
+	"goals":                   {column: "parent_goal_id", parentTable: "goals"},
+	"csfs":                    {column: "goal_id", parentTable: "goals"},
+	"fwus":                    {column: "csf_id", parentTable: "csfs"},
+	"implementation_contexts": {column: "fwu_id", parentTable: "fwus"},
+}
+
+// pendingRef is a child entry whose parent was not resolved within the batch.
+type pendingRef struct {
+	entry    sync.ChangeLogEntry
+	ref      parentRef
+	parentID string
+}
+
+// ValidateReferences rejects upserts whose parent is neither created in the
+// same batch nor live in the store. Parents deleted earlier in the batch
+// count as missing. Soft-delete payloads are not checked, so tombstones can
+// still be pushed for children of already-removed parents.
+func (p *Plugin) ValidateReferences(ctx context.Context, lookup plugin.EntityLookup, entries []sync.ChangeLogEntry) error {
+	// Final state of each entity touched by the batch (true = live)
+	inBatch := make(map[string]map[string]bool)
+	for _, e := range entries {
+		if inBatch[e.TableName] == nil {
+			inBatch[e.TableName] = make(map[string]bool)
+		}
+		inBatch[e.TableName][e.EntityID] = e.Operation == sync.OperationUpsert && !isSoftDeleted(e.Payload)
+	}
+
+	var pending []pendingRef
+	lookupIDs := make(map[string][]string)
+	for _, e := range entries {
+		ref, ok := parentRefs[e.TableName]
+		if !ok || e.Operation != sync.OperationUpsert || isSoftDeleted(e.Payload) {
+			continue
+		}
+		parentID := extractString(e.Payload, ref.column)
+		if parentID == "" {
+			continue
+		}
+		if live, seen := inBatch[ref.parentTable][parentID]; seen {
+			if !live {
+				pending = append(pending, pendingRef{entry: e, ref: ref, parentID: parentID})
+			}
+			continue
+		}
+		pending = append(pending, pendingRef{entry: e, ref: ref, parentID: parentID})
+		lookupIDs[ref.parentTable] = append(lookupIDs[ref.parentTable], parentID)
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	existing := make(map[string]map[string]bool, len(lookupIDs))
+	for table, ids := range lookupIDs {
+		found, err := lookup.ExistingEntityIDs(ctx, table, ids)
+		if err != nil {
+			return fmt.Errorf("lookup %s: %w", table, err)
+		}
+		existing[table] = found
+	}
+
+	var validationErrors []plugin.ValidationError
+	missingSet := make(map[string]map[string]bool)
+	for _, pr := range pending {
+		if existing[pr.ref.parentTable][pr.parentID] {
+			continue
+		}
+		validationErrors = append(validationErrors, plugin.ValidationError{
+			Sequence:  pr.entry.Sequence,
+			TableName: pr.entry.TableName,
+			EntityID:  pr.entry.EntityID,
+			Field:     pr.ref.column,
+			Message:   fmt.Sprintf("references missing %s %q", pr.ref.parentTable, pr.parentID),
+		})
+		if missingSet[pr.ref.parentTable] == nil {
+			missingSet[pr.ref.parentTable] = make(map[string]bool)
+		}
+		missingSet[pr.ref.parentTable][pr.parentID] = true
+	}
+
+	if len(validationErrors) == 0 {
+		return nil
+	}
+
+	missing := make(map[string][]string, len(missingSet))
+	for table, ids := range missingSet {
+		for id := range ids {
+			missing[table] = append(missing[table], id)
+		}
+		sort.Strings(missing[table])
+	}
+
+	return &plugin.MissingParentsError{
+		ValidationErrors: plugin.ValidationErrors{Errors: validationErrors},
+		Missing:          missing,
+	}
+}
+
+// extractString reads a string field from a JSON payload.
+// Returns "" if the field is null, missing, not a string, or the payload
+// is not parseable.
+func extractString(payload json.RawMessage, field string) string {
+	var data map[string]interface{}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return ""
+	}
+	s, _ := data[field].(string)
+	return s
+}
+
+// Ensure Plugin implements ReferenceValidator at compile time.
+var _ plugin.ReferenceValidator = (*Plugin)(nil)
@@ -0,0 +1,142 @@
+package tract
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/plugin"
+	"github.com/hyperengineering/engram/internal/sync"
+)
+
+// fakeLookup serves ExistingEntityIDs from a fixed set and records queries.
+type fakeLookup struct {
+	live    map[string]map[string]bool
+	queries []string
+	err     error
+}
+
+func (f *fakeLookup) ExistingEntityIDs(_ context.Context, table string, ids []string) (map[string]bool, error) {
+	f.queries = append(f.queries, table)
+	if f.err != nil {
+		return nil, f.err
+	}
+	found := make(map[string]bool)
+	for _, id := range ids {
+		if f.live[table][id] {
+			found[id] = true
+		}
+	}
+	return found, nil
+}
+
+func TestValidateReferences_ParentInBatch(t *testing.T) {
+	p := New()
+	lookup := &fakeLookup{}
+	entries := []sync.ChangeLogEntry{
+		makeEntry("goals", "g1", sync.OperationUpsert, map[string]interface{}{"id": "g1", "parent_goal_id": nil}),
+		makeEntry("csfs", "c1", sync.OperationUpsert, map[string]interface{}{"id": "c1", "goal_id": "g1"}),
+		makeEntry("fwus", "f1", sync.OperationUpsert, map[string]interface{}{"id": "f1", "csf_id": "c1"}),
+	}
+
+	if err := p.ValidateReferences(context.Background(), lookup, entries); err != nil {
+		t.Fatalf("ValidateReferences() error = %v", err)
+	}
+	if len(lookup.queries) != 0 {
+		t.Errorf("queries = %v, want none when all parents are in the batch", lookup.queries)
+	}
+}
+
+func TestValidateReferences_ParentInStore(t *testing.T) {
+	p := New()
+	lookup := &fakeLookup{live: map[string]map[string]bool{"goals": {"g1": true}}}
+	entries := []sync.ChangeLogEntry{
+		makeEntry("csfs", "c1", sync.OperationUpsert, map[string]interface{}{"id": "c1", "goal_id": "g1"}),
+	}
+
+	if err := p.ValidateReferences(context.Background(), lookup, entries); err != nil {
+		t.Fatalf("ValidateReferences() error = %v", err)
+	}
+}
+
+func TestValidateReferences_MissingParents(t *testing.T) {
+	p := New()
+	lookup := &fakeLookup{}
+	entries := []sync.ChangeLogEntry{
+		makeEntry("csfs", "c1", sync.OperationUpsert, map[string]interface{}{"id": "c1", "goal_id": "g9"}),
+		makeEntry("csfs", "c2", sync.OperationUpsert, map[string]interface{}{"id": "c2", "goal_id": "g9"}),
+		makeEntry("implementation_contexts", "ic1", sync.OperationUpsert, map[string]interface{}{"id": "ic1", "fwu_id": "f9"}),
+	}
+
+	err := p.ValidateReferences(context.Background(), lookup, entries)
+	var missing *plugin.MissingParentsError
+	if !errors.As(err, &missing) {
+		t.Fatalf("error = %v, want MissingParentsError", err)
+	}
+	if !errors.Is(err, plugin.ErrValidationFailed) {
+		t.Error("MissingParentsError should unwrap to ErrValidationFailed")
+	}
+	if len(missing.Errors) != 3 {
+		t.Errorf("Errors len = %d, want 3", len(missing.Errors))
+	}
+	if missing.Errors[0].Field != "goal_id" || missing.Errors[0].EntityID != "c1" {
+		t.Errorf("Errors[0] = %+v", missing.Errors[0])
+	}
+	if got := missing.Missing["goals"]; len(got) != 1 || got[0] != "g9" {
+		t.Errorf("Missing[goals] = %v, want [g9]", got)
+	}
+	if got := missing.Missing["fwus"]; len(got) != 1 || got[0] != "f9" {
+		t.Errorf("Missing[fwus] = %v, want [f9]", got)
+	}
+}
+
+func TestValidateReferences_ParentDeletedInBatch(t *testing.T) {
+	p := New()
+	lookup := &fakeLookup{live: map[string]map[string]bool{"goals": {"g1": true}}}
+	entries := []sync.ChangeLogEntry{
+		{TableName: "goals", EntityID: "g1", Operation: sync.OperationDelete},
+		makeEntry("csfs", "c1", sync.OperationUpsert, map[string]interface{}{"id": "c1", "goal_id": "g1"}),
+	}
+
+	var missing *plugin.MissingParentsError
+	if err := p.ValidateReferences(context.Background(), lookup, entries); !errors.As(err, &missing) {
+		t.Fatalf("error = %v, want MissingParentsError", err)
+	}
+}
+
+func TestValidateReferences_SkipsUncheckedEntries(t *testing.T) {
+	p := New()
+	lookup := &fakeLookup{}
+	entries := []sync.ChangeLogEntry{
+		// Root goal
+		makeEntry("goals", "g1", sync.OperationUpsert, map[string]interface{}{"id": "g1", "parent_goal_id": nil}),
+		// Soft-delete tombstone for an orphan
+		makeEntry("csfs", "c1", sync.OperationUpsert, map[string]interface{}{"id": "c1", "goal_id": "gone", "deleted_at": "2026-01-01T00:00:00Z"}),
+		// Hard delete
+		{TableName: "fwus", EntityID: "f1", Operation: sync.OperationDelete},
+		// Table outside the checked hierarchy
+		makeEntry("sos", "s1", sync.OperationUpsert, map[string]interface{}{"id": "s1", "csf_id": "nope"}),
+	}
+
+	if err := p.ValidateReferences(context.Background(), lookup, entries); err != nil {
+		t.Fatalf("ValidateReferences() error = %v", err)
+	}
+}
+
+func TestValidateReferences_LookupError(t *testing.T) {
+	p := New()
+	lookupErr := errors.New("db down")
+	lookup := &fakeLookup{err: lookupErr}
+	entries := []sync.ChangeLogEntry{
+		makeEntry("csfs", "c1", sync.OperationUpsert, map[string]interface{}{"id": "c1", "goal_id": "g1"}),
+	}
+
+	err := p.ValidateReferences(context.Background(), lookup, entries)
+	if !errors.Is(err, lookupErr) {
+		t.Fatalf("error = %v, want wrapped lookup error", err)
+	}
+	var missing *plugin.MissingParentsError
+	if errors.As(err, &missing) {
+		t.Error("lookup failure should not be reported as missing parents")
+	}
+}
@@ -0,0 +1,219 @@
+package tract
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/sync"
+)
+
+// ErrGoalNotFound is returned when a roll-up is requested for a goal that
+// does not exist or has been deleted.
+var ErrGoalNotFound = errors.New("goal not found")
+
+// DefaultStaleAfter is how long an implementation context may go without an
+// update before it is reported as stale.
+const DefaultStaleAfter = 14 * 24 * time.Hour
+
+// RollupTables lists the tables read to compute goal roll-ups.
+var RollupTables = []string{"goals", "csfs", "fwus", "implementation_contexts"}
+
+// fwuDoneStatuses are FWU statuses that count toward completion.
+var fwuDoneStatuses = map[string]bool{
+	"complete":  true,
+	"completed": true,
+	"done":      true,
+}
+
+// fwuBlockedStatus marks an FWU that cannot make progress.
+const fwuBlockedStatus = "blocked"
+
+// GoalSummary is the roll-up of a goal and all of its descendant goals over
+// the goal→CSF→FWU→IC hierarchy.
+type GoalSummary struct {
+	GoalID            string         `json:"goal_id"`
+	Title             string         `json:"title"`
+	Status            string         `json:"status"`
+	SubGoalIDs        []string       `json:"sub_goal_ids"`
+	CSFCount          int            `json:"csf_count"`
+	FWUCount          int            `json:"fwu_count"`
+	FWUCompleted      int            `json:"fwu_completed"`
+	FWUByStatus       map[string]int `json:"fwu_by_status"`
+	CompletionPercent float64        `json:"completion_percent"`
+	BlockedFWUs       []FWURef       `json:"blocked_fwus"`
+	StaleContexts     []ContextRef   `json:"stale_contexts"`
+	AsOfSequence      int64          `json:"as_of_sequence"`
+}
+
+// FWURef identifies an FWU in a roll-up.
+type FWURef struct {
+	ID    string `json:"id"`
+	CSFID string `json:"csf_id"`
+	Title string `json:"title"`
+}
+
+// ContextRef identifies an implementation context in a roll-up.
+type ContextRef struct {
+	ID        string    `json:"id"`
+	FWUID     string    `json:"fwu_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// rollupState is the current (non-deleted) state of the tract hierarchy.
+type rollupState struct {
+	goals    map[string]GoalPayload
+	csfs     []CSFPayload
+	fwus     []FWUPayload
+	contexts []icState
+	maxSeq   int64
+}
+
+type icState struct {
+	ICPayload
+	updatedAt time.Time
+}
+
+// BuildGoalSummary computes the roll-up for goalID from the latest change_log
+// entry per entity (see Store.GetLatestEntries). Tract does not replay into
+// domain tables, so the change log is the only authoritative source.
+//
+// Implementation contexts whose FWU is not complete and which have not been
+// updated within staleAfter of now are reported as stale.
+func BuildGoalSummary(goalID string, latest []sync.ChangeLogEntry, now time.Time, staleAfter time.Duration) (*GoalSummary, error) {
+	state := loadRollupState(latest)
+
+	goal, ok := state.goals[goalID]
+	if !ok {
+		return nil, ErrGoalNotFound
+	}
+
+	// Collect the goal and its descendants (guarding against cycles)
+	inScope := map[string]bool{goalID: true}
+	queue := []string{goalID}
+	var subGoals []string
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		for id, g := range state.goals {
+			if g.ParentGoalID != nil && *g.ParentGoalID == parent && !inScope[id] {
+				inScope[id] = true
+				subGoals = append(subGoals, id)
+				queue = append(queue, id)
+			}
+		}
+	}
+	sort.Strings(subGoals)
+
+	summary := &GoalSummary{
+		GoalID:        goal.ID,
+		Title:         goal.Title,
+		Status:        goal.Status,
+		SubGoalIDs:    subGoals,
+		FWUByStatus:   make(map[string]int),
+		BlockedFWUs:   []FWURef{},
+		StaleContexts: []ContextRef{},
+		AsOfSequence:  state.maxSeq,
+	}
+	if summary.SubGoalIDs == nil {
+		summary.SubGoalIDs = []string{}
+	}
+
+	csfInScope := make(map[string]bool)
+	for _, c := range state.csfs {
+		if inScope[c.GoalID] {
+			csfInScope[c.ID] = true
+			summary.CSFCount++
+		}
+	}
+
+	fwuDone := make(map[string]bool)
+	fwuInScope := make(map[string]bool)
+	for _, f := range state.fwus {
+		if !csfInScope[f.CSFID] {
+			continue
+		}
+		fwuInScope[f.ID] = true
+		summary.FWUCount++
+		summary.FWUByStatus[f.Status]++
+		if fwuDoneStatuses[f.Status] {
+			summary.FWUCompleted++
+			fwuDone[f.ID] = true
+		}
+		if f.Status == fwuBlockedStatus {
+			summary.BlockedFWUs = append(summary.BlockedFWUs, FWURef{ID: f.ID, CSFID: f.CSFID, Title: f.Title})
+		}
+	}
+	if summary.FWUCount > 0 {
+		summary.CompletionPercent = float64(summary.FWUCompleted) * 100 / float64(summary.FWUCount)
+	}
+
+	cutoff := now.Add(-staleAfter)
+	for _, ic := range state.contexts {
+		if !fwuInScope[ic.FWUID] || fwuDone[ic.FWUID] {
+			continue
+		}
+		if ic.updatedAt.Before(cutoff) {
+			summary.StaleContexts = append(summary.StaleContexts, ContextRef{ID: ic.ID, FWUID: ic.FWUID, UpdatedAt: ic.updatedAt})
+		}
+	}
+
+	return summary, nil
+}
+
+// loadRollupState decodes the latest entries into typed payloads, skipping
+// deleted entities and payloads that no longer decode.
+func loadRollupState(latest []sync.ChangeLogEntry) rollupState {
+	state := rollupState{goals: make(map[string]GoalPayload)}
+
+	for _, e := range latest {
+		if e.Sequence > state.maxSeq {
+			state.maxSeq = e.Sequence
+		}
+		if e.Operation == sync.OperationDelete || len(e.Payload) == 0 {
+			continue
+		}
+
+		switch e.TableName {
+		case "goals":
+			var g GoalPayload
+			if json.Unmarshal(e.Payload, &g) == nil && !isSoftDeleted(e.Payload) {
+				g.ID = e.EntityID
+				state.goals[g.ID] = g
+			}
+		case "csfs":
+			var c CSFPayload
+			if json.Unmarshal(e.Payload, &c) == nil && !isSoftDeleted(e.Payload) {
+				c.ID = e.EntityID
+				state.csfs = append(state.csfs, c)
+			}
+		case "fwus":
+			var f FWUPayload
+			if json.Unmarshal(e.Payload, &f) == nil && !isSoftDeleted(e.Payload) {
+				f.ID = e.EntityID
+				state.fwus = append(state.fwus, f)
+			}
+		case "implementation_contexts":
+			var ic ICPayload
+			if json.Unmarshal(e.Payload, &ic) == nil && !isSoftDeleted(e.Payload) {
+				ic.ID = e.EntityID
+				updated, err := time.Parse(time.RFC3339Nano, ic.UpdatedAt)
+				if err != nil {
+					updated = e.CreatedAt
+				}
+				state.contexts = append(state.contexts, icState{ICPayload: ic, updatedAt: updated})
+			}
+		}
+	}
+
+	return state
+}
+
+// isSoftDeleted reports whether a payload carries a non-null deleted_at.
+func isSoftDeleted(payload json.RawMessage) bool {
+	var row struct {
+		DeletedAt *string `json:"deleted_at"`
+	}
+	return json.Unmarshal(payload, &row) == nil && row.DeletedAt != nil && *row.DeletedAt != ""
+}
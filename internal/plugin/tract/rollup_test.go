@@ -0,0 +1,168 @@
+package tract
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/sync"
+)
+
+var rollupNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// rollupFixture builds a goal hierarchy:
+//
+//	g1 ─ c1 ─ f1 (done), f2 (blocked) ─ ic2 (stale)
+//	   └ g2 ─ c2 ─ f3 (in_progress) ─ ic3 (fresh)
+//	g3 ─ c3 ─ f4 (blocked)
+func rollupFixture() []sync.ChangeLogEntry {
+	old := rollupNow.Add(-30 * 24 * time.Hour).Format(time.RFC3339)
+	fresh := rollupNow.Add(-time.Hour).Format(time.RFC3339)
+
+	entries := []sync.ChangeLogEntry{
+		makeEntry("goals", "g1", sync.OperationUpsert, map[string]interface{}{"id": "g1", "title": "Ship v2", "status": "active", "parent_goal_id": nil}),
+		makeEntry("goals", "g2", sync.OperationUpsert, map[string]interface{}{"id": "g2", "title": "Sub", "status": "active", "parent_goal_id": "g1"}),
+		makeEntry("goals", "g3", sync.OperationUpsert, map[string]interface{}{"id": "g3", "title": "Other", "status": "active", "parent_goal_id": nil}),
+		makeEntry("csfs", "c1", sync.OperationUpsert, map[string]interface{}{"id": "c1", "goal_id": "g1", "title": "C1", "status": "active"}),
+		makeEntry("csfs", "c2", sync.OperationUpsert, map[string]interface{}{"id": "c2", "goal_id": "g2", "title": "C2", "status": "active"}),
+		makeEntry("csfs", "c3", sync.OperationUpsert, map[string]interface{}{"id": "c3", "goal_id": "g3", "title": "C3", "status": "active"}),
+		makeEntry("fwus", "f1", sync.OperationUpsert, map[string]interface{}{"id": "f1", "csf_id": "c1", "title": "F1", "status": "completed"}),
+		makeEntry("fwus", "f2", sync.OperationUpsert, map[string]interface{}{"id": "f2", "csf_id": "c1", "title": "F2", "status": "blocked"}),
+		makeEntry("fwus", "f3", sync.OperationUpsert, map[string]interface{}{"id": "f3", "csf_id": "c2", "title": "F3", "status": "in_progress"}),
+		makeEntry("fwus", "f4", sync.OperationUpsert, map[string]interface{}{"id": "f4", "csf_id": "c3", "title": "F4", "status": "blocked"}),
+		makeEntry("implementation_contexts", "ic1", sync.OperationUpsert, map[string]interface{}{"id": "ic1", "fwu_id": "f1", "updated_at": old}),
+		makeEntry("implementation_contexts", "ic2", sync.OperationUpsert, map[string]interface{}{"id": "ic2", "fwu_id": "f2", "updated_at": old}),
+		makeEntry("implementation_contexts", "ic3", sync.OperationUpsert, map[string]interface{}{"id": "ic3", "fwu_id": "f3", "updated_at": fresh}),
+	}
+	for i := range entries {
+		entries[i].Sequence = int64(i + 1)
+	}
+	return entries
+}
+
+func TestBuildGoalSummary_RollsUpDescendants(t *testing.T) {
+	s, err := BuildGoalSummary("g1", rollupFixture(), rollupNow, DefaultStaleAfter)
+	if err != nil {
+		t.Fatalf("BuildGoalSummary() error = %v", err)
+	}
+
+	if s.Title != "Ship v2" || s.Status != "active" {
+		t.Errorf("goal = %q/%q, want Ship v2/active", s.Title, s.Status)
+	}
+	if len(s.SubGoalIDs) != 1 || s.SubGoalIDs[0] != "g2" {
+		t.Errorf("SubGoalIDs = %v, want [g2]", s.SubGoalIDs)
+	}
+	if s.CSFCount != 2 {
+		t.Errorf("CSFCount = %d, want 2", s.CSFCount)
+	}
+	if s.FWUCount != 3 || s.FWUCompleted != 1 {
+		t.Errorf("FWUCount/FWUCompleted = %d/%d, want 3/1", s.FWUCount, s.FWUCompleted)
+	}
+	if s.FWUByStatus["blocked"] != 1 || s.FWUByStatus["in_progress"] != 1 || s.FWUByStatus["completed"] != 1 {
+		t.Errorf("FWUByStatus = %v", s.FWUByStatus)
+	}
+	if want := 100.0 / 3; s.CompletionPercent != want {
+		t.Errorf("CompletionPercent = %v, want %v", s.CompletionPercent, want)
+	}
+	if s.AsOfSequence != 13 {
+		t.Errorf("AsOfSequence = %d, want 13", s.AsOfSequence)
+	}
+}
+
+func TestBuildGoalSummary_BlockedFWUs(t *testing.T) {
+	s, err := BuildGoalSummary("g1", rollupFixture(), rollupNow, DefaultStaleAfter)
+	if err != nil {
+		t.Fatalf("BuildGoalSummary() error = %v", err)
+	}
+
+	// f4 is blocked but belongs to an unrelated goal
+	if len(s.BlockedFWUs) != 1 || s.BlockedFWUs[0].ID != "f2" || s.BlockedFWUs[0].CSFID != "c1" {
+		t.Errorf("BlockedFWUs = %+v, want [f2]", s.BlockedFWUs)
+	}
+}
+
+func TestBuildGoalSummary_StaleContexts(t *testing.T) {
+	s, err := BuildGoalSummary("g1", rollupFixture(), rollupNow, DefaultStaleAfter)
+	if err != nil {
+		t.Fatalf("BuildGoalSummary() error = %v", err)
+	}
+
+	// ic1 is old but its FWU is complete; ic3 is recent
+	if len(s.StaleContexts) != 1 || s.StaleContexts[0].ID != "ic2" {
+		t.Errorf("StaleContexts = %+v, want [ic2]", s.StaleContexts)
+	}
+
+	// A short threshold makes the in-progress context stale too
+	s, err = BuildGoalSummary("g1", rollupFixture(), rollupNow, time.Minute)
+	if err != nil {
+		t.Fatalf("BuildGoalSummary() error = %v", err)
+	}
+	if len(s.StaleContexts) != 2 {
+		t.Errorf("StaleContexts = %+v, want 2 entries", s.StaleContexts)
+	}
+}
+
+func TestBuildGoalSummary_SubGoalOnly(t *testing.T) {
+	s, err := BuildGoalSummary("g2", rollupFixture(), rollupNow, DefaultStaleAfter)
+	if err != nil {
+		t.Fatalf("BuildGoalSummary() error = %v", err)
+	}
+	if len(s.SubGoalIDs) != 0 || s.FWUCount != 1 || s.CompletionPercent != 0 {
+		t.Errorf("summary = %+v, want single in-progress FWU", s)
+	}
+}
+
+func TestBuildGoalSummary_SkipsDeleted(t *testing.T) {
+	entries := rollupFixture()
+	entries = append(entries,
+		sync.ChangeLogEntry{Sequence: 14, TableName: "fwus", EntityID: "f2", Operation: sync.OperationDelete},
+		makeEntry("fwus", "f3", sync.OperationUpsert, map[string]interface{}{"id": "f3", "csf_id": "c2", "title": "F3", "status": "in_progress", "deleted_at": "2026-02-01T00:00:00Z"}),
+	)
+	// Emulate GetLatestEntries: drop superseded versions of f2 and f3
+	latest := entries[:0]
+	for _, e := range entries {
+		if e.Sequence == 8 || e.Sequence == 9 {
+			continue
+		}
+		latest = append(latest, e)
+	}
+
+	s, err := BuildGoalSummary("g1", latest, rollupNow, DefaultStaleAfter)
+	if err != nil {
+		t.Fatalf("BuildGoalSummary() error = %v", err)
+	}
+	if s.FWUCount != 1 || s.CompletionPercent != 100 {
+		t.Errorf("FWUCount/CompletionPercent = %d/%v, want 1/100", s.FWUCount, s.CompletionPercent)
+	}
+	if len(s.BlockedFWUs) != 0 || len(s.StaleContexts) != 0 {
+		t.Errorf("deleted FWUs should not be reported: %+v", s)
+	}
+}
+
+func TestBuildGoalSummary_NotFound(t *testing.T) {
+	entries := append(rollupFixture(),
+		sync.ChangeLogEntry{Sequence: 14, TableName: "goals", EntityID: "g3", Operation: sync.OperationDelete})
+
+	if _, err := BuildGoalSummary("missing", entries, rollupNow, DefaultStaleAfter); !errors.Is(err, ErrGoalNotFound) {
+		t.Errorf("missing goal: error = %v, want ErrGoalNotFound", err)
+	}
+
+	// Emulate GetLatestEntries: the tombstone supersedes g3's upsert
+	latest := append(entries[1:2:2], entries[3:]...)
+	if _, err := BuildGoalSummary("g3", latest, rollupNow, DefaultStaleAfter); !errors.Is(err, ErrGoalNotFound) {
+		t.Errorf("deleted goal: error = %v, want ErrGoalNotFound", err)
+	}
+}
+
+func TestBuildGoalSummary_NoFWUs(t *testing.T) {
+	entries := []sync.ChangeLogEntry{
+		makeEntry("goals", "g1", sync.OperationUpsert, map[string]interface{}{"id": "g1", "title": "Empty", "status": "active"}),
+	}
+	s, err := BuildGoalSummary("g1", entries, rollupNow, DefaultStaleAfter)
+	if err != nil {
+		t.Fatalf("BuildGoalSummary() error = %v", err)
+	}
+	if s.CompletionPercent != 0 || s.SubGoalIDs == nil || s.BlockedFWUs == nil || s.StaleContexts == nil {
+		t.Errorf("empty summary = %+v, want zero completion and non-nil slices", s)
+	}
+}
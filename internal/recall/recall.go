@@ -0,0 +1,216 @@
+// Package recall assembles ranked lore into bundles that fit an agent's
+// context window.
+//
+// A bundle keeps the search ranking, drops entries that repeat a
+// higher-ranked one, and stops at the token budget, cutting the entry that
+// crosses it short when enough budget remains to make that worthwhile.
+package recall
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// DuplicateOverlap is the share of words two entries must have in common
+// (Jaccard similarity) for the lower-ranked one to be dropped.
+const DuplicateOverlap = 0.8
+
+// minTruncatedTokens is the smallest remaining budget worth spending on a
+// truncated entry; below it the entry is left out instead.
+const minTruncatedTokens = 24
+
+// markdownHeader opens a markdown bundle.
+const markdownHeader = "## Relevant lore\n"
+
+// ellipsis marks truncated content.
+const ellipsis = "…"
+
+// EstimateTokens returns a conservative token count for text, assuming
+// three bytes per token where English prose averages about four. Bundles
+// sized with it stay within budget for any common tokenizer.
+func EstimateTokens(text string) int {
+	return len(text)/3 + 1
+}
+
+// Bundle builds a recall bundle of at most budget estimated tokens from
+// results, which must be ordered most relevant first. format is
+// types.RecallFormatMarkdown or types.RecallFormatJSON.
+func Bundle(results []types.SearchResult, budget int, format string) *types.RecallResponse {
+	resp := &types.RecallResponse{
+		Format:      format,
+		Entries:     make([]types.RecallEntry, 0),
+		TokenBudget: budget,
+	}
+
+	cost := markdownCost
+	used := EstimateTokens(markdownHeader)
+	if format == types.RecallFormatJSON {
+		cost = jsonCost
+		used = 1 // the enclosing brackets
+	}
+
+	var kept [][]string
+	for _, r := range results {
+		words := wordSet(r.Lore.Content)
+		if duplicatesAny(words, kept) {
+			resp.DuplicatesDropped++
+			continue
+		}
+
+		e := types.RecallEntry{
+			ID:         r.Lore.ID,
+			Category:   string(r.Lore.Category),
+			Content:    r.Lore.Content,
+			Context:    r.Lore.Context,
+			Confidence: r.Lore.Confidence,
+		}
+		if n := cost(e); used+n <= budget {
+			resp.Entries = append(resp.Entries, e)
+			kept = append(kept, words)
+			used += n
+			continue
+		}
+
+		// The budget is spent: cut this entry short if there is room left
+		// for a useful part of it, and leave out the rest.
+		if remaining := budget - used; remaining >= minTruncatedTokens {
+			if t, ok := truncate(e, remaining, cost); ok {
+				resp.Entries = append(resp.Entries, t)
+				used += cost(t)
+			}
+		}
+		resp.Truncated = true
+		break
+	}
+
+	if len(resp.Entries) == 0 {
+		return resp
+	}
+	if format == types.RecallFormatJSON {
+		resp.EstimatedTokens = used
+		return resp
+	}
+	resp.Content = renderMarkdown(resp.Entries)
+	resp.EstimatedTokens = EstimateTokens(resp.Content)
+	return resp
+}
+
+// truncate shortens e's content, dropping its context, until the entry
+// costs at most budget tokens. It reports false if not even a few words fit.
+func truncate(e types.RecallEntry, budget int, cost func(types.RecallEntry) int) (types.RecallEntry, bool) {
+	e.Context = ""
+	e.Truncated = true
+	content := e.Content
+	for {
+		// Cut by the bytes the excess tokens stand for, then back to a word
+		// boundary; repeat while escaping or rounding leaves it over.
+		e.Content = content
+		over := cost(e) - budget
+		if over <= 0 {
+			return e, true
+		}
+		n := len(content) - over*3
+		if n <= 0 {
+			return e, false
+		}
+		for n > 0 && !utf8.RuneStart(content[n]) {
+			n--
+		}
+		if i := strings.LastIndexFunc(content[:n], unicode.IsSpace); i > 0 {
+			n = i
+		}
+		content = strings.TrimRightFunc(content[:n], unicode.IsSpace)
+		content = strings.TrimSuffix(content, ellipsis)
+		if content == "" {
+			return e, false
+		}
+		content += ellipsis
+	}
+}
+
+// markdownCost estimates the tokens e adds to a markdown bundle.
+func markdownCost(e types.RecallEntry) int {
+	return EstimateTokens(markdownEntry(e))
+}
+
+// jsonCost estimates the tokens e adds to a JSON bundle, separator included.
+func jsonCost(e types.RecallEntry) int {
+	b, _ := json.Marshal(e)
+	return EstimateTokens(string(b)) + 1
+}
+
+// renderMarkdown renders entries as a bulleted list under a heading.
+func renderMarkdown(entries []types.RecallEntry) string {
+	var b strings.Builder
+	b.WriteString(markdownHeader)
+	for _, e := range entries {
+		b.WriteString(markdownEntry(e))
+	}
+	return b.String()
+}
+
+// markdownEntry renders one entry as a list item, with its context
+// indented beneath it.
+func markdownEntry(e types.RecallEntry) string {
+	s := fmt.Sprintf("\n- **%s** (confidence %.2f): %s\n", e.Category, e.Confidence, oneLine(e.Content))
+	if e.Context != "" {
+		s += fmt.Sprintf("  Context: %s\n", oneLine(e.Context))
+	}
+	return s
+}
+
+// oneLine collapses whitespace runs, including newlines, to single spaces
+// so multi-line content stays inside its list item.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// wordSet returns the distinct lower-cased words of s.
+func wordSet(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := make(map[string]bool, len(fields))
+	words := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if !seen[f] {
+			seen[f] = true
+			words = append(words, f)
+		}
+	}
+	return words
+}
+
+// duplicatesAny reports whether words overlaps any of kept by at least
+// DuplicateOverlap.
+func duplicatesAny(words []string, kept [][]string) bool {
+	for _, k := range kept {
+		if jaccard(words, k) >= DuplicateOverlap {
+			return true
+		}
+	}
+	return false
+}
+
+// jaccard returns the Jaccard similarity of two word sets.
+func jaccard(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	in := make(map[string]bool, len(a))
+	for _, w := range a {
+		in[w] = true
+	}
+	shared := 0
+	for _, w := range b {
+		if in[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,107 @@
+package recall
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func result(id, content string) types.SearchResult {
+	return types.SearchResult{Lore: types.LoreEntry{
+		ID:         id,
+		Category:   "PATTERN_OUTCOME",
+		Content:    content,
+		Confidence: 0.8,
+	}}
+}
+
+func TestBundle_MarkdownKeepsRankingAndDropsDuplicates(t *testing.T) {
+	results := []types.SearchResult{
+		result("a", "Retry payment webhooks with exponential backoff"),
+		result("b", "Retry payment webhooks with exponential backoff!"),
+		result("c", "The billing SDK caches tokens for an hour"),
+	}
+	results[2].Lore.Context = "seen in\nbilling"
+
+	resp := Bundle(results, 1000, types.RecallFormatMarkdown)
+
+	if resp.DuplicatesDropped != 1 || resp.Truncated {
+		t.Errorf("duplicates = %d, truncated = %v", resp.DuplicatesDropped, resp.Truncated)
+	}
+	if len(resp.Entries) != 2 || resp.Entries[0].ID != "a" || resp.Entries[1].ID != "c" {
+		t.Fatalf("entries = %+v, want a then c", resp.Entries)
+	}
+	want := "## Relevant lore\n" +
+		"\n- **PATTERN_OUTCOME** (confidence 0.80): Retry payment webhooks with exponential backoff\n" +
+		"\n- **PATTERN_OUTCOME** (confidence 0.80): The billing SDK caches tokens for an hour\n" +
+		"  Context: seen in billing\n"
+	if resp.Content != want {
+		t.Errorf("content =\n%s\nwant\n%s", resp.Content, want)
+	}
+	if resp.EstimatedTokens != EstimateTokens(want) || resp.TokenBudget != 1000 {
+		t.Errorf("estimated = %d, budget = %d", resp.EstimatedTokens, resp.TokenBudget)
+	}
+}
+
+func TestBundle_TruncatesAtBudget(t *testing.T) {
+	long := strings.Repeat("backoff retries must be capped ", 40)
+	results := []types.SearchResult{
+		result("a", "Retry payment webhooks with exponential backoff"),
+		result("b", long),
+		result("c", "Never reached"),
+	}
+
+	for _, format := range []string{types.RecallFormatMarkdown, types.RecallFormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			resp := Bundle(results, 120, format)
+
+			if !resp.Truncated {
+				t.Error("truncated = false, want true")
+			}
+			if len(resp.Entries) != 2 {
+				t.Fatalf("got %d entries, want 2", len(resp.Entries))
+			}
+			cut := resp.Entries[1]
+			if !cut.Truncated || !strings.HasSuffix(cut.Content, ellipsis) || len(cut.Content) >= len(long) {
+				t.Errorf("second entry not truncated: %+v", cut)
+			}
+			if resp.EstimatedTokens > 120 {
+				t.Errorf("estimated tokens %d exceed budget", resp.EstimatedTokens)
+			}
+			if format == types.RecallFormatJSON {
+				b, _ := json.Marshal(resp.Entries)
+				if EstimateTokens(string(b)) > 120 {
+					t.Errorf("JSON entries (%d bytes) exceed budget", len(b))
+				}
+			} else if EstimateTokens(resp.Content) > 120 {
+				t.Errorf("content (%d bytes) exceeds budget", len(resp.Content))
+			}
+		})
+	}
+}
+
+func TestBundle_LeavesOutEntryWhenLittleBudgetRemains(t *testing.T) {
+	results := []types.SearchResult{
+		result("a", "Retry payment webhooks with exponential backoff"),
+		result("b", strings.Repeat("word ", 100)),
+	}
+	first := EstimateTokens(markdownHeader) + markdownCost(types.RecallEntry{
+		ID: "a", Category: "PATTERN_OUTCOME", Content: results[0].Lore.Content, Confidence: 0.8,
+	})
+
+	resp := Bundle(results, first+minTruncatedTokens-1, types.RecallFormatMarkdown)
+
+	if len(resp.Entries) != 1 || !resp.Truncated {
+		t.Errorf("entries = %d, truncated = %v; want 1, true", len(resp.Entries), resp.Truncated)
+	}
+}
+
+func TestBundle_NothingFits(t *testing.T) {
+	resp := Bundle([]types.SearchResult{result("a", "Retry")}, 1, types.RecallFormatMarkdown)
+
+	if len(resp.Entries) != 0 || resp.Content != "" || resp.EstimatedTokens != 0 || !resp.Truncated {
+		t.Errorf("unexpected bundle: %+v", resp)
+	}
+}
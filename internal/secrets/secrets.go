@@ -0,0 +1,102 @@
+// Package secrets reads credentials from files, such as Docker and
+// Kubernetes secrets or files rendered by Vault Agent or a KMS decryption
+// sidecar, so they stay out of the environment, where they show up in
+// process listings and crash dumps.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrEmpty is returned for a secret file that holds nothing but whitespace.
+var ErrEmpty = errors.New("secret file is empty")
+
+// ReadFile reads the secret in the file at path, trimming surrounding
+// whitespace such as the trailing newline most tools write.
+func ReadFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read secret file: %w", err)
+	}
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", fmt.Errorf("%w: %s", ErrEmpty, path)
+	}
+	return secret, nil
+}
+
+// File is a secret kept in a file and read again once it is older than
+// the refresh interval, so the secret can be rotated by replacing the file
+// without restarting. When a re-read fails, as while the file is being
+// replaced, the previous secret stays in use.
+type File struct {
+	path    string
+	refresh time.Duration
+
+	mu      sync.Mutex
+	value   string
+	checked time.Time
+}
+
+// NewFile reads the secret at path. A non-positive refresh never reads it
+// again.
+func NewFile(path string, refresh time.Duration) (*File, error) {
+	value, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &File{path: path, refresh: refresh, value: value, checked: time.Now()}, nil
+}
+
+// Path returns the path of the secret file.
+func (f *File) Path() string {
+	return f.path
+}
+
+// Value returns the secret, first reading the file again if the refresh
+// interval has passed since it was last read.
+func (f *File) Value() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.refresh <= 0 || time.Since(f.checked) < f.refresh {
+		return f.value
+	}
+	f.checked = time.Now()
+
+	value, err := ReadFile(f.path)
+	if err != nil {
+		slog.Warn("secret file unreadable, keeping the previous secret",
+			"component", "secrets",
+			"path", f.path,
+			"error", err,
+		)
+		return f.value
+	}
+	if value != f.value {
+		slog.Info("secret rotated",
+			"component", "secrets",
+			"path", f.path,
+		)
+		f.value = value
+	}
+	return f.value
+}
+
+// Source returns a function yielding the secret from file, refreshed as by
+// File, or value if file is empty.
+func Source(value, file string, refresh time.Duration) (func() string, error) {
+	if file == "" {
+		return func() string { return value }, nil
+	}
+	f, err := NewFile(file, refresh)
+	if err != nil {
+		return nil, err
+	}
+	return f.Value, nil
+}
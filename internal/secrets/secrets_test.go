@@ -0,0 +1,78 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSecret(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api_key")
+
+	writeSecret(t, path, "s3cret\n")
+	if got, err := ReadFile(path); err != nil || got != "s3cret" {
+		t.Errorf("ReadFile() = %q, %v, want s3cret", got, err)
+	}
+
+	writeSecret(t, path, " \n")
+	if _, err := ReadFile(path); !errors.Is(err, ErrEmpty) {
+		t.Errorf("ReadFile() of blank file error = %v, want ErrEmpty", err)
+	}
+
+	if _, err := ReadFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("ReadFile() of missing file error = nil")
+	}
+}
+
+func TestFile_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api_key")
+	writeSecret(t, path, "first")
+
+	f, err := NewFile(path, 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewFile() error = %v", err)
+	}
+
+	writeSecret(t, path, "second")
+	if got := f.Value(); got != "first" {
+		t.Errorf("Value() before refresh = %q, want first", got)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if got := f.Value(); got != "second" {
+		t.Errorf("Value() after refresh = %q, want second", got)
+	}
+
+	// A file caught mid-replacement keeps the previous secret
+	writeSecret(t, path, "")
+	time.Sleep(30 * time.Millisecond)
+	if got := f.Value(); got != "second" {
+		t.Errorf("Value() with empty file = %q, want second", got)
+	}
+}
+
+func TestSource(t *testing.T) {
+	src, err := Source("from-env", "", time.Minute)
+	if err != nil || src() != "from-env" {
+		t.Errorf("Source() without file = %v, want the value", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "api_key")
+	writeSecret(t, path, "from-file")
+	src, err = Source("from-env", path, time.Minute)
+	if err != nil || src() != "from-file" {
+		t.Errorf("Source() with file = %v, want the file's secret", err)
+	}
+
+	if _, err := Source("", filepath.Join(t.TempDir(), "missing"), time.Minute); err == nil {
+		t.Error("Source() with missing file error = nil")
+	}
+}
@@ -0,0 +1,82 @@
+package snapshot
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// throttleBurst is the largest read passed through before the limiter
+// waits, so a single large read can't exceed the rate for long.
+const throttleBurst = 64 << 10
+
+// bandwidthLimiter spreads the bytes of concurrent uploads over time so
+// together they average at most bytesPerSec.
+type bandwidthLimiter struct {
+	bytesPerSec int64
+
+	mu   sync.Mutex
+	next time.Time // when the bytes reserved so far have been sent
+}
+
+// newBandwidthLimiter returns a limiter for bytesPerSec, or nil if
+// bytesPerSec isn't positive.
+func newBandwidthLimiter(bytesPerSec int64) *bandwidthLimiter {
+	if bytesPerSec <= 0 {
+		return nil
+	}
+	return &bandwidthLimiter{bytesPerSec: bytesPerSec}
+}
+
+// wait blocks until n more bytes may be sent, or ctx ends.
+func (l *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	now := time.Now()
+	l.mu.Lock()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(int64(n) * int64(time.Second) / l.bytesPerSec))
+	delay := l.next.Sub(now)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// reader returns r read no faster than the limiter allows. A nil limiter
+// returns r.
+func (l *bandwidthLimiter) reader(ctx context.Context, r io.Reader) io.Reader {
+	if l == nil {
+		return r
+	}
+	return &throttledReader{ctx: ctx, r: r, limiter: l}
+}
+
+type throttledReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *bandwidthLimiter
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > throttleBurst {
+		p = p[:throttleBurst]
+	}
+	n, err := t.r.Read(p)
+	if n > 0 {
+		if werr := t.limiter.wait(t.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
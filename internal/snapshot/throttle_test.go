@@ -0,0 +1,49 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestBandwidthLimiter_LimitsReadRate(t *testing.T) {
+	l := newBandwidthLimiter(1 << 20) // 1 MiB/s
+	data := make([]byte, 256<<10)
+
+	start := time.Now()
+	n, err := io.Copy(io.Discard, l.reader(context.Background(), bytes.NewReader(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(data)) {
+		t.Fatalf("copied %d bytes, want %d", n, len(data))
+	}
+	// 256 KiB at 1 MiB/s takes a quarter of a second
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("read took %v, want at least 200ms", elapsed)
+	}
+}
+
+func TestBandwidthLimiter_CancelledContext(t *testing.T) {
+	l := newBandwidthLimiter(1024)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := io.Copy(io.Discard, l.reader(ctx, bytes.NewReader(make([]byte, 4096))))
+	if err != context.Canceled {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestBandwidthLimiter_UnlimitedIsPassThrough(t *testing.T) {
+	if l := newBandwidthLimiter(0); l != nil {
+		t.Fatal("a zero rate should disable the limiter")
+	}
+	r := bytes.NewReader(nil)
+	var l *bandwidthLimiter
+	if got := l.reader(context.Background(), r); got != io.Reader(r) {
+		t.Error("a nil limiter should return the reader unchanged")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"strings"
 	"time"
 
@@ -35,6 +36,8 @@ type Uploader interface {
 type s3Client interface {
 	FPutObject(ctx context.Context, bucket, objectName, filePath string, opts interface{}) error
 	PresignedGetObject(ctx context.Context, bucket, objectName string, expiry time.Duration) (*url.URL, error)
+	FGetObject(ctx context.Context, bucket, objectName, filePath string) error
+	ListObjects(ctx context.Context, bucket string) ([]string, error)
 }
 
 // minioClientWrapper wraps *minio.Client to satisfy the s3Client interface.
@@ -42,16 +45,48 @@ type s3Client interface {
 // that differ from our simplified interface.
 type minioClientWrapper struct {
 	client *minio.Client
+	// limiter caps the upload bandwidth shared by all uploads; nil means
+	// unlimited.
+	limiter *bandwidthLimiter
 }
 
 func (w *minioClientWrapper) FPutObject(ctx context.Context, bucket, objectName, filePath string, opts interface{}) error {
 	putOpts := minio.PutObjectOptions{
 		ContentType: "application/octet-stream",
 	}
-	_, err := w.client.FPutObject(ctx, bucket, objectName, filePath, putOpts)
+	if w.limiter == nil {
+		_, err := w.client.FPutObject(ctx, bucket, objectName, filePath, putOpts)
+		return err
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	_, err = w.client.PutObject(ctx, bucket, objectName, w.limiter.reader(ctx, f), info.Size(), putOpts)
 	return err
 }
 
+func (w *minioClientWrapper) FGetObject(ctx context.Context, bucket, objectName, filePath string) error {
+	return w.client.FGetObject(ctx, bucket, objectName, filePath, minio.GetObjectOptions{})
+}
+
+func (w *minioClientWrapper) ListObjects(ctx context.Context, bucket string) ([]string, error) {
+	var keys []string
+	for obj := range w.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Recursive: true}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		keys = append(keys, obj.Key)
+	}
+	return keys, nil
+}
+
 func (w *minioClientWrapper) PresignedGetObject(ctx context.Context, bucket, objectName string, expiry time.Duration) (*url.URL, error) {
 	return w.client.PresignedGetObject(ctx, bucket, objectName, expiry, nil)
 }
@@ -83,6 +118,45 @@ func (u *S3Uploader) PresignedURL(ctx context.Context, storeID string) (string,
 	return presigned.String(), expiry, nil
 }
 
+// UploadArchive uploads the compressed archive of a dormant store.
+func (u *S3Uploader) UploadArchive(ctx context.Context, storeID string, filePath string) error {
+	if err := u.client.FPutObject(ctx, u.bucket, archiveKey(storeID), filePath, nil); err != nil {
+		return fmt.Errorf("upload archive to S3: %w", err)
+	}
+	return nil
+}
+
+// DownloadArchive downloads the compressed archive of a store to filePath.
+func (u *S3Uploader) DownloadArchive(ctx context.Context, storeID string, filePath string) error {
+	if err := u.client.FGetObject(ctx, u.bucket, archiveKey(storeID), filePath); err != nil {
+		return fmt.Errorf("download archive from S3: %w", err)
+	}
+	return nil
+}
+
+// ListSnapshots returns the IDs of the stores with an uploaded snapshot.
+func (u *S3Uploader) ListSnapshots(ctx context.Context) ([]string, error) {
+	keys, err := u.client.ListObjects(ctx, u.bucket)
+	if err != nil {
+		return nil, fmt.Errorf("list snapshots in S3: %w", err)
+	}
+	var storeIDs []string
+	for _, key := range keys {
+		if storeID, ok := strings.CutSuffix(key, objectKey("")); ok && storeID != "" {
+			storeIDs = append(storeIDs, storeID)
+		}
+	}
+	return storeIDs, nil
+}
+
+// DownloadSnapshot downloads the uploaded snapshot of a store to filePath.
+func (u *S3Uploader) DownloadSnapshot(ctx context.Context, storeID string, filePath string) error {
+	if err := u.client.FGetObject(ctx, u.bucket, objectKey(storeID), filePath); err != nil {
+		return fmt.Errorf("download snapshot from S3: %w", err)
+	}
+	return nil
+}
+
 // NoopUploader is used when S3 storage is not configured.
 // Upload is a no-op and PresignedURL returns ErrNotConfigured.
 type NoopUploader struct{}
@@ -97,13 +171,57 @@ func (u *NoopUploader) PresignedURL(ctx context.Context, storeID string) (string
 	return "", time.Time{}, ErrNotConfigured
 }
 
+// UploaderOption configures optional S3Uploader behavior.
+type UploaderOption func(*uploaderOptions)
+
+type uploaderOptions struct {
+	accessKey, secretKey func() string
+}
+
+// WithCredentials signs requests with the keys returned by accessKey and
+// secretKey, asked for on every request so rotated keys take effect
+// without a restart, in place of the keys in the configuration.
+func WithCredentials(accessKey, secretKey func() string) UploaderOption {
+	return func(o *uploaderOptions) {
+		o.accessKey = accessKey
+		o.secretKey = secretKey
+	}
+}
+
+// rotatingCredentials provides S3 credentials that are fetched again for
+// every request.
+type rotatingCredentials struct {
+	accessKey, secretKey func() string
+}
+
+func (c rotatingCredentials) Retrieve() (credentials.Value, error) {
+	return credentials.Value{
+		AccessKeyID:     c.accessKey(),
+		SecretAccessKey: c.secretKey(),
+		SignerType:      credentials.SignatureV4,
+	}, nil
+}
+
+func (c rotatingCredentials) IsExpired() bool {
+	return true
+}
+
 // NewUploader creates the appropriate Uploader based on configuration.
 // Returns NoopUploader when bucket is empty, S3Uploader otherwise.
-func NewUploader(cfg config.SnapshotStorageConfig) (Uploader, error) {
+func NewUploader(cfg config.SnapshotStorageConfig, opts ...UploaderOption) (Uploader, error) {
 	if cfg.Bucket == "" {
 		return &NoopUploader{}, nil
 	}
 
+	var o uploaderOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	creds := credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, "")
+	if o.accessKey != nil && o.secretKey != nil {
+		creds = credentials.New(rotatingCredentials{accessKey: o.accessKey, secretKey: o.secretKey})
+	}
+
 	useSSL := true
 	if cfg.UseSSL != nil {
 		useSSL = *cfg.UseSSL
@@ -114,7 +232,7 @@ func NewUploader(cfg config.SnapshotStorageConfig) (Uploader, error) {
 	endpoint := stripScheme(cfg.Endpoint, &useSSL)
 
 	client, err := minio.New(endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Creds:  creds,
 		Secure: useSSL,
 		Region: cfg.Region,
 	})
@@ -123,7 +241,7 @@ func NewUploader(cfg config.SnapshotStorageConfig) (Uploader, error) {
 	}
 
 	return &S3Uploader{
-		client:    &minioClientWrapper{client: client},
+		client:    &minioClientWrapper{client: client, limiter: newBandwidthLimiter(cfg.UploadBandwidth)},
 		bucket:    cfg.Bucket,
 		urlExpiry: time.Duration(cfg.URLExpiry),
 	}, nil
@@ -135,6 +253,12 @@ func objectKey(storeID string) string {
 	return storeID + "/snapshot/current.db"
 }
 
+// archiveKey returns the S3 object key for a store's archive.
+// Convention: {store_id}/archive/engram.db.zst
+func archiveKey(storeID string) string {
+	return storeID + "/archive/engram.db.zst"
+}
+
 // stripScheme removes a URL scheme from the endpoint and infers the SSL
 // setting when the operator hasn't explicitly set UseSSL. This lets operators
 // write ENGRAM_S3_ENDPOINT=https://s3.example.com instead of requiring the
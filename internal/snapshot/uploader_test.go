@@ -9,6 +9,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
 	"github.com/hyperengineering/engram/internal/config"
 )
 
@@ -71,6 +73,23 @@ func TestNewUploader_WithBucket_ReturnsS3Uploader(t *testing.T) {
 	}
 }
 
+func TestRotatingCredentials(t *testing.T) {
+	secret := "first"
+	creds := credentials.New(rotatingCredentials{
+		accessKey: func() string { return "access" },
+		secretKey: func() string { return secret },
+	})
+
+	v, err := creds.Get()
+	if err != nil || v.AccessKeyID != "access" || v.SecretAccessKey != "first" {
+		t.Fatalf("Get() = %+v, %v, want access/first", v, err)
+	}
+	secret = "second"
+	if v, _ := creds.Get(); v.SecretAccessKey != "second" {
+		t.Errorf("Get() after rotation SecretAccessKey = %q, want second", v.SecretAccessKey)
+	}
+}
+
 func TestNewUploader_UseSSLNil_DefaultsTrue(t *testing.T) {
 	cfg := config.SnapshotStorageConfig{
 		Bucket:    "test-bucket",
@@ -100,14 +119,18 @@ func TestNewUploader_UseSSLNil_DefaultsTrue(t *testing.T) {
 
 // mockS3Client implements s3Client for testing.
 type mockS3Client struct {
-	uploadCalled    bool
-	uploadErr       error
-	presignCalled   bool
-	presignURL      *url.URL
-	presignErr      error
-	lastBucket      string
-	lastObjectName  string
-	lastFilePath    string
+	uploadCalled   bool
+	uploadErr      error
+	presignCalled  bool
+	downloadCalled bool
+	downloadErr    error
+	presignURL     *url.URL
+	presignErr     error
+	objects        []string
+	listErr        error
+	lastBucket     string
+	lastObjectName string
+	lastFilePath   string
 }
 
 func (m *mockS3Client) FPutObject(ctx context.Context, bucket, objectName, filePath string, opts interface{}) error {
@@ -118,6 +141,19 @@ func (m *mockS3Client) FPutObject(ctx context.Context, bucket, objectName, fileP
 	return m.uploadErr
 }
 
+func (m *mockS3Client) FGetObject(ctx context.Context, bucket, objectName, filePath string) error {
+	m.downloadCalled = true
+	m.lastBucket = bucket
+	m.lastObjectName = objectName
+	m.lastFilePath = filePath
+	return m.downloadErr
+}
+
+func (m *mockS3Client) ListObjects(ctx context.Context, bucket string) ([]string, error) {
+	m.lastBucket = bucket
+	return m.objects, m.listErr
+}
+
 func (m *mockS3Client) PresignedGetObject(ctx context.Context, bucket, objectName string, expiry time.Duration) (*url.URL, error) {
 	m.presignCalled = true
 	m.lastBucket = bucket
@@ -282,3 +318,70 @@ func TestS3Uploader_ObjectKey_Format(t *testing.T) {
 		}
 	}
 }
+
+// --- Archive Tests ---
+
+func TestS3Uploader_Archive_UsesArchiveKey(t *testing.T) {
+	mock := &mockS3Client{}
+	u := &S3Uploader{client: mock, bucket: "test-bucket"}
+
+	if err := u.UploadArchive(context.Background(), "org/project", "/tmp/engram.db.zst"); err != nil {
+		t.Fatalf("UploadArchive() error = %v", err)
+	}
+	if !mock.uploadCalled || mock.lastObjectName != "org/project/archive/engram.db.zst" {
+		t.Errorf("upload object = %q, want org/project/archive/engram.db.zst", mock.lastObjectName)
+	}
+
+	if err := u.DownloadArchive(context.Background(), "org/project", "/tmp/restore.zst"); err != nil {
+		t.Fatalf("DownloadArchive() error = %v", err)
+	}
+	if !mock.downloadCalled || mock.lastObjectName != "org/project/archive/engram.db.zst" || mock.lastFilePath != "/tmp/restore.zst" {
+		t.Errorf("download object = %q to %q", mock.lastObjectName, mock.lastFilePath)
+	}
+}
+
+func TestS3Uploader_DownloadArchive_Error(t *testing.T) {
+	mock := &mockS3Client{downloadErr: errors.New("no such key")}
+	u := &S3Uploader{client: mock, bucket: "test-bucket"}
+
+	if err := u.DownloadArchive(context.Background(), "store-1", "/tmp/x"); err == nil {
+		t.Fatal("DownloadArchive() error = nil, want the S3 error")
+	}
+}
+
+// --- Restore Tests ---
+
+func TestS3Uploader_ListSnapshots_ReturnsStoreIDs(t *testing.T) {
+	mock := &mockS3Client{objects: []string{
+		"default/snapshot/current.db",
+		"org/project/snapshot/current.db",
+		"org/dormant/archive/engram.db.zst",
+		"stray.db",
+	}}
+	u := &S3Uploader{client: mock, bucket: "test-bucket"}
+
+	got, err := u.ListSnapshots(context.Background())
+	if err != nil {
+		t.Fatalf("ListSnapshots() error = %v", err)
+	}
+	want := []string{"default", "org/project"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("ListSnapshots() = %v, want %v", got, want)
+	}
+
+	if err := u.DownloadSnapshot(context.Background(), "org/project", "/tmp/restore.db"); err != nil {
+		t.Fatalf("DownloadSnapshot() error = %v", err)
+	}
+	if mock.lastObjectName != "org/project/snapshot/current.db" || mock.lastFilePath != "/tmp/restore.db" {
+		t.Errorf("download object = %q to %q", mock.lastObjectName, mock.lastFilePath)
+	}
+}
+
+func TestS3Uploader_ListSnapshots_Error(t *testing.T) {
+	mock := &mockS3Client{listErr: errors.New("access denied")}
+	u := &S3Uploader{client: mock, bucket: "test-bucket"}
+
+	if _, err := u.ListSnapshots(context.Background()); err == nil {
+		t.Fatal("ListSnapshots() error = nil, want the S3 error")
+	}
+}
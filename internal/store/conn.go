@@ -0,0 +1,221 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync/atomic"
+	"time"
+)
+
+// connHooks are applied to the statements of a store's connections once
+// armed is set, so migrations run untouched.
+type connHooks struct {
+	faults Faults
+	plans  *queryPlans   // nil counts no index usage
+	slow   *slowQueryLog // nil logs no slow queries
+	armed  *atomic.Bool
+}
+
+func (h connHooks) enabled() bool {
+	return h.faults.Enabled() || h.plans != nil || h.slow != nil
+}
+
+// before injects faults ahead of a statement.
+func (h connHooks) before(ctx context.Context) error {
+	if !h.armed.Load() || !h.faults.Enabled() {
+		return nil
+	}
+	return h.faults.inject(ctx)
+}
+
+// observed reports whether statements are passed to after.
+func (h connHooks) observed() bool {
+	return (h.plans != nil || h.slow != nil) && h.armed.Load()
+}
+
+// after counts the indexes a statement used and logs it if it was slow.
+// conn must be idle, as either may explain the statement.
+func (h connHooks) after(conn driver.Conn, query string, args []driver.NamedValue, elapsed time.Duration) {
+	if h.plans != nil {
+		h.plans.observe(conn, query, args)
+	}
+	if h.slow != nil {
+		h.slow.observe(conn, query, args, elapsed)
+	}
+}
+
+// openDB opens the SQLite database at dsn. With hooks enabled, its
+// connections apply them to every statement.
+func openDB(dsn string, hooks connHooks) (*sql.DB, error) {
+	if !hooks.enabled() {
+		return sql.Open("sqlite", dsn)
+	}
+	// Borrow the registered driver; sql.Open does not connect
+	probe, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	drv := probe.Driver()
+	probe.Close()
+	return sql.OpenDB(&hookedConnector{dsn: dsn, drv: drv, hooks: hooks}), nil
+}
+
+// hookedConnector opens connections that apply hooks to statements.
+type hookedConnector struct {
+	dsn   string
+	drv   driver.Driver
+	hooks connHooks
+}
+
+func (c *hookedConnector) Connect(context.Context) (driver.Conn, error) {
+	conn, err := c.drv.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &hookedConn{conn: conn, hooks: c.hooks}, nil
+}
+
+func (c *hookedConnector) Driver() driver.Driver {
+	return c.drv
+}
+
+// hookedConn wraps a SQLite driver connection, applying hooks around each
+// statement and passing everything else through. The SQLite driver's
+// connections implement the context variants of the driver interfaces.
+type hookedConn struct {
+	conn  driver.Conn
+	hooks connHooks
+}
+
+func (hc *hookedConn) Prepare(query string) (driver.Stmt, error) {
+	return hc.PrepareContext(context.Background(), query)
+}
+
+func (hc *hookedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := hc.conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &hookedStmt{Stmt: stmt, conn: hc.conn, query: query, hooks: hc.hooks}, nil
+}
+
+func (hc *hookedConn) Close() error {
+	return hc.conn.Close()
+}
+
+func (hc *hookedConn) Begin() (driver.Tx, error) {
+	return hc.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (hc *hookedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return hc.conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+func (hc *hookedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := hc.hooks.before(ctx); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	res, err := hc.conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+	if err == nil && hc.hooks.observed() {
+		hc.hooks.after(hc.conn, query, args, time.Since(start))
+	}
+	return res, err
+}
+
+func (hc *hookedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := hc.hooks.before(ctx); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	rows, err := hc.conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+	if err != nil || !hc.hooks.observed() {
+		return rows, err
+	}
+	return &timedRows{Rows: rows, spent: time.Since(start), done: func(spent time.Duration) {
+		hc.hooks.after(hc.conn, query, args, spent)
+	}}, nil
+}
+
+func (hc *hookedConn) Ping(ctx context.Context) error {
+	if p, ok := hc.conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (hc *hookedConn) ResetSession(ctx context.Context) error {
+	if r, ok := hc.conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (hc *hookedConn) IsValid() bool {
+	if v, ok := hc.conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+// hookedStmt wraps a prepared statement, applying hooks around each
+// execution. The SQLite driver's statements implement the context
+// variants of Exec and Query.
+type hookedStmt struct {
+	driver.Stmt
+	conn  driver.Conn
+	query string
+	hooks connHooks
+}
+
+func (s *hookedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if err := s.hooks.before(ctx); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	res, err := s.Stmt.(driver.StmtExecContext).ExecContext(ctx, args)
+	if err == nil && s.hooks.observed() {
+		s.hooks.after(s.conn, s.query, args, time.Since(start))
+	}
+	return res, err
+}
+
+func (s *hookedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if err := s.hooks.before(ctx); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	rows, err := s.Stmt.(driver.StmtQueryContext).QueryContext(ctx, args)
+	if err != nil || !s.hooks.observed() {
+		return rows, err
+	}
+	return &timedRows{Rows: rows, spent: time.Since(start), done: func(spent time.Duration) {
+		s.hooks.after(s.conn, s.query, args, spent)
+	}}, nil
+}
+
+// timedRows counts the time spent stepping a query's rows, but not the
+// caller's time between them, and reports it once they are closed, when
+// the connection is free to explain the query.
+type timedRows struct {
+	driver.Rows
+	spent time.Duration
+	done  func(spent time.Duration)
+}
+
+func (r *timedRows) Next(dest []driver.Value) error {
+	start := time.Now()
+	err := r.Rows.Next(dest)
+	r.spent += time.Since(start)
+	return err
+}
+
+func (r *timedRows) Close() error {
+	err := r.Rows.Close()
+	if r.done != nil {
+		r.done(r.spent)
+		r.done = nil
+	}
+	return err
+}
@@ -1,6 +1,11 @@
 package store
 
-import "errors"
+import (
+	"errors"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
 
 var (
 	ErrNotFound             = errors.New("lore entry not found")
@@ -9,4 +14,36 @@ var (
 	ErrEmbeddingPending     = errors.New("embedding generation pending")
 	ErrSnapshotNotAvailable = errors.New("snapshot not available")
 	ErrSnapshotInProgress   = errors.New("snapshot generation in progress")
+	ErrPushSessionNotFound  = errors.New("push session not found")
+	ErrPushSessionExists    = errors.New("push session already exists")
+	ErrPushSessionFull      = errors.New("push session entry limit reached")
+	ErrConflictNotFound     = errors.New("sync conflict not found")
+	ErrConflictResolved     = errors.New("sync conflict already resolved")
+	ErrAlreadyReviewed      = errors.New("lore entry already reviewed")
+	ErrVersionNotFound      = errors.New("lore version not found")
+	ErrVersionMismatch      = errors.New("lore entry version mismatch")
+	ErrHistoryCompacted     = errors.New("change log history compacted")
+	ErrEvalSetNotFound      = errors.New("evaluation set not found")
+	ErrMetaNotFound         = errors.New("store metadata key not found")
+	ErrMetaFull             = errors.New("store metadata key limit reached")
+	ErrDimensionMismatch    = errors.New("embedding dimension mismatch")
+	ErrInMemoryStore        = errors.New("store is in memory")
 )
+
+// IsBusy reports whether err is SQLite refusing a statement because another
+// connection holds the lock, after busy_timeout has run out. Callers can
+// treat it as transient overload rather than failure.
+func IsBusy(err error) bool {
+	if errors.Is(err, errInjectedBusy) {
+		return true
+	}
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	switch sqliteErr.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return true
+	}
+	return false
+}
@@ -1,8 +1,10 @@
 package store
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"testing"
 )
 
@@ -49,3 +51,34 @@ func TestSentinelErrors_WrappedIdentity(t *testing.T) {
 		})
 	}
 }
+
+func TestIsBusy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "busy.db")
+	holder, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer holder.Close()
+	waiter, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer waiter.Close()
+
+	tx, err := holder.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("CREATE TABLE held (x INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = waiter.Exec("CREATE TABLE blocked (x INTEGER)")
+	if !IsBusy(fmt.Errorf("wrapped: %w", err)) {
+		t.Errorf("IsBusy(%v) = false, want true while another connection holds the lock", err)
+	}
+	if IsBusy(ErrNotFound) || IsBusy(nil) {
+		t.Error("IsBusy should be false for non-SQLite errors")
+	}
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// errInjectedBusy is returned by statements failed by Faults. IsBusy
+// treats it as SQLite reporting the database locked.
+var errInjectedBusy = errors.New("database is locked (injected fault)")
+
+// Faults are failures injected into a store's SQL statements, for soak
+// tests that exercise how the server copes with a contended or slow
+// database. They apply only once the store has opened, so migrations are
+// never failed. Never enable them in production.
+type Faults struct {
+	// BusyRate is the fraction of statements, from 0 to 1, that fail as if
+	// SQLite had found the database locked past its busy timeout.
+	BusyRate float64
+
+	// Latency is added before every statement, as from a slow disk.
+	Latency time.Duration
+}
+
+// Enabled reports whether any fault is configured.
+func (f Faults) Enabled() bool {
+	return f.BusyRate > 0 || f.Latency > 0
+}
+
+// WithFaults injects f into the store's SQL statements.
+func WithFaults(f Faults) StoreOption {
+	return func(s *SQLiteStore) {
+		s.faults = f
+	}
+}
+
+// inject delays by the configured latency and then fails the statement
+// as busy at the configured rate.
+func (f Faults) inject(ctx context.Context) error {
+	if f.Latency > 0 {
+		t := time.NewTimer(f.Latency)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	if f.BusyRate > 0 && rand.Float64() < f.BusyRate {
+		return errInjectedBusy
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/embedding"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestWithFaults_BusyAfterOpen(t *testing.T) {
+	// Migrations run unharmed even when every statement is to fail
+	s, err := NewSQLiteStore(":memory:", WithFaults(Faults{BusyRate: 1}))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer s.Close()
+
+	_, err = s.GetStats(context.Background())
+	if !IsBusy(err) {
+		t.Errorf("GetStats() error = %v, want busy", err)
+	}
+	_, err = s.IngestLore(context.Background(), []types.NewLoreEntry{{
+		Content: "Injected busy errors fail writes", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src",
+	}})
+	if !IsBusy(err) {
+		t.Errorf("IngestLore() error = %v, want busy", err)
+	}
+}
+
+func TestWithFaults_BusyRate(t *testing.T) {
+	s, err := NewSQLiteStore(":memory:", WithFaults(Faults{BusyRate: 0.5}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	busy := 0
+	for range 200 {
+		_, err := s.GetLoreVersion(context.Background(), "01ARZ3NDEKTSV4RRFFQ69G5FAV")
+		switch {
+		case IsBusy(err):
+			busy++
+		case !errors.Is(err, ErrNotFound):
+			t.Fatalf("GetLoreVersion() error = %v", err)
+		}
+	}
+	if busy < 50 || busy > 150 {
+		t.Errorf("%d of 200 statements failed busy, want about half", busy)
+	}
+}
+
+func TestWithFaults_Latency(t *testing.T) {
+	s, err := NewSQLiteStore(":memory:", WithFaults(Faults{Latency: 20 * time.Millisecond}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	start := time.Now()
+	if _, err := s.GetStats(context.Background()); err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("GetStats() took %v, want at least the injected 20ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.GetStats(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetStats() with cancelled context error = %v, want context.Canceled", err)
+	}
+}
+
+func TestWithFaults_EmbeddingFailuresLeaveEntriesPending(t *testing.T) {
+	s := newTestStore(t)
+	s.SetDependencies(embedding.NewFaulty(&mockEmbedder{}, 1, 0), &mockConfig{dedupEnabled: true, threshold: 0.92})
+	ctx := context.Background()
+
+	result, err := s.IngestLore(ctx, []types.NewLoreEntry{{
+		Content: "Ingest survives an embedding outage", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src",
+	}})
+	if err != nil {
+		t.Fatalf("IngestLore() error = %v", err)
+	}
+	if result.Accepted != 1 {
+		t.Errorf("Accepted = %d, want 1", result.Accepted)
+	}
+	pending, err := s.GetPendingEmbeddings(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 {
+		t.Errorf("%d entries pending embedding, want 1", len(pending))
+	}
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// FeedbackLimit caps how often one source can give one lore entry the same
+// type of feedback, so a client repeating helpful cannot inflate an entry's
+// confidence, nor repeating incorrect sink it. Feedback over the cap is
+// skipped with reason rate_limited.
+type FeedbackLimit struct {
+	// Window is the period the cap applies to, starting at a source's
+	// first feedback of a type on an entry. Zero disables the limit.
+	Window time.Duration
+	// Max is the feedback of one type a source may give one entry within
+	// Window. Zero disables the limit.
+	Max int
+}
+
+// WithFeedbackLimit caps repeated feedback from one source on one entry.
+func WithFeedbackLimit(l FeedbackLimit) StoreOption {
+	return func(s *SQLiteStore) {
+		s.feedbackLimit = l
+	}
+}
+
+// enabled reports whether the limit caps anything.
+func (l FeedbackLimit) enabled() bool {
+	return l.Window > 0 && l.Max > 0
+}
+
+// allowFeedback counts entry against its source's window in tx, reporting
+// false if the source has already reached the cap. Feedback without a
+// source is not limited.
+func (s *SQLiteStore) allowFeedback(ctx context.Context, tx *sql.Tx, entry types.FeedbackEntry, now time.Time) (bool, error) {
+	l := s.feedbackLimit
+	if !l.enabled() || entry.SourceID == "" {
+		return true, nil
+	}
+
+	// A window that has ended starts again at now; within one, the count
+	// is raised only while under the cap, so a refused entry changes nothing
+	cutoff := formatTime(now.Add(-l.Window))
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO feedback_windows (source_hash, lore_id, type, window_start, count)
+		VALUES (?1, ?2, ?3, ?4, 1)
+		ON CONFLICT (source_hash, lore_id, type) DO UPDATE SET
+			window_start = CASE WHEN window_start <= ?5 THEN excluded.window_start ELSE window_start END,
+			count = CASE WHEN window_start <= ?5 THEN 1 ELSE count + 1 END
+		WHERE window_start <= ?5 OR count < ?6
+	`, sourceHash(entry.SourceID), entry.LoreID, entry.Type, formatTime(now), cutoff, l.Max)
+	if err != nil {
+		return false, fmt.Errorf("count feedback window: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("count feedback window: %w", err)
+	}
+	return n > 0, nil
+}
+
+// pruneFeedbackWindows deletes windows that have ended, at most once per
+// Window, so the table holds only the sources active within one. It runs
+// outside the feedback transaction, once that has committed, and a failed
+// prune leaves the next call free to retry.
+func (s *SQLiteStore) pruneFeedbackWindows(ctx context.Context, now time.Time) {
+	last := s.feedbackPrunedAt.Load()
+	if now.UnixNano()-last < int64(s.feedbackLimit.Window) || !s.feedbackPrunedAt.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	_, err := s.db.ExecContext(ctx, `DELETE FROM feedback_windows WHERE window_start <= ?`,
+		formatTime(now.Add(-s.feedbackLimit.Window)))
+	if err != nil {
+		s.feedbackPrunedAt.CompareAndSwap(now.UnixNano(), last)
+		slog.Warn("feedback window prune failed",
+			"component", "store",
+			"action", "prune_feedback_windows",
+			"store_id", s.storeID,
+			"error", err,
+		)
+	}
+}
+
+// sourceHash returns the hash under which a source's feedback windows are
+// kept, as feedback itself records no source.
+func sourceHash(sourceID string) string {
+	sum := sha256.Sum256([]byte(sourceID))
+	return hex.EncodeToString(sum[:16])
+}
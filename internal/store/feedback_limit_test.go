@@ -0,0 +1,114 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestRecordFeedback_RateLimit(t *testing.T) {
+	ctx := context.Background()
+	s, id := newFeedbackTestStore(t, WithFeedbackLimit(FeedbackLimit{Window: time.Hour, Max: 1}))
+
+	helpful := func(source string) *types.FeedbackResult {
+		t.Helper()
+		result, err := s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: id, Type: "helpful", SourceID: source}})
+		if err != nil {
+			t.Fatalf("RecordFeedback() error = %v", err)
+		}
+		return result
+	}
+
+	if result := helpful("agent-a"); len(result.Updates) != 1 {
+		t.Fatalf("first helpful = %+v, want applied", result)
+	}
+	result := helpful("agent-a")
+	if len(result.Updates) != 0 || len(result.Skipped) != 1 || result.Skipped[0].Reason != "rate_limited" {
+		t.Errorf("repeated helpful = %+v, want skipped as rate_limited", result)
+	}
+	if result := helpful("agent-b"); len(result.Updates) != 1 {
+		t.Errorf("helpful from another source = %+v, want applied", result)
+	}
+	// The cap is per type
+	if result, err := s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: id, Type: "incorrect", SourceID: "agent-a"}}); err != nil || len(result.Updates) != 1 {
+		t.Errorf("incorrect after helpful = %+v, %v, want applied", result, err)
+	}
+
+	entry, err := s.GetLore(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.ValidationCount != 2 {
+		t.Errorf("validation count = %d, want 2", entry.ValidationCount)
+	}
+
+	// Once the window has ended the source may give feedback again
+	if _, err := s.db.Exec(`UPDATE feedback_windows SET window_start = ?`, formatTime(time.Now().Add(-2*time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	if result := helpful("agent-a"); len(result.Updates) != 1 {
+		t.Errorf("helpful after the window = %+v, want applied", result)
+	}
+}
+
+func TestRecordFeedback_RateLimitWithinBatch(t *testing.T) {
+	ctx := context.Background()
+	s, id := newFeedbackTestStore(t, WithFeedbackLimit(FeedbackLimit{Window: time.Hour, Max: 2}))
+
+	entry := types.FeedbackEntry{LoreID: id, Type: "helpful", SourceID: "agent-a"}
+	result, err := s.RecordFeedbackAtomic(ctx, []types.FeedbackEntry{entry, entry, entry})
+	if err != nil {
+		t.Fatalf("RecordFeedbackAtomic() error = %v", err)
+	}
+	// Rate limited feedback does not fail an atomic batch
+	if len(result.Updates) != 2 || len(result.Skipped) != 1 || result.Skipped[0].Reason != "rate_limited" {
+		t.Errorf("batch of three = %+v, want two applied and one rate limited", result)
+	}
+}
+
+func TestRecordFeedback_NoRateLimitByDefault(t *testing.T) {
+	ctx := context.Background()
+	s, id := newFeedbackTestStore(t, WithFeedbackLimit(FeedbackLimit{}))
+
+	for i := 0; i < 3; i++ {
+		result, err := s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: id, Type: "helpful", SourceID: "agent-a"}})
+		if err != nil || len(result.Updates) != 1 {
+			t.Fatalf("helpful %d = %+v, %v, want applied", i, result, err)
+		}
+	}
+}
+
+func TestRecordFeedback_PrunesEndedWindowsAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	s, id := newFeedbackTestStore(t, WithFeedbackLimit(FeedbackLimit{Window: time.Hour, Max: 1}))
+
+	ended := formatTime(time.Now().Add(-2 * time.Hour))
+	if _, err := s.db.Exec(`INSERT INTO feedback_windows (source_hash, lore_id, type, window_start, count)
+		VALUES (?, ?, 'helpful', ?, 1)`, sourceHash("agent-old"), id, ended); err != nil {
+		t.Fatal(err)
+	}
+
+	// A batch that rolls back leaves the prune for the next commit
+	if _, err := s.RecordFeedbackAtomic(ctx, []types.FeedbackEntry{
+		{LoreID: id, Type: "helpful", SourceID: "agent-a"},
+		{LoreID: "01ARZ3NDEKTSV4RRFFQ69G5FAW", Type: "helpful", SourceID: "agent-a"},
+	}); err != nil {
+		t.Fatalf("RecordFeedbackAtomic() error = %v", err)
+	}
+	if at := s.feedbackPrunedAt.Load(); at != 0 {
+		t.Errorf("feedbackPrunedAt = %d after a rolled back batch, want 0", at)
+	}
+
+	if _, err := s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: id, Type: "helpful", SourceID: "agent-a"}}); err != nil {
+		t.Fatalf("RecordFeedback() error = %v", err)
+	}
+	var n int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM feedback_windows WHERE source_hash = ?`, sourceHash("agent-old")).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("ended windows = %d, want pruned", n)
+	}
+}
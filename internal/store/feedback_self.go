@@ -0,0 +1,52 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// SelfFeedback sets how helpful feedback is applied when it comes from one
+// of the entry's own sources, its creator or a source merged into it, so
+// agents cannot boost their own contributions.
+type SelfFeedback struct {
+	// Exclude enables the rule. When false, self feedback is applied in
+	// full like any other.
+	Exclude bool
+	// Weight scales the confidence boost of self feedback, from 0 to 1. At
+	// zero the feedback is skipped with reason self_feedback; above zero it
+	// is applied at that weight without counting as a validation.
+	Weight float64
+}
+
+// Validate checks that the weight is within 0 to 1.
+func (f SelfFeedback) Validate() error {
+	if f.Weight < 0 || f.Weight > 1 {
+		return fmt.Errorf("self feedback weight %v is not between 0 and 1", f.Weight)
+	}
+	return nil
+}
+
+// WithSelfFeedback sets how helpful feedback from an entry's own sources
+// is applied.
+func WithSelfFeedback(f SelfFeedback) StoreOption {
+	return func(s *SQLiteStore) {
+		s.selfFeedback = f
+	}
+}
+
+// isOwnSource reports whether sourceID created the entry or is among the
+// sources in its sources column.
+func isOwnSource(sourceID, creator, sourcesJSON string) bool {
+	if sourceID == "" {
+		return false
+	}
+	if sourceID == creator {
+		return true
+	}
+	var sources []string
+	if err := json.Unmarshal([]byte(sourcesJSON), &sources); err != nil {
+		return false
+	}
+	return slices.Contains(sources, sourceID)
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestRecordFeedback_SelfFeedbackExcluded(t *testing.T) {
+	ctx := context.Background()
+	s, id := newFeedbackTestStore(t, WithSelfFeedback(SelfFeedback{Exclude: true}))
+	// As if a duplicate from another source had been merged into it
+	if _, err := s.db.Exec(`UPDATE lore_entries SET sources = '["author","merged"]' WHERE id = ?`, id); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, source := range []string{"author", "merged"} {
+		result, err := s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: id, Type: "helpful", SourceID: source}})
+		if err != nil {
+			t.Fatalf("RecordFeedback() error = %v", err)
+		}
+		if len(result.Updates) != 0 || len(result.Skipped) != 1 || result.Skipped[0].Reason != "self_feedback" {
+			t.Errorf("helpful from %s = %+v, want skipped as self_feedback", source, result)
+		}
+	}
+
+	// Self feedback does not fail an atomic batch
+	result, err := s.RecordFeedbackAtomic(ctx, []types.FeedbackEntry{
+		{LoreID: id, Type: "helpful", SourceID: "author"},
+		{LoreID: id, Type: "helpful", SourceID: "other"},
+	})
+	if err != nil {
+		t.Fatalf("RecordFeedbackAtomic() error = %v", err)
+	}
+	if len(result.Updates) != 1 || len(result.Skipped) != 1 {
+		t.Errorf("atomic batch = %+v, want one applied and one skipped", result)
+	}
+
+	// Only helpful feedback is excluded
+	result, err = s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: id, Type: "incorrect", SourceID: "author"}})
+	if err != nil || len(result.Updates) != 1 {
+		t.Errorf("incorrect from author = %+v, %v, want applied", result, err)
+	}
+
+	entry, err := s.GetLore(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.ValidationCount != 1 {
+		t.Errorf("validation count = %d, want 1", entry.ValidationCount)
+	}
+}
+
+func TestRecordFeedback_SelfFeedbackWeighted(t *testing.T) {
+	ctx := context.Background()
+	s, id := newFeedbackTestStore(t, WithSelfFeedback(SelfFeedback{Exclude: true, Weight: 0.5}))
+
+	result, err := s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: id, Type: "helpful", SourceID: "author"}})
+	if err != nil {
+		t.Fatalf("RecordFeedback() error = %v", err)
+	}
+	if len(result.Updates) != 1 {
+		t.Fatalf("helpful from author = %+v, want applied", result)
+	}
+	update := result.Updates[0]
+	if want := 0.5 + FeedbackHelpfulBoost/2; update.CurrentConfidence != want {
+		t.Errorf("confidence = %v, want %v", update.CurrentConfidence, want)
+	}
+	if update.ValidationCount != nil {
+		t.Errorf("validation count = %d, want unchanged", *update.ValidationCount)
+	}
+
+	entry, err := s.GetLore(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.ValidationCount != 0 || entry.LastValidatedAt != nil {
+		t.Errorf("validation = %d, %v, want none", entry.ValidationCount, entry.LastValidatedAt)
+	}
+}
+
+func TestRecordFeedback_SelfFeedbackAllowed(t *testing.T) {
+	s, id := newFeedbackTestStore(t, WithSelfFeedback(SelfFeedback{}))
+
+	result, err := s.RecordFeedback(context.Background(), []types.FeedbackEntry{{LoreID: id, Type: "helpful", SourceID: "author"}})
+	if err != nil {
+		t.Fatalf("RecordFeedback() error = %v", err)
+	}
+	if len(result.Updates) != 1 || result.Updates[0].ValidationCount == nil {
+		t.Errorf("helpful from author = %+v, want applied in full", result)
+	}
+}
+
+func TestSelfFeedback_Validate(t *testing.T) {
+	for _, w := range []float64{-0.1, 1.5} {
+		if err := (SelfFeedback{Exclude: true, Weight: w}).Validate(); err == nil {
+			t.Errorf("Validate() with weight %v = nil, want error", w)
+		}
+	}
+	if err := (SelfFeedback{Exclude: true, Weight: 0.3}).Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
@@ -2,9 +2,17 @@ package store
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/hyperengineering/engram/internal/language"
 	"github.com/hyperengineering/engram/internal/plugin"
 	"github.com/hyperengineering/engram/migrations"
 	"github.com/pressly/goose/v3"
@@ -32,6 +40,331 @@ func RunMigrations(db *sql.DB) error {
 	return nil
 }
 
+// PendingMigration is a migration not yet applied to a database.
+type PendingMigration struct {
+	// Version is the migration's sequence number.
+	Version int64
+	// Name identifies the migration: the core migration's file name or the
+	// plugin migration's name.
+	Name string
+	// Plugin is the store type whose plugin owns the migration, empty for
+	// core migrations.
+	Plugin string
+}
+
+// MigrationStatus describes the migrations a database still needs.
+type MigrationStatus struct {
+	// Exists is false when there is no database file yet; it is created
+	// with every migration applied on first open.
+	Exists bool
+	// Version is the highest core migration applied.
+	Version int64
+	// Pending lists core migrations, then the given plugin migrations,
+	// not yet applied.
+	Pending []PendingMigration
+}
+
+// InspectMigrations reports which core and plugin migrations opening the
+// database at dbPath would apply. The database is opened read-only and is
+// not modified.
+func InspectMigrations(dbPath string, pluginType string, pluginMigrations []plugin.Migration) (*MigrationStatus, error) {
+	status := &MigrationStatus{}
+	if _, err := os.Stat(dbPath); errors.Is(err, fs.ErrNotExist) {
+		core, err := coreMigrations()
+		if err != nil {
+			return nil, err
+		}
+		status.Pending = core
+		for _, m := range pluginMigrations {
+			status.Pending = append(status.Pending, PendingMigration{Version: int64(m.Version), Name: m.Name, Plugin: pluginType})
+		}
+		return status, nil
+	} else if err != nil {
+		return nil, err
+	}
+	status.Exists = true
+
+	db, err := sql.Open("sqlite", "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+
+	version, pending, err := pendingCoreMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+	status.Version = version
+	status.Pending = pending
+
+	if len(pluginMigrations) > 0 {
+		applied, err := AppliedPluginMigrations(db)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range pluginMigrations {
+			if !applied[m.Version] {
+				status.Pending = append(status.Pending, PendingMigration{Version: int64(m.Version), Name: m.Name, Plugin: pluginType})
+			}
+		}
+	}
+	return status, nil
+}
+
+// coreMigrations lists the embedded goose migrations in version order.
+func coreMigrations() ([]PendingMigration, error) {
+	names, err := fs.Glob(migrations.FS, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	var result []PendingMigration
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %q: invalid version prefix", name)
+		}
+		result = append(result, PendingMigration{Version: version, Name: name})
+	}
+	slices.SortFunc(result, func(a, b PendingMigration) int {
+		return int(a.Version - b.Version)
+	})
+	return result, nil
+}
+
+// pendingCoreMigrations returns the database's core schema version and the
+// core migrations above it, without creating goose's version table.
+func pendingCoreMigrations(db *sql.DB) (int64, []PendingMigration, error) {
+	core, err := coreMigrations()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var tables int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'goose_db_version'`).Scan(&tables); err != nil {
+		return 0, nil, fmt.Errorf("check migration table: %w", err)
+	}
+	var version int64
+	if tables > 0 {
+		if err := db.QueryRow(`SELECT COALESCE(MAX(version_id), 0) FROM goose_db_version WHERE is_applied`).Scan(&version); err != nil {
+			return 0, nil, fmt.Errorf("read schema version: %w", err)
+		}
+	}
+
+	var pending []PendingMigration
+	for _, m := range core {
+		if m.Version > version {
+			pending = append(pending, m)
+		}
+	}
+	return version, pending, nil
+}
+
+// AppliedPluginMigrations returns the plugin migration versions recorded in
+// the database.
+func AppliedPluginMigrations(db *sql.DB) (map[int]bool, error) {
+	applied := make(map[int]bool)
+	var tables int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'plugin_migrations'`).Scan(&tables); err != nil {
+		return nil, fmt.Errorf("check plugin migration table: %w", err)
+	}
+	if tables == 0 {
+		return applied, nil
+	}
+	rows, err := db.Query(`SELECT version FROM plugin_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("read plugin migrations: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
+
+// preMigrationSuffix names the copy of a database taken before migrating it.
+const preMigrationSuffix = ".pre-migration"
+
+// migrateWithBackup applies pending core migrations to the database at
+// dbPath. When an existing database has migrations pending, it is first
+// copied to dbPath + ".pre-migration", replacing any earlier copy. If a
+// migration fails, db is closed and the copy restored, so the database is
+// left exactly as it was before the upgrade. db is only closed on failure.
+func migrateWithBackup(db *sql.DB, dbPath string) error {
+	version, pending, err := pendingCoreMigrations(db)
+	if err != nil {
+		db.Close()
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	// New databases have nothing to lose
+	backupPath := ""
+	if version > 0 && dbPath != ":memory:" {
+		backupPath = dbPath + preMigrationSuffix
+		if err := os.Remove(backupPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			db.Close()
+			return fmt.Errorf("remove old pre-migration backup: %w", err)
+		}
+		if _, err := db.Exec("VACUUM INTO ?", backupPath); err != nil {
+			db.Close()
+			return fmt.Errorf("back up database before migrating: %w", err)
+		}
+		slog.Info("database backed up before migration",
+			"component", "store",
+			"action", "pre_migration_backup",
+			"path", dbPath,
+			"backup", backupPath,
+			"schema_version", version,
+			"pending", len(pending),
+		)
+	}
+
+	migrateErr := RunMigrations(db)
+	if migrateErr == nil {
+		return nil
+	}
+	db.Close()
+	if backupPath == "" {
+		return migrateErr
+	}
+	if err := restorePreMigrationBackup(backupPath, dbPath); err != nil {
+		return fmt.Errorf("%w; restoring %s also failed: %v", migrateErr, backupPath, err)
+	}
+	slog.Error("migration failed, database restored from backup",
+		"component", "store",
+		"action", "migration_rolled_back",
+		"path", dbPath,
+		"schema_version", version,
+		"error", migrateErr,
+	)
+	return fmt.Errorf("%w (database restored to schema version %d)", migrateErr, version)
+}
+
+// restorePreMigrationBackup moves the backup over the database and drops
+// the write-ahead log of the failed attempt, which would otherwise be
+// replayed onto the restored file.
+func restorePreMigrationBackup(backupPath, dbPath string) error {
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(dbPath + suffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(backupPath, dbPath)
+}
+
+// backfillEmbeddingIndex stores the norm and projection bucket of
+// embeddings written before those columns existed. It is a no-op once every
+// embedded row has both.
+func backfillEmbeddingIndex(db *sql.DB) error {
+	const batch = 1000
+	type derived struct{ norm, bucket any }
+	for {
+		rows, err := db.Query(`
+			SELECT l.id, e.embedding FROM lore_entries l
+			JOIN lore_embeddings e ON e.lore_id = l.id
+			WHERE l.embedding_norm IS NULL OR l.embedding_bucket IS NULL
+			LIMIT ?
+		`, batch)
+		if err != nil {
+			return fmt.Errorf("query embeddings: %w", err)
+		}
+		pending := make(map[string]derived)
+		for rows.Next() {
+			var id string
+			var blob []byte
+			if err := rows.Scan(&id, &blob); err != nil {
+				rows.Close()
+				return fmt.Errorf("scan embedding: %w", err)
+			}
+			v := unpackEmbedding(blob)
+			// An empty blob has neither; zeros keep it out of the next batch.
+			d := derived{norm: 0.0, bucket: int64(0)}
+			if len(v) > 0 {
+				d = derived{norm: embeddingNorm(v), bucket: embeddingBucket(v)}
+			}
+			pending[id] = d
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return fmt.Errorf("iterate embeddings: %w", err)
+		}
+		rows.Close()
+		if len(pending) == 0 {
+			return nil
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("begin transaction: %w", err)
+		}
+		for id, d := range pending {
+			if _, err := tx.Exec(`UPDATE lore_entries SET embedding_norm = ?, embedding_bucket = ? WHERE id = ?`, d.norm, d.bucket, id); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("store embedding index: %w", err)
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit embedding index: %w", err)
+		}
+		if len(pending) < batch {
+			return nil
+		}
+	}
+}
+
+// backfillLanguage detects the language of entries written before the
+// language column existed. It is a no-op once every row has one.
+func backfillLanguage(db *sql.DB) error {
+	const batch = 1000
+	for {
+		rows, err := db.Query(`SELECT id, content FROM lore_entries WHERE language IS NULL LIMIT ?`, batch)
+		if err != nil {
+			return fmt.Errorf("query undetected languages: %w", err)
+		}
+		pending := make(map[string]string)
+		for rows.Next() {
+			var id, content string
+			if err := rows.Scan(&id, &content); err != nil {
+				rows.Close()
+				return fmt.Errorf("scan content: %w", err)
+			}
+			pending[id] = language.Detect(content)
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return fmt.Errorf("iterate content: %w", err)
+		}
+		rows.Close()
+		if len(pending) == 0 {
+			return nil
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("begin transaction: %w", err)
+		}
+		for id, lang := range pending {
+			if _, err := tx.Exec(`UPDATE lore_entries SET language = ? WHERE id = ?`, lang, id); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("store language: %w", err)
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit languages: %w", err)
+		}
+		if len(pending) < batch {
+			return nil
+		}
+	}
+}
+
 // RunPluginMigrations applies domain-specific migrations from a plugin.
 // These are applied after the base goose migrations.
 // Uses a simple migration tracking table (plugin_migrations) to avoid re-applying.
@@ -65,20 +398,28 @@ func RunPluginMigrations(db *sql.DB, migrations []plugin.Migration) error {
 			continue // Already applied
 		}
 
-		// Apply migration
-		if _, err := db.Exec(m.UpSQL); err != nil {
+		// Apply and record the migration together, so a failure leaves
+		// neither behind
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("begin migration %d: %w", m.Version, err)
+		}
+		if _, err := tx.Exec(m.UpSQL); err != nil {
+			tx.Rollback()
 			return fmt.Errorf("apply migration %d (%s): %w", m.Version, m.Name, err)
 		}
-
-		// Record application
-		now := time.Now().UTC().Format(time.RFC3339)
-		_, err = db.Exec(
+		now := formatTime(time.Now())
+		_, err = tx.Exec(
 			"INSERT INTO plugin_migrations (version, name, applied_at) VALUES (?, ?, ?)",
 			m.Version, m.Name, now,
 		)
 		if err != nil {
+			tx.Rollback()
 			return fmt.Errorf("record migration %d (%s): %w", m.Version, m.Name, err)
 		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit migration %d (%s): %w", m.Version, m.Name, err)
+		}
 	}
 
 	return nil
@@ -0,0 +1,155 @@
+package store
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/plugin"
+	"github.com/hyperengineering/engram/migrations"
+	"github.com/pressly/goose/v3"
+)
+
+// migrateTo creates a database at path with core migrations applied up to
+// version and returns it open.
+func migrateTo(t *testing.T, path string, version int64) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	goose.SetLogger(goose.NopLogger())
+	goose.SetBaseFS(migrations.FS)
+	if err := goose.SetDialect("sqlite"); err != nil {
+		t.Fatal(err)
+	}
+	if err := goose.UpTo(db, ".", version); err != nil {
+		t.Fatalf("migrate to %d: %v", version, err)
+	}
+	return db
+}
+
+func schemaVersion(t *testing.T, db *sql.DB) int64 {
+	t.Helper()
+	version, _, err := pendingCoreMigrations(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return version
+}
+
+func TestNewSQLiteStore_FailedMigrationRestoresDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "engram.db")
+	db := migrateTo(t, dbPath, 15)
+	if _, err := db.Exec(`
+		INSERT INTO lore_entries (id, content, category, confidence, source_id, sources, created_at, updated_at)
+		VALUES ('kept', 'existing content', 'PATTERN_OUTCOME', 0.5, 's1', '[]', '2026-01-01T00:00:00Z', '2026-01-01T00:00:00Z')
+	`); err != nil {
+		t.Fatal(err)
+	}
+	// 016 will apply, then 017 fails on a column that already exists
+	if _, err := db.Exec("ALTER TABLE lore_entries ADD COLUMN path TEXT"); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	if _, err := NewSQLiteStore(dbPath); err == nil {
+		t.Fatal("NewSQLiteStore() error = nil, want the failed migration")
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if v := schemaVersion(t, db); v != 15 {
+		t.Errorf("schema version after failed migration = %d, want 15", v)
+	}
+	var content string
+	if err := db.QueryRow("SELECT content FROM lore_entries WHERE id = 'kept'").Scan(&content); err != nil {
+		t.Fatalf("existing lore after restore: %v", err)
+	}
+	var languageColumns int
+	if err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('lore_entries') WHERE name = 'language'").Scan(&languageColumns); err != nil {
+		t.Fatal(err)
+	}
+	if languageColumns != 0 {
+		t.Error("migration 016 should be undone by the restore")
+	}
+	if _, err := os.Stat(dbPath + preMigrationSuffix); !os.IsNotExist(err) {
+		t.Error("backup should be moved back over the database")
+	}
+}
+
+func TestNewSQLiteStore_KeepsPreMigrationBackup(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "engram.db")
+	migrateTo(t, dbPath, 15).Close()
+
+	s, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	s.Close()
+
+	backup, err := sql.Open("sqlite", dbPath+preMigrationSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backup.Close()
+	if v := schemaVersion(t, backup); v != 15 {
+		t.Errorf("backup schema version = %d, want 15", v)
+	}
+}
+
+func TestInspectMigrations(t *testing.T) {
+	dir := t.TempDir()
+	migs := []plugin.Migration{
+		{Version: 1, Name: "widgets", UpSQL: "CREATE TABLE widgets (id TEXT PRIMARY KEY)"},
+		{Version: 2, Name: "gadgets", UpSQL: "CREATE TABLE gadgets (id TEXT PRIMARY KEY)"},
+	}
+
+	missing, err := InspectMigrations(filepath.Join(dir, "missing.db"), "widget", migs)
+	if err != nil {
+		t.Fatalf("InspectMigrations(missing) error = %v", err)
+	}
+	core, err := coreMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing.Exists || len(missing.Pending) != len(core)+2 {
+		t.Errorf("missing database = %+v, want every migration pending", missing)
+	}
+
+	// Leave the two newest core migrations and one plugin migration pending
+	applied := core[len(core)-3].Version
+	dbPath := filepath.Join(dir, "engram.db")
+	db := migrateTo(t, dbPath, applied)
+	if err := RunPluginMigrations(db, migs[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := InspectMigrations(dbPath, "widget", migs)
+	if err != nil {
+		t.Fatalf("InspectMigrations() error = %v", err)
+	}
+	want := []PendingMigration{
+		core[len(core)-2],
+		core[len(core)-1],
+		{Version: 2, Name: "gadgets", Plugin: "widget"},
+	}
+	if !status.Exists || status.Version != applied || len(status.Pending) != len(want) {
+		t.Fatalf("InspectMigrations() = %+v, want version %d with %v pending", status, applied, want)
+	}
+	for i := range want {
+		if status.Pending[i] != want[i] {
+			t.Errorf("Pending[%d] = %+v, want %+v", i, status.Pending[i], want[i])
+		}
+	}
+	if v := schemaVersion(t, db); v != applied {
+		t.Errorf("schema version after inspection = %d, want %d", v, applied)
+	}
+}
@@ -37,7 +37,7 @@ func TestRunMigrations_FreshDatabase(t *testing.T) {
 	_, err = db.Exec(`
 		SELECT id, content, context, category, confidence, embedding, embedding_status,
 		       source_id, sources, validation_count, created_at, updated_at, deleted_at, last_validated_at
-		FROM lore_entries LIMIT 0
+		FROM lore_entries LEFT JOIN lore_embeddings ON lore_id = id LIMIT 0
 	`)
 	if err != nil {
 		t.Fatalf("lore_entries missing required columns: %v", err)
@@ -121,10 +121,13 @@ func TestSchema_Indexes(t *testing.T) {
 	// Then: All required indexes exist
 	expectedIndexes := []string{
 		"idx_lore_entries_category",
-		"idx_lore_entries_embedding_status",
+		"idx_lore_entries_pending",
+		"idx_lore_entries_live_category",
 		"idx_lore_entries_updated_at",
 		"idx_lore_entries_deleted_at",
 		"idx_lore_entries_last_validated_at",
+		"idx_lore_entries_category_deleted_at",
+		"idx_lore_entries_category_bucket",
 	}
 
 	for _, idx := range expectedIndexes {
@@ -0,0 +1,154 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// maxCachedPlans bounds the distinct statements whose plans are kept.
+// Statements past it, such as IN lists of every length, go uncounted.
+const maxCachedPlans = 1000
+
+// queryPlans caches the EXPLAIN QUERY PLAN of each distinct statement a
+// store runs and counts how often each index is used by them.
+type queryPlans struct {
+	mu    sync.Mutex
+	plans map[string]queryPlan
+	uses  map[string]int64 // Statements run per index
+}
+
+// queryPlan is a statement's plan, one line per step, and the indexes it
+// uses.
+type queryPlan struct {
+	steps   []string
+	indexes []string
+	err     error
+}
+
+func newQueryPlans() *queryPlans {
+	return &queryPlans{plans: make(map[string]queryPlan), uses: make(map[string]int64)}
+}
+
+// observe counts the indexes query uses, explaining it on conn the first
+// time it runs. conn must be idle.
+func (p *queryPlans) observe(conn driver.Conn, query string, args []driver.NamedValue) {
+	plan, ok := p.plan(conn, query, args)
+	if !ok {
+		return
+	}
+	p.mu.Lock()
+	for _, idx := range plan.indexes {
+		p.uses[idx]++
+	}
+	p.mu.Unlock()
+}
+
+// plan returns the cached plan of query, explaining it on conn if it is not
+// cached yet. It returns false for a statement past maxCachedPlans.
+func (p *queryPlans) plan(conn driver.Conn, query string, args []driver.NamedValue) (queryPlan, bool) {
+	p.mu.Lock()
+	plan, ok := p.plans[query]
+	full := len(p.plans) >= maxCachedPlans
+	p.mu.Unlock()
+	if ok {
+		return plan, true
+	}
+	if full {
+		return queryPlan{}, false
+	}
+
+	if explainable(query) {
+		plan.steps, plan.err = explainQuery(conn, query, args)
+		plan.indexes = indexesIn(plan.steps)
+	}
+	p.mu.Lock()
+	p.plans[query] = plan
+	p.mu.Unlock()
+	return plan, true
+}
+
+// usage returns the number of statements run that used each index.
+func (p *queryPlans) usage() map[string]int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	uses := make(map[string]int64, len(p.uses))
+	for idx, n := range p.uses {
+		uses[idx] = n
+	}
+	return uses
+}
+
+// explainable reports whether query is a single statement with a query
+// plan. EXPLAIN applies only to the first of several statements, and the
+// driver would run the rest for real.
+func explainable(query string) bool {
+	q := strings.TrimSpace(query)
+	if i := strings.IndexByte(q, ';'); i >= 0 && strings.TrimSpace(q[i+1:]) != "" {
+		return false
+	}
+	verb, _, _ := strings.Cut(q, " ")
+	switch strings.ToUpper(strings.TrimSpace(verb)) {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "REPLACE", "WITH":
+		return true
+	default:
+		return false
+	}
+}
+
+// explainQuery returns the EXPLAIN QUERY PLAN of query run with args, one
+// line per step, indented under its parent step.
+func explainQuery(conn driver.Conn, query string, args []driver.NamedValue) ([]string, error) {
+	qc, ok := conn.(driver.QueryerContext)
+	if !ok {
+		return nil, errors.New("connection cannot query")
+	}
+	rows, err := qc.QueryContext(context.Background(), "EXPLAIN QUERY PLAN "+query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Columns are id, parent, notused and detail; parents precede children
+	depth := map[int64]int{0: -1}
+	var steps []string
+	dest := make([]driver.Value, len(rows.Columns()))
+	for {
+		if err := rows.Next(dest); err != nil {
+			if errors.Is(err, io.EOF) {
+				return steps, nil
+			}
+			return nil, err
+		}
+		if len(dest) < 4 {
+			return nil, errors.New("unexpected query plan columns")
+		}
+		id, _ := dest[0].(int64)
+		parent, _ := dest[1].(int64)
+		detail, _ := dest[3].(string)
+		d := depth[parent] + 1
+		depth[id] = d
+		steps = append(steps, strings.Repeat("  ", d)+detail)
+	}
+}
+
+var planIndexRe = regexp.MustCompile(`USING (?:COVERING )?INDEX (\S+)`)
+
+// indexesIn returns the named indexes the plan steps search or scan, once
+// each.
+func indexesIn(steps []string) []string {
+	var indexes []string
+	for _, step := range steps {
+		for _, m := range planIndexRe.FindAllStringSubmatch(step, -1) {
+			if !slices.Contains(indexes, m[1]) {
+				indexes = append(indexes, m[1])
+			}
+		}
+	}
+	return indexes
+}
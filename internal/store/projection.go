@@ -0,0 +1,72 @@
+package store
+
+import (
+	"math/bits"
+	"math/rand/v2"
+	"sync"
+)
+
+// projectionBits is the number of random hyperplanes an embedding is
+// projected onto; its bucket is the sign pattern of those projections.
+// Changing it invalidates every stored embedding_bucket.
+const projectionBits = 12
+
+// projectionSeed fixes the hyperplanes so buckets stay stable across
+// restarts and between servers.
+const projectionSeed = 0x656e6772616d
+
+// hyperplanes caches the projection hyperplanes per embedding dimension.
+var hyperplanes sync.Map // int -> [][]float32
+
+func hyperplanesFor(dim int) [][]float32 {
+	if planes, ok := hyperplanes.Load(dim); ok {
+		return planes.([][]float32)
+	}
+	rng := rand.New(rand.NewPCG(projectionSeed, uint64(dim)))
+	planes := make([][]float32, projectionBits)
+	for i := range planes {
+		planes[i] = make([]float32, dim)
+		for j := range planes[i] {
+			planes[i][j] = float32(rng.NormFloat64())
+		}
+	}
+	actual, _ := hyperplanes.LoadOrStore(dim, planes)
+	return actual.([][]float32)
+}
+
+// projectionBucket returns the random projection bucket of v. Two
+// embeddings at angle θ agree on each bit with probability 1 - θ/π, so
+// near-duplicates land in the same or a nearby bucket.
+func projectionBucket(v []float32) int64 {
+	var bucket int64
+	for i, plane := range hyperplanesFor(len(v)) {
+		if dot(plane, v) >= 0 {
+			bucket |= 1 << i
+		}
+	}
+	return bucket
+}
+
+// embeddingBucket returns the value stored in the embedding_bucket column:
+// the projection bucket of v, or NULL when there is no embedding.
+func embeddingBucket(v []float32) any {
+	if len(v) == 0 {
+		return nil
+	}
+	return projectionBucket(v)
+}
+
+// probeBuckets returns every bucket within radius bit flips of bucket.
+//
+// At a 0.92 similarity threshold each bit differs with probability about
+// 0.13, so a radius of 3 finds about 93% of entries right at the threshold
+// and 99% of those at 0.97 or above, while probing 299 of 4096 buckets.
+func probeBuckets(bucket int64, radius int) []int64 {
+	var probes []int64
+	for b := int64(0); b < 1<<projectionBits; b++ {
+		if bits.OnesCount64(uint64(b^bucket)) <= radius {
+			probes = append(probes, b)
+		}
+	}
+	return probes
+}
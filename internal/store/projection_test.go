@@ -0,0 +1,126 @@
+package store
+
+import (
+	"context"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// vectorAtSimilarity returns a unit vector with the given cosine similarity
+// to the unit vector base.
+func vectorAtSimilarity(rng *rand.Rand, base []float32, similarity float64) []float32 {
+	// Gram-Schmidt a random direction against base.
+	r := randomVector(rng, len(base))
+	proj := dot(r, base)
+	for i := range r {
+		r[i] -= proj * base[i]
+	}
+	rn := norm(r)
+	s := float32(math.Sqrt(1 - similarity*similarity))
+	v := make([]float32, len(base))
+	for i := range v {
+		v[i] = float32(similarity)*base[i] + s*r[i]/rn
+	}
+	return v
+}
+
+func unitVector(rng *rand.Rand, dim int) []float32 {
+	v := randomVector(rng, dim)
+	n := norm(v)
+	for i := range v {
+		v[i] /= n
+	}
+	return v
+}
+
+func TestProbeBuckets(t *testing.T) {
+	if got := probeBuckets(5, 0); len(got) != 1 || got[0] != 5 {
+		t.Errorf("radius 0 = %v, want [5]", got)
+	}
+	probes := probeBuckets(5, 3)
+	if len(probes) != 299 {
+		t.Errorf("radius 3 probes %d buckets, want 299", len(probes))
+	}
+	for _, b := range probes {
+		if d := bits.OnesCount64(uint64(b ^ 5)); d > 3 {
+			t.Fatalf("bucket %d is %d bits away", b, d)
+		}
+	}
+}
+
+func TestProjectionBucket_Stable(t *testing.T) {
+	v := unitVector(rand.New(rand.NewPCG(1, 2)), 1536)
+	if a, b := projectionBucket(v), projectionBucket(v); a != b {
+		t.Errorf("buckets differ: %d, %d", a, b)
+	}
+	if b := projectionBucket(v); b < 0 || b >= 1<<projectionBits {
+		t.Errorf("bucket %d out of range", b)
+	}
+}
+
+func TestProjectionBucket_Recall(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	for _, tt := range []struct {
+		similarity, minRecall float64
+	}{
+		{0.92, 0.90},
+		{0.97, 0.98},
+	} {
+		const pairs = 1000
+		found := 0
+		for range pairs {
+			base := unitVector(rng, 1536)
+			near := vectorAtSimilarity(rng, base, tt.similarity)
+			if bits.OnesCount64(uint64(projectionBucket(base)^projectionBucket(near))) <= 3 {
+				found++
+			}
+		}
+		if recall := float64(found) / pairs; recall < tt.minRecall {
+			t.Errorf("similarity %.2f: recall within radius 3 = %.3f, want >= %.2f", tt.similarity, recall, tt.minRecall)
+		}
+	}
+}
+
+func TestFindSimilar_ApproximateSkipsDistantBuckets(t *testing.T) {
+	s := newTestStore(t)
+	rng := rand.New(rand.NewPCG(5, 6))
+	base := unitVector(rng, 64)
+	opposite := make([]float32, len(base))
+	for i := range base {
+		opposite[i] = -base[i]
+	}
+	insertEntryWithEmbedding(t, s, "Near", "PATTERN_OUTCOME", base)
+	insertEntryWithEmbedding(t, s, "Far", "PATTERN_OUTCOME", opposite)
+	ctx := context.Background()
+
+	// A threshold of -2 accepts everything scanned, so the result shows
+	// which entries were candidates.
+	exact, err := s.FindSimilar(ctx, base, "PATTERN_OUTCOME", -2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exact) != 2 {
+		t.Fatalf("exact scan found %d entries, want 2", len(exact))
+	}
+
+	s.SetDependencies(&mockEmbedder{}, &mockConfig{approximate: true, probeRadius: 3})
+	approx, err := s.FindSimilar(ctx, base, "PATTERN_OUTCOME", -2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(approx) != 1 || approx[0].Content != "Near" {
+		t.Errorf("approximate scan = %+v, want only the near entry", contents(approx))
+	}
+}
+
+func contents(entries []types.SimilarEntry) []string {
+	var out []string
+	for _, e := range entries {
+		out = append(out, e.Content)
+	}
+	return out
+}
@@ -0,0 +1,182 @@
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Quantization is how embeddings are encoded when stored. Stored
+// embeddings are decoded on read whatever their encoding, so changing it
+// applies to embeddings written from then on without rewriting older ones.
+type Quantization string
+
+const (
+	// QuantizationNone stores float32 values, 4 bytes per dimension.
+	QuantizationNone Quantization = "none"
+	// QuantizationFloat16 stores IEEE half-precision values, 2 bytes per
+	// dimension.
+	QuantizationFloat16 Quantization = "float16"
+	// QuantizationInt8 stores values as signed bytes scaled by the largest
+	// magnitude in the vector, 1 byte per dimension.
+	QuantizationInt8 Quantization = "int8"
+)
+
+// ParseQuantization returns the quantization named s. An empty s is
+// QuantizationNone.
+func ParseQuantization(s string) (Quantization, error) {
+	switch q := Quantization(s); q {
+	case "", QuantizationNone:
+		return QuantizationNone, nil
+	case QuantizationFloat16, QuantizationInt8:
+		return q, nil
+	default:
+		return "", fmt.Errorf("unknown embedding quantization %q (want none, float16 or int8)", s)
+	}
+}
+
+// Quantized blobs end in a tag byte and never have a length divisible by
+// four, which tells them apart from float32 blobs. An int8 blob whose
+// length would be divisible by four gets a padding byte before its scale
+// and a different tag.
+const (
+	tagFloat16    = 0x01
+	tagInt8       = 0x02
+	tagInt8Padded = 0x03
+)
+
+// pack encodes v for storage.
+func (q Quantization) pack(v []float32) []byte {
+	switch q {
+	case QuantizationFloat16:
+		buf := make([]byte, 2*len(v)+1)
+		for i, f := range v {
+			binary.LittleEndian.PutUint16(buf[2*i:], float16bits(f))
+		}
+		buf[len(buf)-1] = tagFloat16
+		return buf
+	case QuantizationInt8:
+		var maxAbs float32
+		for _, f := range v {
+			maxAbs = max(maxAbs, float32(math.Abs(float64(f))))
+		}
+		var scale float32
+		if maxAbs > 0 && !math.IsInf(float64(maxAbs), 0) {
+			scale = maxAbs / 127
+		}
+		pad := 0
+		if (len(v)+5)%4 == 0 {
+			pad = 1
+		}
+		buf := make([]byte, len(v)+pad+5)
+		for i, f := range v {
+			if scale != 0 {
+				buf[i] = byte(int8(max(-127, min(127, math.Round(float64(f/scale))))))
+			}
+		}
+		binary.LittleEndian.PutUint32(buf[len(v)+pad:], math.Float32bits(scale))
+		buf[len(buf)-1] = tagInt8 + byte(pad)
+		return buf
+	default:
+		return packEmbedding(v)
+	}
+}
+
+// round returns v as it reads back once stored, so the norm and bucket
+// written alongside it describe the stored vector.
+func (q Quantization) round(v []float32) []float32 {
+	if q == QuantizationNone || q == "" || len(v) == 0 {
+		return v
+	}
+	return unpackEmbedding(q.pack(v))
+}
+
+// unpackQuantizedInto decodes a quantized blob into dst, reusing its
+// capacity. A blob with an unknown tag decodes to no values.
+func unpackQuantizedInto(dst []float32, b []byte) []float32 {
+	body := b[:len(b)-1]
+	switch b[len(b)-1] {
+	case tagFloat16:
+		dst = resize(dst, len(body)/2)
+		for i := range dst {
+			dst[i] = float16to32(binary.LittleEndian.Uint16(body[2*i:]))
+		}
+	case tagInt8, tagInt8Padded:
+		if len(body) < 4 {
+			return dst[:0]
+		}
+		scale := math.Float32frombits(binary.LittleEndian.Uint32(body[len(body)-4:]))
+		values := body[:len(body)-4]
+		if b[len(b)-1] == tagInt8Padded && len(values) > 0 {
+			values = values[:len(values)-1]
+		}
+		dst = resize(dst, len(values))
+		for i, x := range values {
+			dst[i] = float32(int8(x)) * scale
+		}
+	default:
+		return dst[:0]
+	}
+	return dst
+}
+
+// resize returns dst with length n, reallocating only if it lacks capacity.
+func resize(dst []float32, n int) []float32 {
+	if cap(dst) < n {
+		return make([]float32, n)
+	}
+	return dst[:n]
+}
+
+// float16bits converts f to IEEE half precision, rounding to nearest even.
+// Values too large for half precision become infinities.
+func float16bits(f float32) uint16 {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	if b&0x7fffffff > 0x7f800000 {
+		return sign | 0x7e00 // NaN
+	}
+	exp := int32(b>>23&0xff) - 127 + 15
+	mant := b & 0x7fffff
+	switch {
+	case exp >= 0x1f:
+		return sign | 0x7c00
+	case exp <= 0:
+		// Subnormal in half precision, or too small and flushed to zero
+		if exp < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint32(14 - exp)
+		half := mant >> shift
+		rem, mid := mant&(1<<shift-1), uint32(1)<<(shift-1)
+		if rem > mid || rem == mid && half&1 == 1 {
+			half++
+		}
+		return sign | uint16(half)
+	}
+	half := uint32(exp)<<10 | mant>>13
+	// A carry out of the mantissa correctly bumps the exponent
+	if rem := mant & 0x1fff; rem > 0x1000 || rem == 0x1000 && half&1 == 1 {
+		half++
+	}
+	return sign | uint16(half)
+}
+
+// float16to32 converts an IEEE half precision value to float32.
+func float16to32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+	switch exp {
+	case 0:
+		f := float32(mant) / (1 << 24)
+		if sign != 0 {
+			f = -f
+		}
+		return f
+	case 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	}
+	return math.Float32frombits(sign | (exp+112)<<23 | mant<<13)
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestFloat16Bits(t *testing.T) {
+	tests := []struct {
+		f    float32
+		want uint16
+	}{
+		{0, 0x0000},
+		{1, 0x3c00},
+		{-2, 0xc000},
+		{0.5, 0x3800},
+		{65504, 0x7bff},                  // largest finite half
+		{1e6, 0x7c00},                    // overflows to infinity
+		{float32(math.Ldexp(1, -24)), 1}, // smallest subnormal
+		{float32(math.Ldexp(1, -26)), 0}, // rounds to zero
+		{float32(math.Inf(-1)), 0xfc00},
+	}
+	for _, tt := range tests {
+		if got := float16bits(tt.f); got != tt.want {
+			t.Errorf("float16bits(%v) = %#04x, want %#04x", tt.f, got, tt.want)
+		}
+		if got := float16to32(tt.want); tt.want != 0x7c00 && tt.f != float32(math.Ldexp(1, -26)) && got != tt.f {
+			t.Errorf("float16to32(%#04x) = %v, want %v", tt.want, got, tt.f)
+		}
+	}
+	if h := float16bits(float32(math.NaN())); !math.IsNaN(float64(float16to32(h))) {
+		t.Errorf("NaN converted to %#04x", h)
+	}
+}
+
+func TestQuantization_PackRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	tolerance := map[Quantization]float64{
+		QuantizationNone:    0,
+		QuantizationFloat16: 1e-3,
+		QuantizationInt8:    1.0 / 127,
+	}
+	for q, tol := range tolerance {
+		for _, dims := range []int{1, 2, 3, 4, 7, 8, 1536} {
+			v := make([]float32, dims)
+			for i := range v {
+				v[i] = float32(rng.NormFloat64() * 0.05)
+			}
+			blob := q.pack(v)
+			if q != QuantizationNone && len(blob)%4 == 0 {
+				t.Fatalf("%s blob of %d dims has length %d, indistinguishable from float32", q, dims, len(blob))
+			}
+			got := unpackEmbedding(blob)
+			if len(got) != dims {
+				t.Fatalf("%s round trip of %d dims returned %d", q, dims, len(got))
+			}
+			var maxAbs float64
+			for _, f := range v {
+				maxAbs = max(maxAbs, math.Abs(float64(f)))
+			}
+			for i := range v {
+				if diff := math.Abs(float64(got[i] - v[i])); diff > tol*maxAbs {
+					t.Fatalf("%s dim %d of %d: got %v, want %v", q, i, dims, got[i], v[i])
+				}
+			}
+			// Storing what reads back changes nothing further
+			if again := q.round(got); cosineSimilarity(again, got) < 0.999999 {
+				t.Errorf("%s round is not stable", q)
+			}
+		}
+	}
+}
+
+func TestQuantization_ZeroVector(t *testing.T) {
+	got := unpackEmbedding(QuantizationInt8.pack(make([]float32, 4)))
+	if len(got) != 4 || got[0] != 0 {
+		t.Errorf("zero vector round trip = %v", got)
+	}
+}
+
+func TestParseQuantization(t *testing.T) {
+	for in, want := range map[string]Quantization{
+		"":        QuantizationNone,
+		"none":    QuantizationNone,
+		"float16": QuantizationFloat16,
+		"int8":    QuantizationInt8,
+	} {
+		if got, err := ParseQuantization(in); err != nil || got != want {
+			t.Errorf("ParseQuantization(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseQuantization("int4"); err == nil {
+		t.Error("ParseQuantization(int4) error = nil")
+	}
+}
@@ -0,0 +1,120 @@
+package store
+
+import (
+	"database/sql/driver"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithSlowQueryThreshold logs statements that spend at least d in SQLite,
+// along with their EXPLAIN QUERY PLAN, so a scan that wants an index shows
+// up in production. Zero logs none.
+func WithSlowQueryThreshold(d time.Duration) StoreOption {
+	return func(s *SQLiteStore) {
+		s.slowQueryThreshold = d
+	}
+}
+
+// slowQueryLogInterval is how often the same slow query is logged; the
+// line carries how many times it was slow since the last one.
+const slowQueryLogInterval = time.Minute
+
+// maxSlowQueries bounds the distinct queries tracked between log lines.
+const maxSlowQueries = 1000
+
+// maxLoggedQueryLen truncates long statements, such as multi-row inserts.
+const maxLoggedQueryLen = 2000
+
+// slowQueryLog logs statements slower than a threshold.
+type slowQueryLog struct {
+	threshold time.Duration
+	storeID   string
+	plans     *queryPlans
+
+	mu      sync.Mutex
+	queries map[string]*slowQuery
+}
+
+// slowQuery tracks one statement's slow runs since it was last logged.
+type slowQuery struct {
+	loggedAt time.Time
+	count    int
+	worst    time.Duration
+}
+
+func newSlowQueryLog(threshold time.Duration, storeID string, plans *queryPlans) *slowQueryLog {
+	return &slowQueryLog{threshold: threshold, storeID: storeID, plans: plans, queries: make(map[string]*slowQuery)}
+}
+
+// observe logs query if it took at least the threshold and was not logged
+// within slowQueryLogInterval. conn must be idle, as it may explain the
+// query.
+func (l *slowQueryLog) observe(conn driver.Conn, query string, args []driver.NamedValue, elapsed time.Duration) {
+	if elapsed < l.threshold {
+		return
+	}
+	now := time.Now()
+	l.mu.Lock()
+	q := l.queries[query]
+	if q == nil {
+		if len(l.queries) >= maxSlowQueries {
+			l.prune(now)
+		}
+		q = &slowQuery{}
+		l.queries[query] = q
+	}
+	q.count++
+	q.worst = max(q.worst, elapsed)
+	if now.Sub(q.loggedAt) < slowQueryLogInterval {
+		l.mu.Unlock()
+		return
+	}
+	count, worst := q.count, q.worst
+	q.loggedAt, q.count, q.worst = now, 0, 0
+	l.mu.Unlock()
+
+	attrs := []any{
+		"component", "store",
+		"action", "slow_query",
+		"store_id", l.storeID,
+		"duration_ms", elapsed.Milliseconds(),
+		"worst_ms", worst.Milliseconds(),
+		"count", count,
+		"query", compactQuery(query),
+	}
+	plan, ok := l.plans.plan(conn, query, args)
+	if !ok && explainable(query) {
+		plan.steps, plan.err = explainQuery(conn, query, args)
+	}
+	if plan.err != nil {
+		attrs = append(attrs, "plan_error", plan.err)
+	} else {
+		attrs = append(attrs, "plan", plan.steps)
+	}
+	slog.Warn("slow query", attrs...)
+}
+
+// prune forgets queries not slow since slowQueryLogInterval, or all of
+// them if every one was. Callers hold mu.
+func (l *slowQueryLog) prune(now time.Time) {
+	for query, q := range l.queries {
+		if q.count == 0 && now.Sub(q.loggedAt) >= slowQueryLogInterval {
+			delete(l.queries, query)
+		}
+	}
+	if len(l.queries) >= maxSlowQueries {
+		clear(l.queries)
+	}
+}
+
+// compactQuery collapses the whitespace of query onto one line and
+// truncates it to maxLoggedQueryLen bytes.
+func compactQuery(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > maxLoggedQueryLen {
+		query = query[:maxLoggedQueryLen] + "..."
+	}
+	return query
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithSlowQueryThreshold_LogsPlan(t *testing.T) {
+	var logBuf bytes.Buffer
+	oldLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, nil)))
+	defer slog.SetDefault(oldLogger)
+
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "slow.db"),
+		WithStoreID("slow"), WithSlowQueryThreshold(time.Nanosecond))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer s.Close()
+	if strings.Contains(logBuf.String(), "slow query") {
+		t.Fatalf("migrations were logged as slow: %s", logBuf.String())
+	}
+
+	// Run the same scan twice, once prepared; only the first is logged
+	ctx := context.Background()
+	query := `SELECT id FROM lore_entries
+		WHERE content = ?`
+	for range 2 {
+		rows, err := s.db.QueryContext(ctx, query, "needle")
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows.Close()
+	}
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	if _, err := stmt.ExecContext(ctx, "needle"); err != nil {
+		t.Fatal(err)
+	}
+
+	var logged []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logBuf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line %q: %v", line, err)
+		}
+		if entry["msg"] == "slow query" && strings.Contains(entry["query"].(string), "content = ?") {
+			logged = append(logged, entry)
+		}
+	}
+	if len(logged) != 1 {
+		t.Fatalf("logged the slow query %d times, want 1", len(logged))
+	}
+	entry := logged[0]
+	if entry["query"] != "SELECT id FROM lore_entries WHERE content = ?" || entry["store_id"] != "slow" {
+		t.Errorf("slow query log = %v, want the compacted query and store ID", entry)
+	}
+	plan, _ := entry["plan"].([]any)
+	if len(plan) == 0 || !strings.Contains(plan[0].(string), "SCAN lore_entries") {
+		t.Errorf("plan = %v, want a scan of lore_entries", entry["plan"])
+	}
+}
+
+func TestSlowQueryLog_Threshold(t *testing.T) {
+	var logBuf bytes.Buffer
+	oldLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, nil)))
+	defer slog.SetDefault(oldLogger)
+
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "fast.db"), WithSlowQueryThreshold(time.Hour))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer s.Close()
+
+	if _, err := s.GetStats(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(logBuf.String(), "slow query") {
+		t.Errorf("queries under the threshold were logged: %s", logBuf.String())
+	}
+}
@@ -12,11 +12,18 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/hyperengineering/engram/internal/hlc"
+	"github.com/hyperengineering/engram/internal/language"
+	"github.com/hyperengineering/engram/internal/normalize"
+	"github.com/hyperengineering/engram/internal/plugin"
+	engramsync "github.com/hyperengineering/engram/internal/sync"
 	"github.com/hyperengineering/engram/internal/types"
 	"github.com/oklog/ulid/v2"
 	_ "modernc.org/sqlite"
@@ -41,6 +48,9 @@ const (
 	MinConfidence            = 0.0  // FR21: floor
 )
 
+// SourceStatsLimit caps the sources listed in extended stats.
+const SourceStatsLimit = 20
+
 // Decay constants (FR22)
 const (
 	DefaultDecayAmount = 0.01 // FR22: -0.01 per decay cycle
@@ -98,6 +108,35 @@ type SQLiteStore struct {
 	lastSnapshot *time.Time
 	lastDecay    atomic.Pointer[time.Time]    // Per-instance decay tracking (thread-safe)
 	snapshotMeta atomic.Pointer[snapshotMeta] // Per-instance snapshot metadata
+	hooks        plugin.DomainPlugin          // Optional; consulted for ingest/delete hooks
+	normalize    normalize.Options            // Applied to ingested content before embedding
+	categories   []string                     // Categories accepted at ingest; empty accepts all
+	threshold    float64                      // Deduplication similarity override; zero uses cfg
+	clock        *hlc.Clock                   // Stamps change_log entries
+	quantization Quantization                 // Encoding of embeddings written
+	faults       Faults                       // Injected into statements once open
+	tuning       Tuning                       // Pragmas applied to every connection
+	hooksArmed   atomic.Bool                  // Set once open; see connHooks
+	plans        *queryPlans                  // Statement plans and index usage since open
+
+	slowQueryThreshold time.Duration // Zero logs no slow queries
+
+	// Cap on repeated feedback from one source on one entry, and when its
+	// ended windows were last pruned, in Unix nanoseconds
+	feedbackLimit    FeedbackLimit
+	feedbackPrunedAt atomic.Int64
+	selfFeedback     SelfFeedback // Applied to helpful feedback from an entry's own sources
+
+	// Embedding dimension recorded in store_metadata, zero until the first
+	// embedding, and embeddings refused for another since the store opened
+	dims          atomic.Int64
+	dimMismatches atomic.Int64
+	lastMismatch  atomic.Int64
+
+	// Push idempotency cache counters since the store was opened
+	idempotencyHits      atomic.Int64
+	idempotencyMisses    atomic.Int64
+	idempotencyEvictions atomic.Int64
 }
 
 // StoreOption configures optional settings for SQLiteStore.
@@ -110,6 +149,47 @@ func WithStoreID(id string) StoreOption {
 	}
 }
 
+// WithPluginHooks attaches the store type's domain plugin so that any
+// optional hooks it implements (see plugin.BeforeIngestHook and friends)
+// run during IngestLore and DeleteLore.
+func WithPluginHooks(p plugin.DomainPlugin) StoreOption {
+	return func(s *SQLiteStore) {
+		s.hooks = p
+	}
+}
+
+// WithNormalization rewrites the content of ingested entries with opts
+// before they are embedded and deduplicated.
+func WithNormalization(opts normalize.Options) StoreOption {
+	return func(s *SQLiteStore) {
+		s.normalize = opts
+	}
+}
+
+// WithCategories restricts ingest to the given lore categories. Entries in
+// any other category are rejected. An empty list accepts every category.
+func WithCategories(categories []string) StoreOption {
+	return func(s *SQLiteStore) {
+		s.categories = categories
+	}
+}
+
+// WithSimilarityThreshold overrides the configured similarity at which an
+// ingested entry merges into existing lore. Zero keeps the configured value.
+func WithSimilarityThreshold(threshold float64) StoreOption {
+	return func(s *SQLiteStore) {
+		s.threshold = threshold
+	}
+}
+
+// WithEmbeddingQuantization stores embeddings written from now on with
+// the given quantization. Embeddings already stored keep their encoding.
+func WithEmbeddingQuantization(q Quantization) StoreOption {
+	return func(s *SQLiteStore) {
+		s.quantization = q
+	}
+}
+
 // Embedder is the interface for embedding generation (matches embedding.Embedder).
 type Embedder interface {
 	Embed(ctx context.Context, content string) ([]float32, error)
@@ -121,6 +201,15 @@ type Embedder interface {
 type Config interface {
 	GetDeduplicationEnabled() bool
 	GetSimilarityThreshold() float64
+	// GetDeduplicationProbeRadius reports whether similarity scans are
+	// approximate and, if so, how many bucket bits may differ from the query's.
+	GetDeduplicationProbeRadius() (radius int, approximate bool)
+	// GetDeduplicationScope returns the maximum age and minimum confidence
+	// of duplicate candidates; zero values mean no limit.
+	GetDeduplicationScope() (maxAge time.Duration, minConfidence float64)
+	// GetEmbeddingChunking returns the chunk size and overlap used to embed
+	// long entries as several vectors; a size of zero disables chunking.
+	GetEmbeddingChunking() (size, overlap int)
 }
 
 // NewSQLiteStore creates a new SQLiteStore instance.
@@ -134,10 +223,26 @@ func NewSQLiteStore(dbPath string, opts ...StoreOption) (*SQLiteStore, error) {
 		}
 	}
 
-	db, err := sql.Open("sqlite", dbPath)
+	// Options are applied before opening, since faults wrap the connections
+	store := &SQLiteStore{dbPath: dbPath, clock: hlc.NewClock(0), quantization: QuantizationNone}
+	for _, opt := range opts {
+		opt(store)
+	}
+
+	dsn := dbPath
+	if dbPath != ":memory:" {
+		dsn = tunedDSN(dbPath, store.tuning)
+	}
+	store.plans = newQueryPlans()
+	hooks := connHooks{faults: store.faults, plans: store.plans, armed: &store.hooksArmed}
+	if store.slowQueryThreshold > 0 {
+		hooks.slow = newSlowQueryLog(store.slowQueryThreshold, store.storeID, store.plans)
+	}
+	db, err := openDB(dsn, hooks)
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
+	store.db = db
 
 	// For in-memory databases, limit to single connection to ensure all
 	// operations see the same database (each :memory: connection gets its own DB)
@@ -151,19 +256,35 @@ func NewSQLiteStore(dbPath string, opts ...StoreOption) (*SQLiteStore, error) {
 		return nil, fmt.Errorf("enable pragmas: %w", err)
 	}
 
-	// Run goose migrations
-	if err := RunMigrations(db); err != nil {
-		db.Close()
+	// Run goose migrations, restoring the database if one fails
+	if err := migrateWithBackup(db, dbPath); err != nil {
 		return nil, fmt.Errorf("run migrations: %w", err)
 	}
+	if err := backfillEmbeddingIndex(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("backfill embedding index: %w", err)
+	}
+	if err := backfillLanguage(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("backfill language: %w", err)
+	}
 
-	store := &SQLiteStore{db: db, dbPath: dbPath}
+	dims, err := loadEmbeddingDimensions(db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("load embedding dimensions: %w", err)
+	}
+	store.dims.Store(int64(dims))
 
-	// Apply options
-	for _, opt := range opts {
-		opt(store)
+	// Resume the clock after the latest stamped change
+	var lastHLC int64
+	if err := db.QueryRow(`SELECT COALESCE(MAX(hlc), 0) FROM change_log`).Scan(&lastHLC); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("read latest hlc: %w", err)
 	}
+	store.clock.Observe(hlc.Timestamp(lastHLC))
 
+	store.hooksArmed.Store(true)
 	return store, nil
 }
 
@@ -202,6 +323,13 @@ func (s *SQLiteStore) Close() error {
 	return s.db.Close()
 }
 
+// Checkpoint copies the write-ahead log into the database file and
+// truncates it, so a store closed afterwards leaves no WAL to replay.
+func (s *SQLiteStore) Checkpoint(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)")
+	return err
+}
+
 // snapshotDir returns the directory for snapshot files.
 func (s *SQLiteStore) snapshotDir() string {
 	return filepath.Join(filepath.Dir(s.dbPath), "snapshots")
@@ -219,16 +347,28 @@ func (s *SQLiteStore) Record(lore types.Lore, embedding []float32) (*types.Lore,
 	lore.ID = ulid.Make().String()
 	lore.CreatedAt = now
 	lore.UpdatedAt = now
-	lore.Embedding = packEmbedding(embedding)
+	lore.Embedding = s.quantization.pack(embedding)
+	stored := s.quantization.round(embedding)
 
-	_, err := s.db.Exec(`
-		INSERT INTO lore_entries (id, content, context, category, confidence, embedding, source_id, validation_count, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, lore.ID, lore.Content, lore.Context, lore.Category, lore.Confidence, lore.Embedding, lore.SourceID, lore.ValidationCount, lore.CreatedAt.Format(time.RFC3339), lore.UpdatedAt.Format(time.RFC3339))
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
+	_, err = tx.Exec(`
+		INSERT INTO lore_entries (id, content, context, category, confidence, embedding_norm, embedding_bucket, source_id, validation_count, created_at, updated_at, language)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, lore.ID, lore.Content, lore.Context, lore.Category, lore.Confidence, embeddingNorm(stored), embeddingBucket(stored), lore.SourceID, lore.ValidationCount, formatTime(lore.CreatedAt), formatTime(lore.UpdatedAt), language.Detect(lore.Content))
 	if err != nil {
 		return nil, err
 	}
+	if err := storeEmbeddingInTx(context.Background(), tx, lore.ID, embedding, s.quantization); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 
 	return &lore, nil
 }
@@ -269,9 +409,10 @@ func (s *SQLiteStore) GetLastDecay() *time.Time {
 
 // snapshotMeta holds observability data captured at snapshot generation.
 type snapshotMeta struct {
-	loreCount   int64
-	sizeBytes   int64
-	generatedAt time.Time
+	loreCount    int64
+	sizeBytes    int64
+	generatedAt  time.Time
+	baseSequence int64
 }
 
 // SetSnapshotMeta updates the snapshot metadata for this store instance.
@@ -284,6 +425,17 @@ func (s *SQLiteStore) SetSnapshotMeta(loreCount, sizeBytes int64, generatedAt ti
 	})
 }
 
+// SnapshotBaseSequence returns the latest change_log sequence captured in
+// the current snapshot, the delta cursor a client bootstrapped from it
+// resumes after. ok is false if no snapshot has been generated.
+func (s *SQLiteStore) SnapshotBaseSequence() (seq int64, ok bool) {
+	meta := s.GetSnapshotMeta()
+	if meta == nil {
+		return 0, false
+	}
+	return meta.baseSequence, true
+}
+
 // GetSnapshotMeta returns the snapshot metadata for this store instance.
 // Returns nil if no snapshot has been generated.
 func (s *SQLiteStore) GetSnapshotMeta() *snapshotMeta {
@@ -301,10 +453,12 @@ func (s *SQLiteStore) GetExtendedStats(ctx context.Context) (*types.ExtendedStat
 	now := time.Now().UTC()
 
 	stats := &types.ExtendedStats{
-		CategoryStats: make(map[string]int64),
-		StatsAsOf:     now,
-		LastSnapshot:  s.lastSnapshot,    // Backward compatibility
-		LastDecay:     s.lastDecay.Load(), // Per-instance decay tracking (thread-safe)
+		CategoryStats:         make(map[string]int64),
+		LanguageStats:         make(map[string]int64),
+		CategoryLanguageStats: make(map[string]map[string]int64),
+		StatsAsOf:             now,
+		LastSnapshot:          s.lastSnapshot,     // Backward compatibility
+		LastDecay:             s.lastDecay.Load(), // Per-instance decay tracking (thread-safe)
 	}
 
 	// Main aggregates query (single pass for most metrics)
@@ -346,12 +500,12 @@ func (s *SQLiteStore) GetExtendedStats(ctx context.Context) (*types.ExtendedStat
 		stats.QualityStats.AverageConfidence = avgConfidence.Float64
 	}
 
-	// Category distribution query
+	// Category and language distribution query
 	catQuery := `
-		SELECT category, COUNT(*)
+		SELECT category, COALESCE(language, 'und'), COUNT(*)
 		FROM lore_entries
 		WHERE deleted_at IS NULL
-		GROUP BY category`
+		GROUP BY category, language`
 
 	rows, err := s.db.QueryContext(ctx, catQuery)
 	if err != nil {
@@ -360,38 +514,191 @@ func (s *SQLiteStore) GetExtendedStats(ctx context.Context) (*types.ExtendedStat
 	defer rows.Close()
 
 	for rows.Next() {
-		var category string
+		var category, lang string
 		var count int64
-		if err := rows.Scan(&category, &count); err != nil {
+		if err := rows.Scan(&category, &lang, &count); err != nil {
 			return nil, fmt.Errorf("scanning category row: %w", err)
 		}
-		stats.CategoryStats[category] = count
+		stats.CategoryStats[category] += count
+		stats.LanguageStats[lang] += count
+		if stats.CategoryLanguageStats[category] == nil {
+			stats.CategoryLanguageStats[category] = make(map[string]int64)
+		}
+		stats.CategoryLanguageStats[category][lang] += count
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterating category rows: %w", err)
 	}
 
-	// Build SnapshotStats from metadata
-	meta := s.GetSnapshotMeta()
-	if meta != nil {
-		stats.SnapshotStats = types.SnapshotStats{
-			LoreCount:      meta.loreCount,
-			SizeBytes:      meta.sizeBytes,
-			GeneratedAt:    &meta.generatedAt,
-			AgeSeconds:     int64(now.Sub(meta.generatedAt).Seconds()),
-			PendingEntries: stats.ActiveLore - meta.loreCount,
-			Available:      true,
+	if stats.SourceStats, err = s.sourceStats(ctx, SourceStatsLimit); err != nil {
+		return nil, err
+	}
+
+	// Push idempotency cache
+	if stats.IdempotencyStats, err = s.IdempotencyStats(ctx); err != nil {
+		return nil, err
+	}
+
+	if stats.IndexStats, err = s.IndexStats(ctx); err != nil {
+		return nil, err
+	}
+
+	stats.SnapshotStats = s.snapshotStats(now, stats.ActiveLore)
+
+	if stats.DiskStats, err = s.DiskStats(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// DiskStats returns the size of the store's database, write-ahead log and
+// snapshot files. Files that don't exist count as zero.
+func (s *SQLiteStore) DiskStats() (types.DiskStats, error) {
+	var stats types.DiskStats
+	if s.dbPath == ":memory:" {
+		return stats, nil
+	}
+
+	fileSize := func(path string) (int64, error) {
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			return 0, nil
 		}
-	} else {
-		stats.SnapshotStats = types.SnapshotStats{
-			Available: false,
+		if err != nil {
+			return 0, fmt.Errorf("stat %s: %w", filepath.Base(path), err)
 		}
+		return info.Size(), nil
+	}
+	var err error
+	if stats.DatabaseBytes, err = fileSize(s.dbPath); err != nil {
+		return stats, err
+	}
+	if stats.WALBytes, err = fileSize(s.dbPath + "-wal"); err != nil {
+		return stats, err
 	}
 
+	entries, err := os.ReadDir(s.snapshotDir())
+	if err != nil && !os.IsNotExist(err) {
+		return stats, fmt.Errorf("read snapshot directory: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		size, err := fileSize(filepath.Join(s.snapshotDir(), e.Name()))
+		if err != nil {
+			return stats, err
+		}
+		stats.SnapshotBytes += size
+	}
+
+	stats.TotalBytes = stats.DatabaseBytes + stats.WALBytes + stats.SnapshotBytes
 	return stats, nil
 }
 
+// GetSnapshotStats returns the snapshot section of GetExtendedStats without
+// computing the rest.
+func (s *SQLiteStore) GetSnapshotStats(ctx context.Context) (types.SnapshotStats, error) {
+	var activeLore int64
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM lore_entries WHERE deleted_at IS NULL`).Scan(&activeLore)
+	if err != nil {
+		return types.SnapshotStats{}, fmt.Errorf("count active lore: %w", err)
+	}
+	return s.snapshotStats(time.Now().UTC(), activeLore), nil
+}
+
+// snapshotStats builds SnapshotStats from the snapshot metadata as of now.
+func (s *SQLiteStore) snapshotStats(now time.Time, activeLore int64) types.SnapshotStats {
+	meta := s.GetSnapshotMeta()
+	if meta == nil {
+		return types.SnapshotStats{Available: false}
+	}
+	return types.SnapshotStats{
+		LoreCount:      meta.loreCount,
+		SizeBytes:      meta.sizeBytes,
+		GeneratedAt:    &meta.generatedAt,
+		AgeSeconds:     int64(now.Sub(meta.generatedAt).Seconds()),
+		PendingEntries: activeLore - meta.loreCount,
+		BaseSequence:   meta.baseSequence,
+		Available:      true,
+	}
+}
+
+// sourceStats returns the limit sources contributing to the most live
+// entries, by creating them or being merged into them.
+func (s *SQLiteStore) sourceStats(ctx context.Context, limit int) ([]types.SourceStats, error) {
+	// Sources merged into an entry are listed in its sources after its
+	// creator. Last seen is looked up for the top sources only.
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT source_id, entries, avg_confidence, merged, last_created,
+		       (SELECT c.created_at FROM change_log c WHERE c.source_id = top.source_id ORDER BY c.sequence DESC LIMIT 1)
+		FROM (
+			SELECT source_id,
+			       SUM(owned) AS entries,
+			       COALESCE(AVG(CASE WHEN owned THEN confidence END), 0) AS avg_confidence,
+			       SUM(NOT owned) AS merged,
+			       MAX(created_at) AS last_created
+			FROM (
+				SELECT source_id, 1 AS owned, confidence, created_at
+				FROM lore_entries WHERE deleted_at IS NULL
+				UNION ALL
+				SELECT j.value, 0, NULL, NULL
+				FROM lore_entries l, json_each(CASE WHEN json_valid(l.sources) THEN l.sources ELSE '[]' END) j
+				WHERE l.deleted_at IS NULL AND j.value != l.source_id
+			)
+			GROUP BY source_id
+			ORDER BY COUNT(*) DESC, source_id
+			LIMIT ?
+		) top
+		ORDER BY entries + merged DESC, source_id
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("source stats query: %w", err)
+	}
+	defer rows.Close()
+
+	sources := make([]types.SourceStats, 0)
+	for rows.Next() {
+		var (
+			st                      types.SourceStats
+			lastCreated, lastLogged sql.NullString
+		)
+		if err := rows.Scan(&st.SourceID, &st.Entries, &st.AverageConfidence, &st.Merged, &lastCreated, &lastLogged); err != nil {
+			return nil, fmt.Errorf("scanning source row: %w", err)
+		}
+		st.MergeRate = float64(st.Merged) / float64(st.Entries+st.Merged)
+		// The change log may have been compacted past the source's entries
+		last := max(lastCreated.String, lastLogged.String)
+		if t, err := time.Parse(time.RFC3339, last); err == nil {
+			st.LastSeen = &t
+		}
+		sources = append(sources, st)
+	}
+	return sources, rows.Err()
+}
+
+// timeLayout is the layout of timestamps written by the store: RFC 3339 in
+// UTC with a fixed nine-digit fraction. Unlike time.RFC3339Nano, which
+// trims trailing zeros, every value has the same width, so stored
+// timestamps order correctly when SQLite compares them as strings.
+const timeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// formatTime formats t for storage.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// normalizeTime reformats an RFC 3339 timestamp received from a client for
+// storage. Values that do not parse are returned unchanged.
+func normalizeTime(v string) string {
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return v
+	}
+	return formatTime(t)
+}
 
 func packEmbedding(v []float32) []byte {
 	buf := make([]byte, len(v)*4)
@@ -402,24 +709,7 @@ func packEmbedding(v []float32) []byte {
 }
 
 func unpackEmbedding(b []byte) []float32 {
-	v := make([]float32, len(b)/4)
-	for i := range v {
-		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[i*4:]))
-	}
-	return v
-}
-
-func cosineSimilarity(a, b []float32) float64 {
-	var dot, normA, normB float64
-	for i := range a {
-		dot += float64(a[i]) * float64(b[i])
-		normA += float64(a[i]) * float64(a[i])
-		normB += float64(b[i]) * float64(b[i])
-	}
-	if normA == 0 || normB == 0 {
-		return 0
-	}
-	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+	return unpackEmbeddingInto(nil, b)
 }
 
 // scanLoreEntry scans a row into a LoreEntry, handling BLOB unpacking and JSON parsing.
@@ -428,7 +718,7 @@ func scanLoreEntry(scanner interface{ Scan(...any) error }) (*types.LoreEntry, e
 	var embeddingBlob []byte
 	var sourcesJSON string
 	var createdAt, updatedAt string
-	var deletedAt, lastValidatedAt sql.NullString
+	var deletedAt, lastValidatedAt, lang sql.NullString
 
 	err := scanner.Scan(
 		&entry.ID,
@@ -445,10 +735,15 @@ func scanLoreEntry(scanner interface{ Scan(...any) error }) (*types.LoreEntry, e
 		&updatedAt,
 		&deletedAt,
 		&lastValidatedAt,
+		&lang,
+		&entry.Repo,
+		&entry.Path,
+		&entry.HLC,
 	)
 	if err != nil {
 		return nil, err
 	}
+	entry.Language = lang.String
 
 	// Unpack embedding if present
 	if len(embeddingBlob) > 0 {
@@ -483,33 +778,107 @@ func scanLoreEntry(scanner interface{ Scan(...any) error }) (*types.LoreEntry, e
 	return &entry, nil
 }
 
+// normalizeEntries returns entries with the store's content normalization
+// applied. Content that would normalize to nothing is kept as submitted.
+// The caller's slice is never modified.
+func (s *SQLiteStore) normalizeEntries(entries []types.NewLoreEntry) []types.NewLoreEntry {
+	if !s.normalize.Enabled() {
+		return entries
+	}
+	normalized := make([]types.NewLoreEntry, len(entries))
+	for i, entry := range entries {
+		if content := s.normalize.Apply(entry.Content); content != "" {
+			entry.Content = content
+		}
+		normalized[i] = entry
+	}
+	return normalized
+}
+
+// filterCategories returns the entries whose category the store accepts
+// and their request indices, recording the others in result as rejected.
+func (s *SQLiteStore) filterCategories(entries []types.NewLoreEntry, indices []int, result *types.IngestResult) ([]types.NewLoreEntry, []int) {
+	if len(s.categories) == 0 {
+		return entries, indices
+	}
+	kept := make([]types.NewLoreEntry, 0, len(entries))
+	keptIndices := make([]int, 0, len(entries))
+	for i, entry := range entries {
+		if !slices.Contains(s.categories, entry.Category) {
+			rejectEntry(result, indices[i], fmt.Sprintf("category %s is not enabled for this store", entry.Category))
+			continue
+		}
+		kept = append(kept, entry)
+		keptIndices = append(keptIndices, indices[i])
+	}
+	return kept, keptIndices
+}
+
+// rejectEntry records the entry at request index as rejected for reason.
+func rejectEntry(result *types.IngestResult, index int, reason string) {
+	result.Rejected++
+	result.Errors = append(result.Errors, fmt.Sprintf("lore[%d]: %s", index, reason))
+	result.Results[index].Status = types.IngestRejected
+	result.Results[index].Errors = append(result.Results[index].Errors, reason)
+}
+
 // IngestLore stores new lore entries with optional embedding generation and deduplication.
 // If an embedder is configured, embeddings are generated synchronously.
 // If deduplication is enabled and embeddings are available, similar entries are merged.
 func (s *SQLiteStore) IngestLore(ctx context.Context, entries []types.NewLoreEntry) (*types.IngestResult, error) {
 	if len(entries) == 0 {
-		return &types.IngestResult{Accepted: 0, Merged: 0, Rejected: 0, Errors: []string{}}, nil
+		return &types.IngestResult{Accepted: 0, Merged: 0, Rejected: 0, Errors: []string{}, Results: []types.IngestEntryResult{}}, nil
 	}
 
 	start := time.Now()
-	result := &types.IngestResult{Errors: []string{}}
+	result := &types.IngestResult{Errors: []string{}, Results: make([]types.IngestEntryResult, len(entries))}
+	// indices[i] is the request index of entries[i] as entries are dropped
+	indices := make([]int, len(entries))
+	for i := range entries {
+		indices[i] = i
+		result.Results[i].Index = i
+	}
+
+	// 0. Drop entries in categories the store does not accept, then run
+	// plugin pre-processing; both are counted as rejected
+	entries, indices = s.filterCategories(entries, indices, result)
+	entries, indices = s.runBeforeIngest(ctx, entries, indices, result)
+	if len(entries) == 0 {
+		return result, nil
+	}
+	entries = s.normalizeEntries(entries)
 
 	// 1. Generate embeddings if embedder is available
 	var embeddings [][]float32
 	var embeddingErr error
+	// chunkSpans[i] locates entry i's chunk embeddings, which follow the
+	// whole-entry embeddings in the same batch.
+	chunkSpans := make([][2]int, len(entries))
 	if s.embedder != nil {
 		contents := make([]string, len(entries))
 		for i, e := range entries {
 			contents[i] = e.Content
 		}
+		for i, e := range entries {
+			start := len(contents)
+			contents = append(contents, s.chunkTexts(e.Content)...)
+			chunkSpans[i] = [2]int{start, len(contents)}
+		}
 		embeddings, embeddingErr = s.embedder.EmbedBatch(ctx, contents)
 		if embeddingErr != nil {
+			// On a partial failure embeddings still holds the successes;
+			// only entries without one are stored pending.
 			slog.Warn("embedding generation failed, entries will be stored pending",
 				"component", "store",
 				"store_id", s.storeID,
 				"error", embeddingErr,
 				"count", len(entries))
 		}
+		// Vectors of another dimension than the store's, as after a model
+		// switch, would be compared as nonsense; their entries wait pending
+		if _, err := s.acceptEmbeddings(ctx, embeddings, len(entries)); err != nil {
+			return nil, err
+		}
 	}
 
 	// 2. Determine deduplication settings
@@ -518,6 +887,10 @@ func (s *SQLiteStore) IngestLore(ctx context.Context, entries []types.NewLoreEnt
 	if s.cfg != nil {
 		threshold = s.cfg.GetSimilarityThreshold()
 	}
+	if s.threshold > 0 {
+		threshold = s.threshold
+	}
+	scope := s.dedupScope(time.Now())
 
 	// 3. Begin transaction
 	tx, err := s.db.BeginTx(ctx, nil)
@@ -527,22 +900,49 @@ func (s *SQLiteStore) IngestLore(ctx context.Context, entries []types.NewLoreEnt
 	defer tx.Rollback()
 
 	// 4. Process each entry
-	now := time.Now().UTC().Format(time.RFC3339)
+	now := formatTime(time.Now())
+	var merges []mergeEvent
 
 	for i, entry := range entries {
 		var embedding []float32
-		hasEmbedding := embeddingErr == nil && embeddings != nil && i < len(embeddings) && len(embeddings[i]) > 0
+		hasEmbedding := i < len(embeddings) && len(embeddings[i]) > 0
 		if hasEmbedding {
 			embedding = embeddings[i]
 		}
 
+		// An entry with a client-supplied ID keeps it: it updates the entry
+		// its source already stored under the ID rather than merging.
+		if entry.ID != "" {
+			existing, reason, err := s.clientIDTargetInTx(ctx, tx, entry)
+			if err != nil {
+				return nil, err
+			}
+			if reason != "" {
+				rejectEntry(result, indices[i], reason)
+				continue
+			}
+			if existing != nil {
+				if _, err := s.replaceLoreInTx(ctx, tx, existing, entry.Content, entry.Context, entry.Category, VersionReasonEdit, entry.SourceID); err != nil {
+					return nil, fmt.Errorf("update lore: %w", err)
+				}
+				result.Updated++
+				result.Results[indices[i]] = types.IngestEntryResult{Index: indices[i], Status: types.IngestUpdated, LoreID: existing.ID}
+				continue
+			}
+		}
+
 		// 5. Deduplication check (if enabled and embedding available)
-		if dedupEnabled && hasEmbedding {
-			similar, err := s.findSimilarInTx(ctx, tx, embedding, entry.Category, threshold)
+		if dedupEnabled && hasEmbedding && entry.ID == "" {
+			similar, err := s.findSimilarInTx(ctx, tx, embedding, entry.Category, threshold, scope)
 			if err != nil {
 				return nil, fmt.Errorf("find similar: %w", err)
 			}
 
+			// Entries only merge within one scope, so lore for one
+			// repository never absorbs another's.
+			similar = slices.DeleteFunc(similar, func(e types.SimilarEntry) bool {
+				return e.Repo != entry.Repo || e.Path != entry.Path
+			})
 			if len(similar) > 0 {
 				// Merge with best match (highest similarity)
 				bestMatch := similar[0]
@@ -559,7 +959,15 @@ func (s *SQLiteStore) IngestLore(ctx context.Context, entries []types.NewLoreEnt
 					return nil, fmt.Errorf("write change log: %w", err)
 				}
 
+				merges = append(merges, mergeEvent{merged: mergedEntry, incoming: entry})
 				result.Merged++
+				result.Results[indices[i]] = types.IngestEntryResult{
+					Index:        indices[i],
+					Status:       types.IngestMerged,
+					LoreID:       bestMatch.ID,
+					MergedIntoID: bestMatch.ID,
+					Similarity:   bestMatch.Similarity,
+				}
 				continue
 			}
 		}
@@ -569,6 +977,11 @@ func (s *SQLiteStore) IngestLore(ctx context.Context, entries []types.NewLoreEnt
 		if err != nil {
 			return nil, fmt.Errorf("insert entry: %w", err)
 		}
+		if span := chunkSpans[i]; hasEmbedding && span[1] > span[0] && span[1] <= len(embeddings) {
+			if err := replaceChunksInTx(ctx, tx, id, embeddings[span[0]:span[1]], s.quantization); err != nil {
+				return nil, err
+			}
+		}
 
 		// Write change_log entry for new entry
 		newEntry, err := s.getLoreInTx(ctx, tx, id)
@@ -580,6 +993,7 @@ func (s *SQLiteStore) IngestLore(ctx context.Context, entries []types.NewLoreEnt
 		}
 
 		result.Accepted++
+		result.Results[indices[i]] = types.IngestEntryResult{Index: indices[i], Status: types.IngestAccepted, LoreID: id}
 	}
 
 	// 7. Commit transaction
@@ -587,7 +1001,10 @@ func (s *SQLiteStore) IngestLore(ctx context.Context, entries []types.NewLoreEnt
 		return nil, fmt.Errorf("commit transaction: %w", err)
 	}
 
-	// 8. Performance logging
+	// 8. Notify plugin of merges (post-commit, cannot affect the result)
+	s.runAfterMerge(ctx, merges)
+
+	// 9. Performance logging
 	duration := time.Since(start)
 	if duration > 5*time.Second {
 		slog.Warn("ingest batch exceeded performance target",
@@ -607,8 +1024,8 @@ func (s *SQLiteStore) IngestLore(ctx context.Context, entries []types.NewLoreEnt
 func (s *SQLiteStore) GetLore(ctx context.Context, id string) (*types.LoreEntry, error) {
 	row := s.db.QueryRowContext(ctx, `
 		SELECT id, content, context, category, confidence, embedding, embedding_status,
-		       source_id, sources, validation_count, created_at, updated_at, deleted_at, last_validated_at
-		FROM lore_entries
+		       source_id, sources, validation_count, created_at, updated_at, deleted_at, last_validated_at, language, repo, path, hlc
+		FROM lore_entries LEFT JOIN lore_embeddings ON lore_embeddings.lore_id = lore_entries.id
 		WHERE id = ? AND deleted_at IS NULL
 	`, id)
 
@@ -627,7 +1044,20 @@ func (s *SQLiteStore) GetLore(ctx context.Context, id string) (*types.LoreEntry,
 // Writes a delete entry to change_log for sync protocol support.
 // Returns ErrNotFound if the entry doesn't exist or is already deleted.
 func (s *SQLiteStore) DeleteLore(ctx context.Context, id, sourceID string) error {
-	now := time.Now().UTC().Format(time.RFC3339)
+	return s.DeleteLoreIfVersion(ctx, id, sourceID, 0)
+}
+
+// DeleteLoreIfVersion soft-deletes a lore entry like DeleteLore, but only if
+// its current version is ifVersion; zero deletes any version. Returns
+// ErrVersionMismatch if the entry has changed since that version.
+func (s *SQLiteStore) DeleteLoreIfVersion(ctx context.Context, id, sourceID string, ifVersion int) error {
+	if h, ok := s.hooks.(plugin.BeforeDeleteHook); ok {
+		if err := h.BeforeDelete(ctx, id); err != nil {
+			return fmt.Errorf("before delete hook: %w", err)
+		}
+	}
+
+	now := formatTime(time.Now())
 
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -635,6 +1065,25 @@ func (s *SQLiteStore) DeleteLore(ctx context.Context, id, sourceID string) error
 	}
 	defer tx.Rollback()
 
+	if ifVersion != 0 {
+		if err := checkLoreVersion(ctx, tx, id, ifVersion); err != nil {
+			return err
+		}
+	}
+	if err := s.softDeleteLoreInTx(ctx, tx, id, sourceID, now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// softDeleteLoreInTx sets deleted_at and records the delete in change_log.
+// Returns ErrNotFound if the entry doesn't exist or is already deleted.
+func (s *SQLiteStore) softDeleteLoreInTx(ctx context.Context, tx *sql.Tx, id, sourceID, now string) error {
 	result, err := tx.ExecContext(ctx, `
 		UPDATE lore_entries
 		SET deleted_at = ?, updated_at = ?
@@ -661,18 +1110,27 @@ func (s *SQLiteStore) DeleteLore(ctx context.Context, id, sourceID string) error
 		return fmt.Errorf("write change log: %w", err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
-	}
-
 	return nil
 }
 
+// CountPendingEmbeddings returns how many active entries are waiting for an
+// embedding.
+func (s *SQLiteStore) CountPendingEmbeddings(ctx context.Context) (int64, error) {
+	var n int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM lore_entries WHERE embedding_status = 'pending' AND deleted_at IS NULL
+	`).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count pending embeddings: %w", err)
+	}
+	return n, nil
+}
+
 // GetPendingEmbeddings retrieves entries that need embedding generation.
 func (s *SQLiteStore) GetPendingEmbeddings(ctx context.Context, limit int) ([]types.LoreEntry, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, content, context, category, confidence, embedding, embedding_status,
-		       source_id, sources, validation_count, created_at, updated_at, deleted_at, last_validated_at
+		SELECT id, content, context, category, confidence, NULL, embedding_status,
+		       source_id, sources, validation_count, created_at, updated_at, deleted_at, last_validated_at, language, repo, path, hlc
 		FROM lore_entries
 		WHERE embedding_status = 'pending' AND deleted_at IS NULL
 		ORDER BY created_at ASC
@@ -699,16 +1157,76 @@ func (s *SQLiteStore) GetPendingEmbeddings(ctx context.Context, limit int) ([]ty
 	return entries, nil
 }
 
+// CountEmbeddingBacklog returns the number of live entries whose embedding
+// is pending or has permanently failed.
+func (s *SQLiteStore) CountEmbeddingBacklog(ctx context.Context) (int64, error) {
+	var n int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM lore_entries
+		WHERE embedding_status IN ('pending', 'failed') AND deleted_at IS NULL
+	`).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count embedding backlog: %w", err)
+	}
+	return n, nil
+}
+
+// GetEmbeddingBacklog returns up to limit live entries with a pending or
+// failed embedding whose ID sorts after afterID, in ID order. Paging by ID
+// lets a backfill step past entries that fail again instead of refetching them.
+func (s *SQLiteStore) GetEmbeddingBacklog(ctx context.Context, afterID string, limit int) ([]types.LoreEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, content, context, category, confidence, NULL, embedding_status,
+		       source_id, sources, validation_count, created_at, updated_at, deleted_at, last_validated_at, language, repo, path, hlc
+		FROM lore_entries
+		WHERE embedding_status IN ('pending', 'failed') AND deleted_at IS NULL AND id > ?
+		ORDER BY id ASC
+		LIMIT ?
+	`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query embedding backlog: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []types.LoreEntry
+	for rows.Next() {
+		entry, err := scanLoreEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		entries = append(entries, *entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return entries, nil
+}
+
 // UpdateEmbedding stores the embedding for a lore entry and marks it complete.
+// An embedding of another dimension than the store's returns
+// ErrDimensionMismatch and leaves the entry pending.
 func (s *SQLiteStore) UpdateEmbedding(ctx context.Context, id string, embedding []float32) error {
-	embeddingBlob := packEmbedding(embedding)
-	now := time.Now().UTC().Format(time.RFC3339)
+	if len(embedding) > 0 {
+		if err := s.acceptEmbedding(ctx, embedding); err != nil {
+			return err
+		}
+	}
+	now := formatTime(time.Now())
+	stored := s.quantization.round(embedding)
 
-	result, err := s.db.ExecContext(ctx, `
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
 		UPDATE lore_entries
-		SET embedding = ?, embedding_status = 'complete', updated_at = ?
+		SET embedding_norm = ?, embedding_bucket = ?, embedding_status = 'complete', updated_at = ?
 		WHERE id = ? AND deleted_at IS NULL
-	`, embeddingBlob, now, id)
+	`, embeddingNorm(stored), embeddingBucket(stored), now, id)
 	if err != nil {
 		return fmt.Errorf("update embedding: %w", err)
 	}
@@ -722,12 +1240,19 @@ func (s *SQLiteStore) UpdateEmbedding(ctx context.Context, id string, embedding
 		return ErrNotFound
 	}
 
+	if err := storeEmbeddingInTx(ctx, tx, id, embedding, s.quantization); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
 	return nil
 }
 
 // MarkEmbeddingFailed marks an entry's embedding as permanently failed.
 func (s *SQLiteStore) MarkEmbeddingFailed(ctx context.Context, id string) error {
-	now := time.Now().UTC().Format(time.RFC3339)
+	now := formatTime(time.Now())
 
 	result, err := s.db.ExecContext(ctx, `
 		UPDATE lore_entries
@@ -753,8 +1278,62 @@ func (s *SQLiteStore) MarkEmbeddingFailed(ctx context.Context, id string) error
 // FindSimilar finds lore entries similar to the given embedding within the same category.
 // Returns entries with cosine similarity >= threshold, ordered by similarity descending.
 func (s *SQLiteStore) FindSimilar(ctx context.Context, embedding []float32, category string, threshold float64) ([]types.SimilarEntry, error) {
+	if err := s.checkDimensions(embedding); err != nil {
+		return nil, err
+	}
 	// Delegate to findSimilarInTx; *sql.DB satisfies queryContext interface
-	return s.findSimilarInTx(ctx, s.db, embedding, category, threshold)
+	return s.findSimilarInTx(ctx, s.db, embedding, category, threshold, similarityScope{})
+}
+
+// similarityScope limits the entries a similarity scan considers beyond
+// being live and in the category. The zero value adds no limit.
+type similarityScope struct {
+	createdSince  time.Time
+	minConfidence float64
+}
+
+// dedupScope returns the configured scope of duplicate candidates at now.
+func (s *SQLiteStore) dedupScope(now time.Time) similarityScope {
+	if s.cfg == nil {
+		return similarityScope{}
+	}
+	var scope similarityScope
+	maxAge, minConfidence := s.cfg.GetDeduplicationScope()
+	if maxAge > 0 {
+		scope.createdSince = now.Add(-maxAge)
+	}
+	if minConfidence > 0 {
+		scope.minConfidence = minConfidence
+	}
+	return scope
+}
+
+// where returns the scope's conditions on the lore_entries columns
+// prefixed with prefix, for appending to a WHERE clause, and their args.
+func (sc similarityScope) where(prefix string) (string, []any) {
+	var clause string
+	var args []any
+	if !sc.createdSince.IsZero() {
+		clause += ` AND ` + prefix + `created_at >= ?`
+		args = append(args, formatTime(sc.createdSince))
+	}
+	if sc.minConfidence > 0 {
+		clause += ` AND ` + prefix + `confidence >= ?`
+		args = append(args, sc.minConfidence)
+	}
+	return clause, args
+}
+
+// probeRadius returns the bucket probe radius for similarity scans, or -1
+// when they are exhaustive.
+func (s *SQLiteStore) probeRadius() int {
+	if s.cfg == nil {
+		return -1
+	}
+	if radius, approximate := s.cfg.GetDeduplicationProbeRadius(); approximate {
+		return max(radius, 0)
+	}
+	return -1
 }
 
 // --- Transaction-aware helper methods for deduplication ---
@@ -770,46 +1349,88 @@ type queryContext interface {
 }
 
 // findSimilarInTx finds similar entries within a transaction.
-func (s *SQLiteStore) findSimilarInTx(ctx context.Context, qc queryContext, embedding []float32, category string, threshold float64) ([]types.SimilarEntry, error) {
-	rows, err := qc.QueryContext(ctx, `
-		SELECT id, content, context, category, confidence, embedding, embedding_status,
-		       source_id, sources, validation_count, created_at, updated_at, deleted_at, last_validated_at
-		FROM lore_entries
-		WHERE category = ? AND embedding IS NOT NULL AND deleted_at IS NULL
-	`, category)
+// Candidates are scored from their id, embedding and stored norm alone; full
+// rows are loaded only for the few that pass the threshold. When
+// deduplication is approximate, only entries in projection buckets near the
+// query's are candidates, and only entries within scope are considered.
+func (s *SQLiteStore) findSimilarInTx(ctx context.Context, qc queryContext, embedding []float32, category string, threshold float64, scope similarityScope) ([]types.SimilarEntry, error) {
+	query := `
+		SELECT id, embedding, embedding_norm
+		FROM lore_entries JOIN lore_embeddings ON lore_embeddings.lore_id = lore_entries.id
+		WHERE category = ? AND deleted_at IS NULL`
+	args := []any{category}
+	scopeClause, scopeArgs := scope.where("")
+	query += scopeClause
+	args = append(args, scopeArgs...)
+	if radius := s.probeRadius(); radius >= 0 && len(embedding) > 0 {
+		probes := probeBuckets(projectionBucket(embedding), radius)
+		query += ` AND embedding_bucket IN (?` + strings.Repeat(`, ?`, len(probes)-1) + `)`
+		for _, b := range probes {
+			args = append(args, b)
+		}
+	}
+	rows, err := qc.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query similar entries: %w", err)
 	}
 	defer rows.Close()
 
-	var results []types.SimilarEntry
+	q := newQueryVector(embedding)
+	similarities := make(map[string]float64)
+	var (
+		id, blob      sql.RawBytes
+		candidateNorm sql.NullFloat64
+		candidate     []float32
+	)
 	for rows.Next() {
-		entry, err := scanLoreEntry(rows)
-		if err != nil {
+		if err := rows.Scan(&id, &blob, &candidateNorm); err != nil {
 			return nil, fmt.Errorf("scan row: %w", err)
 		}
-
-		similarity := cosineSimilarity(embedding, entry.Embedding)
-		if similarity >= threshold {
-			results = append(results, types.SimilarEntry{
-				LoreEntry:  *entry,
-				Similarity: similarity,
-			})
+		candidate = unpackEmbeddingInto(candidate, blob)
+		n := float32(candidateNorm.Float64)
+		if !candidateNorm.Valid {
+			n = norm(candidate)
+		}
+		if similarity := q.similarity(candidate, n); similarity >= threshold {
+			similarities[string(id)] = similarity
 		}
 	}
-
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate rows: %w", err)
 	}
+	rows.Close()
+
+	// A long entry matches at its best chunk when that beats its whole-entry
+	// embedding.
+	chunkBest, err := chunkSimilarities(ctx, qc, q, category, scope)
+	if err != nil {
+		return nil, err
+	}
+	for id, similarity := range chunkBest {
+		if similarity >= threshold && similarity > similarities[id] {
+			similarities[id] = similarity
+		}
+	}
+
+	results := make([]types.SimilarEntry, 0, len(similarities))
+	for id, similarity := range similarities {
+		entry, err := s.getLoreInTx(ctx, qc, id)
+		if errors.Is(err, ErrNotFound) {
+			continue // deleted since the scan
+		}
+		if err != nil {
+			return nil, fmt.Errorf("load similar entry %s: %w", id, err)
+		}
+		results = append(results, types.SimilarEntry{
+			LoreEntry:  *entry,
+			Similarity: similarity,
+		})
+	}
 
 	sort.Slice(results, func(i, j int) bool {
 		return results[i].Similarity > results[j].Similarity
 	})
 
-	if results == nil {
-		results = []types.SimilarEntry{}
-	}
-
 	return results, nil
 }
 
@@ -817,8 +1438,8 @@ func (s *SQLiteStore) findSimilarInTx(ctx context.Context, qc queryContext, embe
 func (s *SQLiteStore) getLoreInTx(ctx context.Context, qc queryContext, id string) (*types.LoreEntry, error) {
 	row := qc.QueryRowContext(ctx, `
 		SELECT id, content, context, category, confidence, embedding, embedding_status,
-		       source_id, sources, validation_count, created_at, updated_at, deleted_at, last_validated_at
-		FROM lore_entries
+		       source_id, sources, validation_count, created_at, updated_at, deleted_at, last_validated_at, language, repo, path, hlc
+		FROM lore_entries LEFT JOIN lore_embeddings ON lore_embeddings.lore_id = lore_entries.id
 		WHERE id = ? AND deleted_at IS NULL
 	`, id)
 
@@ -840,7 +1461,11 @@ func (s *SQLiteStore) mergeLoreInTx(ctx context.Context, qc queryContext, target
 		return err
 	}
 
-	newConfidence := math.Min(target.Confidence+ConfidenceBoost, MaxConfidence)
+	bounds, err := s.loadConfidenceBounds(ctx, qc, targetID)
+	if err != nil {
+		return err
+	}
+	newConfidence := bounds.clamp(target.Confidence, math.Min(target.Confidence+ConfidenceBoost, MaxConfidence))
 	newContext := appendContext(target.Context, source.Context)
 	newSources, _ := addSourceID(target.Sources, source.SourceID)
 	sourcesJSON, err := json.Marshal(newSources)
@@ -848,7 +1473,10 @@ func (s *SQLiteStore) mergeLoreInTx(ctx context.Context, qc queryContext, target
 		return fmt.Errorf("marshal sources: %w", err)
 	}
 
-	now := time.Now().UTC().Format(time.RFC3339)
+	now := formatTime(time.Now())
+	if err := saveLoreVersion(ctx, qc, targetID, target.Content, newContext, target.Category, VersionReasonMerge, source.SourceID, now); err != nil {
+		return err
+	}
 	_, err = qc.ExecContext(ctx, `
 		UPDATE lore_entries
 		SET confidence = ?, context = ?, sources = ?, updated_at = ?
@@ -864,48 +1492,94 @@ func (s *SQLiteStore) mergeLoreInTx(ctx context.Context, qc queryContext, target
 // insertEntryInTx inserts a new entry within a transaction.
 // Returns the generated entry ID.
 func (s *SQLiteStore) insertEntryInTx(ctx context.Context, qc queryContext, entry types.NewLoreEntry, embedding []float32, hasEmbedding bool) (string, error) {
-	id := ulid.Make().String()
+	id := entry.ID
+	if id == "" {
+		id = ulid.Make().String()
+	}
 	sources := []string{entry.SourceID}
 	sourcesBytes, err := json.Marshal(sources)
 	if err != nil {
 		return "", fmt.Errorf("marshal sources: %w", err)
 	}
 
-	now := time.Now().UTC().Format(time.RFC3339)
+	now := formatTime(time.Now())
 
 	embeddingStatus := "pending"
-	var embeddingBlob []byte
+	var normValue, bucketValue any
 	if hasEmbedding {
 		embeddingStatus = "complete"
-		embeddingBlob = packEmbedding(embedding)
+		stored := s.quantization.round(embedding)
+		normValue = embeddingNorm(stored)
+		bucketValue = embeddingBucket(stored)
+	}
+
+	lang := entry.Language
+	if lang == "" {
+		lang = language.Detect(entry.Content)
 	}
 
 	_, err = qc.ExecContext(ctx, `
 		INSERT INTO lore_entries (
 			id, content, context, category, confidence,
-			embedding, embedding_status, source_id, sources,
-			validation_count, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?)
+			embedding_norm, embedding_bucket, embedding_status, source_id, sources,
+			validation_count, created_at, updated_at, language, repo, path
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?, ?, ?, ?)
 	`,
 		id,
 		entry.Content,
 		entry.Context,
 		entry.Category,
 		entry.Confidence,
-		embeddingBlob,
+		normValue,
+		bucketValue,
 		embeddingStatus,
 		entry.SourceID,
 		string(sourcesBytes),
 		now,
 		now,
+		lang,
+		entry.Repo,
+		entry.Path,
 	)
 	if err != nil {
 		return "", fmt.Errorf("insert entry: %w", err)
 	}
+	if hasEmbedding {
+		if err := storeEmbeddingInTx(ctx, qc, id, embedding, s.quantization); err != nil {
+			return "", err
+		}
+	}
 
 	return id, nil
 }
 
+// clientIDTargetInTx looks up the entry stored under entry's client-supplied
+// ID. It returns the live entry when entry's source stored it, nil when the
+// ID is unused, or a reason the ID cannot be used: it belongs to another
+// source or to a deleted entry.
+func (s *SQLiteStore) clientIDTargetInTx(ctx context.Context, qc queryContext, entry types.NewLoreEntry) (*types.LoreEntry, string, error) {
+	var sourceID string
+	var deletedAt sql.NullString
+	err := qc.QueryRowContext(ctx, `SELECT source_id, deleted_at FROM lore_entries WHERE id = ?`, entry.ID).Scan(&sourceID, &deletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("look up client id: %w", err)
+	}
+	if deletedAt.Valid {
+		return nil, fmt.Sprintf("id %s belongs to a deleted entry", entry.ID), nil
+	}
+	if sourceID != entry.SourceID {
+		return nil, fmt.Sprintf("id %s is already in use", entry.ID), nil
+	}
+	existing, err := s.getLoreInTx(ctx, qc, entry.ID)
+	if err != nil {
+		return nil, "", fmt.Errorf("get existing entry: %w", err)
+	}
+	return existing, "", nil
+}
+
 // writeChangeLogInTx appends an entry to the change_log within a transaction.
 func (s *SQLiteStore) writeChangeLogInTx(ctx context.Context, tx *sql.Tx, tableName, entityID, operation string, payload any, sourceID, createdAt string) error {
 	var payloadJSON []byte
@@ -923,15 +1597,16 @@ func (s *SQLiteStore) writeChangeLogInTx(ctx context.Context, tx *sql.Tx, tableN
 		payloadVal = string(payloadJSON)
 	}
 
+	ts := s.clock.Now()
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO change_log (table_name, entity_id, operation, payload, source_id, created_at)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`, tableName, entityID, operation, payloadVal, sourceID, createdAt)
+		INSERT INTO change_log (table_name, entity_id, operation, payload, source_id, created_at, hlc, received_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, tableName, entityID, operation, payloadVal, sourceID, createdAt, ts, formatTime(time.Now()))
 	if err != nil {
 		return fmt.Errorf("insert change_log: %w", err)
 	}
 
-	return nil
+	return stampLoreHLC(ctx, tx, tableName, entityID, ts)
 }
 
 // --- Stub implementations for remaining Store interface methods ---
@@ -946,8 +1621,12 @@ func (s *SQLiteStore) MergeLore(ctx context.Context, targetID string, source typ
 		return err // Propagates ErrNotFound
 	}
 
-	// 2. Calculate new confidence (cap at 1.0)
-	newConfidence := math.Min(target.Confidence+ConfidenceBoost, MaxConfidence)
+	// 2. Calculate new confidence (cap at 1.0, and at a locked ceiling)
+	bounds, err := s.loadConfidenceBounds(ctx, s.db, targetID)
+	if err != nil {
+		return err
+	}
+	newConfidence := bounds.clamp(target.Confidence, math.Min(target.Confidence+ConfidenceBoost, MaxConfidence))
 
 	// 3. Append context (with truncation if needed)
 	newContext := appendContext(target.Context, source.Context)
@@ -959,8 +1638,11 @@ func (s *SQLiteStore) MergeLore(ctx context.Context, targetID string, source typ
 		return fmt.Errorf("marshal sources: %w", err)
 	}
 
-	// 5. Execute UPDATE
-	now := time.Now().UTC().Format(time.RFC3339)
+	// 5. Save the replaced context as a version, then execute UPDATE
+	now := formatTime(time.Now())
+	if err := saveLoreVersion(ctx, s.db, targetID, target.Content, newContext, target.Category, VersionReasonMerge, source.SourceID, now); err != nil {
+		return err
+	}
 	_, err = s.db.ExecContext(ctx, `
 		UPDATE lore_entries
 		SET confidence = ?, context = ?, sources = ?, updated_at = ?
@@ -998,58 +1680,84 @@ func (s *SQLiteStore) GetSnapshot(ctx context.Context) (io.ReadCloser, error) {
 // Returns entries updated after `since` (created or modified) and IDs of entries
 // deleted after `since`. The AsOf field contains the server time of the query.
 // Returns empty arrays (not nil) if no changes exist.
+//
+// Changes are read from change_log by received_at, so the result agrees
+// with the sequence-based sync delta and includes entries that were
+// removed outright. Row updates that are not logged, such as completed
+// embeddings and confidence changes, are found by updated_at. Both
+// bounds are inclusive, so a change in the same instant as the previous
+// as_of is returned again rather than missed.
 func (s *SQLiteStore) GetDelta(ctx context.Context, since time.Time) (*types.DeltaResult, error) {
 	asOf := time.Now().UTC()
-	sinceStr := since.UTC().Format(time.RFC3339)
+	sinceAt := formatTime(since)
 
-	// Query 1: Updated/created entries (not deleted)
-	rows, err := s.db.QueryContext(ctx, `
+	// One read transaction keeps both queries on the same snapshot
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Query 1: Entries changed in the window, live or soft-deleted. Each
+	// branch of the union searches its own index, where an OR of the three
+	// conditions scanned the table.
+	rows, err := tx.QueryContext(ctx, `
 		SELECT id, content, context, category, confidence, embedding, embedding_status,
-		       source_id, sources, validation_count, created_at, updated_at, deleted_at, last_validated_at
-		FROM lore_entries
-		WHERE updated_at > ?
-		  AND deleted_at IS NULL
-		ORDER BY updated_at ASC
-	`, sinceStr)
+		       source_id, sources, validation_count, created_at, updated_at, deleted_at, last_validated_at, language, repo, path, hlc
+		FROM lore_entries LEFT JOIN lore_embeddings ON lore_embeddings.lore_id = lore_entries.id
+		WHERE id IN (
+		        SELECT entity_id FROM change_log
+		        WHERE table_name = 'lore_entries' AND received_at >= ?
+		        UNION SELECT id FROM lore_entries WHERE updated_at >= ?
+		        UNION SELECT id FROM lore_entries WHERE deleted_at >= ?
+		      )
+		ORDER BY updated_at ASC, id ASC
+	`, sinceAt, sinceAt, sinceAt)
 	if err != nil {
 		return nil, fmt.Errorf("query updated entries: %w", err)
 	}
 	defer rows.Close()
 
 	var lore []types.LoreEntry
+	var deletedIDs []string
 	for rows.Next() {
 		entry, err := scanLoreEntry(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scan entry: %w", err)
 		}
+		if entry.DeletedAt != nil {
+			deletedIDs = append(deletedIDs, entry.ID)
+			continue
+		}
 		lore = append(lore, *entry)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate rows: %w", err)
 	}
 
-	// Query 2: Deleted entry IDs
-	deletedRows, err := s.db.QueryContext(ctx, `
-		SELECT id
-		FROM lore_entries
-		WHERE deleted_at IS NOT NULL
-		  AND deleted_at > ?
-		ORDER BY deleted_at ASC
-	`, sinceStr)
+	// Query 2: Entries logged in the window whose rows no longer exist
+	goneRows, err := tx.QueryContext(ctx, `
+		SELECT entity_id
+		FROM change_log c
+		WHERE c.table_name = 'lore_entries'
+		  AND c.received_at >= ?
+		  AND NOT EXISTS (SELECT 1 FROM lore_entries l WHERE l.id = c.entity_id)
+		GROUP BY entity_id
+		ORDER BY MAX(sequence) ASC
+	`, sinceAt)
 	if err != nil {
-		return nil, fmt.Errorf("query deleted entries: %w", err)
+		return nil, fmt.Errorf("query removed entries: %w", err)
 	}
-	defer deletedRows.Close()
+	defer goneRows.Close()
 
-	var deletedIDs []string
-	for deletedRows.Next() {
+	for goneRows.Next() {
 		var id string
-		if err := deletedRows.Scan(&id); err != nil {
+		if err := goneRows.Scan(&id); err != nil {
 			return nil, fmt.Errorf("scan deleted ID: %w", err)
 		}
 		deletedIDs = append(deletedIDs, id)
 	}
-	if err := deletedRows.Err(); err != nil {
+	if err := goneRows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate deleted rows: %w", err)
 	}
 
@@ -1105,6 +1813,23 @@ func (s *SQLiteStore) GenerateSnapshot(ctx context.Context) error {
 		os.Remove(tempPath)
 		return fmt.Errorf("vacuum into snapshot: %w", err)
 	}
+	if err := stripSearchIndex(ctx, tempPath); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("strip search index from snapshot: %w", err)
+	}
+	if err := inlineSnapshotEmbeddings(ctx, tempPath); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("inline snapshot embeddings: %w", err)
+	}
+	var storeType string
+	if s.hooks != nil {
+		storeType = s.hooks.Type()
+	}
+	baseSeq, err := stampSnapshot(ctx, tempPath, storeType)
+	if err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("record snapshot base sequence: %w", err)
+	}
 
 	// Get snapshot file size for logging
 	info, err := os.Stat(tempPath)
@@ -1122,7 +1847,12 @@ func (s *SQLiteStore) GenerateSnapshot(ctx context.Context) error {
 	// Update last snapshot timestamp and metadata
 	now := time.Now().UTC()
 	s.lastSnapshot = &now
-	s.SetSnapshotMeta(loreCount, sizeBytes, now)
+	s.snapshotMeta.Store(&snapshotMeta{
+		loreCount:    loreCount,
+		sizeBytes:    sizeBytes,
+		generatedAt:  now,
+		baseSequence: baseSeq,
+	})
 
 	duration := time.Since(start)
 	slog.Info("snapshot generated",
@@ -1132,11 +1862,76 @@ func (s *SQLiteStore) GenerateSnapshot(ctx context.Context) error {
 		"duration_ms", duration.Milliseconds(),
 		"size_bytes", sizeBytes,
 		"lore_count", loreCount,
+		"base_sequence", baseSeq,
 	)
 
 	return nil
 }
 
+// stampSnapshot records the latest change_log sequence and the store type
+// in the snapshot at path, in the snapshot's own sync_meta, and returns the
+// sequence. A client reading the file knows where to resume deltas, and a
+// restore knows what kind of store to rebuild. Reading the sequence from the
+// snapshot rather than the live database excludes writes made while the
+// snapshot was being taken.
+func stampSnapshot(ctx context.Context, path, storeType string) (int64, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	var seq int64
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(sequence), 0) FROM change_log`).Scan(&seq); err != nil {
+		return 0, err
+	}
+	_, err = db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO sync_meta (key, value) VALUES (?, ?)
+	`, engramsync.SyncMetaSnapshotBaseSeq, fmt.Sprintf("%d", seq))
+	if err != nil || storeType == "" {
+		return seq, err
+	}
+	_, err = db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO sync_meta (key, value) VALUES (?, ?)
+	`, engramsync.SyncMetaSnapshotStoreType, storeType)
+	return seq, err
+}
+
+// PrepareRestoredSnapshot readies a snapshot file, such as one downloaded
+// from snapshot storage, to be opened as a store database: it rebuilds the
+// full-text index removed when the snapshot was taken, moves embeddings back
+// out of lore_entries and clears the sync_meta recorded only in snapshots. It returns the type of the store
+// the snapshot was taken from, or "" if the snapshot doesn't record one.
+func PrepareRestoredSnapshot(ctx context.Context, path string) (string, error) {
+	if err := restoreSearchIndex(ctx, path); err != nil {
+		return "", fmt.Errorf("rebuild search index: %w", err)
+	}
+	if err := restoreEmbeddingsTable(ctx, path); err != nil {
+		return "", fmt.Errorf("restore embeddings table: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+
+	var storeType string
+	err = db.QueryRowContext(ctx, `
+		SELECT value FROM sync_meta WHERE key = ?
+	`, engramsync.SyncMetaSnapshotStoreType).Scan(&storeType)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("read snapshot store type: %w", err)
+	}
+	_, err = db.ExecContext(ctx, `
+		DELETE FROM sync_meta WHERE key IN (?, ?)
+	`, engramsync.SyncMetaSnapshotBaseSeq, engramsync.SyncMetaSnapshotStoreType)
+	if err != nil {
+		return "", fmt.Errorf("clear snapshot sync meta: %w", err)
+	}
+	return storeType, nil
+}
+
 // GetSnapshotPath returns the filesystem path to the current snapshot.
 // Returns ErrSnapshotNotAvailable if no snapshot has been generated.
 func (s *SQLiteStore) GetSnapshotPath(ctx context.Context) (string, error) {
@@ -1150,7 +1945,22 @@ func (s *SQLiteStore) GetSnapshotPath(ctx context.Context) (string, error) {
 // RecordFeedback records feedback entries and adjusts confidence.
 // Uses a transaction for atomic batch processing with partial success semantics:
 // entries that exist are updated, entries that don't exist are skipped and reported.
+// Feedback over the store's FeedbackLimit is skipped and reported as rate_limited,
+// and helpful feedback from an entry's own sources is handled as SelfFeedback sets.
 func (s *SQLiteStore) RecordFeedback(ctx context.Context, feedback []types.FeedbackEntry) (*types.FeedbackResult, error) {
+	return s.recordFeedback(ctx, feedback, false)
+}
+
+// RecordFeedbackAtomic records feedback entries all-or-nothing. If any entry
+// is missing or deleted, nothing is applied and the result lists every such
+// entry in Skipped, with no Updates. Feedback over the store's FeedbackLimit,
+// or skipped as self feedback, does not fail the batch; it is skipped as by
+// RecordFeedback.
+func (s *SQLiteStore) RecordFeedbackAtomic(ctx context.Context, feedback []types.FeedbackEntry) (*types.FeedbackResult, error) {
+	return s.recordFeedback(ctx, feedback, true)
+}
+
+func (s *SQLiteStore) recordFeedback(ctx context.Context, feedback []types.FeedbackEntry, atomic bool) (*types.FeedbackResult, error) {
 	if len(feedback) == 0 {
 		return &types.FeedbackResult{Updates: []types.FeedbackResultUpdate{}}, nil
 	}
@@ -1162,21 +1972,27 @@ func (s *SQLiteStore) RecordFeedback(ctx context.Context, feedback []types.Feedb
 	defer tx.Rollback()
 
 	now := time.Now().UTC()
-	nowStr := now.Format(time.RFC3339)
+	nowStr := formatTime(now)
 	updates := make([]types.FeedbackResultUpdate, 0, len(feedback))
 	skipped := make([]types.FeedbackSkipped, 0)
+	// Feedback refused by the rate limit or as self feedback, which unlike
+	// missing entries does not fail an atomic batch
+	limited, refused := 0, 0
 
 	for _, entry := range feedback {
 		// Fetch current lore entry
-		var id string
+		var id, creator, sources string
 		var currentConfidence float64
 		var validationCount int
+		bounds := unlockedBounds
 
 		err := tx.QueryRowContext(ctx, `
-			SELECT id, confidence, validation_count
-			FROM lore_entries
-			WHERE id = ? AND deleted_at IS NULL
-		`, entry.LoreID).Scan(&id, &currentConfidence, &validationCount)
+			SELECT l.id, l.confidence, l.validation_count, l.source_id, l.sources,
+			       COALESCE(k.floor, ?), COALESCE(k.ceiling, ?)
+			FROM lore_entries l
+			LEFT JOIN confidence_locks k ON k.lore_id = l.id
+			WHERE l.id = ? AND l.deleted_at IS NULL
+		`, MinConfidence, MaxConfidence, entry.LoreID).Scan(&id, &currentConfidence, &validationCount, &creator, &sources, &bounds.floor, &bounds.ceiling)
 
 		if err != nil {
 			if err == sql.ErrNoRows {
@@ -1190,12 +2006,41 @@ func (s *SQLiteStore) RecordFeedback(ctx context.Context, feedback []types.Feedb
 			return nil, fmt.Errorf("fetch lore entry: %w", err)
 		}
 
+		// Helpful feedback from one of the entry's own sources is skipped
+		// or down-weighted, before it counts against the rate limit
+		self := s.selfFeedback.Exclude && entry.Type == "helpful" && isOwnSource(entry.SourceID, creator, sources)
+		if self && s.selfFeedback.Weight == 0 {
+			skipped = append(skipped, types.FeedbackSkipped{
+				LoreID: entry.LoreID,
+				Reason: "self_feedback",
+			})
+			refused++
+			continue
+		}
+
+		allowed, err := s.allowFeedback(ctx, tx, entry, now)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			skipped = append(skipped, types.FeedbackSkipped{
+				LoreID: entry.LoreID,
+				Reason: "rate_limited",
+			})
+			limited++
+			refused++
+			continue
+		}
+
 		// Calculate new confidence based on feedback type
 		previousConfidence := currentConfidence
 		var delta float64
 		switch entry.Type {
 		case "helpful":
 			delta = FeedbackHelpfulBoost
+			if self {
+				delta *= s.selfFeedback.Weight
+			}
 		case "incorrect":
 			delta = -FeedbackIncorrectPenalty
 		case "not_relevant":
@@ -1210,6 +2055,7 @@ func (s *SQLiteStore) RecordFeedback(ctx context.Context, feedback []types.Feedb
 		if newConfidence < MinConfidence {
 			newConfidence = MinConfidence
 		}
+		newConfidence = bounds.clamp(currentConfidence, newConfidence)
 		// Round to 6 decimal places to prevent floating point drift
 		newConfidence = math.Round(newConfidence*1e6) / 1e6
 
@@ -1221,7 +2067,7 @@ func (s *SQLiteStore) RecordFeedback(ctx context.Context, feedback []types.Feedb
 		}
 
 		// Update database based on feedback type
-		if entry.Type == "helpful" {
+		if entry.Type == "helpful" && !self {
 			newValidationCount := validationCount + 1
 			update.ValidationCount = &newValidationCount
 
@@ -1246,12 +2092,45 @@ func (s *SQLiteStore) RecordFeedback(ctx context.Context, feedback []types.Feedb
 			return nil, fmt.Errorf("update lore entry: %w", err)
 		}
 
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO feedback_events (lore_id, type, note, created_at) VALUES (?, ?, ?, ?)
+		`, entry.LoreID, entry.Type, sql.NullString{String: entry.Note, Valid: entry.Note != ""}, nowStr); err != nil {
+			return nil, fmt.Errorf("record feedback event: %w", err)
+		}
+
 		updates = append(updates, update)
 	}
 
+	if atomic && len(skipped) > refused {
+		// Rolled back by the deferred Rollback. Only missing entries are
+		// reported, as the others did not fail the batch.
+		missing := make([]types.FeedbackSkipped, 0, len(skipped)-refused)
+		for _, sk := range skipped {
+			if sk.Reason == "not_found" {
+				missing = append(missing, sk)
+			}
+		}
+		return &types.FeedbackResult{Updates: []types.FeedbackResultUpdate{}, Skipped: missing}, nil
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("commit transaction: %w", err)
 	}
+	if s.feedbackLimit.enabled() {
+		s.pruneFeedbackWindows(ctx, now)
+	}
+
+	if limited > 0 {
+		slog.Warn("feedback rate limited",
+			"component", "store",
+			"action", "feedback_rate_limited",
+			"store_id", s.storeID,
+			"source_id", feedback[0].SourceID,
+			"limited", limited,
+			"window", s.feedbackLimit.Window.String(),
+			"max", s.feedbackLimit.Max,
+		)
+	}
 
 	return &types.FeedbackResult{Updates: updates, Skipped: skipped}, nil
 }
@@ -1259,16 +2138,22 @@ func (s *SQLiteStore) RecordFeedback(ctx context.Context, feedback []types.Feedb
 // DecayConfidence reduces confidence for entries not validated since threshold.
 // Entries with last_validated_at <= threshold OR last_validated_at IS NULL are decayed.
 // Uses a single bulk UPDATE with floor enforcement via max(0.0, confidence - amount).
+// Entries with a locked floor decay no further than it, and entries already
+// at or below their floor are left untouched.
 func (s *SQLiteStore) DecayConfidence(ctx context.Context, threshold time.Time, amount float64) (int64, error) {
-	thresholdStr := threshold.UTC().Format(time.RFC3339)
-	now := time.Now().UTC().Format(time.RFC3339)
+	thresholdStr := formatTime(threshold)
+	now := formatTime(time.Now())
 
 	result, err := s.db.ExecContext(ctx, `
 		UPDATE lore_entries
-		SET confidence = max(0.0, confidence - ?),
+		SET confidence = `+decayedConfidenceSQL+`,
 		    updated_at = ?
 		WHERE deleted_at IS NULL
 		  AND (last_validated_at <= ? OR last_validated_at IS NULL)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM confidence_locks k
+		      WHERE k.lore_id = lore_entries.id AND lore_entries.confidence <= k.floor
+		  )
 	`, amount, now, thresholdStr)
 
 	if err != nil {
@@ -1277,3 +2162,40 @@ func (s *SQLiteStore) DecayConfidence(ctx context.Context, threshold time.Time,
 
 	return result.RowsAffected()
 }
+
+// decayedConfidenceSQL is an entry's confidence after decaying by one bound
+// amount: never below 0 or its locked floor, and never raised.
+const decayedConfidenceSQL = `max(
+		    confidence - ?,
+		    min(confidence, COALESCE((SELECT floor FROM confidence_locks k WHERE k.lore_id = lore_entries.id), 0.0))
+		)`
+
+// ListDecayTransitions returns the entries a DecayConfidence call with the
+// same threshold and amount would move from at-or-above floor to below it.
+// Call it before decaying to learn which entries are about to cross.
+func (s *SQLiteStore) ListDecayTransitions(ctx context.Context, threshold time.Time, amount, floor float64) ([]types.ConfidenceTransition, error) {
+	thresholdStr := formatTime(threshold)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, confidence, `+decayedConfidenceSQL+`
+		FROM lore_entries
+		WHERE deleted_at IS NULL
+		  AND (last_validated_at <= ? OR last_validated_at IS NULL)
+		  AND confidence >= ?
+		  AND `+decayedConfidenceSQL+` < ?
+	`, amount, thresholdStr, floor, amount, floor)
+	if err != nil {
+		return nil, fmt.Errorf("list decay transitions: %w", err)
+	}
+	defer rows.Close()
+
+	var transitions []types.ConfidenceTransition
+	for rows.Next() {
+		var t types.ConfidenceTransition
+		if err := rows.Scan(&t.LoreID, &t.PreviousConfidence, &t.CurrentConfidence); err != nil {
+			return nil, fmt.Errorf("scan decay transition: %w", err)
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, rows.Err()
+}
@@ -0,0 +1,220 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/oklog/ulid/v2"
+)
+
+// Run with: go test ./internal/store -run '^$' -bench . -benchmem
+//
+// Benchmarks seed their stores with raw inserts so setup time stays out of
+// the measured loop. Embeddings are shorter than production ones to keep the
+// 1M entry store under a gigabyte; FindSimilar cost scales linearly with
+// dimension. Pass -short to skip the 1M entry cases.
+
+const benchEmbeddingDim = 256
+
+// benchCategory holds every seeded entry, so FindSimilar scans them all.
+const benchCategory = "PATTERN_OUTCOME"
+
+// newBenchStore opens a file-backed store, since VACUUM INTO and WAL
+// behavior differ in memory.
+func newBenchStore(b *testing.B) *SQLiteStore {
+	b.Helper()
+	s, err := NewSQLiteStore(filepath.Join(b.TempDir(), "bench.db"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { s.Close() })
+	return s
+}
+
+// randomEmbedding returns a unit vector of benchEmbeddingDim dimensions.
+func randomEmbedding(rng *rand.Rand) []float32 {
+	v := make([]float32, benchEmbeddingDim)
+	var norm float64
+	for i := range v {
+		v[i] = float32(rng.NormFloat64())
+		norm += float64(v[i]) * float64(v[i])
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range v {
+		v[i] *= scale
+	}
+	return v
+}
+
+// benchEmbedder returns random benchEmbeddingDim embeddings, so ingested
+// entries rarely duplicate the seeded ones and dedup scans the whole category.
+type benchEmbedder struct {
+	rng *rand.Rand
+}
+
+func (e *benchEmbedder) Embed(ctx context.Context, content string) ([]float32, error) {
+	return randomEmbedding(e.rng), nil
+}
+
+func (e *benchEmbedder) EmbedBatch(ctx context.Context, contents []string) ([][]float32, error) {
+	result := make([][]float32, len(contents))
+	for i := range contents {
+		result[i] = randomEmbedding(e.rng)
+	}
+	return result, nil
+}
+
+func (e *benchEmbedder) ModelName() string {
+	return "bench-embedder"
+}
+
+// seedBenchEntries inserts n embedded entries in batched transactions.
+func seedBenchEntries(b *testing.B, s *SQLiteStore, n int) {
+	b.Helper()
+	const batch = 10000
+	rng := rand.New(rand.NewPCG(1, 2))
+	now := time.Now().UTC().Format(time.RFC3339)
+	sources, _ := json.Marshal([]string{"bench"})
+
+	for start := 0; start < n; start += batch {
+		tx, err := s.db.Begin()
+		if err != nil {
+			b.Fatal(err)
+		}
+		stmt, err := tx.Prepare(`
+			INSERT INTO lore_entries (
+				id, content, context, category, confidence,
+				embedding, embedding_status, source_id, sources,
+				validation_count, created_at, updated_at
+			) VALUES (?, ?, '', ?, 0.5, ?, 'complete', 'bench', ?, 0, ?, ?)
+		`)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for i := start; i < min(start+batch, n); i++ {
+			_, err := stmt.Exec(ulid.Make().String(), fmt.Sprintf("Seeded lore entry %d", i),
+				benchCategory, packEmbedding(randomEmbedding(rng)), string(sources), now, now)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+		stmt.Close()
+		if err := tx.Commit(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// seedBenchChangeLog appends n lore upserts to the change log.
+func seedBenchChangeLog(b *testing.B, s *SQLiteStore, n int) {
+	b.Helper()
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	tx, err := s.db.Begin()
+	if err != nil {
+		b.Fatal(err)
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO change_log (table_name, entity_id, operation, payload, source_id, created_at)
+		VALUES ('lore_entries', ?, 'upsert', ?, 'bench', ?)
+	`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		id := ulid.Make().String()
+		payload := fmt.Sprintf(`{"id":%q,"content":"Seeded lore entry %d","category":%q}`, id, i, benchCategory)
+		if _, err := stmt.Exec(id, payload, now); err != nil {
+			b.Fatal(err)
+		}
+	}
+	stmt.Close()
+	if err := tx.Commit(); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkIngestLore_WithDedup(b *testing.B) {
+	s := newBenchStore(b)
+	seedBenchEntries(b, s, 10000)
+	s.SetDependencies(&benchEmbedder{rng: rand.New(rand.NewPCG(5, 6))}, &mockConfig{dedupEnabled: true, threshold: 0.92})
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		entries := make([]types.NewLoreEntry, 10)
+		for j := range entries {
+			entries[j] = types.NewLoreEntry{
+				Content:    fmt.Sprintf("Benchmark lore %d-%d", i, j),
+				Category:   benchCategory,
+				Confidence: 0.5,
+				SourceID:   "bench",
+			}
+		}
+		if _, err := s.IngestLore(ctx, entries); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFindSimilar(b *testing.B) {
+	for _, n := range []int{10_000, 100_000, 1_000_000} {
+		b.Run(fmt.Sprintf("entries=%d", n), func(b *testing.B) {
+			if n > 100_000 && testing.Short() {
+				b.Skip("skipping 1M entry store in short mode")
+			}
+			s := newBenchStore(b)
+			seedBenchEntries(b, s, n)
+			query := randomEmbedding(rand.New(rand.NewPCG(3, 4)))
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := s.FindSimilar(ctx, query, benchCategory, 0.92); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGetChangeLogAfter_Pagination(b *testing.B) {
+	const total = 100_000
+	s := newBenchStore(b)
+	seedBenchChangeLog(b, s, total)
+	ctx := context.Background()
+
+	for _, limit := range []int{100, 1000} {
+		b.Run(fmt.Sprintf("limit=%d", limit), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				after := int64(i*limit) % (total - int64(limit))
+				page, err := s.GetChangeLogAfter(ctx, after, limit)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(page) != limit {
+					b.Fatalf("page has %d entries, want %d", len(page), limit)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGenerateSnapshot(b *testing.B) {
+	s := newBenchStore(b)
+	seedBenchEntries(b, s, 10000)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := s.GenerateSnapshot(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -2,40 +2,78 @@ package store
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/hyperengineering/engram/internal/hlc"
 	engramsync "github.com/hyperengineering/engram/internal/sync"
+	"github.com/hyperengineering/engram/internal/types"
 )
 
 const insertChangeLogSQL = `
-	INSERT INTO change_log (table_name, entity_id, operation, payload, source_id, created_at)
-	VALUES (?, ?, ?, ?, ?, ?)`
+	INSERT INTO change_log (table_name, entity_id, operation, payload, source_id, created_at, source_sequence, hlc, received_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 // changeLogArgs returns the SQL arguments for inserting a ChangeLogEntry.
 func changeLogArgs(e *engramsync.ChangeLogEntry) []any {
 	return []any{
 		e.TableName, e.EntityID, e.Operation,
 		nullablePayload(e.Payload), e.SourceID,
-		e.CreatedAt.Format(time.RFC3339Nano),
+		formatTime(e.CreatedAt),
+		nullableSequence(e.SourceSequence),
+		e.HLC,
+		formatTime(e.ReceivedAt),
 	}
 }
 
+// appendChangeLogEntry stamps entry with the store's clock and inserts it,
+// returning the assigned sequence. A lore entry's row takes the stamp too.
+// An entry without a received time is given the current time.
+func (s *SQLiteStore) appendChangeLogEntry(ctx context.Context, exec execContext, entry *engramsync.ChangeLogEntry) (int64, error) {
+	entry.HLC = s.clock.Update(entry.HLC)
+	if entry.ReceivedAt.IsZero() {
+		entry.ReceivedAt = time.Now().UTC()
+	}
+	result, err := exec.ExecContext(ctx, insertChangeLogSQL, changeLogArgs(entry)...)
+	if err != nil {
+		return 0, err
+	}
+	if err := stampLoreHLC(ctx, exec, entry.TableName, entry.EntityID, entry.HLC); err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// stampLoreHLC records ts as the clock reading of a lore entry's latest
+// change. Other tables are left alone.
+func stampLoreHLC(ctx context.Context, exec execContext, tableName, entityID string, ts hlc.Timestamp) error {
+	if tableName != "lore_entries" {
+		return nil
+	}
+	if _, err := exec.ExecContext(ctx, `UPDATE lore_entries SET hlc = ? WHERE id = ?`, ts, entityID); err != nil {
+		return fmt.Errorf("stamp lore hlc: %w", err)
+	}
+	return nil
+}
+
 // AppendChangeLog appends a single entry to the change log.
 // Returns the assigned sequence number.
 func (s *SQLiteStore) AppendChangeLog(ctx context.Context, entry *engramsync.ChangeLogEntry) (int64, error) {
-	result, err := s.db.ExecContext(ctx, insertChangeLogSQL, changeLogArgs(entry)...)
+	seq, err := s.appendChangeLogEntry(ctx, s.db, entry)
 	if err != nil {
 		return 0, fmt.Errorf("append change log: %w", err)
 	}
-	return result.LastInsertId()
+	return seq, nil
 }
 
 // AppendChangeLogBatch appends multiple entries atomically.
@@ -53,14 +91,10 @@ func (s *SQLiteStore) AppendChangeLogBatch(ctx context.Context, entries []engram
 
 	var highestSeq int64
 	for i := range entries {
-		result, err := tx.ExecContext(ctx, insertChangeLogSQL, changeLogArgs(&entries[i])...)
+		highestSeq, err = s.appendChangeLogEntry(ctx, tx, &entries[i])
 		if err != nil {
 			return 0, fmt.Errorf("append change log entry %d: %w", i, err)
 		}
-		highestSeq, err = result.LastInsertId()
-		if err != nil {
-			return 0, fmt.Errorf("get last insert id: %w", err)
-		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -71,28 +105,225 @@ func (s *SQLiteStore) AppendChangeLogBatch(ctx context.Context, entries []engram
 
 // GetChangeLogAfter returns entries with sequence > afterSeq, up to limit.
 func (s *SQLiteStore) GetChangeLogAfter(ctx context.Context, afterSeq int64, limit int) ([]engramsync.ChangeLogEntry, error) {
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT sequence, table_name, entity_id, operation, payload, source_id, created_at, received_at
+	return s.QueryChangeLog(ctx, afterSeq, limit, engramsync.ChangeLogFilter{})
+}
+
+// QueryChangeLog returns up to limit entries with sequence > afterSeq that
+// match filter, ordered by sequence ascending. Filtering happens in SQL so
+// the limit applies to matching entries only.
+func (s *SQLiteStore) QueryChangeLog(ctx context.Context, afterSeq int64, limit int, filter engramsync.ChangeLogFilter) ([]engramsync.ChangeLogEntry, error) {
+	where := []string{"sequence > ?"}
+	args := []any{afterSeq}
+
+	if len(filter.Tables) > 0 {
+		placeholders := make([]string, len(filter.Tables))
+		for i, name := range filter.Tables {
+			placeholders[i] = "?"
+			args = append(args, name)
+		}
+		where = append(where, fmt.Sprintf("table_name IN (%s)", strings.Join(placeholders, ",")))
+	}
+	if len(filter.ExcludeTables) > 0 {
+		placeholders := make([]string, len(filter.ExcludeTables))
+		for i, name := range filter.ExcludeTables {
+			placeholders[i] = "?"
+			args = append(args, name)
+		}
+		where = append(where, fmt.Sprintf("table_name NOT IN (%s)", strings.Join(placeholders, ",")))
+	}
+	if filter.ExcludeSourceID != "" {
+		where = append(where, "source_id != ?")
+		args = append(args, filter.ExcludeSourceID)
+	}
+	if !filter.ReceivedSince.IsZero() {
+		where = append(where, "received_at >= ?")
+		args = append(args, formatTime(filter.ReceivedSince))
+	}
+	if !filter.ReceivedUntil.IsZero() {
+		where = append(where, "received_at < ?")
+		args = append(args, formatTime(filter.ReceivedUntil))
+	}
+
+	args = append(args, limit)
+	query := fmt.Sprintf(`
+		SELECT sequence, table_name, entity_id, operation, payload, source_id, created_at, received_at, source_sequence, hlc
 		FROM change_log
-		WHERE sequence > ?
+		WHERE %s
 		ORDER BY sequence ASC
 		LIMIT ?
-	`, afterSeq, limit)
+	`, strings.Join(where, " AND "))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query change log: %w", err)
 	}
 	defer rows.Close()
 
+	return scanChangeLogRows(rows)
+}
+
+// GetLatestEntries returns the most recent change_log entry for every entity
+// in the given tables, ordered by sequence. Delete tombstones are included so
+// callers can tell a deleted entity from one that never existed. Because
+// compaction always retains the latest entry per entity, this reflects the
+// current state even after old history has been compacted away.
+func (s *SQLiteStore) GetLatestEntries(ctx context.Context, tableNames []string) ([]engramsync.ChangeLogEntry, error) {
+	if len(tableNames) == 0 {
+		return []engramsync.ChangeLogEntry{}, nil
+	}
+
+	placeholders := make([]string, len(tableNames))
+	args := make([]any, len(tableNames))
+	for i, name := range tableNames {
+		placeholders[i] = "?"
+		args[i] = name
+	}
+
+	query := fmt.Sprintf(`
+		SELECT c.sequence, c.table_name, c.entity_id, c.operation, c.payload, c.source_id, c.created_at, c.received_at, c.source_sequence, c.hlc
+		FROM change_log c
+		JOIN (
+			SELECT MAX(sequence) AS sequence
+			FROM change_log
+			WHERE table_name IN (%s)
+			GROUP BY table_name, entity_id
+		) latest ON latest.sequence = c.sequence
+		ORDER BY c.sequence ASC
+	`, strings.Join(placeholders, ","))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query latest entries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanChangeLogRows(rows)
+}
+
+// VerifyChangeLog checksums the replica state as of sequence upto, as
+// described on engramsync.VerifyResponse, leaving out the hidden tables. A
+// client that has applied the change log through upto computes the same
+// checksum from its replica. Returns ErrHistoryCompacted if compaction has
+// removed the state at upto.
+func (s *SQLiteStore) VerifyChangeLog(ctx context.Context, upto int64, hidden []string) (*engramsync.VerifyResponse, error) {
+	// Stores compacted before the state floor was recorded fall back to the
+	// last compacted sequence, which is a lower bound for it.
+	floor, err := s.GetSyncMeta(ctx, engramsync.SyncMetaStateFloorSeq)
+	if errors.Is(err, ErrNotFound) {
+		floor, err = s.GetSyncMeta(ctx, engramsync.SyncMetaLastCompactionSeq)
+	}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if n, _ := strconv.ParseInt(floor, 10, 64); upto < n {
+		return nil, fmt.Errorf("%w: state before sequence %d is no longer recorded", ErrHistoryCompacted, n)
+	}
+
+	query := `
+		SELECT c.table_name, c.entity_id,
+		       COALESCE(CAST(CASE WHEN json_valid(c.payload) THEN json_extract(c.payload, '$.updated_at') END AS TEXT), '')
+		FROM change_log c
+		JOIN (
+			SELECT MAX(sequence) AS sequence
+			FROM change_log
+			WHERE sequence <= ?
+			GROUP BY table_name, entity_id
+		) latest ON latest.sequence = c.sequence
+		WHERE c.operation = 'upsert'`
+	args := []any{upto}
+	if len(hidden) > 0 {
+		query += ` AND c.table_name NOT IN (?` + strings.Repeat(`, ?`, len(hidden)-1) + `)`
+		for _, t := range hidden {
+			args = append(args, t)
+		}
+	}
+	rows, err := s.db.QueryContext(ctx, query+` ORDER BY c.table_name, c.entity_id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query replica state: %w", err)
+	}
+	defer rows.Close()
+
+	resp := &engramsync.VerifyResponse{Upto: upto}
+	h := sha256.New()
+	for rows.Next() {
+		var table, id, updatedAt string
+		if err := rows.Scan(&table, &id, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scan replica state: %w", err)
+		}
+		fmt.Fprintf(h, "%s\t%s\t%s\n", table, id, updatedAt)
+		resp.Entities++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate replica state: %w", err)
+	}
+	resp.Checksum = "sha256:" + hex.EncodeToString(h.Sum(nil))
+	return resp, nil
+}
+
+// ExistingEntityIDs returns the subset of ids in tableName whose latest
+// change_log entry is an upsert without a deleted_at marker. It implements
+// plugin.EntityLookup for cross-batch reference validation.
+func (s *SQLiteStore) ExistingEntityIDs(ctx context.Context, tableName string, ids []string) (map[string]bool, error) {
+	found := make(map[string]bool)
+	if len(ids) == 0 {
+		return found, nil
+	}
+
+	seen := make(map[string]bool, len(ids))
+	placeholders := make([]string, 0, len(ids))
+	args := []any{tableName}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		placeholders = append(placeholders, "?")
+		args = append(args, id)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT c.entity_id
+		FROM change_log c
+		JOIN (
+			SELECT MAX(sequence) AS sequence
+			FROM change_log
+			WHERE table_name = ? AND entity_id IN (%s)
+			GROUP BY entity_id
+		) latest ON latest.sequence = c.sequence
+		WHERE c.operation = 'upsert'
+		  AND (CASE WHEN json_valid(c.payload) THEN json_extract(c.payload, '$.deleted_at') END) IS NULL
+	`, strings.Join(placeholders, ","))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query existing entities: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan entity id: %w", err)
+		}
+		found[id] = true
+	}
+	return found, rows.Err()
+}
+
+// scanChangeLogRows reads change_log rows selected in the standard column order.
+func scanChangeLogRows(rows *sql.Rows) ([]engramsync.ChangeLogEntry, error) {
 	entries := make([]engramsync.ChangeLogEntry, 0)
 	for rows.Next() {
 		var e engramsync.ChangeLogEntry
 		var payload sql.NullString
 		var createdAt, receivedAt string
+		var sourceSeq sql.NullInt64
 
 		if err := rows.Scan(&e.Sequence, &e.TableName, &e.EntityID, &e.Operation,
-			&payload, &e.SourceID, &createdAt, &receivedAt); err != nil {
+			&payload, &e.SourceID, &createdAt, &receivedAt, &sourceSeq, &e.HLC); err != nil {
 			return nil, fmt.Errorf("scan change log entry: %w", err)
 		}
+		e.SourceSequence = sourceSeq.Int64
 
 		if payload.Valid {
 			e.Payload = json.RawMessage(payload.String)
@@ -136,6 +367,7 @@ func (s *SQLiteStore) CheckPushIdempotency(ctx context.Context, pushID string) (
 	`, pushID).Scan(&response, &expiresAt)
 
 	if errors.Is(err, sql.ErrNoRows) {
+		s.idempotencyMisses.Add(1)
 		return nil, false, nil
 	}
 	if err != nil {
@@ -148,9 +380,18 @@ func (s *SQLiteStore) CheckPushIdempotency(ctx context.Context, pushID string) (
 		slog.Warn("push_idempotency: failed to parse expires_at", "value", expiresAt, "error", parseErr)
 	}
 	if time.Now().After(expires) {
+		s.idempotencyMisses.Add(1)
 		return nil, false, nil
 	}
 
+	// Refresh recency for LRU eviction; a failure here only affects eviction order
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE push_idempotency SET last_used_at = strftime('%Y-%m-%dT%H:%M:%fZ', 'now') WHERE push_id = ?
+	`, pushID); err != nil {
+		slog.Warn("push_idempotency: failed to update last_used_at", "push_id", pushID, "error", err)
+	}
+
+	s.idempotencyHits.Add(1)
 	return []byte(response), true, nil
 }
 
@@ -158,9 +399,9 @@ func (s *SQLiteStore) CheckPushIdempotency(ctx context.Context, pushID string) (
 func (s *SQLiteStore) RecordPushIdempotency(ctx context.Context, pushID, storeID string, response []byte, ttl time.Duration) error {
 	expiresAt := time.Now().Add(ttl)
 	_, err := s.db.ExecContext(ctx, `
-		INSERT OR REPLACE INTO push_idempotency (push_id, store_id, response, expires_at)
-		VALUES (?, ?, ?, ?)
-	`, pushID, storeID, string(response), expiresAt.Format(time.RFC3339Nano))
+		INSERT OR REPLACE INTO push_idempotency (push_id, store_id, response, expires_at, last_used_at)
+		VALUES (?, ?, ?, ?, strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+	`, pushID, storeID, string(response), formatTime(expiresAt))
 	if err != nil {
 		return fmt.Errorf("record push idempotency: %w", err)
 	}
@@ -172,13 +413,55 @@ func (s *SQLiteStore) RecordPushIdempotency(ctx context.Context, pushID, storeID
 func (s *SQLiteStore) CleanExpiredIdempotency(ctx context.Context) (int64, error) {
 	result, err := s.db.ExecContext(ctx, `
 		DELETE FROM push_idempotency WHERE expires_at < ?
-	`, time.Now().Format(time.RFC3339Nano))
+	`, formatTime(time.Now()))
 	if err != nil {
 		return 0, fmt.Errorf("clean expired idempotency: %w", err)
 	}
 	return result.RowsAffected()
 }
 
+// EnforceIdempotencyLimit evicts the least recently used idempotency
+// entries beyond maxEntries. Returns the number of entries evicted.
+func (s *SQLiteStore) EnforceIdempotencyLimit(ctx context.Context, maxEntries int) (int64, error) {
+	if maxEntries <= 0 {
+		return 0, nil
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM push_idempotency WHERE push_id IN (
+			SELECT push_id FROM push_idempotency
+			ORDER BY last_used_at DESC
+			LIMIT -1 OFFSET ?
+		)
+	`, maxEntries)
+	if err != nil {
+		return 0, fmt.Errorf("enforce idempotency limit: %w", err)
+	}
+	evicted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	s.idempotencyEvictions.Add(evicted)
+	return evicted, nil
+}
+
+// IdempotencyStats reports the push idempotency cache size and the hit,
+// miss, and eviction counts since this store was opened.
+func (s *SQLiteStore) IdempotencyStats(ctx context.Context) (types.IdempotencyStats, error) {
+	stats := types.IdempotencyStats{
+		Hits:      s.idempotencyHits.Load(),
+		Misses:    s.idempotencyMisses.Load(),
+		Evictions: s.idempotencyEvictions.Load(),
+	}
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM push_idempotency`).Scan(&stats.Entries); err != nil {
+		return stats, fmt.Errorf("count idempotency entries: %w", err)
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+	return stats, nil
+}
+
 // GetSyncMeta retrieves a sync metadata value by key.
 func (s *SQLiteStore) GetSyncMeta(ctx context.Context, key string) (string, error) {
 	var value string
@@ -209,7 +492,7 @@ func (s *SQLiteStore) SetSyncMeta(ctx context.Context, key, value string) error
 // Exports all removed entries to auditDir before deletion.
 // Returns (exported, deleted, error).
 func (s *SQLiteStore) CompactChangeLog(ctx context.Context, cutoff time.Time, auditDir string) (exported int64, deleted int64, err error) {
-	cutoffStr := cutoff.UTC().Format(time.RFC3339)
+	cutoffStr := formatTime(cutoff)
 
 	// 1. Query entries eligible for compaction (older than cutoff)
 	rows, err := s.db.QueryContext(ctx, `
@@ -353,12 +636,12 @@ func (s *SQLiteStore) CompactChangeLog(ctx context.Context, cutoff time.Time, au
 	}
 
 	// 6. Update sync_meta
-	now := time.Now().UTC().Format(time.RFC3339)
+	now := formatTime(time.Now())
 	maxDeletedSeq := toDelete[len(toDelete)-1]
 
-	_, err = tx.ExecContext(ctx, `
-		INSERT OR REPLACE INTO sync_meta (key, value) VALUES (?, ?)
-	`, engramsync.SyncMetaLastCompactionSeq, fmt.Sprintf("%d", maxDeletedSeq))
+	// A later run may delete entries older than an earlier run did, so
+	// keep the highest sequence ever compacted.
+	_, err = tx.ExecContext(ctx, raiseSyncMetaSeqSQL, engramsync.SyncMetaLastCompactionSeq, fmt.Sprintf("%d", maxDeletedSeq))
 	if err != nil {
 		return 0, 0, fmt.Errorf("update last_compaction_seq: %w", err)
 	}
@@ -370,6 +653,19 @@ func (s *SQLiteStore) CompactChangeLog(ctx context.Context, cutoff time.Time, au
 		return 0, 0, fmt.Errorf("update last_compaction_at: %w", err)
 	}
 
+	// A deleted entry was its entity's state until the entry that superseded
+	// it, so states before the latest superseding entry are lost.
+	var stateFloor int64
+	for _, e := range entries {
+		if toDeleteSet[e.Sequence] {
+			stateFloor = max(stateFloor, latestSeqPerEntity[e.TableName+":"+e.EntityID])
+		}
+	}
+	_, err = tx.ExecContext(ctx, raiseSyncMetaSeqSQL, engramsync.SyncMetaStateFloorSeq, fmt.Sprintf("%d", stateFloor))
+	if err != nil {
+		return 0, 0, fmt.Errorf("update state_floor_seq: %w", err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return 0, 0, fmt.Errorf("commit transaction: %w", err)
 	}
@@ -377,12 +673,19 @@ func (s *SQLiteStore) CompactChangeLog(ctx context.Context, cutoff time.Time, au
 	return exported, deleted, nil
 }
 
+// raiseSyncMetaSeqSQL sets a sequence-valued sync_meta key to the given
+// value unless it already holds a higher one.
+const raiseSyncMetaSeqSQL = `
+	INSERT INTO sync_meta (key, value) VALUES (?, ?)
+	ON CONFLICT(key) DO UPDATE SET value = CAST(MAX(CAST(value AS INTEGER), CAST(excluded.value AS INTEGER)) AS TEXT)
+`
+
 // SetLastCompaction records compaction metadata in sync_meta.
 func (s *SQLiteStore) SetLastCompaction(ctx context.Context, sequence int64, timestamp time.Time) error {
 	if err := s.SetSyncMeta(ctx, engramsync.SyncMetaLastCompactionSeq, fmt.Sprintf("%d", sequence)); err != nil {
 		return err
 	}
-	return s.SetSyncMeta(ctx, engramsync.SyncMetaLastCompactionAt, timestamp.UTC().Format(time.RFC3339))
+	return s.SetSyncMeta(ctx, engramsync.SyncMetaLastCompactionAt, formatTime(timestamp))
 }
 
 // nullablePayload converts a json.RawMessage to a sql-friendly value.
@@ -393,3 +696,11 @@ func nullablePayload(p json.RawMessage) any {
 	}
 	return string(p)
 }
+
+// nullableSequence maps an unset (zero) source sequence to NULL.
+func nullableSequence(seq int64) any {
+	if seq == 0 {
+		return nil
+	}
+	return seq
+}
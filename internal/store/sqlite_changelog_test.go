@@ -2,8 +2,11 @@ package store
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,7 +15,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hyperengineering/engram/internal/hlc"
 	engramsync "github.com/hyperengineering/engram/internal/sync"
+	"github.com/hyperengineering/engram/internal/types"
 	"github.com/hyperengineering/engram/migrations"
 	"github.com/pressly/goose/v3"
 	_ "modernc.org/sqlite"
@@ -34,6 +39,24 @@ func TestMigration002_CreatesChangeLog(t *testing.T) {
 	}
 }
 
+func TestMigration004_AddsSourceSequence(t *testing.T) {
+	db := newTestDB(t)
+
+	_, err := db.Exec(`SELECT source_sequence FROM change_log LIMIT 0`)
+	if err != nil {
+		t.Fatalf("change_log.source_sequence missing: %v", err)
+	}
+}
+
+func TestMigration005_AddsIdempotencyLastUsed(t *testing.T) {
+	db := newTestDB(t)
+
+	_, err := db.Exec(`SELECT last_used_at FROM push_idempotency LIMIT 0`)
+	if err != nil {
+		t.Fatalf("push_idempotency.last_used_at missing: %v", err)
+	}
+}
+
 func TestMigration002_CreatesIdempotency(t *testing.T) {
 	// Given: A fresh database with migrations applied
 	db := newTestDB(t)
@@ -108,8 +131,8 @@ func TestMigration002_Indexes(t *testing.T) {
 
 	// Then: All new indexes exist
 	expectedIndexes := []string{
-		"idx_change_log_sequence",
 		"idx_change_log_entity",
+		"idx_change_log_received",
 		"idx_push_idempotency_expires",
 	}
 
@@ -200,6 +223,32 @@ func TestAppendChangeLog_AssignsSequence(t *testing.T) {
 	}
 }
 
+func TestAppendChangeLog_ReceivedAtFullPrecision(t *testing.T) {
+	// Given: An entry appended without a received time
+	store := newTestStore(t)
+	ctx := context.Background()
+	entry := &engramsync.ChangeLogEntry{
+		TableName: "lore_entries",
+		EntityID:  "entity-1",
+		Operation: engramsync.OperationDelete,
+		SourceID:  "source-1",
+		CreatedAt: time.Now().UTC(),
+	}
+	seq, err := store.AppendChangeLog(ctx, entry)
+	if err != nil {
+		t.Fatalf("AppendChangeLog failed: %v", err)
+	}
+
+	// Then: received_at is written in the fixed-width nanosecond form
+	var receivedAt string
+	if err := store.db.QueryRow(`SELECT received_at FROM change_log WHERE sequence = ?`, seq).Scan(&receivedAt); err != nil {
+		t.Fatal(err)
+	}
+	if want := formatTime(entry.ReceivedAt); receivedAt != want || len(receivedAt) != len("2006-01-02T15:04:05.000000000Z") {
+		t.Errorf("received_at = %q, want %q", receivedAt, want)
+	}
+}
+
 func TestAppendChangeLog_IncrementsSequence(t *testing.T) {
 	// Given: change_log with entries
 	store := newTestStore(t)
@@ -447,6 +496,45 @@ func TestGetChangeLogAfter_PayloadRoundTrip(t *testing.T) {
 	}
 }
 
+func TestGetChangeLogAfter_SourceSequence(t *testing.T) {
+	// Given: One pushed entry carrying a client sequence and one server entry
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	_, err := store.AppendChangeLogBatch(ctx, []engramsync.ChangeLogEntry{
+		{TableName: "lore_entries", EntityID: "e-1", Operation: engramsync.OperationUpsert,
+			SourceID: "source-1", SourceSequence: 42, CreatedAt: time.Now().UTC()},
+		{TableName: "lore_entries", EntityID: "e-2", Operation: engramsync.OperationUpsert,
+			SourceID: "server", CreatedAt: time.Now().UTC()},
+	})
+	if err != nil {
+		t.Fatalf("AppendChangeLogBatch failed: %v", err)
+	}
+
+	// When: Reading back
+	entries, err := store.GetChangeLogAfter(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("GetChangeLogAfter failed: %v", err)
+	}
+
+	// Then: The client sequence round-trips and unset stays zero
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].SourceSequence != 42 {
+		t.Errorf("SourceSequence = %d, want 42", entries[0].SourceSequence)
+	}
+	if entries[1].SourceSequence != 0 {
+		t.Errorf("SourceSequence = %d, want 0", entries[1].SourceSequence)
+	}
+
+	var nulls int
+	store.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM change_log WHERE source_sequence IS NULL`).Scan(&nulls)
+	if nulls != 1 {
+		t.Errorf("NULL source_sequence rows = %d, want 1", nulls)
+	}
+}
+
 func TestGetChangeLogAfter_TimestampPrecision(t *testing.T) {
 	// Given: An entry with sub-second precision timestamp
 	store := newTestStore(t)
@@ -484,6 +572,104 @@ func TestGetChangeLogAfter_TimestampPrecision(t *testing.T) {
 	}
 }
 
+func TestQueryChangeLog_FiltersTables(t *testing.T) {
+	// Given: Entries across three tables
+	store := newTestStore(t)
+	ctx := context.Background()
+	for i, table := range []string{"goals", "csfs", "fwus", "goals", "csfs"} {
+		_, err := store.AppendChangeLog(ctx, &engramsync.ChangeLogEntry{
+			TableName: table,
+			EntityID:  fmt.Sprintf("e-%d", i),
+			Operation: engramsync.OperationUpsert,
+			SourceID:  "source-1",
+			CreatedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			t.Fatalf("append %d failed: %v", i, err)
+		}
+	}
+
+	// When: Querying only goals and fwus
+	entries, err := store.QueryChangeLog(ctx, 0, 10, engramsync.ChangeLogFilter{Tables: []string{"goals", "fwus"}})
+
+	// Then: Only matching entries, in sequence order
+	if err != nil {
+		t.Fatalf("QueryChangeLog failed: %v", err)
+	}
+	want := []int64{1, 3, 4}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(entries))
+	}
+	for i, seq := range want {
+		if entries[i].Sequence != seq {
+			t.Errorf("entry %d: expected sequence %d, got %d", i, seq, entries[i].Sequence)
+		}
+	}
+}
+
+func TestQueryChangeLog_ExcludesSource(t *testing.T) {
+	// Given: Entries from two sources
+	store := newTestStore(t)
+	ctx := context.Background()
+	for i, source := range []string{"client-a", "client-b", "client-a", "client-b"} {
+		_, err := store.AppendChangeLog(ctx, &engramsync.ChangeLogEntry{
+			TableName: "goals",
+			EntityID:  fmt.Sprintf("e-%d", i),
+			Operation: engramsync.OperationUpsert,
+			SourceID:  source,
+			CreatedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			t.Fatalf("append %d failed: %v", i, err)
+		}
+	}
+
+	// When: Excluding client-a
+	entries, err := store.QueryChangeLog(ctx, 0, 10, engramsync.ChangeLogFilter{ExcludeSourceID: "client-a"})
+
+	// Then: Only client-b entries remain
+	if err != nil {
+		t.Fatalf("QueryChangeLog failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Sequence != 2 || entries[1].Sequence != 4 {
+		t.Fatalf("expected sequences [2 4], got %+v", entries)
+	}
+}
+
+func TestQueryChangeLog_ReceivedWindow(t *testing.T) {
+	// Given: Entries received on three consecutive days
+	store := newTestStore(t)
+	ctx := context.Background()
+	appendEntries(t, store, ctx, 3)
+	for seq, day := range map[int]string{1: "2026-03-01", 2: "2026-03-02", 3: "2026-03-03"} {
+		if _, err := store.db.Exec(`UPDATE change_log SET received_at = ? WHERE sequence = ?`, day+"T10:00:00.000Z", seq); err != nil {
+			t.Fatalf("set received_at: %v", err)
+		}
+	}
+
+	// When: Querying the window covering the second day
+	entries, err := store.QueryChangeLog(ctx, 0, 10, engramsync.ChangeLogFilter{
+		ReceivedSince: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		ReceivedUntil: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+	})
+
+	// Then: Only the entry received that day is returned
+	if err != nil {
+		t.Fatalf("QueryChangeLog failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Sequence != 2 {
+		t.Fatalf("expected sequence [2], got %+v", entries)
+	}
+
+	// Bounds are inclusive at the start and exclusive at the end
+	entries, err = store.QueryChangeLog(ctx, 0, 10, engramsync.ChangeLogFilter{
+		ReceivedSince: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+	})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("since the second entry: got %d entries, %v; want 2", len(entries), err)
+	}
+}
+
 func TestGetLatestSequence_Empty(t *testing.T) {
 	// Given: Empty change_log
 	store := newTestStore(t)
@@ -519,6 +705,97 @@ func TestGetLatestSequence_WithEntries(t *testing.T) {
 	}
 }
 
+func TestGetLatestEntries_OnePerEntity(t *testing.T) {
+	// Given: Multiple versions of the same entities across tables
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	entries := []engramsync.ChangeLogEntry{
+		{TableName: "goals", EntityID: "g1", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{"v":1}`)},
+		{TableName: "goals", EntityID: "g2", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{"v":1}`)},
+		{TableName: "goals", EntityID: "g1", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{"v":2}`)},
+		{TableName: "csfs", EntityID: "g1", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{"v":1}`)},
+		{TableName: "goals", EntityID: "g2", Operation: engramsync.OperationDelete},
+		{TableName: "lore_entries", EntityID: "l1", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{}`)},
+	}
+	for i := range entries {
+		entries[i].SourceID = "source-1"
+		entries[i].CreatedAt = time.Now().UTC()
+		if _, err := store.AppendChangeLog(ctx, &entries[i]); err != nil {
+			t.Fatalf("append %d failed: %v", i, err)
+		}
+	}
+
+	// When: Getting latest entries for goals and csfs
+	got, err := store.GetLatestEntries(ctx, []string{"goals", "csfs"})
+
+	// Then: One entry per (table, entity), ordered by sequence, tombstones included
+	if err != nil {
+		t.Fatalf("GetLatestEntries failed: %v", err)
+	}
+	want := []int64{3, 4, 5}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(got))
+	}
+	for i, seq := range want {
+		if got[i].Sequence != seq {
+			t.Errorf("entry %d: expected sequence %d, got %d", i, seq, got[i].Sequence)
+		}
+	}
+	if string(got[0].Payload) != `{"v":2}` {
+		t.Errorf("expected latest goal payload, got %s", got[0].Payload)
+	}
+	if got[2].Operation != engramsync.OperationDelete {
+		t.Errorf("expected delete tombstone for g2, got %s", got[2].Operation)
+	}
+}
+
+func TestGetLatestEntries_NoTables(t *testing.T) {
+	store := newTestStore(t)
+
+	got, err := store.GetLatestEntries(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("GetLatestEntries failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no entries, got %d", len(got))
+	}
+}
+
+func TestExistingEntityIDs_LiveOnly(t *testing.T) {
+	// Given: Goals that are live, hard-deleted, and soft-deleted
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	entries := []engramsync.ChangeLogEntry{
+		{TableName: "goals", EntityID: "live", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{"id":"live","deleted_at":null}`)},
+		{TableName: "goals", EntityID: "gone", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{"id":"gone"}`)},
+		{TableName: "goals", EntityID: "gone", Operation: engramsync.OperationDelete},
+		{TableName: "goals", EntityID: "soft", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{"id":"soft","deleted_at":"2026-01-01T00:00:00Z"}`)},
+		{TableName: "goals", EntityID: "back", Operation: engramsync.OperationDelete},
+		{TableName: "goals", EntityID: "back", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{"id":"back"}`)},
+		{TableName: "csfs", EntityID: "other", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{}`)},
+	}
+	for i := range entries {
+		entries[i].SourceID = "source-1"
+		entries[i].CreatedAt = time.Now().UTC()
+		if _, err := store.AppendChangeLog(ctx, &entries[i]); err != nil {
+			t.Fatalf("append %d failed: %v", i, err)
+		}
+	}
+
+	// When: Looking up a mix of IDs, including duplicates and another table's ID
+	found, err := store.ExistingEntityIDs(ctx, "goals", []string{"live", "gone", "soft", "back", "back", "other", "never"})
+
+	// Then: Only live goals are returned
+	if err != nil {
+		t.Fatalf("ExistingEntityIDs failed: %v", err)
+	}
+	if len(found) != 2 || !found["live"] || !found["back"] {
+		t.Errorf("expected {live, back}, got %v", found)
+	}
+}
+
 // --- Idempotency Operation Tests ---
 
 func TestCheckPushIdempotency_NotFound(t *testing.T) {
@@ -718,6 +995,93 @@ func TestCleanExpiredIdempotency_NoneExpired(t *testing.T) {
 
 // --- Sync Meta Operation Tests ---
 
+func TestEnforceIdempotencyLimit_EvictsLeastRecentlyUsed(t *testing.T) {
+	// Given: Three cached pushes with staggered last use
+	store := newTestStore(t)
+	ctx := context.Background()
+	for i, id := range []string{"push-a", "push-b", "push-c"} {
+		if err := store.RecordPushIdempotency(ctx, id, "store-1", []byte(`{}`), time.Hour); err != nil {
+			t.Fatalf("record %s failed: %v", id, err)
+		}
+		if _, err := store.db.ExecContext(ctx, `UPDATE push_idempotency SET last_used_at = ? WHERE push_id = ?`,
+			fmt.Sprintf("2026-01-01T00:00:0%d.000Z", i), id); err != nil {
+			t.Fatalf("set last_used_at: %v", err)
+		}
+	}
+
+	// When: The oldest entry is hit, then the cache is capped at 2
+	if _, found, _ := store.CheckPushIdempotency(ctx, "push-a"); !found {
+		t.Fatal("push-a should be cached")
+	}
+	evicted, err := store.EnforceIdempotencyLimit(ctx, 2)
+
+	// Then: push-b, now the least recently used, is evicted
+	if err != nil {
+		t.Fatalf("EnforceIdempotencyLimit failed: %v", err)
+	}
+	if evicted != 1 {
+		t.Errorf("expected 1 evicted, got %d", evicted)
+	}
+	for id, want := range map[string]bool{"push-a": true, "push-b": false, "push-c": true} {
+		if _, found, _ := store.CheckPushIdempotency(ctx, id); found != want {
+			t.Errorf("%s cached = %v, want %v", id, found, want)
+		}
+	}
+}
+
+func TestEnforceIdempotencyLimit_ZeroDisablesCap(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	for _, id := range []string{"push-a", "push-b"} {
+		if err := store.RecordPushIdempotency(ctx, id, "store-1", []byte(`{}`), time.Hour); err != nil {
+			t.Fatalf("record %s failed: %v", id, err)
+		}
+	}
+
+	evicted, err := store.EnforceIdempotencyLimit(ctx, 0)
+	if err != nil {
+		t.Fatalf("EnforceIdempotencyLimit failed: %v", err)
+	}
+	if evicted != 0 {
+		t.Errorf("expected 0 evicted, got %d", evicted)
+	}
+}
+
+func TestIdempotencyStats_TracksHitsAndMisses(t *testing.T) {
+	// Given: One cached push
+	store := newTestStore(t)
+	ctx := context.Background()
+	if err := store.RecordPushIdempotency(ctx, "push-a", "store-1", []byte(`{}`), time.Hour); err != nil {
+		t.Fatalf("record failed: %v", err)
+	}
+
+	// When: One hit and three misses
+	store.CheckPushIdempotency(ctx, "push-a")
+	for _, id := range []string{"push-x", "push-y", "push-z"} {
+		store.CheckPushIdempotency(ctx, id)
+	}
+
+	// Then: Stats reflect size and hit rate
+	stats, err := store.IdempotencyStats(ctx)
+	if err != nil {
+		t.Fatalf("IdempotencyStats failed: %v", err)
+	}
+	if stats.Entries != 1 || stats.Hits != 1 || stats.Misses != 3 {
+		t.Errorf("stats = %+v, want entries=1 hits=1 misses=3", stats)
+	}
+	if stats.HitRate != 0.25 {
+		t.Errorf("HitRate = %v, want 0.25", stats.HitRate)
+	}
+
+	extended, err := store.GetExtendedStats(ctx)
+	if err != nil {
+		t.Fatalf("GetExtendedStats failed: %v", err)
+	}
+	if extended.IdempotencyStats.Entries != 1 {
+		t.Errorf("extended idempotency entries = %d, want 1", extended.IdempotencyStats.Entries)
+	}
+}
+
 func TestGetSyncMeta_DefaultValues(t *testing.T) {
 	// Given: Fresh migration
 	store := newTestStore(t)
@@ -1031,8 +1395,8 @@ func TestMigration002_Rollback(t *testing.T) {
 		t.Fatalf("failed to insert: %v", err)
 	}
 
-	// When: Rolling back migration 002
-	gooseDown(t, db)
+	// When: Rolling back migration 002 (and anything applied after it)
+	gooseDownTo(t, db, 1)
 
 	// Then: New tables are removed
 	for _, tbl := range []string{"change_log", "push_idempotency", "sync_meta"} {
@@ -1263,6 +1627,43 @@ func TestCompactChangeLog_UpdatesSyncMeta(t *testing.T) {
 	}
 }
 
+func TestCompactChangeLog_KeepsHighestCompactedSeq(t *testing.T) {
+	// Given: An earlier compaction reached a later sequence than this one will
+	store := newTestStore(t)
+	ctx := context.Background()
+	if err := store.SetSyncMeta(ctx, engramsync.SyncMetaLastCompactionSeq, "100"); err != nil {
+		t.Fatalf("SetSyncMeta failed: %v", err)
+	}
+	oldTime := time.Now().UTC().Add(-48 * time.Hour)
+	for i := 0; i < 2; i++ {
+		_, err := store.AppendChangeLog(ctx, &engramsync.ChangeLogEntry{
+			TableName: "lore_entries",
+			EntityID:  "entity-1",
+			Operation: engramsync.OperationUpsert,
+			Payload:   json.RawMessage(`{"v":1}`),
+			SourceID:  "src-1",
+			CreatedAt: oldTime.Add(time.Duration(i) * time.Minute),
+		})
+		if err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+	}
+
+	// When: Compact
+	if _, _, err := store.CompactChangeLog(ctx, time.Now().UTC(), filepath.Join(t.TempDir(), "audit")); err != nil {
+		t.Fatalf("compact failed: %v", err)
+	}
+
+	// Then: last_compaction_seq doesn't move backwards
+	seq, err := store.GetSyncMeta(ctx, engramsync.SyncMetaLastCompactionSeq)
+	if err != nil {
+		t.Fatalf("GetSyncMeta failed: %v", err)
+	}
+	if seq != "100" {
+		t.Errorf("last_compaction_seq = %s, want 100", seq)
+	}
+}
+
 func TestCompactChangeLog_CreatesAuditDir(t *testing.T) {
 	// Given: Audit directory does not exist
 	store := newTestStore(t)
@@ -1615,13 +2016,182 @@ func appendEntries(t *testing.T, s *SQLiteStore, ctx context.Context, n int) {
 	}
 }
 
-// gooseDown rolls back one migration using goose.
-func gooseDown(t *testing.T, db *sql.DB) {
+// gooseDownTo rolls back all migrations above version using goose.
+func gooseDownTo(t *testing.T, db *sql.DB, version int64) {
 	t.Helper()
 
-	// goose is already configured (dialect, base FS) from RunMigrations
-	// Just call Down to roll back one migration
-	if err := goose.Down(db, "."); err != nil {
-		t.Fatalf("goose down failed: %v", err)
+	if err := goose.DownTo(db, ".", version); err != nil {
+		t.Fatalf("goose down to %d failed: %v", version, err)
+	}
+}
+
+// --- Hybrid Logical Clock Tests ---
+
+func TestAppendChangeLog_StampsHLC(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	remote := hlc.New(time.Now().Add(time.Minute), 9)
+	entries := []engramsync.ChangeLogEntry{
+		{TableName: "notes", EntityID: "n1", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{}`), SourceID: "a", CreatedAt: time.Now().UTC()},
+		{TableName: "notes", EntityID: "n2", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{}`), SourceID: "b", CreatedAt: time.Now().UTC(), HLC: remote},
+		{TableName: "notes", EntityID: "n3", Operation: engramsync.OperationDelete, SourceID: "a", CreatedAt: time.Now().UTC()},
+	}
+	if _, err := s.AppendChangeLogBatch(ctx, entries); err != nil {
+		t.Fatalf("AppendChangeLogBatch() error = %v", err)
+	}
+
+	got, err := s.GetChangeLogAfter(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("GetChangeLogAfter() error = %v", err)
+	}
+	if got[0].HLC.IsZero() || got[1].HLC <= got[0].HLC || got[2].HLC <= got[1].HLC {
+		t.Errorf("hlc = %s, %s, %s, want strictly increasing", got[0].HLC, got[1].HLC, got[2].HLC)
+	}
+	if got[1].HLC <= remote {
+		t.Errorf("hlc = %s, want after the observed reading %s", got[1].HLC, remote)
+	}
+}
+
+func TestHLC_ResumesAfterReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "hlc.db")
+
+	s, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	// A reading from the future stands in for a wall clock that later
+	// stepped backwards.
+	future := hlc.New(time.Now().Add(2*time.Minute), 0)
+	if _, err := s.AppendChangeLog(ctx, &engramsync.ChangeLogEntry{
+		TableName: "notes", EntityID: "n1", Operation: engramsync.OperationDelete, SourceID: "a", CreatedAt: time.Now().UTC(), HLC: future,
+	}); err != nil {
+		t.Fatalf("AppendChangeLog() error = %v", err)
+	}
+	s.Close()
+
+	s, err = NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("reopen error = %v", err)
+	}
+	defer s.Close()
+	entry := &engramsync.ChangeLogEntry{
+		TableName: "notes", EntityID: "n2", Operation: engramsync.OperationDelete, SourceID: "a", CreatedAt: time.Now().UTC(),
+	}
+	if _, err := s.AppendChangeLog(ctx, entry); err != nil {
+		t.Fatalf("AppendChangeLog() error = %v", err)
+	}
+	if entry.HLC <= future {
+		t.Errorf("hlc after reopen = %s, want after %s", entry.HLC, future)
+	}
+}
+
+func TestIngestLore_StampsLoreHLC(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := s.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Entry 1", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "src"},
+	}); err != nil {
+		t.Fatalf("IngestLore() error = %v", err)
+	}
+
+	log, err := s.GetChangeLogAfter(ctx, 0, 10)
+	if err != nil || len(log) != 1 {
+		t.Fatalf("GetChangeLogAfter() = %d entries, %v; want 1", len(log), err)
+	}
+	entry, err := s.GetLore(ctx, log[0].EntityID)
+	if err != nil {
+		t.Fatalf("GetLore() error = %v", err)
+	}
+	if entry.HLC.IsZero() || entry.HLC != log[0].HLC {
+		t.Errorf("lore hlc = %s, want its change_log reading %s", entry.HLC, log[0].HLC)
+	}
+}
+
+func TestVerifyChangeLog(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	appendAll := func(entries ...engramsync.ChangeLogEntry) {
+		t.Helper()
+		for i := range entries {
+			entries[i].SourceID = "source-1"
+			entries[i].CreatedAt = time.Now().UTC()
+			if _, err := store.AppendChangeLog(ctx, &entries[i]); err != nil {
+				t.Fatalf("append failed: %v", err)
+			}
+		}
+	}
+	verify := func(upto int64, hidden ...string) *engramsync.VerifyResponse {
+		t.Helper()
+		resp, err := store.VerifyChangeLog(ctx, upto, hidden)
+		if err != nil {
+			t.Fatalf("VerifyChangeLog(%d) error = %v", upto, err)
+		}
+		return resp
+	}
+
+	appendAll(
+		engramsync.ChangeLogEntry{TableName: "goals", EntityID: "g1", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{"updated_at":"2026-01-01T00:00:00Z"}`)},
+		engramsync.ChangeLogEntry{TableName: "goals", EntityID: "g2", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{"updated_at":"2026-01-01T00:00:00Z"}`)},
+		engramsync.ChangeLogEntry{TableName: "goals", EntityID: "g2", Operation: engramsync.OperationDelete},
+		engramsync.ChangeLogEntry{TableName: "csfs", EntityID: "c1", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{}`)},
+	)
+
+	// Deleted entities are left out; a missing updated_at hashes as empty
+	h := sha256.New()
+	h.Write([]byte("csfs\tc1\t\ngoals\tg1\t2026-01-01T00:00:00Z\n"))
+	want := "sha256:" + hex.EncodeToString(h.Sum(nil))
+	at4 := verify(4)
+	if at4.Upto != 4 || at4.Entities != 2 || at4.Checksum != want {
+		t.Errorf("verify(4) = %+v, want 2 entities with checksum %s", at4, want)
+	}
+	if hidden := verify(4, "csfs"); hidden.Entities != 1 {
+		t.Errorf("verify with csfs hidden counted %d entities, want 1", hidden.Entities)
+	}
+	if at2 := verify(2); at2.Entities != 2 || at2.Checksum == at4.Checksum {
+		t.Errorf("verify(2) = %+v, want the state before g2 was deleted", at2)
+	}
+
+	// A later change moves the latest state but not the state at 4
+	appendAll(engramsync.ChangeLogEntry{TableName: "goals", EntityID: "g1", Operation: engramsync.OperationUpsert, Payload: json.RawMessage(`{"updated_at":"2026-02-01T00:00:00Z"}`)})
+	if again := verify(4); again.Checksum != at4.Checksum {
+		t.Errorf("checksum at 4 changed after a later write: %s, want %s", again.Checksum, at4.Checksum)
+	}
+	if at5 := verify(5); at5.Checksum == at4.Checksum {
+		t.Error("checksum at 5 doesn't reflect the later write")
+	}
+}
+
+func TestVerifyChangeLog_CompactedHistory(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	oldTime := time.Now().UTC().Add(-48 * time.Hour)
+	for i := 0; i < 3; i++ {
+		_, err := store.AppendChangeLog(ctx, &engramsync.ChangeLogEntry{
+			TableName: "lore_entries",
+			EntityID:  "entity-1",
+			Operation: engramsync.OperationUpsert,
+			Payload:   json.RawMessage(`{"v":` + fmt.Sprintf("%d", i) + `}`),
+			SourceID:  "src-1",
+			CreatedAt: oldTime.Add(time.Duration(i) * time.Minute),
+		})
+		if err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+	}
+	if _, _, err := store.CompactChangeLog(ctx, time.Now().UTC(), filepath.Join(t.TempDir(), "audit")); err != nil {
+		t.Fatalf("CompactChangeLog failed: %v", err)
+	}
+
+	// Sequences 1 and 2 were compacted away; the state is known again from 3
+	if _, err := store.VerifyChangeLog(ctx, 2, nil); !errors.Is(err, ErrHistoryCompacted) {
+		t.Errorf("VerifyChangeLog(2) error = %v, want ErrHistoryCompacted", err)
+	}
+	if _, err := store.VerifyChangeLog(ctx, 3, nil); err != nil {
+		t.Errorf("VerifyChangeLog(3) error = %v", err)
 	}
 }
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hyperengineering/engram/internal/embedding"
+)
+
+// chunkTexts returns the chunks of content to embed alongside the whole
+// content, or nil when chunking is disabled or content is short.
+func (s *SQLiteStore) chunkTexts(content string) []string {
+	if s.cfg == nil {
+		return nil
+	}
+	size, overlap := s.cfg.GetEmbeddingChunking()
+	return embedding.Chunk(content, size, overlap)
+}
+
+// UpdateChunkEmbeddings replaces the chunk embeddings of a live lore entry.
+// Chunks without an embedding are skipped. Returns ErrNotFound if the entry
+// doesn't exist or is deleted.
+func (s *SQLiteStore) UpdateChunkEmbeddings(ctx context.Context, id string, chunks [][]float32) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM lore_entries WHERE id = ? AND deleted_at IS NULL`, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("check lore entry: %w", err)
+	}
+	if err := replaceChunksInTx(ctx, tx, id, chunks, s.quantization); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// replaceChunksInTx replaces the chunk embeddings of a lore entry, stored
+// with quantization q; passing no chunks just clears them.
+func replaceChunksInTx(ctx context.Context, execer execContext, loreID string, chunks [][]float32, q Quantization) error {
+	if _, err := execer.ExecContext(ctx, `DELETE FROM lore_chunks WHERE lore_id = ?`, loreID); err != nil {
+		return fmt.Errorf("clear lore chunks: %w", err)
+	}
+	for i, chunk := range chunks {
+		if len(chunk) == 0 {
+			continue
+		}
+		_, err := execer.ExecContext(ctx, `
+			INSERT INTO lore_chunks (lore_id, chunk_index, embedding, embedding_norm)
+			VALUES (?, ?, ?, ?)
+		`, loreID, i, q.pack(chunk), float64(norm(q.round(chunk))))
+		if err != nil {
+			return fmt.Errorf("insert lore chunk: %w", err)
+		}
+	}
+	return nil
+}
+
+// chunkSimilarities returns, for each live entry with chunk embeddings, the
+// best similarity between q and any of its chunks. An empty category
+// matches every category; entries outside scope are skipped.
+func chunkSimilarities(ctx context.Context, qc queryContext, q queryVector, category string, scope similarityScope) (map[string]float64, error) {
+	scopeClause, args := scope.where("l.")
+	query := `
+		SELECT c.lore_id, c.embedding, c.embedding_norm
+		FROM lore_chunks c JOIN lore_entries l ON l.id = c.lore_id
+		WHERE l.deleted_at IS NULL` + scopeClause
+	if category != "" {
+		query += ` AND l.category = ?`
+		args = append(args, category)
+	}
+	rows, err := qc.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query lore chunks: %w", err)
+	}
+	defer rows.Close()
+
+	best := make(map[string]float64)
+	var (
+		id, blob      sql.RawBytes
+		candidateNorm float64
+		candidate     []float32
+	)
+	for rows.Next() {
+		if err := rows.Scan(&id, &blob, &candidateNorm); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		candidate = unpackEmbeddingInto(candidate, blob)
+		if len(candidate) != len(q.v) {
+			continue // embedded with a different model
+		}
+		similarity := q.similarity(candidate, float32(candidateNorm))
+		if prev, ok := best[string(id)]; !ok || similarity > prev {
+			best[string(id)] = similarity
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return best, nil
+}
@@ -0,0 +1,149 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/embedding"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+const (
+	testChunkSize    = 200
+	testChunkOverlap = 40
+)
+
+// chunkedStore returns a store that chunks content over testChunkSize bytes
+// and embeds the given long content as wholeDim, with its last chunk as
+// chunkDim and every other chunk elsewhere.
+func chunkedStore(t *testing.T, content string, wholeDim, chunkDim int) *SQLiteStore {
+	t.Helper()
+	chunks := embedding.Chunk(content, testChunkSize, testChunkOverlap)
+	if len(chunks) < 2 {
+		t.Fatalf("test content yields %d chunks, want several", len(chunks))
+	}
+	embeddings := map[string][]float32{content: makeTestEmbedding(wholeDim)}
+	for i, c := range chunks {
+		embeddings[c] = makeTestEmbedding(wholeDim + 100 + i)
+	}
+	embeddings[chunks[len(chunks)-1]] = makeTestEmbedding(chunkDim)
+
+	s := newTestStore(t)
+	s.SetDependencies(&mockEmbedder{embeddings: embeddings}, &mockConfig{chunkSize: testChunkSize, chunkOverlap: testChunkOverlap})
+	return s
+}
+
+func longContent() string {
+	return strings.Repeat("Services talk through the event bus. ", 12) + "Retries back off exponentially."
+}
+
+func countChunks(t *testing.T, s *SQLiteStore, id string) int {
+	t.Helper()
+	var n int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM lore_chunks WHERE lore_id = ?`, id).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestIngestLore_StoresChunkEmbeddings(t *testing.T) {
+	content := longContent()
+	s := chunkedStore(t, content, 1, 2)
+	ctx := context.Background()
+
+	if _, err := s.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: content, Category: "ARCHITECTURAL_DECISION", Confidence: 0.8, SourceID: "src"},
+		{Content: "Short entry", Category: "ARCHITECTURAL_DECISION", Confidence: 0.8, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var longID, shortID string
+	s.db.QueryRow(`SELECT id FROM lore_entries WHERE content = ?`, content).Scan(&longID)
+	s.db.QueryRow(`SELECT id FROM lore_entries WHERE content = 'Short entry'`).Scan(&shortID)
+	if got, want := countChunks(t, s, longID), len(embedding.Chunk(content, testChunkSize, testChunkOverlap)); got != want {
+		t.Errorf("long entry has %d chunks, want %d", got, want)
+	}
+	if got := countChunks(t, s, shortID); got != 0 {
+		t.Errorf("short entry has %d chunks, want 0", got)
+	}
+}
+
+func TestFindSimilar_MatchesBestChunk(t *testing.T) {
+	content := longContent()
+	s := chunkedStore(t, content, 1, 2)
+	ctx := context.Background()
+	if _, err := s.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: content, Category: "ARCHITECTURAL_DECISION", Confidence: 0.8, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The query is orthogonal to the whole-entry embedding but identical to
+	// the last chunk's.
+	similar, err := s.FindSimilar(ctx, makeTestEmbedding(2), "ARCHITECTURAL_DECISION", 0.9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(similar) != 1 || similar[0].Similarity < 0.99 {
+		t.Fatalf("FindSimilar() = %+v, want the long entry at similarity 1", similar)
+	}
+
+	results, err := s.SearchLore(ctx, types.SearchQuery{
+		Text:      "anything",
+		Embedding: makeTestEmbedding(2),
+		Mode:      types.SearchModeSemantic,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) == 0 || results[0].Lore.Content != content || results[0].Relevance < 0.99 {
+		t.Errorf("semantic search did not rank the long entry by its best chunk: %+v", results)
+	}
+}
+
+func TestUpdateLore_ContentChangeClearsChunks(t *testing.T) {
+	content := longContent()
+	s := chunkedStore(t, content, 1, 2)
+	ctx := context.Background()
+	if _, err := s.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: content, Category: "ARCHITECTURAL_DECISION", Confidence: 0.8, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var id string
+	s.db.QueryRow(`SELECT id FROM lore_entries`).Scan(&id)
+
+	edited := "Rewritten decision"
+	if _, err := s.UpdateLore(ctx, id, types.LoreUpdate{Content: &edited, SourceID: "src"}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := countChunks(t, s, id); got != 0 {
+		t.Errorf("entry has %d chunks after a content edit, want 0", got)
+	}
+}
+
+func TestUpdateChunkEmbeddings(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if err := s.UpdateChunkEmbeddings(ctx, "missing", [][]float32{{1, 0}}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateChunkEmbeddings(missing) error = %v, want ErrNotFound", err)
+	}
+
+	id := insertEntryWithEmbedding(t, s, "Entry", "PATTERN_OUTCOME", []float32{1, 0})
+	if err := s.UpdateChunkEmbeddings(ctx, id, [][]float32{{1, 0}, nil, {0, 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := countChunks(t, s, id); got != 2 {
+		t.Errorf("entry has %d chunks, want 2 (nil chunks are skipped)", got)
+	}
+	if err := s.UpdateChunkEmbeddings(ctx, id, [][]float32{{0, 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := countChunks(t, s, id); got != 1 {
+		t.Errorf("entry has %d chunks after replacement, want 1", got)
+	}
+}
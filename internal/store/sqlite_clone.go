@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// cloneLoreTables hold rows keyed by lore_id that a clone drops along with
+// the entries the filter leaves out.
+var cloneLoreTables = []string{
+	"feedback_events",
+	"lore_reviews",
+	"confidence_locks",
+	"lore_versions",
+	"lore_chunks",
+	"lore_embeddings",
+}
+
+// cloneClearedTables hold state tied to the source store's clients and
+// subscribers, which a clone starts without.
+var cloneClearedTables = []string{
+	"push_idempotency",
+	"push_session_entries",
+	"push_sessions",
+	"event_outbox",
+	"feedback_windows",
+	"push_signatures",
+}
+
+// CloneInto writes a point-in-time copy of the store's database to path,
+// which must not exist, and returns the number of lore entries it kept.
+//
+// The copy keeps every plugin table as is. Lore is reduced to the active
+// entries that match filter, together with their history, feedback and
+// change_log entries, so clients of the clone never see what was left out.
+// Push idempotency records, push sessions and the event outbox are cleared.
+func (s *SQLiteStore) CloneInto(ctx context.Context, path string, filter types.CloneFilter) (int64, error) {
+	start := time.Now()
+
+	if _, err := s.db.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		os.Remove(path)
+		return 0, fmt.Errorf("vacuum into clone: %w", err)
+	}
+
+	kept, err := pruneClone(ctx, path, filter)
+	if err != nil {
+		os.Remove(path)
+		return 0, fmt.Errorf("prune clone: %w", err)
+	}
+
+	slog.Info("store cloned",
+		"component", "store",
+		"action", "clone_complete",
+		"store_id", s.storeID,
+		"lore_count", kept,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return kept, nil
+}
+
+// cloneStmt is one statement run against a clone.
+type cloneStmt struct {
+	query string
+	args  []any
+}
+
+// pruneClone removes from the database at path the lore filter leaves out
+// and the state a new store should not inherit.
+func pruneClone(ctx context.Context, path string, filter types.CloneFilter) (int64, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	where := []string{"deleted_at IS NOT NULL"}
+	var args []any
+	if len(filter.Categories) > 0 {
+		where = append(where, "category NOT IN ("+strings.Repeat("?,", len(filter.Categories)-1)+"?)")
+		for _, c := range filter.Categories {
+			args = append(args, c)
+		}
+	}
+	if filter.MinConfidence > 0 {
+		where = append(where, "confidence < ?")
+		args = append(args, filter.MinConfidence)
+	}
+
+	stmts := []cloneStmt{
+		{"DELETE FROM lore_entries WHERE " + strings.Join(where, " OR "), args},
+		{"DELETE FROM change_log WHERE table_name = 'lore_entries' AND entity_id NOT IN (SELECT id FROM lore_entries)", nil},
+		{"DELETE FROM sync_meta WHERE key = ?", []any{ConsolidationReportKey}},
+	}
+	for _, table := range cloneLoreTables {
+		stmts = append(stmts, cloneStmt{"DELETE FROM " + table + " WHERE lore_id NOT IN (SELECT id FROM lore_entries)", nil})
+	}
+	for _, table := range cloneClearedTables {
+		stmts = append(stmts, cloneStmt{"DELETE FROM " + table, nil})
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt.query, stmt.args...); err != nil {
+			return 0, err
+		}
+	}
+
+	var kept int64
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM lore_entries").Scan(&kept); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return kept, nil
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestCloneInto_KeepsFilteredLore(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	ids := make(map[string]string)
+	for _, e := range []types.NewLoreEntry{
+		{Content: "Orders use event sourcing", Category: "ARCHITECTURAL_DECISION", Confidence: 0.9, SourceID: "s1"},
+		{Content: "Retries hid the outage", Category: "PATTERN_OUTCOME", Confidence: 0.9, SourceID: "s1"},
+		{Content: "Billing owns invoices", Category: "ARCHITECTURAL_DECISION", Confidence: 0.3, SourceID: "s1"},
+		{Content: "Payments call the ledger", Category: "ARCHITECTURAL_DECISION", Confidence: 0.9, SourceID: "s1"},
+	} {
+		if _, err := s.IngestLore(ctx, []types.NewLoreEntry{e}); err != nil {
+			t.Fatal(err)
+		}
+		var id string
+		if err := s.db.QueryRow("SELECT id FROM lore_entries WHERE content = ?", e.Content).Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		ids[e.Content] = id
+	}
+	if err := s.DeleteLore(ctx, ids["Payments call the ledger"], "s1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: ids["Retries hid the outage"], Type: "helpful", SourceID: "s2"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "clone.db")
+	kept, err := s.CloneInto(ctx, path, types.CloneFilter{
+		Categories:    []string{"ARCHITECTURAL_DECISION"},
+		MinConfidence: 0.5,
+	})
+	if err != nil {
+		t.Fatalf("CloneInto() error = %v", err)
+	}
+	if kept != 1 {
+		t.Errorf("kept = %d, want 1", kept)
+	}
+
+	clone, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("open clone: %v", err)
+	}
+	defer clone.Close()
+
+	if _, err := clone.GetLore(ctx, ids["Orders use event sourcing"]); err != nil {
+		t.Errorf("GetLore(kept) error = %v", err)
+	}
+	for _, content := range []string{"Retries hid the outage", "Billing owns invoices", "Payments call the ledger"} {
+		if _, err := clone.GetLore(ctx, ids[content]); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetLore(%q) error = %v, want ErrNotFound", content, err)
+		}
+	}
+
+	latest, err := clone.GetLatestEntries(ctx, []string{"lore_entries"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(latest) != 1 || latest[0].EntityID != ids["Orders use event sourcing"] {
+		t.Errorf("change_log entities = %+v, want only the kept entry", latest)
+	}
+	var feedback int
+	if err := clone.db.QueryRow("SELECT COUNT(*) FROM feedback_events").Scan(&feedback); err != nil {
+		t.Fatal(err)
+	}
+	if feedback != 0 {
+		t.Errorf("feedback_events = %d, want 0", feedback)
+	}
+
+	results, err := clone.SearchLore(ctx, types.SearchQuery{Text: "event sourcing", Mode: types.SearchModeKeyword, Limit: 5})
+	if err != nil {
+		t.Fatalf("SearchLore() on clone error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("search on clone returned %d results, want 1", len(results))
+	}
+}
+
+func TestCloneInto_ExistingPathFails(t *testing.T) {
+	s := newTestStore(t)
+	path := filepath.Join(t.TempDir(), "clone.db")
+	if err := os.WriteFile(path, []byte("taken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.CloneInto(context.Background(), path, types.CloneFilter{}); err == nil {
+		t.Fatal("CloneInto() error = nil, want an error for an existing file")
+	}
+}
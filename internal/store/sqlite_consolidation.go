@@ -0,0 +1,227 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/plugin"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// ConsolidationReportKey is the sync_meta key holding the JSON
+// types.ConsolidationReport of the latest consolidation run.
+const ConsolidationReportKey = "consolidation_report"
+
+// embeddedEntry is an active entry loaded for pairwise comparison.
+type embeddedEntry struct {
+	id         string
+	content    string
+	confidence float64
+	embedding  []float32
+	norm       float32
+}
+
+// FindConsolidations clusters active entries that are at least threshold
+// similar to one another within a category, returning at most limit
+// proposals (zero for no limit). Such clusters exist when entries were
+// ingested before deduplication was enabled or under a lower threshold.
+//
+// Entries are visited from the strongest (highest confidence, then most
+// validated, then oldest) down. Each entry not yet claimed becomes a target
+// and claims every weaker unclaimed entry similar enough to it, so every
+// member is similar to its own target and not merely to another member.
+// Similarity is over whole-entry embeddings; entries without one are skipped.
+func (s *SQLiteStore) FindConsolidations(ctx context.Context, threshold float64, limit int) ([]types.ConsolidationProposal, error) {
+	categories, err := s.embeddedCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	proposals := []types.ConsolidationProposal{}
+	for _, category := range categories {
+		candidates, err := s.embeddedEntries(ctx, category)
+		if err != nil {
+			return nil, err
+		}
+
+		claimed := make([]bool, len(candidates))
+		for i, target := range candidates {
+			if claimed[i] {
+				continue
+			}
+			q := queryVector{v: target.embedding, norm: target.norm}
+			var members []types.ConsolidationMember
+			for j := i + 1; j < len(candidates); j++ {
+				if claimed[j] {
+					continue
+				}
+				if sim := q.similarity(candidates[j].embedding, candidates[j].norm); sim >= threshold {
+					claimed[j] = true
+					members = append(members, types.ConsolidationMember{
+						ID:         candidates[j].id,
+						Content:    candidates[j].content,
+						Similarity: sim,
+					})
+				}
+			}
+			if len(members) == 0 {
+				continue
+			}
+			proposals = append(proposals, types.ConsolidationProposal{
+				TargetID: target.id,
+				Category: category,
+				Content:  target.content,
+				Members:  members,
+				Status:   types.ConsolidationProposed,
+			})
+			if limit > 0 && len(proposals) >= limit {
+				return proposals, nil
+			}
+		}
+	}
+	return proposals, nil
+}
+
+// embeddedCategories returns the categories that have active embedded
+// entries.
+func (s *SQLiteStore) embeddedCategories(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT category FROM lore_entries
+		WHERE id IN (SELECT lore_id FROM lore_embeddings) AND deleted_at IS NULL
+		ORDER BY category
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []string
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return nil, fmt.Errorf("scan category: %w", err)
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate categories: %w", err)
+	}
+	return categories, nil
+}
+
+// embeddedEntries loads the active embedded entries of a category,
+// strongest first.
+func (s *SQLiteStore) embeddedEntries(ctx context.Context, category string) ([]embeddedEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, content, confidence, embedding, embedding_norm
+		FROM lore_entries JOIN lore_embeddings ON lore_embeddings.lore_id = lore_entries.id
+		WHERE category = ? AND deleted_at IS NULL
+		ORDER BY confidence DESC, validation_count DESC, created_at ASC, id ASC
+	`, category)
+	if err != nil {
+		return nil, fmt.Errorf("query embedded entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []embeddedEntry
+	for rows.Next() {
+		var (
+			e       embeddedEntry
+			blob    []byte
+			normCol sql.NullFloat64
+		)
+		if err := rows.Scan(&e.id, &e.content, &e.confidence, &blob, &normCol); err != nil {
+			return nil, fmt.Errorf("scan embedded entry: %w", err)
+		}
+		e.embedding = unpackEmbeddingInto(nil, blob)
+		e.norm = float32(normCol.Float64)
+		if !normCol.Valid {
+			e.norm = norm(e.embedding)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate embedded entries: %w", err)
+	}
+	return entries, nil
+}
+
+// ConsolidateLore merges each member into target in one transaction, as
+// deduplication would have at ingest, and soft-deletes the members. The
+// target also takes on the members' sources and validation counts. The
+// deletes and the updated target are recorded in change_log under
+// sourceID. Returns ErrNotFound if the target or any member is missing or
+// deleted, in which case nothing is changed.
+func (s *SQLiteStore) ConsolidateLore(ctx context.Context, targetID string, memberIDs []string, sourceID string) (*types.LoreEntry, error) {
+	if h, ok := s.hooks.(plugin.BeforeDeleteHook); ok {
+		for _, id := range memberIDs {
+			if err := h.BeforeDelete(ctx, id); err != nil {
+				return nil, fmt.Errorf("before delete hook: %w", err)
+			}
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := formatTime(time.Now())
+	for _, id := range memberIDs {
+		member, err := s.getLoreInTx(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.mergeLoreInTx(ctx, tx, targetID, types.NewLoreEntry{Context: member.Context, SourceID: member.SourceID}); err != nil {
+			return nil, err
+		}
+		if err := s.absorbMemberInTx(ctx, tx, targetID, member); err != nil {
+			return nil, err
+		}
+		if err := s.softDeleteLoreInTx(ctx, tx, id, sourceID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	merged, err := s.getLoreInTx(ctx, tx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.writeChangeLogInTx(ctx, tx, "lore_entries", targetID, "upsert", merged, sourceID, now); err != nil {
+		return nil, fmt.Errorf("write change log: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return merged, nil
+}
+
+// absorbMemberInTx adds a consolidated member's sources and validation
+// count to the target.
+func (s *SQLiteStore) absorbMemberInTx(ctx context.Context, tx *sql.Tx, targetID string, member *types.LoreEntry) error {
+	target, err := s.getLoreInTx(ctx, tx, targetID)
+	if err != nil {
+		return err
+	}
+	sources := target.Sources
+	for _, src := range member.Sources {
+		sources, _ = addSourceID(sources, src)
+	}
+	sourcesJSON, err := json.Marshal(sources)
+	if err != nil {
+		return fmt.Errorf("marshal sources: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE lore_entries
+		SET sources = ?, validation_count = validation_count + ?
+		WHERE id = ?
+	`, string(sourcesJSON), member.ValidationCount, targetID); err != nil {
+		return fmt.Errorf("update consolidated entry: %w", err)
+	}
+	return nil
+}
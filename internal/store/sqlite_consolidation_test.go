@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestFindConsolidations_ClustersSimilarEntriesPerCategory(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	dup := makeTestEmbedding(1)
+	a := insertEntryWithEmbedding(t, s, "Retry with backoff", "PATTERN_OUTCOME", dup)
+	b := insertEntryWithEmbedding(t, s, "Retry using backoff", "PATTERN_OUTCOME", makeIdenticalEmbedding(dup))
+	strongest := insertEntryWithEmbedding(t, s, "Always retry with backoff", "PATTERN_OUTCOME", makeIdenticalEmbedding(dup))
+	insertEntryWithEmbedding(t, s, "Unrelated", "PATTERN_OUTCOME", makeOrthogonalEmbedding(dup))
+	insertEntryWithEmbedding(t, s, "Retry with backoff (other category)", "TESTING_STRATEGY", makeIdenticalEmbedding(dup))
+	if _, err := s.db.Exec(`UPDATE lore_entries SET confidence = 0.95 WHERE id = ?`, strongest); err != nil {
+		t.Fatal(err)
+	}
+
+	proposals, err := s.FindConsolidations(ctx, 0.95, 0)
+	if err != nil {
+		t.Fatalf("FindConsolidations() error = %v", err)
+	}
+	if len(proposals) != 1 {
+		t.Fatalf("got %d proposals, want 1: %+v", len(proposals), proposals)
+	}
+	p := proposals[0]
+	if p.TargetID != strongest || p.Category != "PATTERN_OUTCOME" || p.Status != types.ConsolidationProposed {
+		t.Errorf("unexpected proposal: %+v", p)
+	}
+	var memberIDs []string
+	for _, m := range p.Members {
+		memberIDs = append(memberIDs, m.ID)
+		if m.Similarity < 0.95 {
+			t.Errorf("member %s similarity = %v", m.ID, m.Similarity)
+		}
+	}
+	slices.Sort(memberIDs)
+	want := []string{a, b}
+	slices.Sort(want)
+	if !slices.Equal(memberIDs, want) {
+		t.Errorf("members = %v, want %v", memberIDs, want)
+	}
+}
+
+func TestFindConsolidations_Limit(t *testing.T) {
+	s := newTestStore(t)
+	for i := range 3 {
+		emb := makeTestEmbedding(i)
+		insertEntryWithEmbedding(t, s, "first "+string(rune('a'+i)), "PATTERN_OUTCOME", emb)
+		insertEntryWithEmbedding(t, s, "second "+string(rune('a'+i)), "PATTERN_OUTCOME", makeIdenticalEmbedding(emb))
+	}
+
+	proposals, err := s.FindConsolidations(context.Background(), 0.95, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposals) != 2 {
+		t.Errorf("got %d proposals, want 2", len(proposals))
+	}
+}
+
+func TestConsolidateLore_MergesAndDeletesMembers(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	emb := makeTestEmbedding(1)
+	target := insertEntryWithEmbedding(t, s, "Retry with backoff", "PATTERN_OUTCOME", emb)
+	member := insertEntryWithEmbedding(t, s, "Retry using backoff", "PATTERN_OUTCOME", makeIdenticalEmbedding(emb))
+	if _, err := s.db.Exec(`UPDATE lore_entries SET validation_count = 3, sources = '["a","b"]', context = 'seen in payments' WHERE id = ?`, member); err != nil {
+		t.Fatal(err)
+	}
+	seqBefore, err := s.GetLatestSequence(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := s.ConsolidateLore(ctx, target, []string{member}, "consolidation")
+	if err != nil {
+		t.Fatalf("ConsolidateLore() error = %v", err)
+	}
+
+	if merged.ValidationCount != 3 {
+		t.Errorf("validation_count = %d, want 3", merged.ValidationCount)
+	}
+	for _, src := range []string{"test-source", "a", "b"} {
+		if !slices.Contains(merged.Sources, src) {
+			t.Errorf("sources = %v, missing %s", merged.Sources, src)
+		}
+	}
+	if merged.Context != "seen in payments" {
+		t.Errorf("context = %q, want the member's context appended", merged.Context)
+	}
+	if merged.Confidence <= 0.8 {
+		t.Errorf("confidence = %v, want boosted above 0.8", merged.Confidence)
+	}
+	if _, err := s.GetLore(ctx, member); !errors.Is(err, ErrNotFound) {
+		t.Errorf("member still active: err = %v", err)
+	}
+
+	entries, err := s.GetChangeLogAfter(ctx, seqBefore, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Operation != "delete" || entries[0].EntityID != member ||
+		entries[1].Operation != "upsert" || entries[1].EntityID != target {
+		t.Errorf("unexpected change log: %+v", entries)
+	}
+}
+
+func TestConsolidateLore_MissingMemberChangesNothing(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	emb := makeTestEmbedding(1)
+	target := insertEntryWithEmbedding(t, s, "Retry with backoff", "PATTERN_OUTCOME", emb)
+	member := insertEntryWithEmbedding(t, s, "Retry using backoff", "PATTERN_OUTCOME", makeIdenticalEmbedding(emb))
+
+	_, err := s.ConsolidateLore(ctx, target, []string{member, "01ARZ3NDEKTSV4RRFFQ69G5FAV"}, "consolidation")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.GetLore(ctx, member); err != nil {
+		t.Errorf("member was deleted despite rollback: %v", err)
+	}
+	entry, err := s.GetLore(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Confidence != 0.8 {
+		t.Errorf("target confidence = %v, want unchanged 0.8", entry.Confidence)
+	}
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// Contradiction search defaults and bounds.
+const (
+	DefaultContradictionMinSimilarity = 0.85
+	DefaultContradictionLimit         = 20
+	MaxContradictionLimit             = 100
+)
+
+// negationWords mark an entry as advising against something.
+var negationWords = map[string]bool{
+	"not": true, "no": true, "never": true, "avoid": true, "without": true,
+	"don't": true, "doesn't": true, "didn't": true, "isn't": true, "aren't": true,
+	"wasn't": true, "shouldn't": true, "mustn't": true, "won't": true,
+	"can't": true, "cannot": true,
+}
+
+// FindContradictions returns pairs of active entries in the same category
+// that are at least q.MinSimilarity similar but differ in polarity: one
+// contains a negation such as "never" or "don't" and the other does not.
+// Pairs are ordered most similar first and capped at q.Limit.
+//
+// Polarity is a cheap heuristic, so results are candidates for a human or
+// language model to confirm rather than verdicts.
+func (s *SQLiteStore) FindContradictions(ctx context.Context, q types.ContradictionQuery) ([]types.Contradiction, error) {
+	categories := []string{q.Category}
+	if q.Category == "" {
+		var err error
+		if categories, err = s.embeddedCategories(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	found := make([]types.Contradiction, 0)
+	for _, category := range categories {
+		entries, err := s.embeddedEntries(ctx, category)
+		if err != nil {
+			return nil, err
+		}
+		negated := make([]bool, len(entries))
+		for i, e := range entries {
+			negated[i] = hasNegation(e.content)
+		}
+
+		for i, a := range entries {
+			qv := queryVector{v: a.embedding, norm: a.norm}
+			for j := i + 1; j < len(entries); j++ {
+				if negated[i] == negated[j] {
+					continue
+				}
+				b := entries[j]
+				sim := qv.similarity(b.embedding, b.norm)
+				if sim < q.MinSimilarity {
+					continue
+				}
+				found = append(found, types.Contradiction{
+					Category:   category,
+					Similarity: sim,
+					A:          types.ContradictionEntry{ID: a.id, Content: a.content, Confidence: a.confidence},
+					B:          types.ContradictionEntry{ID: b.id, Content: b.content, Confidence: b.confidence},
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Similarity > found[j].Similarity
+	})
+	if q.Limit > 0 && len(found) > q.Limit {
+		found = found[:q.Limit]
+	}
+	return found, nil
+}
+
+// hasNegation reports whether content contains a negation word.
+func hasNegation(content string) bool {
+	words := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\'' && r != '’'
+	})
+	for _, w := range words {
+		if negationWords[strings.ReplaceAll(w, "’", "'")] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestFindContradictions_PairsSimilarEntriesOfOppositePolarity(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	emb := makeTestEmbedding(1)
+	always := insertEntryWithEmbedding(t, s, "Always retry payment webhooks", "PATTERN_OUTCOME", emb)
+	never := insertEntryWithEmbedding(t, s, "Don't retry payment webhooks", "PATTERN_OUTCOME", makeIdenticalEmbedding(emb))
+	insertEntryWithEmbedding(t, s, "Retry payment webhooks with backoff", "PATTERN_OUTCOME", makeIdenticalEmbedding(emb))
+	insertEntryWithEmbedding(t, s, "Never log card numbers", "PATTERN_OUTCOME", makeOrthogonalEmbedding(emb))
+	insertEntryWithEmbedding(t, s, "Never retry payment webhooks", "TESTING_STRATEGY", makeIdenticalEmbedding(emb))
+
+	found, err := s.FindContradictions(ctx, types.ContradictionQuery{MinSimilarity: 0.85, Category: "PATTERN_OUTCOME"})
+	if err != nil {
+		t.Fatalf("FindContradictions() error = %v", err)
+	}
+	// "Don't retry" opposes both positive entries; same-polarity and
+	// dissimilar pairs are skipped, as is the other category.
+	if len(found) != 2 {
+		t.Fatalf("got %d contradictions, want 2: %+v", len(found), found)
+	}
+	if c := found[0]; c.A.ID != always || c.B.ID != never || c.Category != "PATTERN_OUTCOME" || c.Similarity < 0.85 {
+		t.Errorf("unexpected first contradiction: %+v", c)
+	}
+
+	all, err := s.FindContradictions(ctx, types.ContradictionQuery{MinSimilarity: 0.85, Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Errorf("across categories got %d, want 2 (the other category has no opposing entry)", len(all))
+	}
+}
+
+func TestHasNegation(t *testing.T) {
+	tests := map[string]bool{
+		"Never retry webhooks":        true,
+		"Don’t cache tokens":          true,
+		"It is not safe to retry":     true,
+		"Retry with backoff":          false,
+		"Notify the on-call engineer": false,
+	}
+	for content, want := range tests {
+		if got := hasNegation(content); got != want {
+			t.Errorf("hasNegation(%q) = %v, want %v", content, got, want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// Digest summarizes store activity in [start, end): lore created, the most
+// common categories among it, and the entries that received the most helpful
+// and incorrect feedback. Each list holds at most limit items. Deleted
+// entries are excluded.
+func (s *SQLiteStore) Digest(ctx context.Context, start, end time.Time, limit int) (*types.Digest, error) {
+	startStr := formatTime(start)
+	endStr := formatTime(end)
+
+	d := &types.Digest{
+		Start:            start.UTC(),
+		End:              end.UTC(),
+		TopCategories:    []types.CategoryCount{},
+		MostValidated:    []types.DigestEntry{},
+		FlaggedIncorrect: []types.DigestEntry{},
+	}
+
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM lore_entries
+		WHERE deleted_at IS NULL AND created_at >= ? AND created_at < ?
+	`, startStr, endStr).Scan(&d.NewLore); err != nil {
+		return nil, fmt.Errorf("count new lore: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT category, COUNT(*) AS n FROM lore_entries
+		WHERE deleted_at IS NULL AND created_at >= ? AND created_at < ?
+		GROUP BY category
+		ORDER BY n DESC, category ASC
+		LIMIT ?
+	`, startStr, endStr, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query top categories: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c types.CategoryCount
+		if err := rows.Scan(&c.Category, &c.Count); err != nil {
+			return nil, fmt.Errorf("scan category count: %w", err)
+		}
+		d.TopCategories = append(d.TopCategories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate top categories: %w", err)
+	}
+
+	if d.MostValidated, err = s.digestFeedbackLeaders(ctx, string(types.FeedbackHelpful), startStr, endStr, limit); err != nil {
+		return nil, err
+	}
+	if d.FlaggedIncorrect, err = s.digestFeedbackLeaders(ctx, string(types.FeedbackIncorrect), startStr, endStr, limit); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// digestFeedbackLeaders returns the entries with the most feedback of the
+// given type in the window.
+func (s *SQLiteStore) digestFeedbackLeaders(ctx context.Context, feedbackType, startStr, endStr string, limit int) ([]types.DigestEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT l.id, l.content, l.category, l.confidence, COUNT(*) AS n
+		FROM feedback_events f
+		JOIN lore_entries l ON l.id = f.lore_id
+		WHERE f.type = ? AND f.created_at >= ? AND f.created_at < ?
+		  AND l.deleted_at IS NULL
+		GROUP BY l.id
+		ORDER BY n DESC, l.confidence DESC, l.id ASC
+		LIMIT ?
+	`, feedbackType, startStr, endStr, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query %s feedback leaders: %w", feedbackType, err)
+	}
+	defer rows.Close()
+
+	entries := make([]types.DigestEntry, 0)
+	for rows.Next() {
+		var e types.DigestEntry
+		if err := rows.Scan(&e.ID, &e.Content, &e.Category, &e.Confidence, &e.Count); err != nil {
+			return nil, fmt.Errorf("scan digest entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
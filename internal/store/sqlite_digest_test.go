@@ -0,0 +1,140 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func digestTestLoreID(t *testing.T, s *SQLiteStore, content string) string {
+	t.Helper()
+	var id string
+	if err := s.db.QueryRow(`SELECT id FROM lore_entries WHERE content = ?`, content).Scan(&id); err != nil {
+		t.Fatalf("lookup %q: %v", content, err)
+	}
+	return id
+}
+
+func TestMigration007_CreatesFeedbackEvents(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, err := db.Exec(`SELECT id, lore_id, type, created_at FROM feedback_events LIMIT 0`); err != nil {
+		t.Fatalf("feedback_events table missing or has wrong columns: %v", err)
+	}
+}
+
+func TestRecordFeedback_LogsFeedbackEvents(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	if _, err := s.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Test lore", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	loreID := digestTestLoreID(t, s, "Test lore")
+
+	if _, err := s.RecordFeedback(ctx, []types.FeedbackEntry{
+		{LoreID: loreID, Type: "incorrect", SourceID: "client-1"},
+		{LoreID: "missing", Type: "helpful", SourceID: "client-1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var n int
+	var typ string
+	if err := s.db.QueryRow(`SELECT COUNT(*), MAX(type) FROM feedback_events WHERE lore_id = ?`, loreID).Scan(&n, &typ); err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || typ != "incorrect" {
+		t.Errorf("feedback_events = %d rows of %q, want 1 incorrect", n, typ)
+	}
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM feedback_events`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("expected skipped feedback not to be logged, got %d rows", n)
+	}
+}
+
+func TestDigest_SummarizesWindow(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := s.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Retry with backoff", Category: "PATTERN_OUTCOME", Confidence: 0.6, SourceID: "src"},
+		{Content: "Cache invalidation", Category: "PATTERN_OUTCOME", Confidence: 0.6, SourceID: "src"},
+		{Content: "Wrong port", Category: "DEPENDENCY_BEHAVIOR", Confidence: 0.6, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	retry := digestTestLoreID(t, s, "Retry with backoff")
+	cache := digestTestLoreID(t, s, "Cache invalidation")
+	wrong := digestTestLoreID(t, s, "Wrong port")
+
+	if _, err := s.RecordFeedback(ctx, []types.FeedbackEntry{
+		{LoreID: retry, Type: "helpful"},
+		{LoreID: retry, Type: "helpful"},
+		{LoreID: cache, Type: "helpful"},
+		{LoreID: wrong, Type: "incorrect"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// An entry created before the window does not count as new
+	if _, err := s.db.Exec(`UPDATE lore_entries SET created_at = ? WHERE id = ?`,
+		time.Now().Add(-48*time.Hour).UTC().Format(time.RFC3339), cache); err != nil {
+		t.Fatal(err)
+	}
+
+	end := time.Now().Add(time.Minute)
+	d, err := s.Digest(ctx, end.Add(-24*time.Hour), end, 5)
+	if err != nil {
+		t.Fatalf("Digest() error = %v", err)
+	}
+
+	if d.NewLore != 2 {
+		t.Errorf("NewLore = %d, want 2", d.NewLore)
+	}
+	if len(d.TopCategories) != 2 || d.TopCategories[0].Count != 1 {
+		t.Errorf("TopCategories = %+v, want two categories with one entry each", d.TopCategories)
+	}
+	if len(d.MostValidated) != 2 || d.MostValidated[0].ID != retry || d.MostValidated[0].Count != 2 {
+		t.Errorf("MostValidated = %+v, want %s first with 2", d.MostValidated, retry)
+	}
+	if len(d.FlaggedIncorrect) != 1 || d.FlaggedIncorrect[0].ID != wrong || d.FlaggedIncorrect[0].Content != "Wrong port" {
+		t.Errorf("FlaggedIncorrect = %+v, want %s", d.FlaggedIncorrect, wrong)
+	}
+}
+
+func TestDigest_LimitsAndEmptyWindow(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	for _, c := range []string{"A", "B", "C"} {
+		if _, err := s.IngestLore(ctx, []types.NewLoreEntry{
+			{Content: "entry " + c, Category: "CATEGORY_" + c, Confidence: 0.5, SourceID: "src"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	end := time.Now().Add(time.Minute)
+	d, err := s.Digest(ctx, end.Add(-time.Hour), end, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.TopCategories) != 2 {
+		t.Errorf("TopCategories has %d items, want limit 2", len(d.TopCategories))
+	}
+
+	past := time.Now().Add(-72 * time.Hour)
+	d, err = s.Digest(ctx, past, past.Add(24*time.Hour), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.NewLore != 0 || len(d.TopCategories) != 0 || d.MostValidated == nil || d.FlaggedIncorrect == nil {
+		t.Errorf("expected empty digest with non-nil lists, got %+v", d)
+	}
+}
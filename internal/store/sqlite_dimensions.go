@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// embeddingDimensionsKey is the store_metadata key recording the dimension
+// of the store's embeddings.
+const embeddingDimensionsKey = "embedding_dimensions"
+
+// loadEmbeddingDimensions returns the dimension recorded for the store's
+// embeddings. A store that predates the record adopts the dimension of an
+// embedding it holds. Zero means the store has no embedding yet.
+func loadEmbeddingDimensions(db *sql.DB) (int, error) {
+	var value string
+	err := db.QueryRow(`SELECT value FROM store_metadata WHERE key = ?`, embeddingDimensionsKey).Scan(&value)
+	if err == nil {
+		return strconv.Atoi(value)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	var blob []byte
+	err = db.QueryRow(`SELECT embedding FROM lore_embeddings LIMIT 1`).Scan(&blob)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	dims := len(unpackEmbedding(blob))
+	if _, err := db.Exec(`INSERT OR IGNORE INTO store_metadata (key, value) VALUES (?, ?)`,
+		embeddingDimensionsKey, strconv.Itoa(dims)); err != nil {
+		return 0, err
+	}
+	return dims, nil
+}
+
+// acceptEmbedding checks that embedding has the store's dimension before it
+// is stored. The first embedding of a store without one sets it.
+func (s *SQLiteStore) acceptEmbedding(ctx context.Context, embedding []float32) error {
+	if s.dims.Load() == 0 {
+		if _, err := s.db.ExecContext(ctx, `INSERT OR IGNORE INTO store_metadata (key, value) VALUES (?, ?)`,
+			embeddingDimensionsKey, strconv.Itoa(len(embedding))); err != nil {
+			return fmt.Errorf("record embedding dimensions: %w", err)
+		}
+		// Another writer may have recorded first
+		var value string
+		if err := s.db.QueryRowContext(ctx, `SELECT value FROM store_metadata WHERE key = ?`,
+			embeddingDimensionsKey).Scan(&value); err != nil {
+			return fmt.Errorf("read embedding dimensions: %w", err)
+		}
+		dims, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("parse embedding dimensions: %w", err)
+		}
+		s.dims.Store(int64(dims))
+	}
+	return s.checkDimensions(embedding)
+}
+
+// checkDimensions returns ErrDimensionMismatch, and counts the mismatch,
+// when embedding differs in dimension from the store's embeddings. Any
+// embedding passes while the store has none.
+func (s *SQLiteStore) checkDimensions(embedding []float32) error {
+	expected := int(s.dims.Load())
+	if expected == 0 || len(embedding) == expected {
+		return nil
+	}
+	s.dimMismatches.Add(1)
+	s.lastMismatch.Store(int64(len(embedding)))
+	return fmt.Errorf("%w: got %d dimensions, store has %d", ErrDimensionMismatch, len(embedding), expected)
+}
+
+// acceptEmbeddings clears the embeddings among the first n of embeddings
+// that have the wrong dimension, so their entries are stored pending, and
+// returns how many it cleared.
+func (s *SQLiteStore) acceptEmbeddings(ctx context.Context, embeddings [][]float32, n int) (int, error) {
+	cleared := 0
+	var mismatch error
+	for i := range min(n, len(embeddings)) {
+		if len(embeddings[i]) == 0 {
+			continue
+		}
+		err := s.acceptEmbedding(ctx, embeddings[i])
+		if errors.Is(err, ErrDimensionMismatch) {
+			embeddings[i] = nil
+			cleared++
+			mismatch = err
+			continue
+		}
+		if err != nil {
+			return cleared, err
+		}
+	}
+	if cleared > 0 {
+		slog.Error("embedder returned vectors of the wrong dimension, entries will be stored pending; was the embedding model changed?",
+			"component", "store",
+			"store_id", s.storeID,
+			"count", cleared,
+			"error", mismatch,
+		)
+	}
+	return cleared, nil
+}
+
+// EmbeddingDimensions reports the dimension of the store's embeddings and
+// the embeddings refused for another dimension since the store opened.
+func (s *SQLiteStore) EmbeddingDimensions() types.EmbeddingDimensions {
+	return types.EmbeddingDimensions{
+		Expected:   int(s.dims.Load()),
+		Mismatches: s.dimMismatches.Load(),
+		LastSeen:   int(s.lastMismatch.Load()),
+	}
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestEmbeddingDimensions_ModelSwitch(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	s.SetDependencies(&mockEmbedder{}, &mockConfig{})
+
+	if got := s.EmbeddingDimensions(); got.Expected != 0 || got.Mismatches != 0 {
+		t.Fatalf("EmbeddingDimensions() of an empty store = %+v", got)
+	}
+	if _, err := s.IngestLore(ctx, []types.NewLoreEntry{{
+		Content: "Embedded by the original model", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src",
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.EmbeddingDimensions().Expected; got != 1536 {
+		t.Fatalf("Expected = %d after the first embedding, want 1536", got)
+	}
+	var recorded string
+	if err := s.db.QueryRow(`SELECT value FROM store_metadata WHERE key = 'embedding_dimensions'`).Scan(&recorded); err != nil || recorded != "1536" {
+		t.Errorf("store_metadata embedding_dimensions = %q, %v; want 1536", recorded, err)
+	}
+
+	// The model is switched to one with smaller vectors
+	small := []float32{1, 0, 0}
+	s.SetDependencies(&mockEmbedder{embeddings: map[string][]float32{"Embedded by the new model": small}}, &mockConfig{})
+	result, err := s.IngestLore(ctx, []types.NewLoreEntry{{
+		Content: "Embedded by the new model", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src",
+	}})
+	if err != nil {
+		t.Fatalf("IngestLore() error = %v", err)
+	}
+	if result.Accepted != 1 {
+		t.Fatalf("Accepted = %d, want the entry kept", result.Accepted)
+	}
+	pending, err := s.GetPendingEmbeddings(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].ID != result.Results[0].LoreID {
+		t.Fatalf("pending = %d entries, want the mismatched entry", len(pending))
+	}
+
+	if err := s.UpdateEmbedding(ctx, pending[0].ID, small); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("UpdateEmbedding() error = %v, want ErrDimensionMismatch", err)
+	}
+	if _, err := s.FindSimilar(ctx, small, "PATTERN_OUTCOME", 0.5); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("FindSimilar() error = %v, want ErrDimensionMismatch", err)
+	}
+	_, err = s.SearchLore(ctx, types.SearchQuery{Text: "model", Embedding: small, Mode: types.SearchModeSemantic})
+	if !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("SearchLore() error = %v, want ErrDimensionMismatch", err)
+	}
+
+	got := s.EmbeddingDimensions()
+	want := types.EmbeddingDimensions{Expected: 1536, Mismatches: 4, LastSeen: 3}
+	if got != want {
+		t.Errorf("EmbeddingDimensions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestEmbeddingDimensions_InferredForExistingStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lore.db")
+	s, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	insertEntryWithEmbedding(t, s, "Stored before dimensions were recorded", "PATTERN_OUTCOME", []float32{1, 0, 0, 0})
+	if _, err := s.db.Exec(`DELETE FROM store_metadata WHERE key = 'embedding_dimensions'`); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if got := s.EmbeddingDimensions().Expected; got != 4 {
+		t.Errorf("Expected = %d, want 4 from the stored embedding", got)
+	}
+	var recorded string
+	if err := s.db.QueryRow(`SELECT value FROM store_metadata WHERE key = 'embedding_dimensions'`).Scan(&recorded); err != nil || recorded != "4" {
+		t.Errorf("store_metadata embedding_dimensions = %q, %v; want 4", recorded, err)
+	}
+}
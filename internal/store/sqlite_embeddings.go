@@ -0,0 +1,208 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hyperengineering/engram/migrations"
+)
+
+// embeddingsMigration is the migration that moved entry embeddings out of
+// lore_entries into lore_embeddings.
+const embeddingsMigration = "023_lore_embeddings.sql"
+
+// storeEmbeddingInTx writes the embedding of a lore entry to
+// lore_embeddings with quantization q, replacing any it had; an empty
+// embedding removes it.
+func storeEmbeddingInTx(ctx context.Context, execer execContext, loreID string, embedding []float32, q Quantization) error {
+	if len(embedding) == 0 {
+		if _, err := execer.ExecContext(ctx, `DELETE FROM lore_embeddings WHERE lore_id = ?`, loreID); err != nil {
+			return fmt.Errorf("clear embedding: %w", err)
+		}
+		return nil
+	}
+	_, err := execer.ExecContext(ctx, `
+		INSERT OR REPLACE INTO lore_embeddings (lore_id, embedding) VALUES (?, ?)
+	`, loreID, q.pack(embedding))
+	if err != nil {
+		return fmt.Errorf("store embedding: %w", err)
+	}
+	return nil
+}
+
+// inlineSnapshotEmbeddings moves the embeddings of a snapshot copy back
+// into an embedding column of lore_entries, where the snapshot schema
+// contract puts them for clients as float32 values, and drops
+// lore_embeddings. Quantized embeddings are dequantized on the way.
+//
+// lore_entries is rebuilt with embedding after confidence, where it was
+// before embeddings moved out, as clients may read columns by position.
+func inlineSnapshotEmbeddings(ctx context.Context, path string) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := rebuildWithEmbedding(ctx, tx); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DROP TABLE lore_embeddings`); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, embedding FROM lore_entries WHERE length(embedding) % 4 != 0
+	`)
+	if err != nil {
+		return err
+	}
+	quantized := make(map[string][]byte)
+	for rows.Next() {
+		var id string
+		var blob []byte
+		if err := rows.Scan(&id, &blob); err != nil {
+			rows.Close()
+			return err
+		}
+		quantized[id] = packEmbedding(unpackEmbedding(blob))
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+	for id, blob := range quantized {
+		if _, err := tx.ExecContext(ctx, `UPDATE lore_entries SET embedding = ? WHERE id = ?`, blob, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// rebuildWithEmbedding recreates lore_entries with an embedding column
+// after confidence, filled from lore_embeddings. Its other columns, indexes
+// and triggers are carried over as they are.
+func rebuildWithEmbedding(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `SELECT name, type, "notnull", dflt_value, pk FROM pragma_table_info('lore_entries')`)
+	if err != nil {
+		return err
+	}
+	var defs, names []string
+	for rows.Next() {
+		var name, typ string
+		var notNull, pk int
+		var dflt sql.NullString
+		if err := rows.Scan(&name, &typ, &notNull, &dflt, &pk); err != nil {
+			rows.Close()
+			return err
+		}
+		def := name + " " + typ
+		if pk > 0 {
+			def += " PRIMARY KEY"
+		}
+		if notNull != 0 {
+			def += " NOT NULL"
+		}
+		if dflt.Valid {
+			def += " DEFAULT " + dflt.String
+		}
+		defs = append(defs, def)
+		names = append(names, name)
+		if name == "confidence" {
+			defs = append(defs, "embedding BLOB")
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+	if len(defs) == len(names) {
+		return fmt.Errorf("lore_entries has no confidence column")
+	}
+
+	var schema []string
+	rows, err = tx.QueryContext(ctx, `
+		SELECT sql FROM sqlite_master
+		WHERE tbl_name = 'lore_entries' AND type IN ('index', 'trigger') AND sql IS NOT NULL
+	`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var stmt string
+		if err := rows.Scan(&stmt); err != nil {
+			rows.Close()
+			return err
+		}
+		schema = append(schema, stmt)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	columns := strings.Join(names, ", ")
+	stmts := []string{
+		`CREATE TABLE lore_entries_rebuilt (` + strings.Join(defs, ", ") + `)`,
+		`INSERT INTO lore_entries_rebuilt (` + columns + `, embedding)
+		 SELECT ` + columns + `, (SELECT embedding FROM lore_embeddings WHERE lore_id = lore_entries.id)
+		 FROM lore_entries`,
+		`DROP TABLE lore_entries`,
+		`ALTER TABLE lore_entries_rebuilt RENAME TO lore_entries`,
+	}
+	for _, stmt := range append(stmts, schema...) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreEmbeddingsTable undoes inlineSnapshotEmbeddings in a database such
+// as a restored snapshot, by reapplying the migration that created
+// lore_embeddings. A database whose schema predates that migration is left
+// for the migration itself to move when the store opens.
+func restoreEmbeddingsTable(ctx context.Context, path string) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, pending, err := pendingCoreMigrations(db)
+	if err != nil {
+		return err
+	}
+	for _, m := range pending {
+		if m.Name == embeddingsMigration {
+			return nil
+		}
+	}
+	var exists int
+	if err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'lore_embeddings'
+	`).Scan(&exists); err != nil {
+		return err
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	script, err := migrations.FS.ReadFile(embeddingsMigration)
+	if err != nil {
+		return err
+	}
+	up, _, _ := strings.Cut(string(script), "-- +goose Down")
+	_, err = db.ExecContext(ctx, up)
+	return err
+}
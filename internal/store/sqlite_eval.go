@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// PutEvalSet stores an evaluation set under its name, replacing the cases
+// and description of any set already stored there. It returns the set as
+// stored, with its creation time kept from the set it replaced.
+func (s *SQLiteStore) PutEvalSet(ctx context.Context, set types.EvalSet) (*types.EvalSet, error) {
+	cases, err := json.Marshal(set.Cases)
+	if err != nil {
+		return nil, fmt.Errorf("encode eval cases: %w", err)
+	}
+	now := formatTime(time.Now())
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO eval_sets (name, description, cases, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			description = excluded.description,
+			cases = excluded.cases,
+			updated_at = excluded.updated_at
+	`, set.Name, set.Description, string(cases), now, now); err != nil {
+		return nil, fmt.Errorf("store eval set: %w", err)
+	}
+	return s.GetEvalSet(ctx, set.Name)
+}
+
+// GetEvalSet returns the evaluation set stored under name. Returns
+// ErrEvalSetNotFound if there is none.
+func (s *SQLiteStore) GetEvalSet(ctx context.Context, name string) (*types.EvalSet, error) {
+	var set types.EvalSet
+	var cases, createdAt, updatedAt string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT name, description, cases, created_at, updated_at FROM eval_sets WHERE name = ?
+	`, name).Scan(&set.Name, &set.Description, &cases, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEvalSetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query eval set: %w", err)
+	}
+	if err := json.Unmarshal([]byte(cases), &set.Cases); err != nil {
+		return nil, fmt.Errorf("decode eval cases: %w", err)
+	}
+	set.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+	set.UpdatedAt, _ = time.Parse(time.RFC3339Nano, updatedAt)
+	return &set, nil
+}
+
+// ListEvalSets returns a summary of every stored evaluation set, ordered
+// by name.
+func (s *SQLiteStore) ListEvalSets(ctx context.Context) ([]types.EvalSetSummary, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT name, description, json_array_length(cases), created_at, updated_at
+		FROM eval_sets ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query eval sets: %w", err)
+	}
+	defer rows.Close()
+
+	sets := make([]types.EvalSetSummary, 0)
+	for rows.Next() {
+		var set types.EvalSetSummary
+		var createdAt, updatedAt string
+		if err := rows.Scan(&set.Name, &set.Description, &set.Cases, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scan eval set: %w", err)
+		}
+		set.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+		set.UpdatedAt, _ = time.Parse(time.RFC3339Nano, updatedAt)
+		sets = append(sets, set)
+	}
+	return sets, rows.Err()
+}
+
+// DeleteEvalSet removes the evaluation set stored under name. Returns
+// ErrEvalSetNotFound if there is none.
+func (s *SQLiteStore) DeleteEvalSet(ctx context.Context, name string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM eval_sets WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("delete eval set: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrEvalSetNotFound
+	}
+	return nil
+}
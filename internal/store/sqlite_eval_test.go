@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestEvalSets_PutGetListDelete(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	set := types.EvalSet{
+		Name:        "golden",
+		Description: "Queries from the payments team",
+		Cases: []types.EvalCase{
+			{Query: "payment retries", Relevant: []string{"01ARZ3NDEKTSV4RRFFQ69G5FAV"}, Category: "PATTERN_OUTCOME"},
+			{Query: "cache warmup", Relevant: []string{"01ARZ3NDEKTSV4RRFFQ69G5FAW", "01ARZ3NDEKTSV4RRFFQ69G5FAX"}},
+		},
+	}
+	stored, err := s.PutEvalSet(ctx, set)
+	if err != nil {
+		t.Fatalf("PutEvalSet() error = %v", err)
+	}
+	if len(stored.Cases) != 2 || stored.Cases[1].Relevant[1] != "01ARZ3NDEKTSV4RRFFQ69G5FAX" || stored.CreatedAt.IsZero() {
+		t.Errorf("stored set = %+v", stored)
+	}
+
+	// Replacing keeps the creation time
+	time.Sleep(time.Millisecond)
+	set.Cases = set.Cases[:1]
+	replaced, err := s.PutEvalSet(ctx, set)
+	if err != nil {
+		t.Fatalf("PutEvalSet() replace error = %v", err)
+	}
+	if len(replaced.Cases) != 1 || !replaced.CreatedAt.Equal(stored.CreatedAt) || !replaced.UpdatedAt.After(stored.UpdatedAt) {
+		t.Errorf("replaced set = %+v, first stored %+v", replaced, stored)
+	}
+
+	list, err := s.ListEvalSets(ctx)
+	if err != nil {
+		t.Fatalf("ListEvalSets() error = %v", err)
+	}
+	if len(list) != 1 || list[0].Name != "golden" || list[0].Cases != 1 || list[0].Description != set.Description {
+		t.Errorf("ListEvalSets() = %+v", list)
+	}
+
+	if err := s.DeleteEvalSet(ctx, "golden"); err != nil {
+		t.Fatalf("DeleteEvalSet() error = %v", err)
+	}
+	if _, err := s.GetEvalSet(ctx, "golden"); !errors.Is(err, ErrEvalSetNotFound) {
+		t.Errorf("GetEvalSet() after delete error = %v, want ErrEvalSetNotFound", err)
+	}
+	if err := s.DeleteEvalSet(ctx, "golden"); !errors.Is(err, ErrEvalSetNotFound) {
+		t.Errorf("DeleteEvalSet() twice error = %v, want ErrEvalSetNotFound", err)
+	}
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+// EnableEventOutbox turns on capture of change_log entries into the event
+// outbox. Entries written before capture is enabled are not published.
+func (s *SQLiteStore) EnableEventOutbox(ctx context.Context) error {
+	return s.SetSyncMeta(ctx, "event_outbox_enabled", "1")
+}
+
+// DisableEventOutbox turns off capture of change_log entries into the event
+// outbox and discards any entries still waiting, as nothing will publish
+// them.
+func (s *SQLiteStore) DisableEventOutbox(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT OR REPLACE INTO sync_meta (key, value) VALUES ('event_outbox_enabled', '0')
+	`); err != nil {
+		return fmt.Errorf("disable event outbox: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM event_outbox`); err != nil {
+		return fmt.Errorf("clear event outbox: %w", err)
+	}
+	return tx.Commit()
+}
+
+// PruneEventOutbox removes undelivered outbox entries received before
+// cutoff, so an outbox whose broker stays unreachable does not grow without
+// bound. Returns the number of entries removed.
+func (s *SQLiteStore) PruneEventOutbox(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM event_outbox WHERE received_at < ?`, formatTime(cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("prune event outbox: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// FetchEventOutbox returns up to limit undelivered outbox entries, oldest first.
+func (s *SQLiteStore) FetchEventOutbox(ctx context.Context, limit int) ([]engramsync.OutboxEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, sequence, table_name, entity_id, operation, payload, source_id, created_at, received_at, source_sequence, attempts
+		FROM event_outbox
+		ORDER BY id ASC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("fetch event outbox: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]engramsync.OutboxEntry, 0)
+	for rows.Next() {
+		var o engramsync.OutboxEntry
+		var payload sql.NullString
+		var sourceSeq sql.NullInt64
+		var createdAt, receivedAt string
+
+		if err := rows.Scan(&o.ID, &o.Entry.Sequence, &o.Entry.TableName, &o.Entry.EntityID,
+			&o.Entry.Operation, &payload, &o.Entry.SourceID, &createdAt, &receivedAt, &sourceSeq, &o.Attempts); err != nil {
+			return nil, fmt.Errorf("scan event outbox entry: %w", err)
+		}
+		o.Entry.SourceSequence = sourceSeq.Int64
+		if payload.Valid {
+			o.Entry.Payload = json.RawMessage(payload.String)
+		}
+		var parseErr error
+		if o.Entry.CreatedAt, parseErr = time.Parse(time.RFC3339Nano, createdAt); parseErr != nil {
+			slog.Warn("event_outbox: failed to parse created_at", "value", createdAt, "error", parseErr)
+		}
+		if o.Entry.ReceivedAt, parseErr = time.Parse(time.RFC3339Nano, receivedAt); parseErr != nil {
+			slog.Warn("event_outbox: failed to parse received_at", "value", receivedAt, "error", parseErr)
+		}
+		entries = append(entries, o)
+	}
+	return entries, rows.Err()
+}
+
+// AckEventOutbox removes delivered outbox entries with id <= throughID.
+func (s *SQLiteStore) AckEventOutbox(ctx context.Context, throughID int64) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM event_outbox WHERE id <= ?`, throughID); err != nil {
+		return fmt.Errorf("ack event outbox: %w", err)
+	}
+	return nil
+}
+
+// RecordEventOutboxFailure increments an entry's attempt count and stores
+// the last delivery error.
+func (s *SQLiteStore) RecordEventOutboxFailure(ctx context.Context, id int64, reason string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE event_outbox SET attempts = attempts + 1, last_error = ? WHERE id = ?
+	`, reason, id)
+	if err != nil {
+		return fmt.Errorf("record event outbox failure: %w", err)
+	}
+	return nil
+}
+
+// EventOutboxDepth returns the number of undelivered outbox entries.
+func (s *SQLiteStore) EventOutboxDepth(ctx context.Context) (int64, error) {
+	var n int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM event_outbox`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count event outbox: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,183 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+func appendOutboxTestEntry(t *testing.T, s *SQLiteStore, entityID string) int64 {
+	t.Helper()
+	seq, err := s.AppendChangeLog(context.Background(), &engramsync.ChangeLogEntry{
+		TableName: "lore_entries",
+		EntityID:  entityID,
+		Operation: engramsync.OperationUpsert,
+		Payload:   json.RawMessage(`{"id":"` + entityID + `"}`),
+		SourceID:  "source-1",
+		CreatedAt: time.Now().UTC(),
+
+		SourceSequence: 7,
+	})
+	if err != nil {
+		t.Fatalf("AppendChangeLog failed: %v", err)
+	}
+	return seq
+}
+
+func TestMigration006_CreatesEventOutbox(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, err := db.Exec(`SELECT id, sequence, payload, received_at, attempts, last_error FROM event_outbox LIMIT 0`); err != nil {
+		t.Fatalf("event_outbox table missing or has wrong columns: %v", err)
+	}
+	var enabled string
+	if err := db.QueryRow(`SELECT value FROM sync_meta WHERE key = 'event_outbox_enabled'`).Scan(&enabled); err != nil {
+		t.Fatalf("event_outbox_enabled not found: %v", err)
+	}
+	if enabled != "0" {
+		t.Errorf("event_outbox_enabled = %q, want '0'", enabled)
+	}
+}
+
+func TestEventOutbox_DisabledByDefault(t *testing.T) {
+	s := newTestStore(t)
+	appendOutboxTestEntry(t, s, "entity-1")
+
+	depth, err := s.EventOutboxDepth(context.Background())
+	if err != nil {
+		t.Fatalf("EventOutboxDepth failed: %v", err)
+	}
+	if depth != 0 {
+		t.Errorf("depth = %d, want 0 before capture is enabled", depth)
+	}
+}
+
+func TestEventOutbox_CapturesChangeLogWhenEnabled(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	if err := s.EnableEventOutbox(ctx); err != nil {
+		t.Fatalf("EnableEventOutbox failed: %v", err)
+	}
+
+	seq1 := appendOutboxTestEntry(t, s, "entity-1")
+	seq2 := appendOutboxTestEntry(t, s, "entity-2")
+
+	entries, err := s.FetchEventOutbox(ctx, 10)
+	if err != nil {
+		t.Fatalf("FetchEventOutbox failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 outbox entries, got %d", len(entries))
+	}
+	if entries[0].Entry.Sequence != seq1 || entries[1].Entry.Sequence != seq2 {
+		t.Errorf("sequences = %d,%d, want %d,%d", entries[0].Entry.Sequence, entries[1].Entry.Sequence, seq1, seq2)
+	}
+	if entries[0].Entry.EntityID != "entity-1" || string(entries[0].Entry.Payload) != `{"id":"entity-1"}` {
+		t.Errorf("unexpected entry: %+v", entries[0].Entry)
+	}
+	if entries[0].Entry.ReceivedAt.IsZero() {
+		t.Error("ReceivedAt should be copied from change_log")
+	}
+	if entries[0].Entry.SourceSequence != 7 {
+		t.Errorf("SourceSequence = %d, want 7", entries[0].Entry.SourceSequence)
+	}
+}
+
+func TestEventOutbox_AckAndFailure(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	if err := s.EnableEventOutbox(ctx); err != nil {
+		t.Fatalf("EnableEventOutbox failed: %v", err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		appendOutboxTestEntry(t, s, id)
+	}
+	entries, _ := s.FetchEventOutbox(ctx, 10)
+
+	// Ack the first two, fail the third
+	if err := s.AckEventOutbox(ctx, entries[1].ID); err != nil {
+		t.Fatalf("AckEventOutbox failed: %v", err)
+	}
+	if err := s.RecordEventOutboxFailure(ctx, entries[2].ID, "broker unavailable"); err != nil {
+		t.Fatalf("RecordEventOutboxFailure failed: %v", err)
+	}
+
+	remaining, err := s.FetchEventOutbox(ctx, 10)
+	if err != nil {
+		t.Fatalf("FetchEventOutbox failed: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].Entry.EntityID != "c" {
+		t.Fatalf("remaining = %+v, want only entity c", remaining)
+	}
+	if remaining[0].Attempts != 1 {
+		t.Errorf("attempts = %d, want 1", remaining[0].Attempts)
+	}
+}
+
+func TestEventOutbox_FetchRespectsLimit(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	s.EnableEventOutbox(ctx)
+	for _, id := range []string{"a", "b", "c"} {
+		appendOutboxTestEntry(t, s, id)
+	}
+
+	entries, err := s.FetchEventOutbox(ctx, 2)
+	if err != nil {
+		t.Fatalf("FetchEventOutbox failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Entry.EntityID != "a" {
+		t.Errorf("entries = %+v, want first 2 in order", entries)
+	}
+}
+
+func TestEventOutbox_Disable(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	if err := s.EnableEventOutbox(ctx); err != nil {
+		t.Fatalf("EnableEventOutbox failed: %v", err)
+	}
+	appendOutboxTestEntry(t, s, "entity-1")
+
+	if err := s.DisableEventOutbox(ctx); err != nil {
+		t.Fatalf("DisableEventOutbox failed: %v", err)
+	}
+	appendOutboxTestEntry(t, s, "entity-2")
+
+	depth, err := s.EventOutboxDepth(ctx)
+	if err != nil {
+		t.Fatalf("EventOutboxDepth failed: %v", err)
+	}
+	if depth != 0 {
+		t.Errorf("depth = %d, want 0 after capture is disabled", depth)
+	}
+}
+
+func TestEventOutbox_Prune(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	if err := s.EnableEventOutbox(ctx); err != nil {
+		t.Fatalf("EnableEventOutbox failed: %v", err)
+	}
+	appendOutboxTestEntry(t, s, "old")
+	appendOutboxTestEntry(t, s, "new")
+	if _, err := s.DB().Exec(`UPDATE event_outbox SET received_at = ? WHERE entity_id = 'old'`,
+		formatTime(time.Now().Add(-48*time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+
+	pruned, err := s.PruneEventOutbox(ctx, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("PruneEventOutbox failed: %v", err)
+	}
+	if pruned != 1 {
+		t.Errorf("pruned = %d, want 1", pruned)
+	}
+	remaining, _ := s.FetchEventOutbox(ctx, 10)
+	if len(remaining) != 1 || remaining[0].Entry.EntityID != "new" {
+		t.Errorf("remaining = %+v, want only entity new", remaining)
+	}
+}
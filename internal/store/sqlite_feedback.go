@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// Feedback history bounds.
+const (
+	DefaultFeedbackHistoryLimit = 50
+	MaxFeedbackHistoryLimit     = 500
+)
+
+// GetFeedbackHistory returns up to limit feedback events recorded for a lore
+// entry, newest first, starting after the event beforeID when it is
+// positive. Returns ErrNotFound if the entry doesn't exist or is deleted.
+func (s *SQLiteStore) GetFeedbackHistory(ctx context.Context, loreID string, beforeID int64, limit int) ([]types.FeedbackEvent, error) {
+	var exists int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT 1 FROM lore_entries WHERE id = ? AND deleted_at IS NULL
+	`, loreID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("check lore entry: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, type, note, created_at FROM feedback_events
+		WHERE lore_id = ? AND (? <= 0 OR id < ?)
+		ORDER BY id DESC
+		LIMIT ?
+	`, loreID, beforeID, beforeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query feedback events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]types.FeedbackEvent, 0)
+	for rows.Next() {
+		var (
+			e         types.FeedbackEvent
+			note      sql.NullString
+			createdAt string
+		)
+		if err := rows.Scan(&e.ID, &e.Type, &note, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan feedback event: %w", err)
+		}
+		e.Note = note.String
+		if e.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return nil, fmt.Errorf("parse feedback event time: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// newFeedbackTestStore opens a store with opts and ingests one lore entry
+// from source "author", returning the store and the entry's ID.
+func newFeedbackTestStore(t *testing.T, opts ...StoreOption) (*SQLiteStore, string) {
+	t.Helper()
+	s, err := NewSQLiteStore(":memory:", opts...)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	const id = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	if _, err := s.IngestLore(context.Background(), []types.NewLoreEntry{
+		{ID: id, Content: "Feedback test lore", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "author"},
+	}); err != nil {
+		t.Fatalf("IngestLore() error = %v", err)
+	}
+	return s, id
+}
+
+func TestGetFeedbackHistory_NewestFirstWithNotes(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	if _, err := s.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Postgres listens on 5432", Category: "DEPENDENCY_BEHAVIOR", Confidence: 0.6, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	delta, _ := s.GetDelta(ctx, time.Time{})
+	loreID := delta.Lore[0].ID
+
+	for _, fb := range []types.FeedbackEntry{
+		{LoreID: loreID, Type: "helpful"},
+		{LoreID: loreID, Type: "incorrect", Note: "compose file maps it to 5433"},
+		{LoreID: loreID, Type: "not_relevant"},
+	} {
+		if _, err := s.RecordFeedback(ctx, []types.FeedbackEntry{fb}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events, err := s.GetFeedbackHistory(ctx, loreID, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	if events[0].Type != "not_relevant" || events[1].Type != "incorrect" || events[2].Type != "helpful" {
+		t.Errorf("events not newest first: %+v", events)
+	}
+	if events[1].Note != "compose file maps it to 5433" || events[0].Note != "" {
+		t.Errorf("notes = %q, %q", events[0].Note, events[1].Note)
+	}
+	if events[0].CreatedAt.IsZero() {
+		t.Error("created_at should be set")
+	}
+
+	limited, err := s.GetFeedbackHistory(ctx, loreID, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(limited) != 1 || limited[0].Type != "not_relevant" {
+		t.Errorf("limit 1 = %+v, want only the newest event", limited)
+	}
+
+	older, err := s.GetFeedbackHistory(ctx, loreID, events[0].ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(older) != 2 || older[0].Type != "incorrect" || older[1].Type != "helpful" {
+		t.Errorf("events before the newest = %+v, want the two older ones", older)
+	}
+}
+
+func TestGetFeedbackHistory_NotFound(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := s.GetFeedbackHistory(ctx, "01ARZ3NDEKTSV4RRFFQ69G5FAV", 0, 10); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing entry: err = %v, want ErrNotFound", err)
+	}
+
+	if _, err := s.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Soon deleted", Category: "PATTERN_OUTCOME", Confidence: 0.6, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	delta, _ := s.GetDelta(ctx, time.Time{})
+	loreID := delta.Lore[0].ID
+	if err := s.DeleteLore(ctx, loreID, "src"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetFeedbackHistory(ctx, loreID, 0, 10); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleted entry: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetFeedbackHistory_NoEvents(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	if _, err := s.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Quiet entry", Category: "PATTERN_OUTCOME", Confidence: 0.6, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	delta, _ := s.GetDelta(ctx, time.Time{})
+
+	events, err := s.GetFeedbackHistory(ctx, delta.Lore[0].ID, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events == nil || len(events) != 0 {
+		t.Errorf("events = %#v, want empty slice", events)
+	}
+}
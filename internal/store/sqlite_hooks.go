@@ -0,0 +1,57 @@
+package store
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/hyperengineering/engram/internal/plugin"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// mergeEvent records a deduplication merge for post-commit hook dispatch.
+type mergeEvent struct {
+	merged   *types.LoreEntry
+	incoming types.NewLoreEntry
+}
+
+// runBeforeIngest passes each entry through the plugin's BeforeIngest hook.
+// Returns the entries that survived and their request indices, in their
+// original order. Vetoed entries are recorded in result as rejected with
+// the hook's message.
+// The caller's slice is never modified.
+func (s *SQLiteStore) runBeforeIngest(ctx context.Context, entries []types.NewLoreEntry, indices []int, result *types.IngestResult) ([]types.NewLoreEntry, []int) {
+	h, ok := s.hooks.(plugin.BeforeIngestHook)
+	if !ok {
+		return entries, indices
+	}
+
+	kept := make([]types.NewLoreEntry, 0, len(entries))
+	keptIndices := make([]int, 0, len(entries))
+	for i, entry := range entries {
+		if err := h.BeforeIngest(ctx, &entry); err != nil {
+			slog.Info("ingest entry rejected by plugin hook",
+				"component", "store",
+				"action", "before_ingest_rejected",
+				"store_id", s.storeID,
+				"source_id", entry.SourceID,
+				"error", err,
+			)
+			rejectEntry(result, indices[i], err.Error())
+			continue
+		}
+		kept = append(kept, entry)
+		keptIndices = append(keptIndices, indices[i])
+	}
+	return kept, keptIndices
+}
+
+// runAfterMerge notifies the plugin's AfterMerge hook of each merge.
+func (s *SQLiteStore) runAfterMerge(ctx context.Context, merges []mergeEvent) {
+	h, ok := s.hooks.(plugin.AfterMergeHook)
+	if !ok {
+		return
+	}
+	for _, m := range merges {
+		h.AfterMerge(ctx, m.merged, m.incoming)
+	}
+}
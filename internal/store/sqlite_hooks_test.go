@@ -0,0 +1,209 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/plugin"
+	"github.com/hyperengineering/engram/internal/sync"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// hookPlugin is a minimal DomainPlugin implementing all optional hooks.
+type hookPlugin struct {
+	beforeIngest func(entry *types.NewLoreEntry) error
+	beforeDelete func(id string) error
+	merges       []string // merged entry IDs observed by AfterMerge
+}
+
+func (p *hookPlugin) Type() string                   { return "hooked" }
+func (p *hookPlugin) Migrations() []plugin.Migration { return nil }
+func (p *hookPlugin) TableSchemas() []plugin.TableSchema {
+	return nil
+}
+func (p *hookPlugin) ValidatePush(ctx context.Context, entries []sync.ChangeLogEntry) ([]sync.ChangeLogEntry, error) {
+	return entries, nil
+}
+func (p *hookPlugin) OnReplay(ctx context.Context, store plugin.ReplayStore, entries []sync.ChangeLogEntry) error {
+	return nil
+}
+
+func (p *hookPlugin) BeforeIngest(ctx context.Context, entry *types.NewLoreEntry) error {
+	if p.beforeIngest == nil {
+		return nil
+	}
+	return p.beforeIngest(entry)
+}
+
+func (p *hookPlugin) AfterMerge(ctx context.Context, merged *types.LoreEntry, incoming types.NewLoreEntry) {
+	p.merges = append(p.merges, merged.ID)
+}
+
+func (p *hookPlugin) BeforeDelete(ctx context.Context, id string) error {
+	if p.beforeDelete == nil {
+		return nil
+	}
+	return p.beforeDelete(id)
+}
+
+func TestIngestLore_BeforeIngestHook_NormalizesEntry(t *testing.T) {
+	hooks := &hookPlugin{beforeIngest: func(e *types.NewLoreEntry) error {
+		e.Content = strings.TrimSpace(e.Content)
+		return nil
+	}}
+	db, err := NewSQLiteStore(":memory:", WithPluginHooks(hooks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	entries := []types.NewLoreEntry{{Content: "  padded content  ", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "s"}}
+	if _, err := db.IngestLore(context.Background(), entries); err != nil {
+		t.Fatal(err)
+	}
+
+	var content string
+	if err := db.db.QueryRow("SELECT content FROM lore_entries").Scan(&content); err != nil {
+		t.Fatal(err)
+	}
+	if content != "padded content" {
+		t.Errorf("content = %q, want normalized %q", content, "padded content")
+	}
+	if entries[0].Content != "  padded content  " {
+		t.Errorf("caller's slice was modified: %q", entries[0].Content)
+	}
+}
+
+func TestIngestLore_BeforeIngestHook_VetoRejectsEntry(t *testing.T) {
+	hooks := &hookPlugin{beforeIngest: func(e *types.NewLoreEntry) error {
+		if strings.Contains(e.Content, "secret") {
+			return fmt.Errorf("%w: content contains a secret", plugin.ErrVetoed)
+		}
+		return nil
+	}}
+	db, err := NewSQLiteStore(":memory:", WithPluginHooks(hooks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	result, err := db.IngestLore(context.Background(), []types.NewLoreEntry{
+		{Content: "fine", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "s"},
+		{Content: "a secret", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "s"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Accepted != 1 || result.Rejected != 1 {
+		t.Errorf("accepted=%d rejected=%d, want 1/1", result.Accepted, result.Rejected)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "lore[1]") {
+		t.Errorf("errors = %v, want one error for lore[1]", result.Errors)
+	}
+}
+
+func TestIngestLore_BeforeIngestHook_AllVetoed(t *testing.T) {
+	hooks := &hookPlugin{beforeIngest: func(e *types.NewLoreEntry) error {
+		return plugin.ErrVetoed
+	}}
+	db, err := NewSQLiteStore(":memory:", WithPluginHooks(hooks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	result, err := db.IngestLore(context.Background(), []types.NewLoreEntry{
+		{Content: "x", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "s"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Accepted != 0 || result.Rejected != 1 {
+		t.Errorf("accepted=%d rejected=%d, want 0/1", result.Accepted, result.Rejected)
+	}
+
+	var count int
+	if err := db.db.QueryRow("SELECT COUNT(*) FROM change_log").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("change_log has %d entries, want 0", count)
+	}
+}
+
+func TestIngestLore_AfterMergeHook_CalledOnMerge(t *testing.T) {
+	base := makeTestEmbedding(0)
+	hooks := &hookPlugin{}
+	db, err := NewSQLiteStore(":memory:", WithPluginHooks(hooks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetDependencies(
+		&mockEmbedder{embeddings: map[string][]float32{"one": base, "two": base}},
+		&mockConfig{dedupEnabled: true, threshold: 0.92},
+	)
+
+	ctx := context.Background()
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{{Content: "one", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "s"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(hooks.merges) != 0 {
+		t.Fatalf("AfterMerge called for a new entry: %v", hooks.merges)
+	}
+
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{{Content: "two", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "s"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(hooks.merges) != 1 {
+		t.Fatalf("AfterMerge calls = %d, want 1", len(hooks.merges))
+	}
+}
+
+func TestDeleteLore_BeforeDeleteHook_Veto(t *testing.T) {
+	hooks := &hookPlugin{beforeDelete: func(id string) error {
+		return fmt.Errorf("%w: entry is pinned", plugin.ErrVetoed)
+	}}
+	db, err := NewSQLiteStore(":memory:", WithPluginHooks(hooks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{{Content: "keep", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "s"}}); err != nil {
+		t.Fatal(err)
+	}
+	var id string
+	if err := db.db.QueryRow("SELECT id FROM lore_entries").Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.DeleteLore(ctx, id, "s")
+	if !errors.Is(err, plugin.ErrVetoed) {
+		t.Fatalf("DeleteLore error = %v, want ErrVetoed", err)
+	}
+	if _, err := db.GetLore(ctx, id); err != nil {
+		t.Errorf("entry should survive vetoed delete: %v", err)
+	}
+}
+
+func TestIngestLore_NoHooks_Unchanged(t *testing.T) {
+	// A plugin without hook methods must not change ingest behavior.
+	db, err := NewSQLiteStore(":memory:", WithPluginHooks(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	result, err := db.IngestLore(context.Background(), []types.NewLoreEntry{{Content: "x", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "s"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Accepted != 1 || result.Rejected != 0 {
+		t.Errorf("accepted=%d rejected=%d, want 1/0", result.Accepted, result.Rejected)
+	}
+}
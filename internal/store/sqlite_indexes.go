@@ -0,0 +1,34 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// IndexStats lists the store's indexes, by table, with the number of
+// statements that used each since the store was opened.
+func (s *SQLiteStore) IndexStats(ctx context.Context) ([]types.IndexStats, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT name, tbl_name FROM sqlite_master
+		WHERE type = 'index'
+		ORDER BY tbl_name, name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list indexes: %w", err)
+	}
+	defer rows.Close()
+
+	uses := s.plans.usage()
+	stats := []types.IndexStats{}
+	for rows.Next() {
+		var idx types.IndexStats
+		if err := rows.Scan(&idx.Name, &idx.Table); err != nil {
+			return nil, fmt.Errorf("scan index: %w", err)
+		}
+		idx.Uses = uses[idx.Name]
+		stats = append(stats, idx)
+	}
+	return stats, rows.Err()
+}
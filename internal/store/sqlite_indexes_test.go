@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIndexStats_CountsUses(t *testing.T) {
+	s, err := NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	for range 2 {
+		if _, err := s.GetPendingEmbeddings(ctx, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.GetDelta(ctx, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := s.IndexStats(ctx)
+	if err != nil {
+		t.Fatalf("IndexStats() error = %v", err)
+	}
+	uses := make(map[string]int64)
+	for _, idx := range stats {
+		uses[idx.Name] = idx.Uses
+	}
+	if got := uses["idx_lore_entries_pending"]; got != 2 {
+		t.Errorf("idx_lore_entries_pending uses = %d, want 2", got)
+	}
+	// Both delta queries read the change log by received_at
+	want := map[string]int64{
+		"idx_lore_entries_updated_at": 1,
+		"idx_lore_entries_deleted_at": 1,
+		"idx_change_log_received":     2,
+	}
+	for idx, n := range want {
+		if uses[idx] != n {
+			t.Errorf("%s uses = %d, want %d from the delta", idx, uses[idx], n)
+		}
+	}
+	if n, ok := uses["idx_lore_entries_live_category"]; !ok || n != 0 {
+		t.Errorf("idx_lore_entries_live_category = %d, %v; want listed, unused", n, ok)
+	}
+}
+
+func TestExplainable(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"SELECT 1", true},
+		{"\n\t\tselect id FROM lore_entries;\n", true},
+		{"WITH x AS (SELECT 1) SELECT * FROM x", true},
+		{"UPDATE lore_entries SET confidence = 1", true},
+		{"PRAGMA journal_mode=WAL", false},
+		{"BEGIN IMMEDIATE", false},
+		{"UPDATE a SET x = 1; DELETE FROM b", false},
+	}
+	for _, tt := range tests {
+		if got := explainable(tt.query); got != tt.want {
+			t.Errorf("explainable(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// WriteLock holds a store's database write lock; see SQLiteStore.LockWrites.
+type WriteLock struct {
+	conn *sql.Conn
+	once sync.Once
+	err  error
+}
+
+// LockWrites takes the database write lock and holds it until the returned
+// lock is released, so the database files stay unchanged and can be copied
+// while reads continue. Writers, including background workers, fail busy
+// while it is held. Committed changes are checkpointed into the database
+// file once the lock is taken, so engram.db is normally complete on its
+// own; any engram.db-wal left beside it belongs with it in a copy.
+//
+// LockWrites waits for writes in progress to finish, failing busy if they
+// outlast the busy timeout. In-memory stores have no files and return
+// ErrInMemoryStore.
+func (s *SQLiteStore) LockWrites(ctx context.Context) (*WriteLock, error) {
+	if s.dbPath == ":memory:" {
+		return nil, ErrInMemoryStore
+	}
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reserve connection: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("take write lock: %w", err)
+	}
+	lock := &WriteLock{conn: conn}
+
+	// A passive checkpoint needs no write lock, and nothing can commit
+	// behind it now
+	if _, err := s.db.ExecContext(ctx, "PRAGMA wal_checkpoint(PASSIVE)"); err != nil {
+		lock.Release()
+		return nil, fmt.Errorf("checkpoint: %w", err)
+	}
+	return lock, nil
+}
+
+// Release gives up the write lock. Further calls do nothing and return the
+// first call's error.
+func (l *WriteLock) Release() error {
+	l.once.Do(func() {
+		_, rollbackErr := l.conn.ExecContext(context.Background(), "ROLLBACK")
+		l.err = errors.Join(rollbackErr, l.conn.Close())
+	})
+	return l.err
+}
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestLockWrites_PausesWritesUntilReleased(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "engram.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+
+	lock, err := s.LockWrites(ctx)
+	if err != nil {
+		t.Fatalf("LockWrites() error = %v", err)
+	}
+
+	if _, err := s.GetStats(ctx); err != nil {
+		t.Errorf("GetStats() while locked error = %v, want reads to continue", err)
+	}
+
+	if err := s.SetSyncMeta(ctx, "lock_test", "1"); !IsBusy(err) {
+		t.Errorf("SetSyncMeta() while locked error = %v, want busy", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if err := s.SetSyncMeta(ctx, "lock_test", "1"); err != nil {
+		t.Errorf("SetSyncMeta() after release error = %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Errorf("second Release() error = %v", err)
+	}
+}
+
+func TestLockWrites_InMemory(t *testing.T) {
+	s := newTestStore(t)
+	if _, err := s.LockWrites(context.Background()); !errors.Is(err, ErrInMemoryStore) {
+		t.Errorf("LockWrites() error = %v, want ErrInMemoryStore", err)
+	}
+}
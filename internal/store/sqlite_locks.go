@@ -0,0 +1,159 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// confidenceBounds is the confidence range an entry's lock allows.
+// Unlocked entries have the full range.
+type confidenceBounds struct {
+	floor, ceiling float64
+}
+
+var unlockedBounds = confidenceBounds{floor: MinConfidence, ceiling: MaxConfidence}
+
+// clamp limits a change from current to proposed to the bounds. A change is
+// never turned around: lowering stops at the floor, or at current if the
+// entry is already below it, and raising likewise stops at the ceiling.
+func (b confidenceBounds) clamp(current, proposed float64) float64 {
+	if proposed < b.floor && proposed < current {
+		return math.Min(b.floor, current)
+	}
+	if proposed > b.ceiling && proposed > current {
+		return math.Max(b.ceiling, current)
+	}
+	return proposed
+}
+
+// loadConfidenceBounds returns the locked bounds of an entry.
+func (s *SQLiteStore) loadConfidenceBounds(ctx context.Context, qc queryContext, loreID string) (confidenceBounds, error) {
+	b := unlockedBounds
+	err := qc.QueryRowContext(ctx, `
+		SELECT COALESCE(floor, ?), COALESCE(ceiling, ?) FROM confidence_locks WHERE lore_id = ?
+	`, MinConfidence, MaxConfidence, loreID).Scan(&b.floor, &b.ceiling)
+	if err != nil && err != sql.ErrNoRows {
+		return b, fmt.Errorf("load confidence lock: %w", err)
+	}
+	return b, nil
+}
+
+// GetConfidenceLock returns an entry's confidence lock. Entries without a
+// lock return one with no bounds. Returns ErrNotFound if the entry doesn't
+// exist or is deleted.
+func (s *SQLiteStore) GetConfidenceLock(ctx context.Context, loreID string) (*types.ConfidenceLock, error) {
+	var (
+		confidence     float64
+		floor, ceiling sql.NullFloat64
+		setBy, setAt   sql.NullString
+	)
+	err := s.db.QueryRowContext(ctx, `
+		SELECT l.confidence, k.floor, k.ceiling, k.set_by, k.set_at
+		FROM lore_entries l
+		LEFT JOIN confidence_locks k ON k.lore_id = l.id
+		WHERE l.id = ? AND l.deleted_at IS NULL
+	`, loreID).Scan(&confidence, &floor, &ceiling, &setBy, &setAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query confidence lock: %w", err)
+	}
+
+	lock := &types.ConfidenceLock{LoreID: loreID, Confidence: confidence, SetBy: setBy.String}
+	if floor.Valid {
+		lock.Floor = &floor.Float64
+		lock.Pinned = true
+	}
+	if ceiling.Valid {
+		lock.Ceiling = &ceiling.Float64
+		lock.Deprecated = true
+	}
+	if setAt.Valid {
+		t, err := time.Parse(time.RFC3339, setAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("parse set_at: %w", err)
+		}
+		lock.SetAt = &t
+	}
+	return lock, nil
+}
+
+// SetConfidenceLock replaces an entry's confidence bounds; a nil bound is
+// removed. The entry's confidence is moved into the new range immediately.
+// Returns ErrNotFound if the entry doesn't exist or is deleted.
+func (s *SQLiteStore) SetConfidenceLock(ctx context.Context, loreID string, floor, ceiling *float64, setBy string) (*types.ConfidenceLock, error) {
+	nowStr := formatTime(time.Now())
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var confidence float64
+	err = tx.QueryRowContext(ctx, `
+		SELECT confidence FROM lore_entries WHERE id = ? AND deleted_at IS NULL
+	`, loreID).Scan(&confidence)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch lore entry: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO confidence_locks (lore_id, floor, ceiling, set_by, set_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(lore_id) DO UPDATE SET
+			floor = excluded.floor, ceiling = excluded.ceiling,
+			set_by = excluded.set_by, set_at = excluded.set_at
+	`, loreID, floor, ceiling, setBy, nowStr); err != nil {
+		return nil, fmt.Errorf("set confidence lock: %w", err)
+	}
+
+	locked := confidence
+	if floor != nil && locked < *floor {
+		locked = *floor
+	}
+	if ceiling != nil && locked > *ceiling {
+		locked = *ceiling
+	}
+	if locked != confidence {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE lore_entries SET confidence = ?, updated_at = ? WHERE id = ?
+		`, locked, nowStr, loreID); err != nil {
+			return nil, fmt.Errorf("apply confidence lock: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return s.GetConfidenceLock(ctx, loreID)
+}
+
+// ClearConfidenceLock removes an entry's confidence bounds, including a
+// floor set by review approval. Returns ErrNotFound if the entry doesn't
+// exist or is deleted.
+func (s *SQLiteStore) ClearConfidenceLock(ctx context.Context, loreID string) error {
+	var exists int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT 1 FROM lore_entries WHERE id = ? AND deleted_at IS NULL
+	`, loreID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("check lore entry: %w", err)
+	}
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM confidence_locks WHERE lore_id = ?`, loreID); err != nil {
+		return fmt.Errorf("clear confidence lock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestConfidenceBounds_Clamp(t *testing.T) {
+	b := confidenceBounds{floor: 0.4, ceiling: 0.6}
+	tests := []struct {
+		current, proposed, want float64
+	}{
+		{0.5, 0.45, 0.45}, // within bounds
+		{0.5, 0.3, 0.4},   // lowered to the floor
+		{0.5, 0.7, 0.6},   // raised to the ceiling
+		{0.3, 0.2, 0.3},   // already below the floor: no further drop, no raise
+		{0.7, 0.8, 0.7},   // already above the ceiling: no further rise, no drop
+		{0.3, 0.5, 0.5},   // recovering from below the floor is allowed
+	}
+	for _, tt := range tests {
+		if got := b.clamp(tt.current, tt.proposed); got != tt.want {
+			t.Errorf("clamp(%v, %v) = %v, want %v", tt.current, tt.proposed, got, tt.want)
+		}
+	}
+}
+
+func TestSetConfidenceLock_CeilingBlocksHelpfulAndMerge(t *testing.T) {
+	s := newReviewTestStore(t,
+		types.NewLoreEntry{Content: "Deprecated lore", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src"},
+	)
+	ctx := context.Background()
+	id := digestTestLoreID(t, s, "Deprecated lore")
+
+	ceiling := 0.3
+	lock, err := s.SetConfidenceLock(ctx, id, nil, &ceiling, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lock.Deprecated || lock.Pinned || lock.Confidence != 0.3 || lock.SetBy != "alice" || lock.SetAt == nil {
+		t.Errorf("lock = %+v, want deprecated at 0.3", lock)
+	}
+
+	result, err := s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: id, Type: "helpful"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.Updates[0].CurrentConfidence; got != 0.3 {
+		t.Errorf("confidence after helpful = %v, want ceiling 0.3", got)
+	}
+	if v := result.Updates[0].ValidationCount; v == nil || *v != 1 {
+		t.Errorf("validation_count = %v, want helpful feedback still counted", v)
+	}
+
+	if err := s.MergeLore(ctx, id, types.NewLoreEntry{SourceID: "other"}); err != nil {
+		t.Fatal(err)
+	}
+	entry, _ := s.GetLore(ctx, id)
+	if entry.Confidence != 0.3 {
+		t.Errorf("confidence after merge = %v, want ceiling 0.3", entry.Confidence)
+	}
+
+	result, err = s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: id, Type: "incorrect"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.Updates[0].CurrentConfidence; got != 0.15 {
+		t.Errorf("confidence after incorrect = %v, want 0.15", got)
+	}
+}
+
+func TestSetConfidenceLock_FloorBlocksIncorrectAndDecay(t *testing.T) {
+	s := newReviewTestStore(t,
+		types.NewLoreEntry{Content: "Pinned lore", Category: "PATTERN_OUTCOME", Confidence: 0.2, SourceID: "src"},
+	)
+	ctx := context.Background()
+	id := digestTestLoreID(t, s, "Pinned lore")
+
+	floor := 0.7
+	lock, err := s.SetConfidenceLock(ctx, id, &floor, nil, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lock.Pinned || lock.Confidence != 0.7 {
+		t.Errorf("lock = %+v, want pinned with confidence raised to 0.7", lock)
+	}
+
+	result, err := s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: id, Type: "incorrect"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.Updates[0].CurrentConfidence; got != 0.7 {
+		t.Errorf("confidence after incorrect = %v, want floor 0.7", got)
+	}
+	if _, err := s.DecayConfidence(ctx, time.Now().Add(time.Hour), 0.5); err != nil {
+		t.Fatal(err)
+	}
+	entry, _ := s.GetLore(ctx, id)
+	if entry.Confidence != 0.7 {
+		t.Errorf("confidence after decay = %v, want floor 0.7", entry.Confidence)
+	}
+}
+
+func TestClearConfidenceLock(t *testing.T) {
+	s := newReviewTestStore(t,
+		types.NewLoreEntry{Content: "Unpinned lore", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src"},
+	)
+	ctx := context.Background()
+	id := digestTestLoreID(t, s, "Unpinned lore")
+
+	floor, ceiling := 0.4, 0.6
+	if _, err := s.SetConfidenceLock(ctx, id, &floor, &ceiling, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ClearConfidenceLock(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := s.GetConfidenceLock(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock.Pinned || lock.Deprecated || lock.Floor != nil || lock.Ceiling != nil {
+		t.Errorf("lock = %+v, want no bounds", lock)
+	}
+
+	result, err := s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: id, Type: "incorrect"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.Updates[0].CurrentConfidence; got != 0.35 {
+		t.Errorf("confidence after incorrect = %v, want 0.35", got)
+	}
+}
+
+func TestConfidenceLock_NotFound(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	missing := "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	floor := 0.5
+
+	if _, err := s.GetConfidenceLock(ctx, missing); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetConfidenceLock: err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.SetConfidenceLock(ctx, missing, &floor, nil, "alice"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("SetConfidenceLock: err = %v, want ErrNotFound", err)
+	}
+	if err := s.ClearConfidenceLock(ctx, missing); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ClearConfidenceLock: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSetConfidenceLock_CeilingBlocksIngestMerge(t *testing.T) {
+	embedding := makeTestEmbedding(0)
+	s := setupDeduplicationTest(t, true, 0.92, map[string][]float32{
+		"First content":  embedding,
+		"Second content": embedding,
+	})
+	defer s.Close()
+	ctx := context.Background()
+
+	if _, err := s.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "First content", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "source-1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	id := digestTestLoreID(t, s, "First content")
+	ceiling := 0.5
+	if _, err := s.SetConfidenceLock(ctx, id, nil, &ceiling, "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := s.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Second content", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "source-2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Merged != 1 {
+		t.Fatalf("merged = %d, want 1", result.Merged)
+	}
+	entry, _ := s.GetLore(ctx, id)
+	if entry.Confidence != 0.5 {
+		t.Errorf("confidence after merge = %v, want ceiling 0.5", entry.Confidence)
+	}
+}
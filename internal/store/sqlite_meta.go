@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ClientMetaPrefix is the sync_meta key prefix under which clients keep
+// their own values through the store metadata API. Client keys are always
+// stored under it and server keys never are, so a client can neither read
+// nor overwrite sync state such as schema_version.
+const ClientMetaPrefix = "client."
+
+// MaxClientMetaKeys caps the client metadata keys a store holds.
+const MaxClientMetaKeys = 100
+
+// GetClientMeta returns the client metadata value stored under key.
+// Returns ErrMetaNotFound if there is none.
+func (s *SQLiteStore) GetClientMeta(ctx context.Context, key string) (string, error) {
+	var value string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT value FROM sync_meta WHERE key = ?
+	`, ClientMetaPrefix+key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrMetaNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("get client meta: %w", err)
+	}
+	return value, nil
+}
+
+// PutClientMeta stores value under key, replacing any value there. Returns
+// ErrMetaFull if key is new and the store already holds MaxClientMetaKeys.
+func (s *SQLiteStore) PutClientMeta(ctx context.Context, key, value string) error {
+	// One statement, so concurrent puts of new keys cannot both pass the
+	// cap. Keys under the prefix sort from "client." to just before
+	// "client/", a range the primary key serves, unlike LIKE.
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO sync_meta (key, value)
+		SELECT ?1, ?2
+		WHERE EXISTS (SELECT 1 FROM sync_meta WHERE key = ?1)
+			OR (SELECT COUNT(*) FROM sync_meta WHERE key >= ?3 AND key < ?4) < ?5
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value
+	`, ClientMetaPrefix+key, value, ClientMetaPrefix, "client/", MaxClientMetaKeys)
+	if err != nil {
+		return fmt.Errorf("put client meta: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrMetaFull
+	}
+	return nil
+}
+
+// DeleteClientMeta removes the client metadata value stored under key.
+// Returns ErrMetaNotFound if there is none.
+func (s *SQLiteStore) DeleteClientMeta(ctx context.Context, key string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM sync_meta WHERE key = ?`, ClientMetaPrefix+key)
+	if err != nil {
+		return fmt.Errorf("delete client meta: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrMetaNotFound
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestClientMeta_PutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	if _, err := s.GetClientMeta(ctx, "fleet.config"); !errors.Is(err, ErrMetaNotFound) {
+		t.Fatalf("GetClientMeta() before put error = %v, want ErrMetaNotFound", err)
+	}
+	if err := s.PutClientMeta(ctx, "fleet.config", `{"sync_interval":"5m"}`); err != nil {
+		t.Fatalf("PutClientMeta() error = %v", err)
+	}
+	if err := s.PutClientMeta(ctx, "fleet.config", `{"sync_interval":"1m"}`); err != nil {
+		t.Fatalf("PutClientMeta() replace error = %v", err)
+	}
+	if got, err := s.GetClientMeta(ctx, "fleet.config"); err != nil || got != `{"sync_interval":"1m"}` {
+		t.Errorf("GetClientMeta() = %q, %v, want the replaced value", got, err)
+	}
+
+	if err := s.DeleteClientMeta(ctx, "fleet.config"); err != nil {
+		t.Fatalf("DeleteClientMeta() error = %v", err)
+	}
+	if err := s.DeleteClientMeta(ctx, "fleet.config"); !errors.Is(err, ErrMetaNotFound) {
+		t.Errorf("DeleteClientMeta() twice error = %v, want ErrMetaNotFound", err)
+	}
+}
+
+func TestClientMeta_KeptApartFromSyncMeta(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	if _, err := s.GetClientMeta(ctx, "schema_version"); !errors.Is(err, ErrMetaNotFound) {
+		t.Errorf("GetClientMeta(schema_version) error = %v, want ErrMetaNotFound", err)
+	}
+	want, err := s.GetSyncMeta(ctx, "schema_version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PutClientMeta(ctx, "schema_version", "999"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.GetSyncMeta(ctx, "schema_version"); got != want {
+		t.Errorf("schema_version = %q after a client put, want %q", got, want)
+	}
+}
+
+func TestClientMeta_KeyLimit(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	for i := 0; i < MaxClientMetaKeys; i++ {
+		if err := s.PutClientMeta(ctx, fmt.Sprintf("key-%d", i), "v"); err != nil {
+			t.Fatalf("PutClientMeta(%d) error = %v", i, err)
+		}
+	}
+	if err := s.PutClientMeta(ctx, "one-more", "v"); !errors.Is(err, ErrMetaFull) {
+		t.Errorf("PutClientMeta() over the limit error = %v, want ErrMetaFull", err)
+	}
+	// Replacing a key already held is not refused
+	if err := s.PutClientMeta(ctx, "key-0", "w"); err != nil {
+		t.Errorf("PutClientMeta() replace at the limit error = %v", err)
+	}
+}
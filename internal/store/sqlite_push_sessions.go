@@ -0,0 +1,214 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+// MaxPushSessionEntries caps the entries staged in one push session.
+const MaxPushSessionEntries = 100000
+
+// CreatePushSession begins a multi-part push. Expired sessions are removed
+// first so an abandoned push_id can be reused. Returns ErrPushSessionExists
+// if a live session with the same push_id exists.
+func (s *SQLiteStore) CreatePushSession(ctx context.Context, session engramsync.PushSession) error {
+	if _, err := s.CleanExpiredPushSessions(ctx); err != nil {
+		return err
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO push_sessions (push_id, source_id, schema_version, allow_deferred_parents, cascade_deletes, quarantine_invalid, mode, entry_count, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 0, ?, ?)
+		ON CONFLICT(push_id) DO NOTHING
+	`, session.PushID, session.SourceID, session.SchemaVersion,
+		boolToInt(session.AllowDeferredParents), boolToInt(session.CascadeDeletes), boolToInt(session.QuarantineInvalid), session.Mode,
+		formatTime(session.CreatedAt), formatTime(session.ExpiresAt))
+	if err != nil {
+		return fmt.Errorf("create push session: %w", err)
+	}
+
+	inserted, _ := result.RowsAffected()
+	if inserted == 0 {
+		return ErrPushSessionExists
+	}
+	return nil
+}
+
+// GetPushSession returns a live push session. Expired sessions are
+// reported as ErrPushSessionNotFound.
+func (s *SQLiteStore) GetPushSession(ctx context.Context, pushID string) (*engramsync.PushSession, error) {
+	var session engramsync.PushSession
+	var deferred, cascade, quarantine int
+	var createdAt, expiresAt string
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT push_id, source_id, schema_version, allow_deferred_parents, cascade_deletes, quarantine_invalid, mode, entry_count, created_at, expires_at
+		FROM push_sessions WHERE push_id = ?
+	`, pushID).Scan(&session.PushID, &session.SourceID, &session.SchemaVersion,
+		&deferred, &cascade, &quarantine, &session.Mode, &session.EntryCount, &createdAt, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPushSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get push session: %w", err)
+	}
+
+	session.AllowDeferredParents = deferred != 0
+	session.CascadeDeletes = cascade != 0
+	session.QuarantineInvalid = quarantine != 0
+	session.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+	session.ExpiresAt, err = time.Parse(time.RFC3339Nano, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("parse expires_at: %w", err)
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return nil, ErrPushSessionNotFound
+	}
+
+	return &session, nil
+}
+
+// AppendPushSessionEntries stages entries for a live session and returns
+// the session's new entry count. Returns ErrPushSessionFull, staging
+// nothing, if the session would then hold more than MaxPushSessionEntries.
+func (s *SQLiteStore) AppendPushSessionEntries(ctx context.Context, pushID string, entries []engramsync.ChangeLogEntry) (int, error) {
+	if _, err := s.GetPushSession(ctx, pushID); err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The count is checked and raised in one statement, so concurrent
+	// appends cannot both pass the limit
+	var count int
+	err = tx.QueryRowContext(ctx, `
+		UPDATE push_sessions SET entry_count = entry_count + ?
+		WHERE push_id = ? AND entry_count + ? <= ?
+		RETURNING entry_count
+	`, len(entries), pushID, len(entries), MaxPushSessionEntries).Scan(&count)
+	if errors.Is(err, sql.ErrNoRows) {
+		var exists int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM push_sessions WHERE push_id = ?`, pushID).Scan(&exists); err != nil {
+			return 0, fmt.Errorf("check push session: %w", err)
+		}
+		if exists == 0 {
+			return 0, ErrPushSessionNotFound
+		}
+		return 0, ErrPushSessionFull
+	}
+	if err != nil {
+		return 0, fmt.Errorf("update entry count: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO push_session_entries (push_id, entry) VALUES (?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, entry := range entries {
+		b, err := json.Marshal(entry)
+		if err != nil {
+			return 0, fmt.Errorf("encode entry %d: %w", i, err)
+		}
+		if _, err := stmt.ExecContext(ctx, pushID, string(b)); err != nil {
+			return 0, fmt.Errorf("stage entry %d: %w", i, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return count, nil
+}
+
+// GetPushSessionEntries returns a live session's staged entries in append order.
+func (s *SQLiteStore) GetPushSessionEntries(ctx context.Context, pushID string) ([]engramsync.ChangeLogEntry, error) {
+	if _, err := s.GetPushSession(ctx, pushID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT entry FROM push_session_entries WHERE push_id = ? ORDER BY id ASC
+	`, pushID)
+	if err != nil {
+		return nil, fmt.Errorf("query push session entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]engramsync.ChangeLogEntry, 0)
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("scan push session entry: %w", err)
+		}
+		var e engramsync.ChangeLogEntry
+		if err := json.Unmarshal([]byte(raw), &e); err != nil {
+			return nil, fmt.Errorf("decode push session entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// DeletePushSession removes a session and its staged entries.
+// Deleting a missing session is not an error.
+func (s *SQLiteStore) DeletePushSession(ctx context.Context, pushID string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM push_session_entries WHERE push_id = ?`, pushID); err != nil {
+		return fmt.Errorf("delete push session entries: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM push_sessions WHERE push_id = ?`, pushID); err != nil {
+		return fmt.Errorf("delete push session: %w", err)
+	}
+	return tx.Commit()
+}
+
+// CleanExpiredPushSessions removes expired sessions and their staged entries.
+// Returns the number of sessions removed.
+func (s *SQLiteStore) CleanExpiredPushSessions(ctx context.Context) (int64, error) {
+	now := formatTime(time.Now())
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM push_session_entries
+		WHERE push_id IN (SELECT push_id FROM push_sessions WHERE expires_at < ?)
+	`, now); err != nil {
+		return 0, fmt.Errorf("clean push session entries: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM push_sessions WHERE expires_at < ?`, now)
+	if err != nil {
+		return 0, fmt.Errorf("clean push sessions: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
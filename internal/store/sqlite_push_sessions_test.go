@@ -0,0 +1,224 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+func newPushSession(pushID string, ttl time.Duration) engramsync.PushSession {
+	now := time.Now().UTC()
+	return engramsync.PushSession{
+		PushID:         pushID,
+		SourceID:       "source-1",
+		SchemaVersion:  2,
+		CascadeDeletes: true,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(ttl),
+	}
+}
+
+func sessionEntries(ids ...string) []engramsync.ChangeLogEntry {
+	entries := make([]engramsync.ChangeLogEntry, len(ids))
+	for i, id := range ids {
+		entries[i] = engramsync.ChangeLogEntry{
+			TableName: "lore_entries",
+			EntityID:  id,
+			Operation: engramsync.OperationUpsert,
+			Payload:   json.RawMessage(`{"id":"` + id + `"}`),
+		}
+	}
+	return entries
+}
+
+func TestPushSession_CreateAndGet(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if err := s.CreatePushSession(ctx, newPushSession("push-1", time.Hour)); err != nil {
+		t.Fatalf("CreatePushSession() error = %v", err)
+	}
+
+	got, err := s.GetPushSession(ctx, "push-1")
+	if err != nil {
+		t.Fatalf("GetPushSession() error = %v", err)
+	}
+	if got.SourceID != "source-1" || got.SchemaVersion != 2 {
+		t.Errorf("session = %+v, want source-1 / schema 2", got)
+	}
+	if !got.CascadeDeletes || got.AllowDeferredParents {
+		t.Errorf("flags = cascade %v deferred %v, want true/false", got.CascadeDeletes, got.AllowDeferredParents)
+	}
+	if got.EntryCount != 0 {
+		t.Errorf("EntryCount = %d, want 0", got.EntryCount)
+	}
+}
+
+func TestPushSession_CreateDuplicate(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if err := s.CreatePushSession(ctx, newPushSession("push-1", time.Hour)); err != nil {
+		t.Fatalf("CreatePushSession() error = %v", err)
+	}
+	err := s.CreatePushSession(ctx, newPushSession("push-1", time.Hour))
+	if !errors.Is(err, ErrPushSessionExists) {
+		t.Errorf("error = %v, want ErrPushSessionExists", err)
+	}
+}
+
+func TestPushSession_AppendPreservesOrder(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if err := s.CreatePushSession(ctx, newPushSession("push-1", time.Hour)); err != nil {
+		t.Fatalf("CreatePushSession() error = %v", err)
+	}
+
+	count, err := s.AppendPushSessionEntries(ctx, "push-1", sessionEntries("c", "a"))
+	if err != nil {
+		t.Fatalf("AppendPushSessionEntries() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("count = %d, want 2", count)
+	}
+	count, err = s.AppendPushSessionEntries(ctx, "push-1", sessionEntries("b"))
+	if err != nil {
+		t.Fatalf("AppendPushSessionEntries() error = %v", err)
+	}
+	if count != 3 {
+		t.Errorf("count = %d, want 3", count)
+	}
+
+	entries, err := s.GetPushSessionEntries(ctx, "push-1")
+	if err != nil {
+		t.Fatalf("GetPushSessionEntries() error = %v", err)
+	}
+	var ids []string
+	for _, e := range entries {
+		ids = append(ids, e.EntityID)
+	}
+	if len(ids) != 3 || ids[0] != "c" || ids[1] != "a" || ids[2] != "b" {
+		t.Errorf("entity order = %v, want [c a b]", ids)
+	}
+}
+
+func TestPushSession_AppendUnknown(t *testing.T) {
+	s := newTestStore(t)
+
+	_, err := s.AppendPushSessionEntries(context.Background(), "missing", sessionEntries("a"))
+	if !errors.Is(err, ErrPushSessionNotFound) {
+		t.Errorf("error = %v, want ErrPushSessionNotFound", err)
+	}
+}
+
+func TestPushSession_AppendFull(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if err := s.CreatePushSession(ctx, newPushSession("push-1", time.Hour)); err != nil {
+		t.Fatalf("CreatePushSession() error = %v", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `UPDATE push_sessions SET entry_count = ? WHERE push_id = ?`,
+		MaxPushSessionEntries-1, "push-1"); err != nil {
+		t.Fatalf("set entry_count: %v", err)
+	}
+
+	_, err := s.AppendPushSessionEntries(ctx, "push-1", sessionEntries("a", "b"))
+	if !errors.Is(err, ErrPushSessionFull) {
+		t.Fatalf("error = %v, want ErrPushSessionFull", err)
+	}
+	entries, err := s.GetPushSessionEntries(ctx, "push-1")
+	if err != nil {
+		t.Fatalf("GetPushSessionEntries() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("staged %d entries, want 0", len(entries))
+	}
+
+	count, err := s.AppendPushSessionEntries(ctx, "push-1", sessionEntries("a"))
+	if err != nil {
+		t.Fatalf("AppendPushSessionEntries() at limit error = %v", err)
+	}
+	if count != MaxPushSessionEntries {
+		t.Errorf("count = %d, want %d", count, MaxPushSessionEntries)
+	}
+}
+
+func TestPushSession_ExpiredIsNotFoundAndReusable(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if err := s.CreatePushSession(ctx, newPushSession("push-1", -time.Minute)); err != nil {
+		t.Fatalf("CreatePushSession() error = %v", err)
+	}
+	if _, err := s.GetPushSession(ctx, "push-1"); !errors.Is(err, ErrPushSessionNotFound) {
+		t.Errorf("GetPushSession() error = %v, want ErrPushSessionNotFound", err)
+	}
+
+	// Creating again clears the expired session first
+	if err := s.CreatePushSession(ctx, newPushSession("push-1", time.Hour)); err != nil {
+		t.Fatalf("CreatePushSession() after expiry error = %v", err)
+	}
+}
+
+func TestPushSession_CleanExpired(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if err := s.CreatePushSession(ctx, newPushSession("live", time.Hour)); err != nil {
+		t.Fatalf("CreatePushSession() error = %v", err)
+	}
+	if _, err := s.AppendPushSessionEntries(ctx, "live", sessionEntries("a")); err != nil {
+		t.Fatalf("AppendPushSessionEntries() error = %v", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO push_sessions (push_id, source_id, schema_version, created_at, expires_at)
+		VALUES ('stale', 'source-1', 2, '2020-01-01T00:00:00Z', '2020-01-01T01:00:00Z')
+	`); err != nil {
+		t.Fatalf("insert stale session: %v", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO push_session_entries (push_id, entry) VALUES ('stale', '{}')`); err != nil {
+		t.Fatalf("insert stale entry: %v", err)
+	}
+
+	removed, err := s.CleanExpiredPushSessions(ctx)
+	if err != nil {
+		t.Fatalf("CleanExpiredPushSessions() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("removed = %d, want 1", removed)
+	}
+
+	var staged int
+	s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM push_session_entries`).Scan(&staged)
+	if staged != 1 {
+		t.Errorf("staged entries = %d, want 1 (live session only)", staged)
+	}
+}
+
+func TestPushSession_Delete(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if err := s.CreatePushSession(ctx, newPushSession("push-1", time.Hour)); err != nil {
+		t.Fatalf("CreatePushSession() error = %v", err)
+	}
+	if _, err := s.AppendPushSessionEntries(ctx, "push-1", sessionEntries("a", "b")); err != nil {
+		t.Fatalf("AppendPushSessionEntries() error = %v", err)
+	}
+
+	if err := s.DeletePushSession(ctx, "push-1"); err != nil {
+		t.Fatalf("DeletePushSession() error = %v", err)
+	}
+	if _, err := s.GetPushSession(ctx, "push-1"); !errors.Is(err, ErrPushSessionNotFound) {
+		t.Errorf("GetPushSession() error = %v, want ErrPushSessionNotFound", err)
+	}
+	if err := s.DeletePushSession(ctx, "push-1"); err != nil {
+		t.Errorf("DeletePushSession() on missing session error = %v", err)
+	}
+}
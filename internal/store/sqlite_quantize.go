@@ -0,0 +1,124 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// QuantizationReport estimates how storing a store's embeddings with a
+// quantization would affect similarity results and size.
+type QuantizationReport struct {
+	Quantization Quantization `json:"quantization"`
+	// Entries is the number of live embedded entries compared.
+	Entries int `json:"entries"`
+	// Queries is the number of entries used as queries.
+	Queries int `json:"queries"`
+	K       int `json:"k"`
+	// RecallAtK is the mean fraction of a query's K nearest entries by the
+	// stored embeddings that are still among its K nearest once quantized.
+	RecallAtK float64 `json:"recall_at_k"`
+	// MeanSimilarityError and MaxSimilarityError are the mean and largest
+	// absolute change in cosine similarity over every query and entry.
+	MeanSimilarityError float64 `json:"mean_similarity_error"`
+	MaxSimilarityError  float64 `json:"max_similarity_error"`
+	// StoredBytes and QuantizedBytes are the total size of the embeddings as
+	// stored and as they would be stored with the quantization.
+	StoredBytes    int64 `json:"stored_bytes"`
+	QuantizedBytes int64 `json:"quantized_bytes"`
+}
+
+// EvaluateQuantization compares similarity search over the store's live
+// embeddings as stored and as quantized with q. Up to queries entries,
+// spread evenly through the store, are each searched for their k nearest
+// other entries, with the query itself kept at full precision as live queries
+// are. Every embedding is held in memory twice while it runs, so it suits
+// offline evaluation rather than a serving process.
+func (s *SQLiteStore) EvaluateQuantization(ctx context.Context, q Quantization, queries, k int) (*QuantizationReport, error) {
+	if queries <= 0 || k <= 0 {
+		return nil, fmt.Errorf("queries and k must be positive")
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT embedding
+		FROM lore_entries JOIN lore_embeddings ON lore_embeddings.lore_id = lore_entries.id
+		WHERE deleted_at IS NULL
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	report := &QuantizationReport{Quantization: q}
+	var stored, quantized []queryVector
+	for rows.Next() {
+		var blob []byte
+		if err := rows.Scan(&blob); err != nil {
+			return nil, fmt.Errorf("scan embedding: %w", err)
+		}
+		v := unpackEmbedding(blob)
+		packed := q.pack(v)
+		report.StoredBytes += int64(len(blob))
+		report.QuantizedBytes += int64(len(packed))
+		stored = append(stored, newQueryVector(v))
+		quantized = append(quantized, newQueryVector(unpackEmbedding(packed)))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate embeddings: %w", err)
+	}
+	report.Entries = len(stored)
+	if len(stored) < 2 {
+		return report, nil
+	}
+
+	queries = min(queries, len(stored))
+	k = min(k, len(stored)-1)
+	var recall, errSum float64
+	var pairs int
+	exact := make([]float64, len(stored))
+	approx := make([]float64, len(stored))
+	for i := range queries {
+		qi := i * len(stored) / queries
+		query := stored[qi]
+		for j := range stored {
+			exact[j] = query.similarity(stored[j].v, stored[j].norm)
+			approx[j] = query.similarity(quantized[j].v, quantized[j].norm)
+			if j != qi {
+				diff := math.Abs(exact[j] - approx[j])
+				errSum += diff
+				report.MaxSimilarityError = max(report.MaxSimilarityError, diff)
+				pairs++
+			}
+		}
+		want := nearest(exact, qi, k)
+		found := 0
+		for j := range nearest(approx, qi, k) {
+			if want[j] {
+				found++
+			}
+		}
+		recall += float64(found) / float64(k)
+	}
+	report.Queries, report.K = queries, k
+	report.RecallAtK = recall / float64(queries)
+	report.MeanSimilarityError = errSum / float64(pairs)
+	return report, nil
+}
+
+// nearest returns the set of indexes of the k highest scores, leaving out
+// skip.
+func nearest(scores []float64, skip, k int) map[int]bool {
+	idx := make([]int, 0, len(scores)-1)
+	for j := range scores {
+		if j != skip {
+			idx = append(idx, j)
+		}
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return scores[idx[a]] > scores[idx[b]] })
+	set := make(map[int]bool, k)
+	for _, j := range idx[:k] {
+		set[j] = true
+	}
+	return set
+}
@@ -0,0 +1,143 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"path/filepath"
+	"testing"
+)
+
+func gaussianEmbedding(rng *rand.Rand, dims int) []float32 {
+	v := make([]float32, dims)
+	for i := range v {
+		v[i] = float32(rng.NormFloat64())
+	}
+	return v
+}
+
+func TestWithEmbeddingQuantization_StoresAndDequantizes(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), WithEmbeddingQuantization(QuantizationInt8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	embedding := gaussianEmbedding(rand.New(rand.NewPCG(3, 4)), 64)
+	id := insertEntryWithEmbedding(t, db, "Queues are drained before deploys", "PATTERN_OUTCOME", embedding)
+
+	var size int
+	if err := db.db.QueryRow(`SELECT length(embedding) FROM lore_embeddings WHERE lore_id = ?`, id).Scan(&size); err != nil {
+		t.Fatal(err)
+	}
+	if size != 64+5 {
+		t.Errorf("stored embedding is %d bytes, want %d", size, 64+5)
+	}
+
+	entry, err := db.GetLore(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entry.Embedding) != 64 {
+		t.Fatalf("GetLore() embedding has %d dims, want 64", len(entry.Embedding))
+	}
+	if sim := cosineSimilarity(entry.Embedding, embedding); sim < 0.999 {
+		t.Errorf("dequantized embedding similarity = %v, want close to 1", sim)
+	}
+
+	similar, err := db.FindSimilar(ctx, embedding, "PATTERN_OUTCOME", 0.99)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(similar) != 1 || similar[0].ID != id {
+		t.Errorf("FindSimilar() = %v, want the quantized entry", similar)
+	}
+
+	// Norms are written for the stored vector, so reindexing finds no drift
+	report, err := db.ReindexSimilarity(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Repaired != 0 {
+		t.Errorf("ReindexSimilarity() repaired %d entries, want 0", report.Repaired)
+	}
+
+	// Snapshot clients read float32 embeddings regardless
+	if err := db.GenerateSnapshot(ctx); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := sql.Open("sqlite", db.snapshotPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snap.Close()
+	var blob []byte
+	if err := snap.QueryRow(`SELECT embedding FROM lore_entries WHERE id = ?`, id).Scan(&blob); err != nil {
+		t.Fatal(err)
+	}
+	if len(blob) != 4*64 {
+		t.Errorf("snapshot embedding is %d bytes, want %d", len(blob), 4*64)
+	}
+}
+
+func TestWithEmbeddingQuantization_ReadsEarlierEncodings(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	embedding := []float32{0.25, -0.5, 0.75}
+	id := insertEntryWithEmbedding(t, db, "Retries use jittered backoff", "PATTERN_OUTCOME", embedding)
+	db.Close()
+
+	db, err = NewSQLiteStore(path, WithEmbeddingQuantization(QuantizationFloat16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	entry, err := db.GetLore(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(entry.Embedding) != fmt.Sprint(embedding) {
+		t.Errorf("float32 embedding read as %v, want %v", entry.Embedding, embedding)
+	}
+}
+
+func TestEvaluateQuantization(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rng := rand.New(rand.NewPCG(5, 6))
+	for i := range 20 {
+		insertEntryWithEmbedding(t, db, fmt.Sprintf("entry %d", i), "PATTERN_OUTCOME", gaussianEmbedding(rng, 32))
+	}
+
+	report, err := db.EvaluateQuantization(ctx, QuantizationFloat16, 100, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Entries != 20 || report.Queries != 20 || report.K != 5 {
+		t.Errorf("report entries/queries/k = %d/%d/%d, want 20/20/5", report.Entries, report.Queries, report.K)
+	}
+	if report.RecallAtK < 0.95 {
+		t.Errorf("float16 recall@5 = %v, want close to 1", report.RecallAtK)
+	}
+	if report.MaxSimilarityError > 0.01 || report.MeanSimilarityError > report.MaxSimilarityError {
+		t.Errorf("similarity error mean %v max %v", report.MeanSimilarityError, report.MaxSimilarityError)
+	}
+	if report.StoredBytes != 20*32*4 || report.QuantizedBytes != 20*(32*2+1) {
+		t.Errorf("bytes %d -> %d, want %d -> %d", report.StoredBytes, report.QuantizedBytes, 20*32*4, 20*(32*2+1))
+	}
+
+	if _, err := db.EvaluateQuantization(ctx, QuantizationInt8, 0, 5); err == nil {
+		t.Error("EvaluateQuantization() with no queries error = nil")
+	}
+}
@@ -0,0 +1,177 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// RecalibrateConfidence rescales the confidence of live entries, optionally
+// of one category, in a single transaction. Scores drift as boosts and
+// decay accumulate; recalibrating restores their meaning without changing
+// their order.
+//
+// With a target mean and/or standard deviation each confidence c becomes
+// mean + (c - µ) / σ × stddev, where µ and σ describe the entries being
+// recalibrated and an omitted target keeps its current value; if the
+// entries all share one value, each becomes mean. With a mapping table, c
+// is interpolated between the table's points. Results are limited to
+// [0, 1] and to each entry's confidence lock. With rc.DryRun nothing is
+// changed.
+func (s *SQLiteStore) RecalibrateConfidence(ctx context.Context, rc types.ConfidenceRecalibration) (*types.RecalibrationResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT l.id, l.confidence, COALESCE(k.floor, ?), COALESCE(k.ceiling, ?)
+		FROM lore_entries l
+		LEFT JOIN confidence_locks k ON k.lore_id = l.id
+		WHERE l.deleted_at IS NULL`
+	args := []any{MinConfidence, MaxConfidence}
+	if rc.Category != "" {
+		query += ` AND l.category = ?`
+		args = append(args, rc.Category)
+	}
+	rows, err := tx.QueryContext(ctx, query+` ORDER BY l.id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query confidence: %w", err)
+	}
+	type scored struct {
+		id     string
+		before float64
+		bounds confidenceBounds
+	}
+	var entries []scored
+	for rows.Next() {
+		var e scored
+		if err := rows.Scan(&e.id, &e.before, &e.bounds.floor, &e.bounds.ceiling); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan confidence: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterate confidence: %w", err)
+	}
+	rows.Close()
+
+	before := make([]float64, len(entries))
+	for i, e := range entries {
+		before[i] = e.before
+	}
+	result := &types.RecalibrationResult{Before: confidenceDistribution(before), DryRun: rc.DryRun}
+	recalibrate := mappingRecalibration(rc.Mapping)
+	if rc.TargetMean != nil || rc.TargetStdDev != nil {
+		mean, stddev := result.Before.Mean, result.Before.StdDev
+		if rc.TargetMean != nil {
+			mean = *rc.TargetMean
+		}
+		if rc.TargetStdDev != nil {
+			stddev = *rc.TargetStdDev
+		}
+		recalibrate = linearRecalibration(result.Before, mean, stddev)
+	}
+
+	now := formatTime(time.Now())
+	after := make([]float64, len(entries))
+	for i, e := range entries {
+		c := math.Min(MaxConfidence, math.Max(MinConfidence, recalibrate(e.before)))
+		c = e.bounds.clamp(e.before, c)
+		// Round to 6 decimal places like feedback, so repeat runs are stable
+		c = math.Round(c*1e6) / 1e6
+		after[i] = c
+		if c == e.before {
+			continue
+		}
+		result.Changed++
+		if rc.DryRun {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE lore_entries SET confidence = ?, updated_at = ? WHERE id = ?
+		`, c, now, e.id); err != nil {
+			return nil, fmt.Errorf("update confidence: %w", err)
+		}
+	}
+	result.After = confidenceDistribution(after)
+
+	if rc.DryRun {
+		return result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	slog.Info("confidence recalibrated",
+		"component", "store",
+		"action", "recalibrate_confidence",
+		"store_id", s.storeID,
+		"category", rc.Category,
+		"changed", result.Changed,
+		"mean_before", result.Before.Mean,
+		"mean_after", result.After.Mean,
+	)
+	return result, nil
+}
+
+// linearRecalibration returns a function moving confidence from the
+// distribution from to the target mean and standard deviation.
+func linearRecalibration(from types.ConfidenceDistribution, mean, stddev float64) func(float64) float64 {
+	if from.StdDev == 0 {
+		return func(float64) float64 { return mean }
+	}
+	return func(c float64) float64 {
+		return mean + (c-from.Mean)/from.StdDev*stddev
+	}
+}
+
+// mappingRecalibration returns a function interpolating confidence between
+// the points of a mapping table ordered by From.
+func mappingRecalibration(points []types.ConfidencePoint) func(float64) float64 {
+	return func(c float64) float64 {
+		if len(points) == 0 {
+			return c
+		}
+		if c <= points[0].From {
+			return points[0].To
+		}
+		for i := 1; i < len(points); i++ {
+			lo, hi := points[i-1], points[i]
+			if c <= hi.From {
+				return lo.To + (c-lo.From)/(hi.From-lo.From)*(hi.To-lo.To)
+			}
+		}
+		return points[len(points)-1].To
+	}
+}
+
+// confidenceDistribution summarizes values, using the population standard
+// deviation.
+func confidenceDistribution(values []float64) types.ConfidenceDistribution {
+	d := types.ConfidenceDistribution{Count: len(values)}
+	if len(values) == 0 {
+		return d
+	}
+	d.Min, d.Max = values[0], values[0]
+	var sum float64
+	for _, v := range values {
+		sum += v
+		d.Min = math.Min(d.Min, v)
+		d.Max = math.Max(d.Max, v)
+	}
+	d.Mean = sum / float64(len(values))
+	var sq float64
+	for _, v := range values {
+		sq += (v - d.Mean) * (v - d.Mean)
+	}
+	d.StdDev = math.Sqrt(sq / float64(len(values)))
+	return d
+}
@@ -0,0 +1,75 @@
+package store
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestRecalibrateConfidence(t *testing.T) {
+	s := newReviewTestStore(t,
+		types.NewLoreEntry{Content: "Low", Category: "PATTERN_OUTCOME", Confidence: 0.2, SourceID: "src"},
+		types.NewLoreEntry{Content: "Mid", Category: "PATTERN_OUTCOME", Confidence: 0.4, SourceID: "src"},
+		types.NewLoreEntry{Content: "High", Category: "PATTERN_OUTCOME", Confidence: 0.6, SourceID: "src"},
+		types.NewLoreEntry{Content: "Other category", Category: "PERFORMANCE_INSIGHT", Confidence: 0.9, SourceID: "src"},
+	)
+	ctx := context.Background()
+	ceiling := 0.65
+	if _, err := s.SetConfidenceLock(ctx, digestTestLoreID(t, s, "High"), nil, &ceiling, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	confidence := func(content string) float64 {
+		t.Helper()
+		entry, err := s.GetLore(ctx, digestTestLoreID(t, s, content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return entry.Confidence
+	}
+
+	// Shifting the mean keeps the spread; the ceiling holds High back
+	mean := 0.5
+	rc := types.ConfidenceRecalibration{Category: "PATTERN_OUTCOME", TargetMean: &mean, DryRun: true}
+	preview, err := s.RecalibrateConfidence(ctx, rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Changed != 3 || preview.Before.Count != 3 || math.Abs(preview.Before.Mean-0.4) > 1e-9 {
+		t.Errorf("dry run = %+v", preview)
+	}
+	if got := confidence("Low"); got != 0.2 {
+		t.Errorf("dry run changed confidence to %v", got)
+	}
+
+	rc.DryRun = false
+	result, err := s.RecalibrateConfidence(ctx, rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed != 3 || result.After.Max != 0.65 {
+		t.Errorf("result = %+v", result)
+	}
+	for content, want := range map[string]float64{"Low": 0.3, "Mid": 0.5, "High": 0.65, "Other category": 0.9} {
+		if got := confidence(content); got != want {
+			t.Errorf("%s confidence = %v, want %v", content, got, want)
+		}
+	}
+
+	// A mapping table interpolates between its points
+	result, err = s.RecalibrateConfidence(ctx, types.ConfidenceRecalibration{
+		Mapping: []types.ConfidencePoint{{From: 0.3, To: 0.1}, {From: 0.5, To: 0.5}, {From: 0.9, To: 0.7}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Before.Count != 4 {
+		t.Errorf("recalibrated %d entries, want 4", result.Before.Count)
+	}
+	for content, want := range map[string]float64{"Low": 0.1, "Mid": 0.5, "High": 0.575, "Other category": 0.7} {
+		if got := confidence(content); got != want {
+			t.Errorf("%s confidence after mapping = %v, want %v", content, got, want)
+		}
+	}
+}
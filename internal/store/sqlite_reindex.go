@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ErrReindexMismatch is returned when a rebuilt similarity index fails
+// verification. The rebuild is rolled back.
+var ErrReindexMismatch = errors.New("similarity index verification failed")
+
+// ReindexReport summarizes a ReindexSimilarity pass.
+type ReindexReport struct {
+	// Embedded is the number of entries with an embedding, live or deleted.
+	Embedded int64 `json:"embedded"`
+	// Live is the number of live embedded entries FindSimilar can match.
+	Live int64 `json:"live"`
+	// Repaired is the number of entries whose stored norm or bucket did
+	// not match their embedding.
+	Repaired int64 `json:"repaired"`
+	// Cleared is the number of entries without an embedding that still
+	// had a norm or bucket.
+	Cleared int64 `json:"cleared"`
+	// Chunks is the number of chunk embeddings kept.
+	Chunks int64 `json:"chunks"`
+	// OrphanChunks is the number of chunk embeddings removed because their
+	// entry no longer exists.
+	OrphanChunks int64 `json:"orphan_chunks"`
+	// OrphanEmbeddings is the number of entry embeddings removed because
+	// their entry no longer exists.
+	OrphanEmbeddings int64 `json:"orphan_embeddings"`
+	DurationMS       int64 `json:"duration_ms"`
+}
+
+// ReindexSimilarity rebuilds the similarity index from the stored
+// embeddings: the norm and projection bucket of every embedded entry, and
+// the chunk embeddings of entries that still exist. It runs in a single
+// transaction and verifies the result before committing, so it recovers
+// from index drift without a window where FindSimilar sees a partial index.
+//
+// Soft-deleted entries keep their index columns and chunks; FindSimilar
+// excludes them by deleted_at, so a restore makes them matchable again in
+// the same statement that clears it.
+func (s *SQLiteStore) ReindexSimilarity(ctx context.Context) (*ReindexReport, error) {
+	start := time.Now()
+	report := &ReindexReport{}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE lore_entries SET embedding_norm = NULL, embedding_bucket = NULL
+		WHERE id NOT IN (SELECT lore_id FROM lore_embeddings)
+		  AND (embedding_norm IS NOT NULL OR embedding_bucket IS NOT NULL)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("clear stale index columns: %w", err)
+	}
+	report.Cleared, _ = result.RowsAffected()
+
+	if err := reindexEmbeddingsInTx(ctx, tx, report); err != nil {
+		return nil, err
+	}
+
+	result, err = tx.ExecContext(ctx, `DELETE FROM lore_chunks WHERE lore_id NOT IN (SELECT id FROM lore_entries)`)
+	if err != nil {
+		return nil, fmt.Errorf("remove orphan chunks: %w", err)
+	}
+	report.OrphanChunks, _ = result.RowsAffected()
+
+	result, err = tx.ExecContext(ctx, `DELETE FROM lore_embeddings WHERE lore_id NOT IN (SELECT id FROM lore_entries)`)
+	if err != nil {
+		return nil, fmt.Errorf("remove orphan embeddings: %w", err)
+	}
+	report.OrphanEmbeddings, _ = result.RowsAffected()
+
+	if err := verifySimilarityIndexInTx(ctx, tx, report); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	report.DurationMS = time.Since(start).Milliseconds()
+	slog.Info("similarity index rebuilt",
+		"component", "store",
+		"action", "reindex_similarity",
+		"store_id", s.storeID,
+		"embedded", report.Embedded,
+		"repaired", report.Repaired,
+		"cleared", report.Cleared,
+		"orphan_chunks", report.OrphanChunks,
+		"orphan_embeddings", report.OrphanEmbeddings,
+		"duration_ms", report.DurationMS,
+	)
+	return report, nil
+}
+
+// reindexEmbeddingsInTx recomputes the norm and bucket of every embedded
+// entry in ID order, rewriting those that differ.
+func reindexEmbeddingsInTx(ctx context.Context, tx *sql.Tx, report *ReindexReport) error {
+	const batch = 1000
+	type derived struct{ norm, bucket any }
+	afterID := ""
+	for {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT id, embedding, embedding_norm, embedding_bucket
+			FROM lore_entries JOIN lore_embeddings ON lore_embeddings.lore_id = lore_entries.id
+			WHERE id > ?
+			ORDER BY id
+			LIMIT ?
+		`, afterID, batch)
+		if err != nil {
+			return fmt.Errorf("query embeddings: %w", err)
+		}
+		stale := make(map[string]derived)
+		n := 0
+		for rows.Next() {
+			var (
+				id           string
+				blob         []byte
+				storedNorm   sql.NullFloat64
+				storedBucket sql.NullInt64
+			)
+			if err := rows.Scan(&id, &blob, &storedNorm, &storedBucket); err != nil {
+				rows.Close()
+				return fmt.Errorf("scan embedding: %w", err)
+			}
+			n++
+			afterID = id
+
+			// An empty blob has neither, matching backfillEmbeddingIndex.
+			d := derived{norm: 0.0, bucket: int64(0)}
+			if v := unpackEmbedding(blob); len(v) > 0 {
+				d = derived{norm: embeddingNorm(v), bucket: embeddingBucket(v)}
+			}
+			if !storedNorm.Valid || storedNorm.Float64 != d.norm || !storedBucket.Valid || storedBucket.Int64 != d.bucket {
+				stale[id] = d
+			}
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return fmt.Errorf("iterate embeddings: %w", err)
+		}
+		rows.Close()
+		report.Embedded += int64(n)
+
+		for id, d := range stale {
+			if _, err := tx.ExecContext(ctx, `UPDATE lore_entries SET embedding_norm = ?, embedding_bucket = ? WHERE id = ?`, d.norm, d.bucket, id); err != nil {
+				return fmt.Errorf("store embedding index: %w", err)
+			}
+		}
+		report.Repaired += int64(len(stale))
+		if n < batch {
+			return nil
+		}
+	}
+}
+
+// verifySimilarityIndexInTx checks that every embedded entry is indexed and
+// no chunk outlives its entry, and fills in the report's live and chunk
+// counts.
+func verifySimilarityIndexInTx(ctx context.Context, tx *sql.Tx, report *ReindexReport) error {
+	var embedded, indexed, orphans int64
+	err := tx.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE embedding_norm IS NOT NULL AND embedding_bucket IS NOT NULL),
+			COUNT(*) FILTER (WHERE deleted_at IS NULL)
+		FROM lore_entries JOIN lore_embeddings ON lore_embeddings.lore_id = lore_entries.id
+	`).Scan(&embedded, &indexed, &report.Live)
+	if err != nil {
+		return fmt.Errorf("count indexed entries: %w", err)
+	}
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE lore_id NOT IN (SELECT id FROM lore_entries))
+		FROM lore_chunks
+	`).Scan(&report.Chunks, &orphans)
+	if err != nil {
+		return fmt.Errorf("count chunks: %w", err)
+	}
+
+	if embedded != report.Embedded || indexed != embedded || orphans != 0 {
+		return fmt.Errorf("%w: %d embedded entries, %d scanned, %d indexed, %d orphan chunks",
+			ErrReindexMismatch, embedded, report.Embedded, indexed, orphans)
+	}
+	return nil
+}
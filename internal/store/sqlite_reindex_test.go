@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func TestFindSimilar_SoftDeleteAndRestore(t *testing.T) {
+	content := longContent()
+	s := chunkedStore(t, content, 1, 2)
+	ctx := context.Background()
+	if _, err := s.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: content, Category: "ARCHITECTURAL_DECISION", Confidence: 0.8, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var id string
+	s.db.QueryRow(`SELECT id FROM lore_entries`).Scan(&id)
+	entry, err := s.GetLore(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	similar := func() int {
+		t.Helper()
+		// Matches the whole-entry embedding and, separately, a chunk
+		n := 0
+		for _, q := range [][]float32{makeTestEmbedding(1), makeTestEmbedding(2)} {
+			results, err := s.FindSimilar(ctx, q, "ARCHITECTURAL_DECISION", 0.9)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n += len(results)
+		}
+		return n
+	}
+	if got := similar(); got != 2 {
+		t.Fatalf("live entry matched %d times, want 2", got)
+	}
+
+	if err := s.DeleteLore(ctx, id, "src"); err != nil {
+		t.Fatal(err)
+	}
+	if got := similar(); got != 0 {
+		t.Errorf("deleted entry matched %d times, want 0", got)
+	}
+
+	// A replayed upsert without deleted_at restores the entry and its index
+	entry.DeletedAt = nil
+	payload, _ := json.Marshal(entry)
+	if err := s.UpsertRow(ctx, "lore_entries", id, payload); err != nil {
+		t.Fatal(err)
+	}
+	if got := similar(); got != 2 {
+		t.Errorf("restored entry matched %d times, want 2", got)
+	}
+}
+
+func TestReindexSimilarity_RepairsDrift(t *testing.T) {
+	content := longContent()
+	s := chunkedStore(t, content, 1, 2)
+	ctx := context.Background()
+	if _, err := s.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: content, Category: "ARCHITECTURAL_DECISION", Confidence: 0.8, SourceID: "src"},
+		{Content: "Short entry", Category: "ARCHITECTURAL_DECISION", Confidence: 0.8, SourceID: "src"},
+		{Content: "Deleted entry", Category: "ARCHITECTURAL_DECISION", Confidence: 0.8, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var longID, shortID, deletedID string
+	s.db.QueryRow(`SELECT id FROM lore_entries WHERE content = ?`, content).Scan(&longID)
+	s.db.QueryRow(`SELECT id FROM lore_entries WHERE content = 'Short entry'`).Scan(&shortID)
+	s.db.QueryRow(`SELECT id FROM lore_entries WHERE content = 'Deleted entry'`).Scan(&deletedID)
+	if err := s.DeleteLore(ctx, deletedID, "src"); err != nil {
+		t.Fatal(err)
+	}
+
+	clean, err := s.ReindexSimilarity(ctx)
+	if err != nil {
+		t.Fatalf("ReindexSimilarity() error = %v", err)
+	}
+	if clean.Embedded != 3 || clean.Live != 2 || clean.Repaired != 0 || clean.Cleared != 0 || clean.OrphanChunks != 0 {
+		t.Errorf("report on a consistent index = %+v", clean)
+	}
+
+	var wantNorm float64
+	var wantBucket int64
+	s.db.QueryRow(`SELECT embedding_norm, embedding_bucket FROM lore_entries WHERE id = ?`, longID).Scan(&wantNorm, &wantBucket)
+
+	// Drift: a lost bucket, a wrong norm, index columns without an
+	// embedding, and chunks and an embedding of an entry that no longer
+	// exists
+	for _, stmt := range []string{
+		`UPDATE lore_entries SET embedding_bucket = NULL WHERE id = '` + longID + `'`,
+		`UPDATE lore_entries SET embedding_norm = 42 WHERE id = '` + deletedID + `'`,
+		`DELETE FROM lore_embeddings WHERE lore_id = '` + shortID + `'`,
+		`INSERT INTO lore_chunks (lore_id, chunk_index, embedding, embedding_norm) VALUES ('gone', 0, x'0000803f', 1)`,
+		`INSERT INTO lore_embeddings (lore_id, embedding) VALUES ('gone', x'0000803f')`,
+	} {
+		if _, err := s.db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := s.ReindexSimilarity(ctx)
+	if err != nil {
+		t.Fatalf("ReindexSimilarity() error = %v", err)
+	}
+	if report.Embedded != 2 || report.Live != 1 || report.Repaired != 2 || report.Cleared != 1 || report.OrphanChunks != 1 || report.OrphanEmbeddings != 1 {
+		t.Errorf("report = %+v", report)
+	}
+	if report.Chunks != int64(countChunks(t, s, longID)) {
+		t.Errorf("report counts %d chunks, want %d", report.Chunks, countChunks(t, s, longID))
+	}
+
+	var gotNorm float64
+	var gotBucket int64
+	s.db.QueryRow(`SELECT embedding_norm, embedding_bucket FROM lore_entries WHERE id = ?`, longID).Scan(&gotNorm, &gotBucket)
+	if gotNorm != wantNorm || gotBucket != wantBucket {
+		t.Errorf("rebuilt index = (%v, %d), want (%v, %d)", gotNorm, gotBucket, wantNorm, wantBucket)
+	}
+	var stale int
+	s.db.QueryRow(`SELECT COUNT(*) FROM lore_entries WHERE id = ? AND (embedding_norm IS NOT NULL OR embedding_bucket IS NOT NULL)`, shortID).Scan(&stale)
+	if stale != 0 {
+		t.Error("index columns of an entry without an embedding were kept")
+	}
+}
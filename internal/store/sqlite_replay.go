@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hyperengineering/engram/internal/language"
 	"github.com/hyperengineering/engram/internal/plugin"
 	engramsync "github.com/hyperengineering/engram/internal/sync"
 )
@@ -27,7 +28,7 @@ func (s *SQLiteStore) UpsertRow(ctx context.Context, tableName string, entityID
 
 	// Legacy hardcoded path for lore_entries (Recall backward compat)
 	if tableName == "lore_entries" {
-		return upsertLoreEntry(ctx, s.db, entityID, payload)
+		return upsertLoreEntry(ctx, s.db, entityID, payload, s.quantization)
 	}
 
 	return fmt.Errorf("unsupported table: %s", tableName)
@@ -55,7 +56,7 @@ func (s *SQLiteStore) QueueEmbedding(ctx context.Context, entryID string) error
 	_, err := s.db.ExecContext(ctx, `
 		UPDATE lore_entries
 		SET embedding_status = 'pending'
-		WHERE id = ? AND embedding IS NULL AND embedding_status != 'pending'
+		WHERE id = ? AND id NOT IN (SELECT lore_id FROM lore_embeddings) AND embedding_status != 'pending'
 	`, entryID)
 	if err != nil {
 		return fmt.Errorf("queue embedding: %w", err)
@@ -67,7 +68,11 @@ func (s *SQLiteStore) QueueEmbedding(ctx context.Context, entryID string) error
 
 // upsertLoreEntry performs the lore_entries-specific upsert with specialized
 // struct deserialization, embedding handling, and sources JSON marshaling.
-func upsertLoreEntry(ctx context.Context, execer execContext, entityID string, payload []byte) error {
+//
+// Clients may omit the embedding to save bandwidth. The entry then keeps the
+// embedding already stored for the same content, or is left pending for the
+// embedding worker to generate. The embedding is stored with quantization q.
+func upsertLoreEntry(ctx context.Context, execer queryContext, entityID string, payload []byte, q Quantization) error {
 	var row loreRow
 	if err := json.Unmarshal(payload, &row); err != nil {
 		return fmt.Errorf("unmarshal payload: %w", err)
@@ -82,39 +87,71 @@ func upsertLoreEntry(ctx context.Context, execer execContext, entityID string, p
 		return fmt.Errorf("marshal sources: %w", err)
 	}
 
-	var embeddingBlob []byte
-	if len(row.Embedding) > 0 {
-		embeddingBlob = packEmbedding(row.Embedding)
+	embedding, embeddingStatus := row.Embedding, row.EmbeddingStatus
+	if len(embedding) == 0 {
+		var blob []byte
+		err := execer.QueryRowContext(ctx, `
+			SELECT embedding, embedding_status
+			FROM lore_entries JOIN lore_embeddings ON lore_embeddings.lore_id = lore_entries.id
+			WHERE id = ? AND content = ?
+		`, row.ID, row.Content).Scan(&blob, &embeddingStatus)
+		switch {
+		case err == sql.ErrNoRows:
+			embeddingStatus = "pending"
+		case err != nil:
+			return fmt.Errorf("query stored embedding: %w", err)
+		default:
+			embedding = unpackEmbedding(blob)
+		}
 	}
-
-	now := time.Now().UTC().Format(time.RFC3339Nano)
-
-	embeddingStatus := row.EmbeddingStatus
 	if embeddingStatus == "" {
 		embeddingStatus = "pending"
 	}
+	stored := q.round(embedding)
 
-	createdAt := row.CreatedAt
+	now := formatTime(time.Now())
+
+	createdAt := normalizeTime(row.CreatedAt)
 	if createdAt == "" {
 		createdAt = now
 	}
 
+	lang := row.Language
+	if lang == "" {
+		lang = language.Detect(row.Content)
+	}
+
+	if err := saveLoreVersion(ctx, execer, row.ID, row.Content, row.Context, row.Category, VersionReasonSync, "", now); err != nil {
+		return err
+	}
+
+	// Chunk embeddings describe the local content; drop them if the
+	// replayed row changes it.
+	_, err = execer.ExecContext(ctx, `
+		DELETE FROM lore_chunks WHERE lore_id = ?
+		AND NOT EXISTS (SELECT 1 FROM lore_entries WHERE id = ? AND content = ?)
+	`, row.ID, row.ID, row.Content)
+	if err != nil {
+		return fmt.Errorf("clear lore chunks: %w", err)
+	}
+
 	// INSERT OR REPLACE in SQLite deletes the existing row then inserts
 	// a new one (rather than updating in-place). This is safe for
 	// lore_entries because it has no child FK relationships. Multi-table
 	// plugins should use INSERT ... ON CONFLICT ... DO UPDATE instead.
 	_, err = execer.ExecContext(ctx, `
 		INSERT OR REPLACE INTO lore_entries (
-			id, content, context, category, confidence, embedding, embedding_status,
-			source_id, sources, validation_count, created_at, updated_at, deleted_at, last_validated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			id, content, context, category, confidence, embedding_norm, embedding_bucket, embedding_status,
+			source_id, sources, validation_count, created_at, updated_at, deleted_at, last_validated_at, language, repo, path
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		row.ID,
 		row.Content,
 		row.Context,
 		row.Category,
 		row.Confidence,
-		embeddingBlob,
+		embeddingNorm(stored),
+		embeddingBucket(stored),
 		embeddingStatus,
 		row.SourceID,
 		string(sourcesJSON),
@@ -123,17 +160,20 @@ func upsertLoreEntry(ctx context.Context, execer execContext, entityID string, p
 		now,
 		formatNullableTime(row.DeletedAt),
 		formatNullableTime(row.LastValidatedAt),
+		lang,
+		row.Repo,
+		row.Path,
 	)
 	if err != nil {
 		return fmt.Errorf("upsert lore entry: %w", err)
 	}
 
-	return nil
+	return storeEmbeddingInTx(ctx, execer, row.ID, embedding, q)
 }
 
 // deleteLoreEntry performs lore_entries-specific soft delete.
 func deleteLoreEntry(ctx context.Context, execer execContext, entityID string) error {
-	now := time.Now().UTC().Format(time.RFC3339Nano)
+	now := formatTime(time.Now())
 
 	_, err := execer.ExecContext(ctx, `
 		UPDATE lore_entries
@@ -164,7 +204,7 @@ func genericUpsertRow(ctx context.Context, execer execContext, schema plugin.Tab
 	}
 
 	// 3. Set updated_at to now (if column exists in schema)
-	now := time.Now().UTC().Format(time.RFC3339Nano)
+	now := formatTime(time.Now())
 	hasUpdatedAt := false
 	for _, col := range schema.Columns {
 		if col == "updated_at" {
@@ -208,7 +248,7 @@ func genericUpsertRow(ctx context.Context, execer execContext, schema plugin.Tab
 // genericDeleteRow performs soft or hard delete based on the schema's SoftDelete flag.
 func genericDeleteRow(ctx context.Context, execer execContext, schema plugin.TableSchema, entityID string) error {
 	if schema.SoftDelete {
-		now := time.Now().UTC().Format(time.RFC3339Nano)
+		now := formatTime(time.Now())
 		sqlStr := fmt.Sprintf(
 			"UPDATE %s SET deleted_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL",
 			schema.Name,
@@ -258,14 +298,18 @@ type loreRow struct {
 	UpdatedAt       string    `json:"updated_at"`
 	DeletedAt       *string   `json:"deleted_at"`
 	LastValidatedAt *string   `json:"last_validated_at"`
+	Language        string    `json:"language"`
+	Repo            string    `json:"repo"`
+	Path            string    `json:"path"`
 }
 
-// formatNullableTime converts a string pointer to a sql-friendly format.
+// formatNullableTime converts an optional client timestamp to its stored
+// form, or NULL when absent.
 func formatNullableTime(t *string) any {
 	if t == nil || *t == "" {
 		return nil
 	}
-	return *t
+	return normalizeTime(*t)
 }
 
 // --- Transaction-scoped replay functions ---
@@ -281,16 +325,10 @@ func (s *SQLiteStore) AppendChangeLogBatchTx(ctx context.Context, tx *sql.Tx, en
 	var maxSeq int64
 
 	for i := range entries {
-		result, err := tx.ExecContext(ctx, insertChangeLogSQL,
-			changeLogArgs(&entries[i])...)
+		seq, err := s.appendChangeLogEntry(ctx, tx, &entries[i])
 		if err != nil {
 			return 0, fmt.Errorf("insert change log entry: %w", err)
 		}
-
-		seq, err := result.LastInsertId()
-		if err != nil {
-			return 0, fmt.Errorf("get last insert id: %w", err)
-		}
 		if seq > maxSeq {
 			maxSeq = seq
 		}
@@ -299,8 +337,20 @@ func (s *SQLiteStore) AppendChangeLogBatchTx(ctx context.Context, tx *sql.Tx, en
 	return maxSeq, nil
 }
 
-// UpsertRowTx performs an upsert within a transaction.
+// UpsertRowTx performs an upsert within a transaction. Embeddings are
+// stored unquantized; SQLiteStore.UpsertRowTx applies the store's
+// quantization.
 func UpsertRowTx(ctx context.Context, tx *sql.Tx, tableName, entityID string, payload []byte) error {
+	return upsertRowTx(ctx, tx, tableName, entityID, payload, QuantizationNone)
+}
+
+// UpsertRowTx performs an upsert within a transaction, storing embeddings
+// with the store's quantization.
+func (s *SQLiteStore) UpsertRowTx(ctx context.Context, tx *sql.Tx, tableName, entityID string, payload []byte) error {
+	return upsertRowTx(ctx, tx, tableName, entityID, payload, s.quantization)
+}
+
+func upsertRowTx(ctx context.Context, tx *sql.Tx, tableName, entityID string, payload []byte, q Quantization) error {
 	// Check for registered table schema first (generic path)
 	if schema, ok := plugin.GetTableSchema(tableName); ok {
 		return genericUpsertRow(ctx, tx, schema, entityID, payload)
@@ -308,7 +358,7 @@ func UpsertRowTx(ctx context.Context, tx *sql.Tx, tableName, entityID string, pa
 
 	// Legacy hardcoded path for lore_entries
 	if tableName == "lore_entries" {
-		return upsertLoreEntry(ctx, tx, entityID, payload)
+		return upsertLoreEntry(ctx, tx, entityID, payload, q)
 	}
 
 	return fmt.Errorf("unsupported table: %s", tableName)
@@ -333,7 +383,7 @@ func DeleteRowTx(ctx context.Context, tx *sql.Tx, tableName, entityID string) er
 func QueueEmbeddingTx(ctx context.Context, tx *sql.Tx, entryID string) error {
 	_, err := tx.ExecContext(ctx, `
 		UPDATE lore_entries SET embedding_status = 'pending'
-		WHERE id = ? AND embedding IS NULL AND embedding_status != 'pending'
+		WHERE id = ? AND id NOT IN (SELECT lore_id FROM lore_embeddings) AND embedding_status != 'pending'
 	`, entryID)
 	if err != nil {
 		return fmt.Errorf("queue embedding: %w", err)
@@ -159,6 +159,28 @@ func TestUpsertRow_WithEmbedding(t *testing.T) {
 	}
 }
 
+func TestUpsertRow_KeepsLanguageAndScope(t *testing.T) {
+	s := newReplayTestStore(t)
+	ctx := context.Background()
+
+	payload := makeLorePayload(t, map[string]interface{}{
+		"language": "de",
+		"repo":     "acme/payments",
+		"path":     "internal/queue",
+	})
+	if err := s.UpsertRow(ctx, "lore_entries", "entry-1", payload); err != nil {
+		t.Fatalf("UpsertRow() error = %v", err)
+	}
+
+	entry, err := s.GetLore(ctx, "entry-1")
+	if err != nil {
+		t.Fatalf("GetLore() error = %v", err)
+	}
+	if entry.Language != "de" || entry.Repo != "acme/payments" || entry.Path != "internal/queue" {
+		t.Errorf("language, repo, path = %q, %q, %q", entry.Language, entry.Repo, entry.Path)
+	}
+}
+
 func TestUpsertRow_WithoutEmbedding_DefaultsPending(t *testing.T) {
 	s := newReplayTestStore(t)
 	ctx := context.Background()
@@ -178,6 +200,50 @@ func TestUpsertRow_WithoutEmbedding_DefaultsPending(t *testing.T) {
 	}
 }
 
+func TestUpsertRow_WithoutEmbedding_KeepsStoredEmbedding(t *testing.T) {
+	s := newReplayTestStore(t)
+	ctx := context.Background()
+
+	payload := makeLorePayload(t, map[string]interface{}{
+		"embedding":        []float32{0.1, 0.2, 0.3},
+		"embedding_status": "complete",
+	})
+	if err := s.UpsertRow(ctx, "lore_entries", "entry-1", payload); err != nil {
+		t.Fatalf("UpsertRow() error = %v", err)
+	}
+
+	// Same content, no embedding: the stored one still describes it
+	payload = makeLorePayload(t, map[string]interface{}{"confidence": 0.9, "embedding_status": "complete"})
+	if err := s.UpsertRow(ctx, "lore_entries", "entry-1", payload); err != nil {
+		t.Fatalf("UpsertRow() error = %v", err)
+	}
+	entry, err := s.GetLore(ctx, "entry-1")
+	if err != nil {
+		t.Fatalf("GetLore() error = %v", err)
+	}
+	if len(entry.Embedding) != 3 || entry.EmbeddingStatus != "complete" || entry.Confidence != 0.9 {
+		t.Errorf("entry = %d dims, %q, confidence %v; want the stored embedding kept", len(entry.Embedding), entry.EmbeddingStatus, entry.Confidence)
+	}
+	var norm sql.NullFloat64
+	s.db.QueryRow(`SELECT embedding_norm FROM lore_entries WHERE id = 'entry-1'`).Scan(&norm)
+	if !norm.Valid || norm.Float64 == 0 {
+		t.Errorf("embedding_norm = %v, want the kept embedding's norm", norm)
+	}
+
+	// New content, no embedding: the server has to generate one
+	payload = makeLorePayload(t, map[string]interface{}{"content": "Changed content", "embedding_status": "complete"})
+	if err := s.UpsertRow(ctx, "lore_entries", "entry-1", payload); err != nil {
+		t.Fatalf("UpsertRow() error = %v", err)
+	}
+	entry, err = s.GetLore(ctx, "entry-1")
+	if err != nil {
+		t.Fatalf("GetLore() error = %v", err)
+	}
+	if len(entry.Embedding) != 0 || entry.EmbeddingStatus != "pending" {
+		t.Errorf("entry = %d dims, %q; want no embedding, pending", len(entry.Embedding), entry.EmbeddingStatus)
+	}
+}
+
 func TestUpsertRow_UnsupportedTable(t *testing.T) {
 	s := newReplayTestStore(t)
 	ctx := context.Background()
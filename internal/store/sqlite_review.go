@@ -0,0 +1,206 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/plugin"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// Review queue defaults and bounds.
+const (
+	DefaultReviewMaxConfidence = 0.3
+	DefaultReviewMinIncorrect  = 2
+	DefaultReviewQueueLimit    = 50
+	MaxReviewQueueLimit        = 500
+)
+
+// ListReviewQueue returns unreviewed entries that are low-confidence or
+// frequently flagged incorrect, most-flagged first and then least
+// confident. Deleted entries are excluded, as are entries in other
+// languages when q.Language is set. With q.After set, the queue starts
+// after that candidate's position.
+func (s *SQLiteStore) ListReviewQueue(ctx context.Context, q types.ReviewQueueQuery) ([]types.ReviewCandidate, error) {
+	var after types.ReviewQueueKey
+	if q.After != nil {
+		after = *q.After
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, content, context, category, language, confidence, validation_count, created_at, incorrect
+		FROM (
+			SELECT l.*,
+			       (SELECT COUNT(*) FROM feedback_events f
+			        WHERE f.lore_id = l.id AND f.type = 'incorrect') AS incorrect
+			FROM lore_entries l
+			WHERE l.deleted_at IS NULL
+			  AND (? = '' OR l.language = ?)
+			  AND NOT EXISTS (SELECT 1 FROM lore_reviews r WHERE r.lore_id = l.id)
+		)
+		WHERE (confidence < ? OR incorrect >= ?)
+		  AND (? = '' OR incorrect < ?
+		       OR (incorrect = ? AND (confidence > ? OR (confidence = ? AND id > ?))))
+		ORDER BY incorrect DESC, confidence ASC, id ASC
+		LIMIT ?
+	`, q.Language, q.Language, q.MaxConfidence, q.MinIncorrect,
+		after.ID, after.IncorrectCount, after.IncorrectCount, after.Confidence, after.Confidence, after.ID,
+		q.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("query review queue: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := make([]types.ReviewCandidate, 0)
+	for rows.Next() {
+		var (
+			c         types.ReviewCandidate
+			loreCtx   sql.NullString
+			lang      sql.NullString
+			createdAt string
+		)
+		if err := rows.Scan(&c.ID, &c.Content, &loreCtx, &c.Category, &lang, &c.Confidence,
+			&c.ValidationCount, &createdAt, &c.IncorrectCount); err != nil {
+			return nil, fmt.Errorf("scan review candidate: %w", err)
+		}
+		c.Context = loreCtx.String
+		c.Language = lang.String
+		if c.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return nil, fmt.Errorf("parse created_at: %w", err)
+		}
+		c.Reasons = make([]string, 0, 2)
+		if c.Confidence < q.MaxConfidence {
+			c.Reasons = append(c.Reasons, "low_confidence")
+		}
+		if c.IncorrectCount >= q.MinIncorrect {
+			c.Reasons = append(c.Reasons, "flagged_incorrect")
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// GetReview returns the review state of a lore entry. Entries never
+// reviewed report ReviewUnreviewed. Rejected entries remain visible here
+// after their soft delete; entries that never existed return ErrNotFound.
+func (s *SQLiteStore) GetReview(ctx context.Context, loreID string) (*types.LoreReview, error) {
+	var (
+		status, reviewer string
+		note, reviewedAt sql.NullString
+		floor            sql.NullFloat64
+		deletedAt        sql.NullString
+	)
+	err := s.db.QueryRowContext(ctx, `
+		SELECT l.deleted_at, COALESCE(r.status, ''), COALESCE(r.reviewer, ''), r.note, r.reviewed_at, k.floor
+		FROM lore_entries l
+		LEFT JOIN lore_reviews r ON r.lore_id = l.id
+		LEFT JOIN confidence_locks k ON k.lore_id = l.id
+		WHERE l.id = ?
+	`, loreID).Scan(&deletedAt, &status, &reviewer, &note, &reviewedAt, &floor)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query review: %w", err)
+	}
+	if status == "" {
+		if deletedAt.Valid {
+			return nil, ErrNotFound
+		}
+		return &types.LoreReview{LoreID: loreID, Status: types.ReviewUnreviewed}, nil
+	}
+
+	review := &types.LoreReview{
+		LoreID:   loreID,
+		Status:   types.ReviewStatus(status),
+		Reviewer: reviewer,
+		Note:     note.String,
+	}
+	if floor.Valid {
+		review.ConfidenceFloor = &floor.Float64
+	}
+	if reviewedAt.Valid {
+		t, err := time.Parse(time.RFC3339, reviewedAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("parse reviewed_at: %w", err)
+		}
+		review.ReviewedAt = &t
+	}
+	return review, nil
+}
+
+// ReviewLore records a reviewer's decision on an unreviewed entry.
+// Approving locks a confidence floor at the entry's current confidence, so
+// feedback and decay can no longer push it lower. Rejecting soft-deletes the
+// entry, running the same delete hooks as DeleteLore. Returns ErrNotFound if
+// the entry doesn't exist or is deleted, and ErrAlreadyReviewed if a decision
+// was already recorded.
+func (s *SQLiteStore) ReviewLore(ctx context.Context, loreID string, status types.ReviewStatus, reviewer, note string) (*types.LoreReview, error) {
+	if status != types.ReviewApproved && status != types.ReviewRejected {
+		return nil, fmt.Errorf("invalid review status %q", status)
+	}
+	if status == types.ReviewRejected {
+		if h, ok := s.hooks.(plugin.BeforeDeleteHook); ok {
+			if err := h.BeforeDelete(ctx, loreID); err != nil {
+				return nil, fmt.Errorf("before delete hook: %w", err)
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	nowStr := formatTime(now)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var confidence float64
+	var reviewed bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT confidence, EXISTS (SELECT 1 FROM lore_reviews r WHERE r.lore_id = lore_entries.id)
+		FROM lore_entries
+		WHERE id = ? AND deleted_at IS NULL
+	`, loreID).Scan(&confidence, &reviewed)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch lore entry: %w", err)
+	}
+	if reviewed {
+		return nil, ErrAlreadyReviewed
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO lore_reviews (lore_id, status, reviewer, note, reviewed_at) VALUES (?, ?, ?, ?, ?)
+	`, loreID, string(status), reviewer, sql.NullString{String: note, Valid: note != ""}, nowStr); err != nil {
+		return nil, fmt.Errorf("record review: %w", err)
+	}
+
+	review := &types.LoreReview{
+		LoreID:     loreID,
+		Status:     status,
+		Reviewer:   reviewer,
+		Note:       note,
+		ReviewedAt: &now,
+	}
+	if status == types.ReviewApproved {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO confidence_locks (lore_id, floor, set_by, set_at) VALUES (?, ?, ?, ?)
+			ON CONFLICT(lore_id) DO UPDATE SET floor = excluded.floor, set_by = excluded.set_by, set_at = excluded.set_at
+		`, loreID, confidence, reviewer, nowStr); err != nil {
+			return nil, fmt.Errorf("lock confidence floor: %w", err)
+		}
+		review.ConfidenceFloor = &confidence
+	} else if err := s.softDeleteLoreInTx(ctx, tx, loreID, reviewer, nowStr); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return review, nil
+}
@@ -0,0 +1,178 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func newReviewTestStore(t *testing.T, entries ...types.NewLoreEntry) *SQLiteStore {
+	t.Helper()
+	s := newTestStore(t)
+	if _, err := s.IngestLore(context.Background(), entries); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestListReviewQueue_LowConfidenceAndFlagged(t *testing.T) {
+	s := newReviewTestStore(t,
+		types.NewLoreEntry{Content: "Low confidence", Category: "PATTERN_OUTCOME", Confidence: 0.2, SourceID: "src"},
+		types.NewLoreEntry{Content: "Often flagged", Category: "PATTERN_OUTCOME", Confidence: 0.9, SourceID: "src"},
+		types.NewLoreEntry{Content: "Healthy", Category: "PATTERN_OUTCOME", Confidence: 0.9, SourceID: "src"},
+		types.NewLoreEntry{Content: "Already approved", Category: "PATTERN_OUTCOME", Confidence: 0.1, SourceID: "src"},
+	)
+	ctx := context.Background()
+	flagged := digestTestLoreID(t, s, "Often flagged")
+	for i := 0; i < 2; i++ {
+		if _, err := s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: flagged, Type: "incorrect"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.ReviewLore(ctx, digestTestLoreID(t, s, "Already approved"), types.ReviewApproved, "alice", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	queue, err := s.ListReviewQueue(ctx, types.ReviewQueueQuery{MaxConfidence: 0.3, MinIncorrect: 2, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queue) != 2 {
+		t.Fatalf("queue = %+v, want 2 entries", queue)
+	}
+	if queue[0].Content != "Often flagged" || queue[0].IncorrectCount != 2 || queue[0].Reasons[0] != "flagged_incorrect" {
+		t.Errorf("first = %+v, want the flagged entry", queue[0])
+	}
+	if queue[1].Content != "Low confidence" || len(queue[1].Reasons) != 1 || queue[1].Reasons[0] != "low_confidence" {
+		t.Errorf("second = %+v, want the low-confidence entry", queue[1])
+	}
+
+	limited, err := s.ListReviewQueue(ctx, types.ReviewQueueQuery{MaxConfidence: 0.3, MinIncorrect: 2, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(limited) != 1 {
+		t.Fatalf("limit 1 returned %d entries", len(limited))
+	}
+
+	after := &types.ReviewQueueKey{IncorrectCount: limited[0].IncorrectCount, Confidence: limited[0].Confidence, ID: limited[0].ID}
+	next, err := s.ListReviewQueue(ctx, types.ReviewQueueQuery{MaxConfidence: 0.3, MinIncorrect: 2, Limit: 10, After: after})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next) != 1 || next[0].ID != queue[1].ID {
+		t.Errorf("after the first entry = %+v, want only the second", next)
+	}
+}
+
+func TestReviewLore_ApproveLocksConfidenceFloor(t *testing.T) {
+	s := newReviewTestStore(t,
+		types.NewLoreEntry{Content: "Approved lore", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src"},
+	)
+	ctx := context.Background()
+	id := digestTestLoreID(t, s, "Approved lore")
+
+	review, err := s.ReviewLore(ctx, id, types.ReviewApproved, "alice", "checked")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if review.ConfidenceFloor == nil || *review.ConfidenceFloor != 0.5 {
+		t.Errorf("floor = %v, want 0.5", review.ConfidenceFloor)
+	}
+
+	result, err := s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: id, Type: "incorrect"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.Updates[0].CurrentConfidence; got != 0.5 {
+		t.Errorf("confidence after incorrect = %v, want floor 0.5", got)
+	}
+
+	if _, err := s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: id, Type: "helpful"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.DecayConfidence(ctx, time.Now().Add(time.Hour), 0.5); err != nil {
+		t.Fatal(err)
+	}
+	entry, _ := s.GetLore(ctx, id)
+	if entry.Confidence != 0.5 {
+		t.Errorf("confidence after decay = %v, want floor 0.5", entry.Confidence)
+	}
+
+	affected, err := s.DecayConfidence(ctx, time.Now().Add(time.Hour), 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affected != 0 {
+		t.Errorf("decay touched %d entries at their floor, want 0", affected)
+	}
+
+	got, err := s.GetReview(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != types.ReviewApproved || got.Reviewer != "alice" || got.Note != "checked" || got.ReviewedAt == nil {
+		t.Errorf("GetReview = %+v", got)
+	}
+
+	if _, err := s.ReviewLore(ctx, id, types.ReviewRejected, "bob", ""); !errors.Is(err, ErrAlreadyReviewed) {
+		t.Errorf("second review: err = %v, want ErrAlreadyReviewed", err)
+	}
+}
+
+func TestReviewLore_RejectSoftDeletes(t *testing.T) {
+	s := newReviewTestStore(t,
+		types.NewLoreEntry{Content: "Wrong lore", Category: "PATTERN_OUTCOME", Confidence: 0.2, SourceID: "src"},
+	)
+	ctx := context.Background()
+	id := digestTestLoreID(t, s, "Wrong lore")
+
+	if _, err := s.ReviewLore(ctx, id, types.ReviewRejected, "bob", "superseded"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetLore(ctx, id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetLore after reject: err = %v, want ErrNotFound", err)
+	}
+
+	var source string
+	if err := s.db.QueryRow(`SELECT source_id FROM change_log WHERE entity_id = ? AND operation = 'delete'`, id).Scan(&source); err != nil {
+		t.Fatalf("expected a delete in change_log: %v", err)
+	}
+	if source != "bob" {
+		t.Errorf("change_log source = %q, want the reviewer", source)
+	}
+
+	review, err := s.GetReview(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if review.Status != types.ReviewRejected || review.ConfidenceFloor != nil {
+		t.Errorf("GetReview = %+v, want rejected without a floor", review)
+	}
+
+	if _, err := s.ReviewLore(ctx, id, types.ReviewApproved, "alice", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("review of deleted entry: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetReview_UnreviewedAndMissing(t *testing.T) {
+	s := newReviewTestStore(t,
+		types.NewLoreEntry{Content: "Fresh lore", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src"},
+	)
+	ctx := context.Background()
+
+	review, err := s.GetReview(ctx, digestTestLoreID(t, s, "Fresh lore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if review.Status != types.ReviewUnreviewed {
+		t.Errorf("status = %q, want unreviewed", review.Status)
+	}
+
+	if _, err := s.GetReview(ctx, "01ARZ3NDEKTSV4RRFFQ69G5FAV"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing entry: err = %v, want ErrNotFound", err)
+	}
+}
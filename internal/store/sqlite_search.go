@@ -0,0 +1,652 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/migrations"
+)
+
+// Search limits.
+const (
+	DefaultSearchLimit = 10
+	MaxSearchLimit     = 100
+
+	// searchCandidateLimit bounds the keyword matches scored per search.
+	searchCandidateLimit = 1000
+
+	// searchFeatureBatch bounds the IDs bound into one feature query.
+	searchFeatureBatch = 500
+)
+
+// DefaultSearchRanking weighs keyword and semantic relevance equally. RRFK
+// is the conventional reciprocal rank fusion constant.
+var DefaultSearchRanking = types.SearchRanking{
+	Fusion:         types.SearchFusionWeighted,
+	KeywordWeight:  0.5,
+	SemanticWeight: 0.5,
+	RRFK:           60,
+}
+
+// DefaultSearchBoost gives each quality feature a modest weight, so quality
+// reorders entries of similar relevance without overriding relevance itself.
+var DefaultSearchBoost = types.SearchBoost{
+	ConfidenceWeight: 0.1,
+	ValidationWeight: 0.1,
+	FeedbackWeight:   0.1,
+	RecencyWeight:    0.1,
+	ValidationPivot:  5,
+	RecencyHalfLife:  90 * 24 * time.Hour,
+}
+
+// SearchLore ranks active lore entries against a query.
+//
+// Keyword relevance is the FTS5 BM25 score over content and context,
+// normalized so the best match scores 1. Semantic relevance is the cosine
+// similarity between the query embedding and the entry embedding, clamped
+// to [0, 1]. Hybrid mode fuses the two as described by q.Ranking, or by
+// DefaultSearchRanking when it is unset. The resulting relevance is then
+// scaled by q.Boost. Results are ordered by score, highest first, and carry
+// no embeddings.
+func (s *SQLiteStore) SearchLore(ctx context.Context, q types.SearchQuery) ([]types.SearchResult, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+	ranking := q.Ranking
+	if ranking == (types.SearchRanking{}) {
+		ranking = DefaultSearchRanking
+	}
+	if q.Mode == types.SearchModeHybrid {
+		if err := ranking.Validate(); err != nil {
+			return nil, fmt.Errorf("search ranking: %w", err)
+		}
+	}
+	if err := q.Boost.Validate(); err != nil {
+		return nil, fmt.Errorf("search boost: %w", err)
+	}
+	if err := q.Quotas.Validate(limit); err != nil {
+		return nil, fmt.Errorf("category quotas: %w", err)
+	}
+
+	var keyword, semantic map[string]float64
+	entries := make(map[string]*types.LoreEntry)
+	var err error
+
+	if q.Mode != types.SearchModeSemantic {
+		if keyword, err = s.keywordScores(ctx, q); err != nil {
+			return nil, err
+		}
+	}
+	if q.Mode != types.SearchModeKeyword {
+		if len(q.Embedding) == 0 {
+			return nil, fmt.Errorf("%s search without query embedding: %w", q.Mode, ErrEmbeddingUnavailable)
+		}
+		if err := s.checkDimensions(q.Embedding); err != nil {
+			return nil, err
+		}
+		if semantic, err = s.semanticScores(ctx, q, entries); err != nil {
+			return nil, err
+		}
+	}
+	keywordRanks := rankScores(keyword)
+	semanticRanks := rankScores(semantic)
+
+	var relevance map[string]float64
+	switch q.Mode {
+	case types.SearchModeKeyword:
+		relevance = keyword
+	case types.SearchModeSemantic:
+		relevance = semantic
+	default:
+		relevance = fuseScores(ranking, keyword, semantic, keywordRanks, semanticRanks)
+	}
+
+	scores := relevance
+	var boosts map[string]float64
+	if q.Boost.TotalWeight() > 0 {
+		// Quotas may reach past the top results, so keep every candidate
+		pruneLimit := limit
+		if len(q.Quotas) > 0 {
+			pruneLimit = len(relevance)
+		}
+		if scores, boosts, err = s.boostScores(ctx, q.Boost, relevance, pruneLimit); err != nil {
+			return nil, err
+		}
+	}
+
+	ranked := make([]string, 0, len(scores))
+	for id, score := range scores {
+		if score > 0 {
+			ranked = append(ranked, id)
+		}
+	}
+	sortByScore(ranked, scores)
+	if len(q.Quotas) > 0 {
+		if ranked, err = s.applyCategoryQuotas(ctx, ranked, q.Quotas, limit, entries); err != nil {
+			return nil, err
+		}
+	} else if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	if err := s.loadSearchEntries(ctx, ranked, entries); err != nil {
+		return nil, err
+	}
+
+	results := make([]types.SearchResult, 0, len(ranked))
+	for _, id := range ranked {
+		entry, ok := entries[id]
+		if !ok {
+			continue // deleted between queries
+		}
+		entry.Embedding = nil
+		result := types.SearchResult{
+			Lore:         *entry,
+			Score:        scores[id],
+			Relevance:    relevance[id],
+			KeywordRank:  keywordRanks[id],
+			SemanticRank: semanticRanks[id],
+		}
+		if b, ok := boosts[id]; ok {
+			result.Boost = &b
+		}
+		if keyword != nil {
+			k := keyword[id]
+			result.KeywordScore = &k
+		}
+		if semantic != nil {
+			v := semantic[id]
+			result.SemanticScore = &v
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// applyCategoryQuotas selects up to limit of the ranked IDs, keeping rank
+// order, such that each category's quota is met as far as the matches
+// allow. The best-ranked matches of a category with a minimum are reserved
+// first; the remaining places go to the best-ranked matches of categories
+// below their maximum. Entries are loaded into entries in batches as their
+// categories are needed.
+func (s *SQLiteStore) applyCategoryQuotas(ctx context.Context, ranked []string, quotas types.CategoryQuotas, limit int, entries map[string]*types.LoreEntry) ([]string, error) {
+	loaded := 0
+	category := func(i int) (string, bool, error) {
+		if i >= loaded {
+			end := min(loaded+searchFeatureBatch, len(ranked))
+			if err := s.loadSearchEntries(ctx, ranked[loaded:end], entries); err != nil {
+				return "", false, err
+			}
+			loaded = end
+		}
+		e, ok := entries[ranked[i]]
+		if !ok {
+			return "", false, nil // deleted between queries
+		}
+		return e.Category, true, nil
+	}
+
+	chosen := make([]bool, len(ranked))
+	counts := make(map[string]int)
+	selected, missing := 0, 0
+	for _, quota := range quotas {
+		missing += quota.Min
+	}
+
+	// Reserve each category's minimum from its best-ranked matches
+	for i := 0; i < len(ranked) && missing > 0; i++ {
+		c, ok, err := category(i)
+		if err != nil {
+			return nil, err
+		}
+		if ok && counts[c] < quotas[c].Min {
+			chosen[i] = true
+			counts[c]++
+			selected++
+			missing--
+		}
+	}
+
+	// Fill the remaining places in rank order, within each maximum
+	for i := 0; i < len(ranked) && selected < limit; i++ {
+		if chosen[i] {
+			continue
+		}
+		c, ok, err := category(i)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if n := quotas[c].Max; n > 0 && counts[c] >= n {
+			continue
+		}
+		chosen[i] = true
+		counts[c]++
+		selected++
+	}
+
+	result := make([]string, 0, selected)
+	for i, id := range ranked {
+		if chosen[i] {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+// searchFeatures holds the quality signals used to boost an entry.
+type searchFeatures struct {
+	confidence      float64
+	validationCount int
+	helpful         int
+	incorrect       int
+	confirmedAt     time.Time // last validation, or creation
+}
+
+// boostScores scales relevance by each entry's quality factor.
+//
+// A boost factor is at least 1 - b.TotalWeight(), so an entry whose
+// relevance is below that fraction of the limit-th best relevance cannot
+// reach the results; such entries are dropped without loading features.
+func (s *SQLiteStore) boostScores(ctx context.Context, b types.SearchBoost, relevance map[string]float64, limit int) (map[string]float64, map[string]float64, error) {
+	ids := make([]string, 0, len(relevance))
+	for id, score := range relevance {
+		if score > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sortByScore(ids, relevance)
+	if len(ids) > limit {
+		cutoff := relevance[ids[limit-1]] * (1 - b.TotalWeight())
+		n := limit
+		for n < len(ids) && relevance[ids[n]] >= cutoff {
+			n++
+		}
+		ids = ids[:n]
+	}
+
+	features, err := s.loadSearchFeatures(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now().UTC()
+	scores := make(map[string]float64, len(ids))
+	boosts := make(map[string]float64, len(ids))
+	for _, id := range ids {
+		f, ok := features[id]
+		if !ok {
+			continue // deleted between queries
+		}
+		boosts[id] = boostFactor(b, f, now)
+		scores[id] = relevance[id] * boosts[id]
+	}
+	return scores, boosts, nil
+}
+
+// boostFactor computes the quality factor described on types.SearchBoost.
+func boostFactor(b types.SearchBoost, f searchFeatures, now time.Time) float64 {
+	factor := 1 - b.TotalWeight()
+	factor += b.ConfidenceWeight * min(max(f.confidence, 0), 1)
+	if b.ValidationWeight > 0 {
+		n := float64(f.validationCount)
+		factor += b.ValidationWeight * n / (n + b.ValidationPivot)
+	}
+	factor += b.FeedbackWeight * float64(f.helpful+1) / float64(f.helpful+f.incorrect+2)
+	if b.RecencyWeight > 0 {
+		age := max(now.Sub(f.confirmedAt), 0)
+		factor += b.RecencyWeight * math.Pow(0.5, float64(age)/float64(b.RecencyHalfLife))
+	}
+	return factor
+}
+
+// loadSearchFeatures fetches the boost features of the given active entries.
+func (s *SQLiteStore) loadSearchFeatures(ctx context.Context, ids []string) (map[string]searchFeatures, error) {
+	features := make(map[string]searchFeatures, len(ids))
+	for start := 0; start < len(ids); start += searchFeatureBatch {
+		batch := ids[start:min(start+searchFeatureBatch, len(ids))]
+		args := make([]any, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+
+		rows, err := s.db.QueryContext(ctx, `
+			SELECT l.id, l.confidence, l.validation_count, l.created_at, l.last_validated_at,
+			       (SELECT COUNT(*) FROM feedback_events f WHERE f.lore_id = l.id AND f.type = 'helpful'),
+			       (SELECT COUNT(*) FROM feedback_events f WHERE f.lore_id = l.id AND f.type = 'incorrect')
+			FROM lore_entries l
+			WHERE l.deleted_at IS NULL AND l.id IN (`+strings.TrimSuffix(strings.Repeat("?,", len(batch)), ",")+`)
+		`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("load search features: %w", err)
+		}
+
+		for rows.Next() {
+			var id, createdAt string
+			var lastValidatedAt sql.NullString
+			var f searchFeatures
+			if err := rows.Scan(&id, &f.confidence, &f.validationCount, &createdAt, &lastValidatedAt, &f.helpful, &f.incorrect); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan search features: %w", err)
+			}
+			f.confirmedAt, _ = time.Parse(time.RFC3339, createdAt)
+			if lastValidatedAt.Valid {
+				if t, err := time.Parse(time.RFC3339, lastValidatedAt.String); err == nil && t.After(f.confirmedAt) {
+					f.confirmedAt = t
+				}
+			}
+			features[id] = f
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("iterate search features: %w", err)
+		}
+	}
+	return features, nil
+}
+
+// fuseScores combines keyword and semantic relevance into hybrid scores.
+func fuseScores(r types.SearchRanking, keyword, semantic map[string]float64, keywordRanks, semanticRanks map[string]int) map[string]float64 {
+	total := r.KeywordWeight + r.SemanticWeight
+	scores := make(map[string]float64, len(keyword)+len(semantic))
+
+	if r.Fusion == types.SearchFusionRRF {
+		k := float64(r.RRFK)
+		// Dividing by the best possible sum keeps scores in [0, 1].
+		best := total / (k + 1)
+		for id, rank := range keywordRanks {
+			scores[id] += r.KeywordWeight / (k + float64(rank)) / best
+		}
+		for id, rank := range semanticRanks {
+			scores[id] += r.SemanticWeight / (k + float64(rank)) / best
+		}
+		return scores
+	}
+
+	for id, v := range keyword {
+		scores[id] += v * r.KeywordWeight / total
+	}
+	for id, v := range semantic {
+		scores[id] += v * r.SemanticWeight / total
+	}
+	return scores
+}
+
+// rankScores returns the 1-based rank of every entry with a positive score.
+func rankScores(scores map[string]float64) map[string]int {
+	ids := make([]string, 0, len(scores))
+	for id, score := range scores {
+		if score > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sortByScore(ids, scores)
+
+	ranks := make(map[string]int, len(ids))
+	for i, id := range ids {
+		ranks[id] = i + 1
+	}
+	return ranks
+}
+
+// sortByScore orders ids by score, highest first, breaking ties by ID so
+// results are stable.
+func sortByScore(ids []string, scores map[string]float64) {
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+}
+
+// searchFilters returns SQL conditions, each prefixed with AND, that
+// restrict lore entries (columns qualified by prefix) to q's filters, and
+// their arguments. An entry scoped to a repository matches only queries
+// for it, and one scoped to a path only queries for that path or a path
+// beneath it; unscoped entries match every query.
+func searchFilters(q types.SearchQuery, prefix string) (string, []any) {
+	var where strings.Builder
+	var args []any
+	if q.Category != "" {
+		fmt.Fprintf(&where, ` AND %scategory = ?`, prefix)
+		args = append(args, q.Category)
+	}
+	if q.Language != "" {
+		fmt.Fprintf(&where, ` AND %slanguage = ?`, prefix)
+		args = append(args, q.Language)
+	}
+	if q.Repo != "" {
+		fmt.Fprintf(&where, ` AND (%[1]srepo = '' OR %[1]srepo = ?)`, prefix)
+		args = append(args, q.Repo)
+	}
+	if q.Path != "" {
+		fmt.Fprintf(&where, ` AND (%[1]spath = '' OR %[1]spath = ? OR substr(?, 1, length(%[1]spath) + 1) = %[1]spath || '/')`, prefix)
+		args = append(args, q.Path, q.Path)
+	}
+	return where.String(), args
+}
+
+// keywordScores returns normalized BM25 scores for entries matching q's
+// text and filters.
+func (s *SQLiteStore) keywordScores(ctx context.Context, q types.SearchQuery) (map[string]float64, error) {
+	match := ftsMatchQuery(q.Text)
+	if match == "" {
+		return map[string]float64{}, nil
+	}
+
+	query := `
+		SELECT l.id, bm25(lore_fts) AS rank
+		FROM lore_fts
+		JOIN lore_entries l ON l.rowid = lore_fts.rowid
+		WHERE lore_fts MATCH ? AND l.deleted_at IS NULL`
+	args := []any{match}
+	filters, filterArgs := searchFilters(q, "l.")
+	query += filters + ` ORDER BY rank LIMIT ?`
+	args = append(args, filterArgs...)
+	args = append(args, searchCandidateLimit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("keyword search: %w", err)
+	}
+	defer rows.Close()
+
+	scores := make(map[string]float64)
+	var best float64
+	for rows.Next() {
+		var id string
+		var rank float64
+		if err := rows.Scan(&id, &rank); err != nil {
+			return nil, fmt.Errorf("scan keyword match: %w", err)
+		}
+		// bm25() is negative; more negative means more relevant.
+		scores[id] = -rank
+		best = max(best, -rank)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate keyword matches: %w", err)
+	}
+
+	for id, raw := range scores {
+		if best > 0 {
+			scores[id] = raw / best
+		} else {
+			scores[id] = 1
+		}
+	}
+	return scores, nil
+}
+
+// semanticScores returns cosine similarity to q's embedding for every
+// embedded entry matching its filters, recording scanned entries in
+// entries for reuse.
+func (s *SQLiteStore) semanticScores(ctx context.Context, q types.SearchQuery, entries map[string]*types.LoreEntry) (map[string]float64, error) {
+	query := `
+		SELECT id, content, context, category, confidence, embedding, embedding_status,
+		       source_id, sources, validation_count, created_at, updated_at, deleted_at, last_validated_at, language, repo, path, hlc
+		FROM lore_entries JOIN lore_embeddings ON lore_embeddings.lore_id = lore_entries.id
+		WHERE deleted_at IS NULL`
+	filters, args := searchFilters(q, "")
+	query += filters
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("semantic search: %w", err)
+	}
+	defer rows.Close()
+
+	qv := newQueryVector(q.Embedding)
+	scores := make(map[string]float64)
+	for rows.Next() {
+		entry, err := scanLoreEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		if len(entry.Embedding) != len(q.Embedding) {
+			continue // embedded with a different model
+		}
+		scores[entry.ID] = min(max(qv.similarity(entry.Embedding, norm(entry.Embedding)), 0), 1)
+		entries[entry.ID] = entry
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	rows.Close()
+
+	// Long entries score at their best-matching chunk. Only entries the
+	// scan above matched are eligible, which applies its filters.
+	chunkBest, err := chunkSimilarities(ctx, s.db, qv, q.Category, similarityScope{})
+	if err != nil {
+		return nil, err
+	}
+	for id, similarity := range chunkBest {
+		if _, ok := scores[id]; !ok {
+			continue
+		}
+		if similarity = min(max(similarity, 0), 1); similarity > scores[id] {
+			scores[id] = similarity
+		}
+	}
+	return scores, nil
+}
+
+// loadSearchEntries fetches the ranked entries not already in entries.
+func (s *SQLiteStore) loadSearchEntries(ctx context.Context, ids []string, entries map[string]*types.LoreEntry) error {
+	var missing []any
+	for _, id := range ids {
+		if _, ok := entries[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, content, context, category, confidence, NULL, embedding_status,
+		       source_id, sources, validation_count, created_at, updated_at, deleted_at, last_validated_at, language, repo, path, hlc
+		FROM lore_entries
+		WHERE deleted_at IS NULL AND id IN (`+strings.TrimSuffix(strings.Repeat("?,", len(missing)), ",")+`)
+	`, missing...)
+	if err != nil {
+		return fmt.Errorf("load search results: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanLoreEntry(rows)
+		if err != nil {
+			return fmt.Errorf("scan row: %w", err)
+		}
+		entries[entry.ID] = entry
+	}
+	return rows.Err()
+}
+
+// ftsMatchQuery turns free text into an FTS5 query that matches any of its
+// words. Each word is quoted, so FTS5 operators and punctuation in the input
+// are taken literally; a word like "pg-pool" becomes the phrase "pg pool".
+func ftsMatchQuery(text string) string {
+	var terms []string
+	for _, word := range strings.Fields(text) {
+		if !strings.ContainsFunc(word, isSearchTokenRune) {
+			continue // would tokenize to nothing
+		}
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"`)
+	}
+	return strings.Join(terms, " OR ")
+}
+
+func isSearchTokenRune(r rune) bool {
+	return r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 127
+}
+
+// searchIndexMigration is the migration that creates the full-text index.
+const searchIndexMigration = "008_lore_fts.sql"
+
+// stripSearchIndex removes the full-text index from a snapshot copy. The
+// index is server-side only, and its triggers would make lore_entries
+// unwritable for clients whose SQLite build lacks FTS5.
+func stripSearchIndex(ctx context.Context, path string) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	for _, stmt := range []string{
+		`DROP TRIGGER IF EXISTS lore_fts_insert`,
+		`DROP TRIGGER IF EXISTS lore_fts_delete`,
+		`DROP TRIGGER IF EXISTS lore_fts_update`,
+		`DROP TABLE IF EXISTS lore_fts`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreSearchIndex recreates the full-text index in a database whose index
+// was removed by stripSearchIndex, such as a restored snapshot. It reapplies
+// the migration that created the index, which also indexes the existing
+// entries. A database that still has the index is left as is.
+func restoreSearchIndex(ctx context.Context, path string) error {
+	script, err := migrations.FS.ReadFile(searchIndexMigration)
+	if err != nil {
+		return err
+	}
+	up, _, _ := strings.Cut(string(script), "-- +goose Down")
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var exists int
+	if err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'lore_fts'
+	`).Scan(&exists); err != nil {
+		return err
+	}
+	if exists > 0 {
+		return nil
+	}
+	_, err = db.ExecContext(ctx, up)
+	return err
+}
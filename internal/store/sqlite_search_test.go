@@ -0,0 +1,647 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// newSearchTestStore returns a store holding the given entries, keyed by content.
+func newSearchTestStore(t *testing.T, entries ...types.NewLoreEntry) (*SQLiteStore, map[string]string) {
+	t.Helper()
+	s := newTestStore(t)
+	if _, err := s.IngestLore(context.Background(), entries); err != nil {
+		t.Fatalf("IngestLore: %v", err)
+	}
+	ids := make(map[string]string, len(entries))
+	for _, e := range entries {
+		ids[e.Content] = digestTestLoreID(t, s, e.Content)
+	}
+	return s, ids
+}
+
+func searchResultIDs(results []types.SearchResult) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.Lore.ID
+	}
+	return ids
+}
+
+func TestMigration008_CreatesLoreFTS(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, err := db.Exec(`SELECT rowid FROM lore_fts WHERE lore_fts MATCH 'x' LIMIT 0`); err != nil {
+		t.Fatalf("lore_fts table missing: %v", err)
+	}
+}
+
+func TestSearchLore_KeywordMatchesIdentifiers(t *testing.T) {
+	s, ids := newSearchTestStore(t,
+		types.NewLoreEntry{Content: "Set ERR_CONN_RESET retries on the pgbouncer pool", Category: "DEPENDENCY_BEHAVIOR", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "Connection resets happen under load", Category: "DEPENDENCY_BEHAVIOR", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "Unrelated testing advice", Context: "mentions pgbouncer once", Category: "TESTING_STRATEGY", Confidence: 0.7, SourceID: "src"},
+	)
+
+	results, err := s.SearchLore(context.Background(), types.SearchQuery{
+		Text: "ERR_CONN_RESET pgbouncer",
+		Mode: types.SearchModeKeyword,
+	})
+	if err != nil {
+		t.Fatalf("SearchLore: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("got %v, want 2 matches", searchResultIDs(results))
+	}
+	if results[0].Lore.ID != ids["Set ERR_CONN_RESET retries on the pgbouncer pool"] {
+		t.Errorf("best match = %q, want the entry naming both terms", results[0].Lore.Content)
+	}
+	if results[0].Score != 1 || results[1].Score <= 0 || results[1].Score >= 1 {
+		t.Errorf("scores = %v, %v; want 1 then (0, 1)", results[0].Score, results[1].Score)
+	}
+}
+
+func TestSearchLore_KeywordExcludesDeletedAndFiltersCategory(t *testing.T) {
+	s, ids := newSearchTestStore(t,
+		types.NewLoreEntry{Content: "Cache warmup before deploy", Category: "PERFORMANCE_INSIGHT", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "Cache keys need versioning", Category: "ARCHITECTURAL_DECISION", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "Cache stampede on expiry", Category: "PERFORMANCE_INSIGHT", Confidence: 0.7, SourceID: "src"},
+	)
+	ctx := context.Background()
+	if err := s.DeleteLore(ctx, ids["Cache stampede on expiry"], "src"); err != nil {
+		t.Fatalf("DeleteLore: %v", err)
+	}
+
+	results, err := s.SearchLore(ctx, types.SearchQuery{
+		Text:     "cache",
+		Mode:     types.SearchModeKeyword,
+		Category: "PERFORMANCE_INSIGHT",
+	})
+	if err != nil {
+		t.Fatalf("SearchLore: %v", err)
+	}
+	if len(results) != 1 || results[0].Lore.ID != ids["Cache warmup before deploy"] {
+		t.Errorf("got %v, want only the active PERFORMANCE_INSIGHT entry", searchResultIDs(results))
+	}
+}
+
+func TestSearchLore_FiltersLanguage(t *testing.T) {
+	s, ids := newSearchTestStore(t,
+		types.NewLoreEntry{Content: "Always drain the Kafka consumer before a rebalance", Category: "DEPENDENCY_BEHAVIOR", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "Der Kafka Consumer muss vor dem Rebalance immer geleert werden", Category: "DEPENDENCY_BEHAVIOR", Confidence: 0.7, SourceID: "src"},
+	)
+	german := ids["Der Kafka Consumer muss vor dem Rebalance immer geleert werden"]
+
+	results, err := s.SearchLore(context.Background(), types.SearchQuery{
+		Text:     "kafka",
+		Mode:     types.SearchModeKeyword,
+		Language: "de",
+	})
+	if err != nil {
+		t.Fatalf("SearchLore: %v", err)
+	}
+	if len(results) != 1 || results[0].Lore.ID != german {
+		t.Fatalf("got %v, want only the German entry", searchResultIDs(results))
+	}
+	if results[0].Lore.Language != "de" {
+		t.Errorf("Language = %q, want de", results[0].Lore.Language)
+	}
+}
+
+func TestSearchLore_FiltersRepoAndPath(t *testing.T) {
+	s, ids := newSearchTestStore(t,
+		types.NewLoreEntry{Content: "Kafka everywhere", Category: "DEPENDENCY_BEHAVIOR", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "Kafka in payments", Category: "DEPENDENCY_BEHAVIOR", Confidence: 0.7, SourceID: "src", Repo: "acme/payments"},
+		types.NewLoreEntry{Content: "Kafka in billing", Category: "DEPENDENCY_BEHAVIOR", Confidence: 0.7, SourceID: "src", Repo: "acme/billing"},
+		types.NewLoreEntry{Content: "Kafka in the queue package", Category: "DEPENDENCY_BEHAVIOR", Confidence: 0.7, SourceID: "src", Repo: "acme/payments", Path: "internal/queue"},
+		types.NewLoreEntry{Content: "Kafka in a sibling package", Category: "DEPENDENCY_BEHAVIOR", Confidence: 0.7, SourceID: "src", Repo: "acme/payments", Path: "internal/queuev2"},
+	)
+	ctx := context.Background()
+	for _, id := range ids {
+		if err := s.UpdateEmbedding(ctx, id, []float32{1, 0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := map[string]bool{
+		ids["Kafka everywhere"]:           true,
+		ids["Kafka in payments"]:          true,
+		ids["Kafka in the queue package"]: true,
+	}
+
+	for _, mode := range []types.SearchMode{types.SearchModeKeyword, types.SearchModeSemantic} {
+		t.Run(string(mode), func(t *testing.T) {
+			results, err := s.SearchLore(ctx, types.SearchQuery{
+				Text:      "kafka",
+				Embedding: []float32{1, 0},
+				Mode:      mode,
+				Repo:      "acme/payments",
+				Path:      "internal/queue/consumer.go",
+			})
+			if err != nil {
+				t.Fatalf("SearchLore: %v", err)
+			}
+			if len(results) != len(want) {
+				t.Fatalf("got %v, want the unscoped, repo and package entries", searchResultIDs(results))
+			}
+			for _, r := range results {
+				if !want[r.Lore.ID] {
+					t.Errorf("unexpected result %q (repo %q, path %q)", r.Lore.Content, r.Lore.Repo, r.Lore.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchLore_KeywordFollowsContentUpdates(t *testing.T) {
+	s, ids := newSearchTestStore(t,
+		types.NewLoreEntry{Content: "Original wording", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+	)
+	ctx := context.Background()
+	if _, err := s.db.Exec(`UPDATE lore_entries SET content = 'Revised wording' WHERE id = ?`, ids["Original wording"]); err != nil {
+		t.Fatal(err)
+	}
+
+	for text, want := range map[string]int{"original": 0, "revised": 1} {
+		results, err := s.SearchLore(ctx, types.SearchQuery{Text: text, Mode: types.SearchModeKeyword})
+		if err != nil {
+			t.Fatalf("SearchLore(%q): %v", text, err)
+		}
+		if len(results) != want {
+			t.Errorf("SearchLore(%q) returned %d results, want %d", text, len(results), want)
+		}
+	}
+}
+
+func TestSearchLore_SemanticRanksByCosine(t *testing.T) {
+	s, ids := newSearchTestStore(t,
+		types.NewLoreEntry{Content: "Near", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "Far", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "Opposite", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "Other model", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+	)
+	ctx := context.Background()
+	for content, vec := range map[string][]float32{
+		"Near":     {1, 0.1, 0},
+		"Far":      {0.2, 1, 0},
+		"Opposite": {-1, 0, 0},
+	} {
+		if err := s.UpdateEmbedding(ctx, ids[content], vec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Stored before the store checked dimensions
+	if err := storeEmbeddingInTx(ctx, s.db, ids["Other model"], []float32{1, 0}, QuantizationNone); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := s.SearchLore(ctx, types.SearchQuery{
+		Text:      "anything",
+		Embedding: []float32{1, 0, 0},
+		Mode:      types.SearchModeSemantic,
+	})
+	if err != nil {
+		t.Fatalf("SearchLore: %v", err)
+	}
+	if len(results) != 2 || results[0].Lore.ID != ids["Near"] || results[1].Lore.ID != ids["Far"] {
+		t.Fatalf("got %v, want Near then Far", searchResultIDs(results))
+	}
+	if results[0].Lore.Embedding != nil {
+		t.Error("expected embeddings to be omitted from results")
+	}
+}
+
+func TestSearchLore_HybridCombinesSignals(t *testing.T) {
+	s, ids := newSearchTestStore(t,
+		types.NewLoreEntry{Content: "Retry idempotent requests only", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "Retry budget for the gateway", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "Backoff with jitter", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+	)
+	ctx := context.Background()
+	for content, vec := range map[string][]float32{
+		"Retry idempotent requests only": {1, 0},
+		"Retry budget for the gateway":   {0, 1},
+		"Backoff with jitter":            {1, 0},
+	} {
+		if err := s.UpdateEmbedding(ctx, ids[content], vec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := s.SearchLore(ctx, types.SearchQuery{
+		Text:      "retry",
+		Embedding: []float32{1, 0},
+		Mode:      types.SearchModeHybrid,
+	})
+	if err != nil {
+		t.Fatalf("SearchLore: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %v, want all three entries", searchResultIDs(results))
+	}
+	// Keyword and semantic match > semantic only > keyword only
+	want := []string{ids["Retry idempotent requests only"], ids["Backoff with jitter"], ids["Retry budget for the gateway"]}
+	for i, id := range searchResultIDs(results) {
+		if id != want[i] {
+			t.Fatalf("order = %v, want %v", searchResultIDs(results), want)
+		}
+	}
+}
+
+func TestSearchLore_RequiresEmbeddingForSemantic(t *testing.T) {
+	s := newTestStore(t)
+
+	_, err := s.SearchLore(context.Background(), types.SearchQuery{Text: "x", Mode: types.SearchModeSemantic})
+	if !errors.Is(err, ErrEmbeddingUnavailable) {
+		t.Errorf("error = %v, want ErrEmbeddingUnavailable", err)
+	}
+}
+
+func TestSearchLore_AppliesLimit(t *testing.T) {
+	var entries []types.NewLoreEntry
+	for _, c := range []string{"alpha one", "alpha two", "alpha three"} {
+		entries = append(entries, types.NewLoreEntry{Content: c, Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"})
+	}
+	s, _ := newSearchTestStore(t, entries...)
+
+	results, err := s.SearchLore(context.Background(), types.SearchQuery{Text: "alpha", Mode: types.SearchModeKeyword, Limit: 2})
+	if err != nil {
+		t.Fatalf("SearchLore: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("got %d results, want 2", len(results))
+	}
+}
+
+func TestSearchLore_AppliesCategoryQuotas(t *testing.T) {
+	s, ids := newSearchTestStore(t,
+		types.NewLoreEntry{Content: "cache cache invalidation by key", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "cache cache warmup before deploys", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "cache cache stampede protection", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "Chose a write-through cache for sessions, not write-back", Category: "ARCHITECTURAL_DECISION", Confidence: 0.7, SourceID: "src"},
+	)
+	ctx := context.Background()
+
+	results, err := s.SearchLore(ctx, types.SearchQuery{
+		Text:  "cache",
+		Mode:  types.SearchModeKeyword,
+		Limit: 3,
+		Quotas: types.CategoryQuotas{
+			"PATTERN_OUTCOME":        {Max: 2},
+			"ARCHITECTURAL_DECISION": {Min: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SearchLore: %v", err)
+	}
+	counts := map[string]int{}
+	for i, r := range results {
+		counts[r.Lore.Category]++
+		if i > 0 && r.Score > results[i-1].Score {
+			t.Errorf("results out of rank order: %v", searchResultIDs(results))
+		}
+	}
+	if len(results) != 3 || counts["PATTERN_OUTCOME"] != 2 || counts["ARCHITECTURAL_DECISION"] != 1 {
+		t.Errorf("got %v (counts %v), want two patterns and the decision", searchResultIDs(results), counts)
+	}
+	if last := results[len(results)-1].Lore.ID; last != ids["Chose a write-through cache for sessions, not write-back"] {
+		t.Errorf("last result = %s, want the lower-ranked decision", last)
+	}
+
+	_, err = s.SearchLore(ctx, types.SearchQuery{
+		Text:   "cache",
+		Mode:   types.SearchModeKeyword,
+		Limit:  1,
+		Quotas: types.CategoryQuotas{"PATTERN_OUTCOME": {Min: 1}, "ARCHITECTURAL_DECISION": {Min: 1}},
+	})
+	if err == nil {
+		t.Error("expected error when minimums exceed the limit")
+	}
+}
+
+func TestFTSMatchQuery(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"retry backoff", `"retry" OR "backoff"`},
+		{`say "hi" NEAR(x) -`, `"say" OR """hi""" OR "NEAR(x)"`},
+		{"pg-pool *", `"pg-pool"`},
+	}
+	for _, tt := range tests {
+		if got := ftsMatchQuery(tt.in); got != tt.want {
+			t.Errorf("ftsMatchQuery(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSearchLore_OperatorsAreLiteral(t *testing.T) {
+	s, _ := newSearchTestStore(t,
+		types.NewLoreEntry{Content: "Use NOT NULL constraints", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+	)
+
+	for _, q := range []string{`NOT`, `"unbalanced`, `col:value`, `a AND OR b`, `(*)`} {
+		if _, err := s.SearchLore(context.Background(), types.SearchQuery{Text: q, Mode: types.SearchModeKeyword}); err != nil {
+			t.Errorf("SearchLore(%q): %v", q, err)
+		}
+	}
+}
+
+func TestGenerateSnapshot_OmitsSearchIndex(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "engram.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if _, err := s.IngestLore(context.Background(), []types.NewLoreEntry{
+		{Content: "Indexed lore", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.GenerateSnapshot(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.snapshotPath()); err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := sql.Open("sqlite", s.snapshotPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snap.Close()
+
+	var n int
+	if err := snap.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name LIKE 'lore_fts%'`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("snapshot contains %d search index objects, want 0", n)
+	}
+	if err := snap.QueryRow(`SELECT COUNT(*) FROM lore_entries`).Scan(&n); err != nil || n != 1 {
+		t.Errorf("snapshot lore count = %d (err %v), want 1", n, err)
+	}
+}
+
+// newFusionTestStore holds one entry that only keyword search finds, one that
+// only semantic search finds, and one weakly found by both.
+func newFusionTestStore(t *testing.T) (*SQLiteStore, map[string]string) {
+	t.Helper()
+	s, ids := newSearchTestStore(t,
+		types.NewLoreEntry{Content: "Quota errors from the billing API", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "Rate limits need client backoff", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "Quota dashboards lag by an hour or more after resets", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+	)
+	for content, vec := range map[string][]float32{
+		"Quota errors from the billing API":                    {0, 1},
+		"Rate limits need client backoff":                      {1, 0},
+		"Quota dashboards lag by an hour or more after resets": {1, 1},
+	} {
+		if err := s.UpdateEmbedding(context.Background(), ids[content], vec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s, ids
+}
+
+func TestSearchLore_HybridWeights(t *testing.T) {
+	s, ids := newFusionTestStore(t)
+	search := func(kw, sem float64) []types.SearchResult {
+		t.Helper()
+		results, err := s.SearchLore(context.Background(), types.SearchQuery{
+			Text:      "quota",
+			Embedding: []float32{1, 0},
+			Mode:      types.SearchModeHybrid,
+			Ranking:   types.SearchRanking{Fusion: types.SearchFusionWeighted, KeywordWeight: kw, SemanticWeight: sem, RRFK: 60},
+		})
+		if err != nil {
+			t.Fatalf("SearchLore: %v", err)
+		}
+		return results
+	}
+
+	if got := search(1, 0)[0].Lore.ID; got != ids["Quota errors from the billing API"] {
+		t.Errorf("keyword-weighted best = %s, want the short keyword match", got)
+	}
+	semanticOnly := search(0, 1)
+	if got := semanticOnly[0].Lore.ID; got != ids["Rate limits need client backoff"] {
+		t.Errorf("semantic-weighted best = %s, want the closest embedding", got)
+	}
+	if semanticOnly[0].Score != 1 {
+		t.Errorf("score = %v, want the semantic score alone", semanticOnly[0].Score)
+	}
+}
+
+func TestSearchLore_HybridRRF(t *testing.T) {
+	s, ids := newFusionTestStore(t)
+
+	results, err := s.SearchLore(context.Background(), types.SearchQuery{
+		Text:      "quota",
+		Embedding: []float32{1, 0},
+		Mode:      types.SearchModeHybrid,
+		Ranking:   types.SearchRanking{Fusion: types.SearchFusionRRF, KeywordWeight: 1, SemanticWeight: 1, RRFK: 1},
+	})
+	if err != nil {
+		t.Fatalf("SearchLore: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %v, want 3 results", searchResultIDs(results))
+	}
+
+	// Ranked second by both signals beats first by one and absent from the other
+	top := results[0]
+	if top.Lore.ID != ids["Quota dashboards lag by an hour or more after resets"] {
+		t.Fatalf("order = %v, want the entry both signals rank", searchResultIDs(results))
+	}
+	if top.KeywordRank != 2 || top.SemanticRank != 2 {
+		t.Errorf("ranks = %d/%d, want 2/2", top.KeywordRank, top.SemanticRank)
+	}
+	// 2 * 1/(1+2), scaled by the best possible 2 * 1/(1+1)
+	if want := 2.0 / 3; math.Abs(top.Score-want) > 1e-9 {
+		t.Errorf("score = %v, want %v", top.Score, want)
+	}
+}
+
+func TestSearchLore_ReportsSignalScores(t *testing.T) {
+	s, ids := newFusionTestStore(t)
+	ctx := context.Background()
+
+	hybrid, err := s.SearchLore(ctx, types.SearchQuery{Text: "quota", Embedding: []float32{1, 0}, Mode: types.SearchModeHybrid})
+	if err != nil {
+		t.Fatalf("SearchLore: %v", err)
+	}
+	for _, r := range hybrid {
+		if r.KeywordScore == nil || r.SemanticScore == nil {
+			t.Fatalf("hybrid result %s missing a signal score", r.Lore.ID)
+		}
+		if r.Lore.ID == ids["Rate limits need client backoff"] {
+			if *r.KeywordScore != 0 || r.KeywordRank != 0 || *r.SemanticScore != 1 || r.SemanticRank != 1 {
+				t.Errorf("semantic-only entry = %+v", r)
+			}
+		}
+	}
+
+	keyword, err := s.SearchLore(ctx, types.SearchQuery{Text: "quota", Mode: types.SearchModeKeyword})
+	if err != nil {
+		t.Fatalf("SearchLore: %v", err)
+	}
+	if r := keyword[0]; r.KeywordScore == nil || r.SemanticScore != nil || r.KeywordRank != 1 {
+		t.Errorf("keyword result = %+v, want keyword signal only", r)
+	}
+}
+
+func TestSearchLore_RejectsInvalidRanking(t *testing.T) {
+	s := newTestStore(t)
+
+	_, err := s.SearchLore(context.Background(), types.SearchQuery{
+		Text:      "x",
+		Embedding: []float32{1},
+		Mode:      types.SearchModeHybrid,
+		Ranking:   types.SearchRanking{Fusion: "max", KeywordWeight: 1, RRFK: 60},
+	})
+	if err == nil {
+		t.Error("expected error for unknown fusion")
+	}
+}
+
+func TestSearchLore_BoostFavoursHelpfulLore(t *testing.T) {
+	s, ids := newSearchTestStore(t,
+		types.NewLoreEntry{Content: "Helpful lore", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+		types.NewLoreEntry{Content: "Disputed lore", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"},
+	)
+	ctx := context.Background()
+	for _, id := range ids {
+		if err := s.UpdateEmbedding(ctx, id, []float32{1, 0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var feedback []types.FeedbackEntry
+	for i := 0; i < 3; i++ {
+		feedback = append(feedback,
+			types.FeedbackEntry{LoreID: ids["Helpful lore"], Type: "helpful", SourceID: "client"},
+			types.FeedbackEntry{LoreID: ids["Disputed lore"], Type: "incorrect", SourceID: "client"},
+		)
+	}
+	if _, err := s.RecordFeedback(ctx, feedback); err != nil {
+		t.Fatal(err)
+	}
+	// Helpful feedback refreshes last_validated_at; age the disputed entry.
+	if _, err := s.db.Exec(`UPDATE lore_entries SET created_at = '2025-01-01T00:00:00Z' WHERE id = ?`, ids["Disputed lore"]); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := s.SearchLore(ctx, types.SearchQuery{
+		Text:      "lore",
+		Embedding: []float32{1, 0},
+		Mode:      types.SearchModeSemantic,
+		Boost:     DefaultSearchBoost,
+	})
+	if err != nil {
+		t.Fatalf("SearchLore: %v", err)
+	}
+	if len(results) != 2 || results[0].Lore.ID != ids["Helpful lore"] {
+		t.Fatalf("order = %v, want helpful lore first", searchResultIDs(results))
+	}
+	for _, r := range results {
+		if r.Boost == nil || r.Relevance != 1 || math.Abs(r.Score-r.Relevance**r.Boost) > 1e-9 {
+			t.Errorf("result %s: score %v, relevance %v, boost %v", r.Lore.ID, r.Score, r.Relevance, r.Boost)
+		}
+	}
+	if *results[0].Boost <= *results[1].Boost {
+		t.Errorf("boosts = %v, %v; want helpful lore boosted more", *results[0].Boost, *results[1].Boost)
+	}
+
+	unboosted, err := s.SearchLore(ctx, types.SearchQuery{Text: "lore", Embedding: []float32{1, 0}, Mode: types.SearchModeSemantic})
+	if err != nil {
+		t.Fatalf("SearchLore: %v", err)
+	}
+	for _, r := range unboosted {
+		if r.Boost != nil || r.Score != r.Relevance {
+			t.Errorf("unboosted result %s: score %v, relevance %v, boost %v", r.Lore.ID, r.Score, r.Relevance, r.Boost)
+		}
+	}
+}
+
+func TestSearchLore_BoostKeepsRelevanceDominant(t *testing.T) {
+	var entries []types.NewLoreEntry
+	for _, c := range []string{"strong", "weak one", "weak two", "weak three"} {
+		entries = append(entries, types.NewLoreEntry{Content: c, Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src"})
+	}
+	s, ids := newSearchTestStore(t, entries...)
+	ctx := context.Background()
+	for content, vec := range map[string][]float32{
+		"strong":     {1, 0},
+		"weak one":   {0.1, 1},
+		"weak two":   {0.1, 1},
+		"weak three": {0.1, 1},
+	} {
+		if err := s.UpdateEmbedding(ctx, ids[content], vec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.RecordFeedback(ctx, []types.FeedbackEntry{
+		{LoreID: ids["weak one"], Type: "helpful", SourceID: "client"},
+		{LoreID: ids["strong"], Type: "incorrect", SourceID: "client"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := s.SearchLore(ctx, types.SearchQuery{
+		Text:      "x",
+		Embedding: []float32{1, 0},
+		Mode:      types.SearchModeSemantic,
+		Boost:     DefaultSearchBoost,
+		Limit:     1,
+	})
+	if err != nil {
+		t.Fatalf("SearchLore: %v", err)
+	}
+	if len(results) != 1 || results[0].Lore.ID != ids["strong"] {
+		t.Errorf("got %v, want the far more relevant entry despite its feedback", searchResultIDs(results))
+	}
+}
+
+func TestBoostFactor(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	b := types.SearchBoost{
+		ConfidenceWeight: 0.1,
+		ValidationWeight: 0.2,
+		FeedbackWeight:   0.3,
+		RecencyWeight:    0.4,
+		ValidationPivot:  5,
+		RecencyHalfLife:  24 * time.Hour,
+	}
+
+	tests := []struct {
+		name string
+		f    searchFeatures
+		want float64
+	}{
+		{"no signal", searchFeatures{confidence: 0, confirmedAt: now.Add(-1000 * 24 * time.Hour)}, 0.1*0 + 0.3*0.5},
+		{"pivot validations", searchFeatures{confidence: 1, validationCount: 5, confirmedAt: now}, 0.1 + 0.2*0.5 + 0.3*0.5 + 0.4},
+		{"one half-life old", searchFeatures{confidence: 0.5, confirmedAt: now.Add(-24 * time.Hour)}, 0.1*0.5 + 0.3*0.5 + 0.4*0.5},
+		{"mixed feedback", searchFeatures{confidence: 0, helpful: 2, incorrect: 6, confirmedAt: now.Add(-1000 * 24 * time.Hour)}, 0.3 * 0.3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := boostFactor(b, tt.f, now); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("boostFactor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	half := types.SearchBoost{ConfidenceWeight: 0.5}
+	if got := boostFactor(half, searchFeatures{confidence: 1}, now); got != 1 {
+		t.Errorf("perfect entry factor = %v, want 1", got)
+	}
+	if got := boostFactor(half, searchFeatures{confidence: 0}, now); got != 0.5 {
+		t.Errorf("worst entry factor = %v, want 1 - total weight", got)
+	}
+}
@@ -0,0 +1,30 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ClaimPushSignature records a push signature as used until expires,
+// reporting false if it was already used and has not expired. Expired
+// signatures are removed as new ones are claimed.
+func (s *SQLiteStore) ClaimPushSignature(ctx context.Context, signature string, expires time.Time) (bool, error) {
+	now := formatTime(time.Now())
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM push_signatures WHERE expires_at < ?`, now); err != nil {
+		return false, fmt.Errorf("prune push signatures: %w", err)
+	}
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO push_signatures (signature, expires_at) VALUES (?, ?)
+		ON CONFLICT (signature) DO UPDATE SET expires_at = excluded.expires_at
+		WHERE push_signatures.expires_at < ?
+	`, signature, formatTime(expires), now)
+	if err != nil {
+		return false, fmt.Errorf("claim push signature: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim push signature: %w", err)
+	}
+	return n == 1, nil
+}
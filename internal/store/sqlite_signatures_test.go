@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClaimPushSignature(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	claim := func(sig string, expires time.Time) bool {
+		t.Helper()
+		ok, err := s.ClaimPushSignature(ctx, sig, expires)
+		if err != nil {
+			t.Fatalf("ClaimPushSignature() error = %v", err)
+		}
+		return ok
+	}
+
+	if !claim("sha256=a", time.Now().Add(time.Minute)) {
+		t.Fatal("first claim = false, want true")
+	}
+	if claim("sha256=a", time.Now().Add(time.Minute)) {
+		t.Error("second claim = true, want false")
+	}
+	if !claim("sha256=b", time.Now().Add(time.Minute)) {
+		t.Error("claim of another signature = false, want true")
+	}
+
+	// An expired signature is forgotten
+	if !claim("sha256=c", time.Now().Add(-time.Second)) {
+		t.Fatal("claim = false, want true")
+	}
+	if !claim("sha256=c", time.Now().Add(time.Minute)) {
+		t.Error("claim after expiry = false, want true")
+	}
+	var n int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM push_signatures`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("push_signatures rows = %d, want 3", n)
+	}
+}
@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+// InsertSyncConflictsTx stores quarantined entries in the conflict inbox
+// within a transaction, so they commit together with the rest of the
+// push. Each conflict is assigned an ID, status and creation time in place.
+func InsertSyncConflictsTx(ctx context.Context, tx *sql.Tx, conflicts []engramsync.SyncConflict) error {
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO sync_conflicts (id, push_id, source_id, table_name, entity_id, entry, errors, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+	for i := range conflicts {
+		c := &conflicts[i]
+		c.ID = ulid.Make().String()
+		c.Status = engramsync.ConflictStatusOpen
+		c.CreatedAt = now
+
+		entry, err := json.Marshal(c.Entry)
+		if err != nil {
+			return fmt.Errorf("encode conflict entry: %w", err)
+		}
+		errs, err := json.Marshal(c.Errors)
+		if err != nil {
+			return fmt.Errorf("encode conflict errors: %w", err)
+		}
+		if _, err := stmt.ExecContext(ctx, c.ID, c.PushID, c.SourceID, c.Entry.TableName, c.Entry.EntityID,
+			string(entry), string(errs), c.Status, formatTime(now)); err != nil {
+			return fmt.Errorf("insert sync conflict: %w", err)
+		}
+	}
+	return nil
+}
+
+const selectSyncConflictSQL = `
+	SELECT id, push_id, source_id, entry, errors, status, resolution, note, created_at, resolved_at
+	FROM sync_conflicts`
+
+// ListSyncConflicts returns conflict inbox entries matching filter, oldest
+// first.
+func (s *SQLiteStore) ListSyncConflicts(ctx context.Context, filter engramsync.SyncConflictFilter) ([]engramsync.SyncConflict, error) {
+	var where []string
+	var args []interface{}
+	if filter.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.SourceID != "" {
+		where = append(where, "source_id = ?")
+		args = append(args, filter.SourceID)
+	}
+	if filter.AfterID != "" {
+		where = append(where, "id > ?")
+		args = append(args, filter.AfterID)
+	}
+
+	query := selectSyncConflictSQL
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id ASC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query sync conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	conflicts := make([]engramsync.SyncConflict, 0)
+	for rows.Next() {
+		c, err := scanSyncConflict(rows)
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, *c)
+	}
+	return conflicts, rows.Err()
+}
+
+// GetSyncConflict returns a conflict inbox entry. Returns
+// ErrConflictNotFound if there is none with the ID.
+func (s *SQLiteStore) GetSyncConflict(ctx context.Context, id string) (*engramsync.SyncConflict, error) {
+	row := s.db.QueryRowContext(ctx, selectSyncConflictSQL+` WHERE id = ?`, id)
+	c, err := scanSyncConflict(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrConflictNotFound
+	}
+	return c, err
+}
+
+// ResolveSyncConflict marks an open conflict resolved with resolution and
+// an optional note. Returns ErrConflictNotFound for an unknown ID and
+// ErrConflictResolved if it was already resolved.
+func (s *SQLiteStore) ResolveSyncConflict(ctx context.Context, id, resolution, note string) (*engramsync.SyncConflict, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE sync_conflicts
+		SET status = ?, resolution = ?, note = ?, resolved_at = ?
+		WHERE id = ? AND status = ?
+	`, engramsync.ConflictStatusResolved, resolution, note,
+		formatTime(time.Now()), id, engramsync.ConflictStatusOpen)
+	if err != nil {
+		return nil, fmt.Errorf("resolve sync conflict: %w", err)
+	}
+
+	updated, _ := result.RowsAffected()
+	if updated == 0 {
+		if _, err := s.GetSyncConflict(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrConflictResolved
+	}
+	return s.GetSyncConflict(ctx, id)
+}
+
+func scanSyncConflict(row interface{ Scan(...any) error }) (*engramsync.SyncConflict, error) {
+	var c engramsync.SyncConflict
+	var entry, errs, createdAt string
+	var resolution, note, resolvedAt sql.NullString
+
+	if err := row.Scan(&c.ID, &c.PushID, &c.SourceID, &entry, &errs, &c.Status,
+		&resolution, &note, &createdAt, &resolvedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan sync conflict: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(entry), &c.Entry); err != nil {
+		return nil, fmt.Errorf("decode conflict entry: %w", err)
+	}
+	if err := json.Unmarshal([]byte(errs), &c.Errors); err != nil {
+		return nil, fmt.Errorf("decode conflict errors: %w", err)
+	}
+	c.Resolution = resolution.String
+	c.Note = note.String
+	c.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+	if resolvedAt.Valid {
+		t, _ := time.Parse(time.RFC3339Nano, resolvedAt.String)
+		c.ResolvedAt = &t
+	}
+	return &c, nil
+}
@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+// quarantine stores conflicts for the given sources and returns their IDs.
+func quarantine(t *testing.T, s *SQLiteStore, sources ...string) []string {
+	t.Helper()
+	ctx := context.Background()
+
+	conflicts := make([]engramsync.SyncConflict, len(sources))
+	for i, src := range sources {
+		conflicts[i] = engramsync.SyncConflict{
+			PushID:   "push-1",
+			SourceID: src,
+			Entry: engramsync.ChangeLogEntry{
+				Sequence:  int64(i + 1),
+				TableName: "lore_entries",
+				EntityID:  "e1",
+				Operation: engramsync.OperationUpsert,
+				Payload:   json.RawMessage(`{"id":"e1"}`),
+			},
+			Errors: []engramsync.PushError{{Sequence: int64(i + 1), Code: engramsync.PushErrorValidation, Message: "bad"}},
+		}
+	}
+
+	tx, err := s.BeginTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InsertSyncConflictsTx(ctx, tx, conflicts); err != nil {
+		t.Fatalf("InsertSyncConflictsTx() error = %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		if c.ID == "" || c.Status != engramsync.ConflictStatusOpen {
+			t.Fatalf("conflict %d not stamped: %+v", i, c)
+		}
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+func TestSyncConflicts_ListAndFilter(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	ids := quarantine(t, s, "client-a", "client-b", "client-a")
+
+	all, err := s.ListSyncConflicts(ctx, engramsync.SyncConflictFilter{})
+	if err != nil {
+		t.Fatalf("ListSyncConflicts() error = %v", err)
+	}
+	if len(all) != 3 || all[0].ID != ids[0] || string(all[0].Entry.Payload) != `{"id":"e1"}` {
+		t.Fatalf("ListSyncConflicts() = %+v, want 3 in insertion order", all)
+	}
+
+	bySource, _ := s.ListSyncConflicts(ctx, engramsync.SyncConflictFilter{SourceID: "client-a"})
+	if len(bySource) != 2 {
+		t.Errorf("source filter returned %d conflicts, want 2", len(bySource))
+	}
+
+	page, _ := s.ListSyncConflicts(ctx, engramsync.SyncConflictFilter{AfterID: ids[0], Limit: 1})
+	if len(page) != 1 || page[0].ID != ids[1] {
+		t.Errorf("page after first = %+v, want the second conflict", page)
+	}
+}
+
+func TestSyncConflicts_Resolve(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	id := quarantine(t, s, "client-a")[0]
+
+	got, err := s.ResolveSyncConflict(ctx, id, engramsync.ConflictResolutionFixed, "re-pushed")
+	if err != nil {
+		t.Fatalf("ResolveSyncConflict() error = %v", err)
+	}
+	if got.Status != engramsync.ConflictStatusResolved || got.Resolution != "fixed" || got.Note != "re-pushed" || got.ResolvedAt == nil {
+		t.Errorf("resolved conflict = %+v", got)
+	}
+
+	if _, err := s.ResolveSyncConflict(ctx, id, engramsync.ConflictResolutionDiscarded, ""); !errors.Is(err, ErrConflictResolved) {
+		t.Errorf("second resolve error = %v, want ErrConflictResolved", err)
+	}
+	if _, err := s.ResolveSyncConflict(ctx, "01ARZ3NDEKTSV4RRFFQ69G5FAV", "fixed", ""); !errors.Is(err, ErrConflictNotFound) {
+		t.Errorf("unknown resolve error = %v, want ErrConflictNotFound", err)
+	}
+
+	open, _ := s.ListSyncConflicts(ctx, engramsync.SyncConflictFilter{Status: engramsync.ConflictStatusOpen})
+	if len(open) != 0 {
+		t.Errorf("open conflicts = %+v, want none", open)
+	}
+}
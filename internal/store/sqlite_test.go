@@ -8,12 +8,15 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/hyperengineering/engram/internal/normalize"
 	"github.com/hyperengineering/engram/internal/types"
 	"github.com/oklog/ulid/v2"
 	_ "modernc.org/sqlite"
@@ -524,6 +527,211 @@ func TestGetPendingEmbeddings_ReturnsPending(t *testing.T) {
 	}
 }
 
+func TestCountPendingEmbeddings(t *testing.T) {
+	db, err := NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	entries := []types.NewLoreEntry{
+		{Content: "Entry 1", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "src"},
+		{Content: "Entry 2", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "src"},
+	}
+	if _, err := db.IngestLore(ctx, entries); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := db.GetPendingEmbeddings(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UpdateEmbedding(ctx, pending[0].ID, []float32{1, 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := db.CountPendingEmbeddings(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("CountPendingEmbeddings() = %d, want 1", n)
+	}
+}
+
+func TestGetEmbeddingBacklog_PagesPendingAndFailedByID(t *testing.T) {
+	db := newTestStore(t)
+	ctx := context.Background()
+
+	entries := make([]types.NewLoreEntry, 4)
+	for i := range entries {
+		entries[i] = types.NewLoreEntry{
+			Content:    "Entry " + string(rune('A'+i)),
+			Category:   "PATTERN_OUTCOME",
+			Confidence: 0.8,
+			SourceID:   "src",
+		}
+	}
+	if _, err := db.IngestLore(ctx, entries); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := db.GetPendingEmbeddings(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One complete, one failed, two still pending.
+	if err := db.UpdateEmbedding(ctx, pending[0].ID, []float32{1, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.MarkEmbeddingFailed(ctx, pending[1].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := db.CountEmbeddingBacklog(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("CountEmbeddingBacklog() = %d, want 3", n)
+	}
+
+	var seen []string
+	after := ""
+	for {
+		page, err := db.GetEmbeddingBacklog(ctx, after, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, e := range page {
+			if e.ID <= after {
+				t.Fatalf("entry %s returned after cursor %s", e.ID, after)
+			}
+			seen = append(seen, e.ID)
+			after = e.ID
+		}
+	}
+	if len(seen) != 3 {
+		t.Errorf("backlog pages returned %d entries, want 3", len(seen))
+	}
+	for _, id := range seen {
+		if id == pending[0].ID {
+			t.Error("backlog included an entry with a complete embedding")
+		}
+	}
+}
+
+func TestUpdateEmbedding_StoresNorm(t *testing.T) {
+	db := newTestStore(t)
+	id := insertEntryWithEmbedding(t, db, "Normed", "PATTERN_OUTCOME", []float32{3, 4})
+
+	var n sql.NullFloat64
+	if err := db.db.QueryRow("SELECT embedding_norm FROM lore_entries WHERE id = ?", id).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if !n.Valid || n.Float64 != 5 {
+		t.Errorf("embedding_norm = %+v, want 5", n)
+	}
+}
+
+func TestNewSQLiteStore_BackfillsEmbeddingIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "norms.db")
+	db, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := insertEntryWithEmbedding(t, db, "Embedded before norms", "PATTERN_OUTCOME", []float32{3, 4})
+	if _, err := db.db.Exec("UPDATE lore_entries SET embedding_norm = NULL, embedding_bucket = NULL"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Scans fall back to computing the norm until the row is backfilled.
+	similar, err := db.FindSimilar(context.Background(), []float32{3, 4}, "PATTERN_OUTCOME", 0.99)
+	if err != nil || len(similar) != 1 {
+		t.Fatalf("FindSimilar() = %v, %v; want the unnormed entry", similar, err)
+	}
+	db.Close()
+
+	db, err = NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var n sql.NullFloat64
+	var bucket sql.NullInt64
+	if err := db.db.QueryRow("SELECT embedding_norm, embedding_bucket FROM lore_entries WHERE id = ?", id).Scan(&n, &bucket); err != nil {
+		t.Fatal(err)
+	}
+	if !n.Valid || n.Float64 != 5 {
+		t.Errorf("embedding_norm after reopen = %+v, want 5", n)
+	}
+	if !bucket.Valid || bucket.Int64 != projectionBucket([]float32{3, 4}) {
+		t.Errorf("embedding_bucket after reopen = %+v, want %d", bucket, projectionBucket([]float32{3, 4}))
+	}
+}
+
+func TestNewSQLiteStore_BackfillsLanguage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "language.db")
+	db, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := insertEntryWithEmbedding(t, db, "Il faut toujours fermer la connexion avant de la rendre", "PATTERN_OUTCOME", []float32{1, 0})
+	if _, err := db.db.Exec("UPDATE lore_entries SET language = NULL"); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	db, err = NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	entry, err := db.GetLore(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Language != "fr" {
+		t.Errorf("language after reopen = %q, want fr", entry.Language)
+	}
+}
+
+func TestIngestLore_StoresDeclaredLanguageAndScope(t *testing.T) {
+	db, err := NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	_, err = db.IngestLore(ctx, []types.NewLoreEntry{{
+		Content:    "Always drain the consumer before a rebalance",
+		Category:   "DEPENDENCY_BEHAVIOR",
+		Confidence: 0.7,
+		SourceID:   "src",
+		Language:   "de",
+		Repo:       "github.com/acme/payments",
+		Path:       "internal/queue",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var id string
+	if err := db.db.QueryRow("SELECT id FROM lore_entries").Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := db.GetLore(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Language != "de" || entry.Repo != "github.com/acme/payments" || entry.Path != "internal/queue" {
+		t.Errorf("language, repo, path = %q, %q, %q", entry.Language, entry.Repo, entry.Path)
+	}
+}
+
 func TestGetPendingEmbeddings_RespectsLimit(t *testing.T) {
 	db, err := NewSQLiteStore(":memory:")
 	if err != nil {
@@ -760,7 +968,7 @@ func TestUpdateEmbedding_UpdatesTimestamp(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	time.Sleep(1100 * time.Millisecond) // Sleep >1s to ensure RFC3339 timestamp changes
+	time.Sleep(time.Millisecond) // Timestamps have sub-second precision
 
 	embedding := make([]float32, 1536)
 	err = db.UpdateEmbedding(context.Background(), id, embedding)
@@ -1632,7 +1840,7 @@ func TestMergeLore_UpdatesTimestamp(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	time.Sleep(1100 * time.Millisecond) // Ensure timestamp changes
+	time.Sleep(time.Millisecond) // Timestamps have sub-second precision
 
 	source := types.NewLoreEntry{
 		Content:  "Source content",
@@ -1736,8 +1944,14 @@ func (m *mockEmbedder) ModelName() string {
 
 // mockConfig implements the Config interface for testing.
 type mockConfig struct {
-	dedupEnabled bool
-	threshold    float64
+	dedupEnabled  bool
+	threshold     float64
+	approximate   bool
+	probeRadius   int
+	chunkSize     int
+	chunkOverlap  int
+	maxAge        time.Duration
+	minConfidence float64
 }
 
 func (m *mockConfig) GetDeduplicationEnabled() bool {
@@ -1748,6 +1962,18 @@ func (m *mockConfig) GetSimilarityThreshold() float64 {
 	return m.threshold
 }
 
+func (m *mockConfig) GetDeduplicationScope() (time.Duration, float64) {
+	return m.maxAge, m.minConfidence
+}
+
+func (m *mockConfig) GetDeduplicationProbeRadius() (int, bool) {
+	return m.probeRadius, m.approximate
+}
+
+func (m *mockConfig) GetEmbeddingChunking() (int, int) {
+	return m.chunkSize, m.chunkOverlap
+}
+
 // setupDeduplicationTest creates a store with embedder and config for deduplication testing.
 func setupDeduplicationTest(t *testing.T, dedupEnabled bool, threshold float64, embeddings map[string][]float32) *SQLiteStore {
 	t.Helper()
@@ -1890,56 +2116,111 @@ func TestIngestLore_WithDeduplication_DifferentCategoriesNotMerged(t *testing.T)
 	}
 }
 
-func TestIngestLore_WithDeduplication_BelowThresholdNotMerged(t *testing.T) {
-	// Create orthogonal embeddings (similarity = 0)
+func TestIngestLore_WithDeduplication_DifferentScopesNotMerged(t *testing.T) {
+	baseEmbedding := makeTestEmbedding(0)
 	embeddings := map[string][]float32{
-		"First content":  makeTestEmbedding(0),
-		"Second content": makeTestEmbedding(1), // Different dimension = orthogonal
+		"Payments content": baseEmbedding,
+		"Billing content":  baseEmbedding,
+		"Payments again":   baseEmbedding,
 	}
 
 	db := setupDeduplicationTest(t, true, 0.92, embeddings)
 	defer db.Close()
+	ctx := context.Background()
 
-	first := []types.NewLoreEntry{{
-		Content:    "First content",
-		Category:   "PATTERN_OUTCOME",
-		Confidence: 0.8,
-		SourceID:   "source-1",
-	}}
-	_, err := db.IngestLore(context.Background(), first)
+	for _, entry := range []types.NewLoreEntry{
+		{Content: "Payments content", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "source-1", Repo: "acme/payments"},
+		{Content: "Billing content", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "source-2", Repo: "acme/billing"},
+		{Content: "Payments again", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "source-3", Repo: "acme/payments"},
+	} {
+		if _, err := db.IngestLore(ctx, []types.NewLoreEntry{entry}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := db.db.Query("SELECT repo, COUNT(*) FROM lore_entries WHERE deleted_at IS NULL GROUP BY repo")
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer rows.Close()
+	counts := make(map[string]int)
+	for rows.Next() {
+		var repo string
+		var n int
+		if err := rows.Scan(&repo, &n); err != nil {
+			t.Fatal(err)
+		}
+		counts[repo] = n
+	}
+	if counts["acme/payments"] != 1 || counts["acme/billing"] != 1 || len(counts) != 2 {
+		t.Errorf("entries per repo = %v, want one each (merged only within acme/payments)", counts)
+	}
+}
 
-	second := []types.NewLoreEntry{{
-		Content:    "Second content",
-		Category:   "PATTERN_OUTCOME",
-		Confidence: 0.8,
-		SourceID:   "source-2",
-	}}
-	result, err := db.IngestLore(context.Background(), second)
+func TestIngestLore_WithDeduplication_OutOfScopeNotMerged(t *testing.T) {
+	base := makeTestEmbedding(0)
+	embeddings := map[string][]float32{
+		"Stale content":    base,
+		"Doubtful content": base,
+		"Fresh content":    base,
+		"New content":      base,
+	}
+	// Store the out-of-scope entries before turning deduplication on
+	db := setupDeduplicationTest(t, false, 0.92, embeddings)
+	defer db.Close()
+	ctx := context.Background()
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Stale content", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "source-1"},
+		{Content: "Doubtful content", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "source-1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	cfg := db.cfg.(*mockConfig)
+	cfg.dedupEnabled = true
+	cfg.maxAge = 90 * 24 * time.Hour
+	cfg.minConfidence = 0.3
+	if _, err := db.db.Exec(`UPDATE lore_entries SET created_at = ? WHERE content = 'Stale content'`,
+		formatTime(time.Now().Add(-100*24*time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.db.Exec(`UPDATE lore_entries SET confidence = 0.1 WHERE content = 'Doubtful content'`); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := db.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Fresh content", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "source-2"},
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
+	if result.Accepted != 1 || result.Merged != 0 {
+		t.Fatalf("entry with only out-of-scope duplicates: accepted %d, merged %d; want stored", result.Accepted, result.Merged)
+	}
 
-	// Should be accepted (below threshold)
-	if result.Accepted != 1 {
-		t.Errorf("Expected accepted=1, got %d", result.Accepted)
+	result, err = db.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "New content", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "source-3"},
+	})
+	if err != nil {
+		t.Fatal(err)
 	}
-	if result.Merged != 0 {
-		t.Errorf("Expected merged=0, got %d", result.Merged)
+	if result.Merged != 1 {
+		t.Fatalf("entry with an in-scope duplicate: merged %d, want 1", result.Merged)
+	}
+	var sources string
+	db.db.QueryRow(`SELECT sources FROM lore_entries WHERE content = 'Fresh content'`).Scan(&sources)
+	if !strings.Contains(sources, "source-3") {
+		t.Errorf("merged into the wrong entry; in-scope entry sources = %s", sources)
 	}
 }
 
-func TestIngestLore_DeduplicationDisabled_StoresAll(t *testing.T) {
-	baseEmbedding := makeTestEmbedding(0)
+func TestIngestLore_WithDeduplication_BelowThresholdNotMerged(t *testing.T) {
+	// Create orthogonal embeddings (similarity = 0)
 	embeddings := map[string][]float32{
-		"First content":  baseEmbedding,
-		"Second content": baseEmbedding,
+		"First content":  makeTestEmbedding(0),
+		"Second content": makeTestEmbedding(1), // Different dimension = orthogonal
 	}
 
-	// Deduplication DISABLED
-	db := setupDeduplicationTest(t, false, 0.92, embeddings)
+	db := setupDeduplicationTest(t, true, 0.92, embeddings)
 	defer db.Close()
 
 	first := []types.NewLoreEntry{{
@@ -1964,66 +2245,354 @@ func TestIngestLore_DeduplicationDisabled_StoresAll(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// Should be accepted (deduplication disabled)
+	// Should be accepted (below threshold)
 	if result.Accepted != 1 {
 		t.Errorf("Expected accepted=1, got %d", result.Accepted)
 	}
 	if result.Merged != 0 {
-		t.Errorf("Expected merged=0 (dedup disabled), got %d", result.Merged)
+		t.Errorf("Expected merged=0, got %d", result.Merged)
 	}
+}
 
-	// Verify two entries exist
-	var count int
-	err = db.db.QueryRow("SELECT COUNT(*) FROM lore_entries WHERE deleted_at IS NULL").Scan(&count)
+func TestIngestLore_StoreSimilarityThresholdOverridesConfig(t *testing.T) {
+	// Cosine similarity 0.8: below the configured 0.92, above the store's 0.75.
+	near := makeTestEmbedding(0)
+	near[0], near[1] = 0.8, 0.6
+	embeddings := map[string][]float32{
+		"First content":  makeTestEmbedding(0),
+		"Second content": near,
+	}
+
+	db, err := NewSQLiteStore(":memory:", WithSimilarityThreshold(0.75))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if count != 2 {
-		t.Errorf("Expected 2 entries (dedup disabled), got %d", count)
+	defer db.Close()
+	db.SetDependencies(&mockEmbedder{embeddings: embeddings}, &mockConfig{dedupEnabled: true, threshold: 0.92})
+
+	ctx := context.Background()
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{{Content: "First content", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "s1"}}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := db.IngestLore(ctx, []types.NewLoreEntry{{Content: "Second content", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "s2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Merged != 1 {
+		t.Errorf("merged = %d, want 1 under the store threshold", result.Merged)
 	}
 }
 
-func TestIngestLore_EmbeddingFailure_StoresAsPending(t *testing.T) {
-	db, err := NewSQLiteStore(":memory:")
+func TestIngestLore_RejectsCategoriesNotEnabled(t *testing.T) {
+	db, err := NewSQLiteStore(":memory:", WithCategories([]string{"ARCHITECTURAL_DECISION"}))
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer db.Close()
 
-	// Set up embedder that returns an error
-	embedder := &mockEmbedder{err: errors.New("API error")}
-	cfg := &mockConfig{dedupEnabled: true, threshold: 0.92}
-	db.SetDependencies(embedder, cfg)
-
-	entries := []types.NewLoreEntry{{
-		Content:    "Test content",
-		Category:   "PATTERN_OUTCOME",
-		Confidence: 0.8,
-		SourceID:   "source-1",
-	}}
-
-	result, err := db.IngestLore(context.Background(), entries)
+	result, err := db.IngestLore(context.Background(), []types.NewLoreEntry{
+		{Content: "Use event sourcing for orders", Category: "ARCHITECTURAL_DECISION", Confidence: 0.8, SourceID: "s1"},
+		{Content: "Retries hid the outage", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "s1"},
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
+	if result.Accepted != 1 || result.Rejected != 1 {
+		t.Fatalf("accepted = %d, rejected = %d; want 1, 1", result.Accepted, result.Rejected)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "lore[1]: category PATTERN_OUTCOME") {
+		t.Errorf("errors = %v", result.Errors)
+	}
+}
 
-	// Should be accepted (stored as pending)
-	if result.Accepted != 1 {
-		t.Errorf("Expected accepted=1, got %d", result.Accepted)
+func TestIngestLore_PerEntryResults(t *testing.T) {
+	base := makeTestEmbedding(0)
+	db, err := NewSQLiteStore(":memory:", WithCategories([]string{"PATTERN_OUTCOME"}))
+	if err != nil {
+		t.Fatal(err)
 	}
+	defer db.Close()
+	db.SetDependencies(&mockEmbedder{embeddings: map[string][]float32{
+		"Original": base,
+		"Again":    base,
+		"Other":    makeTestEmbedding(1),
+	}}, &mockConfig{dedupEnabled: true, threshold: 0.92})
+	ctx := context.Background()
 
-	// Verify entry has pending embedding status
-	var status string
-	err = db.db.QueryRow("SELECT embedding_status FROM lore_entries WHERE deleted_at IS NULL").Scan(&status)
+	result, err := db.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Original", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "s1"},
+		{Content: "Decision", Category: "ARCHITECTURAL_DECISION", Confidence: 0.8, SourceID: "s1"},
+		{Content: "Again", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "s1"},
+		{Content: "Other", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "s1"},
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if status != "pending" {
-		t.Errorf("Expected embedding_status='pending', got %q", status)
+	if len(result.Results) != 4 {
+		t.Fatalf("results = %+v, want one per entry", result.Results)
+	}
+	original := result.Results[0]
+	if original.Index != 0 || original.Status != types.IngestAccepted || original.LoreID == "" {
+		t.Errorf("results[0] = %+v, want accepted with an ID", original)
+	}
+	if r := result.Results[1]; r.Index != 1 || r.Status != types.IngestRejected || len(r.Errors) != 1 {
+		t.Errorf("results[1] = %+v, want rejected", r)
+	}
+	if r := result.Results[2]; r.Index != 2 || r.Status != types.IngestMerged || r.MergedIntoID != original.LoreID || r.LoreID != original.LoreID || r.Similarity < 0.99 {
+		t.Errorf("results[2] = %+v, want merged into %s", r, original.LoreID)
+	}
+	if r := result.Results[3]; r.Index != 3 || r.Status != types.IngestAccepted || r.LoreID == "" || r.LoreID == original.LoreID {
+		t.Errorf("results[3] = %+v, want accepted as a new entry", r)
 	}
 }
 
-func TestIngestLore_MergedCountAccurate(t *testing.T) {
+func TestIngestLore_ClientSuppliedIDs(t *testing.T) {
+	const (
+		clientID  = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+		deletedID = "01BX5ZZKBKACTAV9WEVGEMMVRZ"
+	)
+	base := makeTestEmbedding(0)
+	db := setupDeduplicationTest(t, true, 0.92, map[string][]float32{
+		"Existing":        base,
+		"Offline note":    base,
+		"Offline revised": base,
+		"Removed":         makeTestEmbedding(1),
+	})
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Existing", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "laptop"},
+		{ID: deletedID, Content: "Removed", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "laptop"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeleteLore(ctx, deletedID, "laptop"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A client ID is kept even when the entry duplicates another
+	result, err := db.IngestLore(ctx, []types.NewLoreEntry{
+		{ID: clientID, Content: "Offline note", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "laptop"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := result.Results[0]; r.Status != types.IngestAccepted || r.LoreID != clientID {
+		t.Fatalf("result = %+v, want accepted as %s", r, clientID)
+	}
+
+	// The same source re-sending the ID updates the entry
+	result, err = db.IngestLore(ctx, []types.NewLoreEntry{
+		{ID: clientID, Content: "Offline revised", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "laptop"},
+		{ID: clientID, Content: "Hijack", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "other"},
+		{ID: deletedID, Content: "Removed", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "laptop"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Updated != 1 || result.Rejected != 2 || result.Accepted != 0 || result.Merged != 0 {
+		t.Errorf("counts = %+v, want 1 updated and 2 rejected", result)
+	}
+	if r := result.Results[0]; r.Status != types.IngestUpdated || r.LoreID != clientID {
+		t.Errorf("results[0] = %+v, want updated", r)
+	}
+	for _, r := range result.Results[1:] {
+		if r.Status != types.IngestRejected {
+			t.Errorf("results[%d] = %+v, want rejected", r.Index, r)
+		}
+	}
+
+	entry, err := db.GetLore(ctx, clientID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Content != "Offline revised" || entry.SourceID != "laptop" {
+		t.Errorf("entry = %q from %s, want the revised content from laptop", entry.Content, entry.SourceID)
+	}
+}
+
+func TestIngestLore_DeduplicationDisabled_StoresAll(t *testing.T) {
+	baseEmbedding := makeTestEmbedding(0)
+	embeddings := map[string][]float32{
+		"First content":  baseEmbedding,
+		"Second content": baseEmbedding,
+	}
+
+	// Deduplication DISABLED
+	db := setupDeduplicationTest(t, false, 0.92, embeddings)
+	defer db.Close()
+
+	first := []types.NewLoreEntry{{
+		Content:    "First content",
+		Category:   "PATTERN_OUTCOME",
+		Confidence: 0.8,
+		SourceID:   "source-1",
+	}}
+	_, err := db.IngestLore(context.Background(), first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second := []types.NewLoreEntry{{
+		Content:    "Second content",
+		Category:   "PATTERN_OUTCOME",
+		Confidence: 0.8,
+		SourceID:   "source-2",
+	}}
+	result, err := db.IngestLore(context.Background(), second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Should be accepted (deduplication disabled)
+	if result.Accepted != 1 {
+		t.Errorf("Expected accepted=1, got %d", result.Accepted)
+	}
+	if result.Merged != 0 {
+		t.Errorf("Expected merged=0 (dedup disabled), got %d", result.Merged)
+	}
+
+	// Verify two entries exist
+	var count int
+	err = db.db.QueryRow("SELECT COUNT(*) FROM lore_entries WHERE deleted_at IS NULL").Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 entries (dedup disabled), got %d", count)
+	}
+}
+
+func TestIngestLore_EmbeddingFailure_StoresAsPending(t *testing.T) {
+	db, err := NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Set up embedder that returns an error
+	embedder := &mockEmbedder{err: errors.New("API error")}
+	cfg := &mockConfig{dedupEnabled: true, threshold: 0.92}
+	db.SetDependencies(embedder, cfg)
+
+	entries := []types.NewLoreEntry{{
+		Content:    "Test content",
+		Category:   "PATTERN_OUTCOME",
+		Confidence: 0.8,
+		SourceID:   "source-1",
+	}}
+
+	result, err := db.IngestLore(context.Background(), entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Should be accepted (stored as pending)
+	if result.Accepted != 1 {
+		t.Errorf("Expected accepted=1, got %d", result.Accepted)
+	}
+
+	// Verify entry has pending embedding status
+	var status string
+	err = db.db.QueryRow("SELECT embedding_status FROM lore_entries WHERE deleted_at IS NULL").Scan(&status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != "pending" {
+		t.Errorf("Expected embedding_status='pending', got %q", status)
+	}
+}
+
+// partialEmbedder returns a nil embedding and an error for contents mapped
+// to nil, and the mockEmbedder's embedding for the rest.
+type partialEmbedder struct {
+	mockEmbedder
+}
+
+func (p *partialEmbedder) EmbedBatch(ctx context.Context, contents []string) ([][]float32, error) {
+	result, _ := p.mockEmbedder.EmbedBatch(ctx, contents)
+	for _, emb := range result {
+		if emb == nil {
+			return result, errors.New("some inputs rejected")
+		}
+	}
+	return result, nil
+}
+
+func TestIngestLore_PartialEmbeddingFailure_KeepsSuccesses(t *testing.T) {
+	db := newTestStore(t)
+	db.SetDependencies(&partialEmbedder{mockEmbedder{embeddings: map[string][]float32{"Too long": nil}}}, &mockConfig{})
+
+	entries := []types.NewLoreEntry{
+		{Content: "Fine", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "source-1"},
+		{Content: "Too long", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "source-1"},
+	}
+	if _, err := db.IngestLore(context.Background(), entries); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses := map[string]string{}
+	rows, err := db.db.Query("SELECT content, embedding_status FROM lore_entries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var content, status string
+		if err := rows.Scan(&content, &status); err != nil {
+			t.Fatal(err)
+		}
+		statuses[content] = status
+	}
+	if statuses["Fine"] != "complete" || statuses["Too long"] != "pending" {
+		t.Errorf("embedding statuses = %v, want Fine complete and Too long pending", statuses)
+	}
+}
+
+func TestIngestLore_NormalizesContentBeforeDedup(t *testing.T) {
+	db, err := NewSQLiteStore(":memory:", WithNormalization(normalize.Options{
+		SmartQuotes:        true,
+		StripMarkdown:      true,
+		CollapseWhitespace: true,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetDependencies(&mockEmbedder{}, &mockConfig{dedupEnabled: true, threshold: 0.92})
+	ctx := context.Background()
+
+	for _, content := range []string{
+		"**Don’t** cache   the session token.",
+		"Don't cache the\nsession token.",
+	} {
+		if _, err := db.IngestLore(ctx, []types.NewLoreEntry{{
+			Content: content, Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "source-1",
+		}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := db.db.Query("SELECT content FROM lore_entries WHERE deleted_at IS NULL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var contents []string
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, c)
+	}
+	if len(contents) != 1 || contents[0] != "Don't cache the session token." {
+		t.Errorf("stored contents = %q, want one normalized entry", contents)
+	}
+}
+
+func TestIngestLore_MergedCountAccurate(t *testing.T) {
 	baseEmbedding := makeTestEmbedding(0)
 	embeddings := map[string][]float32{
 		"Content A": baseEmbedding,
@@ -2770,6 +3339,71 @@ func TestGetDelta_OrderByUpdatedAt(t *testing.T) {
 	}
 }
 
+func TestGetDelta_ReportsRemovedEntries(t *testing.T) {
+	db, err := NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Removed later", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var id string
+	if err := db.db.QueryRow("SELECT id FROM lore_entries LIMIT 1").Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	// A replayed delete removes the row outright, leaving no deleted_at
+	if _, err := db.db.Exec("DELETE FROM lore_entries WHERE id = ?", id); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := db.GetDelta(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Lore) != 0 || len(result.DeletedIDs) != 1 || result.DeletedIDs[0] != id {
+		t.Errorf("GetDelta() = %d lore, deleted %v; want only %s deleted", len(result.Lore), result.DeletedIDs, id)
+	}
+}
+
+func TestGetDelta_SubSecondSince(t *testing.T) {
+	db, err := NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Same second", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The row's updated_at has second precision; the change_log entry
+	// records the instant it was received.
+	var receivedAt string
+	if err := db.db.QueryRow("SELECT received_at FROM change_log LIMIT 1").Scan(&receivedAt); err != nil {
+		t.Fatal(err)
+	}
+	since, err := time.Parse(time.RFC3339Nano, receivedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := db.GetDelta(ctx, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Lore) != 1 {
+		t.Errorf("GetDelta(%s) returned %d entries, want the entry received at that instant", receivedAt, len(result.Lore))
+	}
+}
+
 // --- RecordFeedback Tests (Story 5.1) ---
 
 func TestRecordFeedback_Helpful(t *testing.T) {
@@ -3074,6 +3708,79 @@ func TestRecordFeedback_PartialSuccess_TransactionCommits(t *testing.T) {
 	}
 }
 
+func TestRecordFeedbackAtomic_MissingEntryAppliesNothing(t *testing.T) {
+	db := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Test lore", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "test-src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	delta, _ := db.GetDelta(ctx, time.Time{})
+	loreID := delta.Lore[0].ID
+	nonExistentID := "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+
+	result, err := db.RecordFeedbackAtomic(ctx, []types.FeedbackEntry{
+		{LoreID: loreID, Type: "helpful", SourceID: "client-1"},
+		{LoreID: nonExistentID, Type: "helpful", SourceID: "client-1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Updates) != 0 {
+		t.Errorf("Expected no updates, got %d", len(result.Updates))
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].LoreID != nonExistentID {
+		t.Errorf("Skipped = %+v, want %s", result.Skipped, nonExistentID)
+	}
+
+	entry, _ := db.GetLore(ctx, loreID)
+	if entry.Confidence != 0.5 {
+		t.Errorf("Confidence = %v, want 0.5 (nothing should be applied)", entry.Confidence)
+	}
+	var n int
+	if err := db.db.QueryRow(`SELECT COUNT(*) FROM feedback_events`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("feedback_events has %d rows, want 0", n)
+	}
+}
+
+func TestRecordFeedbackAtomic_AppliesAllWithNote(t *testing.T) {
+	db := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "First lore", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "test-src"},
+		{Content: "Second lore", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "test-src"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	delta, _ := db.GetDelta(ctx, time.Time{})
+
+	var feedback []types.FeedbackEntry
+	for _, l := range delta.Lore {
+		feedback = append(feedback, types.FeedbackEntry{LoreID: l.ID, Type: "not_relevant", SourceID: "client-1", Note: "wrong language"})
+	}
+	result, err := db.RecordFeedbackAtomic(ctx, feedback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Updates) != 2 || len(result.Skipped) != 0 {
+		t.Fatalf("result = %+v, want 2 updates", result)
+	}
+
+	var n int
+	if err := db.db.QueryRow(`SELECT COUNT(*) FROM feedback_events WHERE note = 'wrong language'`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("feedback_events with note = %d, want 2", n)
+	}
+}
+
 func TestRecordFeedback_SoftDeleted_ReturnsSkipped(t *testing.T) {
 	db, err := NewSQLiteStore(":memory:")
 	if err != nil {
@@ -3660,19 +4367,58 @@ func TestDecayConfidence_EmptyStore(t *testing.T) {
 	}
 }
 
-// --- DeleteLore Tests (Story 6.4) ---
-
-func TestDeleteLore_Success(t *testing.T) {
+func TestListDecayTransitions_ReportsEntriesCrossingFloor(t *testing.T) {
 	db, err := NewSQLiteStore(":memory:")
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer db.Close()
 
-	// Insert an entry first
-	entry := types.NewLoreEntry{
-		Content:    "Test content for deletion",
-		Category:   "PATTERN_OUTCOME",
+	ctx := context.Background()
+	entries := []types.NewLoreEntry{
+		{Content: "About to cross", Category: "PATTERN_OUTCOME", Confidence: 0.305, SourceID: "test-src"},
+		{Content: "Stays above", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "test-src"},
+		{Content: "Already below", Category: "PATTERN_OUTCOME", Confidence: 0.2, SourceID: "test-src"},
+	}
+	if _, err := db.IngestLore(ctx, entries); err != nil {
+		t.Fatal(err)
+	}
+	oldDate := time.Now().Add(-60 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	if _, err := db.db.Exec("UPDATE lore_entries SET last_validated_at = ?", oldDate); err != nil {
+		t.Fatal(err)
+	}
+
+	threshold := time.Now().Add(-30 * 24 * time.Hour)
+	transitions, err := db.ListDecayTransitions(ctx, threshold, 0.01, 0.3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(transitions) != 1 {
+		t.Fatalf("len(transitions) = %d, want 1", len(transitions))
+	}
+	entry, _ := db.GetLore(ctx, transitions[0].LoreID)
+	if entry.Content != "About to cross" {
+		t.Errorf("transition for %q, want %q", entry.Content, "About to cross")
+	}
+	if math.Abs(transitions[0].CurrentConfidence-0.295) > 0.001 {
+		t.Errorf("CurrentConfidence = %v, want 0.295", transitions[0].CurrentConfidence)
+	}
+}
+
+// --- DeleteLore Tests (Story 6.4) ---
+
+func TestDeleteLore_Success(t *testing.T) {
+	db, err := NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Insert an entry first
+	entry := types.NewLoreEntry{
+		Content:    "Test content for deletion",
+		Category:   "PATTERN_OUTCOME",
 		Confidence: 0.8,
 		SourceID:   "source-123",
 	}
@@ -4744,6 +5490,44 @@ func TestGetExtendedStats_CategoryDistribution(t *testing.T) {
 	}
 }
 
+func TestGetExtendedStats_LanguageDistribution(t *testing.T) {
+	db, err := NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.IngestLore(context.Background(), []types.NewLoreEntry{
+		{Content: "Always pin the driver version before an upgrade", Category: "DEPENDENCY_BEHAVIOR", Confidence: 0.5, SourceID: "src"},
+		{Content: "Die Treiberversion muss vor dem Upgrade immer fixiert werden", Category: "DEPENDENCY_BEHAVIOR", Confidence: 0.5, SourceID: "src"},
+		{Content: "Check the cache hit rate after the deploy", Category: "PERFORMANCE_INSIGHT", Confidence: 0.5, SourceID: "src"},
+		{Content: "GOMAXPROCS=4", Category: "PERFORMANCE_INSIGHT", Confidence: 0.5, SourceID: "src"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := db.GetExtendedStats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantLanguages := map[string]int64{"en": 2, "de": 1, "und": 1}
+	if !reflect.DeepEqual(stats.LanguageStats, wantLanguages) {
+		t.Errorf("LanguageStats = %v, want %v", stats.LanguageStats, wantLanguages)
+	}
+	wantBreakdown := map[string]map[string]int64{
+		"DEPENDENCY_BEHAVIOR": {"en": 1, "de": 1},
+		"PERFORMANCE_INSIGHT": {"en": 1, "und": 1},
+	}
+	if !reflect.DeepEqual(stats.CategoryLanguageStats, wantBreakdown) {
+		t.Errorf("CategoryLanguageStats = %v, want %v", stats.CategoryLanguageStats, wantBreakdown)
+	}
+	if stats.CategoryStats["DEPENDENCY_BEHAVIOR"] != 2 || stats.CategoryStats["PERFORMANCE_INSIGHT"] != 2 {
+		t.Errorf("CategoryStats = %v, want 2 per category", stats.CategoryStats)
+	}
+}
+
 func TestGetExtendedStats_QualityMetrics(t *testing.T) {
 	db, err := NewSQLiteStore(":memory:")
 	if err != nil {
@@ -4830,6 +5614,57 @@ func TestGetExtendedStats_UniqueSourceCount(t *testing.T) {
 	}
 }
 
+func TestGetExtendedStats_SourceStats(t *testing.T) {
+	db, err := NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "First", Category: "PATTERN_OUTCOME", Confidence: 0.6, SourceID: "fleet-a"},
+		{Content: "Second", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "fleet-a"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// fleet-a and fleet-c were merged into fleet-b's entry; fleet-d's is deleted
+	for _, row := range []struct {
+		source, sources, deletedAt string
+	}{
+		{"fleet-b", `["fleet-b","fleet-a","fleet-c"]`, ""},
+		{"fleet-d", `["fleet-d"]`, "2026-01-02T00:00:00.000000000Z"},
+	} {
+		_, err := db.db.Exec(`
+			INSERT INTO lore_entries (id, content, category, confidence, embedding_status, source_id, sources, created_at, updated_at, deleted_at)
+			VALUES (?, 'content', 'PATTERN_OUTCOME', 0.4, 'complete', ?, ?, '2026-01-01T00:00:00.000000000Z', '2026-01-01T00:00:00.000000000Z', NULLIF(?, ''))
+		`, generateTestID(), row.source, row.sources, row.deletedAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := db.GetExtendedStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.SourceStats) != 3 {
+		t.Fatalf("SourceStats = %+v, want fleet-a, fleet-b and fleet-c", stats.SourceStats)
+	}
+	a, b, c := stats.SourceStats[0], stats.SourceStats[1], stats.SourceStats[2]
+	if a.SourceID != "fleet-a" || a.Entries != 2 || a.Merged != 1 || math.Abs(a.AverageConfidence-0.7) > 1e-9 ||
+		math.Abs(a.MergeRate-1.0/3) > 1e-9 || a.LastSeen == nil || time.Since(*a.LastSeen) > time.Minute {
+		t.Errorf("fleet-a = %+v", a)
+	}
+	if b.SourceID != "fleet-b" || b.Entries != 1 || b.Merged != 0 || b.MergeRate != 0 ||
+		b.LastSeen == nil || !b.LastSeen.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("fleet-b = %+v", b)
+	}
+	if c.SourceID != "fleet-c" || c.Entries != 0 || c.Merged != 1 || c.MergeRate != 1 || c.AverageConfidence != 0 || c.LastSeen != nil {
+		t.Errorf("fleet-c = %+v", c)
+	}
+}
+
 func TestGetExtendedStats_ExcludesDeletedFromActive(t *testing.T) {
 	db, err := NewSQLiteStore(":memory:")
 	if err != nil {
@@ -5020,6 +5855,40 @@ func TestGetExtendedStats_SnapshotStatsWithSnapshot(t *testing.T) {
 	}
 }
 
+func TestGetSnapshotStats_MatchesExtendedStats(t *testing.T) {
+	db, err := NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	if stats, err := db.GetSnapshotStats(ctx); err != nil || stats.Available {
+		t.Fatalf("GetSnapshotStats() before a snapshot = %+v, %v", stats, err)
+	}
+
+	db.SetSnapshotMeta(1, 4096, time.Now().UTC().Add(-time.Minute))
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "test 1", Category: "TESTING_STRATEGY", Confidence: 0.5, SourceID: "test"},
+		{Content: "test 2", Category: "TESTING_STRATEGY", Confidence: 0.5, SourceID: "test"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := db.GetSnapshotStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extended, err := db.GetExtendedStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.PendingEntries != 1 || stats.LoreCount != extended.SnapshotStats.LoreCount ||
+		stats.PendingEntries != extended.SnapshotStats.PendingEntries || !stats.Available {
+		t.Errorf("GetSnapshotStats() = %+v, extended stats have %+v", stats, extended.SnapshotStats)
+	}
+}
+
 func TestGetExtendedStats_SnapshotStatsBackwardCompat(t *testing.T) {
 	db, err := NewSQLiteStore(":memory:")
 	if err != nil {
@@ -5093,6 +5962,210 @@ func TestGenerateSnapshot_CapturesMetadata(t *testing.T) {
 	}
 }
 
+func TestGenerateSnapshot_RecordsBaseSequence(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewSQLiteStore(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "test 1", Category: "TESTING_STRATEGY", Confidence: 0.5, SourceID: "test"},
+		{Content: "test 2", Category: "TESTING_STRATEGY", Confidence: 0.5, SourceID: "test"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	want, err := db.GetLatestSequence(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want == 0 {
+		t.Fatal("ingest wasn't recorded in change_log")
+	}
+	if err := db.GenerateSnapshot(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Later writes don't move the snapshot's base sequence
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "test 3", Category: "TESTING_STRATEGY", Confidence: 0.5, SourceID: "test"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := db.SnapshotBaseSequence(); !ok || got != want {
+		t.Errorf("SnapshotBaseSequence() = %d, %v, want %d", got, ok, want)
+	}
+	stats, err := db.GetSnapshotStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.BaseSequence != want {
+		t.Errorf("stats BaseSequence = %d, want %d", stats.BaseSequence, want)
+	}
+
+	snapshotDB, err := sql.Open("sqlite", db.snapshotPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snapshotDB.Close()
+	var recorded int64
+	if err := snapshotDB.QueryRow(`SELECT CAST(value AS INTEGER) FROM sync_meta WHERE key = 'snapshot_base_sequence'`).Scan(&recorded); err != nil {
+		t.Fatalf("read base sequence from snapshot: %v", err)
+	}
+	if recorded != want {
+		t.Errorf("snapshot sync_meta base sequence = %d, want %d", recorded, want)
+	}
+}
+
+func TestPrepareRestoredSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewSQLiteStore(filepath.Join(dir, "test.db"), WithPluginHooks(&hookPlugin{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Orders use event sourcing", Category: "ARCHITECTURAL_DECISION", Confidence: 0.9, SourceID: "test"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.GenerateSnapshot(ctx); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(db.snapshotPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	restoredPath := filepath.Join(dir, "restored.db")
+	if err := os.WriteFile(restoredPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	storeType, err := PrepareRestoredSnapshot(ctx, restoredPath)
+	if err != nil {
+		t.Fatalf("PrepareRestoredSnapshot() error = %v", err)
+	}
+	if storeType != "hooked" {
+		t.Errorf("store type = %q, want %q", storeType, "hooked")
+	}
+
+	restored, err := NewSQLiteStore(restoredPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+	results, err := restored.SearchLore(ctx, types.SearchQuery{Text: "event sourcing", Mode: types.SearchModeKeyword, Limit: 5})
+	if err != nil {
+		t.Fatalf("keyword search on restored store: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("keyword search returned %d results, want 1", len(results))
+	}
+	// New writes are indexed again
+	if _, err := restored.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "Payments retry with backoff", Category: "PATTERN_OUTCOME", Confidence: 0.8, SourceID: "test"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	results, err = restored.SearchLore(ctx, types.SearchQuery{Text: "backoff", Mode: types.SearchModeKeyword, Limit: 5})
+	if err != nil || len(results) != 1 {
+		t.Errorf("keyword search after ingest = %d results, %v, want 1", len(results), err)
+	}
+
+	var snapshotKeys int
+	if err := restored.DB().QueryRow(`SELECT COUNT(*) FROM sync_meta WHERE key LIKE 'snapshot_%'`).Scan(&snapshotKeys); err != nil {
+		t.Fatal(err)
+	}
+	if snapshotKeys != 0 {
+		t.Errorf("restored store kept %d snapshot-only sync_meta keys", snapshotKeys)
+	}
+}
+
+func TestGenerateSnapshot_InlinesEmbeddings(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewSQLiteStore(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	embedding := []float32{0.1, 0.2, 0.3}
+	id := insertEntryWithEmbedding(t, db, "Orders use event sourcing", "ARCHITECTURAL_DECISION", embedding)
+	if err := db.GenerateSnapshot(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Clients read embeddings from lore_entries, as the snapshot contract says
+	snap, err := sql.Open("sqlite", db.snapshotPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var blob []byte
+	err = snap.QueryRow(`SELECT embedding FROM lore_entries WHERE id = ?`, id).Scan(&blob)
+	if err != nil {
+		snap.Close()
+		t.Fatalf("read snapshot embedding: %v", err)
+	}
+
+	// embedding keeps its original position, after confidence, and the
+	// table keeps its indexes
+	var columns []string
+	rows, err := snap.Query(`SELECT name FROM pragma_table_info('lore_entries') ORDER BY cid`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		columns = append(columns, name)
+	}
+	rows.Close()
+	if want := []string{"id", "content", "context", "category", "confidence", "embedding", "embedding_status"}; len(columns) < len(want) || !slices.Equal(columns[:len(want)], want) {
+		t.Errorf("snapshot lore_entries columns = %v, want them to start %v", columns, want)
+	}
+	var indexes int
+	err = snap.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = 'idx_lore_entries_category'`).Scan(&indexes)
+	snap.Close()
+	if err != nil || indexes != 1 {
+		t.Errorf("snapshot idx_lore_entries_category count = %d (err %v), want 1", indexes, err)
+	}
+	if got := unpackEmbedding(blob); !slices.Equal(got, embedding) {
+		t.Errorf("snapshot embedding = %v, want %v", got, embedding)
+	}
+
+	data, err := os.ReadFile(db.snapshotPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	restoredPath := filepath.Join(dir, "restored.db")
+	if err := os.WriteFile(restoredPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PrepareRestoredSnapshot(ctx, restoredPath); err != nil {
+		t.Fatalf("PrepareRestoredSnapshot() error = %v", err)
+	}
+	restored, err := NewSQLiteStore(restoredPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+	entry, err := restored.GetLore(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(entry.Embedding, embedding) {
+		t.Errorf("restored embedding = %v, want %v", entry.Embedding, embedding)
+	}
+}
+
 func TestGenerateSnapshot_MetadataIncludesLoreCountInLog(t *testing.T) {
 	// This test verifies the lore count is available for logging
 	// The actual log output is tested implicitly by the metadata capture
@@ -5434,8 +6507,194 @@ func TestGetExtendedStats_ReturnsPerInstanceLastDecay(t *testing.T) {
 	}
 }
 
+func TestMigration021_NormalizesTimestampPrecision(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "engram.db")
+	db := migrateTo(t, dbPath, 20)
+	if _, err := db.Exec(`
+		INSERT INTO lore_entries (id, content, category, confidence, source_id, sources, created_at, updated_at, deleted_at)
+		VALUES ('legacy', 'legacy content', 'PATTERN_OUTCOME', 0.5, 's1', '[]',
+			'2026-01-01T00:00:00Z', '2026-01-01T00:00:00.25Z', '2026-01-01T00:00:00+02:00')
+	`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	s, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer s.Close()
+
+	var createdAt, updatedAt, deletedAt string
+	if err := s.db.QueryRow(`SELECT created_at, updated_at, deleted_at FROM lore_entries WHERE id = 'legacy'`).
+		Scan(&createdAt, &updatedAt, &deletedAt); err != nil {
+		t.Fatal(err)
+	}
+	if createdAt != "2026-01-01T00:00:00.000000000Z" {
+		t.Errorf("created_at = %q", createdAt)
+	}
+	if updatedAt != "2026-01-01T00:00:00.250000000Z" {
+		t.Errorf("updated_at = %q", updatedAt)
+	}
+	// Values with a non-UTC offset are left alone
+	if deletedAt != "2026-01-01T00:00:00+02:00" {
+		t.Errorf("deleted_at = %q", deletedAt)
+	}
+}
+
+func TestMigration028_NormalizesSyncTimestampPrecision(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "engram.db")
+	db := migrateTo(t, dbPath, 27)
+	if _, err := db.Exec(`
+		INSERT INTO push_idempotency (push_id, store_id, response, expires_at)
+		VALUES ('push-1', 'default', '{}', '2026-01-01T00:00:00Z');
+		INSERT INTO push_sessions (push_id, source_id, schema_version, created_at, expires_at)
+		VALUES ('session-1', 's1', 2, '2026-01-01T00:00:00.5Z', '2026-01-01T01:00:00Z');
+		INSERT INTO sync_conflicts (id, push_id, source_id, table_name, entity_id, entry, errors, created_at, resolved_at)
+		VALUES ('conflict-1', 'push-1', 's1', 'lore_entries', 'e1', '{}', '[]', '2026-01-01T00:00:00Z', '2026-01-01T00:00:00.123Z');
+		INSERT INTO change_log (table_name, entity_id, operation, source_id, created_at, received_at)
+		VALUES ('lore_entries', 'e1', 'delete', 's1', '2026-01-01T00:00:00.000000000Z', '2026-01-01T00:00:00.250Z')
+	`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	s, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer s.Close()
+
+	for _, tt := range []struct {
+		query string
+		want  string
+	}{
+		{`SELECT expires_at FROM push_idempotency`, "2026-01-01T00:00:00.000000000Z"},
+		{`SELECT created_at FROM push_sessions`, "2026-01-01T00:00:00.500000000Z"},
+		{`SELECT expires_at FROM push_sessions`, "2026-01-01T01:00:00.000000000Z"},
+		{`SELECT created_at FROM sync_conflicts`, "2026-01-01T00:00:00.000000000Z"},
+		{`SELECT resolved_at FROM sync_conflicts`, "2026-01-01T00:00:00.123000000Z"},
+		{`SELECT received_at FROM change_log`, "2026-01-01T00:00:00.250000000Z"},
+	} {
+		var got string
+		if err := s.db.QueryRow(tt.query).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestMigration023_MovesEmbeddingsToSideTable(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "engram.db")
+	db := migrateTo(t, dbPath, 22)
+	embedding := []float32{0.5, 0.25}
+	if _, err := db.Exec(`
+		INSERT INTO lore_entries (id, content, context, category, confidence, embedding, source_id, sources, created_at, updated_at)
+		VALUES ('embedded', 'embedded content', '', 'PATTERN_OUTCOME', 0.5, ?, 's1', '[]', ?, ?),
+		       ('pending', 'pending content', '', 'PATTERN_OUTCOME', 0.5, NULL, 's1', '[]', ?, ?)
+	`, packEmbedding(embedding), formatTime(time.Now()), formatTime(time.Now()), formatTime(time.Now()), formatTime(time.Now())); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	s, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+
+	if _, err := s.db.Exec(`SELECT embedding FROM lore_entries LIMIT 0`); err == nil {
+		t.Error("lore_entries still has an embedding column")
+	}
+	var rows int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM lore_embeddings`).Scan(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if rows != 1 {
+		t.Errorf("lore_embeddings has %d rows, want 1", rows)
+	}
+
+	entry, err := s.GetLore(ctx, "embedded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(entry.Embedding, embedding) {
+		t.Errorf("embedding = %v, want %v", entry.Embedding, embedding)
+	}
+	// The similarity index is backfilled from the moved embeddings
+	similar, err := s.FindSimilar(ctx, embedding, "PATTERN_OUTCOME", 0.99)
+	if err != nil || len(similar) != 1 || similar[0].ID != "embedded" {
+		t.Errorf("FindSimilar() = %v, %v, want the embedded entry", similar, err)
+	}
+}
+
+func TestFormatTime_SortsAsString(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	earlier := formatTime(base.Add(900 * time.Millisecond))
+	later := formatTime(base.Add(time.Second))
+	if earlier >= later {
+		t.Errorf("formatTime(%s) >= formatTime(%s)", earlier, later)
+	}
+	if got := normalizeTime("2026-03-01T14:00:00.5+02:00"); got != "2026-03-01T12:00:00.500000000Z" {
+		t.Errorf("normalizeTime() = %q", got)
+	}
+	if got := normalizeTime("not a time"); got != "not a time" {
+		t.Errorf("normalizeTime() = %q, want unparseable input unchanged", got)
+	}
+}
+
 // Helper function to generate test IDs using ULID format
 func generateTestID() string {
 	// Use ulid.Make() for proper ULID generation
 	return ulid.Make().String()
 }
+
+func TestDiskStats(t *testing.T) {
+	memory, err := NewSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memory.Close()
+	if stats, err := memory.DiskStats(); err != nil || stats != (types.DiskStats{}) {
+		t.Errorf("DiskStats() of an in-memory store = %+v, %v", stats, err)
+	}
+
+	db, err := NewSQLiteStore(filepath.Join(t.TempDir(), "disk.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	if _, err := db.IngestLore(ctx, []types.NewLoreEntry{
+		{Content: "test 1", Category: "TESTING_STRATEGY", Confidence: 0.5, SourceID: "test"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.GenerateSnapshot(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := db.DiskStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := os.Stat(db.snapshotPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.DatabaseBytes == 0 || stats.SnapshotBytes != snapshot.Size() ||
+		stats.TotalBytes != stats.DatabaseBytes+stats.WALBytes+stats.SnapshotBytes {
+		t.Errorf("DiskStats() = %+v, snapshot is %d bytes", stats, snapshot.Size())
+	}
+
+	extended, err := db.GetExtendedStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extended.DiskStats.SnapshotBytes != stats.SnapshotBytes {
+		t.Errorf("extended stats have %+v, want %+v", extended.DiskStats, stats)
+	}
+}
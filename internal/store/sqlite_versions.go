@@ -0,0 +1,314 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/language"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// Reasons recorded on a lore version for the change that replaced it.
+const (
+	VersionReasonEdit   = "edit"
+	VersionReasonMerge  = "merge"
+	VersionReasonSync   = "sync"
+	VersionReasonRevert = "revert"
+)
+
+// saveLoreVersion records an entry's current content, context and category
+// as its next version, unless the values replacing them are the same. It is
+// a no-op for entries that don't exist yet.
+func saveLoreVersion(ctx context.Context, execer execContext, id, content, loreCtx, category, reason, sourceID, now string) error {
+	_, err := execer.ExecContext(ctx, `
+		INSERT INTO lore_versions (lore_id, version, content, context, category, reason, source_id, created_at)
+		SELECT id,
+		       COALESCE((SELECT MAX(version) FROM lore_versions WHERE lore_id = ?), 0) + 1,
+		       content, context, category, ?, ?, ?
+		FROM lore_entries
+		WHERE id = ? AND (content != ? OR COALESCE(context, '') != ? OR category != ?)
+	`, id, reason, sql.NullString{String: sourceID, Valid: sourceID != ""}, now,
+		id, content, loreCtx, category)
+	if err != nil {
+		return fmt.Errorf("save lore version: %w", err)
+	}
+	return nil
+}
+
+// currentLoreVersion returns the version number of an entry's current state,
+// one past its newest saved version. Returns ErrNotFound if the entry doesn't
+// exist or is deleted.
+func currentLoreVersion(ctx context.Context, qc queryContext, id string) (int, error) {
+	var version int
+	err := qc.QueryRowContext(ctx, `
+		SELECT (SELECT COALESCE(MAX(version), 0) FROM lore_versions v WHERE v.lore_id = l.id) + 1
+		FROM lore_entries l
+		WHERE l.id = ? AND l.deleted_at IS NULL
+	`, id).Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("query lore version: %w", err)
+	}
+	return version, nil
+}
+
+// checkLoreVersion returns ErrVersionMismatch unless an entry's current
+// version is want.
+func checkLoreVersion(ctx context.Context, qc queryContext, id string, want int) error {
+	version, err := currentLoreVersion(ctx, qc, id)
+	if err != nil {
+		return err
+	}
+	if version != want {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// GetLoreVersion returns the version number of an entry's current state.
+// It changes whenever the content, context or category does, so clients can
+// use it to detect concurrent edits. Returns ErrNotFound if the entry doesn't
+// exist or is deleted.
+func (s *SQLiteStore) GetLoreVersion(ctx context.Context, id string) (int, error) {
+	return currentLoreVersion(ctx, s.db, id)
+}
+
+// ListLoreVersions returns the prior versions of a lore entry, newest first.
+// Returns ErrNotFound if the entry doesn't exist or is deleted.
+func (s *SQLiteStore) ListLoreVersions(ctx context.Context, loreID string) ([]types.LoreVersion, error) {
+	var exists int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT 1 FROM lore_entries WHERE id = ? AND deleted_at IS NULL
+	`, loreID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("check lore entry: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT version, content, context, category, reason, source_id, created_at
+		FROM lore_versions
+		WHERE lore_id = ?
+		ORDER BY version DESC
+	`, loreID)
+	if err != nil {
+		return nil, fmt.Errorf("query lore versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]types.LoreVersion, 0)
+	for rows.Next() {
+		var (
+			v                 types.LoreVersion
+			loreCtx, sourceID sql.NullString
+			createdAt         string
+		)
+		if err := rows.Scan(&v.Version, &v.Content, &loreCtx, &v.Category, &v.Reason, &sourceID, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan lore version: %w", err)
+		}
+		v.Context = loreCtx.String
+		v.SourceID = sourceID.String
+		if v.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return nil, fmt.Errorf("parse lore version time: %w", err)
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// UpdateLore edits an entry's content, context and/or category, saving the
+// previous values as a version. Changing the content clears the embedding so
+// it is regenerated. A non-zero ifVersion must match the entry's current
+// version. Returns ErrNotFound if the entry doesn't exist or is deleted, and
+// ErrVersionMismatch if it has changed since ifVersion.
+func (s *SQLiteStore) UpdateLore(ctx context.Context, id string, update types.LoreUpdate, ifVersion int) (*types.LoreEntry, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	entry, err := s.getLoreInTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ifVersion != 0 {
+		if err := checkLoreVersion(ctx, tx, id, ifVersion); err != nil {
+			return nil, err
+		}
+	}
+	content, loreCtx, category := entry.Content, entry.Context, entry.Category
+	if update.Content != nil {
+		content = *update.Content
+	}
+	if update.Context != nil {
+		loreCtx = *update.Context
+	}
+	if update.Category != nil {
+		category = *update.Category
+	}
+
+	entry, err = s.replaceLoreInTx(ctx, tx, entry, content, loreCtx, category, VersionReasonEdit, update.SourceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return entry, nil
+}
+
+// RevertLore restores an entry's content, context and category from one of
+// its versions. The state being replaced is saved as a new version, so a
+// revert can itself be reverted. Returns ErrNotFound if the entry doesn't
+// exist or is deleted, and ErrVersionNotFound if it has no such version.
+func (s *SQLiteStore) RevertLore(ctx context.Context, id string, version int, sourceID string) (*types.LoreEntry, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	entry, err := s.getLoreInTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		content, category string
+		loreCtx           sql.NullString
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT content, context, category FROM lore_versions WHERE lore_id = ? AND version = ?
+	`, id, version).Scan(&content, &loreCtx, &category)
+	if err == sql.ErrNoRows {
+		return nil, ErrVersionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch lore version: %w", err)
+	}
+
+	entry, err = s.replaceLoreInTx(ctx, tx, entry, content, loreCtx.String, category, VersionReasonRevert, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return entry, nil
+}
+
+// RecategorizeLore moves the live entries matching rc from rc.From to
+// rc.To in one transaction. Each move is an edit: the prior category is
+// saved as a version and the change is recorded in change_log, so synced
+// clients pick it up. With rc.DryRun the matching entries are reported but
+// not changed.
+func (s *SQLiteStore) RecategorizeLore(ctx context.Context, rc types.Recategorization) (*types.RecategorizeResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `SELECT id FROM lore_entries WHERE category = ? AND deleted_at IS NULL`
+	args := []any{rc.From}
+	if rc.Repo != "" {
+		query += ` AND repo = ?`
+		args = append(args, rc.Repo)
+	}
+	if rc.Path != "" {
+		query += ` AND path = ?`
+		args = append(args, rc.Path)
+	}
+	rows, err := tx.QueryContext(ctx, query+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query lore to recategorize: %w", err)
+	}
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan lore id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterate lore ids: %w", err)
+	}
+	rows.Close()
+
+	result := &types.RecategorizeResult{Moved: len(ids), IDs: ids, DryRun: rc.DryRun}
+	if rc.DryRun {
+		return result, nil
+	}
+
+	for _, id := range ids {
+		entry, err := s.getLoreInTx(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.replaceLoreInTx(ctx, tx, entry, entry.Content, entry.Context, rc.To, VersionReasonEdit, rc.SourceID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+// replaceLoreInTx saves entry as a version and replaces its content, context
+// and category, recording the change in change_log. It returns the updated
+// entry, or entry itself when nothing changed.
+func (s *SQLiteStore) replaceLoreInTx(ctx context.Context, tx *sql.Tx, entry *types.LoreEntry, content, loreCtx, category, reason, sourceID string) (*types.LoreEntry, error) {
+	if content == entry.Content && loreCtx == entry.Context && category == entry.Category {
+		return entry, nil
+	}
+
+	now := formatTime(time.Now())
+	if err := saveLoreVersion(ctx, tx, entry.ID, content, loreCtx, category, reason, sourceID, now); err != nil {
+		return nil, err
+	}
+
+	if content != entry.Content {
+		// The embedding describes the old content; regenerate it.
+		_, err := tx.ExecContext(ctx, `
+			UPDATE lore_entries
+			SET content = ?, context = ?, category = ?, embedding_norm = NULL, embedding_bucket = NULL, embedding_status = 'pending', language = ?, updated_at = ?
+			WHERE id = ?
+		`, content, loreCtx, category, language.Detect(content), now, entry.ID)
+		if err != nil {
+			return nil, fmt.Errorf("update lore entry: %w", err)
+		}
+		if err := storeEmbeddingInTx(ctx, tx, entry.ID, nil, s.quantization); err != nil {
+			return nil, err
+		}
+		if err := replaceChunksInTx(ctx, tx, entry.ID, nil, s.quantization); err != nil {
+			return nil, err
+		}
+	} else {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE lore_entries SET context = ?, category = ?, updated_at = ? WHERE id = ?
+		`, loreCtx, category, now, entry.ID)
+		if err != nil {
+			return nil, fmt.Errorf("update lore entry: %w", err)
+		}
+	}
+
+	updated, err := s.getLoreInTx(ctx, tx, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.writeChangeLogInTx(ctx, tx, "lore_entries", entry.ID, "upsert", updated, sourceID, now); err != nil {
+		return nil, fmt.Errorf("write change log: %w", err)
+	}
+	return updated, nil
+}
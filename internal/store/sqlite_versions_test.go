@@ -0,0 +1,303 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestUpdateLore_SavesPriorVersion(t *testing.T) {
+	s := newReviewTestStore(t, types.NewLoreEntry{
+		Content: "Retry twice", Context: "payments", Category: "PATTERN_OUTCOME", Confidence: 0.7, SourceID: "src",
+	})
+	ctx := context.Background()
+	id := digestTestLoreID(t, s, "Retry twice")
+	if err := s.UpdateEmbedding(ctx, id, []float32{1, 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := s.UpdateLore(ctx, id, types.LoreUpdate{Content: strPtr("Retry three times"), SourceID: "alice"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Content != "Retry three times" || entry.Context != "payments" || entry.Category != "PATTERN_OUTCOME" {
+		t.Errorf("entry = %+v, want only content changed", entry)
+	}
+	if entry.EmbeddingStatus != "pending" || entry.Embedding != nil {
+		t.Errorf("embedding status = %q, want pending with no embedding", entry.EmbeddingStatus)
+	}
+
+	versions, err := s.ListLoreVersions(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 {
+		t.Fatalf("versions = %+v, want 1", versions)
+	}
+	v := versions[0]
+	if v.Version != 1 || v.Content != "Retry twice" || v.Context != "payments" || v.Reason != VersionReasonEdit || v.SourceID != "alice" {
+		t.Errorf("version = %+v", v)
+	}
+
+	changes, err := s.GetChangeLogAfter(ctx, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := changes[len(changes)-1]
+	if last.EntityID != id || last.Operation != "upsert" || last.SourceID != "alice" {
+		t.Errorf("last change = %+v, want an upsert by alice", last)
+	}
+}
+
+func TestUpdateLore_ContextOnlyKeepsEmbedding(t *testing.T) {
+	s := newReviewTestStore(t, types.NewLoreEntry{Content: "Cache warmup", Category: "PERFORMANCE_INSIGHT", Confidence: 0.5, SourceID: "src"})
+	ctx := context.Background()
+	id := digestTestLoreID(t, s, "Cache warmup")
+	if err := s.UpdateEmbedding(ctx, id, []float32{1, 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := s.UpdateLore(ctx, id, types.LoreUpdate{Context: strPtr("only on cold start"), SourceID: "alice"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.EmbeddingStatus != "complete" || len(entry.Embedding) != 2 {
+		t.Errorf("embedding status = %q, want complete", entry.EmbeddingStatus)
+	}
+}
+
+func TestUpdateLore_UnchangedSavesNoVersion(t *testing.T) {
+	s := newReviewTestStore(t, types.NewLoreEntry{Content: "Same", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src"})
+	ctx := context.Background()
+	id := digestTestLoreID(t, s, "Same")
+
+	if _, err := s.UpdateLore(ctx, id, types.LoreUpdate{Content: strPtr("Same"), SourceID: "alice"}, 0); err != nil {
+		t.Fatal(err)
+	}
+	versions, err := s.ListLoreVersions(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 0 {
+		t.Errorf("versions = %+v, want none", versions)
+	}
+}
+
+func TestUpdateLore_NotFound(t *testing.T) {
+	s := newTestStore(t)
+	_, err := s.UpdateLore(context.Background(), "01ARZ3NDEKTSV4RRFFQ69G5FAV", types.LoreUpdate{Content: strPtr("x"), SourceID: "alice"}, 0)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRevertLore_RestoresVersion(t *testing.T) {
+	s := newReviewTestStore(t, types.NewLoreEntry{Content: "Original", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src"})
+	ctx := context.Background()
+	id := digestTestLoreID(t, s, "Original")
+	for _, content := range []string{"Second", "Third"} {
+		if _, err := s.UpdateLore(ctx, id, types.LoreUpdate{Content: strPtr(content), SourceID: "alice"}, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entry, err := s.RevertLore(ctx, id, 1, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Content != "Original" {
+		t.Errorf("content = %q, want Original", entry.Content)
+	}
+
+	versions, err := s.ListLoreVersions(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 3 {
+		t.Fatalf("versions = %+v, want 3", versions)
+	}
+	if v := versions[0]; v.Version != 3 || v.Content != "Third" || v.Reason != VersionReasonRevert || v.SourceID != "bob" {
+		t.Errorf("newest version = %+v, want the reverted state", v)
+	}
+}
+
+func TestRevertLore_UnknownVersion(t *testing.T) {
+	s := newReviewTestStore(t, types.NewLoreEntry{Content: "Original", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src"})
+	id := digestTestLoreID(t, s, "Original")
+
+	_, err := s.RevertLore(context.Background(), id, 4, "bob")
+	if !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("err = %v, want ErrVersionNotFound", err)
+	}
+}
+
+func TestMergeLore_SavesPriorVersion(t *testing.T) {
+	s := newReviewTestStore(t, types.NewLoreEntry{Content: "Merged target", Context: "first", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src"})
+	ctx := context.Background()
+	id := digestTestLoreID(t, s, "Merged target")
+
+	if err := s.MergeLore(ctx, id, types.NewLoreEntry{Context: "second", SourceID: "other"}); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := s.ListLoreVersions(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0].Context != "first" || versions[0].Reason != VersionReasonMerge || versions[0].SourceID != "other" {
+		t.Errorf("versions = %+v, want the pre-merge context", versions)
+	}
+}
+
+func TestUpsertRow_SavesPriorVersion(t *testing.T) {
+	s := newReplayTestStore(t)
+	ctx := context.Background()
+
+	if err := s.UpsertRow(ctx, "lore_entries", "entry-1", makeLorePayload(t, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpsertRow(ctx, "lore_entries", "entry-1", makeLorePayload(t, map[string]interface{}{"content": "Replayed edit"})); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := s.ListLoreVersions(ctx, "entry-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0].Content != "Test lore content" || versions[0].Reason != VersionReasonSync {
+		t.Errorf("versions = %+v, want the replaced content", versions)
+	}
+}
+
+func TestListLoreVersions_DeletedEntry(t *testing.T) {
+	s := newReviewTestStore(t, types.NewLoreEntry{Content: "Gone", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src"})
+	ctx := context.Background()
+	id := digestTestLoreID(t, s, "Gone")
+	if err := s.DeleteLore(ctx, id, "src"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.ListLoreVersions(ctx, id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetLoreVersion_AdvancesOnEdit(t *testing.T) {
+	s := newReviewTestStore(t, types.NewLoreEntry{Content: "Original", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src"})
+	ctx := context.Background()
+	id := digestTestLoreID(t, s, "Original")
+
+	version, err := s.GetLoreVersion(ctx, id)
+	if err != nil || version != 1 {
+		t.Fatalf("GetLoreVersion() = %d, %v; want 1", version, err)
+	}
+	if _, err := s.RecordFeedback(ctx, []types.FeedbackEntry{{LoreID: id, Type: "helpful"}}); err != nil {
+		t.Fatal(err)
+	}
+	if version, _ := s.GetLoreVersion(ctx, id); version != 1 {
+		t.Errorf("version after feedback = %d, want 1", version)
+	}
+	if _, err := s.UpdateLore(ctx, id, types.LoreUpdate{Content: strPtr("Edited"), SourceID: "alice"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if version, _ := s.GetLoreVersion(ctx, id); version != 2 {
+		t.Errorf("version after edit = %d, want 2", version)
+	}
+}
+
+func TestUpdateLore_VersionMismatch(t *testing.T) {
+	s := newReviewTestStore(t, types.NewLoreEntry{Content: "Original", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src"})
+	ctx := context.Background()
+	id := digestTestLoreID(t, s, "Original")
+	if _, err := s.UpdateLore(ctx, id, types.LoreUpdate{Content: strPtr("First editor"), SourceID: "alice"}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := s.UpdateLore(ctx, id, types.LoreUpdate{Content: strPtr("Second editor"), SourceID: "bob"}, 1)
+	if !errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("err = %v, want ErrVersionMismatch", err)
+	}
+	entry, _ := s.GetLore(ctx, id)
+	if entry.Content != "First editor" {
+		t.Errorf("content = %q, want the first edit kept", entry.Content)
+	}
+}
+
+func TestDeleteLoreIfVersion_Mismatch(t *testing.T) {
+	s := newReviewTestStore(t, types.NewLoreEntry{Content: "Original", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src"})
+	ctx := context.Background()
+	id := digestTestLoreID(t, s, "Original")
+	if _, err := s.UpdateLore(ctx, id, types.LoreUpdate{Content: strPtr("Edited"), SourceID: "alice"}, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.DeleteLoreIfVersion(ctx, id, "bob", 1); !errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("err = %v, want ErrVersionMismatch", err)
+	}
+	if err := s.DeleteLoreIfVersion(ctx, id, "bob", 2); err != nil {
+		t.Fatalf("delete at current version: %v", err)
+	}
+}
+
+func TestRecategorizeLore_MovesMatchingEntries(t *testing.T) {
+	s := newReviewTestStore(t,
+		types.NewLoreEntry{Content: "Payments retry", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src", Repo: "payments"},
+		types.NewLoreEntry{Content: "Billing retry", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src", Repo: "billing"},
+		types.NewLoreEntry{Content: "Payments cache", Category: "PERFORMANCE_INSIGHT", Confidence: 0.5, SourceID: "src", Repo: "payments"},
+		types.NewLoreEntry{Content: "Payments deleted", Category: "PATTERN_OUTCOME", Confidence: 0.5, SourceID: "src", Repo: "payments"},
+	)
+	ctx := context.Background()
+	moved := digestTestLoreID(t, s, "Payments retry")
+	deleted := digestTestLoreID(t, s, "Payments deleted")
+	if err := s.DeleteLore(ctx, deleted, "src"); err != nil {
+		t.Fatal(err)
+	}
+	rc := types.Recategorization{From: "PATTERN_OUTCOME", To: "TESTING_STRATEGY", Repo: "payments", SourceID: "taxonomy", DryRun: true}
+
+	preview, err := s.RecategorizeLore(ctx, rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Moved != 1 || preview.IDs[0] != moved || !preview.DryRun {
+		t.Errorf("dry run = %+v, want only %s", preview, moved)
+	}
+	if entry, _ := s.GetLore(ctx, moved); entry.Category != "PATTERN_OUTCOME" {
+		t.Errorf("dry run changed category to %s", entry.Category)
+	}
+
+	head, _ := s.GetLatestSequence(ctx)
+	rc.DryRun = false
+	result, err := s.RecategorizeLore(ctx, rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Moved != 1 || result.IDs[0] != moved || result.DryRun {
+		t.Errorf("result = %+v, want only %s moved", result, moved)
+	}
+	for content, want := range map[string]string{
+		"Payments retry": "TESTING_STRATEGY",
+		"Billing retry":  "PATTERN_OUTCOME",
+		"Payments cache": "PERFORMANCE_INSIGHT",
+	} {
+		entry, _ := s.GetLore(ctx, digestTestLoreID(t, s, content))
+		if entry.Category != want {
+			t.Errorf("%q category = %s, want %s", content, entry.Category, want)
+		}
+	}
+
+	versions, _ := s.ListLoreVersions(ctx, moved)
+	if len(versions) != 1 || versions[0].Category != "PATTERN_OUTCOME" || versions[0].SourceID != "taxonomy" {
+		t.Errorf("versions = %+v, want the prior category saved by taxonomy", versions)
+	}
+	changes, err := s.GetChangeLogAfter(ctx, head, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].EntityID != moved || changes[0].Operation != "upsert" || changes[0].SourceID != "taxonomy" {
+		t.Errorf("changes = %+v, want one upsert of %s by taxonomy", changes, moved)
+	}
+}
@@ -13,15 +13,30 @@ import (
 type Store interface {
 	IngestLore(ctx context.Context, entries []types.NewLoreEntry) (*types.IngestResult, error)
 	FindSimilar(ctx context.Context, embedding []float32, category string, threshold float64) ([]types.SimilarEntry, error)
+	SearchLore(ctx context.Context, query types.SearchQuery) ([]types.SearchResult, error)
 	MergeLore(ctx context.Context, targetID string, source types.NewLoreEntry) error
 	GetLore(ctx context.Context, id string) (*types.LoreEntry, error)
+	GetLoreVersion(ctx context.Context, id string) (int, error)
+	UpdateLore(ctx context.Context, id string, update types.LoreUpdate, ifVersion int) (*types.LoreEntry, error)
 	DeleteLore(ctx context.Context, id, sourceID string) error
+	DeleteLoreIfVersion(ctx context.Context, id, sourceID string, ifVersion int) error
+	ListLoreVersions(ctx context.Context, loreID string) ([]types.LoreVersion, error)
+	RevertLore(ctx context.Context, id string, version int, sourceID string) (*types.LoreEntry, error)
 	GetMetadata(ctx context.Context) (*types.StoreMetadata, error)
 	GetSnapshot(ctx context.Context) (io.ReadCloser, error)
 	GetDelta(ctx context.Context, since time.Time) (*types.DeltaResult, error)
 	GenerateSnapshot(ctx context.Context) error
 	GetSnapshotPath(ctx context.Context) (string, error)
 	RecordFeedback(ctx context.Context, feedback []types.FeedbackEntry) (*types.FeedbackResult, error)
+	RecordFeedbackAtomic(ctx context.Context, feedback []types.FeedbackEntry) (*types.FeedbackResult, error)
+	GetFeedbackHistory(ctx context.Context, loreID string, beforeID int64, limit int) ([]types.FeedbackEvent, error)
+	ListReviewQueue(ctx context.Context, q types.ReviewQueueQuery) ([]types.ReviewCandidate, error)
+	FindContradictions(ctx context.Context, q types.ContradictionQuery) ([]types.Contradiction, error)
+	GetReview(ctx context.Context, loreID string) (*types.LoreReview, error)
+	ReviewLore(ctx context.Context, loreID string, status types.ReviewStatus, reviewer, note string) (*types.LoreReview, error)
+	GetConfidenceLock(ctx context.Context, loreID string) (*types.ConfidenceLock, error)
+	SetConfidenceLock(ctx context.Context, loreID string, floor, ceiling *float64, setBy string) (*types.ConfidenceLock, error)
+	ClearConfidenceLock(ctx context.Context, loreID string) error
 	DecayConfidence(ctx context.Context, threshold time.Time, amount float64) (int64, error)
 	SetLastDecay(t time.Time)
 	GetLastDecay() *time.Time
@@ -35,13 +50,28 @@ type Store interface {
 	AppendChangeLog(ctx context.Context, entry *engramsync.ChangeLogEntry) (int64, error)
 	AppendChangeLogBatch(ctx context.Context, entries []engramsync.ChangeLogEntry) (int64, error)
 	GetChangeLogAfter(ctx context.Context, afterSeq int64, limit int) ([]engramsync.ChangeLogEntry, error)
+	QueryChangeLog(ctx context.Context, afterSeq int64, limit int, filter engramsync.ChangeLogFilter) ([]engramsync.ChangeLogEntry, error)
 	GetLatestSequence(ctx context.Context) (int64, error)
+	GetLatestEntries(ctx context.Context, tableNames []string) ([]engramsync.ChangeLogEntry, error)
 
 	// Push idempotency operations (sync protocol)
 	CheckPushIdempotency(ctx context.Context, pushID string) ([]byte, bool, error)
 	RecordPushIdempotency(ctx context.Context, pushID, storeID string, response []byte, ttl time.Duration) error
 	CleanExpiredIdempotency(ctx context.Context) (int64, error)
 
+	// Multi-part push sessions (sync protocol)
+	CreatePushSession(ctx context.Context, session engramsync.PushSession) error
+	GetPushSession(ctx context.Context, pushID string) (*engramsync.PushSession, error)
+	AppendPushSessionEntries(ctx context.Context, pushID string, entries []engramsync.ChangeLogEntry) (int, error)
+	GetPushSessionEntries(ctx context.Context, pushID string) ([]engramsync.ChangeLogEntry, error)
+	DeletePushSession(ctx context.Context, pushID string) error
+	CleanExpiredPushSessions(ctx context.Context) (int64, error)
+
+	// Conflict inbox for quarantined push entries (sync protocol)
+	ListSyncConflicts(ctx context.Context, filter engramsync.SyncConflictFilter) ([]engramsync.SyncConflict, error)
+	GetSyncConflict(ctx context.Context, id string) (*engramsync.SyncConflict, error)
+	ResolveSyncConflict(ctx context.Context, id, resolution, note string) (*engramsync.SyncConflict, error)
+
 	// Sync metadata operations
 	GetSyncMeta(ctx context.Context, key string) (string, error)
 	SetSyncMeta(ctx context.Context, key, value string) error
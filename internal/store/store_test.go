@@ -20,15 +20,33 @@ func (m *mockStore) IngestLore(ctx context.Context, entries []types.NewLoreEntry
 func (m *mockStore) FindSimilar(ctx context.Context, embedding []float32, category string, threshold float64) ([]types.SimilarEntry, error) {
 	return nil, nil
 }
+func (m *mockStore) SearchLore(ctx context.Context, query types.SearchQuery) ([]types.SearchResult, error) {
+	return nil, nil
+}
 func (m *mockStore) MergeLore(ctx context.Context, targetID string, source types.NewLoreEntry) error {
 	return nil
 }
 func (m *mockStore) GetLore(ctx context.Context, id string) (*types.LoreEntry, error) {
 	return nil, nil
 }
+func (m *mockStore) GetLoreVersion(ctx context.Context, id string) (int, error) {
+	return 0, nil
+}
+func (m *mockStore) UpdateLore(ctx context.Context, id string, update types.LoreUpdate, ifVersion int) (*types.LoreEntry, error) {
+	return nil, nil
+}
 func (m *mockStore) DeleteLore(ctx context.Context, id, sourceID string) error {
 	return nil
 }
+func (m *mockStore) DeleteLoreIfVersion(ctx context.Context, id, sourceID string, ifVersion int) error {
+	return nil
+}
+func (m *mockStore) ListLoreVersions(ctx context.Context, loreID string) ([]types.LoreVersion, error) {
+	return nil, nil
+}
+func (m *mockStore) RevertLore(ctx context.Context, id string, version int, sourceID string) (*types.LoreEntry, error) {
+	return nil, nil
+}
 func (m *mockStore) GetMetadata(ctx context.Context) (*types.StoreMetadata, error) {
 	return nil, nil
 }
@@ -47,6 +65,33 @@ func (m *mockStore) GetSnapshotPath(ctx context.Context) (string, error) {
 func (m *mockStore) RecordFeedback(ctx context.Context, feedback []types.FeedbackEntry) (*types.FeedbackResult, error) {
 	return nil, nil
 }
+func (m *mockStore) RecordFeedbackAtomic(ctx context.Context, feedback []types.FeedbackEntry) (*types.FeedbackResult, error) {
+	return nil, nil
+}
+func (m *mockStore) GetFeedbackHistory(ctx context.Context, loreID string, beforeID int64, limit int) ([]types.FeedbackEvent, error) {
+	return nil, nil
+}
+func (m *mockStore) ListReviewQueue(ctx context.Context, q types.ReviewQueueQuery) ([]types.ReviewCandidate, error) {
+	return nil, nil
+}
+func (m *mockStore) FindContradictions(ctx context.Context, q types.ContradictionQuery) ([]types.Contradiction, error) {
+	return nil, nil
+}
+func (m *mockStore) GetReview(ctx context.Context, loreID string) (*types.LoreReview, error) {
+	return nil, nil
+}
+func (m *mockStore) ReviewLore(ctx context.Context, loreID string, status types.ReviewStatus, reviewer, note string) (*types.LoreReview, error) {
+	return nil, nil
+}
+func (m *mockStore) GetConfidenceLock(ctx context.Context, loreID string) (*types.ConfidenceLock, error) {
+	return nil, nil
+}
+func (m *mockStore) SetConfidenceLock(ctx context.Context, loreID string, floor, ceiling *float64, setBy string) (*types.ConfidenceLock, error) {
+	return nil, nil
+}
+func (m *mockStore) ClearConfidenceLock(ctx context.Context, loreID string) error {
+	return nil
+}
 func (m *mockStore) DecayConfidence(ctx context.Context, threshold time.Time, amount float64) (int64, error) {
 	return 0, nil
 }
@@ -80,9 +125,15 @@ func (m *mockStore) AppendChangeLogBatch(ctx context.Context, entries []engramsy
 func (m *mockStore) GetChangeLogAfter(ctx context.Context, afterSeq int64, limit int) ([]engramsync.ChangeLogEntry, error) {
 	return nil, nil
 }
+func (m *mockStore) QueryChangeLog(ctx context.Context, afterSeq int64, limit int, filter engramsync.ChangeLogFilter) ([]engramsync.ChangeLogEntry, error) {
+	return nil, nil
+}
 func (m *mockStore) GetLatestSequence(ctx context.Context) (int64, error) {
 	return 0, nil
 }
+func (m *mockStore) GetLatestEntries(ctx context.Context, tableNames []string) ([]engramsync.ChangeLogEntry, error) {
+	return nil, nil
+}
 func (m *mockStore) CheckPushIdempotency(ctx context.Context, pushID string) ([]byte, bool, error) {
 	return nil, false, nil
 }
@@ -92,6 +143,33 @@ func (m *mockStore) RecordPushIdempotency(ctx context.Context, pushID, storeID s
 func (m *mockStore) CleanExpiredIdempotency(ctx context.Context) (int64, error) {
 	return 0, nil
 }
+func (m *mockStore) CreatePushSession(ctx context.Context, session engramsync.PushSession) error {
+	return nil
+}
+func (m *mockStore) GetPushSession(ctx context.Context, pushID string) (*engramsync.PushSession, error) {
+	return nil, nil
+}
+func (m *mockStore) AppendPushSessionEntries(ctx context.Context, pushID string, entries []engramsync.ChangeLogEntry) (int, error) {
+	return 0, nil
+}
+func (m *mockStore) GetPushSessionEntries(ctx context.Context, pushID string) ([]engramsync.ChangeLogEntry, error) {
+	return nil, nil
+}
+func (m *mockStore) DeletePushSession(ctx context.Context, pushID string) error {
+	return nil
+}
+func (m *mockStore) CleanExpiredPushSessions(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+func (m *mockStore) ListSyncConflicts(ctx context.Context, filter engramsync.SyncConflictFilter) ([]engramsync.SyncConflict, error) {
+	return nil, nil
+}
+func (m *mockStore) GetSyncConflict(ctx context.Context, id string) (*engramsync.SyncConflict, error) {
+	return nil, nil
+}
+func (m *mockStore) ResolveSyncConflict(ctx context.Context, id, resolution, note string) (*engramsync.SyncConflict, error) {
+	return nil, nil
+}
 func (m *mockStore) GetSyncMeta(ctx context.Context, key string) (string, error) {
 	return "", nil
 }
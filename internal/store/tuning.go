@@ -0,0 +1,97 @@
+package store
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+)
+
+// Tuning sets the SQLite pragmas that trade memory for speed. A zero field
+// is sized from the database file as it opens, so a multi-GB store gets a
+// larger cache and mapping than a new one.
+type Tuning struct {
+	// PageSize is the page size in bytes, a power of two from 512 to
+	// 65536. It only takes effect when the database is created; zero keeps
+	// SQLite's default of 4096.
+	PageSize int64
+	// CacheSize is the page cache of each connection in bytes.
+	CacheSize int64
+	// MmapSize is how much of the file, in bytes, is memory-mapped rather
+	// than read through the page cache. Negative disables memory mapping.
+	MmapSize int64
+	// TempStore is where temporary tables and indexes, as built by large
+	// sorts, are kept: "default", "file" or "memory". Empty is "default".
+	TempStore string
+}
+
+// Bounds of the cache and mapping sized from the database file.
+const (
+	minAutoCacheSize = 2 << 20
+	maxAutoCacheSize = 64 << 20
+	minAutoMmapSize  = 256 << 20
+	maxAutoMmapSize  = 2 << 30
+)
+
+// Validate checks the page size and temp store.
+func (t Tuning) Validate() error {
+	if t.PageSize != 0 && (t.PageSize < 512 || t.PageSize > 65536 || t.PageSize&(t.PageSize-1) != 0) {
+		return fmt.Errorf("page size %d is not a power of two from 512 to 65536", t.PageSize)
+	}
+	if t.CacheSize < 0 {
+		return fmt.Errorf("cache size %d is negative", t.CacheSize)
+	}
+	switch t.TempStore {
+	case "", "default", "file", "memory":
+		return nil
+	default:
+		return fmt.Errorf("unknown temp store %q (want default, file or memory)", t.TempStore)
+	}
+}
+
+// WithTuning sets the SQLite pragmas applied to every connection.
+func WithTuning(t Tuning) StoreOption {
+	return func(s *SQLiteStore) {
+		s.tuning = t
+	}
+}
+
+// resolve fills the zero cache and mmap sizes for a database file of size
+// bytes: an eighth of the file for the cache and twice the file, leaving
+// room to grow, for the mapping, each within fixed bounds.
+func (t Tuning) resolve(size int64) Tuning {
+	if t.CacheSize == 0 {
+		t.CacheSize = min(max(size/8, minAutoCacheSize), maxAutoCacheSize)
+	}
+	if t.MmapSize == 0 {
+		t.MmapSize = min(max(2*size, minAutoMmapSize), maxAutoMmapSize)
+	}
+	if t.MmapSize < 0 {
+		t.MmapSize = 0
+	}
+	return t
+}
+
+// tunedDSN returns the DSN opening dbPath with the tuning pragmas, which
+// the driver applies to each connection as it opens. Pragmas set with
+// db.Exec would reach only the connection that ran them.
+func tunedDSN(dbPath string, t Tuning) string {
+	var size int64
+	if info, err := os.Stat(dbPath); err == nil {
+		size = info.Size()
+	}
+	t = t.resolve(size)
+
+	q := url.Values{}
+	// Applied before enablePragmas switches a new database to WAL, after
+	// which its page size is fixed
+	if t.PageSize > 0 {
+		q.Add("_pragma", fmt.Sprintf("page_size(%d)", t.PageSize))
+	}
+	// A negative cache_size is in KiB rather than pages
+	q.Add("_pragma", fmt.Sprintf("cache_size(%d)", -t.CacheSize/1024))
+	q.Add("_pragma", fmt.Sprintf("mmap_size(%d)", t.MmapSize))
+	if t.TempStore != "" {
+		q.Add("_pragma", fmt.Sprintf("temp_store(%s)", t.TempStore))
+	}
+	return dbPath + "?" + q.Encode()
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestTuning_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tuning  Tuning
+		wantErr bool
+	}{
+		{"zero", Tuning{}, false},
+		{"full", Tuning{PageSize: 16384, CacheSize: 64 << 20, MmapSize: -1, TempStore: "memory"}, false},
+		{"page size not a power of two", Tuning{PageSize: 5000}, true},
+		{"page size too large", Tuning{PageSize: 131072}, true},
+		{"negative cache", Tuning{CacheSize: -1}, true},
+		{"unknown temp store", Tuning{TempStore: "ram"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.tuning.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTuning_Resolve(t *testing.T) {
+	small := Tuning{}.resolve(0)
+	if small.CacheSize != minAutoCacheSize || small.MmapSize != minAutoMmapSize {
+		t.Errorf("resolve(0) = %+v, want the minimum cache and mapping", small)
+	}
+	mid := Tuning{}.resolve(200 << 20)
+	if mid.CacheSize != 25<<20 || mid.MmapSize != 400<<20 {
+		t.Errorf("resolve(200 MiB) = %+v, want 25 MiB cache, 400 MiB mapping", mid)
+	}
+	large := Tuning{}.resolve(8 << 30)
+	if large.CacheSize != maxAutoCacheSize || large.MmapSize != maxAutoMmapSize {
+		t.Errorf("resolve(8 GiB) = %+v, want the maximum cache and mapping", large)
+	}
+	set := Tuning{CacheSize: 1 << 20, MmapSize: -1}.resolve(8 << 30)
+	if set.CacheSize != 1<<20 || set.MmapSize != 0 {
+		t.Errorf("resolve() of set sizes = %+v, want them kept with mapping disabled", set)
+	}
+}
+
+func TestWithTuning_EveryConnection(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "tuned.db"),
+		WithTuning(Tuning{PageSize: 8192, CacheSize: 8 << 20, MmapSize: -1, TempStore: "memory"}))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	var pageSize int64
+	if err := s.db.QueryRowContext(ctx, "PRAGMA page_size").Scan(&pageSize); err != nil {
+		t.Fatal(err)
+	}
+	if pageSize != 8192 {
+		t.Errorf("page_size = %d, want 8192", pageSize)
+	}
+
+	// Hold two connections at once so the pragmas are read from both
+	for i := range 2 {
+		conn, err := s.db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		var cacheSize, mmapSize, tempStore int64
+		if err := conn.QueryRowContext(ctx, "PRAGMA cache_size").Scan(&cacheSize); err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.QueryRowContext(ctx, "PRAGMA mmap_size").Scan(&mmapSize); err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.QueryRowContext(ctx, "PRAGMA temp_store").Scan(&tempStore); err != nil {
+			t.Fatal(err)
+		}
+		if cacheSize != -8192 || mmapSize != 0 || tempStore != 2 {
+			t.Errorf("connection %d: cache_size = %d, mmap_size = %d, temp_store = %d; want -8192, 0, 2",
+				i, cacheSize, mmapSize, tempStore)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// dot returns the dot product of a and b, which must have equal length.
+// The loop is unrolled over eight independent float32 accumulators so the CPU
+// can pipeline the multiplies; at 1536 dimensions float32 accumulation stays
+// well within the precision similarity thresholds need.
+func dot(a, b []float32) float32 {
+	b = b[:len(a)]
+	var s0, s1, s2, s3, s4, s5, s6, s7 float32
+	for len(a) >= 8 {
+		// Fixed-size views let the compiler drop per-element bounds checks.
+		x, y := (*[8]float32)(a), (*[8]float32)(b)
+		s0 += x[0] * y[0]
+		s1 += x[1] * y[1]
+		s2 += x[2] * y[2]
+		s3 += x[3] * y[3]
+		s4 += x[4] * y[4]
+		s5 += x[5] * y[5]
+		s6 += x[6] * y[6]
+		s7 += x[7] * y[7]
+		a, b = a[8:], b[8:]
+	}
+	for i := range a {
+		s0 += a[i] * b[i]
+	}
+	return ((s0 + s1) + (s2 + s3)) + ((s4 + s5) + (s6 + s7))
+}
+
+// norm returns the L2 norm of v.
+func norm(v []float32) float32 {
+	return float32(math.Sqrt(float64(dot(v, v))))
+}
+
+// embeddingNorm returns the value stored in the embedding_norm column: the
+// L2 norm of v, or NULL when there is no embedding.
+func embeddingNorm(v []float32) any {
+	if len(v) == 0 {
+		return nil
+	}
+	return float64(norm(v))
+}
+
+// cosineSimilarity returns the cosine similarity of a and b, or 0 when
+// either is a zero vector or their dimensions differ.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	return newQueryVector(a).similarity(b, norm(b))
+}
+
+// queryVector is an embedding compared against many candidates, with its
+// norm computed once up front.
+type queryVector struct {
+	v    []float32
+	norm float32
+}
+
+func newQueryVector(v []float32) queryVector {
+	return queryVector{v: v, norm: norm(v)}
+}
+
+// similarity returns the cosine similarity between the query and candidate,
+// given the candidate's norm.
+func (q queryVector) similarity(candidate []float32, candidateNorm float32) float64 {
+	if len(candidate) != len(q.v) || q.norm == 0 || candidateNorm == 0 {
+		return 0
+	}
+	return float64(dot(q.v, candidate) / (q.norm * candidateNorm))
+}
+
+// unpackEmbeddingInto decodes a packed embedding into dst, reusing its
+// capacity, so scans over many rows don't allocate per candidate. Quantized
+// embeddings are dequantized.
+func unpackEmbeddingInto(dst []float32, b []byte) []float32 {
+	if len(b)%4 != 0 {
+		return unpackQuantizedInto(dst, b)
+	}
+	dst = resize(dst, len(b)/4)
+	for i := range dst {
+		dst[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[i*4:]))
+	}
+	return dst
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// referenceCosine is the straightforward float64 computation dot and
+// queryVector must agree with.
+func referenceCosine(a, b []float32) float64 {
+	var d, na, nb float64
+	for i := range a {
+		d += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	return d / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+func randomVector(rng *rand.Rand, dim int) []float32 {
+	v := make([]float32, dim)
+	for i := range v {
+		v[i] = float32(rng.NormFloat64())
+	}
+	return v
+}
+
+func TestCosineSimilarity_MatchesReference(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	// Odd lengths exercise the remainder loop after the unrolled one.
+	for _, dim := range []int{1, 3, 7, 384, 1536} {
+		a, b := randomVector(rng, dim), randomVector(rng, dim)
+		got, want := cosineSimilarity(a, b), referenceCosine(a, b)
+		if math.Abs(got-want) > 1e-5 {
+			t.Errorf("dim %d: cosineSimilarity = %v, want %v", dim, got, want)
+		}
+	}
+}
+
+func TestCosineSimilarity_Degenerate(t *testing.T) {
+	for name, tt := range map[string]struct{ a, b []float32 }{
+		"zero vector":        {[]float32{0, 0}, []float32{1, 0}},
+		"dimension mismatch": {[]float32{1, 0}, []float32{1, 0, 0}},
+		"empty":              {nil, nil},
+	} {
+		if got := cosineSimilarity(tt.a, tt.b); got != 0 {
+			t.Errorf("%s: cosineSimilarity = %v, want 0", name, got)
+		}
+	}
+}
+
+func TestUnpackEmbeddingInto_ReusesBuffer(t *testing.T) {
+	buf := make([]float32, 0, 4)
+	got := unpackEmbeddingInto(buf, packEmbedding([]float32{1, 2, 3}))
+	if len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Fatalf("unpacked = %v, want [1 2 3]", got)
+	}
+	if &got[0] != &buf[:1][0] {
+		t.Error("unpackEmbeddingInto allocated despite sufficient capacity")
+	}
+}
+
+func BenchmarkCosineSimilarity_1536(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	query := newQueryVector(randomVector(rng, 1536))
+	candidate := randomVector(rng, 1536)
+	candidateNorm := norm(candidate)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		query.similarity(candidate, candidateNorm)
+	}
+}
+
+func BenchmarkReferenceCosine_1536(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	a, c := randomVector(rng, 1536), randomVector(rng, 1536)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		referenceCosine(a, c)
+	}
+}
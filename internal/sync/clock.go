@@ -0,0 +1,79 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PushWarningClockSkew is the code of a push warning for a client
+// timestamp that was ahead of the server clock and was clamped.
+const PushWarningClockSkew = "CLOCK_SKEW"
+
+// TimestampFields are the top-level payload fields checked for clock skew.
+var TimestampFields = []string{"created_at", "updated_at"}
+
+// ClampClockSkew clamps client timestamps more than maxSkew ahead of now
+// to now, so a client with a fast clock cannot push entries dated in the
+// future and hide them from delta-by-time consumers. It checks each
+// entry's created_at and the TimestampFields of upsert payloads, rewrites
+// the entries in place, and returns a warning for every clamped value.
+// Timestamps that do not parse as RFC 3339 are left to validation.
+func ClampClockSkew(entries []ChangeLogEntry, now time.Time, maxSkew time.Duration) []PushError {
+	now = now.UTC()
+	limit := now.Add(maxSkew)
+
+	var warnings []PushError
+	// field is empty for the entry's own created_at
+	warn := func(entry *ChangeLogEntry, field string, ts time.Time) {
+		name := field
+		if name == "" {
+			name = "entry created_at"
+		}
+		warnings = append(warnings, PushError{
+			Sequence:  entry.Sequence,
+			TableName: entry.TableName,
+			EntityID:  entry.EntityID,
+			Code:      PushWarningClockSkew,
+			Message: fmt.Sprintf("%s is %s ahead of server time; clamped to %s",
+				name, ts.Sub(now).Round(time.Second), now.Format(time.RFC3339)),
+			Field: field,
+		})
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		if entry.CreatedAt.After(limit) {
+			warn(entry, "", entry.CreatedAt)
+			entry.CreatedAt = now
+		}
+		if entry.Operation != OperationUpsert || len(entry.Payload) == 0 {
+			continue
+		}
+
+		var payload map[string]json.RawMessage
+		if err := json.Unmarshal(entry.Payload, &payload); err != nil {
+			continue
+		}
+		clamped := false
+		for _, field := range TimestampFields {
+			var raw string
+			if err := json.Unmarshal(payload[field], &raw); err != nil {
+				continue
+			}
+			ts, err := time.Parse(time.RFC3339Nano, raw)
+			if err != nil || !ts.After(limit) {
+				continue
+			}
+			warn(entry, field, ts)
+			payload[field], _ = json.Marshal(now.Format(time.RFC3339Nano))
+			clamped = true
+		}
+		if clamped {
+			if rewritten, err := json.Marshal(payload); err == nil {
+				entry.Payload = rewritten
+			}
+		}
+	}
+	return warnings
+}
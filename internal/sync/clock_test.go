@@ -0,0 +1,49 @@
+package sync
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestClampClockSkew(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	future := now.Add(2 * time.Hour).Format(time.RFC3339)
+	nearFuture := now.Add(time.Minute).Format(time.RFC3339)
+
+	entries := []ChangeLogEntry{
+		{Sequence: 1, TableName: "notes", EntityID: "n1", Operation: OperationUpsert, CreatedAt: now,
+			Payload: json.RawMessage(`{"id":"n1","created_at":"` + nearFuture + `","updated_at":"` + future + `"}`)},
+		{Sequence: 2, TableName: "notes", EntityID: "n2", Operation: OperationDelete, CreatedAt: now.Add(time.Hour)},
+		{Sequence: 3, TableName: "notes", EntityID: "n3", Operation: OperationUpsert, CreatedAt: now,
+			Payload: json.RawMessage(`{"id":"n3","updated_at":"not a time"}`)},
+	}
+
+	warnings := ClampClockSkew(entries, now, 5*time.Minute)
+	if len(warnings) != 2 {
+		t.Fatalf("warnings = %+v, want 2", warnings)
+	}
+	if w := warnings[0]; w.Sequence != 1 || w.Field != "updated_at" || w.Code != PushWarningClockSkew {
+		t.Errorf("warnings[0] = %+v, want updated_at of sequence 1", w)
+	}
+	if w := warnings[1]; w.Sequence != 2 || w.Field != "" {
+		t.Errorf("warnings[1] = %+v, want the entry created_at of sequence 2", w)
+	}
+
+	var payload map[string]string
+	if err := json.Unmarshal(entries[0].Payload, &payload); err != nil {
+		t.Fatalf("decode clamped payload: %v", err)
+	}
+	if payload["updated_at"] != now.Format(time.RFC3339Nano) {
+		t.Errorf("updated_at = %q, want server time", payload["updated_at"])
+	}
+	if payload["created_at"] != nearFuture {
+		t.Errorf("created_at = %q, want %q left within tolerance", payload["created_at"], nearFuture)
+	}
+	if !entries[1].CreatedAt.Equal(now) {
+		t.Errorf("entry created_at = %v, want %v", entries[1].CreatedAt, now)
+	}
+	if string(entries[2].Payload) != `{"id":"n3","updated_at":"not a time"}` {
+		t.Errorf("unparseable timestamp rewritten: %s", entries[2].Payload)
+	}
+}
@@ -3,6 +3,8 @@ package sync
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/hyperengineering/engram/internal/hlc"
 )
 
 // ChangeLogEntry represents a single entry in the change log.
@@ -15,6 +17,23 @@ type ChangeLogEntry struct {
 	SourceID   string          `json:"source_id"`
 	CreatedAt  time.Time       `json:"created_at"`
 	ReceivedAt time.Time       `json:"received_at"`
+
+	// SourceSequence is the sequence the originating client assigned to the
+	// entry when it was pushed. Zero for server-generated entries.
+	SourceSequence int64 `json:"source_sequence,omitempty"`
+
+	// HLC is the hybrid logical clock reading the server stamped on the
+	// entry. A reading sent by a pushing client is observed by the
+	// server's clock first, so the stamp orders after it.
+	HLC hlc.Timestamp `json:"hlc,omitempty"`
+}
+
+// OutboxEntry is a change_log entry waiting in the event outbox to be
+// published to the event bus.
+type OutboxEntry struct {
+	ID       int64          `json:"id"`
+	Entry    ChangeLogEntry `json:"entry"`
+	Attempts int            `json:"attempts"`
 }
 
 // Operation constants
@@ -37,6 +56,17 @@ const (
 	SyncMetaSchemaVersion     = "schema_version"
 	SyncMetaLastCompactionSeq = "last_compaction_seq"
 	SyncMetaLastCompactionAt  = "last_compaction_at"
+	// SyncMetaStateFloorSeq is the lowest sequence at which the change log
+	// still records every entity's state; compaction may have removed the
+	// state at earlier sequences.
+	SyncMetaStateFloorSeq = "state_floor_seq"
+	// SyncMetaSnapshotBaseSeq is recorded in a snapshot file rather than the
+	// live store: the latest change_log sequence the snapshot contains.
+	SyncMetaSnapshotBaseSeq = "snapshot_base_sequence"
+	// SyncMetaSnapshotStoreType is also recorded only in snapshot files: the
+	// type of the store the snapshot was taken from, so it can be restored
+	// as one.
+	SyncMetaSnapshotStoreType = "snapshot_store_type"
 )
 
 // PushRequest is the request body for POST /sync/push.
@@ -45,12 +75,99 @@ type PushRequest struct {
 	SourceID      string           `json:"source_id"`
 	SchemaVersion int              `json:"schema_version"`
 	Entries       []ChangeLogEntry `json:"entries"`
+
+	// AllowDeferredParents skips cross-batch reference validation, for
+	// clients that push children before their parents across pushes.
+	AllowDeferredParents bool `json:"allow_deferred_parents,omitempty"`
+
+	// CascadeDeletes asks the store's plugin to also delete dependents of
+	// entities deleted in this push. Plugins without cascade support ignore it.
+	CascadeDeletes bool `json:"cascade_deletes,omitempty"`
+
+	// QuarantineInvalid applies the entries that pass validation and moves
+	// the rest to the store's conflict inbox instead of rejecting the push.
+	QuarantineInvalid bool `json:"quarantine_invalid,omitempty"`
+
+	// Mode selects how a push with invalid entries is handled: "atomic"
+	// (the default) rejects the whole push, "partial" applies the valid
+	// entries and reports the rest in PushResponse.Rejected.
+	Mode string `json:"mode,omitempty"`
 }
 
+// Push modes.
+const (
+	PushModeAtomic  = "atomic"
+	PushModePartial = "partial"
+)
+
 // PushResponse is the success response for POST /sync/push.
 type PushResponse struct {
 	Accepted       int   `json:"accepted"`
 	RemoteSequence int64 `json:"remote_sequence"`
+
+	// Cascaded is the number of accepted entries generated by cascade_deletes.
+	Cascaded int `json:"cascaded,omitempty"`
+
+	// Quarantined lists the entries of a quarantine_invalid push that were
+	// moved to the conflict inbox.
+	Quarantined []QuarantinedEntry `json:"quarantined,omitempty"`
+
+	// Rejected lists the errors of entries a partial push did not apply.
+	Rejected []PushError `json:"rejected,omitempty"`
+
+	// Warnings reports adjustments made to accepted entries, such as
+	// timestamps clamped for clock skew.
+	Warnings []PushError `json:"warnings,omitempty"`
+
+	// SchemaUpgrade is set when the client's schema_version is behind the
+	// server and the store's plugin ships client migrations.
+	SchemaUpgrade *SchemaUpgrade `json:"schema_upgrade,omitempty"`
+}
+
+// ClientMigration is forward migration SQL for a client's local replica.
+// Version is the sync schema version the client is at after applying it.
+type ClientMigration struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+	SQL     string `json:"sql"`
+}
+
+// SchemaUpgrade tells a client that is behind the server which migrations
+// to apply, in order, to reach ServerVersion.
+type SchemaUpgrade struct {
+	ServerVersion int               `json:"server_version"`
+	Migrations    []ClientMigration `json:"migrations"`
+}
+
+// SchemaNegotiationResponse is the response for GET /sync/schema.
+type SchemaNegotiationResponse struct {
+	ClientVersion int `json:"client_version"`
+	ServerVersion int `json:"server_version"`
+	// SupportedVersions lists the schema versions, up to ServerVersion,
+	// that clients of the store may sync with.
+	SupportedVersions []int             `json:"supported_versions"`
+	Migrations        []ClientMigration `json:"migrations"`
+}
+
+// PushSession is a multi-part push staged on the server. Entries are
+// appended in chunks and committed as one push under PushID.
+type PushSession struct {
+	PushID               string    `json:"push_id"`
+	SourceID             string    `json:"source_id"`
+	SchemaVersion        int       `json:"schema_version"`
+	AllowDeferredParents bool      `json:"allow_deferred_parents,omitempty"`
+	CascadeDeletes       bool      `json:"cascade_deletes,omitempty"`
+	QuarantineInvalid    bool      `json:"quarantine_invalid,omitempty"`
+	Mode                 string    `json:"mode,omitempty"`
+	EntryCount           int       `json:"entry_count"`
+	CreatedAt            time.Time `json:"created_at"`
+	ExpiresAt            time.Time `json:"expires_at"`
+}
+
+// PushSessionAppendRequest is the request body for appending a chunk of
+// entries to a push session.
+type PushSessionAppendRequest struct {
+	Entries []ChangeLogEntry `json:"entries"`
 }
 
 // PushError represents a single entry error in a failed push.
@@ -60,12 +177,22 @@ type PushError struct {
 	EntityID  string `json:"entity_id"`
 	Code      string `json:"code"`
 	Message   string `json:"message"`
+	// Field is the path of the payload field that failed validation, e.g.
+	// "sources[2]", when the error concerns one field.
+	Field string `json:"field,omitempty"`
+	// Constraint is the payload schema keyword that failed, e.g.
+	// "required" or "enum".
+	Constraint string `json:"constraint,omitempty"`
 }
 
 // PushErrorResponse is the failure response for POST /sync/push.
 type PushErrorResponse struct {
 	Accepted int         `json:"accepted"`
 	Errors   []PushError `json:"errors"`
+
+	// MissingParents maps parent table to the referenced IDs that were not
+	// found. Only set when entries were rejected with MISSING_PARENT.
+	MissingParents map[string][]string `json:"missing_parents,omitempty"`
 }
 
 // Error codes for push errors
@@ -74,12 +201,101 @@ const (
 	PushErrorUnknownTable = "UNKNOWN_TABLE"
 	PushErrorMissingField = "MISSING_FIELD"
 	PushErrorInvalidFormat = "INVALID_FORMAT"
+	PushErrorMissingParent = "MISSING_PARENT"
+)
+
+// QuarantinedEntry reports a pushed entry stored in the conflict inbox.
+type QuarantinedEntry struct {
+	ConflictID string      `json:"conflict_id"`
+	Sequence   int64       `json:"sequence"`
+	TableName  string      `json:"table_name"`
+	EntityID   string      `json:"entity_id"`
+	Errors     []PushError `json:"errors"`
+}
+
+// Conflict statuses
+const (
+	ConflictStatusOpen     = "open"
+	ConflictStatusResolved = "resolved"
+)
+
+// Conflict resolutions
+const (
+	// ConflictResolutionDiscarded means the client dropped the change.
+	ConflictResolutionDiscarded = "discarded"
+	// ConflictResolutionFixed means the client pushed a corrected change.
+	ConflictResolutionFixed = "fixed"
 )
 
+// SyncConflict is an entry held in a store's conflict inbox after a
+// quarantine_invalid push rejected it.
+type SyncConflict struct {
+	ID         string         `json:"id"`
+	PushID     string         `json:"push_id"`
+	SourceID   string         `json:"source_id"`
+	Entry      ChangeLogEntry `json:"entry"`
+	Errors     []PushError    `json:"errors"`
+	Status     string         `json:"status"`
+	Resolution string         `json:"resolution,omitempty"`
+	Note       string         `json:"note,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	ResolvedAt *time.Time     `json:"resolved_at,omitempty"`
+}
+
+// SyncConflictFilter narrows a conflict inbox listing. The zero value
+// lists every conflict.
+type SyncConflictFilter struct {
+	// Status restricts results to open or resolved conflicts.
+	Status string
+
+	// SourceID restricts results to conflicts pushed by this client.
+	SourceID string
+
+	// AfterID resumes a listing after the conflict with this ID.
+	AfterID string
+
+	// Limit caps the number of results; zero means no limit.
+	Limit int
+}
+
 // DeltaRequest parameters (parsed from query string).
 type DeltaRequest struct {
 	After int64
 	Limit int
+
+	// Tables restricts the delta to the listed tables (?tables=a,b).
+	Tables []string
+
+	// Exclude lists top-level payload fields to omit (?exclude=embedding).
+	Exclude []string
+
+	// ExcludeSource omits entries pushed by this source_id (?exclude_source=).
+	ExcludeSource string
+
+	// SinceTime and UntilTime restrict the delta to entries the server
+	// received in [SinceTime, UntilTime) (?since_time=, ?until_time=).
+	// Zero values leave that end of the window open.
+	SinceTime time.Time
+	UntilTime time.Time
+}
+
+// ChangeLogFilter narrows a change log query. The zero value matches
+// every entry.
+type ChangeLogFilter struct {
+	// Tables restricts results to the listed table names.
+	Tables []string
+
+	// ExcludeTables drops entries of the listed table names.
+	ExcludeTables []string
+
+	// ExcludeSourceID drops entries pushed by this source.
+	ExcludeSourceID string
+
+	// ReceivedSince and ReceivedUntil keep entries whose received_at is
+	// at or after ReceivedSince and before ReceivedUntil. Zero values are
+	// unbounded.
+	ReceivedSince time.Time
+	ReceivedUntil time.Time
 }
 
 // DeltaResponse is the response for GET /sync/delta.
@@ -90,6 +306,40 @@ type DeltaResponse struct {
 	HasMore        bool             `json:"has_more"`
 }
 
+// VerifyResponse is the response for GET /sync/verify: a checksum of the
+// replica state as of sequence Upto.
+//
+// Checksum is "sha256:" followed by the hex SHA-256 of one line per entity
+// whose latest change up to Upto is an upsert, ordered by table name and
+// then entity ID as raw bytes. Each line is the table name, entity ID and
+// payload updated_at separated by tabs and ending in a newline; a missing
+// updated_at is empty.
+type VerifyResponse struct {
+	Upto     int64  `json:"upto"`
+	Entities int64  `json:"entities"`
+	Checksum string `json:"checksum"`
+}
+
+// ExchangeRequest is the request body for POST /sync/exchange: an optional
+// push applied first, then a delta read from After.
+type ExchangeRequest struct {
+	Push    *PushRequest `json:"push,omitempty"`
+	After   int64        `json:"after"`
+	Limit   int          `json:"limit,omitempty"`
+	Tables  []string     `json:"tables,omitempty"`
+	Exclude []string     `json:"exclude,omitempty"`
+
+	// ExcludeSource omits the caller's own entries from the delta.
+	ExcludeSource string `json:"exclude_source,omitempty"`
+}
+
+// ExchangeResponse is the response for POST /sync/exchange. Push is omitted
+// when the request carried no push.
+type ExchangeResponse struct {
+	Push  *PushResponse `json:"push,omitempty"`
+	Delta DeltaResponse `json:"delta"`
+}
+
 // Default and max limits for delta queries.
 const (
 	DefaultDeltaLimit = 500
@@ -2,7 +2,11 @@ package types
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
+
+	"github.com/hyperengineering/engram/internal/hlc"
 )
 
 // LoreCategory represents the classification of lore type
@@ -34,6 +38,9 @@ type Lore struct {
 	CreatedAt       time.Time    `json:"created_at"`
 	UpdatedAt       time.Time    `json:"updated_at"`
 	SyncedAt        *time.Time   `json:"synced_at,omitempty"`
+	Language        string       `json:"language,omitempty"` // declared language; detected when empty
+	Repo            string       `json:"repo,omitempty"`
+	Path            string       `json:"path,omitempty"`
 }
 
 // FeedbackOutcome represents the type of feedback for lore
@@ -102,6 +109,17 @@ type HealthResponse struct {
 	StoreID        string     `json:"store_id,omitempty"`    // Included when store parameter specified
 	StoreType      string     `json:"store_type,omitempty"`  // Store type: "recall", "generic", etc.
 	SchemaVersion  int        `json:"schema_version"`        // Schema version for client compatibility
+
+	// EmbeddingDimensions is included when the store tracks them. Status is
+	// "degraded" once it has refused embeddings of another dimension.
+	EmbeddingDimensions *EmbeddingDimensions `json:"embedding_dimensions,omitempty"`
+}
+
+// LoreCountResponse carries the number of live lore entries in a store,
+// for clients of the paged listings that need a total.
+type LoreCountResponse struct {
+	StoreID string `json:"store_id,omitempty"`
+	Count   int64  `json:"count"`
 }
 
 // --- Architecture-aligned domain types (Story 1.1) ---
@@ -122,23 +140,66 @@ type LoreEntry struct {
 	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
 	LastValidatedAt *time.Time `json:"last_validated_at,omitempty"`
 	EmbeddingStatus string     `json:"embedding_status"`
+	Language        string     `json:"language,omitempty"` // ISO 639-1 code, or "und"
+	Repo            string     `json:"repo,omitempty"`     // repository the entry applies to; empty for all
+	Path            string     `json:"path,omitempty"`     // file or directory within Repo; empty for all
+	// HLC is the hybrid logical clock reading of the entry's latest
+	// change_log entry.
+	HLC hlc.Timestamp `json:"hlc,omitempty"`
 }
 
 // NewLoreEntry is the input type for creating lore entries (without generated fields).
 type NewLoreEntry struct {
+	// ID is an optional client-generated ULID. An entry with an ID is
+	// stored under it, or updates the entry its source already stored
+	// under it, instead of being merged into a similar entry.
+	ID         string  `json:"id,omitempty"`
 	Content    string  `json:"content"`
 	Context    string  `json:"context,omitempty"`
 	Category   string  `json:"category"`
 	Confidence float64 `json:"confidence"`
 	SourceID   string  `json:"source_id"`
+	Language   string  `json:"language,omitempty"` // declared language; detected when empty
+	Repo       string  `json:"repo,omitempty"`
+	Path       string  `json:"path,omitempty"`
 }
 
 // IngestResult represents the outcome of an ingest operation.
 type IngestResult struct {
-	Accepted int      `json:"accepted"`
-	Merged   int      `json:"merged"`
+	Accepted int `json:"accepted"`
+	Merged   int `json:"merged"`
+	// Updated counts entries whose client-supplied ID was already stored
+	// by the same source and were applied as updates.
+	Updated  int      `json:"updated"`
 	Rejected int      `json:"rejected"`
 	Errors   []string `json:"errors"`
+	// CreatedIDs lists the IDs of entries stored as new lore, in request
+	// order, so clients can reference them without polling delta.
+	CreatedIDs []string `json:"created_ids"`
+	// Results holds one outcome per submitted entry, in request order.
+	Results []IngestEntryResult `json:"results"`
+}
+
+// IngestStatus is the outcome of one ingested entry.
+type IngestStatus string
+
+const (
+	IngestAccepted IngestStatus = "accepted" // stored as a new entry
+	IngestMerged   IngestStatus = "merged"   // merged into an existing entry
+	IngestUpdated  IngestStatus = "updated"  // updated the entry stored under its client ID
+	IngestRejected IngestStatus = "rejected" // not stored; see Errors
+)
+
+// IngestEntryResult is the outcome of one entry of an ingest request, so
+// clients can map their local items to server IDs. LoreID is the entry
+// now holding the content: the new entry, or the one it merged into.
+type IngestEntryResult struct {
+	Index        int          `json:"index"`
+	Status       IngestStatus `json:"status"`
+	LoreID       string       `json:"lore_id,omitempty"`
+	MergedIntoID string       `json:"merged_into_id,omitempty"`
+	Similarity   float64      `json:"similarity,omitempty"`
+	Errors       []string     `json:"errors,omitempty"`
 }
 
 // DeltaResult represents the response from a delta sync query.
@@ -149,13 +210,17 @@ type DeltaResult struct {
 }
 
 // FeedbackEntry represents a single feedback submission.
-// Note: SourceID is captured for request context but intentionally not persisted.
-// Feedback is anonymous by design - we track what lore is helpful/incorrect,
-// not who reported it. This prevents gaming and protects client privacy.
+// Feedback events are recorded without their source, so feedback history
+// shows what lore is helpful or incorrect, not who reported it. SourceID is
+// used to guard against abuse: it is compared with the entry's own sources,
+// so agents cannot validate their own lore, and kept as a hash in the
+// feedback rate limit windows until the window ends, so repeated feedback
+// can be capped.
 type FeedbackEntry struct {
 	LoreID   string `json:"lore_id"`
 	Type     string `json:"type"`
-	SourceID string `json:"source_id"` // For logging/debugging only; not persisted
+	Note     string `json:"note,omitempty"` // Optional explanation, persisted with the feedback event
+	SourceID string `json:"source_id"`      // Hashed into feedback_windows for rate limiting; never stored in plain text
 }
 
 // FeedbackResult represents the outcome of recording feedback.
@@ -167,7 +232,7 @@ type FeedbackResult struct {
 // FeedbackSkipped represents a feedback entry that could not be processed.
 type FeedbackSkipped struct {
 	LoreID string `json:"lore_id"`
-	Reason string `json:"reason"` // "not_found" or "deleted"
+	Reason string `json:"reason"` // "not_found", "deleted", "rate_limited" or "self_feedback"
 }
 
 // FeedbackResultUpdate represents a single confidence change from feedback.
@@ -178,12 +243,272 @@ type FeedbackResultUpdate struct {
 	ValidationCount    *int    `json:"validation_count,omitempty"` // Only set for helpful feedback
 }
 
+// FeedbackEvent is one recorded piece of feedback on a lore entry.
+type FeedbackEvent struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"type"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FeedbackHistoryResponse lists the feedback recorded for a lore entry,
+// newest first.
+type FeedbackHistoryResponse struct {
+	LoreID     string          `json:"lore_id"`
+	Events     []FeedbackEvent `json:"events"`
+	HasMore    bool            `json:"has_more"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// ReviewStatus is the curation state of a lore entry. Entries start
+// unreviewed and move once to approved or rejected.
+type ReviewStatus string
+
+const (
+	ReviewUnreviewed ReviewStatus = "unreviewed"
+	ReviewApproved   ReviewStatus = "approved" // confidence floor locked at approval
+	ReviewRejected   ReviewStatus = "rejected" // entry soft-deleted
+)
+
+// LoreReview is the review state of a lore entry.
+type LoreReview struct {
+	LoreID          string       `json:"lore_id"`
+	Status          ReviewStatus `json:"status"`
+	Reviewer        string       `json:"reviewer,omitempty"`
+	Note            string       `json:"note,omitempty"`
+	ConfidenceFloor *float64     `json:"confidence_floor,omitempty"`
+	ReviewedAt      *time.Time   `json:"reviewed_at,omitempty"`
+}
+
+// ConfidenceLock bounds an entry's confidence. An entry with a floor is
+// pinned: incorrect feedback and decay cannot drop it below the floor. An
+// entry with a ceiling is deprecated: helpful feedback and merges cannot
+// raise it above the ceiling.
+type ConfidenceLock struct {
+	LoreID     string     `json:"lore_id"`
+	Floor      *float64   `json:"floor,omitempty"`
+	Ceiling    *float64   `json:"ceiling,omitempty"`
+	Pinned     bool       `json:"pinned"`
+	Deprecated bool       `json:"deprecated"`
+	Confidence float64    `json:"confidence"` // entry's confidence after the lock applied
+	SetBy      string     `json:"set_by,omitempty"`
+	SetAt      *time.Time `json:"set_at,omitempty"`
+}
+
+// ConfidenceRecalibration rescales the confidence of live entries, either
+// linearly to a target mean and/or standard deviation or through a mapping
+// table. An omitted target keeps the current mean or spread.
+type ConfidenceRecalibration struct {
+	Category     string   `json:"category,omitempty"` // only recalibrate this category
+	TargetMean   *float64 `json:"target_mean,omitempty"`
+	TargetStdDev *float64 `json:"target_stddev,omitempty"`
+	// Mapping maps old confidence to new by linear interpolation between
+	// its points, which are ordered by From. Values outside the table map
+	// to its first or last To.
+	Mapping []ConfidencePoint `json:"mapping,omitempty"`
+	DryRun  bool              `json:"dry_run,omitempty"`
+}
+
+// ConfidencePoint is a point of a confidence mapping table.
+type ConfidencePoint struct {
+	From float64 `json:"from"`
+	To   float64 `json:"to"`
+}
+
+// ConfidenceDistribution summarizes the confidence of a set of entries.
+type ConfidenceDistribution struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// RecalibrationResult reports a confidence recalibration. Before and After
+// describe the recalibrated entries; After is what the entries hold, or
+// with DryRun would hold, once confidence locks are applied.
+type RecalibrationResult struct {
+	Changed int                    `json:"changed"`
+	Before  ConfidenceDistribution `json:"before"`
+	After   ConfidenceDistribution `json:"after"`
+	DryRun  bool                   `json:"dry_run"`
+}
+
+// ReviewQueueQuery selects unreviewed entries that need human curation.
+// An entry qualifies if its confidence is below MaxConfidence or it has at
+// least MinIncorrect "incorrect" feedback events.
+type ReviewQueueQuery struct {
+	MaxConfidence float64
+	MinIncorrect  int
+	Language      string // optional language filter
+	Limit         int
+	After         *ReviewQueueKey // resume after this candidate; nil starts at the top
+}
+
+// ReviewQueueKey is a candidate's position in review queue order.
+type ReviewQueueKey struct {
+	IncorrectCount int
+	Confidence     float64
+	ID             string
+}
+
+// ReviewCandidate is an entry in the review queue.
+type ReviewCandidate struct {
+	ID              string    `json:"id"`
+	Content         string    `json:"content"`
+	Context         string    `json:"context,omitempty"`
+	Category        string    `json:"category"`
+	Language        string    `json:"language,omitempty"`
+	Confidence      float64   `json:"confidence"`
+	ValidationCount int       `json:"validation_count"`
+	IncorrectCount  int       `json:"incorrect_count"`
+	CreatedAt       time.Time `json:"created_at"`
+	Reasons         []string  `json:"reasons"` // "low_confidence" and/or "flagged_incorrect"
+}
+
+// ReviewQueueResponse lists entries awaiting review.
+type ReviewQueueResponse struct {
+	Entries []ReviewCandidate `json:"entries"`
+	HasMore bool              `json:"has_more"`
+	// NextCursor is passed as cursor to list the next page. Empty on the
+	// last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// LoreUpdate is an edit to a lore entry. Nil fields are left unchanged.
+type LoreUpdate struct {
+	Content  *string `json:"content,omitempty"`
+	Context  *string `json:"context,omitempty"`
+	Category *string `json:"category,omitempty"`
+	SourceID string  `json:"source_id"`
+}
+
+// Recategorization moves every live entry in category From to category To,
+// optionally only those scoped to Repo and Path.
+type Recategorization struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Repo     string `json:"repo,omitempty"`
+	Path     string `json:"path,omitempty"`
+	SourceID string `json:"source_id"` // recorded as the author of each edit
+	DryRun   bool   `json:"dry_run,omitempty"`
+}
+
+// RecategorizeResult reports the entries a recategorization moved, or with
+// DryRun would have moved.
+type RecategorizeResult struct {
+	Moved  int      `json:"moved"`
+	IDs    []string `json:"ids"`
+	DryRun bool     `json:"dry_run"`
+}
+
+// LoreVersion is a prior state of a lore entry, saved when an edit, merge,
+// sync replay or revert replaced it. Versions are numbered from 1 per entry.
+type LoreVersion struct {
+	Version   int       `json:"version"`
+	Content   string    `json:"content"`
+	Context   string    `json:"context,omitempty"`
+	Category  string    `json:"category"`
+	Reason    string    `json:"reason"` // "edit", "merge", "sync" or "revert"
+	SourceID  string    `json:"source_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"` // when this state was replaced
+}
+
+// LoreVersionsResponse lists an entry's prior versions, newest first.
+type LoreVersionsResponse struct {
+	LoreID   string        `json:"lore_id"`
+	Versions []LoreVersion `json:"versions"`
+}
+
+// ConfidenceTransition describes a confidence change for a single entry.
+type ConfidenceTransition struct {
+	LoreID             string  `json:"lore_id"`
+	PreviousConfidence float64 `json:"previous_confidence"`
+	CurrentConfidence  float64 `json:"current_confidence"`
+}
+
+// Consolidation proposal statuses.
+const (
+	ConsolidationProposed = "proposed"
+	ConsolidationApplied  = "applied"
+	ConsolidationFailed   = "failed"
+)
+
+// ConsolidationMember is an entry that would be merged into a
+// consolidation target, with its similarity to the target.
+type ConsolidationMember struct {
+	ID         string  `json:"id"`
+	Content    string  `json:"content"`
+	Similarity float64 `json:"similarity"`
+}
+
+// ConsolidationProposal is a cluster of near-duplicate active entries in
+// one category: the target that survives and the members merged into it.
+type ConsolidationProposal struct {
+	TargetID string                `json:"target_id"`
+	Category string                `json:"category"`
+	Content  string                `json:"content"`
+	Members  []ConsolidationMember `json:"members"`
+	Status   string                `json:"status"`
+	Error    string                `json:"error,omitempty"`
+}
+
+// ConsolidationReport is the outcome of the latest consolidation run on a
+// store. Applied is false when the run only proposed merges.
+type ConsolidationReport struct {
+	GeneratedAt time.Time               `json:"generated_at"`
+	Threshold   float64                 `json:"threshold"`
+	Applied     bool                    `json:"applied"`
+	Proposals   []ConsolidationProposal `json:"proposals"`
+}
+
+// ContradictionQuery selects candidate contradictions.
+type ContradictionQuery struct {
+	MinSimilarity float64
+	Category      string // optional category filter
+	Limit         int
+}
+
+// ContradictionEntry is one side of a likely contradiction.
+type ContradictionEntry struct {
+	ID         string  `json:"id"`
+	Content    string  `json:"content"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Contradiction is a pair of active entries in one category that cover the
+// same topic with opposite polarity, such as "always retry" and "never
+// retry". Reason is the language model's explanation when one judged it.
+type Contradiction struct {
+	Category   string             `json:"category"`
+	Similarity float64            `json:"similarity"`
+	A          ContradictionEntry `json:"a"`
+	B          ContradictionEntry `json:"b"`
+	Reason     string             `json:"reason,omitempty"`
+}
+
+// ContradictionsResponse lists likely contradictions. JudgedBy names the
+// language model that confirmed them, if one was asked.
+type ContradictionsResponse struct {
+	Contradictions []Contradiction `json:"contradictions"`
+	JudgedBy       string          `json:"judged_by,omitempty"`
+}
+
 // StoreMetadata holds store-level metadata.
 type StoreMetadata struct {
 	SchemaVersion  string `json:"schema_version"`
 	EmbeddingModel string `json:"embedding_model"`
 }
 
+// EmbeddingDimensions reports the dimension of a store's embeddings and the
+// embeddings refused since the store opened for having another, as when
+// the embedding model is switched without re-embedding the store.
+type EmbeddingDimensions struct {
+	Expected   int   `json:"expected"`            // zero until the store holds an embedding
+	Mismatches int64 `json:"mismatches"`          // embeddings and queries refused
+	LastSeen   int   `json:"last_seen,omitempty"` // dimension of the latest refused
+}
+
 // StoreStats holds aggregate store statistics.
 type StoreStats struct {
 	LoreCount    int64      `json:"lore_count"`
@@ -208,6 +533,10 @@ type SnapshotStats struct {
 	// High values indicate stale snapshot requiring regeneration.
 	PendingEntries int64 `json:"pending_entries"`
 
+	// BaseSequence is the latest change_log sequence captured in the
+	// snapshot. Clients bootstrapped from it resume deltas after it.
+	BaseSequence int64 `json:"base_sequence"`
+
 	// Available indicates whether a snapshot exists.
 	Available bool `json:"available"`
 }
@@ -225,14 +554,31 @@ type ExtendedStats struct {
 	// Snapshot observability
 	SnapshotStats SnapshotStats `json:"snapshot_stats"`
 
+	// Disk usage of the store's files
+	DiskStats DiskStats `json:"disk_stats"`
+
 	// Knowledge distribution
 	CategoryStats map[string]int64 `json:"category_stats"`
+	LanguageStats map[string]int64 `json:"language_stats"`
+	// CategoryLanguageStats counts entries per category, then per language.
+	CategoryLanguageStats map[string]map[string]int64 `json:"category_language_stats"`
 
 	// Quality metrics
 	QualityStats QualityStats `json:"quality_stats"`
 
 	// Source metrics
 	UniqueSourceCount int64 `json:"unique_source_count"`
+	// SourceStats covers the sources contributing to the most live
+	// entries, most first. Only the top sources are listed; see
+	// UniqueSourceCount for the total.
+	SourceStats []SourceStats `json:"source_stats"`
+
+	// Push idempotency cache
+	IdempotencyStats IdempotencyStats `json:"idempotency_stats"`
+
+	// IndexStats lists every index with how often it was used since the
+	// store was opened. An index never used may not be worth its writes.
+	IndexStats []IndexStats `json:"index_stats"`
 
 	// Timestamps
 	LastSnapshot *time.Time `json:"last_snapshot,omitempty"` // Deprecated: Use SnapshotStats.GeneratedAt
@@ -252,6 +598,74 @@ type EmbeddingStats struct {
 	Failed   int64 `json:"failed"`
 }
 
+// LatencyStats summarizes request latency per endpoint since the server
+// started.
+type LatencyStats struct {
+	Since  time.Time      `json:"since"`
+	Routes []RouteLatency `json:"routes"`
+}
+
+// RouteLatency holds latency percentiles for one method and route template.
+// SLOMs is zero when the route has no latency target.
+type RouteLatency struct {
+	Method      string  `json:"method"`
+	Route       string  `json:"route"`
+	Count       int64   `json:"count"`
+	P50Ms       float64 `json:"p50_ms"`
+	P95Ms       float64 `json:"p95_ms"`
+	P99Ms       float64 `json:"p99_ms"`
+	MaxMs       float64 `json:"max_ms"`
+	SLOMs       float64 `json:"slo_ms,omitempty"`
+	SLOBreaches int64   `json:"slo_breaches"`
+}
+
+// IdempotencyStats tracks the push idempotency cache. Counters cover the
+// lifetime of the current server process.
+type IdempotencyStats struct {
+	Entries   int64   `json:"entries"`
+	Hits      int64   `json:"hits"`
+	Misses    int64   `json:"misses"`
+	HitRate   float64 `json:"hit_rate"`
+	Evictions int64   `json:"evictions"`
+}
+
+// IndexStats reports how often one index was used. Uses counts the
+// statements run since the store was opened whose query plan searched or
+// scanned the index.
+type IndexStats struct {
+	Name  string `json:"name"`
+	Table string `json:"table"`
+	Uses  int64  `json:"uses"`
+}
+
+// DiskStats reports the size of a store's files. In-memory stores report
+// zero.
+type DiskStats struct {
+	DatabaseBytes int64 `json:"database_bytes"`
+	// WALBytes is the size of the write-ahead log, which grows until the
+	// next checkpoint.
+	WALBytes int64 `json:"wal_bytes"`
+	// SnapshotBytes is the size of everything in the snapshot directory,
+	// including partially written snapshots.
+	SnapshotBytes int64 `json:"snapshot_bytes"`
+	TotalBytes    int64 `json:"total_bytes"`
+}
+
+// SourceStats summarizes one source's contributions to a store.
+type SourceStats struct {
+	SourceID string `json:"source_id"`
+	// Entries is the number of live entries the source created.
+	Entries           int64   `json:"entries"`
+	AverageConfidence float64 `json:"average_confidence"` // of the entries it created
+	// Merged is the number of live entries created by other sources that
+	// the source's submissions were merged into.
+	Merged int64 `json:"merged"`
+	// MergeRate is Merged as a fraction of all entries the source
+	// contributed to. A high rate means it mostly repeats known lore.
+	MergeRate float64    `json:"merge_rate"`
+	LastSeen  *time.Time `json:"last_seen,omitempty"` // latest change recorded for the source
+}
+
 // QualityStats tracks lore quality metrics.
 type QualityStats struct {
 	AverageConfidence   float64 `json:"average_confidence"`
@@ -260,15 +674,351 @@ type QualityStats struct {
 	LowConfidenceCount  int64   `json:"low_confidence_count"`  // confidence < 0.3
 }
 
-// MarshalJSON ensures nil map in ExtendedStats marshals as {} not null.
+// Digest summarizes lore activity in a store over [Start, End).
+type Digest struct {
+	Start            time.Time       `json:"start"`
+	End              time.Time       `json:"end"`
+	NewLore          int64           `json:"new_lore"`
+	TopCategories    []CategoryCount `json:"top_categories"`
+	MostValidated    []DigestEntry   `json:"most_validated"`    // most helpful feedback in the window
+	FlaggedIncorrect []DigestEntry   `json:"flagged_incorrect"` // most incorrect feedback in the window
+}
+
+// CategoryCount is the number of entries in a category.
+type CategoryCount struct {
+	Category string `json:"category"`
+	Count    int64  `json:"count"`
+}
+
+// DigestEntry is a lore entry highlighted in a digest, with the number of
+// matching feedback events in the window.
+type DigestEntry struct {
+	ID         string  `json:"id"`
+	Content    string  `json:"content"`
+	Category   string  `json:"category"`
+	Confidence float64 `json:"confidence"`
+	Count      int64   `json:"count"`
+}
+
+// MarshalJSON ensures nil maps and slices in ExtendedStats marshal as {}
+// and [], not null.
 func (e ExtendedStats) MarshalJSON() ([]byte, error) {
 	if e.CategoryStats == nil {
 		e.CategoryStats = map[string]int64{}
 	}
+	if e.SourceStats == nil {
+		e.SourceStats = []SourceStats{}
+	}
 	type Alias ExtendedStats
 	return json.Marshal(Alias(e))
 }
 
+// SearchMode selects the signals used to rank lore search results.
+type SearchMode string
+
+const (
+	SearchModeKeyword  SearchMode = "keyword"  // BM25 full-text relevance
+	SearchModeSemantic SearchMode = "semantic" // embedding cosine similarity
+	SearchModeHybrid   SearchMode = "hybrid"   // fusion of both, per SearchRanking
+)
+
+// SearchFusion selects how hybrid search combines its two signals.
+type SearchFusion string
+
+const (
+	SearchFusionWeighted SearchFusion = "weighted" // weighted average of scores
+	SearchFusionRRF      SearchFusion = "rrf"      // reciprocal rank fusion
+)
+
+// SearchRanking tunes hybrid search.
+//
+// Weighted fusion scores an entry as the weighted average of its keyword and
+// semantic scores. RRF sums weight/(RRFK+rank) over the signals that rank the
+// entry, scaled so an entry ranked first by both scores 1. RRF ignores score
+// magnitudes, which makes it robust when the two scales disagree.
+type SearchRanking struct {
+	Fusion         SearchFusion `json:"fusion"`
+	KeywordWeight  float64      `json:"keyword_weight"`
+	SemanticWeight float64      `json:"semantic_weight"`
+	RRFK           int          `json:"rrf_k"`
+}
+
+// Validate reports whether the ranking can be applied.
+func (r SearchRanking) Validate() error {
+	switch r.Fusion {
+	case SearchFusionWeighted, SearchFusionRRF:
+	default:
+		return fmt.Errorf("fusion %q: must be weighted or rrf", r.Fusion)
+	}
+	if r.KeywordWeight < 0 || r.SemanticWeight < 0 {
+		return errors.New("weights must not be negative")
+	}
+	if r.KeywordWeight+r.SemanticWeight == 0 {
+		return errors.New("at least one weight must be positive")
+	}
+	if r.RRFK < 1 {
+		return errors.New("rrf_k must be at least 1")
+	}
+	return nil
+}
+
+// SearchBoost scales search relevance by entry quality, so well-validated,
+// well-received and recently confirmed lore outranks stale entries with
+// similar relevance.
+//
+// Each feature is in [0, 1]: confidence as stored; validation as
+// n/(n+ValidationPivot) for n validations; feedback as the smoothed share of
+// helpful among helpful and incorrect feedback, (helpful+1)/(helpful+incorrect+2);
+// and recency as 0.5^(age/RecencyHalfLife), where age runs from the last
+// validation, or from creation if never validated. The boost factor is
+// 1 - W + sum(weight*feature), where W is the sum of the weights, so a
+// perfect entry keeps its full relevance and the worst keeps 1 - W of it.
+type SearchBoost struct {
+	ConfidenceWeight float64
+	ValidationWeight float64
+	FeedbackWeight   float64
+	RecencyWeight    float64
+	ValidationPivot  float64
+	RecencyHalfLife  time.Duration
+}
+
+// TotalWeight returns the sum of the feature weights. Zero disables boosting.
+func (b SearchBoost) TotalWeight() float64 {
+	return b.ConfidenceWeight + b.ValidationWeight + b.FeedbackWeight + b.RecencyWeight
+}
+
+// Validate reports whether the boost can be applied.
+func (b SearchBoost) Validate() error {
+	if b.ConfidenceWeight < 0 || b.ValidationWeight < 0 || b.FeedbackWeight < 0 || b.RecencyWeight < 0 {
+		return errors.New("boost weights must not be negative")
+	}
+	if b.TotalWeight() > 1 {
+		return errors.New("boost weights must sum to at most 1")
+	}
+	if b.ValidationWeight > 0 && b.ValidationPivot <= 0 {
+		return errors.New("validation pivot must be positive")
+	}
+	if b.RecencyWeight > 0 && b.RecencyHalfLife <= 0 {
+		return errors.New("recency half-life must be positive")
+	}
+	return nil
+}
+
+// SearchQuery describes a lore search.
+type SearchQuery struct {
+	Text      string
+	Embedding []float32 // query embedding; required for semantic and hybrid
+	Mode      SearchMode
+	Ranking   SearchRanking // hybrid mode only
+	Boost     SearchBoost   // zero value ranks by relevance alone
+	Category  string        // optional category filter
+	Language  string        // optional language filter (ISO 639-1, or "und")
+	Repo      string        // optional: only entries for this repository or none
+	Path      string        // optional: only entries for this path, a parent directory, or none
+	Limit     int
+	Quotas    CategoryQuotas // optional per-category bounds on the results
+}
+
+// CloneFilter selects the lore a cloned store keeps. The zero value keeps
+// every active entry.
+type CloneFilter struct {
+	Categories    []string `json:"categories,omitempty"`     // keep only these categories
+	MinConfidence float64  `json:"min_confidence,omitempty"` // keep entries at or above this confidence
+}
+
+// IsZero reports whether the filter keeps every entry.
+func (f CloneFilter) IsZero() bool {
+	return len(f.Categories) == 0 && f.MinConfidence == 0
+}
+
+// CategoryQuota bounds how many results of one category a search returns.
+// A zero Min or Max leaves that side unbounded.
+type CategoryQuota struct {
+	Min int `json:"min,omitempty"`
+	Max int `json:"max,omitempty"`
+}
+
+// CategoryQuotas maps lore categories to their quotas.
+type CategoryQuotas map[string]CategoryQuota
+
+// Validate reports whether the quotas can be met by limit results: bounds
+// must not be negative, each Min must not exceed its Max, and the Mins
+// together must not exceed limit.
+func (q CategoryQuotas) Validate(limit int) error {
+	total := 0
+	for category, quota := range q {
+		if quota.Min < 0 || quota.Max < 0 {
+			return fmt.Errorf("%s: bounds must not be negative", category)
+		}
+		if quota.Max > 0 && quota.Min > quota.Max {
+			return fmt.Errorf("%s: min %d exceeds max %d", category, quota.Min, quota.Max)
+		}
+		total += quota.Min
+	}
+	if total > limit {
+		return fmt.Errorf("minimums total %d, more than the limit of %d", total, limit)
+	}
+	return nil
+}
+
+// SearchResult is a lore entry ranked by a search, with its score in [0, 1].
+//
+// Score is Relevance multiplied by Boost, the entry's quality factor; Boost
+// is omitted when the search was not boosted. The per-signal fields show how
+// Relevance was reached. A signal's score is
+// present whenever the search used that signal, and is 0 for an entry the
+// signal did not match. A rank is the entry's 1-based position under that
+// signal alone, omitted when the signal did not match it.
+type SearchResult struct {
+	Lore          LoreEntry `json:"lore"`
+	Score         float64   `json:"score"`
+	Relevance     float64   `json:"relevance"`
+	Boost         *float64  `json:"boost,omitempty"`
+	KeywordScore  *float64  `json:"keyword_score,omitempty"`
+	SemanticScore *float64  `json:"semantic_score,omitempty"`
+	KeywordRank   int       `json:"keyword_rank,omitempty"`
+	SemanticRank  int       `json:"semantic_rank,omitempty"`
+}
+
+// SearchResponse is the response body of the search endpoint.
+// Mode is the mode actually used, which is keyword when a hybrid search
+// could not embed the query. Ranking is present for hybrid searches only.
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Mode    SearchMode     `json:"mode"`
+	Ranking *SearchRanking `json:"ranking,omitempty"`
+	Results []SearchResult `json:"results"`
+}
+
+// EvalCase is a labeled search query: the IDs of the lore entries a search
+// for Query should return. Category, Repo and Path filter the search as
+// they do on the search endpoint.
+type EvalCase struct {
+	Query    string   `json:"query"`
+	Relevant []string `json:"relevant"`
+	Category string   `json:"category,omitempty"`
+	Repo     string   `json:"repo,omitempty"`
+	Path     string   `json:"path,omitempty"`
+}
+
+// EvalSet is a named set of labeled queries for measuring search quality.
+type EvalSet struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Cases       []EvalCase `json:"cases"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// StoreMetaEntry is a value a client keeps in a store's metadata, such as
+// configuration shared by a client fleet.
+type StoreMetaEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// EvalSetSummary describes a stored evaluation set without its cases.
+type EvalSetSummary struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Cases       int       `json:"cases"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// EvalCaseResult scores the search for one labeled query. Rank is the
+// 1-based position of the first relevant entry in the top K, or 0 if none
+// was found; Missed lists the relevant entries outside the top K.
+type EvalCaseResult struct {
+	Query          string   `json:"query"`
+	Recall         float64  `json:"recall"`
+	ReciprocalRank float64  `json:"reciprocal_rank"`
+	Rank           int      `json:"rank,omitempty"`
+	Missed         []string `json:"missed,omitempty"`
+}
+
+// EvalReport is the outcome of running an evaluation set against the
+// search configuration described by Mode, Ranking and Boosted. RecallAtK
+// and MRR are means over the cases of their recall within the top K and
+// reciprocal rank.
+type EvalReport struct {
+	Set        string           `json:"set"`
+	Mode       SearchMode       `json:"mode"`
+	Ranking    *SearchRanking   `json:"ranking,omitempty"`
+	Boosted    bool             `json:"boosted"`
+	K          int              `json:"k"`
+	Cases      int              `json:"cases"`
+	RecallAtK  float64          `json:"recall_at_k"`
+	MRR        float64          `json:"mrr"`
+	Results    []EvalCaseResult `json:"results"`
+	DurationMS int64            `json:"duration_ms"`
+}
+
+// SummarizeRequest selects the lore to condense into a digest, either by
+// IDs or by a search filter, but not both. Focus optionally tells the model
+// what the digest is for.
+type SummarizeRequest struct {
+	IDs      []string `json:"ids,omitempty"`
+	Query    string   `json:"query,omitempty"`
+	Category string   `json:"category,omitempty"`
+	Language string   `json:"language,omitempty"`
+	Limit    int      `json:"limit,omitempty"`
+	Focus    string   `json:"focus,omitempty"`
+}
+
+// SummarizeResponse is the response body of the summarize endpoint.
+// EntryIDs lists the entries the summary was generated from, in the order
+// they were given to the model.
+type SummarizeResponse struct {
+	Summary  string   `json:"summary"`
+	Model    string   `json:"model"`
+	EntryIDs []string `json:"entry_ids"`
+}
+
+// Recall bundle formats.
+const (
+	RecallFormatMarkdown = "markdown"
+	RecallFormatJSON     = "json"
+)
+
+// RecallRequest asks for the lore relevant to a task, sized to fit
+// TokenBudget tokens of an agent's context window.
+type RecallRequest struct {
+	Task           string         `json:"task"`
+	TokenBudget    int            `json:"token_budget"`
+	Format         string         `json:"format,omitempty"` // "markdown" (default) or "json"
+	Category       string         `json:"category,omitempty"`
+	Language       string         `json:"language,omitempty"`
+	Repo           string         `json:"repo,omitempty"`
+	Path           string         `json:"path,omitempty"`
+	CategoryQuotas CategoryQuotas `json:"category_quotas,omitempty"`
+}
+
+// RecallEntry is a lore entry in a recall bundle. Truncated is set when
+// its content was cut to fit the budget.
+type RecallEntry struct {
+	ID         string  `json:"id"`
+	Category   string  `json:"category"`
+	Content    string  `json:"content"`
+	Context    string  `json:"context,omitempty"`
+	Confidence float64 `json:"confidence"`
+	Truncated  bool    `json:"truncated,omitempty"`
+}
+
+// RecallResponse is a recall bundle, most relevant entry first. For the
+// markdown format, Content is the rendering of Entries to inject into a
+// prompt. Truncated is set when relevant lore was cut or left out to stay
+// within the budget.
+type RecallResponse struct {
+	Format            string        `json:"format"`
+	Content           string        `json:"content,omitempty"`
+	Entries           []RecallEntry `json:"entries"`
+	EstimatedTokens   int           `json:"estimated_tokens"`
+	TokenBudget       int           `json:"token_budget"`
+	Truncated         bool          `json:"truncated"`
+	DuplicatesDropped int           `json:"duplicates_dropped"`
+}
+
 // SimilarEntry represents a lore entry with its similarity score.
 type SimilarEntry struct {
 	LoreEntry
@@ -292,6 +1042,12 @@ func (r IngestResult) MarshalJSON() ([]byte, error) {
 	if r.Errors == nil {
 		r.Errors = []string{}
 	}
+	if r.CreatedIDs == nil {
+		r.CreatedIDs = []string{}
+	}
+	if r.Results == nil {
+		r.Results = []IngestEntryResult{}
+	}
 	type Alias IngestResult
 	return json.Marshal(Alias(r))
 }
@@ -398,3 +398,76 @@ func TestStoreStats_Fields(t *testing.T) {
 		t.Errorf("Expected last_snapshot key, got: %s", raw)
 	}
 }
+
+func TestSearchRanking_Validate(t *testing.T) {
+	valid := SearchRanking{Fusion: SearchFusionRRF, KeywordWeight: 1, SemanticWeight: 0, RRFK: 60}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*SearchRanking)
+	}{
+		{"unknown fusion", func(r *SearchRanking) { r.Fusion = "max" }},
+		{"negative weight", func(r *SearchRanking) { r.SemanticWeight = -0.1 }},
+		{"zero weights", func(r *SearchRanking) { r.KeywordWeight = 0 }},
+		{"zero rrf_k", func(r *SearchRanking) { r.RRFK = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid
+			tt.mutate(&r)
+			if err := r.Validate(); err == nil {
+				t.Errorf("Validate(%+v) = nil, want error", r)
+			}
+		})
+	}
+}
+
+func TestSearchBoost_Validate(t *testing.T) {
+	if err := (SearchBoost{}).Validate(); err != nil {
+		t.Errorf("zero boost: Validate() = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name  string
+		boost SearchBoost
+	}{
+		{"negative weight", SearchBoost{ConfidenceWeight: -0.1}},
+		{"weights over 1", SearchBoost{ConfidenceWeight: 0.6, FeedbackWeight: 0.6}},
+		{"validation without pivot", SearchBoost{ValidationWeight: 0.1}},
+		{"recency without half-life", SearchBoost{RecencyWeight: 0.1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.boost.Validate(); err == nil {
+				t.Errorf("Validate(%+v) = nil, want error", tt.boost)
+			}
+		})
+	}
+}
+
+func TestCategoryQuotas_Validate(t *testing.T) {
+	valid := CategoryQuotas{"PATTERN_OUTCOME": {Max: 3}, "ARCHITECTURAL_DECISION": {Min: 1}}
+	if err := valid.Validate(10); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name   string
+		quotas CategoryQuotas
+		limit  int
+	}{
+		{"negative bound", CategoryQuotas{"PATTERN_OUTCOME": {Max: -1}}, 10},
+		{"min above max", CategoryQuotas{"PATTERN_OUTCOME": {Min: 3, Max: 2}}, 10},
+		{"mins above limit", CategoryQuotas{"PATTERN_OUTCOME": {Min: 3}, "TESTING_STRATEGY": {Min: 3}}, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.quotas.Validate(tt.limit); err == nil {
+				t.Errorf("Validate(%d) = nil, want error", tt.limit)
+			}
+		})
+	}
+}
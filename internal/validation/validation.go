@@ -3,6 +3,7 @@ package validation
 import (
 	"fmt"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/hyperengineering/engram/internal/types"
@@ -13,6 +14,43 @@ const (
 	MaxContentLength = 4000
 	MaxContextLength = 1000
 	MaxBatchSize     = 50
+
+	// MaxBulkFeedbackSize caps the lore IDs in one bulk feedback request,
+	// which rates a whole recalled set at once.
+	MaxBulkFeedbackSize   = 500
+	MaxFeedbackNoteLength = 1000
+
+	MaxReviewerLength   = 200
+	MaxReviewNoteLength = 1000
+
+	// MaxSummaryEntries caps the entries condensed by one summarize
+	// request, keeping the prompt within a model's context window.
+	MaxSummaryEntries = 50
+	MaxFocusLength    = 500
+
+	// MaxRecallTokenBudget caps the token budget of a recall bundle.
+	MaxRecallTokenBudget = 200000
+
+	// RecallCandidates is how many search results a recall request ranks
+	// before deduplicating them and fitting them to its token budget.
+	RecallCandidates = 50
+
+	// MaxRepoLength and MaxPathLength cap the scope an entry is recorded
+	// against: a repository identifier and a path within it.
+	MaxRepoLength = 200
+	MaxPathLength = 1024
+
+	// MaxEvalCases and MaxEvalRelevant cap the labeled queries in a search
+	// evaluation set and the relevant entries labeled for each.
+	MaxEvalCases             = 1000
+	MaxEvalRelevant          = 100
+	MaxEvalSetNameLength     = 64
+	MaxEvalDescriptionLength = 1000
+
+	// MaxMetaKeyLength and MaxMetaValueBytes cap the keys and values of
+	// store metadata, which holds small coordination values, not data.
+	MaxMetaKeyLength  = 128
+	MaxMetaValueBytes = 16 << 10
 )
 
 // ValidLoreCategories defines the allowed category values from types.go.
@@ -113,6 +151,53 @@ func ValidateULID(field, value string) *ValidationError {
 	return nil
 }
 
+// ValidateLanguage returns an error if the value is not a lowercase ISO 639
+// language code of two or three letters, such as "en" or "und".
+func ValidateLanguage(field, value string) *ValidationError {
+	if len(value) < 2 || len(value) > 3 || strings.ContainsFunc(value, func(r rune) bool { return r < 'a' || r > 'z' }) {
+		return &ValidationError{
+			Field:   field,
+			Message: "must be a lowercase ISO 639 language code (e.g. en, und)",
+		}
+	}
+	return nil
+}
+
+// ValidateRepo returns an error if the value is not a repository
+// identifier, such as "github.com/acme/payments": valid UTF-8 of at most
+// MaxRepoLength characters with no whitespace or control characters.
+func ValidateRepo(field, value string) *ValidationError {
+	if !utf8.ValidString(value) || utf8.RuneCountInString(value) > MaxRepoLength ||
+		strings.ContainsFunc(value, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) {
+		return &ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("must be a repository identifier of at most %d characters without whitespace", MaxRepoLength),
+		}
+	}
+	return nil
+}
+
+// ValidateScopePath returns an error if the value is not a relative,
+// slash-separated path such as "internal/api" or "cmd/main.go": at most
+// MaxPathLength characters, with no empty, "." or ".." segments, no
+// leading or trailing slash, and no backslashes or control characters.
+func ValidateScopePath(field, value string) *ValidationError {
+	invalid := !utf8.ValidString(value) || utf8.RuneCountInString(value) > MaxPathLength ||
+		strings.ContainsFunc(value, func(r rune) bool { return r == '\\' || unicode.IsControl(r) })
+	for _, segment := range strings.Split(value, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			invalid = true
+		}
+	}
+	if invalid {
+		return &ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("must be a relative slash-separated path of at most %d characters without . or .. segments", MaxPathLength),
+		}
+	}
+	return nil
+}
+
 // ValidateRequired returns an error if the value is empty or whitespace-only.
 func ValidateRequired(field, value string) *ValidationError {
 	if strings.TrimSpace(value) == "" {
@@ -153,6 +238,11 @@ func ValidateLoreEntry(index int, entry types.Lore) []ValidationError {
 	c := &Collector{}
 	fieldPrefix := fmt.Sprintf("lore[%d]", index)
 
+	// ID: optional client-generated ULID
+	if entry.ID != "" {
+		c.Add(ValidateULID(fieldPrefix+".id", entry.ID))
+	}
+
 	// Content: required, max 4000 chars, UTF-8, no null bytes
 	c.Add(ValidateRequired(fieldPrefix+".content", entry.Content))
 	c.Add(ValidateMaxLength(fieldPrefix+".content", entry.Content, MaxContentLength))
@@ -173,6 +263,17 @@ func ValidateLoreEntry(index int, entry types.Lore) []ValidationError {
 	// Confidence: required, range 0.0-1.0
 	c.Add(ValidateRange(fieldPrefix+".confidence", entry.Confidence, 0.0, 1.0))
 
+	// Language, repo, path: optional scope metadata
+	if entry.Language != "" {
+		c.Add(ValidateLanguage(fieldPrefix+".language", entry.Language))
+	}
+	if entry.Repo != "" {
+		c.Add(ValidateRepo(fieldPrefix+".repo", entry.Repo))
+	}
+	if entry.Path != "" {
+		c.Add(ValidateScopePath(fieldPrefix+".path", entry.Path))
+	}
+
 	return c.Errors()
 }
 
@@ -224,3 +325,347 @@ func ValidateFeedbackEntry(index int, loreID, feedbackType string) []ValidationE
 
 	return c.Errors()
 }
+
+// ValidateBulkFeedbackRequest validates a bulk feedback request, which applies
+// one feedback type and optional note to every listed lore entry.
+func ValidateBulkFeedbackRequest(sourceID string, loreIDs []string, feedbackType, note string) []ValidationError {
+	c := &Collector{}
+	c.Add(ValidateRequired("source_id", sourceID))
+
+	if len(loreIDs) == 0 {
+		c.Add(&ValidationError{Field: "lore_ids", Message: "is required and must not be empty"})
+	} else if len(loreIDs) > MaxBulkFeedbackSize {
+		c.Add(&ValidationError{Field: "lore_ids", Message: fmt.Sprintf("exceeds maximum batch size of %d", MaxBulkFeedbackSize)})
+	}
+	seen := make(map[string]bool, len(loreIDs))
+	for i, id := range loreIDs {
+		field := fmt.Sprintf("lore_ids[%d]", i)
+		if err := ValidateULID(field, id); err != nil {
+			c.Add(err)
+			continue
+		}
+		if seen[id] {
+			c.Add(&ValidationError{Field: field, Message: "duplicates an earlier lore ID"})
+		}
+		seen[id] = true
+	}
+
+	if err := ValidateRequired("type", feedbackType); err != nil {
+		c.Add(err)
+	} else {
+		c.Add(ValidateEnum("type", feedbackType, ValidFeedbackTypes))
+	}
+
+	c.errors = append(c.errors, ValidateFeedbackNote("note", note)...)
+
+	return c.Errors()
+}
+
+// ValidateFeedbackNote validates an optional free-text feedback note.
+func ValidateFeedbackNote(field, note string) []ValidationError {
+	c := &Collector{}
+	c.Add(ValidateMaxLength(field, note, MaxFeedbackNoteLength))
+	c.Add(ValidateUTF8(field, note))
+	c.Add(ValidateNoNullBytes(field, note))
+	return c.Errors()
+}
+
+// ValidateReviewRequest validates a reviewer's approve or reject decision.
+func ValidateReviewRequest(reviewer, note string) []ValidationError {
+	c := &Collector{}
+	if err := ValidateRequired("reviewer", reviewer); err != nil {
+		c.Add(err)
+	} else {
+		c.Add(ValidateMaxLength("reviewer", reviewer, MaxReviewerLength))
+		c.Add(ValidateUTF8("reviewer", reviewer))
+		c.Add(ValidateNoNullBytes("reviewer", reviewer))
+	}
+	c.Add(ValidateMaxLength("note", note, MaxReviewNoteLength))
+	c.Add(ValidateUTF8("note", note))
+	c.Add(ValidateNoNullBytes("note", note))
+	return c.Errors()
+}
+
+// ValidateConfidenceLock validates a request to set an entry's confidence
+// floor and/or ceiling.
+func ValidateConfidenceLock(floor, ceiling *float64, setBy string) []ValidationError {
+	c := &Collector{}
+	if err := ValidateRequired("set_by", setBy); err != nil {
+		c.Add(err)
+	} else {
+		c.Add(ValidateMaxLength("set_by", setBy, MaxReviewerLength))
+		c.Add(ValidateUTF8("set_by", setBy))
+		c.Add(ValidateNoNullBytes("set_by", setBy))
+	}
+
+	if floor == nil && ceiling == nil {
+		c.Add(&ValidationError{Field: "floor", Message: "floor or ceiling is required"})
+	}
+	if floor != nil {
+		c.Add(ValidateRange("floor", *floor, 0.0, 1.0))
+	}
+	if ceiling != nil {
+		c.Add(ValidateRange("ceiling", *ceiling, 0.0, 1.0))
+	}
+	if floor != nil && ceiling != nil && *floor > *ceiling {
+		c.Add(&ValidationError{Field: "ceiling", Message: "must not be below floor"})
+	}
+	return c.Errors()
+}
+
+// ValidateLoreUpdate validates an edit to a lore entry. At least one of
+// content, context or category must be set.
+func ValidateLoreUpdate(update types.LoreUpdate) []ValidationError {
+	c := &Collector{}
+	c.Add(ValidateRequired("source_id", update.SourceID))
+
+	if update.Content == nil && update.Context == nil && update.Category == nil {
+		c.Add(&ValidationError{Field: "content", Message: "content, context or category is required"})
+	}
+	if update.Content != nil {
+		c.Add(ValidateRequired("content", *update.Content))
+		c.Add(ValidateMaxLength("content", *update.Content, MaxContentLength))
+		c.Add(ValidateUTF8("content", *update.Content))
+		c.Add(ValidateNoNullBytes("content", *update.Content))
+	}
+	if update.Context != nil {
+		c.Add(ValidateMaxLength("context", *update.Context, MaxContextLength))
+		c.Add(ValidateUTF8("context", *update.Context))
+		c.Add(ValidateNoNullBytes("context", *update.Context))
+	}
+	if update.Category != nil {
+		c.Add(ValidateEnum("category", *update.Category, ValidLoreCategories))
+	}
+	return c.Errors()
+}
+
+// ValidateRecategorization validates a bulk move of lore between
+// categories. The categories must differ.
+func ValidateRecategorization(rc types.Recategorization) []ValidationError {
+	c := &Collector{}
+	c.Add(ValidateRequired("source_id", rc.SourceID))
+	c.Add(ValidateEnum("from", rc.From, ValidLoreCategories))
+	c.Add(ValidateEnum("to", rc.To, ValidLoreCategories))
+	if rc.From != "" && rc.From == rc.To {
+		c.Add(&ValidationError{Field: "to", Message: "must differ from from"})
+	}
+	if rc.Repo != "" {
+		c.Add(ValidateRepo("repo", rc.Repo))
+	}
+	if rc.Path != "" {
+		c.Add(ValidateScopePath("path", rc.Path))
+	}
+	return c.Errors()
+}
+
+// ValidateConfidenceRecalibration validates a confidence recalibration.
+// It takes either targets or a mapping table, whose points must be ordered
+// by strictly increasing from.
+func ValidateConfidenceRecalibration(rc types.ConfidenceRecalibration) []ValidationError {
+	c := &Collector{}
+	if rc.Category != "" {
+		c.Add(ValidateEnum("category", rc.Category, ValidLoreCategories))
+	}
+	linear := rc.TargetMean != nil || rc.TargetStdDev != nil
+	switch {
+	case linear && len(rc.Mapping) > 0:
+		c.Add(&ValidationError{Field: "mapping", Message: "cannot be combined with target_mean or target_stddev"})
+	case !linear && len(rc.Mapping) == 0:
+		c.Add(&ValidationError{Field: "target_mean", Message: "target_mean, target_stddev or mapping is required"})
+	}
+	if rc.TargetMean != nil {
+		c.Add(ValidateRange("target_mean", *rc.TargetMean, 0, 1))
+	}
+	if rc.TargetStdDev != nil {
+		c.Add(ValidateRange("target_stddev", *rc.TargetStdDev, 0, 1))
+	}
+	for i, p := range rc.Mapping {
+		c.Add(ValidateRange(fmt.Sprintf("mapping[%d].from", i), p.From, 0, 1))
+		c.Add(ValidateRange(fmt.Sprintf("mapping[%d].to", i), p.To, 0, 1))
+		if i > 0 && p.From <= rc.Mapping[i-1].From {
+			c.Add(&ValidationError{Field: fmt.Sprintf("mapping[%d].from", i), Message: "must be greater than the previous point's from"})
+		}
+	}
+	return c.Errors()
+}
+
+// ValidateSummarizeRequest validates a request to summarize lore. Exactly
+// one of ids and query must be set; the filter fields apply to query only.
+func ValidateSummarizeRequest(req types.SummarizeRequest) []ValidationError {
+	c := &Collector{}
+	switch {
+	case len(req.IDs) == 0 && req.Query == "":
+		c.Add(&ValidationError{Field: "ids", Message: "ids or query is required"})
+	case len(req.IDs) > 0 && req.Query != "":
+		c.Add(&ValidationError{Field: "query", Message: "must not be combined with ids"})
+	}
+
+	if len(req.IDs) > MaxSummaryEntries {
+		c.Add(&ValidationError{Field: "ids", Message: fmt.Sprintf("exceeds maximum of %d entries", MaxSummaryEntries)})
+	}
+	seen := make(map[string]bool, len(req.IDs))
+	for i, id := range req.IDs {
+		field := fmt.Sprintf("ids[%d]", i)
+		if err := ValidateULID(field, id); err != nil {
+			c.Add(err)
+			continue
+		}
+		if seen[id] {
+			c.Add(&ValidationError{Field: field, Message: "duplicates an earlier lore ID"})
+		}
+		seen[id] = true
+	}
+
+	if req.Query != "" {
+		c.Add(ValidateMaxLength("query", req.Query, MaxContentLength))
+		c.Add(ValidateUTF8("query", req.Query))
+		c.Add(ValidateNoNullBytes("query", req.Query))
+	}
+	if req.Category != "" {
+		c.Add(ValidateEnum("category", req.Category, ValidLoreCategories))
+	}
+	if req.Language != "" {
+		c.Add(ValidateLanguage("language", req.Language))
+	}
+	if req.Limit < 0 || req.Limit > MaxSummaryEntries {
+		c.Add(&ValidationError{Field: "limit", Message: fmt.Sprintf("must be between 1 and %d", MaxSummaryEntries)})
+	}
+
+	c.Add(ValidateMaxLength("focus", req.Focus, MaxFocusLength))
+	c.Add(ValidateUTF8("focus", req.Focus))
+	c.Add(ValidateNoNullBytes("focus", req.Focus))
+	return c.Errors()
+}
+
+// ValidateRecallRequest validates a request for a recall bundle.
+func ValidateRecallRequest(req types.RecallRequest) []ValidationError {
+	c := &Collector{}
+	c.Add(ValidateRequired("task", req.Task))
+	c.Add(ValidateMaxLength("task", req.Task, MaxContentLength))
+	c.Add(ValidateUTF8("task", req.Task))
+	c.Add(ValidateNoNullBytes("task", req.Task))
+	if req.TokenBudget < 1 || req.TokenBudget > MaxRecallTokenBudget {
+		c.Add(&ValidationError{Field: "token_budget", Message: fmt.Sprintf("must be between 1 and %d", MaxRecallTokenBudget)})
+	}
+	if req.Format != "" {
+		c.Add(ValidateEnum("format", req.Format, []string{types.RecallFormatMarkdown, types.RecallFormatJSON}))
+	}
+	if req.Category != "" {
+		c.Add(ValidateEnum("category", req.Category, ValidLoreCategories))
+	}
+	if req.Language != "" {
+		c.Add(ValidateLanguage("language", req.Language))
+	}
+	if req.Repo != "" {
+		c.Add(ValidateRepo("repo", req.Repo))
+	}
+	if req.Path != "" {
+		c.Add(ValidateScopePath("path", req.Path))
+	}
+	c.errors = append(c.errors, ValidateCategoryQuotas("category_quotas", req.CategoryQuotas, RecallCandidates)...)
+	return c.Errors()
+}
+
+// ValidateCategoryQuotas validates per-category result quotas for a search
+// returning at most limit results.
+func ValidateCategoryQuotas(field string, quotas types.CategoryQuotas, limit int) []ValidationError {
+	c := &Collector{}
+	for category := range quotas {
+		c.Add(ValidateEnum(field+"."+category, category, ValidLoreCategories))
+	}
+	if err := quotas.Validate(limit); err != nil {
+		c.Add(&ValidationError{Field: field, Message: err.Error()})
+	}
+	return c.Errors()
+}
+
+// ValidateCloneFilter validates the lore filter of a store clone.
+func ValidateCloneFilter(filter types.CloneFilter) []ValidationError {
+	c := &Collector{}
+	for i, category := range filter.Categories {
+		c.Add(ValidateEnum(fmt.Sprintf("categories[%d]", i), category, ValidLoreCategories))
+	}
+	c.Add(ValidateRange("min_confidence", filter.MinConfidence, 0, 1))
+	return c.Errors()
+}
+
+// ValidateEvalSet validates a search evaluation set. Names are lowercase
+// letters, digits, hyphens and underscores, and every case needs a query
+// and at least one relevant lore ID.
+func ValidateEvalSet(set types.EvalSet) []ValidationError {
+	c := &Collector{}
+	c.Add(ValidateRequired("name", set.Name))
+	c.Add(ValidateMaxLength("name", set.Name, MaxEvalSetNameLength))
+	if strings.IndexFunc(set.Name, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_'
+	}) >= 0 {
+		c.Add(&ValidationError{Field: "name", Message: "must contain only lowercase letters, digits, hyphens and underscores"})
+	}
+	c.Add(ValidateMaxLength("description", set.Description, MaxEvalDescriptionLength))
+	c.Add(ValidateUTF8("description", set.Description))
+	c.Add(ValidateNoNullBytes("description", set.Description))
+
+	switch {
+	case len(set.Cases) == 0:
+		c.Add(&ValidationError{Field: "cases", Message: "at least one case is required"})
+	case len(set.Cases) > MaxEvalCases:
+		c.Add(&ValidationError{Field: "cases", Message: fmt.Sprintf("exceeds maximum of %d cases", MaxEvalCases)})
+	}
+	for i, ec := range set.Cases {
+		field := fmt.Sprintf("cases[%d]", i)
+		c.Add(ValidateRequired(field+".query", ec.Query))
+		c.Add(ValidateMaxLength(field+".query", ec.Query, MaxContentLength))
+		c.Add(ValidateUTF8(field+".query", ec.Query))
+		c.Add(ValidateNoNullBytes(field+".query", ec.Query))
+		switch {
+		case len(ec.Relevant) == 0:
+			c.Add(&ValidationError{Field: field + ".relevant", Message: "at least one lore ID is required"})
+		case len(ec.Relevant) > MaxEvalRelevant:
+			c.Add(&ValidationError{Field: field + ".relevant", Message: fmt.Sprintf("exceeds maximum of %d entries", MaxEvalRelevant)})
+		}
+		for j, id := range ec.Relevant {
+			c.Add(ValidateULID(fmt.Sprintf("%s.relevant[%d]", field, j), id))
+		}
+		if ec.Category != "" {
+			c.Add(ValidateEnum(field+".category", ec.Category, ValidLoreCategories))
+		}
+		if ec.Repo != "" {
+			c.Add(ValidateRepo(field+".repo", ec.Repo))
+		}
+		if ec.Path != "" {
+			c.Add(ValidateScopePath(field+".path", ec.Path))
+		}
+	}
+	return c.Errors()
+}
+
+// ValidateMetaKey validates a store metadata key: lowercase letters,
+// digits, dots, hyphens and underscores, starting with a letter or digit.
+func ValidateMetaKey(field, key string) *ValidationError {
+	if err := ValidateRequired(field, key); err != nil {
+		return err
+	}
+	if err := ValidateMaxLength(field, key, MaxMetaKeyLength); err != nil {
+		return err
+	}
+	valid := func(r rune) bool {
+		return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+	}
+	if !valid(rune(key[0])) || strings.IndexFunc(key, func(r rune) bool {
+		return !valid(r) && r != '.' && r != '-' && r != '_'
+	}) >= 0 {
+		return &ValidationError{Field: field, Message: "must contain only lowercase letters, digits, dots, hyphens and underscores, starting with a letter or digit"}
+	}
+	return nil
+}
+
+// ValidateMetaValue validates a store metadata value, which may be empty.
+func ValidateMetaValue(field, value string) []ValidationError {
+	c := &Collector{}
+	if len(value) > MaxMetaValueBytes {
+		c.Add(&ValidationError{Field: field, Message: fmt.Sprintf("exceeds maximum of %d bytes", MaxMetaValueBytes)})
+	}
+	c.Add(ValidateUTF8(field, value))
+	c.Add(ValidateNoNullBytes(field, value))
+	return c.Errors()
+}
@@ -217,6 +217,45 @@ func TestValidateRequired_WhitespaceOnly(t *testing.T) {
 
 // --- ValidateEnum Tests ---
 
+func TestValidateLanguage(t *testing.T) {
+	for _, v := range []string{"en", "de", "und"} {
+		if err := ValidateLanguage("language", v); err != nil {
+			t.Errorf("ValidateLanguage(%q) = %v, want nil", v, err)
+		}
+	}
+	for _, v := range []string{"", "e", "EN", "en-US", "engl", "e1"} {
+		if err := ValidateLanguage("language", v); err == nil {
+			t.Errorf("ValidateLanguage(%q) = nil, want error", v)
+		}
+	}
+}
+
+func TestValidateRepo(t *testing.T) {
+	for _, v := range []string{"payments", "acme/payments", "github.com/acme/payments", "git@github.com:acme/payments.git"} {
+		if err := ValidateRepo("repo", v); err != nil {
+			t.Errorf("ValidateRepo(%q) = %v, want nil", v, err)
+		}
+	}
+	for _, v := range []string{"acme payments", "acme\tpayments", "acme\x00", strings.Repeat("a", MaxRepoLength+1)} {
+		if err := ValidateRepo("repo", v); err == nil {
+			t.Errorf("ValidateRepo(%q) = nil, want error", v)
+		}
+	}
+}
+
+func TestValidateScopePath(t *testing.T) {
+	for _, v := range []string{"internal", "internal/api", "cmd/engram/main.go", "docs/read me.md"} {
+		if err := ValidateScopePath("path", v); err != nil {
+			t.Errorf("ValidateScopePath(%q) = %v, want nil", v, err)
+		}
+	}
+	for _, v := range []string{"", "/internal", "internal/", "internal//api", "./internal", "internal/../cmd", "internal\\api", strings.Repeat("a", MaxPathLength+1)} {
+		if err := ValidateScopePath("path", v); err == nil {
+			t.Errorf("ValidateScopePath(%q) = nil, want error", v)
+		}
+	}
+}
+
 func TestValidateEnum_Valid(t *testing.T) {
 	allowed := []string{
 		"DEPENDENCY_BEHAVIOR",
@@ -505,6 +544,24 @@ func TestValidateLoreEntry_ContextOptional(t *testing.T) {
 	}
 }
 
+func TestValidateLoreEntry_ClientID(t *testing.T) {
+	entry := types.Lore{
+		ID:         "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+		Content:    "valid content",
+		Category:   types.CategoryDependencyBehavior,
+		Confidence: 0.5,
+	}
+	if errs := ValidateLoreEntry(0, entry); len(errs) != 0 {
+		t.Errorf("ValidateLoreEntry(valid id) = %v, want no errors", errs)
+	}
+
+	entry.ID = "not-a-ulid"
+	errs := ValidateLoreEntry(0, entry)
+	if len(errs) != 1 || errs[0].Field != "lore[0].id" {
+		t.Errorf("ValidateLoreEntry(invalid id) = %v, want one lore[0].id error", errs)
+	}
+}
+
 func TestValidateLoreEntry_InvalidCategory(t *testing.T) {
 	entry := types.Lore{
 		Content:    "valid content",
@@ -872,3 +929,333 @@ func TestValidateFeedbackEntry_IndexInFieldName(t *testing.T) {
 		t.Errorf("ValidateFeedbackEntry(index 5) should use feedback[5] prefix, got: %v", errs)
 	}
 }
+
+func TestValidateBulkFeedbackRequest_Valid(t *testing.T) {
+	ids := []string{"01ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAW"}
+	if errs := ValidateBulkFeedbackRequest("src", ids, "helpful", "all useful"); len(errs) != 0 {
+		t.Errorf("ValidateBulkFeedbackRequest(valid) = %v, want no errors", errs)
+	}
+}
+
+func TestValidateBulkFeedbackRequest_Invalid(t *testing.T) {
+	valid := "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	tooMany := make([]string, MaxBulkFeedbackSize+1)
+	for i := range tooMany {
+		tooMany[i] = valid
+	}
+
+	tests := []struct {
+		name      string
+		sourceID  string
+		ids       []string
+		typ, note string
+		field     string
+	}{
+		{"missing source", "", []string{valid}, "helpful", "", "source_id"},
+		{"no ids", "src", nil, "helpful", "", "lore_ids"},
+		{"too many ids", "src", tooMany, "helpful", "", "lore_ids"},
+		{"bad id", "src", []string{valid, "nope"}, "helpful", "", "lore_ids[1]"},
+		{"duplicate id", "src", []string{valid, valid}, "helpful", "", "lore_ids[1]"},
+		{"missing type", "src", []string{valid}, "", "", "type"},
+		{"bad type", "src", []string{valid}, "great", "", "type"},
+		{"long note", "src", []string{valid}, "helpful", strings.Repeat("x", MaxFeedbackNoteLength+1), "note"},
+		{"null byte note", "src", []string{valid}, "helpful", "a\x00b", "note"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateBulkFeedbackRequest(tt.sourceID, tt.ids, tt.typ, tt.note)
+			found := false
+			for _, e := range errs {
+				if e.Field == tt.field {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected error on %s, got %v", tt.field, errs)
+			}
+		})
+	}
+}
+
+func TestValidateFeedbackNote(t *testing.T) {
+	if errs := ValidateFeedbackNote("feedback[0].note", ""); len(errs) != 0 {
+		t.Errorf("empty note should be valid, got %v", errs)
+	}
+	if errs := ValidateFeedbackNote("feedback[0].note", "cites the v1 API, which was removed"); len(errs) != 0 {
+		t.Errorf("plain note should be valid, got %v", errs)
+	}
+	for _, note := range []string{strings.Repeat("x", MaxFeedbackNoteLength+1), "a\x00b", string([]byte{0xff})} {
+		errs := ValidateFeedbackNote("feedback[0].note", note)
+		if len(errs) == 0 || errs[0].Field != "feedback[0].note" {
+			t.Errorf("ValidateFeedbackNote(%q) = %v, want error on feedback[0].note", note, errs)
+		}
+	}
+}
+
+func TestValidateReviewRequest(t *testing.T) {
+	if errs := ValidateReviewRequest("alice@example.com", "verified against the v2 docs"); len(errs) != 0 {
+		t.Errorf("valid review = %v, want no errors", errs)
+	}
+
+	tests := []struct {
+		name, reviewer, note, field string
+	}{
+		{"missing reviewer", "", "", "reviewer"},
+		{"blank reviewer", "   ", "", "reviewer"},
+		{"long reviewer", strings.Repeat("r", MaxReviewerLength+1), "", "reviewer"},
+		{"null byte reviewer", "a\x00b", "", "reviewer"},
+		{"long note", "alice", strings.Repeat("x", MaxReviewNoteLength+1), "note"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateReviewRequest(tt.reviewer, tt.note)
+			if len(errs) == 0 || errs[0].Field != tt.field {
+				t.Errorf("ValidateReviewRequest() = %v, want error on %s", errs, tt.field)
+			}
+		})
+	}
+}
+
+func TestValidateConfidenceLock(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	for _, tt := range []struct{ floor, ceiling *float64 }{
+		{f(0.6), nil}, {nil, f(0.2)}, {f(0.3), f(0.3)},
+	} {
+		if errs := ValidateConfidenceLock(tt.floor, tt.ceiling, "alice"); len(errs) != 0 {
+			t.Errorf("ValidateConfidenceLock(%v, %v) = %v, want no errors", tt.floor, tt.ceiling, errs)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		floor, ceiling *float64
+		setBy, field   string
+	}{
+		{"missing set_by", f(0.5), nil, "", "set_by"},
+		{"no bounds", nil, nil, "alice", "floor"},
+		{"floor above 1", f(1.5), nil, "alice", "floor"},
+		{"negative ceiling", nil, f(-0.1), "alice", "ceiling"},
+		{"ceiling below floor", f(0.7), f(0.4), "alice", "ceiling"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateConfidenceLock(tt.floor, tt.ceiling, tt.setBy)
+			if len(errs) == 0 || errs[0].Field != tt.field {
+				t.Errorf("ValidateConfidenceLock() = %v, want error on %s", errs, tt.field)
+			}
+		})
+	}
+}
+
+func TestValidateLoreUpdate(t *testing.T) {
+	s := func(v string) *string { return &v }
+
+	valid := types.LoreUpdate{Content: s("Updated content"), Context: s(""), Category: s("PATTERN_OUTCOME"), SourceID: "alice"}
+	if errs := ValidateLoreUpdate(valid); len(errs) != 0 {
+		t.Errorf("ValidateLoreUpdate() = %v, want no errors", errs)
+	}
+
+	tests := []struct {
+		name   string
+		update types.LoreUpdate
+		field  string
+	}{
+		{"missing source_id", types.LoreUpdate{Content: s("x")}, "source_id"},
+		{"no fields", types.LoreUpdate{SourceID: "alice"}, "content"},
+		{"empty content", types.LoreUpdate{Content: s(""), SourceID: "alice"}, "content"},
+		{"long context", types.LoreUpdate{Context: s(strings.Repeat("a", MaxContextLength+1)), SourceID: "alice"}, "context"},
+		{"bad category", types.LoreUpdate{Category: s("NOPE"), SourceID: "alice"}, "category"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateLoreUpdate(tt.update)
+			if len(errs) == 0 || errs[0].Field != tt.field {
+				t.Errorf("ValidateLoreUpdate() = %v, want error on %s", errs, tt.field)
+			}
+		})
+	}
+}
+
+func TestValidateRecategorization(t *testing.T) {
+	valid := types.Recategorization{From: "PATTERN_OUTCOME", To: "TESTING_STRATEGY", Repo: "payments", SourceID: "alice"}
+	if errs := ValidateRecategorization(valid); len(errs) != 0 {
+		t.Errorf("ValidateRecategorization() = %v, want no errors", errs)
+	}
+
+	tests := []struct {
+		name  string
+		rc    types.Recategorization
+		field string
+	}{
+		{"missing source_id", types.Recategorization{From: "PATTERN_OUTCOME", To: "TESTING_STRATEGY"}, "source_id"},
+		{"bad from", types.Recategorization{From: "NOPE", To: "TESTING_STRATEGY", SourceID: "alice"}, "from"},
+		{"missing to", types.Recategorization{From: "PATTERN_OUTCOME", SourceID: "alice"}, "to"},
+		{"same category", types.Recategorization{From: "PATTERN_OUTCOME", To: "PATTERN_OUTCOME", SourceID: "alice"}, "to"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateRecategorization(tt.rc)
+			if len(errs) == 0 || errs[0].Field != tt.field {
+				t.Errorf("ValidateRecategorization() = %v, want error on %s", errs, tt.field)
+			}
+		})
+	}
+}
+
+func TestValidateConfidenceRecalibration(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	for _, rc := range []types.ConfidenceRecalibration{
+		{TargetMean: f(0.5), TargetStdDev: f(0.2)},
+		{TargetStdDev: f(0.2), Category: "PATTERN_OUTCOME"},
+		{Mapping: []types.ConfidencePoint{{From: 0, To: 0.1}, {From: 1, To: 0.9}}},
+	} {
+		if errs := ValidateConfidenceRecalibration(rc); len(errs) != 0 {
+			t.Errorf("ValidateConfidenceRecalibration(%+v) = %v, want no errors", rc, errs)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		rc    types.ConfidenceRecalibration
+		field string
+	}{
+		{"nothing to apply", types.ConfidenceRecalibration{}, "target_mean"},
+		{"both modes", types.ConfidenceRecalibration{TargetMean: f(0.5), Mapping: []types.ConfidencePoint{{From: 0, To: 0}}}, "mapping"},
+		{"mean out of range", types.ConfidenceRecalibration{TargetMean: f(1.5)}, "target_mean"},
+		{"unordered mapping", types.ConfidenceRecalibration{Mapping: []types.ConfidencePoint{{From: 0.5, To: 0.5}, {From: 0.5, To: 0.6}}}, "mapping[1].from"},
+		{"bad category", types.ConfidenceRecalibration{TargetMean: f(0.5), Category: "NOPE"}, "category"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateConfidenceRecalibration(tt.rc)
+			if len(errs) == 0 || errs[0].Field != tt.field {
+				t.Errorf("ValidateConfidenceRecalibration() = %v, want error on %s", errs, tt.field)
+			}
+		})
+	}
+}
+
+func TestValidateSummarizeRequest(t *testing.T) {
+	for _, req := range []types.SummarizeRequest{
+		{IDs: []string{"01ARZ3NDEKTSV4RRFFQ69G5FAV"}, Focus: "onboarding"},
+		{Query: "retry", Category: "PATTERN_OUTCOME", Language: "en", Limit: 20},
+	} {
+		if errs := ValidateSummarizeRequest(req); len(errs) != 0 {
+			t.Errorf("ValidateSummarizeRequest(%+v) = %v, want no errors", req, errs)
+		}
+	}
+
+	tooMany := make([]string, MaxSummaryEntries+1)
+	for i := range tooMany {
+		tooMany[i] = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	}
+	tests := []struct {
+		name  string
+		req   types.SummarizeRequest
+		field string
+	}{
+		{"neither", types.SummarizeRequest{}, "ids"},
+		{"both", types.SummarizeRequest{IDs: []string{"01ARZ3NDEKTSV4RRFFQ69G5FAV"}, Query: "retry"}, "query"},
+		{"too many ids", types.SummarizeRequest{IDs: tooMany}, "ids"},
+		{"bad id", types.SummarizeRequest{IDs: []string{"nope"}}, "ids[0]"},
+		{"duplicate id", types.SummarizeRequest{IDs: []string{"01ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAV"}}, "ids[1]"},
+		{"bad category", types.SummarizeRequest{Query: "retry", Category: "NOPE"}, "category"},
+		{"bad language", types.SummarizeRequest{Query: "retry", Language: "EN"}, "language"},
+		{"limit too high", types.SummarizeRequest{Query: "retry", Limit: MaxSummaryEntries + 1}, "limit"},
+		{"long focus", types.SummarizeRequest{Query: "retry", Focus: strings.Repeat("f", MaxFocusLength+1)}, "focus"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateSummarizeRequest(tt.req)
+			if len(errs) == 0 || errs[0].Field != tt.field {
+				t.Errorf("ValidateSummarizeRequest() = %v, want error on %s", errs, tt.field)
+			}
+		})
+	}
+}
+
+func TestValidateRecallRequest(t *testing.T) {
+	valid := types.RecallRequest{Task: "add retries to the payments client", TokenBudget: 2000, Format: "json", Category: "PATTERN_OUTCOME", Language: "en"}
+	if errs := ValidateRecallRequest(valid); len(errs) != 0 {
+		t.Errorf("ValidateRecallRequest(%+v) = %v, want no errors", valid, errs)
+	}
+
+	tests := []struct {
+		name  string
+		req   types.RecallRequest
+		field string
+	}{
+		{"missing task", types.RecallRequest{TokenBudget: 2000}, "task"},
+		{"missing budget", types.RecallRequest{Task: "retry"}, "token_budget"},
+		{"budget too high", types.RecallRequest{Task: "retry", TokenBudget: MaxRecallTokenBudget + 1}, "token_budget"},
+		{"bad format", types.RecallRequest{Task: "retry", TokenBudget: 2000, Format: "xml"}, "format"},
+		{"bad category", types.RecallRequest{Task: "retry", TokenBudget: 2000, Category: "NOPE"}, "category"},
+		{"bad repo", types.RecallRequest{Task: "retry", TokenBudget: 2000, Repo: "acme payments"}, "repo"},
+		{"bad path", types.RecallRequest{Task: "retry", TokenBudget: 2000, Path: "../secrets"}, "path"},
+		{"bad quota category", types.RecallRequest{Task: "retry", TokenBudget: 2000,
+			CategoryQuotas: types.CategoryQuotas{"NOPE": {Max: 1}}}, "category_quotas.NOPE"},
+		{"min above max", types.RecallRequest{Task: "retry", TokenBudget: 2000,
+			CategoryQuotas: types.CategoryQuotas{"PATTERN_OUTCOME": {Min: 2, Max: 1}}}, "category_quotas"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateRecallRequest(tt.req)
+			if len(errs) == 0 || errs[0].Field != tt.field {
+				t.Errorf("ValidateRecallRequest() = %v, want error on %s", errs, tt.field)
+			}
+		})
+	}
+}
+
+func TestValidateEvalSet(t *testing.T) {
+	const id = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	valid := types.EvalSet{
+		Name:  "golden-queries_v2",
+		Cases: []types.EvalCase{{Query: "payment retries", Relevant: []string{id}, Category: "PATTERN_OUTCOME", Repo: "acme/payments", Path: "internal/billing"}},
+	}
+	if errs := ValidateEvalSet(valid); len(errs) != 0 {
+		t.Errorf("ValidateEvalSet(%+v) = %v, want no errors", valid, errs)
+	}
+
+	withCase := func(c types.EvalCase) types.EvalSet {
+		return types.EvalSet{Name: "golden", Cases: []types.EvalCase{c}}
+	}
+	tests := []struct {
+		name  string
+		set   types.EvalSet
+		field string
+	}{
+		{"missing name", types.EvalSet{Cases: valid.Cases}, "name"},
+		{"bad name", types.EvalSet{Name: "Golden Set", Cases: valid.Cases}, "name"},
+		{"no cases", types.EvalSet{Name: "golden"}, "cases"},
+		{"too many cases", types.EvalSet{Name: "golden", Cases: make([]types.EvalCase, MaxEvalCases+1)}, "cases"},
+		{"missing query", withCase(types.EvalCase{Query: " ", Relevant: []string{id}}), "cases[0].query"},
+		{"no relevant", withCase(types.EvalCase{Query: "retries"}), "cases[0].relevant"},
+		{"bad relevant id", withCase(types.EvalCase{Query: "retries", Relevant: []string{"nope"}}), "cases[0].relevant[0]"},
+		{"bad category", withCase(types.EvalCase{Query: "retries", Relevant: []string{id}, Category: "NOPE"}), "cases[0].category"},
+		{"bad path", withCase(types.EvalCase{Query: "retries", Relevant: []string{id}, Path: "../x"}), "cases[0].path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateEvalSet(tt.set)
+			if len(errs) == 0 || errs[0].Field != tt.field {
+				t.Errorf("ValidateEvalSet() = %v, want error on %s", errs, tt.field)
+			}
+		})
+	}
+}
+
+func TestValidateMetaKey(t *testing.T) {
+	for _, v := range []string{"fleet", "fleet.config", "recall-v2_sync", "0"} {
+		if err := ValidateMetaKey("key", v); err != nil {
+			t.Errorf("ValidateMetaKey(%q) = %v, want nil", v, err)
+		}
+	}
+	for _, v := range []string{"", "Fleet", ".fleet", "-fleet", "fleet config", "fleet/config", "flëet", strings.Repeat("a", MaxMetaKeyLength+1)} {
+		if err := ValidateMetaKey("key", v); err == nil {
+			t.Errorf("ValidateMetaKey(%q) = nil, want error", v)
+		}
+	}
+}
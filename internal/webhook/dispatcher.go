@@ -0,0 +1,257 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/config"
+)
+
+// knownEvents lists the event types accepted in endpoint filters.
+var knownEvents = map[EventType]bool{
+	EventLoreIngested:      true,
+	EventLoreMerged:        true,
+	EventLoreDeleted:       true,
+	EventLoreLowConfidence: true,
+	EventSnapshotCompleted: true,
+	EventDiskUtilization:   true,
+}
+
+// Dispatcher delivers events to the configured endpoints.
+// Notify enqueues without blocking; Run performs delivery until its
+// context is cancelled. Events still queued at shutdown are discarded.
+type Dispatcher struct {
+	endpoints      []*endpoint
+	client         *http.Client
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// endpoint is a single receiver with its own ordered delivery queue.
+type endpoint struct {
+	url    string
+	secret string
+	events map[EventType]bool
+	stores map[string]bool
+	queue  chan Event
+}
+
+// NewDispatcher validates the webhook configuration and creates a Dispatcher.
+// A configuration without endpoints yields a Dispatcher that drops all events.
+func NewDispatcher(cfg config.WebhooksConfig) (*Dispatcher, error) {
+	d := &Dispatcher{
+		client:         &http.Client{Timeout: positiveDuration(cfg.Timeout, 10*time.Second)},
+		maxAttempts:    cfg.MaxAttempts,
+		initialBackoff: positiveDuration(cfg.InitialBackoff, time.Second),
+		maxBackoff:     positiveDuration(cfg.MaxBackoff, time.Minute),
+	}
+	if d.maxAttempts <= 0 {
+		d.maxAttempts = 1
+	}
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+
+	for i, ec := range cfg.Endpoints {
+		u, err := url.Parse(ec.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("webhook endpoint %d: invalid url %q", i, ec.URL)
+		}
+
+		ep := &endpoint{
+			url:    ec.URL,
+			secret: ec.Secret,
+			queue:  make(chan Event, queueSize),
+		}
+		if len(ec.Events) > 0 {
+			ep.events = make(map[EventType]bool, len(ec.Events))
+			for _, name := range ec.Events {
+				if !knownEvents[EventType(name)] {
+					return nil, fmt.Errorf("webhook endpoint %d: unknown event %q", i, name)
+				}
+				ep.events[EventType(name)] = true
+			}
+		}
+		if len(ec.Stores) > 0 {
+			ep.stores = make(map[string]bool, len(ec.Stores))
+			for _, id := range ec.Stores {
+				ep.stores[id] = true
+			}
+		}
+		d.endpoints = append(d.endpoints, ep)
+	}
+
+	return d, nil
+}
+
+// Notify queues the event for every endpoint whose filters match.
+// If an endpoint's queue is full the event is dropped for that endpoint.
+func (d *Dispatcher) Notify(event Event) {
+	for _, ep := range d.endpoints {
+		if !ep.matches(event) {
+			continue
+		}
+		select {
+		case ep.queue <- event:
+		default:
+			slog.Warn("webhook queue full, event dropped",
+				"component", "webhook",
+				"url", ep.url,
+				"event_type", event.Type,
+				"event_id", event.ID,
+				"store_id", event.StoreID,
+			)
+		}
+	}
+}
+
+// Run delivers queued events until ctx is cancelled.
+// Each endpoint is served by its own goroutine, preserving per-endpoint order.
+func (d *Dispatcher) Run(ctx context.Context) {
+	if len(d.endpoints) == 0 {
+		return
+	}
+
+	slog.Info("webhook dispatcher started",
+		"component", "worker",
+		"worker", "webhook-dispatcher",
+		"endpoints", len(d.endpoints),
+	)
+
+	var wg sync.WaitGroup
+	for _, ep := range d.endpoints {
+		wg.Add(1)
+		go func(ep *endpoint) {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event := <-ep.queue:
+					d.deliver(ctx, ep, event)
+				}
+			}
+		}(ep)
+	}
+	wg.Wait()
+
+	slog.Info("webhook dispatcher stopped",
+		"component", "worker",
+		"worker", "webhook-dispatcher",
+		"reason", "context_cancelled",
+	)
+}
+
+// deliver sends one event, retrying retryable failures with exponential backoff.
+func (d *Dispatcher) deliver(ctx context.Context, ep *endpoint, event Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("webhook event encoding failed",
+			"component", "webhook",
+			"event_type", event.Type,
+			"event_id", event.ID,
+			"error", err,
+		)
+		return
+	}
+
+	backoff := d.initialBackoff
+	for attempt := 1; ; attempt++ {
+		retryable, err := d.send(ctx, ep, event, body)
+		if err == nil {
+			slog.Debug("webhook delivered",
+				"component", "webhook",
+				"url", ep.url,
+				"event_type", event.Type,
+				"event_id", event.ID,
+				"attempt", attempt,
+			)
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if !retryable || attempt >= d.maxAttempts {
+			slog.Warn("webhook delivery failed",
+				"component", "webhook",
+				"url", ep.url,
+				"event_type", event.Type,
+				"event_id", event.ID,
+				"store_id", event.StoreID,
+				"attempts", attempt,
+				"error", err,
+			)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, d.maxBackoff)
+	}
+}
+
+// send performs a single delivery attempt. Transport errors, 408, 429 and
+// 5xx responses are retryable; other non-2xx responses are not.
+func (d *Dispatcher) send(ctx context.Context, ep *endpoint, event Event, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "engram-webhook")
+	req.Header.Set(HeaderEvent, string(event.Type))
+	req.Header.Set(HeaderDelivery, event.ID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	if ep.secret != "" {
+		req.Header.Set(HeaderSignature, Sign(ep.secret, timestamp, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= 500
+	return retryable, fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+}
+
+func (ep *endpoint) matches(event Event) bool {
+	if ep.events != nil && !ep.events[event.Type] {
+		return false
+	}
+	// Server-wide events have no store and pass store filters
+	if ep.stores != nil && event.StoreID != "" && !ep.stores[event.StoreID] {
+		return false
+	}
+	return true
+}
+
+func positiveDuration(d config.Duration, fallback time.Duration) time.Duration {
+	if d <= 0 {
+		return fallback
+	}
+	return time.Duration(d)
+}
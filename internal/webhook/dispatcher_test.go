@@ -0,0 +1,314 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/config"
+)
+
+// receiver records webhook requests and replies with scripted statuses.
+type receiver struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+	statuses []int
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+	status := http.StatusOK
+	if len(rc.statuses) > 0 {
+		status = rc.statuses[0]
+		rc.statuses = rc.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (rc *receiver) count() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.requests)
+}
+
+func (rc *receiver) waitFor(n int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if rc.count() >= n {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return false
+}
+
+func fastConfig(endpoints ...config.WebhookEndpoint) config.WebhooksConfig {
+	return config.WebhooksConfig{
+		Endpoints:      endpoints,
+		MaxAttempts:    3,
+		InitialBackoff: config.Duration(time.Millisecond),
+		MaxBackoff:     config.Duration(5 * time.Millisecond),
+		Timeout:        config.Duration(time.Second),
+		QueueSize:      10,
+	}
+}
+
+func startDispatcher(t *testing.T, cfg config.WebhooksConfig) *Dispatcher {
+	t.Helper()
+	d, err := NewDispatcher(cfg)
+	if err != nil {
+		t.Fatalf("NewDispatcher() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return d
+}
+
+func TestDispatcher_DeliversSignedEvent(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	d := startDispatcher(t, fastConfig(config.WebhookEndpoint{URL: srv.URL, Secret: "s3cret"}))
+	event := NewEvent(EventLoreDeleted, "default", DeleteData{LoreID: "01ARZ3NDEKTSV4RRFFQ69G5FAV"})
+	d.Notify(event)
+
+	if !rc.waitFor(1, 2*time.Second) {
+		t.Fatal("timed out waiting for delivery")
+	}
+
+	req, body := rc.requests[0], rc.bodies[0]
+	if got := req.Header.Get(HeaderEvent); got != string(EventLoreDeleted) {
+		t.Errorf("%s = %q, want %q", HeaderEvent, got, EventLoreDeleted)
+	}
+	if got := req.Header.Get(HeaderDelivery); got != event.ID {
+		t.Errorf("%s = %q, want %q", HeaderDelivery, got, event.ID)
+	}
+	want := Sign("s3cret", req.Header.Get(HeaderTimestamp), body)
+	if got := req.Header.Get(HeaderSignature); got != want {
+		t.Errorf("%s = %q, want %q", HeaderSignature, got, want)
+	}
+
+	var decoded struct {
+		Type    EventType  `json:"type"`
+		StoreID string     `json:"store_id"`
+		Data    DeleteData `json:"data"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if decoded.Type != EventLoreDeleted || decoded.StoreID != "default" || decoded.Data.LoreID != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Errorf("unexpected body: %s", body)
+	}
+}
+
+func TestDispatcher_NoSignatureWithoutSecret(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	d := startDispatcher(t, fastConfig(config.WebhookEndpoint{URL: srv.URL}))
+	d.Notify(NewEvent(EventSnapshotCompleted, "default", SnapshotData{}))
+
+	if !rc.waitFor(1, 2*time.Second) {
+		t.Fatal("timed out waiting for delivery")
+	}
+	if got := rc.requests[0].Header.Get(HeaderSignature); got != "" {
+		t.Errorf("%s = %q, want empty", HeaderSignature, got)
+	}
+}
+
+func TestDispatcher_RetriesServerErrors(t *testing.T) {
+	rc := &receiver{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	d := startDispatcher(t, fastConfig(config.WebhookEndpoint{URL: srv.URL}))
+	d.Notify(NewEvent(EventLoreIngested, "default", IngestData{Accepted: 1}))
+
+	if !rc.waitFor(3, 2*time.Second) {
+		t.Fatalf("expected 3 attempts, got %d", rc.count())
+	}
+	rc.mu.Lock()
+	first, last := rc.requests[0].Header.Get(HeaderDelivery), rc.requests[2].Header.Get(HeaderDelivery)
+	rc.mu.Unlock()
+	if first != last {
+		t.Errorf("delivery ID changed across retries: %q vs %q", first, last)
+	}
+}
+
+func TestDispatcher_StopsAfterMaxAttempts(t *testing.T) {
+	rc := &receiver{statuses: []int{500, 500, 500, 500, 500}}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	d := startDispatcher(t, fastConfig(config.WebhookEndpoint{URL: srv.URL}))
+	d.Notify(NewEvent(EventLoreIngested, "default", IngestData{}))
+
+	rc.waitFor(3, 2*time.Second)
+	time.Sleep(50 * time.Millisecond)
+	if got := rc.count(); got != 3 {
+		t.Errorf("expected 3 attempts (MaxAttempts), got %d", got)
+	}
+}
+
+func TestDispatcher_DoesNotRetryClientErrors(t *testing.T) {
+	rc := &receiver{statuses: []int{http.StatusBadRequest}}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	d := startDispatcher(t, fastConfig(config.WebhookEndpoint{URL: srv.URL}))
+	d.Notify(NewEvent(EventLoreIngested, "default", IngestData{}))
+
+	rc.waitFor(1, 2*time.Second)
+	time.Sleep(50 * time.Millisecond)
+	if got := rc.count(); got != 1 {
+		t.Errorf("expected 1 attempt for 400, got %d", got)
+	}
+}
+
+func TestDispatcher_FiltersByEventAndStore(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	d := startDispatcher(t, fastConfig(config.WebhookEndpoint{
+		URL:    srv.URL,
+		Events: []string{string(EventLoreDeleted)},
+		Stores: []string{"project-a"},
+	}))
+	d.Notify(NewEvent(EventLoreIngested, "project-a", IngestData{}))
+	d.Notify(NewEvent(EventLoreDeleted, "project-b", DeleteData{}))
+	d.Notify(NewEvent(EventLoreDeleted, "project-a", DeleteData{LoreID: "match"}))
+
+	if !rc.waitFor(1, 2*time.Second) {
+		t.Fatal("timed out waiting for delivery")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := rc.count(); got != 1 {
+		t.Fatalf("expected 1 matching delivery, got %d", got)
+	}
+	if got := rc.requests[0].Header.Get(HeaderEvent); got != string(EventLoreDeleted) {
+		t.Errorf("delivered %q, want %q", got, EventLoreDeleted)
+	}
+}
+
+func TestDispatcher_ServerWideEventsPassStoreFilter(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	d := startDispatcher(t, fastConfig(config.WebhookEndpoint{
+		URL:    srv.URL,
+		Stores: []string{"project-a"},
+	}))
+	d.Notify(NewEvent(EventDiskUtilization, "", DiskUtilizationData{Threshold: 0.9}))
+
+	if !rc.waitFor(1, 2*time.Second) {
+		t.Fatal("server-wide event was not delivered to a store-filtered endpoint")
+	}
+}
+
+func TestDispatcher_SlowEndpointDoesNotBlockOthers(t *testing.T) {
+	var release atomic.Bool
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for !release.Load() {
+			time.Sleep(5 * time.Millisecond)
+		}
+	}))
+	defer slow.Close()
+	defer release.Store(true)
+
+	rc := &receiver{}
+	fast := httptest.NewServer(rc)
+	defer fast.Close()
+
+	d := startDispatcher(t, fastConfig(
+		config.WebhookEndpoint{URL: slow.URL},
+		config.WebhookEndpoint{URL: fast.URL},
+	))
+	d.Notify(NewEvent(EventLoreIngested, "default", IngestData{}))
+
+	if !rc.waitFor(1, 2*time.Second) {
+		t.Fatal("fast endpoint blocked behind slow endpoint")
+	}
+	release.Store(true)
+}
+
+func TestDispatcher_DropsWhenQueueFull(t *testing.T) {
+	cfg := fastConfig(config.WebhookEndpoint{URL: "http://127.0.0.1:1"})
+	cfg.QueueSize = 2
+	d, err := NewDispatcher(cfg)
+	if err != nil {
+		t.Fatalf("NewDispatcher() error = %v", err)
+	}
+
+	// Run is not started, so nothing drains the queue.
+	for i := 0; i < 5; i++ {
+		d.Notify(NewEvent(EventLoreIngested, "default", IngestData{}))
+	}
+	if got := len(d.endpoints[0].queue); got != 2 {
+		t.Errorf("queue length = %d, want 2", got)
+	}
+}
+
+func TestNewDispatcher_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint config.WebhookEndpoint
+		wantErr  bool
+	}{
+		{"valid https", config.WebhookEndpoint{URL: "https://hooks.example.com/engram"}, false},
+		{"valid with filters", config.WebhookEndpoint{URL: "http://localhost:9000", Events: []string{"lore.merged", "snapshot.completed"}}, false},
+		{"missing scheme", config.WebhookEndpoint{URL: "hooks.example.com"}, true},
+		{"unsupported scheme", config.WebhookEndpoint{URL: "ftp://hooks.example.com"}, true},
+		{"unknown event", config.WebhookEndpoint{URL: "https://hooks.example.com", Events: []string{"lore.exploded"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDispatcher(config.WebhooksConfig{Endpoints: []config.WebhookEndpoint{tt.endpoint}})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewDispatcher() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDispatcher_RunReturnsWithoutEndpoints(t *testing.T) {
+	d, err := NewDispatcher(config.WebhooksConfig{})
+	if err != nil {
+		t.Fatalf("NewDispatcher() error = %v", err)
+	}
+	d.Notify(NewEvent(EventLoreIngested, "default", IngestData{}))
+
+	done := make(chan struct{})
+	go func() {
+		d.Run(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run should return immediately without endpoints")
+	}
+}
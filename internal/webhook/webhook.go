@@ -0,0 +1,127 @@
+// Package webhook delivers lore lifecycle notifications to external HTTP
+// endpoints. Each configured endpoint has its own delivery queue, so a slow
+// or failing receiver never delays the others. Payloads are signed with
+// HMAC-SHA256 when the endpoint has a secret.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// EventType identifies the kind of lore event being delivered.
+type EventType string
+
+// Supported event types.
+const (
+	EventLoreIngested      EventType = "lore.ingested"
+	EventLoreMerged        EventType = "lore.merged"
+	EventLoreDeleted       EventType = "lore.deleted"
+	EventLoreLowConfidence EventType = "lore.low_confidence"
+	EventSnapshotCompleted EventType = "snapshot.completed"
+	EventDiskUtilization   EventType = "disk.utilization"
+)
+
+// Delivery headers sent with every webhook request.
+const (
+	HeaderEvent     = "X-Engram-Event"
+	HeaderDelivery  = "X-Engram-Delivery"
+	HeaderTimestamp = "X-Engram-Timestamp"
+	HeaderSignature = "X-Engram-Signature"
+)
+
+// LowConfidenceThreshold is the confidence below which an entry is reported
+// as low confidence. It matches the threshold used by quality stats.
+const LowConfidenceThreshold = 0.3
+
+// Event is the JSON body of a webhook delivery.
+type Event struct {
+	ID         string    `json:"id"`
+	Type       EventType `json:"type"`
+	StoreID    string    `json:"store_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// NewEvent creates an event with a fresh ULID and the current time.
+func NewEvent(eventType EventType, storeID string, data any) Event {
+	return Event{
+		ID:         ulid.Make().String(),
+		Type:       eventType,
+		StoreID:    storeID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+// IngestData describes a lore.ingested event.
+type IngestData struct {
+	SourceID string `json:"source_id"`
+	Accepted int    `json:"accepted"`
+	Merged   int    `json:"merged"`
+	Rejected int    `json:"rejected"`
+}
+
+// MergeData describes a lore.merged event.
+type MergeData struct {
+	SourceID string `json:"source_id"`
+	Merged   int    `json:"merged"`
+}
+
+// DeleteData describes a lore.deleted event.
+type DeleteData struct {
+	LoreID   string `json:"lore_id"`
+	SourceID string `json:"source_id"`
+}
+
+// LowConfidenceData describes a lore.low_confidence event.
+// Reason is "feedback" or "decay".
+type LowConfidenceData struct {
+	LoreID             string  `json:"lore_id"`
+	PreviousConfidence float64 `json:"previous_confidence"`
+	CurrentConfidence  float64 `json:"current_confidence"`
+	Threshold          float64 `json:"threshold"`
+	Reason             string  `json:"reason"`
+}
+
+// SnapshotData describes a snapshot.completed event.
+type SnapshotData struct {
+	Uploaded bool `json:"uploaded"`
+}
+
+// DiskUtilizationData describes a disk.utilization event, sent when the
+// filesystem holding the data directory fills past a configured threshold.
+// The event is server-wide, so its store_id is empty.
+type DiskUtilizationData struct {
+	Path        string  `json:"path"`
+	UsedBytes   uint64  `json:"used_bytes"`
+	TotalBytes  uint64  `json:"total_bytes"`
+	Utilization float64 `json:"utilization"`
+	Threshold   float64 `json:"threshold"`
+}
+
+// CrossedLowConfidence reports whether a confidence change moved an entry
+// from at-or-above LowConfidenceThreshold to below it.
+func CrossedLowConfidence(previous, current float64) bool {
+	return previous >= LowConfidenceThreshold && current < LowConfidenceThreshold
+}
+
+// Notifier accepts events for asynchronous delivery.
+// Implementations must not block the caller.
+type Notifier interface {
+	Notify(event Event)
+}
+
+// Sign returns the signature header value for a payload:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>".
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSign_KnownVector(t *testing.T) {
+	got := Sign("secret", "1700000000", []byte(`{"id":"x"}`))
+	if !strings.HasPrefix(got, "sha256=") || len(got) != len("sha256=")+64 {
+		t.Fatalf("Sign() = %q, want sha256=<64 hex chars>", got)
+	}
+	if again := Sign("secret", "1700000000", []byte(`{"id":"x"}`)); again != got {
+		t.Error("Sign() is not deterministic")
+	}
+	if other := Sign("secret", "1700000001", []byte(`{"id":"x"}`)); other == got {
+		t.Error("Sign() should cover the timestamp")
+	}
+	if other := Sign("other", "1700000000", []byte(`{"id":"x"}`)); other == got {
+		t.Error("Sign() should depend on the secret")
+	}
+}
+
+func TestCrossedLowConfidence(t *testing.T) {
+	tests := []struct {
+		previous, current float64
+		want              bool
+	}{
+		{0.5, 0.25, true},
+		{0.3, 0.2, true},
+		{0.5, 0.3, false},
+		{0.25, 0.1, false},
+		{0.2, 0.4, false},
+	}
+
+	for _, tt := range tests {
+		if got := CrossedLowConfidence(tt.previous, tt.current); got != tt.want {
+			t.Errorf("CrossedLowConfidence(%v, %v) = %v, want %v", tt.previous, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestNewEvent_AssignsIDAndTime(t *testing.T) {
+	a := NewEvent(EventLoreIngested, "default", IngestData{})
+	b := NewEvent(EventLoreIngested, "default", IngestData{})
+	if a.ID == "" || a.ID == b.ID {
+		t.Errorf("expected unique non-empty IDs, got %q and %q", a.ID, b.ID)
+	}
+	if a.OccurredAt.IsZero() {
+		t.Error("OccurredAt should be set")
+	}
+}
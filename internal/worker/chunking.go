@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperengineering/engram/internal/embedding"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// ChunkEmbeddingStore is implemented by stores that keep embeddings of
+// chunks of long entries alongside the whole-entry embedding.
+// Implemented by SQLiteStore.
+type ChunkEmbeddingStore interface {
+	UpdateChunkEmbeddings(ctx context.Context, id string, chunks [][]float32) error
+}
+
+// Chunking configures multi-vector embedding of long entries by the
+// embedding workers. The zero value disables it.
+type Chunking struct {
+	Size    int
+	Overlap int
+}
+
+// embed returns the chunk embeddings of each entry long enough to chunk,
+// keyed by entry ID, from a single batch. Chunks the embedder rejected on
+// their own are nil.
+func (c Chunking) embed(ctx context.Context, embedder Embedder, entries []types.LoreEntry) (map[string][][]float32, error) {
+	if c.Size <= 0 {
+		return nil, nil
+	}
+	var texts []string
+	spans := make(map[string][2]int)
+	for _, entry := range entries {
+		chunks := embedding.Chunk(entry.Content, c.Size, c.Overlap)
+		if len(chunks) == 0 {
+			continue
+		}
+		spans[entry.ID] = [2]int{len(texts), len(texts) + len(chunks)}
+		texts = append(texts, chunks...)
+	}
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	vectors, err := embedder.EmbedBatch(ctx, texts)
+	if vectors == nil {
+		return nil, err
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("embedder returned %d embeddings for %d chunks", len(vectors), len(texts))
+	}
+	result := make(map[string][][]float32, len(spans))
+	for id, span := range spans {
+		result[id] = vectors[span[0]:span[1]]
+	}
+	return result, nil
+}
+
+// storeChunks saves the chunk embeddings of an entry when s keeps them.
+func storeChunks(ctx context.Context, s any, id string, chunks [][]float32) error {
+	cs, ok := s.(ChunkEmbeddingStore)
+	if !ok || len(chunks) == 0 {
+		return nil
+	}
+	return cs.UpdateChunkEmbeddings(ctx, id, chunks)
+}
@@ -44,9 +44,10 @@ func NewCompactionStoreManagerAdapter(manager *multistore.StoreManager) *Compact
 	return &CompactionStoreManagerAdapter{manager: manager}
 }
 
-// ListStores returns all stores from the underlying StoreManager.
+// ListStores returns the active stores from the underlying StoreManager;
+// archived stores have nothing to work on until thawed.
 func (a *CompactionStoreManagerAdapter) ListStores(ctx context.Context) ([]multistore.StoreInfo, error) {
-	return a.manager.ListStores(ctx)
+	return a.manager.ListActiveStores(ctx)
 }
 
 // GetCompactionStore returns the store and its base path.
@@ -121,7 +122,7 @@ func (c *CompactionCoordinator) compactAllStores(ctx context.Context) {
 			return // Graceful shutdown
 		}
 
-		exported, deleted, ok := c.compactStore(ctx, info.ID)
+		exported, deleted, ok := c.compactStore(ctx, info)
 		if ok {
 			if exported == 0 && deleted == 0 {
 				skipped++
@@ -149,11 +150,17 @@ func (c *CompactionCoordinator) compactAllStores(ctx context.Context) {
 	}
 }
 
-// compactStore runs compaction for a single store.
+// compactStore runs compaction for a single store, keeping the store's own
+// change log retention when its metadata sets one.
 // Returns: exported, deleted, success.
-func (c *CompactionCoordinator) compactStore(ctx context.Context, storeID string) (int64, int64, bool) {
+func (c *CompactionCoordinator) compactStore(ctx context.Context, info multistore.StoreInfo) (int64, int64, bool) {
+	storeID := info.ID
+	retention := c.retention
+	if info.Retention.ChangeLog > 0 {
+		retention = info.Retention.ChangeLog
+	}
 	start := time.Now()
-	cutoff := start.Add(-c.retention)
+	cutoff := start.Add(-retention)
 
 	store, basePath, err := c.manager.GetCompactionStore(ctx, storeID)
 	if err != nil {
@@ -25,12 +25,14 @@ type mockCompactionCapableStore struct {
 	lastCompSeq    int64
 	lastCompTime   *time.Time
 	setCompErr     error
+	lastCutoff     time.Time
 }
 
 func (m *mockCompactionCapableStore) CompactChangeLog(ctx context.Context, cutoff time.Time, auditDir string) (int64, int64, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.compactCalls++
+	m.lastCutoff = cutoff
 	if m.compactErr != nil {
 		return 0, 0, m.compactErr
 	}
@@ -172,6 +174,22 @@ func TestCompactionCoordinator_IteratesAllStores(t *testing.T) {
 	}
 }
 
+func TestCompactionCoordinator_UsesStoreRetention(t *testing.T) {
+	enum := newMockCompactionStoreEnumerator("default", "short")
+	enum.stores[1].Retention = multistore.RetentionPolicy{ChangeLog: time.Hour}
+
+	coord := NewCompactionCoordinator(enum, time.Hour, 7*24*time.Hour)
+	coord.compactAllStores(context.Background())
+
+	now := time.Now()
+	if age := now.Sub(enum.getStores["default"].lastCutoff); age < 7*24*time.Hour-time.Minute {
+		t.Errorf("default store cutoff age = %v, want the coordinator's 7 days", age)
+	}
+	if age := now.Sub(enum.getStores["short"].lastCutoff); age > time.Hour+time.Minute || age < time.Hour-time.Minute {
+		t.Errorf("short store cutoff age = %v, want the store's 1h", age)
+	}
+}
+
 func TestCompactionCoordinator_DoesNotRunImmediately(t *testing.T) {
 	enum := newMockCompactionStoreEnumerator("default")
 
@@ -0,0 +1,255 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// ConsolidationSourceID is the source recorded in change_log for merges the
+// consolidation job applies.
+const ConsolidationSourceID = "engram-consolidation"
+
+// ConsolidationCapableStore defines the operations required to consolidate
+// near-duplicate lore. Implemented by SQLiteStore.
+type ConsolidationCapableStore interface {
+	FindConsolidations(ctx context.Context, threshold float64, limit int) ([]types.ConsolidationProposal, error)
+	ConsolidateLore(ctx context.Context, targetID string, memberIDs []string, sourceID string) (*types.LoreEntry, error)
+	SetSyncMeta(ctx context.Context, key, value string) error
+}
+
+// ConsolidationStoreEnumerator provides access to all managed stores for
+// consolidation.
+type ConsolidationStoreEnumerator interface {
+	ListStores(ctx context.Context) ([]multistore.StoreInfo, error)
+	GetConsolidationStore(ctx context.Context, storeID string) (ConsolidationCapableStore, error)
+}
+
+// ConsolidationStoreManagerAdapter adapts multistore.StoreManager to
+// ConsolidationStoreEnumerator.
+type ConsolidationStoreManagerAdapter struct {
+	manager *multistore.StoreManager
+}
+
+// NewConsolidationStoreManagerAdapter creates an adapter for the given StoreManager.
+func NewConsolidationStoreManagerAdapter(manager *multistore.StoreManager) *ConsolidationStoreManagerAdapter {
+	return &ConsolidationStoreManagerAdapter{manager: manager}
+}
+
+// ListStores returns the active stores from the underlying StoreManager;
+// archived stores have nothing to work on until thawed.
+func (a *ConsolidationStoreManagerAdapter) ListStores(ctx context.Context) ([]multistore.StoreInfo, error) {
+	return a.manager.ListActiveStores(ctx)
+}
+
+// GetConsolidationStore returns the store which implements ConsolidationCapableStore.
+func (a *ConsolidationStoreManagerAdapter) GetConsolidationStore(ctx context.Context, storeID string) (ConsolidationCapableStore, error) {
+	managed, err := a.manager.GetStore(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := managed.Store.(ConsolidationCapableStore)
+	if !ok {
+		return nil, fmt.Errorf("store %q does not support consolidation", storeID)
+	}
+	return s, nil
+}
+
+// ConsolidationCoordinator periodically clusters near-duplicate lore in
+// every store and records the proposed merges as a report. When apply is
+// set it also performs them.
+type ConsolidationCoordinator struct {
+	manager      ConsolidationStoreEnumerator
+	interval     time.Duration
+	threshold    float64
+	maxProposals int
+	apply        bool
+}
+
+// NewConsolidationCoordinator creates a consolidation coordinator. Each run
+// considers at most maxProposals clusters per store; zero means no limit.
+func NewConsolidationCoordinator(
+	manager ConsolidationStoreEnumerator,
+	interval time.Duration,
+	threshold float64,
+	maxProposals int,
+	apply bool,
+) *ConsolidationCoordinator {
+	return &ConsolidationCoordinator{
+		manager:      manager,
+		interval:     interval,
+		threshold:    threshold,
+		maxProposals: maxProposals,
+		apply:        apply,
+	}
+}
+
+// Run starts the coordinator loop. It blocks until ctx is cancelled.
+//
+// Like DecayCoordinator, it waits for the first interval before running:
+// clustering compares every pair of embedded entries in a category.
+func (c *ConsolidationCoordinator) Run(ctx context.Context) {
+	slog.Info("consolidation coordinator started",
+		"component", "worker",
+		"worker", "consolidation-coordinator",
+		"interval", c.interval.String(),
+		"threshold", c.threshold,
+		"apply", c.apply,
+	)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("consolidation coordinator stopped",
+				"component", "worker",
+				"worker", "consolidation-coordinator",
+				"reason", "context_cancelled",
+			)
+			return
+		case <-ticker.C:
+			c.consolidateAllStores(ctx)
+		}
+	}
+}
+
+// consolidateAllStores runs consolidation on each store, continuing on
+// individual failures.
+func (c *ConsolidationCoordinator) consolidateAllStores(ctx context.Context) {
+	stores, err := c.manager.ListStores(ctx)
+	if err != nil {
+		slog.Error("failed to list stores for consolidation",
+			"component", "worker",
+			"worker", "consolidation-coordinator",
+			"error", err,
+		)
+		return
+	}
+
+	var succeeded, failed int
+	for _, info := range stores {
+		if ctx.Err() != nil {
+			return // Graceful shutdown
+		}
+		if c.consolidateStore(ctx, info.ID) {
+			succeeded++
+		} else {
+			failed++
+		}
+	}
+
+	if succeeded > 0 || failed > 0 {
+		slog.Info("consolidation cycle completed",
+			"component", "worker",
+			"worker", "consolidation-coordinator",
+			"stores_total", len(stores),
+			"stores_succeeded", succeeded,
+			"stores_failed", failed,
+		)
+	}
+}
+
+// consolidateStore finds, optionally applies, and reports the
+// consolidations of a single store. Returns true on success; a proposal
+// that fails to apply is recorded in the report and does not fail the run.
+func (c *ConsolidationCoordinator) consolidateStore(ctx context.Context, storeID string) bool {
+	start := time.Now()
+
+	s, err := c.manager.GetConsolidationStore(ctx, storeID)
+	if err != nil {
+		slog.Warn("failed to get store for consolidation",
+			"component", "worker",
+			"worker", "consolidation-coordinator",
+			"store_id", storeID,
+			"error", err,
+		)
+		return false
+	}
+
+	proposals, err := s.FindConsolidations(ctx, c.threshold, c.maxProposals)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false // Graceful shutdown, don't log as error
+		}
+		slog.Error("finding consolidations failed",
+			"component", "worker",
+			"worker", "consolidation-coordinator",
+			"store_id", storeID,
+			"error", err,
+		)
+		return false
+	}
+
+	var applied, failed int
+	if c.apply {
+		for i := range proposals {
+			p := &proposals[i]
+			memberIDs := make([]string, len(p.Members))
+			for j, m := range p.Members {
+				memberIDs[j] = m.ID
+			}
+			if _, err := s.ConsolidateLore(ctx, p.TargetID, memberIDs, ConsolidationSourceID); err != nil {
+				if ctx.Err() != nil {
+					return false
+				}
+				p.Status = types.ConsolidationFailed
+				p.Error = err.Error()
+				failed++
+				slog.Warn("consolidation failed",
+					"component", "worker",
+					"worker", "consolidation-coordinator",
+					"store_id", storeID,
+					"target_id", p.TargetID,
+					"error", err,
+				)
+				continue
+			}
+			p.Status = types.ConsolidationApplied
+			applied++
+		}
+	}
+
+	report, err := json.Marshal(types.ConsolidationReport{
+		GeneratedAt: start.UTC(),
+		Threshold:   c.threshold,
+		Applied:     c.apply,
+		Proposals:   proposals,
+	})
+	if err != nil {
+		slog.Error("failed to encode consolidation report",
+			"component", "worker",
+			"worker", "consolidation-coordinator",
+			"store_id", storeID,
+			"error", err,
+		)
+		return false
+	}
+	if err := s.SetSyncMeta(ctx, store.ConsolidationReportKey, string(report)); err != nil {
+		slog.Error("failed to save consolidation report",
+			"component", "worker",
+			"worker", "consolidation-coordinator",
+			"store_id", storeID,
+			"error", err,
+		)
+		return false
+	}
+
+	slog.Info("consolidation completed for store",
+		"component", "worker",
+		"worker", "consolidation-coordinator",
+		"store_id", storeID,
+		"proposals", len(proposals),
+		"applied", applied,
+		"failed", failed,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return true
+}
@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// mockConsolidationStore implements ConsolidationCapableStore for testing.
+type mockConsolidationStore struct {
+	proposals       []types.ConsolidationProposal
+	consolidateErrs map[string]error
+	consolidated    map[string][]string
+	meta            map[string]string
+}
+
+func (m *mockConsolidationStore) FindConsolidations(ctx context.Context, threshold float64, limit int) ([]types.ConsolidationProposal, error) {
+	return m.proposals, nil
+}
+
+func (m *mockConsolidationStore) ConsolidateLore(ctx context.Context, targetID string, memberIDs []string, sourceID string) (*types.LoreEntry, error) {
+	if err := m.consolidateErrs[targetID]; err != nil {
+		return nil, err
+	}
+	m.consolidated[targetID] = memberIDs
+	return &types.LoreEntry{ID: targetID}, nil
+}
+
+func (m *mockConsolidationStore) SetSyncMeta(ctx context.Context, key, value string) error {
+	m.meta[key] = value
+	return nil
+}
+
+// mockConsolidationEnumerator serves one store.
+type mockConsolidationEnumerator struct {
+	store *mockConsolidationStore
+}
+
+func (m *mockConsolidationEnumerator) ListStores(ctx context.Context) ([]multistore.StoreInfo, error) {
+	return []multistore.StoreInfo{{ID: "default"}}, nil
+}
+
+func (m *mockConsolidationEnumerator) GetConsolidationStore(ctx context.Context, storeID string) (ConsolidationCapableStore, error) {
+	return m.store, nil
+}
+
+func newMockConsolidationStore() *mockConsolidationStore {
+	return &mockConsolidationStore{
+		proposals: []types.ConsolidationProposal{
+			{TargetID: "t1", Members: []types.ConsolidationMember{{ID: "m1"}, {ID: "m2"}}, Status: types.ConsolidationProposed},
+			{TargetID: "t2", Members: []types.ConsolidationMember{{ID: "m3"}}, Status: types.ConsolidationProposed},
+		},
+		consolidateErrs: map[string]error{},
+		consolidated:    map[string][]string{},
+		meta:            map[string]string{},
+	}
+}
+
+func savedConsolidationReport(t *testing.T, s *mockConsolidationStore) types.ConsolidationReport {
+	t.Helper()
+	raw, ok := s.meta[store.ConsolidationReportKey]
+	if !ok {
+		t.Fatal("no consolidation report saved")
+	}
+	var report types.ConsolidationReport
+	if err := json.Unmarshal([]byte(raw), &report); err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func TestConsolidationCoordinator_ProposeOnly(t *testing.T) {
+	s := newMockConsolidationStore()
+	c := NewConsolidationCoordinator(&mockConsolidationEnumerator{store: s}, 0, 0.95, 100, false)
+
+	c.consolidateAllStores(context.Background())
+
+	if len(s.consolidated) != 0 {
+		t.Errorf("propose-only run merged %v", s.consolidated)
+	}
+	report := savedConsolidationReport(t, s)
+	if report.Applied || report.Threshold != 0.95 || len(report.Proposals) != 2 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	for _, p := range report.Proposals {
+		if p.Status != types.ConsolidationProposed {
+			t.Errorf("proposal %s status = %q, want proposed", p.TargetID, p.Status)
+		}
+	}
+}
+
+func TestConsolidationCoordinator_Apply(t *testing.T) {
+	s := newMockConsolidationStore()
+	s.consolidateErrs["t2"] = errors.New("before delete hook: locked")
+	c := NewConsolidationCoordinator(&mockConsolidationEnumerator{store: s}, 0, 0.95, 100, true)
+
+	c.consolidateAllStores(context.Background())
+
+	if got := s.consolidated["t1"]; len(got) != 2 || got[0] != "m1" || got[1] != "m2" {
+		t.Errorf("t1 merged %v, want [m1 m2]", got)
+	}
+	report := savedConsolidationReport(t, s)
+	if !report.Applied {
+		t.Error("report.Applied = false, want true")
+	}
+	if p := report.Proposals[0]; p.Status != types.ConsolidationApplied {
+		t.Errorf("t1 status = %q, want applied", p.Status)
+	}
+	if p := report.Proposals[1]; p.Status != types.ConsolidationFailed || p.Error == "" {
+		t.Errorf("t2 = %+v, want failed with error", p)
+	}
+}
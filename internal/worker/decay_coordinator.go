@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/webhook"
 )
 
 // DecayCapableStore defines the operations required for confidence decay.
@@ -15,6 +17,12 @@ type DecayCapableStore interface {
 	SetLastDecay(t time.Time)
 }
 
+// DecayTransitionLister is implemented by stores that can report which
+// entries a decay pass will push below a confidence floor.
+type DecayTransitionLister interface {
+	ListDecayTransitions(ctx context.Context, threshold time.Time, amount, floor float64) ([]types.ConfidenceTransition, error)
+}
+
 // DecayStoreEnumerator provides access to all managed stores for decay operations.
 // This abstraction allows testing with mock stores while production uses StoreManager.
 type DecayStoreEnumerator interface {
@@ -27,6 +35,7 @@ type DecayCoordinator struct {
 	manager     DecayStoreEnumerator
 	interval    time.Duration
 	decayAmount float64
+	notifier    webhook.Notifier
 }
 
 // DecayStoreManagerAdapter adapts multistore.StoreManager to DecayStoreEnumerator.
@@ -39,9 +48,10 @@ func NewDecayStoreManagerAdapter(manager *multistore.StoreManager) *DecayStoreMa
 	return &DecayStoreManagerAdapter{manager: manager}
 }
 
-// ListStores returns all stores from the underlying StoreManager.
+// ListStores returns the active stores from the underlying StoreManager;
+// archived stores have nothing to work on until thawed.
 func (a *DecayStoreManagerAdapter) ListStores(ctx context.Context) ([]multistore.StoreInfo, error) {
-	return a.manager.ListStores(ctx)
+	return a.manager.ListActiveStores(ctx)
 }
 
 // GetDecayStore returns the store which implements DecayCapableStore.
@@ -66,6 +76,12 @@ func NewDecayCoordinator(
 	}
 }
 
+// SetNotifier enables lore.low_confidence webhook events for entries that
+// decay below webhook.LowConfidenceThreshold. Must be called before Run.
+func (c *DecayCoordinator) SetNotifier(n webhook.Notifier) {
+	c.notifier = n
+}
+
 // Run starts the decay coordinator loop. It blocks until ctx is cancelled.
 //
 // Unlike EmbeddingRetryCoordinator which runs immediately on start, this coordinator
@@ -159,6 +175,8 @@ func (c *DecayCoordinator) decayStore(ctx context.Context, storeID string) bool
 		return false
 	}
 
+	transitions := c.listTransitions(ctx, store, storeID, threshold)
+
 	affected, err := store.DecayConfidence(ctx, threshold, c.decayAmount)
 	if err != nil {
 		if ctx.Err() != nil {
@@ -176,6 +194,16 @@ func (c *DecayCoordinator) decayStore(ctx context.Context, storeID string) bool
 	// Update per-store decay timestamp for observability
 	store.SetLastDecay(time.Now().UTC())
 
+	for _, t := range transitions {
+		c.notifier.Notify(webhook.NewEvent(webhook.EventLoreLowConfidence, storeID, webhook.LowConfidenceData{
+			LoreID:             t.LoreID,
+			PreviousConfidence: t.PreviousConfidence,
+			CurrentConfidence:  t.CurrentConfidence,
+			Threshold:          webhook.LowConfidenceThreshold,
+			Reason:             "decay",
+		}))
+	}
+
 	slog.Info("decay completed for store",
 		"component", "worker",
 		"worker", "decay-coordinator",
@@ -186,3 +214,28 @@ func (c *DecayCoordinator) decayStore(ctx context.Context, storeID string) bool
 
 	return true
 }
+
+// listTransitions returns the entries about to decay below the low-confidence
+// threshold. Returns nil when no notifier is set or the store cannot report
+// transitions; failures are logged and do not block decay.
+func (c *DecayCoordinator) listTransitions(ctx context.Context, store DecayCapableStore, storeID string, threshold time.Time) []types.ConfidenceTransition {
+	if c.notifier == nil {
+		return nil
+	}
+	lister, ok := store.(DecayTransitionLister)
+	if !ok {
+		return nil
+	}
+
+	transitions, err := lister.ListDecayTransitions(ctx, threshold, c.decayAmount, webhook.LowConfidenceThreshold)
+	if err != nil {
+		slog.Warn("failed to list decay transitions",
+			"component", "worker",
+			"worker", "decay-coordinator",
+			"store_id", storeID,
+			"error", err,
+		)
+		return nil
+	}
+	return transitions
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/hyperengineering/engram/internal/webhook"
 )
 
 // mockDecayCapableStore implements store operations for decay coordinator tests.
@@ -18,6 +20,13 @@ type mockDecayCapableStore struct {
 	decayErr    error
 	affected    int64
 	lastDecay   *time.Time
+	transitions []types.ConfidenceTransition
+}
+
+func (m *mockDecayCapableStore) ListDecayTransitions(ctx context.Context, threshold time.Time, amount, floor float64) ([]types.ConfidenceTransition, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.transitions, nil
 }
 
 func (m *mockDecayCapableStore) DecayConfidence(ctx context.Context, threshold time.Time, amount float64) (int64, error) {
@@ -550,3 +559,84 @@ func TestDecayStoreManagerAdapter_Integration_CoordinatorDecaysAllStores(t *test
 		}
 	}
 }
+
+// recordingNotifier captures webhook events for assertions.
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []webhook.Event
+}
+
+func (n *recordingNotifier) Notify(event webhook.Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events = append(n.events, event)
+}
+
+func (n *recordingNotifier) snapshot() []webhook.Event {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]webhook.Event(nil), n.events...)
+}
+
+func TestDecayCoordinator_NotifiesLowConfidenceTransitions(t *testing.T) {
+	enum := newMockDecayStoreEnumerator("default")
+	enum.getStores["default"].transitions = []types.ConfidenceTransition{
+		{LoreID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", PreviousConfidence: 0.305, CurrentConfidence: 0.295},
+	}
+	notifier := &recordingNotifier{}
+
+	coord := NewDecayCoordinator(enum, 20*time.Millisecond, 0.01)
+	coord.SetNotifier(notifier)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		coord.Run(ctx)
+		close(done)
+	}()
+
+	if !enum.waitForDecayCalls(1, 2*time.Second) {
+		t.Fatal("Timed out waiting for decay")
+	}
+	cancel()
+	<-done
+
+	events := notifier.snapshot()
+	if len(events) == 0 {
+		t.Fatal("expected a lore.low_confidence event")
+	}
+	data, ok := events[0].Data.(webhook.LowConfidenceData)
+	if events[0].Type != webhook.EventLoreLowConfidence || !ok {
+		t.Fatalf("unexpected event: %+v", events[0])
+	}
+	if data.LoreID != "01ARZ3NDEKTSV4RRFFQ69G5FAV" || data.Reason != "decay" || events[0].StoreID != "default" {
+		t.Errorf("unexpected event data: %+v (store %q)", data, events[0].StoreID)
+	}
+}
+
+func TestDecayCoordinator_NoNotificationWhenDecayFails(t *testing.T) {
+	enum := newMockDecayStoreEnumerator("default")
+	enum.getStores["default"].transitions = []types.ConfidenceTransition{{LoreID: "x", PreviousConfidence: 0.31, CurrentConfidence: 0.29}}
+	enum.setStoreError("default", errors.New("database locked"))
+	notifier := &recordingNotifier{}
+
+	coord := NewDecayCoordinator(enum, 20*time.Millisecond, 0.01)
+	coord.SetNotifier(notifier)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		coord.Run(ctx)
+		close(done)
+	}()
+
+	if !enum.waitForDecayCalls(1, 2*time.Second) {
+		t.Fatal("Timed out waiting for decay")
+	}
+	cancel()
+	<-done
+
+	if events := notifier.snapshot(); len(events) != 0 {
+		t.Errorf("expected no events after failed decay, got %d", len(events))
+	}
+}
@@ -0,0 +1,190 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/digest"
+	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// DigestStore defines the operations required to build and schedule digests.
+// Implemented by SQLiteStore.
+type DigestStore interface {
+	Digest(ctx context.Context, start, end time.Time, limit int) (*types.Digest, error)
+	GetSyncMeta(ctx context.Context, key string) (string, error)
+	SetSyncMeta(ctx context.Context, key, value string) error
+}
+
+// DigestStoreProvider provides access to stores named by digest reports.
+type DigestStoreProvider interface {
+	GetDigestStore(ctx context.Context, storeID string) (DigestStore, error)
+}
+
+// DigestStoreManagerAdapter adapts multistore.StoreManager to DigestStoreProvider.
+type DigestStoreManagerAdapter struct {
+	manager *multistore.StoreManager
+}
+
+// NewDigestStoreManagerAdapter creates an adapter for the given StoreManager.
+func NewDigestStoreManagerAdapter(manager *multistore.StoreManager) *DigestStoreManagerAdapter {
+	return &DigestStoreManagerAdapter{manager: manager}
+}
+
+// GetDigestStore returns the store for the given ID.
+func (a *DigestStoreManagerAdapter) GetDigestStore(ctx context.Context, storeID string) (DigestStore, error) {
+	managed, err := a.manager.GetStore(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := managed.Store.(DigestStore)
+	if !ok {
+		return nil, fmt.Errorf("store %q does not support digests", storeID)
+	}
+	return s, nil
+}
+
+// DigestCoordinator posts scheduled digest reports.
+//
+// Each report records the end of the last window it posted in its store's
+// metadata, so restarts neither repeat nor skip a digest. A report seen for
+// the first time starts with the next scheduled window. When several windows
+// were missed, only the most recent is posted. A failed post is retried on
+// the next check.
+type DigestCoordinator struct {
+	stores   DigestStoreProvider
+	reports  []digest.Report
+	sender   digest.Sender
+	interval time.Duration
+	topN     int
+
+	now func() time.Time
+}
+
+// NewDigestCoordinator creates a coordinator that checks report schedules
+// every interval and lists at most topN items per digest section.
+func NewDigestCoordinator(
+	stores DigestStoreProvider,
+	reports []digest.Report,
+	sender digest.Sender,
+	interval time.Duration,
+	topN int,
+) *DigestCoordinator {
+	if topN <= 0 {
+		topN = 5
+	}
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	return &DigestCoordinator{
+		stores:   stores,
+		reports:  reports,
+		sender:   sender,
+		interval: interval,
+		topN:     topN,
+		now:      time.Now,
+	}
+}
+
+// Run starts the schedule loop. Blocks until ctx is cancelled.
+// The first check runs after one interval.
+func (c *DigestCoordinator) Run(ctx context.Context) {
+	if len(c.reports) == 0 {
+		return
+	}
+
+	slog.Info("digest coordinator started",
+		"component", "worker",
+		"worker", "digest-coordinator",
+		"interval", c.interval.String(),
+		"reports", len(c.reports),
+	)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("digest coordinator stopped",
+				"component", "worker",
+				"worker", "digest-coordinator",
+				"reason", "context_cancelled",
+			)
+			return
+		case <-ticker.C:
+			c.checkAll(ctx)
+		}
+	}
+}
+
+// checkAll checks every report, continuing on individual failures.
+func (c *DigestCoordinator) checkAll(ctx context.Context) {
+	for _, r := range c.reports {
+		if ctx.Err() != nil {
+			return // Graceful shutdown
+		}
+		if err := c.check(ctx, r); err != nil {
+			slog.Error("digest report failed",
+				"component", "worker",
+				"worker", "digest-coordinator",
+				"store_id", r.StoreID,
+				"report", r.Name,
+				"error", err,
+			)
+		}
+	}
+}
+
+// check posts the report's latest window if it has not been posted yet.
+func (c *DigestCoordinator) check(ctx context.Context, r digest.Report) error {
+	s, err := c.stores.GetDigestStore(ctx, r.StoreID)
+	if err != nil {
+		return fmt.Errorf("get store: %w", err)
+	}
+
+	end := r.WindowEnd(c.now())
+	endStr := end.Format(time.RFC3339)
+
+	last, err := s.GetSyncMeta(ctx, r.MetaKey())
+	if errors.Is(err, store.ErrNotFound) {
+		// First sight of this report: the first digest is the next window.
+		return s.SetSyncMeta(ctx, r.MetaKey(), endStr)
+	}
+	if err != nil {
+		return fmt.Errorf("read last sent: %w", err)
+	}
+	if lastSent, err := time.Parse(time.RFC3339, last); err == nil && !lastSent.Before(end) {
+		return nil
+	}
+
+	d, err := s.Digest(ctx, end.Add(-r.Period()), end, c.topN)
+	if err != nil {
+		return fmt.Errorf("build digest: %w", err)
+	}
+	body, err := digest.Render(r, d)
+	if err != nil {
+		return err
+	}
+	if err := c.sender.Send(ctx, r.URL, body); err != nil {
+		return err
+	}
+	if err := s.SetSyncMeta(ctx, r.MetaKey(), endStr); err != nil {
+		return fmt.Errorf("record last sent: %w", err)
+	}
+
+	slog.Info("digest posted",
+		"component", "worker",
+		"worker", "digest-coordinator",
+		"store_id", r.StoreID,
+		"report", r.Name,
+		"window_end", endStr,
+		"new_lore", d.NewLore,
+	)
+	return nil
+}
@@ -0,0 +1,226 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/digest"
+	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// mockDigestStore implements DigestStore with in-memory metadata.
+type mockDigestStore struct {
+	mu      sync.Mutex
+	meta    map[string]string
+	windows [][2]time.Time
+}
+
+func newMockDigestStore() *mockDigestStore {
+	return &mockDigestStore{meta: make(map[string]string)}
+}
+
+func (m *mockDigestStore) Digest(ctx context.Context, start, end time.Time, limit int) (*types.Digest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.windows = append(m.windows, [2]time.Time{start, end})
+	return &types.Digest{Start: start, End: end, NewLore: 3}, nil
+}
+
+func (m *mockDigestStore) GetSyncMeta(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.meta[key]
+	if !ok {
+		return "", fmt.Errorf("sync meta key %q: %w", key, store.ErrNotFound)
+	}
+	return v, nil
+}
+
+func (m *mockDigestStore) SetSyncMeta(ctx context.Context, key, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.meta[key] = value
+	return nil
+}
+
+type mockDigestStoreProvider map[string]*mockDigestStore
+
+func (m mockDigestStoreProvider) GetDigestStore(ctx context.Context, storeID string) (DigestStore, error) {
+	if s, ok := m[storeID]; ok {
+		return s, nil
+	}
+	return nil, errors.New("store not found")
+}
+
+// mockDigestSender records posted bodies and optionally fails.
+type mockDigestSender struct {
+	mu    sync.Mutex
+	posts []string
+	urls  []string
+	err   error
+}
+
+func (m *mockDigestSender) Send(ctx context.Context, webhookURL string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.posts = append(m.posts, string(body))
+	m.urls = append(m.urls, webhookURL)
+	return nil
+}
+
+func dailyDigestReport() digest.Report {
+	return digest.Report{
+		Name:     "daily-slack",
+		StoreID:  "default",
+		Schedule: digest.ScheduleDaily,
+		Format:   digest.FormatSlack,
+		Hour:     9,
+		URL:      "https://hooks.slack.com/services/T/B/x",
+	}
+}
+
+func newTestDigestCoordinator(stores mockDigestStoreProvider, sender *mockDigestSender, now *time.Time, reports ...digest.Report) *DigestCoordinator {
+	c := NewDigestCoordinator(stores, reports, sender, time.Minute, 5)
+	c.now = func() time.Time { return *now }
+	return c
+}
+
+// --- Tests ---
+
+func TestDigestCoordinator_FirstCheckSchedulesNextWindow(t *testing.T) {
+	s := newMockDigestStore()
+	sender := &mockDigestSender{}
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	c := newTestDigestCoordinator(mockDigestStoreProvider{"default": s}, sender, &now, dailyDigestReport())
+
+	c.checkAll(context.Background())
+
+	if len(sender.posts) != 0 {
+		t.Errorf("expected no digest on first check, got %d", len(sender.posts))
+	}
+	if got := s.meta["digest_last_sent:daily-slack"]; got != "2026-03-04T09:00:00Z" {
+		t.Errorf("last sent = %q, want current window end", got)
+	}
+}
+
+func TestDigestCoordinator_PostsOncePerWindow(t *testing.T) {
+	s := newMockDigestStore()
+	s.meta["digest_last_sent:daily-slack"] = "2026-03-04T09:00:00Z"
+	sender := &mockDigestSender{}
+	now := time.Date(2026, 3, 5, 8, 0, 0, 0, time.UTC)
+	c := newTestDigestCoordinator(mockDigestStoreProvider{"default": s}, sender, &now, dailyDigestReport())
+
+	// Before the next scheduled time: nothing to send
+	c.checkAll(context.Background())
+	if len(sender.posts) != 0 {
+		t.Fatalf("expected no digest before 09:00, got %d", len(sender.posts))
+	}
+
+	// After it: exactly one digest covering the previous 24 hours
+	now = time.Date(2026, 3, 5, 9, 5, 0, 0, time.UTC)
+	c.checkAll(context.Background())
+	c.checkAll(context.Background())
+
+	if len(sender.posts) != 1 {
+		t.Fatalf("expected 1 digest, got %d", len(sender.posts))
+	}
+	if sender.urls[0] != "https://hooks.slack.com/services/T/B/x" {
+		t.Errorf("posted to %q", sender.urls[0])
+	}
+	if !strings.Contains(sender.posts[0], "3 entries added") {
+		t.Errorf("unexpected digest body: %s", sender.posts[0])
+	}
+	wantEnd := time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)
+	if w := s.windows[0]; !w[1].Equal(wantEnd) || !w[0].Equal(wantEnd.Add(-24*time.Hour)) {
+		t.Errorf("window = %v, want 24h ending %v", w, wantEnd)
+	}
+	if got := s.meta["digest_last_sent:daily-slack"]; got != "2026-03-05T09:00:00Z" {
+		t.Errorf("last sent = %q", got)
+	}
+}
+
+func TestDigestCoordinator_RetriesAfterSendFailure(t *testing.T) {
+	s := newMockDigestStore()
+	s.meta["digest_last_sent:daily-slack"] = "2026-03-04T09:00:00Z"
+	sender := &mockDigestSender{err: errors.New("status 500")}
+	now := time.Date(2026, 3, 5, 9, 5, 0, 0, time.UTC)
+	c := newTestDigestCoordinator(mockDigestStoreProvider{"default": s}, sender, &now, dailyDigestReport())
+
+	c.checkAll(context.Background())
+	if got := s.meta["digest_last_sent:daily-slack"]; got != "2026-03-04T09:00:00Z" {
+		t.Fatalf("last sent advanced despite failure: %q", got)
+	}
+
+	sender.err = nil
+	c.checkAll(context.Background())
+	if len(sender.posts) != 1 {
+		t.Errorf("expected retry to post 1 digest, got %d", len(sender.posts))
+	}
+}
+
+func TestDigestCoordinator_ContinuesOnError(t *testing.T) {
+	s := newMockDigestStore()
+	s.meta["digest_last_sent:daily-slack"] = "2026-03-04T09:00:00Z"
+	sender := &mockDigestSender{}
+	now := time.Date(2026, 3, 5, 9, 5, 0, 0, time.UTC)
+
+	missing := dailyDigestReport()
+	missing.StoreID = "deleted-store"
+	c := newTestDigestCoordinator(mockDigestStoreProvider{"default": s}, sender, &now, missing, dailyDigestReport())
+
+	c.checkAll(context.Background())
+	if len(sender.posts) != 1 {
+		t.Errorf("expected the valid report to post, got %d posts", len(sender.posts))
+	}
+}
+
+func TestDigestCoordinator_RunReturnsWithoutReports(t *testing.T) {
+	c := NewDigestCoordinator(mockDigestStoreProvider{}, nil, &mockDigestSender{}, time.Minute, 5)
+
+	done := make(chan struct{})
+	go func() {
+		c.Run(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run should return immediately when no reports are configured")
+	}
+}
+
+// --- Integration Tests ---
+
+func TestDigestStoreManagerAdapter_ReturnsSQLiteStore(t *testing.T) {
+	manager, err := multistore.NewStoreManager(filepath.Join(t.TempDir(), "stores"))
+	if err != nil {
+		t.Fatalf("NewStoreManager() error = %v", err)
+	}
+	defer manager.Close()
+
+	ctx := context.Background()
+	adapter := NewDigestStoreManagerAdapter(manager)
+	s, err := adapter.GetDigestStore(ctx, "default")
+	if err != nil {
+		t.Fatalf("GetDigestStore failed: %v", err)
+	}
+
+	end := time.Now()
+	if _, err := s.Digest(ctx, end.Add(-24*time.Hour), end, 5); err != nil {
+		t.Errorf("Digest failed: %v", err)
+	}
+	if _, err := s.GetSyncMeta(ctx, "digest_last_sent:missing"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("GetSyncMeta error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,163 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/webhook"
+)
+
+// DiskUsage describes how full a filesystem is. TotalBytes excludes space
+// reserved for the superuser.
+type DiskUsage struct {
+	UsedBytes  uint64
+	TotalBytes uint64
+}
+
+// Utilization returns the used fraction of the filesystem.
+func (u DiskUsage) Utilization() float64 {
+	if u.TotalBytes == 0 {
+		return 0
+	}
+	return float64(u.UsedBytes) / float64(u.TotalBytes)
+}
+
+// DiskMonitor periodically checks how full the filesystem holding the data
+// directory is. SQLite reports a full disk as an opaque I/O error on the
+// write that hits it, so the monitor warns while there is still room: once
+// per threshold crossed upwards, with an info log when utilization drops
+// back below it.
+type DiskMonitor struct {
+	path       string
+	interval   time.Duration
+	thresholds []float64
+	usage      func(path string) (DiskUsage, error)
+	notifier   webhook.Notifier
+
+	// crossed is the number of thresholds at or below the last utilization.
+	crossed int
+}
+
+// NewDiskMonitor creates a monitor for the filesystem holding path.
+// Thresholds are utilization fractions in (0, 1]; others are ignored.
+func NewDiskMonitor(path string, interval time.Duration, thresholds []float64) *DiskMonitor {
+	valid := make([]float64, 0, len(thresholds))
+	for _, t := range thresholds {
+		if t > 0 && t <= 1 {
+			valid = append(valid, t)
+		}
+	}
+	slices.Sort(valid)
+	return &DiskMonitor{
+		path:       path,
+		interval:   interval,
+		thresholds: slices.Compact(valid),
+		usage:      diskUsage,
+	}
+}
+
+// SetNotifier enables disk.utilization webhook events.
+// Must be called before Run.
+func (m *DiskMonitor) SetNotifier(n webhook.Notifier) {
+	m.notifier = n
+}
+
+// Run starts the monitor loop. Blocks until ctx is cancelled.
+// The first check runs immediately, so a nearly full disk is reported at
+// startup.
+func (m *DiskMonitor) Run(ctx context.Context) {
+	slog.Info("disk monitor started",
+		"component", "worker",
+		"worker", "disk-monitor",
+		"path", m.path,
+		"interval", m.interval.String(),
+		"thresholds", m.thresholds,
+	)
+
+	if !m.check() {
+		return
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("disk monitor stopped",
+				"component", "worker",
+				"worker", "disk-monitor",
+				"reason", "context_cancelled",
+			)
+			return
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+// check compares the current utilization with the thresholds and reports
+// any crossing. It returns false if utilization can't be measured on this
+// platform.
+func (m *DiskMonitor) check() bool {
+	usage, err := m.usage(m.path)
+	if errors.Is(err, errors.ErrUnsupported) {
+		slog.Warn("disk monitor unsupported on this platform",
+			"component", "worker",
+			"worker", "disk-monitor",
+		)
+		return false
+	}
+	if err != nil {
+		slog.Warn("failed to check disk utilization",
+			"component", "worker",
+			"worker", "disk-monitor",
+			"path", m.path,
+			"error", err,
+		)
+		return true
+	}
+
+	utilization := usage.Utilization()
+	crossed := 0
+	for crossed < len(m.thresholds) && utilization >= m.thresholds[crossed] {
+		crossed++
+	}
+	previous := m.crossed
+	m.crossed = crossed
+
+	switch {
+	case crossed > previous:
+		threshold := m.thresholds[crossed-1]
+		slog.Warn("disk utilization above threshold",
+			"component", "worker",
+			"worker", "disk-monitor",
+			"path", m.path,
+			"utilization", utilization,
+			"threshold", threshold,
+			"used_bytes", usage.UsedBytes,
+			"total_bytes", usage.TotalBytes,
+		)
+		if m.notifier != nil {
+			m.notifier.Notify(webhook.NewEvent(webhook.EventDiskUtilization, "", webhook.DiskUtilizationData{
+				Path:        m.path,
+				UsedBytes:   usage.UsedBytes,
+				TotalBytes:  usage.TotalBytes,
+				Utilization: utilization,
+				Threshold:   threshold,
+			}))
+		}
+	case crossed < previous:
+		slog.Info("disk utilization back below threshold",
+			"component", "worker",
+			"worker", "disk-monitor",
+			"path", m.path,
+			"utilization", utilization,
+			"threshold", m.thresholds[previous-1],
+		)
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperengineering/engram/internal/webhook"
+)
+
+func TestDiskMonitor_ReportsEachThresholdCrossedOnce(t *testing.T) {
+	m := NewDiskMonitor("/data", 0, []float64{0.9, 0.8, 0, 1.5, 0.8})
+	notifier := &recordingNotifier{}
+	m.SetNotifier(notifier)
+
+	used := uint64(0)
+	m.usage = func(string) (DiskUsage, error) {
+		return DiskUsage{UsedBytes: used, TotalBytes: 100}, nil
+	}
+	// Rising past both thresholds at once reports the highest; recovering
+	// re-arms the lower one
+	for _, u := range []uint64{50, 85, 86, 95, 70, 82} {
+		used = u
+		if !m.check() {
+			t.Fatal("check() = false")
+		}
+	}
+
+	events := notifier.snapshot()
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	for i, want := range []float64{0.8, 0.9, 0.8} {
+		data := events[i].Data.(webhook.DiskUtilizationData)
+		if events[i].Type != webhook.EventDiskUtilization || data.Threshold != want || events[i].StoreID != "" {
+			t.Errorf("event %d = %+v, want threshold %v", i, events[i], want)
+		}
+	}
+	if data := events[1].Data.(webhook.DiskUtilizationData); data.UsedBytes != 95 || data.Utilization != 0.95 {
+		t.Errorf("event data = %+v", data)
+	}
+}
+
+func TestDiskMonitor_StopsWhenUnsupported(t *testing.T) {
+	m := NewDiskMonitor("/data", 0, []float64{0.8})
+	m.usage = func(string) (DiskUsage, error) {
+		return DiskUsage{}, errors.ErrUnsupported
+	}
+	if m.check() {
+		t.Error("check() = true on an unsupported platform")
+	}
+
+	m.usage = func(string) (DiskUsage, error) {
+		return DiskUsage{}, errors.New("transient")
+	}
+	if !m.check() {
+		t.Error("check() = false after a transient error")
+	}
+}
+
+func TestDiskUsage(t *testing.T) {
+	usage, err := diskUsage(t.TempDir())
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("disk usage unsupported on this platform")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.TotalBytes == 0 || usage.UsedBytes > usage.TotalBytes {
+		t.Errorf("diskUsage() = %+v", usage)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !(linux || darwin || freebsd)
+
+package worker
+
+import "errors"
+
+// diskUsage is not supported on this platform; the disk monitor logs the
+// error once and stops.
+func diskUsage(string) (DiskUsage, error) {
+	return DiskUsage{}, errors.ErrUnsupported
+}
@@ -0,0 +1,21 @@
+//go:build linux || darwin || freebsd
+
+package worker
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// diskUsage reports the usage of the filesystem holding path. Space
+// reserved for the superuser counts as neither used nor available, as in
+// df, since the server can't write to it.
+func diskUsage(path string) (DiskUsage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return DiskUsage{}, fmt.Errorf("statfs %s: %w", path, err)
+	}
+	bsize := uint64(st.Bsize)
+	used := (uint64(st.Blocks) - uint64(st.Bfree)) * bsize
+	return DiskUsage{UsedBytes: used, TotalBytes: used + uint64(st.Bavail)*bsize}, nil
+}
@@ -0,0 +1,448 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/embedding"
+	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+)
+
+// backfillCursorKey is the sync_meta key holding the ID of the last backlog
+// entry a backfill finished with, so an interrupted run resumes after it.
+const backfillCursorKey = "embedding_backfill_cursor"
+
+// BackfillStore defines the store operations needed by the embedding backfill.
+// Implemented by SQLiteStore.
+type BackfillStore interface {
+	CountEmbeddingBacklog(ctx context.Context) (int64, error)
+	GetEmbeddingBacklog(ctx context.Context, afterID string, limit int) ([]types.LoreEntry, error)
+	UpdateEmbedding(ctx context.Context, id string, embedding []float32) error
+	MarkEmbeddingFailed(ctx context.Context, id string) error
+	GetSyncMeta(ctx context.Context, key string) (string, error)
+	SetSyncMeta(ctx context.Context, key, value string) error
+}
+
+// BackfillStoreEnumerator provides access to all managed stores for the
+// embedding backfill.
+type BackfillStoreEnumerator interface {
+	ListStores(ctx context.Context) ([]multistore.StoreInfo, error)
+	GetBackfillStore(ctx context.Context, storeID string) (BackfillStore, error)
+}
+
+// GetBackfillStore returns the store as a BackfillStore.
+func (a *EmbeddingStoreManagerAdapter) GetBackfillStore(ctx context.Context, storeID string) (BackfillStore, error) {
+	managed, err := a.manager.GetStore(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := managed.Store.(BackfillStore)
+	if !ok {
+		return nil, fmt.Errorf("store %q does not support embedding backfill", storeID)
+	}
+	return s, nil
+}
+
+// BackfillConfig tunes an EmbeddingBackfill. Zero fields take defaults.
+type BackfillConfig struct {
+	// BatchSize is the number of entries sent per embedding request.
+	BatchSize int
+	// Concurrency is the number of embedding requests in flight at once.
+	Concurrency int
+	// MaxAttempts is how often a batch is retried on errors other than
+	// rate limiting before its entries are embedded one at a time.
+	MaxAttempts int
+	// MinBackoff and MaxBackoff bound the exponential wait between retries
+	// when the provider gives no Retry-After.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// ProgressInterval is how often progress is logged.
+	ProgressInterval time.Duration
+	// Chunking, when enabled, also embeds the chunks of long entries.
+	Chunking Chunking
+}
+
+func (c BackfillConfig) withDefaults() BackfillConfig {
+	if c.BatchSize <= 0 {
+		c.BatchSize = 256
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = 4
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+	if c.MinBackoff <= 0 {
+		c.MinBackoff = time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = time.Minute
+	}
+	if c.ProgressInterval <= 0 {
+		c.ProgressInterval = 10 * time.Second
+	}
+	return c
+}
+
+// BackfillResult summarizes an embedding backfill run.
+type BackfillResult struct {
+	Stores   int
+	Embedded int64
+	Failed   int64
+}
+
+// EmbeddingBackfill embeds the whole pending and failed backlog of every
+// managed store in one pass, for use after importing large dumps.
+//
+// Unlike EmbeddingRetryCoordinator it runs to completion rather than on an
+// interval, keeps several large batches in flight, and waits out provider
+// rate limits instead of counting them as failed attempts. Progress is
+// checkpointed per store after every batch, so a rerun resumes where an
+// interrupted one stopped.
+type EmbeddingBackfill struct {
+	manager  BackfillStoreEnumerator
+	embedder Embedder
+	cfg      BackfillConfig
+}
+
+// NewEmbeddingBackfill creates a backfill over all managed stores.
+func NewEmbeddingBackfill(manager BackfillStoreEnumerator, embedder Embedder, cfg BackfillConfig) *EmbeddingBackfill {
+	return &EmbeddingBackfill{
+		manager:  manager,
+		embedder: embedder,
+		cfg:      cfg.withDefaults(),
+	}
+}
+
+// Run backfills every store in turn. A store that fails is logged and
+// skipped; the returned error joins every store failure.
+func (b *EmbeddingBackfill) Run(ctx context.Context) (BackfillResult, error) {
+	stores, err := b.manager.ListStores(ctx)
+	if err != nil {
+		return BackfillResult{}, fmt.Errorf("list stores: %w", err)
+	}
+
+	slog.Info("embedding backfill started",
+		"component", "worker",
+		"worker", "embedding-backfill",
+		"stores_total", len(stores),
+		"batch_size", b.cfg.BatchSize,
+		"concurrency", b.cfg.Concurrency,
+	)
+
+	var result BackfillResult
+	var errs []error
+	for _, info := range stores {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+		embedded, failed, err := b.backfillStore(ctx, info.ID)
+		result.Embedded += embedded
+		result.Failed += failed
+		if err != nil {
+			slog.Error("embedding backfill failed for store",
+				"component", "worker",
+				"worker", "embedding-backfill",
+				"store_id", info.ID,
+				"error", err,
+			)
+			errs = append(errs, fmt.Errorf("store %s: %w", info.ID, err))
+			continue
+		}
+		result.Stores++
+	}
+
+	slog.Info("embedding backfill finished",
+		"component", "worker",
+		"worker", "embedding-backfill",
+		"stores_completed", result.Stores,
+		"stores_total", len(stores),
+		"entries_embedded", result.Embedded,
+		"entries_failed", result.Failed,
+	)
+
+	return result, errors.Join(errs...)
+}
+
+// backfillStore embeds one store's backlog, returning how many entries were
+// embedded and how many were marked failed.
+func (b *EmbeddingBackfill) backfillStore(ctx context.Context, storeID string) (embedded, failed int64, err error) {
+	s, err := b.manager.GetBackfillStore(ctx, storeID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	cursor, err := s.GetSyncMeta(ctx, backfillCursorKey)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return 0, 0, err
+	}
+	total, err := s.CountEmbeddingBacklog(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	if cursor != "" {
+		slog.Info("resuming embedding backfill",
+			"component", "worker",
+			"worker", "embedding-backfill",
+			"store_id", storeID,
+			"after_id", cursor,
+		)
+	}
+
+	progress := newBackfillProgress(storeID, total, b.cfg.ProgressInterval)
+	for {
+		// Read ahead as many batches as can be embedded concurrently.
+		var batches [][]types.LoreEntry
+		after := cursor
+		for len(batches) < b.cfg.Concurrency {
+			page, err := s.GetEmbeddingBacklog(ctx, after, b.cfg.BatchSize)
+			if err != nil {
+				return progress.embedded, progress.failed, err
+			}
+			if len(page) == 0 {
+				break
+			}
+			batches = append(batches, page)
+			after = page[len(page)-1].ID
+			if len(page) < b.cfg.BatchSize {
+				break
+			}
+		}
+		if len(batches) == 0 {
+			break
+		}
+
+		results := make([][][]float32, len(batches))
+		chunks := make([]map[string][][]float32, len(batches))
+		errs := make([]error, len(batches))
+		var wg sync.WaitGroup
+		for i, batch := range batches {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], errs[i] = b.embedBatch(ctx, storeID, batch)
+				if errs[i] == nil {
+					chunks[i] = b.embedChunks(ctx, storeID, batch)
+				}
+			}()
+		}
+		wg.Wait()
+
+		// Write batches in ID order so the checkpoint never passes an
+		// entry that has not been handled.
+		for i, batch := range batches {
+			if errs[i] != nil {
+				return progress.embedded, progress.failed, errs[i]
+			}
+			if err := b.writeBatch(ctx, s, batch, results[i], chunks[i], progress); err != nil {
+				return progress.embedded, progress.failed, err
+			}
+			cursor = batch[len(batch)-1].ID
+			if err := s.SetSyncMeta(ctx, backfillCursorKey, cursor); err != nil {
+				return progress.embedded, progress.failed, fmt.Errorf("checkpoint backfill: %w", err)
+			}
+			progress.maybeLog()
+		}
+	}
+
+	// The backlog is drained; the next backfill starts from the beginning.
+	if err := s.SetSyncMeta(ctx, backfillCursorKey, ""); err != nil {
+		return progress.embedded, progress.failed, fmt.Errorf("clear backfill checkpoint: %w", err)
+	}
+	progress.log("embedding backfill completed for store")
+	return progress.embedded, progress.failed, nil
+}
+
+// writeBatch stores the embeddings for batch, and the chunk embeddings of
+// its long entries. A nil embedding marks an entry that could not be
+// embedded on its own.
+func (b *EmbeddingBackfill) writeBatch(ctx context.Context, s BackfillStore, batch []types.LoreEntry, embeddings [][]float32, chunks map[string][][]float32, progress *backfillProgress) error {
+	for i, entry := range batch {
+		var err error
+		if embeddings[i] == nil {
+			err = s.MarkEmbeddingFailed(ctx, entry.ID)
+			if err == nil {
+				progress.failed++
+			}
+		} else {
+			err = s.UpdateEmbedding(ctx, entry.ID, embeddings[i])
+			if err == nil {
+				progress.embedded++
+				err = storeChunks(ctx, s, entry.ID, chunks[entry.ID])
+			}
+		}
+		// Entries deleted while the backfill ran no longer need an embedding.
+		if errors.Is(err, store.ErrNotFound) {
+			progress.skipped++
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("write embedding for %s: %w", entry.ID, err)
+		}
+	}
+	return nil
+}
+
+// embedBatch embeds batch, waiting out rate limits and retrying other
+// errors with exponential backoff. Once MaxAttempts is exhausted it embeds
+// entries one at a time so a single bad entry can't stall the backlog.
+func (b *EmbeddingBackfill) embedBatch(ctx context.Context, storeID string, batch []types.LoreEntry) ([][]float32, error) {
+	contents := make([]string, len(batch))
+	for i, entry := range batch {
+		contents[i] = entry.Content
+	}
+
+	backoff := b.cfg.MinBackoff
+	attempts := 0
+	for {
+		embeddings, err := b.embedder.EmbedBatch(ctx, contents)
+		var partial *embedding.PartialError
+		if err == nil || (errors.As(err, &partial) && embeddings != nil) {
+			if len(embeddings) != len(batch) {
+				return nil, fmt.Errorf("embedder returned %d embeddings for %d entries", len(embeddings), len(batch))
+			}
+			// The provider rejected the entries left without an embedding
+			// on their own, so retrying them would not help.
+			if partial != nil {
+				slog.Warn("embedding backfill entries rejected",
+					"component", "worker",
+					"worker", "embedding-backfill",
+					"store_id", storeID,
+					"entries_count", len(partial.Failed),
+					"error", err,
+				)
+			}
+			return embeddings, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		wait, rateLimited := embedding.RateLimited(err)
+		if !rateLimited {
+			attempts++
+			if attempts >= b.cfg.MaxAttempts {
+				return b.embedEach(ctx, storeID, batch)
+			}
+		}
+		if wait == 0 {
+			wait = backoff
+			backoff = min(backoff*2, b.cfg.MaxBackoff)
+		}
+
+		slog.Warn("embedding backfill batch failed, backing off",
+			"component", "worker",
+			"worker", "embedding-backfill",
+			"store_id", storeID,
+			"entries_count", len(batch),
+			"rate_limited", rateLimited,
+			"wait", wait.String(),
+			"error", err,
+		)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// embedChunks embeds the chunks of the long entries in batch. It is best
+// effort: entries whose chunks fail keep their single embedding.
+func (b *EmbeddingBackfill) embedChunks(ctx context.Context, storeID string, batch []types.LoreEntry) map[string][][]float32 {
+	chunks, err := b.cfg.Chunking.embed(ctx, b.embedder, batch)
+	if err != nil {
+		slog.Warn("embedding backfill chunks failed, entries keep a single embedding",
+			"component", "worker",
+			"worker", "embedding-backfill",
+			"store_id", storeID,
+			"error", err,
+		)
+	}
+	return chunks
+}
+
+// embedEach embeds the entries of a repeatedly failing batch individually.
+// Entries that still fail get a nil embedding. If every entry fails the
+// provider itself is down, so the batch fails rather than marking them all.
+func (b *EmbeddingBackfill) embedEach(ctx context.Context, storeID string, batch []types.LoreEntry) ([][]float32, error) {
+	embeddings := make([][]float32, len(batch))
+	var lastErr error
+	succeeded := 0
+	for i, entry := range batch {
+		result, err := b.embedder.EmbedBatch(ctx, []string{entry.Content})
+		if err != nil || len(result) != 1 {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
+			slog.Warn("embedding backfill entry failed",
+				"component", "worker",
+				"worker", "embedding-backfill",
+				"store_id", storeID,
+				"lore_id", entry.ID,
+				"error", err,
+			)
+			continue
+		}
+		embeddings[i] = result[0]
+		succeeded++
+	}
+	if succeeded == 0 {
+		return nil, fmt.Errorf("embed batch: %w", lastErr)
+	}
+	return embeddings, nil
+}
+
+// backfillProgress tracks and periodically logs one store's backfill.
+type backfillProgress struct {
+	storeID  string
+	total    int64
+	interval time.Duration
+	start    time.Time
+	lastLog  time.Time
+
+	embedded, failed, skipped int64
+}
+
+func newBackfillProgress(storeID string, total int64, interval time.Duration) *backfillProgress {
+	now := time.Now()
+	return &backfillProgress{storeID: storeID, total: total, interval: interval, start: now, lastLog: now}
+}
+
+func (p *backfillProgress) maybeLog() {
+	if time.Since(p.lastLog) >= p.interval {
+		p.log("embedding backfill progress")
+	}
+}
+
+func (p *backfillProgress) log(msg string) {
+	p.lastLog = time.Now()
+	done := p.embedded + p.failed + p.skipped
+	elapsed := time.Since(p.start)
+	rate := float64(done) / elapsed.Seconds()
+	remaining := max(p.total-done, 0)
+
+	attrs := []any{
+		"component", "worker",
+		"worker", "embedding-backfill",
+		"store_id", p.storeID,
+		"entries_embedded", p.embedded,
+		"entries_failed", p.failed,
+		"entries_remaining", remaining,
+		"entries_per_second", rate,
+		"elapsed", elapsed.Round(time.Second).String(),
+	}
+	if rate > 0 && remaining > 0 {
+		eta := time.Duration(float64(remaining) / rate * float64(time.Second))
+		attrs = append(attrs, "eta", eta.Round(time.Second).String())
+	}
+	slog.Info(msg, attrs...)
+}
@@ -0,0 +1,318 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/store"
+	"github.com/hyperengineering/engram/internal/types"
+	"github.com/openai/openai-go"
+)
+
+// mockBackfillStore keeps a backlog of entries keyed by ID.
+type mockBackfillStore struct {
+	mu       sync.Mutex
+	backlog  map[string]types.LoreEntry
+	embedded []string
+	failed   []string
+	meta     map[string]string
+}
+
+func newMockBackfillStore(n int) *mockBackfillStore {
+	s := &mockBackfillStore{backlog: make(map[string]types.LoreEntry), meta: make(map[string]string)}
+	for i := range n {
+		id := fmt.Sprintf("entry-%03d", i)
+		s.backlog[id] = types.LoreEntry{ID: id, Content: "content " + id}
+	}
+	return s
+}
+
+func (m *mockBackfillStore) CountEmbeddingBacklog(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return int64(len(m.backlog)), nil
+}
+
+func (m *mockBackfillStore) GetEmbeddingBacklog(ctx context.Context, afterID string, limit int) ([]types.LoreEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ids []string
+	for id := range m.backlog {
+		if id > afterID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	var page []types.LoreEntry
+	for _, id := range ids[:min(limit, len(ids))] {
+		page = append(page, m.backlog[id])
+	}
+	return page, nil
+}
+
+func (m *mockBackfillStore) UpdateEmbedding(ctx context.Context, id string, embedding []float32) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.backlog, id)
+	m.embedded = append(m.embedded, id)
+	return nil
+}
+
+func (m *mockBackfillStore) MarkEmbeddingFailed(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failed = append(m.failed, id)
+	return nil
+}
+
+func (m *mockBackfillStore) GetSyncMeta(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.meta[key]
+	if !ok {
+		return "", store.ErrNotFound
+	}
+	return v, nil
+}
+
+func (m *mockBackfillStore) SetSyncMeta(ctx context.Context, key, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.meta[key] = value
+	return nil
+}
+
+type mockBackfillEnumerator struct {
+	stores map[string]*mockBackfillStore
+}
+
+func (m *mockBackfillEnumerator) ListStores(ctx context.Context) ([]multistore.StoreInfo, error) {
+	var infos []multistore.StoreInfo
+	for id := range m.stores {
+		infos = append(infos, multistore.StoreInfo{ID: id})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos, nil
+}
+
+func (m *mockBackfillEnumerator) GetBackfillStore(ctx context.Context, storeID string) (BackfillStore, error) {
+	s, ok := m.stores[storeID]
+	if !ok {
+		return nil, multistore.ErrStoreNotFound
+	}
+	return s, nil
+}
+
+// scriptedEmbedder fails calls according to fail, which sees the call
+// number and the batch.
+type scriptedEmbedder struct {
+	mu    sync.Mutex
+	calls int
+	fail  func(call int, contents []string) error
+}
+
+func (e *scriptedEmbedder) EmbedBatch(ctx context.Context, contents []string) ([][]float32, error) {
+	e.mu.Lock()
+	e.calls++
+	call := e.calls
+	e.mu.Unlock()
+	if e.fail != nil {
+		if err := e.fail(call, contents); err != nil {
+			return nil, err
+		}
+	}
+	result := make([][]float32, len(contents))
+	for i := range result {
+		result[i] = []float32{1, 0}
+	}
+	return result, nil
+}
+
+var fastBackfill = BackfillConfig{
+	BatchSize:   4,
+	Concurrency: 2,
+	MaxAttempts: 2,
+	MinBackoff:  time.Millisecond,
+	MaxBackoff:  time.Millisecond,
+}
+
+func TestEmbeddingBackfill_DrainsEveryStore(t *testing.T) {
+	enum := &mockBackfillEnumerator{stores: map[string]*mockBackfillStore{
+		"a": newMockBackfillStore(11),
+		"b": newMockBackfillStore(3),
+	}}
+	embedder := &scriptedEmbedder{}
+
+	result, err := NewEmbeddingBackfill(enum, embedder, fastBackfill).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Stores != 2 || result.Embedded != 14 || result.Failed != 0 {
+		t.Errorf("Run() = %+v, want 2 stores, 14 embedded, 0 failed", result)
+	}
+	for id, s := range enum.stores {
+		if len(s.backlog) != 0 {
+			t.Errorf("store %s: %d entries left in backlog", id, len(s.backlog))
+		}
+		if cursor := s.meta[backfillCursorKey]; cursor != "" {
+			t.Errorf("store %s: checkpoint %q left after completion", id, cursor)
+		}
+	}
+	// 11 entries in batches of 4 plus 3 entries in one batch.
+	if embedder.calls != 4 {
+		t.Errorf("embedder called %d times, want 4", embedder.calls)
+	}
+}
+
+func TestEmbeddingBackfill_WaitsOutRateLimits(t *testing.T) {
+	enum := &mockBackfillEnumerator{stores: map[string]*mockBackfillStore{"a": newMockBackfillStore(4)}}
+	// Rate limited more often than MaxAttempts allows for other errors.
+	embedder := &scriptedEmbedder{fail: func(call int, contents []string) error {
+		if call <= 5 {
+			return &openai.Error{StatusCode: 429}
+		}
+		return nil
+	}}
+
+	result, err := NewEmbeddingBackfill(enum, embedder, fastBackfill).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Embedded != 4 || result.Failed != 0 {
+		t.Errorf("Run() = %+v, want 4 embedded, 0 failed", result)
+	}
+	if embedder.calls != 6 {
+		t.Errorf("embedder called %d times, want 6", embedder.calls)
+	}
+}
+
+func TestEmbeddingBackfill_IsolatesFailingEntry(t *testing.T) {
+	s := newMockBackfillStore(4)
+	enum := &mockBackfillEnumerator{stores: map[string]*mockBackfillStore{"a": s}}
+	embedder := &scriptedEmbedder{fail: func(call int, contents []string) error {
+		for _, c := range contents {
+			if strings.HasSuffix(c, "entry-002") {
+				return fmt.Errorf("input too long")
+			}
+		}
+		return nil
+	}}
+
+	result, err := NewEmbeddingBackfill(enum, embedder, fastBackfill).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Embedded != 3 || result.Failed != 1 {
+		t.Errorf("Run() = %+v, want 3 embedded, 1 failed", result)
+	}
+	if len(s.failed) != 1 || s.failed[0] != "entry-002" {
+		t.Errorf("marked failed = %v, want [entry-002]", s.failed)
+	}
+}
+
+func TestEmbeddingBackfill_MarksRejectedEntriesFailed(t *testing.T) {
+	s := newMockBackfillStore(3)
+	enum := &mockBackfillEnumerator{stores: map[string]*mockBackfillStore{"a": s}}
+
+	result, err := NewEmbeddingBackfill(enum, &partialEmbedder{reject: "content entry-001"}, fastBackfill).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Embedded != 2 || result.Failed != 1 {
+		t.Errorf("Run() = %+v, want 2 embedded, 1 failed", result)
+	}
+	if len(s.failed) != 1 || s.failed[0] != "entry-001" {
+		t.Errorf("marked failed = %v, want [entry-001]", s.failed)
+	}
+}
+
+func TestEmbeddingBackfill_ProviderDownKeepsCheckpoint(t *testing.T) {
+	s := newMockBackfillStore(8)
+	enum := &mockBackfillEnumerator{stores: map[string]*mockBackfillStore{"a": s}}
+	// The first batch succeeds, then the provider goes down.
+	embedder := &scriptedEmbedder{fail: func(call int, contents []string) error {
+		if strings.HasSuffix(contents[0], "entry-000") {
+			return nil
+		}
+		return fmt.Errorf("connection refused")
+	}}
+
+	_, err := NewEmbeddingBackfill(enum, embedder, fastBackfill).Run(context.Background())
+	if err == nil {
+		t.Fatal("Run() error = nil, want provider failure")
+	}
+	if len(s.failed) != 0 {
+		t.Errorf("marked failed = %v, want none while the provider is down", s.failed)
+	}
+	if cursor := s.meta[backfillCursorKey]; cursor != "entry-003" {
+		t.Errorf("checkpoint = %q, want entry-003", cursor)
+	}
+}
+
+func TestEmbeddingBackfill_ResumesFromCheckpoint(t *testing.T) {
+	s := newMockBackfillStore(6)
+	s.meta[backfillCursorKey] = "entry-003"
+	enum := &mockBackfillEnumerator{stores: map[string]*mockBackfillStore{"a": s}}
+
+	result, err := NewEmbeddingBackfill(enum, &scriptedEmbedder{}, fastBackfill).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Embedded != 2 {
+		t.Errorf("embedded %d entries, want the 2 after the checkpoint", result.Embedded)
+	}
+	sort.Strings(s.embedded)
+	if len(s.embedded) != 2 || s.embedded[0] != "entry-004" || s.embedded[1] != "entry-005" {
+		t.Errorf("embedded = %v, want [entry-004 entry-005]", s.embedded)
+	}
+}
+
+func TestEmbeddingBackfill_Integration(t *testing.T) {
+	manager, err := multistore.NewStoreManager(filepath.Join(t.TempDir(), "stores"))
+	if err != nil {
+		t.Fatalf("NewStoreManager() error = %v", err)
+	}
+	defer manager.Close()
+	ctx := context.Background()
+
+	managed, err := manager.GetStore(ctx, "default")
+	if err != nil {
+		t.Fatalf("GetStore('default') error = %v", err)
+	}
+	entries := make([]types.NewLoreEntry, 10)
+	for i := range entries {
+		entries[i] = types.NewLoreEntry{
+			Content:    fmt.Sprintf("Imported lore %d", i),
+			Category:   "PATTERN_OUTCOME",
+			Confidence: 0.5,
+			SourceID:   "import",
+		}
+	}
+	if _, err := managed.Store.IngestLore(ctx, entries); err != nil {
+		t.Fatalf("IngestLore() error = %v", err)
+	}
+
+	backfill := NewEmbeddingBackfill(NewEmbeddingStoreManagerAdapter(manager), &scriptedEmbedder{}, fastBackfill)
+	result, err := backfill.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Embedded != 10 {
+		t.Errorf("embedded %d entries, want 10", result.Embedded)
+	}
+
+	remaining, err := managed.Store.(BackfillStore).CountEmbeddingBacklog(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remaining != 0 {
+		t.Errorf("backlog after backfill = %d, want 0", remaining)
+	}
+}
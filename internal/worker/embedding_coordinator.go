@@ -33,6 +33,8 @@ type EmbeddingRetryCoordinator struct {
 	maxAttempts int
 	batchSize   int
 
+	chunking Chunking
+
 	mu         sync.Mutex
 	retryCount map[string]map[string]int // storeID -> entryID -> count
 }
@@ -47,9 +49,10 @@ func NewEmbeddingStoreManagerAdapter(manager *multistore.StoreManager) *Embeddin
 	return &EmbeddingStoreManagerAdapter{manager: manager}
 }
 
-// ListStores returns all stores from the underlying StoreManager.
+// ListStores returns the active stores from the underlying StoreManager;
+// archived stores have nothing to work on until thawed.
 func (a *EmbeddingStoreManagerAdapter) ListStores(ctx context.Context) ([]multistore.StoreInfo, error) {
-	return a.manager.ListStores(ctx)
+	return a.manager.ListActiveStores(ctx)
 }
 
 // GetEmbeddingStore returns the store which implements EmbeddingCapableStore.
@@ -79,6 +82,12 @@ func NewEmbeddingRetryCoordinator(
 	}
 }
 
+// SetChunking enables multi-vector embedding of long entries: after an
+// entry is embedded, its chunks are embedded and stored as well.
+func (c *EmbeddingRetryCoordinator) SetChunking(chunking Chunking) {
+	c.chunking = chunking
+}
+
 // Run starts the embedding retry coordinator loop. It blocks until ctx is cancelled.
 //
 // Unlike DecayCoordinator which waits for the first tick, this coordinator processes
@@ -245,7 +254,7 @@ func (c *EmbeddingRetryCoordinator) processStore(ctx context.Context, storeID st
 	}
 
 	embeddings, err := c.embedder.EmbedBatch(ctx, contents)
-	if err != nil {
+	if err != nil && embeddings == nil {
 		slog.Warn("embedding batch failed, will retry",
 			"component", "worker",
 			"worker", "embedding-coordinator",
@@ -262,9 +271,25 @@ func (c *EmbeddingRetryCoordinator) processStore(ctx context.Context, storeID st
 		return false
 	}
 
+	if err != nil {
+		slog.Warn("embedding batch partially failed, will retry failed entries",
+			"component", "worker",
+			"worker", "embedding-coordinator",
+			"store_id", storeID,
+			"error", err,
+		)
+	}
+
 	// Update each entry with its embedding
 	var successCount int
+	var embedded []types.LoreEntry
 	for i, entry := range toProcess {
+		if i >= len(embeddings) || len(embeddings[i]) == 0 {
+			c.mu.Lock()
+			storeRetries[entry.ID]++
+			c.mu.Unlock()
+			continue
+		}
 		if err := store.UpdateEmbedding(ctx, entry.ID, embeddings[i]); err != nil {
 			slog.Error("failed to update embedding",
 				"component", "worker",
@@ -283,7 +308,9 @@ func (c *EmbeddingRetryCoordinator) processStore(ctx context.Context, storeID st
 		delete(storeRetries, entry.ID)
 		c.mu.Unlock()
 		successCount++
+		embedded = append(embedded, entry)
 	}
+	c.embedChunks(ctx, store, storeID, embedded)
 
 	if successCount > 0 {
 		slog.Info("processed pending embeddings",
@@ -325,3 +352,29 @@ func (c *EmbeddingRetryCoordinator) markAsFailed(ctx context.Context, store Embe
 	}
 	c.mu.Unlock()
 }
+
+// embedChunks stores chunk embeddings for the long entries among those just
+// embedded. It is best effort: an entry whose chunks fail keeps its single
+// embedding.
+func (c *EmbeddingRetryCoordinator) embedChunks(ctx context.Context, store EmbeddingCapableStore, storeID string, entries []types.LoreEntry) {
+	chunks, err := c.chunking.embed(ctx, c.embedder, entries)
+	if err != nil {
+		slog.Warn("chunk embedding failed, entries keep a single embedding",
+			"component", "worker",
+			"worker", "embedding-coordinator",
+			"store_id", storeID,
+			"error", err,
+		)
+	}
+	for id, vectors := range chunks {
+		if err := storeChunks(ctx, store, id, vectors); err != nil {
+			slog.Error("failed to store chunk embeddings",
+				"component", "worker",
+				"worker", "embedding-coordinator",
+				"store_id", storeID,
+				"lore_id", id,
+				"error", err,
+			)
+		}
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/hyperengineering/engram/internal/embedding"
 	"github.com/hyperengineering/engram/internal/multistore"
 	"github.com/hyperengineering/engram/internal/types"
 )
@@ -23,6 +25,7 @@ type mockEmbeddingCapableStore struct {
 	markFailedErr      error
 	updatedIDs         []string
 	failedIDs          []string
+	chunkCounts        map[string]int
 }
 
 func (m *mockEmbeddingCapableStore) GetPendingEmbeddings(ctx context.Context, limit int) ([]types.LoreEntry, error) {
@@ -56,6 +59,16 @@ func (m *mockEmbeddingCapableStore) UpdateEmbedding(ctx context.Context, id stri
 	return nil
 }
 
+func (m *mockEmbeddingCapableStore) UpdateChunkEmbeddings(ctx context.Context, id string, chunks [][]float32) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.chunkCounts == nil {
+		m.chunkCounts = make(map[string]int)
+	}
+	m.chunkCounts[id] = len(chunks)
+	return nil
+}
+
 func (m *mockEmbeddingCapableStore) MarkEmbeddingFailed(ctx context.Context, id string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -600,6 +613,73 @@ func TestEmbeddingRetryCoordinator_CleansUpOrphanedRetryCounts(t *testing.T) {
 	_ = storeAExists
 }
 
+// partialEmbedder embeds every content except reject, reporting it with a
+// *embedding.PartialError.
+type partialEmbedder struct {
+	reject string
+}
+
+func (p *partialEmbedder) EmbedBatch(ctx context.Context, contents []string) ([][]float32, error) {
+	result := make([][]float32, len(contents))
+	failed := make(map[int]error)
+	for i, c := range contents {
+		if c == p.reject {
+			failed[i] = errors.New("maximum context length exceeded")
+			continue
+		}
+		result[i] = []float32{1, 0}
+	}
+	if len(failed) > 0 {
+		return result, &embedding.PartialError{Failed: failed}
+	}
+	return result, nil
+}
+
+func TestEmbeddingRetryCoordinator_PartialBatchKeepsSuccesses(t *testing.T) {
+	enum := newMockEmbeddingStoreEnumerator("default")
+	enum.addPendingEntries("default",
+		types.LoreEntry{ID: "1", Content: "fine"},
+		types.LoreEntry{ID: "2", Content: "too long"},
+		types.LoreEntry{ID: "3", Content: "also fine"},
+	)
+	coord := NewEmbeddingRetryCoordinator(enum, &partialEmbedder{reject: "too long"}, time.Hour, 3, 10)
+
+	if !coord.processStore(context.Background(), "default") {
+		t.Fatal("processStore() = false, want true")
+	}
+
+	updated := enum.getStores["default"].getUpdatedIDs()
+	if len(updated) != 2 || updated[0] != "1" || updated[1] != "3" {
+		t.Errorf("updated IDs = %v, want [1 3]", updated)
+	}
+	if n := coord.retryCount["default"]["2"]; n != 1 {
+		t.Errorf("retry count for rejected entry = %d, want 1", n)
+	}
+}
+
+func TestEmbeddingRetryCoordinator_StoresChunkEmbeddings(t *testing.T) {
+	long := strings.Repeat("word ", 100)
+	enum := newMockEmbeddingStoreEnumerator("default")
+	enum.addPendingEntries("default",
+		types.LoreEntry{ID: "long", Content: long},
+		types.LoreEntry{ID: "short", Content: "short"},
+	)
+	coord := NewEmbeddingRetryCoordinator(enum, newMockCoordinatorEmbedder(), time.Hour, 3, 10)
+	coord.SetChunking(Chunking{Size: 100, Overlap: 20})
+
+	if !coord.processStore(context.Background(), "default") {
+		t.Fatal("processStore() = false, want true")
+	}
+
+	counts := enum.getStores["default"].chunkCounts
+	if want := len(embedding.Chunk(long, 100, 20)); counts["long"] != want {
+		t.Errorf("long entry has %d chunks, want %d", counts["long"], want)
+	}
+	if _, ok := counts["short"]; ok {
+		t.Error("short entry should not be chunked")
+	}
+}
+
 // --- Integration Tests ---
 // These tests use real StoreManager and SQLiteStores to verify
 // the adapter correctly wires through to the underlying stores.
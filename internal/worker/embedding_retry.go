@@ -101,7 +101,7 @@ func (w *EmbeddingRetryWorker) processPendingEmbeddings(ctx context.Context) {
 	}
 
 	embeddings, err := w.embedder.EmbedBatch(ctx, contents)
-	if err != nil {
+	if err != nil && embeddings == nil {
 		slog.Warn("embedding batch failed, will retry",
 			"error", err,
 			"count", len(toProcess),
@@ -114,9 +114,20 @@ func (w *EmbeddingRetryWorker) processPendingEmbeddings(ctx context.Context) {
 		return
 	}
 
+	if err != nil {
+		slog.Warn("embedding batch partially failed, will retry failed entries",
+			"error", err,
+			"component", "worker",
+		)
+	}
+
 	// Update each entry with its embedding
 	var successCount int
 	for i, entry := range toProcess {
+		if i >= len(embeddings) || len(embeddings[i]) == 0 {
+			w.retryCount[entry.ID]++
+			continue
+		}
 		if err := w.store.UpdateEmbedding(ctx, entry.ID, embeddings[i]); err != nil {
 			slog.Error("failed to update embedding",
 				"lore_id", entry.ID,
@@ -0,0 +1,365 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/eventbus"
+	"github.com/hyperengineering/engram/internal/multistore"
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+// EventOutboxStore defines the outbox operations required to relay
+// change_log entries to the event bus. Implemented by SQLiteStore.
+type EventOutboxStore interface {
+	EnableEventOutbox(ctx context.Context) error
+	DisableEventOutbox(ctx context.Context) error
+	PruneEventOutbox(ctx context.Context, cutoff time.Time) (int64, error)
+	FetchEventOutbox(ctx context.Context, limit int) ([]engramsync.OutboxEntry, error)
+	AckEventOutbox(ctx context.Context, throughID int64) error
+	RecordEventOutboxFailure(ctx context.Context, id int64, reason string) error
+}
+
+// EventOutboxStoreEnumerator provides access to stores for event publishing.
+type EventOutboxStoreEnumerator interface {
+	ListStores(ctx context.Context) ([]multistore.StoreInfo, error)
+	GetEventOutboxStore(ctx context.Context, storeID string) (EventOutboxStore, error)
+}
+
+// EventOutboxStoreManagerAdapter adapts multistore.StoreManager to EventOutboxStoreEnumerator.
+type EventOutboxStoreManagerAdapter struct {
+	manager *multistore.StoreManager
+}
+
+// NewEventOutboxStoreManagerAdapter creates an adapter for the given StoreManager.
+func NewEventOutboxStoreManagerAdapter(manager *multistore.StoreManager) *EventOutboxStoreManagerAdapter {
+	return &EventOutboxStoreManagerAdapter{manager: manager}
+}
+
+// ListStores returns the active stores from the underlying StoreManager;
+// archived stores have nothing to work on until thawed.
+func (a *EventOutboxStoreManagerAdapter) ListStores(ctx context.Context) ([]multistore.StoreInfo, error) {
+	return a.manager.ListActiveStores(ctx)
+}
+
+// GetEventOutboxStore returns the store for the given ID.
+func (a *EventOutboxStoreManagerAdapter) GetEventOutboxStore(ctx context.Context, storeID string) (EventOutboxStore, error) {
+	managed, err := a.manager.GetStore(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := managed.Store.(EventOutboxStore)
+	if !ok {
+		return nil, fmt.Errorf("store %q does not support the event outbox", storeID)
+	}
+	return s, nil
+}
+
+// outboxPruneInterval is how often a relayed store's outbox is checked for
+// entries older than the retention.
+const outboxPruneInterval = time.Minute
+
+// DisableEventOutboxes switches outbox capture off in every store and
+// discards entries still waiting, for when no event bus is configured.
+// Capture left on by an earlier configuration would otherwise fill the
+// outbox with entries nothing publishes.
+func DisableEventOutboxes(ctx context.Context, manager EventOutboxStoreEnumerator) {
+	stores, err := manager.ListStores(ctx)
+	if err != nil {
+		slog.Error("failed to list stores to disable event outbox",
+			"component", "worker",
+			"worker", "eventbus-coordinator",
+			"error", err,
+		)
+		return
+	}
+	for _, info := range stores {
+		disableEventOutbox(ctx, manager, info.ID)
+	}
+}
+
+// disableEventOutbox switches off outbox capture in one store. Returns
+// whether it succeeded.
+func disableEventOutbox(ctx context.Context, manager EventOutboxStoreEnumerator, storeID string) bool {
+	store, err := manager.GetEventOutboxStore(ctx, storeID)
+	if err == nil {
+		err = store.DisableEventOutbox(ctx)
+	}
+	if err != nil {
+		slog.Warn("failed to disable event outbox",
+			"component", "worker",
+			"worker", "eventbus-coordinator",
+			"store_id", storeID,
+			"error", err,
+		)
+		return false
+	}
+	return true
+}
+
+// EventBusCoordinator relays each store's event outbox to the event bus.
+// Entries are published in outbox order and removed only after the broker
+// acknowledges them. A failed publish stops the store's relay until the
+// next poll so ordering is preserved. Stores that are not relayed have
+// their outbox capture switched off.
+type EventBusCoordinator struct {
+	manager     EventOutboxStoreEnumerator
+	publisher   eventbus.Publisher
+	topicPrefix string
+	interval    time.Duration
+	batchSize   int
+	retention   time.Duration
+	stores      map[string]bool
+
+	// enabled tracks stores whose outbox capture has been switched on,
+	// and disabled those switched off. prunedAt records when each store's
+	// outbox was last pruned. Only accessed from the Run goroutine.
+	enabled  map[string]bool
+	disabled map[string]bool
+	prunedAt map[string]time.Time
+}
+
+// NewEventBusCoordinator creates an event bus relay. An empty stores list
+// publishes every store. Undelivered entries older than retention are
+// dropped; zero keeps them until they are delivered.
+func NewEventBusCoordinator(
+	manager EventOutboxStoreEnumerator,
+	publisher eventbus.Publisher,
+	topicPrefix string,
+	interval time.Duration,
+	batchSize int,
+	stores []string,
+	retention time.Duration,
+) *EventBusCoordinator {
+	c := &EventBusCoordinator{
+		manager:     manager,
+		publisher:   publisher,
+		topicPrefix: topicPrefix,
+		interval:    interval,
+		batchSize:   batchSize,
+		retention:   retention,
+		enabled:     make(map[string]bool),
+		disabled:    make(map[string]bool),
+		prunedAt:    make(map[string]time.Time),
+	}
+	if c.batchSize <= 0 {
+		c.batchSize = 100
+	}
+	if c.interval <= 0 {
+		c.interval = time.Second
+	}
+	if len(stores) > 0 {
+		c.stores = make(map[string]bool, len(stores))
+		for _, id := range stores {
+			c.stores[id] = true
+		}
+	}
+	return c
+}
+
+// Run starts the relay loop. Blocks until ctx is cancelled, then closes
+// the publisher. The first relay runs immediately so outbox capture is
+// enabled at startup.
+func (c *EventBusCoordinator) Run(ctx context.Context) {
+	slog.Info("event bus coordinator started",
+		"component", "worker",
+		"worker", "eventbus-coordinator",
+		"interval", c.interval.String(),
+		"topic_prefix", c.topicPrefix,
+	)
+	defer c.publisher.Close()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	c.relayAllStores(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("event bus coordinator stopped",
+				"component", "worker",
+				"worker", "eventbus-coordinator",
+				"reason", "context_cancelled",
+			)
+			return
+		case <-ticker.C:
+			c.relayAllStores(ctx)
+		}
+	}
+}
+
+// relayAllStores relays each selected store, continuing on individual failures.
+func (c *EventBusCoordinator) relayAllStores(ctx context.Context) {
+	stores, err := c.manager.ListStores(ctx)
+	if err != nil {
+		slog.Error("failed to list stores for event bus relay",
+			"component", "worker",
+			"worker", "eventbus-coordinator",
+			"error", err,
+		)
+		return
+	}
+
+	for _, info := range stores {
+		if ctx.Err() != nil {
+			return // Graceful shutdown
+		}
+		if c.stores != nil && !c.stores[info.ID] {
+			if !c.disabled[info.ID] {
+				c.disabled[info.ID] = disableEventOutbox(ctx, c.manager, info.ID)
+			}
+			continue
+		}
+		c.relayStore(ctx, info.ID)
+	}
+}
+
+// relayStore publishes a store's outbox until it is drained or a publish fails.
+// Returns the number of entries published.
+func (c *EventBusCoordinator) relayStore(ctx context.Context, storeID string) int {
+	store, err := c.manager.GetEventOutboxStore(ctx, storeID)
+	if err != nil {
+		slog.Warn("failed to get store for event bus relay",
+			"component", "worker",
+			"worker", "eventbus-coordinator",
+			"store_id", storeID,
+			"error", err,
+		)
+		return 0
+	}
+
+	if !c.enabled[storeID] {
+		if err := store.EnableEventOutbox(ctx); err != nil {
+			slog.Error("failed to enable event outbox",
+				"component", "worker",
+				"worker", "eventbus-coordinator",
+				"store_id", storeID,
+				"error", err,
+			)
+			return 0
+		}
+		c.enabled[storeID] = true
+	}
+
+	topic := eventbus.Topic(c.topicPrefix, storeID)
+	published := 0
+	for ctx.Err() == nil {
+		batch, err := store.FetchEventOutbox(ctx, c.batchSize)
+		if err != nil {
+			slog.Error("failed to read event outbox",
+				"component", "worker",
+				"worker", "eventbus-coordinator",
+				"store_id", storeID,
+				"error", err,
+			)
+			break
+		}
+
+		n, ok := c.publishBatch(ctx, store, storeID, topic, batch)
+		published += n
+		if !ok || len(batch) < c.batchSize {
+			break
+		}
+	}
+
+	c.pruneStore(ctx, store, storeID)
+
+	if published > 0 {
+		slog.Debug("event outbox relayed",
+			"component", "worker",
+			"worker", "eventbus-coordinator",
+			"store_id", storeID,
+			"topic", topic,
+			"published", published,
+		)
+	}
+	return published
+}
+
+// pruneStore drops the store's undelivered entries older than the
+// retention, at most once per outboxPruneInterval.
+func (c *EventBusCoordinator) pruneStore(ctx context.Context, store EventOutboxStore, storeID string) {
+	now := time.Now()
+	if c.retention <= 0 || ctx.Err() != nil || now.Sub(c.prunedAt[storeID]) < outboxPruneInterval {
+		return
+	}
+	c.prunedAt[storeID] = now
+
+	pruned, err := store.PruneEventOutbox(ctx, now.Add(-c.retention))
+	if err != nil {
+		slog.Warn("failed to prune event outbox",
+			"component", "worker",
+			"worker", "eventbus-coordinator",
+			"store_id", storeID,
+			"error", err,
+		)
+		return
+	}
+	if pruned > 0 {
+		slog.Warn("undelivered events dropped from event outbox",
+			"component", "worker",
+			"worker", "eventbus-coordinator",
+			"store_id", storeID,
+			"dropped", pruned,
+			"retention", c.retention.String(),
+		)
+	}
+}
+
+// publishBatch publishes entries in order and acknowledges those delivered.
+// Returns the number published and whether the whole batch succeeded.
+func (c *EventBusCoordinator) publishBatch(ctx context.Context, store EventOutboxStore, storeID, topic string, batch []engramsync.OutboxEntry) (int, bool) {
+	var lastID int64
+	published := 0
+	ok := true
+
+	for _, o := range batch {
+		value, err := json.Marshal(eventbus.Message{StoreID: storeID, ChangeLogEntry: o.Entry})
+		if err == nil {
+			err = c.publisher.Publish(ctx, topic, o.Entry.EntityID, value)
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Warn("event bus publish failed",
+					"component", "worker",
+					"worker", "eventbus-coordinator",
+					"store_id", storeID,
+					"topic", topic,
+					"sequence", o.Entry.Sequence,
+					"attempts", o.Attempts+1,
+					"error", err,
+				)
+				if recErr := store.RecordEventOutboxFailure(ctx, o.ID, err.Error()); recErr != nil {
+					slog.Warn("failed to record event outbox failure",
+						"component", "worker",
+						"worker", "eventbus-coordinator",
+						"store_id", storeID,
+						"error", recErr,
+					)
+				}
+			}
+			ok = false
+			break
+		}
+		lastID = o.ID
+		published++
+	}
+
+	if published > 0 {
+		if err := store.AckEventOutbox(ctx, lastID); err != nil {
+			// Entries will be published again; consumers deduplicate on sequence.
+			slog.Error("failed to acknowledge event outbox",
+				"component", "worker",
+				"worker", "eventbus-coordinator",
+				"store_id", storeID,
+				"error", err,
+			)
+			return published, false
+		}
+	}
+	return published, ok
+}
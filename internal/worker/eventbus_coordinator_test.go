@@ -0,0 +1,335 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/eventbus"
+	"github.com/hyperengineering/engram/internal/multistore"
+	engramsync "github.com/hyperengineering/engram/internal/sync"
+)
+
+// mockEventOutboxStore implements EventOutboxStore with an in-memory outbox.
+type mockEventOutboxStore struct {
+	mu           sync.Mutex
+	enableCalls  int
+	disableCalls int
+	pruneCutoffs []time.Time
+	outbox       []engramsync.OutboxEntry
+	failures     map[int64]string
+}
+
+func newMockEventOutboxStore(entityIDs ...string) *mockEventOutboxStore {
+	m := &mockEventOutboxStore{failures: make(map[int64]string)}
+	for i, id := range entityIDs {
+		m.outbox = append(m.outbox, engramsync.OutboxEntry{
+			ID: int64(i + 1),
+			Entry: engramsync.ChangeLogEntry{
+				Sequence:  int64(i + 1),
+				TableName: "lore_entries",
+				EntityID:  id,
+				Operation: engramsync.OperationUpsert,
+			},
+		})
+	}
+	return m
+}
+
+func (m *mockEventOutboxStore) EnableEventOutbox(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enableCalls++
+	return nil
+}
+
+func (m *mockEventOutboxStore) DisableEventOutbox(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.disableCalls++
+	m.outbox = nil
+	return nil
+}
+
+func (m *mockEventOutboxStore) PruneEventOutbox(ctx context.Context, cutoff time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneCutoffs = append(m.pruneCutoffs, cutoff)
+	return 0, nil
+}
+
+func (m *mockEventOutboxStore) FetchEventOutbox(ctx context.Context, limit int) ([]engramsync.OutboxEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := min(limit, len(m.outbox))
+	return append([]engramsync.OutboxEntry(nil), m.outbox[:n]...), nil
+}
+
+func (m *mockEventOutboxStore) AckEventOutbox(ctx context.Context, throughID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for len(m.outbox) > 0 && m.outbox[0].ID <= throughID {
+		m.outbox = m.outbox[1:]
+	}
+	return nil
+}
+
+func (m *mockEventOutboxStore) RecordEventOutboxFailure(ctx context.Context, id int64, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures[id] = reason
+	return nil
+}
+
+func (m *mockEventOutboxStore) remaining() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.outbox)
+}
+
+// mockEventOutboxStoreEnumerator implements EventOutboxStoreEnumerator for testing.
+type mockEventOutboxStoreEnumerator struct {
+	stores []multistore.StoreInfo
+	byID   map[string]*mockEventOutboxStore
+}
+
+func (m *mockEventOutboxStoreEnumerator) ListStores(ctx context.Context) ([]multistore.StoreInfo, error) {
+	return m.stores, nil
+}
+
+func (m *mockEventOutboxStoreEnumerator) GetEventOutboxStore(ctx context.Context, storeID string) (EventOutboxStore, error) {
+	if s, ok := m.byID[storeID]; ok {
+		return s, nil
+	}
+	return nil, errors.New("store not found")
+}
+
+func newMockEventOutboxEnumerator(stores map[string]*mockEventOutboxStore) *mockEventOutboxStoreEnumerator {
+	m := &mockEventOutboxStoreEnumerator{byID: stores}
+	for id := range stores {
+		m.stores = append(m.stores, multistore.StoreInfo{ID: id})
+	}
+	return m
+}
+
+type publishedMessage struct {
+	topic, key string
+	msg        eventbus.Message
+}
+
+// mockPublisher records published messages and fails once failAfter
+// messages have been accepted (when failAfter >= 0).
+type mockPublisher struct {
+	mu        sync.Mutex
+	published []publishedMessage
+	failAfter int
+	closed    bool
+}
+
+func (p *mockPublisher) Publish(ctx context.Context, topic, key string, value []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failAfter >= 0 && len(p.published) >= p.failAfter {
+		return errors.New("broker unavailable")
+	}
+	var msg eventbus.Message
+	if err := json.Unmarshal(value, &msg); err != nil {
+		return err
+	}
+	p.published = append(p.published, publishedMessage{topic: topic, key: key, msg: msg})
+	return nil
+}
+
+func (p *mockPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
+
+func (p *mockPublisher) messages() []publishedMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]publishedMessage(nil), p.published...)
+}
+
+// --- Tests ---
+
+func TestEventBusCoordinator_PublishesInOrderAndAcks(t *testing.T) {
+	store := newMockEventOutboxStore("a", "b", "c", "d", "e")
+	enum := newMockEventOutboxEnumerator(map[string]*mockEventOutboxStore{"org/project": store})
+	pub := &mockPublisher{failAfter: -1}
+	coord := NewEventBusCoordinator(enum, pub, "engram", time.Hour, 2, nil, 0)
+
+	if n := coord.relayStore(context.Background(), "org/project"); n != 5 {
+		t.Fatalf("relayStore() = %d, want 5", n)
+	}
+
+	msgs := pub.messages()
+	if len(msgs) != 5 {
+		t.Fatalf("expected 5 messages, got %d", len(msgs))
+	}
+	for i, m := range msgs {
+		if m.topic != "engram.org.project" {
+			t.Errorf("message %d topic = %q, want engram.org.project", i, m.topic)
+		}
+		if m.msg.StoreID != "org/project" || m.msg.Sequence != int64(i+1) || m.key != m.msg.EntityID {
+			t.Errorf("message %d = %+v (key %q), want store org/project sequence %d", i, m.msg, m.key, i+1)
+		}
+	}
+	if store.remaining() != 0 {
+		t.Errorf("expected outbox drained, %d remaining", store.remaining())
+	}
+	if store.enableCalls != 1 {
+		t.Errorf("EnableEventOutbox calls = %d, want 1", store.enableCalls)
+	}
+}
+
+func TestEventBusCoordinator_StopsOnFailureAndKeepsUnpublished(t *testing.T) {
+	store := newMockEventOutboxStore("a", "b", "c")
+	enum := newMockEventOutboxEnumerator(map[string]*mockEventOutboxStore{"default": store})
+	pub := &mockPublisher{failAfter: 1}
+	coord := NewEventBusCoordinator(enum, pub, "engram", time.Hour, 10, nil, 0)
+
+	if n := coord.relayStore(context.Background(), "default"); n != 1 {
+		t.Fatalf("relayStore() = %d, want 1", n)
+	}
+	if store.remaining() != 2 {
+		t.Errorf("expected 2 entries left in outbox, got %d", store.remaining())
+	}
+	if reason := store.failures[2]; reason == "" {
+		t.Error("expected failure recorded for entry 2")
+	}
+
+	// Broker recovers: the next relay resumes from the first unpublished entry.
+	pub.mu.Lock()
+	pub.failAfter = -1
+	pub.mu.Unlock()
+	coord.relayStore(context.Background(), "default")
+
+	msgs := pub.messages()
+	if len(msgs) != 3 || msgs[1].msg.Sequence != 2 || msgs[2].msg.Sequence != 3 {
+		t.Errorf("unexpected messages after recovery: %+v", msgs)
+	}
+	if store.enableCalls != 1 {
+		t.Errorf("EnableEventOutbox calls = %d, want 1", store.enableCalls)
+	}
+}
+
+func TestEventBusCoordinator_StoreFilter(t *testing.T) {
+	included := newMockEventOutboxStore("a")
+	excluded := newMockEventOutboxStore("b")
+	enum := newMockEventOutboxEnumerator(map[string]*mockEventOutboxStore{
+		"included": included,
+		"excluded": excluded,
+	})
+	pub := &mockPublisher{failAfter: -1}
+	coord := NewEventBusCoordinator(enum, pub, "engram", time.Hour, 10, []string{"included"}, 0)
+
+	coord.relayAllStores(context.Background())
+
+	if included.remaining() != 0 {
+		t.Error("expected included store to be relayed")
+	}
+	if excluded.enableCalls != 0 || excluded.disableCalls != 1 || excluded.remaining() != 0 {
+		t.Errorf("expected excluded store's outbox disabled, enable calls = %d, disable calls = %d",
+			excluded.enableCalls, excluded.disableCalls)
+	}
+
+	// Disabled once, not on every poll
+	coord.relayAllStores(context.Background())
+	if excluded.disableCalls != 1 {
+		t.Errorf("disable calls = %d after a second poll, want 1", excluded.disableCalls)
+	}
+}
+
+func TestEventBusCoordinator_PrunesStaleEntries(t *testing.T) {
+	store := newMockEventOutboxStore()
+	enum := newMockEventOutboxEnumerator(map[string]*mockEventOutboxStore{"default": store})
+	pub := &mockPublisher{failAfter: -1}
+	coord := NewEventBusCoordinator(enum, pub, "engram", time.Hour, 10, nil, 24*time.Hour)
+
+	before := time.Now()
+	coord.relayAllStores(context.Background())
+	coord.relayAllStores(context.Background())
+
+	if len(store.pruneCutoffs) != 1 {
+		t.Fatalf("prune calls = %d, want 1 within the prune interval", len(store.pruneCutoffs))
+	}
+	if cutoff := store.pruneCutoffs[0]; cutoff.After(before.Add(-24*time.Hour).Add(time.Minute)) || cutoff.Before(before.Add(-25*time.Hour)) {
+		t.Errorf("prune cutoff = %v, want about 24h ago", cutoff)
+	}
+}
+
+func TestDisableEventOutboxes(t *testing.T) {
+	a := newMockEventOutboxStore("x")
+	b := newMockEventOutboxStore()
+	enum := newMockEventOutboxEnumerator(map[string]*mockEventOutboxStore{"a": a, "b": b})
+
+	DisableEventOutboxes(context.Background(), enum)
+
+	if a.disableCalls != 1 || b.disableCalls != 1 || a.remaining() != 0 {
+		t.Errorf("disable calls = %d, %d, remaining = %d; want every store disabled and emptied",
+			a.disableCalls, b.disableCalls, a.remaining())
+	}
+}
+
+func TestEventBusCoordinator_RunClosesPublisherOnCancel(t *testing.T) {
+	store := newMockEventOutboxStore("a")
+	enum := newMockEventOutboxEnumerator(map[string]*mockEventOutboxStore{"default": store})
+	pub := &mockPublisher{failAfter: -1}
+	coord := NewEventBusCoordinator(enum, pub, "engram", time.Hour, 10, nil, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		coord.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.After(2 * time.Second)
+	for store.remaining() != 0 {
+		select {
+		case <-deadline:
+			t.Fatal("Timed out waiting for initial relay")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not stop after cancel")
+	}
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+	if !pub.closed {
+		t.Error("expected publisher to be closed on shutdown")
+	}
+}
+
+// --- Integration Tests ---
+
+func TestEventOutboxStoreManagerAdapter_ReturnsSQLiteStore(t *testing.T) {
+	manager, err := multistore.NewStoreManager(filepath.Join(t.TempDir(), "stores"))
+	if err != nil {
+		t.Fatalf("NewStoreManager() error = %v", err)
+	}
+	defer manager.Close()
+
+	ctx := context.Background()
+	adapter := NewEventOutboxStoreManagerAdapter(manager)
+	store, err := adapter.GetEventOutboxStore(ctx, "default")
+	if err != nil {
+		t.Fatalf("GetEventOutboxStore failed: %v", err)
+	}
+
+	if err := store.EnableEventOutbox(ctx); err != nil {
+		t.Errorf("EnableEventOutbox failed: %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/multistore"
+)
+
+// IdempotencyCleanupStore defines operations required to bound the push
+// idempotency cache and discard abandoned push sessions.
+// Implemented by SQLiteStore.
+type IdempotencyCleanupStore interface {
+	CleanExpiredIdempotency(ctx context.Context) (int64, error)
+	EnforceIdempotencyLimit(ctx context.Context, maxEntries int) (int64, error)
+	CleanExpiredPushSessions(ctx context.Context) (int64, error)
+}
+
+// IdempotencyStoreEnumerator provides access to stores for idempotency cleanup.
+type IdempotencyStoreEnumerator interface {
+	ListStores(ctx context.Context) ([]multistore.StoreInfo, error)
+	GetIdempotencyStore(ctx context.Context, storeID string) (IdempotencyCleanupStore, error)
+}
+
+// IdempotencyStoreManagerAdapter adapts multistore.StoreManager to IdempotencyStoreEnumerator.
+type IdempotencyStoreManagerAdapter struct {
+	manager *multistore.StoreManager
+}
+
+// NewIdempotencyStoreManagerAdapter creates an adapter for the given StoreManager.
+func NewIdempotencyStoreManagerAdapter(manager *multistore.StoreManager) *IdempotencyStoreManagerAdapter {
+	return &IdempotencyStoreManagerAdapter{manager: manager}
+}
+
+// ListStores returns the active stores from the underlying StoreManager;
+// archived stores have nothing to work on until thawed.
+func (a *IdempotencyStoreManagerAdapter) ListStores(ctx context.Context) ([]multistore.StoreInfo, error) {
+	return a.manager.ListActiveStores(ctx)
+}
+
+// GetIdempotencyStore returns the store for the given ID.
+func (a *IdempotencyStoreManagerAdapter) GetIdempotencyStore(ctx context.Context, storeID string) (IdempotencyCleanupStore, error) {
+	managed, err := a.manager.GetStore(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := managed.Store.(IdempotencyCleanupStore)
+	if !ok {
+		return nil, fmt.Errorf("store %q does not support idempotency cleanup", storeID)
+	}
+	return s, nil
+}
+
+// IdempotencyCoordinator periodically removes expired idempotency entries
+// and push sessions across all stores, and caps each store's idempotency
+// cache at maxEntries by evicting the least recently used entries.
+type IdempotencyCoordinator struct {
+	manager    IdempotencyStoreEnumerator
+	interval   time.Duration
+	maxEntries int
+}
+
+// NewIdempotencyCoordinator creates an idempotency cleanup coordinator.
+// A maxEntries of zero disables the cap; expiry cleanup still runs.
+func NewIdempotencyCoordinator(
+	manager IdempotencyStoreEnumerator,
+	interval time.Duration,
+	maxEntries int,
+) *IdempotencyCoordinator {
+	return &IdempotencyCoordinator{
+		manager:    manager,
+		interval:   interval,
+		maxEntries: maxEntries,
+	}
+}
+
+// Run starts the coordinator loop. Blocks until ctx is cancelled.
+// The first cleanup runs after one interval.
+func (c *IdempotencyCoordinator) Run(ctx context.Context) {
+	slog.Info("idempotency coordinator started",
+		"component", "worker",
+		"worker", "idempotency-coordinator",
+		"interval", c.interval.String(),
+		"max_entries", c.maxEntries,
+	)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("idempotency coordinator stopped",
+				"component", "worker",
+				"worker", "idempotency-coordinator",
+				"reason", "context_cancelled",
+			)
+			return
+		case <-ticker.C:
+			c.cleanAllStores(ctx)
+		}
+	}
+}
+
+// cleanAllStores runs cleanup on each store, continuing on individual failures.
+func (c *IdempotencyCoordinator) cleanAllStores(ctx context.Context) {
+	stores, err := c.manager.ListStores(ctx)
+	if err != nil {
+		slog.Error("failed to list stores for idempotency cleanup",
+			"component", "worker",
+			"worker", "idempotency-coordinator",
+			"error", err,
+		)
+		return
+	}
+
+	var failed int
+	var totalExpired, totalEvicted, totalSessions int64
+
+	for _, info := range stores {
+		if ctx.Err() != nil {
+			return // Graceful shutdown
+		}
+
+		expired, evicted, sessions, ok := c.cleanStore(ctx, info.ID)
+		if !ok {
+			failed++
+			continue
+		}
+		totalExpired += expired
+		totalEvicted += evicted
+		totalSessions += sessions
+	}
+
+	if totalExpired > 0 || totalEvicted > 0 || totalSessions > 0 || failed > 0 {
+		slog.Info("idempotency cleanup cycle completed",
+			"component", "worker",
+			"worker", "idempotency-coordinator",
+			"stores_total", len(stores),
+			"stores_failed", failed,
+			"entries_expired", totalExpired,
+			"entries_evicted", totalEvicted,
+			"push_sessions_expired", totalSessions,
+		)
+	}
+}
+
+// cleanStore runs cleanup for a single store.
+// Returns: expired entries, evicted entries, expired push sessions, success.
+func (c *IdempotencyCoordinator) cleanStore(ctx context.Context, storeID string) (int64, int64, int64, bool) {
+	store, err := c.manager.GetIdempotencyStore(ctx, storeID)
+	if err != nil {
+		slog.Warn("failed to get store for idempotency cleanup",
+			"component", "worker",
+			"worker", "idempotency-coordinator",
+			"store_id", storeID,
+			"error", err,
+		)
+		return 0, 0, 0, false
+	}
+
+	fail := func(step string, err error) (int64, int64, int64, bool) {
+		if ctx.Err() == nil {
+			slog.Error("idempotency cleanup failed for store",
+				"component", "worker",
+				"worker", "idempotency-coordinator",
+				"store_id", storeID,
+				"step", step,
+				"error", err,
+			)
+		}
+		return 0, 0, 0, false
+	}
+
+	expired, err := store.CleanExpiredIdempotency(ctx)
+	if err != nil {
+		return fail("expire", err)
+	}
+	evicted, err := store.EnforceIdempotencyLimit(ctx, c.maxEntries)
+	if err != nil {
+		return fail("evict", err)
+	}
+	sessions, err := store.CleanExpiredPushSessions(ctx)
+	if err != nil {
+		return fail("push_sessions", err)
+	}
+
+	return expired, evicted, sessions, true
+}
@@ -0,0 +1,210 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperengineering/engram/internal/multistore"
+)
+
+// mockIdempotencyCleanupStore implements IdempotencyCleanupStore for testing.
+type mockIdempotencyCleanupStore struct {
+	mu             sync.Mutex
+	cleanCalls     int
+	cleanErr       error
+	lastMaxEntries int
+	evictCalls     int
+	sessionCalls   int
+}
+
+func (m *mockIdempotencyCleanupStore) CleanExpiredIdempotency(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cleanCalls++
+	if m.cleanErr != nil {
+		return 0, m.cleanErr
+	}
+	return 2, nil
+}
+
+func (m *mockIdempotencyCleanupStore) EnforceIdempotencyLimit(ctx context.Context, maxEntries int) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.evictCalls++
+	m.lastMaxEntries = maxEntries
+	return 1, nil
+}
+
+func (m *mockIdempotencyCleanupStore) CleanExpiredPushSessions(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessionCalls++
+	return 0, nil
+}
+
+func (m *mockIdempotencyCleanupStore) calls() (clean, evict, sessions, maxEntries int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cleanCalls, m.evictCalls, m.sessionCalls, m.lastMaxEntries
+}
+
+// mockIdempotencyStoreEnumerator implements IdempotencyStoreEnumerator for testing.
+type mockIdempotencyStoreEnumerator struct {
+	mu      sync.Mutex
+	stores  []multistore.StoreInfo
+	listErr error
+	byID    map[string]*mockIdempotencyCleanupStore
+	getErr  map[string]error
+}
+
+func newMockIdempotencyStoreEnumerator(storeIDs ...string) *mockIdempotencyStoreEnumerator {
+	m := &mockIdempotencyStoreEnumerator{
+		byID:   make(map[string]*mockIdempotencyCleanupStore),
+		getErr: make(map[string]error),
+	}
+	for _, id := range storeIDs {
+		m.stores = append(m.stores, multistore.StoreInfo{ID: id})
+		m.byID[id] = &mockIdempotencyCleanupStore{}
+	}
+	return m
+}
+
+func (m *mockIdempotencyStoreEnumerator) ListStores(ctx context.Context) ([]multistore.StoreInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.listErr != nil {
+		return nil, m.listErr
+	}
+	return m.stores, nil
+}
+
+func (m *mockIdempotencyStoreEnumerator) GetIdempotencyStore(ctx context.Context, storeID string) (IdempotencyCleanupStore, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.getErr[storeID]; err != nil {
+		return nil, err
+	}
+	if s, ok := m.byID[storeID]; ok {
+		return s, nil
+	}
+	return nil, errors.New("store not found")
+}
+
+// waitForCleanCalls waits until totalCalls expiry cleanups have occurred.
+func (m *mockIdempotencyStoreEnumerator) waitForCleanCalls(totalCalls int, timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	for {
+		current := 0
+		for _, s := range m.byID {
+			clean, _, _, _ := s.calls()
+			current += clean
+		}
+		if current >= totalCalls {
+			return true
+		}
+
+		select {
+		case <-deadline:
+			return false
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func runIdempotencyCoordinator(t *testing.T, coord *IdempotencyCoordinator, until func() bool) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		coord.Run(ctx)
+		close(done)
+	}()
+
+	ok := until()
+	cancel()
+	<-done
+	if !ok {
+		t.Fatal("Timed out waiting for idempotency cleanup")
+	}
+}
+
+// --- Tests ---
+
+func TestIdempotencyCoordinator_CleansAllStores(t *testing.T) {
+	enum := newMockIdempotencyStoreEnumerator("default", "project-a")
+	coord := NewIdempotencyCoordinator(enum, 20*time.Millisecond, 500)
+
+	runIdempotencyCoordinator(t, coord, func() bool {
+		return enum.waitForCleanCalls(2, 2*time.Second)
+	})
+
+	for _, id := range []string{"default", "project-a"} {
+		clean, evict, sessions, maxEntries := enum.byID[id].calls()
+		if clean < 1 || evict < 1 || sessions < 1 {
+			t.Errorf("store %q: clean=%d evict=%d sessions=%d, want all >= 1", id, clean, evict, sessions)
+		}
+		if maxEntries != 500 {
+			t.Errorf("store %q: maxEntries = %d, want 500", id, maxEntries)
+		}
+	}
+}
+
+func TestIdempotencyCoordinator_DoesNotRunImmediately(t *testing.T) {
+	enum := newMockIdempotencyStoreEnumerator("default")
+	coord := NewIdempotencyCoordinator(enum, time.Hour, 500)
+
+	runIdempotencyCoordinator(t, coord, func() bool {
+		time.Sleep(50 * time.Millisecond)
+		return true
+	})
+
+	if clean, _, _, _ := enum.byID["default"].calls(); clean != 0 {
+		t.Errorf("Expected 0 cleanup calls before first tick, got %d", clean)
+	}
+}
+
+func TestIdempotencyCoordinator_ContinuesOnError(t *testing.T) {
+	enum := newMockIdempotencyStoreEnumerator("store-a", "store-b", "store-c")
+	enum.byID["store-b"].cleanErr = errors.New("database locked")
+	enum.getErr["store-c"] = errors.New("store deleted")
+	coord := NewIdempotencyCoordinator(enum, 20*time.Millisecond, 500)
+
+	runIdempotencyCoordinator(t, coord, func() bool {
+		return enum.waitForCleanCalls(2, 2*time.Second)
+	})
+
+	if _, evict, _, _ := enum.byID["store-a"].calls(); evict < 1 {
+		t.Errorf("Expected store-a to be evicted, got %d calls", evict)
+	}
+	if _, evict, _, _ := enum.byID["store-b"].calls(); evict != 0 {
+		t.Errorf("Expected store-b to stop after expiry failure, got %d evict calls", evict)
+	}
+	if clean, _, _, _ := enum.byID["store-c"].calls(); clean != 0 {
+		t.Errorf("Expected store-c to be skipped, got %d calls", clean)
+	}
+}
+
+// --- Integration Tests ---
+
+func TestIdempotencyStoreManagerAdapter_ReturnsSQLiteStore(t *testing.T) {
+	manager, err := multistore.NewStoreManager(filepath.Join(t.TempDir(), "stores"))
+	if err != nil {
+		t.Fatalf("NewStoreManager() error = %v", err)
+	}
+	defer manager.Close()
+
+	ctx := context.Background()
+	adapter := NewIdempotencyStoreManagerAdapter(manager)
+	store, err := adapter.GetIdempotencyStore(ctx, "default")
+	if err != nil {
+		t.Fatalf("GetIdempotencyStore failed: %v", err)
+	}
+
+	if _, err := store.EnforceIdempotencyLimit(ctx, 10); err != nil {
+		t.Errorf("EnforceIdempotencyLimit failed: %v", err)
+	}
+}
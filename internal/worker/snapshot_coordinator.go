@@ -3,10 +3,12 @@ package worker
 import (
 	"context"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/hyperengineering/engram/internal/multistore"
 	"github.com/hyperengineering/engram/internal/snapshot"
+	"github.com/hyperengineering/engram/internal/webhook"
 )
 
 // StoreEnumerator provides access to all managed stores.
@@ -32,9 +34,10 @@ func NewStoreManagerAdapter(manager *multistore.StoreManager) *StoreManagerAdapt
 	return &StoreManagerAdapter{manager: manager}
 }
 
-// ListStores returns all stores from the underlying StoreManager.
+// ListStores returns the active stores from the underlying StoreManager;
+// archived stores have nothing to work on until thawed.
 func (a *StoreManagerAdapter) ListStores(ctx context.Context) ([]multistore.StoreInfo, error) {
-	return a.manager.ListStores(ctx)
+	return a.manager.ListActiveStores(ctx)
 }
 
 // GetStore returns the store's underlying Store which implements SnapshotCapableStore.
@@ -46,11 +49,26 @@ func (a *StoreManagerAdapter) GetStore(ctx context.Context, storeID string) (Sna
 	return managed.Store, nil
 }
 
+// Defaults for deferring uploads while the server is under pressure.
+const (
+	// defaultUploadGatePoll is how often a deferred upload rechecks the gate.
+	defaultUploadGatePoll = 10 * time.Second
+	// defaultUploadGateMaxWait bounds how long an upload is deferred, so
+	// sustained pressure delays uploads rather than stopping them.
+	defaultUploadGateMaxWait = 15 * time.Minute
+)
+
 // SnapshotCoordinator generates snapshots for all managed stores.
 type SnapshotCoordinator struct {
 	manager  StoreEnumerator
 	uploader snapshot.Uploader
 	interval time.Duration
+	notifier webhook.Notifier
+
+	uploadConcurrency int
+	uploadGate        func(ctx context.Context) bool
+	gatePoll          time.Duration
+	gateMaxWait       time.Duration
 }
 
 // NewSnapshotCoordinator creates a coordinator that generates snapshots
@@ -62,12 +80,37 @@ func NewSnapshotCoordinator(
 	uploader snapshot.Uploader,
 ) *SnapshotCoordinator {
 	return &SnapshotCoordinator{
-		manager:  manager,
-		uploader: uploader,
-		interval: interval,
+		manager:           manager,
+		uploader:          uploader,
+		interval:          interval,
+		uploadConcurrency: 1,
+		gatePoll:          defaultUploadGatePoll,
+		gateMaxWait:       defaultUploadGateMaxWait,
 	}
 }
 
+// SetUploadConcurrency sets how many uploads run at once. Uploads run
+// alongside the generation of the next stores' snapshots; generation waits
+// while n uploads are in flight. Values below 1 mean 1.
+// Must be called before Run.
+func (c *SnapshotCoordinator) SetUploadConcurrency(n int) {
+	c.uploadConcurrency = max(n, 1)
+}
+
+// SetUploadGate defers uploads while healthy reports false, such as while
+// the API is shedding load, rechecking it periodically. An upload deferred
+// for defaultUploadGateMaxWait proceeds anyway.
+// Must be called before Run.
+func (c *SnapshotCoordinator) SetUploadGate(healthy func(ctx context.Context) bool) {
+	c.uploadGate = healthy
+}
+
+// SetNotifier enables snapshot.completed webhook events.
+// Must be called before Run.
+func (c *SnapshotCoordinator) SetNotifier(n webhook.Notifier) {
+	c.notifier = n
+}
+
 // Run starts the coordinator loop.
 func (c *SnapshotCoordinator) Run(ctx context.Context) {
 	slog.Info("worker started",
@@ -111,17 +154,41 @@ func (c *SnapshotCoordinator) generateAllSnapshots(ctx context.Context) {
 		return
 	}
 
+	// Uploads run in the background, at most uploadConcurrency at once,
+	// and the cycle ends when the last one does.
+	var uploads sync.WaitGroup
+	slots := make(chan struct{}, c.uploadConcurrency)
+	defer uploads.Wait()
+
 	var succeeded, failed int
 	for _, storeInfo := range stores {
 		if ctx.Err() != nil {
 			return // Graceful shutdown, don't log summary
 		}
-		if c.generateStoreSnapshot(ctx, storeInfo.ID) {
-			succeeded++
-		} else {
+		store, ok := c.generateStoreSnapshot(ctx, storeInfo.ID)
+		if !ok {
 			failed++
+			continue
+		}
+		succeeded++
+
+		if _, noop := c.uploader.(*snapshot.NoopUploader); c.uploader == nil || noop {
+			c.notifySnapshot(storeInfo.ID, false)
+			continue
 		}
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		uploads.Add(1)
+		go func(storeID string) {
+			defer uploads.Done()
+			defer func() { <-slots }()
+			c.uploadAndNotify(ctx, store, storeID)
+		}(storeInfo.ID)
 	}
+	uploads.Wait()
 
 	// Log summary only if we processed stores (not during shutdown)
 	if succeeded > 0 || failed > 0 {
@@ -137,8 +204,8 @@ func (c *SnapshotCoordinator) generateAllSnapshots(ctx context.Context) {
 }
 
 // generateStoreSnapshot generates a snapshot for a single store.
-// Returns true if successful, false if failed.
-func (c *SnapshotCoordinator) generateStoreSnapshot(ctx context.Context, storeID string) bool {
+// Returns the store and true if successful, false if failed.
+func (c *SnapshotCoordinator) generateStoreSnapshot(ctx context.Context, storeID string) (SnapshotCapableStore, bool) {
 	slog.Info("snapshot generation started",
 		"component", "worker",
 		"worker", "snapshot-coordinator",
@@ -155,12 +222,12 @@ func (c *SnapshotCoordinator) generateStoreSnapshot(ctx context.Context, storeID
 			"store_id", storeID,
 			"error", err,
 		)
-		return false
+		return nil, false
 	}
 
 	if err := store.GenerateSnapshot(ctx); err != nil {
 		if ctx.Err() != nil {
-			return false // Graceful shutdown, don't log as error
+			return nil, false // Graceful shutdown, don't log as error
 		}
 		slog.Warn("snapshot generation failed",
 			"component", "worker",
@@ -169,20 +236,71 @@ func (c *SnapshotCoordinator) generateStoreSnapshot(ctx context.Context, storeID
 			"store_id", storeID,
 			"error", err,
 		)
-		return false
+		return nil, false
 	}
+	return store, true
+}
 
-	// Upload to S3 if configured (non-fatal on failure)
-	if c.uploader != nil {
-		c.uploadSnapshot(ctx, store, storeID)
+// uploadAndNotify uploads a generated snapshot to S3 once the upload gate
+// allows, then sends snapshot.completed. Upload failures are not fatal.
+func (c *SnapshotCoordinator) uploadAndNotify(ctx context.Context, store SnapshotCapableStore, storeID string) {
+	if !c.waitForUploadGate(ctx, storeID) {
+		return
 	}
+	c.notifySnapshot(storeID, c.uploadSnapshot(ctx, store, storeID))
+}
 
-	return true
+// waitForUploadGate blocks while the upload gate reports the server
+// unhealthy, for at most gateMaxWait. It returns false if ctx ends first.
+func (c *SnapshotCoordinator) waitForUploadGate(ctx context.Context, storeID string) bool {
+	if c.uploadGate == nil || c.uploadGate(ctx) {
+		return true
+	}
+	slog.Info("snapshot upload deferred while server is under pressure",
+		"component", "worker",
+		"worker", "snapshot-coordinator",
+		"action", "snapshot_upload_deferred",
+		"store_id", storeID,
+	)
+
+	deadline := time.NewTimer(c.gateMaxWait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(c.gatePoll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-deadline.C:
+			slog.Warn("snapshot upload proceeding despite server pressure",
+				"component", "worker",
+				"worker", "snapshot-coordinator",
+				"action", "snapshot_upload_forced",
+				"store_id", storeID,
+				"waited", c.gateMaxWait.String(),
+			)
+			return true
+		case <-ticker.C:
+			if c.uploadGate(ctx) {
+				return true
+			}
+		}
+	}
+}
+
+// notifySnapshot sends snapshot.completed for a generated snapshot.
+func (c *SnapshotCoordinator) notifySnapshot(storeID string, uploaded bool) {
+	if c.notifier != nil {
+		c.notifier.Notify(webhook.NewEvent(webhook.EventSnapshotCompleted, storeID, webhook.SnapshotData{
+			Uploaded: uploaded,
+		}))
+	}
 }
 
 // uploadSnapshot uploads the generated snapshot to S3.
 // Upload failures are logged as warnings but are NOT fatal — local snapshot remains valid.
-func (c *SnapshotCoordinator) uploadSnapshot(ctx context.Context, store SnapshotCapableStore, storeID string) {
+// Returns true if the snapshot was uploaded.
+func (c *SnapshotCoordinator) uploadSnapshot(ctx context.Context, store SnapshotCapableStore, storeID string) bool {
 	path, err := store.GetSnapshotPath(ctx)
 	if err != nil {
 		slog.Warn("failed to get snapshot path for upload",
@@ -192,7 +310,7 @@ func (c *SnapshotCoordinator) uploadSnapshot(ctx context.Context, store Snapshot
 			"store_id", storeID,
 			"error", err,
 		)
-		return
+		return false
 	}
 
 	if err := c.uploader.Upload(ctx, storeID, path); err != nil {
@@ -203,7 +321,7 @@ func (c *SnapshotCoordinator) uploadSnapshot(ctx context.Context, store Snapshot
 			"store_id", storeID,
 			"error", err,
 		)
-		return
+		return false
 	}
 
 	slog.Info("snapshot uploaded to S3",
@@ -212,4 +330,5 @@ func (c *SnapshotCoordinator) uploadSnapshot(ctx context.Context, store Snapshot
 		"action", "snapshot_uploaded",
 		"store_id", storeID,
 	)
+	return true
 }
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/hyperengineering/engram/internal/multistore"
+	"github.com/hyperengineering/engram/internal/webhook"
 )
 
 // mockStoreEnumerator implements StoreEnumerator for testing.
@@ -765,3 +766,138 @@ func TestSnapshotCoordinator_NilUploaderSkipsUpload(t *testing.T) {
 		t.Errorf("Expected at least 1 GenerateSnapshot call, got %d", calls)
 	}
 }
+
+func TestSnapshotCoordinator_NotifiesOnCompletion(t *testing.T) {
+	enum := newMockStoreEnumerator("store-a", "store-b")
+	enum.setStoreError("store-b", errors.New("disk full"))
+	notifier := &recordingNotifier{}
+
+	coord := NewSnapshotCoordinator(enum, 1*time.Hour, &mockUploader{})
+	coord.SetNotifier(notifier)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		coord.Run(ctx)
+		close(done)
+	}()
+
+	if !enum.waitForCalls(2, 2*time.Second) {
+		t.Fatal("Timed out waiting for snapshot generation")
+	}
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	events := notifier.snapshot()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 snapshot.completed event (store-b failed), got %d", len(events))
+	}
+	if events[0].Type != webhook.EventSnapshotCompleted || events[0].StoreID != "store-a" {
+		t.Errorf("unexpected event: %+v", events[0])
+	}
+	if data, _ := events[0].Data.(webhook.SnapshotData); !data.Uploaded {
+		t.Error("expected Uploaded = true")
+	}
+}
+
+// slowUploader records how many uploads run at once.
+type slowUploader struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	done     int
+}
+
+func (u *slowUploader) Upload(ctx context.Context, storeID string, filePath string) error {
+	u.mu.Lock()
+	u.inFlight++
+	u.peak = max(u.peak, u.inFlight)
+	u.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	u.mu.Lock()
+	u.inFlight--
+	u.done++
+	u.mu.Unlock()
+	return nil
+}
+
+func (u *slowUploader) PresignedURL(ctx context.Context, storeID string) (string, time.Time, error) {
+	return "", time.Time{}, nil
+}
+
+func TestSnapshotCoordinator_UploadConcurrency(t *testing.T) {
+	enum := newMockStoreEnumerator("store-a", "store-b", "store-c", "store-d", "store-e")
+	uploader := &slowUploader{}
+	coord := NewSnapshotCoordinator(enum, 1*time.Hour, uploader)
+	coord.SetUploadConcurrency(2)
+
+	coord.generateAllSnapshots(context.Background())
+
+	uploader.mu.Lock()
+	defer uploader.mu.Unlock()
+	if uploader.done != 5 {
+		t.Errorf("uploads completed = %d, want 5 before the cycle ends", uploader.done)
+	}
+	if uploader.peak != 2 {
+		t.Errorf("peak concurrent uploads = %d, want 2", uploader.peak)
+	}
+}
+
+func TestSnapshotCoordinator_UploadGateDefersUploads(t *testing.T) {
+	enum := newMockStoreEnumerator("store-a")
+	uploader := &mockUploader{}
+	coord := NewSnapshotCoordinator(enum, 1*time.Hour, uploader)
+	coord.gatePoll = 5 * time.Millisecond
+
+	var mu sync.Mutex
+	healthy := false
+	coord.SetUploadGate(func(context.Context) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return healthy
+	})
+
+	done := make(chan struct{})
+	go func() {
+		coord.generateAllSnapshots(context.Background())
+		close(done)
+	}()
+
+	if !enum.waitForCalls(1, 2*time.Second) {
+		t.Fatal("Timed out waiting for snapshot generation")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if calls := uploader.getUploadCalls(); calls != 0 {
+		t.Fatalf("upload ran under pressure: %d calls", calls)
+	}
+
+	mu.Lock()
+	healthy = true
+	mu.Unlock()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("cycle didn't finish once the server was healthy")
+	}
+	if calls := uploader.getUploadCalls(); calls != 1 {
+		t.Errorf("expected 1 upload once healthy, got %d", calls)
+	}
+}
+
+func TestSnapshotCoordinator_UploadGateMaxWait(t *testing.T) {
+	enum := newMockStoreEnumerator("store-a")
+	uploader := &mockUploader{}
+	coord := NewSnapshotCoordinator(enum, 1*time.Hour, uploader)
+	coord.gatePoll = 5 * time.Millisecond
+	coord.gateMaxWait = 20 * time.Millisecond
+	coord.SetUploadGate(func(context.Context) bool { return false })
+
+	coord.generateAllSnapshots(context.Background())
+
+	if calls := uploader.getUploadCalls(); calls != 1 {
+		t.Errorf("expected the upload to proceed after the maximum wait, got %d calls", calls)
+	}
+}
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Multi-part push sessions
+-- A client begins a session with a push_id, appends entries in chunks,
+-- then commits them as a single all-or-nothing push.
+CREATE TABLE push_sessions (
+    push_id                TEXT    PRIMARY KEY,
+    source_id              TEXT    NOT NULL,
+    schema_version         INTEGER NOT NULL,
+    allow_deferred_parents INTEGER NOT NULL DEFAULT 0,
+    cascade_deletes        INTEGER NOT NULL DEFAULT 0,
+    entry_count            INTEGER NOT NULL DEFAULT 0,
+    created_at             TEXT    NOT NULL,
+    expires_at             TEXT    NOT NULL
+);
+
+-- Index for TTL cleanup: DELETE FROM push_sessions WHERE expires_at < ?
+CREATE INDEX idx_push_sessions_expires ON push_sessions (expires_at);
+
+-- Staged entries, kept in append order
+CREATE TABLE push_session_entries (
+    id      INTEGER PRIMARY KEY AUTOINCREMENT,
+    push_id TEXT    NOT NULL,
+    entry   TEXT    NOT NULL
+);
+
+CREATE INDEX idx_push_session_entries_push ON push_session_entries (push_id, id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_push_session_entries_push;
+DROP TABLE IF EXISTS push_session_entries;
+DROP INDEX IF EXISTS idx_push_sessions_expires;
+DROP TABLE IF EXISTS push_sessions;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Originating client's local sequence for pushed entries. NULL for
+-- server-generated entries (lore ingest, cascades) and for clients that
+-- do not send one.
+ALTER TABLE change_log ADD COLUMN source_sequence INTEGER;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE change_log DROP COLUMN source_sequence;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Track last use of each cached push response so the cache can be capped
+-- by evicting the least recently used entries.
+ALTER TABLE push_idempotency ADD COLUMN last_used_at TEXT;
+UPDATE push_idempotency SET last_used_at = created_at;
+
+CREATE INDEX idx_push_idempotency_last_used ON push_idempotency (last_used_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_push_idempotency_last_used;
+ALTER TABLE push_idempotency DROP COLUMN last_used_at;
+-- +goose StatementEnd
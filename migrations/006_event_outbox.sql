@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Transactional outbox for event bus publishing. Rows are copied from
+-- change_log by trigger in the same transaction as the change itself and
+-- deleted once the publisher has delivered them.
+CREATE TABLE event_outbox (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    sequence        INTEGER NOT NULL,
+    table_name      TEXT    NOT NULL,
+    entity_id       TEXT    NOT NULL,
+    operation       TEXT    NOT NULL,
+    payload         TEXT,
+    source_id       TEXT    NOT NULL,
+    created_at      TEXT    NOT NULL,
+    received_at     TEXT    NOT NULL,
+    source_sequence INTEGER,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    last_error      TEXT
+);
+
+-- Capture is off until a publisher enables it, so stores without an event
+-- bus do not accumulate outbox rows.
+INSERT INTO sync_meta (key, value) VALUES ('event_outbox_enabled', '0');
+
+CREATE TRIGGER change_log_event_outbox
+AFTER INSERT ON change_log
+WHEN (SELECT value FROM sync_meta WHERE key = 'event_outbox_enabled') = '1'
+BEGIN
+    INSERT INTO event_outbox (sequence, table_name, entity_id, operation, payload, source_id, created_at, received_at, source_sequence)
+    VALUES (NEW.sequence, NEW.table_name, NEW.entity_id, NEW.operation, NEW.payload, NEW.source_id, NEW.created_at, NEW.received_at, NEW.source_sequence);
+END;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS change_log_event_outbox;
+DELETE FROM sync_meta WHERE key = 'event_outbox_enabled';
+DROP TABLE IF EXISTS event_outbox;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Log of applied feedback, used for activity reports. Feedback stays
+-- anonymous: the submitting source is not recorded.
+CREATE TABLE feedback_events (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    lore_id     TEXT    NOT NULL,
+    type        TEXT    NOT NULL,
+    created_at  TEXT    NOT NULL
+);
+
+CREATE INDEX idx_feedback_events_created_at ON feedback_events (created_at);
+CREATE INDEX idx_feedback_events_lore_id ON feedback_events (lore_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_feedback_events_lore_id;
+DROP INDEX IF EXISTS idx_feedback_events_created_at;
+DROP TABLE IF EXISTS feedback_events;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Full-text index over lore content and context for keyword search.
+-- External-content table keyed on lore_entries.rowid: text is read from
+-- lore_entries, and triggers keep the index in step with every write.
+-- Soft-deleted entries stay indexed and are filtered at query time.
+CREATE VIRTUAL TABLE lore_fts USING fts5(
+    content,
+    context,
+    content = 'lore_entries',
+    content_rowid = 'rowid',
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO lore_fts (lore_fts) VALUES ('rebuild');
+
+CREATE TRIGGER lore_fts_insert AFTER INSERT ON lore_entries BEGIN
+    INSERT INTO lore_fts (rowid, content, context)
+    VALUES (NEW.rowid, NEW.content, NEW.context);
+END;
+
+CREATE TRIGGER lore_fts_delete AFTER DELETE ON lore_entries BEGIN
+    INSERT INTO lore_fts (lore_fts, rowid, content, context)
+    VALUES ('delete', OLD.rowid, OLD.content, OLD.context);
+END;
+
+CREATE TRIGGER lore_fts_update AFTER UPDATE OF content, context ON lore_entries BEGIN
+    INSERT INTO lore_fts (lore_fts, rowid, content, context)
+    VALUES ('delete', OLD.rowid, OLD.content, OLD.context);
+    INSERT INTO lore_fts (rowid, content, context)
+    VALUES (NEW.rowid, NEW.content, NEW.context);
+END;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS lore_fts_update;
+DROP TRIGGER IF EXISTS lore_fts_delete;
+DROP TRIGGER IF EXISTS lore_fts_insert;
+DROP TABLE IF EXISTS lore_fts;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Optional free-text note explaining a piece of feedback.
+ALTER TABLE feedback_events ADD COLUMN note TEXT;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE feedback_events DROP COLUMN note;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Curation decisions. Entries without a row are unreviewed.
+CREATE TABLE lore_reviews (
+    lore_id     TEXT PRIMARY KEY,
+    status      TEXT NOT NULL CHECK (status IN ('approved', 'rejected')),
+    reviewer    TEXT NOT NULL,
+    note        TEXT,
+    reviewed_at TEXT NOT NULL
+);
+
+-- Per-entry confidence bounds that feedback and decay must respect.
+-- Approving an entry locks a floor at its confidence when approved.
+CREATE TABLE confidence_locks (
+    lore_id TEXT PRIMARY KEY,
+    floor   REAL,
+    set_by  TEXT NOT NULL,
+    set_at  TEXT NOT NULL
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS confidence_locks;
+DROP TABLE IF EXISTS lore_reviews;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Upper confidence bound for deprecated entries: positive feedback and
+-- merges cannot raise confidence above it.
+ALTER TABLE confidence_locks ADD COLUMN ceiling REAL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE confidence_locks DROP COLUMN ceiling;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Prior content of lore entries. A row is written each time an edit, merge,
+-- sync replay or revert replaces an entry's content, context or category.
+CREATE TABLE lore_versions (
+    lore_id    TEXT NOT NULL,
+    version    INTEGER NOT NULL,
+    content    TEXT NOT NULL,
+    context    TEXT,
+    category   TEXT NOT NULL,
+    reason     TEXT NOT NULL CHECK (reason IN ('edit', 'merge', 'sync', 'revert')),
+    source_id  TEXT,
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (lore_id, version)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS lore_versions;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- L2 norm of the embedding, written alongside it so similarity scans only
+-- compute dot products. NULL when there is no embedding, or for rows
+-- embedded before this column existed until the store backfills them.
+ALTER TABLE lore_entries ADD COLUMN embedding_norm REAL;
+
+-- Similarity scans filter on both columns.
+CREATE INDEX idx_lore_entries_category_deleted_at ON lore_entries(category, deleted_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_lore_entries_category_deleted_at;
+ALTER TABLE lore_entries DROP COLUMN embedding_norm;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Random projection bucket of the embedding, so approximate deduplication
+-- can scan only the buckets near a new entry's. NULL when there is no
+-- embedding, or for rows embedded before this column existed until the
+-- store backfills them.
+ALTER TABLE lore_entries ADD COLUMN embedding_bucket INTEGER;
+
+CREATE INDEX idx_lore_entries_category_bucket ON lore_entries(category, embedding_bucket);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_lore_entries_category_bucket;
+ALTER TABLE lore_entries DROP COLUMN embedding_bucket;
+-- +goose StatementEnd
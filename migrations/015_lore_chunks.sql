@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Embeddings of overlapping chunks of long lore entries, kept alongside the
+-- entry's single embedding so similarity scans can match any part of it.
+-- Server-local: rows are replaced whenever the entry's content changes.
+CREATE TABLE lore_chunks (
+    lore_id        TEXT NOT NULL,
+    chunk_index    INTEGER NOT NULL,
+    embedding      BLOB NOT NULL,
+    embedding_norm REAL NOT NULL,
+    PRIMARY KEY (lore_id, chunk_index)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS lore_chunks;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Detected natural language of the content as an ISO 639-1 code, or 'und'
+-- when none stands out. NULL for rows written before this column existed
+-- until the store backfills them.
+ALTER TABLE lore_entries ADD COLUMN language TEXT;
+
+CREATE INDEX idx_lore_entries_language ON lore_entries(language, deleted_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_lore_entries_language;
+ALTER TABLE lore_entries DROP COLUMN language;
+-- +goose StatementEnd
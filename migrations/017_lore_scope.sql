@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Where an entry applies: the repository it was learned in and, within it,
+-- a slash-separated file or directory path. Empty means the entry applies
+-- everywhere.
+ALTER TABLE lore_entries ADD COLUMN repo TEXT NOT NULL DEFAULT '';
+ALTER TABLE lore_entries ADD COLUMN path TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_lore_entries_repo ON lore_entries(repo, deleted_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_lore_entries_repo;
+ALTER TABLE lore_entries DROP COLUMN path;
+ALTER TABLE lore_entries DROP COLUMN repo;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Conflict inbox: entries rejected from pushes made with
+-- quarantine_invalid, kept until the client resolves them.
+CREATE TABLE sync_conflicts (
+    id          TEXT    PRIMARY KEY,
+    push_id     TEXT    NOT NULL,
+    source_id   TEXT    NOT NULL,
+    table_name  TEXT    NOT NULL,
+    entity_id   TEXT    NOT NULL,
+    entry       TEXT    NOT NULL,
+    errors      TEXT    NOT NULL,
+    status      TEXT    NOT NULL DEFAULT 'open',
+    resolution  TEXT,
+    note        TEXT,
+    created_at  TEXT    NOT NULL,
+    resolved_at TEXT
+);
+
+CREATE INDEX idx_sync_conflicts_status ON sync_conflicts (status, id);
+CREATE INDEX idx_sync_conflicts_source ON sync_conflicts (source_id, status);
+
+-- Push sessions carry the option to their commit.
+ALTER TABLE push_sessions ADD COLUMN quarantine_invalid INTEGER NOT NULL DEFAULT 0;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE push_sessions DROP COLUMN quarantine_invalid;
+DROP INDEX IF EXISTS idx_sync_conflicts_source;
+DROP INDEX IF EXISTS idx_sync_conflicts_status;
+DROP TABLE IF EXISTS sync_conflicts;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Push sessions carry the push mode (atomic or partial) to their commit.
+ALTER TABLE push_sessions ADD COLUMN mode TEXT NOT NULL DEFAULT '';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE push_sessions DROP COLUMN mode;
+-- +goose StatementEnd
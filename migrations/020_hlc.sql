@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Hybrid logical clock readings (Unix milliseconds << 16 | logical
+-- counter) order changes across writers and restarts. Existing change_log
+-- rows are backfilled from received_at; lore rows take the reading of
+-- their latest change.
+ALTER TABLE change_log ADD COLUMN hlc INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE lore_entries ADD COLUMN hlc INTEGER NOT NULL DEFAULT 0;
+
+UPDATE change_log
+SET hlc = CAST(ROUND((julianday(received_at) - 2440587.5) * 86400000) AS INTEGER) * 65536
+WHERE julianday(received_at) IS NOT NULL;
+
+UPDATE lore_entries
+SET hlc = COALESCE((
+    SELECT MAX(c.hlc) FROM change_log c
+    WHERE c.table_name = 'lore_entries' AND c.entity_id = lore_entries.id
+), 0);
+
+CREATE INDEX idx_change_log_hlc ON change_log (hlc);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_change_log_hlc;
+ALTER TABLE lore_entries DROP COLUMN hlc;
+ALTER TABLE change_log DROP COLUMN hlc;
+-- +goose StatementEnd
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Timestamps are now stored as RFC 3339 UTC with a fixed nine-digit
+-- fraction (2026-03-01T12:00:00.000000000Z) so they sort correctly as
+-- strings. Rewrite UTC values stored at second precision, or with a
+-- trimmed fraction, in the same form. Values with other offsets are left
+-- as they are.
+
+UPDATE lore_entries
+SET created_at = substr(created_at, 1, 19) || '.' || substr(
+        CASE WHEN substr(created_at, 20, 1) = '.' THEN substr(created_at, 21, length(created_at) - 21) ELSE '' END || '000000000', 1, 9) || 'Z'
+WHERE created_at LIKE '____-__-__T__:__:__%Z' AND length(created_at) != 30;
+
+UPDATE lore_entries
+SET updated_at = substr(updated_at, 1, 19) || '.' || substr(
+        CASE WHEN substr(updated_at, 20, 1) = '.' THEN substr(updated_at, 21, length(updated_at) - 21) ELSE '' END || '000000000', 1, 9) || 'Z'
+WHERE updated_at LIKE '____-__-__T__:__:__%Z' AND length(updated_at) != 30;
+
+UPDATE lore_entries
+SET deleted_at = substr(deleted_at, 1, 19) || '.' || substr(
+        CASE WHEN substr(deleted_at, 20, 1) = '.' THEN substr(deleted_at, 21, length(deleted_at) - 21) ELSE '' END || '000000000', 1, 9) || 'Z'
+WHERE deleted_at LIKE '____-__-__T__:__:__%Z' AND length(deleted_at) != 30;
+
+UPDATE lore_entries
+SET last_validated_at = substr(last_validated_at, 1, 19) || '.' || substr(
+        CASE WHEN substr(last_validated_at, 20, 1) = '.' THEN substr(last_validated_at, 21, length(last_validated_at) - 21) ELSE '' END || '000000000', 1, 9) || 'Z'
+WHERE last_validated_at LIKE '____-__-__T__:__:__%Z' AND length(last_validated_at) != 30;
+
+UPDATE change_log
+SET created_at = substr(created_at, 1, 19) || '.' || substr(
+        CASE WHEN substr(created_at, 20, 1) = '.' THEN substr(created_at, 21, length(created_at) - 21) ELSE '' END || '000000000', 1, 9) || 'Z'
+WHERE created_at LIKE '____-__-__T__:__:__%Z' AND length(created_at) != 30;
+
+UPDATE feedback_events
+SET created_at = substr(created_at, 1, 19) || '.' || substr(
+        CASE WHEN substr(created_at, 20, 1) = '.' THEN substr(created_at, 21, length(created_at) - 21) ELSE '' END || '000000000', 1, 9) || 'Z'
+WHERE created_at LIKE '____-__-__T__:__:__%Z' AND length(created_at) != 30;
+
+UPDATE lore_versions
+SET created_at = substr(created_at, 1, 19) || '.' || substr(
+        CASE WHEN substr(created_at, 20, 1) = '.' THEN substr(created_at, 21, length(created_at) - 21) ELSE '' END || '000000000', 1, 9) || 'Z'
+WHERE created_at LIKE '____-__-__T__:__:__%Z' AND length(created_at) != 30;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Earlier versions parse the fixed-width form, so there is nothing to undo.
+SELECT 1;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Finds a source's latest change without scanning the log, for the
+-- per-source section of extended stats.
+CREATE INDEX idx_change_log_source ON change_log (source_id, sequence);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_change_log_source;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Entry embeddings, moved out of lore_entries so that listing and delta
+-- scans, which rarely need vectors, don't page in several kilobytes per
+-- row. One row per embedded entry; entries without an embedding have none.
+-- The norm, bucket and status stay on lore_entries, where scans filter on
+-- them. Kept in step with lore_entries by the store rather than a foreign
+-- key, since sync replay replaces entry rows wholesale.
+CREATE TABLE lore_embeddings (
+    lore_id   TEXT PRIMARY KEY,
+    embedding BLOB NOT NULL
+);
+
+INSERT INTO lore_embeddings (lore_id, embedding)
+SELECT id, embedding FROM lore_entries WHERE embedding IS NOT NULL;
+
+ALTER TABLE lore_entries DROP COLUMN embedding;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE lore_entries ADD COLUMN embedding BLOB;
+
+UPDATE lore_entries
+SET embedding = (SELECT embedding FROM lore_embeddings WHERE lore_id = lore_entries.id);
+
+DROP TABLE IF EXISTS lore_embeddings;
+-- +goose StatementEnd
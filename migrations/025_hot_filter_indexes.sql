@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- The embedding worker reads live pending entries oldest first; with
+-- deleted_at and created_at in the index it neither filters nor sorts
+-- table rows. It replaces the single-column status index.
+DROP INDEX IF EXISTS idx_lore_entries_embedding_status;
+CREATE INDEX idx_lore_entries_pending ON lore_entries (embedding_status, deleted_at, created_at);
+
+-- Category and language stats count live entries from the index alone,
+-- already grouped.
+CREATE INDEX idx_lore_entries_live_category ON lore_entries (deleted_at, category, language);
+
+-- Delta sync and change log filters select changes received since a time,
+-- which otherwise reads every change to the table.
+CREATE INDEX idx_change_log_received ON change_log (table_name, received_at);
+
+-- sequence is the rowid, which already searches and orders the log, so
+-- its own index only cost a write per change.
+DROP INDEX IF EXISTS idx_change_log_sequence;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS idx_change_log_sequence ON change_log (sequence);
+DROP INDEX IF EXISTS idx_change_log_received;
+DROP INDEX IF EXISTS idx_lore_entries_live_category;
+DROP INDEX IF EXISTS idx_lore_entries_pending;
+CREATE INDEX IF NOT EXISTS idx_lore_entries_embedding_status ON lore_entries (embedding_status);
+-- +goose StatementEnd
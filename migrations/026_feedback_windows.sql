@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Feedback each source gave each lore entry within the current rate limit
+-- window, so repeated feedback from one source can be capped. Sources are
+-- kept as a hash, and rows outlive their window only until the next prune.
+CREATE TABLE feedback_windows (
+    source_hash  TEXT NOT NULL,
+    lore_id      TEXT NOT NULL,
+    type         TEXT NOT NULL,
+    window_start TEXT NOT NULL,
+    count        INTEGER NOT NULL,
+    PRIMARY KEY (source_hash, lore_id, type)
+) WITHOUT ROWID;
+
+CREATE INDEX idx_feedback_windows_start ON feedback_windows (window_start);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS feedback_windows;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Push signatures already accepted, kept until their timestamp would be
+-- refused anyway, so a signed push is applied once across restarts and
+-- every replica serving the store.
+CREATE TABLE push_signatures (
+    signature  TEXT PRIMARY KEY,
+    expires_at TEXT NOT NULL
+) WITHOUT ROWID;
+
+CREATE INDEX idx_push_signatures_expires ON push_signatures (expires_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS push_signatures;
+-- +goose StatementEnd
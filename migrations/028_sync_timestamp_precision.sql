@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Migration 021 moved lore timestamps and change_log.created_at to the
+-- fixed-width form but left change_log.received_at, which delta queries
+-- page on, and the timestamps of the sync tables, so comparisons with
+-- fixed-width values as strings misjudged older rows. Rewrite them the
+-- same way. Values with other offsets are left as they are. The store now
+-- writes received_at itself, so the column's millisecond default is no
+-- longer used.
+
+UPDATE change_log
+SET received_at = substr(received_at, 1, 19) || '.' || substr(
+        CASE WHEN substr(received_at, 20, 1) = '.' THEN substr(received_at, 21, length(received_at) - 21) ELSE '' END || '000000000', 1, 9) || 'Z'
+WHERE received_at LIKE '____-__-__T__:__:__%Z' AND length(received_at) != 30;
+
+UPDATE event_outbox
+SET received_at = substr(received_at, 1, 19) || '.' || substr(
+        CASE WHEN substr(received_at, 20, 1) = '.' THEN substr(received_at, 21, length(received_at) - 21) ELSE '' END || '000000000', 1, 9) || 'Z'
+WHERE received_at LIKE '____-__-__T__:__:__%Z' AND length(received_at) != 30;
+
+UPDATE push_idempotency
+SET expires_at = substr(expires_at, 1, 19) || '.' || substr(
+        CASE WHEN substr(expires_at, 20, 1) = '.' THEN substr(expires_at, 21, length(expires_at) - 21) ELSE '' END || '000000000', 1, 9) || 'Z'
+WHERE expires_at LIKE '____-__-__T__:__:__%Z' AND length(expires_at) != 30;
+
+UPDATE push_sessions
+SET created_at = substr(created_at, 1, 19) || '.' || substr(
+        CASE WHEN substr(created_at, 20, 1) = '.' THEN substr(created_at, 21, length(created_at) - 21) ELSE '' END || '000000000', 1, 9) || 'Z'
+WHERE created_at LIKE '____-__-__T__:__:__%Z' AND length(created_at) != 30;
+
+UPDATE push_sessions
+SET expires_at = substr(expires_at, 1, 19) || '.' || substr(
+        CASE WHEN substr(expires_at, 20, 1) = '.' THEN substr(expires_at, 21, length(expires_at) - 21) ELSE '' END || '000000000', 1, 9) || 'Z'
+WHERE expires_at LIKE '____-__-__T__:__:__%Z' AND length(expires_at) != 30;
+
+UPDATE sync_conflicts
+SET created_at = substr(created_at, 1, 19) || '.' || substr(
+        CASE WHEN substr(created_at, 20, 1) = '.' THEN substr(created_at, 21, length(created_at) - 21) ELSE '' END || '000000000', 1, 9) || 'Z'
+WHERE created_at LIKE '____-__-__T__:__:__%Z' AND length(created_at) != 30;
+
+UPDATE sync_conflicts
+SET resolved_at = substr(resolved_at, 1, 19) || '.' || substr(
+        CASE WHEN substr(resolved_at, 20, 1) = '.' THEN substr(resolved_at, 21, length(resolved_at) - 21) ELSE '' END || '000000000', 1, 9) || 'Z'
+WHERE resolved_at LIKE '____-__-__T__:__:__%Z' AND length(resolved_at) != 30;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Earlier versions parse the fixed-width form, so there is nothing to undo.
+SELECT 1;
+-- +goose StatementEnd
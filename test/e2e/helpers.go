@@ -197,11 +197,6 @@ func setupSyncTestEnv(t *testing.T) (*multistore.StoreManager, *api.Handler, *mu
 		t.Fatalf("CreateStore() error = %v", err)
 	}
 
-	// Set schema version to 2
-	if err := managed.Store.SetSyncMeta(ctx, "schema_version", "2"); err != nil {
-		t.Fatalf("SetSyncMeta() error = %v", err)
-	}
-
 	defaultStore := &noopStore{}
 	embedder := &noopEmbedder{}
 	handler := api.NewHandler(defaultStore, manager, embedder, nil, "test-api-key", "1.0.0")
@@ -235,11 +230,27 @@ func (s *noopStore) IngestLore(_ context.Context, _ []types.NewLoreEntry) (*type
 func (s *noopStore) FindSimilar(_ context.Context, _ []float32, _ string, _ float64) ([]types.SimilarEntry, error) {
 	return nil, nil
 }
+func (s *noopStore) SearchLore(_ context.Context, _ types.SearchQuery) ([]types.SearchResult, error) {
+	return nil, nil
+}
 func (s *noopStore) MergeLore(_ context.Context, _ string, _ types.NewLoreEntry) error { return nil }
 func (s *noopStore) GetLore(_ context.Context, _ string) (*types.LoreEntry, error) {
 	return nil, nil
 }
+func (s *noopStore) GetLoreVersion(_ context.Context, _ string) (int, error) { return 1, nil }
+func (s *noopStore) UpdateLore(_ context.Context, id string, _ types.LoreUpdate, _ int) (*types.LoreEntry, error) {
+	return &types.LoreEntry{ID: id}, nil
+}
 func (s *noopStore) DeleteLore(_ context.Context, _, _ string) error { return nil }
+func (s *noopStore) DeleteLoreIfVersion(_ context.Context, _, _ string, _ int) error {
+	return nil
+}
+func (s *noopStore) ListLoreVersions(_ context.Context, _ string) ([]types.LoreVersion, error) {
+	return []types.LoreVersion{}, nil
+}
+func (s *noopStore) RevertLore(_ context.Context, id string, _ int, _ string) (*types.LoreEntry, error) {
+	return &types.LoreEntry{ID: id}, nil
+}
 func (s *noopStore) GetMetadata(_ context.Context) (*types.StoreMetadata, error) {
 	return &types.StoreMetadata{}, nil
 }
@@ -256,6 +267,33 @@ func (s *noopStore) GetSnapshotPath(_ context.Context) (string, error) {
 func (s *noopStore) RecordFeedback(_ context.Context, _ []types.FeedbackEntry) (*types.FeedbackResult, error) {
 	return &types.FeedbackResult{}, nil
 }
+func (s *noopStore) RecordFeedbackAtomic(_ context.Context, _ []types.FeedbackEntry) (*types.FeedbackResult, error) {
+	return &types.FeedbackResult{}, nil
+}
+func (s *noopStore) GetFeedbackHistory(_ context.Context, _ string, _ int64, _ int) ([]types.FeedbackEvent, error) {
+	return []types.FeedbackEvent{}, nil
+}
+func (s *noopStore) ListReviewQueue(_ context.Context, _ types.ReviewQueueQuery) ([]types.ReviewCandidate, error) {
+	return []types.ReviewCandidate{}, nil
+}
+func (s *noopStore) FindContradictions(_ context.Context, _ types.ContradictionQuery) ([]types.Contradiction, error) {
+	return []types.Contradiction{}, nil
+}
+func (s *noopStore) GetReview(_ context.Context, id string) (*types.LoreReview, error) {
+	return &types.LoreReview{LoreID: id, Status: types.ReviewUnreviewed}, nil
+}
+func (s *noopStore) ReviewLore(_ context.Context, id string, status types.ReviewStatus, reviewer, note string) (*types.LoreReview, error) {
+	return &types.LoreReview{LoreID: id, Status: status, Reviewer: reviewer, Note: note}, nil
+}
+func (s *noopStore) GetConfidenceLock(_ context.Context, id string) (*types.ConfidenceLock, error) {
+	return &types.ConfidenceLock{LoreID: id}, nil
+}
+func (s *noopStore) SetConfidenceLock(_ context.Context, id string, floor, ceiling *float64, setBy string) (*types.ConfidenceLock, error) {
+	return &types.ConfidenceLock{LoreID: id, Floor: floor, Ceiling: ceiling, SetBy: setBy}, nil
+}
+func (s *noopStore) ClearConfidenceLock(_ context.Context, _ string) error {
+	return nil
+}
 func (s *noopStore) DecayConfidence(_ context.Context, _ time.Time, _ float64) (int64, error) {
 	return 0, nil
 }
@@ -281,14 +319,41 @@ func (s *noopStore) AppendChangeLogBatch(_ context.Context, _ []engramsync.Chang
 func (s *noopStore) GetChangeLogAfter(_ context.Context, _ int64, _ int) ([]engramsync.ChangeLogEntry, error) {
 	return nil, nil
 }
+func (s *noopStore) QueryChangeLog(_ context.Context, _ int64, _ int, _ engramsync.ChangeLogFilter) ([]engramsync.ChangeLogEntry, error) {
+	return nil, nil
+}
 func (s *noopStore) GetLatestSequence(_ context.Context) (int64, error) { return 0, nil }
 func (s *noopStore) CheckPushIdempotency(_ context.Context, _ string) ([]byte, bool, error) {
 	return nil, false, nil
 }
+func (s *noopStore) GetLatestEntries(_ context.Context, _ []string) ([]engramsync.ChangeLogEntry, error) {
+	return nil, nil
+}
 func (s *noopStore) RecordPushIdempotency(_ context.Context, _, _ string, _ []byte, _ time.Duration) error {
 	return nil
 }
 func (s *noopStore) CleanExpiredIdempotency(_ context.Context) (int64, error) { return 0, nil }
+func (s *noopStore) CreatePushSession(_ context.Context, _ engramsync.PushSession) error { return nil }
+func (s *noopStore) GetPushSession(_ context.Context, _ string) (*engramsync.PushSession, error) {
+	return nil, nil
+}
+func (s *noopStore) AppendPushSessionEntries(_ context.Context, _ string, _ []engramsync.ChangeLogEntry) (int, error) {
+	return 0, nil
+}
+func (s *noopStore) GetPushSessionEntries(_ context.Context, _ string) ([]engramsync.ChangeLogEntry, error) {
+	return nil, nil
+}
+func (s *noopStore) DeletePushSession(_ context.Context, _ string) error { return nil }
+func (s *noopStore) CleanExpiredPushSessions(_ context.Context) (int64, error) { return 0, nil }
+func (s *noopStore) ListSyncConflicts(_ context.Context, _ engramsync.SyncConflictFilter) ([]engramsync.SyncConflict, error) {
+	return nil, nil
+}
+func (s *noopStore) GetSyncConflict(_ context.Context, _ string) (*engramsync.SyncConflict, error) {
+	return nil, nil
+}
+func (s *noopStore) ResolveSyncConflict(_ context.Context, _, _, _ string) (*engramsync.SyncConflict, error) {
+	return nil, nil
+}
 func (s *noopStore) GetSyncMeta(_ context.Context, _ string) (string, error) { return "", nil }
 func (s *noopStore) SetSyncMeta(_ context.Context, _, _ string) error        { return nil }
 func (s *noopStore) CompactChangeLog(_ context.Context, _ time.Time, _ string) (int64, int64, error) {
@@ -393,6 +393,7 @@ func TestSync_DeleteVisibleInDelta(t *testing.T) {
 
 		// Delete via legacy endpoint
 		delReq := httptest.NewRequest(http.MethodDelete, "/api/v1/stores/test-store/lore/"+entryID, nil)
+		delReq.Header.Set("If-Match", "*")
 		delReq.Header.Set("Authorization", "Bearer test-api-key")
 		delReq.Header.Set("X-Recall-Source-ID", "delete-test")
 		dw := httptest.NewRecorder()
@@ -421,6 +422,7 @@ func TestSync_DeleteVisibleInDelta(t *testing.T) {
 
 	// Delete via legacy endpoint
 	delReq := httptest.NewRequest(http.MethodDelete, "/api/v1/stores/test-store/lore/"+entryID, nil)
+	delReq.Header.Set("If-Match", "*")
 	delReq.Header.Set("Authorization", "Bearer test-api-key")
 	delReq.Header.Set("X-Recall-Source-ID", "delete-test")
 	dw := httptest.NewRecorder()